@@ -47,6 +47,8 @@ func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.Topology != nil {
 		dst.Spec.Topology = restored.Spec.Topology
 	}
+	dst.Spec.DeletionTimeouts = restored.Spec.DeletionTimeouts
+	dst.Status.MachinesPerFailureDomain = restored.Status.MachinesPerFailureDomain
 
 	return nil
 }
@@ -97,6 +99,7 @@ func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Status.NodeInfo = restored.Status.NodeInfo
+	restoreMachineSpec(&restored.Spec, &dst.Spec)
 	return nil
 }
 
@@ -139,6 +142,7 @@ func (src *MachineSet) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 	dst.Status.Conditions = restored.Status.Conditions
+	restoreMachineSpec(&restored.Spec.Template.Spec, &dst.Spec.Template.Spec)
 	return nil
 }
 
@@ -191,6 +195,9 @@ func (src *MachineDeployment) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.Strategy.RollingUpdate.DeletePolicy = restored.Spec.Strategy.RollingUpdate.DeletePolicy
 	}
 
+	dst.Spec.RolloutAfter = restored.Spec.RolloutAfter
+	dst.Spec.RollbackTo = restored.Spec.RollbackTo
+	restoreMachineSpec(&restored.Spec.Template.Spec, &dst.Spec.Template.Spec)
 	dst.Status.Conditions = restored.Status.Conditions
 	return nil
 }
@@ -210,6 +217,11 @@ func (dst *MachineDeployment) ConvertFrom(srcRaw conversion.Hub) error {
 	return nil
 }
 
+// MachineDeploymentSpec.RolloutAfter and MachineDeploymentSpec.RollbackTo do not exist in v1alpha3, the values are going to be preserved in an annotation.
+func Convert_v1beta1_MachineDeploymentSpec_To_v1alpha3_MachineDeploymentSpec(in *v1beta1.MachineDeploymentSpec, out *MachineDeploymentSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_MachineDeploymentSpec_To_v1alpha3_MachineDeploymentSpec(in, out, s)
+}
+
 // Status.Conditions was introduced in v1alpha4, thus requiring a custom conversion function; the values is going to be preserved in an annotation thus allowing roundtrip without loosing informations
 func Convert_v1beta1_MachineDeploymentStatus_To_v1alpha3_MachineDeploymentStatus(in *v1beta1.MachineDeploymentStatus, out *MachineDeploymentStatus, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_MachineDeploymentStatus_To_v1alpha3_MachineDeploymentStatus(in, out, s)
@@ -243,6 +255,7 @@ func (src *MachineHealthCheck) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.UnhealthyRange != nil {
 		dst.Spec.UnhealthyRange = restored.Spec.UnhealthyRange
 	}
+	dst.Spec.ExternalRemediationTimeout = restored.Spec.ExternalRemediationTimeout
 
 	return nil
 }
@@ -279,8 +292,14 @@ func Convert_v1beta1_MachineSetStatus_To_v1alpha3_MachineSetStatus(in *v1beta1.M
 	return autoConvert_v1beta1_MachineSetStatus_To_v1alpha3_MachineSetStatus(in, out, nil)
 }
 
+func Convert_v1beta1_MachineSpec_To_v1alpha3_MachineSpec(in *v1beta1.MachineSpec, out *MachineSpec, s apiconversion.Scope) error {
+	// spec.nodeDrainGracePeriod, nodeDrainExcludedPodSelector, skipWaitForNodeVolumeDetach and nodeVolumeDetachTimeout
+	// have been added with v1beta1.
+	return autoConvert_v1beta1_MachineSpec_To_v1alpha3_MachineSpec(in, out, s)
+}
+
 func Convert_v1beta1_ClusterSpec_To_v1alpha3_ClusterSpec(in *v1beta1.ClusterSpec, out *ClusterSpec, s apiconversion.Scope) error {
-	// NOTE: custom conversion func is required because spec.Topology does not exists in v1alpha3
+	// NOTE: custom conversion func is required because spec.Topology and spec.DeletionTimeouts do not exist in v1alpha3
 	return autoConvert_v1beta1_ClusterSpec_To_v1alpha3_ClusterSpec(in, out, s)
 }
 
@@ -293,9 +312,15 @@ func Convert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha3_MachineRollingUp
 }
 
 func Convert_v1beta1_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(in *v1beta1.MachineHealthCheckSpec, out *MachineHealthCheckSpec, s apiconversion.Scope) error {
+	// spec.unhealthyRange has been added with v1alpha4, spec.externalRemediationTimeout with v1beta1.
 	return autoConvert_v1beta1_MachineHealthCheckSpec_To_v1alpha3_MachineHealthCheckSpec(in, out, s)
 }
 
+func Convert_v1beta1_ClusterStatus_To_v1alpha3_ClusterStatus(in *v1beta1.ClusterStatus, out *ClusterStatus, s apiconversion.Scope) error {
+	// status.machinesPerFailureDomain has been added with v1beta1.
+	return autoConvert_v1beta1_ClusterStatus_To_v1alpha3_ClusterStatus(in, out, s)
+}
+
 func Convert_v1alpha3_ClusterStatus_To_v1beta1_ClusterStatus(in *ClusterStatus, out *v1beta1.ClusterStatus, s apiconversion.Scope) error {
 	return autoConvert_v1alpha3_ClusterStatus_To_v1beta1_ClusterStatus(in, out, s)
 }
@@ -307,3 +332,11 @@ func Convert_v1alpha3_ObjectMeta_To_v1beta1_ObjectMeta(in *ObjectMeta, out *v1be
 func Convert_v1beta1_MachineStatus_To_v1alpha3_MachineStatus(in *v1beta1.MachineStatus, out *MachineStatus, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_MachineStatus_To_v1alpha3_MachineStatus(in, out, s)
 }
+
+// restoreMachineSpec restores the MachineSpec fields which have been added with v1beta1.
+func restoreMachineSpec(restored *v1beta1.MachineSpec, dst *v1beta1.MachineSpec) {
+	dst.NodeDrainGracePeriod = restored.NodeDrainGracePeriod
+	dst.NodeDrainExcludedPodSelector = restored.NodeDrainExcludedPodSelector
+	dst.SkipWaitForNodeVolumeDetach = restored.SkipWaitForNodeVolumeDetach
+	dst.NodeVolumeDetachTimeout = restored.NodeVolumeDetachTimeout
+}
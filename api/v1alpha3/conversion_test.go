@@ -17,9 +17,11 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"encoding/json"
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
 	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
@@ -32,6 +34,7 @@ func TestFuzzyConversion(t *testing.T) {
 		Hub:                &v1beta1.Cluster{},
 		Spoke:              &Cluster{},
 		SpokeAfterMutation: clusterSpokeAfterMutation,
+		FuzzerFuncs:        []fuzzer.FuzzerFuncs{JSONFuzzFuncs},
 	}))
 
 	t.Run("for Machine", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
@@ -75,6 +78,36 @@ func CustomObjectMetaFuzzer(in *ObjectMeta, c fuzz.Continue) {
 	in.OwnerReferences = nil
 }
 
+func JSONFuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		JSONFuzzer,
+		JSONSchemaPropsFuzzer,
+	}
+}
+
+func JSONFuzzer(in *apiextensionsv1.JSON, c fuzz.Continue) {
+	// apiextensionsv1.JSON must contain a valid JSON value, otherwise marshalling the Hub
+	// into the conversion annotation fails.
+	in.Raw, _ = json.Marshal(c.RandString())
+}
+
+func JSONSchemaPropsFuzzer(in *v1beta1.JSONSchemaProps, c fuzz.Continue) {
+	// Fuzz only the top level fields; nested schemas are recursive and fuzzing them
+	// could lead to very deep objects.
+	in.Type = c.RandString()
+	in.Format = c.RandString()
+	in.Pattern = c.RandString()
+	c.Fuzz(&in.Required)
+	c.Fuzz(&in.MaxLength)
+	c.Fuzz(&in.Minimum)
+	c.Fuzz(&in.Default)
+	c.Fuzz(&in.Enum)
+	in.Properties = map[string]v1beta1.JSONSchemaProps{
+		c.RandString(): {Type: c.RandString()},
+	}
+	in.Items = &v1beta1.JSONSchemaProps{Type: c.RandString()}
+}
+
 func BootstrapFuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 	return []interface{}{
 		BootstrapFuzzer,
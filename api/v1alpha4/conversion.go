@@ -17,20 +17,69 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
 func (src *Cluster) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.Cluster)
 
-	return Convert_v1alpha4_Cluster_To_v1beta1_Cluster(src, dst, nil)
+	if err := Convert_v1alpha4_Cluster_To_v1beta1_Cluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.Cluster{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	if restored.Spec.Topology != nil && dst.Spec.Topology != nil {
+		dst.Spec.Topology.ClassNamespace = restored.Spec.Topology.ClassNamespace
+		dst.Spec.Topology.Variables = restored.Spec.Topology.Variables
+
+		if restored.Spec.Topology.Workers != nil && dst.Spec.Topology.Workers != nil {
+			for i := range dst.Spec.Topology.Workers.MachineDeployments {
+				for _, restoredMachineDeployment := range restored.Spec.Topology.Workers.MachineDeployments {
+					if dst.Spec.Topology.Workers.MachineDeployments[i].Name == restoredMachineDeployment.Name {
+						dst.Spec.Topology.Workers.MachineDeployments[i].RolloutStrategy = restoredMachineDeployment.RolloutStrategy
+						dst.Spec.Topology.Workers.MachineDeployments[i].NodeLabels = restoredMachineDeployment.NodeLabels
+						dst.Spec.Topology.Workers.MachineDeployments[i].NodeTaints = restoredMachineDeployment.NodeTaints
+						dst.Spec.Topology.Workers.MachineDeployments[i].Autoscaling = restoredMachineDeployment.Autoscaling
+					}
+				}
+			}
+		}
+
+		if restored.Spec.Topology.Workers != nil {
+			if dst.Spec.Topology.Workers == nil {
+				dst.Spec.Topology.Workers = &v1beta1.WorkersTopology{}
+			}
+			dst.Spec.Topology.Workers.MachinePools = restored.Spec.Topology.Workers.MachinePools
+			dst.Spec.Topology.Workers.MachineDeploymentDeletionPolicy = restored.Spec.Topology.Workers.MachineDeploymentDeletionPolicy
+		}
+	}
+	dst.Spec.DeletionTimeouts = restored.Spec.DeletionTimeouts
+	dst.Status.MachinesPerFailureDomain = restored.Status.MachinesPerFailureDomain
+
+	return nil
 }
 
 func (dst *Cluster) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.Cluster)
 
-	return Convert_v1beta1_Cluster_To_v1alpha4_Cluster(src, dst, nil)
+	if err := Convert_v1beta1_Cluster_To_v1alpha4_Cluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *ClusterList) ConvertTo(dstRaw conversion.Hub) error {
@@ -48,13 +97,48 @@ func (dst *ClusterList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *ClusterClass) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.ClusterClass)
 
-	return Convert_v1alpha4_ClusterClass_To_v1beta1_ClusterClass(src, dst, nil)
+	if err := Convert_v1alpha4_ClusterClass_To_v1beta1_ClusterClass(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.ClusterClass{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Variables = restored.Spec.Variables
+	dst.Spec.Patches = restored.Spec.Patches
+	dst.Spec.ControlPlane.MachineHealthCheck = restored.Spec.ControlPlane.MachineHealthCheck
+	for i := range dst.Spec.Workers.MachineDeployments {
+		for _, restoredMachineDeployment := range restored.Spec.Workers.MachineDeployments {
+			if dst.Spec.Workers.MachineDeployments[i].Class == restoredMachineDeployment.Class {
+				dst.Spec.Workers.MachineDeployments[i].MachineHealthCheck = restoredMachineDeployment.MachineHealthCheck
+				dst.Spec.Workers.MachineDeployments[i].RolloutStrategy = restoredMachineDeployment.RolloutStrategy
+				dst.Spec.Workers.MachineDeployments[i].Template.NodeLabels = restoredMachineDeployment.Template.NodeLabels
+				dst.Spec.Workers.MachineDeployments[i].Template.NodeTaints = restoredMachineDeployment.Template.NodeTaints
+			}
+		}
+	}
+	dst.Spec.Workers.MachinePools = restored.Spec.Workers.MachinePools
+	dst.Status = restored.Status
+
+	return nil
 }
 
 func (dst *ClusterClass) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.ClusterClass)
 
-	return Convert_v1beta1_ClusterClass_To_v1alpha4_ClusterClass(src, dst, nil)
+	if err := Convert_v1beta1_ClusterClass_To_v1alpha4_ClusterClass(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *ClusterClassList) ConvertTo(dstRaw conversion.Hub) error {
@@ -72,13 +156,33 @@ func (dst *ClusterClassList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *Machine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.Machine)
 
-	return Convert_v1alpha4_Machine_To_v1beta1_Machine(src, dst, nil)
+	if err := Convert_v1alpha4_Machine_To_v1beta1_Machine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.Machine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	restoreMachineSpec(&restored.Spec, &dst.Spec)
+	return nil
 }
 
 func (dst *Machine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.Machine)
 
-	return Convert_v1beta1_Machine_To_v1alpha4_Machine(src, dst, nil)
+	if err := Convert_v1beta1_Machine_To_v1alpha4_Machine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *MachineList) ConvertTo(dstRaw conversion.Hub) error {
@@ -96,13 +200,33 @@ func (dst *MachineList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *MachineSet) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.MachineSet)
 
-	return Convert_v1alpha4_MachineSet_To_v1beta1_MachineSet(src, dst, nil)
+	if err := Convert_v1alpha4_MachineSet_To_v1beta1_MachineSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.MachineSet{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	restoreMachineSpec(&restored.Spec.Template.Spec, &dst.Spec.Template.Spec)
+	return nil
 }
 
 func (dst *MachineSet) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.MachineSet)
 
-	return Convert_v1beta1_MachineSet_To_v1alpha4_MachineSet(src, dst, nil)
+	if err := Convert_v1beta1_MachineSet_To_v1alpha4_MachineSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *MachineSetList) ConvertTo(dstRaw conversion.Hub) error {
@@ -120,13 +244,35 @@ func (dst *MachineSetList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *MachineDeployment) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.MachineDeployment)
 
-	return Convert_v1alpha4_MachineDeployment_To_v1beta1_MachineDeployment(src, dst, nil)
+	if err := Convert_v1alpha4_MachineDeployment_To_v1beta1_MachineDeployment(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.MachineDeployment{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.RolloutAfter = restored.Spec.RolloutAfter
+	dst.Spec.RollbackTo = restored.Spec.RollbackTo
+	restoreMachineSpec(&restored.Spec.Template.Spec, &dst.Spec.Template.Spec)
+	return nil
 }
 
 func (dst *MachineDeployment) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.MachineDeployment)
 
-	return Convert_v1beta1_MachineDeployment_To_v1alpha4_MachineDeployment(src, dst, nil)
+	if err := Convert_v1beta1_MachineDeployment_To_v1alpha4_MachineDeployment(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *MachineDeploymentList) ConvertTo(dstRaw conversion.Hub) error {
@@ -144,13 +290,33 @@ func (dst *MachineDeploymentList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *MachineHealthCheck) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.MachineHealthCheck)
 
-	return Convert_v1alpha4_MachineHealthCheck_To_v1beta1_MachineHealthCheck(src, dst, nil)
+	if err := Convert_v1alpha4_MachineHealthCheck_To_v1beta1_MachineHealthCheck(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.MachineHealthCheck{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.ExternalRemediationTimeout = restored.Spec.ExternalRemediationTimeout
+
+	return nil
 }
 
 func (dst *MachineHealthCheck) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.MachineHealthCheck)
 
-	return Convert_v1beta1_MachineHealthCheck_To_v1alpha4_MachineHealthCheck(src, dst, nil)
+	if err := Convert_v1beta1_MachineHealthCheck_To_v1alpha4_MachineHealthCheck(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, dst); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (src *MachineHealthCheckList) ConvertTo(dstRaw conversion.Hub) error {
@@ -164,3 +330,82 @@ func (dst *MachineHealthCheckList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_MachineHealthCheckList_To_v1alpha4_MachineHealthCheckList(src, dst, nil)
 }
+
+func Convert_v1beta1_ClusterSpec_To_v1alpha4_ClusterSpec(in *v1beta1.ClusterSpec, out *ClusterSpec, s apiconversion.Scope) error {
+	// spec.deletionTimeouts has been added with v1beta1.
+	return autoConvert_v1beta1_ClusterSpec_To_v1alpha4_ClusterSpec(in, out, s)
+}
+
+func Convert_v1beta1_ClusterStatus_To_v1alpha4_ClusterStatus(in *v1beta1.ClusterStatus, out *ClusterStatus, s apiconversion.Scope) error {
+	// status.machinesPerFailureDomain has been added with v1beta1.
+	return autoConvert_v1beta1_ClusterStatus_To_v1alpha4_ClusterStatus(in, out, s)
+}
+
+func Convert_v1beta1_ClusterClass_To_v1alpha4_ClusterClass(in *v1beta1.ClusterClass, out *ClusterClass, s apiconversion.Scope) error {
+	// status has been added with v1beta1.
+	return autoConvert_v1beta1_ClusterClass_To_v1alpha4_ClusterClass(in, out, s)
+}
+
+func Convert_v1beta1_ClusterClassSpec_To_v1alpha4_ClusterClassSpec(in *v1beta1.ClusterClassSpec, out *ClusterClassSpec, s apiconversion.Scope) error {
+	// spec.variables and spec.patches have been added with v1beta1.
+	return autoConvert_v1beta1_ClusterClassSpec_To_v1alpha4_ClusterClassSpec(in, out, s)
+}
+
+func Convert_v1beta1_ControlPlaneClass_To_v1alpha4_ControlPlaneClass(in *v1beta1.ControlPlaneClass, out *ControlPlaneClass, s apiconversion.Scope) error {
+	// spec.controlPlane.machineHealthCheck has been added with v1beta1.
+	return autoConvert_v1beta1_ControlPlaneClass_To_v1alpha4_ControlPlaneClass(in, out, s)
+}
+
+func Convert_v1beta1_MachineDeploymentClass_To_v1alpha4_MachineDeploymentClass(in *v1beta1.MachineDeploymentClass, out *MachineDeploymentClass, s apiconversion.Scope) error {
+	// spec.workers.machineDeployments[].machineHealthCheck and rolloutStrategy have been added with v1beta1.
+	return autoConvert_v1beta1_MachineDeploymentClass_To_v1alpha4_MachineDeploymentClass(in, out, s)
+}
+
+func Convert_v1beta1_MachineDeploymentClassTemplate_To_v1alpha4_MachineDeploymentClassTemplate(in *v1beta1.MachineDeploymentClassTemplate, out *MachineDeploymentClassTemplate, s apiconversion.Scope) error {
+	// spec.workers.machineDeployments[].template.nodeLabels and nodeTaints have been added with v1beta1.
+	return autoConvert_v1beta1_MachineDeploymentClassTemplate_To_v1alpha4_MachineDeploymentClassTemplate(in, out, s)
+}
+
+func Convert_v1beta1_MachineDeploymentSpec_To_v1alpha4_MachineDeploymentSpec(in *v1beta1.MachineDeploymentSpec, out *MachineDeploymentSpec, s apiconversion.Scope) error {
+	// spec.rolloutAfter and rollbackTo have been added with v1beta1.
+	return autoConvert_v1beta1_MachineDeploymentSpec_To_v1alpha4_MachineDeploymentSpec(in, out, s)
+}
+
+func Convert_v1beta1_MachineDeploymentTopology_To_v1alpha4_MachineDeploymentTopology(in *v1beta1.MachineDeploymentTopology, out *MachineDeploymentTopology, s apiconversion.Scope) error {
+	// spec.topology.workers.machineDeployments[].rolloutStrategy, nodeLabels and nodeTaints have been added with v1beta1.
+	return autoConvert_v1beta1_MachineDeploymentTopology_To_v1alpha4_MachineDeploymentTopology(in, out, s)
+}
+
+func Convert_v1beta1_MachineHealthCheckSpec_To_v1alpha4_MachineHealthCheckSpec(in *v1beta1.MachineHealthCheckSpec, out *MachineHealthCheckSpec, s apiconversion.Scope) error {
+	// spec.externalRemediationTimeout has been added with v1beta1.
+	return autoConvert_v1beta1_MachineHealthCheckSpec_To_v1alpha4_MachineHealthCheckSpec(in, out, s)
+}
+
+func Convert_v1beta1_MachineSpec_To_v1alpha4_MachineSpec(in *v1beta1.MachineSpec, out *MachineSpec, s apiconversion.Scope) error {
+	// spec.nodeDrainGracePeriod, nodeDrainExcludedPodSelector, skipWaitForNodeVolumeDetach and nodeVolumeDetachTimeout
+	// have been added with v1beta1.
+	return autoConvert_v1beta1_MachineSpec_To_v1alpha4_MachineSpec(in, out, s)
+}
+
+func Convert_v1beta1_Topology_To_v1alpha4_Topology(in *v1beta1.Topology, out *Topology, s apiconversion.Scope) error {
+	// spec.topology.variables has been added with v1beta1.
+	return autoConvert_v1beta1_Topology_To_v1alpha4_Topology(in, out, s)
+}
+
+func Convert_v1beta1_WorkersClass_To_v1alpha4_WorkersClass(in *v1beta1.WorkersClass, out *WorkersClass, s apiconversion.Scope) error {
+	// spec.workers.machinePools has been added with v1beta1.
+	return autoConvert_v1beta1_WorkersClass_To_v1alpha4_WorkersClass(in, out, s)
+}
+
+func Convert_v1beta1_WorkersTopology_To_v1alpha4_WorkersTopology(in *v1beta1.WorkersTopology, out *WorkersTopology, s apiconversion.Scope) error {
+	// spec.topology.workers.machinePools has been added with v1beta1.
+	return autoConvert_v1beta1_WorkersTopology_To_v1alpha4_WorkersTopology(in, out, s)
+}
+
+// restoreMachineSpec restores the MachineSpec fields which have been added with v1beta1.
+func restoreMachineSpec(restored *v1beta1.MachineSpec, dst *v1beta1.MachineSpec) {
+	dst.NodeDrainGracePeriod = restored.NodeDrainGracePeriod
+	dst.NodeDrainExcludedPodSelector = restored.NodeDrainExcludedPodSelector
+	dst.SkipWaitForNodeVolumeDetach = restored.SkipWaitForNodeVolumeDetach
+	dst.NodeVolumeDetachTimeout = restored.NodeVolumeDetachTimeout
+}
@@ -23,7 +23,9 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -67,6 +69,32 @@ type ClusterSpec struct {
 	// this feature is highly experimental, and parts of it might still be not implemented.
 	// +optional
 	Topology *Topology `json:"topology,omitempty"`
+
+	// DeletionTimeouts defines how long each phase of the Cluster deletion is waited for
+	// before the deletion moves on to the next phase.
+	// The default is to wait for each phase without time limitations.
+	// +optional
+	DeletionTimeouts *ClusterDeletionTimeouts `json:"deletionTimeouts,omitempty"`
+}
+
+// ClusterDeletionTimeouts defines the timeouts of the phases of the Cluster deletion.
+// A Cluster is deleted in three phases: first the workers, then the control plane and finally the infrastructure.
+// NOTE: The Cluster is removed only after all the phases have completed, also when some of them timed out.
+type ClusterDeletionTimeouts struct {
+	// Workers is the amount of time to wait for the MachinePools, MachineDeployments, MachineSets
+	// and worker Machines of the Cluster to be deleted before starting to delete the control plane.
+	// +optional
+	Workers *metav1.Duration `json:"workers,omitempty"`
+
+	// ControlPlane is the amount of time to wait for the control plane of the Cluster to be deleted
+	// before starting to delete the infrastructure.
+	// +optional
+	ControlPlane *metav1.Duration `json:"controlPlane,omitempty"`
+
+	// Infrastructure is the amount of time to wait for the infrastructure of the Cluster to be deleted
+	// before reporting the deletion of the infrastructure as timed out.
+	// +optional
+	Infrastructure *metav1.Duration `json:"infrastructure,omitempty"`
 }
 
 // Topology encapsulates the information of the managed resources.
@@ -74,6 +102,13 @@ type Topology struct {
 	// The name of the ClusterClass object to create the topology.
 	Class string `json:"class"`
 
+	// ClassNamespace is the namespace of the ClusterClass object to create the topology.
+	// If not set, the ClusterClass is expected to be in the same namespace of the Cluster; a different namespace
+	// can be set only if it is in the list of namespaces allowed to provide ClusterClasses to other namespaces.
+	// NOTE: The templates referenced by the ClusterClass are cloned into the namespace of the Cluster.
+	// +optional
+	ClassNamespace string `json:"classNamespace,omitempty"`
+
 	// The Kubernetes version of the cluster.
 	Version string `json:"version"`
 
@@ -90,6 +125,25 @@ type Topology struct {
 	// for the cluster.
 	// +optional
 	Workers *WorkersTopology `json:"workers,omitempty"`
+
+	// Variables can be used to customize the Cluster through
+	// the templates referenced by the ClusterClass. They must comply to the corresponding
+	// ClusterClassVariables defined in the ClusterClass.
+	// +optional
+	Variables []ClusterVariable `json:"variables,omitempty"`
+}
+
+// ClusterVariable can be used to customize the Cluster through
+// the templates referenced by the ClusterClass. It must comply to the corresponding
+// ClusterClassVariable defined in the ClusterClass.
+type ClusterVariable struct {
+	// Name of the variable.
+	Name string `json:"name"`
+
+	// Value of the variable.
+	// Note: the value will be validated against the schema of the corresponding ClusterClassVariable
+	// from the ClusterClass.
+	Value apiextensionsv1.JSON `json:"value"`
 }
 
 // ControlPlaneTopology specifies the parameters for the control plane nodes in the cluster.
@@ -113,8 +167,35 @@ type ControlPlaneTopology struct {
 type WorkersTopology struct {
 	// MachineDeployments is a list of machine deployments in the cluster.
 	MachineDeployments []MachineDeploymentTopology `json:"machineDeployments,omitempty"`
+
+	// MachinePools is a list of machine pools in the cluster.
+	// NOTE: MachinePools can be used only if the MachinePool feature flag is enabled.
+	// +optional
+	MachinePools []MachinePoolTopology `json:"machinePools,omitempty"`
+
+	// MachineDeploymentDeletionPolicy defines what happens to the MachineDeployment and its templates
+	// when the corresponding MachineDeploymentTopology is removed from the list of MachineDeployments.
+	// Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	MachineDeploymentDeletionPolicy MachineDeploymentTopologyDeletionPolicy `json:"machineDeploymentDeletionPolicy,omitempty"`
 }
 
+// MachineDeploymentTopologyDeletionPolicy defines how a MachineDeployment generated by the Cluster topology
+// controller is handled when the corresponding MachineDeploymentTopology is removed.
+type MachineDeploymentTopologyDeletionPolicy string
+
+const (
+	// MachineDeploymentTopologyDeletionPolicyDelete deletes the MachineDeployment, which drains and deletes
+	// its Machines, and the templates cloned for it.
+	MachineDeploymentTopologyDeletionPolicyDelete MachineDeploymentTopologyDeletionPolicy = "Delete"
+
+	// MachineDeploymentTopologyDeletionPolicyOrphan releases the MachineDeployment, its MachineHealthCheck and
+	// its templates from the Cluster topology; those objects are left in place and are no longer managed by the
+	// Cluster topology controller.
+	MachineDeploymentTopologyDeletionPolicyOrphan MachineDeploymentTopologyDeletionPolicy = "Orphan"
+)
+
 // MachineDeploymentTopology specifies the different parameters for a set of worker nodes in the topology.
 // This set of nodes is managed by a MachineDeployment object whose lifecycle is managed by the Cluster controller.
 type MachineDeploymentTopology struct {
@@ -139,6 +220,73 @@ type MachineDeploymentTopology struct {
 	// of this value.
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
+
+	// RolloutStrategy is the deployment strategy to use to replace existing machines with new ones.
+	// If set, it overrides the RolloutStrategy defined in the MachineDeploymentClass.
+	// +optional
+	RolloutStrategy *MachineDeploymentStrategy `json:"rolloutStrategy,omitempty"`
+
+	// NodeLabels are the labels applied to the Nodes of the MachineDeployment.
+	// At runtime these labels are merged with the corresponding labels from the ClusterClass;
+	// in case of conflicts, the value defined here takes precedence.
+	// Changes to NodeLabels are applied to existing Nodes without rolling out new Machines.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// NodeTaints are the taints applied to the Nodes of the MachineDeployment.
+	// At runtime these taints are merged with the corresponding taints from the ClusterClass;
+	// in case of conflicts, i.e. taints with the same key and effect, the taint defined here takes precedence.
+	// Changes to NodeTaints are applied to existing Nodes without rolling out new Machines.
+	// +optional
+	NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+
+	// Autoscaling marks the MachineDeployment as autoscaled by the cluster-autoscaler; if set, the topology controller
+	// sets the cluster-autoscaler min and max size annotations on the MachineDeployment and stops reconciling its
+	// replicas, which are then managed by the cluster-autoscaler.
+	// When the MachineDeployment is created, Replicas is used as initial number of replicas, if set; otherwise MinSize is used.
+	// +optional
+	Autoscaling *MachineDeploymentAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// MachinePoolTopology specifies the different parameters for a pool of worker nodes in the topology.
+// This pool of nodes is managed by a MachinePool object whose lifecycle is managed by the Cluster controller.
+type MachinePoolTopology struct {
+	// Metadata is the metadata applied to the machines of the MachinePool.
+	// At runtime this metadata is merged with the corresponding metadata from the ClusterClass.
+	Metadata ObjectMeta `json:"metadata,omitempty"`
+
+	// Class is the name of the MachinePoolClass used to create the pool of worker nodes.
+	// This should match one of the machine pool classes defined in the ClusterClass object
+	// mentioned in the `Cluster.Spec.Class` field.
+	Class string `json:"class"`
+
+	// Name is the unique identifier for this MachinePoolTopology.
+	// The value is used with other unique identifiers to create a MachinePool's Name
+	// (e.g. cluster's name, etc). In case the name is greater than the allowed maximum length,
+	// the values are hashed together.
+	Name string `json:"name"`
+
+	// FailureDomains is the list of failure domains the machine pool will be created in.
+	// +optional
+	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// Replicas is the number of worker nodes belonging to this pool.
+	// If the value is nil, the MachinePool is created without the number of Replicas (defaulting to one)
+	// and it's assumed that an external entity (like cluster autoscaler) is responsible for the management
+	// of this value.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// MachineDeploymentAutoscaling defines the cluster-autoscaler settings for a MachineDeployment.
+type MachineDeploymentAutoscaling struct {
+	// MinSize is the minimum number of replicas of the MachineDeployment the cluster-autoscaler can scale down to.
+	// +kubebuilder:validation:Minimum=0
+	MinSize int32 `json:"minSize"`
+
+	// MaxSize is the maximum number of replicas of the MachineDeployment the cluster-autoscaler can scale up to.
+	// +kubebuilder:validation:Minimum=1
+	MaxSize int32 `json:"maxSize"`
 }
 
 // ANCHOR_END: ClusterSpec
@@ -191,6 +339,11 @@ type ClusterStatus struct {
 	// FailureDomains is a slice of failure domain objects synced from the infrastructure provider.
 	FailureDomains FailureDomains `json:"failureDomains,omitempty"`
 
+	// MachinesPerFailureDomain is the number of Machines in each of the failure domains of the Cluster,
+	// keyed by failure domain id; Machines being deleted are not counted.
+	// +optional
+	MachinesPerFailureDomain map[string]int32 `json:"machinesPerFailureDomain,omitempty"`
+
 	// FailureReason indicates that there is a fatal problem reconciling the
 	// state, and will be set to a token value suitable for
 	// programmatic interpretation.
@@ -302,6 +455,25 @@ func (c *Cluster) SetConditions(conditions Conditions) {
 }
 
 // GetIPFamily returns a ClusterIPFamily from the configuration provided.
+// GetClusterClassKey returns the key of the ClusterClass used by the managed topology of the Cluster;
+// if spec.topology.classNamespace is not set, the ClusterClass is in the same namespace of the Cluster.
+// NOTE: This func assumes that spec.topology is set.
+func (c *Cluster) GetClusterClassKey() types.NamespacedName {
+	namespace := c.Spec.Topology.ClassNamespace
+	if namespace == "" {
+		namespace = c.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: c.Spec.Topology.Class}
+}
+
+// UsesClusterClass returns true if the managed topology of the Cluster uses the given ClusterClass.
+func (c *Cluster) UsesClusterClass(clusterClass *ClusterClass) bool {
+	if c.Spec.Topology == nil {
+		return false
+	}
+	return c.GetClusterClassKey() == types.NamespacedName{Namespace: clusterClass.Namespace, Name: clusterClass.Name}
+}
+
 func (c *Cluster) GetIPFamily() (ClusterIPFamily, error) {
 	var podCIDRs, serviceCIDRs []string
 	if c.Spec.ClusterNetwork != nil {
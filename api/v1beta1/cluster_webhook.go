@@ -17,21 +17,47 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/blang/semver"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util/version"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// clusterClassReader is used to read ClusterClasses when validating a change to spec.topology.class;
+// it is set when setting up the webhook with the manager.
+var clusterClassReader client.Reader
+
+// clusterClassNamespaces is the set of namespaces whose ClusterClasses can be used by Clusters in other namespaces;
+// it is set via SetClusterClassNamespaces.
+var clusterClassNamespaces = sets.NewString()
+
+// SetClusterClassNamespaces sets the namespaces whose ClusterClasses can be used by Clusters in other namespaces,
+// e.g. to publish a central catalog of ClusterClasses; it must be called before starting the manager.
+func SetClusterClassNamespaces(namespaces ...string) {
+	clusterClassNamespaces = sets.NewString(namespaces...)
+}
+
+// IsClusterClassNamespaceAllowed returns true if the ClusterClasses in the given namespace can be used by
+// Clusters in the given cluster namespace.
+func IsClusterClassNamespaceAllowed(clusterNamespace, clusterClassNamespace string) bool {
+	return clusterClassNamespace == "" || clusterClassNamespace == clusterNamespace || clusterClassNamespaces.Has(clusterClassNamespace)
+}
+
 func (c *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	clusterClassReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
 		Complete()
@@ -105,6 +131,8 @@ func (c *Cluster) validate(old *Cluster) error {
 		)
 	}
 
+	allErrs = append(allErrs, validateDeletionTimeouts(c.Spec.DeletionTimeouts, field.NewPath("spec", "deletionTimeouts"))...)
+
 	// Validate the managed topology, if defined.
 	if c.Spec.Topology != nil {
 		if topologyErrs := c.validateTopology(old); len(topologyErrs) > 0 {
@@ -132,6 +160,43 @@ func (c *Cluster) validateTopology(old *Cluster) field.ErrorList {
 
 	var allErrs field.ErrorList
 
+	// NOTE: MachinePools are behind the MachinePool feature gate flag; the web hook
+	// must prevent the usage of MachinePools in the topology in case the feature flag is disabled.
+	if c.Spec.Topology.Workers != nil && len(c.Spec.Topology.Workers.MachinePools) > 0 && !feature.Gates.Enabled(feature.MachinePool) {
+		allErrs = append(allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "topology", "workers", "machinePools"),
+				"can be set only if the MachinePool feature flag is enabled",
+			),
+		)
+	}
+
+	// ClusterClasses in other namespaces can be used only if their namespace is allowed to provide ClusterClasses to other namespaces.
+	if !IsClusterClassNamespaceAllowed(c.Namespace, c.Spec.Topology.ClassNamespace) {
+		allErrs = append(allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "topology", "classNamespace"),
+				fmt.Sprintf("ClusterClasses in namespace %q cannot be used by Clusters in other namespaces", c.Spec.Topology.ClassNamespace),
+			),
+		)
+	}
+
+	allErrs = append(allErrs, c.ValidateTopologySpec(old)...)
+
+	// Class could be changed only to a ClusterClass compatible with the current one.
+	if old != nil && c.GetClusterClassKey() != old.GetClusterClassKey() {
+		allErrs = append(allErrs, c.validateTopologyClassChange(old)...)
+	}
+
+	return allErrs
+}
+
+// ValidateTopologySpec validates the managed topology of the Cluster and, if old is set, that the changes from old are allowed.
+// NOTE: Feature gates are not checked, and changes to the class are not validated given that this requires reading
+// the ClusterClasses; this allows validating a Cluster without a management cluster, e.g. in CI pipelines.
+func (c *Cluster) ValidateTopologySpec(old *Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+
 	// class should be defined.
 	if len(c.Spec.Topology.Class) == 0 {
 		allErrs = append(
@@ -144,6 +209,20 @@ func (c *Cluster) validateTopology(old *Cluster) field.ErrorList {
 		)
 	}
 
+	// classNamespace, if defined, should be a valid namespace name.
+	if c.Spec.Topology.ClassNamespace != "" {
+		for _, msg := range validation.IsDNS1123Label(c.Spec.Topology.ClassNamespace) {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("spec", "topology", "classNamespace"),
+					c.Spec.Topology.ClassNamespace,
+					msg,
+				),
+			)
+		}
+	}
+
 	// version should be valid.
 	if !version.KubeSemver.MatchString(c.Spec.Topology.Version) {
 		allErrs = append(
@@ -171,6 +250,65 @@ func (c *Cluster) validateTopology(old *Cluster) field.ErrorList {
 			}
 			names.Insert(md.Name)
 		}
+
+		// Rollout strategies must be valid.
+		for i, md := range c.Spec.Topology.Workers.MachineDeployments {
+			if md.RolloutStrategy == nil {
+				continue
+			}
+			total := 1
+			if md.Replicas != nil {
+				total = int(*md.Replicas)
+			}
+			allErrs = append(allErrs, md.RolloutStrategy.validate(total, field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i).Child("rolloutStrategy"))...)
+		}
+
+		// Node labels and taints must be valid.
+		for i, md := range c.Spec.Topology.Workers.MachineDeployments {
+			allErrs = append(allErrs, validateNodeLabelsAndTaints(md.NodeLabels, md.NodeTaints, field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i))...)
+		}
+
+		// Autoscaling settings must be valid.
+		for i, md := range c.Spec.Topology.Workers.MachineDeployments {
+			allErrs = append(allErrs, validateMachineDeploymentAutoscaling(md, field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i))...)
+		}
+
+		// MachinePool names must be unique.
+		machinePoolNames := sets.String{}
+		for _, mp := range c.Spec.Topology.Workers.MachinePools {
+			if machinePoolNames.Has(mp.Name) {
+				allErrs = append(allErrs,
+					field.Invalid(
+						field.NewPath("spec", "topology", "workers", "machinePools"),
+						mp,
+						fmt.Sprintf("MachinePool names should be unique. MachinePool with name %q is defined more than once.", mp.Name),
+					),
+				)
+			}
+			machinePoolNames.Insert(mp.Name)
+		}
+	}
+
+	// Variable names must be unique.
+	// NOTE: Values are validated against the schema of the corresponding ClusterClass variables
+	// by the topology controller, given that the ClusterClass is not available here.
+	variableNames := sets.String{}
+	for i, variable := range c.Spec.Topology.Variables {
+		fldPath := field.NewPath("spec", "topology", "variables").Index(i)
+		if variable.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("name"), "variable name must be defined"))
+			continue
+		}
+		if variableNames.Has(variable.Name) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					fldPath.Child("name"),
+					variable.Name,
+					fmt.Sprintf("variable names should be unique. Variable with name %q is defined more than once.", variable.Name),
+				),
+			)
+		}
+		variableNames.Insert(variable.Name)
 	}
 
 	switch old {
@@ -197,18 +335,6 @@ func (c *Cluster) validateTopology(old *Cluster) field.ErrorList {
 			)
 		}
 	default: // On update
-		// Class could not be mutated.
-		if c.Spec.Topology.Class != old.Spec.Topology.Class {
-			allErrs = append(
-				allErrs,
-				field.Invalid(
-					field.NewPath("spec", "topology", "class"),
-					c.Spec.Topology.Class,
-					"class cannot be changed",
-				),
-			)
-		}
-
 		// Version could only be increased.
 		inVersion, err := semver.ParseTolerant(c.Spec.Topology.Version)
 		if err != nil {
@@ -233,15 +359,188 @@ func (c *Cluster) validateTopology(old *Cluster) field.ErrorList {
 				),
 			)
 		}
-		if inVersion.NE(semver.Version{}) && oldVersion.NE(semver.Version{}) && version.CompareWithBuildIdentifiers(inVersion, oldVersion) == -1 {
-			allErrs = append(
-				allErrs,
-				field.Invalid(
-					field.NewPath("spec", "topology", "version"),
-					c.Spec.Topology.Version,
-					"cannot be decreased",
-				),
-			)
+		if inVersion.NE(semver.Version{}) && oldVersion.NE(semver.Version{}) {
+			if version.Compare(inVersion, oldVersion, version.WithBuildTags()) == -1 {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						field.NewPath("spec", "topology", "version"),
+						c.Spec.Topology.Version,
+						"cannot be decreased",
+					),
+				)
+			}
+
+			// Version could only be increased by one minor version at a time.
+			if err := version.ValidateUpgrade(oldVersion, inVersion); err != nil {
+				allErrs = append(
+					allErrs,
+					field.Forbidden(
+						field.NewPath("spec", "topology", "version"),
+						fmt.Sprintf("cannot be increased from %s to %s: %v", old.Spec.Topology.Version, c.Spec.Topology.Version, err),
+					),
+				)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateTopologyClassChange validates that the Cluster can be moved from the current ClusterClass to the new one.
+// NOTE: The ClusterClasses are read using the clusterClassReader; if it is not set, changing the class is not allowed.
+func (c *Cluster) validateTopologyClassChange(old *Cluster) field.ErrorList {
+	fldPath := field.NewPath("spec", "topology", "class")
+
+	if clusterClassReader == nil {
+		return field.ErrorList{field.Invalid(fldPath, c.Spec.Topology.Class, "class cannot be changed")}
+	}
+
+	// NOTE: webhook.Validator does not provide a context.
+	ctx := context.Background()
+
+	oldClusterClass := &ClusterClass{}
+	if err := clusterClassReader.Get(ctx, old.GetClusterClassKey(), oldClusterClass); err != nil {
+		return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("class cannot be changed: failed to get the current ClusterClass %q: %v", old.Spec.Topology.Class, err))}
+	}
+
+	newClusterClass := &ClusterClass{}
+	if err := clusterClassReader.Get(ctx, c.GetClusterClassKey(), newClusterClass); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, c.Spec.Topology.Class, fmt.Sprintf("failed to get ClusterClass: %v", err))}
+	}
+
+	var allErrs field.ErrorList
+	if errs := newClusterClass.ValidateRebaseFrom(oldClusterClass); len(errs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("class cannot be changed from %q to %q, ClusterClasses are not compatible: %v", old.Spec.Topology.Class, c.Spec.Topology.Class, errs.ToAggregate())))
+	}
+
+	allErrs = append(allErrs, c.ValidateTopologyClasses(newClusterClass)...)
+
+	return allErrs
+}
+
+// ValidateTopologyClasses validates that the MachineDeployment and MachinePool classes used in the managed topology
+// of the Cluster are defined in the given ClusterClass.
+func (c *Cluster) ValidateTopologyClasses(clusterClass *ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// MachineDeployment classes used in the topology must be defined in the ClusterClass.
+	if c.Spec.Topology.Workers != nil {
+		classes := clusterClass.Spec.Workers.classNames()
+		for i, md := range c.Spec.Topology.Workers.MachineDeployments {
+			if !classes.Has(md.Class) {
+				allErrs = append(allErrs,
+					field.Invalid(
+						field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i).Child("class"),
+						md.Class,
+						fmt.Sprintf("MachineDeployment class is not defined in ClusterClass %q", clusterClass.Name),
+					),
+				)
+			}
+		}
+
+		// MachinePool classes used in the topology must be defined in the ClusterClass.
+		machinePoolClasses := clusterClass.Spec.Workers.machinePoolClassNames()
+		for i, mp := range c.Spec.Topology.Workers.MachinePools {
+			if !machinePoolClasses.Has(mp.Class) {
+				allErrs = append(allErrs,
+					field.Invalid(
+						field.NewPath("spec", "topology", "workers", "machinePools").Index(i).Child("class"),
+						mp.Class,
+						fmt.Sprintf("MachinePool class is not defined in ClusterClass %q", clusterClass.Name),
+					),
+				)
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateNodeLabelsAndTaints validates the labels and taints to be applied to the Nodes of a MachineDeployment.
+// NOTE: Label keys and values, as well as taint keys and values, must be valid label keys and values, thus ensuring they can
+// be passed to the kubelet using the --node-labels and --register-with-taints flags.
+func validateNodeLabelsAndTaints(labels map[string]string, taints []corev1.Taint, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for k, v := range labels {
+		for _, msg := range validation.IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeLabels"), k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeLabels").Key(k), v, msg))
+		}
+	}
+
+	taintKeys := sets.NewString()
+	for i, taint := range taints {
+		taintPath := fldPath.Child("nodeTaints").Index(i)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("key"), taint.Key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("value"), taint.Value, msg))
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			allErrs = append(allErrs, field.NotSupported(taintPath.Child("effect"), taint.Effect,
+				[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+		}
+		if taint.TimeAdded != nil {
+			allErrs = append(allErrs, field.Forbidden(taintPath.Child("timeAdded"), "timeAdded is set by the system and cannot be specified"))
+		}
+
+		key := fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+		if taintKeys.Has(key) {
+			allErrs = append(allErrs, field.Duplicate(taintPath, key))
+		}
+		taintKeys.Insert(key)
+	}
+
+	return allErrs
+}
+
+// validateMachineDeploymentAutoscaling validates the autoscaling settings of a MachineDeploymentTopology.
+func validateMachineDeploymentAutoscaling(md MachineDeploymentTopology, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if md.Autoscaling == nil {
+		return allErrs
+	}
+
+	if md.Autoscaling.MinSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("autoscaling", "minSize"), md.Autoscaling.MinSize, "must be greater than or equal to 0"))
+	}
+	if md.Autoscaling.MaxSize < md.Autoscaling.MinSize || md.Autoscaling.MaxSize < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("autoscaling", "maxSize"), md.Autoscaling.MaxSize, "must be greater than or equal to 1 and to minSize"))
+	}
+	if md.Replicas != nil && (*md.Replicas < md.Autoscaling.MinSize || *md.Replicas > md.Autoscaling.MaxSize) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), *md.Replicas, "must be between autoscaling minSize and maxSize"))
+	}
+
+	return allErrs
+}
+
+// validateDeletionTimeouts validates the timeouts of the phases of the Cluster deletion.
+func validateDeletionTimeouts(timeouts *ClusterDeletionTimeouts, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if timeouts == nil {
+		return allErrs
+	}
+
+	phases := []struct {
+		name    string
+		timeout *metav1.Duration
+	}{
+		{name: "workers", timeout: timeouts.Workers},
+		{name: "controlPlane", timeout: timeouts.ControlPlane},
+		{name: "infrastructure", timeout: timeouts.Infrastructure},
+	}
+	for _, phase := range phases {
+		if phase.timeout != nil && phase.timeout.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(phase.name), phase.timeout.Duration.String(), "must be greater than or equal to 0"))
 		}
 	}
 
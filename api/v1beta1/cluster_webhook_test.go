@@ -17,14 +17,24 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api/feature"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestClusterDefaultNamespaces(t *testing.T) {
@@ -298,6 +308,46 @@ func TestClusterTopologyValidation(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "should return error when upgrading topology version skipping a minor version",
+			expectErr: true,
+			old: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.20.3",
+					},
+				},
+			},
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.22.3",
+					},
+				},
+			},
+		},
+		{
+			name:      "should update when upgrading topology version to the next minor version with build metadata",
+			expectErr: false,
+			old: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.21.3+vmware.1",
+					},
+				},
+			},
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.22.3+vmware.1",
+					},
+				},
+			},
+		},
 		{
 			name:      "should return error when duplicated MachineDeployments names exists in a Topology",
 			expectErr: true,
@@ -342,6 +392,53 @@ func TestClusterTopologyValidation(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "should return error when duplicated variable names exists in a Topology",
+			expectErr: true,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Variables: []ClusterVariable{
+							{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+							{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"eu-west-1"`)}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "should return error when a variable in a Topology has no name",
+			expectErr: true,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Variables: []ClusterVariable{
+							{Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "should pass when variable names in a Topology are unique",
+			expectErr: false,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Variables: []ClusterVariable{
+							{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+							{Name: "instanceType", Value: apiextensionsv1.JSON{Raw: []byte(`"m5.large"`)}},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:      "should return error on create when both Topology and control plane ref are defined",
 			expectErr: true,
@@ -454,12 +551,285 @@ func TestClusterTopologyValidation(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "should return error when a MachineDeploymentTopology rolloutStrategy has an invalid maxSurge",
+			expectErr: true,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Workers: &WorkersTopology{
+							MachineDeployments: []MachineDeploymentTopology{
+								{
+									Name: "aa",
+									RolloutStrategy: &MachineDeploymentStrategy{
+										Type: RollingUpdateMachineDeploymentStrategyType,
+										RollingUpdate: &MachineRollingUpdateDeployment{
+											MaxSurge: &intstr.IntOrString{Type: intstr.String, StrVal: "foo"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "should pass when a MachineDeploymentTopology rolloutStrategy is valid",
+			expectErr: false,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Workers: &WorkersTopology{
+							MachineDeployments: []MachineDeploymentTopology{
+								{
+									Name: "aa",
+									RolloutStrategy: &MachineDeploymentStrategy{
+										Type: RollingUpdateMachineDeploymentStrategyType,
+										RollingUpdate: &MachineRollingUpdateDeployment{
+											MaxSurge:       &intstr.IntOrString{Type: intstr.String, StrVal: "25%"},
+											MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "should return error when a MachineDeploymentTopology has an invalid node label",
+			expectErr: true,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Workers: &WorkersTopology{
+							MachineDeployments: []MachineDeploymentTopology{
+								{
+									Name: "aa",
+									NodeLabels: map[string]string{
+										"foo": "bar,baz",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "should return error when a MachineDeploymentTopology has a node taint with an invalid effect",
+			expectErr: true,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Workers: &WorkersTopology{
+							MachineDeployments: []MachineDeploymentTopology{
+								{
+									Name: "aa",
+									NodeTaints: []corev1.Taint{
+										{Key: "foo", Value: "bar", Effect: "Invalid"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "should return error when a MachineDeploymentTopology has duplicated node taints",
+			expectErr: true,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Workers: &WorkersTopology{
+							MachineDeployments: []MachineDeploymentTopology{
+								{
+									Name: "aa",
+									NodeTaints: []corev1.Taint{
+										{Key: "foo", Value: "bar", Effect: corev1.TaintEffectNoSchedule},
+										{Key: "foo", Value: "baz", Effect: corev1.TaintEffectNoSchedule},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:      "should pass when a MachineDeploymentTopology has valid node labels and taints",
+			expectErr: false,
+			in: &Cluster{
+				Spec: ClusterSpec{
+					Topology: &Topology{
+						Class:   "foo",
+						Version: "v1.19.1",
+						Workers: &WorkersTopology{
+							MachineDeployments: []MachineDeploymentTopology{
+								{
+									Name: "aa",
+									NodeLabels: map[string]string{
+										"node-role.kubernetes.io/worker": "",
+										"foo":                            "bar",
+									},
+									NodeTaints: []corev1.Taint{
+										{Key: "foo", Value: "bar", Effect: corev1.TaintEffectNoSchedule},
+										{Key: "foo", Effect: corev1.TaintEffectNoExecute},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.in.validate(tt.old)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClusterTopologyClassChange(t *testing.T) {
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to set Cluster.Topologies.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	ref := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "barTemplate",
+		Name:       "baz",
+		Namespace:  metav1.NamespaceDefault,
+	}
+	incompatibleRef := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "another-barTemplate",
+		Name:       "baz",
+		Namespace:  metav1.NamespaceDefault,
+	}
+	clusterClass := func(name string, infrastructureRef *corev1.ObjectReference, workerClasses ...string) *ClusterClass {
+		cc := &ClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec: ClusterClassSpec{
+				Infrastructure: LocalObjectTemplate{Ref: infrastructureRef},
+				ControlPlane: ControlPlaneClass{
+					LocalObjectTemplate: LocalObjectTemplate{Ref: ref},
+				},
+			},
+		}
+		for _, class := range workerClasses {
+			cc.Spec.Workers.MachineDeployments = append(cc.Spec.Workers.MachineDeployments, MachineDeploymentClass{
+				Class: class,
+				Template: MachineDeploymentClassTemplate{
+					Bootstrap:      LocalObjectTemplate{Ref: ref},
+					Infrastructure: LocalObjectTemplate{Ref: ref},
+				},
+			})
+		}
+		return cc
+	}
+	cluster := func(class string, workerClasses ...string) *Cluster {
+		c := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1"},
+			Spec: ClusterSpec{
+				Topology: &Topology{
+					Class:   class,
+					Version: "v1.19.1",
+					Workers: &WorkersTopology{},
+				},
+			},
+		}
+		for i, class := range workerClasses {
+			c.Spec.Topology.Workers.MachineDeployments = append(c.Spec.Topology.Workers.MachineDeployments, MachineDeploymentTopology{
+				Class: class,
+				Name:  fmt.Sprintf("md%d", i),
+			})
+		}
+		return c
+	}
+
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		clusterClass("current", ref, "linux-worker"),
+		clusterClass("compatible", ref, "linux-worker", "windows-worker"),
+		clusterClass("incompatible-infrastructure", incompatibleRef, "linux-worker"),
+		clusterClass("missing-worker-class", ref, "windows-worker"),
+	).Build()
+
+	tests := []struct {
+		name      string
+		reader    client.Reader
+		in        *Cluster
+		old       *Cluster
+		expectErr bool
+	}{
+		{
+			name:      "should pass when changing to a compatible ClusterClass",
+			reader:    reader,
+			old:       cluster("current", "linux-worker"),
+			in:        cluster("compatible", "linux-worker"),
+			expectErr: false,
+		},
+		{
+			name:      "should return error when changing to a ClusterClass with an incompatible infrastructure",
+			reader:    reader,
+			old:       cluster("current", "linux-worker"),
+			in:        cluster("incompatible-infrastructure", "linux-worker"),
+			expectErr: true,
+		},
+		{
+			name:      "should return error when changing to a ClusterClass without a MachineDeployment class in use",
+			reader:    reader,
+			old:       cluster("current", "linux-worker"),
+			in:        cluster("missing-worker-class", "linux-worker"),
+			expectErr: true,
+		},
+		{
+			name:      "should return error when changing to a ClusterClass which does not exist",
+			reader:    reader,
+			old:       cluster("current", "linux-worker"),
+			in:        cluster("does-not-exist", "linux-worker"),
+			expectErr: true,
+		},
+		{
+			name:      "should return error when ClusterClasses can't be read",
+			reader:    nil,
+			old:       cluster("current", "linux-worker"),
+			in:        cluster("compatible", "linux-worker"),
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
+			defer func(r client.Reader) { clusterClassReader = r }(clusterClassReader)
+			clusterClassReader = tt.reader
+
 			err := tt.in.validate(tt.old)
 			if tt.expectErr {
 				g.Expect(err).To(HaveOccurred())
@@ -469,3 +839,313 @@ func TestClusterTopologyValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterTopologyMachinePoolValidation(t *testing.T) {
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to set Cluster.Topologies.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	cluster := func(machinePools ...MachinePoolTopology) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1"},
+			Spec: ClusterSpec{
+				Topology: &Topology{
+					Class:   "foo",
+					Version: "v1.19.1",
+					Workers: &WorkersTopology{
+						MachinePools: machinePools,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		machinePoolEnabled bool
+		in                 *Cluster
+		expectErr          bool
+	}{
+		{
+			name:               "should return error when MachinePools are used with the MachinePool feature flag disabled",
+			machinePoolEnabled: false,
+			in:                 cluster(MachinePoolTopology{Class: "pool-class", Name: "pool1"}),
+			expectErr:          true,
+		},
+		{
+			name:               "should return error when MachinePool names are not unique",
+			machinePoolEnabled: true,
+			in: cluster(
+				MachinePoolTopology{Class: "pool-class", Name: "pool1"},
+				MachinePoolTopology{Class: "pool-class", Name: "pool1"},
+			),
+			expectErr: true,
+		},
+		{
+			name:               "should pass when MachinePools are used with the MachinePool feature flag enabled",
+			machinePoolEnabled: true,
+			in: cluster(
+				MachinePoolTopology{Class: "pool-class", Name: "pool1"},
+				MachinePoolTopology{Class: "pool-class", Name: "pool2"},
+			),
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, tt.machinePoolEnabled)()
+
+			err := tt.in.validate(nil)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidateNodeLabelsAndTaints(t *testing.T) {
+	now := metav1.Now()
+
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		taints    []corev1.Taint
+		expectErr bool
+	}{
+		{
+			name: "pass without labels and taints",
+		},
+		{
+			name:   "pass with valid labels and taints",
+			labels: map[string]string{"node-role.kubernetes.io/worker": "", "foo": "bar"},
+			taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+		{
+			name:      "fail with an invalid label key",
+			labels:    map[string]string{"foo=bar": "baz"},
+			expectErr: true,
+		},
+		{
+			name:      "fail with an invalid label value",
+			labels:    map[string]string{"foo": "bar:baz"},
+			expectErr: true,
+		},
+		{
+			name:      "fail with an invalid taint key",
+			taints:    []corev1.Taint{{Key: "foo,bar", Effect: corev1.TaintEffectNoSchedule}},
+			expectErr: true,
+		},
+		{
+			name:      "fail with an invalid taint value",
+			taints:    []corev1.Taint{{Key: "foo", Value: "bar=baz", Effect: corev1.TaintEffectNoSchedule}},
+			expectErr: true,
+		},
+		{
+			name:      "fail with a missing taint effect",
+			taints:    []corev1.Taint{{Key: "foo"}},
+			expectErr: true,
+		},
+		{
+			name:      "fail with timeAdded set",
+			taints:    []corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoExecute, TimeAdded: &now}},
+			expectErr: true,
+		},
+		{
+			name: "fail with duplicated taints",
+			taints: []corev1.Taint{
+				{Key: "foo", Value: "bar", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "foo", Value: "baz", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := validateNodeLabelsAndTaints(tt.labels, tt.taints, field.NewPath("spec"))
+			if tt.expectErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateMachineDeploymentAutoscaling(t *testing.T) {
+	tests := []struct {
+		name      string
+		md        MachineDeploymentTopology
+		expectErr bool
+	}{
+		{
+			name: "pass without autoscaling",
+			md:   MachineDeploymentTopology{Replicas: pointer.Int32(3)},
+		},
+		{
+			name: "pass with valid autoscaling",
+			md:   MachineDeploymentTopology{Autoscaling: &MachineDeploymentAutoscaling{MinSize: 0, MaxSize: 5}},
+		},
+		{
+			name: "pass with valid autoscaling and initial replicas",
+			md:   MachineDeploymentTopology{Replicas: pointer.Int32(3), Autoscaling: &MachineDeploymentAutoscaling{MinSize: 1, MaxSize: 5}},
+		},
+		{
+			name:      "fail with maxSize lower than minSize",
+			md:        MachineDeploymentTopology{Autoscaling: &MachineDeploymentAutoscaling{MinSize: 3, MaxSize: 2}},
+			expectErr: true,
+		},
+		{
+			name:      "fail with maxSize equal to 0",
+			md:        MachineDeploymentTopology{Autoscaling: &MachineDeploymentAutoscaling{MinSize: 0, MaxSize: 0}},
+			expectErr: true,
+		},
+		{
+			name:      "fail with initial replicas out of range",
+			md:        MachineDeploymentTopology{Replicas: pointer.Int32(6), Autoscaling: &MachineDeploymentAutoscaling{MinSize: 1, MaxSize: 5}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := validateMachineDeploymentAutoscaling(tt.md, field.NewPath("spec"))
+			if tt.expectErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateDeletionTimeouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeouts  *ClusterDeletionTimeouts
+		expectErr bool
+	}{
+		{
+			name: "pass without deletion timeouts",
+		},
+		{
+			name: "pass with valid deletion timeouts",
+			timeouts: &ClusterDeletionTimeouts{
+				Workers:        &metav1.Duration{Duration: 10 * time.Minute},
+				ControlPlane:   &metav1.Duration{Duration: 0},
+				Infrastructure: &metav1.Duration{Duration: time.Hour},
+			},
+		},
+		{
+			name:      "fail with a negative workers timeout",
+			timeouts:  &ClusterDeletionTimeouts{Workers: &metav1.Duration{Duration: -time.Minute}},
+			expectErr: true,
+		},
+		{
+			name:      "fail with a negative control plane timeout",
+			timeouts:  &ClusterDeletionTimeouts{ControlPlane: &metav1.Duration{Duration: -time.Minute}},
+			expectErr: true,
+		},
+		{
+			name:      "fail with a negative infrastructure timeout",
+			timeouts:  &ClusterDeletionTimeouts{Infrastructure: &metav1.Duration{Duration: -time.Minute}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := validateDeletionTimeouts(tt.timeouts, field.NewPath("spec", "deletionTimeouts"))
+			if tt.expectErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestClusterTopologyClassNamespaceValidation(t *testing.T) {
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to set Cluster.Topologies.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	cluster := func(classNamespace string) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1"},
+			Spec: ClusterSpec{
+				Topology: &Topology{
+					Class:          "foo",
+					ClassNamespace: classNamespace,
+					Version:        "v1.19.1",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                   string
+		clusterClassNamespaces []string
+		in                     *Cluster
+		expectErr              bool
+	}{
+		{
+			name:      "should pass when classNamespace is not set",
+			in:        cluster(""),
+			expectErr: false,
+		},
+		{
+			name:      "should pass when classNamespace is the namespace of the Cluster",
+			in:        cluster(metav1.NamespaceDefault),
+			expectErr: false,
+		},
+		{
+			name:                   "should pass when classNamespace is allowed to provide ClusterClasses to other namespaces",
+			clusterClassNamespaces: []string{"catalog"},
+			in:                     cluster("catalog"),
+			expectErr:              false,
+		},
+		{
+			name:                   "should return error when classNamespace is not allowed to provide ClusterClasses to other namespaces",
+			clusterClassNamespaces: []string{"catalog"},
+			in:                     cluster("other"),
+			expectErr:              true,
+		},
+		{
+			name:                   "should return error when classNamespace is not a valid namespace name",
+			clusterClassNamespaces: []string{"Catalog"},
+			in:                     cluster("Catalog"),
+			expectErr:              true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			defer func(namespaces sets.String) { clusterClassNamespaces = namespaces }(clusterClassNamespaces)
+			SetClusterClassNamespaces(tt.clusterClassNamespaces...)
+
+			err := tt.in.validate(nil)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// ConvertToAPIExtensionsJSONSchemaProps converts a JSONSchemaProps
+// to apiextensions.JSONSchemaProps, which can be used to build a schema validator.
+func ConvertToAPIExtensionsJSONSchemaProps(schema *JSONSchemaProps) (*apiextensions.JSONSchemaProps, error) {
+	props := &apiextensions.JSONSchemaProps{
+		Type:             schema.Type,
+		Required:         schema.Required,
+		MaxItems:         schema.MaxItems,
+		MinItems:         schema.MinItems,
+		UniqueItems:      schema.UniqueItems,
+		Format:           schema.Format,
+		MaxLength:        schema.MaxLength,
+		MinLength:        schema.MinLength,
+		Pattern:          schema.Pattern,
+		ExclusiveMaximum: schema.ExclusiveMaximum,
+		ExclusiveMinimum: schema.ExclusiveMinimum,
+	}
+
+	if schema.Maximum != nil {
+		f := float64(*schema.Maximum)
+		props.Maximum = &f
+	}
+
+	if schema.Minimum != nil {
+		f := float64(*schema.Minimum)
+		props.Minimum = &f
+	}
+
+	if schema.Default != nil && schema.Default.Raw != nil {
+		var v interface{}
+		if err := json.Unmarshal(schema.Default.Raw, &v); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse default value %q", string(schema.Default.Raw))
+		}
+		defaultJSON := apiextensions.JSON(v)
+		props.Default = &defaultJSON
+	}
+
+	for _, enum := range schema.Enum {
+		var v interface{}
+		if err := json.Unmarshal(enum.Raw, &v); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse enum value %q", string(enum.Raw))
+		}
+		props.Enum = append(props.Enum, v)
+	}
+
+	if len(schema.Properties) > 0 {
+		props.Properties = map[string]apiextensions.JSONSchemaProps{}
+		for name, property := range schema.Properties {
+			property := property
+			apiExtensionsProperty, err := ConvertToAPIExtensionsJSONSchemaProps(&property)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to convert schema of property %q", name)
+			}
+			props.Properties[name] = *apiExtensionsProperty
+		}
+	}
+
+	if schema.Items != nil {
+		apiExtensionsItems, err := ConvertToAPIExtensionsJSONSchemaProps(schema.Items)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert schema of items")
+		}
+		props.Items = &apiextensions.JSONSchemaPropsOrArray{Schema: apiExtensionsItems}
+	}
+
+	return props, nil
+}
@@ -17,27 +17,47 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+	"crypto/x509"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsvalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// clusterReader is used to read Clusters when validating the deletion of a ClusterClass;
+// it is set when setting up the webhook with the manager.
+var clusterReader client.Reader
+
+// restMapper is used to check that the kinds referenced by a ClusterClass are installed;
+// it is set when setting up the webhook with the manager.
+var restMapper meta.RESTMapper
+
 func (in *ClusterClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	clusterReader = mgr.GetAPIReader()
+	restMapper = mgr.GetRESTMapper()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(in).
 		Complete()
 }
 
-// +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1beta1-clusterclass,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clusterclasses,versions=v1beta1,name=validation.clusterclass.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update;delete,path=/validate-cluster-x-k8s-io-v1beta1-clusterclass,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clusterclasses,versions=v1beta1,name=validation.clusterclass.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-cluster-x-k8s-io-v1beta1-clusterclass,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clusterclasses,versions=v1beta1,name=default.clusterclass.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 var _ webhook.Validator = &ClusterClass{}
@@ -53,9 +73,22 @@ func (in *ClusterClass) Default() {
 		defaultNamespace(in.Spec.ControlPlane.MachineInfrastructure.Ref, in.Namespace)
 	}
 
+	if in.Spec.ControlPlane.MachineHealthCheck != nil {
+		defaultNamespace(in.Spec.ControlPlane.MachineHealthCheck.RemediationTemplate, in.Namespace)
+	}
+
 	for i := range in.Spec.Workers.MachineDeployments {
 		defaultNamespace(in.Spec.Workers.MachineDeployments[i].Template.Bootstrap.Ref, in.Namespace)
 		defaultNamespace(in.Spec.Workers.MachineDeployments[i].Template.Infrastructure.Ref, in.Namespace)
+
+		if in.Spec.Workers.MachineDeployments[i].MachineHealthCheck != nil {
+			defaultNamespace(in.Spec.Workers.MachineDeployments[i].MachineHealthCheck.RemediationTemplate, in.Namespace)
+		}
+	}
+
+	for i := range in.Spec.Workers.MachinePools {
+		defaultNamespace(in.Spec.Workers.MachinePools[i].Template.Bootstrap.Ref, in.Namespace)
+		defaultNamespace(in.Spec.Workers.MachinePools[i].Template.Infrastructure.Ref, in.Namespace)
 	}
 }
 
@@ -81,6 +114,31 @@ func (in *ClusterClass) ValidateUpdate(old runtime.Object) error {
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (in *ClusterClass) ValidateDelete() error {
+	// NOTE: The Clusters are read using the clusterReader; if it is not set, e.g. in unit tests,
+	// the check is skipped and deletion is protected by the ClusterClass finalizer only.
+	if clusterReader == nil {
+		return nil
+	}
+
+	clusters := &ClusterList{}
+	// NOTE: Clusters are listed in all namespaces, because Clusters can use ClusterClasses in other namespaces.
+	if err := clusterReader.List(context.Background(), clusters); err != nil {
+		return apierrors.NewInternalError(errors.Wrapf(err, "failed to list Clusters using ClusterClass %q", in.Name))
+	}
+
+	var names []string
+	for i := range clusters.Items {
+		if clusters.Items[i].UsesClusterClass(in) {
+			names = append(names, client.ObjectKeyFromObject(&clusters.Items[i]).String())
+		}
+	}
+	if len(names) > 0 {
+		return apierrors.NewForbidden(
+			GroupVersion.WithResource("clusterclasses").GroupResource(),
+			in.Name,
+			errors.Errorf("ClusterClass is in use by Clusters: %s", strings.Join(names, ", ")),
+		)
+	}
 	return nil
 }
 
@@ -96,33 +154,476 @@ func (in *ClusterClass) validate(old *ClusterClass) error {
 
 	var allErrs field.ErrorList
 
+	// NOTE: MachinePools are behind the MachinePool feature gate flag; the web hook
+	// must prevent the usage of MachinePool classes in case the feature flag is disabled.
+	if len(in.Spec.Workers.MachinePools) > 0 && !feature.Gates.Enabled(feature.MachinePool) {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "workers", "machinePools"),
+			"can be set only if the MachinePool feature flag is enabled",
+		))
+	}
+
+	allErrs = append(allErrs, in.ValidateSpec(old)...)
+
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("ClusterClass").GroupKind(), in.Name, allErrs)
+	}
+	return nil
+}
+
+// ValidateSpec validates the ClusterClass and, if old is set, that the changes from old are compatible.
+// NOTE: Feature gates are not checked, and referenced kinds are checked to be installed only if the webhook
+// has been set up with a manager; this allows validating a ClusterClass without a management cluster, e.g. in CI pipelines.
+func (in *ClusterClass) ValidateSpec(old *ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
 	// Ensure all references are valid.
 	allErrs = append(allErrs, in.validateAllRefs()...)
 
 	// Ensure all MachineDeployment classes are unique.
 	allErrs = append(allErrs, in.Spec.Workers.validateUniqueClasses(field.NewPath("spec", "workers"))...)
 
+	// Ensure all variables are valid.
+	allErrs = append(allErrs, in.validateVariables(field.NewPath("spec", "variables"))...)
+
+	// Ensure all patches are valid.
+	allErrs = append(allErrs, in.validatePatches(field.NewPath("spec", "patches"))...)
+
+	// Ensure all MachineHealthChecks are valid.
+	allErrs = append(allErrs, in.validateMachineHealthChecks()...)
+
+	// Ensure all rollout strategies are valid.
+	for i, class := range in.Spec.Workers.MachineDeployments {
+		if class.RolloutStrategy != nil {
+			allErrs = append(allErrs, class.RolloutStrategy.validate(1, field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("rolloutStrategy"))...)
+		}
+	}
+
+	// Ensure all node labels and taints are valid.
+	for i, class := range in.Spec.Workers.MachineDeployments {
+		allErrs = append(allErrs, validateNodeLabelsAndTaints(class.Template.NodeLabels, class.Template.NodeTaints, field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("template"))...)
+	}
+
 	// Ensure spec changes are compatible.
 	allErrs = append(allErrs, in.validateCompatibleSpecChanges(old)...)
 
-	if len(allErrs) > 0 {
-		return apierrors.NewInvalid(GroupVersion.WithKind("ClusterClass").GroupKind(), in.Name, allErrs)
-	}
-	return nil
+	return allErrs
 }
 
 func (in *ClusterClass) validateAllRefs() field.ErrorList {
 	var allErrs field.ErrorList
 
-	allErrs = append(allErrs, in.Spec.Infrastructure.isValid(in.Namespace, field.NewPath("spec", "infrastructure"))...)
-	allErrs = append(allErrs, in.Spec.ControlPlane.LocalObjectTemplate.isValid(in.Namespace, field.NewPath("spec", "controlPlane"))...)
+	// validateRef checks that a reference is valid and, only if it is, that the referenced kind is installed.
+	validateRef := func(r *LocalObjectTemplate, pathPrefix *field.Path) {
+		errs := r.isValid(in.Namespace, pathPrefix)
+		if len(errs) == 0 {
+			errs = r.validateKindInstalled(pathPrefix)
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	validateRef(&in.Spec.Infrastructure, field.NewPath("spec", "infrastructure"))
+	validateRef(&in.Spec.ControlPlane.LocalObjectTemplate, field.NewPath("spec", "controlPlane"))
 	if in.Spec.ControlPlane.MachineInfrastructure != nil {
-		allErrs = append(allErrs, in.Spec.ControlPlane.MachineInfrastructure.isValid(in.Namespace, field.NewPath("spec", "controlPlane", "machineInfrastructure"))...)
+		validateRef(in.Spec.ControlPlane.MachineInfrastructure, field.NewPath("spec", "controlPlane", "machineInfrastructure"))
+	}
+
+	for i := range in.Spec.Workers.MachineDeployments {
+		class := &in.Spec.Workers.MachineDeployments[i]
+		validateRef(&class.Template.Bootstrap, field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("template", "bootstrap"))
+		validateRef(&class.Template.Infrastructure, field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("template", "infrastructure"))
+	}
+
+	for i := range in.Spec.Workers.MachinePools {
+		class := &in.Spec.Workers.MachinePools[i]
+		validateRef(&class.Template.Bootstrap, field.NewPath("spec", "workers", "machinePools").Index(i).Child("template", "bootstrap"))
+		validateRef(&class.Template.Infrastructure, field.NewPath("spec", "workers", "machinePools").Index(i).Child("template", "infrastructure"))
+	}
+
+	return allErrs
+}
+
+func (in *ClusterClass) validateMachineHealthChecks() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if in.Spec.ControlPlane.MachineHealthCheck != nil {
+		fldPath := field.NewPath("spec", "controlPlane", "machineHealthCheck")
+
+		// A MachineHealthCheck for the control plane requires the control plane to be Machine based.
+		if in.Spec.ControlPlane.MachineInfrastructure == nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "can be set only if spec.controlPlane.machineInfrastructure is set"))
+		}
+		allErrs = append(allErrs, in.Spec.ControlPlane.MachineHealthCheck.validate(in.Namespace, fldPath)...)
 	}
 
 	for i, class := range in.Spec.Workers.MachineDeployments {
-		allErrs = append(allErrs, class.Template.Bootstrap.isValid(in.Namespace, field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("template", "bootstrap"))...)
-		allErrs = append(allErrs, class.Template.Infrastructure.isValid(in.Namespace, field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("template", "infrastructure"))...)
+		if class.MachineHealthCheck != nil {
+			allErrs = append(allErrs, class.MachineHealthCheck.validate(in.Namespace, field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("machineHealthCheck"))...)
+		}
+	}
+
+	return allErrs
+}
+
+// validate validates a MachineHealthCheckClass using the same rules applied to MachineHealthCheck objects,
+// so the MachineHealthChecks generated by the topology controller are valid.
+func (m *MachineHealthCheckClass) validate(namespace string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(m.UnhealthyConditions) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("unhealthyConditions"), "at least one unhealthy condition must be defined"))
+	}
+
+	if m.NodeStartupTimeout != nil &&
+		m.NodeStartupTimeout.Seconds() != disabledNodeStartupTimeout.Seconds() &&
+		m.NodeStartupTimeout.Seconds() < minNodeStartupTimeout.Seconds() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeStartupTimeout"), m.NodeStartupTimeout.Seconds(), "must be at least 30s"))
+	}
+
+	if m.MaxUnhealthy != nil {
+		if _, err := intstr.GetScaledValueFromIntOrPercent(m.MaxUnhealthy, 0, false); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnhealthy"), m.MaxUnhealthy, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())))
+		}
+	}
+
+	if m.RemediationTemplate != nil && m.RemediationTemplate.Namespace != namespace {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("remediationTemplate", "namespace"), m.RemediationTemplate.Namespace, "must match metadata.namespace"))
+	}
+
+	return allErrs
+}
+
+func (in *ClusterClass) validateVariables(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := sets.String{}
+	for i, variable := range in.Spec.Variables {
+		variablePath := fldPath.Index(i)
+
+		switch {
+		case variable.Name == "":
+			allErrs = append(allErrs, field.Required(variablePath.Child("name"), "variable name must be defined"))
+		case strings.Contains(variable.Name, "."):
+			allErrs = append(allErrs, field.Invalid(variablePath.Child("name"), variable.Name, "variable name cannot contain \".\""))
+		case names.Has(variable.Name):
+			allErrs = append(allErrs,
+				field.Invalid(
+					variablePath.Child("name"),
+					variable.Name,
+					fmt.Sprintf("variable names should be unique. Variable with name %q is defined more than once.", variable.Name),
+				),
+			)
+		}
+		names.Insert(variable.Name)
+
+		schemaPath := variablePath.Child("schema", "openAPIV3Schema")
+		if errs := variable.Schema.OpenAPIV3Schema.validate(schemaPath); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+			continue
+		}
+		allErrs = append(allErrs, variable.Schema.OpenAPIV3Schema.validateValues(schemaPath)...)
+	}
+
+	return allErrs
+}
+
+func (in *ClusterClass) validatePatches(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	variableNames := sets.String{}
+	for _, variable := range in.Spec.Variables {
+		variableNames.Insert(variable.Name)
+	}
+
+	names := sets.String{}
+	for i, patch := range in.Spec.Patches {
+		patchPath := fldPath.Index(i)
+
+		switch {
+		case patch.Name == "":
+			allErrs = append(allErrs, field.Required(patchPath.Child("name"), "patch name must be defined"))
+		case names.Has(patch.Name):
+			allErrs = append(allErrs,
+				field.Invalid(
+					patchPath.Child("name"),
+					patch.Name,
+					fmt.Sprintf("patch names should be unique. Patch with name %q is defined more than once.", patch.Name),
+				),
+			)
+		}
+		names.Insert(patch.Name)
+
+		switch {
+		case len(patch.Definitions) == 0 && patch.External == nil:
+			allErrs = append(allErrs, field.Required(patchPath, "one of definitions or external must be set"))
+		case len(patch.Definitions) > 0 && patch.External != nil:
+			allErrs = append(allErrs, field.Invalid(patchPath, patch.Name, "only one of definitions or external can be set"))
+		}
+		if patch.External != nil {
+			allErrs = append(allErrs, patch.External.validate(patchPath.Child("external"))...)
+		}
+		for j, definition := range patch.Definitions {
+			definitionPath := patchPath.Child("definitions").Index(j)
+			if errs := definition.validate(variableNames, definitionPath); len(errs) > 0 {
+				allErrs = append(allErrs, errs...)
+				continue
+			}
+			allErrs = append(allErrs, in.validatePatchSelector(definition.Selector, definitionPath.Child("selector"))...)
+		}
+	}
+
+	return allErrs
+}
+
+// validatePatchSelector checks that a patch selector matches at least one of the templates referenced by the ClusterClass,
+// and that all the MachineDeployment classes it refers to are defined in the ClusterClass.
+// NOTE: This func assumes that d.validate() is called before, thus the selector is syntactically valid.
+func (in *ClusterClass) validatePatchSelector(selector PatchSelector, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	matches := false
+	match := func(r *LocalObjectTemplate) {
+		if r != nil && r.Ref != nil && r.Ref.APIVersion == selector.APIVersion && r.Ref.Kind == selector.Kind {
+			matches = true
+		}
+	}
+
+	if selector.MatchResources.InfrastructureCluster {
+		match(&in.Spec.Infrastructure)
+	}
+
+	if selector.MatchResources.ControlPlane {
+		match(&in.Spec.ControlPlane.LocalObjectTemplate)
+		match(in.Spec.ControlPlane.MachineInfrastructure)
+	}
+
+	if selector.MatchResources.MachineDeploymentClass != nil {
+		classes := map[string]*MachineDeploymentClass{}
+		for i := range in.Spec.Workers.MachineDeployments {
+			classes[in.Spec.Workers.MachineDeployments[i].Class] = &in.Spec.Workers.MachineDeployments[i]
+		}
+		for i, name := range selector.MatchResources.MachineDeploymentClass.Names {
+			class, ok := classes[name]
+			if !ok {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("matchResources", "machineDeploymentClass", "names").Index(i), name, "MachineDeployment class is not defined in the ClusterClass"))
+				continue
+			}
+			match(&class.Template.Bootstrap)
+			match(&class.Template.Infrastructure)
+		}
+	}
+
+	if !matches {
+		allErrs = append(allErrs, field.Invalid(fldPath, selector, fmt.Sprintf("must match at least one of the templates referenced by the ClusterClass, but no template of kind %s in apiVersion %s is referenced by the selected resources", selector.Kind, selector.APIVersion)))
+	}
+
+	return allErrs
+}
+
+func (d *PatchDefinition) validate(variableNames sets.String, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	selectorPath := fldPath.Child("selector")
+	if d.Selector.APIVersion == "" {
+		allErrs = append(allErrs, field.Required(selectorPath.Child("apiVersion"), "apiVersion must be defined"))
+	}
+	if d.Selector.Kind == "" {
+		allErrs = append(allErrs, field.Required(selectorPath.Child("kind"), "kind must be defined"))
+	}
+	match := d.Selector.MatchResources
+	if !match.ControlPlane && !match.InfrastructureCluster && (match.MachineDeploymentClass == nil || len(match.MachineDeploymentClass.Names) == 0) {
+		allErrs = append(allErrs, field.Required(selectorPath.Child("matchResources"), "at least one of controlPlane, infrastructureCluster or machineDeploymentClass must be set"))
+	}
+
+	if len(d.JSONPatches) == 0 && d.MergePatch == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of jsonPatches or mergePatch must be set"))
+	}
+	for i, patch := range d.JSONPatches {
+		allErrs = append(allErrs, patch.validate(variableNames, fldPath.Child("jsonPatches").Index(i))...)
+	}
+
+	return allErrs
+}
+
+func (e *ExternalPatchDefinition) validate(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	clientConfig := e.ClientConfig
+	clientConfigPath := fldPath.Child("clientConfig")
+	switch {
+	case clientConfig.URL == nil && clientConfig.Service == nil:
+		allErrs = append(allErrs, field.Required(clientConfigPath, "one of url or service must be set"))
+	case clientConfig.URL != nil && clientConfig.Service != nil:
+		allErrs = append(allErrs, field.Invalid(clientConfigPath, clientConfig, "only one of url or service can be set"))
+	}
+
+	if clientConfig.URL != nil {
+		urlPath := clientConfigPath.Child("url")
+		u, err := url.Parse(*clientConfig.URL)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(urlPath, *clientConfig.URL, fmt.Sprintf("must be a valid URL: %v", err)))
+		case u.Scheme != "http" && u.Scheme != "https":
+			allErrs = append(allErrs, field.Invalid(urlPath, *clientConfig.URL, "scheme must be either http or https"))
+		case u.Host == "":
+			allErrs = append(allErrs, field.Invalid(urlPath, *clientConfig.URL, "host must be set"))
+		case u.User != nil || u.RawQuery != "" || u.Fragment != "":
+			allErrs = append(allErrs, field.Invalid(urlPath, *clientConfig.URL, "user info, query and fragment are not allowed"))
+		}
+	}
+
+	if service := clientConfig.Service; service != nil {
+		servicePath := clientConfigPath.Child("service")
+		if service.Namespace == "" {
+			allErrs = append(allErrs, field.Required(servicePath.Child("namespace"), "namespace must be defined"))
+		}
+		if service.Name == "" {
+			allErrs = append(allErrs, field.Required(servicePath.Child("name"), "name must be defined"))
+		}
+		if service.Path != nil && !strings.HasPrefix(*service.Path, "/") {
+			allErrs = append(allErrs, field.Invalid(servicePath.Child("path"), *service.Path, "path must start with \"/\""))
+		}
+		if service.Port != nil && (*service.Port < 1 || *service.Port > 65535) {
+			allErrs = append(allErrs, field.Invalid(servicePath.Child("port"), *service.Port, "port must be between 1 and 65535"))
+		}
+	}
+
+	if len(clientConfig.CABundle) > 0 && !x509.NewCertPool().AppendCertsFromPEM(clientConfig.CABundle) {
+		allErrs = append(allErrs, field.Invalid(clientConfigPath.Child("caBundle"), "", "must contain at least one valid PEM encoded certificate"))
+	}
+
+	if e.TimeoutSeconds != nil && (*e.TimeoutSeconds < 1 || *e.TimeoutSeconds > 30) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), *e.TimeoutSeconds, "timeoutSeconds must be between 1 and 30"))
+	}
+
+	if e.FailurePolicy != nil {
+		supportedPolicies := []string{string(ExternalPatchFailurePolicyFail), string(ExternalPatchFailurePolicyIgnore)}
+		if !sets.NewString(supportedPolicies...).Has(string(*e.FailurePolicy)) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("failurePolicy"), *e.FailurePolicy, supportedPolicies))
+		}
+	}
+
+	return allErrs
+}
+
+func (p *JSONPatch) validate(variableNames sets.String, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch p.Op {
+	case "add", "replace":
+		if p.Value == nil && p.ValueFrom == nil {
+			allErrs = append(allErrs, field.Required(fldPath, "one of value or valueFrom must be set for add and replace operations"))
+		}
+		if p.Value != nil && p.ValueFrom != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, p, "only one of value or valueFrom can be set"))
+		}
+	case "remove":
+		if p.Value != nil || p.ValueFrom != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, p, "value and valueFrom cannot be set for remove operations"))
+		}
+	default:
+		return append(allErrs, field.NotSupported(fldPath.Child("op"), p.Op, []string{"add", "replace", "remove"}))
+	}
+
+	if !strings.HasPrefix(p.Path, "/spec/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), p.Path, "path must start with \"/spec/\""))
+	}
+	segments := strings.Split(p.Path, "/")
+	for i, segment := range segments {
+		if _, err := strconv.Atoi(segment); err != nil && segment != "-" {
+			continue
+		}
+		// Only prepend (index 0) and append (-) are allowed, and only for add operations on the last segment.
+		if p.Op != "add" || i != len(segments)-1 || (segment != "0" && segment != "-") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), p.Path, "only index 0 (prepend) and - (append) are allowed in path, and only for add operations"))
+			break
+		}
+	}
+
+	if p.ValueFrom != nil {
+		variable := strings.Split(p.ValueFrom.Variable, ".")[0]
+		if !variableNames.Has(variable) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("valueFrom", "variable"), p.ValueFrom.Variable, fmt.Sprintf("variable %q is not defined in the ClusterClass", variable)))
+		}
+	}
+
+	return allErrs
+}
+
+// validate checks that only the fields supported by the type of the schema are set;
+// values are validated against the schema by the topology controller.
+func (s *JSONSchemaProps) validate(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	supportedTypes := []string{"object", "array", "string", "integer", "number", "boolean"}
+	if !sets.NewString(supportedTypes...).Has(s.Type) {
+		return append(allErrs, field.NotSupported(fldPath.Child("type"), s.Type, supportedTypes))
+	}
+
+	if s.Type != "object" {
+		if len(s.Properties) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("properties"), "can be set only if type is object"))
+		}
+		if len(s.Required) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("required"), "can be set only if type is object"))
+		}
+	}
+	for i, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok && s.Type == "object" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("required").Index(i), name, "must be defined in properties"))
+		}
+	}
+	for name, property := range s.Properties {
+		property := property
+		allErrs = append(allErrs, property.validate(fldPath.Child("properties").Key(name))...)
+	}
+
+	if s.Type == "array" {
+		if s.Items == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("items"), "must be set if type is array"))
+		} else {
+			allErrs = append(allErrs, s.Items.validate(fldPath.Child("items"))...)
+		}
+	} else {
+		if s.Items != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("items"), "can be set only if type is array"))
+		}
+		if s.MaxItems != nil || s.MinItems != nil || s.UniqueItems {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "maxItems, minItems and uniqueItems can be set only if type is array"))
+		}
+	}
+
+	if s.Type != "string" && (s.Format != "" || s.MaxLength != nil || s.MinLength != nil || s.Pattern != "") {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "format, maxLength, minLength and pattern can be set only if type is string"))
+	}
+
+	if s.Type != "integer" && s.Type != "number" && (s.Maximum != nil || s.Minimum != nil || s.ExclusiveMaximum || s.ExclusiveMinimum) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "maximum, minimum, exclusiveMaximum and exclusiveMinimum can be set only if type is integer or number"))
+	}
+
+	return allErrs
+}
+
+// validateValues checks that the schema can be used to validate values, and that the default and enum values comply with it.
+// NOTE: This func assumes that s.validate() is called before, thus only the fields supported by the type of the schema are set.
+func (s *JSONSchemaProps) validateValues(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	props, err := ConvertToAPIExtensionsJSONSchemaProps(s)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath, "", err.Error()))
+	}
+
+	validator, _, err := apiextensionsvalidation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: props})
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath, "", fmt.Sprintf("invalid schema: %v", err)))
+	}
+
+	if props.Default != nil {
+		allErrs = append(allErrs, apiextensionsvalidation.ValidateCustomResource(fldPath.Child("default"), *props.Default, validator)...)
+	}
+	for i, value := range props.Enum {
+		allErrs = append(allErrs, apiextensionsvalidation.ValidateCustomResource(fldPath.Child("enum").Index(i), value, validator)...)
 	}
 
 	return allErrs
@@ -140,6 +641,9 @@ func (in *ClusterClass) validateCompatibleSpecChanges(old *ClusterClass) field.E
 	// Validate changes to MachineDeployments.
 	allErrs = append(allErrs, in.validateMachineDeploymentsCompatibleChanges(old)...)
 
+	// Validate changes to MachinePools.
+	allErrs = append(allErrs, in.validateMachinePoolsCompatibleChanges(old)...)
+
 	// Validate InfrastructureClusterTemplate changes in a compatible way.
 	allErrs = append(allErrs, in.Spec.Infrastructure.isCompatibleWith(
 		old.Spec.Infrastructure,
@@ -162,6 +666,25 @@ func (in *ClusterClass) validateCompatibleSpecChanges(old *ClusterClass) field.E
 	return allErrs
 }
 
+// ValidateRebaseFrom validates that a Cluster using the old ClusterClass can be moved to this ClusterClass;
+// this requires the same compatibility rules enforced when changing a ClusterClass and, additionally,
+// that the control plane machineInfrastructure is either defined in both ClusterClasses or in none of them.
+func (in *ClusterClass) ValidateRebaseFrom(old *ClusterClass) field.ErrorList {
+	allErrs := in.validateCompatibleSpecChanges(old)
+
+	if (in.Spec.ControlPlane.MachineInfrastructure == nil) != (old.Spec.ControlPlane.MachineInfrastructure == nil) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("spec", "controlPlane", "machineInfrastructure"),
+				in.Spec.ControlPlane.MachineInfrastructure,
+				"cannot be added or removed",
+			),
+		)
+	}
+
+	return allErrs
+}
+
 func (in *ClusterClass) validateMachineDeploymentsCompatibleChanges(old *ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -205,7 +728,7 @@ func (r *LocalObjectTemplate) isValid(namespace string, pathPrefix *field.Path)
 	if r.Ref == nil {
 		return field.ErrorList{field.Invalid(
 			pathPrefix.Child("ref"),
-			r.Ref.Name,
+			r.Ref,
 			"cannot be nil",
 		)}
 	}
@@ -268,6 +791,29 @@ func (r *LocalObjectTemplate) isValid(namespace string, pathPrefix *field.Path)
 	return allErrs
 }
 
+// validateKindInstalled checks that the kind referenced by a LocalObjectTemplate is served by the API server.
+// NOTE: this func assumes that r.isValid() is called before, thus the ref is defined and syntactically valid.
+func (r *LocalObjectTemplate) validateKindInstalled(pathPrefix *field.Path) field.ErrorList {
+	// NOTE: The check is skipped if the restMapper is not set, e.g. in unit tests.
+	if restMapper == nil {
+		return nil
+	}
+
+	gvk := r.Ref.GroupVersionKind()
+	if _, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return field.ErrorList{field.Invalid(
+				pathPrefix.Child("ref", "kind"),
+				r.Ref.Kind,
+				fmt.Sprintf("kind is not installed in apiVersion %s", r.Ref.APIVersion),
+			)}
+		}
+		return field.ErrorList{field.InternalError(pathPrefix.Child("ref"), errors.Wrapf(err, "failed to get REST mapping for %s", gvk))}
+	}
+
+	return nil
+}
+
 // isCompatibleWith checks if a reference is compatible with the old one.
 // NOTE: this func assumes that r.isValid() is called before, thus both ref are defined and syntactically valid;
 // also namespace are enforced to be the same of the ClusterClass.
@@ -329,6 +875,42 @@ func (r *LocalObjectTemplate) isCompatibleWith(old LocalObjectTemplate, pathPref
 	return allErrs
 }
 
+func (in *ClusterClass) validateMachinePoolsCompatibleChanges(old *ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// Ensure no MachinePool class was removed.
+	classes := in.Spec.Workers.machinePoolClassNames()
+	for _, oldClass := range old.Spec.Workers.MachinePools {
+		if !classes.Has(oldClass.Class) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					field.NewPath("spec", "workers", "machinePools"),
+					in.Spec.Workers.MachinePools,
+					fmt.Sprintf("The %q MachinePool class can't be removed.", oldClass.Class),
+				),
+			)
+		}
+	}
+
+	// Ensure previous MachinePool class was modified in a compatible way.
+	for i, class := range in.Spec.Workers.MachinePools {
+		for _, oldClass := range old.Spec.Workers.MachinePools {
+			if class.Class == oldClass.Class {
+				// NOTE: class.Template.Metadata and class.Template.Bootstrap are allowed to change;
+				// class.Template.Bootstrap are ensured syntactically correct by validateAllRefs.
+
+				// Validates class.Template.Infrastructure template changes in a compatible way
+				allErrs = append(allErrs, class.Template.Infrastructure.isCompatibleWith(
+					oldClass.Template.Infrastructure,
+					field.NewPath("spec", "workers", "machinePools").Index(i).Child("template", "infrastructure"),
+				)...)
+			}
+		}
+	}
+
+	return allErrs
+}
+
 // classNames returns the set of MachineDeployment class names.
 func (w *WorkersClass) classNames() sets.String {
 	classes := sets.NewString()
@@ -338,6 +920,15 @@ func (w *WorkersClass) classNames() sets.String {
 	return classes
 }
 
+// machinePoolClassNames returns the set of MachinePool class names.
+func (w *WorkersClass) machinePoolClassNames() sets.String {
+	classes := sets.NewString()
+	for _, class := range w.MachinePools {
+		classes.Insert(class.Class)
+	}
+	return classes
+}
+
 func (w *WorkersClass) validateUniqueClasses(pathPrefix *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -355,5 +946,19 @@ func (w *WorkersClass) validateUniqueClasses(pathPrefix *field.Path) field.Error
 		classes.Insert(class.Class)
 	}
 
+	machinePoolClasses := sets.NewString()
+	for i, class := range w.MachinePools {
+		if machinePoolClasses.Has(class.Class) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					pathPrefix.Child("machinePools").Index(i).Child("class"),
+					class.Class,
+					fmt.Sprintf("MachinePool class should be unique. MachinePool with class %q is defined more than once.", class.Class),
+				),
+			)
+		}
+		machinePoolClasses.Insert(class.Class)
+	}
+
 	return allErrs
 }
@@ -18,12 +18,22 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
 )
@@ -1442,3 +1452,833 @@ func TestClusterClassValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterClassValidateVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []ClusterClassVariable
+		expectErr bool
+	}{
+		{
+			name: "pass with valid variables",
+			variables: []ClusterClassVariable{
+				{
+					Name:     "region",
+					Required: true,
+					Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+						Type:      "string",
+						MinLength: pointer.Int64Ptr(1),
+					}},
+				},
+				{
+					Name: "machine",
+					Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]JSONSchemaProps{
+							"replicas": {Type: "integer", Minimum: pointer.Int64Ptr(0)},
+							"zones":    {Type: "array", Items: &JSONSchemaProps{Type: "string"}},
+						},
+						Required: []string{"replicas"},
+					}},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "fail with empty name",
+			variables: []ClusterClassVariable{
+				{Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{Type: "string"}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with name containing a dot",
+			variables: []ClusterClassVariable{
+				{Name: "machine.type", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{Type: "string"}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with duplicated names",
+			variables: []ClusterClassVariable{
+				{Name: "region", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{Type: "string"}}},
+				{Name: "region", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{Type: "string"}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with unsupported type",
+			variables: []ClusterClassVariable{
+				{Name: "region", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{Type: "null"}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with array without items",
+			variables: []ClusterClassVariable{
+				{Name: "zones", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{Type: "array"}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with properties on a string",
+			variables: []ClusterClassVariable{
+				{Name: "region", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+					Type:       "string",
+					Properties: map[string]JSONSchemaProps{"name": {Type: "string"}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with invalid nested property",
+			variables: []ClusterClassVariable{
+				{Name: "machine", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+					Type:       "object",
+					Properties: map[string]JSONSchemaProps{"replicas": {Type: "integer", Pattern: "^[0-9]+$"}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with required field not defined in properties",
+			variables: []ClusterClassVariable{
+				{Name: "machine", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+					Type:       "object",
+					Properties: map[string]JSONSchemaProps{"replicas": {Type: "integer"}},
+					Required:   []string{"instanceType"},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "pass with default and enum values matching the schema",
+			variables: []ClusterClassVariable{
+				{Name: "region", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+					Type:    "string",
+					Enum:    []apiextensionsv1.JSON{{Raw: []byte(`"us-east-1"`)}, {Raw: []byte(`"eu-west-1"`)}},
+					Default: &apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)},
+				}}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "fail with default value not matching the schema",
+			variables: []ClusterClassVariable{
+				{Name: "replicas", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+					Type:    "integer",
+					Minimum: pointer.Int64Ptr(1),
+					Default: &apiextensionsv1.JSON{Raw: []byte(`0`)},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with enum value not matching the schema",
+			variables: []ClusterClassVariable{
+				{Name: "region", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+					Type: "string",
+					Enum: []apiextensionsv1.JSON{{Raw: []byte(`1`)}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with default value that can't be parsed",
+			variables: []ClusterClassVariable{
+				{Name: "region", Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+					Type:    "string",
+					Default: &apiextensionsv1.JSON{Raw: []byte(`{`)},
+				}}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			in := &ClusterClass{Spec: ClusterClassSpec{Variables: tt.variables}}
+			errs := in.validateVariables(field.NewPath("spec", "variables"))
+			if tt.expectErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestClusterClassValidatePatches(t *testing.T) {
+	variables := []ClusterClassVariable{
+		{
+			Name: "machine",
+			Schema: VariableSchema{OpenAPIV3Schema: JSONSchemaProps{
+				Type:       "object",
+				Properties: map[string]JSONSchemaProps{"instanceType": {Type: "string"}},
+			}},
+		},
+	}
+	infrastructure := LocalObjectTemplate{
+		Ref: &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericInfrastructureClusterTemplate", Name: "infra"},
+	}
+	workers := WorkersClass{
+		MachineDeployments: []MachineDeploymentClass{
+			{
+				Class: "linux-worker",
+				Template: MachineDeploymentClassTemplate{
+					Bootstrap: LocalObjectTemplate{
+						Ref: &corev1.ObjectReference{APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1", Kind: "GenericBootstrapConfigTemplate", Name: "bootstrap"},
+					},
+					Infrastructure: LocalObjectTemplate{
+						Ref: &corev1.ObjectReference{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "GenericInfrastructureMachineTemplate", Name: "infra"},
+					},
+				},
+			},
+		},
+	}
+	selector := PatchSelector{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		Kind:       "GenericInfrastructureMachineTemplate",
+		MatchResources: PatchSelectorMatch{
+			MachineDeploymentClass: &PatchSelectorMatchMachineDeploymentClass{Names: []string{"linux-worker"}},
+		},
+	}
+	ignore := ExternalPatchFailurePolicyIgnore
+	unsupportedFailurePolicy := ExternalPatchFailurePolicy("Retry")
+
+	tests := []struct {
+		name      string
+		patches   []ClusterClassPatch
+		expectErr bool
+	}{
+		{
+			name: "pass with valid patches",
+			patches: []ClusterClassPatch{
+				{
+					Name: "instanceType",
+					Definitions: []PatchDefinition{
+						{
+							Selector: selector,
+							JSONPatches: []JSONPatch{
+								{Op: "replace", Path: "/spec/template/spec/instanceType", ValueFrom: &JSONPatchValue{Variable: "machine.instanceType"}},
+								{Op: "add", Path: "/spec/template/spec/tags/-", Value: &apiextensionsv1.JSON{Raw: []byte(`"managed"`)}},
+								{Op: "remove", Path: "/spec/template/spec/spotMarketOptions"},
+							},
+						},
+					},
+				},
+				{
+					Name: "labels",
+					Definitions: []PatchDefinition{
+						{
+							Selector:   selector,
+							MergePatch: &apiextensionsv1.JSON{Raw: []byte(`{"spec":{"template":{"spec":{"rootVolumeSize":100}}}}`)},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "fail with duplicated names",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{Selector: selector, MergePatch: &apiextensionsv1.JSON{Raw: []byte(`{}`)}}}},
+				{Name: "patch", Definitions: []PatchDefinition{{Selector: selector, MergePatch: &apiextensionsv1.JSON{Raw: []byte(`{}`)}}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail without definitions",
+			patches: []ClusterClassPatch{
+				{Name: "patch"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with selector not matching any resource",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector:   PatchSelector{APIVersion: selector.APIVersion, Kind: selector.Kind},
+					MergePatch: &apiextensionsv1.JSON{Raw: []byte(`{}`)},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with unsupported op",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector:    selector,
+					JSONPatches: []JSONPatch{{Op: "move", Path: "/spec/template/spec/instanceType"}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with path outside of spec",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector:    selector,
+					JSONPatches: []JSONPatch{{Op: "add", Path: "/metadata/labels", Value: &apiextensionsv1.JSON{Raw: []byte(`{}`)}}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with index in path of replace operation",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector:    selector,
+					JSONPatches: []JSONPatch{{Op: "replace", Path: "/spec/template/spec/tags/1", Value: &apiextensionsv1.JSON{Raw: []byte(`"a"`)}}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with both value and valueFrom",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector: selector,
+					JSONPatches: []JSONPatch{{
+						Op:        "replace",
+						Path:      "/spec/template/spec/instanceType",
+						Value:     &apiextensionsv1.JSON{Raw: []byte(`"a"`)},
+						ValueFrom: &JSONPatchValue{Variable: "machine"},
+					}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with undefined variable",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector:    selector,
+					JSONPatches: []JSONPatch{{Op: "replace", Path: "/spec/template/spec/region", ValueFrom: &JSONPatchValue{Variable: "region"}}},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with selector referring to an undefined MachineDeployment class",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector: PatchSelector{
+						APIVersion: selector.APIVersion,
+						Kind:       selector.Kind,
+						MatchResources: PatchSelectorMatch{
+							MachineDeploymentClass: &PatchSelectorMatchMachineDeploymentClass{Names: []string{"linux-worker", "windows-worker"}},
+						},
+					},
+					MergePatch: &apiextensionsv1.JSON{Raw: []byte(`{}`)},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with selector not matching any template referenced by the selected resources",
+			patches: []ClusterClassPatch{
+				{Name: "patch", Definitions: []PatchDefinition{{
+					Selector: PatchSelector{
+						APIVersion: selector.APIVersion,
+						Kind:       selector.Kind,
+						MatchResources: PatchSelectorMatch{
+							InfrastructureCluster: true,
+						},
+					},
+					MergePatch: &apiextensionsv1.JSON{Raw: []byte(`{}`)},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "pass with valid external patches",
+			patches: []ClusterClassPatch{
+				{Name: "external-url", External: &ExternalPatchDefinition{
+					ClientConfig:   ExternalPatchClientConfig{URL: pointer.String("https://patches.example.com:8443/v1")},
+					TimeoutSeconds: pointer.Int32(5),
+					FailurePolicy:  &ignore,
+					Settings:       map[string]string{"region": "eu"},
+				}},
+				{Name: "external-service", External: &ExternalPatchDefinition{
+					ClientConfig: ExternalPatchClientConfig{Service: &ExternalPatchServiceReference{
+						Namespace: "extensions",
+						Name:      "patches",
+						Path:      pointer.String("/v1"),
+						Port:      pointer.Int32(8443),
+					}},
+				}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "fail with both definitions and external",
+			patches: []ClusterClassPatch{
+				{
+					Name:        "patch",
+					Definitions: []PatchDefinition{{Selector: selector, MergePatch: &apiextensionsv1.JSON{Raw: []byte(`{}`)}}},
+					External:    &ExternalPatchDefinition{ClientConfig: ExternalPatchClientConfig{URL: pointer.String("https://patches.example.com")}},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external without url and service",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external with both url and service",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{ClientConfig: ExternalPatchClientConfig{
+					URL:     pointer.String("https://patches.example.com"),
+					Service: &ExternalPatchServiceReference{Namespace: "extensions", Name: "patches"},
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external url with unsupported scheme",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{ClientConfig: ExternalPatchClientConfig{URL: pointer.String("ftp://patches.example.com")}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external url with query",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{ClientConfig: ExternalPatchClientConfig{URL: pointer.String("https://patches.example.com?foo=bar")}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external service without name",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{ClientConfig: ExternalPatchClientConfig{Service: &ExternalPatchServiceReference{Namespace: "extensions"}}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external service with invalid path",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{ClientConfig: ExternalPatchClientConfig{Service: &ExternalPatchServiceReference{
+					Namespace: "extensions",
+					Name:      "patches",
+					Path:      pointer.String("v1"),
+				}}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external with invalid caBundle",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{ClientConfig: ExternalPatchClientConfig{
+					URL:      pointer.String("https://patches.example.com"),
+					CABundle: []byte("not a certificate"),
+				}}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external with invalid timeout",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{
+					ClientConfig:   ExternalPatchClientConfig{URL: pointer.String("https://patches.example.com")},
+					TimeoutSeconds: pointer.Int32(60),
+				}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with external with unsupported failure policy",
+			patches: []ClusterClassPatch{
+				{Name: "patch", External: &ExternalPatchDefinition{
+					ClientConfig:  ExternalPatchClientConfig{URL: pointer.String("https://patches.example.com")},
+					FailurePolicy: &unsupportedFailurePolicy,
+				}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			in := &ClusterClass{Spec: ClusterClassSpec{Infrastructure: infrastructure, Workers: workers, Variables: variables, Patches: tt.patches}}
+			errs := in.validatePatches(field.NewPath("spec", "patches"))
+			if tt.expectErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestClusterClassValidateMachineHealthChecks(t *testing.T) {
+	unhealthyConditions := []UnhealthyCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: metav1.Duration{Duration: 5 * time.Minute}},
+	}
+	maxUnhealthy := intstr.FromString("40%")
+	invalidMaxUnhealthy := intstr.FromString("forty")
+
+	tests := []struct {
+		name                  string
+		machineInfrastructure *LocalObjectTemplate
+		controlPlane          *MachineHealthCheckClass
+		machineDeployment     *MachineHealthCheckClass
+		expectErr             bool
+	}{
+		{
+			name:                  "pass with valid MachineHealthChecks",
+			machineInfrastructure: &LocalObjectTemplate{Ref: &corev1.ObjectReference{Name: "cp-infra"}},
+			controlPlane: &MachineHealthCheckClass{
+				UnhealthyConditions: unhealthyConditions,
+				MaxUnhealthy:        &maxUnhealthy,
+				NodeStartupTimeout:  &metav1.Duration{Duration: 15 * time.Minute},
+			},
+			machineDeployment: &MachineHealthCheckClass{
+				UnhealthyConditions: unhealthyConditions,
+				RemediationTemplate: &corev1.ObjectReference{Namespace: metav1.NamespaceDefault, Name: "remediation"},
+			},
+		},
+		{
+			name: "pass without MachineHealthChecks",
+		},
+		{
+			name: "fail with a control plane MachineHealthCheck without machineInfrastructure",
+			controlPlane: &MachineHealthCheckClass{
+				UnhealthyConditions: unhealthyConditions,
+			},
+			expectErr: true,
+		},
+		{
+			name:              "fail without unhealthy conditions",
+			machineDeployment: &MachineHealthCheckClass{},
+			expectErr:         true,
+		},
+		{
+			name: "fail with a too short nodeStartupTimeout",
+			machineDeployment: &MachineHealthCheckClass{
+				UnhealthyConditions: unhealthyConditions,
+				NodeStartupTimeout:  &metav1.Duration{Duration: 10 * time.Second},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with an invalid maxUnhealthy",
+			machineDeployment: &MachineHealthCheckClass{
+				UnhealthyConditions: unhealthyConditions,
+				MaxUnhealthy:        &invalidMaxUnhealthy,
+			},
+			expectErr: true,
+		},
+		{
+			name: "fail with a remediationTemplate in a different namespace",
+			machineDeployment: &MachineHealthCheckClass{
+				UnhealthyConditions: unhealthyConditions,
+				RemediationTemplate: &corev1.ObjectReference{Namespace: "other", Name: "remediation"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			in := &ClusterClass{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault},
+				Spec: ClusterClassSpec{
+					ControlPlane: ControlPlaneClass{
+						MachineInfrastructure: tt.machineInfrastructure,
+						MachineHealthCheck:    tt.controlPlane,
+					},
+					Workers: WorkersClass{
+						MachineDeployments: []MachineDeploymentClass{
+							{Class: "linux-worker", MachineHealthCheck: tt.machineDeployment},
+						},
+					},
+				},
+			}
+			errs := in.validateMachineHealthChecks()
+			if tt.expectErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestClusterClassValidateMachinePools(t *testing.T) {
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to create or update ClusterClasses.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	ref := &corev1.ObjectReference{
+		APIVersion: "foo",
+		Kind:       "barTemplate",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	incompatibleRef := &corev1.ObjectReference{
+		APIVersion: "foo",
+		Kind:       "another-barTemplate",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	machinePoolClass := func(class string, infrastructureRef *corev1.ObjectReference) MachinePoolClass {
+		return MachinePoolClass{
+			Class: class,
+			Template: MachinePoolClassTemplate{
+				Bootstrap:      LocalObjectTemplate{Ref: ref},
+				Infrastructure: LocalObjectTemplate{Ref: infrastructureRef},
+			},
+		}
+	}
+	clusterClass := func(machinePools ...MachinePoolClass) *ClusterClass {
+		return &ClusterClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+			},
+			Spec: ClusterClassSpec{
+				Infrastructure: LocalObjectTemplate{Ref: ref},
+				ControlPlane: ControlPlaneClass{
+					LocalObjectTemplate: LocalObjectTemplate{Ref: ref},
+				},
+				Workers: WorkersClass{
+					MachinePools: machinePools,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		machinePoolEnabled bool
+		in                 *ClusterClass
+		old                *ClusterClass
+		expectErr          bool
+	}{
+		{
+			name:               "create fails if MachinePool classes are used with the MachinePool feature flag disabled",
+			machinePoolEnabled: false,
+			in:                 clusterClass(machinePoolClass("aa", ref)),
+			expectErr:          true,
+		},
+		{
+			name:               "create pass with MachinePool classes and the MachinePool feature flag enabled",
+			machinePoolEnabled: true,
+			in:                 clusterClass(machinePoolClass("aa", ref), machinePoolClass("bb", ref)),
+			expectErr:          false,
+		},
+		{
+			name:               "create fails if MachinePool classes are not unique",
+			machinePoolEnabled: true,
+			in:                 clusterClass(machinePoolClass("aa", ref), machinePoolClass("aa", ref)),
+			expectErr:          true,
+		},
+		{
+			name:               "create fails if a MachinePool class has an invalid bootstrap reference",
+			machinePoolEnabled: true,
+			in: clusterClass(MachinePoolClass{
+				Class: "aa",
+				Template: MachinePoolClassTemplate{
+					Infrastructure: LocalObjectTemplate{Ref: ref},
+				},
+			}),
+			expectErr: true,
+		},
+		{
+			name:               "update fails if a MachinePool class is removed",
+			machinePoolEnabled: true,
+			old:                clusterClass(machinePoolClass("aa", ref), machinePoolClass("bb", ref)),
+			in:                 clusterClass(machinePoolClass("aa", ref)),
+			expectErr:          true,
+		},
+		{
+			name:               "update fails if the infrastructure template of a MachinePool class changes to an incompatible kind",
+			machinePoolEnabled: true,
+			old:                clusterClass(machinePoolClass("aa", ref)),
+			in:                 clusterClass(machinePoolClass("aa", incompatibleRef)),
+			expectErr:          true,
+		},
+		{
+			name:               "update pass if a MachinePool class is added",
+			machinePoolEnabled: true,
+			old:                clusterClass(machinePoolClass("aa", ref)),
+			in:                 clusterClass(machinePoolClass("aa", ref), machinePoolClass("bb", ref)),
+			expectErr:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, tt.machinePoolEnabled)()
+
+			if tt.expectErr {
+				g.Expect(tt.in.validate(tt.old)).NotTo(Succeed())
+			} else {
+				g.Expect(tt.in.validate(tt.old)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestClusterClassValidateDelete(t *testing.T) {
+	clusterClass := &ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "class1"},
+	}
+	cluster := func(namespace, name, class string) *Cluster {
+		c := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		}
+		if class != "" {
+			c.Spec.Topology = &Topology{Class: class, Version: "v1.19.1"}
+		}
+		return c
+	}
+
+	scheme := runtime.NewScheme()
+	_ = AddToScheme(scheme)
+
+	tests := []struct {
+		name      string
+		reader    client.Reader
+		expectErr bool
+	}{
+		{
+			name:      "should pass when the ClusterClass is not used by any Cluster",
+			reader:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster(metav1.NamespaceDefault, "cluster1", "class2"), cluster(metav1.NamespaceDefault, "cluster2", "")).Build(),
+			expectErr: false,
+		},
+		{
+			name:      "should pass when the ClusterClass is used only by Clusters in other namespaces",
+			reader:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster("other", "cluster1", "class1")).Build(),
+			expectErr: false,
+		},
+		{
+			name:      "should return error when the ClusterClass is used by a Cluster",
+			reader:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster(metav1.NamespaceDefault, "cluster1", "class1")).Build(),
+			expectErr: true,
+		},
+		{
+			name: "should return error when the ClusterClass is used by a Cluster in another namespace",
+			reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(func() client.Object {
+				c := cluster("other", "cluster1", "class1")
+				c.Spec.Topology.ClassNamespace = metav1.NamespaceDefault
+				return c
+			}()).Build(),
+			expectErr: true,
+		},
+		{
+			name:      "should pass when Clusters can't be read",
+			reader:    nil,
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			defer func(r client.Reader) { clusterReader = r }(clusterReader)
+			clusterReader = tt.reader
+
+			err := clusterClass.ValidateDelete()
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClusterClassValidateAllRefsKindInstalled(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta1", Kind: "GenericInfrastructureClusterTemplate"},
+		{Group: "controlplane.cluster.x-k8s.io", Version: "v1beta1", Kind: "GenericControlPlaneTemplate"},
+		{Group: "bootstrap.cluster.x-k8s.io", Version: "v1beta1", Kind: "GenericBootstrapConfigTemplate"},
+		{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta1", Kind: "GenericInfrastructureMachineTemplate"},
+	} {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+
+	ref := func(apiVersion, kind string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Name: "template", Namespace: metav1.NamespaceDefault}
+	}
+	clusterClass := func(bootstrapKind string) *ClusterClass {
+		return &ClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "class1"},
+			Spec: ClusterClassSpec{
+				Infrastructure: LocalObjectTemplate{Ref: ref("infrastructure.cluster.x-k8s.io/v1beta1", "GenericInfrastructureClusterTemplate")},
+				ControlPlane: ControlPlaneClass{
+					LocalObjectTemplate: LocalObjectTemplate{Ref: ref("controlplane.cluster.x-k8s.io/v1beta1", "GenericControlPlaneTemplate")},
+				},
+				Workers: WorkersClass{
+					MachineDeployments: []MachineDeploymentClass{
+						{
+							Class: "linux-worker",
+							Template: MachineDeploymentClassTemplate{
+								Bootstrap:      LocalObjectTemplate{Ref: ref("bootstrap.cluster.x-k8s.io/v1beta1", bootstrapKind)},
+								Infrastructure: LocalObjectTemplate{Ref: ref("infrastructure.cluster.x-k8s.io/v1beta1", "GenericInfrastructureMachineTemplate")},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		mapper    meta.RESTMapper
+		in        *ClusterClass
+		expectErr bool
+	}{
+		{
+			name:      "pass when all the referenced kinds are installed",
+			mapper:    mapper,
+			in:        clusterClass("GenericBootstrapConfigTemplate"),
+			expectErr: false,
+		},
+		{
+			name:      "fail when a referenced kind is not installed",
+			mapper:    mapper,
+			in:        clusterClass("OtherBootstrapConfigTemplate"),
+			expectErr: true,
+		},
+		{
+			name:      "pass when kinds can't be checked",
+			mapper:    nil,
+			in:        clusterClass("OtherBootstrapConfigTemplate"),
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			defer func(m meta.RESTMapper) { restMapper = m }(restMapper)
+			restMapper = tt.mapper
+
+			errs := tt.in.validateAllRefs()
+			if tt.expectErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
@@ -37,6 +37,21 @@ const (
 	// to track the name of the MachineDeployment topology it represents.
 	ClusterTopologyMachineDeploymentLabelName = "topology.cluster.x-k8s.io/deployment-name"
 
+	// ClusterTopologyMachinePoolLabelName is the label set on the generated MachinePool objects
+	// to track the name of the MachinePool topology it represents.
+	ClusterTopologyMachinePoolLabelName = "topology.cluster.x-k8s.io/pool-name"
+
+	// ClusterTopologyMachineDeploymentRolloutOrderAnnotation can be set on MachineDeployments generated from a
+	// managed topology to define the order in which they pick up changes to their templates; MachineDeployments with
+	// a lower value roll out first, and a MachineDeployment starts rolling out only after all the MachineDeployments with
+	// a lower value completed their rollout. MachineDeployments without the annotation have rollout order 0.
+	ClusterTopologyMachineDeploymentRolloutOrderAnnotation = "topology.cluster.x-k8s.io/rollout-order"
+
+	// ClusterTopologyMachineDeploymentRolloutPausedAnnotation can be set on MachineDeployments generated from a
+	// managed topology to prevent them from picking up changes to their templates or version, and thus from
+	// rolling out new Machines, until the annotation is removed.
+	ClusterTopologyMachineDeploymentRolloutPausedAnnotation = "topology.cluster.x-k8s.io/rollout-paused"
+
 	// ProviderLabelName is the label set on components in the provider manifest.
 	// This label allows to easily identify all the components belonging to a provider; the clusterctl
 	// tool uses this label for implementing provider's lifecycle operations.
@@ -57,6 +72,25 @@ const (
 	// OwnerNameAnnotation is the annotation set on nodes identifying the owner name.
 	OwnerNameAnnotation = "cluster.x-k8s.io/owner-name"
 
+	// NodeLabelsAnnotation is the annotation set on MachineDeployments, MachineSets and Machines defining the labels
+	// to be applied to the corresponding Nodes, using the format of the kubelet --node-labels flag, e.g. "key1=value1,key2=value2".
+	// The annotation is also set on Nodes to keep track of the labels applied by Cluster API.
+	NodeLabelsAnnotation = "cluster.x-k8s.io/node-labels"
+
+	// NodeTaintsAnnotation is the annotation set on MachineDeployments, MachineSets and Machines defining the taints
+	// to be applied to the corresponding Nodes, using the format of the kubelet --register-with-taints flag,
+	// e.g. "key1=value1:NoSchedule,key2:NoExecute".
+	// The annotation is also set on Nodes to keep track of the taints applied by Cluster API.
+	NodeTaintsAnnotation = "cluster.x-k8s.io/node-taints"
+
+	// AutoscalerMinSizeAnnotation is the annotation used by the cluster-autoscaler to read the minimum number of
+	// replicas of an autoscaled MachineDeployment or MachineSet.
+	AutoscalerMinSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+
+	// AutoscalerMaxSizeAnnotation is the annotation used by the cluster-autoscaler to read the maximum number of
+	// replicas of an autoscaled MachineDeployment or MachineSet.
+	AutoscalerMaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+
 	// PausedAnnotation is an annotation that can be applied to any Cluster API
 	// object to prevent a controller from processing a resource.
 	//
@@ -64,6 +98,11 @@ const (
 	// on the reconciled object.
 	PausedAnnotation = "cluster.x-k8s.io/paused"
 
+	// PausedFromClusterAnnotation is an annotation set by the Cluster controller, together with the PausedAnnotation,
+	// on the objects referenced by a paused Cluster and by its Machines. It allows to tell apart a paused annotation
+	// propagated from the Cluster from one set by users, so only the former is removed when the Cluster is resumed.
+	PausedFromClusterAnnotation = "cluster.x-k8s.io/paused-from-cluster"
+
 	// DisableMachineCreate is an annotation that can be used to signal a MachineSet to stop creating new machines.
 	// It is utilized in the OnDelete MachineDeploymentStrategy to allow the MachineDeployment controller to scale down
 	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
@@ -89,6 +89,66 @@ const (
 	// NOTE: Having the control plane machine available is a pre-condition for joining additional control planes
 	// or workers nodes.
 	WaitingForControlPlaneAvailableReason = "WaitingForControlPlaneAvailable"
+
+	// WaitingForExternallyManagedInfrastructureReason (Severity=Info) documents a Cluster waiting for the external
+	// management system to mark the externally managed infrastructure as ready.
+	//
+	// NOTE: The infrastructure is externally managed when the InfraCluster object has the cluster.x-k8s.io/managed-by annotation.
+	WaitingForExternallyManagedInfrastructureReason = "WaitingForExternallyManagedInfrastructure"
+)
+
+// Conditions and condition Reasons for the deletion of the Cluster object.
+
+const (
+	// WorkersDeletedCondition reports if the MachinePools, MachineDeployments, MachineSets and worker Machines
+	// of a Cluster being deleted are gone.
+	WorkersDeletedCondition ConditionType = "WorkersDeleted"
+
+	// ControlPlaneDeletedCondition reports if the control plane of a Cluster being deleted is gone.
+	ControlPlaneDeletedCondition ConditionType = "ControlPlaneDeleted"
+
+	// InfrastructureDeletedCondition reports if the infrastructure of a Cluster being deleted is gone.
+	InfrastructureDeletedCondition ConditionType = "InfrastructureDeleted"
+
+	// DeletionTimedOutReason (Severity=Warning) documents a phase of the Cluster deletion that took longer than
+	// the timeout defined in spec.deletionTimeouts; the deletion moved on to the next phase without waiting further.
+	DeletionTimedOutReason = "DeletionTimedOut"
+)
+
+// Conditions and condition Reasons for the Cluster's managed topology.
+
+const (
+	// TopologyReconciledCondition summarizes the state of the reconciliation of the managed topology of a Cluster;
+	// it is set to false if any error occurred while computing or applying the desired state of the topology.
+	TopologyReconciledCondition ConditionType = "TopologyReconciled"
+
+	// TopologyControlPlaneTemplatesReconciledCondition reports if the control plane object and its templates
+	// have been reconciled with the desired state of the managed topology.
+	TopologyControlPlaneTemplatesReconciledCondition ConditionType = "ControlPlaneTemplatesReconciled"
+
+	// TopologyMachineDeploymentsReconciledCondition is the prefix of the conditions reporting if the MachineDeployments
+	// of a given MachineDeployment class have been reconciled with the desired state of the managed topology;
+	// a condition is set for each MachineDeployment class in use, e.g. MachineDeploymentsReconciled/default-worker.
+	TopologyMachineDeploymentsReconciledCondition ConditionType = "MachineDeploymentsReconciled"
+
+	// TopologyReconcileFailedReason (Severity=Error) documents an error occurred while reconciling the managed
+	// topology of a Cluster, or one of its components.
+	TopologyReconcileFailedReason = "TopologyReconcileFailed"
+
+	// TopologyReconcileSkippedReason (Severity=Info) documents a component of the managed topology of a Cluster
+	// that has not been reconciled because of a previous error.
+	TopologyReconcileSkippedReason = "TopologyReconcileSkipped"
+
+	// TopologyControlPlaneMachinesRolledOutCondition reports if the control plane machines are rolled out after
+	// the InfrastructureMachineTemplate of the control plane has been rotated by the topology controller, e.g. due to
+	// changes to the template referenced by the ClusterClass.
+	// NOTE: This condition is set only for managed topologies using a ClusterClass which defines the control plane
+	// machine infrastructure.
+	TopologyControlPlaneMachinesRolledOutCondition ConditionType = "TopologyControlPlaneMachinesRolledOut"
+
+	// TopologyControlPlaneMachinesRollingOutReason (Severity=Info) documents a Cluster waiting for the control plane
+	// to roll out its machines after the InfrastructureMachineTemplate of the control plane has been rotated.
+	TopologyControlPlaneMachinesRollingOutReason = "ControlPlaneMachinesRollingOut"
 )
 
 // Conditions and condition Reasons for the Machine object
@@ -124,11 +184,19 @@ const (
 	// WaitingExternalHookReason (Severity=Info) provide evidence that we are waiting for an external hook to complete.
 	WaitingExternalHookReason = "WaitingExternalHook"
 
+	// ExternalHookTimedOutReason (Severity=Warning) documents that the controller stopped waiting for an external hook
+	// to complete because the configured lifecycle hook timeout expired.
+	ExternalHookTimedOutReason = "ExternalHookTimedOut"
+
 	// VolumeDetachSucceededCondition reports a machine waiting for volumes to be detached.
 	VolumeDetachSucceededCondition ConditionType = "VolumeDetachSucceeded"
 
 	// WaitingForVolumeDetachReason (Severity=Info) provide evidence that a machine node waiting for volumes to be attached.
 	WaitingForVolumeDetachReason = "WaitingForVolumeDetach"
+
+	// VolumeDetachTimedOutReason (Severity=Warning) documents that the controller stopped waiting for the volumes of a
+	// machine node to be detached because the machine's NodeVolumeDetachTimeout expired.
+	VolumeDetachTimedOutReason = "VolumeDetachTimedOut"
 )
 
 const (
@@ -29,6 +29,10 @@ import (
 const (
 	// ClusterClassNameField is used by the Cluster controller to index Clusters by ClusterClass name.
 	ClusterClassNameField = "spec.topology.class"
+
+	// ClusterNameField is used to index objects belonging to a Cluster, e.g. Machines, MachineSets
+	// and MachineDeployments, by Cluster name.
+	ClusterNameField = "spec.clusterName"
 )
 
 // ByClusterClassName adds the cluster class name  index to the
@@ -53,3 +57,34 @@ func clusterByClassName(o client.Object) []string {
 	}
 	return nil
 }
+
+// ByClusterName adds the cluster name index for the given object type to the
+// managers cache.
+func ByClusterName(ctx context.Context, mgr ctrl.Manager, obj client.Object) error {
+	if err := mgr.GetCache().IndexField(ctx, obj,
+		ClusterNameField,
+		objectByClusterName,
+	); err != nil {
+		return errors.Wrapf(err, "error setting index field for %T", obj)
+	}
+	return nil
+}
+
+func objectByClusterName(o client.Object) []string {
+	var clusterName string
+	switch obj := o.(type) {
+	case *clusterv1.Machine:
+		clusterName = obj.Spec.ClusterName
+	case *clusterv1.MachineSet:
+		clusterName = obj.Spec.ClusterName
+	case *clusterv1.MachineDeployment:
+		clusterName = obj.Spec.ClusterName
+	default:
+		panic(fmt.Sprintf("Expected an object with spec.clusterName but got a %T", o))
+	}
+
+	if clusterName == "" {
+		return nil
+	}
+	return []string{clusterName}
+}
@@ -56,3 +56,46 @@ func TestClusterByClassName(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectByClusterName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		object   client.Object
+		expected []string
+	}{
+		{
+			name:     "when the Machine has no cluster name",
+			object:   &clusterv1.Machine{},
+			expected: nil,
+		},
+		{
+			name: "when the Machine has a cluster name",
+			object: &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{ClusterName: "cluster1"},
+			},
+			expected: []string{"cluster1"},
+		},
+		{
+			name: "when the MachineSet has a cluster name",
+			object: &clusterv1.MachineSet{
+				Spec: clusterv1.MachineSetSpec{ClusterName: "cluster1"},
+			},
+			expected: []string{"cluster1"},
+		},
+		{
+			name: "when the MachineDeployment has a cluster name",
+			object: &clusterv1.MachineDeployment{
+				Spec: clusterv1.MachineDeploymentSpec{ClusterName: "cluster1"},
+			},
+			expected: []string{"cluster1"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := objectByClusterName(test.object)
+			g.Expect(got).To(Equal(test.expected))
+		})
+	}
+}
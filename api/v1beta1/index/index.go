@@ -20,11 +20,15 @@ package index
 import (
 	"context"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // AddDefaultIndexes registers the default list of indexes.
+// NOTE: AddDefaultIndexes must be called exactly once per manager, before starting it; controllers and
+// webhooks relying on the default indexes can then assume they exist in the manager's cache.
 func AddDefaultIndexes(ctx context.Context, mgr ctrl.Manager) error {
 	if err := ByMachineNode(ctx, mgr); err != nil {
 		return err
@@ -34,6 +38,12 @@ func AddDefaultIndexes(ctx context.Context, mgr ctrl.Manager) error {
 		return err
 	}
 
+	for _, obj := range []client.Object{&clusterv1.Machine{}, &clusterv1.MachineSet{}, &clusterv1.MachineDeployment{}} {
+		if err := ByClusterName(ctx, mgr, obj); err != nil {
+			return err
+		}
+	}
+
 	if feature.Gates.Enabled(feature.ClusterTopology) {
 		if err := ByClusterClassName(ctx, mgr); err != nil {
 			return err
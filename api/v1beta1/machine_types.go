@@ -38,6 +38,9 @@ const (
 	// MachineDeploymentLabelName is the label set on machines if they're controlled by MachineDeployment.
 	MachineDeploymentLabelName = "cluster.x-k8s.io/deployment-name"
 
+	// MachinePoolLabelName is the label set on machines and infrastructure machines if they belong to a MachinePool.
+	MachinePoolLabelName = "cluster.x-k8s.io/pool-name"
+
 	// PreDrainDeleteHookAnnotationPrefix annotation specifies the prefix we
 	// search each annotation for during the pre-drain.delete lifecycle hook
 	// to pause reconciliation of deletion. These hooks will prevent removal of
@@ -95,6 +98,27 @@ type MachineSpec struct {
 	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
 	// +optional
 	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// NodeDrainGracePeriod overrides the termination grace period of the pods evicted when draining the node.
+	// When not set, the termination grace period defined in each pod is used.
+	// +optional
+	NodeDrainGracePeriod *metav1.Duration `json:"nodeDrainGracePeriod,omitempty"`
+
+	// NodeDrainExcludedPodSelector is a label query over the pods that are not evicted when draining the node,
+	// e.g. pods that are expected to keep running until the node is deleted.
+	// +optional
+	NodeDrainExcludedPodSelector *metav1.LabelSelector `json:"nodeDrainExcludedPodSelector,omitempty"`
+
+	// SkipWaitForNodeVolumeDetach, if true, allows the node to be deleted after draining it without waiting
+	// for its volumes to be detached.
+	// +optional
+	SkipWaitForNodeVolumeDetach bool `json:"skipWaitForNodeVolumeDetach,omitempty"`
+
+	// NodeVolumeDetachTimeout is the total amount of time that the controller will spend on waiting for all volumes
+	// to be detached from the node after draining it, including the ones attached by CSI drivers.
+	// The default value is 0, meaning that the volumes can be detached without any time limitations.
+	// +optional
+	NodeVolumeDetachTimeout *metav1.Duration `json:"nodeVolumeDetachTimeout,omitempty"`
 }
 
 // ANCHOR_END: MachineSpec
@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/version"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -128,8 +129,34 @@ func (m *Machine) validate(old *Machine) error {
 		}
 	}
 
+	allErrs = append(allErrs, validateNodeDrainOptions(&m.Spec, field.NewPath("spec"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("Machine").GroupKind(), m.Name, allErrs)
 }
+
+// validateNodeDrainOptions validates the options used when draining the node of a Machine.
+// NOTE: This is also used to validate the machine templates of MachineSets and MachineDeployments.
+func validateNodeDrainOptions(spec *MachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.NodeDrainGracePeriod != nil && spec.NodeDrainGracePeriod.Duration < 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(fldPath.Child("nodeDrainGracePeriod"), spec.NodeDrainGracePeriod.Duration.String(), "must be greater than or equal to 0"),
+		)
+	}
+
+	if spec.NodeDrainExcludedPodSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.NodeDrainExcludedPodSelector); err != nil {
+			allErrs = append(
+				allErrs,
+				field.Invalid(fldPath.Child("nodeDrainExcludedPodSelector"), spec.NodeDrainExcludedPodSelector, err.Error()),
+			)
+		}
+	}
+
+	return allErrs
+}
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
@@ -247,3 +248,59 @@ func TestMachineVersionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineNodeDrainOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name                string
+		gracePeriod         *metav1.Duration
+		excludedPodSelector *metav1.LabelSelector
+		expectErr           bool
+	}{
+		{
+			name:      "should succeed when no drain options are given",
+			expectErr: false,
+		},
+		{
+			name:                "should succeed when given a valid grace period and excluded pod selector",
+			gracePeriod:         &metav1.Duration{Duration: 30 * time.Second},
+			excludedPodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "keep-running"}},
+			expectErr:           false,
+		},
+		{
+			name:        "should return error when given a negative grace period",
+			gracePeriod: &metav1.Duration{Duration: -1 * time.Second},
+			expectErr:   true,
+		},
+		{
+			name: "should return error when given an invalid excluded pod selector",
+			excludedPodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: "Unknown"},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				Spec: MachineSpec{
+					Bootstrap:                    Bootstrap{ConfigRef: nil, DataSecretName: pointer.StringPtr("test")},
+					NodeDrainGracePeriod:         tt.gracePeriod,
+					NodeDrainExcludedPodSelector: tt.excludedPodSelector,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
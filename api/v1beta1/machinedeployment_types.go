@@ -95,6 +95,19 @@ type MachineDeploymentSpec struct {
 	// +optional
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
 
+	// RolloutAfter is a field to indicate a rollout should be performed
+	// after the specified time even if no changes have been made to the
+	// MachineDeployment.
+	//
+	// +optional
+	RolloutAfter *metav1.Time `json:"rolloutAfter,omitempty"`
+
+	// RollbackTo is the revision to which the MachineDeployment is rolled back.
+	// The machine template of the MachineSet with the given revision is copied
+	// into the MachineDeployment, and then the field is cleared.
+	// +optional
+	RollbackTo *MachineDeploymentRollback `json:"rollbackTo,omitempty"`
+
 	// Indicates that the deployment is paused.
 	// +optional
 	Paused bool `json:"paused,omitempty"`
@@ -109,6 +122,19 @@ type MachineDeploymentSpec struct {
 
 // ANCHOR_END: MachineDeploymentSpec
 
+// ANCHOR: MachineDeploymentRollback
+
+// MachineDeploymentRollback describes the revision a MachineDeployment is rolled back to.
+type MachineDeploymentRollback struct {
+	// Revision of the MachineSet to roll back to. If set to 0, the
+	// MachineDeployment is rolled back to the previous revision.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// ANCHOR_END: MachineDeploymentRollback
+
 // ANCHOR: MachineDeploymentStrategy
 
 // MachineDeploymentStrategy describes how to replace existing machines
@@ -164,9 +190,9 @@ type MachineRollingUpdateDeployment struct {
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 
 	// DeletePolicy defines the policy used by the MachineDeployment to identify nodes to delete when downscaling.
-	// Valid values are "Random, "Newest", "Oldest"
+	// Valid values are "Random, "Newest", "Oldest", "UnhealthyFirst"
 	// When no value is supplied, the default DeletePolicy of MachineSet is used
-	// +kubebuilder:validation:Enum=Random;Newest;Oldest
+	// +kubebuilder:validation:Enum=Random;Newest;Oldest;UnhealthyFirst
 	// +optional
 	DeletePolicy *string `json:"deletePolicy,omitempty"`
 }
@@ -99,31 +99,19 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 		)
 	}
 
-	if m.Spec.Strategy != nil && m.Spec.Strategy.RollingUpdate != nil {
+	if old != nil && !selectorsEqualIgnoringClusterLabel(old.Spec.Selector, m.Spec.Selector) {
+		allErrs = append(
+			allErrs,
+			field.Invalid(field.NewPath("spec", "selector"), m.Spec.Selector, "field is immutable"),
+		)
+	}
+
+	if m.Spec.Strategy != nil {
 		total := 1
 		if m.Spec.Replicas != nil {
 			total = int(*m.Spec.Replicas)
 		}
-
-		if m.Spec.Strategy.RollingUpdate.MaxSurge != nil {
-			if _, err := intstr.GetScaledValueFromIntOrPercent(m.Spec.Strategy.RollingUpdate.MaxSurge, total, true); err != nil {
-				allErrs = append(
-					allErrs,
-					field.Invalid(field.NewPath("spec", "strategy", "rollingUpdate", "maxSurge"),
-						m.Spec.Strategy.RollingUpdate.MaxSurge, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())),
-				)
-			}
-		}
-
-		if m.Spec.Strategy.RollingUpdate.MaxUnavailable != nil {
-			if _, err := intstr.GetScaledValueFromIntOrPercent(m.Spec.Strategy.RollingUpdate.MaxUnavailable, total, true); err != nil {
-				allErrs = append(
-					allErrs,
-					field.Invalid(field.NewPath("spec", "strategy", "rollingUpdate", "maxUnavailable"),
-						m.Spec.Strategy.RollingUpdate.MaxUnavailable, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())),
-				)
-			}
-		}
+		allErrs = append(allErrs, m.Spec.Strategy.validate(total, field.NewPath("spec", "strategy"))...)
 	}
 
 	if m.Spec.Template.Spec.Version != nil {
@@ -132,6 +120,17 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 		}
 	}
 
+	allErrs = append(allErrs, validateNodeDrainOptions(&m.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+
+	// The machine template of a MachineDeployment managed by a Cluster topology is reconciled by the topology
+	// controller, so rolling it back would be reverted immediately.
+	if _, ok := m.Labels[ClusterTopologyOwnedLabel]; ok && m.Spec.RollbackTo != nil {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(field.NewPath("spec", "rollbackTo"), "cannot be set for a MachineDeployment managed by a Cluster topology"),
+		)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -139,6 +138,63 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 	return apierrors.NewInvalid(GroupVersion.WithKind("MachineDeployment").GroupKind(), m.Name, allErrs)
 }
 
+// validate validates a MachineDeploymentStrategy; total is the number of replicas used to scale
+// maxSurge and maxUnavailable if they are defined as a percentage.
+// NOTE: This is also used to validate the rollout strategies defined in ClusterClass and Cluster topologies.
+func (s *MachineDeploymentStrategy) validate(total int, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if s.RollingUpdate == nil {
+		return allErrs
+	}
+
+	if s.RollingUpdate.MaxSurge != nil {
+		if _, err := intstr.GetScaledValueFromIntOrPercent(s.RollingUpdate.MaxSurge, total, true); err != nil {
+			allErrs = append(
+				allErrs,
+				field.Invalid(fldPath.Child("rollingUpdate", "maxSurge"),
+					s.RollingUpdate.MaxSurge, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())),
+			)
+		}
+	}
+
+	if s.RollingUpdate.MaxUnavailable != nil {
+		if _, err := intstr.GetScaledValueFromIntOrPercent(s.RollingUpdate.MaxUnavailable, total, true); err != nil {
+			allErrs = append(
+				allErrs,
+				field.Invalid(fldPath.Child("rollingUpdate", "maxUnavailable"),
+					s.RollingUpdate.MaxUnavailable, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())),
+			)
+		}
+	}
+
+	if s.RollingUpdate.DeletePolicy != nil {
+		allErrs = append(allErrs, validateDeletePolicy(*s.RollingUpdate.DeletePolicy, fldPath.Child("rollingUpdate", "deletePolicy"))...)
+	}
+
+	// A rolling update can't make progress if it is not allowed to create new Machines nor to delete old ones.
+	if isZeroIntOrPercent(s.RollingUpdate.MaxSurge) && isZeroIntOrPercent(s.RollingUpdate.MaxUnavailable) {
+		allErrs = append(
+			allErrs,
+			field.Invalid(fldPath.Child("rollingUpdate", "maxUnavailable"),
+				s.RollingUpdate.MaxUnavailable, "must not be 0 when maxSurge is 0"),
+		)
+	}
+
+	return allErrs
+}
+
+// isZeroIntOrPercent returns true if the given value is set and equal to 0 or "0%".
+func isZeroIntOrPercent(v *intstr.IntOrString) bool {
+	if v == nil {
+		return false
+	}
+	if v.Type == intstr.Int {
+		return v.IntVal == 0
+	}
+	return v.StrVal == "0%"
+}
+
 // PopulateDefaultsMachineDeployment fills in default field values.
 // This is also called during MachineDeployment sync.
 func PopulateDefaultsMachineDeployment(d *MachineDeployment) {
@@ -68,6 +68,9 @@ func TestMachineDeploymentValidation(t *testing.T) {
 	goodMaxSurgeInt := intstr.FromInt(1)
 	goodMaxUnavailableInt := intstr.FromInt(0)
 
+	zeroMaxSurgeInt := intstr.FromInt(0)
+	zeroMaxSurgePercentage := intstr.FromString("0%")
+
 	tests := []struct {
 		name      string
 		selectors map[string]string
@@ -144,6 +147,32 @@ func TestMachineDeploymentValidation(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name:      "should return error when both maxSurge and maxUnavailable are 0",
+			selectors: map[string]string{"foo": "bar"},
+			labels:    map[string]string{"foo": "bar"},
+			strategy: MachineDeploymentStrategy{
+				Type: RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxUnavailable: &goodMaxUnavailableInt,
+					MaxSurge:       &zeroMaxSurgeInt,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "should return error when both maxSurge and maxUnavailable are 0%",
+			selectors: map[string]string{"foo": "bar"},
+			labels:    map[string]string{"foo": "bar"},
+			strategy: MachineDeploymentStrategy{
+				Type: RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					MaxUnavailable: &goodMaxUnavailablePercentage,
+					MaxSurge:       &zeroMaxSurgePercentage,
+				},
+			},
+			expectErr: true,
+		},
 		{
 			name:      "should not return error for valid percentage string maxSurge and maxUnavailable",
 			selectors: map[string]string{"foo": "bar"},
@@ -157,6 +186,30 @@ func TestMachineDeploymentValidation(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name:      "should not return error for a supported deletePolicy",
+			selectors: map[string]string{"foo": "bar"},
+			labels:    map[string]string{"foo": "bar"},
+			strategy: MachineDeploymentStrategy{
+				Type: RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					DeletePolicy: pointer.String(string(UnhealthyFirstMachineSetDeletePolicy)),
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name:      "should return error for an unsupported deletePolicy",
+			selectors: map[string]string{"foo": "bar"},
+			labels:    map[string]string{"foo": "bar"},
+			strategy: MachineDeploymentStrategy{
+				Type: RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &MachineRollingUpdateDeployment{
+					DeletePolicy: pointer.String("Biggest"),
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -245,6 +298,47 @@ func TestMachineDeploymentVersionValidation(t *testing.T) {
 	}
 }
 
+func TestMachineDeploymentRollbackToValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		expectErr bool
+	}{
+		{
+			name:      "should succeed when the MachineDeployment is not managed by a Cluster topology",
+			expectErr: false,
+		},
+		{
+			name:      "should return error when the MachineDeployment is managed by a Cluster topology",
+			labels:    map[string]string{ClusterTopologyOwnedLabel: ""},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := &MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: tt.labels,
+				},
+				Spec: MachineDeploymentSpec{
+					RollbackTo: &MachineDeploymentRollback{Revision: 2},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(md.ValidateCreate()).NotTo(Succeed())
+				g.Expect(md.ValidateUpdate(md)).NotTo(Succeed())
+			} else {
+				g.Expect(md.ValidateCreate()).To(Succeed())
+				g.Expect(md.ValidateUpdate(md)).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestMachineDeploymentWithSpec(t *testing.T) {
 	g := NewWithT(t)
 	md := MachineDeployment{
@@ -308,3 +402,61 @@ func TestMachineDeploymentClusterNameImmutable(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineDeploymentSelectorImmutable(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldSelector map[string]string
+		newSelector map[string]string
+		expectErr   bool
+	}{
+		{
+			name:        "when the selector has not changed",
+			oldSelector: map[string]string{"foo": "bar"},
+			newSelector: map[string]string{"foo": "bar"},
+			expectErr:   false,
+		},
+		{
+			name:        "when the cluster name label is added to the selector",
+			oldSelector: map[string]string{"foo": "bar"},
+			newSelector: map[string]string{"foo": "bar", ClusterLabelName: "test-cluster"},
+			expectErr:   false,
+		},
+		{
+			name:        "when the selector has changed",
+			oldSelector: map[string]string{"foo": "bar"},
+			newSelector: map[string]string{"foo": "baz"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newMD := &MachineDeployment{
+				Spec: MachineDeploymentSpec{
+					Selector: metav1.LabelSelector{MatchLabels: tt.newSelector},
+					Template: MachineTemplateSpec{
+						ObjectMeta: ObjectMeta{Labels: tt.newSelector},
+					},
+				},
+			}
+
+			oldMD := &MachineDeployment{
+				Spec: MachineDeploymentSpec{
+					Selector: metav1.LabelSelector{MatchLabels: tt.oldSelector},
+					Template: MachineTemplateSpec{
+						ObjectMeta: ObjectMeta{Labels: tt.oldSelector},
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(newMD.ValidateUpdate(oldMD)).NotTo(Succeed())
+			} else {
+				g.Expect(newMD.ValidateUpdate(oldMD)).To(Succeed())
+			}
+		})
+	}
+}
@@ -69,6 +69,14 @@ type MachineHealthCheckSpec struct {
 	// a controller that lives outside of Cluster API.
 	// +optional
 	RemediationTemplate *corev1.ObjectReference `json:"remediationTemplate,omitempty"`
+
+	// ExternalRemediationTimeout is the maximum amount of time an external remediation request
+	// created from RemediationTemplate is given to remediate a Machine.
+	// If the Machine is still unhealthy after the timeout, the MachineHealthCheck controller falls
+	// back to the default remediation and marks the Machine for deletion by its owner controller.
+	// If not set, the MachineHealthCheck controller waits for the external remediation indefinitely.
+	// +optional
+	ExternalRemediationTimeout *metav1.Duration `json:"externalRemediationTimeout,omitempty"`
 }
 
 // ANCHOR_END: MachineHealthCHeckSpec
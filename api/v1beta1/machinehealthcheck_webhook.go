@@ -164,6 +164,21 @@ func (m *MachineHealthCheck) validate(old *MachineHealthCheck) error {
 		)
 	}
 
+	if m.Spec.ExternalRemediationTimeout != nil {
+		if m.Spec.RemediationTemplate == nil {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(field.NewPath("spec", "externalRemediationTimeout"), "can only be set together with spec.remediationTemplate"),
+			)
+		}
+		if m.Spec.ExternalRemediationTimeout.Duration <= 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("spec", "externalRemediationTimeout"), m.Spec.ExternalRemediationTimeout.Duration.String(), "must be greater than 0"),
+			)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -339,3 +339,63 @@ func TestMachineHealthCheckRemediationTemplateNamespaceValidation(t *testing.T)
 		})
 	}
 }
+
+func TestMachineHealthCheckExternalRemediationTimeoutValidation(t *testing.T) {
+	tests := []struct {
+		name                string
+		remediationTemplate *corev1.ObjectReference
+		timeout             *metav1.Duration
+		expectErr           bool
+	}{
+		{
+			name:                "should succeed when not set",
+			remediationTemplate: &corev1.ObjectReference{Namespace: "foo"},
+			expectErr:           false,
+		},
+		{
+			name:                "should succeed when set together with a remediation template",
+			remediationTemplate: &corev1.ObjectReference{Namespace: "foo"},
+			timeout:             &metav1.Duration{Duration: 30 * time.Minute},
+			expectErr:           false,
+		},
+		{
+			name:      "should return error when set without a remediation template",
+			timeout:   &metav1.Duration{Duration: 30 * time.Minute},
+			expectErr: true,
+		},
+		{
+			name:                "should return error when zero",
+			remediationTemplate: &corev1.ObjectReference{Namespace: "foo"},
+			timeout:             &metav1.Duration{},
+			expectErr:           true,
+		},
+		{
+			name:                "should return error when negative",
+			remediationTemplate: &corev1.ObjectReference{Namespace: "foo"},
+			timeout:             &metav1.Duration{Duration: -30 * time.Minute},
+			expectErr:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mhc := &MachineHealthCheck{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+				},
+				Spec: MachineHealthCheckSpec{
+					Selector:                   metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
+					RemediationTemplate:        tt.remediationTemplate,
+					ExternalRemediationTimeout: tt.timeout,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(mhc.validate(nil)).NotTo(Succeed())
+			} else {
+				g.Expect(mhc.validate(nil)).To(Succeed())
+			}
+		})
+	}
+}
@@ -51,8 +51,9 @@ type MachineSetSpec struct {
 	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
 
 	// DeletePolicy defines the policy used to identify nodes to delete when downscaling.
-	// Defaults to "Random".  Valid values are "Random, "Newest", "Oldest"
-	// +kubebuilder:validation:Enum=Random;Newest;Oldest
+	// Machines with the "cluster.x-k8s.io/delete-machine" annotation are always deleted first.
+	// Defaults to "Random".  Valid values are "Random, "Newest", "Oldest", "UnhealthyFirst"
+	// +kubebuilder:validation:Enum=Random;Newest;Oldest;UnhealthyFirst
 	DeletePolicy string `json:"deletePolicy,omitempty"`
 
 	// Selector is a label query over machines that should match the replica count.
@@ -109,6 +110,12 @@ const (
 	// (Status.FailureReason or Status.FailureMessage are set to a non-empty value).
 	// It then prioritizes the oldest Machines for deletion based on the Machine's CreationTimestamp.
 	OldestMachineSetDeletePolicy MachineSetDeletePolicy = "Oldest"
+
+	// UnhealthyFirstMachineSetDeletePolicy prioritizes both Machines that have the annotation
+	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy, which includes
+	// Machines that failed a MachineHealthCheck or whose Node is not healthy.
+	// It then prioritizes the oldest Machines for deletion based on the Machine's CreationTimestamp.
+	UnhealthyFirstMachineSetDeletePolicy MachineSetDeletePolicy = "UnhealthyFirst"
 )
 
 // ANCHOR: MachineSetStatus
@@ -18,12 +18,15 @@ package v1beta1
 
 import (
 	"fmt"
+	"strings"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/util/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -64,6 +67,15 @@ func (m *MachineSet) Default() {
 		m.Spec.Selector.MatchLabels[MachineSetLabelName] = m.Name
 		m.Spec.Template.Labels[MachineSetLabelName] = m.Name
 	}
+	// Make sure selector and template to be in the same cluster.
+	m.Spec.Selector.MatchLabels[ClusterLabelName] = m.Spec.ClusterName
+	m.Spec.Template.Labels[ClusterLabelName] = m.Spec.ClusterName
+
+	// tolerate version strings without a "v" prefix: prepend it if it's not there
+	if m.Spec.Template.Spec.Version != nil && !strings.HasPrefix(*m.Spec.Template.Spec.Version, "v") {
+		normalizedVersion := "v" + *m.Spec.Template.Spec.Version
+		m.Spec.Template.Spec.Version = &normalizedVersion
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -111,9 +123,64 @@ func (m *MachineSet) validate(old *MachineSet) error {
 		)
 	}
 
+	if old != nil && !selectorsEqualIgnoringClusterLabel(old.Spec.Selector, m.Spec.Selector) {
+		allErrs = append(
+			allErrs,
+			field.Invalid(field.NewPath("spec", "selector"), m.Spec.Selector, "field is immutable"),
+		)
+	}
+
+	if m.Spec.DeletePolicy != "" {
+		allErrs = append(allErrs, validateDeletePolicy(m.Spec.DeletePolicy, field.NewPath("spec", "deletePolicy"))...)
+	}
+
+	if m.Spec.Template.Spec.Version != nil {
+		if !version.KubeSemver.MatchString(*m.Spec.Template.Spec.Version) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "template", "spec", "version"), *m.Spec.Template.Spec.Version, "must be a valid semantic version"))
+		}
+	}
+
+	allErrs = append(allErrs, validateNodeDrainOptions(&m.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 
 	return apierrors.NewInvalid(GroupVersion.WithKind("MachineSet").GroupKind(), m.Name, allErrs)
 }
+
+// validateDeletePolicy validates the policy used to identify the Machines to delete when downscaling.
+// NOTE: This is also used to validate the delete policy of the MachineSets created by a MachineDeployment.
+func validateDeletePolicy(deletePolicy string, fldPath *field.Path) field.ErrorList {
+	switch MachineSetDeletePolicy(deletePolicy) {
+	case RandomMachineSetDeletePolicy, NewestMachineSetDeletePolicy, OldestMachineSetDeletePolicy, UnhealthyFirstMachineSetDeletePolicy:
+		return nil
+	default:
+		return field.ErrorList{
+			field.NotSupported(fldPath, deletePolicy, []string{
+				string(RandomMachineSetDeletePolicy),
+				string(NewestMachineSetDeletePolicy),
+				string(OldestMachineSetDeletePolicy),
+				string(UnhealthyFirstMachineSetDeletePolicy),
+			}),
+		}
+	}
+}
+
+// selectorsEqualIgnoringClusterLabel compares two label selectors, ignoring the cluster name label
+// that defaulting adds to the selector; this allows objects created before the label was defaulted
+// to be updated without tripping the selector immutability check.
+func selectorsEqualIgnoringClusterLabel(a, b metav1.LabelSelector) bool {
+	withoutClusterLabel := func(in map[string]string) map[string]string {
+		out := make(map[string]string, len(in))
+		for k, v := range in {
+			if k == ClusterLabelName {
+				continue
+			}
+			out[k] = v
+		}
+		return out
+	}
+	return apiequality.Semantic.DeepEqual(withoutClusterLabel(a.MatchLabels), withoutClusterLabel(b.MatchLabels)) &&
+		apiequality.Semantic.DeepEqual(a.MatchExpressions, b.MatchExpressions)
+}
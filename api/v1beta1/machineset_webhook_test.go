@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
 )
 
@@ -31,6 +32,14 @@ func TestMachineSetDefault(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "test-ms",
 		},
+		Spec: MachineSetSpec{
+			ClusterName: "test-cluster",
+			Template: MachineTemplateSpec{
+				Spec: MachineSpec{
+					Version: pointer.String("1.19.10"),
+				},
+			},
+		},
 	}
 	t.Run("for MachineSet", utildefaulting.DefaultValidateTest(ms))
 	ms.Default()
@@ -39,6 +48,9 @@ func TestMachineSetDefault(t *testing.T) {
 	g.Expect(ms.Spec.DeletePolicy).To(Equal(string(RandomMachineSetDeletePolicy)))
 	g.Expect(ms.Spec.Selector.MatchLabels).To(HaveKeyWithValue(MachineSetLabelName, "test-ms"))
 	g.Expect(ms.Spec.Template.Labels).To(HaveKeyWithValue(MachineSetLabelName, "test-ms"))
+	g.Expect(ms.Spec.Selector.MatchLabels).To(HaveKeyWithValue(ClusterLabelName, "test-cluster"))
+	g.Expect(ms.Spec.Template.Labels).To(HaveKeyWithValue(ClusterLabelName, "test-cluster"))
+	g.Expect(*ms.Spec.Template.Spec.Version).To(Equal("v1.19.10"))
 }
 
 func TestMachineSetLabelSelectorMatchValidation(t *testing.T) {
@@ -151,3 +163,153 @@ func TestMachineSetClusterNameImmutable(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineSetSelectorImmutable(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldSelector map[string]string
+		newSelector map[string]string
+		expectErr   bool
+	}{
+		{
+			name:        "when the selector has not changed",
+			oldSelector: map[string]string{"foo": "bar"},
+			newSelector: map[string]string{"foo": "bar"},
+			expectErr:   false,
+		},
+		{
+			name:        "when the cluster name label is added to the selector",
+			oldSelector: map[string]string{"foo": "bar"},
+			newSelector: map[string]string{"foo": "bar", ClusterLabelName: "test-cluster"},
+			expectErr:   false,
+		},
+		{
+			name:        "when the selector has changed",
+			oldSelector: map[string]string{"foo": "bar"},
+			newSelector: map[string]string{"foo": "bar", "hello": "world"},
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newMS := &MachineSet{
+				Spec: MachineSetSpec{
+					Selector: metav1.LabelSelector{MatchLabels: tt.newSelector},
+					Template: MachineTemplateSpec{
+						ObjectMeta: ObjectMeta{Labels: tt.newSelector},
+					},
+				},
+			}
+
+			oldMS := &MachineSet{
+				Spec: MachineSetSpec{
+					Selector: metav1.LabelSelector{MatchLabels: tt.oldSelector},
+					Template: MachineTemplateSpec{
+						ObjectMeta: ObjectMeta{Labels: tt.oldSelector},
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(newMS.ValidateUpdate(oldMS)).NotTo(Succeed())
+			} else {
+				g.Expect(newMS.ValidateUpdate(oldMS)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineSetVersionValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		expectErr bool
+	}{
+		{
+			name:      "should succeed when given a valid semantic version with prepended 'v'",
+			version:   "v1.19.2",
+			expectErr: false,
+		},
+		{
+			name:      "should return error when given a valid semantic version without 'v'",
+			version:   "1.19.2",
+			expectErr: true,
+		},
+		{
+			name:      "should return error when given an invalid semantic version",
+			version:   "v1",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := &MachineSet{
+				Spec: MachineSetSpec{
+					Template: MachineTemplateSpec{
+						Spec: MachineSpec{
+							Version: pointer.String(tt.version),
+						},
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(ms.ValidateCreate()).NotTo(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).NotTo(Succeed())
+			} else {
+				g.Expect(ms.ValidateCreate()).To(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineSetDeletePolicyValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		deletePolicy string
+		expectErr    bool
+	}{
+		{
+			name:         "should succeed when given the UnhealthyFirst delete policy",
+			deletePolicy: string(UnhealthyFirstMachineSetDeletePolicy),
+			expectErr:    false,
+		},
+		{
+			name:         "should succeed when no delete policy is given",
+			deletePolicy: "",
+			expectErr:    false,
+		},
+		{
+			name:         "should return error when given an unsupported delete policy",
+			deletePolicy: "Biggest",
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := &MachineSet{
+				Spec: MachineSetSpec{
+					DeletePolicy: tt.deletePolicy,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(ms.ValidateCreate()).NotTo(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).NotTo(Succeed())
+			} else {
+				g.Expect(ms.ValidateCreate()).To(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).To(Succeed())
+			}
+		})
+	}
+}
@@ -51,6 +51,10 @@ func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.InitConfiguration.NodeRegistration.IgnorePreflightErrors = restored.Spec.InitConfiguration.NodeRegistration.IgnorePreflightErrors
 	}
 
+	RestoreFiles(restored.Spec.Files, dst.Spec.Files)
+	dst.Spec.DiskSetup = RestoreDiskSetup(restored.Spec.DiskSetup, dst.Spec.DiskSetup)
+	dst.Spec.ContainerImages = restored.Spec.ContainerImages
+
 	return nil
 }
 
@@ -108,6 +112,10 @@ func (src *KubeadmConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.Template.Spec.InitConfiguration.NodeRegistration.IgnorePreflightErrors = restored.Spec.Template.Spec.InitConfiguration.NodeRegistration.IgnorePreflightErrors
 	}
 
+	RestoreFiles(restored.Spec.Template.Spec.Files, dst.Spec.Template.Spec.Files)
+	dst.Spec.Template.Spec.DiskSetup = RestoreDiskSetup(restored.Spec.Template.Spec.DiskSetup, dst.Spec.Template.Spec.DiskSetup)
+	dst.Spec.Template.Spec.ContainerImages = restored.Spec.Template.Spec.ContainerImages
+
 	return nil
 }
 
@@ -143,6 +151,73 @@ func Convert_v1alpha3_KubeadmConfigStatus_To_v1beta1_KubeadmConfigStatus(in *Kub
 	return autoConvert_v1alpha3_KubeadmConfigStatus_To_v1beta1_KubeadmConfigStatus(in, out, s)
 }
 
+func Convert_v1alpha3_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s apiconversion.Scope) error {
+	// FileSource.Secret is optional in v1beta1, because a file can be populated from a Secret or a ConfigMap.
+	out.Secret = &v1beta1.SecretFileSource{}
+	if err := Convert_v1alpha3_SecretFileSource_To_v1beta1_SecretFileSource(&in.Secret, out.Secret, s); err != nil {
+		return err
+	}
+	return autoConvert_v1alpha3_FileSource_To_v1beta1_FileSource(in, out, s)
+}
+
+func Convert_v1beta1_FileSource_To_v1alpha3_FileSource(in *v1beta1.FileSource, out *FileSource, s apiconversion.Scope) error {
+	// FileSource.ConfigMap does not exist in v1alpha3, value will be lost during conversion.
+	if in.Secret != nil {
+		if err := Convert_v1beta1_SecretFileSource_To_v1alpha3_SecretFileSource(in.Secret, &out.Secret, s); err != nil {
+			return err
+		}
+	}
+	return autoConvert_v1beta1_FileSource_To_v1alpha3_FileSource(in, out, s)
+}
+
+func Convert_v1beta1_File_To_v1alpha3_File(in *v1beta1.File, out *File, s apiconversion.Scope) error {
+	// File.Append does not exist in v1alpha3, value will be lost during conversion.
+	return autoConvert_v1beta1_File_To_v1alpha3_File(in, out, s)
+}
+
+func Convert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(in *v1beta1.DiskSetup, out *DiskSetup, s apiconversion.Scope) error {
+	// DiskSetup.VolumeGroups and DiskSetup.Swap do not exist in v1alpha3, values will be lost during conversion.
+	return autoConvert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(in, out, s)
+}
+
+func Convert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in *v1beta1.KubeadmConfigSpec, out *KubeadmConfigSpec, s apiconversion.Scope) error {
+	// KubeadmConfigSpec.ContainerImages does not exist in v1alpha3, value will be lost during conversion.
+	return autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in, out, s)
+}
+
+// RestoreDiskSetup restores the fields of the disk setup which do not exist in v1alpha3.
+func RestoreDiskSetup(restored, dst *v1beta1.DiskSetup) *v1beta1.DiskSetup {
+	if restored == nil || (restored.VolumeGroups == nil && restored.Swap == nil) {
+		return dst
+	}
+	if dst == nil {
+		dst = &v1beta1.DiskSetup{}
+	}
+	dst.VolumeGroups = restored.VolumeGroups
+	dst.Swap = restored.Swap
+	return dst
+}
+
+// RestoreFiles restores the fields of the files which do not exist in v1alpha3, as long as
+// the files have not been changed since the down-conversion.
+func RestoreFiles(restored, dst []v1beta1.File) {
+	if len(restored) != len(dst) {
+		return
+	}
+	for i := range dst {
+		if dst[i].Path != restored[i].Path {
+			continue
+		}
+		dst[i].Append = restored[i].Append
+		if dst[i].ContentFrom != nil && restored[i].ContentFrom != nil {
+			dst[i].ContentFrom.ConfigMap = restored[i].ContentFrom.ConfigMap
+			if restored[i].ContentFrom.Secret == nil {
+				dst[i].ContentFrom.Secret = nil
+			}
+		}
+	}
+}
+
 func Convert_v1beta1_ClusterConfiguration_To_upstreamv1beta1_ClusterConfiguration(in *v1beta1.ClusterConfiguration, out *upstreamv1beta1.ClusterConfiguration, s apiconversion.Scope) error {
 	// DNS.Type was removed in v1alpha4 because only CoreDNS is supported; the information will be left to empty (kubeadm defaults it to CoredDNS);
 	// Existing clusters using kube-dns or other DNS solutions will continue to be managed/supported via the skip-coredns annotation.
@@ -43,31 +43,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.DiskSetup)(nil), (*DiskSetup)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(a.(*v1beta1.DiskSetup), b.(*DiskSetup), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*File)(nil), (*v1beta1.File)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_File_To_v1beta1_File(a.(*File), b.(*v1beta1.File), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.File)(nil), (*File)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_File_To_v1alpha3_File(a.(*v1beta1.File), b.(*File), scope)
-	}); err != nil {
-		return err
-	}
-	if err := s.AddGeneratedConversionFunc((*FileSource)(nil), (*v1beta1.FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha3_FileSource_To_v1beta1_FileSource(a.(*FileSource), b.(*v1beta1.FileSource), scope)
-	}); err != nil {
-		return err
-	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.FileSource)(nil), (*FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_FileSource_To_v1alpha3_FileSource(a.(*v1beta1.FileSource), b.(*FileSource), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*Filesystem)(nil), (*v1beta1.Filesystem)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_Filesystem_To_v1beta1_Filesystem(a.(*Filesystem), b.(*v1beta1.Filesystem), scope)
 	}); err != nil {
@@ -103,11 +83,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.KubeadmConfigSpec)(nil), (*KubeadmConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(a.(*v1beta1.KubeadmConfigSpec), b.(*KubeadmConfigSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.KubeadmConfigStatus)(nil), (*KubeadmConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_KubeadmConfigStatus_To_v1alpha3_KubeadmConfigStatus(a.(*v1beta1.KubeadmConfigStatus), b.(*KubeadmConfigStatus), scope)
 	}); err != nil {
@@ -208,11 +183,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*FileSource)(nil), (*v1beta1.FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_FileSource_To_v1beta1_FileSource(a.(*FileSource), b.(*v1beta1.FileSource), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*KubeadmConfigStatus)(nil), (*v1beta1.KubeadmConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_KubeadmConfigStatus_To_v1beta1_KubeadmConfigStatus(a.(*KubeadmConfigStatus), b.(*v1beta1.KubeadmConfigStatus), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.DiskSetup)(nil), (*DiskSetup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(a.(*v1beta1.DiskSetup), b.(*DiskSetup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.File)(nil), (*File)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_File_To_v1alpha3_File(a.(*v1beta1.File), b.(*File), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.FileSource)(nil), (*FileSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FileSource_To_v1alpha3_FileSource(a.(*v1beta1.FileSource), b.(*FileSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.KubeadmConfigSpec)(nil), (*KubeadmConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(a.(*v1beta1.KubeadmConfigSpec), b.(*KubeadmConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.ClusterConfiguration)(nil), (*upstreamv1beta1.ClusterConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ClusterConfiguration_To_upstreamv1beta1_ClusterConfiguration(a.(*v1beta1.ClusterConfiguration), b.(*upstreamv1beta1.ClusterConfiguration), scope)
 	}); err != nil {
@@ -245,21 +245,26 @@ func Convert_v1alpha3_DiskSetup_To_v1beta1_DiskSetup(in *DiskSetup, out *v1beta1
 func autoConvert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(in *v1beta1.DiskSetup, out *DiskSetup, s conversion.Scope) error {
 	out.Partitions = *(*[]Partition)(unsafe.Pointer(&in.Partitions))
 	out.Filesystems = *(*[]Filesystem)(unsafe.Pointer(&in.Filesystems))
+	// WARNING: in.VolumeGroups requires manual conversion: does not exist in peer-type
+	// WARNING: in.Swap requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup is an autogenerated conversion function.
-func Convert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(in *v1beta1.DiskSetup, out *DiskSetup, s conversion.Scope) error {
-	return autoConvert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(in, out, s)
-}
-
 func autoConvert_v1alpha3_File_To_v1beta1_File(in *File, out *v1beta1.File, s conversion.Scope) error {
 	out.Path = in.Path
 	out.Owner = in.Owner
 	out.Permissions = in.Permissions
 	out.Encoding = v1beta1.Encoding(in.Encoding)
 	out.Content = in.Content
-	out.ContentFrom = (*v1beta1.FileSource)(unsafe.Pointer(in.ContentFrom))
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(v1beta1.FileSource)
+		if err := Convert_v1alpha3_FileSource_To_v1beta1_FileSource(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContentFrom = nil
+	}
 	return nil
 }
 
@@ -274,39 +279,30 @@ func autoConvert_v1beta1_File_To_v1alpha3_File(in *v1beta1.File, out *File, s co
 	out.Permissions = in.Permissions
 	out.Encoding = Encoding(in.Encoding)
 	out.Content = in.Content
-	out.ContentFrom = (*FileSource)(unsafe.Pointer(in.ContentFrom))
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(FileSource)
+		if err := Convert_v1beta1_FileSource_To_v1alpha3_FileSource(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ContentFrom = nil
+	}
+	// WARNING: in.Append requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_File_To_v1alpha3_File is an autogenerated conversion function.
-func Convert_v1beta1_File_To_v1alpha3_File(in *v1beta1.File, out *File, s conversion.Scope) error {
-	return autoConvert_v1beta1_File_To_v1alpha3_File(in, out, s)
-}
-
 func autoConvert_v1alpha3_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s conversion.Scope) error {
-	if err := Convert_v1alpha3_SecretFileSource_To_v1beta1_SecretFileSource(&in.Secret, &out.Secret, s); err != nil {
-		return err
-	}
+	// WARNING: in.Secret requires manual conversion: inconvertible types (sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha3.SecretFileSource vs *sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1.SecretFileSource)
 	return nil
 }
 
-// Convert_v1alpha3_FileSource_To_v1beta1_FileSource is an autogenerated conversion function.
-func Convert_v1alpha3_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s conversion.Scope) error {
-	return autoConvert_v1alpha3_FileSource_To_v1beta1_FileSource(in, out, s)
-}
-
 func autoConvert_v1beta1_FileSource_To_v1alpha3_FileSource(in *v1beta1.FileSource, out *FileSource, s conversion.Scope) error {
-	if err := Convert_v1beta1_SecretFileSource_To_v1alpha3_SecretFileSource(&in.Secret, &out.Secret, s); err != nil {
-		return err
-	}
+	// WARNING: in.Secret requires manual conversion: inconvertible types (*sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1.SecretFileSource vs sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha3.SecretFileSource)
+	// WARNING: in.ConfigMap requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_FileSource_To_v1alpha3_FileSource is an autogenerated conversion function.
-func Convert_v1beta1_FileSource_To_v1alpha3_FileSource(in *v1beta1.FileSource, out *FileSource, s conversion.Scope) error {
-	return autoConvert_v1beta1_FileSource_To_v1alpha3_FileSource(in, out, s)
-}
-
 func autoConvert_v1alpha3_Filesystem_To_v1beta1_Filesystem(in *Filesystem, out *v1beta1.Filesystem, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -441,8 +437,26 @@ func autoConvert_v1alpha3_KubeadmConfigSpec_To_v1beta1_KubeadmConfigSpec(in *Kub
 	} else {
 		out.JoinConfiguration = nil
 	}
-	out.Files = *(*[]v1beta1.File)(unsafe.Pointer(&in.Files))
-	out.DiskSetup = (*v1beta1.DiskSetup)(unsafe.Pointer(in.DiskSetup))
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]v1beta1.File, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_File_To_v1beta1_File(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Files = nil
+	}
+	if in.DiskSetup != nil {
+		in, out := &in.DiskSetup, &out.DiskSetup
+		*out = new(v1beta1.DiskSetup)
+		if err := Convert_v1alpha3_DiskSetup_To_v1beta1_DiskSetup(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.DiskSetup = nil
+	}
 	out.Mounts = *(*[]v1beta1.MountPoints)(unsafe.Pointer(&in.Mounts))
 	out.PreKubeadmCommands = *(*[]string)(unsafe.Pointer(&in.PreKubeadmCommands))
 	out.PostKubeadmCommands = *(*[]string)(unsafe.Pointer(&in.PostKubeadmCommands))
@@ -487,9 +501,28 @@ func autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in *v1b
 	} else {
 		out.JoinConfiguration = nil
 	}
-	out.Files = *(*[]File)(unsafe.Pointer(&in.Files))
-	out.DiskSetup = (*DiskSetup)(unsafe.Pointer(in.DiskSetup))
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_File_To_v1alpha3_File(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Files = nil
+	}
+	if in.DiskSetup != nil {
+		in, out := &in.DiskSetup, &out.DiskSetup
+		*out = new(DiskSetup)
+		if err := Convert_v1beta1_DiskSetup_To_v1alpha3_DiskSetup(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.DiskSetup = nil
+	}
 	out.Mounts = *(*[]MountPoints)(unsafe.Pointer(&in.Mounts))
+	// WARNING: in.ContainerImages requires manual conversion: does not exist in peer-type
 	out.PreKubeadmCommands = *(*[]string)(unsafe.Pointer(&in.PreKubeadmCommands))
 	out.PostKubeadmCommands = *(*[]string)(unsafe.Pointer(&in.PostKubeadmCommands))
 	out.Users = *(*[]User)(unsafe.Pointer(&in.Users))
@@ -500,11 +533,6 @@ func autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in *v1b
 	return nil
 }
 
-// Convert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec is an autogenerated conversion function.
-func Convert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in *v1beta1.KubeadmConfigSpec, out *KubeadmConfigSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha3_KubeadmConfigSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_KubeadmConfigStatus_To_v1beta1_KubeadmConfigStatus(in *KubeadmConfigStatus, out *v1beta1.KubeadmConfigStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.DataSecretName = (*string)(unsafe.Pointer(in.DataSecretName))
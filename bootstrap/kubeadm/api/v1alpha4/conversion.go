@@ -17,20 +17,41 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
 func (src *KubeadmConfig) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.KubeadmConfig)
 
-	return Convert_v1alpha4_KubeadmConfig_To_v1beta1_KubeadmConfig(src, dst, nil)
+	if err := Convert_v1alpha4_KubeadmConfig_To_v1beta1_KubeadmConfig(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.KubeadmConfig{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	RestoreFiles(restored.Spec.Files, dst.Spec.Files)
+	dst.Spec.DiskSetup = RestoreDiskSetup(restored.Spec.DiskSetup, dst.Spec.DiskSetup)
+	dst.Spec.ContainerImages = restored.Spec.ContainerImages
+
+	return nil
 }
 
 func (dst *KubeadmConfig) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.KubeadmConfig)
 
-	return Convert_v1beta1_KubeadmConfig_To_v1alpha4_KubeadmConfig(src, dst, nil)
+	if err := Convert_v1beta1_KubeadmConfig_To_v1alpha4_KubeadmConfig(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *KubeadmConfigList) ConvertTo(dstRaw conversion.Hub) error {
@@ -48,13 +69,32 @@ func (dst *KubeadmConfigList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *KubeadmConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.KubeadmConfigTemplate)
 
-	return Convert_v1alpha4_KubeadmConfigTemplate_To_v1beta1_KubeadmConfigTemplate(src, dst, nil)
+	if err := Convert_v1alpha4_KubeadmConfigTemplate_To_v1beta1_KubeadmConfigTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.KubeadmConfigTemplate{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	RestoreFiles(restored.Spec.Template.Spec.Files, dst.Spec.Template.Spec.Files)
+	dst.Spec.Template.Spec.DiskSetup = RestoreDiskSetup(restored.Spec.Template.Spec.DiskSetup, dst.Spec.Template.Spec.DiskSetup)
+	dst.Spec.Template.Spec.ContainerImages = restored.Spec.Template.Spec.ContainerImages
+
+	return nil
 }
 
 func (dst *KubeadmConfigTemplate) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.KubeadmConfigTemplate)
 
-	return Convert_v1beta1_KubeadmConfigTemplate_To_v1alpha4_KubeadmConfigTemplate(src, dst, nil)
+	if err := Convert_v1beta1_KubeadmConfigTemplate_To_v1alpha4_KubeadmConfigTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *KubeadmConfigTemplateList) ConvertTo(dstRaw conversion.Hub) error {
@@ -68,3 +108,70 @@ func (dst *KubeadmConfigTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_KubeadmConfigTemplateList_To_v1alpha4_KubeadmConfigTemplateList(src, dst, nil)
 }
+
+func Convert_v1alpha4_FileSource_To_v1beta1_FileSource(in *FileSource, out *v1beta1.FileSource, s apiconversion.Scope) error {
+	// FileSource.Secret is optional in v1beta1, because a file can be populated from a Secret or a ConfigMap.
+	out.Secret = &v1beta1.SecretFileSource{}
+	if err := Convert_v1alpha4_SecretFileSource_To_v1beta1_SecretFileSource(&in.Secret, out.Secret, s); err != nil {
+		return err
+	}
+	return autoConvert_v1alpha4_FileSource_To_v1beta1_FileSource(in, out, s)
+}
+
+func Convert_v1beta1_FileSource_To_v1alpha4_FileSource(in *v1beta1.FileSource, out *FileSource, s apiconversion.Scope) error {
+	// FileSource.ConfigMap does not exist in v1alpha4, value will be lost during conversion.
+	if in.Secret != nil {
+		if err := Convert_v1beta1_SecretFileSource_To_v1alpha4_SecretFileSource(in.Secret, &out.Secret, s); err != nil {
+			return err
+		}
+	}
+	return autoConvert_v1beta1_FileSource_To_v1alpha4_FileSource(in, out, s)
+}
+
+func Convert_v1beta1_File_To_v1alpha4_File(in *v1beta1.File, out *File, s apiconversion.Scope) error {
+	// File.Append does not exist in v1alpha4, value will be lost during conversion.
+	return autoConvert_v1beta1_File_To_v1alpha4_File(in, out, s)
+}
+
+func Convert_v1beta1_DiskSetup_To_v1alpha4_DiskSetup(in *v1beta1.DiskSetup, out *DiskSetup, s apiconversion.Scope) error {
+	// DiskSetup.VolumeGroups and DiskSetup.Swap do not exist in v1alpha4, values will be lost during conversion.
+	return autoConvert_v1beta1_DiskSetup_To_v1alpha4_DiskSetup(in, out, s)
+}
+
+func Convert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in *v1beta1.KubeadmConfigSpec, out *KubeadmConfigSpec, s apiconversion.Scope) error {
+	// KubeadmConfigSpec.ContainerImages does not exist in v1alpha4, value will be lost during conversion.
+	return autoConvert_v1beta1_KubeadmConfigSpec_To_v1alpha4_KubeadmConfigSpec(in, out, s)
+}
+
+// RestoreDiskSetup restores the fields of the disk setup which do not exist in v1alpha4.
+func RestoreDiskSetup(restored, dst *v1beta1.DiskSetup) *v1beta1.DiskSetup {
+	if restored == nil || (restored.VolumeGroups == nil && restored.Swap == nil) {
+		return dst
+	}
+	if dst == nil {
+		dst = &v1beta1.DiskSetup{}
+	}
+	dst.VolumeGroups = restored.VolumeGroups
+	dst.Swap = restored.Swap
+	return dst
+}
+
+// RestoreFiles restores the fields of the files which do not exist in v1alpha4, as long as
+// the files have not been changed since the down-conversion.
+func RestoreFiles(restored, dst []v1beta1.File) {
+	if len(restored) != len(dst) {
+		return
+	}
+	for i := range dst {
+		if dst[i].Path != restored[i].Path {
+			continue
+		}
+		dst[i].Append = restored[i].Append
+		if dst[i].ContentFrom != nil && restored[i].ContentFrom != nil {
+			dst[i].ContentFrom.ConfigMap = restored[i].ContentFrom.ConfigMap
+			if restored[i].ContentFrom.Secret == nil {
+				dst[i].ContentFrom.Secret = nil
+			}
+		}
+	}
+}
@@ -56,6 +56,7 @@ func fuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 		// the values for ID and Secret to working alphanumeric values.
 		kubeadmBootstrapTokenStringFuzzerV1UpstreamBeta1,
 		kubeadmBootstrapTokenStringFuzzerV1Beta1,
+		kubeadmBootstrapTokenStringFuzzerV1Alpha4,
 	}
 }
 
@@ -82,3 +83,8 @@ func kubeadmBootstrapTokenStringFuzzerV1Beta1(in *v1beta1.BootstrapTokenString,
 	in.ID = "abcdef"
 	in.Secret = "abcdef0123456789"
 }
+
+func kubeadmBootstrapTokenStringFuzzerV1Alpha4(in *BootstrapTokenString, c fuzz.Continue) {
+	in.ID = "abcdef"
+	in.Secret = "abcdef0123456789"
+}
@@ -39,6 +39,11 @@ const (
 	// an error while generating a data secret; those kind of errors are usually due to misconfigurations
 	// and user intervention is required to get them fixed.
 	DataSecretGenerationFailedReason = "DataSecretGenerationFailed"
+
+	// BootstrapTokenExpiredReason (Severity=Warning) documents a KubeadmConfig controller detecting that the
+	// bootstrap token in the data secret expired before being used by the machine to join the cluster;
+	// the controller automatically issues a new token and updates the data secret.
+	BootstrapTokenExpiredReason = "BootstrapTokenExpired"
 )
 
 const (
@@ -22,12 +22,18 @@ import (
 )
 
 // Format specifies the output format of the bootstrap data
-// +kubebuilder:validation:Enum=cloud-config
+// +kubebuilder:validation:Enum=cloud-config;ignition;script
 type Format string
 
 const (
 	// CloudConfig make the bootstrap data to be of cloud-config format.
 	CloudConfig Format = "cloud-config"
+
+	// Ignition make the bootstrap data to be of Ignition format, e.g. for bootstrapping immutable OSes like Flatcar.
+	Ignition Format = "ignition"
+
+	// Script make the bootstrap data to be a shell script, e.g. for bootstrapping machines without cloud-init or Ignition.
+	Script Format = "script"
 )
 
 // KubeadmConfigSpec defines the desired state of KubeadmConfig.
@@ -57,6 +63,12 @@ type KubeadmConfigSpec struct {
 	// +optional
 	Mounts []MountPoints `json:"mounts,omitempty"`
 
+	// ContainerImages specifies the configuration of the container images used by the machine, e.g. the image
+	// repositories, the images to be pulled before running kubeadm and the registry mirrors and credentials,
+	// which allows to bootstrap machines in air-gapped environments.
+	// +optional
+	ContainerImages *ContainerImages `json:"containerImages,omitempty"`
+
 	// PreKubeadmCommands specifies extra commands to run before kubeadm runs
 	// +optional
 	PreKubeadmCommands []string `json:"preKubeadmCommands,omitempty"`
@@ -73,7 +85,10 @@ type KubeadmConfigSpec struct {
 	// +optional
 	NTP *NTP `json:"ntp,omitempty"`
 
-	// Format specifies the output format of the bootstrap data
+	// Format specifies the output format of the bootstrap data.
+	// Defaults to cloud-config. When using ignition, UseExperimentalRetryJoin, Users[].Inactive,
+	// mbr partition tables and the Partition and ReplaceFS fields of file systems are not supported.
+	// When using script, UseExperimentalRetryJoin, Users, NTP, DiskSetup and Mounts are not supported.
 	// +optional
 	Format Format `json:"format,omitempty"`
 
@@ -197,6 +212,11 @@ type File struct {
 	// ContentFrom is a referenced source of content to populate the file.
 	// +optional
 	ContentFrom *FileSource `json:"contentFrom,omitempty"`
+
+	// Append specifies whether to append the content to the file if it already exists,
+	// instead of overwriting it.
+	// +optional
+	Append bool `json:"append,omitempty"`
 }
 
 // FileSource is a union of all possible external source types for file data.
@@ -204,7 +224,12 @@ type File struct {
 // sources of data for target systems should add them here.
 type FileSource struct {
 	// Secret represents a secret that should populate this file.
-	Secret SecretFileSource `json:"secret"`
+	// +optional
+	Secret *SecretFileSource `json:"secret,omitempty"`
+
+	// ConfigMap represents a config map that should populate this file.
+	// +optional
+	ConfigMap *ConfigMapFileSource `json:"configMap,omitempty"`
 }
 
 // SecretFileSource adapts a Secret into a FileSource.
@@ -219,6 +244,18 @@ type SecretFileSource struct {
 	Key string `json:"key"`
 }
 
+// ConfigMapFileSource adapts a ConfigMap into a FileSource.
+//
+// The contents of the target ConfigMap's Data field will be presented
+// as files using the keys in the Data field as the file names.
+type ConfigMapFileSource struct {
+	// Name of the config map in the KubeadmBootstrapConfig's namespace to use.
+	Name string `json:"name"`
+
+	// Key is the key in the config map's data map for this value.
+	Key string `json:"key"`
+}
+
 // User defines the input for a generated user in cloud-init.
 type User struct {
 	// Name specifies the user name
@@ -276,12 +313,20 @@ type NTP struct {
 	Enabled *bool `json:"enabled,omitempty"`
 }
 
-// DiskSetup defines input for generated disk_setup and fs_setup in cloud-init.
+// DiskSetup defines input for generated disk_setup, fs_setup, LVM and swap setup in cloud-init.
 type DiskSetup struct {
 	// Partitions specifies the list of the partitions to setup.
 	Partitions []Partition `json:"partitions,omitempty"`
 	// Filesystems specifies the list of file systems to setup.
 	Filesystems []Filesystem `json:"filesystems,omitempty"`
+	// VolumeGroups specifies the list of LVM volume groups to setup, together with their logical volumes.
+	// Volume groups are created before file systems, so file systems can be created on the logical
+	// volumes, using /dev/<volume group>/<logical volume> as device.
+	// +optional
+	VolumeGroups []VolumeGroup `json:"volumeGroups,omitempty"`
+	// Swap specifies the swap file to setup.
+	// +optional
+	Swap *Swap `json:"swap,omitempty"`
 }
 
 // Partition defines how to create and layout a partition.
@@ -309,8 +354,9 @@ type Filesystem struct {
 	Device string `json:"device"`
 	// Filesystem specifies the file system type.
 	Filesystem string `json:"filesystem"`
-	// Label specifies the file system label to be used. If set to None, no label is used.
-	Label string `json:"label"`
+	// Label specifies the file system label to be used. If empty or set to None, no label is used.
+	// +optional
+	Label string `json:"label,omitempty"`
 	// Partition specifies the partition to use. The valid options are: "auto|any", "auto", "any", "none", and <NUM>, where NUM is the actual partition number.
 	// +optional
 	Partition *string `json:"partition,omitempty"`
@@ -327,5 +373,88 @@ type Filesystem struct {
 	ExtraOpts []string `json:"extraOpts,omitempty"`
 }
 
+// VolumeGroup defines a LVM volume group to be created.
+type VolumeGroup struct {
+	// Name specifies the name of the volume group.
+	Name string `json:"name"`
+	// PhysicalVolumes specifies the devices to be used as physical volumes of the volume group.
+	// The devices must exist before the disk setup, so partitions created by Partitions can not be used.
+	PhysicalVolumes []string `json:"physicalVolumes"`
+	// LogicalVolumes specifies the logical volumes to be created in the volume group.
+	// +optional
+	LogicalVolumes []LogicalVolume `json:"logicalVolumes,omitempty"`
+}
+
+// LogicalVolume defines a LVM logical volume to be created.
+type LogicalVolume struct {
+	// Name specifies the name of the logical volume.
+	Name string `json:"name"`
+	// Size specifies the size of the logical volume, either as an absolute size (e.g. 10G) or
+	// as a percentage of the volume group (e.g. 100%FREE), as accepted by lvcreate.
+	Size string `json:"size"`
+}
+
+// Swap defines the swap file to be created.
+type Swap struct {
+	// Filename specifies the path of the swap file. Default is '/swap.img'.
+	// +optional
+	Filename string `json:"filename,omitempty"`
+	// Size specifies the size of the swap file, e.g. 2G.
+	Size string `json:"size"`
+	// FailSwapOn defines whether the kubelet should fail to start when swap is enabled on the node.
+	// When a swap file is set up and this is not set to true, the kubelet is configured with
+	// fail-swap-on=false and the Swap kubeadm preflight check is ignored. Default is 'false'.
+	// +optional
+	FailSwapOn *bool `json:"failSwapOn,omitempty"`
+}
+
 // MountPoints defines input for generated mounts in cloud-init.
 type MountPoints []string
+
+// ContainerImages defines the configuration of the container images used by a machine.
+type ContainerImages struct {
+	// ImageRepositories overrides the image repository of single components; when not set, the image repository
+	// defined in the ClusterConfiguration is used. Please note that the image repositories defined for single
+	// components in the ClusterConfiguration take precedence.
+	// +optional
+	ImageRepositories *ComponentImageRepositories `json:"imageRepositories,omitempty"`
+
+	// PrePull specifies a list of container images to be pulled with crictl before running kubeadm, e.g. the images
+	// of the static pods defined in Files or of the add-ons installed by PostKubeadmCommands.
+	// +optional
+	PrePull []string `json:"prePull,omitempty"`
+
+	// RegistryMirrors specifies the mirrors to be used when pulling images from a registry. Mirrors are configured
+	// in a containerd hosts.toml file for each registry in /etc/containerd/certs.d, which requires containerd to be
+	// configured with config_path = "/etc/containerd/certs.d".
+	// +optional
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+
+	// RegistryCredentials references the key of a secret with the credentials to be used for pulling images,
+	// in the docker config.json format, e.g. the .dockerconfigjson key of a secret of type
+	// kubernetes.io/dockerconfigjson. The credentials are written to /var/lib/kubelet/config.json,
+	// where they are used by the kubelet.
+	// +optional
+	RegistryCredentials *SecretFileSource `json:"registryCredentials,omitempty"`
+}
+
+// ComponentImageRepositories defines the image repositories of single components.
+type ComponentImageRepositories struct {
+	// Etcd specifies the image repository of etcd.
+	// +optional
+	Etcd string `json:"etcd,omitempty"`
+
+	// CoreDNS specifies the image repository of CoreDNS.
+	// +optional
+	CoreDNS string `json:"coreDNS,omitempty"`
+}
+
+// RegistryMirror defines the mirrors of a container image registry.
+type RegistryMirror struct {
+	// Registry specifies the host of the registry to be mirrored, e.g. k8s.gcr.io or docker.io.
+	Registry string `json:"registry"`
+
+	// Endpoints specifies the mirror endpoints, e.g. https://registry.example.com:5000, which are tried in order
+	// before the registry itself.
+	Endpoints []string `json:"endpoints"`
+}
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 func TestClusterValidate(t *testing.T) {
@@ -54,7 +55,7 @@ func TestClusterValidate(t *testing.T) {
 					Files: []File{
 						{
 							ContentFrom: &FileSource{
-								Secret: SecretFileSource{
+								Secret: &SecretFileSource{
 									Name: "foo",
 									Key:  "bar",
 								},
@@ -91,7 +92,7 @@ func TestClusterValidate(t *testing.T) {
 					Files: []File{
 						{
 							ContentFrom: &FileSource{
-								Secret: SecretFileSource{
+								Secret: &SecretFileSource{
 									Key: "bar",
 								},
 							},
@@ -112,7 +113,7 @@ func TestClusterValidate(t *testing.T) {
 					Files: []File{
 						{
 							ContentFrom: &FileSource{
-								Secret: SecretFileSource{
+								Secret: &SecretFileSource{
 									Name: "foo",
 								},
 							},
@@ -123,6 +124,123 @@ func TestClusterValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid contentFrom config map": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							ContentFrom: &FileSource{
+								ConfigMap: &ConfigMapFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+							Append: true,
+						},
+					},
+				},
+			},
+		},
+		"invalid contentFrom with secret and config map": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							ContentFrom: &FileSource{
+								Secret: &SecretFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+								ConfigMap: &ConfigMapFileSource{
+									Name: "foo",
+									Key:  "bar",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid contentFrom config map without key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							ContentFrom: &FileSource{
+								ConfigMap: &ConfigMapFileSource{
+									Name: "foo",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"valid permissions and owner": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							Content:     "foo",
+							Permissions: "0640",
+							Owner:       "root:root",
+						},
+					},
+				},
+			},
+		},
+		"invalid permissions": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							Content:     "foo",
+							Permissions: "rw-r-----",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid owner": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Files: []File{
+						{
+							Content: "foo",
+							Owner:   "root:",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 		"invalid with duplicate file path": {
 			in: &KubeadmConfig{
 				ObjectMeta: metav1.ObjectMeta{
@@ -142,6 +260,262 @@ func TestClusterValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid ignition format": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Users: []User{
+						{
+							Name: "core",
+						},
+					},
+				},
+			},
+		},
+		"ignition format with unsupported fields": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format:                   Ignition,
+					UseExperimentalRetryJoin: true,
+				},
+			},
+			expectErr: true,
+		},
+		"ignition format with disk setup and mounts": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					DiskSetup: &DiskSetup{
+						Partitions: []Partition{
+							{Device: "/dev/sdb", Layout: true, TableType: pointer.StringPtr("gpt")},
+						},
+						Filesystems: []Filesystem{
+							{Device: "/dev/sdb1", Filesystem: "ext4", Label: "etcd_disk"},
+						},
+						VolumeGroups: []VolumeGroup{
+							{Name: "data", PhysicalVolumes: []string{"/dev/sdc"}, LogicalVolumes: []LogicalVolume{{Name: "containerd", Size: "100%FREE"}}},
+						},
+						Swap: &Swap{Size: "2G"},
+					},
+					Mounts: []MountPoints{
+						{"LABEL=etcd_disk", "/var/lib/etcd"},
+					},
+				},
+			},
+		},
+		"ignition format with mbr partition table": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					DiskSetup: &DiskSetup{
+						Partitions: []Partition{
+							{Device: "/dev/sdb", Layout: true, TableType: pointer.StringPtr("mbr")},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"ignition format with file system partition and replaceFS": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					DiskSetup: &DiskSetup{
+						Filesystems: []Filesystem{
+							{Device: "ephemeral0.1", Filesystem: "ext4", Partition: pointer.StringPtr("any"), ReplaceFS: pointer.StringPtr("ntfs")},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"ignition format with incomplete mount point": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Mounts: []MountPoints{
+						{"LABEL=etcd_disk"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"valid container images": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					ContainerImages: &ContainerImages{
+						ImageRepositories: &ComponentImageRepositories{
+							Etcd:    "registry.example.com/etcd",
+							CoreDNS: "registry.example.com/coredns",
+						},
+						PrePull: []string{"registry.example.com/calico/node:v3.20.0"},
+						RegistryMirrors: []RegistryMirror{
+							{
+								Registry:  "docker.io",
+								Endpoints: []string{"https://registry.example.com"},
+							},
+							{
+								Registry:  "k8s.gcr.io",
+								Endpoints: []string{"https://registry.example.com"},
+							},
+						},
+						RegistryCredentials: &SecretFileSource{
+							Name: "registry-credentials",
+							Key:  ".dockerconfigjson",
+						},
+					},
+				},
+			},
+		},
+		"container images with invalid pre pull image": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					ContainerImages: &ContainerImages{
+						PrePull: []string{"nginx; reboot"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"container images with duplicated registry mirrors": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					ContainerImages: &ContainerImages{
+						RegistryMirrors: []RegistryMirror{
+							{
+								Registry:  "docker.io",
+								Endpoints: []string{"https://registry.example.com"},
+							},
+							{
+								Registry:  "docker.io",
+								Endpoints: []string{"https://registry.example.org"},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"container images with registry mirror without endpoints": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					ContainerImages: &ContainerImages{
+						RegistryMirrors: []RegistryMirror{
+							{
+								Registry: "docker.io",
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"container images with registry credentials without key": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					ContainerImages: &ContainerImages{
+						RegistryCredentials: &SecretFileSource{
+							Name: "registry-credentials",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"valid script format": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Script,
+					Files: []File{
+						{
+							Path:    "/etc/foo",
+							Content: "bar",
+						},
+					},
+					PreKubeadmCommands: []string{"echo pre"},
+				},
+			},
+		},
+		"script format with users": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Script,
+					Users: []User{
+						{
+							Name: "core",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"script format with mounts": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Script,
+					Mounts: []MountPoints{
+						{"LABEL=etcd_disk", "/var/lib/etcddisk"},
+					},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for name, tt := range cases {
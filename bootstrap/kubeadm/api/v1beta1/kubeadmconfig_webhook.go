@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strconv"
+	"strings"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -28,7 +31,19 @@ var (
 	conflictingFileSourceMsg = "only one of content or contentFrom may be specified for a single file"
 	missingSecretNameMsg     = "secret file source must specify non-empty secret name"
 	missingSecretKeyMsg      = "secret file source must specify non-empty secret key"
+	missingConfigMapNameMsg  = "config map file source must specify non-empty config map name"
+	missingConfigMapKeyMsg   = "config map file source must specify non-empty config map key"
+	invalidFileSourceMsg     = "exactly one of secret or configMap must be specified"
+	invalidPermissionsMsg    = "permissions must be specified in octal notation, e.g. \"0640\""
+	invalidOwnerMsg          = "owner must be specified as \"user\" or \"user:group\""
 	pathConflictMsg          = "path property must be unique among all files"
+	ignitionUnsupportedMsg   = "not supported when using the ignition format"
+	scriptUnsupportedMsg     = "not supported when using the script format"
+	invalidMountMsg          = "mount point must specify at least a device and a mount path"
+	invalidImageMsg          = "image must be non-empty and must not contain whitespaces"
+	invalidRegistryMsg       = "registry must be specified as a host, optionally with a port, e.g. \"docker.io\""
+	registryConflictMsg      = "registry must be unique among all registry mirrors"
+	missingEndpointsMsg      = "registry mirror must specify at least one non-empty endpoint"
 )
 
 func (c *KubeadmConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -73,27 +88,29 @@ func (c *KubeadmConfigSpec) validate(name string) error {
 				),
 			)
 		}
-		// n.b.: if we ever add types besides Secret as a ContentFrom
-		// Source, we must add webhook validation here for one of the
-		// sources being non-nil.
 		if file.ContentFrom != nil {
-			if file.ContentFrom.Secret.Name == "" {
+			allErrs = append(allErrs, file.ContentFrom.validate(field.NewPath("spec", "files").Index(i).Child("contentFrom"), file)...)
+		}
+		if file.Permissions != "" {
+			if _, err := strconv.ParseUint(file.Permissions, 8, 32); err != nil {
 				allErrs = append(
 					allErrs,
 					field.Invalid(
-						field.NewPath("spec", "files").Index(i).Child("contentFrom", "secret", "name"),
-						file,
-						missingSecretNameMsg,
+						field.NewPath("spec", "files").Index(i).Child("permissions"),
+						file.Permissions,
+						invalidPermissionsMsg,
 					),
 				)
 			}
-			if file.ContentFrom.Secret.Key == "" {
+		}
+		if file.Owner != "" {
+			if owner := strings.Split(file.Owner, ":"); len(owner) > 2 || owner[0] == "" || (len(owner) == 2 && owner[1] == "") {
 				allErrs = append(
 					allErrs,
 					field.Invalid(
-						field.NewPath("spec", "files").Index(i).Child("contentFrom", "secret", "key"),
-						file,
-						missingSecretKeyMsg,
+						field.NewPath("spec", "files").Index(i).Child("owner"),
+						file.Owner,
+						invalidOwnerMsg,
 					),
 				)
 			}
@@ -112,8 +129,160 @@ func (c *KubeadmConfigSpec) validate(name string) error {
 		knownPaths[file.Path] = struct{}{}
 	}
 
+	allErrs = append(allErrs, c.validateContainerImages()...)
+	allErrs = append(allErrs, c.validateIgnition()...)
+	allErrs = append(allErrs, c.validateScript()...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("KubeadmConfig").GroupKind(), name, allErrs)
 }
+
+// validate validates that exactly one source is specified, with a non-empty name and key.
+func (s *FileSource) validate(path *field.Path, file File) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if (s.Secret == nil) == (s.ConfigMap == nil) {
+		return append(allErrs, field.Invalid(path, file, invalidFileSourceMsg))
+	}
+
+	if s.Secret != nil {
+		if s.Secret.Name == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("secret", "name"), file, missingSecretNameMsg))
+		}
+		if s.Secret.Key == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("secret", "key"), file, missingSecretKeyMsg))
+		}
+	}
+
+	if s.ConfigMap != nil {
+		if s.ConfigMap.Name == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("configMap", "name"), file, missingConfigMapNameMsg))
+		}
+		if s.ConfigMap.Key == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("configMap", "key"), file, missingConfigMapKeyMsg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateContainerImages validates the images to be pulled and the registry mirrors and credentials.
+func (c *KubeadmConfigSpec) validateContainerImages() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.ContainerImages == nil {
+		return allErrs
+	}
+
+	for i, image := range c.ContainerImages.PrePull {
+		if image == "" || strings.ContainsAny(image, " \t\n") {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "containerImages", "prePull").Index(i), image, invalidImageMsg))
+		}
+	}
+
+	knownRegistries := map[string]struct{}{}
+	for i, mirror := range c.ContainerImages.RegistryMirrors {
+		path := field.NewPath("spec", "containerImages", "registryMirrors").Index(i)
+		if mirror.Registry == "" || strings.ContainsAny(mirror.Registry, "/ \t\n") {
+			allErrs = append(allErrs, field.Invalid(path.Child("registry"), mirror.Registry, invalidRegistryMsg))
+		}
+		if _, conflict := knownRegistries[mirror.Registry]; conflict {
+			allErrs = append(allErrs, field.Invalid(path.Child("registry"), mirror.Registry, registryConflictMsg))
+		}
+		knownRegistries[mirror.Registry] = struct{}{}
+		if len(mirror.Endpoints) == 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("endpoints"), mirror.Endpoints, missingEndpointsMsg))
+		}
+		for j, endpoint := range mirror.Endpoints {
+			if endpoint == "" {
+				allErrs = append(allErrs, field.Invalid(path.Child("endpoints").Index(j), endpoint, missingEndpointsMsg))
+			}
+		}
+	}
+
+	if credentials := c.ContainerImages.RegistryCredentials; credentials != nil {
+		path := field.NewPath("spec", "containerImages", "registryCredentials")
+		if credentials.Name == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), credentials.Name, missingSecretNameMsg))
+		}
+		if credentials.Key == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("key"), credentials.Key, missingSecretKeyMsg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateIgnition validates that the KubeadmConfigSpec does not use features which can't be rendered
+// in the ignition format.
+func (c *KubeadmConfigSpec) validateIgnition() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.Format != Ignition {
+		return allErrs
+	}
+
+	if c.DiskSetup != nil {
+		for i, partition := range c.DiskSetup.Partitions {
+			if partition.TableType != nil && *partition.TableType != "gpt" {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "diskSetup", "partitions").Index(i).Child("tableType"), ignitionUnsupportedMsg))
+			}
+		}
+		for i, fs := range c.DiskSetup.Filesystems {
+			if fs.Partition != nil {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "diskSetup", "filesystems").Index(i).Child("partition"), ignitionUnsupportedMsg))
+			}
+			if fs.ReplaceFS != nil {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "diskSetup", "filesystems").Index(i).Child("replaceFS"), ignitionUnsupportedMsg))
+			}
+		}
+	}
+	for i, mount := range c.Mounts {
+		if len(mount) < 2 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "mounts").Index(i), mount, invalidMountMsg))
+		}
+		if len(mount) > 2 && mount[2] == "swap" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "mounts").Index(i), ignitionUnsupportedMsg))
+		}
+	}
+	if c.UseExperimentalRetryJoin {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "useExperimentalRetryJoin"), ignitionUnsupportedMsg))
+	}
+	for i, user := range c.Users {
+		if user.Inactive != nil && *user.Inactive {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "users").Index(i).Child("inactive"), ignitionUnsupportedMsg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateScript validates that the KubeadmConfigSpec does not use features which can't be rendered
+// in the script format.
+func (c *KubeadmConfigSpec) validateScript() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.Format != Script {
+		return allErrs
+	}
+
+	if c.UseExperimentalRetryJoin {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "useExperimentalRetryJoin"), scriptUnsupportedMsg))
+	}
+	if len(c.Users) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "users"), scriptUnsupportedMsg))
+	}
+	if c.NTP != nil && c.NTP.Enabled != nil && *c.NTP.Enabled {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "ntp"), scriptUnsupportedMsg))
+	}
+	if c.DiskSetup != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "diskSetup"), scriptUnsupportedMsg))
+	}
+	if len(c.Mounts) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "mounts"), scriptUnsupportedMsg))
+	}
+
+	return allErrs
+}
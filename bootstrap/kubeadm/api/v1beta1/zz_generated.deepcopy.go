@@ -210,6 +210,73 @@ func (in *ClusterStatus) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentImageRepositories) DeepCopyInto(out *ComponentImageRepositories) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentImageRepositories.
+func (in *ComponentImageRepositories) DeepCopy() *ComponentImageRepositories {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentImageRepositories)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapFileSource) DeepCopyInto(out *ConfigMapFileSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapFileSource.
+func (in *ConfigMapFileSource) DeepCopy() *ConfigMapFileSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapFileSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerImages) DeepCopyInto(out *ContainerImages) {
+	*out = *in
+	if in.ImageRepositories != nil {
+		in, out := &in.ImageRepositories, &out.ImageRepositories
+		*out = new(ComponentImageRepositories)
+		**out = **in
+	}
+	if in.PrePull != nil {
+		in, out := &in.PrePull, &out.PrePull
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RegistryCredentials != nil {
+		in, out := &in.RegistryCredentials, &out.RegistryCredentials
+		*out = new(SecretFileSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerImages.
+func (in *ContainerImages) DeepCopy() *ContainerImages {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneComponent) DeepCopyInto(out *ControlPlaneComponent) {
 	*out = *in
@@ -300,6 +367,18 @@ func (in *DiskSetup) DeepCopyInto(out *DiskSetup) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VolumeGroups != nil {
+		in, out := &in.VolumeGroups, &out.VolumeGroups
+		*out = make([]VolumeGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Swap != nil {
+		in, out := &in.Swap, &out.Swap
+		*out = new(Swap)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSetup.
@@ -363,7 +442,7 @@ func (in *File) DeepCopyInto(out *File) {
 	if in.ContentFrom != nil {
 		in, out := &in.ContentFrom, &out.ContentFrom
 		*out = new(FileSource)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -395,7 +474,16 @@ func (in *FileDiscovery) DeepCopy() *FileDiscovery {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileSource) DeepCopyInto(out *FileSource) {
 	*out = *in
-	out.Secret = in.Secret
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretFileSource)
+		**out = **in
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapFileSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileSource.
@@ -653,6 +741,11 @@ func (in *KubeadmConfigSpec) DeepCopyInto(out *KubeadmConfigSpec) {
 			}
 		}
 	}
+	if in.ContainerImages != nil {
+		in, out := &in.ContainerImages, &out.ContainerImages
+		*out = new(ContainerImages)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PreKubeadmCommands != nil {
 		in, out := &in.PreKubeadmCommands, &out.PreKubeadmCommands
 		*out = make([]string, len(*in))
@@ -861,6 +954,21 @@ func (in MountPoints) DeepCopy() MountPoints {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalVolume) DeepCopyInto(out *LogicalVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolume.
+func (in *LogicalVolume) DeepCopy() *LogicalVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NTP) DeepCopyInto(out *NTP) {
 	*out = *in
@@ -960,6 +1068,26 @@ func (in *Partition) DeepCopy() *Partition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretFileSource) DeepCopyInto(out *SecretFileSource) {
 	*out = *in
@@ -975,6 +1103,26 @@ func (in *SecretFileSource) DeepCopy() *SecretFileSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Swap) DeepCopyInto(out *Swap) {
+	*out = *in
+	if in.FailSwapOn != nil {
+		in, out := &in.FailSwapOn, &out.FailSwapOn
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Swap.
+func (in *Swap) DeepCopy() *Swap {
+	if in == nil {
+		return nil
+	}
+	out := new(Swap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -1039,3 +1187,28 @@ func (in *User) DeepCopy() *User {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeGroup) DeepCopyInto(out *VolumeGroup) {
+	*out = *in
+	if in.PhysicalVolumes != nil {
+		in, out := &in.PhysicalVolumes, &out.PhysicalVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogicalVolumes != nil {
+		in, out := &in.LogicalVolumes, &out.LogicalVolumes
+		*out = make([]LogicalVolume, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeGroup.
+func (in *VolumeGroup) DeepCopy() *VolumeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
@@ -34,8 +35,9 @@ import (
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
-	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/locking"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/cloudinit"
 	kubeadmtypes "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types"
 	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -44,6 +46,8 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
+	"sigs.k8s.io/cluster-api/util/nodemetadata"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -68,6 +72,8 @@ type InitLocker interface {
 // +kubebuilder:rbac:groups=bootstrap.cluster.x-k8s.io,resources=kubeadmconfigs;kubeadmconfigs/status;kubeadmconfigs/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status;machines;machines/status;machinepools;machinepools/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets;events;configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // KubeadmConfigReconciler reconciles a KubeadmConfig object.
 type KubeadmConfigReconciler struct {
@@ -158,7 +164,8 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if configOwner == nil {
 		return ctrl.Result{}, nil
 	}
-	log = log.WithValues("kind", configOwner.GetKind(), "version", configOwner.GetResourceVersion(), "name", configOwner.GetName())
+	ctx, log = logutil.IntoWithCluster(ctx, configOwner.GetNamespace(), configOwner.ClusterName(),
+		configOwner.GetKind(), logutil.KRef(configOwner.GetNamespace(), configOwner.GetName()), "resourceVersion", configOwner.GetResourceVersion())
 
 	// Lookup the cluster the config owner is associated with
 	cluster, err := util.GetClusterByName(ctx, r.Client, configOwner.GetNamespace(), configOwner.ClusterName())
@@ -235,7 +242,7 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			if !configOwner.IsInfrastructureReady() {
 				// If the BootstrapToken has been generated for a join and the infrastructure is not ready.
 				// This indicates the token in the join config has not been consumed and it may need a refresh.
-				return r.refreshBootstrapToken(ctx, config, cluster)
+				return r.refreshBootstrapToken(ctx, scope)
 			}
 			if configOwner.IsMachinePool() {
 				// If the BootstrapToken has been generated and infrastructure is ready but the configOwner is a MachinePool,
@@ -273,11 +280,11 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return r.joinWorker(ctx, scope)
 }
 
-func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, scope *Scope) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
-	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+	token := scope.Config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
 
-	remoteClient, err := r.remoteClientGetter(ctx, KubeadmConfigControllerName, r.Client, util.ObjectKey(cluster))
+	remoteClient, err := r.remoteClientGetter(ctx, KubeadmConfigControllerName, r.Client, util.ObjectKey(scope.Cluster))
 	if err != nil {
 		log.Error(err, "Error creating remote cluster client")
 		return ctrl.Result{}, err
@@ -285,13 +292,43 @@ func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, con
 
 	log.Info("Refreshing token until the infrastructure has a chance to consume it")
 	if err := refreshToken(ctx, remoteClient, token); err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to refresh bootstrap token")
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to refresh bootstrap token")
+		}
+		// The token expired and it has been deleted by the token cleaner before the infrastructure consumed it,
+		// e.g. because the controller was not running; issue a new token, so the machine can still join.
+		if res, err := r.reissueBootstrapToken(ctx, scope, remoteClient); err != nil || !res.IsZero() {
+			return res, err
+		}
 	}
 	return ctrl.Result{
 		RequeueAfter: DefaultTokenTTL / 2,
 	}, nil
 }
 
+// reissueBootstrapToken creates a new bootstrap token replacing an expired one, and updates the bootstrap data accordingly.
+func (r *KubeadmConfigReconciler) reissueBootstrapToken(ctx context.Context, scope *Scope, remoteClient client.Client) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// The bootstrap data can't be used to join the cluster until they are updated with the new token.
+	conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.BootstrapTokenExpiredReason, clusterv1.ConditionSeverityWarning, "Issuing a new bootstrap token")
+
+	log.Info("Bootstrap token expired before being consumed, creating a new bootstrap token")
+	token, err := createToken(ctx, remoteClient)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.BootstrapTokenExpiredReason, clusterv1.ConditionSeverityWarning, "Failed to create a new bootstrap token: %v", err)
+		return ctrl.Result{}, errors.Wrapf(err, "failed to create new bootstrap token")
+	}
+	scope.Config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token = token
+	log.Info("Altering JoinConfiguration.Discovery.BootstrapToken")
+
+	// update the bootstrap data
+	if scope.ConfigOwner.IsControlPlaneMachine() {
+		return r.joinControlplane(ctx, scope)
+	}
+	return r.joinWorker(ctx, scope)
+}
+
 func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Context, config *bootstrapv1.KubeadmConfig, cluster *clusterv1.Cluster, scope *Scope) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 	log.V(2).Info("Config is owned by a MachinePool, checking if token should be rotated")
@@ -303,7 +340,11 @@ func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Co
 	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
 	shouldRotate, err := shouldRotate(ctx, remoteClient, token)
 	if err != nil {
-		return ctrl.Result{}, err
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// The token expired and it has been deleted by the token cleaner, e.g. because the controller was not running.
+		shouldRotate = true
 	}
 	if shouldRotate {
 		log.V(2).Info("Creating new bootstrap token")
@@ -385,7 +426,10 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 			},
 		}
 	}
-	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(scope.Config.Spec.InitConfiguration, parsedVersion)
+	initConfiguration := scope.Config.Spec.InitConfiguration.DeepCopy()
+	allowSwap(&initConfiguration.NodeRegistration, scope.Config.Spec.DiskSetup)
+
+	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(initConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal init configuration")
 		return ctrl.Result{}, err
@@ -403,7 +447,9 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 	// injects into config.ClusterConfiguration values from top level object
 	r.reconcileTopLevelObjectSettings(ctx, scope.Cluster, machine, scope.Config)
 
-	clusterdata, err := kubeadmtypes.MarshalClusterConfigurationForVersion(scope.Config.Spec.ClusterConfiguration, parsedVersion)
+	clusterConfiguration := provisioning.ClusterConfigurationWithImageRepositories(scope.Config.Spec.ClusterConfiguration, scope.Config.Spec.ContainerImages)
+
+	clusterdata, err := kubeadmtypes.MarshalClusterConfigurationForVersion(clusterConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal cluster configuration")
 		return ctrl.Result{}, err
@@ -433,11 +479,11 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		return ctrl.Result{}, err
 	}
 
-	cloudInitData, err := cloudinit.NewInitControlPlane(&cloudinit.ControlPlaneInput{
+	controlPlaneInput := &cloudinit.ControlPlaneInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:     files,
 			NTP:                 scope.Config.Spec.NTP,
-			PreKubeadmCommands:  scope.Config.Spec.PreKubeadmCommands,
+			PreKubeadmCommands:  preKubeadmCommands(&scope.Config.Spec),
 			PostKubeadmCommands: scope.Config.Spec.PostKubeadmCommands,
 			Users:               scope.Config.Spec.Users,
 			Mounts:              scope.Config.Spec.Mounts,
@@ -447,7 +493,15 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		InitConfiguration:    initdata,
 		ClusterConfiguration: clusterdata,
 		Certificates:         certificates,
-	})
+	}
+
+	provisioner, err := provisioning.ForFormat(scope.Config.Spec.Format)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	cloudInitData, err := provisioner.NewInitControlPlane(controlPlaneInput)
 	if err != nil {
 		scope.Error(err, "Failed to generate cloud init for bootstrap control plane")
 		return ctrl.Result{}, err
@@ -491,7 +545,14 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", kubernetesVersion)
 	}
 
-	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(scope.Config.Spec.JoinConfiguration, parsedVersion)
+	// Add the labels and taints defined on the owner, if any, to the ones the Node should be registered with.
+	joinConfiguration, err := joinConfigurationWithNodeMetadata(scope.Config.Spec.JoinConfiguration, scope.ConfigOwner.GetAnnotations())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	allowSwap(&joinConfiguration.NodeRegistration, scope.Config.Spec.DiskSetup)
+
+	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
 		return ctrl.Result{}, err
@@ -514,11 +575,11 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		return ctrl.Result{}, err
 	}
 
-	cloudJoinData, err := cloudinit.NewNode(&cloudinit.NodeInput{
+	nodeInput := &cloudinit.NodeInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:      files,
 			NTP:                  scope.Config.Spec.NTP,
-			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
+			PreKubeadmCommands:   preKubeadmCommands(&scope.Config.Spec),
 			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
 			Users:                scope.Config.Spec.Users,
 			Mounts:               scope.Config.Spec.Mounts,
@@ -527,7 +588,15 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
 		JoinConfiguration: joinData,
-	})
+	}
+
+	provisioner, err := provisioning.ForFormat(scope.Config.Spec.Format)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	cloudJoinData, err := provisioner.NewNode(nodeInput)
 	if err != nil {
 		scope.Error(err, "Failed to create a worker join configuration")
 		return ctrl.Result{}, err
@@ -540,6 +609,79 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 	return ctrl.Result{}, nil
 }
 
+// joinConfigurationWithNodeMetadata returns a copy of the JoinConfiguration with the labels and taints defined by
+// the NodeLabelsAnnotation and the NodeTaintsAnnotation on the config owner added to the node registration options;
+// labels and taints defined in the JoinConfiguration take precedence.
+// NOTE: Labels and taints are applied when the Node registers, so workloads are not scheduled on the Node
+// before the Machine controller reconciles them.
+func joinConfigurationWithNodeMetadata(joinConfiguration *bootstrapv1.JoinConfiguration, ownerAnnotations map[string]string) (*bootstrapv1.JoinConfiguration, error) {
+	joinConfiguration = joinConfiguration.DeepCopy()
+
+	labels, err := nodemetadata.ParseLabels(ownerAnnotations[clusterv1.NodeLabelsAnnotation])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s annotation", clusterv1.NodeLabelsAnnotation)
+	}
+	if len(labels) > 0 {
+		existingLabels, err := nodemetadata.ParseLabels(joinConfiguration.NodeRegistration.KubeletExtraArgs["node-labels"])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse node-labels in JoinConfiguration.NodeRegistration.KubeletExtraArgs")
+		}
+		for k, v := range existingLabels {
+			labels[k] = v
+		}
+		if joinConfiguration.NodeRegistration.KubeletExtraArgs == nil {
+			joinConfiguration.NodeRegistration.KubeletExtraArgs = map[string]string{}
+		}
+		joinConfiguration.NodeRegistration.KubeletExtraArgs["node-labels"] = nodemetadata.FormatLabels(labels)
+	}
+
+	taints, err := nodemetadata.ParseTaints(ownerAnnotations[clusterv1.NodeTaintsAnnotation])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s annotation", clusterv1.NodeTaintsAnnotation)
+	}
+	if len(taints) > 0 {
+		joinConfiguration.NodeRegistration.Taints = nodemetadata.MergeTaints(joinConfiguration.NodeRegistration.Taints, taints)
+	}
+
+	return joinConfiguration, nil
+}
+
+// allowSwap configures the node registration options so the kubelet runs with swap enabled, if a swap file is
+// set up by the DiskSetup and FailSwapOn is not set to true; a fail-swap-on value already defined in the
+// KubeletExtraArgs takes precedence.
+func allowSwap(nodeRegistration *bootstrapv1.NodeRegistrationOptions, diskSetup *bootstrapv1.DiskSetup) {
+	if diskSetup == nil || diskSetup.Swap == nil || (diskSetup.Swap.FailSwapOn != nil && *diskSetup.Swap.FailSwapOn) {
+		return
+	}
+
+	if _, ok := nodeRegistration.KubeletExtraArgs["fail-swap-on"]; !ok {
+		if nodeRegistration.KubeletExtraArgs == nil {
+			nodeRegistration.KubeletExtraArgs = map[string]string{}
+		}
+		nodeRegistration.KubeletExtraArgs["fail-swap-on"] = "false"
+	}
+
+	for _, preflightError := range nodeRegistration.IgnorePreflightErrors {
+		if strings.EqualFold(preflightError, "Swap") || strings.EqualFold(preflightError, "all") {
+			return
+		}
+	}
+	nodeRegistration.IgnorePreflightErrors = append(nodeRegistration.IgnorePreflightErrors, "Swap")
+}
+
+// preKubeadmCommands returns the commands to be run before kubeadm, i.e. the PreKubeadmCommands followed by
+// the commands pulling the images defined in ContainerImages.
+func preKubeadmCommands(spec *bootstrapv1.KubeadmConfigSpec) []string {
+	prePullCommands := provisioning.PrePullCommands(spec.ContainerImages)
+	if len(prePullCommands) == 0 {
+		return spec.PreKubeadmCommands
+	}
+
+	commands := make([]string, 0, len(spec.PreKubeadmCommands)+len(prePullCommands))
+	commands = append(commands, spec.PreKubeadmCommands...)
+	return append(commands, prePullCommands...)
+}
+
 func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *Scope) (ctrl.Result, error) {
 	if !scope.ConfigOwner.IsControlPlaneMachine() {
 		return ctrl.Result{}, fmt.Errorf("%s is not a valid control plane kind, only Machine is supported", scope.ConfigOwner.GetKind())
@@ -578,7 +720,10 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", kubernetesVersion)
 	}
 
-	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(scope.Config.Spec.JoinConfiguration, parsedVersion)
+	joinConfiguration := scope.Config.Spec.JoinConfiguration.DeepCopy()
+	allowSwap(&joinConfiguration.NodeRegistration, scope.Config.Spec.DiskSetup)
+
+	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
 		return ctrl.Result{}, err
@@ -597,13 +742,13 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return ctrl.Result{}, err
 	}
 
-	cloudJoinData, err := cloudinit.NewJoinControlPlane(&cloudinit.ControlPlaneJoinInput{
+	controlPlaneJoinInput := &cloudinit.ControlPlaneJoinInput{
 		JoinConfiguration: joinData,
 		Certificates:      certificates,
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:      files,
 			NTP:                  scope.Config.Spec.NTP,
-			PreKubeadmCommands:   scope.Config.Spec.PreKubeadmCommands,
+			PreKubeadmCommands:   preKubeadmCommands(&scope.Config.Spec),
 			PostKubeadmCommands:  scope.Config.Spec.PostKubeadmCommands,
 			Users:                scope.Config.Spec.Users,
 			Mounts:               scope.Config.Spec.Mounts,
@@ -611,7 +756,15 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 			KubeadmVerbosity:     verbosityFlag,
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
-	})
+	}
+
+	provisioner, err := provisioning.ForFormat(scope.Config.Spec.Format)
+	if err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	cloudJoinData, err := provisioner.NewJoinControlPlane(controlPlaneJoinInput)
 	if err != nil {
 		scope.Error(err, "Failed to create a control plane join configuration")
 		return ctrl.Result{}, err
@@ -626,14 +779,23 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 }
 
 // resolveFiles maps .Spec.Files into cloudinit.Files, resolving any object references
-// along the way.
+// along the way. The files configuring the registry mirrors and credentials defined in
+// .Spec.ContainerImages are added before .Spec.Files, so they can be overridden.
 func (r *KubeadmConfigReconciler) resolveFiles(ctx context.Context, cfg *bootstrapv1.KubeadmConfig) ([]bootstrapv1.File, error) {
-	collected := make([]bootstrapv1.File, 0, len(cfg.Spec.Files))
+	files := provisioning.ContainerImagesFiles(cfg.Spec.ContainerImages)
+	files = append(files, cfg.Spec.Files...)
+	collected := make([]bootstrapv1.File, 0, len(files))
 
-	for i := range cfg.Spec.Files {
-		in := cfg.Spec.Files[i]
+	for i := range files {
+		in := files[i]
 		if in.ContentFrom != nil {
-			data, err := r.resolveSecretFileContent(ctx, cfg.Namespace, in)
+			var data []byte
+			var err error
+			if in.ContentFrom.ConfigMap != nil {
+				data, err = r.resolveConfigMapFileContent(ctx, cfg.Namespace, in)
+			} else {
+				data, err = r.resolveSecretFileContent(ctx, cfg.Namespace, in)
+			}
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to resolve file source")
 			}
@@ -663,6 +825,25 @@ func (r *KubeadmConfigReconciler) resolveSecretFileContent(ctx context.Context,
 	return data, nil
 }
 
+// resolveConfigMapFileContent returns file content fetched from a referenced config map object.
+func (r *KubeadmConfigReconciler) resolveConfigMapFileContent(ctx context.Context, ns string, source bootstrapv1.File) ([]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: ns, Name: source.ContentFrom.ConfigMap.Name}
+	if err := r.Client.Get(ctx, key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "config map not found: %s", key)
+		}
+		return nil, errors.Wrapf(err, "failed to retrieve ConfigMap %q", key)
+	}
+	if data, ok := configMap.Data[source.ContentFrom.ConfigMap.Key]; ok {
+		return []byte(data), nil
+	}
+	if data, ok := configMap.BinaryData[source.ContentFrom.ConfigMap.Key]; ok {
+		return data, nil
+	}
+	return nil, errors.Errorf("config map references non-existent config map key: %q", source.ContentFrom.ConfigMap.Key)
+}
+
 // ClusterToKubeadmConfigs is a handler.ToRequestsFunc to be used to enqeue
 // requests for reconciliation of KubeadmConfigs.
 func (r *KubeadmConfigReconciler) ClusterToKubeadmConfigs(o client.Object) []ctrl.Request {
@@ -860,6 +1041,11 @@ func (r *KubeadmConfigReconciler) reconcileTopLevelObjectSettings(ctx context.Co
 func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope *Scope, data []byte) error {
 	log := ctrl.LoggerFrom(ctx)
 
+	format := scope.Config.Spec.Format
+	if format == "" {
+		format = bootstrapv1.CloudConfig
+	}
+
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      scope.Config.Name,
@@ -878,7 +1064,8 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 			},
 		},
 		Data: map[string][]byte{
-			"value": data,
+			"value":  data,
+			"format": []byte(format),
 		},
 		Type: clusterv1.ClusterSecretType,
 	}
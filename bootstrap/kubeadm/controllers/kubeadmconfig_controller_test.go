@@ -399,6 +399,50 @@ func TestKubeadmConfigReconciler_Reconcile_GenerateCloudConfigData(t *testing.T)
 	g.Expect(err).NotTo(HaveOccurred())
 }
 
+func TestKubeadmConfigReconciler_Reconcile_GenerateIgnitionData(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster", metav1.NamespaceDefault)
+	cluster.Status.InfrastructureReady = true
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	controlPlaneInitConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-cfg")
+	controlPlaneInitConfig.Spec.Format = bootstrapv1.Ignition
+
+	objects := []client.Object{
+		cluster,
+		controlPlaneInitMachine,
+		controlPlaneInitConfig,
+	}
+	objects = append(objects, createSecrets(t, cluster, controlPlaneInitConfig)...)
+
+	myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+
+	k := &KubeadmConfigReconciler{
+		Client:          myclient,
+		KubeadmInitLock: &myInitLocker{},
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "control-plane-init-cfg",
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "control-plane-init-cfg", metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(s.Data["format"]).To(Equal([]byte(bootstrapv1.Ignition)))
+	g.Expect(string(s.Data["value"])).To(HavePrefix(`{"ignition":{"version":"3.1.0"}`))
+}
+
 // If a control plane has no JoinConfiguration, then we will create a default and no error will occur.
 func TestKubeadmConfigReconciler_Reconcile_ErrorIfJoiningControlPlaneHasInvalidConfiguration(t *testing.T) {
 	g := NewWithT(t)
@@ -863,6 +907,74 @@ func TestBootstrapTokenTTLExtension(t *testing.T) {
 	}
 }
 
+func TestBootstrapTokenReissueAfterExpiration(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster", metav1.NamespaceDefault)
+	cluster.Status.InfrastructureReady = true
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	initConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-config")
+	workerMachine := newWorkerMachine(cluster)
+	workerJoinConfig := newWorkerJoinKubeadmConfig(workerMachine)
+	objects := []client.Object{
+		cluster,
+		workerMachine,
+		workerJoinConfig,
+	}
+
+	objects = append(objects, createSecrets(t, cluster, initConfig)...)
+	myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "worker-join-cfg",
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, "worker-join-cfg", metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	expiredToken := cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+
+	// simulate the token cleaner deleting the token after its expiration.
+	l := &corev1.SecretList{}
+	g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+	g.Expect(l.Items).To(HaveLen(1))
+	g.Expect(myclient.Delete(ctx, &l.Items[0])).To(Succeed())
+
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(DefaultTokenTTL / 2))
+
+	cfg, err = getKubeadmConfig(myclient, "worker-join-cfg", metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(conditions.IsTrue(cfg, bootstrapv1.DataSecretAvailableCondition)).To(BeTrue())
+	newToken := cfg.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
+	g.Expect(newToken).NotTo(Equal(expiredToken))
+
+	l = &corev1.SecretList{}
+	g.Expect(myclient.List(ctx, l, client.InNamespace(metav1.NamespaceSystem))).To(Succeed())
+	g.Expect(l.Items).To(HaveLen(1))
+	g.Expect(newToken).To(HavePrefix(string(l.Items[0].Data[bootstrapapi.BootstrapTokenIDKey]) + "."))
+
+	// the bootstrap data secret must be updated with the new token.
+	dataSecret := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: *cfg.Status.DataSecretName}, dataSecret)).To(Succeed())
+	g.Expect(string(dataSecret.Data["value"])).To(ContainSubstring(newToken))
+	g.Expect(string(dataSecret.Data["value"])).NotTo(ContainSubstring(expiredToken))
+}
+
 func TestBootstrapTokenRotationMachinePool(t *testing.T) {
 	_ = feature.MutableGates.Set("MachinePool=true")
 	g := NewWithT(t)
@@ -1539,6 +1651,118 @@ func TestKubeadmConfigReconciler_Reconcile_PatchWhenErrorOccurred(t *testing.T)
 	g.Expect(cfg.Status.ObservedGeneration).NotTo(BeNil())
 }
 
+func TestJoinConfigurationWithNodeMetadata(t *testing.T) {
+	tests := []struct {
+		name              string
+		joinConfiguration *bootstrapv1.JoinConfiguration
+		annotations       map[string]string
+		want              *bootstrapv1.JoinConfiguration
+		wantErr           bool
+	}{
+		{
+			name:              "no annotations",
+			joinConfiguration: &bootstrapv1.JoinConfiguration{},
+			want:              &bootstrapv1.JoinConfiguration{},
+		},
+		{
+			name: "labels and taints are merged with the ones in the JoinConfiguration",
+			joinConfiguration: &bootstrapv1.JoinConfiguration{
+				NodeRegistration: bootstrapv1.NodeRegistrationOptions{
+					KubeletExtraArgs: map[string]string{"node-labels": "zone=a", "v": "2"},
+					Taints:           []corev1.Taint{{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			annotations: map[string]string{
+				clusterv1.NodeLabelsAnnotation: "role=worker,zone=b",
+				clusterv1.NodeTaintsAnnotation: "dedicated=gpu:NoSchedule,spot:PreferNoSchedule",
+			},
+			want: &bootstrapv1.JoinConfiguration{
+				NodeRegistration: bootstrapv1.NodeRegistrationOptions{
+					KubeletExtraArgs: map[string]string{"node-labels": "role=worker,zone=a", "v": "2"},
+					Taints: []corev1.Taint{
+						{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule},
+						{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+					},
+				},
+			},
+		},
+		{
+			name:              "invalid taints",
+			joinConfiguration: &bootstrapv1.JoinConfiguration{},
+			annotations: map[string]string{
+				clusterv1.NodeTaintsAnnotation: "dedicated=gpu",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			original := tt.joinConfiguration.DeepCopy()
+			got, err := joinConfigurationWithNodeMetadata(tt.joinConfiguration, tt.annotations)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+			// The JoinConfiguration in the KubeadmConfig must not be changed.
+			g.Expect(tt.joinConfiguration).To(Equal(original))
+		})
+	}
+}
+
+func TestAllowSwap(t *testing.T) {
+	tests := []struct {
+		name             string
+		nodeRegistration bootstrapv1.NodeRegistrationOptions
+		diskSetup        *bootstrapv1.DiskSetup
+		want             bootstrapv1.NodeRegistrationOptions
+	}{
+		{
+			name:      "no swap",
+			diskSetup: &bootstrapv1.DiskSetup{},
+			want:      bootstrapv1.NodeRegistrationOptions{},
+		},
+		{
+			name:      "swap",
+			diskSetup: &bootstrapv1.DiskSetup{Swap: &bootstrapv1.Swap{Size: "2G"}},
+			want: bootstrapv1.NodeRegistrationOptions{
+				KubeletExtraArgs:      map[string]string{"fail-swap-on": "false"},
+				IgnorePreflightErrors: []string{"Swap"},
+			},
+		},
+		{
+			name:      "swap with fail-swap-on",
+			diskSetup: &bootstrapv1.DiskSetup{Swap: &bootstrapv1.Swap{Size: "2G", FailSwapOn: pointer.BoolPtr(true)}},
+			want:      bootstrapv1.NodeRegistrationOptions{},
+		},
+		{
+			name: "swap with existing kubelet args and preflight errors",
+			nodeRegistration: bootstrapv1.NodeRegistrationOptions{
+				KubeletExtraArgs:      map[string]string{"fail-swap-on": "true", "v": "2"},
+				IgnorePreflightErrors: []string{"swap"},
+			},
+			diskSetup: &bootstrapv1.DiskSetup{Swap: &bootstrapv1.Swap{Size: "2G"}},
+			want: bootstrapv1.NodeRegistrationOptions{
+				KubeletExtraArgs:      map[string]string{"fail-swap-on": "true", "v": "2"},
+				IgnorePreflightErrors: []string{"swap"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			allowSwap(&tt.nodeRegistration, tt.diskSetup)
+			g.Expect(tt.nodeRegistration).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 	testSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1548,6 +1772,14 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			"key": []byte("foo"),
 		},
 	}
+	testConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "source",
+		},
+		Data: map[string]string{
+			"key": "baz",
+		},
+	}
 
 	cases := map[string]struct {
 		cfg     *bootstrapv1.KubeadmConfig
@@ -1582,7 +1814,7 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 					Files: []bootstrapv1.File{
 						{
 							ContentFrom: &bootstrapv1.FileSource{
-								Secret: bootstrapv1.SecretFileSource{
+								Secret: &bootstrapv1.SecretFileSource{
 									Name: "source",
 									Key:  "key",
 								},
@@ -1604,6 +1836,36 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			},
 			objects: []client.Object{testSecret},
 		},
+		"contentFrom config map should convert correctly": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					Files: []bootstrapv1.File{
+						{
+							ContentFrom: &bootstrapv1.FileSource{
+								ConfigMap: &bootstrapv1.ConfigMapFileSource{
+									Name: "source",
+									Key:  "key",
+								},
+							},
+							Path:        "/path",
+							Owner:       "root:root",
+							Permissions: "0600",
+							Append:      true,
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.File{
+				{
+					Content:     "baz",
+					Path:        "/path",
+					Owner:       "root:root",
+					Permissions: "0600",
+					Append:      true,
+				},
+			},
+			objects: []client.Object{testConfigMap},
+		},
 		"multiple files should work correctly": {
 			cfg: &bootstrapv1.KubeadmConfig{
 				Spec: bootstrapv1.KubeadmConfigSpec{
@@ -1616,7 +1878,7 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 						},
 						{
 							ContentFrom: &bootstrapv1.FileSource{
-								Secret: bootstrapv1.SecretFileSource{
+								Secret: &bootstrapv1.SecretFileSource{
 									Name: "source",
 									Key:  "key",
 								},
@@ -1644,6 +1906,41 @@ func TestKubeadmConfigReconciler_ResolveFiles(t *testing.T) {
 			},
 			objects: []client.Object{testSecret},
 		},
+		"registry credentials should be resolved before files": {
+			cfg: &bootstrapv1.KubeadmConfig{
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					ContainerImages: &bootstrapv1.ContainerImages{
+						RegistryCredentials: &bootstrapv1.SecretFileSource{
+							Name: "source",
+							Key:  "key",
+						},
+					},
+					Files: []bootstrapv1.File{
+						{
+							Content:     "bar",
+							Path:        "/bar",
+							Owner:       "root:root",
+							Permissions: "0600",
+						},
+					},
+				},
+			},
+			expect: []bootstrapv1.File{
+				{
+					Content:     "foo",
+					Path:        "/var/lib/kubelet/config.json",
+					Owner:       "root:root",
+					Permissions: "0600",
+				},
+				{
+					Content:     "bar",
+					Path:        "/bar",
+					Owner:       "root:root",
+					Permissions: "0600",
+				},
+			},
+			objects: []client.Object{testSecret},
+		},
 	}
 
 	for name, tc := range cases {
@@ -72,6 +72,13 @@ func (c *ControlPlaneInitMutex) Lock(ctx context.Context, cluster *clusterv1.Clu
 		if info.MachineName == machine.Name {
 			return true
 		}
+		// If the machine that created the lock does not exist anymore, e.g. because it was deleted by KCP remediation
+		// after failing to initialize the control plane, release the lock so another machine can acquire it.
+		if err := c.client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: info.MachineName}, &clusterv1.Machine{}); apierrors.IsNotFound(err) {
+			log.Info("The machine holding the lock does not exist anymore, releasing the lock", "init-machine", info.MachineName)
+			c.Unlock(ctx, cluster)
+			return false
+		}
 		log.Info("Waiting on another machine to initialize", "init-machine", info.MachineName)
 		return false
 	}
@@ -129,6 +129,49 @@ func TestControlPlaneInitMutex_Lock(t *testing.T) {
 		})
 	}
 }
+func TestControlPlaneInitMutex_LockReleasedIfMachineNotFound(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	info := information{MachineName: "deleted-machine"}
+	b, err := json.Marshal(info)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName(clusterName),
+			Namespace: clusterNamespace,
+		},
+		Data: map[string]string{semaphoreInformationKey: string(b)},
+	}).Build()
+
+	l := &ControlPlaneInitMutex{
+		log:    log.Log,
+		client: c,
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: clusterNamespace,
+			Name:      clusterName,
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("machine-%s", cluster.Name),
+			Namespace: clusterNamespace,
+		},
+	}
+
+	// The lock held by a machine that does not exist anymore is released, so it can be acquired at the next attempt.
+	g.Expect(l.Lock(ctx, cluster, machine)).To(BeFalse())
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: clusterNamespace, Name: configMapName(clusterName)}, &corev1.ConfigMap{})).ToNot(Succeed())
+	g.Expect(l.Lock(ctx, cluster, machine)).To(BeTrue())
+}
+
 func TestControlPlaneInitMutex_UnLock(t *testing.T) {
 	uid := types.UID("test-uid")
 	configMap := &corev1.ConfigMap{
@@ -41,6 +41,7 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/diagnostics"
 	"sigs.k8s.io/cluster-api/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -74,6 +75,7 @@ var (
 	watchFilterValue            string
 	watchNamespace              string
 	profilerAddress             string
+	diagnosticsOptions          diagnostics.Options
 	kubeadmConfigConcurrency    int
 	syncPeriod                  time.Duration
 	webhookPort                 int
@@ -103,6 +105,9 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&profilerAddress, "profiler-address", "",
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)")
+	_ = fs.MarkDeprecated("profiler-address", "use --diagnostics-address together with --diagnostics-enable-profiling instead")
+
+	diagnostics.AddFlags(fs, &diagnosticsOptions)
 
 	fs.IntVar(&kubeadmConfigConcurrency, "kubeadmconfig-concurrency", 10,
 		"Number of kubeadm configs to process simultaneously")
@@ -176,6 +181,7 @@ func main() {
 	setupChecks(mgr)
 	setupWebhooks(mgr)
 	setupReconcilers(ctx, mgr)
+	setupDiagnostics(mgr, nil)
 
 	// +kubebuilder:scaffold:builder
 	setupLog.Info("starting manager", "version", version.Get().String())
@@ -197,6 +203,22 @@ func setupChecks(mgr ctrl.Manager) {
 	}
 }
 
+func setupDiagnostics(mgr ctrl.Manager, tracker diagnostics.ClusterTracker) {
+	if diagnosticsOptions.BindAddress == "" {
+		return
+	}
+
+	server, err := diagnostics.NewServer(mgr, diagnosticsOptions, tracker)
+	if err != nil {
+		setupLog.Error(err, "unable to create diagnostics server")
+		os.Exit(1)
+	}
+	if err := mgr.Add(server); err != nil {
+		setupLog.Error(err, "unable to add diagnostics server")
+		os.Exit(1)
+	}
+}
+
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if err := (&kubeadmbootstrapcontrollers.KubeadmConfigReconciler{
 		Client:           mgr.GetClient(),
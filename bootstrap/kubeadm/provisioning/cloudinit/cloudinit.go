@@ -57,6 +57,35 @@ type BaseUserData struct {
 	SentinelFileCommand  string
 }
 
+// Provisioner renders the kubeadm bootstrap data in the cloud-config format.
+type Provisioner struct{}
+
+// Format returns the format of the bootstrap data rendered by the provisioner.
+func (Provisioner) Format() bootstrapv1.Format {
+	return bootstrapv1.CloudConfig
+}
+
+// Validate returns an error if the bootstrap data uses features that are not supported by the cloud-config format;
+// all the features of the KubeadmConfig are supported by cloud-init.
+func (Provisioner) Validate(_ *BaseUserData) error {
+	return nil
+}
+
+// NewInitControlPlane returns the cloud-config to be used on the first control plane instance.
+func (Provisioner) NewInitControlPlane(input *ControlPlaneInput) ([]byte, error) {
+	return NewInitControlPlane(input)
+}
+
+// NewJoinControlPlane returns the cloud-config to be used on a new control plane instance.
+func (Provisioner) NewJoinControlPlane(input *ControlPlaneJoinInput) ([]byte, error) {
+	return NewJoinControlPlane(input)
+}
+
+// NewNode returns the cloud-config to be used on a node instance.
+func (Provisioner) NewNode(input *NodeInput) ([]byte, error) {
+	return NewNode(input)
+}
+
 func (input *BaseUserData) prepare() error {
 	input.Header = cloudConfigHeader
 	input.WriteFiles = append(input.WriteFiles, input.AdditionalFiles...)
@@ -103,6 +132,14 @@ func generate(kind string, tpl string, data interface{}) ([]byte, error) {
 		return nil, errors.Wrap(err, "failed to parse mounts template")
 	}
 
+	if _, err := tm.Parse(lvmTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse lvm template")
+	}
+
+	if _, err := tm.Parse(swapTemplate); err != nil {
+		return nil, errors.Wrap(err, "failed to parse swap template")
+	}
+
 	t, err := tm.Parse(tpl)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse %s template", kind)
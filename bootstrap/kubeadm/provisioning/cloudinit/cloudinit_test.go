@@ -45,6 +45,13 @@ func TestNewInitControlPlaneAdditionalFileEncodings(t *testing.T) {
 					Path:    "/tmp/my-other-path",
 					Content: "hi",
 				},
+				{
+					Path:        "/tmp/my-appended-path",
+					Owner:       "root:root",
+					Permissions: "0644",
+					Content:     "hi",
+					Append:      true,
+				},
 			},
 			WriteFiles: nil,
 			Users:      nil,
@@ -71,6 +78,12 @@ func TestNewInitControlPlaneAdditionalFileEncodings(t *testing.T) {
     content: |
       aGk=`,
 		`-   path: /tmp/my-other-path
+    content: |
+      hi`,
+		`-   path: /tmp/my-appended-path
+    owner: root:root
+    permissions: '0644'
+    append: true
     content: |
       hi`,
 	}
@@ -143,6 +156,23 @@ func TestNewInitControlPlaneDiskMounts(t *testing.T) {
 						Label:      "test_disk",
 						ExtraOpts:  []string{"-F", "-E", "lazy_itable_init=1,lazy_journal_init=1"},
 					},
+					{
+						Device:     "/dev/test-vg/test-lv",
+						Filesystem: "xfs",
+					},
+				},
+				VolumeGroups: []bootstrapv1.VolumeGroup{
+					{
+						Name:            "test-vg",
+						PhysicalVolumes: []string{"/dev/sdc", "/dev/sdd"},
+						LogicalVolumes: []bootstrapv1.LogicalVolume{
+							{Name: "test-lv", Size: "10G"},
+							{Name: "test-other-lv", Size: "100%FREE"},
+						},
+					},
+				},
+				Swap: &bootstrapv1.Swap{
+					Size: "2G",
 				},
 			},
 			Mounts: []bootstrapv1.MountPoints{
@@ -169,14 +199,25 @@ func TestNewInitControlPlaneDiskMounts(t *testing.T) {
     extra_opts:
       - -F
       - -E
-      - lazy_itable_init=1,lazy_journal_init=1`
+      - lazy_itable_init=1,lazy_journal_init=1
+  - filesystem: xfs
+    device: /dev/test-vg/test-lv`
 	expectedMounts := `mounts:
   - - test_disk
     - /var/lib/testdir`
+	expectedLVM := `bootcmd:
+  - "vgs test-vg >/dev/null 2>&1 || vgcreate test-vg /dev/sdc /dev/sdd"
+  - "lvs test-vg/test-lv >/dev/null 2>&1 || lvcreate -y -n test-lv -L 10G test-vg"
+  - "lvs test-vg/test-other-lv >/dev/null 2>&1 || lvcreate -y -n test-other-lv -l 100%FREE test-vg"`
+	expectedSwap := `swap:
+  filename: /swap.img
+  size: 2G`
 
 	g.Expect(string(out)).To(ContainSubstring(expectedDiskSetup))
 	g.Expect(string(out)).To(ContainSubstring(expectedFSSetup))
 	g.Expect(string(out)).To(ContainSubstring(expectedMounts))
+	g.Expect(string(out)).To(ContainSubstring(expectedLVM))
+	g.Expect(string(out)).To(ContainSubstring(expectedSwap))
 }
 
 func TestNewJoinControlPlaneAdditionalFileEncodings(t *testing.T) {
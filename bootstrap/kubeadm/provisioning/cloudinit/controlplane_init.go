@@ -41,8 +41,10 @@ runcmd:
 {{- template "commands" .PostKubeadmCommands }}
 {{- template "ntp" .NTP }}
 {{- template "users" .Users }}
+{{- template "lvm" .DiskSetup}}
 {{- template "disk_setup" .DiskSetup}}
 {{- template "fs_setup" .DiskSetup}}
+{{- template "swap" .DiskSetup}}
 {{- template "mounts" .Mounts}}
 `
 )
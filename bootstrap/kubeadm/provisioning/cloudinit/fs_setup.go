@@ -20,8 +20,8 @@ const (
 	fsSetupTemplate = `{{ define "fs_setup" -}}
 {{- if . }}
 fs_setup:{{ range .Filesystems }}
-  - label: {{ .Label }}
-    filesystem: {{ .Filesystem }}
+  - {{ if .Label }}label: {{ .Label }}
+    {{ end }}filesystem: {{ .Filesystem }}
     device: {{ .Device }}
   {{- if .Partition }}
     partition: {{ .Partition }}
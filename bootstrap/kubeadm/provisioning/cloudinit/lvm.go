@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	"fmt"
+	"strings"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+const (
+	// NOTE: bootcmd runs before disk_setup and fs_setup, so file systems can be created on the logical volumes.
+	lvmTemplate = `{{ define "lvm" -}}
+{{- if . }}{{ with VolumeGroupCommands .VolumeGroups }}
+bootcmd:
+{{- template "commands" . }}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+`
+)
+
+// VolumeGroupCommands returns the commands creating the given LVM volume groups and their logical volumes.
+// The commands are idempotent, because they are run at every boot.
+func VolumeGroupCommands(volumeGroups []bootstrapv1.VolumeGroup) []string {
+	var commands []string
+	for _, vg := range volumeGroups {
+		commands = append(commands, fmt.Sprintf("vgs %s >/dev/null 2>&1 || vgcreate %s %s", vg.Name, vg.Name, strings.Join(vg.PhysicalVolumes, " ")))
+		for _, lv := range vg.LogicalVolumes {
+			// Sizes expressed as a percentage, e.g. 100%FREE, are passed in extents.
+			sizeFlag := "-L"
+			if strings.Contains(lv.Size, "%") {
+				sizeFlag = "-l"
+			}
+			commands = append(commands, fmt.Sprintf("lvs %s/%s >/dev/null 2>&1 || lvcreate -y -n %s %s %s %s", vg.Name, lv.Name, lv.Name, sizeFlag, lv.Size, vg.Name))
+		}
+	}
+	return commands
+}
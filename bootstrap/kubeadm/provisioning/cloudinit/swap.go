@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudinit
+
+import (
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+const (
+	defaultSwapFilename = "/swap.img"
+
+	swapTemplate = `{{ define "swap" -}}
+{{- if . }}{{ with .Swap }}
+swap:
+  filename: {{ SwapFilename . }}
+  size: {{ .Size }}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+`
+)
+
+// SwapFilename returns the path of the given swap file, defaulting to /swap.img.
+func SwapFilename(swap *bootstrapv1.Swap) string {
+	if swap.Filename == "" {
+		return defaultSwapFilename
+	}
+	return swap.Filename
+}
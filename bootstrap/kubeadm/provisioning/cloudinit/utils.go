@@ -23,7 +23,9 @@ import (
 
 var (
 	defaultTemplateFuncMap = template.FuncMap{
-		"Indent": templateYAMLIndent,
+		"Indent":              templateYAMLIndent,
+		"VolumeGroupCommands": VolumeGroupCommands,
+		"SwapFilename":        SwapFilename,
 	}
 )
 
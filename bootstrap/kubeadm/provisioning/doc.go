@@ -0,0 +1,24 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package provisioning defines the interface implemented by the renderers of the kubeadm bootstrap data.
+
+The bootstrap data can be rendered in different formats, i.e. cloud-config, Ignition or a plain shell
+script; each format is implemented in a sub package, which can be used by other bootstrap providers
+to share the validation and the rendering of the bootstrap data.
+*/
+package provisioning
@@ -14,6 +14,5 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package contract provides support for the ClusterReconciler to handle with providers objects
-// according to the Cluster API contract.
-package contract
+// Package ignition implements the rendering of the kubeadm bootstrap data in the Ignition format.
+package ignition
@@ -0,0 +1,574 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/cloudinit"
+)
+
+const (
+	// ignitionVersion is the version of the Ignition config spec, supported e.g. by Fedora CoreOS and Flatcar Container Linux.
+	ignitionVersion = "3.1.0"
+
+	// NOTE: Ignition writes files before /run is mounted, so the kubeadm configuration and the bootstrap script
+	// are written in /etc; the kubeadm configuration is moved away once kubeadm succeeds, so the bootstrap unit
+	// runs only once.
+	kubeadmConfigPath   = "/etc/kubeadm.yml"
+	kubeadmScriptPath   = "/etc/kubeadm.sh"
+	kubeadmUnitName     = "kubeadm.service"
+	initCommand         = "kubeadm init --config " + kubeadmConfigPath + " %s"
+	joinCommand         = "kubeadm join --config " + kubeadmConfigPath + " %s"
+	sentinelFileCommand = "mkdir -p /run/cluster-api && echo success > /run/cluster-api/bootstrap-success.complete"
+
+	kubeadmUnit = `[Unit]
+Description=kubeadm
+# Run only once. After a successful run, the kubeadm configuration is moved to /tmp.
+ConditionPathExists=` + kubeadmConfigPath + `
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=` + kubeadmScriptPath + `
+
+[Install]
+WantedBy=multi-user.target
+`
+
+	timesyncdConfigPath = "/etc/systemd/timesyncd.conf"
+	timesyncdUnitName   = "systemd-timesyncd.service"
+
+	// NOTE: Ignition creates partitions and file systems in the initramfs, before LVM is available, so volume groups,
+	// file systems on logical volumes and the swap file are set up by a script run at every boot, before the local
+	// file systems are mounted; the script is idempotent.
+	diskSetupScriptPath = "/etc/disk-setup.sh"
+	diskSetupUnitName   = "disk-setup.service"
+
+	diskSetupUnit = `[Unit]
+Description=Disk setup
+DefaultDependencies=no
+After=systemd-udev-settle.service systemd-remount-fs.service
+Wants=systemd-udev-settle.service
+Before=local-fs-pre.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=` + diskSetupScriptPath + `
+
+[Install]
+WantedBy=local-fs.target
+`
+
+	mountUnit = `[Unit]
+Description=Mount %[2]s
+
+[Mount]
+What=%[1]s
+Where=%[2]s
+Type=%[3]s
+Options=%[4]s
+
+[Install]
+WantedBy=local-fs.target
+`
+)
+
+// NewInitControlPlane returns the Ignition config to be used on the first control plane instance.
+func NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error) {
+	kubeadmConfig := fmt.Sprintf("---\n%s\n---\n%s", input.ClusterConfiguration, input.InitConfiguration)
+	return render(&input.BaseUserData, input.Certificates.AsFiles(), fmt.Sprintf(initCommand, input.KubeadmVerbosity), kubeadmConfig)
+}
+
+// NewJoinControlPlane returns the Ignition config to be used on a new control plane instance.
+func NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error) {
+	return render(&input.BaseUserData, input.Certificates.AsFiles(), fmt.Sprintf(joinCommand, input.KubeadmVerbosity), fmt.Sprintf("---\n%s", input.JoinConfiguration))
+}
+
+// NewNode returns the Ignition config to be used on a node instance.
+func NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	return render(&input.BaseUserData, nil, fmt.Sprintf(joinCommand, input.KubeadmVerbosity), fmt.Sprintf("---\n%s", input.JoinConfiguration))
+}
+
+// Provisioner renders the kubeadm bootstrap data in the Ignition format.
+type Provisioner struct{}
+
+// Format returns the format of the bootstrap data rendered by the provisioner.
+func (Provisioner) Format() bootstrapv1.Format {
+	return bootstrapv1.Ignition
+}
+
+// Validate returns an error if the bootstrap data uses features that are not supported by the ignition format.
+func (Provisioner) Validate(input *cloudinit.BaseUserData) error {
+	return Validate(input)
+}
+
+// NewInitControlPlane returns the Ignition config to be used on the first control plane instance.
+func (Provisioner) NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error) {
+	return NewInitControlPlane(input)
+}
+
+// NewJoinControlPlane returns the Ignition config to be used on a new control plane instance.
+func (Provisioner) NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error) {
+	return NewJoinControlPlane(input)
+}
+
+// NewNode returns the Ignition config to be used on a node instance.
+func (Provisioner) NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	return NewNode(input)
+}
+
+// config is the subset of the Ignition config spec v3.1.0 used for bootstrapping kubeadm.
+type config struct {
+	Ignition ignitionInfo `json:"ignition"`
+	Passwd   passwd       `json:"passwd"`
+	Storage  storage      `json:"storage"`
+	Systemd  systemd      `json:"systemd"`
+}
+
+type ignitionInfo struct {
+	Version string `json:"version"`
+}
+
+type passwd struct {
+	Users []passwdUser `json:"users,omitempty"`
+}
+
+type passwdUser struct {
+	Name              string   `json:"name"`
+	PasswordHash      *string  `json:"passwordHash,omitempty"`
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+	Gecos             string   `json:"gecos,omitempty"`
+	HomeDir           string   `json:"homeDir,omitempty"`
+	PrimaryGroup      string   `json:"primaryGroup,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	Shell             string   `json:"shell,omitempty"`
+}
+
+type storage struct {
+	Disks       []disk       `json:"disks,omitempty"`
+	Filesystems []filesystem `json:"filesystems,omitempty"`
+	Files       []file       `json:"files,omitempty"`
+}
+
+type disk struct {
+	Device     string      `json:"device"`
+	WipeTable  bool        `json:"wipeTable,omitempty"`
+	Partitions []partition `json:"partitions,omitempty"`
+}
+
+type partition struct {
+	Number int `json:"number"`
+}
+
+type filesystem struct {
+	Device         string   `json:"device"`
+	Format         string   `json:"format"`
+	Label          *string  `json:"label,omitempty"`
+	WipeFilesystem bool     `json:"wipeFilesystem,omitempty"`
+	Options        []string `json:"options,omitempty"`
+}
+
+type file struct {
+	Path      string         `json:"path"`
+	Mode      *int           `json:"mode,omitempty"`
+	User      *fileOwner     `json:"user,omitempty"`
+	Group     *fileOwner     `json:"group,omitempty"`
+	Overwrite bool           `json:"overwrite"`
+	Contents  *fileContents  `json:"contents,omitempty"`
+	Append    []fileContents `json:"append,omitempty"`
+}
+
+type fileOwner struct {
+	Name string `json:"name"`
+}
+
+type fileContents struct {
+	Source      string `json:"source"`
+	Compression string `json:"compression,omitempty"`
+}
+
+type systemd struct {
+	Units []unit `json:"units,omitempty"`
+}
+
+type unit struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled,omitempty"`
+	Contents string `json:"contents,omitempty"`
+}
+
+// Validate returns an error if the bootstrap data uses features that are not supported by the ignition format.
+func Validate(input *cloudinit.BaseUserData) error {
+	if input.UseExperimentalRetry {
+		return errors.New("experimental retry join is not supported by the ignition format")
+	}
+
+	for _, user := range input.Users {
+		if user.Inactive != nil && *user.Inactive {
+			return errors.Errorf("inactive users are not supported by the ignition format, user %q", user.Name)
+		}
+	}
+
+	if input.DiskSetup != nil {
+		for _, p := range input.DiskSetup.Partitions {
+			if p.TableType != nil && *p.TableType != "gpt" {
+				return errors.Errorf("partition table type %q is not supported by the ignition format, device %s", *p.TableType, p.Device)
+			}
+		}
+		for _, fs := range input.DiskSetup.Filesystems {
+			if fs.Partition != nil || fs.ReplaceFS != nil {
+				return errors.Errorf("partition and replaceFS are not supported by the ignition format, device %s", fs.Device)
+			}
+		}
+	}
+
+	for _, mount := range input.Mounts {
+		if len(mount) < 2 {
+			return errors.Errorf("mount point %v must specify at least a device and a mount path", mount)
+		}
+		if len(mount) > 2 && mount[2] == "swap" {
+			return errors.New("swap mount points are not supported by the ignition format, use diskSetup.swap instead")
+		}
+	}
+	return nil
+}
+
+// render generates an Ignition config writing the given files, the kubeadm configuration and a bootstrap script
+// running the kubeadm command, executed at boot by a systemd unit.
+func render(input *cloudinit.BaseUserData, files []bootstrapv1.File, kubeadmCommand, kubeadmConfig string) ([]byte, error) {
+	if err := Validate(input); err != nil {
+		return nil, err
+	}
+
+	ignitionConfig := &config{
+		Ignition: ignitionInfo{Version: ignitionVersion},
+	}
+
+	files = append(files, input.AdditionalFiles...)
+	files = append(files,
+		bootstrapv1.File{
+			Path:        kubeadmConfigPath,
+			Owner:       "root:root",
+			Permissions: "0640",
+			Content:     kubeadmConfig,
+		},
+		bootstrapv1.File{
+			Path:        kubeadmScriptPath,
+			Owner:       "root:root",
+			Permissions: "0700",
+			Content:     kubeadmScript(input.PreKubeadmCommands, kubeadmCommand, input.PostKubeadmCommands),
+		},
+	)
+	ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, unit{
+		Name:     kubeadmUnitName,
+		Enabled:  true,
+		Contents: kubeadmUnit,
+	})
+
+	for _, user := range input.Users {
+		ignitionConfig.Passwd.Users = append(ignitionConfig.Passwd.Users, toPasswdUser(user))
+		if user.Sudo != nil {
+			files = append(files, bootstrapv1.File{
+				Path:        fmt.Sprintf("/etc/sudoers.d/%s", user.Name),
+				Owner:       "root:root",
+				Permissions: "0440",
+				Content:     fmt.Sprintf("%s %s\n", user.Name, *user.Sudo),
+			})
+		}
+	}
+
+	if input.NTP != nil && input.NTP.Enabled != nil && *input.NTP.Enabled {
+		files = append(files, bootstrapv1.File{
+			Path:        timesyncdConfigPath,
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     fmt.Sprintf("[Time]\nNTP=%s\n", strings.Join(input.NTP.Servers, " ")),
+		})
+		ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, unit{
+			Name:    timesyncdUnitName,
+			Enabled: true,
+		})
+	}
+
+	if input.DiskSetup != nil {
+		if diskSetupScript := renderDiskSetup(ignitionConfig, input.DiskSetup); diskSetupScript != "" {
+			files = append(files, bootstrapv1.File{
+				Path:        diskSetupScriptPath,
+				Owner:       "root:root",
+				Permissions: "0700",
+				Content:     diskSetupScript,
+			})
+			ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, unit{
+				Name:     diskSetupUnitName,
+				Enabled:  true,
+				Contents: diskSetupUnit,
+			})
+		}
+	}
+
+	for _, mount := range input.Mounts {
+		ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, toMountUnit(mount))
+	}
+
+	for _, f := range files {
+		ignitionFile, err := toFile(f)
+		if err != nil {
+			return nil, err
+		}
+		ignitionConfig.Storage.Files = append(ignitionConfig.Storage.Files, ignitionFile)
+	}
+
+	out, err := json.Marshal(ignitionConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal ignition config")
+	}
+	return out, nil
+}
+
+// renderDiskSetup adds the partitions and the file systems of the disk setup to the Ignition config, and returns
+// the script setting up the volume groups, the file systems on logical volumes and the swap file, if any.
+func renderDiskSetup(ignitionConfig *config, diskSetup *bootstrapv1.DiskSetup) string {
+	for _, p := range diskSetup.Partitions {
+		// NOTE: As in cloud-init, when layout is false the device is not partitioned.
+		if !p.Layout {
+			continue
+		}
+		ignitionConfig.Storage.Disks = append(ignitionConfig.Storage.Disks, disk{
+			Device:     p.Device,
+			WipeTable:  p.Overwrite != nil && *p.Overwrite,
+			Partitions: []partition{{Number: 1}},
+		})
+	}
+
+	var commands []string
+	commands = append(commands, cloudinit.VolumeGroupCommands(diskSetup.VolumeGroups)...)
+	for _, vg := range diskSetup.VolumeGroups {
+		commands = append(commands, fmt.Sprintf("vgchange -ay %s", vg.Name))
+	}
+
+	for _, fs := range diskSetup.Filesystems {
+		label := fs.Label
+		if strings.EqualFold(label, "None") {
+			label = ""
+		}
+
+		if isLogicalVolume(fs.Device, diskSetup.VolumeGroups) {
+			// NOTE: The file system is created only if the logical volume is not formatted yet, because the
+			// script runs at every boot.
+			mkfs := []string{"mkfs." + fs.Filesystem}
+			if label != "" {
+				mkfs = append(mkfs, "-L", label)
+			}
+			mkfs = append(mkfs, fs.ExtraOpts...)
+			mkfs = append(mkfs, fs.Device)
+			commands = append(commands, fmt.Sprintf("blkid %s >/dev/null 2>&1 || %s", fs.Device, strings.Join(mkfs, " ")))
+			continue
+		}
+
+		out := filesystem{
+			Device:         fs.Device,
+			Format:         fs.Filesystem,
+			WipeFilesystem: fs.Overwrite != nil && *fs.Overwrite,
+			Options:        fs.ExtraOpts,
+		}
+		if label != "" {
+			out.Label = &label
+		}
+		ignitionConfig.Storage.Filesystems = append(ignitionConfig.Storage.Filesystems, out)
+	}
+
+	if diskSetup.Swap != nil {
+		filename := cloudinit.SwapFilename(diskSetup.Swap)
+		commands = append(commands,
+			fmt.Sprintf("[ -f %[1]s ] || { fallocate -l %[2]s %[1]s && chmod 0600 %[1]s && mkswap %[1]s; }", filename, diskSetup.Swap.Size),
+			fmt.Sprintf("swapon --show=NAME --noheadings | grep -qxF %[1]s || swapon %[1]s", filename),
+		)
+	}
+
+	if len(commands) == 0 {
+		return ""
+	}
+	return "#!/bin/bash\nset -e\n" + strings.Join(commands, "\n") + "\n"
+}
+
+// isLogicalVolume returns true if the device is a logical volume of one of the given volume groups.
+func isLogicalVolume(device string, volumeGroups []bootstrapv1.VolumeGroup) bool {
+	for _, vg := range volumeGroups {
+		if strings.HasPrefix(device, fmt.Sprintf("/dev/%s/", vg.Name)) || strings.HasPrefix(device, fmt.Sprintf("/dev/mapper/%s-", vg.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// toMountUnit converts a mount point, defined as in cloud-init as device, mount path and optionally file system
+// type and mount options, into a systemd mount unit.
+func toMountUnit(mount bootstrapv1.MountPoints) unit {
+	what := mount[0]
+	switch {
+	case strings.HasPrefix(what, "LABEL="):
+		what = "/dev/disk/by-label/" + strings.TrimPrefix(what, "LABEL=")
+	case strings.HasPrefix(what, "UUID="):
+		what = "/dev/disk/by-uuid/" + strings.TrimPrefix(what, "UUID=")
+	}
+	where := path.Clean(mount[1])
+
+	fsType := "auto"
+	if len(mount) > 2 && mount[2] != "" {
+		fsType = mount[2]
+	}
+	options := "defaults"
+	if len(mount) > 3 && mount[3] != "" {
+		options = mount[3]
+	}
+
+	return unit{
+		Name:     mountUnitName(where),
+		Enabled:  true,
+		Contents: fmt.Sprintf(mountUnit, what, where, fsType, options),
+	}
+}
+
+// mountUnitName returns the name of the systemd mount unit for the given path, which must be escaped
+// as by systemd-escape --path.
+func mountUnitName(where string) string {
+	where = strings.Trim(where, "/")
+	if where == "" {
+		return "-.mount"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(where); i++ {
+		c := where[i]
+		switch {
+		case c == '/':
+			b.WriteByte('-')
+		case c == '.' && i == 0:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		case c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9'):
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		}
+	}
+	return b.String() + ".mount"
+}
+
+// kubeadmScript returns the script running the kubeadm command, together with the pre and post kubeadm commands.
+func kubeadmScript(preKubeadmCommands []string, kubeadmCommand string, postKubeadmCommands []string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\nset -e\n")
+	for _, command := range preKubeadmCommands {
+		b.WriteString(command + "\n")
+	}
+	b.WriteString(kubeadmCommand + "\n")
+	b.WriteString(sentinelFileCommand + "\n")
+	b.WriteString(fmt.Sprintf("mv %s /tmp/\n", kubeadmConfigPath))
+	for _, command := range postKubeadmCommands {
+		b.WriteString(command + "\n")
+	}
+	return b.String()
+}
+
+// toPasswdUser converts a user into an Ignition user.
+func toPasswdUser(user bootstrapv1.User) passwdUser {
+	out := passwdUser{
+		Name:              user.Name,
+		SSHAuthorizedKeys: user.SSHAuthorizedKeys,
+	}
+	// NOTE: The password is locked unless lockPassword is explicitly set to false, as in cloud-init.
+	if user.Passwd != nil && user.LockPassword != nil && !*user.LockPassword {
+		out.PasswordHash = user.Passwd
+	}
+	if user.Gecos != nil {
+		out.Gecos = *user.Gecos
+	}
+	if user.HomeDir != nil {
+		out.HomeDir = *user.HomeDir
+	}
+	if user.PrimaryGroup != nil {
+		out.PrimaryGroup = *user.PrimaryGroup
+	}
+	if user.Groups != nil {
+		for _, group := range strings.Split(*user.Groups, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				out.Groups = append(out.Groups, group)
+			}
+		}
+	}
+	if user.Shell != nil {
+		out.Shell = *user.Shell
+	}
+	return out
+}
+
+// toFile converts a file into an Ignition file, with the content stored as a base64 data URL.
+func toFile(f bootstrapv1.File) (file, error) {
+	out := file{
+		Path: f.Path,
+	}
+
+	if f.Permissions != "" {
+		mode, err := strconv.ParseUint(f.Permissions, 8, 32)
+		if err != nil {
+			return file{}, errors.Wrapf(err, "failed to parse permissions %q of file %s", f.Permissions, f.Path)
+		}
+		m := int(mode)
+		out.Mode = &m
+	}
+
+	if f.Owner != "" {
+		owner := strings.SplitN(f.Owner, ":", 2)
+		out.User = &fileOwner{Name: owner[0]}
+		if len(owner) == 2 {
+			out.Group = &fileOwner{Name: owner[1]}
+		}
+	}
+
+	// Content already base64 encoded is used as is, after removing line breaks.
+	contents := fileContents{}
+	content := base64.StdEncoding.EncodeToString([]byte(f.Content))
+	switch f.Encoding {
+	case bootstrapv1.Base64:
+		content = strings.Join(strings.Fields(f.Content), "")
+	case bootstrapv1.Gzip:
+		contents.Compression = "gzip"
+	case bootstrapv1.GzipBase64:
+		content = strings.Join(strings.Fields(f.Content), "")
+		contents.Compression = "gzip"
+	}
+	contents.Source = "data:;base64," + content
+
+	// NOTE: Ignition does not allow to overwrite a file when appending to it.
+	if f.Append {
+		out.Append = []fileContents{contents}
+		return out, nil
+	}
+	out.Overwrite = true
+	out.Contents = &contents
+	return out, nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/cloudinit"
+)
+
+func TestNewInitControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.ControlPlaneInput{
+		BaseUserData: cloudinit.BaseUserData{
+			PreKubeadmCommands:  []string{"echo pre"},
+			PostKubeadmCommands: []string{"echo post"},
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:        "/tmp/my-path",
+					Owner:       "foo:bar",
+					Permissions: "0600",
+					Encoding:    bootstrapv1.Base64,
+					Content:     "aGk=",
+				},
+				{
+					Path:    "/tmp/my-other-path",
+					Content: "hi",
+					Append:  true,
+				},
+			},
+			Users: []bootstrapv1.User{
+				{
+					Name:              "foo",
+					Groups:            pointer.StringPtr("wheel, docker"),
+					Sudo:              pointer.StringPtr("ALL=(ALL) NOPASSWD:ALL"),
+					SSHAuthorizedKeys: []string{"ssh-rsa foo"},
+				},
+			},
+			NTP: &bootstrapv1.NTP{
+				Enabled: pointer.BoolPtr(true),
+				Servers: []string{"a.pool.ntp.org", "b.pool.ntp.org"},
+			},
+			KubeadmVerbosity: "--v=5",
+		},
+		ClusterConfiguration: "my-cluster-config",
+		InitConfiguration:    "my-init-config",
+	}
+
+	out, err := NewInitControlPlane(input)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ignitionConfig := &config{}
+	g.Expect(json.Unmarshal(out, ignitionConfig)).To(Succeed())
+	g.Expect(ignitionConfig.Ignition.Version).To(Equal(ignitionVersion))
+
+	files := decodeFiles(g, ignitionConfig)
+	g.Expect(files).To(HaveKeyWithValue("/tmp/my-path", "hi"))
+	g.Expect(files).To(HaveKeyWithValue("/tmp/my-other-path", "hi"))
+	g.Expect(files).To(HaveKeyWithValue(kubeadmConfigPath, "---\nmy-cluster-config\n---\nmy-init-config"))
+	g.Expect(files).To(HaveKeyWithValue("/etc/sudoers.d/foo", "foo ALL=(ALL) NOPASSWD:ALL\n"))
+	g.Expect(files).To(HaveKeyWithValue(timesyncdConfigPath, "[Time]\nNTP=a.pool.ntp.org b.pool.ntp.org\n"))
+	g.Expect(files).To(HaveKeyWithValue(kubeadmScriptPath, strings.Join([]string{
+		"#!/bin/bash",
+		"set -e",
+		"echo pre",
+		"kubeadm init --config /etc/kubeadm.yml --v=5",
+		sentinelFileCommand,
+		"mv /etc/kubeadm.yml /tmp/",
+		"echo post",
+		"",
+	}, "\n")))
+
+	for _, f := range ignitionConfig.Storage.Files {
+		switch f.Path {
+		case "/tmp/my-path":
+			g.Expect(*f.Mode).To(Equal(0600))
+			g.Expect(f.User.Name).To(Equal("foo"))
+			g.Expect(f.Group.Name).To(Equal("bar"))
+			g.Expect(f.Overwrite).To(BeTrue())
+		case "/tmp/my-other-path":
+			g.Expect(f.Contents).To(BeNil())
+			g.Expect(f.Append).To(HaveLen(1))
+			g.Expect(f.Overwrite).To(BeFalse())
+		}
+	}
+
+	g.Expect(ignitionConfig.Passwd.Users).To(ConsistOf(passwdUser{
+		Name:              "foo",
+		Groups:            []string{"wheel", "docker"},
+		SSHAuthorizedKeys: []string{"ssh-rsa foo"},
+	}))
+	g.Expect(ignitionConfig.Systemd.Units).To(ConsistOf(
+		unit{Name: kubeadmUnitName, Enabled: true, Contents: kubeadmUnit},
+		unit{Name: timesyncdUnitName, Enabled: true},
+	))
+}
+
+func TestNewNode(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.NodeInput{
+		JoinConfiguration: "my-join-config",
+	}
+
+	out, err := NewNode(input)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ignitionConfig := &config{}
+	g.Expect(json.Unmarshal(out, ignitionConfig)).To(Succeed())
+
+	files := decodeFiles(g, ignitionConfig)
+	g.Expect(files).To(HaveLen(2))
+	g.Expect(files).To(HaveKeyWithValue(kubeadmConfigPath, "---\nmy-join-config"))
+	g.Expect(files[kubeadmScriptPath]).To(ContainSubstring("kubeadm join --config /etc/kubeadm.yml"))
+}
+
+func TestDiskSetup(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			DiskSetup: &bootstrapv1.DiskSetup{
+				Partitions: []bootstrapv1.Partition{
+					{Device: "/dev/sdb", Layout: true, Overwrite: pointer.BoolPtr(true), TableType: pointer.StringPtr("gpt")},
+					{Device: "/dev/sdc", Layout: false},
+				},
+				Filesystems: []bootstrapv1.Filesystem{
+					{Device: "/dev/sdb1", Filesystem: "ext4", Label: "etcd_disk", ExtraOpts: []string{"-E", "lazy_itable_init=1"}},
+					{Device: "/dev/data/containerd", Filesystem: "xfs", Label: "containerd"},
+				},
+				VolumeGroups: []bootstrapv1.VolumeGroup{
+					{
+						Name:            "data",
+						PhysicalVolumes: []string{"/dev/sdd"},
+						LogicalVolumes:  []bootstrapv1.LogicalVolume{{Name: "containerd", Size: "100%FREE"}},
+					},
+				},
+				Swap: &bootstrapv1.Swap{Size: "2G"},
+			},
+			Mounts: []bootstrapv1.MountPoints{
+				{"LABEL=etcd_disk", "/var/lib/etcd"},
+				{"/dev/data/containerd", "/var/lib/containerd", "xfs", "defaults,noatime"},
+			},
+		},
+		JoinConfiguration: "my-join-config",
+	}
+
+	out, err := NewNode(input)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ignitionConfig := &config{}
+	g.Expect(json.Unmarshal(out, ignitionConfig)).To(Succeed())
+
+	g.Expect(ignitionConfig.Storage.Disks).To(ConsistOf(disk{
+		Device:     "/dev/sdb",
+		WipeTable:  true,
+		Partitions: []partition{{Number: 1}},
+	}))
+	g.Expect(ignitionConfig.Storage.Filesystems).To(ConsistOf(filesystem{
+		Device:  "/dev/sdb1",
+		Format:  "ext4",
+		Label:   pointer.StringPtr("etcd_disk"),
+		Options: []string{"-E", "lazy_itable_init=1"},
+	}))
+
+	files := decodeFiles(g, ignitionConfig)
+	g.Expect(files).To(HaveKeyWithValue(diskSetupScriptPath, strings.Join([]string{
+		"#!/bin/bash",
+		"set -e",
+		"vgs data >/dev/null 2>&1 || vgcreate data /dev/sdd",
+		"lvs data/containerd >/dev/null 2>&1 || lvcreate -y -n containerd -l 100%FREE data",
+		"vgchange -ay data",
+		"blkid /dev/data/containerd >/dev/null 2>&1 || mkfs.xfs -L containerd /dev/data/containerd",
+		"[ -f /swap.img ] || { fallocate -l 2G /swap.img && chmod 0600 /swap.img && mkswap /swap.img; }",
+		"swapon --show=NAME --noheadings | grep -qxF /swap.img || swapon /swap.img",
+		"",
+	}, "\n")))
+
+	g.Expect(ignitionConfig.Systemd.Units).To(ContainElements(
+		unit{Name: diskSetupUnitName, Enabled: true, Contents: diskSetupUnit},
+		unit{Name: "var-lib-etcd.mount", Enabled: true, Contents: fmt.Sprintf(mountUnit, "/dev/disk/by-label/etcd_disk", "/var/lib/etcd", "auto", "defaults")},
+		unit{Name: "var-lib-containerd.mount", Enabled: true, Contents: fmt.Sprintf(mountUnit, "/dev/data/containerd", "/var/lib/containerd", "xfs", "defaults,noatime")},
+	))
+}
+
+func TestMountUnitName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/", want: "-.mount"},
+		{path: "/var/lib/etcd", want: "var-lib-etcd.mount"},
+		{path: "/var/lib/etcd/", want: "var-lib-etcd.mount"},
+		{path: "/mnt/my-disk", want: "mnt-my\\x2ddisk.mount"},
+		{path: "/.hidden/data_1", want: "\\x2ehidden-data_1.mount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(mountUnitName(tt.path)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestUnsupportedFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		input cloudinit.BaseUserData
+	}{
+		{
+			name: "mbr partition table",
+			input: cloudinit.BaseUserData{
+				DiskSetup: &bootstrapv1.DiskSetup{
+					Partitions: []bootstrapv1.Partition{{Device: "/dev/sdb", Layout: true, TableType: pointer.StringPtr("mbr")}},
+				},
+			},
+		},
+		{
+			name: "file system partition",
+			input: cloudinit.BaseUserData{
+				DiskSetup: &bootstrapv1.DiskSetup{
+					Filesystems: []bootstrapv1.Filesystem{{Device: "/dev/sdb", Filesystem: "ext4", Partition: pointer.StringPtr("auto")}},
+				},
+			},
+		},
+		{
+			name: "incomplete mount point",
+			input: cloudinit.BaseUserData{
+				Mounts: []bootstrapv1.MountPoints{{"/dev/sda1"}},
+			},
+		},
+		{
+			name: "swap mount point",
+			input: cloudinit.BaseUserData{
+				Mounts: []bootstrapv1.MountPoints{{"/dev/sda2", "none", "swap"}},
+			},
+		},
+		{
+			name: "experimental retry join",
+			input: cloudinit.BaseUserData{
+				UseExperimentalRetry: true,
+			},
+		},
+		{
+			name: "inactive user",
+			input: cloudinit.BaseUserData{
+				Users: []bootstrapv1.User{{Name: "foo", Inactive: pointer.BoolPtr(true)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(Validate(&tt.input)).NotTo(Succeed())
+			_, err := NewNode(&cloudinit.NodeInput{BaseUserData: tt.input})
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func decodeFiles(g *WithT, ignitionConfig *config) map[string]string {
+	files := map[string]string{}
+	for _, f := range ignitionConfig.Storage.Files {
+		contents := f.Contents
+		if contents == nil {
+			g.Expect(f.Append).To(HaveLen(1))
+			contents = &f.Append[0]
+		}
+		g.Expect(contents.Source).To(HavePrefix("data:;base64,"))
+		content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(contents.Source, "data:;base64,"))
+		g.Expect(err).NotTo(HaveOccurred())
+		files[f.Path] = string(content)
+	}
+	return files
+}
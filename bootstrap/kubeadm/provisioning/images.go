@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+const (
+	// containerdCertsPath is the directory containerd reads the registry hosts configuration from, when
+	// configured with config_path.
+	containerdCertsPath = "/etc/containerd/certs.d"
+
+	// kubeletCredentialsPath is the docker config.json file the kubelet reads the registry credentials from.
+	kubeletCredentialsPath = "/var/lib/kubelet/config.json"
+
+	// dockerHubRegistry and dockerHubServer are respectively the name and the host of Docker Hub.
+	dockerHubRegistry = "docker.io"
+	dockerHubServer   = "registry-1.docker.io"
+)
+
+// ClusterConfigurationWithImageRepositories returns a copy of the ClusterConfiguration with the image repositories
+// of etcd and CoreDNS defined in ContainerImages, unless already defined in the ClusterConfiguration.
+func ClusterConfigurationWithImageRepositories(clusterConfiguration *bootstrapv1.ClusterConfiguration, containerImages *bootstrapv1.ContainerImages) *bootstrapv1.ClusterConfiguration {
+	out := clusterConfiguration.DeepCopy()
+	if out == nil || containerImages == nil || containerImages.ImageRepositories == nil {
+		return out
+	}
+
+	imageRepositories := containerImages.ImageRepositories
+	if imageRepositories.Etcd != "" && out.Etcd.External == nil {
+		if out.Etcd.Local == nil {
+			out.Etcd.Local = &bootstrapv1.LocalEtcd{}
+		}
+		if out.Etcd.Local.ImageRepository == "" {
+			out.Etcd.Local.ImageRepository = imageRepositories.Etcd
+		}
+	}
+	if imageRepositories.CoreDNS != "" && out.DNS.ImageRepository == "" {
+		out.DNS.ImageRepository = imageRepositories.CoreDNS
+	}
+	return out
+}
+
+// ContainerImagesFiles returns the files configuring the registry mirrors and the registry credentials defined
+// in ContainerImages; the content of the registry credentials file must be resolved from its secret.
+func ContainerImagesFiles(containerImages *bootstrapv1.ContainerImages) []bootstrapv1.File {
+	if containerImages == nil {
+		return nil
+	}
+
+	var files []bootstrapv1.File
+	for _, mirror := range containerImages.RegistryMirrors {
+		files = append(files, bootstrapv1.File{
+			Path:        path.Join(containerdCertsPath, mirror.Registry, "hosts.toml"),
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     registryHosts(mirror),
+		})
+	}
+
+	if containerImages.RegistryCredentials != nil {
+		files = append(files, bootstrapv1.File{
+			Path:        kubeletCredentialsPath,
+			Owner:       "root:root",
+			Permissions: "0600",
+			ContentFrom: &bootstrapv1.FileSource{
+				Secret: containerImages.RegistryCredentials.DeepCopy(),
+			},
+		})
+	}
+	return files
+}
+
+// PrePullCommands returns the commands pulling the images defined in ContainerImages, to be run before kubeadm.
+func PrePullCommands(containerImages *bootstrapv1.ContainerImages) []string {
+	if containerImages == nil {
+		return nil
+	}
+
+	commands := make([]string, 0, len(containerImages.PrePull))
+	for _, image := range containerImages.PrePull {
+		commands = append(commands, fmt.Sprintf("crictl pull %s", image))
+	}
+	return commands
+}
+
+// registryHosts returns the containerd hosts.toml file configuring the mirrors of a registry.
+func registryHosts(mirror bootstrapv1.RegistryMirror) string {
+	server := mirror.Registry
+	if server == dockerHubRegistry {
+		server = dockerHubServer
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "server = %q\n", "https://"+server)
+	for _, endpoint := range mirror.Endpoints {
+		fmt.Fprintf(&b, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", endpoint)
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+func TestClusterConfigurationWithImageRepositories(t *testing.T) {
+	g := NewWithT(t)
+
+	containerImages := &bootstrapv1.ContainerImages{
+		ImageRepositories: &bootstrapv1.ComponentImageRepositories{
+			Etcd:    "registry.example.com/etcd",
+			CoreDNS: "registry.example.com/coredns",
+		},
+	}
+
+	clusterConfiguration := &bootstrapv1.ClusterConfiguration{}
+	out := ClusterConfigurationWithImageRepositories(clusterConfiguration, containerImages)
+	g.Expect(out.Etcd.Local.ImageRepository).To(Equal("registry.example.com/etcd"))
+	g.Expect(out.DNS.ImageRepository).To(Equal("registry.example.com/coredns"))
+	g.Expect(clusterConfiguration.Etcd.Local).To(BeNil())
+
+	clusterConfiguration = &bootstrapv1.ClusterConfiguration{
+		Etcd: bootstrapv1.Etcd{
+			External: &bootstrapv1.ExternalEtcd{Endpoints: []string{"https://etcd:2379"}},
+		},
+		DNS: bootstrapv1.DNS{
+			ImageMeta: bootstrapv1.ImageMeta{ImageRepository: "registry.example.org/coredns"},
+		},
+	}
+	out = ClusterConfigurationWithImageRepositories(clusterConfiguration, containerImages)
+	g.Expect(out.Etcd.Local).To(BeNil())
+	g.Expect(out.DNS.ImageRepository).To(Equal("registry.example.org/coredns"))
+
+	g.Expect(ClusterConfigurationWithImageRepositories(nil, containerImages)).To(BeNil())
+}
+
+func TestContainerImagesFiles(t *testing.T) {
+	g := NewWithT(t)
+
+	files := ContainerImagesFiles(&bootstrapv1.ContainerImages{
+		RegistryMirrors: []bootstrapv1.RegistryMirror{
+			{
+				Registry:  "docker.io",
+				Endpoints: []string{"https://registry.example.com", "https://registry.example.org:5000"},
+			},
+			{
+				Registry:  "k8s.gcr.io",
+				Endpoints: []string{"https://registry.example.com"},
+			},
+		},
+		RegistryCredentials: &bootstrapv1.SecretFileSource{
+			Name: "registry-credentials",
+			Key:  ".dockerconfigjson",
+		},
+	})
+	g.Expect(files).To(Equal([]bootstrapv1.File{
+		{
+			Path:        "/etc/containerd/certs.d/docker.io/hosts.toml",
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content: `server = "https://registry-1.docker.io"
+
+[host."https://registry.example.com"]
+  capabilities = ["pull", "resolve"]
+
+[host."https://registry.example.org:5000"]
+  capabilities = ["pull", "resolve"]
+`,
+		},
+		{
+			Path:        "/etc/containerd/certs.d/k8s.gcr.io/hosts.toml",
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content: `server = "https://k8s.gcr.io"
+
+[host."https://registry.example.com"]
+  capabilities = ["pull", "resolve"]
+`,
+		},
+		{
+			Path:        "/var/lib/kubelet/config.json",
+			Owner:       "root:root",
+			Permissions: "0600",
+			ContentFrom: &bootstrapv1.FileSource{
+				Secret: &bootstrapv1.SecretFileSource{
+					Name: "registry-credentials",
+					Key:  ".dockerconfigjson",
+				},
+			},
+		},
+	}))
+
+	g.Expect(ContainerImagesFiles(nil)).To(BeEmpty())
+}
+
+func TestPrePullCommands(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(PrePullCommands(&bootstrapv1.ContainerImages{
+		PrePull: []string{"registry.example.com/calico/node:v3.20.0", "registry.example.com/calico/cni:v3.20.0"},
+	})).To(Equal([]string{
+		"crictl pull registry.example.com/calico/node:v3.20.0",
+		"crictl pull registry.example.com/calico/cni:v3.20.0",
+	}))
+	g.Expect(PrePullCommands(nil)).To(BeEmpty())
+}
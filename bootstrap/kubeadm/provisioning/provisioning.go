@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"github.com/pkg/errors"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/cloudinit"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/ignition"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/script"
+)
+
+// BootstrapProvisioner renders the bootstrap data running kubeadm on a machine in a given format.
+type BootstrapProvisioner interface {
+	// Format returns the format of the bootstrap data rendered by the provisioner.
+	Format() bootstrapv1.Format
+
+	// Validate returns an error if the bootstrap data uses features that are not supported by the format.
+	Validate(input *cloudinit.BaseUserData) error
+
+	// NewInitControlPlane returns the bootstrap data to be used on the first control plane instance.
+	NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error)
+
+	// NewJoinControlPlane returns the bootstrap data to be used on a new control plane instance.
+	NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error)
+
+	// NewNode returns the bootstrap data to be used on a node instance.
+	NewNode(input *cloudinit.NodeInput) ([]byte, error)
+}
+
+var (
+	_ BootstrapProvisioner = cloudinit.Provisioner{}
+	_ BootstrapProvisioner = ignition.Provisioner{}
+	_ BootstrapProvisioner = script.Provisioner{}
+)
+
+// ForFormat returns the BootstrapProvisioner rendering the bootstrap data in the given format;
+// if the format is not set, the bootstrap data is rendered in cloud-config format.
+func ForFormat(format bootstrapv1.Format) (BootstrapProvisioner, error) {
+	switch format {
+	case bootstrapv1.CloudConfig, "":
+		return cloudinit.Provisioner{}, nil
+	case bootstrapv1.Ignition:
+		return ignition.Provisioner{}, nil
+	case bootstrapv1.Script:
+		return script.Provisioner{}, nil
+	default:
+		return nil, errors.Errorf("unsupported bootstrap data format %q", format)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+)
+
+func TestForFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  bootstrapv1.Format
+		want    bootstrapv1.Format
+		wantErr bool
+	}{
+		{
+			name:   "defaults to cloud-config",
+			format: "",
+			want:   bootstrapv1.CloudConfig,
+		},
+		{
+			name:   "cloud-config",
+			format: bootstrapv1.CloudConfig,
+			want:   bootstrapv1.CloudConfig,
+		},
+		{
+			name:   "ignition",
+			format: bootstrapv1.Ignition,
+			want:   bootstrapv1.Ignition,
+		},
+		{
+			name:   "script",
+			format: bootstrapv1.Script,
+			want:   bootstrapv1.Script,
+		},
+		{
+			name:    "unknown format",
+			format:  "foo",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provisioner, err := ForFormat(tt.format)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(provisioner.Format()).To(Equal(tt.want))
+		})
+	}
+}
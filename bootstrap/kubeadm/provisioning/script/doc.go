@@ -0,0 +1,24 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package script implements the rendering of the kubeadm bootstrap data as a plain shell script.
+
+The script writes the files required by kubeadm, runs the kubeadm command together with the pre and post kubeadm
+commands, and it is intended for machines without cloud-init or Ignition; as a consequence, the features of the
+KubeadmConfig implemented by those tools, e.g. users, NTP, disk setup and mounts, are not supported.
+*/
+package script
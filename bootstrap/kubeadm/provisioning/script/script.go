@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package script
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/cloudinit"
+)
+
+const (
+	scriptHeader = "#!/bin/bash\nset -e\n"
+
+	kubeadmInitConfigPath = "/run/kubeadm/kubeadm.yaml"
+	kubeadmJoinConfigPath = "/run/kubeadm/kubeadm-join-config.yaml"
+	initCommand           = "kubeadm init --config " + kubeadmInitConfigPath + " %s"
+	joinCommand           = "kubeadm join --config " + kubeadmJoinConfigPath + " %s"
+	sentinelFileCommand   = "mkdir -p /run/cluster-api && echo success > /run/cluster-api/bootstrap-success.complete"
+
+	// heredocDelimiter delimits the base64 encoded content of the files; it can't collide with the content,
+	// given that it is not a valid base64 string.
+	heredocDelimiter = "CLUSTER_API_EOF"
+
+	// base64LineLength is the length of the lines of the base64 encoded content of the files.
+	base64LineLength = 76
+)
+
+// Provisioner renders the kubeadm bootstrap data as a shell script.
+type Provisioner struct{}
+
+// Format returns the format of the bootstrap data rendered by the provisioner.
+func (Provisioner) Format() bootstrapv1.Format {
+	return bootstrapv1.Script
+}
+
+// Validate returns an error if the bootstrap data uses features that are not supported by the script format.
+func (Provisioner) Validate(input *cloudinit.BaseUserData) error {
+	return Validate(input)
+}
+
+// NewInitControlPlane returns the script to be used on the first control plane instance.
+func (Provisioner) NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error) {
+	return NewInitControlPlane(input)
+}
+
+// NewJoinControlPlane returns the script to be used on a new control plane instance.
+func (Provisioner) NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error) {
+	return NewJoinControlPlane(input)
+}
+
+// NewNode returns the script to be used on a node instance.
+func (Provisioner) NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	return NewNode(input)
+}
+
+// NewInitControlPlane returns the script to be used on the first control plane instance.
+func NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error) {
+	kubeadmConfig := fmt.Sprintf("---\n%s\n---\n%s", input.ClusterConfiguration, input.InitConfiguration)
+	return render(&input.BaseUserData, input.Certificates.AsFiles(), fmt.Sprintf(initCommand, input.KubeadmVerbosity), kubeadmInitConfigPath, kubeadmConfig)
+}
+
+// NewJoinControlPlane returns the script to be used on a new control plane instance.
+func NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error) {
+	return render(&input.BaseUserData, input.Certificates.AsFiles(), fmt.Sprintf(joinCommand, input.KubeadmVerbosity), kubeadmJoinConfigPath, fmt.Sprintf("---\n%s", input.JoinConfiguration))
+}
+
+// NewNode returns the script to be used on a node instance.
+func NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	return render(&input.BaseUserData, nil, fmt.Sprintf(joinCommand, input.KubeadmVerbosity), kubeadmJoinConfigPath, fmt.Sprintf("---\n%s", input.JoinConfiguration))
+}
+
+// Validate returns an error if the bootstrap data uses features that are not supported by the script format.
+func Validate(input *cloudinit.BaseUserData) error {
+	if input.UseExperimentalRetry {
+		return errors.New("experimental retry join is not supported by the script format")
+	}
+	if len(input.Users) > 0 {
+		return errors.New("users are not supported by the script format")
+	}
+	if input.NTP != nil && input.NTP.Enabled != nil && *input.NTP.Enabled {
+		return errors.New("ntp is not supported by the script format")
+	}
+	if input.DiskSetup != nil {
+		return errors.New("disk setup is not supported by the script format")
+	}
+	if len(input.Mounts) > 0 {
+		return errors.New("mounts are not supported by the script format")
+	}
+	return nil
+}
+
+// render generates a script writing the given files and the kubeadm configuration, then running the kubeadm command
+// together with the pre and post kubeadm commands.
+func render(input *cloudinit.BaseUserData, files []bootstrapv1.File, kubeadmCommand, kubeadmConfigPath, kubeadmConfig string) ([]byte, error) {
+	if err := Validate(input); err != nil {
+		return nil, err
+	}
+
+	files = append(files, input.AdditionalFiles...)
+	files = append(files, bootstrapv1.File{
+		Path:        kubeadmConfigPath,
+		Owner:       "root:root",
+		Permissions: "0640",
+		Content:     kubeadmConfig,
+	})
+
+	var b strings.Builder
+	b.WriteString(scriptHeader)
+	for _, f := range files {
+		b.WriteString(writeFile(f))
+	}
+	for _, command := range input.PreKubeadmCommands {
+		b.WriteString(command + "\n")
+	}
+	b.WriteString(kubeadmCommand + "\n")
+	b.WriteString(sentinelFileCommand + "\n")
+	for _, command := range input.PostKubeadmCommands {
+		b.WriteString(command + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// writeFile returns the commands writing a file; the content is embedded in the script base64 encoded,
+// so it is written as is, whatever characters it contains.
+func writeFile(f bootstrapv1.File) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "mkdir -p %s\n", quote(path.Dir(f.Path)))
+
+	// Content already base64 encoded is used as is, after removing line breaks.
+	content := base64.StdEncoding.EncodeToString([]byte(f.Content))
+	decompress := ""
+	switch f.Encoding {
+	case bootstrapv1.Base64:
+		content = strings.Join(strings.Fields(f.Content), "")
+	case bootstrapv1.Gzip:
+		decompress = " | gunzip"
+	case bootstrapv1.GzipBase64:
+		content = strings.Join(strings.Fields(f.Content), "")
+		decompress = " | gunzip"
+	}
+
+	redirect := ">"
+	if f.Append {
+		redirect = ">>"
+	}
+	fmt.Fprintf(&b, "base64 -d <<'%s'%s %s %s\n", heredocDelimiter, decompress, redirect, quote(f.Path))
+	for len(content) > base64LineLength {
+		b.WriteString(content[:base64LineLength] + "\n")
+		content = content[base64LineLength:]
+	}
+	b.WriteString(content + "\n")
+	b.WriteString(heredocDelimiter + "\n")
+
+	if f.Owner != "" {
+		fmt.Fprintf(&b, "chown %s %s\n", quote(f.Owner), quote(f.Path))
+	}
+	if f.Permissions != "" {
+		fmt.Fprintf(&b, "chmod %s %s\n", quote(f.Permissions), quote(f.Path))
+	}
+	return b.String()
+}
+
+// quote returns the given string single quoted, so it is not interpreted by the shell.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
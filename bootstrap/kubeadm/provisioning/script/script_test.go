@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package script
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/provisioning/cloudinit"
+)
+
+func TestNewInitControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.ControlPlaneInput{
+		BaseUserData: cloudinit.BaseUserData{
+			PreKubeadmCommands:  []string{"echo pre"},
+			PostKubeadmCommands: []string{"echo post"},
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:        "/tmp/my path",
+					Owner:       "foo:bar",
+					Permissions: "0600",
+					Encoding:    bootstrapv1.Base64,
+					Content:     "aGk=",
+				},
+				{
+					Path:    "/tmp/my-other-path",
+					Content: "it's",
+					Append:  true,
+				},
+			},
+			KubeadmVerbosity: "--v=5",
+		},
+		ClusterConfiguration: "my-cluster-config",
+		InitConfiguration:    "my-init-config",
+	}
+
+	out, err := NewInitControlPlane(input)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(out)).To(Equal(strings.Join([]string{
+		"#!/bin/bash",
+		"set -e",
+		"mkdir -p '/tmp'",
+		"base64 -d <<'CLUSTER_API_EOF' > '/tmp/my path'",
+		"aGk=",
+		"CLUSTER_API_EOF",
+		"chown 'foo:bar' '/tmp/my path'",
+		"chmod '0600' '/tmp/my path'",
+		"mkdir -p '/tmp'",
+		"base64 -d <<'CLUSTER_API_EOF' >> '/tmp/my-other-path'",
+		"aXQncw==",
+		"CLUSTER_API_EOF",
+		"mkdir -p '/run/kubeadm'",
+		"base64 -d <<'CLUSTER_API_EOF' > '/run/kubeadm/kubeadm.yaml'",
+		"LS0tCm15LWNsdXN0ZXItY29uZmlnCi0tLQpteS1pbml0LWNvbmZpZw==",
+		"CLUSTER_API_EOF",
+		"chown 'root:root' '/run/kubeadm/kubeadm.yaml'",
+		"chmod '0640' '/run/kubeadm/kubeadm.yaml'",
+		"echo pre",
+		"kubeadm init --config /run/kubeadm/kubeadm.yaml --v=5",
+		sentinelFileCommand,
+		"echo post",
+		"",
+	}, "\n")))
+}
+
+func TestNewNode(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:     "/tmp/my-path",
+					Encoding: bootstrapv1.GzipBase64,
+					Content:  "H4sIAAAAAAACA8vIBACsKpPYAgAAAA==",
+				},
+			},
+		},
+		JoinConfiguration: "my-join-config",
+	}
+
+	out, err := NewNode(input)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(out)).To(ContainSubstring("base64 -d <<'CLUSTER_API_EOF' | gunzip > '/tmp/my-path'\nH4sIAAAAAAACA8vIBACsKpPYAgAAAA==\nCLUSTER_API_EOF\n"))
+	g.Expect(string(out)).To(ContainSubstring("kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml \n" + sentinelFileCommand + "\n"))
+}
+
+func TestWriteFileLongContent(t *testing.T) {
+	g := NewWithT(t)
+
+	out := writeFile(bootstrapv1.File{
+		Path:    "/tmp/my-path",
+		Content: strings.Repeat("a", 100),
+	})
+	lines := strings.Split(out, "\n")
+	g.Expect(lines).To(HaveLen(6))
+	g.Expect(lines[2]).To(HaveLen(base64LineLength))
+	g.Expect(strings.Join(lines[2:4], "")).To(Equal(strings.Repeat("YWFh", 33) + "YQ=="))
+	g.Expect(lines[4]).To(Equal(heredocDelimiter))
+}
+
+func TestUnsupportedFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		input cloudinit.BaseUserData
+	}{
+		{
+			name: "users",
+			input: cloudinit.BaseUserData{
+				Users: []bootstrapv1.User{{Name: "foo"}},
+			},
+		},
+		{
+			name: "ntp",
+			input: cloudinit.BaseUserData{
+				NTP: &bootstrapv1.NTP{Enabled: pointer.BoolPtr(true)},
+			},
+		},
+		{
+			name: "disk setup",
+			input: cloudinit.BaseUserData{
+				DiskSetup: &bootstrapv1.DiskSetup{},
+			},
+		},
+		{
+			name: "mounts",
+			input: cloudinit.BaseUserData{
+				Mounts: []bootstrapv1.MountPoints{{"/dev/sda1", "/data"}},
+			},
+		},
+		{
+			name: "experimental retry join",
+			input: cloudinit.BaseUserData{
+				UseExperimentalRetry: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(Validate(&tt.input)).NotTo(Succeed())
+			_, err := NewNode(&cloudinit.NodeInput{BaseUserData: tt.input})
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
@@ -31,8 +31,17 @@ type Metadata struct {
 
 	// +optional
 	ReleaseSeries []ReleaseSeries `json:"releaseSeries"`
+
+	// TemplateProcessor is the name of the processor to be used for the cluster templates of the provider, e.g. `ytt`.
+	// If not set, or set to `simple`, the cluster templates are processed using envsubst; any other value requires
+	// the corresponding template processor plugin to be installed on the machine running clusterctl.
+	// +optional
+	TemplateProcessor string `json:"templateProcessor,omitempty"`
 }
 
+// SimpleTemplateProcessor is the name of the default template processor, processing the cluster templates using envsubst.
+const SimpleTemplateProcessor = "simple"
+
 // ReleaseSeries maps a provider release series (major/minor) with a API Version of Cluster API (contract).
 type ReleaseSeries struct {
 	// Major version of the release series
@@ -45,6 +54,16 @@ type ReleaseSeries struct {
 	//
 	// The value is an API Version, e.g. `v1alpha3`.
 	Contract string `json:"contract,omitempty"`
+
+	// MinKubernetesVersion is the oldest Kubernetes version of workload clusters supported by this series, e.g. `v1.20.0`.
+	// If not set, no lower bound is enforced.
+	// +optional
+	MinKubernetesVersion string `json:"minKubernetesVersion,omitempty"`
+
+	// MaxKubernetesVersion is the newest Kubernetes version of workload clusters supported by this series, e.g. `v1.22.99`.
+	// If not set, no upper bound is enforced.
+	// +optional
+	MaxKubernetesVersion string `json:"maxKubernetesVersion,omitempty"`
 }
 
 func (rs ReleaseSeries) newer(release ReleaseSeries) bool {
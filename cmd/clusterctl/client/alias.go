@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
@@ -35,6 +36,9 @@ type Components repository.Components
 // ComponentsOptions wraps inputs to get provider's components.
 type ComponentsOptions repository.ComponentsOptions
 
+// Variable defines the value of a clusterctl configuration variable and where it comes from.
+type Variable config.Variable
+
 // Template wraps a YAML file that defines the cluster objects (Cluster, Machines etc.).
 type Template repository.Template
 
@@ -45,6 +49,15 @@ type UpgradePlan cluster.UpgradePlan
 // upgraded to a different version.
 type CertManagerUpgradePlan cluster.CertManagerUpgradePlan
 
+// ProviderDescription describes a provider installed in the management cluster.
+type ProviderDescription cluster.ProviderDescription
+
+// WorkloadClusterUpgradePlan describes the Kubernetes versions of a workload cluster and its upgrade options.
+type WorkloadClusterUpgradePlan cluster.WorkloadClusterUpgradePlan
+
+// RolloutStatus describes the status of the rollout of a cluster-api resource.
+type RolloutStatus alpha.RolloutStatus
+
 // Kubeconfig is a type that specifies inputs related to the actual kubeconfig.
 type Kubeconfig cluster.Kubeconfig
 
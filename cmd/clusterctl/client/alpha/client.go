@@ -25,11 +25,13 @@ var (
 // Client is the alpha client.
 type Client interface {
 	Rollout() Rollout
+	Machine() Machine
 }
 
 // alphaClient implements Client.
 type alphaClient struct {
 	rollout Rollout
+	machine Machine
 }
 
 // ensure alphaClient implements Client.
@@ -45,6 +47,13 @@ func InjectRollout(rollout Rollout) Option {
 	}
 }
 
+// InjectMachine allows to override the machine implementation to use.
+func InjectMachine(machine Machine) Option {
+	return func(c *alphaClient) {
+		c.machine = machine
+	}
+}
+
 // New returns a Client.
 func New(options ...Option) Client {
 	return newAlphaClient(options...)
@@ -61,9 +70,18 @@ func newAlphaClient(options ...Option) *alphaClient {
 		client.rollout = newRolloutClient()
 	}
 
+	// if there is an injected machine, use it, otherwise use a default one
+	if client.machine == nil {
+		client.machine = newMachineClient()
+	}
+
 	return client
 }
 
 func (c *alphaClient) Rollout() Rollout {
 	return c.rollout
 }
+
+func (c *alphaClient) Machine() Machine {
+	return c.machine
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DrainOptions carries the options supported by the machine drain operation.
+type DrainOptions struct {
+	// Timeout is the time to wait for the pods to be evicted from the node; zero means wait forever.
+	Timeout time.Duration
+
+	// DeleteEmptyDirData allows to evict pods using emptyDir volumes; the data in those volumes is lost.
+	DeleteEmptyDirData bool
+}
+
+// Machine defines the behavior of the operations on a single Machine.
+type Machine interface {
+	MachineDrainer(cluster.Proxy, corev1.ObjectReference, DrainOptions) error
+	MachineDeleter(cluster.Proxy, corev1.ObjectReference) error
+	MachineRemediator(cluster.Proxy, corev1.ObjectReference) error
+}
+
+var _ Machine = &machine{}
+
+type machine struct{}
+
+func newMachineClient() Machine {
+	return &machine{}
+}
+
+// getMachine retrieves the Machine object corresponding to the name and namespace specified.
+func getMachine(proxy cluster.Proxy, name, namespace string) (*clusterv1.Machine, error) {
+	machineObj := &clusterv1.Machine{}
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	machineObjKey := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	if err := c.Get(ctx, machineObjKey, machineObj); err != nil {
+		return nil, errors.Wrapf(err, "error reading Machine %s/%s",
+			machineObjKey.Namespace, machineObjKey.Name)
+	}
+	return machineObj, nil
+}
+
+// patchMachine applies a patch to a machine.
+func patchMachine(proxy cluster.Proxy, name, namespace string, patch client.Patch) error {
+	cFrom, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	machineObj := &clusterv1.Machine{}
+	machineObjKey := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	if err := cFrom.Get(ctx, machineObjKey, machineObj); err != nil {
+		return errors.Wrapf(err, "error reading Machine %s/%s", machineObjKey.Namespace, machineObjKey.Name)
+	}
+
+	if err := cFrom.Patch(ctx, machineObj, patch); err != nil {
+		return errors.Wrapf(err, "error while patching Machine %s/%s", machineObj.GetNamespace(), machineObj.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MachineDeleter will delete the specified Machine.
+// If the Machine belongs to a MachineSet, the Machine is marked with the delete-machine annotation and the owning
+// MachineDeployment, or the MachineSet if it is not part of a MachineDeployment, is scaled down by one replica, so the
+// Machine is deleted without being replaced. If the Machine does not have a controller, it is deleted directly.
+func (m *machine) MachineDeleter(proxy cluster.Proxy, ref corev1.ObjectReference) error {
+	machine, err := getMachine(proxy, ref.Name, ref.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch Machine %s/%s", ref.Namespace, ref.Name)
+	}
+	if !machine.DeletionTimestamp.IsZero() {
+		return errors.Errorf("Machine %s/%s is already being deleted", ref.Namespace, ref.Name)
+	}
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	owner := metav1.GetControllerOf(machine)
+	if owner == nil {
+		if err := c.Delete(ctx, machine); err != nil {
+			return errors.Wrapf(err, "error deleting Machine %s/%s", ref.Namespace, ref.Name)
+		}
+		return nil
+	}
+
+	if owner.Kind != "MachineSet" {
+		return errors.Errorf("Machine %s/%s is controlled by %s %s; only Machines belonging to a MachineSet or without a controller can be deleted, use \"clusterctl alpha machine remediate\" to replace it instead",
+			ref.Namespace, ref.Name, owner.Kind, owner.Name)
+	}
+
+	machineSet := &clusterv1.MachineSet{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: owner.Name}, machineSet); err != nil {
+		return errors.Wrapf(err, "error reading MachineSet %s/%s", machine.Namespace, owner.Name)
+	}
+
+	// Mark the Machine so it is given priority when the MachineSet scales down.
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"metadata\":{\"annotations\":{%q:\"\"}}}", clusterv1.DeleteMachineAnnotation)))
+	if err := patchMachine(proxy, machine.Name, machine.Namespace, patch); err != nil {
+		return err
+	}
+
+	if msOwner := metav1.GetControllerOf(machineSet); msOwner != nil && msOwner.Kind == "MachineDeployment" {
+		deployment, err := getMachineDeployment(proxy, msOwner.Name, machineSet.Namespace)
+		if err != nil {
+			return err
+		}
+		replicas, err := scaledDownReplicas(deployment.Spec.Replicas, "MachineDeployment", deployment.Namespace, deployment.Name)
+		if err != nil {
+			return err
+		}
+		patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"spec\":{\"replicas\":%d}}", replicas)))
+		return patchMachineDeployemt(proxy, deployment.Name, deployment.Namespace, patch)
+	}
+
+	replicas, err := scaledDownReplicas(machineSet.Spec.Replicas, "MachineSet", machineSet.Namespace, machineSet.Name)
+	if err != nil {
+		return err
+	}
+	msPatch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"spec\":{\"replicas\":%d}}", replicas)))
+	if err := c.Patch(ctx, machineSet, msPatch); err != nil {
+		return errors.Wrapf(err, "error while patching MachineSet %s/%s", machineSet.Namespace, machineSet.Name)
+	}
+	return nil
+}
+
+// scaledDownReplicas returns the number of replicas after removing a Machine.
+func scaledDownReplicas(replicas *int32, kind, namespace, name string) (int32, error) {
+	if replicas == nil || *replicas < 1 {
+		return 0, errors.Errorf("%s %s/%s has no replicas to scale down", kind, namespace, name)
+	}
+	return *replicas - 1, nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_MachineDeleter(t *testing.T) {
+	machineDeployment := &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MachineDeployment",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "md-1",
+			UID:       "md-1-uid",
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas: pointer.Int32Ptr(3),
+		},
+	}
+	machineSet := func(owner *metav1.OwnerReference) *clusterv1.MachineSet {
+		ms := &clusterv1.MachineSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "MachineSet",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "ms-1",
+				UID:       "ms-1-uid",
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32Ptr(3),
+			},
+		}
+		if owner != nil {
+			ms.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return ms
+	}
+	machine := func(owner *metav1.OwnerReference) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Machine",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "m-1",
+			},
+		}
+		if owner != nil {
+			m.OwnerReferences = []metav1.OwnerReference{*owner}
+		}
+		return m
+	}
+	controllerRef := func(kind, name, uid string) *metav1.OwnerReference {
+		return &metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       kind,
+			Name:       name,
+			UID:        types.UID(uid),
+			Controller: pointer.BoolPtr(true),
+		}
+	}
+
+	tests := []struct {
+		name                   string
+		objs                   []client.Object
+		wantErr                bool
+		wantMachineDeleted     bool
+		wantDeploymentReplicas *int32
+		wantMachineSetReplicas *int32
+	}{
+		{
+			name: "machine belonging to a machinedeployment should be annotated and the machinedeployment scaled down",
+			objs: []client.Object{
+				machineDeployment.DeepCopy(),
+				machineSet(controllerRef("MachineDeployment", "md-1", "md-1-uid")),
+				machine(controllerRef("MachineSet", "ms-1", "ms-1-uid")),
+			},
+			wantErr:                false,
+			wantDeploymentReplicas: pointer.Int32Ptr(2),
+			wantMachineSetReplicas: pointer.Int32Ptr(3),
+		},
+		{
+			name: "machine belonging to a machineset should be annotated and the machineset scaled down",
+			objs: []client.Object{
+				machineSet(nil),
+				machine(controllerRef("MachineSet", "ms-1", "ms-1-uid")),
+			},
+			wantErr:                false,
+			wantMachineSetReplicas: pointer.Int32Ptr(2),
+		},
+		{
+			name: "machine without a controller should be deleted",
+			objs: []client.Object{
+				machine(nil),
+			},
+			wantErr:            false,
+			wantMachineDeleted: true,
+		},
+		{
+			name: "machine belonging to a kubeadmcontrolplane should return error",
+			objs: []client.Object{
+				machine(controllerRef("KubeadmControlPlane", "kcp", "kcp-uid")),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "machine not existing should return error",
+			objs:    []client.Object{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := newMachineClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			err := m.MachineDeleter(proxy, corev1.ObjectReference{
+				Kind:      "Machine",
+				Name:      "m-1",
+				Namespace: "default",
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cl, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+
+			gotMachine := &clusterv1.Machine{}
+			err = cl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "m-1"}, gotMachine)
+			if tt.wantMachineDeleted {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(gotMachine.Annotations).To(HaveKey(clusterv1.DeleteMachineAnnotation))
+
+			if tt.wantDeploymentReplicas != nil {
+				gotDeployment := &clusterv1.MachineDeployment{}
+				g.Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "md-1"}, gotDeployment)).To(Succeed())
+				g.Expect(gotDeployment.Spec.Replicas).To(Equal(tt.wantDeploymentReplicas))
+			}
+			if tt.wantMachineSetReplicas != nil {
+				gotMachineSet := &clusterv1.MachineSet{}
+				g.Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "ms-1"}, gotMachineSet)).To(Succeed())
+				g.Expect(gotMachineSet.Spec.Replicas).To(Equal(tt.wantMachineSetReplicas))
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	kubedrain "sigs.k8s.io/cluster-api/third_party/kubernetes-drain"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MachineDrainer will cordon and drain the Node hosted on the specified Machine.
+// NB. The Machine itself is not modified, so the Node is drained again by the Machine controller when the Machine is deleted.
+func (m *machine) MachineDrainer(proxy cluster.Proxy, ref corev1.ObjectReference, options DrainOptions) error {
+	log := logf.Log
+
+	machine, err := getMachine(proxy, ref.Name, ref.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch Machine %s/%s", ref.Namespace, ref.Name)
+	}
+	if machine.Status.NodeRef == nil {
+		return errors.Errorf("Machine %s/%s does not have a Node yet", ref.Namespace, ref.Name)
+	}
+	nodeName := machine.Status.NodeRef.Name
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	restConfig, err := remote.RESTConfig(ctx, "clusterctl", c, client.ObjectKey{Namespace: machine.Namespace, Name: machine.Spec.ClusterName})
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create a client for Cluster %s/%s", machine.Namespace, machine.Spec.ClusterName)
+	}
+
+	node, err := kubeClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to get Node %s", nodeName)
+	}
+
+	drainer := &kubedrain.Helper{
+		Client:              kubeClient,
+		Force:               true,
+		IgnoreAllDaemonSets: true,
+		DeleteLocalData:     options.DeleteEmptyDirData,
+		GracePeriodSeconds:  -1,
+		Timeout:             options.Timeout,
+		OnPodDeletedOrEvicted: func(pod *corev1.Pod, usingEviction bool) {
+			verbStr := "Deleted"
+			if usingEviction {
+				verbStr = "Evicted"
+			}
+			log.Info(verbStr+" pod from Node", "Pod", pod.Namespace+"/"+pod.Name)
+		},
+		Out:    os.Stdout,
+		ErrOut: os.Stderr,
+	}
+
+	log.Info("Cordoning Node", "Node", nodeName)
+	if err := kubedrain.RunCordonOrUncordon(ctx, drainer, node, true); err != nil {
+		return errors.Wrapf(err, "unable to cordon Node %s", nodeName)
+	}
+
+	log.Info("Draining Node", "Node", nodeName)
+	if err := kubedrain.RunNodeDrain(ctx, drainer, nodeName); err != nil {
+		return errors.Wrapf(err, "unable to drain Node %s", nodeName)
+	}
+	return nil
+}
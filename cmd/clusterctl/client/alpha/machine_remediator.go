@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// remediationRequestedReason is the reason used for the MachineHealthCheckSucceeded condition
+// when the remediation of a Machine is requested by clusterctl.
+const remediationRequestedReason = "RemediationRequested"
+
+// MachineRemediator will mark the specified Machine for remediation.
+// The Machine gets the same conditions set by a MachineHealthCheck for unhealthy Machines, so the owning
+// MachineSet or KubeadmControlPlane replaces it according to its own remediation rules.
+func (m *machine) MachineRemediator(proxy cluster.Proxy, ref corev1.ObjectReference) error {
+	machine, err := getMachine(proxy, ref.Name, ref.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch Machine %s/%s", ref.Namespace, ref.Name)
+	}
+	if !machine.DeletionTimestamp.IsZero() {
+		return errors.Errorf("Machine %s/%s is already being deleted", ref.Namespace, ref.Name)
+	}
+
+	owner := metav1.GetControllerOf(machine)
+	if owner == nil || (owner.Kind != "MachineSet" && owner.Kind != "KubeadmControlPlane") {
+		return errors.Errorf("Machine %s/%s can't be remediated: only Machines controlled by a MachineSet or a KubeadmControlPlane support remediation", ref.Namespace, ref.Name)
+	}
+	if conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition) {
+		return errors.Errorf("Machine %s/%s is already waiting for remediation", ref.Namespace, ref.Name)
+	}
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	patchHelper, err := patch.NewHelper(machine, c)
+	if err != nil {
+		return err
+	}
+
+	conditions.MarkFalse(machine, clusterv1.MachineHealthCheckSuccededCondition, remediationRequestedReason, clusterv1.ConditionSeverityWarning, "Remediation requested using clusterctl")
+	conditions.MarkFalse(machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return errors.Wrapf(err, "error while patching Machine %s/%s", machine.Namespace, machine.Name)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_MachineRemediator(t *testing.T) {
+	machine := func(ownerKind string, remediationConditions ...clusterv1.Condition) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Machine",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "m-1",
+			},
+			Status: clusterv1.MachineStatus{
+				Conditions: remediationConditions,
+			},
+		}
+		if ownerKind != "" {
+			m.OwnerReferences = []metav1.OwnerReference{
+				{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       ownerKind,
+					Name:       "owner",
+					UID:        "owner-uid",
+					Controller: pointer.BoolPtr(true),
+				},
+			}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		wantErr bool
+	}{
+		{
+			name: "machine belonging to a machineset should be marked for remediation",
+			objs: []client.Object{
+				machine("MachineSet"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "machine belonging to a kubeadmcontrolplane should be marked for remediation",
+			objs: []client.Object{
+				machine("KubeadmControlPlane"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "machine without a controller should return error",
+			objs: []client.Object{
+				machine(""),
+			},
+			wantErr: true,
+		},
+		{
+			name: "machine already waiting for remediation should return error",
+			objs: []client.Object{
+				machine("MachineSet", *conditions.FalseCondition(clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := newMachineClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			err := m.MachineRemediator(proxy, corev1.ObjectReference{
+				Kind:      "Machine",
+				Name:      "m-1",
+				Namespace: "default",
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cl, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			gotMachine := &clusterv1.Machine{}
+			g.Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "m-1"}, gotMachine)).To(Succeed())
+			g.Expect(conditions.IsFalse(gotMachine, clusterv1.MachineHealthCheckSuccededCondition)).To(BeTrue())
+			g.Expect(conditions.IsFalse(gotMachine, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(gotMachine, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(clusterv1.WaitingForRemediationReason))
+		})
+	}
+}
@@ -21,10 +21,14 @@ import (
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
 
-// MachineDeployment is a resource type.
-const MachineDeployment = "machinedeployment"
+const (
+	// MachineDeployment is a resource type.
+	MachineDeployment = "machinedeployment"
+	// KubeadmControlPlane is a resource type.
+	KubeadmControlPlane = "kubeadmcontrolplane"
+)
 
-var validResourceTypes = []string{MachineDeployment}
+var validResourceTypes = []string{MachineDeployment, KubeadmControlPlane}
 
 // Rollout defines the behavior of a rollout implementation.
 type Rollout interface {
@@ -32,6 +36,7 @@ type Rollout interface {
 	ObjectPauser(cluster.Proxy, corev1.ObjectReference) error
 	ObjectResumer(cluster.Proxy, corev1.ObjectReference) error
 	ObjectRollbacker(cluster.Proxy, corev1.ObjectReference, int64) error
+	ObjectStatusViewer(cluster.Proxy, corev1.ObjectReference) (*RolloutStatus, error)
 }
 
 var _ Rollout = &rollout{}
@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -41,6 +42,17 @@ func (r *rollout) ObjectRestarter(proxy cluster.Proxy, ref corev1.ObjectReferenc
 		if err := setRestartedAtAnnotation(proxy, ref.Name, ref.Namespace); err != nil {
 			return err
 		}
+	case KubeadmControlPlane:
+		kcp, err := getKubeadmControlPlane(proxy, ref.Name, ref.Namespace)
+		if err != nil || kcp == nil {
+			return errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
+		}
+		if annotations.HasPausedAnnotation(kcp) {
+			return errors.Errorf("can't restart paused kubeadmcontrolplane (run rollout resume first): %v/%v\n", ref.Kind, ref.Name)
+		}
+		if err := setRolloutAfter(proxy, ref.Name, ref.Namespace); err != nil {
+			return err
+		}
 	default:
 		return errors.Errorf("Invalid resource type %v. Valid values: %v", ref.Kind, validResourceTypes)
 	}
@@ -52,3 +64,10 @@ func setRestartedAtAnnotation(proxy cluster.Proxy, name, namespace string) error
 	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"spec\":{\"template\":{\"metadata\":{\"annotations\":{\"cluster.x-k8s.io/restartedAt\":\"%v\"}}}}}", time.Now().Format(time.RFC3339))))
 	return patchMachineDeployemt(proxy, name, namespace, patch)
 }
+
+// setRolloutAfter sets rolloutAfter to the current time in the KubeadmControlPlane's spec,
+// so all the machines created before now are rolled out.
+func setRolloutAfter(proxy cluster.Proxy, name, namespace string) error {
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"spec\":{\"rolloutAfter\":\"%v\"}}", time.Now().Format(time.RFC3339))))
+	return patchKubeadmControlPlane(proxy, name, namespace, patch)
+}
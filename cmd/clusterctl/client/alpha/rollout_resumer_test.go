@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -115,3 +116,68 @@ func Test_ObjectResumer(t *testing.T) {
 		})
 	}
 }
+
+func Test_ObjectResumer_KubeadmControlPlane(t *testing.T) {
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		wantErr bool
+	}{
+		{
+			name: "paused kubeadmcontrolplane should be resumed",
+			objs: []client.Object{
+				&controlplanev1.KubeadmControlPlane{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "KubeadmControlPlane",
+						APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   "default",
+						Name:        "kcp",
+						Annotations: map[string]string{clusterv1.PausedAnnotation: "true"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "resuming a kubeadmcontrolplane which is not paused should return error",
+			objs: []client.Object{
+				&controlplanev1.KubeadmControlPlane{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "KubeadmControlPlane",
+						APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "default",
+						Name:      "kcp",
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.objs...)
+			err := r.ObjectResumer(proxy, corev1.ObjectReference{
+				Kind:      KubeadmControlPlane,
+				Name:      "kcp",
+				Namespace: "default",
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cl, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			kcp := &controlplanev1.KubeadmControlPlane{}
+			g.Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "kcp"}, kcp)).To(Succeed())
+			g.Expect(kcp.Annotations).ToNot(HaveKey(clusterv1.PausedAnnotation))
+		})
+	}
+}
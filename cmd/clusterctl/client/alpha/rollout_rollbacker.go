@@ -40,6 +40,8 @@ func (r *rollout) ObjectRollbacker(proxy cluster.Proxy, ref corev1.ObjectReferen
 		if err := rollbackMachineDeployment(proxy, deployment, toRevision); err != nil {
 			return err
 		}
+	case KubeadmControlPlane:
+		return errors.Errorf("rollback is not supported for %q: KubeadmControlPlane does not keep a revision history", ref.Kind)
 	default:
 		return errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validResourceTypes)
 	}
@@ -62,7 +64,7 @@ func rollbackMachineDeployment(proxy cluster.Proxy, d *clusterv1.MachineDeployme
 		return err
 	}
 	log.V(7).Info("Found MachineSets", "count", len(msList))
-	msForRevision, err := findMachineDeploymentRevision(toRevision, msList)
+	msForRevision, err := mdutil.FindMachineSetForRevision(toRevision, msList)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// RolloutStatus describes the status of the rollout of a cluster-api resource.
+type RolloutStatus struct {
+	// Ref is the reference to the resource.
+	Ref corev1.ObjectReference
+
+	// Message describes the progress of the rollout.
+	Message string
+
+	// Done is true when the rollout is complete.
+	Done bool
+}
+
+// ObjectStatusViewer returns the status of the rollout of the specified cluster-api resource.
+func (r *rollout) ObjectStatusViewer(proxy cluster.Proxy, ref corev1.ObjectReference) (*RolloutStatus, error) {
+	switch ref.Kind {
+	case MachineDeployment:
+		deployment, err := getMachineDeployment(proxy, ref.Name, ref.Namespace)
+		if err != nil || deployment == nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
+		}
+		if deployment.Spec.Paused {
+			return nil, errors.Errorf("can't get the rollout status of a paused machinedeployment (run rollout resume first): %v/%v", ref.Kind, ref.Name)
+		}
+		return machineDeploymentRolloutStatus(ref, deployment), nil
+	case KubeadmControlPlane:
+		kcp, err := getKubeadmControlPlane(proxy, ref.Name, ref.Namespace)
+		if err != nil || kcp == nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", ref.Kind, ref.Name)
+		}
+		if annotations.HasPausedAnnotation(kcp) {
+			return nil, errors.Errorf("can't get the rollout status of a paused kubeadmcontrolplane (run rollout resume first): %v/%v", ref.Kind, ref.Name)
+		}
+		return kubeadmControlPlaneRolloutStatus(ref, kcp), nil
+	default:
+		return nil, errors.Errorf("invalid resource type %q, valid values are %v", ref.Kind, validResourceTypes)
+	}
+}
+
+// machineDeploymentRolloutStatus returns the rollout status of a MachineDeployment, derived from its replica counters
+// and from the MachineDeployment's Available condition.
+func machineDeploymentRolloutStatus(ref corev1.ObjectReference, d *clusterv1.MachineDeployment) *RolloutStatus {
+	status := &RolloutStatus{Ref: ref}
+
+	if d.Generation > d.Status.ObservedGeneration {
+		status.Message = fmt.Sprintf("Waiting for MachineDeployment %q spec update to be observed...", d.Name)
+		return status
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	switch {
+	case d.Status.UpdatedReplicas < replicas:
+		status.Message = fmt.Sprintf("Waiting for MachineDeployment %q rollout to finish: %d out of %d new replicas have been updated...", d.Name, d.Status.UpdatedReplicas, replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for MachineDeployment %q rollout to finish: %d old replicas are pending termination...", d.Name, d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for MachineDeployment %q rollout to finish: %d of %d updated replicas are available...", d.Name, d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	case conditions.IsFalse(d, clusterv1.MachineDeploymentAvailableCondition):
+		status.Message = fmt.Sprintf("Waiting for MachineDeployment %q to be available: %s", d.Name, conditions.GetMessage(d, clusterv1.MachineDeploymentAvailableCondition))
+	default:
+		status.Message = fmt.Sprintf("MachineDeployment %q successfully rolled out", d.Name)
+		status.Done = true
+	}
+	return status
+}
+
+// kubeadmControlPlaneRolloutStatus returns the rollout status of a KubeadmControlPlane, derived from its replica counters
+// and from the KubeadmControlPlane's MachinesSpecUpToDate condition.
+func kubeadmControlPlaneRolloutStatus(ref corev1.ObjectReference, kcp *controlplanev1.KubeadmControlPlane) *RolloutStatus {
+	status := &RolloutStatus{Ref: ref}
+
+	if kcp.Generation > kcp.Status.ObservedGeneration {
+		status.Message = fmt.Sprintf("Waiting for KubeadmControlPlane %q spec update to be observed...", kcp.Name)
+		return status
+	}
+
+	replicas := int32(1)
+	if kcp.Spec.Replicas != nil {
+		replicas = *kcp.Spec.Replicas
+	}
+
+	switch {
+	case kcp.Status.UpdatedReplicas < replicas || conditions.IsFalse(kcp, controlplanev1.MachinesSpecUpToDateCondition):
+		status.Message = fmt.Sprintf("Waiting for KubeadmControlPlane %q rollout to finish: %d out of %d new replicas have been updated...", kcp.Name, kcp.Status.UpdatedReplicas, replicas)
+	case kcp.Status.Replicas > kcp.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for KubeadmControlPlane %q rollout to finish: %d old replicas are pending termination...", kcp.Name, kcp.Status.Replicas-kcp.Status.UpdatedReplicas)
+	case kcp.Status.ReadyReplicas < kcp.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Waiting for KubeadmControlPlane %q rollout to finish: %d of %d updated replicas are ready...", kcp.Name, kcp.Status.ReadyReplicas, kcp.Status.UpdatedReplicas)
+	default:
+		status.Message = fmt.Sprintf("KubeadmControlPlane %q successfully rolled out", kcp.Name)
+		status.Done = true
+	}
+	return status
+}
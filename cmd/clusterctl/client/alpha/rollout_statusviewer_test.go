@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_ObjectStatusViewer(t *testing.T) {
+	md := func(status clusterv1.MachineDeploymentStatus, paused bool) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "MachineDeployment",
+				APIVersion: "cluster.x-k8s.io/v1beta1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "default",
+				Name:       "md-1",
+				Generation: 2,
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				Replicas: pointer.Int32Ptr(3),
+				Paused:   paused,
+			},
+			Status: status,
+		}
+	}
+	kcp := func(status controlplanev1.KubeadmControlPlaneStatus) *controlplanev1.KubeadmControlPlane {
+		return &controlplanev1.KubeadmControlPlane{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "KubeadmControlPlane",
+				APIVersion: "controlplane.cluster.x-k8s.io/v1beta1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "default",
+				Name:       "kcp",
+				Generation: 2,
+			},
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas: pointer.Int32Ptr(3),
+			},
+			Status: status,
+		}
+	}
+	mdRef := corev1.ObjectReference{Kind: MachineDeployment, Name: "md-1", Namespace: "default"}
+	kcpRef := corev1.ObjectReference{Kind: KubeadmControlPlane, Name: "kcp", Namespace: "default"}
+
+	tests := []struct {
+		name        string
+		obj         client.Object
+		ref         corev1.ObjectReference
+		wantMessage string
+		wantDone    bool
+		wantErr     bool
+	}{
+		{
+			name:        "machinedeployment spec update not observed yet",
+			obj:         md(clusterv1.MachineDeploymentStatus{ObservedGeneration: 1}, false),
+			ref:         mdRef,
+			wantMessage: `Waiting for MachineDeployment "md-1" spec update to be observed...`,
+		},
+		{
+			name:        "machinedeployment with replicas to be updated",
+			obj:         md(clusterv1.MachineDeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1}, false),
+			ref:         mdRef,
+			wantMessage: `Waiting for MachineDeployment "md-1" rollout to finish: 1 out of 3 new replicas have been updated...`,
+		},
+		{
+			name:        "machinedeployment with old replicas pending termination",
+			obj:         md(clusterv1.MachineDeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3}, false),
+			ref:         mdRef,
+			wantMessage: `Waiting for MachineDeployment "md-1" rollout to finish: 1 old replicas are pending termination...`,
+		},
+		{
+			name:        "machinedeployment with updated replicas not available",
+			obj:         md(clusterv1.MachineDeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}, false),
+			ref:         mdRef,
+			wantMessage: `Waiting for MachineDeployment "md-1" rollout to finish: 2 of 3 updated replicas are available...`,
+		},
+		{
+			name:        "machinedeployment rolled out",
+			obj:         md(clusterv1.MachineDeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}, false),
+			ref:         mdRef,
+			wantMessage: `MachineDeployment "md-1" successfully rolled out`,
+			wantDone:    true,
+		},
+		{
+			name:    "paused machinedeployment should return error",
+			obj:     md(clusterv1.MachineDeploymentStatus{ObservedGeneration: 2}, true),
+			ref:     mdRef,
+			wantErr: true,
+		},
+		{
+			name: "kubeadmcontrolplane with machines not up to date",
+			obj: kcp(controlplanev1.KubeadmControlPlaneStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3, Conditions: clusterv1.Conditions{
+				{Type: controlplanev1.MachinesSpecUpToDateCondition, Status: corev1.ConditionFalse},
+			}}),
+			ref:         kcpRef,
+			wantMessage: `Waiting for KubeadmControlPlane "kcp" rollout to finish: 3 out of 3 new replicas have been updated...`,
+		},
+		{
+			name:        "kubeadmcontrolplane with old replicas pending termination",
+			obj:         kcp(controlplanev1.KubeadmControlPlaneStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3, ReadyReplicas: 4}),
+			ref:         kcpRef,
+			wantMessage: `Waiting for KubeadmControlPlane "kcp" rollout to finish: 1 old replicas are pending termination...`,
+		},
+		{
+			name:        "kubeadmcontrolplane rolled out",
+			obj:         kcp(controlplanev1.KubeadmControlPlaneStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3}),
+			ref:         kcpRef,
+			wantMessage: `KubeadmControlPlane "kcp" successfully rolled out`,
+			wantDone:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.obj)
+			got, err := r.ObjectStatusViewer(proxy, tt.ref)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Ref).To(Equal(tt.ref))
+			g.Expect(got.Message).To(Equal(tt.wantMessage))
+			g.Expect(got.Done).To(Equal(tt.wantDone))
+		})
+	}
+}
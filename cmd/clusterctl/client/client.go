@@ -30,6 +30,12 @@ type Client interface {
 	// GetProvidersConfig returns the list of providers configured for this instance of clusterctl.
 	GetProvidersConfig() ([]Provider, error)
 
+	// GetVariablesConfig returns the variables defined in the clusterctl configuration, with the source of their values.
+	GetVariablesConfig() ([]Variable, error)
+
+	// ExplainVariable returns the value of a variable, where it comes from, and the provider files consuming it.
+	ExplainVariable(options ExplainVariableOptions) (*VariableExplanation, error)
+
 	// GetProviderComponents returns the provider components for a given provider with options including targetNamespace.
 	GetProviderComponents(provider string, providerType clusterctlv1.ProviderType, options ComponentsOptions) (Components, error)
 
@@ -39,6 +45,10 @@ type Client interface {
 	// InitImages returns the list of images required for executing the init command.
 	InitImages(options InitOptions) ([]string, error)
 
+	// InitManifests returns the provider components that would be installed by the init command, with all the
+	// variables substituted, without applying them to the management cluster.
+	InitManifests(options InitOptions) ([]Components, error)
+
 	// GetClusterTemplate returns a workload cluster template.
 	GetClusterTemplate(options GetClusterTemplateOptions) (Template, error)
 
@@ -51,6 +61,10 @@ type Client interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 	Move(options MoveOptions) error
 
+	// GetMoveGraph returns the graph of the Cluster API objects that would be moved to a target management cluster, without
+	// pausing or moving anything.
+	GetMoveGraph(options MoveOptions) (*cluster.MoveGraph, error)
+
 	// Backup saves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 	Backup(options BackupOptions) error
 
@@ -65,9 +79,17 @@ type Client interface {
 	// PlanCertManagerUpgrade returns a CertManagerUpgradePlan.
 	PlanCertManagerUpgrade(options PlanUpgradeOptions) (CertManagerUpgradePlan, error)
 
+	// PlanWorkloadClustersUpgrade returns, for each workload cluster, the current Kubernetes versions, the next
+	// Kubernetes version supported by the installed providers and the version skew violations.
+	PlanWorkloadClustersUpgrade(options PlanUpgradeOptions) ([]WorkloadClusterUpgradePlan, error)
+
 	// ApplyUpgrade executes an upgrade plan.
 	ApplyUpgrade(options ApplyUpgradeOptions) error
 
+	// MirrorRepositories copies the components YAML, the metadata YAML and the workload cluster templates of the providers
+	// into a mirror folder, that can be used for reading the providers in air-gapped environments.
+	MirrorRepositories(options MirrorRepositoriesOptions) error
+
 	// ProcessYAML provides a direct way to process a yaml and inspect its
 	// variables.
 	ProcessYAML(options ProcessYAMLOptions) (YamlPrinter, error)
@@ -75,6 +97,10 @@ type Client interface {
 	// DescribeCluster returns the object tree representing the status of a Cluster API cluster.
 	DescribeCluster(options DescribeClusterOptions) (*tree.ObjectTree, error)
 
+	// DescribeProviders returns, for each provider installed in the management cluster, the installed version and contract,
+	// the status of the provider's controllers, webhooks and CRDs, and the versions the provider can be upgraded to.
+	DescribeProviders(options DescribeProvidersOptions) ([]ProviderDescription, error)
+
 	// Interface for alpha features in clusterctl
 	AlphaClient
 }
@@ -89,6 +115,14 @@ type AlphaClient interface {
 	RolloutResume(options RolloutOptions) error
 	// RolloutUndo provides rollout rollback of cluster-api resources
 	RolloutUndo(options RolloutOptions) error
+	// RolloutStatus provides the rollout status of cluster-api resources
+	RolloutStatus(options RolloutOptions) ([]RolloutStatus, error)
+	// MachineDrain cordons and drains the Nodes hosted on the given Machines
+	MachineDrain(options MachineOptions) error
+	// MachineDelete deletes the given Machines without replacing them
+	MachineDelete(options MachineOptions) error
+	// MachineRemediate marks the given Machines for remediation by their owner
+	MachineRemediate(options MachineOptions) error
 }
 
 // YamlPrinter exposes methods that prints the processed template and
@@ -113,6 +147,9 @@ type clusterctlClient struct {
 type RepositoryClientFactoryInput struct {
 	Provider  Provider
 	Processor Processor
+
+	// IgnoreMirror forces reading the provider from its repository URL, even if a mirror of the provider repositories is configured.
+	IgnoreMirror bool
 }
 
 // RepositoryClientFactory is a factory of repository.Client from a given input.
@@ -199,10 +236,14 @@ func newClusterctlClient(path string, options ...Option) (*clusterctlClient, err
 // defaultRepositoryFactory is a RepositoryClientFactory func the uses the default client provided by the repository low level library.
 func defaultRepositoryFactory(configClient config.Client) RepositoryClientFactory {
 	return func(input RepositoryClientFactoryInput) (repository.Client, error) {
+		options := []repository.Option{repository.InjectYamlProcessor(input.Processor)}
+		if input.IgnoreMirror {
+			options = append(options, repository.IgnoreMirror())
+		}
 		return repository.New(
 			input.Provider,
 			configClient,
-			repository.InjectYamlProcessor(input.Processor),
+			options...,
 		)
 	}
 }
@@ -76,6 +76,14 @@ func (f fakeClient) GetProvidersConfig() ([]Provider, error) {
 	return f.internalClient.GetProvidersConfig()
 }
 
+func (f fakeClient) GetVariablesConfig() ([]Variable, error) {
+	return f.internalClient.GetVariablesConfig()
+}
+
+func (f fakeClient) ExplainVariable(options ExplainVariableOptions) (*VariableExplanation, error) {
+	return f.internalClient.ExplainVariable(options)
+}
+
 func (f fakeClient) GetProviderComponents(provider string, providerType clusterctlv1.ProviderType, options ComponentsOptions) (Components, error) {
 	return f.internalClient.GetProviderComponents(provider, providerType, options)
 }
@@ -96,6 +104,10 @@ func (f fakeClient) InitImages(options InitOptions) ([]string, error) {
 	return f.internalClient.InitImages(options)
 }
 
+func (f fakeClient) InitManifests(options InitOptions) ([]Components, error) {
+	return f.internalClient.InitManifests(options)
+}
+
 func (f fakeClient) Delete(options DeleteOptions) error {
 	return f.internalClient.Delete(options)
 }
@@ -104,6 +116,10 @@ func (f fakeClient) Move(options MoveOptions) error {
 	return f.internalClient.Move(options)
 }
 
+func (f fakeClient) GetMoveGraph(options MoveOptions) (*cluster.MoveGraph, error) {
+	return f.internalClient.GetMoveGraph(options)
+}
+
 func (f fakeClient) Backup(options BackupOptions) error {
 	return f.internalClient.Backup(options)
 }
@@ -120,6 +136,14 @@ func (f fakeClient) PlanCertManagerUpgrade(options PlanUpgradeOptions) (CertMana
 	return f.internalClient.PlanCertManagerUpgrade(options)
 }
 
+func (f fakeClient) MirrorRepositories(options MirrorRepositoriesOptions) error {
+	return f.internalClient.MirrorRepositories(options)
+}
+
+func (f fakeClient) PlanWorkloadClustersUpgrade(options PlanUpgradeOptions) ([]WorkloadClusterUpgradePlan, error) {
+	return f.internalClient.PlanWorkloadClustersUpgrade(options)
+}
+
 func (f fakeClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
 	return f.internalClient.ApplyUpgrade(options)
 }
@@ -136,6 +160,10 @@ func (f fakeClient) DescribeCluster(options DescribeClusterOptions) (*tree.Objec
 	return f.internalClient.DescribeCluster(options)
 }
 
+func (f fakeClient) DescribeProviders(options DescribeProvidersOptions) ([]ProviderDescription, error) {
+	return f.internalClient.DescribeProviders(options)
+}
+
 func (f fakeClient) RolloutPause(options RolloutOptions) error {
 	return f.internalClient.RolloutPause(options)
 }
@@ -148,6 +176,22 @@ func (f fakeClient) RolloutUndo(options RolloutOptions) error {
 	return f.internalClient.RolloutUndo(options)
 }
 
+func (f fakeClient) RolloutStatus(options RolloutOptions) ([]RolloutStatus, error) {
+	return f.internalClient.RolloutStatus(options)
+}
+
+func (f fakeClient) MachineDrain(options MachineOptions) error {
+	return f.internalClient.MachineDrain(options)
+}
+
+func (f fakeClient) MachineDelete(options MachineOptions) error {
+	return f.internalClient.MachineDelete(options)
+}
+
+func (f fakeClient) MachineRemediate(options MachineOptions) error {
+	return f.internalClient.MachineRemediate(options)
+}
+
 // newFakeClient returns a clusterctl client that allows to execute tests on a set of fake config, fake repositories and fake clusters.
 // you can use WithCluster and WithRepository to prepare for the test case.
 func newFakeClient(configClient config.Client) *fakeClient {
@@ -310,6 +354,10 @@ func (f *fakeClusterClient) ProviderUpgrader() cluster.ProviderUpgrader {
 	return f.internalclient.ProviderUpgrader()
 }
 
+func (f *fakeClusterClient) ProviderDescriber() cluster.ProviderDescriber {
+	return f.internalclient.ProviderDescriber()
+}
+
 func (f *fakeClusterClient) Template() cluster.TemplateClient {
 	return f.internalclient.Template()
 }
@@ -453,6 +501,22 @@ func (f fakeRepositoryClient) Metadata(version string) repository.MetadataClient
 	}
 }
 
+func (f fakeRepositoryClient) Mirror(version, mirrorFolder string) error {
+	r, err := repository.New(f.Provider, f.configClient, repository.InjectRepository(f.fakeRepository))
+	if err != nil {
+		return err
+	}
+	return r.Mirror(version, mirrorFolder)
+}
+
+func (f fakeRepositoryClient) Variables(version string) (map[string][]string, error) {
+	r, err := repository.New(f.Provider, f.configClient, repository.InjectRepository(f.fakeRepository))
+	if err != nil {
+		return nil, err
+	}
+	return r.Variables(version)
+}
+
 func (f *fakeRepositoryClient) WithPaths(rootPath, componentsPath string) *fakeRepositoryClient {
 	f.fakeRepository.WithPaths(rootPath, componentsPath)
 	return f
@@ -84,10 +84,15 @@ type Client interface {
 	// ProviderUpgrader returns a ProviderUpgrader that supports upgrading Cluster API providers.
 	ProviderUpgrader() ProviderUpgrader
 
+	// ProviderDescriber returns a ProviderDescriber that supports describing the Cluster API providers installed
+	// in the management cluster.
+	ProviderDescriber() ProviderDescriber
+
 	// Template has methods to work with templates stored in the cluster.
 	Template() TemplateClient
 
-	// WorkloadCluster has methods for fetching kubeconfig of workload cluster from management cluster.
+	// WorkloadCluster has methods for fetching kubeconfig of workload cluster from management cluster
+	// and for deleting workload clusters.
 	WorkloadCluster() WorkloadCluster
 }
 
@@ -139,7 +144,11 @@ func (c *clusterClient) ObjectMover() ObjectMover {
 }
 
 func (c *clusterClient) ProviderUpgrader() ProviderUpgrader {
-	return newProviderUpgrader(c.configClient, c.repositoryClientFactory, c.ProviderInventory(), c.ProviderComponents())
+	return newProviderUpgrader(c.proxy, c.configClient, c.repositoryClientFactory, c.ProviderInventory(), c.ProviderComponents())
+}
+
+func (c *clusterClient) ProviderDescriber() ProviderDescriber {
+	return newProviderDescriber(c.proxy, c.ProviderInventory(), newProviderUpgrader(c.proxy, c.configClient, c.repositoryClientFactory, c.ProviderInventory(), c.ProviderComponents()))
 }
 
 func (c *clusterClient) Template() TemplateClient {
@@ -147,7 +156,7 @@ func (c *clusterClient) Template() TemplateClient {
 }
 
 func (c *clusterClient) WorkloadCluster() WorkloadCluster {
-	return newWorkloadCluster(c.proxy)
+	return newWorkloadCluster(c.proxy, c.pollImmediateWaiter)
 }
 
 // Option is a configuration option supplied to New.
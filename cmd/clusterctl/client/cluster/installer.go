@@ -54,6 +54,9 @@ type ProviderInstaller interface {
 
 	// Images returns the list of images required for installing the providers ready in the install queue.
 	Images() []string
+
+	// Components returns the provider components ready in the install queue.
+	Components() []repository.Components
 }
 
 // InstallOptions defines the options used to configure installation.
@@ -123,20 +126,28 @@ func (i *providerInstaller) waitForProvidersReady(opts InstallOptions) error {
 // waitManagerDeploymentsReady waits till the installed manager deployments are ready.
 func (i *providerInstaller) waitManagerDeploymentsReady(opts InstallOptions) error {
 	for _, components := range i.installQueue {
-		for _, obj := range components.Objs() {
-			if util.IsDeploymentWithManager(obj) {
-				if err := i.waitDeploymentReady(obj, opts.WaitProviderTimeout); err != nil {
-					return err
-				}
+		if err := waitManagerDeploymentsReady(i.proxy, components, opts.WaitProviderTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitManagerDeploymentsReady waits till the manager deployments of a provider are ready.
+func waitManagerDeploymentsReady(proxy Proxy, components repository.Components, timeout time.Duration) error {
+	for _, obj := range components.Objs() {
+		if util.IsDeploymentWithManager(obj) {
+			if err := waitDeploymentReady(proxy, obj, timeout); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
 
-func (i *providerInstaller) waitDeploymentReady(deployment unstructured.Unstructured, timeout time.Duration) error {
+func waitDeploymentReady(proxy Proxy, deployment unstructured.Unstructured, timeout time.Duration) error {
 	return wait.Poll(100*time.Millisecond, timeout, func() (bool, error) {
-		c, err := i.proxy.NewClient()
+		c, err := proxy.NewClient()
 		if err != nil {
 			return false, err
 		}
@@ -269,6 +280,10 @@ func (i *providerInstaller) Images() []string {
 	return ret.List()
 }
 
+func (i *providerInstaller) Components() []repository.Components {
+	return i.installQueue
+}
+
 func newProviderInstaller(configClient config.Client, repositoryClientFactory RepositoryClientFactory, proxy Proxy, providerMetadata InventoryClient, providerComponents ComponentsClient) *providerInstaller {
 	return &providerInstaller{
 		configClient:            configClient,
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// MoveGraph describes the objects that are transferred by move, together with the relations between them.
+type MoveGraph struct {
+	// Nodes are the objects transferred by move, sorted by move order.
+	Nodes []MoveGraphNode `json:"nodes"`
+}
+
+// MoveGraphNode describes an object transferred by move.
+type MoveGraphNode struct {
+	// ID uniquely identifies the node in the MoveGraph.
+	ID string `json:"id"`
+
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	// MoveGroup is the index of the group of objects the node is moved with; groups are moved in order,
+	// so an object is always created in the target cluster after its owners.
+	MoveGroup int `json:"moveGroup"`
+
+	// Owners are the IDs of the nodes owning the current node via OwnerReferences.
+	Owners []string `json:"owners,omitempty"`
+
+	// SoftOwners are the IDs of the nodes owning the current node without an explicit OwnerReference,
+	// e.g. a Cluster soft-owning secrets via a naming convention.
+	SoftOwners []string `json:"softOwners,omitempty"`
+
+	// KeepInSource is true if the object is not deleted from the source cluster after move, e.g. because it is
+	// a global object or it is shared with Clusters not included in the move.
+	KeepInSource bool `json:"keepInSource,omitempty"`
+}
+
+// DOT returns the MoveGraph in the Graphviz DOT language, with edges going from owners to the objects they own;
+// edges for soft ownership are dashed.
+func (g *MoveGraph) DOT() []byte {
+	b := &bytes.Buffer{}
+	fmt.Fprintln(b, "digraph move {")
+	fmt.Fprintln(b, "  rankdir=LR;")
+	fmt.Fprintln(b, "  node [shape=box];")
+	for _, n := range g.Nodes {
+		label := fmt.Sprintf("%s\\n%s", n.Kind, n.Name)
+		if n.Namespace != "" {
+			label = fmt.Sprintf("%s\\n%s/%s", n.Kind, n.Namespace, n.Name)
+		}
+		style := ""
+		if n.KeepInSource {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(b, "  %q [label=%q%s];\n", n.ID, label, style)
+	}
+	for _, n := range g.Nodes {
+		for _, owner := range n.Owners {
+			fmt.Fprintf(b, "  %q -> %q;\n", owner, n.ID)
+		}
+		for _, owner := range n.SoftOwners {
+			fmt.Fprintf(b, "  %q -> %q [style=dashed];\n", owner, n.ID)
+		}
+	}
+	fmt.Fprintln(b, "}")
+	return b.Bytes()
+}
+
+// newMoveGraph returns the MoveGraph for a move sequence.
+func newMoveGraph(moveSequence *moveSequence) *MoveGraph {
+	graph := &MoveGraph{
+		Nodes: []MoveGraphNode{},
+	}
+
+	for groupIndex, group := range moveSequence.groups {
+		groupNodes := []MoveGraphNode{}
+		for _, n := range group {
+			graphNode := MoveGraphNode{
+				ID:           n.getID(),
+				APIVersion:   n.identity.APIVersion,
+				Kind:         n.identity.Kind,
+				Namespace:    n.identity.Namespace,
+				Name:         n.identity.Name,
+				MoveGroup:    groupIndex,
+				KeepInSource: n.isGlobal || n.isGlobalHierarchy || n.shouldNotDelete,
+			}
+			for owner := range n.owners {
+				graphNode.Owners = append(graphNode.Owners, owner.getID())
+			}
+			for owner := range n.softOwners {
+				graphNode.SoftOwners = append(graphNode.SoftOwners, owner.getID())
+			}
+			sort.Strings(graphNode.Owners)
+			sort.Strings(graphNode.SoftOwners)
+			groupNodes = append(groupNodes, graphNode)
+		}
+
+		// Sort the nodes in a group, so the output is stable.
+		sort.Slice(groupNodes, func(i, j int) bool {
+			return groupNodes[i].ID < groupNodes[j].ID
+		})
+		graph.Nodes = append(graph.Nodes, groupNodes...)
+	}
+
+	return graph
+}
@@ -27,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -41,11 +42,46 @@ import (
 // ObjectMover defines methods for moving Cluster API objects to another management cluster.
 type ObjectMover interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-	Move(namespace string, toCluster Client, dryRun bool) error
+	// If the filter is not empty, only the Clusters matching the filter and the objects belonging to them are moved.
+	Move(namespace string, toCluster Client, dryRun bool, filter ClusterFilter) error
+	// Graph returns the graph of the Cluster API objects that would be moved, without pausing or moving anything.
+	Graph(namespace string, filter ClusterFilter) (*MoveGraph, error)
 	// Backup saves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 	Backup(namespace string, directory string) error
 	// Restore restores all the Cluster API objects existing in a configured directory to a target management cluster.
 	Restore(toCluster Client, directory string) error
+	// ToDirectory saves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a directory,
+	// so they can be moved to a target management cluster using FromDirectory; Clusters are left paused in the source management cluster.
+	// If the filter is not empty, only the Clusters matching the filter and the objects belonging to them are saved.
+	ToDirectory(namespace string, directory string, filter ClusterFilter) error
+	// FromDirectory creates all the Cluster API objects saved in a directory by ToDirectory in a target management cluster.
+	FromDirectory(toCluster Client, directory string) error
+}
+
+// ClusterFilter defines criteria for selecting the Clusters to be moved.
+// A Cluster is selected if it matches all the criteria that are set; an empty ClusterFilter selects all the Clusters.
+type ClusterFilter struct {
+	// Names of the Clusters to be moved.
+	Names []string
+
+	// Selector is a label selector for the Clusters to be moved.
+	Selector labels.Selector
+}
+
+// IsEmpty returns true if the ClusterFilter does not define any criteria.
+func (f ClusterFilter) IsEmpty() bool {
+	return len(f.Names) == 0 && (f.Selector == nil || f.Selector.Empty())
+}
+
+// matches returns true if the Cluster matches all the criteria defined in the ClusterFilter.
+func (f ClusterFilter) matches(cluster *clusterv1.Cluster) bool {
+	if len(f.Names) > 0 && !sets.NewString(f.Names...).Has(cluster.Name) {
+		return false
+	}
+	if f.Selector != nil && !f.Selector.Matches(labels.Set(cluster.Labels)) {
+		return false
+	}
+	return true
 }
 
 // objectMover implements the ObjectMover interface.
@@ -58,7 +94,7 @@ type objectMover struct {
 // ensure objectMover implements the ObjectMover interface.
 var _ ObjectMover = &objectMover{}
 
-func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) error {
+func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool, filter ClusterFilter) error {
 	log := logf.Log
 	log.Info("Performing move...")
 	o.dryRun = dryRun
@@ -75,7 +111,7 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) erro
 		}
 	}
 
-	objectGraph, err := o.getObjectGraph(namespace)
+	objectGraph, err := o.getObjectGraph(namespace, filter)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
@@ -89,11 +125,23 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) erro
 	return o.move(objectGraph, proxy)
 }
 
+func (o *objectMover) Graph(namespace string, filter ClusterFilter) (*MoveGraph, error) {
+	// Computing the graph does not change anything in the source cluster, so it is always considered as a dry run.
+	o.dryRun = true
+
+	objectGraph, err := o.getObjectGraph(namespace, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object graph")
+	}
+
+	return newMoveGraph(getMoveSequence(objectGraph)), nil
+}
+
 func (o *objectMover) Backup(namespace string, directory string) error {
 	log := logf.Log
 	log.Info("Performing backup...")
 
-	objectGraph, err := o.getObjectGraph(namespace)
+	objectGraph, err := o.getObjectGraph(namespace, ClusterFilter{})
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
@@ -105,6 +153,37 @@ func (o *objectMover) Restore(toCluster Client, directory string) error {
 	log := logf.Log
 	log.Info("Performing restore...")
 
+	return o.fromDirectory(toCluster, directory)
+}
+
+func (o *objectMover) ToDirectory(namespace string, directory string, filter ClusterFilter) error {
+	log := logf.Log
+	log.Info("Performing move to directory...")
+
+	objectGraph, err := o.getObjectGraph(namespace, filter)
+	if err != nil {
+		return errors.Wrap(err, "failed to get object graph")
+	}
+
+	if err := o.toDirectory(objectGraph, directory); err != nil {
+		return err
+	}
+
+	// Clusters are not resumed, because they are going to be reconciled by the target management cluster once the objects
+	// are created there using FromDirectory.
+	log.Info("Clusters are left paused in the source management cluster; run clusterctl move --from-directory to complete the move")
+	return nil
+}
+
+func (o *objectMover) FromDirectory(toCluster Client, directory string) error {
+	log := logf.Log
+	log.Info("Performing move from directory...")
+
+	return o.fromDirectory(toCluster, directory)
+}
+
+// fromDirectory creates in the target management cluster the objects saved in a directory.
+func (o *objectMover) fromDirectory(toCluster Client, directory string) error {
 	// Build an empty object graph used for the restore sequence not tied to a specific namespace
 	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
 
@@ -149,6 +228,11 @@ func (o *objectMover) filesToObjs(dir string) ([]unstructured.Unstructured, erro
 
 	rawYAMLs := make([][]byte, 0)
 	for i := range files {
+		// Skip directories, given that backup saves all the objects as files in the top level directory.
+		if files[i].IsDir() {
+			continue
+		}
+
 		path := filepath.Clean(filepath.Join(dir, files[i].Name()))
 
 		byObj, err := ioutil.ReadFile(path)
@@ -169,7 +253,7 @@ func (o *objectMover) filesToObjs(dir string) ([]unstructured.Unstructured, erro
 	return objs, nil
 }
 
-func (o *objectMover) getObjectGraph(namespace string) (*objectGraph, error) {
+func (o *objectMover) getObjectGraph(namespace string, filter ClusterFilter) (*objectGraph, error) {
 	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
 
 	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
@@ -185,6 +269,11 @@ func (o *objectMover) getObjectGraph(namespace string) (*objectGraph, error) {
 		return nil, errors.Wrap(err, "failed to discover the object graph")
 	}
 
+	// Restricts the object graph to the selected Clusters, if any.
+	if err := objectGraph.filterClusters(filter); err != nil {
+		return nil, errors.Wrap(err, "failed to filter the Clusters to be moved")
+	}
+
 	// Checks if Cluster API has already completed the provisioning of the infrastructure for the objects involved in the move/backup operation.
 	// This is required because if the infrastructure is provisioned, then we can reasonably assume that the objects we are moving/backing up are
 	// not currently waiting for long-running reconciliation loops, and so we can safely rely on the pause field on the Cluster object
@@ -349,8 +438,26 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 func (o *objectMover) backup(graph *objectGraph, directory string) error {
 	log := logf.Log
 
+	if err := o.toDirectory(graph, directory); err != nil {
+		return err
+	}
+
+	// Reset the pause field on the Cluster object in the source management cluster, so the controllers start reconciling it.
+	log.V(1).Info("Resuming the source cluster")
+	return setClusterPause(o.fromProxy, graph.getClusters(), false, o.dryRun)
+}
+
+// toDirectory pauses the Clusters in the source management cluster and saves all the objects in the graph to a directory.
+func (o *objectMover) toDirectory(graph *objectGraph, directory string) error {
+	log := logf.Log
+
 	clusters := graph.getClusters()
-	log.Info("Starting backup of Cluster API objects", "Clusters", len(clusters))
+	log.Info("Saving Cluster API objects", "Clusters", len(clusters))
+
+	// Ensure the directory where to save the objects exists.
+	if err := os.MkdirAll(directory, 0750); err != nil {
+		return errors.Wrapf(err, "failed to create backup directory %s", directory)
+	}
 
 	// Sets the pause field on the Cluster object in the source management cluster, so the controllers stop reconciling it.
 	log.V(1).Info("Pausing the source cluster")
@@ -372,10 +479,7 @@ func (o *objectMover) backup(graph *objectGraph, directory string) error {
 			return err
 		}
 	}
-
-	// Reset the pause field on the Cluster object in the target management cluster, so the controllers start reconciling it.
-	log.V(1).Info("Resuming the source cluster")
-	return setClusterPause(o.fromProxy, clusters, false, o.dryRun)
+	return nil
 }
 
 func (o *objectMover) restore(graph *objectGraph, toProxy Proxy) error {
@@ -869,6 +973,10 @@ func (o *objectMover) restoreTargetObject(nodeToCreate *node, toProxy Proxy) err
 	// New objects cannot have a specified resource version. Clear it out.
 	obj.SetResourceVersion("")
 
+	// The UID of the object in the source management cluster can't be reused; a new UID is assigned by the target
+	// management cluster, and the OwnerReferences are rebuilt using the new UIDs of the owners.
+	obj.SetUID("")
+
 	// Removes current OwnerReferences
 	obj.SetOwnerReferences(nil)
 
@@ -945,6 +1053,13 @@ func (o *objectMover) deleteSourceObject(nodeToDelete *node) error {
 		return nil
 	}
 
+	// Don't delete nodes shared with Clusters not included in the move (e.g. a ClusterClass used by other Clusters).
+	if nodeToDelete.shouldNotDelete {
+		log := logf.Log
+		log.V(1).Info("Keeping shared object in the source cluster", nodeToDelete.identity.Kind, nodeToDelete.identity.Name, "Namespace", nodeToDelete.identity.Namespace)
+		return nil
+	}
+
 	log := logf.Log
 	log.V(1).Info("Deleting", nodeToDelete.identity.Kind, nodeToDelete.identity.Name, "Namespace", nodeToDelete.identity.Namespace)
 
@@ -30,6 +30,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
@@ -750,6 +751,9 @@ func Test_objectMover_backup(t *testing.T) {
 			}
 			defer os.RemoveAll(dir)
 
+			// backup to a directory which does not exist yet, so it is created by backup
+			dir = filepath.Join(dir, "backup")
+
 			err = mover.backup(graph, dir)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
@@ -799,6 +803,60 @@ func Test_objectMover_backup(t *testing.T) {
 	}
 }
 
+func Test_objectMover_toDirectory(t *testing.T) {
+	for _, tt := range backupRestoreTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+
+			// Get all the types to be considered for discovery
+			g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+			// trigger discovery the content of the source cluster
+			g.Expect(graph.Discovery("")).To(Succeed())
+
+			mover := objectMover{
+				fromProxy: graph.proxy,
+			}
+
+			dir, err := ioutil.TempDir("/tmp", "cluster-api")
+			if err != nil {
+				t.Error(err)
+			}
+			defer os.RemoveAll(dir)
+
+			err = mover.toDirectory(graph, dir)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// check that all the objects are stored in the directory
+			files, err := ioutil.ReadDir(dir)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(files).To(HaveLen(len(graph.uidToNode)))
+
+			// check that the Clusters are left paused in the source cluster, so they are not reconciled by two management clusters
+			csFrom, err := graph.proxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, cluster := range graph.getClusters() {
+				clusterObj := &clusterv1.Cluster{}
+				key := client.ObjectKey{
+					Namespace: cluster.identity.Namespace,
+					Name:      cluster.identity.Name,
+				}
+				g.Expect(csFrom.Get(ctx, key, clusterObj)).To(Succeed())
+				g.Expect(clusterObj.Spec.Paused).To(BeTrue())
+			}
+		})
+	}
+}
+
 func Test_objectMover_filesToObjs(t *testing.T) {
 	// NB. we are testing the move and move sequence using the same set of moveTests, but checking the results at different stages of the move process
 	for _, tt := range backupRestoreTests {
@@ -823,6 +881,9 @@ func Test_objectMover_filesToObjs(t *testing.T) {
 				g.Expect(file.Close()).To(Succeed())
 			}
 
+			// sub directories are ignored
+			g.Expect(os.Mkdir(filepath.Join(dir, "subdir"), 0750)).To(Succeed())
+
 			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
 			graph := getObjectGraphWithObjs(tt.fields.objs)
 
@@ -932,6 +993,19 @@ func Test_objectMover_restore(t *testing.T) {
 					t.Errorf("error = %v when checking for %v created in target cluster", err, key)
 					continue
 				}
+
+				// owner references are rebuilt using the UIDs of the owners in the target cluster
+				g.Expect(oTo.GetOwnerReferences()).To(HaveLen(len(node.owners)))
+				for _, ownerRef := range oTo.GetOwnerReferences() {
+					found := false
+					for owner := range node.owners {
+						if owner.identity.Kind == ownerRef.Kind && owner.identity.Name == ownerRef.Name {
+							g.Expect(ownerRef.UID).To(Equal(owner.newUID))
+							found = true
+						}
+					}
+					g.Expect(found).To(BeTrue(), "unexpected owner reference %v for %v", ownerRef, key)
+				}
 			}
 		})
 	}
@@ -1115,6 +1189,191 @@ func Test_objectMover_move(t *testing.T) {
 	}
 }
 
+func Test_objectMover_move_withFilter(t *testing.T) {
+	// uid returns the UID assigned to test objects, see test.setUID.
+	uid := func(gvk, namespace, name string) string {
+		return fmt.Sprintf("%s, %s/%s", gvk, namespace, name)
+	}
+	clusterObjs := func(namespace, name string) []string {
+		return []string{
+			uid("cluster.x-k8s.io/v1beta1, Kind=Cluster", namespace, name),
+			uid("infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster", namespace, name),
+			uid("/v1, Kind=Secret", namespace, name+"-ca"),
+			uid("/v1, Kind=Secret", namespace, name+"-kubeconfig"),
+		}
+	}
+	clusterClassObjs := func(namespace, name string) []string {
+		return []string{
+			uid("cluster.x-k8s.io/v1beta1, Kind=ClusterClass", namespace, name),
+			uid("infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureMachineTemplate", namespace, name+"-worker"),
+			uid("bootstrap.cluster.x-k8s.io/v1beta1, Kind=GenericBootstrapConfigTemplate", namespace, name+"-worker"),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		filter     ClusterFilter
+		wantMoved  []string // objects created in the target cluster and deleted from the source cluster.
+		wantCopied []string // objects created in the target cluster and kept in the source cluster.
+		wantErr    bool
+	}{
+		{
+			name: "Move a Cluster selected by name",
+			objs: func() []client.Object {
+				objs := []client.Object{}
+				objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+				return objs
+			}(),
+			filter:    ClusterFilter{Names: []string{"foo"}},
+			wantMoved: clusterObjs("ns1", "foo"),
+		},
+		{
+			name: "Move Clusters selected by label",
+			objs: func() []client.Object {
+				objs := []client.Object{}
+				objs = append(objs, test.NewFakeCluster("ns1", "foo").WithLabels(map[string]string{"env": "dev"}).Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "bar").WithLabels(map[string]string{"env": "prod"}).Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "baz").WithLabels(map[string]string{"env": "dev"}).Objs()...)
+				return objs
+			}(),
+			filter:    ClusterFilter{Selector: labels.SelectorFromSet(labels.Set{"env": "dev"})},
+			wantMoved: append(clusterObjs("ns1", "foo"), clusterObjs("ns1", "baz")...),
+		},
+		{
+			name: "Move a ClusterClass used only by the selected Cluster",
+			objs: func() []client.Object {
+				objs := []client.Object{}
+				objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "foo").WithTopologyClass("class1").Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+				return objs
+			}(),
+			filter:    ClusterFilter{Names: []string{"foo"}},
+			wantMoved: append(clusterObjs("ns1", "foo"), clusterClassObjs("ns1", "class1")...),
+		},
+		{
+			name: "Copy a ClusterClass shared with a Cluster not selected",
+			objs: func() []client.Object {
+				objs := []client.Object{}
+				objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "foo").WithTopologyClass("class1").Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "bar").WithTopologyClass("class1").Objs()...)
+				return objs
+			}(),
+			filter:     ClusterFilter{Names: []string{"foo"}},
+			wantMoved:  clusterObjs("ns1", "foo"),
+			wantCopied: clusterClassObjs("ns1", "class1"),
+		},
+		{
+			name: "Copy a ClusterResourceSet applied to a Cluster not selected",
+			objs: func() []client.Object {
+				objs := []client.Object{}
+				objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+				objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+				objs = append(objs, test.NewFakeClusterResourceSet("ns1", "crs1").
+					WithSecret("resource-s1").
+					WithConfigMap("resource-c1").
+					ApplyToCluster(test.SelectClusterObj(objs, "ns1", "foo")).
+					ApplyToCluster(test.SelectClusterObj(objs, "ns1", "bar")).
+					Objs()...)
+				return objs
+			}(),
+			filter: ClusterFilter{Names: []string{"foo"}},
+			wantMoved: append(clusterObjs("ns1", "foo"),
+				uid("addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSetBinding", "ns1", "foo"),
+			),
+			wantCopied: []string{
+				uid("addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSet", "ns1", "crs1"),
+				uid("/v1, Kind=Secret", "ns1", "resource-s1"),
+				uid("/v1, Kind=ConfigMap", "ns1", "resource-c1"),
+			},
+		},
+		{
+			name: "Fails if a selected Cluster does not exist",
+			objs: func() []client.Object {
+				objs := []client.Object{}
+				objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+				return objs
+			}(),
+			filter:  ClusterFilter{Names: []string{"foo", "bar"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+			graph := getObjectGraphWithObjs(tt.objs)
+
+			// Get all the types to be considered for discovery
+			g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+			// trigger discovery the content of the source cluster
+			g.Expect(graph.Discovery("")).To(Succeed())
+
+			// restrict the graph to the selected Clusters
+			err := graph.filterClusters(tt.filter)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// gets a fakeProxy to an empty cluster with all the required CRDs
+			toProxy := getFakeProxyWithCRDs()
+
+			// Run move
+			mover := objectMover{
+				fromProxy: graph.proxy,
+			}
+			g.Expect(mover.move(graph, toProxy)).To(Succeed())
+
+			csFrom, err := graph.proxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			csTo, err := toProxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, obj := range tt.objs {
+				key := client.ObjectKeyFromObject(obj)
+				objUID := string(obj.GetUID())
+
+				oFrom := &unstructured.Unstructured{}
+				oFrom.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+				errFrom := csFrom.Get(ctx, key, oFrom)
+
+				oTo := &unstructured.Unstructured{}
+				oTo.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+				errTo := csTo.Get(ctx, key, oTo)
+
+				switch {
+				case containsString(tt.wantMoved, objUID):
+					g.Expect(apierrors.IsNotFound(errFrom)).To(BeTrue(), "%s should be deleted from the source cluster", objUID)
+					g.Expect(errTo).NotTo(HaveOccurred(), "%s should be created in the target cluster", objUID)
+				case containsString(tt.wantCopied, objUID):
+					g.Expect(errFrom).NotTo(HaveOccurred(), "%s should be kept in the source cluster", objUID)
+					g.Expect(errTo).NotTo(HaveOccurred(), "%s should be created in the target cluster", objUID)
+				default:
+					g.Expect(errFrom).NotTo(HaveOccurred(), "%s should be kept in the source cluster", objUID)
+					g.Expect(apierrors.IsNotFound(errTo)).To(BeTrue(), "%s should not be created in the target cluster", objUID)
+				}
+			}
+		})
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 func Test_objectMover_checkProvisioningCompleted(t *testing.T) {
 	type fields struct {
 		objs []client.Object
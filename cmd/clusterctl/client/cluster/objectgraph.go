@@ -26,7 +26,10 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
@@ -82,6 +85,14 @@ type node struct {
 	// restoreObject holds the object that is referenced when creating a node during restore from file.
 	// the object can then be referenced latter when restoring objects to a target management cluster
 	restoreObject *unstructured.Unstructured
+
+	// refs contains the references to other objects which are not tracked via OwnerReferences, e.g.
+	// the ClusterClass used by a Cluster with a managed topology or the templates referenced by a ClusterClass.
+	refs []corev1.ObjectReference
+
+	// shouldNotDelete is set to true if the object must be kept in the source cluster after move, because it is
+	// shared with Clusters not included in the move (e.g. a ClusterClass or a ClusterResourceSet used by other Clusters).
+	shouldNotDelete bool
 }
 
 type discoveryTypeInfo struct {
@@ -114,6 +125,26 @@ func (n *node) isSoftOwnedBy(other *node) bool {
 	return ok
 }
 
+// getTenantsOfKind returns the tenants of the node with the given GroupKind.
+func (n *node) getTenantsOfKind(groupKind schema.GroupKind) []*node {
+	tenants := []*node{}
+	for tenant := range n.tenant {
+		if tenant.identity.GroupVersionKind().GroupKind() == groupKind {
+			tenants = append(tenants, tenant)
+		}
+	}
+	return tenants
+}
+
+// getID returns a human readable identifier for the node, unique in the object graph.
+func (n *node) getID() string {
+	id := n.identity.GroupVersionKind().GroupKind().String() + "/"
+	if n.identity.Namespace != "" {
+		id += n.identity.Namespace + "/"
+	}
+	return id + n.identity.Name
+}
+
 func (n *node) getFilename() string {
 	return n.identity.Kind + "_" + n.identity.Namespace + "_" + n.identity.Name + ".yaml"
 }
@@ -142,6 +173,9 @@ func (o *objectGraph) addObj(obj *unstructured.Unstructured) {
 
 	// Process OwnerReferences; if the owner object does not exists yet, create a virtual node as a placeholder for it.
 	o.processOwnerReferences(obj, newNode)
+
+	// Process references to ClusterClasses and to ClusterClass templates.
+	processClusterClassReferences(obj, newNode)
 }
 
 // addRestoredObj adds a Kubernetes object to the object graph from file that is generated during a restore
@@ -180,6 +214,69 @@ func (o *objectGraph) processOwnerReferences(obj *unstructured.Unstructured, nod
 	}
 }
 
+// processClusterClassReferences records the references that are not tracked via OwnerReferences, i.e. the ClusterClass
+// used by a Cluster with a managed topology and the templates referenced by a ClusterClass.
+func processClusterClassReferences(obj *unstructured.Unstructured, node *node) {
+	switch obj.GroupVersionKind().GroupKind() {
+	case clusterv1.GroupVersion.WithKind("Cluster").GroupKind():
+		class, _, _ := unstructured.NestedString(obj.Object, "spec", "topology", "class")
+		if class == "" {
+			return
+		}
+		classNamespace, _, _ := unstructured.NestedString(obj.Object, "spec", "topology", "classNamespace")
+		if classNamespace == "" {
+			classNamespace = obj.GetNamespace()
+		}
+		node.refs = append(node.refs, corev1.ObjectReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "ClusterClass",
+			Namespace:  classNamespace,
+			Name:       class,
+		})
+	case clusterv1.GroupVersion.WithKind("ClusterClass").GroupKind():
+		refs := []*corev1.ObjectReference{
+			getNestedRef(obj.Object, "spec", "infrastructure", "ref"),
+			getNestedRef(obj.Object, "spec", "controlPlane", "ref"),
+			getNestedRef(obj.Object, "spec", "controlPlane", "machineInfrastructure", "ref"),
+		}
+		for _, workers := range []string{"machineDeployments", "machinePools"} {
+			classes, _, _ := unstructured.NestedSlice(obj.Object, "spec", "workers", workers)
+			for _, class := range classes {
+				classMap, ok := class.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				refs = append(refs,
+					getNestedRef(classMap, "template", "bootstrap", "ref"),
+					getNestedRef(classMap, "template", "infrastructure", "ref"),
+				)
+			}
+		}
+		for _, ref := range refs {
+			if ref == nil {
+				continue
+			}
+			if ref.Namespace == "" {
+				ref.Namespace = obj.GetNamespace()
+			}
+			node.refs = append(node.refs, *ref)
+		}
+	}
+}
+
+// getNestedRef returns the object reference at the given path, if any.
+func getNestedRef(obj map[string]interface{}, fields ...string) *corev1.ObjectReference {
+	refMap, found, err := unstructured.NestedMap(obj, fields...)
+	if err != nil || !found {
+		return nil
+	}
+	ref := &corev1.ObjectReference{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(refMap, ref); err != nil || ref.Kind == "" || ref.Name == "" {
+		return nil
+	}
+	return ref
+}
+
 // ownerToVirtualNode creates a virtual node as a placeholder for the Kubernetes owner object received in input.
 // The virtual node will be eventually converted to an actual node when the node will be visited during discovery.
 func (o *objectGraph) ownerToVirtualNode(owner metav1.OwnerReference) *node {
@@ -409,6 +506,10 @@ func (o *objectGraph) Discovery(namespace string) error {
 	// Completes the graph by setting for each node the list of tenants the node belongs to.
 	o.setTenants()
 
+	// Completes the graph by linking Clusters to the ClusterClass they use, so the ClusterClass and its templates
+	// are moved together with the Clusters.
+	o.setClusterClassTenants()
+
 	return nil
 }
 
@@ -543,6 +644,138 @@ func (o *objectGraph) setTenant(node, tenant *node, isGlobalHierarchy bool) {
 	}
 }
 
+// setClusterClassTenants links each Cluster with a managed topology to the ClusterClass it uses, and each ClusterClass to the
+// templates it references; then the Clusters are set as tenants of the ClusterClass and of its templates.
+// NOTE: The ClusterClass is set as a soft owner of the Cluster, thus ensuring the ClusterClass is moved before the Cluster.
+func (o *objectGraph) setClusterClassTenants() {
+	log := logf.Log
+	for _, cluster := range o.getClusters() {
+		for _, classRef := range cluster.refs {
+			class := o.getNodeByRef(classRef)
+			if class == nil {
+				log.V(5).Info("ClusterClass not found in the object graph, it is expected to exist in the target cluster", "ClusterClass", classRef.Name, "Namespace", classRef.Namespace, "Cluster", cluster.identity.Name)
+				continue
+			}
+
+			cluster.addSoftOwner(class)
+			class.tenant[cluster] = empty{}
+			for _, templateRef := range class.refs {
+				template := o.getNodeByRef(templateRef)
+				if template == nil {
+					continue
+				}
+				template.addSoftOwner(class)
+				o.setTenant(template, cluster, false)
+			}
+		}
+	}
+}
+
+// getNodeByRef returns the node corresponding to an object reference, if the object was observed during discovery.
+// NOTE: The version of the reference is ignored, because it can be different from the storage version used for discovery.
+func (o *objectGraph) getNodeByRef(ref corev1.ObjectReference) *node {
+	for _, node := range o.uidToNode {
+		if node.virtual {
+			continue
+		}
+		if node.identity.GroupVersionKind().GroupKind() == ref.GroupVersionKind().GroupKind() &&
+			node.identity.Namespace == ref.Namespace && node.identity.Name == ref.Name {
+			return node
+		}
+	}
+	return nil
+}
+
+// filterClusters restricts the object graph to the Clusters selected by the filter and to the objects belonging to them.
+// Objects shared between selected and non selected Clusters, e.g. a ClusterClass or a ClusterResourceSet, are kept in the graph
+// but flagged so they are not deleted from the source cluster; objects belonging only to non selected Clusters are removed from the graph.
+func (o *objectGraph) filterClusters(filter ClusterFilter) error {
+	if filter.IsEmpty() {
+		return nil
+	}
+
+	log := logf.Log
+
+	clusters := o.getClusters()
+	selected := map[*node]empty{}
+	selectedNames := sets.NewString()
+	for _, cluster := range clusters {
+		clusterObj := &clusterv1.Cluster{}
+		if err := getClusterObj(o.proxy, cluster, clusterObj); err != nil {
+			return err
+		}
+		if filter.matches(clusterObj) {
+			selected[cluster] = empty{}
+			selectedNames.Insert(cluster.identity.Name)
+		}
+	}
+
+	if missing := sets.NewString(filter.Names...).Difference(selectedNames); missing.Len() > 0 {
+		return errors.Errorf("failed to find Clusters matching the filter with name %s", strings.Join(missing.List(), ", "))
+	}
+	if len(selected) == 0 {
+		return errors.New("failed to find Clusters matching the filter")
+	}
+	log.Info("Filtering Clusters to be moved", "Selected", len(selected), "Total", len(clusters))
+
+	// Gets the Clusters each ClusterResourceSet is applied to, as recorded by the ClusterResourceSetBindings owned by both.
+	crsClusters := map[*node][]*node{}
+	for _, n := range o.getNodes() {
+		if n.identity.GroupVersionKind().GroupKind() != addonsv1.GroupVersion.WithKind("ClusterResourceSetBinding").GroupKind() {
+			continue
+		}
+		bindingClusters := n.getTenantsOfKind(clusterv1.GroupVersion.WithKind("Cluster").GroupKind())
+		for _, crs := range n.getTenantsOfKind(addonsv1.GroupVersion.WithKind("ClusterResourceSet").GroupKind()) {
+			crsClusters[crs] = append(crsClusters[crs], bindingClusters...)
+		}
+	}
+
+	for _, n := range o.getMoveNodes() {
+		// Global objects and their hierarchy are never deleted from the source cluster, so they can be always moved.
+		if n.isGlobal || n.isGlobalHierarchy {
+			continue
+		}
+
+		// Gets the Clusters the object belongs to; if the object belongs only to ClusterResourceSets, gets the Clusters
+		// the ClusterResourceSets are applied to.
+		users := map[*node]empty{}
+		for _, cluster := range n.getTenantsOfKind(clusterv1.GroupVersion.WithKind("Cluster").GroupKind()) {
+			users[cluster] = empty{}
+		}
+		if len(users) == 0 {
+			for _, crs := range n.getTenantsOfKind(addonsv1.GroupVersion.WithKind("ClusterResourceSet").GroupKind()) {
+				for _, cluster := range crsClusters[crs] {
+					users[cluster] = empty{}
+				}
+			}
+		}
+
+		// If it is not possible to determine the Clusters the object belongs to (e.g. a ClusterResourceSet not yet applied
+		// or an object labeled for force move), consider the object shared.
+		if len(users) == 0 {
+			n.shouldNotDelete = true
+			continue
+		}
+
+		selectedUsers := 0
+		for user := range users {
+			if _, ok := selected[user]; ok {
+				selectedUsers++
+			}
+		}
+
+		switch {
+		case selectedUsers == 0:
+			delete(o.uidToNode, n.identity.UID)
+		case selectedUsers < len(users):
+			log.V(5).Info("Object is shared with Clusters not selected for move, it won't be deleted from the source cluster", n.identity.Kind, n.identity.Name, "Namespace", n.identity.Namespace)
+			n.shouldNotDelete = true
+		}
+	}
+
+	return nil
+}
+
 // checkVirtualNode logs if nodes are still virtual.
 func (o *objectGraph) checkVirtualNode() {
 	log := logf.Log
@@ -1981,6 +1981,110 @@ func Test_objectGraph_setCRSTenants(t *testing.T) {
 	}
 }
 
+func Test_objectGraph_setClusterClassTenants(t *testing.T) {
+	type fields struct {
+		objs []client.Object
+	}
+	tests := []struct {
+		name              string
+		fields            fields
+		wantClusterClass  string
+		wantTenants       []string
+		wantSoftOwnedByCC []string
+	}{
+		{
+			name: "A ClusterClass used by a cluster",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster1").WithTopologyClass("class1").Objs()...)
+					return objs
+				}(),
+			},
+			wantClusterClass: "cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+			wantTenants: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+			},
+			wantSoftOwnedByCC: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",                                                  // the ClusterClass must be moved before the Cluster
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureMachineTemplate, ns1/class1-worker", // templates are referenced by the ClusterClass
+				"bootstrap.cluster.x-k8s.io/v1beta1, Kind=GenericBootstrapConfigTemplate, ns1/class1-worker",
+			},
+		},
+		{
+			name: "A ClusterClass used by two clusters",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster1").WithTopologyClass("class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster2").WithTopologyClass("class1").Objs()...)
+					return objs
+				}(),
+			},
+			wantClusterClass: "cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+			wantTenants: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster2",
+			},
+			wantSoftOwnedByCC: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster2",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureMachineTemplate, ns1/class1-worker",
+				"bootstrap.cluster.x-k8s.io/v1beta1, Kind=GenericBootstrapConfigTemplate, ns1/class1-worker",
+			},
+		},
+		{
+			name: "A ClusterClass not used by any cluster",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster1").Objs()...)
+					return objs
+				}(),
+			},
+			wantClusterClass:  "cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+			wantTenants:       []string{},
+			wantSoftOwnedByCC: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			gb, err := getDetachedObjectGraphWihObjs(tt.fields.objs)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			gb.setTenants()
+			gb.setClusterClassTenants()
+
+			class, ok := gb.uidToNode[types.UID(tt.wantClusterClass)]
+			g.Expect(ok).To(BeTrue())
+
+			gotTenants := []string{}
+			for tenant := range class.tenant {
+				gotTenants = append(gotTenants, string(tenant.identity.UID))
+			}
+			g.Expect(gotTenants).To(ConsistOf(tt.wantTenants))
+
+			gotSoftOwned := []string{}
+			for _, node := range gb.uidToNode {
+				if node.isSoftOwnedBy(class) {
+					gotSoftOwned = append(gotSoftOwned, string(node.identity.UID))
+
+					// templates get the same tenants of the ClusterClass
+					if node.identity.Kind != "Cluster" {
+						g.Expect(node.tenant).To(HaveLen(len(tt.wantTenants)))
+					}
+				}
+			}
+			g.Expect(gotSoftOwned).To(ConsistOf(tt.wantSoftOwnedByCC))
+		})
+	}
+}
+
 func Test_objectGraph_setGlobalIdentityTenants(t *testing.T) {
 	type fields struct {
 		objs []client.Object
@@ -0,0 +1,330 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ValidatingWebhook identifies a validating admission webhook.
+	ValidatingWebhook = "validating"
+	// MutatingWebhook identifies a mutating admission webhook.
+	MutatingWebhook = "mutating"
+	// ConversionWebhook identifies a CRD conversion webhook.
+	ConversionWebhook = "conversion"
+)
+
+// ProviderDescriber has methods for describing the providers installed in the management cluster.
+type ProviderDescriber interface {
+	// Describe returns, for each provider in the inventory, the installed version and contract, the status
+	// of the provider's controllers, webhooks and CRDs, and the versions the provider can be upgraded to.
+	Describe() ([]ProviderDescription, error)
+}
+
+// ProviderDescription describes a provider installed in the management cluster.
+type ProviderDescription struct {
+	clusterctlv1.Provider
+
+	// Contract is the API Version of Cluster API (contract) implemented by the installed version of the provider.
+	Contract string
+
+	// WatchedNamespaces are the namespaces watched by the provider's controllers; empty means all the namespaces.
+	WatchedNamespaces []string
+
+	// Controllers reports the status of the Deployments running the provider's controllers.
+	Controllers []ControllerStatus
+
+	// Webhooks reports the status of the admission and conversion webhooks served by the provider.
+	Webhooks []WebhookStatus
+
+	// CRDs reports the storage version of the CRDs installed by the provider.
+	CRDs []CRDStatus
+
+	// Upgrades lists the versions the provider can be upgraded to, one for each contract.
+	Upgrades []ProviderUpgrade
+
+	// UpgradesError reports why the contract and the upgrades cannot be determined, e.g. the provider repository
+	// is not reachable; it is empty if no error occurred.
+	UpgradesError string
+}
+
+// ControllerStatus defines the status of a Deployment running the controllers of a provider.
+type ControllerStatus struct {
+	Name              string
+	Replicas          int32
+	AvailableReplicas int32
+}
+
+// WebhookStatus defines the status of a webhook served by a provider.
+type WebhookStatus struct {
+	// Name of the webhook configuration, or of the CRD for conversion webhooks.
+	Name string
+
+	// Type of the webhook, i.e. validating, mutating or conversion.
+	Type string
+
+	// Ready is true if all the services serving the webhook have at least one ready endpoint.
+	Ready bool
+}
+
+// CRDStatus defines the storage versions of a CRD installed by a provider.
+type CRDStatus struct {
+	Name string
+
+	// StorageVersion is the version used for persisting new objects.
+	StorageVersion string
+
+	// StoredVersions lists all the versions objects of this CRD were ever persisted with.
+	StoredVersions []string
+}
+
+// ProviderUpgrade defines a version a provider can be upgraded to.
+type ProviderUpgrade struct {
+	Contract string
+	Version  string
+}
+
+type providerDescriber struct {
+	proxy             Proxy
+	providerInventory InventoryClient
+	providerUpgrader  *providerUpgrader
+}
+
+var _ ProviderDescriber = &providerDescriber{}
+
+func (d *providerDescriber) Describe() ([]ProviderDescription, error) {
+	providerList, err := d.providerInventory.List()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := d.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]ProviderDescription, 0, len(providerList.Items))
+	for _, provider := range providerList.Items {
+		description := ProviderDescription{
+			Provider: provider,
+		}
+
+		selector := client.MatchingLabels{clusterv1.ProviderLabelName: provider.ManifestLabel()}
+		if err := describeControllers(c, provider, selector, &description); err != nil {
+			return nil, errors.Wrapf(err, "failed to get the controllers for the %s provider", provider.InstanceName())
+		}
+		if err := describeWebhooksAndCRDs(c, selector, &description); err != nil {
+			return nil, errors.Wrapf(err, "failed to get the webhooks and CRDs for the %s provider", provider.InstanceName())
+		}
+
+		// Contract and upgrades require to read the provider repository; errors are reported in the description
+		// so the cluster-side information is still available when the repository cannot be reached.
+		if err := d.describeUpgrades(provider, &description); err != nil {
+			description.UpgradesError = err.Error()
+		}
+
+		ret = append(ret, description)
+	}
+	return ret, nil
+}
+
+// describeControllers sets the status of the provider's Deployments and the namespaces watched by the provider's controllers.
+func describeControllers(c client.Client, provider clusterctlv1.Provider, selector client.MatchingLabels, description *ProviderDescription) error {
+	deploymentList := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deploymentList, client.InNamespace(provider.Namespace), selector); err != nil {
+		return err
+	}
+
+	watchedNamespaces := map[string]struct{}{}
+	for _, deployment := range deploymentList.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		description.Controllers = append(description.Controllers, ControllerStatus{
+			Name:              deployment.Name,
+			Replicas:          replicas,
+			AvailableReplicas: deployment.Status.AvailableReplicas,
+		})
+
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if namespace := namespaceArg(container); namespace != "" {
+				watchedNamespaces[namespace] = struct{}{}
+			}
+		}
+	}
+
+	// NOTE: WatchedNamespace is deprecated, but it is still set for providers installed with older versions of clusterctl.
+	if provider.WatchedNamespace != "" {
+		watchedNamespaces[provider.WatchedNamespace] = struct{}{}
+	}
+	for namespace := range watchedNamespaces {
+		description.WatchedNamespaces = append(description.WatchedNamespaces, namespace)
+	}
+	sort.Strings(description.WatchedNamespaces)
+	return nil
+}
+
+// namespaceArg returns the value of the --namespace flag of a container, if any.
+func namespaceArg(container corev1.Container) string {
+	args := append(append([]string{}, container.Command...), container.Args...)
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "--namespace=") {
+			return strings.TrimPrefix(arg, "--namespace=")
+		}
+		if arg == "--namespace" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// describeWebhooksAndCRDs sets the status of the admission and conversion webhooks served by a provider, and the storage
+// versions of the provider's CRDs.
+func describeWebhooksAndCRDs(c client.Client, selector client.MatchingLabels, description *ProviderDescription) error {
+	validatingList := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := c.List(ctx, validatingList, selector); err != nil {
+		return err
+	}
+	for _, config := range validatingList.Items {
+		services := []*admissionregistrationv1.ServiceReference{}
+		for _, webhook := range config.Webhooks {
+			services = append(services, webhook.ClientConfig.Service)
+		}
+		ready, err := servicesReady(c, services...)
+		if err != nil {
+			return err
+		}
+		description.Webhooks = append(description.Webhooks, WebhookStatus{Name: config.Name, Type: ValidatingWebhook, Ready: ready})
+	}
+
+	mutatingList := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := c.List(ctx, mutatingList, selector); err != nil {
+		return err
+	}
+	for _, config := range mutatingList.Items {
+		services := []*admissionregistrationv1.ServiceReference{}
+		for _, webhook := range config.Webhooks {
+			services = append(services, webhook.ClientConfig.Service)
+		}
+		ready, err := servicesReady(c, services...)
+		if err != nil {
+			return err
+		}
+		description.Webhooks = append(description.Webhooks, WebhookStatus{Name: config.Name, Type: MutatingWebhook, Ready: ready})
+	}
+
+	crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := c.List(ctx, crdList, selector); err != nil {
+		return err
+	}
+	for _, crd := range crdList.Items {
+		status := CRDStatus{
+			Name:           crd.Name,
+			StoredVersions: crd.Status.StoredVersions,
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Storage {
+				status.StorageVersion = version.Name
+			}
+		}
+		description.CRDs = append(description.CRDs, status)
+
+		conversion := crd.Spec.Conversion
+		if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter ||
+			conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil || conversion.Webhook.ClientConfig.Service == nil {
+			continue
+		}
+		service := conversion.Webhook.ClientConfig.Service
+		ready, err := servicesReady(c, &admissionregistrationv1.ServiceReference{Namespace: service.Namespace, Name: service.Name})
+		if err != nil {
+			return err
+		}
+		description.Webhooks = append(description.Webhooks, WebhookStatus{Name: crd.Name, Type: ConversionWebhook, Ready: ready})
+	}
+	return nil
+}
+
+// servicesReady returns true if all the services have at least one ready endpoint.
+func servicesReady(c client.Client, services ...*admissionregistrationv1.ServiceReference) (bool, error) {
+	for _, service := range services {
+		// Webhooks can be served using an URL instead of a service; in this case readiness cannot be checked.
+		if service == nil {
+			continue
+		}
+		endpoints := &corev1.Endpoints{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: service.Namespace, Name: service.Name}, endpoints); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, errors.Wrapf(err, "failed to get the endpoints for the service %s/%s", service.Namespace, service.Name)
+		}
+		ready := false
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				ready = true
+				break
+			}
+		}
+		if !ready {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// describeUpgrades sets the contract implemented by the installed version of a provider and the versions the provider
+// can be upgraded to, according to the provider's repository.
+func (d *providerDescriber) describeUpgrades(provider clusterctlv1.Provider, description *ProviderDescription) error {
+	upgradeInfo, err := d.providerUpgrader.getUpgradeInfo(provider)
+	if err != nil {
+		return err
+	}
+
+	description.Contract = upgradeInfo.currentContract
+	for _, contract := range upgradeInfo.getContractsForUpgrade() {
+		nextVersion := upgradeInfo.getLatestNextVersion(contract)
+		if nextVersion == nil {
+			continue
+		}
+		description.Upgrades = append(description.Upgrades, ProviderUpgrade{
+			Contract: contract,
+			Version:  versionTag(nextVersion),
+		})
+	}
+	return nil
+}
+
+func newProviderDescriber(proxy Proxy, providerInventory InventoryClient, providerUpgrader *providerUpgrader) *providerDescriber {
+	return &providerDescriber{
+		proxy:             proxy,
+		providerInventory: providerInventory,
+		providerUpgrader:  providerUpgrader,
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_providerDescriber_Describe(t *testing.T) {
+	providerLabels := map[string]string{clusterv1.ProviderLabelName: "infrastructure-infra"}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: appsv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra-system", Name: "infra-controller-manager", Labels: providerLabels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "manager", Args: []string{"--leader-elect", "--namespace=ns1"}},
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+	}
+	validatingWebhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta:   metav1.TypeMeta{Kind: "ValidatingWebhookConfiguration", APIVersion: admissionregistrationv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "infra-validating-webhook-configuration", Labels: providerLabels},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name: "validation.infra",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Namespace: "infra-system", Name: "infra-webhook-service"},
+				},
+			},
+		},
+	}
+	endpoints := &corev1.Endpoints{
+		TypeMeta:   metav1.TypeMeta{Kind: "Endpoints", APIVersion: corev1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra-system", Name: "infra-webhook-service"},
+		Subsets: []corev1.EndpointSubset{
+			{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+		},
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{Kind: "CustomResourceDefinition", APIVersion: apiextensionsv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: "infraclusters.infrastructure.cluster.x-k8s.io", Labels: providerLabels},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha4", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+			Conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						// this service does not have endpoints, so the conversion webhook is not ready
+						Service: &apiextensionsv1.ServiceReference{Namespace: "infra-system", Name: "infra-conversion-service"},
+					},
+				},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			StoredVersions: []string{"v1alpha4", "v1beta1"},
+		},
+	}
+
+	infraRepository := repository.NewMemoryRepository().
+		WithVersions("v2.0.0", "v2.0.1").
+		WithMetadata("v2.0.1", &clusterctlv1.Metadata{
+			ReleaseSeries: []clusterctlv1.ReleaseSeries{
+				{Major: 2, Minor: 0, Contract: test.CurrentCAPIContract},
+			},
+		})
+
+	tests := []struct {
+		name          string
+		repositoryErr error
+		want          ProviderDescription
+	}{
+		{
+			name: "describes the provider",
+			want: ProviderDescription{
+				Provider:          fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+				Contract:          test.CurrentCAPIContract,
+				WatchedNamespaces: []string{"ns1"},
+				Controllers: []ControllerStatus{
+					{Name: "infra-controller-manager", Replicas: 2, AvailableReplicas: 1},
+				},
+				Webhooks: []WebhookStatus{
+					{Name: "infra-validating-webhook-configuration", Type: ValidatingWebhook, Ready: true},
+					{Name: "infraclusters.infrastructure.cluster.x-k8s.io", Type: ConversionWebhook, Ready: false},
+				},
+				CRDs: []CRDStatus{
+					{Name: "infraclusters.infrastructure.cluster.x-k8s.io", StorageVersion: "v1beta1", StoredVersions: []string{"v1alpha4", "v1beta1"}},
+				},
+				Upgrades: []ProviderUpgrade{
+					{Contract: test.CurrentCAPIContract, Version: "v2.0.1"},
+				},
+			},
+		},
+		{
+			name:          "reports the error if the provider repository cannot be read",
+			repositoryErr: errors.New("repository not reachable"),
+			want: ProviderDescription{
+				Provider:          fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
+				WatchedNamespaces: []string{"ns1"},
+				Controllers: []ControllerStatus{
+					{Name: "infra-controller-manager", Replicas: 2, AvailableReplicas: 1},
+				},
+				Webhooks: []WebhookStatus{
+					{Name: "infra-validating-webhook-configuration", Type: ValidatingWebhook, Ready: true},
+					{Name: "infraclusters.infrastructure.cluster.x-k8s.io", Type: ConversionWebhook, Ready: false},
+				},
+				CRDs: []CRDStatus{
+					{Name: "infraclusters.infrastructure.cluster.x-k8s.io", StorageVersion: "v1beta1", StoredVersions: []string{"v1alpha4", "v1beta1"}},
+				},
+				UpgradesError: "repository not reachable",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := test.NewFakeReader().
+				WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com")
+			configClient, _ := config.New("", config.InjectReader(reader))
+
+			proxy := test.NewFakeProxy().
+				WithProviderInventory("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system").
+				WithObjs(deployment, validatingWebhook, endpoints, crd)
+
+			inventory := newInventoryClient(proxy, nil)
+			upgrader := &providerUpgrader{
+				configClient: configClient,
+				repositoryClientFactory: func(provider config.Provider, configClient config.Client, options ...repository.Option) (repository.Client, error) {
+					if tt.repositoryErr != nil {
+						return nil, tt.repositoryErr
+					}
+					return repository.New(provider, configClient, repository.InjectRepository(infraRepository))
+				},
+				providerInventory: inventory,
+			}
+
+			got, err := newProviderDescriber(proxy, inventory, upgrader).Describe()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(HaveLen(1))
+			g.Expect(got[0]).To(Equal(tt.want))
+		})
+	}
+}
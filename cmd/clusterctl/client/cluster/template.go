@@ -19,15 +19,19 @@ package cluster
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"unicode"
 
 	"github.com/google/go-github/v33/github"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
@@ -41,6 +45,10 @@ type TemplateClient interface {
 
 	// GetFromURL returns a workload cluster template from the given URL.
 	GetFromURL(templateURL, targetNamespace string, skipTemplateProcess bool) (repository.Template, error)
+
+	// GetFromClusterClass returns a workload cluster template defining a Cluster with a managed topology
+	// using the given ClusterClass.
+	GetFromClusterClass(classNamespace, className, targetNamespace string, skipTemplateProcess bool) (repository.Template, error)
 }
 
 // templateClient implements TemplateClient.
@@ -127,6 +135,135 @@ func (t *templateClient) GetFromURL(templateURL, targetNamespace string, skipTem
 	})
 }
 
+func (t *templateClient) GetFromClusterClass(classNamespace, className, targetNamespace string, skipTemplateProcess bool) (repository.Template, error) {
+	if classNamespace == "" {
+		return nil, errors.New("invalid GetFromClusterClass operation: missing classNamespace value")
+	}
+	if className == "" {
+		return nil, errors.New("invalid GetFromClusterClass operation: missing className value")
+	}
+
+	c, err := t.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterClass := &clusterv1.ClusterClass{}
+	key := client.ObjectKey{
+		Namespace: classNamespace,
+		Name:      className,
+	}
+
+	if err := c.Get(ctx, key, clusterClass); err != nil {
+		return nil, errors.Wrapf(err, "error reading ClusterClass %s/%s", classNamespace, className)
+	}
+
+	content := clusterClassTemplate(clusterClass, targetNamespace, t.configClient.Variables(), skipTemplateProcess)
+
+	// NOTE: The template generated from the ClusterClass uses the ${VAR} syntax, so it is always processed
+	// with the SimpleProcessor, no matter of the processor configured for the other template sources.
+	return repository.NewTemplate(repository.TemplateInput{
+		RawArtifact:           content,
+		ConfigVariablesClient: t.configClient.Variables(),
+		Processor:             yaml.NewSimpleProcessor(),
+		TargetNamespace:       targetNamespace,
+		SkipTemplateProcess:   skipTemplateProcess,
+	})
+}
+
+// clusterClassTemplate returns a template for a Cluster with a managed topology using the given ClusterClass.
+// The template uses the well-known CLUSTER_NAME, KUBERNETES_VERSION, CONTROL_PLANE_MACHINE_COUNT and
+// WORKER_MACHINE_COUNT variables, plus a variable for each one of the variables defined in the ClusterClass,
+// e.g. the imageRepository variable is set from the IMAGE_REPOSITORY variable.
+// Variables which are not required, or which have a default value in the ClusterClass, are added to the
+// Cluster only if a value is set; when the template is not processed they are all added, so it is possible
+// to list them.
+func clusterClassTemplate(clusterClass *clusterv1.ClusterClass, targetNamespace string, variablesClient config.VariablesClient, skipTemplateProcess bool) []byte {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "apiVersion: %s\n", clusterv1.GroupVersion.String())
+	fmt.Fprintln(b, "kind: Cluster")
+	fmt.Fprintln(b, "metadata:")
+	fmt.Fprintln(b, "  name: ${CLUSTER_NAME}")
+	fmt.Fprintln(b, "spec:")
+	fmt.Fprintln(b, "  topology:")
+	fmt.Fprintf(b, "    class: %s\n", clusterClass.Name)
+	if clusterClass.Namespace != targetNamespace {
+		fmt.Fprintf(b, "    classNamespace: %s\n", clusterClass.Namespace)
+	}
+	fmt.Fprintln(b, "    version: ${KUBERNETES_VERSION}")
+	fmt.Fprintln(b, "    controlPlane:")
+	fmt.Fprintln(b, "      replicas: ${CONTROL_PLANE_MACHINE_COUNT}")
+
+	if len(clusterClass.Spec.Workers.MachineDeployments) > 0 {
+		fmt.Fprintln(b, "    workers:")
+		fmt.Fprintln(b, "      machineDeployments:")
+		for _, md := range clusterClass.Spec.Workers.MachineDeployments {
+			fmt.Fprintf(b, "      - class: %s\n", md.Class)
+			fmt.Fprintf(b, "        name: %s\n", md.Class)
+			fmt.Fprintln(b, "        replicas: ${WORKER_MACHINE_COUNT}")
+		}
+	}
+
+	variables := &strings.Builder{}
+	for _, variable := range clusterClass.Spec.Variables {
+		name := clusterClassVariableName(variable.Name)
+		schema := variable.Schema.OpenAPIV3Schema
+		value := fmt.Sprintf("${%s}", name)
+		if !variable.Required || schema.Default != nil {
+			if skipTemplateProcess {
+				value = fmt.Sprintf("${%s:=%s}", name, clusterClassVariableDefault(schema.Default))
+			} else if _, err := variablesClient.Get(name); err != nil {
+				continue
+			}
+		}
+		fmt.Fprintf(variables, "    - name: %s\n", variable.Name)
+		fmt.Fprintf(variables, "      value: %s\n", value)
+	}
+	if variables.Len() > 0 {
+		fmt.Fprintln(b, "    variables:")
+		b.WriteString(variables.String())
+	}
+
+	return []byte(b.String())
+}
+
+// clusterClassVariableName returns the name of the clusterctl variable used to set the value
+// of a ClusterClass variable, e.g. IMAGE_REPOSITORY for imageRepository.
+func clusterClassVariableName(name string) string {
+	runes := []rune(name)
+	b := strings.Builder{}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			b.WriteRune('_')
+			continue
+		}
+		// Add a separator at the beginning of each word, e.g. before the R in imageRepository
+		// or before the P in HTTPProxy.
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// clusterClassVariableDefault returns the default value of a ClusterClass variable, to be used when listing variables.
+// NOTE: Defaults which can't be embedded in a ${VAR:=default} expression, like e.g. objects, are reported as "default".
+func clusterClassVariableDefault(value *apiextensionsv1.JSON) string {
+	if value == nil {
+		return "null"
+	}
+	d := string(value.Raw)
+	if strings.ContainsAny(d, "{}\n") {
+		return "default"
+	}
+	return d
+}
+
 func (t *templateClient) getURLContent(templateURL string) ([]byte, error) {
 	rURL, err := url.Parse(templateURL)
 	if err != nil {
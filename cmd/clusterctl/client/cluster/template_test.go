@@ -30,6 +30,7 @@ import (
 	"github.com/google/go-github/v33/github"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
@@ -379,3 +380,146 @@ func mustParseURL(rawURL string) *url.URL {
 	}
 	return rURL
 }
+
+func Test_templateClient_GetFromClusterClass(t *testing.T) {
+	g := NewWithT(t)
+
+	configClient, err := config.New("", config.InjectReader(test.NewFakeReader().
+		WithVar("CLUSTER_NAME", "my-cluster").
+		WithVar("KUBERNETES_VERSION", "v1.22.2").
+		WithVar("CONTROL_PLANE_MACHINE_COUNT", "3").
+		WithVar("WORKER_MACHINE_COUNT", "2").
+		WithVar("IMAGE_REPOSITORY", "registry.example.com"),
+	))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	clusterClass := &clusterv1.ClusterClass{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterClass",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "my-class",
+		},
+		Spec: clusterv1.ClusterClassSpec{
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{Class: "default-worker"},
+				},
+			},
+			Variables: []clusterv1.ClusterClassVariable{
+				{
+					Name:     "imageRepository",
+					Required: true,
+					Schema:   clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"}},
+				},
+				{
+					Name:     "httpProxy",
+					Required: false,
+					Schema:   clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"}},
+				},
+			},
+		},
+	}
+
+	type args struct {
+		classNamespace      string
+		className           string
+		targetNamespace     string
+		skipTemplateProcess bool
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantVariables []string
+		wantErr       bool
+	}{
+		{
+			name: "Return template",
+			args: args{
+				classNamespace:      "ns1",
+				className:           "my-class",
+				targetNamespace:     "ns1",
+				skipTemplateProcess: false,
+			},
+			wantVariables: []string{"CLUSTER_NAME", "CONTROL_PLANE_MACHINE_COUNT", "IMAGE_REPOSITORY", "KUBERNETES_VERSION", "WORKER_MACHINE_COUNT"},
+			wantErr:       false,
+		},
+		{
+			name: "Return template listing also optional variables",
+			args: args{
+				classNamespace:      "ns1",
+				className:           "my-class",
+				targetNamespace:     "ns1",
+				skipTemplateProcess: true,
+			},
+			wantVariables: []string{"CLUSTER_NAME", "CONTROL_PLANE_MACHINE_COUNT", "HTTP_PROXY", "IMAGE_REPOSITORY", "KUBERNETES_VERSION", "WORKER_MACHINE_COUNT"},
+			wantErr:       false,
+		},
+		{
+			name: "ClusterClass does not exists",
+			args: args{
+				classNamespace:      "ns1",
+				className:           "something-else",
+				targetNamespace:     "ns1",
+				skipTemplateProcess: false,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tc := newTemplateClient(TemplateClientInput{test.NewFakeProxy().WithObjs(clusterClass), configClient, yaml.NewSimpleProcessor()})
+			got, err := tc.GetFromClusterClass(tt.args.classNamespace, tt.args.className, tt.args.targetNamespace, tt.args.skipTemplateProcess)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got.Variables()).To(Equal(tt.wantVariables))
+
+			if tt.args.skipTemplateProcess {
+				return
+			}
+
+			g.Expect(got.Objs()).To(HaveLen(1))
+			cluster := &clusterv1.Cluster{}
+			g.Expect(localScheme.Convert(&got.Objs()[0], cluster, nil)).To(Succeed())
+			g.Expect(cluster.Name).To(Equal("my-cluster"))
+			g.Expect(cluster.Namespace).To(Equal("ns1"))
+			g.Expect(cluster.Spec.Topology).ToNot(BeNil())
+			g.Expect(cluster.Spec.Topology.Class).To(Equal("my-class"))
+			g.Expect(cluster.Spec.Topology.ClassNamespace).To(BeEmpty())
+			g.Expect(cluster.Spec.Topology.Version).To(Equal("v1.22.2"))
+			g.Expect(*cluster.Spec.Topology.ControlPlane.Replicas).To(Equal(int32(3)))
+			g.Expect(cluster.Spec.Topology.Workers.MachineDeployments).To(HaveLen(1))
+			g.Expect(cluster.Spec.Topology.Workers.MachineDeployments[0].Class).To(Equal("default-worker"))
+			g.Expect(*cluster.Spec.Topology.Workers.MachineDeployments[0].Replicas).To(Equal(int32(2)))
+			g.Expect(cluster.Spec.Topology.Variables).To(HaveLen(1))
+			g.Expect(cluster.Spec.Topology.Variables[0].Name).To(Equal("imageRepository"))
+			g.Expect(string(cluster.Spec.Topology.Variables[0].Value.Raw)).To(Equal(`"registry.example.com"`))
+		})
+	}
+}
+
+func Test_clusterClassVariableName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "imageRepository", want: "IMAGE_REPOSITORY"},
+		{name: "HTTPProxy", want: "HTTP_PROXY"},
+		{name: "etcd-image-tag", want: "ETCD_IMAGE_TAG"},
+		{name: "k8s2Version", want: "K8S2_VERSION"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(clusterClassVariableName(tt.name)).To(Equal(tt.want))
+		})
+	}
+}
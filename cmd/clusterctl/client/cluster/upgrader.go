@@ -17,14 +17,22 @@ limitations under the License.
 package cluster
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ProviderUpgrader defines methods for supporting provider upgrade.
@@ -35,10 +43,27 @@ type ProviderUpgrader interface {
 	Plan() ([]UpgradePlan, error)
 
 	// ApplyPlan executes an upgrade following an UpgradePlan generated by clusterctl.
-	ApplyPlan(clusterAPIVersion string) error
+	ApplyPlan(opts UpgradeOptions, clusterAPIVersion string) error
 
 	// ApplyCustomPlan plan executes an upgrade using the UpgradeItems provided by the user.
-	ApplyCustomPlan(providersToUpgrade ...UpgradeItem) error
+	ApplyCustomPlan(opts UpgradeOptions, providersToUpgrade ...UpgradeItem) error
+
+	// PlanWorkloadClusters returns, for each workload cluster, the Kubernetes versions of the control plane and of the
+	// MachineDeployments, the next Kubernetes version supported by the installed providers and the version skew violations.
+	PlanWorkloadClusters() ([]WorkloadClusterUpgradePlan, error)
+}
+
+// UpgradeOptions defines the options used to configure an upgrade.
+type UpgradeOptions struct {
+	// WaitProviders instructs the upgrade to wait, after upgrading each provider, for the provider's controllers
+	// to be available and for the provider's conversion webhooks to be ready before upgrading the next provider.
+	WaitProviders bool
+
+	// WaitProviderTimeout sets the timeout for each provider to become ready.
+	WaitProviderTimeout time.Duration
+
+	// ContinueOnError instructs the upgrade to continue with the next providers when the upgrade of a provider fails.
+	ContinueOnError bool
 }
 
 // UpgradePlan defines a list of possible upgrade targets for a management cluster.
@@ -69,6 +94,7 @@ func (u *UpgradeItem) UpgradeRef() string {
 }
 
 type providerUpgrader struct {
+	proxy                   Proxy
 	configClient            config.Client
 	repositoryClientFactory RepositoryClientFactory
 	providerInventory       InventoryClient
@@ -139,7 +165,7 @@ func (u *providerUpgrader) Plan() ([]UpgradePlan, error) {
 	return ret, nil
 }
 
-func (u *providerUpgrader) ApplyPlan(contract string) error {
+func (u *providerUpgrader) ApplyPlan(opts UpgradeOptions, contract string) error {
 	if contract != clusterv1.GroupVersion.Version {
 		return errors.Errorf("current version of clusterctl could only upgrade to %s contract, requested %s", clusterv1.GroupVersion.Version, contract)
 	}
@@ -159,10 +185,10 @@ func (u *providerUpgrader) ApplyPlan(contract string) error {
 	}
 
 	// Do the upgrade
-	return u.doUpgrade(upgradePlan)
+	return u.doUpgrade(upgradePlan, opts)
 }
 
-func (u *providerUpgrader) ApplyCustomPlan(upgradeItems ...UpgradeItem) error {
+func (u *providerUpgrader) ApplyCustomPlan(opts UpgradeOptions, upgradeItems ...UpgradeItem) error {
 	log := logf.Log
 	log.Info("Performing upgrade...")
 
@@ -174,7 +200,7 @@ func (u *providerUpgrader) ApplyCustomPlan(upgradeItems ...UpgradeItem) error {
 	}
 
 	// Do the upgrade
-	return u.doUpgrade(upgradePlan)
+	return u.doUpgrade(upgradePlan, opts)
 }
 
 // getUpgradePlan returns the upgrade plan for a specific set of providers/contract
@@ -336,7 +362,9 @@ func (u *providerUpgrader) getUpgradeComponents(provider UpgradeItem) (repositor
 	return components, nil
 }
 
-func (u *providerUpgrader) doUpgrade(upgradePlan *UpgradePlan) error {
+func (u *providerUpgrader) doUpgrade(upgradePlan *UpgradePlan, opts UpgradeOptions) error {
+	log := logf.Log
+
 	// Check for multiple instances of the same provider if current contract is v1alpha3.
 	if upgradePlan.Contract == clusterv1.GroupVersion.Version {
 		if err := u.providerInventory.CheckSingleProviderInstance(); err != nil {
@@ -344,32 +372,26 @@ func (u *providerUpgrader) doUpgrade(upgradePlan *UpgradePlan) error {
 		}
 	}
 
+	// Upgrade the providers one at a time; if the upgrade of a provider fails, the provider is rolled back
+	// to the version installed before the upgrade.
+	var errList []error
 	for _, upgradeItem := range upgradePlan.Providers {
 		// If there is not a specified next version, skip it (we are already up-to-date).
 		if upgradeItem.NextVersion == "" {
 			continue
 		}
 
-		// Gets the provider components for the target version.
-		components, err := u.getUpgradeComponents(upgradeItem)
-		if err != nil {
-			return err
-		}
-
-		// Delete the provider, preserving CRD and namespace.
-		if err := u.providerComponents.Delete(DeleteOptions{
-			Provider:         upgradeItem.Provider,
-			IncludeNamespace: false,
-			IncludeCRDs:      false,
-		}); err != nil {
-			return err
-		}
-
-		// Install the new version of the provider components.
-		if err := installComponentsAndUpdateInventory(components, u.providerComponents, u.providerInventory); err != nil {
-			return err
+		if err := u.upgradeProvider(upgradeItem, opts); err != nil {
+			if !opts.ContinueOnError {
+				return err
+			}
+			log.Info("Failed to upgrade provider, continuing with the next providers", "Provider", upgradeItem.InstanceName(), "Error", err.Error())
+			errList = append(errList, err)
 		}
 	}
+	if len(errList) > 0 {
+		return kerrors.NewAggregate(errList)
+	}
 
 	// Delete webhook namespace since it's not needed from v1alpha4.
 	if upgradePlan.Contract == clusterv1.GroupVersion.Version {
@@ -381,8 +403,134 @@ func (u *providerUpgrader) doUpgrade(upgradePlan *UpgradePlan) error {
 	return nil
 }
 
-func newProviderUpgrader(configClient config.Client, repositoryClientFactory RepositoryClientFactory, providerInventory InventoryClient, providerComponents ComponentsClient) *providerUpgrader {
+// upgradeProvider upgrades a provider to the next version, and rolls it back to the current version if the upgrade fails.
+func (u *providerUpgrader) upgradeProvider(upgradeItem UpgradeItem, opts UpgradeOptions) error {
+	// Gets the provider components for the target version.
+	components, err := u.getUpgradeComponents(upgradeItem)
+	if err != nil {
+		return err
+	}
+
+	// Delete the provider, preserving CRD and namespace.
+	if err := u.providerComponents.Delete(DeleteOptions{
+		Provider:         upgradeItem.Provider,
+		IncludeNamespace: false,
+		IncludeCRDs:      false,
+	}); err != nil {
+		return err
+	}
+
+	// Install the new version of the provider components, and if required wait for the provider to be ready.
+	err = installComponentsAndUpdateInventory(components, u.providerComponents, u.providerInventory)
+	if err == nil {
+		err = u.waitForProviderReady(components, opts)
+	}
+	if err == nil {
+		return nil
+	}
+
+	err = errors.Wrapf(err, "failed to upgrade the %s provider to version %s", upgradeItem.InstanceName(), upgradeItem.NextVersion)
+	if rollbackErr := u.rollbackProvider(upgradeItem, opts); rollbackErr != nil {
+		return kerrors.NewAggregate([]error{err, rollbackErr})
+	}
+	return err
+}
+
+// rollbackProvider re-installs the version of a provider that was installed before a failed upgrade.
+// NB. CRDs are applied again using the definitions of the previous version, so the API versions introduced by
+// the failed upgrade are no longer served.
+func (u *providerUpgrader) rollbackProvider(upgradeItem UpgradeItem, opts UpgradeOptions) error {
+	log := logf.Log
+	log.Info("Rolling back", "Provider", upgradeItem.InstanceName(), "Version", upgradeItem.Version)
+
+	previous := upgradeItem
+	previous.NextVersion = upgradeItem.Version
+	components, err := u.getUpgradeComponents(previous)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rollback the %s provider to version %s", upgradeItem.InstanceName(), upgradeItem.Version)
+	}
+
+	// Delete what was installed by the failed upgrade, preserving CRD and namespace.
+	if err := u.providerComponents.Delete(DeleteOptions{
+		Provider:         upgradeItem.Provider,
+		IncludeNamespace: false,
+		IncludeCRDs:      false,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to rollback the %s provider to version %s", upgradeItem.InstanceName(), upgradeItem.Version)
+	}
+
+	if err := installComponentsAndUpdateInventory(components, u.providerComponents, u.providerInventory); err != nil {
+		return errors.Wrapf(err, "failed to rollback the %s provider to version %s", upgradeItem.InstanceName(), upgradeItem.Version)
+	}
+	if err := u.waitForProviderReady(components, opts); err != nil {
+		return errors.Wrapf(err, "failed to rollback the %s provider to version %s", upgradeItem.InstanceName(), upgradeItem.Version)
+	}
+	return nil
+}
+
+// waitForProviderReady waits till the manager deployments of a provider are available and till the conversion
+// webhooks for the provider's CRDs are ready.
+func (u *providerUpgrader) waitForProviderReady(components repository.Components, opts UpgradeOptions) error {
+	// If we dont have to wait for providers to be ready return early.
+	if !opts.WaitProviders {
+		return nil
+	}
+
+	log := logf.Log
+	log.Info("Waiting for provider to be available...", "Provider", components.ManifestLabel())
+
+	if err := waitManagerDeploymentsReady(u.proxy, components, opts.WaitProviderTimeout); err != nil {
+		return errors.Wrapf(err, "failed waiting for the %s provider's controllers to be available", components.ManifestLabel())
+	}
+	if err := waitConversionWebhooksReady(u.proxy, components, opts.WaitProviderTimeout); err != nil {
+		return errors.Wrapf(err, "failed waiting for the %s provider's conversion webhooks to be ready", components.ManifestLabel())
+	}
+	return nil
+}
+
+// waitConversionWebhooksReady waits till the services serving the conversion webhooks for the provider's CRDs
+// have at least one ready endpoint.
+func waitConversionWebhooksReady(proxy Proxy, components repository.Components, timeout time.Duration) error {
+	for _, obj := range components.Objs() {
+		if obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+			return errors.Wrapf(err, "failed to convert %s to a CustomResourceDefinition", obj.GetName())
+		}
+		if crd.Spec.Conversion == nil || crd.Spec.Conversion.Strategy != apiextensionsv1.WebhookConverter ||
+			crd.Spec.Conversion.Webhook == nil || crd.Spec.Conversion.Webhook.ClientConfig == nil || crd.Spec.Conversion.Webhook.ClientConfig.Service == nil {
+			continue
+		}
+
+		service := crd.Spec.Conversion.Webhook.ClientConfig.Service
+		if err := wait.Poll(100*time.Millisecond, timeout, func() (bool, error) {
+			c, err := proxy.NewClient()
+			if err != nil {
+				return false, err
+			}
+			endpoints := &corev1.Endpoints{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: service.Namespace, Name: service.Name}, endpoints); err != nil {
+				return false, nil //nolint:nilerr // endpoints are created asynchronously, so keep polling
+			}
+			for _, subset := range endpoints.Subsets {
+				if len(subset.Addresses) > 0 {
+					return true, nil
+				}
+			}
+			return false, nil
+		}); err != nil {
+			return errors.Wrapf(err, "the conversion webhook service %s/%s for %s has no ready endpoints", service.Namespace, service.Name, crd.Name)
+		}
+	}
+	return nil
+}
+
+func newProviderUpgrader(proxy Proxy, configClient config.Client, repositoryClientFactory RepositoryClientFactory, providerInventory InventoryClient, providerComponents ComponentsClient) *providerUpgrader {
 	return &providerUpgrader{
+		proxy:                   proxy,
 		configClient:            configClient,
 		repositoryClientFactory: repositoryClientFactory,
 		providerInventory:       providerInventory,
@@ -0,0 +1,271 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/version"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxKubeletVersionSkew is the number of minor versions kubelets are allowed to be older than the control plane.
+const maxKubeletVersionSkew = 2
+
+// WorkloadClusterUpgradePlan describes the Kubernetes versions of a workload cluster and its upgrade options.
+type WorkloadClusterUpgradePlan struct {
+	Namespace string
+	Name      string
+
+	// ControlPlaneVersion is the Kubernetes version of the control plane; it is empty if the
+	// version can't be determined, e.g. for Clusters without a control plane object.
+	ControlPlaneVersion string
+
+	// MachineDeployments are the MachineDeployments of the workload cluster, with their Kubernetes version.
+	MachineDeployments []MachineDeploymentVersion
+
+	// NextVersion is the Kubernetes minor version the control plane can be upgraded to, e.g. `v1.23`; it is empty
+	// if the upgrade is not supported by the installed providers or it would break the version skew policy.
+	NextVersion string
+
+	// Violations are the version skew violations detected in the workload cluster.
+	Violations []string
+}
+
+// MachineDeploymentVersion describes the Kubernetes version of a MachineDeployment.
+type MachineDeploymentVersion struct {
+	Name    string
+	Version string
+}
+
+// kubernetesVersionRange is the range of Kubernetes versions supported by the providers installed in a management cluster.
+type kubernetesVersionRange struct {
+	min *version.Version
+	max *version.Version
+}
+
+// contains returns true if the version is in the range.
+func (r kubernetesVersionRange) contains(v *version.Version) bool {
+	if r.min != nil && v.LessThan(r.min) {
+		return false
+	}
+	if r.max != nil && r.max.LessThan(v) {
+		return false
+	}
+	return true
+}
+
+func (r kubernetesVersionRange) String() string {
+	switch {
+	case r.min != nil && r.max != nil:
+		return fmt.Sprintf(">= v%s, <= v%s", r.min, r.max)
+	case r.min != nil:
+		return fmt.Sprintf(">= v%s", r.min)
+	case r.max != nil:
+		return fmt.Sprintf("<= v%s", r.max)
+	}
+	return "any"
+}
+
+func (u *providerUpgrader) PlanWorkloadClusters() ([]WorkloadClusterUpgradePlan, error) {
+	log := logf.Log
+	log.Info("Checking Kubernetes versions of workload clusters...")
+
+	supportedVersions, err := u.getSupportedKubernetesVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := u.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterList := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusterList); err != nil {
+		return nil, errors.Wrap(err, "failed to list Clusters")
+	}
+
+	ret := make([]WorkloadClusterUpgradePlan, 0, len(clusterList.Items))
+	for i := range clusterList.Items {
+		plan, err := getWorkloadClusterUpgradePlan(c, &clusterList.Items[i], supportedVersions)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *plan)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Namespace != ret[j].Namespace {
+			return ret[i].Namespace < ret[j].Namespace
+		}
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret, nil
+}
+
+// getSupportedKubernetesVersions returns the range of Kubernetes versions supported by all the providers installed in
+// the management cluster, as declared by the release series of their current version in the provider's metadata.
+func (u *providerUpgrader) getSupportedKubernetesVersions() (kubernetesVersionRange, error) {
+	ret := kubernetesVersionRange{}
+
+	providerList, err := u.providerInventory.List()
+	if err != nil {
+		return ret, err
+	}
+
+	for _, provider := range providerList.Items {
+		upgradeInfo, err := u.getUpgradeInfo(provider)
+		if err != nil {
+			return ret, err
+		}
+
+		releaseSeries := upgradeInfo.metadata.GetReleaseSeriesForVersion(upgradeInfo.currentVersion)
+		if releaseSeries == nil {
+			continue
+		}
+
+		if releaseSeries.MinKubernetesVersion != "" {
+			minVersion, err := version.ParseSemantic(releaseSeries.MinKubernetesVersion)
+			if err != nil {
+				return ret, errors.Wrapf(err, "invalid provider metadata: failed to parse minKubernetesVersion for the %s provider", provider.InstanceName())
+			}
+			if ret.min == nil || ret.min.LessThan(minVersion) {
+				ret.min = minVersion
+			}
+		}
+
+		if releaseSeries.MaxKubernetesVersion != "" {
+			maxVersion, err := version.ParseSemantic(releaseSeries.MaxKubernetesVersion)
+			if err != nil {
+				return ret, errors.Wrapf(err, "invalid provider metadata: failed to parse maxKubernetesVersion for the %s provider", provider.InstanceName())
+			}
+			if ret.max == nil || maxVersion.LessThan(ret.max) {
+				ret.max = maxVersion
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// getWorkloadClusterUpgradePlan returns the WorkloadClusterUpgradePlan for a Cluster.
+func getWorkloadClusterUpgradePlan(c client.Client, cluster *clusterv1.Cluster, supportedVersions kubernetesVersionRange) (*WorkloadClusterUpgradePlan, error) {
+	plan := &WorkloadClusterUpgradePlan{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Name,
+	}
+
+	controlPlaneVersion, err := getControlPlaneVersion(c, cluster)
+	if err != nil {
+		return nil, err
+	}
+	plan.ControlPlaneVersion = controlPlaneVersion
+
+	machineDeploymentList := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, machineDeploymentList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineDeployments for Cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	for _, md := range machineDeploymentList.Items {
+		mdVersion := MachineDeploymentVersion{Name: md.Name}
+		if md.Spec.Template.Spec.Version != nil {
+			mdVersion.Version = *md.Spec.Template.Spec.Version
+		}
+		plan.MachineDeployments = append(plan.MachineDeployments, mdVersion)
+	}
+	sort.Slice(plan.MachineDeployments, func(i, j int) bool {
+		return plan.MachineDeployments[i].Name < plan.MachineDeployments[j].Name
+	})
+
+	var controlPlaneSemVersion *version.Version
+	if plan.ControlPlaneVersion != "" {
+		controlPlaneSemVersion, err = version.ParseSemantic(plan.ControlPlaneVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse control plane version for Cluster %s/%s", cluster.Namespace, cluster.Name)
+		}
+		if !supportedVersions.contains(controlPlaneSemVersion) {
+			plan.Violations = append(plan.Violations, fmt.Sprintf("control plane version %s is not supported by the installed providers (%s)", plan.ControlPlaneVersion, supportedVersions))
+		}
+	}
+
+	// The control plane can be upgraded to the next minor only if all the kubelets are going to be within
+	// the version skew policy after the upgrade.
+	canUpgrade := controlPlaneSemVersion != nil
+	for _, md := range plan.MachineDeployments {
+		if md.Version == "" {
+			continue
+		}
+		mdSemVersion, err := version.ParseSemantic(md.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse version for MachineDeployment %s/%s", cluster.Namespace, md.Name)
+		}
+		if !supportedVersions.contains(mdSemVersion) {
+			plan.Violations = append(plan.Violations, fmt.Sprintf("MachineDeployment %s version %s is not supported by the installed providers (%s)", md.Name, md.Version, supportedVersions))
+		}
+		if controlPlaneSemVersion == nil {
+			continue
+		}
+		if controlPlaneSemVersion.LessThan(mdSemVersion) {
+			plan.Violations = append(plan.Violations, fmt.Sprintf("MachineDeployment %s version %s is newer than the control plane version %s", md.Name, md.Version, plan.ControlPlaneVersion))
+			continue
+		}
+		skew := int(controlPlaneSemVersion.Minor()) - int(mdSemVersion.Minor())
+		if controlPlaneSemVersion.Major() != mdSemVersion.Major() || skew > maxKubeletVersionSkew {
+			plan.Violations = append(plan.Violations, fmt.Sprintf("MachineDeployment %s version %s is more than %d minor versions older than the control plane version %s", md.Name, md.Version, maxKubeletVersionSkew, plan.ControlPlaneVersion))
+		}
+		if skew >= maxKubeletVersionSkew {
+			canUpgrade = false
+		}
+	}
+
+	if canUpgrade {
+		next := version.MustParseSemantic(fmt.Sprintf("v%d.%d.0", controlPlaneSemVersion.Major(), controlPlaneSemVersion.Minor()+1))
+		if supportedVersions.contains(next) {
+			plan.NextVersion = fmt.Sprintf("v%d.%d", next.Major(), next.Minor())
+		}
+	}
+
+	return plan, nil
+}
+
+// getControlPlaneVersion returns the Kubernetes version of the control plane of a Cluster.
+func getControlPlaneVersion(c client.Client, cluster *clusterv1.Cluster) (string, error) {
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get control plane for Cluster %s/%s", cluster.Namespace, cluster.Name)
+		}
+		v, _, err := unstructured.NestedString(controlPlane.Object, "spec", "version")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get version from %s %s/%s", controlPlane.GetKind(), controlPlane.GetNamespace(), controlPlane.GetName())
+		}
+		return v, nil
+	}
+
+	if cluster.Spec.Topology != nil {
+		return cluster.Spec.Topology.Version, nil
+	}
+
+	return "", nil
+}
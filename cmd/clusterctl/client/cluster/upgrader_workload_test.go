@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_providerUpgrader_getSupportedKubernetesVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	reader := test.NewFakeReader().
+		WithProvider("cluster-api", clusterctlv1.CoreProviderType, "https://somewhere.com").
+		WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com")
+	repositories := map[string]repository.Repository{
+		"cluster-api": repository.NewMemoryRepository().
+			WithVersions("v1.0.0", "v1.1.0").
+			WithMetadata("v1.1.0", &clusterctlv1.Metadata{
+				ReleaseSeries: []clusterctlv1.ReleaseSeries{
+					{Major: 1, Minor: 0, Contract: test.CurrentCAPIContract, MinKubernetesVersion: "v1.19.0", MaxKubernetesVersion: "v1.22.99"},
+					{Major: 1, Minor: 1, Contract: test.CurrentCAPIContract, MinKubernetesVersion: "v1.20.0", MaxKubernetesVersion: "v1.23.99"},
+				},
+			}),
+		"infrastructure-infra": repository.NewMemoryRepository().
+			WithVersions("v2.0.0").
+			WithMetadata("v2.0.0", &clusterctlv1.Metadata{
+				ReleaseSeries: []clusterctlv1.ReleaseSeries{
+					{Major: 2, Minor: 0, Contract: test.CurrentCAPIContract, MinKubernetesVersion: "v1.20.0"},
+				},
+			}),
+	}
+	proxy := test.NewFakeProxy().
+		WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system").
+		WithProviderInventory("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system")
+
+	configClient, _ := config.New("", config.InjectReader(reader))
+
+	u := &providerUpgrader{
+		configClient: configClient,
+		repositoryClientFactory: func(provider config.Provider, configClient config.Client, options ...repository.Option) (repository.Client, error) {
+			return repository.New(provider, configClient, repository.InjectRepository(repositories[provider.ManifestLabel()]))
+		},
+		providerInventory: newInventoryClient(proxy, nil),
+	}
+
+	got, err := u.getSupportedKubernetesVersions()
+	g.Expect(err).NotTo(HaveOccurred())
+	// The range is the intersection of the ranges for the release series of the current version of each provider.
+	g.Expect(got.String()).To(Equal(">= v1.20.0, <= v1.22.99"))
+}
+
+func Test_getWorkloadClusterUpgradePlan(t *testing.T) {
+	supportedVersions := kubernetesVersionRange{
+		min: version.MustParseSemantic("v1.20.0"),
+		max: version.MustParseSemantic("v1.22.99"),
+	}
+
+	tests := []struct {
+		name                string
+		controlPlaneVersion string
+		mdVersions          []string
+		want                *WorkloadClusterUpgradePlan
+	}{
+		{
+			name:                "Cluster can be upgraded to the next minor",
+			controlPlaneVersion: "v1.21.2",
+			mdVersions:          []string{"v1.21.2", "v1.20.5"},
+			want: &WorkloadClusterUpgradePlan{
+				Namespace:           "ns1",
+				Name:                "cluster1",
+				ControlPlaneVersion: "v1.21.2",
+				MachineDeployments: []MachineDeploymentVersion{
+					{Name: "md-0", Version: "v1.21.2"},
+					{Name: "md-1", Version: "v1.20.5"},
+				},
+				NextVersion: "v1.22",
+			},
+		},
+		{
+			name:                "Cluster can't be upgraded to a minor not supported by the providers",
+			controlPlaneVersion: "v1.22.2",
+			mdVersions:          []string{"v1.22.2"},
+			want: &WorkloadClusterUpgradePlan{
+				Namespace:           "ns1",
+				Name:                "cluster1",
+				ControlPlaneVersion: "v1.22.2",
+				MachineDeployments: []MachineDeploymentVersion{
+					{Name: "md-0", Version: "v1.22.2"},
+				},
+			},
+		},
+		{
+			name:                "Cluster can't be upgraded if MachineDeployments would violate the version skew policy",
+			controlPlaneVersion: "v1.22.2",
+			mdVersions:          []string{"v1.20.5"},
+			want: &WorkloadClusterUpgradePlan{
+				Namespace:           "ns1",
+				Name:                "cluster1",
+				ControlPlaneVersion: "v1.22.2",
+				MachineDeployments: []MachineDeploymentVersion{
+					{Name: "md-0", Version: "v1.20.5"},
+				},
+			},
+		},
+		{
+			name:                "Version skew violations are reported",
+			controlPlaneVersion: "v1.21.2",
+			mdVersions:          []string{"v1.22.0", "v1.18.3"},
+			want: &WorkloadClusterUpgradePlan{
+				Namespace:           "ns1",
+				Name:                "cluster1",
+				ControlPlaneVersion: "v1.21.2",
+				MachineDeployments: []MachineDeploymentVersion{
+					{Name: "md-0", Version: "v1.22.0"},
+					{Name: "md-1", Version: "v1.18.3"},
+				},
+				Violations: []string{
+					"MachineDeployment md-0 version v1.22.0 is newer than the control plane version v1.21.2",
+					"MachineDeployment md-1 version v1.18.3 is not supported by the installed providers (>= v1.20.0, <= v1.22.99)",
+					"MachineDeployment md-1 version v1.18.3 is more than 2 minor versions older than the control plane version v1.21.2",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Cluster",
+					APIVersion: clusterv1.GroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns1",
+					Name:      "cluster1",
+				},
+				Spec: clusterv1.ClusterSpec{
+					Topology: &clusterv1.Topology{
+						Class:   "class1",
+						Version: tt.controlPlaneVersion,
+					},
+				},
+			}
+			objs := []client.Object{cluster}
+			for i, v := range tt.mdVersions {
+				objs = append(objs, &clusterv1.MachineDeployment{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "MachineDeployment",
+						APIVersion: clusterv1.GroupVersion.String(),
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns1",
+						Name:      tt.want.MachineDeployments[i].Name,
+						Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster1"},
+					},
+					Spec: clusterv1.MachineDeploymentSpec{
+						ClusterName: "cluster1",
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								ClusterName: "cluster1",
+								Version:     pointer.StringPtr(v),
+							},
+						},
+					},
+				})
+			}
+
+			c, err := test.NewFakeProxy().WithObjs(objs...).NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := getWorkloadClusterUpgradePlan(c, cluster, supportedVersions)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
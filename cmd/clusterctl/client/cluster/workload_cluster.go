@@ -17,26 +17,49 @@ limitations under the License.
 package cluster
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/util/certs"
 	utilkubeconfig "sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const waitClusterDeletionInterval = 10 * time.Second
+
 // WorkloadCluster has methods for fetching kubeconfig of workload cluster from management cluster.
 type WorkloadCluster interface {
 	// GetKubeconfig returns the kubeconfig of the workload cluster.
 	GetKubeconfig(workloadClusterName string, namespace string) (string, error)
+
+	// GetKubeconfigWithCertificateTTL returns a kubeconfig for the workload cluster with a new admin client
+	// certificate, signed by the cluster CA and valid for the given duration.
+	GetKubeconfigWithCertificateTTL(workloadClusterName string, namespace string, ttl time.Duration) (string, error)
+
+	// DeleteAll deletes all the workload clusters in the management cluster, and waits for the providers
+	// to tear down their infrastructure, up to the given timeout.
+	DeleteAll(timeout time.Duration) error
 }
 
 // workloadCluster implements WorkloadCluster.
 type workloadCluster struct {
-	proxy Proxy
+	proxy               Proxy
+	pollImmediateWaiter PollImmediateWaiter
 }
 
 // newWorkloadCluster returns a workloadCluster.
-func newWorkloadCluster(proxy Proxy) *workloadCluster {
+func newWorkloadCluster(proxy Proxy, pollImmediateWaiter PollImmediateWaiter) *workloadCluster {
 	return &workloadCluster{
-		proxy: proxy,
+		proxy:               proxy,
+		pollImmediateWaiter: pollImmediateWaiter,
 	}
 }
 
@@ -56,3 +79,112 @@ func (p *workloadCluster) GetKubeconfig(workloadClusterName string, namespace st
 	}
 	return string(dataBytes), nil
 }
+
+func (p *workloadCluster) GetKubeconfigWithCertificateTTL(workloadClusterName string, namespace string, ttl time.Duration) (string, error) {
+	cs, err := p.proxy.NewClient()
+	if err != nil {
+		return "", err
+	}
+
+	obj := client.ObjectKey{
+		Namespace: namespace,
+		Name:      workloadClusterName,
+	}
+
+	// Read the API server endpoint from the kubeconfig generated for the workload cluster.
+	dataBytes, err := utilkubeconfig.FromSecret(ctx, cs, obj)
+	if err != nil {
+		return "", errors.Wrapf(err, "\"%s-kubeconfig\" not found in namespace %q", workloadClusterName, namespace)
+	}
+	config, err := clientcmd.Load(dataBytes)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse \"%s-kubeconfig\"", workloadClusterName)
+	}
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return "", errors.Errorf("\"%s-kubeconfig\" does not define the current context", workloadClusterName)
+	}
+	currentCluster, ok := config.Clusters[currentContext.Cluster]
+	if !ok {
+		return "", errors.Errorf("\"%s-kubeconfig\" does not define the cluster for the current context", workloadClusterName)
+	}
+
+	// Sign the new client certificate with the cluster CA.
+	caSecret, err := secret.GetFromNamespacedName(ctx, cs, obj, secret.ClusterCA)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the CA for the workload cluster %q in namespace %q", workloadClusterName, namespace)
+	}
+	caCert, err := certs.DecodeCertPEM(caSecret.Data[secret.TLSCrtDataName])
+	if err != nil || caCert == nil {
+		return "", errors.Errorf("failed to decode the CA certificate for the workload cluster %q", workloadClusterName)
+	}
+	caKey, err := certs.DecodePrivateKeyPEM(caSecret.Data[secret.TLSKeyDataName])
+	if err != nil || caKey == nil {
+		return "", errors.Errorf("failed to decode the CA private key for the workload cluster %q; client certificates can't be generated for clusters with an external CA", workloadClusterName)
+	}
+
+	newConfig, err := utilkubeconfig.NewWithCertificateDuration(workloadClusterName, currentCluster.Server, caCert, caKey, ttl)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate a kubeconfig for the workload cluster %q", workloadClusterName)
+	}
+	out, err := clientcmd.Write(*newConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize the kubeconfig")
+	}
+	return string(out), nil
+}
+
+func (p *workloadCluster) DeleteAll(timeout time.Duration) error {
+	log := logf.Log
+
+	cs, err := p.proxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	clusterList := &clusterv1.ClusterList{}
+	if err := cs.List(ctx, clusterList); err != nil {
+		return errors.Wrap(err, "failed to list Clusters")
+	}
+
+	var errList []error
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		log.Info("Deleting", "Cluster", cluster.Name, "Namespace", cluster.Namespace)
+		if err := cs.Delete(ctx, cluster); err != nil && !apierrors.IsNotFound(err) {
+			errList = append(errList, errors.Wrapf(err, "failed to delete Cluster %s/%s", cluster.Namespace, cluster.Name))
+		}
+	}
+	if len(errList) > 0 {
+		return kerrors.NewAggregate(errList)
+	}
+
+	if len(clusterList.Items) == 0 {
+		return nil
+	}
+
+	// Wait for the Clusters to go away; Clusters are removed only after the providers have deleted
+	// all the machines and the infrastructure of the cluster.
+	log.Info("Waiting for the Clusters to be deleted", "Timeout", timeout.String())
+	var remaining []string
+	if err := p.pollImmediateWaiter(waitClusterDeletionInterval, timeout, func() (bool, error) {
+		clusterList := &clusterv1.ClusterList{}
+		if err := cs.List(ctx, clusterList); err != nil {
+			return false, err
+		}
+		remaining = remaining[:0]
+		for _, cluster := range clusterList.Items {
+			remaining = append(remaining, fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name))
+		}
+		return len(remaining) == 0, nil
+	}); err != nil {
+		if len(remaining) > 0 {
+			return errors.Wrapf(err, "failed to wait for the deletion of Clusters %s", strings.Join(remaining, ", "))
+		}
+		return errors.Wrap(err, "failed to wait for the deletion of Clusters")
+	}
+	return nil
+}
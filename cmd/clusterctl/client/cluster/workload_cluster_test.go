@@ -18,13 +18,19 @@ package cluster
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/clientcmd"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func Test_WorkloadCluster_GetKubeconfig(t *testing.T) {
@@ -83,7 +89,7 @@ users:
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			wc := newWorkloadCluster(tt.proxy)
+			wc := newWorkloadCluster(tt.proxy, nil)
 			data, err := wc.GetKubeconfig("test1", "test")
 
 			if tt.expectErr {
@@ -96,3 +102,148 @@ users:
 		})
 	}
 }
+
+func Test_WorkloadCluster_GetKubeconfigWithCertificateTTL(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeconfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-kubeconfig",
+			Namespace: "test",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "test1"},
+		},
+		Data: map[string][]byte{
+			secret.KubeconfigDataName: []byte(`
+clusters:
+- cluster:
+    server: https://test-cluster-api:6443
+  name: test1
+contexts:
+- context:
+    cluster: test1
+    user: test1-admin
+  name: test1-admin@test1
+current-context: test1-admin@test1
+kind: Config
+users:
+- name: test1-admin
+  user: {}
+`),
+		},
+	}
+
+	clusterCertificates := secret.NewCertificatesForInitialControlPlane(&bootstrapv1.ClusterConfiguration{})
+	g.Expect(clusterCertificates.Generate()).To(Succeed())
+	caSecret := clusterCertificates.GetByPurpose(secret.ClusterCA).AsSecret(client.ObjectKey{Namespace: "test", Name: "test1"}, metav1.OwnerReference{})
+
+	tests := []struct {
+		name      string
+		expectErr bool
+		proxy     Proxy
+	}{
+		{
+			name:      "return a kubeconfig with a short-lived client certificate",
+			expectErr: false,
+			proxy:     test.NewFakeProxy().WithObjs(kubeconfigSecret, caSecret),
+		},
+		{
+			name:      "return error if cannot find the cluster CA",
+			expectErr: true,
+			proxy:     test.NewFakeProxy().WithObjs(kubeconfigSecret),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			wc := newWorkloadCluster(tt.proxy, nil)
+			data, err := wc.GetKubeconfigWithCertificateTTL("test1", "test", time.Hour)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			config, err := clientcmd.Load([]byte(data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(config.Clusters["test1"].Server).To(Equal("https://test-cluster-api:6443"))
+
+			cert, err := certs.DecodeCertPEM(config.AuthInfos["test1-admin"].ClientCertificateData)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		})
+	}
+}
+
+func Test_WorkloadCluster_DeleteAll(t *testing.T) {
+	cluster := func(name string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Cluster",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns1",
+			},
+		}
+	}
+	deletingCluster := cluster("cluster1")
+	deletingCluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deletingCluster.Finalizers = []string{clusterv1.ClusterFinalizer}
+
+	tests := []struct {
+		name      string
+		proxy     Proxy
+		expectErr bool
+	}{
+		{
+			name:      "no clusters",
+			proxy:     test.NewFakeProxy(),
+			expectErr: false,
+		},
+		{
+			name:      "delete all the clusters",
+			proxy:     test.NewFakeProxy().WithObjs(cluster("cluster1"), cluster("cluster2")),
+			expectErr: false,
+		},
+		{
+			name:      "return error if clusters are not deleted before the timeout",
+			proxy:     test.NewFakeProxy().WithObjs(deletingCluster),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Check the condition only once, so clusters still being deleted cause a timeout.
+			pollImmediateWaiter := func(interval, timeout time.Duration, condition wait.ConditionFunc) error {
+				done, err := condition()
+				if err != nil {
+					return err
+				}
+				if !done {
+					return wait.ErrWaitTimeout
+				}
+				return nil
+			}
+
+			wc := newWorkloadCluster(tt.proxy, pollImmediateWaiter)
+			err := wc.DeleteAll(time.Minute)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			c, err := tt.proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			clusterList := &clusterv1.ClusterList{}
+			g.Expect(c.List(ctx, clusterList)).To(Succeed())
+			g.Expect(clusterList.Items).To(BeEmpty())
+		})
+	}
+}
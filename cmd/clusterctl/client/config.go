@@ -126,6 +126,10 @@ type GetClusterTemplateOptions struct {
 	// ConfigMapSource to be used for reading the workload cluster template; only one template source can be used at time.
 	ConfigMapSource *ConfigMapSourceOptions
 
+	// ClusterClassSource to be used for generating a workload cluster template with a managed topology from a
+	// ClusterClass; only one template source can be used at time.
+	ClusterClassSource *ClusterClassSourceOptions
+
 	// TargetNamespace where the objects describing the workload cluster should be deployed. If unspecified,
 	// the current namespace will be used.
 	TargetNamespace string
@@ -166,6 +170,9 @@ func (o *GetClusterTemplateOptions) numSources() int {
 	if o.URLSource != nil {
 		numSources++
 	}
+	if o.ClusterClassSource != nil {
+		numSources++
+	}
 	return numSources
 }
 
@@ -203,6 +210,15 @@ type ConfigMapSourceOptions struct {
 	DataKey string
 }
 
+// ClusterClassSourceOptions defines the options to be used when generating a workload cluster template from a ClusterClass.
+type ClusterClassSourceOptions struct {
+	// Namespace where the ClusterClass exists. If unspecified, the target namespace will be used.
+	Namespace string
+
+	// Name of the ClusterClass to generate the workload cluster template from.
+	Name string
+}
+
 func (c *clusterctlClient) GetClusterTemplate(options GetClusterTemplateOptions) (Template, error) {
 	// Checks that no more than on source is set
 	numsSource := options.numSources()
@@ -258,6 +274,9 @@ func (c *clusterctlClient) GetClusterTemplate(options GetClusterTemplateOptions)
 	if options.URLSource != nil {
 		return c.getTemplateFromURL(clusterClient, *options.URLSource, options.TargetNamespace, options.ListVariablesOnly)
 	}
+	if options.ClusterClassSource != nil {
+		return c.getTemplateFromClusterClass(clusterClient, *options.ClusterClassSource, options.TargetNamespace, options.ListVariablesOnly)
+	}
 
 	return nil, errors.New("unable to read custom template. Please specify a template source")
 }
@@ -358,6 +377,16 @@ func (c *clusterctlClient) getTemplateFromURL(cluster cluster.Client, source URL
 	return cluster.Template().GetFromURL(source.URL, targetNamespace, listVariablesOnly)
 }
 
+// getTemplateFromClusterClass returns a workload cluster template with a managed topology generated from a ClusterClass.
+func (c *clusterctlClient) getTemplateFromClusterClass(cluster cluster.Client, source ClusterClassSourceOptions, targetNamespace string, listVariablesOnly bool) (Template, error) {
+	// If the option specifying the ClusterClass namespace is empty, default it to the target namespace.
+	if source.Namespace == "" {
+		source.Namespace = targetNamespace
+	}
+
+	return cluster.Template().GetFromClusterClass(source.Namespace, source.Name, targetNamespace, listVariablesOnly)
+}
+
 // templateOptionsToVariables injects some of the templateOptions to the configClient so they can be consumed as a variables from the template.
 func (c *clusterctlClient) templateOptionsToVariables(options GetClusterTemplateOptions) error {
 	// the TargetNamespace, if valid, can be used in templates using the ${ NAMESPACE } variable.
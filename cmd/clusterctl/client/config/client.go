@@ -111,4 +111,12 @@ type Reader interface {
 
 	// UnmarshalKey reads a configuration value and unmarshals it into the provided value object.
 	UnmarshalKey(key string, value interface{}) error
+
+	// Keys returns the names of the configuration values defined in the clusterctl configuration file,
+	// in the selected configuration profile or explicitly set.
+	Keys() []string
+
+	// Source returns where a configuration value comes from, as one of the VariableSource values.
+	// In case the configuration value does not exists, it returns an error.
+	Source(key string) (string, error)
 }
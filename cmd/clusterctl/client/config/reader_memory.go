@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	"sort"
+
 	"github.com/pkg/errors"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/yaml"
@@ -80,6 +82,24 @@ func (f *MemoryReader) UnmarshalKey(key string, rawval interface{}) error {
 	return yaml.Unmarshal([]byte(data), rawval)
 }
 
+// Keys returns the keys of all the values stored in the reader.
+func (f *MemoryReader) Keys() []string {
+	keys := make([]string, 0, len(f.variables))
+	for key := range f.variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Source returns the source of the value for the given key; all the values of a MemoryReader are explicitly set.
+func (f *MemoryReader) Source(key string) (string, error) {
+	if _, err := f.Get(key); err != nil {
+		return "", err
+	}
+	return string(VariableSourceOverride), nil
+}
+
 // AddProvider adds the given provider to the "providers" map entry and returns any errors.
 func (f *MemoryReader) AddProvider(name string, ttype clusterctlv1.ProviderType, url string) (*MemoryReader, error) {
 	f.providers = append(f.providers, configProvider{
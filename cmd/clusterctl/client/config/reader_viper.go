@@ -23,6 +23,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/client-go/util/homedir"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -39,12 +41,23 @@ const (
 	ConfigName = "clusterctl"
 	// DownloadConfigFile is the config file when fetching the config from a remote location.
 	DownloadConfigFile = "clusterctl-download.yaml"
+	// ProfileKey is the name of the variable selecting the configuration profile to be used.
+	ProfileKey = "CLUSTERCTL_PROFILE"
+
+	// profilesKey is the key of the section of the config file defining the configuration profiles.
+	profilesKey = "profiles"
 )
 
 // viperReader implements Reader using viper as backend for reading from environment variables
 // and from a clusterctl config file.
 type viperReader struct {
 	configPaths []string
+
+	// overrides, fileKeys and profileKeys track the names of the configuration values explicitly set, defined in the
+	// config file and defined in the selected configuration profile; they are indexed by the lowercase name used by viper.
+	overrides   map[string]string
+	fileKeys    map[string]string
+	profileKeys map[string]string
 }
 
 type viperReaderOption func(*viperReader)
@@ -115,6 +128,9 @@ func (v *viperReader) Init(path string) error {
 			// since there is no default config to read from, just skip
 			// reading in config
 			log.V(5).Info("No default config file available")
+			if profile := viper.GetString(ProfileKey); profile != "" {
+				return errors.Errorf("failed to use the configuration profile %q: no clusterctl config file available", profile)
+			}
 			return nil
 		}
 		// Configure viper for reading .cluster-api/clusterctl{.extension} in home directory
@@ -128,6 +144,58 @@ func (v *viperReader) Init(path string) error {
 		return err
 	}
 	log.V(5).Info("Using configuration", "File", viper.ConfigFileUsed())
+	raw := v.recordConfigFileKeys()
+	return v.applyProfile(raw)
+}
+
+// recordConfigFileKeys records the names of the configuration values defined in the config file, and returns the
+// raw content of the config file, if it can be read as YAML.
+// NB. viper lowercases all the names, so the config file is read again in order to preserve the original case.
+func (v *viperReader) recordConfigFileKeys() map[string]interface{} {
+	v.fileKeys = map[string]string{}
+	for key := range viper.AllSettings() {
+		v.fileKeys[key] = key
+	}
+	delete(v.fileKeys, profilesKey)
+
+	raw := map[string]interface{}{}
+	content, err := os.ReadFile(viper.ConfigFileUsed())
+	if err != nil || yaml.Unmarshal(content, &raw) != nil {
+		return nil
+	}
+	preserveCase(v.fileKeys, raw)
+	return raw
+}
+
+// applyProfile merges the settings defined in the selected configuration profile, if any, on top of the
+// settings defined at the top level of the config file; environment variables still take precedence.
+// NB. Maps, e.g. images, are merged with the top level ones, while lists, e.g. providers, replace them.
+func (v *viperReader) applyProfile(raw map[string]interface{}) error {
+	log := logf.Log
+
+	profile := viper.GetString(ProfileKey)
+	if profile == "" {
+		return nil
+	}
+
+	settings := viper.Sub(fmt.Sprintf("%s.%s", profilesKey, profile))
+	if settings == nil {
+		return errors.Errorf("failed to use the configuration profile %q: the profile is not defined in %s", profile, viper.ConfigFileUsed())
+	}
+	if err := viper.MergeConfigMap(settings.AllSettings()); err != nil {
+		return errors.Wrapf(err, "failed to use the configuration profile %q", profile)
+	}
+
+	v.profileKeys = map[string]string{}
+	for key := range settings.AllSettings() {
+		v.profileKeys[key] = key
+	}
+	if profiles, ok := raw[profilesKey].(map[string]interface{}); ok {
+		if rawProfile, ok := profiles[profile].(map[string]interface{}); ok {
+			preserveCase(v.profileKeys, rawProfile)
+		}
+	}
+	log.V(5).Info("Using configuration profile", "Profile", profile)
 	return nil
 }
 
@@ -169,6 +237,10 @@ func (v *viperReader) Get(key string) (string, error) {
 }
 
 func (v *viperReader) Set(key, value string) {
+	if v.overrides == nil {
+		v.overrides = map[string]string{}
+	}
+	v.overrides[strings.ToLower(key)] = key
 	viper.Set(key, value)
 }
 
@@ -176,6 +248,55 @@ func (v *viperReader) UnmarshalKey(key string, rawval interface{}) error {
 	return viper.UnmarshalKey(key, rawval)
 }
 
+func (v *viperReader) Keys() []string {
+	names := map[string]string{}
+	for _, keys := range []map[string]string{v.fileKeys, v.profileKeys, v.overrides} {
+		for lowercaseKey, key := range keys {
+			names[lowercaseKey] = key
+		}
+	}
+
+	ret := make([]string, 0, len(names))
+	for _, key := range names {
+		ret = append(ret, key)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return strings.ToLower(ret[i]) < strings.ToLower(ret[j])
+	})
+	return ret
+}
+
+func (v *viperReader) Source(key string) (string, error) {
+	if _, err := v.Get(key); err != nil {
+		return "", err
+	}
+
+	// NB. The checks below follow the viper precedence order: explicit values, environment variables, config file.
+	lowercaseKey := strings.ToLower(key)
+	if _, ok := v.overrides[lowercaseKey]; ok {
+		return string(VariableSourceOverride), nil
+	}
+	if _, ok := os.LookupEnv(strings.ToUpper(strings.ReplaceAll(key, "-", "_"))); ok {
+		return string(VariableSourceEnv), nil
+	}
+	if _, ok := v.profileKeys[lowercaseKey]; ok {
+		return string(VariableSourceProfile), nil
+	}
+	if _, ok := v.fileKeys[lowercaseKey]; ok {
+		return string(VariableSourceConfigFile), nil
+	}
+	return string(VariableSourceDefault), nil
+}
+
+// preserveCase replaces the lowercase names of a set of keys with the original names defined in a raw map.
+func preserveCase(keys map[string]string, raw map[string]interface{}) {
+	for key := range raw {
+		if _, ok := keys[strings.ToLower(key)]; ok {
+			keys[strings.ToLower(key)] = key
+		}
+	}
+}
+
 // checkDefaultConfig checks the existence of the default config.
 // Returns true if it finds a supported config file in the available config
 // folders.
@@ -184,6 +184,141 @@ func Test_viperReader_Get(t *testing.T) {
 	}
 }
 
+func Test_viperReader_Profile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "clusterctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "clusterctl.yaml")
+	config := `
+bar: bar
+baz: baz
+images:
+  all:
+    repository: default.registry.io
+profiles:
+  staging:
+    bar: bar-staging
+    images:
+      cert-manager:
+        tag: v1.5.3
+`
+	if err := os.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		profile   string
+		wantBar   string
+		wantImage map[string]interface{}
+		wantErr   bool
+	}{
+		{
+			name:    "no profile",
+			profile: "",
+			wantBar: "bar",
+			wantImage: map[string]interface{}{
+				"all": map[string]interface{}{"repository": "default.registry.io"},
+			},
+		},
+		{
+			name:    "profile settings override the top level settings",
+			profile: "staging",
+			wantBar: "bar-staging",
+			wantImage: map[string]interface{}{
+				"all":          map[string]interface{}{"repository": "default.registry.io"},
+				"cert-manager": map[string]interface{}{"tag": "v1.5.3"},
+			},
+		},
+		{
+			name:    "fails if the profile is not defined",
+			profile: "production",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_ = os.Setenv(ProfileKey, tt.profile)
+			defer os.Unsetenv(ProfileKey)
+
+			v := newViperReader(injectConfigPaths([]string{dir}))
+			err := v.Init(configFile)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := v.Get("bar")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.wantBar))
+
+			got, err = v.Get("baz")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal("baz"))
+
+			images := map[string]interface{}{}
+			g.Expect(v.UnmarshalKey("images", &images)).To(Succeed())
+			g.Expect(images).To(Equal(tt.wantImage))
+		})
+	}
+}
+
+func Test_viperReader_Source(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := os.MkdirTemp("", "clusterctl")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "clusterctl.yaml")
+	config := `
+FOO_FILE: file
+FOO_PROFILE: file
+FOO_ENV: file
+FOO_OVERRIDE: file
+profiles:
+  staging:
+    FOO_PROFILE: profile
+`
+	g.Expect(os.WriteFile(configFile, []byte(config), 0600)).To(Succeed())
+
+	_ = os.Setenv(ProfileKey, "staging")
+	defer os.Unsetenv(ProfileKey)
+	_ = os.Setenv("FOO_ENV", "env")
+	defer os.Unsetenv("FOO_ENV")
+
+	v := newViperReader(injectConfigPaths([]string{dir}))
+	g.Expect(v.Init(configFile)).To(Succeed())
+	v.Set("FOO_OVERRIDE", "override")
+
+	// Keys are returned with the case used in the config file.
+	g.Expect(v.Keys()).To(Equal([]string{"FOO_ENV", "FOO_FILE", "FOO_OVERRIDE", "FOO_PROFILE"}))
+
+	tests := []struct {
+		key  string
+		want VariableSource
+	}{
+		{key: "FOO_FILE", want: VariableSourceConfigFile},
+		{key: "FOO_PROFILE", want: VariableSourceProfile},
+		{key: "FOO_ENV", want: VariableSourceEnv},
+		{key: "FOO_OVERRIDE", want: VariableSourceOverride},
+	}
+	for _, tt := range tests {
+		got, err := v.Source(tt.key)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(got).To(Equal(string(tt.want)), "source of %s", tt.key)
+	}
+
+	_, err = v.Source("FOO_DOES_NOT_EXIST")
+	g.Expect(err).To(HaveOccurred())
+}
+
 func Test_viperReader_GetWithoutDefaultConfig(t *testing.T) {
 	g := NewWithT(t)
 	dir, err := os.MkdirTemp("", "clusterctl")
@@ -16,11 +16,44 @@ limitations under the License.
 
 package config
 
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
 const (
 	// GitHubTokenVariable defines a variable hosting the GitHub access token.
 	GitHubTokenVariable = "github-token"
 )
 
+// VariableSource defines where the value of a variable comes from.
+type VariableSource string
+
+const (
+	// VariableSourceOverride is the source of variables explicitly set, e.g. from a flag value.
+	VariableSourceOverride VariableSource = "override"
+
+	// VariableSourceEnv is the source of variables read from the environment variables.
+	VariableSourceEnv VariableSource = "env"
+
+	// VariableSourceProfile is the source of variables read from the selected configuration profile.
+	VariableSourceProfile VariableSource = "profile"
+
+	// VariableSourceConfigFile is the source of variables read from the clusterctl configuration file.
+	VariableSourceConfigFile VariableSource = "config file"
+
+	// VariableSourceDefault is the source of settings using the clusterctl default value.
+	VariableSourceDefault VariableSource = "default"
+)
+
+// Variable defines the value of a variable and where it comes from.
+type Variable struct {
+	Name   string
+	Value  string
+	Source VariableSource
+}
+
 // VariablesClient has methods to work with environment variables and with variables defined in the clusterctl configuration file.
 type VariablesClient interface {
 	// Get returns a variable value. If the variable is not defined an error is returned.
@@ -33,13 +66,24 @@ type VariablesClient interface {
 	Set(key, values string)
 }
 
+// VariablesLister is implemented by the VariablesClient which can list the variables and report where their values come from.
+type VariablesLister interface {
+	// List returns the variables defined in the clusterctl configuration file, in the selected configuration profile
+	// or explicitly set, with the source of their value; environment variables take precedence as in Get.
+	List() ([]Variable, error)
+
+	// Source returns where the value of a variable comes from. If the variable is not defined an error is returned.
+	Source(key string) (VariableSource, error)
+}
+
 // variablesClient implements VariablesClient.
 type variablesClient struct {
 	reader Reader
 }
 
-// ensure variablesClient implements VariablesClient.
+// ensure variablesClient implements VariablesClient and VariablesLister.
 var _ VariablesClient = &variablesClient{}
+var _ VariablesLister = &variablesClient{}
 
 func newVariablesClient(reader Reader) *variablesClient {
 	return &variablesClient{
@@ -54,3 +98,46 @@ func (p *variablesClient) Get(key string) (string, error) {
 func (p *variablesClient) Set(key, value string) {
 	p.reader.Set(key, value)
 }
+
+func (p *variablesClient) List() ([]Variable, error) {
+	keys := p.reader.Keys()
+	ret := make([]Variable, 0, len(keys))
+	for _, key := range keys {
+		value, err := p.reader.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		// Structured settings, e.g. providers or images, are not returned by Get as a string, so they are serialized.
+		if value == "" {
+			var rawValue interface{}
+			if err := p.reader.UnmarshalKey(key, &rawValue); err != nil {
+				return nil, errors.Wrapf(err, "failed to read the value of %q", key)
+			}
+			if rawValue != nil {
+				data, err := json.Marshal(rawValue)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to serialize the value of %q", key)
+				}
+				value = string(data)
+			}
+		}
+		source, err := p.reader.Source(key)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, Variable{
+			Name:   key,
+			Value:  value,
+			Source: VariableSource(source),
+		})
+	}
+	return ret, nil
+}
+
+func (p *variablesClient) Source(key string) (VariableSource, error) {
+	source, err := p.reader.Source(key)
+	if err != nil {
+		return "", err
+	}
+	return VariableSource(source), nil
+}
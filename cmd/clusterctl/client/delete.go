@@ -17,6 +17,8 @@ limitations under the License.
 package client
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
@@ -53,8 +55,19 @@ type DeleteOptions struct {
 
 	// IncludeCRDs forces the deletion of the provider's CRDs (and of all the related objects).
 	IncludeCRDs bool
+
+	// IncludeClusters forces the deletion of all the workload clusters before deleting the providers, so the providers
+	// can tear down the infrastructure of the clusters.
+	IncludeClusters bool
+
+	// ClusterDeletionTimeout defines how long to wait for the workload clusters to be deleted when IncludeClusters is set.
+	// If unspecified, defaults to 30 minutes.
+	ClusterDeletionTimeout time.Duration
 }
 
+// defaultClusterDeletionTimeout is the default time to wait for the workload clusters to be deleted.
+const defaultClusterDeletionTimeout = 30 * time.Minute
+
 func (c *clusterctlClient) Delete(options DeleteOptions) error {
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -110,6 +123,18 @@ func (c *clusterctlClient) Delete(options DeleteOptions) error {
 		}
 	}
 
+	// If requested, delete all the workload clusters while the providers are still running, so they can
+	// tear down the clusters' infrastructure.
+	if options.IncludeClusters {
+		timeout := options.ClusterDeletionTimeout
+		if timeout == 0 {
+			timeout = defaultClusterDeletionTimeout
+		}
+		if err := clusterClient.WorkloadCluster().DeleteAll(timeout); err != nil {
+			return errors.Wrap(err, "failed to delete workload clusters; providers have not been deleted")
+		}
+	}
+
 	// Delete the selected providers
 	for _, provider := range providersToDelete {
 		if err := clusterClient.ProviderComponents().Delete(cluster.DeleteOptions{Provider: provider, IncludeNamespace: options.IncludeNamespace, IncludeCRDs: options.IncludeCRDs}); err != nil {
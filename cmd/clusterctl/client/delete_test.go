@@ -20,7 +20,9 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
@@ -39,6 +41,7 @@ func Test_clusterctlClient_Delete(t *testing.T) {
 		fields        fields
 		args          args
 		wantProviders sets.String
+		wantClusters  int
 		wantErr       bool
 	}{
 		{
@@ -59,6 +62,7 @@ func Test_clusterctlClient_Delete(t *testing.T) {
 				},
 			},
 			wantProviders: sets.NewString(),
+			wantClusters:  1,
 			wantErr:       false,
 		},
 		{
@@ -82,7 +86,8 @@ func Test_clusterctlClient_Delete(t *testing.T) {
 				capiProviderConfig.Name(),
 				clusterctlv1.ManifestLabel(controlPlaneProviderConfig.Name(), controlPlaneProviderConfig.Type()),
 				clusterctlv1.ManifestLabel(infraProviderConfig.Name(), infraProviderConfig.Type())),
-			wantErr: false,
+			wantClusters: 1,
+			wantErr:      false,
 		},
 		{
 			name: "Delete multiple providers of different type",
@@ -104,7 +109,24 @@ func Test_clusterctlClient_Delete(t *testing.T) {
 			wantProviders: sets.NewString(
 				clusterctlv1.ManifestLabel(controlPlaneProviderConfig.Name(), controlPlaneProviderConfig.Type()),
 				clusterctlv1.ManifestLabel(infraProviderConfig.Name(), infraProviderConfig.Type())),
-			wantErr: false,
+			wantClusters: 1,
+			wantErr:      false,
+		},
+		{
+			name: "Delete all the providers and the workload clusters",
+			fields: fields{
+				client: fakeClusterForDelete(),
+			},
+			args: args{
+				options: DeleteOptions{
+					Kubeconfig:      Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					DeleteAll:       true,
+					IncludeClusters: true,
+				},
+			},
+			wantProviders: sets.NewString(),
+			wantClusters:  0,
+			wantErr:       false,
 		},
 	}
 	for _, tt := range tests {
@@ -132,6 +154,10 @@ func Test_clusterctlClient_Delete(t *testing.T) {
 			}
 
 			g.Expect(gotProvidersSet).To(Equal(tt.wantProviders))
+
+			gotClusters := &clusterv1.ClusterList{}
+			g.Expect(c.List(ctx, gotClusters)).To(Succeed())
+			g.Expect(gotClusters.Items).To(HaveLen(tt.wantClusters))
 		})
 	}
 }
@@ -156,6 +182,16 @@ func fakeClusterForDelete() *fakeClient {
 	cluster1.fakeProxy.WithProviderInventory(controlPlaneProviderConfig.Name(), controlPlaneProviderConfig.Type(), "v1.0.0", namespace)
 	cluster1.fakeProxy.WithProviderInventory(infraProviderConfig.Name(), infraProviderConfig.Type(), "v1.0.0", namespace)
 	cluster1.fakeProxy.WithFakeCAPISetup()
+	cluster1.fakeProxy.WithObjs(&clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workload-cluster",
+			Namespace: "default",
+		},
+	})
 
 	client := newFakeClient(config1).
 		// fake repository for capi, bootstrap, controlplane and infra provider (matching provider's config)
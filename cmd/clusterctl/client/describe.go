@@ -19,6 +19,8 @@ package client
 import (
 	"context"
 
+	clusterv1old "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 )
 
@@ -82,3 +84,36 @@ func (c *clusterctlClient) DescribeCluster(options DescribeClusterOptions) (*tre
 		DisableGrouping:     options.DisableGrouping,
 	})
 }
+
+// DescribeProvidersOptions carries the options supported by DescribeProviders.
+type DescribeProvidersOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+}
+
+// DescribeProviders returns the description of the providers installed in the management cluster.
+func (c *clusterctlClient) DescribeProviders(options DescribeProvidersOptions) ([]ProviderDescription, error) {
+	// gets access to the management cluster
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract (default) or the previous one.
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(cluster.AllowCAPIContract{Contract: clusterv1old.GroupVersion.Version}); err != nil {
+		return nil, err
+	}
+
+	descriptions, err := clusterClient.ProviderDescriber().Describe()
+	if err != nil {
+		return nil, err
+	}
+
+	// ProviderDescription is an alias for cluster.ProviderDescription; this makes the conversion
+	aliasDescriptions := make([]ProviderDescription, len(descriptions))
+	for i, description := range descriptions {
+		aliasDescriptions[i] = ProviderDescription(description)
+	}
+	return aliasDescriptions, nil
+}
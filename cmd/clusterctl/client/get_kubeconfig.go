@@ -17,9 +17,17 @@ limitations under the License.
 package client
 
 import (
+	"os"
+	"time"
+
 	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
+// defaultExecAPIVersion is the API version of the exec credential plugins used if not otherwise specified.
+const defaultExecAPIVersion = "client.authentication.k8s.io/v1beta1"
+
 // GetKubeconfigOptions carries all the options supported by GetKubeconfig.
 type GetKubeconfigOptions struct {
 	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
@@ -31,6 +39,28 @@ type GetKubeconfigOptions struct {
 
 	// WorkloadClusterName is the name of the workload cluster.
 	WorkloadClusterName string
+
+	// ClientCertificateTTL, if set, replaces the admin client certificate stored in the kubeconfig secret
+	// with a new client certificate, signed by the cluster CA and valid for the given duration.
+	ClientCertificateTTL time.Duration
+
+	// ExecCommand, if set, replaces the credentials in the kubeconfig with an exec credential plugin
+	// running the given command, e.g. an OIDC login plugin.
+	ExecCommand string
+
+	// ExecArgs are the arguments passed to the ExecCommand.
+	ExecArgs []string
+
+	// ExecAPIVersion is the API version of the ExecCommand's credential plugin.
+	// If unspecified, client.authentication.k8s.io/v1beta1 is used.
+	ExecAPIVersion string
+
+	// ContextName, if set, is used as the name of the context, cluster and user in the kubeconfig.
+	ContextName string
+
+	// MergeInto, if set, is the path of a kubeconfig file where the kubeconfig for the workload cluster is merged.
+	// Existing entries with the same names of the ones in the workload cluster kubeconfig are replaced.
+	MergeInto string
 }
 
 func (c *clusterctlClient) GetKubeconfig(options GetKubeconfigOptions) (string, error) {
@@ -56,5 +86,126 @@ func (c *clusterctlClient) GetKubeconfig(options GetKubeconfigOptions) (string,
 		options.Namespace = currentNamespace
 	}
 
-	return clusterClient.WorkloadCluster().GetKubeconfig(options.WorkloadClusterName, options.Namespace)
+	if options.ClientCertificateTTL < 0 {
+		return "", errors.New("the client certificate TTL must be a positive duration")
+	}
+	if options.ClientCertificateTTL > 0 && options.ExecCommand != "" {
+		return "", errors.New("a client certificate TTL can't be used in combination with an exec command")
+	}
+
+	var kubeconfig string
+	if options.ClientCertificateTTL > 0 {
+		kubeconfig, err = clusterClient.WorkloadCluster().GetKubeconfigWithCertificateTTL(options.WorkloadClusterName, options.Namespace, options.ClientCertificateTTL)
+	} else {
+		kubeconfig, err = clusterClient.WorkloadCluster().GetKubeconfig(options.WorkloadClusterName, options.Namespace)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// If there are no changes to be applied to the kubeconfig, return it as it is.
+	if options.ExecCommand == "" && options.ContextName == "" && options.MergeInto == "" {
+		return kubeconfig, nil
+	}
+
+	config, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the kubeconfig for the workload cluster %q", options.WorkloadClusterName)
+	}
+
+	if options.ExecCommand != "" {
+		setExecCredentials(config, options)
+	}
+	if options.ContextName != "" {
+		config, err = renameKubeconfigEntries(config, options.ContextName)
+		if err != nil {
+			return "", err
+		}
+	}
+	if options.MergeInto != "" {
+		if err := mergeKubeconfig(config, options.MergeInto); err != nil {
+			return "", err
+		}
+	}
+
+	out, err := clientcmd.Write(*config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize the kubeconfig")
+	}
+	return string(out), nil
+}
+
+// setExecCredentials replaces the credentials for all the users in the kubeconfig with an exec credential plugin.
+func setExecCredentials(config *clientcmdapi.Config, options GetKubeconfigOptions) {
+	apiVersion := options.ExecAPIVersion
+	if apiVersion == "" {
+		apiVersion = defaultExecAPIVersion
+	}
+
+	for name := range config.AuthInfos {
+		config.AuthInfos[name] = &clientcmdapi.AuthInfo{
+			Exec: &clientcmdapi.ExecConfig{
+				APIVersion: apiVersion,
+				Command:    options.ExecCommand,
+				Args:       options.ExecArgs,
+			},
+		}
+	}
+}
+
+// renameKubeconfigEntries renames the context, the cluster and the user of a kubeconfig with a single context.
+func renameKubeconfigEntries(config *clientcmdapi.Config, name string) (*clientcmdapi.Config, error) {
+	context, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, errors.New("the kubeconfig for the workload cluster does not define the current context")
+	}
+	cluster, ok := config.Clusters[context.Cluster]
+	if !ok {
+		return nil, errors.New("the kubeconfig for the workload cluster does not define the cluster for the current context")
+	}
+	authInfo, ok := config.AuthInfos[context.AuthInfo]
+	if !ok {
+		return nil, errors.New("the kubeconfig for the workload cluster does not define the user for the current context")
+	}
+
+	renamed := clientcmdapi.NewConfig()
+	renamed.Clusters[name] = cluster
+	renamed.AuthInfos[name] = authInfo
+	renamed.Contexts[name] = &clientcmdapi.Context{
+		Cluster:   name,
+		AuthInfo:  name,
+		Namespace: context.Namespace,
+	}
+	renamed.CurrentContext = name
+	return renamed, nil
+}
+
+// mergeKubeconfig merges the kubeconfig into a kubeconfig file, creating the file if it does not exist.
+// The current context of the kubeconfig file is changed only if not already set.
+func mergeKubeconfig(config *clientcmdapi.Config, path string) error {
+	existing := clientcmdapi.NewConfig()
+	if _, err := os.Stat(path); err == nil {
+		existing, err = clientcmd.LoadFromFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the kubeconfig file %q", path)
+		}
+	}
+
+	for name, cluster := range config.Clusters {
+		existing.Clusters[name] = cluster
+	}
+	for name, authInfo := range config.AuthInfos {
+		existing.AuthInfos[name] = authInfo
+	}
+	for name, context := range config.Contexts {
+		existing.Contexts[name] = context
+	}
+	if existing.CurrentContext == "" {
+		existing.CurrentContext = config.CurrentContext
+	}
+
+	if err := clientcmd.WriteToFile(*existing, path); err != nil {
+		return errors.Wrapf(err, "failed to write the kubeconfig file %q", path)
+	}
+	return nil
 }
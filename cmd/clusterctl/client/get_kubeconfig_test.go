@@ -17,9 +17,13 @@ limitations under the License.
 package client
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 )
@@ -66,3 +70,73 @@ func Test_clusterctlClient_GetKubeconfig(t *testing.T) {
 		})
 	}
 }
+
+func fakeWorkloadClusterKubeconfig() *clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["test1"] = &clientcmdapi.Cluster{Server: "https://test-cluster-api:6443"}
+	config.AuthInfos["test1-admin"] = &clientcmdapi.AuthInfo{ClientCertificateData: []byte("cert"), ClientKeyData: []byte("key")}
+	config.Contexts["test1-admin@test1"] = &clientcmdapi.Context{Cluster: "test1", AuthInfo: "test1-admin"}
+	config.CurrentContext = "test1-admin@test1"
+	return config
+}
+
+func Test_setExecCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	config := fakeWorkloadClusterKubeconfig()
+	setExecCredentials(config, GetKubeconfigOptions{
+		ExecCommand: "kubectl",
+		ExecArgs:    []string{"oidc-login", "get-token", "--oidc-issuer-url=https://issuer.example.com"},
+	})
+
+	g.Expect(config.AuthInfos["test1-admin"]).To(Equal(&clientcmdapi.AuthInfo{
+		Exec: &clientcmdapi.ExecConfig{
+			APIVersion: defaultExecAPIVersion,
+			Command:    "kubectl",
+			Args:       []string{"oidc-login", "get-token", "--oidc-issuer-url=https://issuer.example.com"},
+		},
+	}))
+}
+
+func Test_renameKubeconfigEntries(t *testing.T) {
+	g := NewWithT(t)
+
+	config, err := renameKubeconfigEntries(fakeWorkloadClusterKubeconfig(), "workload")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(config.CurrentContext).To(Equal("workload"))
+	g.Expect(config.Contexts).To(HaveLen(1))
+	g.Expect(config.Contexts["workload"].Cluster).To(Equal("workload"))
+	g.Expect(config.Contexts["workload"].AuthInfo).To(Equal("workload"))
+	g.Expect(config.Clusters).To(HaveKey("workload"))
+	g.Expect(config.AuthInfos).To(HaveKey("workload"))
+}
+
+func Test_mergeKubeconfig(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := os.MkdirTemp("", "clusterctl")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+
+	// Merging into a file which does not exist creates the file and sets the current context.
+	g.Expect(mergeKubeconfig(fakeWorkloadClusterKubeconfig(), path)).To(Succeed())
+
+	got, err := clientcmd.LoadFromFile(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.CurrentContext).To(Equal("test1-admin@test1"))
+	g.Expect(got.Contexts).To(HaveKey("test1-admin@test1"))
+
+	// Merging into an existing file preserves the existing entries and the current context.
+	other, err := renameKubeconfigEntries(fakeWorkloadClusterKubeconfig(), "other")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mergeKubeconfig(other, path)).To(Succeed())
+
+	got, err = clientcmd.LoadFromFile(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.CurrentContext).To(Equal("test1-admin@test1"))
+	g.Expect(got.Contexts).To(HaveKey("test1-admin@test1"))
+	g.Expect(got.Contexts).To(HaveKey("other"))
+	g.Expect(got.Clusters).To(HaveKey("other"))
+}
@@ -186,6 +186,36 @@ func (c *clusterctlClient) InitImages(options InitOptions) ([]string, error) {
 	return images, nil
 }
 
+// InitManifests returns the provider components that would be installed by Init.
+func (c *clusterctlClient) InitManifests(options InitOptions) ([]Components, error) {
+	// gets access to the management cluster
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// checks if the cluster already contains a Core provider.
+	// if not we consider this the first time init is executed, and thus we enforce the rendering of a core provider,
+	// a bootstrap provider and a control-plane provider (if not already explicitly requested by the user).
+	// Nb. the management cluster is not required to be reachable, so the provider components can be rendered offline;
+	// in this case we assume there are no core providers installed in the cluster.
+	c.addDefaultProviders(clusterClient, &options)
+
+	// create an installer service and add the requested providers to the install queue, without installing them.
+	installer, err := c.setupInstaller(clusterClient, options)
+	if err != nil {
+		return nil, err
+	}
+	components := installer.Components()
+
+	// Components is an alias for repository.Components; this makes the conversion from the two types
+	aliasComponents := make([]Components, len(components))
+	for i, components := range components {
+		aliasComponents[i] = components
+	}
+	return aliasComponents, nil
+}
+
 func (c *clusterctlClient) setupInstaller(cluster cluster.Client, options InitOptions) (cluster.ProviderInstaller, error) {
 	installer := cluster.ProviderInstaller()
 
@@ -192,6 +192,71 @@ func Test_clusterctlClient_InitImages(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_InitManifests(t *testing.T) {
+	tests := []struct {
+		name                   string
+		client                 *fakeClient
+		infrastructureProvider []string
+		wantProviders          []string
+		wantErr                bool
+	}{
+		{
+			name:                   "renders the default providers and the requested infrastructure provider with variables substituted",
+			client:                 fakeEmptyCluster(),
+			infrastructureProvider: []string{"infra"},
+			wantProviders: []string{
+				capiProviderConfig.ManifestLabel(),
+				bootstrapProviderConfig.ManifestLabel(),
+				controlPlaneProviderConfig.ManifestLabel(),
+				infraProviderConfig.ManifestLabel(),
+			},
+		},
+		{
+			name:                   "renders only the requested providers if a core provider is already installed",
+			client:                 fakeInitializedCluster(),
+			infrastructureProvider: []string{"infra"},
+			wantProviders: []string{
+				infraProviderConfig.ManifestLabel(),
+			},
+		},
+		{
+			name: "returns error if variables are not available",
+			client: func() *fakeClient {
+				_, fc := setupCluster(nil, newFakeCertManagerClient(nil, nil))
+				return fc
+			}(),
+			infrastructureProvider: []string{"infra"},
+			wantErr:                true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := tt.client.InitManifests(InitOptions{
+				Kubeconfig:              Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				InfrastructureProviders: tt.infrastructureProvider,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			gotProviders := []string{}
+			for _, components := range got {
+				gotProviders = append(gotProviders, components.ManifestLabel())
+
+				yaml, err := components.Yaml()
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(yaml)).ToNot(ContainSubstring("${SOME_VARIABLE}"))
+			}
+			g.Expect(gotProviders).To(Equal(tt.wantProviders))
+		})
+	}
+}
+
 func Test_clusterctlClient_Init(t *testing.T) {
 	// create a config variables client which does not have the value for
 	// SOME_VARIABLE as expected in the infra components YAML
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// MachineOptions carries the base set of options supported by the machine commands.
+type MachineOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Machines are the names of the Machines to operate on.
+	Machines []string
+
+	// Namespace where the Machine(s) live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+
+	// DrainTimeout is the time to wait for the pods to be evicted when issuing the drain command; zero means wait forever.
+	DrainTimeout time.Duration
+
+	// DeleteEmptyDirData allows to evict pods using emptyDir volumes when issuing the drain command.
+	DeleteEmptyDirData bool
+}
+
+func (c *clusterctlClient) MachineDrain(options MachineOptions) error {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+	machineRefs, err := getMachineRefs(clusterClient, options)
+	if err != nil {
+		return err
+	}
+	drainOptions := alpha.DrainOptions{
+		Timeout:            options.DrainTimeout,
+		DeleteEmptyDirData: options.DeleteEmptyDirData,
+	}
+	for _, ref := range machineRefs {
+		if err := c.alphaClient.Machine().MachineDrainer(clusterClient.Proxy(), ref, drainOptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *clusterctlClient) MachineDelete(options MachineOptions) error {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+	machineRefs, err := getMachineRefs(clusterClient, options)
+	if err != nil {
+		return err
+	}
+	for _, ref := range machineRefs {
+		if err := c.alphaClient.Machine().MachineDeleter(clusterClient.Proxy(), ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *clusterctlClient) MachineRemediate(options MachineOptions) error {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+	machineRefs, err := getMachineRefs(clusterClient, options)
+	if err != nil {
+		return err
+	}
+	for _, ref := range machineRefs {
+		if err := c.alphaClient.Machine().MachineRemediator(clusterClient.Proxy(), ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getMachineRefs(clusterClient cluster.Client, options MachineOptions) ([]corev1.ObjectReference, error) {
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return []corev1.ObjectReference{}, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	if len(options.Machines) == 0 {
+		return []corev1.ObjectReference{}, fmt.Errorf("required machine not specified")
+	}
+	refs := make([]corev1.ObjectReference, 0, len(options.Machines))
+	for _, name := range options.Machines {
+		refs = append(refs, corev1.ObjectReference{
+			Kind:      "Machine",
+			Name:      name,
+			Namespace: options.Namespace,
+		})
+	}
+	return refs, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// MirrorRepositoriesOptions carries the options supported by MirrorRepositories.
+type MirrorRepositoriesOptions struct {
+	// MirrorFolder is the folder where the mirror of the provider repositories should be created.
+	MirrorFolder string
+
+	// CoreProvider version (e.g. cluster-api:v0.3.0) to add to the mirror.
+	CoreProvider string
+
+	// BootstrapProviders and versions (e.g. kubeadm:v0.3.0) to add to the mirror.
+	BootstrapProviders []string
+
+	// ControlPlaneProviders and versions (e.g. kubeadm:v0.3.0) to add to the mirror.
+	ControlPlaneProviders []string
+
+	// InfrastructureProviders and versions (e.g. aws:v0.5.0) to add to the mirror.
+	InfrastructureProviders []string
+
+	// NOTE: If no provider is specified, all the providers known by clusterctl are added to the mirror.
+	// If the version of a provider is not specified, the latest release for the current Cluster API contract is used.
+}
+
+func (c *clusterctlClient) MirrorRepositories(options MirrorRepositoriesOptions) error {
+	log := logf.Log
+
+	if options.MirrorFolder == "" {
+		return errors.New("invalid mirror folder. Please specify a mirror folder")
+	}
+	mirrorFolder, err := filepath.Abs(options.MirrorFolder)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the absolute path for the mirror folder %q", options.MirrorFolder)
+	}
+
+	providers, err := c.getProvidersToMirror(options)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range providers {
+		providerConfig, err := c.configClient.Providers().Get(p.name, p.providerType)
+		if err != nil {
+			return err
+		}
+
+		// NB. Providers are read from their repository URL, even if a mirror is already configured.
+		repositoryClient, err := c.repositoryClientFactory(RepositoryClientFactoryInput{Provider: providerConfig, IgnoreMirror: true})
+		if err != nil {
+			return err
+		}
+
+		// NB. If the version is not set, the default version of the provider repository is mirrored.
+		log.Info("Mirroring", "Provider", providerConfig.ManifestLabel(), "Version", p.version, "MirrorFolder", mirrorFolder)
+		if err := repositoryClient.Mirror(p.version, mirrorFolder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// providerToMirror identifies a provider and version to be added to the mirror.
+type providerToMirror struct {
+	name         string
+	version      string
+	providerType clusterctlv1.ProviderType
+}
+
+// getProvidersToMirror returns the list of providers to be added to the mirror.
+func (c *clusterctlClient) getProvidersToMirror(options MirrorRepositoriesOptions) ([]providerToMirror, error) {
+	ret := []providerToMirror{}
+
+	addProviders := func(providerType clusterctlv1.ProviderType, providers ...string) error {
+		for _, provider := range providers {
+			name, version, err := parseProviderName(provider)
+			if err != nil {
+				return err
+			}
+			ret = append(ret, providerToMirror{name: name, version: version, providerType: providerType})
+		}
+		return nil
+	}
+
+	if options.CoreProvider != "" {
+		if err := addProviders(clusterctlv1.CoreProviderType, options.CoreProvider); err != nil {
+			return nil, err
+		}
+	}
+	if err := addProviders(clusterctlv1.BootstrapProviderType, options.BootstrapProviders...); err != nil {
+		return nil, err
+	}
+	if err := addProviders(clusterctlv1.ControlPlaneProviderType, options.ControlPlaneProviders...); err != nil {
+		return nil, err
+	}
+	if err := addProviders(clusterctlv1.InfrastructureProviderType, options.InfrastructureProviders...); err != nil {
+		return nil, err
+	}
+
+	if len(ret) > 0 {
+		return ret, nil
+	}
+
+	// If no provider is specified, all the providers known by clusterctl are added to the mirror.
+	providers, err := c.configClient.Providers().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range providers {
+		ret = append(ret, providerToMirror{name: p.Name(), providerType: p.Type()})
+	}
+	return ret, nil
+}
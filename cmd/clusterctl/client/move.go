@@ -19,6 +19,8 @@ package client
 import (
 	"os"
 
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
 
@@ -36,8 +38,24 @@ type MoveOptions struct {
 	// namespace will be used.
 	Namespace string
 
+	// ClusterNames restricts the move to the Clusters with the given names. If unspecified, all the Clusters
+	// in the namespace will be moved.
+	ClusterNames []string
+
+	// ClusterSelector restricts the move to the Clusters matching the given label selector. If unspecified, all the Clusters
+	// in the namespace will be moved.
+	ClusterSelector string
+
 	// DryRun means the move action is a dry run, no real action will be performed
 	DryRun bool
+
+	// ToDirectory defines a local directory where to save the objects to be moved instead of creating them in a target
+	// management cluster; the move can be then completed using FromDirectory, also from a different machine.
+	ToDirectory string
+
+	// FromDirectory defines a local directory created using ToDirectory, from where to read the objects to be created
+	// in the target management cluster instead of reading them from a source management cluster.
+	FromDirectory string
 }
 
 // BackupOptions holds options supported by backup.
@@ -65,6 +83,17 @@ type RestoreOptions struct {
 }
 
 func (c *clusterctlClient) Move(options MoveOptions) error {
+	if options.ToDirectory != "" && options.FromDirectory != "" {
+		return errors.New("ToDirectory and FromDirectory cannot be used together")
+	}
+	if options.DryRun && (options.ToDirectory != "" || options.FromDirectory != "") {
+		return errors.New("DryRun cannot be used together with ToDirectory or FromDirectory")
+	}
+
+	if options.FromDirectory != "" {
+		return c.moveFromDirectory(options)
+	}
+
 	// Get the client for interacting with the source management cluster.
 	fromCluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.FromKubeconfig})
 	if err != nil {
@@ -82,7 +111,7 @@ func (c *clusterctlClient) Move(options MoveOptions) error {
 	}
 
 	var toCluster cluster.Client
-	if !options.DryRun {
+	if !options.DryRun && options.ToDirectory == "" {
 		// Get the client for interacting with the target management cluster.
 		toCluster, err = c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.ToKubeconfig})
 		if err != nil {
@@ -109,7 +138,90 @@ func (c *clusterctlClient) Move(options MoveOptions) error {
 		options.Namespace = currentNamespace
 	}
 
-	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun)
+	filter, err := getClusterFilter(options)
+	if err != nil {
+		return err
+	}
+
+	if options.ToDirectory != "" {
+		return fromCluster.ObjectMover().ToDirectory(options.Namespace, options.ToDirectory, filter)
+	}
+
+	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun, filter)
+}
+
+// moveFromDirectory creates in the target management cluster the objects saved in a directory by a move with ToDirectory.
+func (c *clusterctlClient) moveFromDirectory(options MoveOptions) error {
+	// Get the client for interacting with the target management cluster.
+	toCluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.ToKubeconfig})
+	if err != nil {
+		return err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := toCluster.ProviderInventory().CheckCAPIContract(); err != nil {
+		return err
+	}
+
+	// Ensures the custom resource definitions required by clusterctl are in place.
+	if err := toCluster.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(options.FromDirectory); err != nil {
+		return errors.Wrapf(err, "failed to read directory %s", options.FromDirectory)
+	}
+
+	return toCluster.ObjectMover().FromDirectory(toCluster, options.FromDirectory)
+}
+
+func (c *clusterctlClient) GetMoveGraph(options MoveOptions) (*cluster.MoveGraph, error) {
+	// Get the client for interacting with the source management cluster.
+	fromCluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.FromKubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := fromCluster.ProviderInventory().CheckCAPIContract(); err != nil {
+		return nil, err
+	}
+
+	// Ensures the custom resource definitions required by clusterctl are in place.
+	if err := fromCluster.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := fromCluster.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	filter, err := getClusterFilter(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromCluster.ObjectMover().Graph(options.Namespace, filter)
+}
+
+// getClusterFilter returns the filter for the Clusters to be moved.
+func getClusterFilter(options MoveOptions) (cluster.ClusterFilter, error) {
+	filter := cluster.ClusterFilter{
+		Names: options.ClusterNames,
+	}
+	if options.ClusterSelector != "" {
+		selector, err := labels.Parse(options.ClusterSelector)
+		if err != nil {
+			return cluster.ClusterFilter{}, errors.Wrapf(err, "failed to parse Cluster selector %q", options.ClusterSelector)
+		}
+		filter.Selector = selector
+	}
+	return filter, nil
 }
 
 func (c *clusterctlClient) Backup(options BackupOptions) error {
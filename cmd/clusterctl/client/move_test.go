@@ -19,6 +19,7 @@ package client
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -29,6 +30,12 @@ import (
 )
 
 func Test_clusterctlClient_Move(t *testing.T) {
+	dir, err := ioutil.TempDir("/tmp", "cluster-api")
+	if err != nil {
+		t.Error(err)
+	}
+	defer os.RemoveAll(dir)
+
 	type fields struct {
 		client *fakeClient
 	}
@@ -80,6 +87,59 @@ func Test_clusterctlClient_Move(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "does not return error if moving to a directory",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:    dir,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "does not return error if moving from a directory",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					ToKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					FromDirectory: dir,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if moving from a directory that does not exist",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					ToKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					FromDirectory: filepath.Join(dir, "does-not-exist"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if moving both to and from a directory",
+			fields: fields{
+				client: fakeClientForMove(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:    dir,
+					FromDirectory:  dir,
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +156,73 @@ func Test_clusterctlClient_Move(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_GetMoveGraph(t *testing.T) {
+	type fields struct {
+		client *fakeClient
+	}
+	type args struct {
+		options MoveOptions
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "does not return error if cluster client is found",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if from cluster client is not found",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "does-not-exist"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if the cluster selector is not valid",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ClusterSelector: "env in (dev",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			graph, err := tt.fields.client.GetMoveGraph(tt.args.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(graph).NotTo(BeNil())
+		})
+	}
+}
+
 func Test_clusterctlClient_Backup(t *testing.T) {
 	dir, err := ioutil.TempDir("/tmp", "cluster-api")
 	if err != nil {
@@ -255,10 +382,14 @@ type fakeObjectMover struct {
 	restoerErr error
 }
 
-func (f *fakeObjectMover) Move(namespace string, toCluster cluster.Client, dryRun bool) error {
+func (f *fakeObjectMover) Move(namespace string, toCluster cluster.Client, dryRun bool, filter cluster.ClusterFilter) error {
 	return f.moveErr
 }
 
+func (f *fakeObjectMover) Graph(namespace string, filter cluster.ClusterFilter) (*cluster.MoveGraph, error) {
+	return &cluster.MoveGraph{}, f.moveErr
+}
+
 func (f *fakeObjectMover) Backup(namespace string, directory string) error {
 	return f.backupErr
 }
@@ -266,3 +397,11 @@ func (f *fakeObjectMover) Backup(namespace string, directory string) error {
 func (f *fakeObjectMover) Restore(toCluster cluster.Client, directory string) error {
 	return f.restoerErr
 }
+
+func (f *fakeObjectMover) ToDirectory(namespace string, directory string, filter cluster.ClusterFilter) error {
+	return f.backupErr
+}
+
+func (f *fakeObjectMover) FromDirectory(toCluster cluster.Client, directory string) error {
+	return f.restoerErr
+}
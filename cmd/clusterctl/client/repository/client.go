@@ -43,6 +43,14 @@ type Client interface {
 
 	// Metadata provide access to YAML with the provider's metadata.
 	Metadata(version string) MetadataClient
+
+	// Mirror copies the components YAML, the metadata YAML and the workload cluster templates for a provider version
+	// into a mirror folder, so the provider can be read from the mirror by setting the mirrorFolder config variable.
+	Mirror(version, mirrorFolder string) error
+
+	// Variables returns the variables consumed by each of the YAML files for a provider version, i.e. the components YAML
+	// and the workload cluster templates, indexed by file name.
+	Variables(version string) (map[string][]string, error)
 }
 
 // repositoryClient implements Client.
@@ -51,6 +59,11 @@ type repositoryClient struct {
 	configClient config.Client
 	repository   Repository
 	processor    yaml.Processor
+	ignoreMirror bool
+
+	// processorFactory selects the yaml processor for the cluster templates according to the provider's metadata;
+	// it is nil when a yaml processor is injected, because the injected processor takes precedence.
+	processorFactory TemplateProcessorFactory
 }
 
 // ensure repositoryClient implements Client.
@@ -65,13 +78,35 @@ func (c *repositoryClient) Components() ComponentsClient {
 }
 
 func (c *repositoryClient) Templates(version string) TemplateClient {
-	return newTemplateClient(TemplateClientInput{version, c.Provider, c.repository, c.configClient.Variables(), c.processor})
+	return newTemplateClient(TemplateClientInput{version, c.Provider, c.repository, c.configClient.Variables(), c.processor, c.processorFactory})
 }
 
 func (c *repositoryClient) Metadata(version string) MetadataClient {
 	return newMetadataClient(c.Provider, version, c.repository, c.configClient.Variables())
 }
 
+func (c *repositoryClient) Mirror(version, mirrorFolder string) error {
+	if version == "" {
+		version = c.repository.DefaultVersion()
+	}
+	return mirrorFiles(c.Provider, c.repository, version, mirrorFolder)
+}
+
+func (c *repositoryClient) Variables(version string) (map[string][]string, error) {
+	if version == "" {
+		version = c.repository.DefaultVersion()
+	}
+	processor := c.processor
+	if c.processorFactory != nil {
+		var err error
+		processor, err = getTemplateProcessor(c.Provider, version, c.repository, c.configClient.Variables(), c.processorFactory)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return listVariables(c.Provider, c.repository, c.configClient.Variables(), processor, version)
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*repositoryClient)
 
@@ -85,16 +120,26 @@ func InjectRepository(repository Repository) Option {
 }
 
 // InjectYamlProcessor allows you to override the yaml processor that the
-// repository client uses. By default, the SimpleProcessor is used. This is
-// true even if a nil processor is injected.
+// repository client uses. By default, the processor defined in the provider's
+// metadata is used for the cluster templates, falling back to the SimpleProcessor
+// if none is defined. This is true even if a nil processor is injected.
 func InjectYamlProcessor(p yaml.Processor) Option {
 	return func(c *repositoryClient) {
 		if p != nil {
 			c.processor = p
+			c.processorFactory = nil
 		}
 	}
 }
 
+// IgnoreMirror allows to read the provider from its repository URL, even if the mirrorFolder config variable is set;
+// this is required e.g. when creating the mirror.
+func IgnoreMirror() Option {
+	return func(c *repositoryClient) {
+		c.ignoreMirror = true
+	}
+}
+
 // New returns a Client.
 func New(provider config.Provider, configClient config.Client, options ...Option) (Client, error) {
 	return newRepositoryClient(provider, configClient, options...)
@@ -102,9 +147,10 @@ func New(provider config.Provider, configClient config.Client, options ...Option
 
 func newRepositoryClient(provider config.Provider, configClient config.Client, options ...Option) (*repositoryClient, error) {
 	client := &repositoryClient{
-		Provider:     provider,
-		configClient: configClient,
-		processor:    yaml.NewSimpleProcessor(),
+		Provider:         provider,
+		configClient:     configClient,
+		processor:        yaml.NewSimpleProcessor(),
+		processorFactory: defaultTemplateProcessorFactory,
 	}
 	for _, o := range options {
 		o(client)
@@ -112,7 +158,7 @@ func newRepositoryClient(provider config.Provider, configClient config.Client, o
 
 	// if there is an injected repository, use it, otherwise use a default one
 	if client.repository == nil {
-		r, err := repositoryFactory(provider, configClient.Variables())
+		r, err := repositoryFactory(provider, configClient.Variables(), client.ignoreMirror)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get repository client for the %s with name %s", provider.Type(), provider.Name())
 		}
@@ -148,7 +194,17 @@ type Repository interface {
 }
 
 // repositoryFactory returns the repository implementation corresponding to the provider URL.
-func repositoryFactory(providerConfig config.Provider, configVariablesClient config.VariablesClient) (Repository, error) {
+// If a mirror folder is configured, the provider is read from the mirror instead, unless ignoreMirror is set.
+func repositoryFactory(providerConfig config.Provider, configVariablesClient config.VariablesClient, ignoreMirror bool) (Repository, error) {
+	// if a mirror of the provider repositories is configured
+	if mirrorFolder := getMirrorFolder(configVariablesClient); mirrorFolder != "" && !ignoreMirror {
+		repo, err := newMirrorRepository(providerConfig, configVariablesClient, mirrorFolder)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating the repository client for the mirror %q", mirrorFolder)
+		}
+		return repo, err
+	}
+
 	// parse the repository url
 	rURL, err := url.Parse(providerConfig.URL())
 	if err != nil {
@@ -164,6 +220,15 @@ func repositoryFactory(providerConfig config.Provider, configVariablesClient con
 		return repo, err
 	}
 
+	// if the url is an OCI repository
+	if rURL.Scheme+"://" == ociScheme {
+		repo, err := newOCIRepository(providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the OCI repository client")
+		}
+		return repo, err
+	}
+
 	// if the url is a local filesystem repository
 	if rURL.Scheme == "file" || rURL.Scheme == "" {
 		repo, err := newLocalRepository(providerConfig, configVariablesClient)
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const (
+	// MirrorFolderKey is the name of the clusterctl config variable defining the folder hosting a mirror of the provider
+	// repositories; when set, all the providers are read from the mirror instead of their repository URL.
+	MirrorFolderKey = "mirrorFolder"
+
+	// clusterTemplatePrefix is the prefix of the name of the workload cluster templates hosted in a provider repository.
+	clusterTemplatePrefix = "cluster-template"
+)
+
+// fileLister is implemented by repositories which can list the files available for a provider version.
+type fileLister interface {
+	listFiles(version string) ([]string, error)
+}
+
+// getMirrorFolder returns the folder hosting the mirror of the provider repositories, if any.
+func getMirrorFolder(configVariablesClient config.VariablesClient) string {
+	mirrorFolder, err := configVariablesClient.Get(MirrorFolderKey)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(mirrorFolder)
+}
+
+// newMirrorRepository returns a repository reading a provider from a mirror folder.
+// The mirror uses the same layout of the local repositories, {mirror-folder}/{provider-label}/{version}/{file},
+// so the provider is read using a localRepository pointing to the latest version in the mirror; the name of the
+// components YAML is derived from the provider's repository URL.
+func newMirrorRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient, mirrorFolder string) (*localRepository, error) {
+	rURL, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Errorf("failed to parse repository url %q", providerConfig.URL())
+	}
+
+	mirrorURL := filepath.ToSlash(filepath.Join(mirrorFolder, providerConfig.ManifestLabel(), latestVersionTag, path.Base(rURL.Path)))
+	if runtime.GOOS == "windows" {
+		// in case of windows, local paths should be expressed as an URI, e.g. /C:/mirror/...
+		mirrorURL = "/" + mirrorURL
+	}
+
+	return newLocalRepository(config.NewProvider(providerConfig.Name(), mirrorURL, providerConfig.Type()), configVariablesClient)
+}
+
+// mirrorFiles copies the files for a provider version into a mirror folder: the components YAML, the metadata YAML
+// and the workload cluster templates. Templates are copied only from repositories that can list their files.
+func mirrorFiles(provider config.Provider, repository Repository, version, mirrorFolder string) error {
+	log := logf.Log
+
+	files := []string{repository.ComponentsPath(), metadataFile}
+	if lister, ok := repository.(fileLister); ok {
+		repositoryFiles, err := lister.listFiles(version)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list files for provider %q version %s", provider.ManifestLabel(), version)
+		}
+		for _, f := range repositoryFiles {
+			if strings.HasPrefix(f, clusterTemplatePrefix) && strings.HasSuffix(f, ".yaml") {
+				files = append(files, f)
+			}
+		}
+	}
+
+	versionFolder := filepath.Join(mirrorFolder, provider.ManifestLabel(), version)
+	if err := os.MkdirAll(versionFolder, 0750); err != nil {
+		return errors.Wrapf(err, "failed to create mirror folder %q", versionFolder)
+	}
+
+	for _, f := range files {
+		log.V(3).Info("Mirroring", "File", f, "Provider", provider.ManifestLabel(), "Version", version)
+		content, err := repository.GetFile(version, f)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q from the repository for provider %q", f, provider.ManifestLabel())
+		}
+		if err := os.WriteFile(filepath.Join(versionFolder, f), content, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write %q into the mirror folder %q", f, versionFolder)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_mirrorFiles(t *testing.T) {
+	g := NewWithT(t)
+
+	mirrorFolder := createTempDir(t)
+	defer os.RemoveAll(mirrorFolder)
+
+	provider := config.NewProvider("foo", "https://github.com/o/r/releases/latest/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType)
+	repository := NewMemoryRepository().
+		WithPaths("root", "infrastructure-components.yaml").
+		WithDefaultVersion("v1.0.0").
+		WithFile("v1.0.0", "infrastructure-components.yaml", []byte("components")).
+		WithFile("v1.0.0", "cluster-template.yaml", []byte("template")).
+		WithFile("v1.0.0", "cluster-template-prod.yaml", []byte("template-prod")).
+		WithFile("v1.0.0", "something-else.yaml", []byte("something-else")).
+		WithMetadata("v1.0.0", &clusterctlv1.Metadata{
+			ReleaseSeries: []clusterctlv1.ReleaseSeries{
+				{Major: 1, Minor: 0, Contract: test.CurrentCAPIContract},
+			},
+		})
+
+	g.Expect(mirrorFiles(provider, repository, "v1.0.0", mirrorFolder)).To(Succeed())
+
+	files, err := os.ReadDir(filepath.Join(mirrorFolder, "infrastructure-foo", "v1.0.0"))
+	g.Expect(err).NotTo(HaveOccurred())
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	g.Expect(names).To(ConsistOf("infrastructure-components.yaml", "metadata.yaml", "cluster-template.yaml", "cluster-template-prod.yaml"))
+
+	// Reads the provider from the mirror.
+	mirror, err := repositoryFactory(provider, test.NewFakeVariableClient().WithVar(MirrorFolderKey, mirrorFolder), false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mirror.DefaultVersion()).To(Equal("v1.0.0"))
+	g.Expect(mirror.ComponentsPath()).To(Equal("infrastructure-components.yaml"))
+
+	content, err := mirror.GetFile("v1.0.0", "cluster-template-prod.yaml")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(content)).To(Equal("template-prod"))
+
+	// Ignores the mirror if required.
+	original, err := repositoryFactory(config.NewProvider("foo", filepath.Join(mirrorFolder, "infrastructure-foo", "v1.0.0", "infrastructure-components.yaml"), clusterctlv1.InfrastructureProviderType), test.NewFakeVariableClient().WithVar(MirrorFolderKey, "/does-not-exist"), true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(original.DefaultVersion()).To(Equal("v1.0.0"))
+}
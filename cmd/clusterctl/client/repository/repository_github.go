@@ -116,6 +116,24 @@ func (g *gitHubRepository) GetFile(version, path string) ([]byte, error) {
 	return files, nil
 }
 
+// listFiles returns the list of files attached to the GitHub release for a given version.
+func (g *gitHubRepository) listFiles(version string) ([]string, error) {
+	release, err := g.getReleaseByTag(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get GitHub release %s", version)
+	}
+
+	files := []string{}
+	for _, a := range release.Assets {
+		if a.Name == nil {
+			continue
+		}
+		// NB. asset names include the root path, while the files returned by this func should be relative to it.
+		files = append(files, strings.TrimPrefix(strings.TrimPrefix(*a.Name, g.rootPath), "/"))
+	}
+	return files, nil
+}
+
 // newGitHubRepository returns a gitHubRepository implementation.
 func newGitHubRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient, opts ...githubRepositoryOption) (*gitHubRepository, error) {
 	if configVariablesClient == nil {
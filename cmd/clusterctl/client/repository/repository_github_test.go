@@ -718,4 +718,7 @@ func resetCaches() {
 	cacheVersions = map[string][]string{}
 	cacheReleases = map[string]*github.RepositoryRelease{}
 	cacheFiles = map[string][]byte{}
+	cacheOCIVersions = map[string][]string{}
+	cacheOCIManifests = map[string]*ociManifest{}
+	cacheOCIFiles = map[string][]byte{}
 }
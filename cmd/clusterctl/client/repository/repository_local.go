@@ -134,6 +134,23 @@ func (r *localRepository) GetVersions() ([]string, error) {
 	return versions, nil
 }
 
+// listFiles returns the list of files available for a given version of a local repository.
+func (r *localRepository) listFiles(version string) ([]string, error) {
+	versionPath := filepath.Join(r.basepath, r.providerLabel, version, r.RootPath())
+	entries, err := os.ReadDir(versionPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list files for local release %s", version)
+	}
+	files := []string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		files = append(files, e.Name())
+	}
+	return files, nil
+}
+
 // newLocalRepository returns a new localRepository.
 func newLocalRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient) (*localRepository, error) {
 	url, err := url.Parse(providerConfig.URL())
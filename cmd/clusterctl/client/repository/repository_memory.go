@@ -18,6 +18,8 @@ package repository
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -95,6 +97,21 @@ func (f *MemoryRepository) GetFile(version string, path string) ([]byte, error)
 	return nil, errors.Errorf("unable to get file %s for version %s", path, version)
 }
 
+// listFiles returns the list of files available for a given version.
+func (f *MemoryRepository) listFiles(version string) ([]string, error) {
+	if _, ok := f.versions[version]; !ok {
+		return nil, errors.Errorf("unable to get files for version %s", version)
+	}
+	files := []string{}
+	for p := range f.files {
+		if strings.HasPrefix(p, vpath(version, "")) {
+			files = append(files, strings.TrimPrefix(p, vpath(version, "")))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 // GetVersions returns the list of versions that are available.
 func (f *MemoryRepository) GetVersions() ([]string, error) {
 	v := make([]string, 0, len(f.versions))
@@ -0,0 +1,451 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	ociScheme = "oci://"
+
+	// ociImageTitleAnnotation is the annotation defining the name of the file stored in an OCI artifact layer.
+	ociImageTitleAnnotation = "org.opencontainers.image.title"
+	// ociImageVersionAnnotation is the annotation defining the version of an OCI artifact.
+	ociImageVersionAnnotation = "org.opencontainers.image.version"
+
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+
+	dockerHubDomain      = "docker.io"
+	dockerHubRegistryAPI = "registry-1.docker.io"
+)
+
+var (
+	// Caches used to limit the number of OCI registry API calls.
+
+	cacheOCIVersions  = map[string][]string{}
+	cacheOCIManifests = map[string]*ociManifest{}
+	cacheOCIFiles     = map[string][]byte{}
+)
+
+// ociRepository provides support for providers published as OCI artifacts into an OCI registry.
+//
+// Each release of the provider is expected to be pushed as an OCI artifact tagged with the release version, with
+// one layer for each file, e.g. the components YAML, the metadata YAML and the cluster templates; the name of
+// each file is read from the org.opencontainers.image.title annotation of the layer, like e.g. oras does.
+// The URL is expected to be in the form:
+// oci://{registry}/{repository}[:{latest|version-tag}][@{digest}]/{components.yaml}
+//
+// When a digest is provided, the manifest of the artifact is pinned to that digest; if no tag is provided together
+// with the digest, the version is read from the org.opencontainers.image.version annotation of the manifest.
+type ociRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	registry              string
+	repository            string
+	defaultVersion        string
+	componentsPath        string
+	pinnedDigest          string
+	httpClient            *http.Client
+	authorization         string
+}
+
+var _ Repository = &ociRepository{}
+
+type ociRepositoryOption func(*ociRepository)
+
+func injectOCIHTTPClient(c *http.Client) ociRepositoryOption {
+	return func(r *ociRepository) {
+		r.httpClient = c
+	}
+}
+
+// ociManifest is the subset of an OCI image manifest used by clusterctl.
+type ociManifest struct {
+	Layers      []ociDescriptor   `json:"layers"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociDescriptor is the subset of an OCI content descriptor used by clusterctl.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DefaultVersion returns defaultVersion field of ociRepository struct.
+func (r *ociRepository) DefaultVersion() string {
+	return r.defaultVersion
+}
+
+// RootPath returns the empty string as it is not applicable to OCI repositories.
+func (r *ociRepository) RootPath() string {
+	return ""
+}
+
+// ComponentsPath returns componentsPath field of ociRepository struct.
+func (r *ociRepository) ComponentsPath() string {
+	return r.componentsPath
+}
+
+// GetFile returns a file for a given provider version.
+func (r *ociRepository) GetFile(version, fileName string) ([]byte, error) {
+	if version == "" {
+		version = r.defaultVersion
+	}
+
+	manifest, err := r.getManifest(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get OCI artifact %s", version)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.Annotations[ociImageTitleAnnotation] == fileName {
+			content, err := r.getBlob(layer)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to download file %q from OCI artifact %s", fileName, version)
+			}
+			return content, nil
+		}
+	}
+	return nil, errors.Errorf("failed to get file %q from OCI artifact %s", fileName, version)
+}
+
+// GetVersions returns the list of versions that are available in a provider repository.
+func (r *ociRepository) GetVersions() ([]string, error) {
+	versions, err := r.getVersions()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository versions")
+	}
+	return versions, nil
+}
+
+// listFiles returns the list of files stored in the OCI artifact for a given version.
+func (r *ociRepository) listFiles(version string) ([]string, error) {
+	manifest, err := r.getManifest(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get OCI artifact %s", version)
+	}
+
+	files := []string{}
+	for _, layer := range manifest.Layers {
+		if title := layer.Annotations[ociImageTitleAnnotation]; title != "" {
+			files = append(files, title)
+		}
+	}
+	return files, nil
+}
+
+// newOCIRepository returns an ociRepository implementation.
+func newOCIRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient, opts ...ociRepositoryOption) (*ociRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	if !strings.HasPrefix(providerConfig.URL(), ociScheme) {
+		return nil, errors.Errorf("invalid url: an OCI repository url should start with %s", ociScheme)
+	}
+
+	// Split the url into the artifact reference and the components file name.
+	refAndPath := strings.TrimPrefix(providerConfig.URL(), ociScheme)
+	ref := path.Dir(refAndPath)
+	componentsPath := path.Base(refAndPath)
+	if ref == "." || !strings.HasSuffix(componentsPath, ".yaml") {
+		return nil, errors.Errorf("invalid url: an OCI repository url should be in the form %s{registry}/{repository}[:{latest|version-tag}][@{digest}]/{components.yaml}", ociScheme)
+	}
+
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid url: failed to parse OCI artifact reference %q", ref)
+	}
+
+	repo := &ociRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		registry:              reference.Domain(named),
+		repository:            reference.Path(named),
+		defaultVersion:        latestVersionTag,
+		componentsPath:        componentsPath,
+		httpClient:            http.DefaultClient,
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		repo.defaultVersion = tagged.Tag()
+	}
+
+	// process ociRepositoryOptions
+	for _, o := range opts {
+		o(repo)
+	}
+
+	// If the artifact is pinned to a digest, the default version is always read from the pinned manifest.
+	if digested, ok := named.(reference.Digested); ok {
+		repo.pinnedDigest = digested.Digest().String()
+		if repo.defaultVersion == latestVersionTag {
+			manifest, err := repo.getManifestByReference(repo.pinnedDigest)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get OCI artifact %s", repo.pinnedDigest)
+			}
+			v, ok := manifest.Annotations[ociImageVersionAnnotation]
+			if !ok {
+				return nil, errors.Errorf("invalid OCI artifact %s: the %s annotation is required when the url does not define a version tag", repo.pinnedDigest, ociImageVersionAnnotation)
+			}
+			repo.defaultVersion = v
+		}
+		return repo, nil
+	}
+
+	if repo.defaultVersion == latestVersionTag {
+		repo.defaultVersion, err = latestContractRelease(repo, clusterv1.GroupVersion.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get OCI latest version")
+		}
+	}
+
+	return repo, nil
+}
+
+// registryURL returns the base URL of the registry API for the repository.
+func (r *ociRepository) registryURL() string {
+	host := r.registry
+	if host == dockerHubDomain {
+		host = dockerHubRegistryAPI
+	}
+
+	// NB. registries on the local host are accessed using http, like the container runtimes do by default.
+	scheme := httpsScheme
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if hostname == "localhost" || net.ParseIP(hostname).IsLoopback() {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s", scheme, host, r.repository)
+}
+
+// getVersions returns all the tags of the OCI repository which are valid semantic versions.
+func (r *ociRepository) getVersions() ([]string, error) {
+	cacheID := fmt.Sprintf("%s/%s", r.registry, r.repository)
+	if versions, ok := cacheOCIVersions[cacheID]; ok {
+		return versions, nil
+	}
+
+	versions := []string{}
+	tagsURL := r.registryURL() + "/tags/list"
+	for tagsURL != "" {
+		content, header, err := r.get(tagsURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the list of tags")
+		}
+
+		tagList := struct {
+			Tags []string `json:"tags"`
+		}{}
+		if err := json.Unmarshal(content, &tagList); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the list of tags")
+		}
+		for _, tag := range tagList.Tags {
+			if _, err := version.ParseSemantic(tag); err != nil {
+				// Discard tags that are not a valid semantic versions (the user can point explicitly to such tags).
+				continue
+			}
+			versions = append(versions, tag)
+		}
+
+		tagsURL, err = nextPageURL(tagsURL, header.Get("Link"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cacheOCIVersions[cacheID] = versions
+	return versions, nil
+}
+
+// getManifest returns the manifest of the OCI artifact for a version.
+// NB. if the repository is pinned to a digest, the manifest for the default version is read using the digest.
+func (r *ociRepository) getManifest(version string) (*ociManifest, error) {
+	if r.pinnedDigest != "" && version == r.defaultVersion {
+		return r.getManifestByReference(r.pinnedDigest)
+	}
+	return r.getManifestByReference(version)
+}
+
+// getManifestByReference returns the manifest of the OCI artifact with a given tag or digest.
+func (r *ociRepository) getManifestByReference(ref string) (*ociManifest, error) {
+	cacheID := fmt.Sprintf("%s/%s:%s", r.registry, r.repository, ref)
+	if manifest, ok := cacheOCIManifests[cacheID]; ok {
+		return manifest, nil
+	}
+
+	content, _, err := r.get(fmt.Sprintf("%s/manifests/%s", r.registryURL(), ref), ociManifestMediaType, dockerManifestMediaType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read manifest %q", ref)
+	}
+
+	// If the manifest is read by digest, ensure the content is the expected one.
+	if strings.Contains(ref, ":") {
+		if err := verifyDigest(content, ref); err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest %q", ref)
+		}
+	}
+
+	manifest := &ociManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse manifest %q", ref)
+	}
+
+	cacheOCIManifests[cacheID] = manifest
+	return manifest, nil
+}
+
+// getBlob downloads a blob from the OCI repository, verifying its digest.
+func (r *ociRepository) getBlob(descriptor ociDescriptor) ([]byte, error) {
+	cacheID := fmt.Sprintf("%s/%s@%s", r.registry, r.repository, descriptor.Digest)
+	if content, ok := cacheOCIFiles[cacheID]; ok {
+		return content, nil
+	}
+
+	content, _, err := r.get(fmt.Sprintf("%s/blobs/%s", r.registryURL(), descriptor.Digest))
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyDigest(content, descriptor.Digest); err != nil {
+		return nil, errors.Wrapf(err, "invalid blob %q", descriptor.Digest)
+	}
+
+	cacheOCIFiles[cacheID] = content
+	return content, nil
+}
+
+// get executes a GET request against the registry API, authenticating if required by the registry.
+func (r *ociRepository) get(requestURL string, accept ...string) ([]byte, http.Header, error) {
+	response, err := r.do(requestURL, accept...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		response.Body.Close()
+
+		if err := r.authorize(challenge); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to authenticate to the %s registry", r.registry)
+		}
+		response, err = r.do(requestURL, accept...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, nil, errors.Errorf("failed to get %s: %s", requestURL, response.Status)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read response from %s", requestURL)
+	}
+	return content, response.Header, nil
+}
+
+func (r *ociRepository) do(requestURL string, accept ...string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, requestURL, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create request for %s", requestURL)
+	}
+	if len(accept) > 0 {
+		request.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if r.authorization != "" {
+		request.Header.Set("Authorization", r.authorization)
+	}
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s", requestURL)
+	}
+	return response, nil
+}
+
+// verifyDigest checks that content matches a digest in the form {algorithm}:{hex}.
+func verifyDigest(content []byte, digest string) error {
+	algorithm, expected := "", digest
+	if i := strings.Index(digest, ":"); i >= 0 {
+		algorithm, expected = digest[:i], digest[i+1:]
+	}
+
+	var actual string
+	switch algorithm {
+	case "sha256":
+		sum := sha256.Sum256(content)
+		actual = hex.EncodeToString(sum[:])
+	case "sha512":
+		sum := sha512.Sum512(content)
+		actual = hex.EncodeToString(sum[:])
+	default:
+		return errors.Errorf("unsupported digest %q", digest)
+	}
+
+	if actual != expected {
+		return errors.Errorf("digest mismatch: expected %s, got %s:%s", digest, algorithm, actual)
+	}
+	return nil
+}
+
+// nextPageURL returns the URL of the next page from a Link header, if any.
+func nextPageURL(currentURL, link string) (string, error) {
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return "", nil
+	}
+
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start < 0 || end < start {
+		return "", errors.Errorf("invalid Link header %q", link)
+	}
+
+	// NB. the next page URL is usually relative to the current one.
+	base, err := url.Parse(currentURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid url %q", currentURL)
+	}
+	next, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid Link header %q", link)
+	}
+	return base.ResolveReference(next).String(), nil
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// dockerConfigEnvVariable is the environment variable overriding the folder hosting the container config file.
+	dockerConfigEnvVariable = "DOCKER_CONFIG"
+
+	// dockerHubAuthKey is the key used in the container config file for the Docker Hub credentials.
+	dockerHubAuthKey = "https://index.docker.io/v1/"
+
+	// identityTokenUsername is the username returned by credential helpers for identity tokens.
+	identityTokenUsername = "<token>"
+)
+
+// ociCredentials are the credentials used to authenticate to an OCI registry.
+type ociCredentials struct {
+	username      string
+	password      string
+	identityToken string
+}
+
+// dockerConfigFile is the subset of the container config file, usually $HOME/.docker/config.json, used by clusterctl.
+type dockerConfigFile struct {
+	Auths       map[string]dockerAuthConfig `json:"auths,omitempty"`
+	CredsStore  string                      `json:"credsStore,omitempty"`
+	CredHelpers map[string]string           `json:"credHelpers,omitempty"`
+}
+
+type dockerAuthConfig struct {
+	Auth          string `json:"auth,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// runCredentialHelper executes a container credential helper, returning its output.
+// NB. this is a variable so it can be replaced in tests.
+var runCredentialHelper = func(helper, registry string) ([]byte, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get") //nolint:gosec // the helper name is read from the user's container config file
+	cmd.Stdin = strings.NewReader(registry)
+	out, err := cmd.Output()
+	if err != nil {
+		// NB. credential helpers return an error with this message if there are no credentials for the registry.
+		if strings.Contains(string(out), "credentials not found") {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to execute the docker-credential-%s credential helper", helper)
+	}
+	return out, nil
+}
+
+// getOCICredentials returns the credentials for a registry, as defined in the container config file
+// or by the container credential helpers; nil is returned if there are no credentials for the registry.
+func getOCICredentials(registry string) (*ociCredentials, error) {
+	configFile, err := readDockerConfigFile()
+	if err != nil || configFile == nil {
+		return nil, err
+	}
+
+	authKey := registry
+	if registry == dockerHubDomain {
+		authKey = dockerHubAuthKey
+	}
+
+	helper := configFile.CredHelpers[registry]
+	if helper == "" {
+		helper = configFile.CredsStore
+	}
+	if helper != "" {
+		out, err := runCredentialHelper(helper, authKey)
+		if err != nil || out == nil {
+			return nil, err
+		}
+		helperCredentials := struct {
+			Username string `json:"Username"`
+			Secret   string `json:"Secret"`
+		}{}
+		if err := json.Unmarshal(out, &helperCredentials); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the output of the docker-credential-%s credential helper", helper)
+		}
+		if helperCredentials.Username == identityTokenUsername {
+			return &ociCredentials{identityToken: helperCredentials.Secret}, nil
+		}
+		return &ociCredentials{username: helperCredentials.Username, password: helperCredentials.Secret}, nil
+	}
+
+	for key, auth := range configFile.Auths {
+		if normalizeAuthKey(key) != normalizeAuthKey(authKey) {
+			continue
+		}
+		credentials := &ociCredentials{
+			username:      auth.Username,
+			password:      auth.Password,
+			identityToken: auth.IdentityToken,
+		}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid auth for %q in the container config file", key)
+			}
+			userAndPassword := strings.SplitN(string(decoded), ":", 2)
+			if len(userAndPassword) != 2 {
+				return nil, errors.Errorf("invalid auth for %q in the container config file", key)
+			}
+			credentials.username, credentials.password = userAndPassword[0], userAndPassword[1]
+		}
+		return credentials, nil
+	}
+	return nil, nil
+}
+
+// readDockerConfigFile reads the container config file, if any.
+func readDockerConfigFile() (*dockerConfigFile, error) {
+	configFolder := os.Getenv(dockerConfigEnvVariable)
+	if configFolder == "" {
+		homeFolder, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil //nolint:nilerr // if there is no home folder, there is no container config file.
+		}
+		configFolder = filepath.Join(homeFolder, ".docker")
+	}
+
+	content, err := os.ReadFile(filepath.Join(configFolder, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to read the container config file")
+	}
+
+	configFile := &dockerConfigFile{}
+	if err := json.Unmarshal(content, configFile); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the container config file")
+	}
+	return configFile, nil
+}
+
+// normalizeAuthKey removes the scheme and the path from the keys of the auths section of the container config file,
+// which could be expressed as URLs, e.g. https://index.docker.io/v1/.
+func normalizeAuthKey(key string) string {
+	key = strings.TrimPrefix(key, "https://")
+	key = strings.TrimPrefix(key, "http://")
+	return strings.SplitN(key, "/", 2)[0]
+}
+
+// authorize sets the authorization for the next requests to the registry, according to
+// the authentication challenge returned by the registry.
+func (r *ociRepository) authorize(challenge string) error {
+	scheme, params := parseAuthChallenge(challenge)
+
+	credentials, err := getOCICredentials(r.registry)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credentials == nil || credentials.username == "" {
+			return errors.New("the registry requires basic authentication, but there are no credentials for it in the container config file")
+		}
+		r.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials.username+":"+credentials.password))
+		return nil
+	case "bearer":
+		token, err := r.getToken(params, credentials)
+		if err != nil {
+			return err
+		}
+		r.authorization = "Bearer " + token
+		return nil
+	}
+	return errors.Errorf("unsupported authentication challenge %q", challenge)
+}
+
+// getToken gets a bearer token from the authorization service of the registry.
+func (r *ociRepository) getToken(params map[string]string, credentials *ociCredentials) (string, error) {
+	realm, ok := params["realm"]
+	if !ok {
+		return "", errors.New("invalid authentication challenge: realm is required")
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", r.repository)
+	}
+
+	var request *http.Request
+	var err error
+	if credentials != nil && credentials.identityToken != "" {
+		// Identity tokens are exchanged for a bearer token using the OAuth2 refresh token flow.
+		form := url.Values{}
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", credentials.identityToken)
+		form.Set("service", params["service"])
+		form.Set("scope", scope)
+		form.Set("client_id", "clusterctl")
+		request, err = http.NewRequest(http.MethodPost, realm, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create token request for %s", realm)
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		tokenURL, err := url.Parse(realm)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid authentication realm %q", realm)
+		}
+		query := tokenURL.Query()
+		if service, ok := params["service"]; ok {
+			query.Set("service", service)
+		}
+		query.Set("scope", scope)
+		tokenURL.RawQuery = query.Encode()
+
+		request, err = http.NewRequest(http.MethodGet, tokenURL.String(), http.NoBody)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create token request for %s", realm)
+		}
+		if credentials != nil && credentials.username != "" {
+			request.SetBasicAuth(credentials.username, credentials.password)
+		}
+	}
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get token from %s", realm)
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read token from %s", realm)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get token from %s: %s %s", realm, response.Status, string(bytes.TrimSpace(content)))
+	}
+
+	tokenResponse := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal(content, &tokenResponse); err != nil {
+		return "", errors.Wrapf(err, "failed to parse token from %s", realm)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", errors.Errorf("failed to get token from %s: the response does not contain a token", realm)
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header, e.g. Bearer realm="https://auth.example.com/token",service="example.com".
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+
+	challenge = strings.TrimSpace(challenge)
+	i := strings.Index(challenge, " ")
+	if i < 0 {
+		return challenge, params
+	}
+	scheme, rest := challenge[:i], challenge[i+1:]
+
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.Index(rest, ",")
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end+1:]
+			}
+		}
+		params[key] = strings.TrimSpace(value)
+	}
+	return scheme, params
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+// fakeOCIRegistry is a minimal OCI registry hosting a single repository, requiring token authentication.
+type fakeOCIRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte
+	blobs     map[string][]byte
+}
+
+func newFakeOCIRegistry(t *testing.T, repository string) *fakeOCIRegistry {
+	t.Helper()
+
+	r := &fakeOCIRegistry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		testMethod(t, req, "GET")
+		if user, password, ok := req.BasicAuth(); !ok || user != "user" || password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"token": "secret"}`)
+	})
+	mux.HandleFunc(fmt.Sprintf("/v2/%s/", repository), func(w http.ResponseWriter, req *http.Request) {
+		testMethod(t, req, "GET")
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:%s:pull"`, r.server.URL, repository))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		resource := strings.TrimPrefix(req.URL.Path, fmt.Sprintf("/v2/%s/", repository))
+		switch {
+		case resource == "tags/list" && req.URL.Query().Get("last") == "":
+			w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?last=v1.0.0>; rel="next"`, repository))
+			fmt.Fprint(w, `{"tags": ["v1.0.0", "not-a-semver"]}`)
+		case resource == "tags/list":
+			fmt.Fprint(w, `{"tags": ["v1.1.0"]}`)
+		case strings.HasPrefix(resource, "manifests/"):
+			content, ok := r.manifests[strings.TrimPrefix(resource, "manifests/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", ociManifestMediaType)
+			_, _ = w.Write(content)
+		case strings.HasPrefix(resource, "blobs/"):
+			content, ok := r.blobs[strings.TrimPrefix(resource, "blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	r.server = httptest.NewServer(mux)
+	return r
+}
+
+// withArtifact pushes an artifact with the given files and tag, and returns the digest of its manifest.
+func (r *fakeOCIRegistry) withArtifact(t *testing.T, tag string, files map[string][]byte) string {
+	t.Helper()
+
+	manifest := ociManifest{
+		Annotations: map[string]string{ociImageVersionAnnotation: tag},
+	}
+	for name, content := range files {
+		digest := fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+		r.blobs[digest] = content
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType:   "application/vnd.oci.image.layer.v1.tar",
+			Digest:      digest,
+			Size:        int64(len(content)),
+			Annotations: map[string]string{ociImageTitleAnnotation: name},
+		})
+	}
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+	r.manifests[tag] = content
+	r.manifests[digest] = content
+	return digest
+}
+
+func Test_ociRepository(t *testing.T) {
+	registry := newFakeOCIRegistry(t, "org/infrastructure-foo")
+	defer registry.server.Close()
+
+	metadata := []byte(fmt.Sprintf(`apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3
+kind: Metadata
+releaseSeries:
+- major: 1
+  minor: 0
+  contract: v1alpha4
+- major: 1
+  minor: 1
+  contract: %s
+`, test.CurrentCAPIContract))
+	v100Digest := registry.withArtifact(t, "v1.0.0", map[string][]byte{
+		"infrastructure-components.yaml": []byte("components-v1.0.0"),
+		"metadata.yaml":                  metadata,
+		"cluster-template.yaml":          []byte("template-v1.0.0"),
+	})
+	registry.withArtifact(t, "v1.1.0", map[string][]byte{
+		"infrastructure-components.yaml": []byte("components-v1.1.0"),
+		"metadata.yaml":                  metadata,
+	})
+
+	// Configure credentials for the registry in the container config file.
+	registryHost := strings.TrimPrefix(registry.server.URL, "http://")
+	dockerConfigFolder := createTempDir(t)
+	defer os.RemoveAll(dockerConfigFolder)
+	dockerConfig := fmt.Sprintf(`{"auths": {"%s": {"auth": "%s"}}}`, registryHost, base64.StdEncoding.EncodeToString([]byte("user:password")))
+	if err := os.WriteFile(filepath.Join(dockerConfigFolder, "config.json"), []byte(dockerConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Setenv(dockerConfigEnvVariable, dockerConfigFolder)
+	defer os.Unsetenv(dockerConfigEnvVariable)
+
+	tests := []struct {
+		name               string
+		url                string
+		wantDefaultVersion string
+		wantComponents     string
+		wantErr            bool
+	}{
+		{
+			name:               "latest resolves to the latest release for the current contract",
+			url:                fmt.Sprintf("oci://%s/org/infrastructure-foo:latest/infrastructure-components.yaml", registryHost),
+			wantDefaultVersion: "v1.1.0",
+			wantComponents:     "components-v1.1.0",
+		},
+		{
+			name:               "no tag resolves to the latest release for the current contract",
+			url:                fmt.Sprintf("oci://%s/org/infrastructure-foo/infrastructure-components.yaml", registryHost),
+			wantDefaultVersion: "v1.1.0",
+			wantComponents:     "components-v1.1.0",
+		},
+		{
+			name:               "version tag",
+			url:                fmt.Sprintf("oci://%s/org/infrastructure-foo:v1.0.0/infrastructure-components.yaml", registryHost),
+			wantDefaultVersion: "v1.0.0",
+			wantComponents:     "components-v1.0.0",
+		},
+		{
+			name:               "digest pinning reads the version from the manifest",
+			url:                fmt.Sprintf("oci://%s/org/infrastructure-foo@%s/infrastructure-components.yaml", registryHost, v100Digest),
+			wantDefaultVersion: "v1.0.0",
+			wantComponents:     "components-v1.0.0",
+		},
+		{
+			name:               "digest pinning with a version tag",
+			url:                fmt.Sprintf("oci://%s/org/infrastructure-foo:v1.0.0@%s/infrastructure-components.yaml", registryHost, v100Digest),
+			wantDefaultVersion: "v1.0.0",
+			wantComponents:     "components-v1.0.0",
+		},
+		{
+			name:    "fails if the pinned digest does not exist",
+			url:     fmt.Sprintf("oci://%s/org/infrastructure-foo@sha256:%x/infrastructure-components.yaml", registryHost, sha256.Sum256([]byte("foo"))),
+			wantErr: true,
+		},
+		{
+			name:    "fails if the url does not include the components file",
+			url:     fmt.Sprintf("oci://%s/org/infrastructure-foo:v1.0.0", registryHost),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			providerConfig := config.NewProvider("foo", tt.url, clusterctlv1.InfrastructureProviderType)
+			repo, err := newOCIRepository(providerConfig, test.NewFakeVariableClient())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(repo.DefaultVersion()).To(Equal(tt.wantDefaultVersion))
+			g.Expect(repo.ComponentsPath()).To(Equal("infrastructure-components.yaml"))
+
+			got, err := repo.GetFile("", repo.ComponentsPath())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.wantComponents))
+
+			versions, err := repo.GetVersions()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(versions).To(ConsistOf("v1.0.0", "v1.1.0"))
+
+			files, err := repo.listFiles("v1.0.0")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(files).To(ConsistOf("infrastructure-components.yaml", "metadata.yaml", "cluster-template.yaml"))
+
+			_, err = repo.GetFile("v1.1.0", "cluster-template.yaml")
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
+
+func Test_verifyDigest(t *testing.T) {
+	content := []byte("content")
+
+	tests := []struct {
+		name    string
+		digest  string
+		wantErr bool
+	}{
+		{
+			name:   "sha256 digest matches",
+			digest: fmt.Sprintf("sha256:%x", sha256.Sum256(content)),
+		},
+		{
+			name:    "sha256 digest does not match",
+			digest:  fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("something else"))),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			digest:  "md5:9a0364b9e99bb480dd25e1f0284c8555",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := verifyDigest(content, tt.digest)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func Test_parseAuthChallenge(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:org/foo:pull,push"`)
+	g.Expect(scheme).To(Equal("Bearer"))
+	g.Expect(params).To(Equal(map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:org/foo:pull,push",
+	}))
+
+	scheme, params = parseAuthChallenge(`Basic realm="registry"`)
+	g.Expect(scheme).To(Equal("Basic"))
+	g.Expect(params).To(Equal(map[string]string{"realm": "registry"}))
+}
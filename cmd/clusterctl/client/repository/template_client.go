@@ -36,6 +36,7 @@ type templateClient struct {
 	repository            Repository
 	configVariablesClient config.VariablesClient
 	processor             yaml.Processor
+	processorFactory      TemplateProcessorFactory
 }
 
 // TemplateClientInput is an input strict for newTemplateClient.
@@ -45,13 +46,14 @@ type TemplateClientInput struct {
 	repository            Repository
 	configVariablesClient config.VariablesClient
 	processor             yaml.Processor
+	processorFactory      TemplateProcessorFactory
 }
 
 // Ensure templateClient implements the TemplateClient interface.
 var _ TemplateClient = &templateClient{}
 
-// newTemplateClient returns a templateClient. It uses the SimpleYamlProcessor
-// by default.
+// newTemplateClient returns a templateClient. If a processorFactory is provided, the
+// template processor defined in the provider's metadata is used instead of the given processor.
 func newTemplateClient(input TemplateClientInput) *templateClient {
 	return &templateClient{
 		provider:              input.provider,
@@ -59,6 +61,7 @@ func newTemplateClient(input TemplateClientInput) *templateClient {
 		repository:            input.repository,
 		configVariablesClient: input.configVariablesClient,
 		processor:             input.processor,
+		processorFactory:      input.processorFactory,
 	}
 }
 
@@ -73,7 +76,16 @@ func (c *templateClient) Get(flavor, targetNamespace string, skipTemplateProcess
 	}
 
 	version := c.version
-	name := c.processor.GetTemplateName(version, flavor)
+
+	processor := c.processor
+	if c.processorFactory != nil {
+		var err error
+		processor, err = getTemplateProcessor(c.provider, version, c.repository, c.configVariablesClient, c.processorFactory)
+		if err != nil {
+			return nil, err
+		}
+	}
+	name := processor.GetTemplateName(version, flavor)
 
 	// read the component YAML, reading the local override file if it exists, otherwise read from the provider repository
 	rawArtifact, err := getLocalOverride(&newOverrideInput{
@@ -99,7 +111,7 @@ func (c *templateClient) Get(flavor, targetNamespace string, skipTemplateProcess
 	return NewTemplate(TemplateInput{
 		rawArtifact,
 		c.configVariablesClient,
-		c.processor,
+		processor,
 		targetNamespace,
 		skipTemplateProcess,
 	})
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"github.com/pkg/errors"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// TemplateProcessorFactory returns the yaml processor to be used for the cluster templates of a provider,
+// given the name of the template processor defined in the provider's metadata.
+type TemplateProcessorFactory func(name string) (yaml.Processor, error)
+
+// defaultTemplateProcessorFactory returns the SimpleProcessor when the provider's metadata does not define a
+// template processor, otherwise the ExecProcessor running the corresponding template processor plugin.
+func defaultTemplateProcessorFactory(name string) (yaml.Processor, error) {
+	if name == "" || name == clusterctlv1.SimpleTemplateProcessor {
+		return yaml.NewSimpleProcessor(), nil
+	}
+	return yaml.NewExecProcessorForPlugin(name)
+}
+
+// getTemplateProcessor returns the yaml processor defined in the metadata of a provider version.
+func getTemplateProcessor(provider config.Provider, version string, repository Repository, configVariablesClient config.VariablesClient, factory TemplateProcessorFactory) (yaml.Processor, error) {
+	log := logf.Log
+
+	metadata, err := newMetadataClient(provider, version, repository, configVariablesClient).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	processor, err := factory(metadata.TemplateProcessor)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the template processor for provider %q", provider.ManifestLabel())
+	}
+	if metadata.TemplateProcessor != "" {
+		log.V(5).Info("Using", "TemplateProcessor", metadata.TemplateProcessor, "Provider", provider.ManifestLabel(), "Version", version)
+	}
+	return processor, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_getTemplateProcessor(t *testing.T) {
+	provider := config.NewProvider("foo", "https://github.com/o/r/releases/v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType)
+
+	tests := []struct {
+		name              string
+		templateProcessor string
+		noMetadata        bool
+		assert            func(*WithT, yaml.Processor)
+		wantErr           bool
+	}{
+		{
+			name:              "uses the simple processor if the metadata does not define a template processor",
+			templateProcessor: "",
+			assert: func(g *WithT, p yaml.Processor) {
+				g.Expect(p).To(BeAssignableToTypeOf(&yaml.SimpleProcessor{}))
+			},
+		},
+		{
+			name:              "uses the simple processor if the metadata defines the simple template processor",
+			templateProcessor: clusterctlv1.SimpleTemplateProcessor,
+			assert: func(g *WithT, p yaml.Processor) {
+				g.Expect(p).To(BeAssignableToTypeOf(&yaml.SimpleProcessor{}))
+			},
+		},
+		{
+			name:              "returns error if the template processor plugin is not installed",
+			templateProcessor: "does-not-exist",
+			wantErr:           true,
+		},
+		{
+			name:       "returns error if the metadata cannot be read",
+			noMetadata: true,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			repository := NewMemoryRepository().
+				WithPaths("root", "infrastructure-components.yaml").
+				WithDefaultVersion("v1.0.0")
+			if !tt.noMetadata {
+				repository.WithMetadata("v1.0.0", &clusterctlv1.Metadata{TemplateProcessor: tt.templateProcessor})
+			}
+
+			got, err := getTemplateProcessor(provider, "v1.0.0", repository, test.NewFakeVariableClient(), defaultTemplateProcessorFactory)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			tt.assert(g, got)
+		})
+	}
+}
+
+func Test_templateClient_GetWithTemplateProcessor(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := config.NewProvider("foo", "https://github.com/o/r/releases/v1.0.0/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType)
+	repository := NewMemoryRepository().
+		WithPaths("root", "infrastructure-components.yaml").
+		WithDefaultVersion("v1.0.0").
+		WithMetadata("v1.0.0", &clusterctlv1.Metadata{TemplateProcessor: "fake"}).
+		WithFile("v1.0.0", "cluster-template-fake.yaml", templateMapYaml)
+
+	var gotName string
+	factory := func(name string) (yaml.Processor, error) {
+		gotName = name
+		return test.NewFakeProcessor().WithTemplateName("cluster-template-fake.yaml"), nil
+	}
+
+	c := newTemplateClient(TemplateClientInput{
+		version:               "v1.0.0",
+		provider:              provider,
+		repository:            repository,
+		configVariablesClient: test.NewFakeVariableClient(),
+		processor:             yaml.NewSimpleProcessor(),
+		processorFactory:      factory,
+	})
+
+	// The template name is returned by the processor selected according to the metadata.
+	_, err := c.Get("", "ns1", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gotName).To(Equal("fake"))
+}
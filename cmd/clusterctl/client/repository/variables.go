@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+)
+
+// listVariables returns the variables consumed by the components YAML and by the workload cluster templates of a
+// provider version, indexed by file name. Templates are read only from repositories that can list their files.
+// The components YAML is always inspected with the SimpleProcessor, while templates are inspected with the given processor.
+// NB. Local overrides are taken into account, because they replace the files hosted in the repository.
+func listVariables(provider config.Provider, repository Repository, configVariablesClient config.VariablesClient, processor yaml.Processor, version string) (map[string][]string, error) {
+	files := []string{repository.ComponentsPath()}
+	if lister, ok := repository.(fileLister); ok {
+		repositoryFiles, err := lister.listFiles(version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list files for provider %q version %s", provider.ManifestLabel(), version)
+		}
+		for _, f := range repositoryFiles {
+			if strings.HasPrefix(f, clusterTemplatePrefix) && strings.HasSuffix(f, ".yaml") {
+				files = append(files, f)
+			}
+		}
+	}
+
+	ret := map[string][]string{}
+	for _, f := range files {
+		content, err := getLocalOverride(&newOverrideInput{
+			configVariablesClient: configVariablesClient,
+			provider:              provider,
+			version:               version,
+			filePath:              f,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			content, err = repository.GetFile(version, f)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %q from the repository for provider %q", f, provider.ManifestLabel())
+			}
+		}
+
+		fileProcessor := processor
+		if f == repository.ComponentsPath() {
+			fileProcessor = yaml.NewSimpleProcessor()
+		}
+		variables, err := fileProcessor.GetVariables(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the variables from %q for provider %q", f, provider.ManifestLabel())
+		}
+		ret[f] = variables
+	}
+	return ret, nil
+}
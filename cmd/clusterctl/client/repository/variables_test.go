@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_listVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := config.NewProvider("foo", "https://github.com/o/r/releases/latest/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType)
+	repository := NewMemoryRepository().
+		WithPaths("root", "infrastructure-components.yaml").
+		WithDefaultVersion("v1.0.0").
+		WithFile("v1.0.0", "infrastructure-components.yaml", []byte("region: ${FOO_REGION}\ncredentials: ${FOO_CREDENTIALS}")).
+		WithFile("v1.0.0", "cluster-template.yaml", []byte("region: ${FOO_REGION}\nversion: ${KUBERNETES_VERSION}")).
+		WithFile("v1.0.0", "cluster-template-prod.yaml", []byte("version: ${KUBERNETES_VERSION}")).
+		WithFile("v1.0.0", "something-else.yaml", []byte("something: ${SOMETHING_ELSE}"))
+
+	got, err := listVariables(provider, repository, test.NewFakeVariableClient(), yaml.NewSimpleProcessor(), "v1.0.0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(HaveLen(3))
+	g.Expect(got).To(HaveKeyWithValue("infrastructure-components.yaml", ConsistOf("FOO_REGION", "FOO_CREDENTIALS")))
+	g.Expect(got).To(HaveKeyWithValue("cluster-template.yaml", ConsistOf("FOO_REGION", "KUBERNETES_VERSION")))
+	g.Expect(got).To(HaveKeyWithValue("cluster-template-prod.yaml", ConsistOf("KUBERNETES_VERSION")))
+
+	// Returns an error if the version does not exist.
+	_, err = listVariables(provider, repository, test.NewFakeVariableClient(), yaml.NewSimpleProcessor(), "v2.0.0")
+	g.Expect(err).To(HaveOccurred())
+}
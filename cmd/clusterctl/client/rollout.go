@@ -111,6 +111,26 @@ func (c *clusterctlClient) RolloutUndo(options RolloutOptions) error {
 	return nil
 }
 
+func (c *clusterctlClient) RolloutStatus(options RolloutOptions) ([]RolloutStatus, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+	objRefs, err := getObjectRefs(clusterClient, options)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]RolloutStatus, 0, len(objRefs))
+	for _, ref := range objRefs {
+		status, err := c.alphaClient.Rollout().ObjectStatusViewer(clusterClient.Proxy(), ref)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, RolloutStatus(*status))
+	}
+	return ret, nil
+}
+
 func getObjectRefs(clusterClient cluster.Client, options RolloutOptions) ([]corev1.ObjectReference, error) {
 	// If the option specifying the Namespace is empty, try to detect it.
 	if options.Namespace == "" {
@@ -220,3 +220,39 @@ func Test_clusterctlClient_RolloutResume(t *testing.T) {
 		})
 	}
 }
+
+func Test_clusterctlClient_RolloutStatus(t *testing.T) {
+	tests := genericTestCases()
+	additionalTests := []rolloutTest{
+		{
+			name: "do not return error if all machinedeployments found",
+			fields: fields{
+				client: fakeClientForRollout(),
+			},
+			args: args{
+				options: RolloutOptions{
+					Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					Resources:  []string{"machinedeployment/md-1", "machinedeployment/md-2"},
+					Namespace:  "default",
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	tests = append(tests, additionalTests...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := tt.fields.client.RolloutStatus(tt.args.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(HaveLen(len(tt.args.options.Resources)))
+		})
+	}
+}
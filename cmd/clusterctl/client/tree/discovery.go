@@ -21,6 +21,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -130,6 +131,22 @@ func Discovery(ctx context.Context, c client.Client, namespace, name string, opt
 		}
 	}
 
+	// Adds machine pools and the machines representing their instances.
+	machinePoolList, err := getMachinePoolsInCluster(ctx, c, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range machinePoolList.Items {
+		mp := &machinePoolList.Items[i]
+		tree.Add(workers, mp, GroupingObject(true))
+
+		machines := selectMachinesControlledBy(machinesList, mp)
+		for _, w := range machines {
+			addMachineFunc(mp, w)
+		}
+	}
+
 	// Handles orphan machines.
 	if len(machineMap) < len(machinesList.Items) {
 		other := VirtualObject(cluster.Namespace, "OtherGroup", "Other")
@@ -192,6 +209,21 @@ func getMachineSetsInCluster(ctx context.Context, c client.Client, namespace, na
 	return machineSetList, nil
 }
 
+func getMachinePoolsInCluster(ctx context.Context, c client.Client, namespace, name string) (*expv1.MachinePoolList, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	machinePoolList := &expv1.MachinePoolList{}
+	labels := map[string]string{clusterv1.ClusterLabelName: name}
+
+	if err := c.List(ctx, machinePoolList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	return machinePoolList, nil
+}
+
 func selectControlPlaneMachines(machineList *clusterv1.MachineList) []*clusterv1.Machine {
 	machines := []*clusterv1.Machine{}
 	for i := range machineList.Items {
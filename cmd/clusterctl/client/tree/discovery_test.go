@@ -268,6 +268,52 @@ func Test_Discovery(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Discovery with machine pools",
+			args: args{
+				discoverOptions: DiscoverOptions{
+					DisableGrouping: true,
+				},
+				objs: test.NewFakeCluster("ns1", "cluster1").
+					WithControlPlane(
+						test.NewFakeControlPlane("cp").
+							WithMachines(
+								test.NewFakeMachine("cp1"),
+							),
+					).
+					WithMachinePools(
+						test.NewFakeMachinePool("mp1").
+							WithMachines(
+								test.NewFakeMachine("mp1-m1"),
+								test.NewFakeMachine("mp1-m2"),
+							),
+					).
+					Objs(),
+			},
+			wantTree: map[string][]string{
+				// Cluster should be parent of InfrastructureCluster, ControlPlane, and WorkerNodes
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1": {
+					"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/cluster1",
+					"controlplane.cluster.x-k8s.io/v1beta1, Kind=GenericControlPlane, ns1/cp",
+					"virtual.cluster.x-k8s.io/v1beta1, ns1/Workers",
+				},
+				// Workers should have a machine pool
+				"virtual.cluster.x-k8s.io/v1beta1, ns1/Workers": {
+					"cluster.x-k8s.io/v1beta1, Kind=MachinePool, ns1/mp1",
+				},
+				// Machine pool should have the machines representing its instances
+				"cluster.x-k8s.io/v1beta1, Kind=MachinePool, ns1/mp1": {
+					"cluster.x-k8s.io/v1beta1, Kind=Machine, ns1/mp1-m1",
+					"cluster.x-k8s.io/v1beta1, Kind=Machine, ns1/mp1-m2",
+				},
+			},
+			wantNodeCheck: map[string]nodeCheck{
+				// Machine pool should NOT be a grouping object
+				"cluster.x-k8s.io/v1beta1, Kind=MachinePool, ns1/mp1": func(g *WithT, obj client.Object) {
+					g.Expect(IsGroupingObject(obj)).To(BeFalse())
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
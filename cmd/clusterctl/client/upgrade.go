@@ -18,6 +18,7 @@ package client
 
 import (
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -79,6 +80,37 @@ func (c *clusterctlClient) PlanUpgrade(options PlanUpgradeOptions) ([]UpgradePla
 	return aliasUpgradePlan, nil
 }
 
+func (c *clusterctlClient) PlanWorkloadClustersUpgrade(options PlanUpgradeOptions) ([]WorkloadClusterUpgradePlan, error) {
+	// Get the client for interacting with the management cluster.
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(); err != nil {
+		return nil, err
+	}
+
+	// Ensures the custom resource definitions required by clusterctl are in place.
+	if err := clusterClient.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+		return nil, err
+	}
+
+	plans, err := clusterClient.ProviderUpgrader().PlanWorkloadClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	// WorkloadClusterUpgradePlan is an alias for cluster.WorkloadClusterUpgradePlan; this makes the conversion
+	aliasPlans := make([]WorkloadClusterUpgradePlan, len(plans))
+	for i, plan := range plans {
+		aliasPlans[i] = WorkloadClusterUpgradePlan(plan)
+	}
+
+	return aliasPlans, nil
+}
+
 // ApplyUpgradeOptions carries the options supported by upgrade apply.
 type ApplyUpgradeOptions struct {
 	// Kubeconfig to use for accessing the management cluster. If empty, default discovery rules apply.
@@ -100,6 +132,17 @@ type ApplyUpgradeOptions struct {
 
 	// InfrastructureProviders instance and versions (e.g. capa-system/aws:v0.5.0) to upgrade to. This field can be used as alternative to Contract.
 	InfrastructureProviders []string
+
+	// WaitProviders instructs the upgrade apply command to wait, after upgrading each provider, for the provider's
+	// controllers to be available and for its conversion webhooks to be ready before upgrading the next provider.
+	WaitProviders bool
+
+	// WaitProviderTimeout sets the timeout per provider upgrade.
+	WaitProviderTimeout time.Duration
+
+	// ContinueOnError instructs the upgrade apply command to continue with the next providers when the upgrade
+	// of a provider fails; failed providers are rolled back to the version installed before the upgrade.
+	ContinueOnError bool
 }
 
 func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
@@ -132,6 +175,12 @@ func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
 		return err
 	}
 
+	upgradeOptions := cluster.UpgradeOptions{
+		WaitProviders:       options.WaitProviders,
+		WaitProviderTimeout: options.WaitProviderTimeout,
+		ContinueOnError:     options.ContinueOnError,
+	}
+
 	// Check if the user want a custom upgrade
 	isCustomUpgrade := options.CoreProvider != "" ||
 		len(options.BootstrapProviders) > 0 ||
@@ -163,11 +212,11 @@ func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
 		}
 
 		// Execute the upgrade using the custom upgrade items
-		return clusterClient.ProviderUpgrader().ApplyCustomPlan(upgradeItems...)
+		return clusterClient.ProviderUpgrader().ApplyCustomPlan(upgradeOptions, upgradeItems...)
 	}
 
 	// Otherwise we are upgrading a whole management cluster according to a clusterctl generated upgrade plan.
-	return clusterClient.ProviderUpgrader().ApplyPlan(options.Contract)
+	return clusterClient.ProviderUpgrader().ApplyPlan(upgradeOptions, options.Contract)
 }
 
 func addUpgradeItems(upgradeItems []cluster.UpgradeItem, providerType clusterctlv1.ProviderType, providers ...string) ([]cluster.UpgradeItem, error) {
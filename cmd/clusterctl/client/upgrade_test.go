@@ -19,6 +19,7 @@ package client
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
@@ -305,6 +306,92 @@ func Test_clusterctlClient_ApplyUpgrade(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_ApplyUpgrade_Rollback(t *testing.T) {
+	g := NewWithT(t)
+
+	core := config.NewProvider("cluster-api", "https://somewhere.com", clusterctlv1.CoreProviderType)
+	infra := config.NewProvider("infra", "https://somewhere.com", clusterctlv1.InfrastructureProviderType)
+
+	config1 := newFakeConfig().
+		WithProvider(core).
+		WithProvider(infra)
+
+	repository1 := newFakeRepository(core, config1).
+		WithPaths("root", "components.yaml").
+		WithDefaultVersion("v1.0.1").
+		WithFile("v1.0.1", "components.yaml", componentsYAML("ns2")).
+		WithVersions("v1.0.0", "v1.0.1").
+		WithMetadata("v1.0.1", &clusterctlv1.Metadata{
+			ReleaseSeries: []clusterctlv1.ReleaseSeries{
+				{Major: 1, Minor: 0, Contract: test.CurrentCAPIContract},
+			},
+		})
+	// The infra provider v2.0.1 has a controller Deployment that never becomes available.
+	repository2 := newFakeRepository(infra, config1).
+		WithPaths("root", "components.yaml").
+		WithDefaultVersion("v2.0.0").
+		WithFile("v2.0.0", "components.yaml", componentsYAML("ns2")).
+		WithFile("v2.0.1", "components.yaml", []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: infra-controller-manager
+spec:
+  selector:
+    matchLabels:
+      control-plane: controller-manager
+  template:
+    metadata:
+      labels:
+        control-plane: controller-manager
+    spec:
+      containers:
+      - name: manager
+        image: registry.k8s.io/infra:v2.0.1`)).
+		WithVersions("v2.0.0", "v2.0.1").
+		WithMetadata("v2.0.1", &clusterctlv1.Metadata{
+			ReleaseSeries: []clusterctlv1.ReleaseSeries{
+				{Major: 2, Minor: 0, Contract: test.CurrentCAPIContract},
+			},
+		})
+
+	cluster1 := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, config1).
+		WithRepository(repository1).
+		WithRepository(repository2).
+		WithProviderInventory(core.Name(), core.Type(), "v1.0.0", "cluster-api-system").
+		WithProviderInventory(infra.Name(), infra.Type(), "v2.0.0", "infra-system").
+		WithObjs(test.FakeCAPISetupObjects()...)
+
+	client := newFakeClient(config1).
+		WithRepository(repository1).
+		WithRepository(repository2).
+		WithCluster(cluster1)
+
+	err := client.ApplyUpgrade(ApplyUpgradeOptions{
+		Kubeconfig:          Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		Contract:            test.CurrentCAPIContract,
+		WaitProviders:       true,
+		WaitProviderTimeout: 500 * time.Millisecond,
+		ContinueOnError:     true,
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to upgrade the infra-system/infrastructure-infra provider to version v2.0.1"))
+
+	c, err := client.clusters[cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}].Proxy().NewClient()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The core provider is upgraded, while the infra provider is rolled back to the previous version.
+	gotProviders := &clusterctlv1.ProviderList{}
+	g.Expect(c.List(ctx, gotProviders)).To(Succeed())
+	gotVersions := map[string]string{}
+	for _, p := range gotProviders.Items {
+		gotVersions[p.Name] = p.Version
+	}
+	g.Expect(gotVersions).To(Equal(map[string]string{
+		"cluster-api":          "v1.0.1",
+		"infrastructure-infra": "v2.0.0",
+	}))
+}
+
 func fakeClientForUpgrade() *fakeClient {
 	core := config.NewProvider("cluster-api", "https://somewhere.com", clusterctlv1.CoreProviderType)
 	infra := config.NewProvider("infra", "https://somewhere.com", clusterctlv1.InfrastructureProviderType)
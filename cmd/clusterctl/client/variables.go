@@ -0,0 +1,204 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+// ExplainVariableOptions carries the options supported by ExplainVariable.
+type ExplainVariableOptions struct {
+	// Name of the variable to explain.
+	Name string
+
+	// CoreProvider, BootstrapProviders, ControlPlaneProviders and InfrastructureProviders are the providers to inspect
+	// for finding the files consuming the variable, in the form name[:version]; if no provider is specified, the
+	// default core, bootstrap and control plane providers installed by init are inspected.
+	CoreProvider            string
+	BootstrapProviders      []string
+	ControlPlaneProviders   []string
+	InfrastructureProviders []string
+}
+
+// VariableExplanation describes the value of a variable and the files consuming it.
+type VariableExplanation struct {
+	// Name of the variable.
+	Name string
+
+	// Variable holds the value of the variable and where it comes from; it is nil if the variable is not defined.
+	Variable *Variable
+
+	// Consumers lists the provider files consuming the variable.
+	Consumers []VariableConsumer
+}
+
+// VariableConsumer defines a provider file consuming a variable.
+type VariableConsumer struct {
+	// Provider is the name of the provider, e.g. infrastructure-aws.
+	Provider string
+
+	// Version of the provider; it is empty if the provider default version was inspected.
+	Version string
+
+	// File is the name of the file consuming the variable, e.g. cluster-template.yaml.
+	File string
+}
+
+func (c *clusterctlClient) GetVariablesConfig() ([]Variable, error) {
+	lister, ok := c.configClient.Variables().(config.VariablesLister)
+	if !ok {
+		return nil, errors.New("the clusterctl configuration does not support listing variables")
+	}
+	variables, err := lister.List()
+	if err != nil {
+		return nil, err
+	}
+
+	// Variable is an alias for config.Variable; this makes the conversion
+	ret := make([]Variable, 0, len(variables)+1)
+	hasCertManager := false
+	for _, v := range variables {
+		ret = append(ret, Variable(v))
+		if v.Name == config.CertManagerConfigKey {
+			hasCertManager = true
+		}
+	}
+
+	// Adds the cert-manager settings in use when they are not defined in the configuration.
+	if !hasCertManager {
+		certManager, err := c.configClient.CertManager().Get()
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(map[string]string{
+			"url":     certManager.URL(),
+			"version": certManager.Version(),
+			"timeout": certManager.Timeout(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, Variable{
+			Name:   config.CertManagerConfigKey,
+			Value:  string(data),
+			Source: config.VariableSourceDefault,
+		})
+	}
+	return ret, nil
+}
+
+func (c *clusterctlClient) ExplainVariable(options ExplainVariableOptions) (*VariableExplanation, error) {
+	if options.Name == "" {
+		return nil, errors.New("the name of the variable to explain is required")
+	}
+
+	ret := &VariableExplanation{
+		Name: options.Name,
+	}
+
+	if value, err := c.configClient.Variables().Get(options.Name); err == nil {
+		variable := &Variable{
+			Name:  options.Name,
+			Value: value,
+		}
+		if lister, ok := c.configClient.Variables().(config.VariablesLister); ok {
+			source, err := lister.Source(options.Name)
+			if err != nil {
+				return nil, err
+			}
+			variable.Source = source
+		}
+		ret.Variable = variable
+	}
+
+	providers := map[clusterctlv1.ProviderType][]string{
+		clusterctlv1.BootstrapProviderType:      options.BootstrapProviders,
+		clusterctlv1.ControlPlaneProviderType:   options.ControlPlaneProviders,
+		clusterctlv1.InfrastructureProviderType: options.InfrastructureProviders,
+	}
+	if options.CoreProvider != "" {
+		providers[clusterctlv1.CoreProviderType] = []string{options.CoreProvider}
+	}
+	if options.CoreProvider == "" && len(options.BootstrapProviders) == 0 && len(options.ControlPlaneProviders) == 0 && len(options.InfrastructureProviders) == 0 {
+		providers[clusterctlv1.CoreProviderType] = []string{config.ClusterAPIProviderName}
+		providers[clusterctlv1.BootstrapProviderType] = []string{config.KubeadmBootstrapProviderName}
+		providers[clusterctlv1.ControlPlaneProviderType] = []string{config.KubeadmControlPlaneProviderName}
+	}
+
+	for _, providerType := range []clusterctlv1.ProviderType{
+		clusterctlv1.CoreProviderType,
+		clusterctlv1.BootstrapProviderType,
+		clusterctlv1.ControlPlaneProviderType,
+		clusterctlv1.InfrastructureProviderType,
+	} {
+		for _, provider := range providers[providerType] {
+			consumers, err := c.getVariableConsumers(options.Name, provider, providerType)
+			if err != nil {
+				return nil, err
+			}
+			ret.Consumers = append(ret.Consumers, consumers...)
+		}
+	}
+	return ret, nil
+}
+
+// getVariableConsumers returns the files of a provider consuming a variable.
+func (c *clusterctlClient) getVariableConsumers(variable, provider string, providerType clusterctlv1.ProviderType) ([]VariableConsumer, error) {
+	// Parse the abbreviated syntax for name[:version]
+	name, version, err := parseProviderName(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfig, err := c.configClient.Providers().Get(name, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryClient, err := c.repositoryClientFactory(RepositoryClientFactoryInput{Provider: providerConfig})
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := repositoryClient.Variables(version)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []VariableConsumer{}
+	for file, variables := range files {
+		for _, v := range variables {
+			if v == variable {
+				ret = append(ret, VariableConsumer{
+					Provider: providerConfig.ManifestLabel(),
+					Version:  version,
+					File:     file,
+				})
+				break
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].File < ret[j].File
+	})
+	return ret, nil
+}
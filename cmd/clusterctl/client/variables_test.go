@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+func Test_clusterctlClient_GetVariablesConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config1 := newFakeConfig().
+		WithVar("FOO_REGION", "eu-west-1")
+
+	client := newFakeClient(config1)
+
+	got, err := client.GetVariablesConfig()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(ContainElement(Variable{Name: "FOO_REGION", Value: "eu-west-1", Source: config.VariableSourceOverride}))
+
+	// cert-manager settings are reported using the default values.
+	var certManager *Variable
+	for i := range got {
+		if got[i].Name == config.CertManagerConfigKey {
+			certManager = &got[i]
+		}
+	}
+	g.Expect(certManager).NotTo(BeNil())
+	g.Expect(certManager.Source).To(Equal(config.VariableSourceDefault))
+	g.Expect(certManager.Value).To(ContainSubstring(config.CertManagerDefaultVersion))
+}
+
+func Test_clusterctlClient_ExplainVariable(t *testing.T) {
+	infra := config.NewProvider("infra", "https://somewhere.com", clusterctlv1.InfrastructureProviderType)
+
+	config1 := newFakeConfig().
+		WithProvider(infra).
+		WithVar("FOO_REGION", "eu-west-1")
+
+	repository1 := newFakeRepository(infra, config1).
+		WithPaths("root", "components.yaml").
+		WithDefaultVersion("v1.0.0").
+		WithMetadata("v1.0.0", &clusterctlv1.Metadata{}).
+		WithFile("v1.0.0", "components.yaml", []byte("region: ${FOO_REGION}\ncredentials: ${FOO_CREDENTIALS}")).
+		WithFile("v1.0.0", "cluster-template.yaml", []byte("region: ${FOO_REGION}\nversion: ${KUBERNETES_VERSION}")).
+		WithFile("v1.0.0", "cluster-template-prod.yaml", []byte("version: ${KUBERNETES_VERSION}"))
+
+	client := newFakeClient(config1).
+		WithRepository(repository1)
+
+	type args struct {
+		options ExplainVariableOptions
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *VariableExplanation
+		wantErr bool
+	}{
+		{
+			name: "explain a variable defined in the configuration",
+			args: args{
+				options: ExplainVariableOptions{
+					Name:                    "FOO_REGION",
+					InfrastructureProviders: []string{"infra"},
+				},
+			},
+			want: &VariableExplanation{
+				Name:     "FOO_REGION",
+				Variable: &Variable{Name: "FOO_REGION", Value: "eu-west-1", Source: config.VariableSourceOverride},
+				Consumers: []VariableConsumer{
+					{Provider: "infrastructure-infra", File: "cluster-template.yaml"},
+					{Provider: "infrastructure-infra", File: "components.yaml"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "explain a variable not defined in the configuration",
+			args: args{
+				options: ExplainVariableOptions{
+					Name:                    "KUBERNETES_VERSION",
+					InfrastructureProviders: []string{"infra:v1.0.0"},
+				},
+			},
+			want: &VariableExplanation{
+				Name: "KUBERNETES_VERSION",
+				Consumers: []VariableConsumer{
+					{Provider: "infrastructure-infra", Version: "v1.0.0", File: "cluster-template-prod.yaml"},
+					{Provider: "infrastructure-infra", Version: "v1.0.0", File: "cluster-template.yaml"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "fails if the provider does not exist",
+			args: args{
+				options: ExplainVariableOptions{
+					Name:                    "FOO_REGION",
+					InfrastructureProviders: []string{"does-not-exist"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the variable name is empty",
+			args: args{
+				options: ExplainVariableOptions{
+					InfrastructureProviders: []string{"infra"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := client.ExplainVariable(tt.args.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ExecPluginPrefix is the prefix of the name of the executables implementing a template processor plugin,
+// e.g. the ytt template processor plugin is expected to be named clusterctl-template-processor-ytt.
+const ExecPluginPrefix = "clusterctl-template-processor-"
+
+const (
+	execVariablesAction = "variables"
+	execProcessAction   = "process"
+)
+
+// ExecProcessor is a yaml processor that delegates processing to an external
+// executable, thus allowing to use template engines other than envsubst,
+// e.g. ytt or Helm.
+//
+// The executable receives the template on stdin and it is invoked with:
+//   - the "variables" argument, to print on stdout a JSON object mapping the
+//     names of the variables the template uses to their default values, or to
+//     null for required variables.
+//   - the "process" argument, to print on stdout the final yaml; the values of
+//     the variables the template uses are passed as environment variables.
+type ExecProcessor struct {
+	command string
+}
+
+var _ Processor = &ExecProcessor{}
+
+// NewExecProcessor returns a new template processor running the given command.
+func NewExecProcessor(command string) *ExecProcessor {
+	return &ExecProcessor{
+		command: command,
+	}
+}
+
+// NewExecProcessorForPlugin returns a new template processor running the
+// template processor plugin with the given name, that is expected to be
+// installed in PATH as clusterctl-template-processor-<name>.
+func NewExecProcessorForPlugin(name string) (*ExecProcessor, error) {
+	command, err := exec.LookPath(ExecPluginPrefix + name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "template processor %q is not available, please install %s%s in your PATH", name, ExecPluginPrefix, name)
+	}
+	return NewExecProcessor(command), nil
+}
+
+// GetTemplateName returns the name of the template that the exec processor
+// uses. It follows the same naming convention of the simple processor.
+func (tp *ExecProcessor) GetTemplateName(version, flavor string) string {
+	return NewSimpleProcessor().GetTemplateName(version, flavor)
+}
+
+// GetVariables returns a list of the variables the template uses.
+func (tp *ExecProcessor) GetVariables(rawArtifact []byte) ([]string, error) {
+	variables, err := tp.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	varNames := make([]string, 0, len(variables))
+	for k := range variables {
+		varNames = append(varNames, k)
+	}
+	sort.Strings(varNames)
+	return varNames, nil
+}
+
+// GetVariableMap returns a map of the variables the template uses with their
+// default values.
+func (tp *ExecProcessor) GetVariableMap(rawArtifact []byte) (map[string]*string, error) {
+	out, err := tp.run(execVariablesAction, rawArtifact, nil)
+	if err != nil {
+		return nil, err
+	}
+	varMap := map[string]*string{}
+	if err := json.Unmarshal(out, &varMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the variables returned by %q", tp.command)
+	}
+	return varMap, nil
+}
+
+// Process returns the final yaml as generated by the external executable.
+// If there are variables without corresponding values and without default
+// values, it will return the raw yaml along with an error.
+func (tp *ExecProcessor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	variables, err := tp.GetVariableMap(rawArtifact)
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	var missingVariables []string
+	env := make([]string, 0, len(variables))
+	for name, defaultValue := range variables {
+		value, err := variablesClient(name)
+		if err != nil {
+			// add to missingVariables list if the variable does not exist in the
+			// variablesClient AND it does not have a default value
+			if defaultValue == nil {
+				missingVariables = append(missingVariables, name)
+				continue
+			}
+			value = *defaultValue
+		}
+		env = append(env, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	if len(missingVariables) > 0 {
+		return rawArtifact, &errMissingVariables{missingVariables}
+	}
+
+	out, err := tp.run(execProcessAction, rawArtifact, env)
+	if err != nil {
+		return rawArtifact, err
+	}
+	return out, nil
+}
+
+// run invokes the external executable for the given action, passing the
+// template on stdin, and returns its stdout.
+func (tp *ExecProcessor) run(action string, rawArtifact []byte, env []string) ([]byte, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.Command(tp.command, action) //nolint:gosec // the command is a template processor plugin installed by the user
+	cmd.Stdin = bytes.NewReader(rawArtifact)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), env...)
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to run %q %s: %s", tp.command, action, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
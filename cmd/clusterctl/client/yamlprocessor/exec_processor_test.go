@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+// fakePlugin is a template processor plugin consuming FOO, which is required, and BAR, which defaults to bar.
+const fakePlugin = `#!/bin/sh
+cat > /dev/null
+case "$1" in
+  variables) echo '{"FOO": null, "BAR": "bar"}' ;;
+  process) echo "foo: ${FOO}"; echo "bar: ${BAR}" ;;
+  *) echo "unknown action $1" >&2; exit 1 ;;
+esac
+`
+
+func writeFakePlugin(t *testing.T, dir string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake template processor plugin is a shell script")
+	}
+
+	path := filepath.Join(dir, ExecPluginPrefix+"fake")
+	if err := os.WriteFile(path, []byte(fakePlugin), 0700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecProcessor_GetVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := os.MkdirTemp("", "clusterctl")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	p := NewExecProcessor(writeFakePlugin(t, dir))
+
+	got, err := p.GetVariables([]byte("template"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal([]string{"BAR", "FOO"}))
+
+	gotMap, err := p.GetVariableMap([]byte("template"))
+	g.Expect(err).NotTo(HaveOccurred())
+	bar := "bar"
+	g.Expect(gotMap).To(Equal(map[string]*string{"FOO": nil, "BAR": &bar}))
+}
+
+func TestExecProcessor_Process(t *testing.T) {
+	dir, err := os.MkdirTemp("", "clusterctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	plugin := writeFakePlugin(t, dir)
+
+	tests := []struct {
+		name            string
+		variablesClient *test.FakeVariableClient
+		want            string
+		wantErr         bool
+	}{
+		{
+			name:            "uses default values for variables not set",
+			variablesClient: test.NewFakeVariableClient().WithVar("FOO", "foo"),
+			want:            "foo: foo\nbar: bar\n",
+		},
+		{
+			name:            "uses the values of the variables set",
+			variablesClient: test.NewFakeVariableClient().WithVar("FOO", "foo").WithVar("BAR", "baz"),
+			want:            "foo: foo\nbar: baz\n",
+		},
+		{
+			name:            "returns error if required variables are not set",
+			variablesClient: test.NewFakeVariableClient(),
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			p := NewExecProcessor(plugin)
+			got, err := p.Process([]byte("template"), tt.variablesClient.Get)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNewExecProcessorForPlugin(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := os.MkdirTemp("", "clusterctl")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	plugin := writeFakePlugin(t, dir)
+
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	g.Expect(os.Setenv("PATH", dir)).To(Succeed())
+
+	p, err := NewExecProcessorForPlugin("fake")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p.command).To(Equal(plugin))
+
+	_, err = NewExecProcessorForPlugin("does-not-exist")
+	g.Expect(err).To(HaveOccurred())
+}
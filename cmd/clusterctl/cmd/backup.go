@@ -35,7 +35,11 @@ var backupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "Backup Cluster API objects and all dependencies from a management cluster.",
 	Long: LongDesc(`
-		Backup Cluster API objects and all dependencies from a management cluster.`),
+		Backup Cluster API objects and all dependencies from a management cluster.
+
+		All the objects which would be moved by clusterctl move, e.g. Clusters, Machines, templates and Secrets,
+		are saved as files in the given directory, which is created if it does not exist; the backup can be
+		restored to a management cluster using clusterctl restore.`),
 
 	Example: Examples(`
 		Backup Cluster API objects and all dependencies from a management cluster.
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type configExplainOptions struct {
+	coreProvider            string
+	bootstrapProviders      []string
+	controlPlaneProviders   []string
+	infrastructureProviders []string
+	showSecrets             bool
+}
+
+var ceo = &configExplainOptions{}
+
+var configExplainCmd = &cobra.Command{
+	Use:   "explain VARIABLE",
+	Args:  cobra.ExactArgs(1),
+	Short: "Explain where a variable is defined and which provider files consume it.",
+	Long: LongDesc(`
+		Explain where a variable is defined and which provider files consume it.
+
+		The command reports the value of the variable and the source it is read from,
+		then lists the components and cluster templates consuming the variable.
+
+		If no provider is specified, the default core, bootstrap and control plane
+		providers installed by clusterctl init are inspected.`),
+
+	Example: Examples(`
+		# Explain where AWS_REGION is defined and which files of the aws infrastructure provider consume it.
+		clusterctl config explain AWS_REGION --infrastructure aws
+
+		# Explain a variable consumed by a specific version of a provider.
+		clusterctl config explain KUBERNETES_VERSION --infrastructure aws:v0.4.1`),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigExplain(cfgFile, args[0], os.Stdout)
+	},
+}
+
+func init() {
+	configExplainCmd.Flags().StringVar(&ceo.coreProvider, "core", "",
+		"Core provider version (e.g. cluster-api:v1.0.0) to inspect for files consuming the variable.")
+	configExplainCmd.Flags().StringSliceVarP(&ceo.bootstrapProviders, "bootstrap", "b", nil,
+		"Bootstrap providers and versions (e.g. kubeadm:v1.0.0) to inspect for files consuming the variable.")
+	configExplainCmd.Flags().StringSliceVarP(&ceo.controlPlaneProviders, "control-plane", "c", nil,
+		"ControlPlane providers and versions (e.g. kubeadm:v1.0.0) to inspect for files consuming the variable.")
+	configExplainCmd.Flags().StringSliceVarP(&ceo.infrastructureProviders, "infrastructure", "i", nil,
+		"Infrastructure providers and versions (e.g. aws:v0.5.0) to inspect for files consuming the variable.")
+	configExplainCmd.Flags().BoolVar(&ceo.showSecrets, "show-secrets", false,
+		"Print the value of the variable even if it is likely to contain secrets.")
+	configCmd.AddCommand(configExplainCmd)
+}
+
+func runConfigExplain(cfgFile, name string, out io.Writer) error {
+	if out == nil {
+		return errors.New("unable to print to nil output writer")
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	explanation, err := c.ExplainVariable(client.ExplainVariableOptions{
+		Name:                    name,
+		CoreProvider:            ceo.coreProvider,
+		BootstrapProviders:      ceo.bootstrapProviders,
+		ControlPlaneProviders:   ceo.controlPlaneProviders,
+		InfrastructureProviders: ceo.infrastructureProviders,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Variable: %s\n", explanation.Name)
+	if explanation.Variable == nil {
+		fmt.Fprintln(out, "Value:    <not defined>")
+	} else {
+		fmt.Fprintf(out, "Value:    %s\n", variableValue(*explanation.Variable, ceo.showSecrets))
+		fmt.Fprintf(out, "Source:   %s\n", explanation.Variable.Source)
+	}
+	fmt.Fprintln(out)
+
+	if len(explanation.Consumers) == 0 {
+		fmt.Fprintln(out, "No files of the inspected providers consume this variable.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tVERSION\tFILE")
+	for _, consumer := range explanation.Consumers {
+		version := consumer.Version
+		if version == "" {
+			version = "latest"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", consumer.Provider, version, consumer.File)
+	}
+	return w.Flush()
+}
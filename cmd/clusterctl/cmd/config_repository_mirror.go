@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type configRepositoryMirrorOptions struct {
+	dir                     string
+	coreProvider            string
+	bootstrapProviders      []string
+	controlPlaneProviders   []string
+	infrastructureProviders []string
+}
+
+var crmo = &configRepositoryMirrorOptions{}
+
+var configRepositoryParentCmd = &cobra.Command{
+	Use:   "repository",
+	Short: "Manage provider repositories.",
+	Long:  `Manage provider repositories.`,
+}
+
+var configRepositoryMirrorCmd = &cobra.Command{
+	Use:   "mirror",
+	Args:  cobra.NoArgs,
+	Short: "Create a local mirror of the provider repositories.",
+	Long: LongDesc(`
+		Create a local mirror of the provider repositories.
+
+		Downloads the components YAML, the metadata and the cluster templates of the selected providers
+		into a local folder, which can then be moved into an air-gapped environment.
+
+		To read providers from the mirror, set the mirrorFolder variable in the
+		$HOME/.cluster-api/clusterctl.yaml file; no per-provider URL override is required.`),
+
+	Example: Examples(`
+		# Create a mirror of all the providers known by clusterctl, using the latest release of each provider.
+		clusterctl config repository mirror --dir=/path/to/mirror
+
+		# Create a mirror of a specific version of the given providers.
+		clusterctl config repository mirror --dir=/path/to/mirror --core=cluster-api:v1.0.0 --infrastructure=aws:v1.0.0`),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigRepositoryMirror()
+	},
+}
+
+func init() {
+	configRepositoryMirrorCmd.Flags().StringVar(&crmo.dir, "dir", "",
+		"The folder where the mirror of the provider repositories should be created.")
+	configRepositoryMirrorCmd.Flags().StringVar(&crmo.coreProvider, "core", "",
+		"Core provider version (e.g. cluster-api:v0.3.0) to add to the mirror.")
+	configRepositoryMirrorCmd.Flags().StringSliceVarP(&crmo.infrastructureProviders, "infrastructure", "i", nil,
+		"Infrastructure providers and versions (e.g. aws:v0.5.0) to add to the mirror.")
+	configRepositoryMirrorCmd.Flags().StringSliceVarP(&crmo.bootstrapProviders, "bootstrap", "b", nil,
+		"Bootstrap providers and versions (e.g. kubeadm:v0.3.0) to add to the mirror.")
+	configRepositoryMirrorCmd.Flags().StringSliceVarP(&crmo.controlPlaneProviders, "control-plane", "c", nil,
+		"Control plane providers and versions (e.g. kubeadm:v0.3.0) to add to the mirror.")
+	_ = configRepositoryMirrorCmd.MarkFlagRequired("dir")
+
+	configRepositoryParentCmd.AddCommand(configRepositoryMirrorCmd)
+	configCmd.AddCommand(configRepositoryParentCmd)
+}
+
+func runConfigRepositoryMirror() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.MirrorRepositories(client.MirrorRepositoriesOptions{
+		MirrorFolder:            crmo.dir,
+		CoreProvider:            crmo.coreProvider,
+		BootstrapProviders:      crmo.bootstrapProviders,
+		ControlPlaneProviders:   crmo.controlPlaneProviders,
+		InfrastructureProviders: crmo.infrastructureProviders,
+	})
+}
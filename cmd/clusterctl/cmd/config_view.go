@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+// maskedValue is printed instead of the value of variables which are likely to contain secrets.
+const maskedValue = "<redacted>"
+
+// sensitiveVariableFragments are the fragments identifying variables which are likely to contain secrets.
+var sensitiveVariableFragments = []string{"TOKEN", "PASSWORD", "SECRET", "CREDENTIALS", "KEY"}
+
+type configViewOptions struct {
+	showSecrets bool
+}
+
+var cvo = &configViewOptions{}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Args:  cobra.NoArgs,
+	Short: "Display the effective clusterctl configuration.",
+	Long: LongDesc(`
+		Display the effective clusterctl configuration.
+
+		The effective configuration is the result of merging the variables defined
+		in the clusterctl config file, in the active profile and in environment variables;
+		for each variable, the source the value is read from is reported.
+
+		Values of variables likely to contain secrets, e.g. tokens or passwords, are
+		redacted unless --show-secrets is set.`),
+
+	Example: Examples(`
+		# Displays the effective clusterctl configuration.
+		clusterctl config view
+
+		# Displays the effective clusterctl configuration including secret values.
+		clusterctl config view --show-secrets`),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigView(cfgFile, os.Stdout)
+	},
+}
+
+func init() {
+	configViewCmd.Flags().BoolVar(&cvo.showSecrets, "show-secrets", false,
+		"Print the values of variables likely to contain secrets.")
+	configCmd.AddCommand(configViewCmd)
+}
+
+func runConfigView(cfgFile string, out io.Writer) error {
+	if out == nil {
+		return errors.New("unable to print to nil output writer")
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	variables, err := c.GetVariablesConfig()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSOURCE\tVALUE")
+	for _, v := range variables {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, v.Source, variableValue(v, cvo.showSecrets))
+	}
+	return w.Flush()
+}
+
+// variableValue returns the value to be printed for a variable, redacting values likely to contain secrets.
+func variableValue(v client.Variable, showSecrets bool) string {
+	if showSecrets || v.Value == "" {
+		return v.Value
+	}
+	name := strings.ToUpper(v.Name)
+	for _, fragment := range sensitiveVariableFragments {
+		if strings.Contains(name, fragment) {
+			return maskedValue
+		}
+	}
+	return v.Value
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+func Test_runConfigView(t *testing.T) {
+	g := NewWithT(t)
+
+	tmpDir, err := os.MkdirTemp("", "cc")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "clusterctl.yaml")
+	g.Expect(os.WriteFile(path, []byte("FOO_REGION: eu-west-1\nFOO_TOKEN: secret\n"), 0600)).To(Succeed())
+
+	buf := bytes.NewBufferString("")
+	g.Expect(runConfigView(path, buf)).To(Succeed())
+	g.Expect(buf.String()).To(MatchRegexp(`FOO_REGION\s+config file\s+eu-west-1`))
+	g.Expect(buf.String()).To(MatchRegexp(`FOO_TOKEN\s+config file\s+<redacted>`))
+	g.Expect(buf.String()).To(MatchRegexp(`cert-manager\s+default\s+`))
+}
+
+func Test_variableValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		variable    client.Variable
+		showSecrets bool
+		want        string
+	}{
+		{
+			name:     "prints plain values",
+			variable: client.Variable{Name: "AWS_REGION", Value: "eu-west-1"},
+			want:     "eu-west-1",
+		},
+		{
+			name:     "redacts values likely to contain secrets",
+			variable: client.Variable{Name: "GITHUB_TOKEN", Value: "foo"},
+			want:     maskedValue,
+		},
+		{
+			name:        "prints secrets if requested",
+			variable:    client.Variable{Name: "AWS_B64ENCODED_CREDENTIALS", Value: "foo"},
+			showSecrets: true,
+			want:        "foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(variableValue(tt.variable, tt.showSecrets)).To(Equal(tt.want))
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package cmd
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -31,6 +33,8 @@ type deleteOptions struct {
 	infrastructureProviders []string
 	includeNamespace        bool
 	includeCRDs             bool
+	includeClusters         bool
+	clusterDeletionTimeout  time.Duration
 	deleteAll               bool
 }
 
@@ -72,13 +76,17 @@ var deleteCmd = &cobra.Command{
 		# Cluster API Providers are orphaned and there might be ongoing costs incurred as a result of this.
 		clusterctl delete --infrastructure aws --include-namespace
 
+		# Delete all the workload clusters, waiting for the providers to tear down their infrastructure,
+		# and then delete all the providers.
+		clusterctl delete --all --include-clusters
+
 		# Reset the management cluster to its original state
 		# Important! As a consequence of this operation all the corresponding resources on target clouds
 		# are "orphaned" and thus there may be ongoing costs incurred as a result of this.
 		clusterctl delete --all --include-crd  --include-namespace`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runDelete()
+		return runDelete(cmd)
 	},
 }
 
@@ -92,6 +100,10 @@ func init() {
 		"Forces the deletion of the namespace where the providers are hosted (and of all the contained objects)")
 	deleteCmd.Flags().BoolVar(&dd.includeCRDs, "include-crd", false,
 		"Forces the deletion of the provider's CRDs (and of all the related objects)")
+	deleteCmd.Flags().BoolVar(&dd.includeClusters, "include-clusters", false,
+		"Deletes all the workload clusters and waits for their infrastructure to be torn down before deleting the providers")
+	deleteCmd.Flags().DurationVar(&dd.clusterDeletionTimeout, "cluster-deletion-timeout", 30*time.Minute,
+		"The time to wait for the workload clusters to be deleted when --include-clusters is set")
 
 	deleteCmd.Flags().StringVar(&dd.coreProvider, "core", "",
 		"Core provider version (e.g. cluster-api:v0.3.0) to delete from the management cluster")
@@ -108,7 +120,7 @@ func init() {
 	RootCmd.AddCommand(deleteCmd)
 }
 
-func runDelete() error {
+func runDelete(cmd *cobra.Command) error {
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
@@ -127,10 +139,16 @@ func runDelete() error {
 		return errors.New("At least one of --core, --bootstrap, --control-plane, --infrastructure should be specified or the --all flag should be set")
 	}
 
+	if cmd.Flags().Changed("cluster-deletion-timeout") && !dd.includeClusters {
+		return errors.New("The --cluster-deletion-timeout flag can be used only in combination with --include-clusters")
+	}
+
 	return c.Delete(client.DeleteOptions{
 		Kubeconfig:              client.Kubeconfig{Path: dd.kubeconfig, Context: dd.kubeconfigContext},
 		IncludeNamespace:        dd.includeNamespace,
 		IncludeCRDs:             dd.includeCRDs,
+		IncludeClusters:         dd.includeClusters,
+		ClusterDeletionTimeout:  dd.clusterDeletionTimeout,
 		CoreProvider:            dd.coreProvider,
 		BootstrapProviders:      dd.bootstrapProviders,
 		InfrastructureProviders: dd.infrastructureProviders,
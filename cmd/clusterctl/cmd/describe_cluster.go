@@ -17,7 +17,10 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -25,6 +28,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/gobuffalo/flect"
 	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
@@ -32,6 +36,7 @@ import (
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -41,6 +46,20 @@ const (
 	pipe            = `│ `
 )
 
+const (
+	// DescribeOutputTree is an option used to print the cluster status as a tree view.
+	DescribeOutputTree = "tree"
+	// DescribeOutputJSON is an option used to print the cluster status in json format.
+	DescribeOutputJSON = "json"
+	// DescribeOutputYaml is an option used to print the cluster status in yaml format.
+	DescribeOutputYaml = "yaml"
+)
+
+var (
+	// DescribeOutputs is a list of valid describe cluster outputs.
+	DescribeOutputs = []string{DescribeOutputTree, DescribeOutputJSON, DescribeOutputYaml}
+)
+
 var (
 	gray   = color.New(color.FgHiBlack)
 	red    = color.New(color.FgRed)
@@ -58,6 +77,7 @@ type describeClusterOptions struct {
 	showOtherConditions string
 	disableNoEcho       bool
 	disableGrouping     bool
+	output              string
 }
 
 var dc = &describeClusterOptions{}
@@ -86,7 +106,13 @@ var describeClusterClusterCmd = &cobra.Command{
 
 		# Describe the cluster named test-1 disabling automatic echo suppression
         # e.g. show the infrastructure machine objects, no matter if the current state is already reported by the machine's Ready condition.
-		clusterctl describe cluster test-1`),
+		clusterctl describe cluster test-1 --disable-no-echo
+
+		# Describe the cluster named test-1 down to each individual Machine, with its infrastructure machine and bootstrap config.
+		clusterctl describe cluster test-1 --disable-grouping --disable-no-echo
+
+		# Describe the cluster named test-1 in json format, e.g. for processing the output with other tools.
+		clusterctl describe cluster test-1 -o json`),
 
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -108,6 +134,8 @@ func init() {
 		"Disable hiding of a MachineInfrastructure and BootstrapConfig when ready condition is true or it has the Status, Severity and Reason of the machine's object.")
 	describeClusterClusterCmd.Flags().BoolVar(&dc.disableGrouping, "disable-grouping", false,
 		"Disable grouping machines when ready condition has the same Status, Severity and Reason.")
+	describeClusterClusterCmd.Flags().StringVarP(&dc.output, "output", "o", DescribeOutputTree,
+		fmt.Sprintf("Output format. Valid values: %v.", DescribeOutputs))
 
 	// completions
 	describeClusterClusterCmd.ValidArgsFunction = resourceNameCompletionFunc(
@@ -122,6 +150,10 @@ func init() {
 }
 
 func runDescribeCluster(name string) error {
+	if dc.output != DescribeOutputTree && dc.output != DescribeOutputJSON && dc.output != DescribeOutputYaml {
+		return errors.Errorf("invalid output format %q. Valid values: %v", dc.output, DescribeOutputs)
+	}
+
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
@@ -139,10 +171,106 @@ func runDescribeCluster(name string) error {
 		return err
 	}
 
-	printObjectTree(tree)
+	switch dc.output {
+	case DescribeOutputJSON, DescribeOutputYaml:
+		return printObjectTreeData(os.Stdout, tree, dc.output)
+	default:
+		printObjectTree(tree)
+	}
 	return nil
 }
 
+// objectTreeNode is the machine-readable representation of an object in the cluster status tree.
+type objectTreeNode struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+
+	// MetaName is the name describing the role of the object in the cluster, e.g. ControlPlane.
+	MetaName string `json:"metaName,omitempty"`
+
+	// Deleted is true if the object is being deleted.
+	Deleted bool `json:"deleted,omitempty"`
+
+	// Group is true if the node represents a group of objects with the same ready condition;
+	// the names of those objects are listed in GroupItems.
+	Group      bool     `json:"group,omitempty"`
+	GroupItems []string `json:"groupItems,omitempty"`
+
+	Ready      *clusterv1.Condition  `json:"ready,omitempty"`
+	Conditions []clusterv1.Condition `json:"conditions,omitempty"`
+
+	Children []objectTreeNode `json:"children,omitempty"`
+}
+
+// printObjectTreeData prints the cluster status in a machine-readable format.
+func printObjectTreeData(w io.Writer, objectTree *tree.ObjectTree, output string) error {
+	root := newObjectTreeNode(objectTree, objectTree.GetRoot())
+
+	var out []byte
+	var err error
+	switch output {
+	case DescribeOutputJSON:
+		out, err = json.MarshalIndent(root, "", "  ")
+		out = append(out, '\n')
+	case DescribeOutputYaml:
+		out, err = yaml.Marshal(root)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(out)
+	return err
+}
+
+// newObjectTreeNode returns the objectTreeNode for a given object, and recursively for all the object's children.
+func newObjectTreeNode(objectTree *tree.ObjectTree, obj ctrlclient.Object) objectTreeNode {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	node := objectTreeNode{
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		MetaName:  tree.GetMetaName(obj),
+		Deleted:   !obj.GetDeletionTimestamp().IsZero(),
+	}
+
+	// Virtual objects are not real Kubernetes objects, so they do not have an API version.
+	if !tree.IsVirtualObject(obj) {
+		node.APIVersion = gvk.GroupVersion().String()
+	}
+
+	if tree.IsGroupObject(obj) {
+		node.Group = true
+		node.Kind = strings.TrimSuffix(gvk.Kind, "Group")
+		node.GroupItems = strings.Split(tree.GetGroupItems(obj), tree.GroupItemsSeparator)
+	}
+
+	if ready := tree.GetReadyCondition(obj); ready != nil {
+		node.Ready = ready
+	}
+	for _, c := range tree.GetOtherConditions(obj) {
+		node.Conditions = append(node.Conditions, *c)
+	}
+
+	// NOTE: Children objects are sorted by kind and name, so the output is stable.
+	childrenObj := objectTree.GetObjectsByParent(obj.GetUID())
+	sort.Slice(childrenObj, func(i, j int) bool {
+		kindI := childrenObj[i].GetObjectKind().GroupVersionKind().Kind
+		kindJ := childrenObj[j].GetObjectKind().GroupVersionKind().Kind
+		if kindI != kindJ {
+			return kindI < kindJ
+		}
+		return childrenObj[i].GetName() < childrenObj[j].GetName()
+	})
+	for _, child := range childrenObj {
+		node.Children = append(node.Children, newObjectTreeNode(objectTree, child))
+	}
+
+	return node
+}
+
 // printObjectTree prints the cluster status to stdout.
 func printObjectTree(tree *tree.ObjectTree) {
 	// Creates the output table
@@ -290,6 +290,50 @@ func Test_TreePrefix(t *testing.T) {
 	}
 }
 
+func Test_newObjectTreeNode(t *testing.T) {
+	g := NewWithT(t)
+
+	root := fakeObject("root", withCondition(conditions.TrueCondition(clusterv1.ReadyCondition)))
+	objectTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+
+	o1 := fakeObject("child1",
+		withAnnotation(tree.ObjectMetaNameAnnotation, "MetaName"),
+		withCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+		withCondition(conditions.FalseCondition("C1.1", "Reason", clusterv1.ConditionSeverityWarning, "")),
+	)
+	o2 := fakeObject("child2",
+		withAnnotation(tree.VirtualObjectAnnotation, "True"),
+		withAnnotation(tree.GroupObjectAnnotation, "True"),
+		withAnnotation(tree.GroupItemsAnnotation, "m1, m2"),
+	)
+	o2.GetObjectKind().SetGroupVersionKind(clusterv1.GroupVersion.WithKind("MachineGroup"))
+	o1_1 := fakeObject("child1.1", withDeletionTimestamp)
+	objectTree.Add(root, o1)
+	objectTree.Add(o1, o1_1)
+	objectTree.Add(root, o2)
+
+	got := newObjectTreeNode(objectTree, root)
+
+	g.Expect(got.Name).To(Equal("root"))
+	g.Expect(got.Ready).ToNot(BeNil())
+	g.Expect(got.Ready.Status).To(BeEquivalentTo("True"))
+	g.Expect(got.Children).To(HaveLen(2))
+
+	// Children are sorted by kind, so the group of machines comes before the object.
+	g.Expect(got.Children[0].Kind).To(Equal("Machine"))
+	g.Expect(got.Children[0].APIVersion).To(BeEmpty())
+	g.Expect(got.Children[0].Group).To(BeTrue())
+	g.Expect(got.Children[0].GroupItems).To(Equal([]string{"m1", "m2"}))
+
+	g.Expect(got.Children[1].Name).To(Equal("child1"))
+	g.Expect(got.Children[1].MetaName).To(Equal("MetaName"))
+	g.Expect(got.Children[1].Conditions).To(HaveLen(1))
+	g.Expect(got.Children[1].Conditions[0].Type).To(BeEquivalentTo("C1.1"))
+	g.Expect(got.Children[1].Children).To(HaveLen(1))
+	g.Expect(got.Children[1].Children[0].Name).To(Equal("child1.1"))
+	g.Expect(got.Children[1].Children[0].Deleted).To(BeTrue())
+}
+
 type objectOption func(object ctrlclient.Object)
 
 func fakeObject(name string, options ...objectOption) ctrlclient.Object {
@@ -0,0 +1,309 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DescribeProvidersOutputText is an option used to print the providers description as a table.
+	DescribeProvidersOutputText = "text"
+)
+
+var (
+	// DescribeProvidersOutputs is a list of valid describe providers outputs.
+	DescribeProvidersOutputs = []string{DescribeProvidersOutputText, DescribeOutputJSON, DescribeOutputYaml}
+)
+
+type describeProvidersOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	output            string
+}
+
+var dp = &describeProvidersOptions{}
+
+var describeProvidersCmd = &cobra.Command{
+	Use:   "providers",
+	Args:  cobra.NoArgs,
+	Short: "Describe the providers installed in the management cluster.",
+	Long: LongDesc(`
+		Describe the providers installed in the management cluster.
+
+		For each provider the command reports the installed version and the Cluster API contract it implements,
+		the namespaces watched by the provider's controllers, the availability of the controllers, the readiness
+		of the webhooks, the storage versions of the provider's CRDs and the versions available for upgrade.
+
+		The Cluster API contract and the versions available for upgrade are read from the provider repositories;
+		if a repository cannot be reached, this information is reported as unknown.`),
+
+	Example: Examples(`
+		# Describe the providers installed in the management cluster.
+		clusterctl describe providers
+
+		# Describe the providers installed in the management cluster in yaml format, including the details of webhooks and CRDs.
+		clusterctl describe providers -o yaml`),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDescribeProviders(os.Stdout)
+	},
+}
+
+func init() {
+	describeProvidersCmd.Flags().StringVar(&dp.kubeconfig, "kubeconfig", "",
+		"Path to a kubeconfig file to use for the management cluster. If empty, default discovery rules apply.")
+	describeProvidersCmd.Flags().StringVar(&dp.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	describeProvidersCmd.Flags().StringVarP(&dp.output, "output", "o", DescribeProvidersOutputText,
+		fmt.Sprintf("Output format. Valid values: %v.", DescribeProvidersOutputs))
+
+	describeCmd.AddCommand(describeProvidersCmd)
+}
+
+func runDescribeProviders(out io.Writer) error {
+	if dp.output != DescribeProvidersOutputText && dp.output != DescribeOutputJSON && dp.output != DescribeOutputYaml {
+		return errors.Errorf("invalid output format %q. Valid values: %v", dp.output, DescribeProvidersOutputs)
+	}
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	descriptions, err := c.DescribeProviders(client.DescribeProvidersOptions{
+		Kubeconfig: client.Kubeconfig{Path: dp.kubeconfig, Context: dp.kubeconfigContext},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(descriptions) == 0 {
+		fmt.Fprintln(out, "There are no providers in the cluster. Please use clusterctl init to initialize a Cluster API management cluster.")
+		return nil
+	}
+
+	// ensure providers are sorted consistently (by Type, Name, Namespace).
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Provider.Type < descriptions[j].Provider.Type ||
+			(descriptions[i].Provider.Type == descriptions[j].Provider.Type && descriptions[i].Provider.Name < descriptions[j].Provider.Name) ||
+			(descriptions[i].Provider.Type == descriptions[j].Provider.Type && descriptions[i].Provider.Name == descriptions[j].Provider.Name && descriptions[i].Provider.Namespace < descriptions[j].Provider.Namespace)
+	})
+
+	return printProviderDescriptions(out, descriptions, dp.output)
+}
+
+// providerDescriptionData is the machine-readable representation of a provider description.
+type providerDescriptionData struct {
+	Name              string                 `json:"name"`
+	Namespace         string                 `json:"namespace"`
+	Type              string                 `json:"type"`
+	ProviderName      string                 `json:"providerName"`
+	Version           string                 `json:"version"`
+	Contract          string                 `json:"contract,omitempty"`
+	WatchedNamespaces []string               `json:"watchedNamespaces,omitempty"`
+	Controllers       []controllerStatusData `json:"controllers,omitempty"`
+	Webhooks          []webhookStatusData    `json:"webhooks,omitempty"`
+	CRDs              []crdStatusData        `json:"crds,omitempty"`
+	Upgrades          []providerUpgradeData  `json:"upgrades,omitempty"`
+	UpgradesError     string                 `json:"upgradesError,omitempty"`
+}
+
+type controllerStatusData struct {
+	Name              string `json:"name"`
+	Replicas          int32  `json:"replicas"`
+	AvailableReplicas int32  `json:"availableReplicas"`
+}
+
+type webhookStatusData struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Ready bool   `json:"ready"`
+}
+
+type crdStatusData struct {
+	Name           string   `json:"name"`
+	StorageVersion string   `json:"storageVersion"`
+	StoredVersions []string `json:"storedVersions,omitempty"`
+}
+
+type providerUpgradeData struct {
+	Contract string `json:"contract"`
+	Version  string `json:"version"`
+}
+
+func printProviderDescriptions(out io.Writer, descriptions []client.ProviderDescription, output string) error {
+	switch output {
+	case DescribeOutputJSON, DescribeOutputYaml:
+		data := make([]providerDescriptionData, 0, len(descriptions))
+		for _, d := range descriptions {
+			data = append(data, toProviderDescriptionData(d))
+		}
+		var raw []byte
+		var err error
+		if output == DescribeOutputJSON {
+			raw, err = json.MarshalIndent(data, "", "  ")
+		} else {
+			raw, err = yaml.Marshal(data)
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the providers description")
+		}
+		fmt.Fprintln(out, strings.TrimSuffix(string(raw), "\n"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tNAMESPACE\tTYPE\tVERSION\tCONTRACT\tWATCHING\tCONTROLLERS\tWEBHOOKS\tCRD STORAGE\tUPGRADES")
+	for _, d := range descriptions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			d.Provider.Name,
+			d.Provider.Namespace,
+			d.Provider.Type,
+			d.Provider.Version,
+			prettifyContract(d),
+			prettifyWatchedNamespaces(d),
+			prettifyControllers(d),
+			prettifyWebhooks(d),
+			prettifyCRDStorage(d),
+			prettifyUpgrades(d),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, d := range descriptions {
+		if d.UpgradesError != "" {
+			fmt.Fprintf(out, "\nFailed to read the repository for the %s provider: %s\n", d.Provider.InstanceName(), d.UpgradesError)
+		}
+	}
+	return nil
+}
+
+func toProviderDescriptionData(d client.ProviderDescription) providerDescriptionData {
+	data := providerDescriptionData{
+		Name:              d.Provider.Name,
+		Namespace:         d.Provider.Namespace,
+		Type:              d.Provider.Type,
+		ProviderName:      d.Provider.ProviderName,
+		Version:           d.Provider.Version,
+		Contract:          d.Contract,
+		WatchedNamespaces: d.WatchedNamespaces,
+		UpgradesError:     d.UpgradesError,
+	}
+	for _, c := range d.Controllers {
+		data.Controllers = append(data.Controllers, controllerStatusData(c))
+	}
+	for _, w := range d.Webhooks {
+		data.Webhooks = append(data.Webhooks, webhookStatusData(w))
+	}
+	for _, c := range d.CRDs {
+		data.CRDs = append(data.CRDs, crdStatusData(c))
+	}
+	for _, u := range d.Upgrades {
+		data.Upgrades = append(data.Upgrades, providerUpgradeData(u))
+	}
+	return data
+}
+
+func prettifyContract(d client.ProviderDescription) string {
+	if d.Contract == "" {
+		return "unknown"
+	}
+	return d.Contract
+}
+
+func prettifyWatchedNamespaces(d client.ProviderDescription) string {
+	if len(d.WatchedNamespaces) == 0 {
+		return "all namespaces"
+	}
+	return strings.Join(d.WatchedNamespaces, ",")
+}
+
+// prettifyControllers returns the number of available replicas over the number of desired replicas for the provider's controllers.
+func prettifyControllers(d client.ProviderDescription) string {
+	if len(d.Controllers) == 0 {
+		return "-"
+	}
+	var replicas, available int32
+	for _, c := range d.Controllers {
+		replicas += c.Replicas
+		available += c.AvailableReplicas
+	}
+	return fmt.Sprintf("%d/%d available", available, replicas)
+}
+
+// prettifyWebhooks returns the number of ready webhooks over the number of webhooks served by the provider.
+func prettifyWebhooks(d client.ProviderDescription) string {
+	if len(d.Webhooks) == 0 {
+		return "-"
+	}
+	ready := 0
+	for _, w := range d.Webhooks {
+		if w.Ready {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d ready", ready, len(d.Webhooks))
+}
+
+// prettifyCRDStorage returns the storage versions of the provider's CRDs; versions with objects still persisted
+// in a version different than the storage version are marked with an asterisk.
+func prettifyCRDStorage(d client.ProviderDescription) string {
+	if len(d.CRDs) == 0 {
+		return "-"
+	}
+	versions := sets.NewString()
+	for _, c := range d.CRDs {
+		version := c.StorageVersion
+		for _, stored := range c.StoredVersions {
+			if stored != c.StorageVersion {
+				version += "*"
+				break
+			}
+		}
+		versions.Insert(version)
+	}
+	return strings.Join(versions.List(), ",")
+}
+
+func prettifyUpgrades(d client.ProviderDescription) string {
+	if d.UpgradesError != "" {
+		return "unknown"
+	}
+	if len(d.Upgrades) == 0 {
+		return "up to date"
+	}
+	upgrades := make([]string, 0, len(d.Upgrades))
+	for _, u := range d.Upgrades {
+		upgrades = append(upgrades, fmt.Sprintf("%s (%s)", u.Version, u.Contract))
+	}
+	return strings.Join(upgrades, ",")
+}
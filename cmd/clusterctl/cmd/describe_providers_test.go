@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+func Test_printProviderDescriptions(t *testing.T) {
+	descriptions := []client.ProviderDescription{
+		{
+			Provider: clusterctlv1.Provider{
+				ObjectMeta:   metav1.ObjectMeta{Namespace: "capi-system", Name: "cluster-api"},
+				ProviderName: "cluster-api",
+				Type:         string(clusterctlv1.CoreProviderType),
+				Version:      "v1.0.0",
+			},
+			Contract: "v1beta1",
+			Controllers: []cluster.ControllerStatus{
+				{Name: "capi-controller-manager", Replicas: 1, AvailableReplicas: 1},
+			},
+			Webhooks: []cluster.WebhookStatus{
+				{Name: "capi-validating-webhook-configuration", Type: cluster.ValidatingWebhook, Ready: true},
+				{Name: "clusters.cluster.x-k8s.io", Type: cluster.ConversionWebhook, Ready: false},
+			},
+			CRDs: []cluster.CRDStatus{
+				{Name: "clusters.cluster.x-k8s.io", StorageVersion: "v1beta1", StoredVersions: []string{"v1alpha4", "v1beta1"}},
+				{Name: "machines.cluster.x-k8s.io", StorageVersion: "v1beta1", StoredVersions: []string{"v1beta1"}},
+			},
+			Upgrades: []cluster.ProviderUpgrade{
+				{Contract: "v1beta1", Version: "v1.0.1"},
+			},
+		},
+		{
+			Provider: clusterctlv1.Provider{
+				ObjectMeta:   metav1.ObjectMeta{Namespace: "capa-system", Name: "infrastructure-aws"},
+				ProviderName: "aws",
+				Type:         string(clusterctlv1.InfrastructureProviderType),
+				Version:      "v1.0.0",
+			},
+			WatchedNamespaces: []string{"ns1"},
+			UpgradesError:     "repository not reachable",
+		},
+	}
+
+	t.Run("prints a table", func(t *testing.T) {
+		g := NewWithT(t)
+
+		buf := bytes.NewBufferString("")
+		g.Expect(printProviderDescriptions(buf, descriptions, DescribeProvidersOutputText)).To(Succeed())
+
+		out := buf.String()
+		g.Expect(out).To(MatchRegexp(`cluster-api\s+capi-system\s+CoreProvider\s+v1.0.0\s+v1beta1\s+all namespaces\s+1/1 available\s+1/2 ready\s+v1beta1,v1beta1\*\s+v1.0.1 \(v1beta1\)`))
+		g.Expect(out).To(MatchRegexp(`infrastructure-aws\s+capa-system\s+InfrastructureProvider\s+v1.0.0\s+unknown\s+ns1\s+-\s+-\s+-\s+unknown`))
+		g.Expect(out).To(ContainSubstring("Failed to read the repository for the capa-system/infrastructure-aws provider: repository not reachable"))
+	})
+
+	t.Run("prints yaml", func(t *testing.T) {
+		g := NewWithT(t)
+
+		buf := bytes.NewBufferString("")
+		g.Expect(printProviderDescriptions(buf, descriptions, DescribeOutputYaml)).To(Succeed())
+
+		out := buf.String()
+		g.Expect(out).To(ContainSubstring("storageVersion: v1beta1"))
+		g.Expect(out).To(ContainSubstring("upgradesError: repository not reachable"))
+	})
+}
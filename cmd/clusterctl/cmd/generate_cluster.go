@@ -39,6 +39,9 @@ type generateClusterOptions struct {
 	configMapName      string
 	configMapDataKey   string
 
+	clusterClassName      string
+	clusterClassNamespace string
+
 	listVariables bool
 }
 
@@ -84,6 +87,11 @@ var generateClusterClusterCmd = &cobra.Command{
 		# Generates a yaml file for creating workload clusters using a template stored locally.
 		clusterctl generate cluster my-cluster --from ~/workspace/cluster-template.yaml
 
+		# Generates a yaml file for creating workload clusters with a managed topology using a ClusterClass
+		# from the management cluster; values for the ClusterClass variables are read from os environment variables
+		# or the clusterctl config file, e.g. the imageRepository variable is read from IMAGE_REPOSITORY.
+		clusterctl generate cluster my-cluster --from-clusterclass my-class
+
 		# Prints the list of variables required by the yaml file for creating workload cluster.
 		clusterctl generate cluster my-cluster --list-variables`),
 
@@ -127,6 +135,12 @@ func init() {
 	generateClusterClusterCmd.Flags().StringVar(&gc.configMapDataKey, "from-config-map-key", "",
 		fmt.Sprintf("The ConfigMap.Data key where the workload cluster template is hosted. If unspecified, %q will be used", client.DefaultCustomTemplateConfigMapKey))
 
+	// flags for the ClusterClass source
+	generateClusterClusterCmd.Flags().StringVar(&gc.clusterClassName, "from-clusterclass", "",
+		"The ClusterClass to generate a workload cluster with a managed topology from. This can be used as alternative to read from the provider repository, from an URL or from a ConfigMap")
+	generateClusterClusterCmd.Flags().StringVar(&gc.clusterClassNamespace, "from-clusterclass-namespace", "",
+		"The namespace where the ClusterClass exists. If unspecified, the target namespace will be used")
+
 	// other flags
 	generateClusterClusterCmd.Flags().BoolVar(&gc.listVariables, "list-variables", false,
 		"Returns the list of variables expected by the template instead of the template yaml")
@@ -169,6 +183,13 @@ func runGenerateClusterTemplate(cmd *cobra.Command, name string) error {
 		}
 	}
 
+	if gc.clusterClassName != "" || gc.clusterClassNamespace != "" {
+		templateOptions.ClusterClassSource = &client.ClusterClassSourceOptions{
+			Namespace: gc.clusterClassNamespace,
+			Name:      gc.clusterClassName,
+		}
+	}
+
 	if gc.infrastructureProvider != "" || gc.flavor != "" {
 		templateOptions.ProviderRepositorySource = &client.ProviderRepositorySourceOptions{
 			InfrastructureProvider: gc.infrastructureProvider,
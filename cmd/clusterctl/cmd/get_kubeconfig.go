@@ -18,16 +18,24 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 )
 
 type getKubeconfigOptions struct {
-	kubeconfig        string
-	kubeconfigContext string
-	namespace         string
+	kubeconfig           string
+	kubeconfigContext    string
+	namespace            string
+	clientCertificateTTL time.Duration
+	execCommand          string
+	execArgs             []string
+	execAPIVersion       string
+	contextName          string
+	mergeInto            string
 }
 
 var gk = &getKubeconfigOptions{}
@@ -43,7 +51,17 @@ var getKubeconfigCmd = &cobra.Command{
 		clusterctl get kubeconfig <name of workload cluster>
 
 		# Get the workload cluster's kubeconfig in a particular namespace.
-		clusterctl get kubeconfig <name of workload cluster> --namespace foo`),
+		clusterctl get kubeconfig <name of workload cluster> --namespace foo
+
+		# Get a kubeconfig for the workload cluster with a new admin client certificate valid for 8 hours.
+		clusterctl get kubeconfig <name of workload cluster> --client-certificate-ttl 8h
+
+		# Get a kubeconfig for the workload cluster using an OIDC exec credential plugin instead of the admin credentials.
+		clusterctl get kubeconfig <name of workload cluster> --exec-command kubectl \
+		  --exec-arg oidc-login --exec-arg get-token --exec-arg=--oidc-issuer-url=https://issuer.example.com
+
+		# Merge the workload cluster's kubeconfig into the default kubeconfig file, using "my-cluster" as context name.
+		clusterctl get kubeconfig <name of workload cluster> --context-name my-cluster --merge-into ~/.kube/config`),
 
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -58,6 +76,18 @@ func init() {
 		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
 	getKubeconfigCmd.Flags().StringVar(&gk.kubeconfigContext, "kubeconfig-context", "",
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	getKubeconfigCmd.Flags().DurationVar(&gk.clientCertificateTTL, "client-certificate-ttl", 0,
+		"If set, generates a new admin client certificate valid for the given duration (e.g. 8h), signed by the cluster CA.")
+	getKubeconfigCmd.Flags().StringVar(&gk.execCommand, "exec-command", "",
+		"If set, replaces the admin credentials with an exec credential plugin running the given command (e.g. an OIDC login plugin).")
+	getKubeconfigCmd.Flags().StringArrayVar(&gk.execArgs, "exec-arg", nil,
+		"Argument to pass to the exec credential plugin. Can be repeated.")
+	getKubeconfigCmd.Flags().StringVar(&gk.execAPIVersion, "exec-api-version", "",
+		"API version of the exec credential plugin. If empty, client.authentication.k8s.io/v1beta1 will be used.")
+	getKubeconfigCmd.Flags().StringVar(&gk.contextName, "context-name", "",
+		"Name to be used for the context, cluster and user in the generated kubeconfig.")
+	getKubeconfigCmd.Flags().StringVar(&gk.mergeInto, "merge-into", "",
+		"Path of a kubeconfig file where to merge the workload cluster's kubeconfig, instead of printing it.")
 
 	// completions
 	getKubeconfigCmd.ValidArgsFunction = resourceNameCompletionFunc(
@@ -77,16 +107,30 @@ func runGetKubeconfig(workloadClusterName string) error {
 		return err
 	}
 
+	if len(gk.execArgs) > 0 && gk.execCommand == "" {
+		return errors.New("The --exec-arg flag can be used only in combination with --exec-command")
+	}
+
 	options := client.GetKubeconfigOptions{
-		Kubeconfig:          client.Kubeconfig{Path: gk.kubeconfig, Context: gk.kubeconfigContext},
-		WorkloadClusterName: workloadClusterName,
-		Namespace:           gk.namespace,
+		Kubeconfig:           client.Kubeconfig{Path: gk.kubeconfig, Context: gk.kubeconfigContext},
+		WorkloadClusterName:  workloadClusterName,
+		Namespace:            gk.namespace,
+		ClientCertificateTTL: gk.clientCertificateTTL,
+		ExecCommand:          gk.execCommand,
+		ExecArgs:             gk.execArgs,
+		ExecAPIVersion:       gk.execAPIVersion,
+		ContextName:          gk.contextName,
+		MergeInto:            gk.mergeInto,
 	}
 
 	out, err := c.GetKubeconfig(options)
 	if err != nil {
 		return err
 	}
+	if gk.mergeInto != "" {
+		fmt.Printf("Kubeconfig for the workload cluster %q merged into %s\n", workloadClusterName, gk.mergeInto)
+		return nil
+	}
 	fmt.Println(out)
 	return nil
 }
@@ -18,10 +18,15 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
 )
 
 type initOptions struct {
@@ -33,6 +38,8 @@ type initOptions struct {
 	infrastructureProviders []string
 	targetNamespace         string
 	listImages              bool
+	dryRun                  bool
+	outputDir               string
 	waitProviders           bool
 	waitProviderTimeout     int
 }
@@ -81,7 +88,15 @@ var initCmd = &cobra.Command{
 		# Lists the container images required for initializing the management cluster.
 		#
 		# Note: This command is a dry-run; it won't perform any action other than printing to screen.
-		clusterctl init --infrastructure aws --list-images`),
+		clusterctl init --infrastructure aws --list-images
+
+		# Prints the provider components YAML, with all the variables substituted, instead of installing the providers.
+		#
+		# Note: This command is a dry-run; it won't perform any action other than printing to screen.
+		clusterctl init --infrastructure aws --dry-run
+
+		# Writes the provider components YAML into a directory, one file for each provider, e.g. to commit them into a GitOps repository.
+		clusterctl init --infrastructure aws --dry-run --output-dir ./management-cluster`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runInit()
@@ -112,10 +127,22 @@ func init() {
 	initCmd.Flags().BoolVar(&initOpts.listImages, "list-images", false,
 		"Lists the container images required for initializing the management cluster (without actually installing the providers)")
 
+	initCmd.Flags().BoolVar(&initOpts.dryRun, "dry-run", false,
+		"Render the provider components YAML, with all the variables substituted, instead of installing the providers. The management cluster is not required to be reachable.")
+	initCmd.Flags().StringVar(&initOpts.outputDir, "output-dir", "",
+		"The directory where the provider components YAML should be written when using --dry-run, one file for each provider. If unspecified, the YAML is printed to stdout.")
+
 	RootCmd.AddCommand(initCmd)
 }
 
 func runInit() error {
+	if initOpts.outputDir != "" && !initOpts.dryRun {
+		return errors.New("--output-dir can only be used with --dry-run")
+	}
+	if initOpts.dryRun && initOpts.listImages {
+		return errors.New("--dry-run and --list-images can't be used together")
+	}
+
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
@@ -145,8 +172,52 @@ func runInit() error {
 		return nil
 	}
 
+	if initOpts.dryRun {
+		components, err := c.InitManifests(options)
+		if err != nil {
+			return err
+		}
+		if initOpts.outputDir != "" {
+			return writeInitManifests(components, initOpts.outputDir)
+		}
+		return printInitManifests(components, os.Stdout)
+	}
+
 	if _, err := c.Init(options); err != nil {
 		return err
 	}
 	return nil
 }
+
+// printInitManifests prints the YAML of the provider components to a writer.
+func printInitManifests(components []client.Components, w io.Writer) error {
+	yamls := make([][]byte, 0, len(components))
+	for _, c := range components {
+		yaml, err := c.Yaml()
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate YAML for the %s provider", c.ManifestLabel())
+		}
+		yamls = append(yamls, yaml)
+	}
+	_, err := fmt.Fprintln(w, string(utilyaml.JoinYaml(yamls...)))
+	return err
+}
+
+// writeInitManifests writes the YAML of the provider components into a directory, one file for each provider.
+func writeInitManifests(components []client.Components, dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return errors.Wrapf(err, "failed to create directory %q", dir)
+	}
+	for _, c := range components {
+		yaml, err := c.Yaml()
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate YAML for the %s provider", c.ManifestLabel())
+		}
+		fileName := filepath.Join(dir, fmt.Sprintf("%s.yaml", c.ManifestLabel()))
+		if err := os.WriteFile(fileName, yaml, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write %q", fileName)
+		}
+		fmt.Printf("Wrote %s %s components to %s\n", c.ManifestLabel(), c.Version(), fileName)
+	}
+	return nil
+}
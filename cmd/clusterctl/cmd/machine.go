@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/cmd/machine"
+)
+
+var (
+	machineLong = LongDesc(`
+		Manage individual Cluster API Machines.`)
+
+	machineExample = Examples(`
+		# Cordon and drain the Node hosted on a machine
+		clusterctl alpha machine drain my-md-0-6d8f9c7c5b-x7k2p
+
+		# Delete a machine, scaling down its MachineDeployment or MachineSet
+		clusterctl alpha machine delete my-md-0-6d8f9c7c5b-x7k2p
+
+		# Replace a machine using the remediation of its MachineSet or KubeadmControlPlane
+		clusterctl alpha machine remediate my-cluster-control-plane-8k2hf`)
+
+	machineCmd = &cobra.Command{
+		Use:     "machine SUBCOMMAND",
+		Short:   "Manage individual Cluster API Machines",
+		Long:    machineLong,
+		Example: machineExample,
+	}
+)
+
+func init() {
+	// subcommands
+	machineCmd.AddCommand(machine.NewCmdMachineDrain(cfgFile))
+	machineCmd.AddCommand(machine.NewCmdMachineDelete(cfgFile))
+	machineCmd.AddCommand(machine.NewCmdMachineRemediate(cfgFile))
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+// deleteOptions is the start of the data required to perform the operation.
+type deleteOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	machines          []string
+	namespace         string
+}
+
+var deleteOpt = &deleteOptions{}
+
+var (
+	deleteLong = templates.LongDesc(`
+		Delete the provided Machine without replacing it.
+
+		If the Machine belongs to a MachineSet, the Machine gets the delete-machine annotation and the
+		owning MachineDeployment, or the MachineSet if it does not belong to a MachineDeployment, is scaled
+		down by one replica, so the Machine is deleted first. Machines without a controller are deleted directly.
+		Control plane Machines can't be deleted with this command; use "clusterctl alpha machine remediate" instead.`)
+
+	deleteExample = templates.Examples(`
+		# Delete a machine, scaling down its MachineDeployment.
+		clusterctl alpha machine delete my-md-0-6d8f9c7c5b-x7k2p`)
+)
+
+// NewCmdMachineDelete returns a Command instance for 'machine delete' sub command.
+func NewCmdMachineDelete(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "delete MACHINE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Delete a Machine without replacing it",
+		Long:                  deleteLong,
+		Example:               deleteExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDelete(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&deleteOpt.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&deleteOpt.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVar(&deleteOpt.namespace, "namespace", "", "Namespace where the machine(s) reside. If unspecified, the defult namespace will be used.")
+
+	return cmd
+}
+
+func runDelete(cfgFile string, args []string) error {
+	deleteOpt.machines = args
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.MachineDelete(client.MachineOptions{
+		Kubeconfig: client.Kubeconfig{Path: deleteOpt.kubeconfig, Context: deleteOpt.kubeconfigContext},
+		Namespace:  deleteOpt.namespace,
+		Machines:   deleteOpt.machines,
+	})
+}
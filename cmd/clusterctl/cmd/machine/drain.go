@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package machine implements the clusterctl alpha machine command.
+package machine
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+// drainOptions is the start of the data required to perform the operation.
+type drainOptions struct {
+	kubeconfig         string
+	kubeconfigContext  string
+	machines           []string
+	namespace          string
+	timeout            time.Duration
+	deleteEmptyDirData bool
+}
+
+var drainOpt = &drainOptions{}
+
+var (
+	drainLong = templates.LongDesc(`
+		Cordon and drain the Node hosted on the provided Machine.
+
+		Pods managed by a DaemonSet are ignored. The Machine object is not modified, so the Node will be drained
+		again by the Machine controller when the Machine is deleted.`)
+
+	drainExample = templates.Examples(`
+		# Cordon and drain the Node hosted on a machine.
+		clusterctl alpha machine drain my-md-0-6d8f9c7c5b-x7k2p
+
+		# Drain the Node evicting also pods using emptyDir volumes, giving up after 5 minutes.
+		clusterctl alpha machine drain my-md-0-6d8f9c7c5b-x7k2p --delete-emptydir-data --timeout 5m`)
+)
+
+// NewCmdMachineDrain returns a Command instance for 'machine drain' sub command.
+func NewCmdMachineDrain(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "drain MACHINE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Cordon and drain the Node hosted on a Machine",
+		Long:                  drainLong,
+		Example:               drainExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDrain(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&drainOpt.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&drainOpt.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVar(&drainOpt.namespace, "namespace", "", "Namespace where the machine(s) reside. If unspecified, the defult namespace will be used.")
+	cmd.Flags().DurationVar(&drainOpt.timeout, "timeout", 0, "The length of time to wait before giving up, zero means infinite. Any other values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	cmd.Flags().BoolVar(&drainOpt.deleteEmptyDirData, "delete-emptydir-data", false, "Continue even if there are pods using emptyDir (local data that will be deleted when the node is drained).")
+
+	return cmd
+}
+
+func runDrain(cfgFile string, args []string) error {
+	drainOpt.machines = args
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.MachineDrain(client.MachineOptions{
+		Kubeconfig:         client.Kubeconfig{Path: drainOpt.kubeconfig, Context: drainOpt.kubeconfigContext},
+		Namespace:          drainOpt.namespace,
+		Machines:           drainOpt.machines,
+		DrainTimeout:       drainOpt.timeout,
+		DeleteEmptyDirData: drainOpt.deleteEmptyDirData,
+	})
+}
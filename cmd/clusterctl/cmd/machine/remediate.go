@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+// remediateOptions is the start of the data required to perform the operation.
+type remediateOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	machines          []string
+	namespace         string
+}
+
+var remediateOpt = &remediateOptions{}
+
+var (
+	remediateLong = templates.LongDesc(`
+		Mark the provided Machine for remediation.
+
+		The Machine gets the same conditions a MachineHealthCheck sets on unhealthy Machines, so it is
+		replaced by the owning MachineSet or KubeadmControlPlane according to their remediation rules.`)
+
+	remediateExample = templates.Examples(`
+		# Replace a control plane machine.
+		clusterctl alpha machine remediate my-cluster-control-plane-8k2hf`)
+)
+
+// NewCmdMachineRemediate returns a Command instance for 'machine remediate' sub command.
+func NewCmdMachineRemediate(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "remediate MACHINE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Mark a Machine for remediation",
+		Long:                  remediateLong,
+		Example:               remediateExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemediate(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&remediateOpt.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&remediateOpt.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVar(&remediateOpt.namespace, "namespace", "", "Namespace where the machine(s) reside. If unspecified, the defult namespace will be used.")
+
+	return cmd
+}
+
+func runRemediate(cfgFile string, args []string) error {
+	remediateOpt.machines = args
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.MachineRemediate(client.MachineOptions{
+		Kubeconfig: client.Kubeconfig{Path: remediateOpt.kubeconfig, Context: remediateOpt.kubeconfigContext},
+		Namespace:  remediateOpt.namespace,
+		Machines:   remediateOpt.machines,
+	})
+}
@@ -17,18 +17,40 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 )
 
+const (
+	// MoveOutputDot is an option used to print the move graph in the Graphviz DOT language.
+	MoveOutputDot = "dot"
+	// MoveOutputJSON is an option used to print the move graph in json format.
+	MoveOutputJSON = "json"
+)
+
+var (
+	// MoveOutputs is a list of valid move graph outputs.
+	MoveOutputs = []string{MoveOutputDot, MoveOutputJSON}
+)
+
 type moveOptions struct {
 	fromKubeconfig        string
 	fromKubeconfigContext string
 	toKubeconfig          string
 	toKubeconfigContext   string
 	namespace             string
+	clusterNames          []string
+	clusterSelector       string
 	dryRun                bool
+	output                string
+	toDirectory           string
+	fromDirectory         string
 }
 
 var mo = &moveOptions{}
@@ -39,11 +61,31 @@ var moveCmd = &cobra.Command{
 	Long: LongDesc(`
 		Move Cluster API objects and all dependencies between management clusters.
 
-		Note: The destination cluster MUST have the required provider components installed.`),
+		Note: The destination cluster MUST have the required provider components installed.
+
+		The move can also be split in two steps, e.g. when the source and the destination management clusters
+		are not reachable at the same time: the objects are first saved to a local directory using --to-directory,
+		leaving the Clusters paused in the source management cluster, and then created in the destination
+		management cluster using --from-directory.`),
 
 	Example: Examples(`
 		Move Cluster API objects and all dependencies between management clusters.
-		clusterctl move --to-kubeconfig=target-kubeconfig.yaml`),
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml
+
+		Move only the Cluster named my-cluster and all its dependencies between management clusters.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --cluster=my-cluster
+
+		Move only the Clusters labeled with env=dev and all their dependencies between management clusters.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --selector=env=dev
+
+		Print the graph of the objects that would be moved in the Graphviz DOT language, without moving anything.
+		clusterctl move --dry-run -o dot | dot -Tsvg > move.svg
+
+		Save Cluster API objects and all dependencies to a local directory, to be moved to another management cluster.
+		clusterctl move --to-directory=/tmp/move-directory
+
+		Create in the destination management cluster the Cluster API objects saved in a local directory.
+		clusterctl move --from-directory=/tmp/move-directory --to-kubeconfig=target-kubeconfig.yaml`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runMove()
@@ -61,16 +103,39 @@ func init() {
 		"Context to be used within the kubeconfig file for the destination management cluster. If empty, current context will be used.")
 	moveCmd.Flags().StringVarP(&mo.namespace, "namespace", "n", "",
 		"The namespace where the workload cluster is hosted. If unspecified, the current context's namespace is used.")
+	moveCmd.Flags().StringSliceVar(&mo.clusterNames, "cluster", nil,
+		"The name of the Clusters to be moved. If unspecified, all the Clusters in the namespace are moved.")
+	moveCmd.Flags().StringVarP(&mo.clusterSelector, "selector", "l", "",
+		"Label selector for the Clusters to be moved. If unspecified, all the Clusters in the namespace are moved.")
 	moveCmd.Flags().BoolVar(&mo.dryRun, "dry-run", false,
 		"Enable dry run, don't really perform the move actions")
+	moveCmd.Flags().StringVarP(&mo.output, "output", "o", "",
+		fmt.Sprintf("Print the graph of the objects that would be moved instead of the dry run logs. Requires --dry-run. Valid values: %v.", MoveOutputs))
+	moveCmd.Flags().StringVar(&mo.toDirectory, "to-directory", "",
+		"Save the objects to be moved to a local directory instead of creating them in the destination management cluster. The directory is created if it does not exist.")
+	moveCmd.Flags().StringVar(&mo.fromDirectory, "from-directory", "",
+		"Create in the destination management cluster the objects saved in a local directory using --to-directory, instead of reading them from the source management cluster.")
 
 	RootCmd.AddCommand(moveCmd)
 }
 
 func runMove() error {
-	// if no to kubeconfig provided and it's not a dry run, return error
-	if mo.toKubeconfig == "" && !mo.dryRun {
-		return errors.New("please specify a target cluster using the --to-kubeconfig flag")
+	if err := validateMoveDirectoryFlags(); err != nil {
+		return err
+	}
+
+	// if no to kubeconfig provided and it's not a dry run or a move involving a directory, return error
+	if mo.toKubeconfig == "" && !mo.dryRun && mo.toDirectory == "" && mo.fromDirectory == "" {
+		return errors.New("please specify a target cluster using the --to-kubeconfig flag, or a directory using the --to-directory flag")
+	}
+
+	if mo.output != "" {
+		if !mo.dryRun {
+			return errors.New("the --output flag can be used only together with the --dry-run flag")
+		}
+		if mo.output != MoveOutputDot && mo.output != MoveOutputJSON {
+			return errors.Errorf("invalid output format %q. Valid values: %v", mo.output, MoveOutputs)
+		}
 	}
 
 	c, err := client.New(cfgFile)
@@ -78,10 +143,62 @@ func runMove() error {
 		return err
 	}
 
-	return c.Move(client.MoveOptions{
-		FromKubeconfig: client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
-		ToKubeconfig:   client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
-		Namespace:      mo.namespace,
-		DryRun:         mo.dryRun,
-	})
+	options := client.MoveOptions{
+		FromKubeconfig:  client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
+		ToKubeconfig:    client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
+		Namespace:       mo.namespace,
+		ClusterNames:    mo.clusterNames,
+		ClusterSelector: mo.clusterSelector,
+		DryRun:          mo.dryRun,
+		ToDirectory:     mo.toDirectory,
+		FromDirectory:   mo.fromDirectory,
+	}
+
+	if mo.output != "" {
+		return printMoveGraph(c, options, os.Stdout)
+	}
+
+	return c.Move(options)
+}
+
+// validateMoveDirectoryFlags checks the --to-directory and --from-directory flags are not used together with
+// incompatible flags.
+func validateMoveDirectoryFlags() error {
+	if mo.toDirectory == "" && mo.fromDirectory == "" {
+		return nil
+	}
+	if mo.toDirectory != "" && mo.fromDirectory != "" {
+		return errors.New("the --to-directory and --from-directory flags cannot be used together")
+	}
+	if mo.dryRun {
+		return errors.New("the --dry-run flag cannot be used together with the --to-directory or --from-directory flags")
+	}
+	if mo.toDirectory != "" && (mo.toKubeconfig != "" || mo.toKubeconfigContext != "") {
+		return errors.New("the --to-kubeconfig and --to-kubeconfig-context flags cannot be used together with the --to-directory flag")
+	}
+	if mo.fromDirectory != "" && (mo.fromKubeconfig != "" || mo.fromKubeconfigContext != "" || mo.namespace != "" || len(mo.clusterNames) > 0 || mo.clusterSelector != "") {
+		return errors.New("the --kubeconfig, --kubeconfig-context, --namespace, --cluster and --selector flags cannot be used together with the --from-directory flag")
+	}
+	return nil
+}
+
+func printMoveGraph(c client.Client, options client.MoveOptions, out io.Writer) error {
+	graph, err := c.GetMoveGraph(options)
+	if err != nil {
+		return err
+	}
+
+	switch mo.output {
+	case MoveOutputDot:
+		_, err = out.Write(graph.DOT())
+		return err
+	case MoveOutputJSON:
+		b, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	return nil
 }
@@ -32,13 +32,16 @@ var ro = &restoreOptions{}
 
 var restoreCmd = &cobra.Command{
 	Use:   "restore",
-	Short: "Restore Cluster API objects from file by glob. Object files are searched in config directory",
+	Short: "Restore Cluster API objects from a backup directory to a management cluster.",
 	Long: LongDesc(`
-		Restore Cluster API objects from file by glob. Object files are searched in the default config directory
-		or in the provided directory.`),
+		Restore Cluster API objects from a directory created by clusterctl backup to a management cluster.
+
+		Objects are created following their ownership chain, so OwnerReferences are rebuilt using the UIDs
+		assigned by the target management cluster; objects already existing in the target management cluster
+		are not modified. Clusters are unpaused once all the objects are restored.`),
 	Example: Examples(`
-		Restore Cluster API objects from file by glob. Object files are searched in config directory.
-		clusterctl restore my-cluster`),
+		Restore Cluster API objects from a backup directory to a management cluster.
+		clusterctl restore --directory=/tmp/backup-directory`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runRestore()
@@ -27,6 +27,7 @@ var (
 		Valid resource types include:
 
 		   * machinedeployment
+		   * kubeadmcontrolplane
 		`)
 
 	rolloutExample = Examples(`
@@ -40,7 +41,13 @@ var (
 		clusterctl alpha rollout resume machinedeployment/my-md-0
 
 		# Rollback a machinedeployment
-		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3`)
+		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3
+
+		# Force an immediate rollout of a kubeadmcontrolplane
+		clusterctl alpha rollout restart kubeadmcontrolplane/my-cluster-control-plane
+
+		# Watch the rollout status of a machinedeployment
+		clusterctl alpha rollout status machinedeployment/my-md-0`)
 
 	rolloutCmd = &cobra.Command{
 		Use:     "rollout SUBCOMMAND",
@@ -56,4 +63,5 @@ func init() {
 	rolloutCmd.AddCommand(rollout.NewCmdRolloutPause(cfgFile))
 	rolloutCmd.AddCommand(rollout.NewCmdRolloutResume(cfgFile))
 	rolloutCmd.AddCommand(rollout.NewCmdRolloutUndo(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutStatus(cfgFile))
 }
@@ -36,7 +36,7 @@ var (
 	resumeLong = templates.LongDesc(`
 		Resume a paused cluster-api resource
 
-	        Paused resources will not be reconciled by a controller. By resuming a resource, we allow it to be reconciled again. Currently only MachineDeployments support being resumed.`)
+	        Paused resources will not be reconciled by a controller. By resuming a resource, we allow it to be reconciled again. Currently only MachineDeployments and KubeadmControlPlanes support being resumed.`)
 
 	resumeExample = templates.Examples(`
 		# Resume an already paused machinedeployment
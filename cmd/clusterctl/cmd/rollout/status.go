@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+// statusOptions is the start of the data required to perform the operation.
+type statusOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	resources         []string
+	namespace         string
+	watch             bool
+	timeout           time.Duration
+}
+
+var statusOpt = &statusOptions{}
+
+var (
+	statusLong = templates.LongDesc(`
+		Show the status of the rollout of cluster-api resources.
+
+		By default 'rollout status' will watch the status of the latest rollout
+		until it's done. If you don't want to wait for the rollout to finish then
+		you can use --watch=false.`)
+
+	statusExample = templates.Examples(`
+		# Watch the rollout status of a machinedeployment
+		clusterctl alpha rollout status machinedeployment/my-md-0
+
+		# Show the rollout status of a kubeadmcontrolplane without waiting for the rollout to finish
+		clusterctl alpha rollout status kubeadmcontrolplane/my-cluster-control-plane --watch=false`)
+)
+
+// NewCmdRolloutStatus returns a Command instance for 'rollout status' sub command.
+func NewCmdRolloutStatus(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "status RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show the status of the rollout of a cluster-api resource",
+		Long:                  statusLong,
+		Example:               statusExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&statusOpt.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&statusOpt.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVar(&statusOpt.namespace, "namespace", "", "Namespace where the resource(s) reside. If unspecified, the defult namespace will be used.")
+	cmd.Flags().BoolVarP(&statusOpt.watch, "watch", "w", true, "Watch the status of the rollout until it's done.")
+	cmd.Flags().DurationVar(&statusOpt.timeout, "timeout", 0, "The length of time to wait before ending watch, zero means never. Any other values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+
+	return cmd
+}
+
+func runStatus(cfgFile string, args []string) error {
+	statusOpt.resources = args
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	options := client.RolloutOptions{
+		Kubeconfig: client.Kubeconfig{Path: statusOpt.kubeconfig, Context: statusOpt.kubeconfigContext},
+		Namespace:  statusOpt.namespace,
+		Resources:  statusOpt.resources,
+	}
+
+	if !statusOpt.watch {
+		statuses, err := c.RolloutStatus(options)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			fmt.Println(s.Message)
+		}
+		return nil
+	}
+
+	// Poll the status of the rollout, printing a message every time it changes, until all the resources are rolled out.
+	lastMessages := map[string]string{}
+	condition := func() (bool, error) {
+		statuses, err := c.RolloutStatus(options)
+		if err != nil {
+			return false, err
+		}
+		done := true
+		for _, s := range statuses {
+			key := fmt.Sprintf("%s/%s", s.Ref.Kind, s.Ref.Name)
+			if lastMessages[key] != s.Message {
+				fmt.Println(s.Message)
+				lastMessages[key] = s.Message
+			}
+			done = done && s.Done
+		}
+		return done, nil
+	}
+
+	if statusOpt.timeout > 0 {
+		err = wait.PollImmediate(2*time.Second, statusOpt.timeout, condition)
+	} else {
+		err = wait.PollImmediateInfinite(2*time.Second, condition)
+	}
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return errors.New("timed out waiting for the rollout to finish")
+	}
+	return err
+}
@@ -38,6 +38,7 @@ type stackTracer interface {
 
 var (
 	cfgFile   string
+	profile   string
 	verbosity *int
 )
 
@@ -111,11 +112,18 @@ func init() {
 	RootCmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "",
 		"Path to clusterctl configuration (default is `$HOME/.cluster-api/clusterctl.yaml`) or to a remote location (i.e. https://example.com/clusterctl.yaml)")
+	RootCmd.PersistentFlags().StringVar(&profile, "profile", "",
+		"Name of the configuration profile defined in the clusterctl configuration to be used. This overrides the CLUSTERCTL_PROFILE environment variable.")
 
 	cobra.OnInitialize(initConfig, registerCompletionFuncForCommonFlags)
 }
 
 func initConfig() {
+	// the --profile flag takes precedence over the CLUSTERCTL_PROFILE env var and over the config file
+	if profile != "" {
+		_ = os.Setenv(config.ProfileKey, profile)
+	}
+
 	// check if the CLUSTERCTL_LOG_LEVEL was set via env var or in the config file
 	if *verbosity == 0 {
 		configClient, err := config.New(cfgFile)
@@ -17,6 +17,8 @@ limitations under the License.
 package cmd
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 
 	"github.com/spf13/cobra"
@@ -31,6 +33,9 @@ type upgradeApplyOptions struct {
 	bootstrapProviders      []string
 	controlPlaneProviders   []string
 	infrastructureProviders []string
+	waitProviders           bool
+	waitProviderTimeout     int
+	continueOnError         bool
 }
 
 var ua = &upgradeApplyOptions{}
@@ -50,7 +55,11 @@ var upgradeApplyCmd = &cobra.Command{
 		clusterctl upgrade apply --contract v1alpha4
 
 		# Upgrades only the capa-system/aws provider to the v0.5.0 version.
-		clusterctl upgrade apply --infrastructure capa-system/aws:v0.5.0`),
+		clusterctl upgrade apply --infrastructure capa-system/aws:v0.5.0
+
+		# Upgrades all the providers one at a time, waiting for each provider to be available before upgrading the next one;
+		# providers failing the upgrade are rolled back to the previous version, and the upgrade continues with the other providers.
+		clusterctl upgrade apply --contract v1alpha4 --wait-providers --continue-on-error`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUpgradeApply()
@@ -73,6 +82,12 @@ func init() {
 		"Bootstrap providers instance and versions (e.g. capi-kubeadm-bootstrap-system/kubeadm:v0.3.0) to upgrade to. This flag can be used as alternative to --contract.")
 	upgradeApplyCmd.Flags().StringSliceVarP(&ua.controlPlaneProviders, "control-plane", "c", nil,
 		"ControlPlane providers instance and versions (e.g. capi-kubeadm-control-plane-system/kubeadm:v0.3.0) to upgrade to. This flag can be used as alternative to --contract.")
+	upgradeApplyCmd.Flags().BoolVar(&ua.waitProviders, "wait-providers", false,
+		"Wait for each provider to be available and for its conversion webhooks to be ready before upgrading the next provider.")
+	upgradeApplyCmd.Flags().IntVar(&ua.waitProviderTimeout, "wait-provider-timeout", 5*60,
+		"Wait timeout per provider upgrade in seconds. This value is ignored if --wait-providers is false")
+	upgradeApplyCmd.Flags().BoolVar(&ua.continueOnError, "continue-on-error", false,
+		"Continue with the upgrade of the other providers if the upgrade of a provider fails. Failed providers are rolled back to the previous version.")
 }
 
 func runUpgradeApply() error {
@@ -97,5 +112,8 @@ func runUpgradeApply() error {
 		BootstrapProviders:      ua.bootstrapProviders,
 		ControlPlaneProviders:   ua.controlPlaneProviders,
 		InfrastructureProviders: ua.infrastructureProviders,
+		WaitProviders:           ua.waitProviders,
+		WaitProviderTimeout:     time.Duration(ua.waitProviderTimeout) * time.Second,
+		ContinueOnError:         ua.continueOnError,
 	})
 }
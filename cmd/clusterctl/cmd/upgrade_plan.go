@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
@@ -45,7 +46,12 @@ var upgradePlanCmd = &cobra.Command{
 
 		Then, for each provider, the following upgrade options are provided:
 		- The latest patch release for the current API Version of Cluster API (contract).
-		- The latest patch release for the next API Version of Cluster API (contract), if available.`),
+		- The latest patch release for the next API Version of Cluster API (contract), if available.
+
+		Additionally, for each workload cluster, the Kubernetes versions of the control plane and of the
+		MachineDeployments are reported, together with the next Kubernetes version the control plane can be
+		upgraded to and the version skew violations, if any; the Kubernetes versions supported by the providers
+		are read from the providers' metadata.`),
 
 	Example: Examples(`
 		# Gets the recommended target versions for upgrading Cluster API providers.
@@ -135,5 +141,58 @@ func runUpgradePlan() error {
 		fmt.Println("")
 	}
 
+	return printWorkloadClustersUpgradePlan(c)
+}
+
+func printWorkloadClustersUpgradePlan(c client.Client) error {
+	plans, err := c.PlanWorkloadClustersUpgrade(client.PlanUpgradeOptions{
+		Kubeconfig: client.Kubeconfig{Path: up.kubeconfig, Context: up.kubeconfigContext},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(plans) == 0 {
+		return nil
+	}
+
+	fmt.Println("Kubernetes versions of the workload clusters:")
+	fmt.Println("")
+	w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tCONTROL PLANE VERSION\tMACHINE DEPLOYMENT VERSIONS\tNEXT VERSION")
+	for _, plan := range plans {
+		mdVersions := make([]string, 0, len(plan.MachineDeployments))
+		for _, md := range plan.MachineDeployments {
+			mdVersions = append(mdVersions, fmt.Sprintf("%s=%s", md.Name, prettifyKubernetesVersion(md.Version)))
+		}
+		nextVersion := plan.NextVersion
+		if nextVersion == "" {
+			nextVersion = "Not available"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", plan.Namespace, plan.Name, prettifyKubernetesVersion(plan.ControlPlaneVersion), strings.Join(mdVersions, ","), nextVersion)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("")
+
+	for _, plan := range plans {
+		if len(plan.Violations) == 0 {
+			continue
+		}
+		fmt.Printf("Version skew violations detected for Cluster %s/%s:\n", plan.Namespace, plan.Name)
+		for _, violation := range plan.Violations {
+			fmt.Printf("- %s\n", violation)
+		}
+		fmt.Println("")
+	}
+
 	return nil
 }
+
+func prettifyKubernetesVersion(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+	return version
+}
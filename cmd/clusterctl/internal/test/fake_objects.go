@@ -46,6 +46,8 @@ type FakeCluster struct {
 	machines              []*FakeMachine
 	withCloudConfigSecret bool
 	withCredentialSecret  bool
+	labels                map[string]string
+	topologyClass         string
 }
 
 // NewFakeCluster return a FakeCluster that can generate a cluster object, all its own ancillary objects:
@@ -96,6 +98,17 @@ func (f *FakeCluster) WithMachines(fakeMachine ...*FakeMachine) *FakeCluster {
 	return f
 }
 
+func (f *FakeCluster) WithLabels(labels map[string]string) *FakeCluster {
+	f.labels = labels
+	return f
+}
+
+// WithTopologyClass sets a managed topology using the ClusterClass with the given name.
+func (f *FakeCluster) WithTopologyClass(class string) *FakeCluster {
+	f.topologyClass = class
+	return f
+}
+
 func (f *FakeCluster) Objs() []client.Object {
 	clusterInfrastructure := &fakeinfrastructure.GenericInfrastructureCluster{
 		TypeMeta: metav1.TypeMeta{
@@ -118,6 +131,7 @@ func (f *FakeCluster) Objs() []client.Object {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      f.name,
 			Namespace: f.namespace,
+			Labels:    f.labels,
 			// Labels: cluster.x-k8s.io/cluster-name=cluster MISSING??
 		},
 		Spec: clusterv1.ClusterSpec{
@@ -129,6 +143,12 @@ func (f *FakeCluster) Objs() []client.Object {
 			},
 		},
 	}
+	if f.topologyClass != "" {
+		cluster.Spec.Topology = &clusterv1.Topology{
+			Class:   f.topologyClass,
+			Version: "v1.22.2",
+		}
+	}
 
 	// Ensure the cluster gets a UID to be used by dependant objects for creating OwnerReferences.
 	setUID(cluster)
@@ -391,18 +411,25 @@ func (f *FakeControlPlane) Objs(cluster *clusterv1.Cluster) []client.Object {
 }
 
 type FakeMachinePool struct {
-	name string
+	name     string
+	machines []*FakeMachine
 }
 
 // NewFakeMachinePool return a FakeMachinePool that can generate a MachinePool object, all its own ancillary objects:
 // - the machinePoolInfrastructure object
-// - the machinePoolBootstrap object.
+// - the machinePoolBootstrap object
+// and all the objects for the defined FakeMachines.
 func NewFakeMachinePool(name string) *FakeMachinePool {
 	return &FakeMachinePool{
 		name: name,
 	}
 }
 
+func (f *FakeMachinePool) WithMachines(fakeMachine ...*FakeMachine) *FakeMachinePool {
+	f.machines = append(f.machines, fakeMachine...)
+	return f
+}
+
 func (f *FakeMachinePool) Objs(cluster *clusterv1.Cluster) []client.Object {
 	machinePoolInfrastructure := &fakeinfrastructure.GenericInfrastructureMachineTemplate{
 		TypeMeta: metav1.TypeMeta{
@@ -496,6 +523,19 @@ func (f *FakeMachinePool) Objs(cluster *clusterv1.Cluster) []client.Object {
 		machinePoolBootstrap,
 	}
 
+	// Adds the objects for the machines representing the instances of the machinePool
+	for _, machine := range f.machines {
+		machineObjs := machine.Objs(cluster, false, nil, nil)
+		for _, o := range machineObjs {
+			if m, ok := o.(*clusterv1.Machine); ok {
+				// The machine is controlled by the machinePool / ownership set by the machinePool controller -- ** NOT RECONCILED **
+				m.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(machinePool, machinePool.GroupVersionKind())})
+				m.Labels[clusterv1.MachinePoolLabelName] = machinePool.Name
+			}
+		}
+		objs = append(objs, machineObjs...)
+	}
+
 	return objs
 }
 
@@ -1142,6 +1182,88 @@ func (f *FakeClusterResourceSet) Objs() []client.Object {
 	return objs
 }
 
+type FakeClusterClass struct {
+	name      string
+	namespace string
+}
+
+// NewFakeClusterClass return a FakeClusterClass that can generate a ClusterClass object and the templates it references:
+// - the infrastructure machine template for the workers
+// - the bootstrap config template for the workers.
+func NewFakeClusterClass(namespace, name string) *FakeClusterClass {
+	return &FakeClusterClass{
+		name:      name,
+		namespace: namespace,
+	}
+}
+
+func (f *FakeClusterClass) Objs() []client.Object {
+	workerInfrastructure := NewFakeInfrastructureTemplate(f.name + "-worker")
+	workerInfrastructure.Namespace = f.namespace
+
+	workerBootstrap := &fakebootstrap.GenericBootstrapConfigTemplate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fakebootstrap.GroupVersion.String(),
+			Kind:       "GenericBootstrapConfigTemplate",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      f.name + "-worker",
+			Namespace: f.namespace,
+		},
+	}
+
+	clusterClass := &clusterv1.ClusterClass{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterClass",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      f.name,
+			Namespace: f.namespace,
+		},
+		Spec: clusterv1.ClusterClassSpec{
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{
+						Class: "worker",
+						Template: clusterv1.MachineDeploymentClassTemplate{
+							Bootstrap: clusterv1.LocalObjectTemplate{
+								Ref: &corev1.ObjectReference{
+									APIVersion: workerBootstrap.APIVersion,
+									Kind:       workerBootstrap.Kind,
+									Name:       workerBootstrap.Name,
+									Namespace:  workerBootstrap.Namespace,
+								},
+							},
+							Infrastructure: clusterv1.LocalObjectTemplate{
+								Ref: &corev1.ObjectReference{
+									APIVersion: workerInfrastructure.APIVersion,
+									Kind:       workerInfrastructure.Kind,
+									Name:       workerInfrastructure.Name,
+									Namespace:  workerInfrastructure.Namespace,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	objs := []client.Object{
+		clusterClass,
+		workerInfrastructure,
+		workerBootstrap,
+	}
+
+	// Ensure all the objects gets UID.
+	for _, o := range objs {
+		setUID(o)
+	}
+
+	return objs
+}
+
 type FakeExternalObject struct {
 	name      string
 	namespace string
@@ -1354,6 +1476,7 @@ func FakeCRDList() []*apiextensionsv1.CustomResourceDefinition {
 
 	return []*apiextensionsv1.CustomResourceDefinition{
 		FakeNamespacedCustomResourceDefinition(clusterv1.GroupVersion.Group, "Cluster", version),
+		FakeNamespacedCustomResourceDefinition(clusterv1.GroupVersion.Group, "ClusterClass", version),
 		FakeNamespacedCustomResourceDefinition(clusterv1.GroupVersion.Group, "Machine", version),
 		FakeNamespacedCustomResourceDefinition(clusterv1.GroupVersion.Group, "MachineDeployment", version),
 		FakeNamespacedCustomResourceDefinition(clusterv1.GroupVersion.Group, "MachineSet", version),
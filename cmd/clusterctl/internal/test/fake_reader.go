@@ -17,6 +17,8 @@ limitations under the License.
 package test
 
 import (
+	"sort"
+
 	"github.com/pkg/errors"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/yaml"
@@ -78,6 +80,22 @@ func (f *FakeReader) UnmarshalKey(key string, rawval interface{}) error {
 	return yaml.Unmarshal([]byte(data), rawval)
 }
 
+func (f *FakeReader) Keys() []string {
+	keys := make([]string, 0, len(f.variables))
+	for key := range f.variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *FakeReader) Source(key string) (string, error) {
+	if _, err := f.Get(key); err != nil {
+		return "", err
+	}
+	return "override", nil
+}
+
 func NewFakeReader() *FakeReader {
 	return &FakeReader{
 		variables:  map[string]string{},
@@ -28,16 +28,20 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/failuredomains"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -61,14 +65,20 @@ const (
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status;clusters/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // ClusterReconciler reconciles a Cluster object.
 type ClusterReconciler struct {
 	Client           client.Client
 	WatchFilterValue string
 
-	recorder        record.EventRecorder
-	externalTracker external.ObjectTracker
+	// ExternalTracker watches external objects referenced by Clusters; if not set, an ObjectTracker
+	// for the controller is created when setting up with the manager.
+	ExternalTracker *external.ObjectTracker
+
+	controller controller.Controller
+	recorder   record.EventRecorder
 }
 
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -78,6 +88,10 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&source.Kind{Type: &clusterv1.Machine{}},
 			handler.EnqueueRequestsFromMapFunc(r.controlPlaneMachineToCluster),
 		).
+		Watches(
+			&source.Kind{Type: &clusterv1.Machine{}},
+			handler.EnqueueRequestsFromMapFunc(r.failureDomainMachineToCluster),
+		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Build(r)
@@ -86,16 +100,18 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	r.controller = controller
 	r.recorder = mgr.GetEventRecorderFor("cluster-controller")
-	r.externalTracker = external.ObjectTracker{
-		Controller: controller,
+	if r.ExternalTracker == nil {
+		r.ExternalTracker, err = external.NewObjectTracker(mgr, external.ObjectTrackerOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create external object tracker")
+		}
 	}
 	return nil
 }
 
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	// Fetch the Cluster instance.
 	cluster := &clusterv1.Cluster{}
 	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
@@ -109,9 +125,15 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		return ctrl.Result{}, err
 	}
 
+	ctx, log := logutil.Into(ctx, logutil.ClusterKey, logutil.KObj(cluster))
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, cluster) {
 		log.Info("Reconciliation is paused for this object")
+		// Propagate the paused state to the descendants, so providers relying only on the paused annotation stop reconciling too.
+		if cluster.Spec.Paused {
+			return ctrl.Result{}, r.reconcilePaused(ctx, cluster)
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -168,6 +190,9 @@ func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clust
 			clusterv1.ReadyCondition,
 			clusterv1.ControlPlaneReadyCondition,
 			clusterv1.InfrastructureReadyCondition,
+			clusterv1.WorkersDeletedCondition,
+			clusterv1.ControlPlaneDeletedCondition,
+			clusterv1.InfrastructureDeletedCondition,
 		}},
 	)
 	return patchHelper.Patch(ctx, cluster, options...)
@@ -175,7 +200,7 @@ func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clust
 
 // reconcile handles cluster reconciliation.
 func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx)
 
 	if cluster.Spec.Topology != nil {
 		if cluster.Spec.ControlPlaneRef == nil || cluster.Spec.InfrastructureRef == nil {
@@ -190,6 +215,7 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cl
 		r.reconcileControlPlane,
 		r.reconcileKubeconfig,
 		r.reconcileControlPlaneInitialized,
+		r.reconcileMachinesPerFailureDomain,
 	}
 
 	res := ctrl.Result{}
@@ -209,6 +235,9 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cl
 }
 
 // reconcileDelete handles cluster deletion.
+// The Cluster is deleted in phases: first the workers, then the control plane and finally the infrastructure.
+// Each phase starts once the previous one has completed or has exceeded its timeout in spec.deletionTimeouts;
+// the finalizer is removed only after all the phases have completed.
 func (r *ClusterReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -218,108 +247,210 @@ func (r *ClusterReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 		return reconcile.Result{}, err
 	}
 
-	children, err := descendants.filterOwnedDescendants(cluster)
+	timeouts := clusterv1.ClusterDeletionTimeouts{}
+	if cluster.Spec.DeletionTimeouts != nil {
+		timeouts = *cluster.Spec.DeletionTimeouts
+	}
+
+	phases := []struct {
+		condition clusterv1.ConditionType
+		timeout   *metav1.Duration
+		reconcile func(context.Context, *clusterv1.Cluster, clusterDescendants) (bool, error)
+	}{
+		{condition: clusterv1.WorkersDeletedCondition, timeout: timeouts.Workers, reconcile: r.reconcileDeleteWorkers},
+		{condition: clusterv1.ControlPlaneDeletedCondition, timeout: timeouts.ControlPlane, reconcile: r.reconcileDeleteControlPlane},
+		{condition: clusterv1.InfrastructureDeletedCondition, timeout: timeouts.Infrastructure, reconcile: r.reconcileDeleteInfrastructure},
+	}
+
+	deleted := true
+	for _, phase := range phases {
+		phaseDeleted, err := phase.reconcile(ctx, cluster, descendants)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if phaseDeleted {
+			conditions.MarkTrue(cluster, phase.condition)
+			continue
+		}
+
+		deleted = false
+		if !deletionPhaseTimedOut(cluster, phase.condition, phase.timeout) {
+			// Requeue so we can check the next time to see if the phase has completed.
+			return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+		}
+		log.Info("Deletion phase timed out, moving on to the next phase", "condition", phase.condition)
+	}
+
+	if !deleted {
+		// Some of the phases timed out; requeue so we can check the next time to see if they have completed.
+		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+	}
+
+	r.ExternalTracker.Release(log, r.controller, cluster)
+	controllerutil.RemoveFinalizer(cluster, clusterv1.ClusterFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// reconcileDeleteWorkers deletes the MachinePools, MachineDeployments, MachineSets and worker Machines owned by the
+// cluster, and returns true once all the worker descendants of the cluster are gone.
+func (r *ClusterReconciler) reconcileDeleteWorkers(ctx context.Context, cluster *clusterv1.Cluster, descendants clusterDescendants) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	workerCount := descendants.workersLength()
+	if workerCount == 0 {
+		return true, nil
+	}
+
+	children, err := descendants.filterOwnedWorkers(cluster)
 	if err != nil {
-		log.Error(err, "Failed to extract direct descendants")
-		return reconcile.Result{}, err
+		log.Error(err, "Failed to extract direct worker descendants")
+		return false, err
 	}
 
-	if len(children) > 0 {
-		log.Info("Cluster still has children - deleting them first", "count", len(children))
+	if err := r.deleteChildren(ctx, cluster, children); err != nil {
+		return false, err
+	}
 
-		var errs []error
+	indirect := workerCount - len(children)
+	log.Info("Cluster still has worker descendants - need to requeue", "descendants", descendants.descendantNames(), "indirect descendants count", indirect)
+	return false, nil
+}
 
-		for _, child := range children {
-			if !child.GetDeletionTimestamp().IsZero() {
-				// Don't handle deleted child
-				continue
-			}
-			gvk := child.GetObjectKind().GroupVersionKind().String()
+// reconcileDeleteControlPlane deletes the control plane Machines owned by the cluster, if there is no control plane
+// provider, and the control plane object; it returns true once the control plane of the cluster is gone.
+func (r *ClusterReconciler) reconcileDeleteControlPlane(ctx context.Context, cluster *clusterv1.Cluster, descendants clusterDescendants) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
 
-			log.Info("Deleting child object", "gvk", gvk, "name", child.GetName())
-			if err := r.Client.Delete(ctx, child); err != nil {
-				err = errors.Wrapf(err, "error deleting cluster %s/%s: failed to delete %s %s", cluster.Namespace, cluster.Name, gvk, child.GetName())
-				log.Error(err, "Error deleting resource", "gvk", gvk, "name", child.GetName())
-				errs = append(errs, err)
-			}
+	if len(descendants.controlPlaneMachines.Items) > 0 {
+		children, err := filterOwnedBy(cluster, &descendants.controlPlaneMachines)
+		if err != nil {
+			log.Error(err, "Failed to extract direct control plane descendants")
+			return false, err
 		}
 
-		if len(errs) > 0 {
-			return ctrl.Result{}, kerrors.NewAggregate(errs)
+		if err := r.deleteChildren(ctx, cluster, children); err != nil {
+			return false, err
 		}
+
+		log.Info("Cluster still has control plane machines - need to requeue", "descendants", descendants.descendantNames())
+		return false, nil
 	}
 
-	if descendantCount := descendants.length(); descendantCount > 0 {
-		indirect := descendantCount - len(children)
-		log.Info("Cluster still has descendants - need to requeue", "descendants", descendants.descendantNames(), "indirect descendants count", indirect)
-		// Requeue so we can check the next time to see if there are still any descendants left.
-		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+	if cluster.Spec.ControlPlaneRef == nil {
+		return true, nil
 	}
 
-	if cluster.Spec.ControlPlaneRef != nil {
-		obj, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
-		switch {
-		case apierrors.IsNotFound(errors.Cause(err)):
-			// All good - the control plane resource has been deleted
-			conditions.MarkFalse(cluster, clusterv1.ControlPlaneReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
-		case err != nil:
-			return reconcile.Result{}, errors.Wrapf(err, "failed to get %s %q for Cluster %s/%s",
-				path.Join(cluster.Spec.ControlPlaneRef.APIVersion, cluster.Spec.ControlPlaneRef.Kind),
-				cluster.Spec.ControlPlaneRef.Name, cluster.Namespace, cluster.Name)
-		default:
-			// Report a summary of current status of the control plane object defined for this cluster.
-			conditions.SetMirror(cluster, clusterv1.ControlPlaneReadyCondition,
-				conditions.UnstructuredGetter(obj),
-				conditions.WithFallbackValue(false, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, ""),
-			)
-
-			// Issue a deletion request for the control plane object.
-			// Once it's been deleted, the cluster will get processed again.
-			if err := r.Client.Delete(ctx, obj); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err,
-					"failed to delete %v %q for Cluster %q in namespace %q",
-					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
-			}
-
-			// Return here so we don't remove the finalizer yet.
-			log.Info("Cluster still has descendants - need to requeue", "controlPlaneRef", cluster.Spec.ControlPlaneRef.Name)
-			return ctrl.Result{}, nil
-		}
+	obj, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	switch {
+	case apierrors.IsNotFound(errors.Cause(err)):
+		// All good - the control plane resource has been deleted
+		conditions.MarkFalse(cluster, clusterv1.ControlPlaneReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
+		return true, nil
+	case err != nil:
+		return false, errors.Wrapf(err, "failed to get %s %q for Cluster %s/%s",
+			path.Join(cluster.Spec.ControlPlaneRef.APIVersion, cluster.Spec.ControlPlaneRef.Kind),
+			cluster.Spec.ControlPlaneRef.Name, cluster.Namespace, cluster.Name)
 	}
 
-	if cluster.Spec.InfrastructureRef != nil {
-		obj, err := external.Get(ctx, r.Client, cluster.Spec.InfrastructureRef, cluster.Namespace)
-		switch {
-		case apierrors.IsNotFound(errors.Cause(err)):
-			// All good - the infra resource has been deleted
-			conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
-		case err != nil:
-			return ctrl.Result{}, errors.Wrapf(err, "failed to get %s %q for Cluster %s/%s",
-				path.Join(cluster.Spec.InfrastructureRef.APIVersion, cluster.Spec.InfrastructureRef.Kind),
-				cluster.Spec.InfrastructureRef.Name, cluster.Namespace, cluster.Name)
-		default:
-			// Report a summary of current status of the infrastructure object defined for this cluster.
-			conditions.SetMirror(cluster, clusterv1.InfrastructureReadyCondition,
-				conditions.UnstructuredGetter(obj),
-				conditions.WithFallbackValue(false, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, ""),
-			)
-
-			// Issue a deletion request for the infrastructure object.
-			// Once it's been deleted, the cluster will get processed again.
-			if err := r.Client.Delete(ctx, obj); err != nil {
-				return ctrl.Result{}, errors.Wrapf(err,
-					"failed to delete %v %q for Cluster %q in namespace %q",
-					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
-			}
-
-			// Return here so we don't remove the finalizer yet.
-			log.Info("Cluster still has descendants - need to requeue", "infrastructureRef", cluster.Spec.InfrastructureRef.Name)
-			return ctrl.Result{}, nil
+	// Report a summary of current status of the control plane object defined for this cluster.
+	conditions.SetMirror(cluster, clusterv1.ControlPlaneReadyCondition,
+		conditions.UnstructuredGetter(obj),
+		conditions.WithFallbackValue(false, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, ""),
+	)
+
+	// Issue a deletion request for the control plane object.
+	// Once it's been deleted, the cluster will get processed again.
+	if err := r.Client.Delete(ctx, obj); err != nil {
+		return false, errors.Wrapf(err,
+			"failed to delete %v %q for Cluster %q in namespace %q",
+			obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
+	}
+
+	log.Info("Cluster still has descendants - need to requeue", "controlPlaneRef", cluster.Spec.ControlPlaneRef.Name)
+	return false, nil
+}
+
+// reconcileDeleteInfrastructure deletes the infrastructure object of the cluster, and returns true once it is gone.
+func (r *ClusterReconciler) reconcileDeleteInfrastructure(ctx context.Context, cluster *clusterv1.Cluster, _ clusterDescendants) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if cluster.Spec.InfrastructureRef == nil {
+		return true, nil
+	}
+
+	obj, err := external.Get(ctx, r.Client, cluster.Spec.InfrastructureRef, cluster.Namespace)
+	switch {
+	case apierrors.IsNotFound(errors.Cause(err)):
+		// All good - the infra resource has been deleted
+		conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
+		return true, nil
+	case err != nil:
+		return false, errors.Wrapf(err, "failed to get %s %q for Cluster %s/%s",
+			path.Join(cluster.Spec.InfrastructureRef.APIVersion, cluster.Spec.InfrastructureRef.Kind),
+			cluster.Spec.InfrastructureRef.Name, cluster.Namespace, cluster.Name)
+	}
+
+	// Report a summary of current status of the infrastructure object defined for this cluster.
+	conditions.SetMirror(cluster, clusterv1.InfrastructureReadyCondition,
+		conditions.UnstructuredGetter(obj),
+		conditions.WithFallbackValue(false, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, ""),
+	)
+
+	// Issue a deletion request for the infrastructure object.
+	// Once it's been deleted, the cluster will get processed again.
+	if err := r.Client.Delete(ctx, obj); err != nil {
+		return false, errors.Wrapf(err,
+			"failed to delete %v %q for Cluster %q in namespace %q",
+			obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
+	}
+
+	log.Info("Cluster still has descendants - need to requeue", "infrastructureRef", cluster.Spec.InfrastructureRef.Name)
+	return false, nil
+}
+
+// deleteChildren issues a deletion request for the given children of the cluster which are not being deleted yet.
+func (r *ClusterReconciler) deleteChildren(ctx context.Context, cluster *clusterv1.Cluster, children []client.Object) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	var errs []error
+	for _, child := range children {
+		if !child.GetDeletionTimestamp().IsZero() {
+			// Don't handle deleted child
+			continue
+		}
+		gvk := child.GetObjectKind().GroupVersionKind().String()
+
+		log.Info("Deleting child object", "gvk", gvk, "name", child.GetName())
+		if err := r.Client.Delete(ctx, child); err != nil {
+			err = errors.Wrapf(err, "error deleting cluster %s/%s: failed to delete %s %s", cluster.Namespace, cluster.Name, gvk, child.GetName())
+			log.Error(err, "Error deleting resource", "gvk", gvk, "name", child.GetName())
+			errs = append(errs, err)
 		}
 	}
+	return kerrors.NewAggregate(errs)
+}
 
-	controllerutil.RemoveFinalizer(cluster, clusterv1.ClusterFinalizer)
-	return ctrl.Result{}, nil
+// deletionPhaseTimedOut marks the condition of a Cluster deletion phase as in progress, and returns true if the phase
+// has been in progress for longer than timeout; in this case the condition is marked as timed out.
+func deletionPhaseTimedOut(cluster *clusterv1.Cluster, condition clusterv1.ConditionType, timeout *metav1.Duration) bool {
+	if conditions.GetReason(cluster, condition) == clusterv1.DeletionTimedOutReason {
+		return true
+	}
+
+	// NOTE: The last transition time of the condition is preserved as long as the phase is in progress,
+	// so it can be used to track when the phase started.
+	conditions.MarkFalse(cluster, condition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+	if timeout == nil || timeout.Duration <= 0 {
+		return false
+	}
+
+	if time.Since(conditions.GetLastTransitionTime(cluster, condition).Time) < timeout.Duration {
+		return false
+	}
+
+	conditions.MarkFalse(cluster, condition, clusterv1.DeletionTimedOutReason, clusterv1.ConditionSeverityWarning,
+		"Deletion did not complete within %s", timeout.Duration)
+	return true
 }
 
 type clusterDescendants struct {
@@ -339,6 +470,11 @@ func (c *clusterDescendants) length() int {
 		len(c.machinePools.Items)
 }
 
+// workersLength returns the number of descendants which are not control plane machines.
+func (c *clusterDescendants) workersLength() int {
+	return c.length() - len(c.controlPlaneMachines.Items)
+}
+
 func (c *clusterDescendants) descendantNames() string {
 	descendants := make([]string, 0)
 	controlPlaneMachineNames := make([]string, len(c.controlPlaneMachines.Items))
@@ -385,26 +521,32 @@ func (c *clusterDescendants) descendantNames() string {
 func (r *ClusterReconciler) listDescendants(ctx context.Context, cluster *clusterv1.Cluster) (clusterDescendants, error) {
 	var descendants clusterDescendants
 
-	listOptions := []client.ListOption{
+	// NOTE: MachineDeployments, MachineSets and Machines are listed using the cluster name index, so objects
+	// belonging to the cluster are found even if their cluster name label has been removed.
+	indexedListOptions := []client.ListOption{
 		client.InNamespace(cluster.Namespace),
-		client.MatchingLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name}),
+		client.MatchingFields{index.ClusterNameField: cluster.Name},
 	}
 
-	if err := r.Client.List(ctx, &descendants.machineDeployments, listOptions...); err != nil {
+	if err := r.Client.List(ctx, &descendants.machineDeployments, indexedListOptions...); err != nil {
 		return descendants, errors.Wrapf(err, "failed to list MachineDeployments for cluster %s/%s", cluster.Namespace, cluster.Name)
 	}
 
-	if err := r.Client.List(ctx, &descendants.machineSets, listOptions...); err != nil {
+	if err := r.Client.List(ctx, &descendants.machineSets, indexedListOptions...); err != nil {
 		return descendants, errors.Wrapf(err, "failed to list MachineSets for cluster %s/%s", cluster.Namespace, cluster.Name)
 	}
 
 	if feature.Gates.Enabled(feature.MachinePool) {
+		listOptions := []client.ListOption{
+			client.InNamespace(cluster.Namespace),
+			client.MatchingLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name}),
+		}
 		if err := r.Client.List(ctx, &descendants.machinePools, listOptions...); err != nil {
 			return descendants, errors.Wrapf(err, "failed to list MachinePools for the cluster %s/%s", cluster.Namespace, cluster.Name)
 		}
 	}
 	var machines clusterv1.MachineList
-	if err := r.Client.List(ctx, &machines, listOptions...); err != nil {
+	if err := r.Client.List(ctx, &machines, indexedListOptions...); err != nil {
 		return descendants, errors.Wrapf(err, "failed to list Machines for cluster %s/%s", cluster.Namespace, cluster.Name)
 	}
 
@@ -424,6 +566,31 @@ func (r *ClusterReconciler) listDescendants(ctx context.Context, cluster *cluste
 // filterOwnedDescendants returns an array of runtime.Objects containing only those descendants that have the cluster
 // as an owner reference, with control plane machines sorted last.
 func (c clusterDescendants) filterOwnedDescendants(cluster *clusterv1.Cluster) ([]client.Object, error) {
+	return filterOwnedBy(cluster, append(c.workerLists(), &c.controlPlaneMachines)...)
+}
+
+// filterOwnedWorkers returns an array of runtime.Objects containing only those descendants that have the cluster
+// as an owner reference, excluding control plane machines.
+func (c clusterDescendants) filterOwnedWorkers(cluster *clusterv1.Cluster) ([]client.Object, error) {
+	return filterOwnedBy(cluster, c.workerLists()...)
+}
+
+// workerLists returns the lists of descendants which are not control plane machines.
+func (c *clusterDescendants) workerLists() []client.ObjectList {
+	lists := []client.ObjectList{
+		&c.machineDeployments,
+		&c.machineSets,
+		&c.workerMachines,
+	}
+	if feature.Gates.Enabled(feature.MachinePool) {
+		lists = append([]client.ObjectList{&c.machinePools}, lists...)
+	}
+	return lists
+}
+
+// filterOwnedBy returns an array of runtime.Objects containing only the items of lists that have the cluster
+// as an owner reference.
+func filterOwnedBy(cluster *clusterv1.Cluster, lists ...client.ObjectList) ([]client.Object, error) {
 	var ownedDescendants []client.Object
 	eachFunc := func(o runtime.Object) error {
 		obj := o.(client.Object)
@@ -439,16 +606,6 @@ func (c clusterDescendants) filterOwnedDescendants(cluster *clusterv1.Cluster) (
 		return nil
 	}
 
-	lists := []client.ObjectList{
-		&c.machineDeployments,
-		&c.machineSets,
-		&c.workerMachines,
-		&c.controlPlaneMachines,
-	}
-	if feature.Gates.Enabled(feature.MachinePool) {
-		lists = append([]client.ObjectList{&c.machinePools}, lists...)
-	}
-
 	for _, list := range lists {
 		if err := meta.EachListItem(list, eachFunc); err != nil {
 			return nil, errors.Wrapf(err, "error finding owned descendants of cluster %s/%s", cluster.Namespace, cluster.Name)
@@ -493,6 +650,44 @@ func (r *ClusterReconciler) reconcileControlPlaneInitialized(ctx context.Context
 	return ctrl.Result{}, nil
 }
 
+// reconcileMachinesPerFailureDomain counts the Machines in each of the failure domains of the Cluster
+// and surfaces the counts in the Cluster status.
+func (r *ClusterReconciler) reconcileMachinesPerFailureDomain(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	if len(cluster.Status.FailureDomains) == 0 {
+		cluster.Status.MachinesPerFailureDomain = nil
+		return ctrl.Result{}, nil
+	}
+
+	machines, err := collections.GetFilteredMachinesForCluster(ctx, r.Client, cluster, collections.ActiveMachines)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list Machines for Cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	counts := failuredomains.CountMachines(machines.UnsortedList())
+	cluster.Status.MachinesPerFailureDomain = make(map[string]int32, len(cluster.Status.FailureDomains))
+	for id := range cluster.Status.FailureDomains {
+		cluster.Status.MachinesPerFailureDomain[id] = int32(counts[id])
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// failureDomainMachineToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update its status.machinesPerFailureDomain field.
+func (r *ClusterReconciler) failureDomainMachineToCluster(o client.Object) []ctrl.Request {
+	m, ok := o.(*clusterv1.Machine)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Machine but got a %T", o))
+	}
+	if m.Spec.FailureDomain == nil || m.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: client.ObjectKey{Namespace: m.Namespace, Name: m.Spec.ClusterName},
+	}}
+}
+
 // controlPlaneMachineToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
 // for Cluster to update its status.controlPlaneInitialized field.
 func (r *ClusterReconciler) controlPlaneMachineToCluster(o client.Object) []ctrl.Request {
@@ -24,13 +24,16 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -79,18 +82,21 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 		return external.ReconcileOutput{}, err
 	}
 
-	// if external ref is paused, return error.
-	if annotations.IsPaused(cluster, obj) {
-		log.V(3).Info("External object referenced is paused")
-		return external.ReconcileOutput{Paused: true}, nil
-	}
-
 	// Initialize the patch helper.
 	patchHelper, err := patch.NewHelper(obj, r.Client)
 	if err != nil {
 		return external.ReconcileOutput{}, err
 	}
 
+	// Remove the paused annotation propagated from the Cluster, if any, given that the Cluster is not paused anymore.
+	annotations.RemovePausedFromCluster(obj)
+
+	// if external ref is paused, return error.
+	if annotations.IsPaused(cluster, obj) {
+		log.V(3).Info("External object referenced is paused")
+		return external.ReconcileOutput{Paused: true}, nil
+	}
+
 	// Set external object ControllerReference to the Cluster.
 	if err := controllerutil.SetControllerReference(cluster, obj, r.Client.Scheme()); err != nil {
 		return external.ReconcileOutput{}, err
@@ -110,7 +116,7 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 	}
 
 	// Ensure we add a watcher to the external object.
-	if err := r.externalTracker.Watch(log, obj, &handler.EnqueueRequestForOwner{OwnerType: &clusterv1.Cluster{}}); err != nil {
+	if err := r.ExternalTracker.Watch(log, r.controller, cluster, obj, &handler.EnqueueRequestForOwner{OwnerType: &clusterv1.Cluster{}}); err != nil {
 		return external.ReconcileOutput{}, err
 	}
 
@@ -133,6 +139,53 @@ func (r *ClusterReconciler) reconcileExternal(ctx context.Context, cluster *clus
 	return external.ReconcileOutput{Result: obj}, nil
 }
 
+// reconcilePaused propagates the paused annotation to the objects referenced by a paused Cluster and by its Machines.
+func (r *ClusterReconciler) reconcilePaused(ctx context.Context, cluster *clusterv1.Cluster) error {
+	refs := []*corev1.ObjectReference{}
+	if cluster.Spec.InfrastructureRef != nil {
+		refs = append(refs, cluster.Spec.InfrastructureRef)
+	}
+	if cluster.Spec.ControlPlaneRef != nil {
+		refs = append(refs, cluster.Spec.ControlPlaneRef)
+	}
+
+	machines, err := collections.GetFilteredMachinesForCluster(ctx, r.Client, cluster)
+	if err != nil {
+		return err
+	}
+	for _, m := range machines {
+		refs = append(refs, &m.Spec.InfrastructureRef)
+		if m.Spec.Bootstrap.ConfigRef != nil {
+			refs = append(refs, m.Spec.Bootstrap.ConfigRef)
+		}
+	}
+
+	errs := []error{}
+	for _, ref := range refs {
+		obj, err := external.Get(ctx, r.Client, ref, cluster.Namespace)
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				continue
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(obj, r.Client)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !annotations.ReconcilePausedFromCluster(cluster, obj) {
+			continue
+		}
+		if err := patchHelper.Patch(ctx, obj); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to propagate the paused annotation to %s %q", obj.GetKind(), obj.GetName()))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
 // reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a Cluster.
 func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -168,11 +221,21 @@ func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster
 	}
 	cluster.Status.InfrastructureReady = ready
 
-	// Report a summary of current status of the infrastructure object defined for this cluster.
-	conditions.SetMirror(cluster, clusterv1.InfrastructureReadyCondition,
-		conditions.UnstructuredGetter(infraConfig),
-		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
-	)
+	if annotations.IsExternallyManaged(infraConfig) {
+		// Externally managed infrastructure is not reconciled by the infrastructure provider, so there are no
+		// provider conditions to wait for; readiness is the one reported by the external management system.
+		if ready {
+			conditions.MarkTrue(cluster, clusterv1.InfrastructureReadyCondition)
+		} else {
+			conditions.MarkFalse(cluster, clusterv1.InfrastructureReadyCondition, clusterv1.WaitingForExternallyManagedInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		}
+	} else {
+		// Report a summary of current status of the infrastructure object defined for this cluster.
+		conditions.SetMirror(cluster, clusterv1.InfrastructureReadyCondition,
+			conditions.UnstructuredGetter(infraConfig),
+			conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
+		)
+	}
 
 	if !ready {
 		log.V(3).Info("Infrastructure provider is not ready yet")
@@ -181,16 +244,24 @@ func (r *ClusterReconciler) reconcileInfrastructure(ctx context.Context, cluster
 
 	// Get and parse Spec.ControlPlaneEndpoint field from the infrastructure provider.
 	if !cluster.Spec.ControlPlaneEndpoint.IsValid() {
-		if err := util.UnstructuredUnmarshalField(infraConfig, &cluster.Spec.ControlPlaneEndpoint, "spec", "controlPlaneEndpoint"); err != nil {
+		endpoint, err := contract.InfrastructureCluster().ControlPlaneEndpoint().Get(infraConfig)
+		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve Spec.ControlPlaneEndpoint from infrastructure provider for Cluster %q in namespace %q",
 				cluster.Name, cluster.Namespace)
 		}
+		cluster.Spec.ControlPlaneEndpoint = *endpoint
 	}
 
 	// Get and parse Status.FailureDomains from the infrastructure provider.
-	if err := util.UnstructuredUnmarshalField(infraConfig, &cluster.Status.FailureDomains, "status", "failureDomains"); err != nil && err != util.ErrUnstructuredFieldNotFound {
+	failureDomains, err := contract.InfrastructureCluster().FailureDomains().Get(infraConfig)
+	switch {
+	case contract.IsNotFound(err):
+		// Failure domains are optional.
+	case err != nil:
 		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve Status.FailureDomains from infrastructure provider for Cluster %q in namespace %q",
 			cluster.Name, cluster.Namespace)
+	default:
+		cluster.Status.FailureDomains = failureDomains
 	}
 
 	return ctrl.Result{}, nil
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -233,6 +234,88 @@ func TestClusterReconcilePhases(t *testing.T) {
 	})
 }
 
+func TestClusterReconcileExternallyManagedInfrastructure(t *testing.T) {
+	tests := []struct {
+		name         string
+		ready        bool
+		expectStatus corev1.ConditionStatus
+		expectReason string
+	}{
+		{
+			name:         "waits for the external management system to mark the infrastructure ready",
+			ready:        false,
+			expectStatus: corev1.ConditionFalse,
+			expectReason: clusterv1.WaitingForExternallyManagedInfrastructureReason,
+		},
+		{
+			name:         "uses the readiness reported by the external management system",
+			ready:        true,
+			expectStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{
+						Host: "1.2.3.4",
+						Port: 8443,
+					},
+					InfrastructureRef: &corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "GenericInfrastructureMachine",
+						Name:       "test",
+					},
+				},
+			}
+			// The Ready condition is left over from a time the infrastructure was managed by the provider,
+			// and it must be ignored.
+			infraConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "test",
+					"namespace": "test-namespace",
+					"annotations": map[string]interface{}{
+						clusterv1.ManagedByAnnotation: "external-system",
+					},
+				},
+				"status": map[string]interface{}{
+					"ready": tt.ready,
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":   string(clusterv1.ReadyCondition),
+							"status": string(corev1.ConditionFalse),
+							"reason": "Provisioning",
+						},
+					},
+				},
+			}}
+
+			r := &ClusterReconciler{
+				Client: fake.NewClientBuilder().
+					WithObjects(testtypes.GenericInfrastructureMachineCRD.DeepCopy(), cluster, infraConfig).
+					Build(),
+			}
+
+			_, err := r.reconcileInfrastructure(ctx, cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cluster.Status.InfrastructureReady).To(Equal(tt.ready))
+			c := conditions.Get(cluster, clusterv1.InfrastructureReadyCondition)
+			g.Expect(c).NotTo(BeNil())
+			g.Expect(c.Status).To(Equal(tt.expectStatus))
+			g.Expect(c.Reason).To(Equal(tt.expectReason))
+		})
+	}
+}
+
 func TestClusterReconciler_reconcilePhase(t *testing.T) {
 	cluster := &clusterv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
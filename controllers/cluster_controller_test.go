@@ -18,10 +18,12 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -754,3 +756,259 @@ func TestReconcileControlPlaneInitializedControlPlaneRef(t *testing.T) {
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(conditions.Has(c, clusterv1.ControlPlaneInitializedCondition)).To(BeFalse())
 }
+
+func TestReconcileMachinesPerFailureDomain(t *testing.T) {
+	newMachine := func(name string, failureDomain *string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: "c"},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName:   "c",
+				FailureDomain: failureDomain,
+			},
+		}
+	}
+	deletingMachine := newMachine("deleting", pointer.StringPtr("a"))
+	deletingMachine.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+	deletingMachine.Finalizers = []string{clusterv1.MachineFinalizer}
+
+	tests := []struct {
+		name           string
+		failureDomains clusterv1.FailureDomains
+		machines       []client.Object
+		want           map[string]int32
+	}{
+		{
+			name:     "no failure domains",
+			machines: []client.Object{newMachine("m1", pointer.StringPtr("a"))},
+			want:     nil,
+		},
+		{
+			name: "counts the active Machines in each failure domain",
+			failureDomains: clusterv1.FailureDomains{
+				"a": clusterv1.FailureDomainSpec{},
+				"b": clusterv1.FailureDomainSpec{},
+				"c": clusterv1.FailureDomainSpec{},
+			},
+			machines: []client.Object{
+				newMachine("m1", pointer.StringPtr("a")),
+				newMachine("m2", pointer.StringPtr("a")),
+				newMachine("m3", pointer.StringPtr("b")),
+				newMachine("m4", pointer.StringPtr("unknown")),
+				newMachine("m5", nil),
+				deletingMachine,
+			},
+			want: map[string]int32{"a": 2, "b": 1, "c": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "c",
+					Namespace: "test",
+				},
+				Status: clusterv1.ClusterStatus{
+					FailureDomains: tt.failureDomains,
+				},
+			}
+
+			r := &ClusterReconciler{
+				Client: fake.NewClientBuilder().WithObjects(tt.machines...).Build(),
+			}
+			res, err := r.reconcileMachinesPerFailureDomain(ctx, c)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(res.IsZero()).To(BeTrue())
+			g.Expect(c.Status.MachinesPerFailureDomain).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestFailureDomainMachineToCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &ClusterReconciler{}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "m", Namespace: "test"},
+		Spec:       clusterv1.MachineSpec{ClusterName: "c"},
+	}
+	g.Expect(r.failureDomainMachineToCluster(machine)).To(BeEmpty())
+
+	machine.Spec.FailureDomain = pointer.StringPtr("a")
+	g.Expect(r.failureDomainMachineToCluster(machine)).To(ConsistOf(ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "test", Name: "c"},
+	}))
+}
+
+func TestClusterReconcilerReconcileDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "GenericControlPlane",
+			"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "cp",
+				"namespace": "test",
+			},
+		},
+	}
+	infraCluster := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "GenericInfrastructureCluster",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "infra",
+				"namespace": "test",
+			},
+		},
+	}
+	c := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "c",
+			Namespace:         "test",
+			DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+			Finalizers:        []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: controlPlane.GetAPIVersion(),
+				Kind:       controlPlane.GetKind(),
+				Name:       controlPlane.GetName(),
+				Namespace:  controlPlane.GetNamespace(),
+			},
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: infraCluster.GetAPIVersion(),
+				Kind:       infraCluster.GetKind(),
+				Name:       infraCluster.GetName(),
+				Namespace:  infraCluster.GetNamespace(),
+			},
+		},
+	}
+	md := newMachineDeploymentBuilder().named("md").ownedBy(c).build()
+	md.Namespace = c.Namespace
+	md.Labels = map[string]string{clusterv1.ClusterLabelName: c.Name}
+
+	r := &ClusterReconciler{
+		Client: fake.NewClientBuilder().WithObjects(&md, controlPlane.DeepCopy(), infraCluster.DeepCopy()).Build(),
+	}
+
+	exists := func(obj client.Object) bool {
+		err := r.Client.Get(ctx, util.ObjectKey(obj), obj)
+		g.Expect(client.IgnoreNotFound(err)).To(Succeed())
+		return err == nil
+	}
+
+	// The workers are deleted first.
+	res, err := r.reconcileDelete(ctx, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(exists(&clusterv1.MachineDeployment{ObjectMeta: md.ObjectMeta})).To(BeFalse())
+	g.Expect(exists(controlPlane.DeepCopy())).To(BeTrue())
+	g.Expect(conditions.GetReason(c, clusterv1.WorkersDeletedCondition)).To(Equal(clusterv1.DeletingReason))
+	g.Expect(conditions.Has(c, clusterv1.ControlPlaneDeletedCondition)).To(BeFalse())
+
+	// Then the control plane.
+	res, err = r.reconcileDelete(ctx, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(conditions.IsTrue(c, clusterv1.WorkersDeletedCondition)).To(BeTrue())
+	g.Expect(exists(controlPlane.DeepCopy())).To(BeFalse())
+	g.Expect(exists(infraCluster.DeepCopy())).To(BeTrue())
+	g.Expect(conditions.GetReason(c, clusterv1.ControlPlaneDeletedCondition)).To(Equal(clusterv1.DeletingReason))
+	g.Expect(conditions.Has(c, clusterv1.InfrastructureDeletedCondition)).To(BeFalse())
+
+	// Then the infrastructure.
+	res, err = r.reconcileDelete(ctx, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(deleteRequeueAfter))
+	g.Expect(conditions.IsTrue(c, clusterv1.ControlPlaneDeletedCondition)).To(BeTrue())
+	g.Expect(exists(infraCluster.DeepCopy())).To(BeFalse())
+	g.Expect(conditions.GetReason(c, clusterv1.InfrastructureDeletedCondition)).To(Equal(clusterv1.DeletingReason))
+	g.Expect(c.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+
+	// Finally the finalizer is removed.
+	res, err = r.reconcileDelete(ctx, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.IsZero()).To(BeTrue())
+	g.Expect(conditions.IsTrue(c, clusterv1.InfrastructureDeletedCondition)).To(BeTrue())
+	g.Expect(c.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+}
+
+func TestDeletionPhaseTimedOut(t *testing.T) {
+	tests := []struct {
+		name         string
+		condition    *clusterv1.Condition
+		timeout      *metav1.Duration
+		want         bool
+		expectReason string
+	}{
+		{
+			name:         "phase starting without timeout",
+			want:         false,
+			expectReason: clusterv1.DeletingReason,
+		},
+		{
+			name:         "phase in progress without timeout",
+			condition:    deletionPhaseCondition(clusterv1.DeletingReason, time.Hour),
+			want:         false,
+			expectReason: clusterv1.DeletingReason,
+		},
+		{
+			name:         "phase in progress within timeout",
+			condition:    deletionPhaseCondition(clusterv1.DeletingReason, time.Minute),
+			timeout:      &metav1.Duration{Duration: 10 * time.Minute},
+			want:         false,
+			expectReason: clusterv1.DeletingReason,
+		},
+		{
+			name:         "phase in progress exceeding timeout",
+			condition:    deletionPhaseCondition(clusterv1.DeletingReason, time.Hour),
+			timeout:      &metav1.Duration{Duration: 10 * time.Minute},
+			want:         true,
+			expectReason: clusterv1.DeletionTimedOutReason,
+		},
+		{
+			name:         "phase already timed out",
+			condition:    deletionPhaseCondition(clusterv1.DeletionTimedOutReason, time.Minute),
+			timeout:      &metav1.Duration{Duration: 10 * time.Minute},
+			want:         true,
+			expectReason: clusterv1.DeletionTimedOutReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &clusterv1.Cluster{}
+			if tt.condition != nil {
+				c.Status.Conditions = clusterv1.Conditions{*tt.condition}
+			}
+
+			g.Expect(deletionPhaseTimedOut(c, clusterv1.WorkersDeletedCondition, tt.timeout)).To(Equal(tt.want))
+			g.Expect(conditions.GetReason(c, clusterv1.WorkersDeletedCondition)).To(Equal(tt.expectReason))
+		})
+	}
+}
+
+func deletionPhaseCondition(reason string, age time.Duration) *clusterv1.Condition {
+	return &clusterv1.Condition{
+		Type:               clusterv1.WorkersDeletedCondition,
+		Status:             corev1.ConditionFalse,
+		Severity:           clusterv1.ConditionSeverityInfo,
+		Reason:             reason,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-age)),
+	}
+}
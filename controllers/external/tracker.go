@@ -17,50 +17,226 @@ limitations under the License.
 package external
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-// ObjectTracker is a helper struct to deal when watching external unstructured objects.
+// ObjectTrackerOptions are the options for the ObjectTracker.
+type ObjectTrackerOptions struct {
+	// Namespace restricts the informers to the given namespace.
+	// If empty, external objects are watched in all namespaces.
+	Namespace string
+
+	// PartialMetadata configures the informers to only cache the metadata of the external objects,
+	// thus reducing memory usage; this is enough as long as controllers read external objects using
+	// a client and rely on the informers only for triggering reconciliation.
+	PartialMetadata bool
+
+	// ResyncPeriod is the resync period of the informers; defaults to no resync.
+	ResyncPeriod time.Duration
+}
+
+// ObjectTracker watches external objects, like infrastructure or bootstrap objects referenced by
+// Cluster API objects, on behalf of one or more controllers.
+//
+// The ObjectTracker runs a single informer for each kind of external object, shared across all the
+// controllers watching it, and keeps track of the objects referencing external objects of each kind,
+// so the informer is stopped when there are no referencing objects left.
 type ObjectTracker struct {
-	m sync.Map
+	lock      sync.Mutex
+	informers map[schema.GroupKind]*trackedInformer
+
+	// ctx is the context informers are started with; it is cancelled when the manager stops.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// newInformer returns a new informer for the given kind of external object.
+	newInformer func(gvk schema.GroupVersionKind) (cache.SharedIndexInformer, error)
+}
+
+// trackedInformer is an informer for a kind of external object.
+type trackedInformer struct {
+	informer cache.SharedIndexInformer
+	cancel   context.CancelFunc
+
+	// controllers is the set of controllers watching the informer.
+	controllers map[controller.Controller]struct{}
+
+	// referrers is the set of objects referencing an external object of this kind, for each controller.
+	referrers map[controller.Controller]sets.String
+}
 
-	Controller controller.Controller
+var _ manager.Runnable = &ObjectTracker{}
+
+// NewObjectTracker returns an ObjectTracker for the given manager; the ObjectTracker is added
+// to the manager, so all the informers are stopped when the manager stops.
+func NewObjectTracker(mgr ctrl.Manager, options ObjectTrackerOptions) (*ObjectTracker, error) {
+	var newInformer func(gvr schema.GroupVersionResource) cache.SharedIndexInformer
+	if options.PartialMetadata {
+		metadataClient, err := metadata.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create metadata client")
+		}
+		newInformer = func(gvr schema.GroupVersionResource) cache.SharedIndexInformer {
+			return metadatainformer.NewFilteredMetadataInformer(metadataClient, gvr, options.Namespace, options.ResyncPeriod, cache.Indexers{}, nil).Informer()
+		}
+	} else {
+		dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create dynamic client")
+		}
+		newInformer = func(gvr schema.GroupVersionResource) cache.SharedIndexInformer {
+			return dynamicinformer.NewFilteredDynamicInformer(dynamicClient, gvr, options.Namespace, options.ResyncPeriod, cache.Indexers{}, nil).Informer()
+		}
+	}
+
+	mapper := mgr.GetRESTMapper()
+	t := newObjectTracker(func(gvk schema.GroupVersionKind) (cache.SharedIndexInformer, error) {
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get REST mapping for %q", gvk.String())
+		}
+		return newInformer(mapping.Resource), nil
+	})
+
+	if err := mgr.Add(t); err != nil {
+		return nil, errors.Wrap(err, "failed to add the external object tracker to the manager")
+	}
+	return t, nil
 }
 
-// Watch uses the controller to issue a Watch only if the object hasn't been seen before.
-func (o *ObjectTracker) Watch(log logr.Logger, obj runtime.Object, handler handler.EventHandler) error {
-	// Consider this a no-op if the controller isn't present.
-	if o.Controller == nil {
+func newObjectTracker(newInformer func(gvk schema.GroupVersionKind) (cache.SharedIndexInformer, error)) *ObjectTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ObjectTracker{
+		informers:   map[schema.GroupKind]*trackedInformer{},
+		ctx:         ctx,
+		cancel:      cancel,
+		newInformer: newInformer,
+	}
+}
+
+// Start implements manager.Runnable; it blocks until the context is done, then it stops all the informers.
+func (t *ObjectTracker) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.cancel()
+	t.informers = map[schema.GroupKind]*trackedInformer{}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; the ObjectTracker must be stopped
+// with the manager no matter if the manager is the leader.
+func (t *ObjectTracker) NeedLeaderElection() bool {
+	return false
+}
+
+// Watch ensures the given controller is watching external objects of the same kind as obj, starting an informer
+// for this kind if there isn't one already; referrer is the object referencing obj, and it keeps the informer
+// running until it is released by the same controller.
+// Watch is a no-op if the ObjectTracker or the controller are nil, e.g. in unit tests.
+func (t *ObjectTracker) Watch(log logr.Logger, c controller.Controller, referrer client.Object, obj runtime.Object, h handler.EventHandler) error {
+	if t == nil || c == nil {
 		return nil
 	}
 
 	gvk := obj.GetObjectKind().GroupVersionKind()
-	key := gvk.GroupKind().String()
-	if _, loaded := o.m.LoadOrStore(key, struct{}{}); loaded {
-		return nil
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	i, ok := t.informers[gvk.GroupKind()]
+	if !ok {
+		informer, err := t.newInformer(gvk)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create informer for external object %q", gvk.String())
+		}
+
+		log.Info("Starting informer on external objects", "GroupKind", gvk.GroupKind().String())
+		ctx, cancel := context.WithCancel(t.ctx)
+		go informer.Run(ctx.Done())
+
+		i = &trackedInformer{
+			informer:    informer,
+			cancel:      cancel,
+			controllers: map[controller.Controller]struct{}{},
+			referrers:   map[controller.Controller]sets.String{},
+		}
+		t.informers[gvk.GroupKind()] = i
+	}
+	if _, ok := i.referrers[c]; !ok {
+		i.referrers[c] = sets.NewString()
 	}
+	i.referrers[c].Insert(referrerKey(referrer))
 
-	u := &unstructured.Unstructured{}
-	u.SetGroupVersionKind(gvk)
+	if _, ok := i.controllers[c]; ok {
+		return nil
+	}
 
 	log.Info("Adding watcher on external object", "GroupVersionKind", gvk.String())
-	err := o.Controller.Watch(
-		&source.Kind{Type: u},
-		handler,
-		predicates.ResourceNotPaused(log),
-	)
-	if err != nil {
-		o.m.Delete(key)
+	if err := c.Watch(&source.Informer{Informer: i.informer}, h, predicates.ResourceNotPaused(log)); err != nil {
 		return errors.Wrapf(err, "failed to add watcher on external object %q", gvk.String())
 	}
+	i.controllers[c] = struct{}{}
 	return nil
 }
+
+// Release drops the references to external objects of the given object, as tracked for the given controller;
+// informers for kinds of external objects that are no longer referenced are stopped.
+// NOTE: controllers watching a stopped informer keep the corresponding event handler, which never fires again;
+// if the kind is referenced again, a new informer is started and controllers are set up to watch it.
+func (t *ObjectTracker) Release(log logr.Logger, c controller.Controller, referrer client.Object) {
+	if t == nil || c == nil {
+		return
+	}
+
+	key := referrerKey(referrer)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for gk, i := range t.informers {
+		referrers, ok := i.referrers[c]
+		if !ok || !referrers.Has(key) {
+			continue
+		}
+		referrers.Delete(key)
+		if referrers.Len() == 0 {
+			delete(i.referrers, c)
+		}
+		if len(i.referrers) > 0 {
+			continue
+		}
+
+		log.Info("Stopping informer on external objects that are no longer referenced", "GroupKind", gk.String())
+		i.cancel()
+		delete(t.informers, gk)
+	}
+}
+
+// referrerKey returns the key identifying an object referencing external objects; the Go type is used
+// because the GroupVersionKind is not set on typed objects read from the cache.
+func referrerKey(referrer client.Object) string {
+	return fmt.Sprintf("%T %s/%s", referrer, referrer.GetNamespace(), referrer.GetName())
+}
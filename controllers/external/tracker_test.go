@@ -18,10 +18,13 @@ package external
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -61,36 +64,152 @@ func (c *watchCountController) Watch(_ source.Source, _ handler.EventHandler, _
 	return nil
 }
 
+type fakeInformer struct {
+	cache.SharedIndexInformer
+	stopped chan struct{}
+}
+
+func (i *fakeInformer) Run(stopCh <-chan struct{}) {
+	<-stopCh
+	close(i.stopped)
+}
+
+// fakeInformerFactory keeps track of the informers created by an ObjectTracker.
+type fakeInformerFactory struct {
+	informers []*fakeInformer
+}
+
+func (f *fakeInformerFactory) newInformer(_ schema.GroupVersionKind) (cache.SharedIndexInformer, error) {
+	i := &fakeInformer{stopped: make(chan struct{})}
+	f.informers = append(f.informers, i)
+	return i, nil
+}
+
+func newCluster(name string) *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      name,
+		},
+	}
+}
+
 func TestRetryWatch(t *testing.T) {
 	g := NewWithT(t)
 	ctrl := newWatchCountController(true)
-	tracker := ObjectTracker{Controller: ctrl}
+	factory := &fakeInformerFactory{}
+	tracker := newObjectTracker(factory.newInformer)
+	defer tracker.cancel()
 
-	err := tracker.Watch(logger, &clusterv1.Cluster{}, nil)
+	err := tracker.Watch(logger, ctrl, newCluster("referrer"), &clusterv1.Cluster{}, nil)
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(ctrl.count).Should(Equal(1))
 	// Calling Watch on same Object kind that failed earlier should be retryable.
-	err = tracker.Watch(logger, &clusterv1.Cluster{}, nil)
+	err = tracker.Watch(logger, ctrl, newCluster("referrer"), &clusterv1.Cluster{}, nil)
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(ctrl.count).Should(Equal(2))
+	// The informer should be created only once.
+	g.Expect(factory.informers).To(HaveLen(1))
 }
 
 func TestWatchMultipleTimes(t *testing.T) {
 	g := NewWithT(t)
 	ctrl := &watchCountController{}
-	tracker := ObjectTracker{Controller: ctrl}
+	factory := &fakeInformerFactory{}
+	tracker := newObjectTracker(factory.newInformer)
+	defer tracker.cancel()
 
-	obj := &clusterv1.Cluster{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Cluster",
-			APIVersion: clusterv1.GroupVersion.Version,
-		},
-	}
-	err := tracker.Watch(logger, obj, nil)
+	obj := newCluster("external")
+	err := tracker.Watch(logger, ctrl, newCluster("referrer"), obj, nil)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(ctrl.count).Should(Equal(1))
 	// Calling Watch on same Object kind should not register watch again.
-	err = tracker.Watch(logger, obj, nil)
+	err = tracker.Watch(logger, ctrl, newCluster("referrer"), obj, nil)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(ctrl.count).Should(Equal(1))
+	g.Expect(factory.informers).To(HaveLen(1))
+}
+
+func TestWatchMultipleControllers(t *testing.T) {
+	g := NewWithT(t)
+	ctrl1 := &watchCountController{}
+	ctrl2 := &watchCountController{}
+	factory := &fakeInformerFactory{}
+	tracker := newObjectTracker(factory.newInformer)
+	defer tracker.cancel()
+
+	obj := newCluster("external")
+	g.Expect(tracker.Watch(logger, ctrl1, newCluster("referrer1"), obj, nil)).To(Succeed())
+	g.Expect(tracker.Watch(logger, ctrl2, newCluster("referrer2"), obj, nil)).To(Succeed())
+
+	// Each controller should watch the informer, which should be shared across controllers.
+	g.Expect(ctrl1.count).Should(Equal(1))
+	g.Expect(ctrl2.count).Should(Equal(1))
+	g.Expect(factory.informers).To(HaveLen(1))
+}
+
+func TestRelease(t *testing.T) {
+	g := NewWithT(t)
+	ctrl := &watchCountController{}
+	factory := &fakeInformerFactory{}
+	tracker := newObjectTracker(factory.newInformer)
+	defer tracker.cancel()
+
+	obj := newCluster("external")
+	referrer1 := newCluster("referrer1")
+	referrer2 := newCluster("referrer2")
+	g.Expect(tracker.Watch(logger, ctrl, referrer1, obj, nil)).To(Succeed())
+	g.Expect(tracker.Watch(logger, ctrl, referrer2, obj, nil)).To(Succeed())
+	g.Expect(factory.informers).To(HaveLen(1))
+
+	// The informer should keep running as long as there are objects referencing the kind.
+	tracker.Release(logger, ctrl, referrer1)
+	g.Expect(tracker.informers).To(HaveLen(1))
+	g.Consistently(factory.informers[0].stopped, 100*time.Millisecond).ShouldNot(BeClosed())
+
+	// The informer should be stopped when the last referencing object is released.
+	tracker.Release(logger, ctrl, referrer2)
+	g.Expect(tracker.informers).To(BeEmpty())
+	g.Eventually(factory.informers[0].stopped).Should(BeClosed())
+
+	// Watching the kind again should start a new informer and watch it.
+	g.Expect(tracker.Watch(logger, ctrl, referrer1, obj, nil)).To(Succeed())
+	g.Expect(factory.informers).To(HaveLen(2))
+	g.Expect(ctrl.count).Should(Equal(2))
+}
+
+func TestWatchNilTracker(t *testing.T) {
+	g := NewWithT(t)
+	ctrl := &watchCountController{}
+
+	var tracker *ObjectTracker
+	g.Expect(tracker.Watch(logger, ctrl, newCluster("referrer"), newCluster("external"), nil)).To(Succeed())
+	g.Expect(ctrl.count).Should(Equal(0))
+	tracker.Release(logger, ctrl, newCluster("referrer"))
+}
+
+func TestReleaseMultipleControllers(t *testing.T) {
+	g := NewWithT(t)
+	ctrl1 := &watchCountController{}
+	ctrl2 := &watchCountController{}
+	factory := &fakeInformerFactory{}
+	tracker := newObjectTracker(factory.newInformer)
+	defer tracker.cancel()
+
+	obj := newCluster("external")
+	referrer := newCluster("referrer")
+	g.Expect(tracker.Watch(logger, ctrl1, referrer, obj, nil)).To(Succeed())
+	g.Expect(tracker.Watch(logger, ctrl2, referrer, obj, nil)).To(Succeed())
+
+	// The informer should keep running as long as the object is referenced for any of the controllers.
+	tracker.Release(logger, ctrl1, referrer)
+	g.Expect(tracker.informers).To(HaveLen(1))
+
+	tracker.Release(logger, ctrl2, referrer)
+	g.Expect(tracker.informers).To(BeEmpty())
+	g.Eventually(factory.informers[0].stopped).Should(BeClosed())
 }
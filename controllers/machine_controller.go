@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -42,8 +43,10 @@ import (
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/requeue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -56,6 +59,15 @@ import (
 const (
 	// MachineControllerName defines the controller used when creating clients.
 	MachineControllerName = "machine-controller"
+
+	// drainRetryInterval is how long to wait before trying to drain a Node again after a failure.
+	drainRetryInterval = 20 * time.Second
+
+	// maxDrainRetryInterval is the maximum interval between attempts to drain a Node that is persistently failing.
+	maxDrainRetryInterval = 5 * time.Minute
+
+	// volumeDetachRetryInterval is how long to wait before checking again if the volumes of a drained Node are detached.
+	volumeDetachRetryInterval = 20 * time.Second
 )
 
 var (
@@ -79,9 +91,20 @@ type MachineReconciler struct {
 	Tracker          *remote.ClusterCacheTracker
 	WatchFilterValue string
 
-	controller      controller.Controller
-	recorder        record.EventRecorder
-	externalTracker external.ObjectTracker
+	// ExternalTracker watches external objects referenced by Machines; if not set, an ObjectTracker
+	// for the controller is created when setting up with the manager.
+	ExternalTracker *external.ObjectTracker
+
+	// LifecycleHookTimeout is the maximum amount of time the deletion of a Machine waits for the pre-drain.delete
+	// and pre-terminate.delete lifecycle hooks to be removed; when zero, the deletion waits indefinitely.
+	LifecycleHookTimeout time.Duration
+
+	controller controller.Controller
+	recorder   record.EventRecorder
+
+	// drainBackoff tracks consecutive drain failures, so Machines with a Node that cannot be drained
+	// are requeued with an increasing interval.
+	drainBackoff requeue.Backoff
 }
 
 func (r *MachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -112,15 +135,16 @@ func (r *MachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 	r.controller = controller
 
 	r.recorder = mgr.GetEventRecorderFor("machine-controller")
-	r.externalTracker = external.ObjectTracker{
-		Controller: controller,
+	if r.ExternalTracker == nil {
+		r.ExternalTracker, err = external.NewObjectTracker(mgr, external.ObjectTrackerOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create external object tracker")
+		}
 	}
 	return nil
 }
 
 func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	// Fetch the Machine instance
 	m := &clusterv1.Machine{}
 	if err := r.Client.Get(ctx, req.NamespacedName, m); err != nil {
@@ -134,6 +158,8 @@ func (r *MachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		return ctrl.Result{}, err
 	}
 
+	ctx, log := logutil.IntoWithCluster(ctx, m.Namespace, m.Spec.ClusterName, logutil.MachineKey, logutil.KObj(m))
+
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.ObjectMeta.Namespace, m.Spec.ClusterName)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to get cluster %q for machine %q in namespace %q",
@@ -269,7 +295,7 @@ func (r *MachineReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cl
 }
 
 func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx)
 
 	err := r.isDeleteNodeAllowed(ctx, cluster, m)
 	isDeleteNodeAllowed := err == nil //nolint:ifshort
@@ -284,12 +310,10 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 
 	if isDeleteNodeAllowed {
 		// pre-drain.delete lifecycle hook
-		// Return early without error, will requeue if/when the hook owner removes the annotation.
-		if annotations.HasWithPrefix(clusterv1.PreDrainDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
-			conditions.MarkFalse(m, clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
-			return ctrl.Result{}, nil
+		// Return early without error, will requeue if/when the hook owner removes the annotation or the hook times out.
+		if shouldWait, requeueAfter := r.waitForDeleteHooks(m, "pre-drain.delete", clusterv1.PreDrainDeleteHookAnnotationPrefix, clusterv1.PreDrainDeleteHookSucceededCondition); shouldWait {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 		}
-		conditions.MarkTrue(m, clusterv1.PreDrainDeleteHookSucceededCondition)
 
 		// Drain node before deletion and issue a patch in order to make this operation visible to the users.
 		if r.isNodeDrainAllowed(m) {
@@ -310,7 +334,7 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 				return ctrl.Result{}, errors.Wrap(err, "failed to patch Machine")
 			}
 
-			if result, err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name); !result.IsZero() || err != nil {
+			if result, err := r.drainNode(ctx, cluster, m); !result.IsZero() || err != nil {
 				if err != nil {
 					conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
@@ -321,30 +345,39 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 			conditions.MarkTrue(m, clusterv1.DrainingSucceededCondition)
 			r.recorder.Eventf(m, corev1.EventTypeNormal, "SuccessfulDrainNode", "success draining Machine's node %q", m.Status.NodeRef.Name)
 
-			// After node draining, make sure volumes are detached before deleting the Node.
-			if conditions.Get(m, clusterv1.VolumeDetachSucceededCondition) == nil {
-				conditions.MarkFalse(m, clusterv1.VolumeDetachSucceededCondition, clusterv1.WaitingForVolumeDetachReason, clusterv1.ConditionSeverityInfo, "Waiting for node volumes to be detached")
-			}
-			if ok, err := r.shouldWaitForNodeVolumes(ctx, cluster, m.Status.NodeRef.Name, m.Name); ok || err != nil {
+			// After node draining, make sure volumes are detached before deleting the Node, unless the Machine
+			// opted out of waiting for it or the NodeVolumeDetachTimeout already expired.
+			if !m.Spec.SkipWaitForNodeVolumeDetach && conditions.GetReason(m, clusterv1.VolumeDetachSucceededCondition) != clusterv1.VolumeDetachTimedOutReason {
+				if conditions.Get(m, clusterv1.VolumeDetachSucceededCondition) == nil {
+					conditions.MarkFalse(m, clusterv1.VolumeDetachSucceededCondition, clusterv1.WaitingForVolumeDetachReason, clusterv1.ConditionSeverityInfo, "Waiting for node volumes to be detached")
+				}
+				ok, err := r.shouldWaitForNodeVolumes(ctx, cluster, m.Status.NodeRef.Name)
 				if err != nil {
 					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedWaitForVolumeDetach", "error wait for volume detach, node %q: %v", m.Status.NodeRef.Name, err)
 					return ctrl.Result{}, err
 				}
-				log.Info("Waiting for node volumes to be detached", "node", m.Status.NodeRef.Name)
-				return ctrl.Result{}, nil
+				switch {
+				case !ok:
+					conditions.MarkTrue(m, clusterv1.VolumeDetachSucceededCondition)
+					r.recorder.Eventf(m, corev1.EventTypeNormal, "NodeVolumesDetached", "success waiting for node volumes detach Machine's node %q", m.Status.NodeRef.Name)
+				case r.nodeVolumeDetachTimeoutExceeded(m):
+					log.Info("Timed out waiting for node volumes to be detached, moving on", "node", m.Status.NodeRef.Name)
+					conditions.MarkFalse(m, clusterv1.VolumeDetachSucceededCondition, clusterv1.VolumeDetachTimedOutReason, clusterv1.ConditionSeverityWarning, "Timed out waiting for node volumes to be detached")
+					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedWaitForVolumeDetach", "timed out waiting for node volumes detach Machine's node %q", m.Status.NodeRef.Name)
+				default:
+					// VolumeAttachments are not watched, so requeue to check them again.
+					log.Info("Waiting for node volumes to be detached", "node", m.Status.NodeRef.Name)
+					return ctrl.Result{RequeueAfter: volumeDetachRetryInterval}, nil
+				}
 			}
-			conditions.MarkTrue(m, clusterv1.VolumeDetachSucceededCondition)
-			r.recorder.Eventf(m, corev1.EventTypeNormal, "NodeVolumesDetached", "success waiting for node volumes detach Machine's node %q", m.Status.NodeRef.Name)
 		}
 	}
 
 	// pre-term.delete lifecycle hook
-	// Return early without error, will requeue if/when the hook owner removes the annotation.
-	if annotations.HasWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
-		conditions.MarkFalse(m, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
-		return ctrl.Result{}, nil
+	// Return early without error, will requeue if/when the hook owner removes the annotation or the hook times out.
+	if shouldWait, requeueAfter := r.waitForDeleteHooks(m, "pre-terminate.delete", clusterv1.PreTerminateDeleteHookAnnotationPrefix, clusterv1.PreTerminateDeleteHookSucceededCondition); shouldWait {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
-	conditions.MarkTrue(m, clusterv1.PreTerminateDeleteHookSucceededCondition)
 
 	// Return early and don't remove the finalizer if we got an error or
 	// the external reconciliation deletion isn't ready.
@@ -386,10 +419,44 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 		}
 	}
 
+	r.drainBackoff.Forget(m)
+	r.ExternalTracker.Release(log, r.controller, m)
 	controllerutil.RemoveFinalizer(m, clusterv1.MachineFinalizer)
 	return ctrl.Result{}, nil
 }
 
+// waitForDeleteHooks returns true if the deletion of the Machine has to wait for the lifecycle hooks with the
+// given annotation prefix to be removed, and reports the state of the hooks in the given condition.
+// If a LifecycleHookTimeout is set, the deletion stops waiting once the timeout expires, and the returned
+// duration is the time left before it does.
+func (r *MachineReconciler) waitForDeleteHooks(m *clusterv1.Machine, hookName, annotationPrefix string, hookCondition clusterv1.ConditionType) (bool, time.Duration) {
+	if !annotations.HasWithPrefix(annotationPrefix, m.ObjectMeta.Annotations) {
+		conditions.MarkTrue(m, hookCondition)
+		return false, 0
+	}
+
+	// The hooks already timed out, do not wait any longer.
+	if conditions.GetReason(m, hookCondition) == clusterv1.ExternalHookTimedOutReason {
+		return false, 0
+	}
+
+	// NOTE: The message must not change while waiting, so the transition time of the condition
+	// records when the controller started waiting for the hooks.
+	conditions.MarkFalse(m, hookCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "Waiting for %s hooks to be removed", hookName)
+	if r.LifecycleHookTimeout <= 0 {
+		return true, 0
+	}
+
+	waiting := time.Since(conditions.GetLastTransitionTime(m, hookCondition).Time)
+	if waiting < r.LifecycleHookTimeout {
+		return true, r.LifecycleHookTimeout - waiting
+	}
+
+	conditions.MarkFalse(m, hookCondition, clusterv1.ExternalHookTimedOutReason, clusterv1.ConditionSeverityWarning, "Timed out after %s waiting for %s hooks to be removed", r.LifecycleHookTimeout, hookName)
+	r.recorder.Eventf(m, corev1.EventTypeWarning, "LifecycleHookTimedOut", "Timed out after %s waiting for %s hooks to be removed", r.LifecycleHookTimeout, hookName)
+	return false, 0
+}
+
 func (r *MachineReconciler) isNodeDrainAllowed(m *clusterv1.Machine) bool {
 	if _, exists := m.ObjectMeta.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; exists {
 		return false
@@ -419,10 +486,27 @@ func (r *MachineReconciler) nodeDrainTimeoutExceeded(machine *clusterv1.Machine)
 	return diff.Seconds() >= machine.Spec.NodeDrainTimeout.Seconds()
 }
 
+func (r *MachineReconciler) nodeVolumeDetachTimeoutExceeded(machine *clusterv1.Machine) bool {
+	// if the NodeVolumeDetachTimeout is not set by user
+	if machine.Spec.NodeVolumeDetachTimeout == nil || machine.Spec.NodeVolumeDetachTimeout.Seconds() <= 0 {
+		return false
+	}
+
+	// if the volume detach succeeded condition does not exist
+	if conditions.Get(machine, clusterv1.VolumeDetachSucceededCondition) == nil {
+		return false
+	}
+
+	now := time.Now()
+	firstTimeDetach := conditions.GetLastTransitionTime(machine, clusterv1.VolumeDetachSucceededCondition)
+	diff := now.Sub(firstTimeDetach.Time)
+	return diff.Seconds() >= machine.Spec.NodeVolumeDetachTimeout.Seconds()
+}
+
 // isDeleteNodeAllowed returns nil only if the Machine's NodeRef is not nil
 // and if the Machine is not the last control plane node in the cluster.
 func (r *MachineReconciler) isDeleteNodeAllowed(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) error {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx)
 	// Return early if the cluster is being deleted.
 	if !cluster.DeletionTimestamp.IsZero() {
 		return errClusterIsBeingDeleted
@@ -481,8 +565,9 @@ func (r *MachineReconciler) isDeleteNodeAllowed(ctx context.Context, cluster *cl
 	return nil
 }
 
-func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name, "node", nodeName)
+func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
+	nodeName := m.Status.NodeRef.Name
+	log := ctrl.LoggerFrom(ctx, logutil.NodeKey, logutil.KRef("", nodeName))
 
 	restConfig, err := remote.RESTConfig(ctx, MachineControllerName, r.Client, util.ObjectKey(cluster))
 	if err != nil {
@@ -527,6 +612,18 @@ func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cl
 		DryRun: false,
 	}
 
+	if m.Spec.NodeDrainGracePeriod != nil {
+		drainer.GracePeriodSeconds = int(m.Spec.NodeDrainGracePeriod.Seconds())
+	}
+
+	if m.Spec.NodeDrainExcludedPodSelector != nil {
+		excludedPodSelector, err := metav1.LabelSelectorAsSelector(m.Spec.NodeDrainExcludedPodSelector)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "invalid nodeDrainExcludedPodSelector for Machine %q", m.Name)
+		}
+		drainer.ExcludedPodSelector = excludedPodSelector
+	}
+
 	if noderefutil.IsNodeUnreachable(node) {
 		// When the node is unreachable and some pods are not evicted for as long as this timeout, we ignore them.
 		drainer.SkipWaitForDeleteTimeoutSeconds = 60 * 5 // 5 minutes
@@ -539,22 +636,24 @@ func (r *MachineReconciler) drainNode(ctx context.Context, cluster *clusterv1.Cl
 	}
 
 	if err := kubedrain.RunNodeDrain(ctx, drainer, node.Name); err != nil {
-		// Machine will be re-reconciled after a drain failure.
-		log.Error(err, "Drain failed, retry in 20s")
-		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+		// Machine will be re-reconciled after a drain failure, backing off in case of persistent failures.
+		requeueAfter := r.drainBackoff.After(m, drainRetryInterval, maxDrainRetryInterval)
+		log.Error(err, "Drain failed, retrying", "after", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
+	r.drainBackoff.Forget(m)
 	log.Info("Drain successful")
 	return ctrl.Result{}, nil
 }
 
-// shouldWaitForNodeVolumes returns true if node status still have volumes attached
+// shouldWaitForNodeVolumes returns true if node status still have volumes attached, or if VolumeAttachments still reference the node;
 // pod deletion and volume detach happen asynchronously, so pod could be deleted before volume detached from the node
 // this could cause issue for some storage provisioner, for example, vsphere-volume this is problematic
 // because if the node is deleted before detach success, then the underline VMDK will be deleted together with the Machine
 // so after node draining we need to check if all volumes are detached before deleting the node.
-func (r *MachineReconciler) shouldWaitForNodeVolumes(ctx context.Context, cluster *clusterv1.Cluster, nodeName string, machineName string) (bool, error) {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name, "node", nodeName, "machine", machineName)
+func (r *MachineReconciler) shouldWaitForNodeVolumes(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) (bool, error) {
+	log := ctrl.LoggerFrom(ctx, logutil.NodeKey, logutil.KRef("", nodeName))
 
 	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
 	if err != nil {
@@ -570,11 +669,27 @@ func (r *MachineReconciler) shouldWaitForNodeVolumes(ctx context.Context, cluste
 		return true, err
 	}
 
-	return len(node.Status.VolumesAttached) != 0, nil
+	if len(node.Status.VolumesAttached) != 0 {
+		return true, nil
+	}
+
+	// Volumes attached by CSI drivers are tracked by VolumeAttachments, which are only removed once the
+	// volume has actually been detached from the node.
+	volumeAttachments := &storagev1.VolumeAttachmentList{}
+	if err := remoteClient.List(ctx, volumeAttachments); err != nil {
+		return true, errors.Wrap(err, "failed to list VolumeAttachments")
+	}
+	for _, volumeAttachment := range volumeAttachments.Items {
+		if volumeAttachment.Spec.NodeName == nodeName {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 func (r *MachineReconciler) deleteNode(ctx context.Context, cluster *clusterv1.Cluster, name string) error {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx)
 
 	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
 	if err != nil {
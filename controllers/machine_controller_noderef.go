@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/nodemetadata"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,8 +41,7 @@ var (
 )
 
 func (r *MachineReconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx, "machine", machine.Name, "namespace", machine.Namespace)
-	log = log.WithValues("cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx)
 
 	// Check that the Machine has a valid ProviderID.
 	if machine.Spec.ProviderID == nil || *machine.Spec.ProviderID == "" {
@@ -108,7 +108,15 @@ func (r *MachineReconciler) reconcileNode(ctx context.Context, cluster *clusterv
 		desired[clusterv1.OwnerKindAnnotation] = owner.Kind
 		desired[clusterv1.OwnerNameAnnotation] = owner.Name
 	}
-	if annotations.AddAnnotations(node, desired) {
+	changed := annotations.AddAnnotations(node, desired)
+
+	// Reconcile node labels and taints.
+	nodeMetadataChanged, err := reconcileNodeMetadata(machine, node)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if changed || nodeMetadataChanged {
 		if err := patchHelper.Patch(ctx, node); err != nil {
 			log.V(2).Info("Failed patch node to set annotations", "err", err, "node name", node.Name)
 			return ctrl.Result{}, err
@@ -130,6 +138,37 @@ func (r *MachineReconciler) reconcileNode(ctx context.Context, cluster *clusterv
 	return ctrl.Result{}, nil
 }
 
+// reconcileNodeMetadata applies to the Node the labels and taints defined by the NodeLabelsAnnotation and the
+// NodeTaintsAnnotation on the Machine, together with the Machine labels in the node-role.kubernetes.io and
+// node-restriction.kubernetes.io domains, removing the labels and taints previously applied and not desired anymore.
+// In case the NodeLabelsAnnotation and the Machine labels define the same label, the NodeLabelsAnnotation takes precedence.
+// Returns true if the Node has been changed.
+func reconcileNodeMetadata(machine *clusterv1.Machine, node *corev1.Node) (bool, error) {
+	machineLabels, hasLabels := machine.Annotations[clusterv1.NodeLabelsAnnotation]
+	machineTaints, hasTaints := machine.Annotations[clusterv1.NodeTaintsAnnotation]
+	propagatedLabels := nodemetadata.MachineNodeLabels(machine.Labels)
+	_, nodeHasLabels := node.Annotations[clusterv1.NodeLabelsAnnotation]
+	_, nodeHasTaints := node.Annotations[clusterv1.NodeTaintsAnnotation]
+	if !hasLabels && !hasTaints && len(propagatedLabels) == 0 && !nodeHasLabels && !nodeHasTaints {
+		return false, nil
+	}
+
+	labels, err := nodemetadata.ParseLabels(machineLabels)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s annotation on Machine %q", clusterv1.NodeLabelsAnnotation, machine.Name)
+	}
+	for k, v := range propagatedLabels {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	taints, err := nodemetadata.ParseTaints(machineTaints)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s annotation on Machine %q", clusterv1.NodeTaintsAnnotation, machine.Name)
+	}
+	return nodemetadata.SyncNode(node, labels, taints)
+}
+
 // summarizeNodeConditions summarizes a Node's conditions and returns the summary of condition statuses and concatenate failed condition messages:
 // if there is at least 1 semantically-negative condition, summarized status = False;
 // if there is at least 1 semantically-positive condition when there is 0 semantically negative condition, summarized status = True;
@@ -226,3 +226,118 @@ func TestSummarizeNodeConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileNodeMetadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		machine     *clusterv1.Machine
+		node        *corev1.Node
+		wantLabels  map[string]string
+		wantChanged bool
+	}{
+		{
+			name: "propagates Machine labels in the node-role and node-restriction domains",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName:                 "cluster",
+						"node-role.kubernetes.io/worker":           "",
+						"node-restriction.kubernetes.io/dedicated": "gpu",
+					},
+				},
+			},
+			node: &corev1.Node{},
+			wantLabels: map[string]string{
+				"node-role.kubernetes.io/worker":           "",
+				"node-restriction.kubernetes.io/dedicated": "gpu",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "the node labels annotation takes precedence over Machine labels",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"node-restriction.kubernetes.io/dedicated": "gpu"},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "node-restriction.kubernetes.io/dedicated=infra,zone=a",
+					},
+				},
+			},
+			node: &corev1.Node{},
+			wantLabels: map[string]string{
+				"node-restriction.kubernetes.io/dedicated": "infra",
+				"zone": "a",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "restores labels reset on the Node",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+				},
+			},
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kubernetes.io/os": "linux"},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "node-role.kubernetes.io/worker=",
+					},
+				},
+			},
+			wantLabels: map[string]string{
+				"kubernetes.io/os":               "linux",
+				"node-role.kubernetes.io/worker": "",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "removes labels previously propagated from the Machine",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{clusterv1.ClusterLabelName: "cluster"},
+				},
+			},
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kubernetes.io/os": "linux", "node-role.kubernetes.io/worker": ""},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "node-role.kubernetes.io/worker=",
+					},
+				},
+			},
+			wantLabels: map[string]string{
+				"kubernetes.io/os": "linux",
+			},
+			wantChanged: true,
+		},
+		{
+			name: "does not change a Node without labels and taints to sync",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{clusterv1.ClusterLabelName: "cluster"},
+				},
+			},
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kubernetes.io/os": "linux"},
+				},
+			},
+			wantLabels: map[string]string{
+				"kubernetes.io/os": "linux",
+			},
+			wantChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			changed, err := reconcileNodeMetadata(tt.machine, tt.node)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changed).To(Equal(tt.wantChanged))
+			g.Expect(tt.node.Labels).To(Equal(tt.wantLabels))
+		})
+	}
+}
@@ -23,10 +23,12 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -1231,6 +1233,235 @@ func TestIsNodeDrainedAllowed(t *testing.T) {
 	}
 }
 
+func TestWaitForDeleteHooks(t *testing.T) {
+	hookAnnotation := clusterv1.PreDrainDeleteHookAnnotationPrefix + "/test"
+	waitingCondition := func(since time.Duration) clusterv1.Conditions {
+		return clusterv1.Conditions{
+			{
+				Type:               clusterv1.PreDrainDeleteHookSucceededCondition,
+				Status:             corev1.ConditionFalse,
+				Severity:           clusterv1.ConditionSeverityInfo,
+				Reason:             clusterv1.WaitingExternalHookReason,
+				Message:            "Waiting for pre-drain.delete hooks to be removed",
+				LastTransitionTime: metav1.Time{Time: time.Now().Add(-since).UTC()},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		conditions        clusterv1.Conditions
+		timeout           time.Duration
+		expectWait        bool
+		expectRequeue     bool
+		expectedCondition *clusterv1.Condition
+	}{
+		{
+			name:              "No hooks",
+			expectWait:        false,
+			expectedCondition: conditions.TrueCondition(clusterv1.PreDrainDeleteHookSucceededCondition),
+		},
+		{
+			name:              "Hooks without timeout",
+			annotations:       map[string]string{hookAnnotation: ""},
+			expectWait:        true,
+			expectRequeue:     false,
+			expectedCondition: conditions.FalseCondition(clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "Waiting for pre-drain.delete hooks to be removed"),
+		},
+		{
+			name:              "Hooks with a timeout that is not yet over",
+			annotations:       map[string]string{hookAnnotation: ""},
+			conditions:        waitingCondition(30 * time.Second),
+			timeout:           60 * time.Second,
+			expectWait:        true,
+			expectRequeue:     true,
+			expectedCondition: conditions.FalseCondition(clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "Waiting for pre-drain.delete hooks to be removed"),
+		},
+		{
+			name:              "Hooks with a timeout that is over",
+			annotations:       map[string]string{hookAnnotation: ""},
+			conditions:        waitingCondition(70 * time.Second),
+			timeout:           60 * time.Second,
+			expectWait:        false,
+			expectedCondition: conditions.FalseCondition(clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.ExternalHookTimedOutReason, clusterv1.ConditionSeverityWarning, "Timed out after 1m0s waiting for pre-drain.delete hooks to be removed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-machine",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: tt.annotations,
+				},
+				Status: clusterv1.MachineStatus{
+					Conditions: tt.conditions,
+				},
+			}
+
+			r := &MachineReconciler{
+				LifecycleHookTimeout: tt.timeout,
+				recorder:             record.NewFakeRecorder(32),
+			}
+
+			shouldWait, requeueAfter := r.waitForDeleteHooks(m, "pre-drain.delete", clusterv1.PreDrainDeleteHookAnnotationPrefix, clusterv1.PreDrainDeleteHookSucceededCondition)
+			g.Expect(shouldWait).To(Equal(tt.expectWait))
+			g.Expect(requeueAfter > 0).To(Equal(tt.expectRequeue))
+
+			c := conditions.Get(m, clusterv1.PreDrainDeleteHookSucceededCondition)
+			g.Expect(c).ToNot(BeNil())
+			g.Expect(c.Status).To(Equal(tt.expectedCondition.Status))
+			g.Expect(c.Reason).To(Equal(tt.expectedCondition.Reason))
+			g.Expect(c.Severity).To(Equal(tt.expectedCondition.Severity))
+			g.Expect(c.Message).To(Equal(tt.expectedCondition.Message))
+		})
+	}
+}
+
+func TestNodeVolumeDetachTimeoutExceeded(t *testing.T) {
+	tests := []struct {
+		name     string
+		machine  *clusterv1.Machine
+		expected bool
+	}{
+		{
+			name: "Node volume detach timeout is over",
+			machine: &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{
+					NodeVolumeDetachTimeout: &metav1.Duration{Duration: time.Second * 60},
+				},
+				Status: clusterv1.MachineStatus{
+					Conditions: clusterv1.Conditions{
+						{
+							Type:               clusterv1.VolumeDetachSucceededCondition,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 70)).UTC()},
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Node volume detach timeout is not yet over",
+			machine: &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{
+					NodeVolumeDetachTimeout: &metav1.Duration{Duration: time.Second * 60},
+				},
+				Status: clusterv1.MachineStatus{
+					Conditions: clusterv1.Conditions{
+						{
+							Type:               clusterv1.VolumeDetachSucceededCondition,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 30)).UTC()},
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "NodeVolumeDetachTimeout option is set to its default value 0",
+			machine: &clusterv1.Machine{
+				Status: clusterv1.MachineStatus{
+					Conditions: clusterv1.Conditions{
+						{
+							Type:               clusterv1.VolumeDetachSucceededCondition,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: metav1.Time{Time: time.Now().Add(-(time.Second * 1000)).UTC()},
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &MachineReconciler{}
+			g.Expect(r.nodeVolumeDetachTimeoutExceeded(tt.machine)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestShouldWaitForNodeVolumes(t *testing.T) {
+	testCluster := &clusterv1.Cluster{
+		TypeMeta:   metav1.TypeMeta{Kind: "Cluster", APIVersion: clusterv1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "test-cluster"},
+	}
+
+	tests := []struct {
+		name     string
+		node     *corev1.Node
+		objs     []client.Object
+		expected bool
+	}{
+		{
+			name:     "Node without volumes",
+			node:     &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}},
+			expected: false,
+		},
+		{
+			name: "Node with volumes attached",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Status: corev1.NodeStatus{
+					VolumesAttached: []corev1.AttachedVolume{{Name: "test-volume", DevicePath: "test-path"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Node with a CSI VolumeAttachment",
+			node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}},
+			objs: []client.Object{
+				&storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-volume-attachment"},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: "test-csi-driver",
+						NodeName: "test-node",
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Node without a CSI VolumeAttachment, another node has one",
+			node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}},
+			objs: []client.Object{
+				&storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-volume-attachment"},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: "test-csi-driver",
+						NodeName: "another-node",
+					},
+				},
+			},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			remoteClient := fake.NewClientBuilder().WithObjects(append(tt.objs, tt.node)...).Build()
+			r := &MachineReconciler{
+				Client:  fake.NewClientBuilder().WithObjects(testCluster).Build(),
+				Tracker: remote.NewTestClusterCacheTracker(log.NullLogger{}, remoteClient, scheme.Scheme, client.ObjectKey{Name: testCluster.Name, Namespace: testCluster.Namespace}),
+			}
+
+			got, err := r.shouldWaitForNodeVolumes(ctx, testCluster, tt.node.Name)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.expected))
+		})
+	}
+}
+
 func TestIsDeleteNodeAllowed(t *testing.T) {
 	deletionts := metav1.Now()
 
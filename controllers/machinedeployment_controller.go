@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -94,8 +96,6 @@ func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr
 }
 
 func (r *MachineDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	// Fetch the MachineDeployment instance.
 	deployment := &clusterv1.MachineDeployment{}
 	if err := r.Client.Get(ctx, req.NamespacedName, deployment); err != nil {
@@ -108,6 +108,8 @@ func (r *MachineDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	ctx, log := logutil.IntoWithCluster(ctx, deployment.Namespace, deployment.Spec.ClusterName, logutil.MachineDeploymentKey, logutil.KObj(deployment))
+
 	cluster, err := util.GetClusterByName(ctx, r.Client, deployment.Namespace, deployment.Spec.ClusterName)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -216,19 +218,32 @@ func (r *MachineDeploymentReconciler) reconcile(ctx context.Context, cluster *cl
 		return ctrl.Result{}, r.sync(ctx, d, msList)
 	}
 
+	// Roll back the machine template; the rollout is performed by the reconciliation
+	// triggered by the MachineDeployment update.
+	if d.Spec.RollbackTo != nil {
+		r.rollback(ctx, d, msList)
+		return ctrl.Result{}, nil
+	}
+
 	if d.Spec.Strategy == nil {
 		return ctrl.Result{}, errors.Errorf("missing MachineDeployment strategy")
 	}
 
+	// Requeue at the rolloutAfter time, if it is in the future, so the rollout starts on time.
+	result := ctrl.Result{}
+	if d.Spec.RolloutAfter != nil && d.Spec.RolloutAfter.After(time.Now()) {
+		result.RequeueAfter = time.Until(d.Spec.RolloutAfter.Time)
+	}
+
 	if d.Spec.Strategy.Type == clusterv1.RollingUpdateMachineDeploymentStrategyType {
 		if d.Spec.Strategy.RollingUpdate == nil {
 			return ctrl.Result{}, errors.Errorf("missing MachineDeployment settings for strategy type: %s", d.Spec.Strategy.Type)
 		}
-		return ctrl.Result{}, r.rolloutRolling(ctx, d, msList)
+		return result, r.rolloutRolling(ctx, d, msList)
 	}
 
 	if d.Spec.Strategy.Type == clusterv1.OnDeleteMachineDeploymentStrategyType {
-		return ctrl.Result{}, r.rolloutOnDelete(ctx, d, msList)
+		return result, r.rolloutOnDelete(ctx, d, msList)
 	}
 
 	return ctrl.Result{}, errors.Errorf("unexpected deployment strategy type: %s", d.Spec.Strategy.Type)
@@ -250,19 +265,19 @@ func (r *MachineDeploymentReconciler) getMachineSetsForDeployment(ctx context.Co
 
 		selector, err := metav1.LabelSelectorAsSelector(&d.Spec.Selector)
 		if err != nil {
-			log.Error(err, "Skipping MachineSet, failed to get label selector from spec selector", "machineset", ms.Name)
+			log.Error(err, "Skipping MachineSet, failed to get label selector from spec selector", logutil.MachineSetKey, logutil.KObj(ms))
 			continue
 		}
 
 		// If a MachineDeployment with a nil or empty selector creeps in, it should match nothing, not everything.
 		if selector.Empty() {
-			log.Info("Skipping MachineSet as the selector is empty", "machineset", ms.Name)
+			log.Info("Skipping MachineSet as the selector is empty", logutil.MachineSetKey, logutil.KObj(ms))
 			continue
 		}
 
 		// Skip this MachineSet unless either selector matches or it has a controller ref pointing to this MachineDeployment
 		if !selector.Matches(labels.Set(ms.Labels)) && !metav1.IsControlledBy(ms, d) {
-			log.V(4).Info("Skipping MachineSet, label mismatch", "machineset", ms.Name)
+			log.V(4).Info("Skipping MachineSet, label mismatch", logutil.MachineSetKey, logutil.KObj(ms))
 			continue
 		}
 
@@ -270,7 +285,7 @@ func (r *MachineDeploymentReconciler) getMachineSetsForDeployment(ctx context.Co
 		if metav1.GetControllerOf(ms) == nil {
 			if err := r.adoptOrphan(ctx, d, ms); err != nil {
 				r.recorder.Eventf(d, corev1.EventTypeWarning, "FailedAdopt", "Failed to adopt MachineSet %q: %v", ms.Name, err)
-				log.Error(err, "Failed to adopt MachineSet into MachineDeployment", "machineset", ms.Name)
+				log.Error(err, "Failed to adopt MachineSet into MachineDeployment", logutil.MachineSetKey, logutil.KObj(ms))
 				continue
 			}
 			r.recorder.Eventf(d, corev1.EventTypeNormal, "SuccessfulAdopt", "Adopted MachineSet %q", ms.Name)
@@ -299,7 +314,7 @@ func (r *MachineDeploymentReconciler) getMachineDeploymentsForMachineSet(ctx con
 	log := ctrl.LoggerFrom(ctx)
 
 	if len(ms.Labels) == 0 {
-		log.V(2).Info("No MachineDeployments found for MachineSet because it has no labels", "machineset", ms.Name)
+		log.V(2).Info("No MachineDeployments found for MachineSet because it has no labels", logutil.MachineSetKey, logutil.KObj(ms))
 		return nil
 	}
 
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// rollback copies the machine template of the machine set with the revision set in spec.rollbackTo into the
+// deployment, then clears spec.rollbackTo; the rollout of the restored machine template is performed by the
+// next reconciliation. If the revision can't be found, the rollback is aborted.
+func (r *MachineDeploymentReconciler) rollback(ctx context.Context, d *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) {
+	log := ctrl.LoggerFrom(ctx)
+
+	toRevision := d.Spec.RollbackTo.Revision
+	d.Spec.RollbackTo = nil
+
+	ms, err := mdutil.FindMachineSetForRevision(toRevision, msList)
+	if err != nil {
+		log.Info("Unable to roll back MachineDeployment", "revision", toRevision, "reason", err.Error())
+		r.recorder.Eventf(d, corev1.EventTypeWarning, "RollbackRevisionNotFound", "Unable to roll back: %v", err)
+		return
+	}
+
+	// Copy the machine template into the deployment, excluding the hash.
+	template := *ms.Spec.Template.DeepCopy()
+	delete(template.Labels, mdutil.DefaultMachineDeploymentUniqueLabelKey)
+	d.Spec.Template = template
+
+	log.Info("Rolled back MachineDeployment", "revision", ms.Annotations[clusterv1.RevisionAnnotation])
+	r.recorder.Eventf(d, corev1.EventTypeNormal, "RollbackDone", "Rolled back to revision %s", ms.Annotations[clusterv1.RevisionAnnotation])
+}
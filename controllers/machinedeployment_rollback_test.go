@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+)
+
+func TestRollback(t *testing.T) {
+	machineSet := func(revision, version string) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ms-" + revision,
+				Annotations: map[string]string{clusterv1.RevisionAnnotation: revision},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					ObjectMeta: clusterv1.ObjectMeta{
+						Labels: map[string]string{
+							"foo": "bar",
+							mdutil.DefaultMachineDeploymentUniqueLabelKey: "hash-" + revision,
+						},
+					},
+					Spec: clusterv1.MachineSpec{
+						Version: &version,
+					},
+				},
+			},
+		}
+	}
+	msList := []*clusterv1.MachineSet{
+		machineSet("1", "v1.21.1"),
+		machineSet("2", "v1.22.0"),
+		machineSet("3", "v1.22.1"),
+	}
+	currentTemplate := msList[2].Spec.Template.DeepCopy()
+	delete(currentTemplate.Labels, mdutil.DefaultMachineDeploymentUniqueLabelKey)
+
+	testCases := []struct {
+		name            string
+		rollbackTo      int64
+		expectedVersion string
+	}{
+		{
+			name:            "It rolls back to the given revision",
+			rollbackTo:      1,
+			expectedVersion: "v1.21.1",
+		},
+		{
+			name:            "It rolls back to the previous revision when revision is 0",
+			rollbackTo:      0,
+			expectedVersion: "v1.22.0",
+		},
+		{
+			name:            "It keeps the machine template when the revision does not exist",
+			rollbackTo:      4,
+			expectedVersion: "v1.22.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := &clusterv1.MachineDeployment{
+				Spec: clusterv1.MachineDeploymentSpec{
+					Template:   *currentTemplate.DeepCopy(),
+					RollbackTo: &clusterv1.MachineDeploymentRollback{Revision: tc.rollbackTo},
+				},
+			}
+
+			r := &MachineDeploymentReconciler{
+				recorder: record.NewFakeRecorder(32),
+			}
+			r.rollback(ctx, md, msList)
+
+			g.Expect(md.Spec.RollbackTo).To(BeNil())
+			g.Expect(*md.Spec.Template.Spec.Version).To(Equal(tc.expectedVersion))
+			g.Expect(md.Spec.Template.Labels).To(Equal(map[string]string{"foo": "bar"}))
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileOldMachineSetsOnDelete(t *testing.T) {
+	onDeleteStrategy := &clusterv1.MachineDeploymentStrategy{
+		Type: clusterv1.OnDeleteMachineDeploymentStrategyType,
+	}
+
+	testCases := []struct {
+		name                          string
+		machineDeployment             *clusterv1.MachineDeployment
+		newMachineSet                 *clusterv1.MachineSet
+		oldMachineSet                 *clusterv1.MachineSet
+		oldMachines                   int
+		expectedOldMachineSetReplicas int32
+	}{
+		{
+			name: "It scales down the old MachineSet for deleted machines",
+			machineDeployment: &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "bar",
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Strategy: onDeleteStrategy,
+					Replicas: pointer.Int32Ptr(3),
+				},
+			},
+			newMachineSet: &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "new",
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32Ptr(1),
+				},
+			},
+			oldMachineSet: &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "old",
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32Ptr(3),
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"machine-set": "old"},
+					},
+				},
+			},
+			oldMachines:                   2,
+			expectedOldMachineSetReplicas: 2,
+		},
+		{
+			name: "It scales down the old MachineSet when the MachineDeployment is scaled down",
+			machineDeployment: &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "bar",
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Strategy: onDeleteStrategy,
+					Replicas: pointer.Int32Ptr(2),
+				},
+			},
+			newMachineSet: &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "new",
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32Ptr(0),
+				},
+			},
+			oldMachineSet: &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "old",
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32Ptr(3),
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"machine-set": "old"},
+					},
+				},
+			},
+			oldMachines:                   3,
+			expectedOldMachineSetReplicas: 2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			resources := []client.Object{
+				tc.machineDeployment,
+				tc.newMachineSet,
+				tc.oldMachineSet,
+			}
+			for i := 0; i < tc.oldMachines; i++ {
+				resources = append(resources, &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: tc.oldMachineSet.Namespace,
+						Name:      fmt.Sprintf("%s-%d", tc.oldMachineSet.Name, i),
+						Labels:    tc.oldMachineSet.Spec.Selector.MatchLabels,
+					},
+				})
+			}
+
+			r := &MachineDeploymentReconciler{
+				Client:   fake.NewClientBuilder().WithObjects(resources...).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			allMachineSets := []*clusterv1.MachineSet{tc.oldMachineSet, tc.newMachineSet}
+			err := r.reconcileOldMachineSetsOnDelete(ctx, []*clusterv1.MachineSet{tc.oldMachineSet}, allMachineSets, tc.machineDeployment)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			freshOldMachineSet := &clusterv1.MachineSet{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(tc.oldMachineSet), freshOldMachineSet)).To(Succeed())
+			g.Expect(*freshOldMachineSet.Spec.Replicas).To(Equal(tc.expectedOldMachineSetReplicas))
+			g.Expect(freshOldMachineSet.Annotations).To(HaveKeyWithValue(clusterv1.DisableMachineCreate, "true"))
+		})
+	}
+}
+
+func TestReconcileNewMachineSetOnDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	machineDeployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "bar",
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			// The rolling update parameters are not required when using the OnDelete strategy.
+			Strategy: &clusterv1.MachineDeploymentStrategy{
+				Type: clusterv1.OnDeleteMachineDeploymentStrategyType,
+			},
+			Replicas: pointer.Int32Ptr(3),
+		},
+	}
+	newMachineSet := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "new",
+			Annotations: map[string]string{
+				clusterv1.DisableMachineCreate: "true",
+			},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			Replicas: pointer.Int32Ptr(0),
+		},
+	}
+	oldMachineSet := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "old",
+		},
+		Spec: clusterv1.MachineSetSpec{
+			Replicas: pointer.Int32Ptr(2),
+		},
+	}
+
+	r := &MachineDeploymentReconciler{
+		Client:   fake.NewClientBuilder().WithObjects(machineDeployment, newMachineSet, oldMachineSet).Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	allMachineSets := []*clusterv1.MachineSet{oldMachineSet, newMachineSet}
+	g.Expect(r.reconcileNewMachineSetOnDelete(ctx, allMachineSets, newMachineSet, machineDeployment)).To(Succeed())
+
+	// The new MachineSet is only scaled up to replace the machines that are missing across all MachineSets.
+	freshNewMachineSet := &clusterv1.MachineSet{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(newMachineSet), freshNewMachineSet)).To(Succeed())
+	g.Expect(*freshNewMachineSet.Spec.Replicas).To(Equal(int32(1)))
+	g.Expect(freshNewMachineSet.Annotations).ToNot(HaveKey(clusterv1.DisableMachineCreate))
+}
@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/labels"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -75,10 +76,11 @@ func (r *MachineDeploymentReconciler) sync(ctx context.Context, d *clusterv1.Mac
 // Note that currently the deployment controller is using caches to avoid querying the server for reads.
 // This may lead to stale reads of machine sets, thus incorrect deployment status.
 func (r *MachineDeploymentReconciler) getAllMachineSetsAndSyncRevision(ctx context.Context, d *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet, createIfNotExisted bool) (*clusterv1.MachineSet, []*clusterv1.MachineSet, error) {
-	_, allOldMSs := mdutil.FindOldMachineSets(d, msList)
+	reconciliationTime := metav1.Now()
+	_, allOldMSs := mdutil.FindOldMachineSets(d, msList, &reconciliationTime)
 
 	// Get new machine set with the updated revision number
-	newMS, err := r.getNewMachineSet(ctx, d, msList, allOldMSs, createIfNotExisted)
+	newMS, err := r.getNewMachineSet(ctx, d, msList, allOldMSs, createIfNotExisted, &reconciliationTime)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -91,10 +93,11 @@ func (r *MachineDeploymentReconciler) getAllMachineSetsAndSyncRevision(ctx conte
 // 2. If there's existing new MS, update its revision number if it's smaller than (maxOldRevision + 1), where maxOldRevision is the max revision number among all old MSes.
 // 3. If there's no existing new MS and createIfNotExisted is true, create one with appropriate revision number (maxOldRevision + 1) and replicas.
 // Note that the machine-template-hash will be added to adopted MSes and machines.
-func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *clusterv1.MachineDeployment, msList, oldMSs []*clusterv1.MachineSet, createIfNotExisted bool) (*clusterv1.MachineSet, error) {
+// Note that MSes created before the deployment's rolloutAfter time are not considered new once rolloutAfter has passed.
+func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *clusterv1.MachineDeployment, msList, oldMSs []*clusterv1.MachineSet, createIfNotExisted bool, reconciliationTime *metav1.Time) (*clusterv1.MachineSet, error) {
 	log := ctrl.LoggerFrom(ctx)
 
-	existingNewMS := mdutil.FindNewMachineSet(d, msList)
+	existingNewMS := mdutil.FindNewMachineSet(d, msList, reconciliationTime)
 
 	// Calculate the max revision number among all old MSes
 	maxOldRevision := mdutil.MaxRevision(oldMSs, log)
@@ -117,12 +120,13 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 		annotationsUpdated := mdutil.SetNewMachineSetAnnotations(d, msCopy, newRevision, true, log)
 
 		minReadySecondsNeedsUpdate := msCopy.Spec.MinReadySeconds != *d.Spec.MinReadySeconds
-		deletePolicyNeedsUpdate := d.Spec.Strategy.RollingUpdate.DeletePolicy != nil && msCopy.Spec.DeletePolicy != *d.Spec.Strategy.RollingUpdate.DeletePolicy
+		deletePolicy := mdutil.DeletePolicy(d)
+		deletePolicyNeedsUpdate := deletePolicy != nil && msCopy.Spec.DeletePolicy != *deletePolicy
 		if annotationsUpdated || minReadySecondsNeedsUpdate || deletePolicyNeedsUpdate {
 			msCopy.Spec.MinReadySeconds = *d.Spec.MinReadySeconds
 
 			if deletePolicyNeedsUpdate {
-				msCopy.Spec.DeletePolicy = *d.Spec.Strategy.RollingUpdate.DeletePolicy
+				msCopy.Spec.DeletePolicy = *deletePolicy
 			}
 
 			return nil, patchHelper.Patch(ctx, msCopy)
@@ -141,7 +145,7 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 
 	// new MachineSet does not exist, create one.
 	newMSTemplate := *d.Spec.Template.DeepCopy()
-	hash, err := mdutil.ComputeSpewHash(&newMSTemplate)
+	hash, err := mdutil.ComputeMachineSetHash(d, reconciliationTime)
 	if err != nil {
 		return nil, err
 	}
@@ -187,8 +191,8 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 		}
 	}
 
-	if d.Spec.Strategy.RollingUpdate.DeletePolicy != nil {
-		newMS.Spec.DeletePolicy = *d.Spec.Strategy.RollingUpdate.DeletePolicy
+	if deletePolicy := mdutil.DeletePolicy(d); deletePolicy != nil {
+		newMS.Spec.DeletePolicy = *deletePolicy
 	}
 
 	// Add foregroundDeletion finalizer to MachineSet if the MachineDeployment has it
@@ -235,13 +239,13 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 
 		return nil, err
 	case err != nil:
-		log.Error(err, "Failed to create new machine set", "machineset", newMS.Name)
+		log.Error(err, "Failed to create new machine set", logutil.MachineSetKey, logutil.KObj(&newMS))
 		r.recorder.Eventf(d, corev1.EventTypeWarning, "FailedCreate", "Failed to create MachineSet %q: %v", newMS.Name, err)
 		return nil, err
 	}
 
 	if !alreadyExists {
-		log.V(4).Info("Created new machine set", "machineset", createdMS.Name)
+		log.V(4).Info("Created new machine set", logutil.MachineSetKey, logutil.KObj(createdMS))
 		r.recorder.Eventf(d, corev1.EventTypeNormal, "SuccessfulCreate", "Created MachineSet %q", newMS.Name)
 	}
 
@@ -327,7 +331,7 @@ func (r *MachineDeploymentReconciler) scale(ctx context.Context, deployment *clu
 		for i := range allMSs {
 			ms := allMSs[i]
 			if ms.Spec.Replicas == nil {
-				log.Info("Spec.Replicas for machine set is nil, this is unexpected.", "machineset", ms.Name)
+				log.Info("Spec.Replicas for machine set is nil, this is unexpected.", logutil.MachineSetKey, logutil.KObj(ms))
 				continue
 			}
 
@@ -511,7 +515,7 @@ func (r *MachineDeploymentReconciler) cleanupDeployment(ctx context.Context, old
 			continue
 		}
 
-		log.V(4).Info("Trying to cleanup machine set for deployment", "machineset", ms.Name)
+		log.V(4).Info("Trying to cleanup machine set for deployment", logutil.MachineSetKey, logutil.KObj(ms))
 		if err := r.Client.Delete(ctx, ms); err != nil && !apierrors.IsNotFound(err) {
 			// Return error instead of aggregating and continuing DELETEs on the theory
 			// that we may be overloading the api server.
@@ -42,6 +42,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -126,8 +127,7 @@ func (r *MachineHealthCheckReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
-	log = log.WithValues("cluster", m.Spec.ClusterName)
-	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, log = logutil.IntoWithCluster(ctx, m.Namespace, m.Spec.ClusterName, logutil.MachineHealthCheckKey, logutil.KObj(m))
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.Namespace, m.Spec.ClusterName)
 	if err != nil {
@@ -271,8 +271,21 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 			m,
 			corev1.EventTypeWarning,
 			EventRemediationRestricted,
+			"%s; remediation is blocked for machines: %s",
 			message,
+			strings.Join(targetMachineNames(unhealthy), ", "),
 		)
+		for _, t := range unhealthy {
+			r.recorder.Eventf(
+				t.Machine,
+				corev1.EventTypeWarning,
+				EventRemediationRestricted,
+				"Remediation of Machine %v is blocked by MachineHealthCheck %s: %s",
+				t.string(),
+				m.Name,
+				message,
+			)
+		}
 		errList := []error{}
 		for _, t := range append(healthy, unhealthy...) {
 			if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
@@ -306,7 +319,8 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 	m.Status.RemediationsAllowed = remediationCount
 	conditions.MarkTrue(m, clusterv1.RemediationAllowedCondition)
 
-	errList := r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
+	remediationCheckTimes, errList := r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
+	nextCheckTimes = append(nextCheckTimes, remediationCheckTimes...)
 	errList = append(errList, r.patchHealthyTargets(ctx, logger, healthy, m)...)
 
 	// handle update errors
@@ -349,7 +363,7 @@ func (r *MachineHealthCheckReconciler) patchHealthyTargets(ctx context.Context,
 		}
 
 		if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
-			logger.Error(err, "failed to patch healthy machine status for machine", "machine", t.Machine.GetName())
+			logger.Error(err, "failed to patch healthy machine status for machine", logutil.MachineKey, logutil.KObj(t.Machine))
 			errList = append(errList, errors.Wrapf(err, "failed to patch healthy machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
 		}
 	}
@@ -357,9 +371,11 @@ func (r *MachineHealthCheckReconciler) patchHealthyTargets(ctx context.Context,
 }
 
 // patchUnhealthyTargets patches machines with MachineOwnerRemediatedCondition for remediation.
-func (r *MachineHealthCheckReconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logger, unhealthy []healthCheckTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) []error {
+// It returns the durations after which external remediation requests that are still in progress time out.
+func (r *MachineHealthCheckReconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logger, unhealthy []healthCheckTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) ([]time.Duration, []error) {
 	// mark for remediation
 	errList := []error{}
+	var nextCheckTimes []time.Duration
 	for _, t := range unhealthy {
 		condition := conditions.Get(t.Machine, clusterv1.MachineHealthCheckSuccededCondition)
 
@@ -367,53 +383,33 @@ func (r *MachineHealthCheckReconciler) patchUnhealthyTargets(ctx context.Context
 			logger.Info("Machine has failed health check, but machine is paused so skipping remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
 		} else {
 			if m.Spec.RemediationTemplate != nil {
-				// If external remediation request already exists,
-				// return early
-				if r.externalRemediationRequestExists(ctx, m, t.Machine.Name) {
-					return errList
-				}
-
-				cloneOwnerRef := &metav1.OwnerReference{
-					APIVersion: clusterv1.GroupVersion.String(),
-					Kind:       "Machine",
-					Name:       t.Machine.Name,
-					UID:        t.Machine.UID,
-				}
-
-				from, err := external.Get(ctx, r.Client, m.Spec.RemediationTemplate, t.Machine.Namespace)
-				if err != nil {
-					conditions.MarkFalse(m, clusterv1.ExternalRemediationTemplateAvailable, clusterv1.ExternalRemediationTemplateNotFound, clusterv1.ConditionSeverityError, err.Error())
-					errList = append(errList, errors.Wrapf(err, "error retrieving remediation template %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName))
-					return errList
-				}
-
-				generateTemplateInput := &external.GenerateTemplateInput{
-					Template:    from,
-					TemplateRef: m.Spec.RemediationTemplate,
-					Namespace:   t.Machine.Namespace,
-					ClusterName: t.Machine.ClusterName,
-					OwnerRef:    cloneOwnerRef,
-				}
-				to, err := external.GenerateTemplate(generateTemplateInput)
-				if err != nil {
-					errList = append(errList, errors.Wrapf(err, "failed to create template for remediation request %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, t.Machine.Name, t.Machine.Namespace, m.Spec.ClusterName))
-					return errList
-				}
-
-				// Set the Remediation Request to match the Machine name, the name is used to
-				// guarantee uniqueness between runs. A Machine should only ever have a single
-				// remediation object of a specific GVK created.
-				//
-				// NOTE: This doesn't guarantee uniqueness across different MHC objects watching
-				// the same Machine, users are in charge of setting health checks and remediation properly.
-				to.SetName(t.Machine.Name)
-
-				logger.Info("Target has failed health check, creating an external remediation request", "remediation request name", to.GetName(), "target", t.string(), "reason", condition.Reason, "message", condition.Message)
-				// Create the external clone.
-				if err := r.Client.Create(ctx, to); err != nil {
-					conditions.MarkFalse(m, clusterv1.ExternalRemediationRequestAvailable, clusterv1.ExternalRemediationRequestCreationFailed, clusterv1.ConditionSeverityError, err.Error())
-					errList = append(errList, errors.Wrapf(err, "error creating remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.ClusterName))
-					return errList
+				remediationReq, err := r.getExternalRemediationRequest(ctx, m, t.Machine.Name)
+				switch {
+				case err == nil:
+					// If the external remediation request already exists, wait for the external remediation
+					// to complete; if it takes longer than ExternalRemediationTimeout, fall back to the
+					// default remediation and let the owner controller replace the machine.
+					timedOut, nextCheck := externalRemediationTimedOut(m, remediationReq)
+					if !timedOut {
+						if nextCheck > 0 {
+							nextCheckTimes = append(nextCheckTimes, nextCheck)
+						}
+						continue
+					}
+					if !conditions.IsFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition) {
+						logger.Info("External remediation request timed out, marking for remediation", "remediation request name", remediationReq.GetName(), "target", t.string(), "timeout", m.Spec.ExternalRemediationTimeout.Duration.String())
+						conditions.MarkFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning,
+							"External remediation request %s %q did not complete within %s", remediationReq.GetKind(), remediationReq.GetName(), m.Spec.ExternalRemediationTimeout.Duration)
+					}
+				case apierrors.IsNotFound(errors.Cause(err)):
+					logger.Info("Target has failed health check, creating an external remediation request", "remediation request name", t.Machine.Name, "target", t.string(), "reason", condition.Reason, "message", condition.Message)
+					if err := r.createExternalRemediationRequest(ctx, m, t.Machine); err != nil {
+						errList = append(errList, err)
+						return nextCheckTimes, errList
+					}
+				default:
+					errList = append(errList, errors.Wrapf(err, "failed to fetch remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.ClusterName))
+					continue
 				}
 			} else {
 				logger.Info("Target has failed health check, marking for remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
@@ -437,7 +433,51 @@ func (r *MachineHealthCheckReconciler) patchUnhealthyTargets(ctx context.Context
 			t.string(),
 		)
 	}
-	return errList
+	return nextCheckTimes, errList
+}
+
+// createExternalRemediationRequest creates an external remediation request for the machine
+// from the MachineHealthCheck's remediation template.
+func (r *MachineHealthCheckReconciler) createExternalRemediationRequest(ctx context.Context, m *clusterv1.MachineHealthCheck, machine *clusterv1.Machine) error {
+	cloneOwnerRef := &metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Machine",
+		Name:       machine.Name,
+		UID:        machine.UID,
+	}
+
+	from, err := external.Get(ctx, r.Client, m.Spec.RemediationTemplate, machine.Namespace)
+	if err != nil {
+		conditions.MarkFalse(m, clusterv1.ExternalRemediationTemplateAvailable, clusterv1.ExternalRemediationTemplateNotFound, clusterv1.ConditionSeverityError, err.Error())
+		return errors.Wrapf(err, "error retrieving remediation template %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, machine.Name, machine.Namespace, m.Spec.ClusterName)
+	}
+
+	generateTemplateInput := &external.GenerateTemplateInput{
+		Template:    from,
+		TemplateRef: m.Spec.RemediationTemplate,
+		Namespace:   machine.Namespace,
+		ClusterName: machine.ClusterName,
+		OwnerRef:    cloneOwnerRef,
+	}
+	to, err := external.GenerateTemplate(generateTemplateInput)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create template for remediation request %v %q for machine %q in namespace %q within cluster %q", m.Spec.RemediationTemplate.GroupVersionKind(), m.Spec.RemediationTemplate.Name, machine.Name, machine.Namespace, m.Spec.ClusterName)
+	}
+
+	// Set the Remediation Request to match the Machine name, the name is used to
+	// guarantee uniqueness between runs. A Machine should only ever have a single
+	// remediation object of a specific GVK created.
+	//
+	// NOTE: This doesn't guarantee uniqueness across different MHC objects watching
+	// the same Machine, users are in charge of setting health checks and remediation properly.
+	to.SetName(machine.Name)
+
+	// Create the external clone.
+	if err := r.Client.Create(ctx, to); err != nil {
+		conditions.MarkFalse(m, clusterv1.ExternalRemediationRequestAvailable, clusterv1.ExternalRemediationRequestCreationFailed, clusterv1.ConditionSeverityError, err.Error())
+		return errors.Wrapf(err, "error creating remediation request for machine %q in namespace %q within cluster %q", machine.Name, machine.Namespace, machine.ClusterName)
+	}
+	return nil
 }
 
 // clusterToMachineHealthCheck maps events from Cluster objects to
@@ -643,12 +683,19 @@ func (r *MachineHealthCheckReconciler) getExternalRemediationRequest(ctx context
 	return remediationReq, nil
 }
 
-// externalRemediationRequestExists checks if the External Remediation Request is created
-// for the machine.
-func (r *MachineHealthCheckReconciler) externalRemediationRequestExists(ctx context.Context, m *clusterv1.MachineHealthCheck, machineName string) bool {
-	remediationReq, err := r.getExternalRemediationRequest(ctx, m, machineName)
-	if err != nil {
-		return false
+// externalRemediationTimedOut returns true if the external remediation request has been in progress for
+// longer than the MachineHealthCheck's ExternalRemediationTimeout; otherwise it also returns the time left
+// before the timeout expires, or 0 if no timeout is configured.
+func externalRemediationTimedOut(m *clusterv1.MachineHealthCheck, remediationReq *unstructured.Unstructured) (bool, time.Duration) {
+	if m.Spec.ExternalRemediationTimeout == nil || m.Spec.ExternalRemediationTimeout.Duration <= 0 {
+		return false, 0
+	}
+
+	createdAt := remediationReq.GetCreationTimestamp()
+	timeoutAt := createdAt.Add(m.Spec.ExternalRemediationTimeout.Duration)
+	now := time.Now()
+	if !now.Before(timeoutAt) {
+		return true, 0
 	}
-	return remediationReq != nil
+	return false, timeoutAt.Sub(now)
 }
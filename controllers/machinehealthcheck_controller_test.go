@@ -2617,10 +2617,75 @@ func TestPatchTargets(t *testing.T) {
 	}
 
 	// Target with wrong patch helper will fail but the other one will be patched.
-	g.Expect(len(r.patchUnhealthyTargets(context.TODO(), log.NullLogger{}, []healthCheckTarget{target1, target3}, defaultCluster, mhc))).To(BeNumerically(">", 0))
+	_, errList := r.patchUnhealthyTargets(context.TODO(), log.NullLogger{}, []healthCheckTarget{target1, target3}, defaultCluster, mhc)
+	g.Expect(len(errList)).To(BeNumerically(">", 0))
 	g.Expect(cl.Get(ctx, client.ObjectKey{Name: machine2.Name, Namespace: machine2.Namespace}, machine2)).NotTo(HaveOccurred())
 	g.Expect(conditions.Get(machine2, clusterv1.MachineOwnerRemediatedCondition).Status).To(Equal(corev1.ConditionFalse))
 
 	// Target with wrong patch helper will fail but the other one will be patched.
 	g.Expect(len(r.patchHealthyTargets(context.TODO(), log.NullLogger{}, []healthCheckTarget{target1, target3}, mhc))).To(BeNumerically(">", 0))
 }
+
+func TestExternalRemediationTimedOut(t *testing.T) {
+	newRemediationRequest := func(createdAgo time.Duration) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-createdAgo)))
+		return u
+	}
+
+	tests := []struct {
+		name            string
+		timeout         *metav1.Duration
+		remediationReq  *unstructured.Unstructured
+		expectTimedOut  bool
+		expectNextCheck bool
+		maxNextCheck    time.Duration
+	}{
+		{
+			name:           "no timeout configured",
+			remediationReq: newRemediationRequest(time.Hour),
+			expectTimedOut: false,
+		},
+		{
+			name:           "zero timeout is ignored",
+			timeout:        &metav1.Duration{},
+			remediationReq: newRemediationRequest(time.Hour),
+			expectTimedOut: false,
+		},
+		{
+			name:            "remediation request still within the timeout",
+			timeout:         &metav1.Duration{Duration: 30 * time.Minute},
+			remediationReq:  newRemediationRequest(10 * time.Minute),
+			expectTimedOut:  false,
+			expectNextCheck: true,
+			maxNextCheck:    20 * time.Minute,
+		},
+		{
+			name:           "remediation request exceeded the timeout",
+			timeout:        &metav1.Duration{Duration: 30 * time.Minute},
+			remediationReq: newRemediationRequest(time.Hour),
+			expectTimedOut: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mhc := &clusterv1.MachineHealthCheck{
+				Spec: clusterv1.MachineHealthCheckSpec{
+					ExternalRemediationTimeout: tt.timeout,
+				},
+			}
+
+			timedOut, nextCheck := externalRemediationTimedOut(mhc, tt.remediationReq)
+			g.Expect(timedOut).To(Equal(tt.expectTimedOut))
+			if tt.expectNextCheck {
+				g.Expect(nextCheck).To(BeNumerically(">", 0))
+				g.Expect(nextCheck).To(BeNumerically("<=", tt.maxNextCheck))
+			} else {
+				g.Expect(nextCheck).To(BeZero())
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -28,8 +29,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -110,7 +113,9 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 	}
 
 	// Don't penalize any Machine/Node if the control plane has not been initialized.
-	if !conditions.IsTrue(t.Cluster, clusterv1.ControlPlaneInitializedCondition) {
+	// Control plane machines are an exception to this rule, so the first control plane machine can be remediated
+	// if it fails to initialize the control plane.
+	if !conditions.IsTrue(t.Cluster, clusterv1.ControlPlaneInitializedCondition) && !util.IsControlPlaneMachine(t.Machine) {
 		logger.V(3).Info("Not evaluating target health because the control plane has not yet been initialized")
 		// Return a nextCheck time of 0 because we'll get requeued when the Cluster is updated.
 		return false, 0
@@ -131,7 +136,12 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 			return false, 0
 		}
 
-		controlPlaneInitializedTime := conditions.GetLastTransitionTime(t.Cluster, clusterv1.ControlPlaneInitializedCondition).Time
+		// NOTE: The control plane initialized time is considered only after the control plane is initialized, because
+		// control plane machines are health checked also before initialization.
+		var controlPlaneInitializedTime time.Time
+		if conditions.IsTrue(t.Cluster, clusterv1.ControlPlaneInitializedCondition) {
+			controlPlaneInitializedTime = conditions.GetLastTransitionTime(t.Cluster, clusterv1.ControlPlaneInitializedCondition).Time
+		}
 		clusterInfraReadyTime := conditions.GetLastTransitionTime(t.Cluster, clusterv1.InfrastructureReadyCondition).Time
 		machineCreationTime := t.Machine.CreationTimestamp.Time
 
@@ -200,7 +210,7 @@ func (r *MachineHealthCheckReconciler) getTargetsFromMHC(ctx context.Context, lo
 	for k := range machines {
 		skip, reason := shouldSkipRemediation(&machines[k])
 		if skip {
-			logger.Info("skipping remediation", "machine", machines[k].Name, "reason", reason)
+			logger.Info("skipping remediation", logutil.MachineKey, logutil.KObj(&machines[k]), "reason", reason)
 			continue
 		}
 
@@ -334,6 +344,16 @@ func minDuration(durations []time.Duration) time.Duration {
 	return minDuration
 }
 
+// targetMachineNames returns the sorted names of the Machines of the given targets.
+func targetMachineNames(targets []healthCheckTarget) []string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, t.Machine.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // shouldSkipRemediation checks if the machine should be skipped for remediation.
 // Returns true if it should be skipped along with the reason for skipping.
 func shouldSkipRemediation(m *clusterv1.Machine) (bool, string) {
@@ -310,6 +310,26 @@ func TestHealthCheckTargets(t *testing.T) {
 		nodeMissing: false,
 	}
 
+	// Targets for when the control plane has not been initialized yet
+	clusterNotInitialized := cluster.DeepCopy()
+	conditions.MarkFalse(clusterNotInitialized, clusterv1.ControlPlaneInitializedCondition, clusterv1.WaitingForControlPlaneProviderInitializedReason, clusterv1.ConditionSeverityInfo, "")
+
+	workerNodeNotYetStartedBeforeInitialization := healthCheckTarget{
+		Cluster: clusterNotInitialized,
+		MHC:     testMHC,
+		Machine: testMachineCreated1200s,
+		Node:    nil,
+	}
+
+	testControlPlaneMachineCreated1200s := testMachineCreated1200s.DeepCopy()
+	testControlPlaneMachineCreated1200s.Labels[clusterv1.MachineControlPlaneLabelName] = ""
+	controlPlaneNodeNotYetStartedBeforeInitialization := healthCheckTarget{
+		Cluster: clusterNotInitialized,
+		MHC:     testMHC,
+		Machine: testControlPlaneMachineCreated1200s,
+		Node:    nil,
+	}
+
 	testCases := []struct {
 		desc                        string
 		targets                     []healthCheckTarget
@@ -367,6 +387,20 @@ func TestHealthCheckTargets(t *testing.T) {
 			expectedNeedsRemediation: []healthCheckTarget{nodeUnknown400},
 			expectedNextCheckTimes:   []time.Duration{200 * time.Second, 100 * time.Second},
 		},
+		{
+			desc:                     "when the node of a worker machine has not yet started before the control plane is initialized",
+			targets:                  []healthCheckTarget{workerNodeNotYetStartedBeforeInitialization},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{},
+		},
+		{
+			desc:                     "when the node of a control plane machine has not yet started for longer than the timeout before the control plane is initialized",
+			targets:                  []healthCheckTarget{controlPlaneNodeNotYetStartedBeforeInitialization},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{controlPlaneNodeNotYetStartedBeforeInitialization},
+			expectedNextCheckTimes:   []time.Duration{},
+		},
 		{
 			desc:                        "when the node has not started for a long time but the startup timeout is disabled",
 			targets:                     []healthCheckTarget{nodeNotYetStartedTarget400s},
@@ -411,6 +445,19 @@ func TestHealthCheckTargets(t *testing.T) {
 	}
 }
 
+func TestTargetMachineNames(t *testing.T) {
+	g := NewWithT(t)
+
+	targets := []healthCheckTarget{
+		{Machine: newTestMachine("machine-c", "default", "cluster", "node-c", nil)},
+		{Machine: newTestMachine("machine-a", "default", "cluster", "node-a", nil)},
+		{Machine: newTestMachine("machine-b", "default", "cluster", "node-b", nil)},
+	}
+
+	g.Expect(targetMachineNames(targets)).To(Equal([]string{"machine-a", "machine-b", "machine-c"}))
+	g.Expect(targetMachineNames(nil)).To(BeEmpty())
+}
+
 func newTestMachine(name, namespace, clusterName, nodeName string, labels map[string]string) *clusterv1.Machine {
 	// Copy the labels so that the map is unique to each test Machine
 	l := make(map[string]string)
@@ -38,6 +38,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -109,8 +110,6 @@ func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 }
 
 func (r *MachineSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	machineSet := &clusterv1.MachineSet{}
 	if err := r.Client.Get(ctx, req.NamespacedName, machineSet); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -122,6 +121,8 @@ func (r *MachineSetReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	ctx, log := logutil.IntoWithCluster(ctx, machineSet.Namespace, machineSet.Spec.ClusterName, logutil.MachineSetKey, logutil.KObj(machineSet))
+
 	cluster, err := util.GetClusterByName(ctx, r.Client, machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -251,11 +252,11 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 		// Attempt to adopt machine if it meets previous conditions and it has no controller references.
 		if metav1.GetControllerOf(machine) == nil {
 			if err := r.adoptOrphan(ctx, machineSet, machine); err != nil {
-				log.Error(err, "Failed to adopt Machine", "machine", machine.Name)
+				log.Error(err, "Failed to adopt Machine", logutil.MachineKey, logutil.KObj(machine))
 				r.recorder.Eventf(machineSet, corev1.EventTypeWarning, "FailedAdopt", "Failed to adopt Machine %q: %v", machine.Name, err)
 				continue
 			}
-			log.Info("Adopted Machine", "machine", machine.Name)
+			log.Info("Adopted Machine", logutil.MachineKey, logutil.KObj(machine))
 			r.recorder.Eventf(machineSet, corev1.EventTypeNormal, "SuccessfulAdopt", "Adopted Machine %q", machine.Name)
 		}
 
@@ -270,7 +271,7 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 			continue
 		}
 		if conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition) {
-			log.Info("Deleting unhealthy machine", "machine", machine.GetName())
+			log.Info("Deleting unhealthy machine", logutil.MachineKey, logutil.KObj(machine))
 			patch := client.MergeFrom(machine.DeepCopy())
 			if err := r.Client.Delete(ctx, machine); err != nil {
 				errs = append(errs, errors.Wrap(err, "failed to delete"))
@@ -289,6 +290,10 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 		return ctrl.Result{}, errors.Wrap(err, "failed to remediate machines")
 	}
 
+	if err := r.syncNodeMetadataAnnotations(ctx, machineSet, filteredMachines); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to sync node labels and taints to machines")
+	}
+
 	syncErr := r.syncReplicas(ctx, machineSet, filteredMachines)
 
 	// Always updates status as machines come up or die.
@@ -391,7 +396,7 @@ func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.M
 			machine.Spec.InfrastructureRef = *infraRef
 
 			if err := r.Client.Create(ctx, machine); err != nil {
-				log.Error(err, "Unable to create Machine", "machine", machine.Name)
+				log.Error(err, "Unable to create Machine", logutil.MachineKey, logutil.KObj(machine))
 				r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedCreate", "Failed to create machine %q: %v", machine.Name, err)
 				errs = append(errs, err)
 				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.MachineCreationFailedReason,
@@ -431,12 +436,12 @@ func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.M
 		machinesToDelete := getMachinesToDeletePrioritized(machines, diff, deletePriorityFunc)
 		for _, machine := range machinesToDelete {
 			if err := r.Client.Delete(ctx, machine); err != nil {
-				log.Error(err, "Unable to delete Machine", "machine", machine.Name)
+				log.Error(err, "Unable to delete Machine", logutil.MachineKey, logutil.KObj(machine))
 				r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedDelete", "Failed to delete machine %q: %v", machine.Name, err)
 				errs = append(errs, err)
 				continue
 			}
-			log.Info("Deleted machine", "machine", machine.Name)
+			log.Info("Deleted machine", logutil.MachineKey, logutil.KObj(machine))
 			r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted machine %q", machine.Name)
 		}
 
@@ -471,9 +476,64 @@ func (r *MachineSetReconciler) getNewMachine(machineSet *clusterv1.MachineSet) *
 	if machine.Labels == nil {
 		machine.Labels = make(map[string]string)
 	}
+	// Propagate the labels and taints to be applied to the Node, if any.
+	// NOTE: Those annotations are defined on the MachineSet object, and not in .spec.template, so they can
+	// be changed without creating new Machines.
+	for _, key := range nodeMetadataAnnotations {
+		value, ok := machineSet.Annotations[key]
+		if !ok {
+			continue
+		}
+		// Copy the annotations to avoid changing the MachineSet's template.
+		annotations := make(map[string]string, len(machine.Annotations)+1)
+		for k, v := range machine.Annotations {
+			annotations[k] = v
+		}
+		annotations[key] = value
+		machine.Annotations = annotations
+	}
 	return machine
 }
 
+// nodeMetadataAnnotations are the annotations defining the labels and taints to be applied to the Nodes,
+// which are propagated from the MachineSet to its Machines.
+var nodeMetadataAnnotations = []string{clusterv1.NodeLabelsAnnotation, clusterv1.NodeTaintsAnnotation}
+
+// syncNodeMetadataAnnotations propagates the annotations defining the labels and taints to be applied to the Nodes
+// from the MachineSet to the existing Machines, thus allowing to change them without a rollout.
+func (r *MachineSetReconciler) syncNodeMetadataAnnotations(ctx context.Context, machineSet *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+	var errs []error
+	for _, machine := range machines {
+		if !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		patch := client.MergeFrom(machine.DeepCopy())
+		changed := false
+		for _, key := range nodeMetadataAnnotations {
+			value, ok := machineSet.Annotations[key]
+			if !ok {
+				continue
+			}
+			if current, ok := machine.Annotations[key]; ok && current == value {
+				continue
+			}
+			if machine.Annotations == nil {
+				machine.Annotations = map[string]string{}
+			}
+			machine.Annotations[key] = value
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if err := r.Client.Patch(ctx, machine, patch); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to patch Machine %q", machine.Name))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
 // shouldExcludeMachine returns true if the machine should be filtered out, false otherwise.
 func shouldExcludeMachine(machineSet *clusterv1.MachineSet, machine *clusterv1.Machine) bool {
 	if metav1.GetControllerOf(machine) != nil && !metav1.IsControlledBy(machine, machineSet) {
@@ -545,7 +605,7 @@ func (r *MachineSetReconciler) waitForMachineDeletion(ctx context.Context, machi
 func (r *MachineSetReconciler) MachineToMachineSets(o client.Object) []ctrl.Request {
 	ctx := context.Background()
 	// This won't log unless the global logger is set
-	log := ctrl.LoggerFrom(ctx, "object", client.ObjectKeyFromObject(o))
+	log := ctrl.LoggerFrom(ctx, logutil.MachineKey, logutil.KObj(o))
 	result := []ctrl.Request{}
 
 	m, ok := o.(*clusterv1.Machine)
@@ -634,7 +694,7 @@ func (r *MachineSetReconciler) updateStatus(ctx context.Context, cluster *cluste
 		}
 
 		if machine.Status.NodeRef == nil {
-			log.V(2).Info("Unable to retrieve Node status, missing NodeRef", "machine", machine.Name)
+			log.V(2).Info("Unable to retrieve Node status, missing NodeRef", logutil.MachineKey, logutil.KObj(machine))
 			continue
 		}
 
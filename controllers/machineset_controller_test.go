@@ -714,6 +714,51 @@ func TestAdoptOrphan(t *testing.T) {
 	}
 }
 
+func TestSyncNodeMetadataAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := newMachineSet("ms1", "cluster1", 2)
+	ms.Annotations = map[string]string{
+		clusterv1.NodeLabelsAnnotation: "zone=a",
+		clusterv1.NodeTaintsAnnotation: "dedicated=gpu:NoSchedule",
+	}
+
+	m1 := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "m1",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	m2 := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "m2",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				clusterv1.NodeLabelsAnnotation: "zone=b",
+				"foo":                          "bar",
+			},
+		},
+	}
+
+	r := &MachineSetReconciler{
+		Client: fake.NewClientBuilder().WithObjects(m1, m2).Build(),
+	}
+	g.Expect(r.syncNodeMetadataAnnotations(ctx, ms, []*clusterv1.Machine{m1.DeepCopy(), m2.DeepCopy()})).To(Succeed())
+
+	for _, m := range []*clusterv1.Machine{m1, m2} {
+		got := &clusterv1.Machine{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(m), got)).To(Succeed())
+		g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.NodeLabelsAnnotation, "zone=a"))
+		g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.NodeTaintsAnnotation, "dedicated=gpu:NoSchedule"))
+	}
+
+	// New Machines get the annotations, without changing the MachineSet's template.
+	newMachine := r.getNewMachine(ms)
+	g.Expect(newMachine.Annotations).To(HaveKeyWithValue(clusterv1.NodeLabelsAnnotation, "zone=a"))
+	g.Expect(newMachine.Annotations).To(HaveKeyWithValue(clusterv1.NodeTaintsAnnotation, "dedicated=gpu:NoSchedule"))
+	g.Expect(ms.Spec.Template.Annotations).To(BeEmpty())
+}
+
 func newMachineSet(name, cluster string, replicas int32) *clusterv1.MachineSet {
 	return &clusterv1.MachineSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -23,6 +23,8 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/failuredomains"
 )
 
 type (
@@ -95,15 +97,52 @@ func randomDeletePolicy(machine *clusterv1.Machine) deletePriority {
 	return couldDelete
 }
 
+// unhealthyFirstDeletePriority prioritizes Machines that are unhealthy, including the ones which failed a
+// MachineHealthCheck or whose Node is not healthy, and then falls back to the oldest Machines.
+func unhealthyFirstDeletePriority(machine *clusterv1.Machine) deletePriority {
+	if conditions.IsFalse(machine, clusterv1.MachineHealthCheckSuccededCondition) ||
+		conditions.IsFalse(machine, clusterv1.MachineNodeHealthyCondition) {
+		return mustDelete
+	}
+	return oldestDeletePriority(machine)
+}
+
+// isMarkedForDeletion returns true if the Machine is already being deleted or if it has been marked for deletion
+// using the delete machine annotation; such Machines are deleted first regardless of the delete policy.
+func isMarkedForDeletion(machine *clusterv1.Machine) bool {
+	if !machine.DeletionTimestamp.IsZero() {
+		return true
+	}
+	_, ok := machine.ObjectMeta.Annotations[clusterv1.DeleteMachineAnnotation]
+	return ok
+}
+
 type sortableMachines struct {
 	machines []*clusterv1.Machine
 	priority deletePriorityFunc
+	// machinesPerFailureDomain is the number of machines in each failure domain.
+	machinesPerFailureDomain map[string]int
 }
 
 func (m sortableMachines) Len() int      { return len(m.machines) }
 func (m sortableMachines) Swap(i, j int) { m.machines[i], m.machines[j] = m.machines[j], m.machines[i] }
 func (m sortableMachines) Less(i, j int) bool {
-	return m.priority(m.machines[j]) < m.priority(m.machines[i]) // high to low
+	if iMarked, jMarked := isMarkedForDeletion(m.machines[i]), isMarkedForDeletion(m.machines[j]); iMarked != jMarked {
+		return iMarked
+	}
+	if iPriority, jPriority := m.priority(m.machines[i]), m.priority(m.machines[j]); iPriority != jPriority {
+		return jPriority < iPriority // high to low
+	}
+	// Machines with the same priority in the failure domain with most machines are deleted first,
+	// so the remaining machines are spread across failure domains.
+	return m.failureDomainMachines(m.machines[i]) > m.failureDomainMachines(m.machines[j])
+}
+
+func (m sortableMachines) failureDomainMachines(machine *clusterv1.Machine) int {
+	if machine.Spec.FailureDomain == nil {
+		return 0
+	}
+	return m.machinesPerFailureDomain[*machine.Spec.FailureDomain]
 }
 
 func getMachinesToDeletePrioritized(filteredMachines []*clusterv1.Machine, diff int, fun deletePriorityFunc) []*clusterv1.Machine {
@@ -114,8 +153,9 @@ func getMachinesToDeletePrioritized(filteredMachines []*clusterv1.Machine, diff
 	}
 
 	sortable := sortableMachines{
-		machines: filteredMachines,
-		priority: fun,
+		machines:                 filteredMachines,
+		priority:                 fun,
+		machinesPerFailureDomain: failuredomains.CountMachines(filteredMachines),
 	}
 	sort.Sort(sortable)
 
@@ -131,9 +171,11 @@ func getDeletePriorityFunc(ms *clusterv1.MachineSet) (deletePriorityFunc, error)
 		return newestDeletePriority, nil
 	case clusterv1.OldestMachineSetDeletePolicy:
 		return oldestDeletePriority, nil
+	case clusterv1.UnhealthyFirstMachineSetDeletePolicy:
+		return unhealthyFirstDeletePriority, nil
 	case "":
 		return randomDeletePolicy, nil
 	default:
-		return nil, errors.Errorf("Unsupported delete policy %s. Must be one of 'Random', 'Newest', or 'Oldest'", msdp)
+		return nil, errors.Errorf("Unsupported delete policy %s. Must be one of 'Random', 'Newest', 'Oldest', or 'UnhealthyFirst'", msdp)
 	}
 }
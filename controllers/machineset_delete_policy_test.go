@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestMachineToDelete(t *testing.T) {
@@ -385,6 +386,14 @@ func TestMachineOldestDelete(t *testing.T) {
 			},
 			expect: []*clusterv1.Machine{unhealthyMachine},
 		},
+		{
+			desc: "func=oldestDeletePriority, diff=1 (DeleteMachineAnnotation before unhealthy)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				empty, unhealthyMachine, oldest, deleteMachineWithMachineAnnotation, newest,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithMachineAnnotation},
+		},
 	}
 
 	for _, test := range tests {
@@ -396,3 +405,143 @@ func TestMachineOldestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineUnhealthyFirstDelete(t *testing.T) {
+	currentTime := metav1.Now()
+	nodeRef := &corev1.ObjectReference{Name: "some-node"}
+	newest := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -1))},
+		Status:     clusterv1.MachineStatus{NodeRef: nodeRef},
+	}
+	oldest := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -10))},
+		Status:     clusterv1.MachineStatus{NodeRef: nodeRef},
+	}
+	healthCheckFailedMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -1))},
+		Status: clusterv1.MachineStatus{
+			NodeRef: nodeRef,
+			Conditions: clusterv1.Conditions{
+				*conditions.FalseCondition(clusterv1.MachineHealthCheckSuccededCondition, clusterv1.UnhealthyNodeConditionReason, clusterv1.ConditionSeverityWarning, ""),
+			},
+		},
+	}
+	nodeUnhealthyMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -1))},
+		Status: clusterv1.MachineStatus{
+			NodeRef: nodeRef,
+			Conditions: clusterv1.Conditions{
+				*conditions.FalseCondition(clusterv1.MachineNodeHealthyCondition, clusterv1.NodeConditionsFailedReason, clusterv1.ConditionSeverityWarning, ""),
+			},
+		},
+	}
+	deleteMachineWithMachineAnnotation := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{clusterv1.DeleteMachineAnnotation: ""}, CreationTimestamp: metav1.NewTime(currentTime.Time.AddDate(0, 0, -1))},
+		Status:     clusterv1.MachineStatus{NodeRef: nodeRef},
+	}
+
+	tests := []struct {
+		desc     string
+		machines []*clusterv1.Machine
+		diff     int
+		expect   []*clusterv1.Machine
+	}{
+		{
+			desc: "func=unhealthyFirstDeletePriority, diff=1",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				newest, oldest,
+			},
+			expect: []*clusterv1.Machine{oldest},
+		},
+		{
+			desc: "func=unhealthyFirstDeletePriority, diff=1 (failed MachineHealthCheck)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				newest, oldest, healthCheckFailedMachine,
+			},
+			expect: []*clusterv1.Machine{healthCheckFailedMachine},
+		},
+		{
+			desc: "func=unhealthyFirstDeletePriority, diff=1 (unhealthy Node)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				newest, nodeUnhealthyMachine, oldest,
+			},
+			expect: []*clusterv1.Machine{nodeUnhealthyMachine},
+		},
+		{
+			desc: "func=unhealthyFirstDeletePriority, diff=1 (DeleteMachineAnnotation before unhealthy)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				healthCheckFailedMachine, oldest, deleteMachineWithMachineAnnotation,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithMachineAnnotation},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result := getMachinesToDeletePrioritized(test.machines, test.diff, unhealthyFirstDeletePriority)
+			g.Expect(result).To(Equal(test.expect))
+		})
+	}
+}
+
+func TestMachineDeleteFailureDomainSpread(t *testing.T) {
+	nodeRef := &corev1.ObjectReference{Name: "some-node"}
+	newMachine := func(name, failureDomain string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       clusterv1.MachineSpec{FailureDomain: &failureDomain},
+			Status:     clusterv1.MachineStatus{NodeRef: nodeRef},
+		}
+	}
+	a1 := newMachine("a1", "a")
+	b1 := newMachine("b1", "b")
+	b2 := newMachine("b2", "b")
+	b3 := newMachine("b3", "b")
+	deleteMachineWithMachineAnnotation := newMachine("a2", "a")
+	deleteMachineWithMachineAnnotation.Annotations = map[string]string{clusterv1.DeleteMachineAnnotation: ""}
+
+	tests := []struct {
+		desc                 string
+		machines             []*clusterv1.Machine
+		diff                 int
+		expectFailureDomains []string
+	}{
+		{
+			desc:                 "func=randomDeletePolicy, diff=1 (failure domain with most machines first)",
+			diff:                 1,
+			machines:             []*clusterv1.Machine{a1, b1, b2},
+			expectFailureDomains: []string{"b"},
+		},
+		{
+			desc:                 "func=randomDeletePolicy, diff=2 (failure domain with most machines first)",
+			diff:                 2,
+			machines:             []*clusterv1.Machine{a1, b1, b2, b3},
+			expectFailureDomains: []string{"b", "b"},
+		},
+		{
+			desc:                 "func=randomDeletePolicy, diff=1 (DeleteMachineAnnotation before failure domain spread)",
+			diff:                 1,
+			machines:             []*clusterv1.Machine{b1, b2, deleteMachineWithMachineAnnotation},
+			expectFailureDomains: []string{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result := getMachinesToDeletePrioritized(test.machines, test.diff, randomDeletePolicy)
+			failureDomains := make([]string, 0, len(result))
+			for _, m := range result {
+				failureDomains = append(failureDomains, *m.Spec.FailureDomain)
+			}
+			g.Expect(failureDomains).To(Equal(test.expectFailureDomains))
+		})
+	}
+}
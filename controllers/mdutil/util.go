@@ -27,6 +27,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -36,6 +37,7 @@ import (
 	"k8s.io/utils/integer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conversion"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 )
 
 const (
@@ -125,7 +127,7 @@ func MaxRevision(allMSs []*clusterv1.MachineSet, logger logr.Logger) int64 {
 		if v, err := Revision(ms); err != nil {
 			// Skip the machine sets when it failed to parse their revision information
 			logger.Error(err, "Couldn't parse revision for machine set, deployment controller will skip it when reconciling revisions",
-				"machineset", ms.Name)
+				logutil.MachineSetKey, logutil.KObj(ms))
 		} else if v > max {
 			max = v
 		}
@@ -194,7 +196,7 @@ func getMaxReplicasAnnotation(ms *clusterv1.MachineSet, logger logr.Logger) (int
 }
 
 func getIntFromAnnotation(ms *clusterv1.MachineSet, annotationKey string, logger logr.Logger) (int32, bool) {
-	logger = logger.WithValues("machineset", ms.Name, "annotationKey", annotationKey)
+	logger = logger.WithValues(logutil.MachineSetKey, logutil.KObj(ms), "annotationKey", annotationKey)
 
 	annotationValue, ok := ms.Annotations[annotationKey]
 	if !ok {
@@ -211,7 +213,7 @@ func getIntFromAnnotation(ms *clusterv1.MachineSet, annotationKey string, logger
 // SetNewMachineSetAnnotations sets new machine set's annotations appropriately by updating its revision and
 // copying required deployment annotations to it; it returns true if machine set's annotation is changed.
 func SetNewMachineSetAnnotations(deployment *clusterv1.MachineDeployment, newMS *clusterv1.MachineSet, newRevision string, exists bool, logger logr.Logger) bool {
-	logger = logger.WithValues("machineset", newMS.Name)
+	logger = logger.WithValues(logutil.MachineSetKey, logutil.KObj(newMS))
 
 	// First, copy deployment's annotations (except for apply and revision annotations)
 	annotationChanged := copyDeploymentAnnotationsToMachineSet(deployment, newMS)
@@ -421,9 +423,14 @@ func EqualMachineTemplate(template1, template2 *clusterv1.MachineTemplateSpec) b
 }
 
 // FindNewMachineSet returns the new MS this given deployment targets (the one with the same machine template).
-func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) *clusterv1.MachineSet {
+// Note: If the reconciliation time is after the deployment's rolloutAfter time, a MS has to be newer than
+// rolloutAfter to be considered as matching the deployment's intent.
+func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet, reconciliationTime *metav1.Time) *clusterv1.MachineSet {
 	sort.Sort(MachineSetsByCreationTimestamp(msList))
 	for i := range msList {
+		if ShouldRolloutAfter(deployment, reconciliationTime) && msList[i].CreationTimestamp.Before(deployment.Spec.RolloutAfter) {
+			continue
+		}
 		if EqualMachineTemplate(&msList[i].Spec.Template, &deployment.Spec.Template) {
 			// In rare cases, such as after cluster upgrades, Deployment may end up with
 			// having more than one new MachineSets that have the same template,
@@ -440,10 +447,10 @@ func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*cluste
 // Returns two list of machine sets
 //  - the first contains all old machine sets with all non-zero replicas
 //  - the second contains all old machine sets
-func FindOldMachineSets(deployment *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) ([]*clusterv1.MachineSet, []*clusterv1.MachineSet) {
+func FindOldMachineSets(deployment *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet, reconciliationTime *metav1.Time) ([]*clusterv1.MachineSet, []*clusterv1.MachineSet) {
 	var requiredMSs []*clusterv1.MachineSet
 	allMSs := make([]*clusterv1.MachineSet, 0, len(msList))
-	newMS := FindNewMachineSet(deployment, msList)
+	newMS := FindNewMachineSet(deployment, msList, reconciliationTime)
 	for _, ms := range msList {
 		// Filter out new machine set
 		if newMS != nil && ms.UID == newMS.UID {
@@ -457,6 +464,53 @@ func FindOldMachineSets(deployment *clusterv1.MachineDeployment, msList []*clust
 	return requiredMSs, allMSs
 }
 
+// ShouldRolloutAfter returns true if the deployment's rolloutAfter time is set and has passed at the reconciliation time.
+func ShouldRolloutAfter(deployment *clusterv1.MachineDeployment, reconciliationTime *metav1.Time) bool {
+	if deployment.Spec.RolloutAfter == nil || reconciliationTime == nil {
+		return false
+	}
+	return !reconciliationTime.Before(deployment.Spec.RolloutAfter)
+}
+
+// FindMachineSetForRevision returns the machine set with the given revision; when the revision is 0, the machine set
+// with the revision preceding the latest one is returned.
+func FindMachineSetForRevision(toRevision int64, allMSs []*clusterv1.MachineSet) (*clusterv1.MachineSet, error) {
+	var (
+		latestMachineSet   *clusterv1.MachineSet
+		latestRevision     = int64(-1)
+		previousMachineSet *clusterv1.MachineSet
+		previousRevision   = int64(-1)
+	)
+	for _, ms := range allMSs {
+		if v, err := Revision(ms); err == nil {
+			if toRevision == 0 {
+				if latestRevision < v {
+					// newest one we've seen so far
+					previousRevision = latestRevision
+					previousMachineSet = latestMachineSet
+					latestRevision = v
+					latestMachineSet = ms
+				} else if previousRevision < v {
+					// second newest one we've seen so far
+					previousRevision = v
+					previousMachineSet = ms
+				}
+			} else if toRevision == v {
+				return ms, nil
+			}
+		}
+	}
+
+	if toRevision > 0 {
+		return nil, errors.Errorf("unable to find specified MachineDeployment revision: %v", toRevision)
+	}
+
+	if previousMachineSet == nil {
+		return nil, errors.Errorf("no rollout history found for MachineDeployment")
+	}
+	return previousMachineSet, nil
+}
+
 // GetReplicaCountForMachineSets returns the sum of Replicas of the given machine sets.
 func GetReplicaCountForMachineSets(machineSets []*clusterv1.MachineSet) int32 {
 	totalReplicas := int32(0)
@@ -522,6 +576,15 @@ func IsRollingUpdate(deployment *clusterv1.MachineDeployment) bool {
 	return deployment.Spec.Strategy.Type == clusterv1.RollingUpdateMachineDeploymentStrategyType
 }
 
+// DeletePolicy returns the delete policy of the deployment, if any. The delete policy is defined together with
+// the rolling update parameters, which are usually not set when using the OnDelete strategy.
+func DeletePolicy(deployment *clusterv1.MachineDeployment) *string {
+	if deployment.Spec.Strategy == nil || deployment.Spec.Strategy.RollingUpdate == nil {
+		return nil
+	}
+	return deployment.Spec.Strategy.RollingUpdate.DeletePolicy
+}
+
 // DeploymentComplete considers a deployment to be complete once all of its desired replicas
 // are updated and available, and no old machines are running.
 func DeploymentComplete(deployment *clusterv1.MachineDeployment, newStatus *clusterv1.MachineDeploymentStatus) bool {
@@ -749,6 +812,27 @@ func ComputeSpewHash(template *clusterv1.MachineTemplateSpec) (uint32, error) {
 	return machineTemplateSpecHasher.Sum32(), nil
 }
 
+// ComputeMachineSetHash computes the hash of the machine set for the machine template of a deployment; if the
+// deployment's rolloutAfter time has passed at the reconciliation time, rolloutAfter is part of the hash, so the
+// machine set is distinct from the ones created before rolloutAfter with the same machine template.
+func ComputeMachineSetHash(deployment *clusterv1.MachineDeployment, reconciliationTime *metav1.Time) (uint32, error) {
+	if !ShouldRolloutAfter(deployment, reconciliationTime) {
+		return ComputeSpewHash(&deployment.Spec.Template)
+	}
+
+	machineSetHasher := fnv.New32a()
+	if err := SpewHashObject(machineSetHasher, struct {
+		Template     clusterv1.MachineTemplateSpec
+		RolloutAfter metav1.Time
+	}{
+		Template:     deployment.Spec.Template,
+		RolloutAfter: *deployment.Spec.RolloutAfter,
+	}); err != nil {
+		return 0, err
+	}
+	return machineSetHasher.Sum32(), nil
+}
+
 // GetDeletingMachineCount gets the number of machines that are in the process of being deleted
 // in a machineList.
 func GetDeletingMachineCount(machineList *clusterv1.MachineList) int32 {
@@ -287,11 +287,18 @@ func TestFindNewMachineSet(t *testing.T) {
 	}
 	oldMS.Status.FullyLabeledReplicas = *(oldMS.Spec.Replicas)
 
+	muchLater := metav1.Time{Time: now.Add(2 * time.Minute)}
+	deploymentWithRolloutAfterLater := *deployment.DeepCopy()
+	deploymentWithRolloutAfterLater.Spec.RolloutAfter = &later
+	deploymentWithRolloutAfterMuchLater := *deployment.DeepCopy()
+	deploymentWithRolloutAfterMuchLater.Spec.RolloutAfter = &muchLater
+
 	tests := []struct {
-		Name       string
-		deployment clusterv1.MachineDeployment
-		msList     []*clusterv1.MachineSet
-		expected   *clusterv1.MachineSet
+		Name               string
+		deployment         clusterv1.MachineDeployment
+		msList             []*clusterv1.MachineSet
+		reconciliationTime *metav1.Time
+		expected           *clusterv1.MachineSet
 	}{
 		{
 			Name:       "Get new MachineSet with the same template as Deployment spec but different machine-template-hash value",
@@ -311,13 +318,34 @@ func TestFindNewMachineSet(t *testing.T) {
 			msList:     []*clusterv1.MachineSet{&oldMS},
 			expected:   nil,
 		},
+		{
+			Name:               "Get the oldest new MachineSet when rolloutAfter is in the future",
+			deployment:         deploymentWithRolloutAfterMuchLater,
+			msList:             []*clusterv1.MachineSet{&newMS, &oldMS, &newMSDup},
+			reconciliationTime: &later,
+			expected:           &newMSDup,
+		},
+		{
+			Name:               "Get the new MachineSet created after rolloutAfter when rolloutAfter has passed",
+			deployment:         deploymentWithRolloutAfterLater,
+			msList:             []*clusterv1.MachineSet{&newMS, &oldMS, &newMSDup},
+			reconciliationTime: &muchLater,
+			expected:           &newMS,
+		},
+		{
+			Name:               "Get nil new MachineSet when rolloutAfter has passed and all MachineSets were created before it",
+			deployment:         deploymentWithRolloutAfterMuchLater,
+			msList:             []*clusterv1.MachineSet{&newMS, &oldMS, &newMSDup},
+			reconciliationTime: &muchLater,
+			expected:           nil,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			ms := FindNewMachineSet(&test.deployment, test.msList)
+			ms := FindNewMachineSet(&test.deployment, test.msList, test.reconciliationTime)
 			g.Expect(ms).To(Equal(test.expected))
 		})
 	}
@@ -401,7 +429,7 @@ func TestFindOldMachineSets(t *testing.T) {
 		t.Run(test.Name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			requireMS, allMS := FindOldMachineSets(&test.deployment, test.msList)
+			requireMS, allMS := FindOldMachineSets(&test.deployment, test.msList, nil)
 			g.Expect(allMS).To(ConsistOf(test.expected))
 			// MSs are getting filtered correctly by ms.spec.replicas
 			g.Expect(requireMS).To(ConsistOf(test.expectedRequire))
@@ -409,6 +437,93 @@ func TestFindOldMachineSets(t *testing.T) {
 	}
 }
 
+func TestFindMachineSetForRevision(t *testing.T) {
+	deployment := generateDeployment("nginx")
+	ms1 := generateMS(deployment)
+	ms1.Annotations = map[string]string{clusterv1.RevisionAnnotation: "1"}
+	ms2 := generateMS(deployment)
+	ms2.Annotations = map[string]string{clusterv1.RevisionAnnotation: "2"}
+	ms3 := generateMS(deployment)
+	ms3.Annotations = map[string]string{clusterv1.RevisionAnnotation: "3"}
+
+	tests := []struct {
+		name       string
+		toRevision int64
+		msList     []*clusterv1.MachineSet
+		expected   *clusterv1.MachineSet
+		expectErr  bool
+	}{
+		{
+			name:       "Get the MachineSet with the given revision",
+			toRevision: 1,
+			msList:     []*clusterv1.MachineSet{&ms1, &ms3, &ms2},
+			expected:   &ms1,
+		},
+		{
+			name:       "Get the MachineSet with the previous revision when revision is 0",
+			toRevision: 0,
+			msList:     []*clusterv1.MachineSet{&ms1, &ms3, &ms2},
+			expected:   &ms2,
+		},
+		{
+			name:       "Return error when the revision does not exist",
+			toRevision: 4,
+			msList:     []*clusterv1.MachineSet{&ms1, &ms3, &ms2},
+			expectErr:  true,
+		},
+		{
+			name:       "Return error when there is no previous revision",
+			toRevision: 0,
+			msList:     []*clusterv1.MachineSet{&ms3},
+			expectErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms, err := FindMachineSetForRevision(test.toRevision, test.msList)
+			if test.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ms).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestComputeMachineSetHash(t *testing.T) {
+	g := NewWithT(t)
+
+	now := metav1.Now()
+	later := metav1.Time{Time: now.Add(time.Minute)}
+
+	deployment := generateDeployment("nginx")
+	templateHash, err := ComputeSpewHash(&deployment.Spec.Template)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The hash of the machine template is used when rolloutAfter is not set or in the future.
+	hash, err := ComputeMachineSetHash(&deployment, &now)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hash).To(Equal(templateHash))
+
+	deployment.Spec.RolloutAfter = &later
+	hash, err = ComputeMachineSetHash(&deployment, &now)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hash).To(Equal(templateHash))
+
+	// The hash changes once rolloutAfter has passed, and is stable across reconciliations.
+	hash, err = ComputeMachineSetHash(&deployment, &later)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hash).NotTo(Equal(templateHash))
+
+	laterHash, err := ComputeMachineSetHash(&deployment, &metav1.Time{Time: later.Add(time.Minute)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(laterHash).To(Equal(hash))
+}
+
 func TestGetReplicaCountForMachineSets(t *testing.T) {
 	ms1 := generateMS(generateDeployment("foo"))
 	*(ms1.Spec.Replicas) = 1
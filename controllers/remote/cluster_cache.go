@@ -17,8 +17,10 @@ limitations under the License.
 package remote
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -51,6 +53,10 @@ const (
 	clusterCacheControllerName    = "cluster-cache-tracker"
 )
 
+// errRESTConfigChanged is returned by the health check when the REST config for a cluster no longer
+// matches the one its clusterAccessor was created with, e.g. after the kubeconfig secret was rotated.
+var errRESTConfigChanged = errors.New("REST config for remote cluster has changed")
+
 // ClusterCacheTracker manages client caches for workload clusters.
 type ClusterCacheTracker struct {
 	log                   logr.Logger
@@ -124,6 +130,24 @@ type clusterAccessor struct {
 	watches sets.String
 }
 
+// TrackedClusters returns the list of clusters for which a clusterAccessor exists, sorted by namespace and name.
+func (t *ClusterCacheTracker) TrackedClusters() []client.ObjectKey {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	clusters := make([]client.ObjectKey, 0, len(t.clusterAccessors))
+	for cluster := range t.clusterAccessors {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Namespace != clusters[j].Namespace {
+			return clusters[i].Namespace < clusters[j].Namespace
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters
+}
+
 // clusterAccessorExists returns true if a clusterAccessor exists for cluster.
 func (t *ClusterCacheTracker) clusterAccessorExists(cluster client.ObjectKey) bool {
 	t.lock.RLock()
@@ -147,6 +171,7 @@ func (t *ClusterCacheTracker) getClusterAccessorLH(ctx context.Context, cluster
 	}
 
 	t.clusterAccessors[cluster] = a
+	trackedClustersGauge.Set(float64(len(t.clusterAccessors)))
 
 	return a, nil
 }
@@ -240,6 +265,7 @@ func (t *ClusterCacheTracker) deleteAccessor(cluster client.ObjectKey) {
 	t.log.V(4).Info("Cache stopped", "cluster", cluster.String())
 
 	delete(t.clusterAccessors, cluster)
+	trackedClustersGauge.Set(float64(len(t.clusterAccessors)))
 }
 
 // Watcher is a scoped-down interface from Controller that only knows how to watch.
@@ -327,6 +353,8 @@ func (h *healthCheckInput) setDefaults() {
 // healthCheckCluster will poll the cluster's API at the path given and, if there are
 // `unhealthyThreshold` consecutive failures, will deem the cluster unhealthy.
 // Once the cluster is deemed unhealthy, the cluster's cache is stopped and removed.
+// The cache is also stopped and removed if the cluster's kubeconfig secret no longer matches
+// the REST config the cache was created with, so that it is re-created on next access.
 func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *healthCheckInput) {
 	// populate optional params for healthCheckInput
 	in.setDefaults()
@@ -366,11 +394,19 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 			return true, nil
 		}
 
+		// If the kubeconfig secret has been rotated or now points to a different apiserver endpoint,
+		// the cache has to be re-created. Errors retrieving the secret are ignored here and
+		// surface through the health check itself.
+		if config, err := RESTConfig(ctx, clusterCacheControllerName, t.client, in.cluster); err == nil && restConfigChanged(in.cfg, config) {
+			return false, errRESTConfigChanged
+		}
+
 		// An error here means there was either an issue connecting or the API returned an error.
 		// If no error occurs, reset the unhealthy counter.
 		_, err := restClient.Get().AbsPath(in.path).Timeout(in.requestTimeout).DoRaw(ctx)
 		if err != nil {
 			unhealthyCount++
+			healthCheckFailuresTotal.WithLabelValues(in.cluster.Namespace, in.cluster.Name).Inc()
 		} else {
 			unhealthyCount = 0
 		}
@@ -389,7 +425,20 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 	// NB. we are ignoring ErrWaitTimeout because this error happens when the channel is close, that in this case
 	// happens when the cache is explicitly stopped.
 	if err != nil && err != wait.ErrWaitTimeout {
-		t.log.Error(err, "Error health checking cluster", "cluster", in.cluster.String())
+		if errors.Is(err, errRESTConfigChanged) {
+			t.log.Info("REST config changed, re-creating cluster cache on next access", "cluster", in.cluster.String())
+		} else {
+			t.log.Error(err, "Error health checking cluster", "cluster", in.cluster.String())
+		}
 		t.deleteAccessor(in.cluster)
 	}
 }
+
+// restConfigChanged returns true if the endpoint or the credentials of current differ from the ones in previous.
+func restConfigChanged(previous, current *rest.Config) bool {
+	return previous.Host != current.Host ||
+		previous.BearerToken != current.BearerToken ||
+		!bytes.Equal(previous.CAData, current.CAData) ||
+		!bytes.Equal(previous.CertData, current.CertData) ||
+		!bytes.Equal(previous.KeyData, current.KeyData)
+}
@@ -32,6 +32,8 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -193,5 +195,100 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 			// This should succeed after N consecutive failed requests.
 			g.Eventually(func() bool { return cct.clusterAccessorExists(testClusterKey) }, 5*time.Second, 1*time.Second).Should(BeFalse())
 		})
+
+		t.Run("with a rotated kubeconfig", func(t *testing.T) {
+			g := NewWithT(t)
+			ns := setup(t, g)
+			defer teardown(t, g, ns)
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			go cct.healthCheckCluster(ctx, &healthCheckInput{
+				cluster:            testClusterKey,
+				cfg:                env.Config,
+				interval:           testPollInterval,
+				requestTimeout:     testPollTimeout,
+				unhealthyThreshold: testUnhealthyThreshold,
+				path:               "/",
+			})
+
+			// Make sure the health check passes before the kubeconfig is rotated.
+			g.Consistently(func() bool { return cct.clusterAccessorExists(testClusterKey) }, 2*time.Second, 500*time.Millisecond).Should(BeTrue())
+
+			// Point the kubeconfig secret to a different apiserver endpoint.
+			config := rest.CopyConfig(env.Config)
+			config.Host = "https://127.0.0.1:6443"
+			kubeconfigSecret, err := secret.GetFromNamespacedName(ctx, k8sClient, testClusterKey, secret.Kubeconfig)
+			g.Expect(err).NotTo(HaveOccurred())
+			kubeconfigSecret.Data[secret.KubeconfigDataName] = kubeconfig.FromEnvTestConfig(config, &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: testClusterKey.Name}})
+			g.Expect(k8sClient.Update(ctx, kubeconfigSecret)).To(Succeed())
+
+			// The clusterAccessor should be removed so it gets re-created with the new config.
+			g.Eventually(func() bool { return cct.clusterAccessorExists(testClusterKey) }, 5*time.Second, 1*time.Second).Should(BeFalse())
+		})
 	})
 }
+
+func TestRESTConfigChanged(t *testing.T) {
+	previous := &rest.Config{
+		Host:        "https://10.0.0.1:6443",
+		BearerToken: "token",
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   []byte("ca"),
+			CertData: []byte("cert"),
+			KeyData:  []byte("key"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*rest.Config)
+		want   bool
+	}{
+		{
+			name:   "unchanged",
+			mutate: func(*rest.Config) {},
+			want:   false,
+		},
+		{
+			name:   "unrelated field changed",
+			mutate: func(c *rest.Config) { c.UserAgent = "other" },
+			want:   false,
+		},
+		{
+			name:   "host changed",
+			mutate: func(c *rest.Config) { c.Host = "https://10.0.0.2:6443" },
+			want:   true,
+		},
+		{
+			name:   "bearer token changed",
+			mutate: func(c *rest.Config) { c.BearerToken = "rotated" },
+			want:   true,
+		},
+		{
+			name:   "CA data changed",
+			mutate: func(c *rest.Config) { c.CAData = []byte("rotated") },
+			want:   true,
+		},
+		{
+			name:   "client certificate changed",
+			mutate: func(c *rest.Config) { c.CertData = []byte("rotated") },
+			want:   true,
+		},
+		{
+			name:   "client key changed",
+			mutate: func(c *rest.Config) { c.KeyData = []byte("rotated") },
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			current := rest.CopyConfig(previous)
+			tt.mutate(current)
+			g.Expect(restConfigChanged(previous, current)).To(Equal(tt.want))
+		})
+	}
+}
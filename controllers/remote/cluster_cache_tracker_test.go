@@ -226,3 +226,22 @@ func cleanupTestClusters(ctx context.Context, c client.Client) error {
 	}
 	return nil
 }
+
+func TestClusterCacheTrackerTrackedClusters(t *testing.T) {
+	g := NewWithT(t)
+
+	cct := &ClusterCacheTracker{
+		clusterAccessors: map[client.ObjectKey]*clusterAccessor{
+			{Namespace: "ns2", Name: "cluster1"}: {},
+			{Namespace: "ns1", Name: "cluster2"}: {},
+			{Namespace: "ns1", Name: "cluster1"}: {},
+		},
+	}
+
+	g.Expect(cct.TrackedClusters()).To(Equal([]client.ObjectKey{
+		{Namespace: "ns1", Name: "cluster1"},
+		{Namespace: "ns1", Name: "cluster2"},
+		{Namespace: "ns2", Name: "cluster1"},
+	}))
+	g.Expect((&ClusterCacheTracker{}).TrackedClusters()).To(BeEmpty())
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// trackedClustersGauge reports the number of clusters for which a clusterAccessor exists.
+	trackedClustersGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "capi_cluster_cache_tracker_tracked_clusters",
+		Help: "Number of workload clusters with a cached client in the ClusterCacheTracker.",
+	})
+
+	// healthCheckFailuresTotal counts the failed health probes against workload cluster apiservers.
+	healthCheckFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capi_cluster_cache_tracker_health_check_failures_total",
+		Help: "Total number of failed health probes against workload cluster apiservers.",
+	}, []string{"namespace", "cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		trackedClustersGauge,
+		healthCheckFailuresTotal,
+	)
+}
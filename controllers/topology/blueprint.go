@@ -25,7 +25,6 @@ import (
 	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
 	"sigs.k8s.io/cluster-api/util/patch"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // getBlueprint gets a ClusterBlueprint with the ClusterClass and the referenced templates to be used for a managed Cluster topology.
@@ -36,14 +35,34 @@ func (r *ClusterReconciler) getBlueprint(ctx context.Context, cluster *clusterv1
 		Topology:           cluster.Spec.Topology,
 		ClusterClass:       &clusterv1.ClusterClass{},
 		MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{},
+		MachinePools:       map[string]*scope.MachinePoolBlueprint{},
 	}
 
 	// Get ClusterClass.
-	key := client.ObjectKey{Name: cluster.Spec.Topology.Class, Namespace: cluster.Namespace}
+	// NOTE: The ClusterClass can be in another namespace only if its namespace is allowed to provide ClusterClasses
+	// to other namespaces; in this case the templates are cloned into the namespace of the Cluster as usual.
+	key := cluster.GetClusterClassKey()
+	if !clusterv1.IsClusterClassNamespaceAllowed(cluster.Namespace, key.Namespace) {
+		return nil, errors.Errorf("failed to retrieve ClusterClass/%s: ClusterClasses in namespace %q cannot be used by Clusters in other namespaces", key, key.Namespace)
+	}
 	if err := r.Client.Get(ctx, key, blueprint.ClusterClass); err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve ClusterClass/%s", cluster.Spec.Topology.Class)
+		return nil, errors.Wrapf(err, "failed to retrieve ClusterClass/%s", key)
+	}
+
+	// Use the blueprint from the cache, if still valid for the current ClusterClass and templates.
+	if cached, ok := r.blueprintCache.Get(ctx, r.UnstructuredCachingClient, blueprint.ClusterClass); ok {
+		cached.Topology = cluster.Spec.Topology
+		return cached, nil
 	}
 
+	// Cache the blueprint if it has been successfully computed.
+	// NOTE: This is deferred before patching the ClusterClass, so the blueprint is cached with the latest ClusterClass generation.
+	defer func() {
+		if reterr == nil {
+			r.blueprintCache.Set(blueprint)
+		}
+	}()
+
 	// We use the patchHelper to patch potential changes to the ObjectReferences in ClusterClass.
 	patchHelper, err := patch.NewHelper(blueprint.ClusterClass, r.Client)
 	if err != nil {
@@ -80,6 +99,9 @@ func (r *ClusterReconciler) getBlueprint(ctx context.Context, cluster *clusterv1
 		}
 	}
 
+	// Get the MachineHealthCheck for the controlPlane, if defined.
+	blueprint.ControlPlane.MachineHealthCheck = blueprint.ClusterClass.Spec.ControlPlane.MachineHealthCheck
+
 	// Loop over the machine deployments classes in ClusterClass
 	// and fetch the related templates.
 	for _, machineDeploymentClass := range blueprint.ClusterClass.Spec.Workers.MachineDeployments {
@@ -102,8 +124,43 @@ func (r *ClusterReconciler) getBlueprint(ctx context.Context, cluster *clusterv1
 			return nil, errors.Wrapf(err, "failed to get bootstrap machine template for %s, MachineDeployment class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machineDeploymentClass.Class)
 		}
 
+		// Get the MachineHealthCheck for the MachineDeployment, if defined.
+		machineDeploymentBlueprint.MachineHealthCheck = machineDeploymentClass.MachineHealthCheck
+
+		// Get the rollout strategy for the MachineDeployment, if defined.
+		machineDeploymentBlueprint.RolloutStrategy = machineDeploymentClass.RolloutStrategy
+
+		// Get the default node labels and taints for the MachineDeployment, if defined.
+		machineDeploymentBlueprint.NodeLabels = machineDeploymentClass.Template.NodeLabels
+		machineDeploymentBlueprint.NodeTaints = machineDeploymentClass.Template.NodeTaints
+
 		blueprint.MachineDeployments[machineDeploymentClass.Class] = machineDeploymentBlueprint
 	}
 
+	// Loop over the machine pools classes in ClusterClass
+	// and fetch the related templates.
+	for _, machinePoolClass := range blueprint.ClusterClass.Spec.Workers.MachinePools {
+		machinePoolBlueprint := &scope.MachinePoolBlueprint{}
+
+		// Make sure to copy the metadata from the blueprint, which is later layered
+		// with the additional metadata defined in the Cluster's topology section
+		// for the MachinePool that is created or updated.
+		machinePoolClass.Template.Metadata.DeepCopyInto(&machinePoolBlueprint.Metadata)
+
+		// Get the infrastructure machine pool template.
+		machinePoolBlueprint.InfrastructureMachinePoolTemplate, err = r.getReference(ctx, machinePoolClass.Template.Infrastructure.Ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get infrastructure machine pool template for %s, MachinePool class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machinePoolClass.Class)
+		}
+
+		// Get the bootstrap config template.
+		machinePoolBlueprint.BootstrapTemplate, err = r.getReference(ctx, machinePoolClass.Template.Bootstrap.Ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get bootstrap config template for %s, MachinePool class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machinePoolClass.Class)
+		}
+
+		blueprint.MachinePools[machinePoolClass.Class] = machinePoolBlueprint
+	}
+
 	return blueprint, nil
 }
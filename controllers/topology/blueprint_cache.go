@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// blueprintCache caches the ClusterBlueprints computed for ClusterClasses, so Clusters using the same ClusterClass
+// do not have to read and convert the ClusterClass references and templates on every reconcile.
+// A cached blueprint is valid as long as the ClusterClass generation does not change and all the referenced templates
+// keep the same resourceVersion; both are checked using cached clients, thus without hitting the API server.
+// NOTE: The blueprint is mutated while computing the desired state, so the blueprintCache always stores and
+// returns copies of the blueprint.
+type blueprintCache struct {
+	lock    sync.RWMutex
+	entries map[client.ObjectKey]*blueprintCacheEntry
+}
+
+// blueprintCacheEntry is a blueprint computed for a specific generation of a ClusterClass.
+type blueprintCacheEntry struct {
+	uid        types.UID
+	generation int64
+	blueprint  *scope.ClusterBlueprint
+}
+
+func newBlueprintCache() *blueprintCache {
+	return &blueprintCache{
+		entries: map[client.ObjectKey]*blueprintCacheEntry{},
+	}
+}
+
+// Get returns a copy of the blueprint cached for the given ClusterClass, if still valid.
+// templateReader is used to check that the templates referenced by the blueprint did not change.
+// Get is a no-op if the blueprintCache is nil.
+func (c *blueprintCache) Get(ctx context.Context, templateReader client.Reader, clusterClass *clusterv1.ClusterClass) (*scope.ClusterBlueprint, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.lock.RLock()
+	entry, ok := c.entries[client.ObjectKeyFromObject(clusterClass)]
+	c.lock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	// The blueprint is not valid anymore if the ClusterClass has been changed or re-created.
+	if entry.uid != clusterClass.UID || entry.generation != clusterClass.Generation {
+		c.Delete(clusterClass)
+		return nil, false
+	}
+
+	// The blueprint is not valid anymore if any of the referenced templates has been changed.
+	for _, template := range blueprintTemplates(entry.blueprint) {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(template.GroupVersionKind())
+		if err := templateReader.Get(ctx, client.ObjectKeyFromObject(template), current); err != nil || current.GetResourceVersion() != template.GetResourceVersion() {
+			c.Delete(clusterClass)
+			return nil, false
+		}
+	}
+
+	return entry.blueprint.DeepCopy(), true
+}
+
+// Set caches a copy of the blueprint for the ClusterClass in the blueprint.
+// NOTE: The cached blueprint does not include the Cluster topology.
+// Set is a no-op if the blueprintCache is nil.
+func (c *blueprintCache) Set(blueprint *scope.ClusterBlueprint) {
+	if c == nil {
+		return
+	}
+
+	entry := &blueprintCacheEntry{
+		uid:        blueprint.ClusterClass.UID,
+		generation: blueprint.ClusterClass.Generation,
+		blueprint:  blueprint.DeepCopy(),
+	}
+	entry.blueprint.Topology = nil
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries[client.ObjectKeyFromObject(blueprint.ClusterClass)] = entry
+}
+
+// Delete removes the blueprint cached for the given ClusterClass, if any.
+// Delete is a no-op if the blueprintCache is nil.
+func (c *blueprintCache) Delete(clusterClass client.Object) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, client.ObjectKeyFromObject(clusterClass))
+}
+
+// blueprintTemplates returns all the templates referenced by a blueprint.
+func blueprintTemplates(blueprint *scope.ClusterBlueprint) []*unstructured.Unstructured {
+	templates := []*unstructured.Unstructured{blueprint.InfrastructureClusterTemplate}
+	if blueprint.ControlPlane != nil {
+		templates = append(templates, blueprint.ControlPlane.Template)
+		if blueprint.ControlPlane.InfrastructureMachineTemplate != nil {
+			templates = append(templates, blueprint.ControlPlane.InfrastructureMachineTemplate)
+		}
+	}
+	for _, md := range blueprint.MachineDeployments {
+		templates = append(templates, md.InfrastructureMachineTemplate, md.BootstrapTemplate)
+	}
+	for _, mp := range blueprint.MachinePools {
+		templates = append(templates, mp.InfrastructureMachinePoolTemplate, mp.BootstrapTemplate)
+	}
+	return templates
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/internal/testtypes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBlueprintCache(t *testing.T) {
+	g := NewWithT(t)
+
+	infrastructureClusterTemplate := testtypes.NewInfrastructureClusterTemplateBuilder(metav1.NamespaceDefault, "infraclustertemplate1").
+		Build()
+	controlPlaneTemplate := testtypes.NewControlPlaneTemplateBuilder(metav1.NamespaceDefault, "controlplanetemplate1").
+		Build()
+	clusterClass := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(infrastructureClusterTemplate).
+		WithControlPlaneTemplate(controlPlaneTemplate).
+		Build()
+	clusterClass.UID = "uid1"
+	clusterClass.Generation = 1
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(fakeScheme).
+		WithObjects(infrastructureClusterTemplate, controlPlaneTemplate).
+		Build()
+
+	// Read the templates back, so they have the resourceVersion set by the fake client.
+	getTemplate := func(template *unstructured.Unstructured) *unstructured.Unstructured {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(template.GroupVersionKind())
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(template), got)).To(Succeed())
+		return got
+	}
+	blueprint := &scope.ClusterBlueprint{
+		Topology:                      &clusterv1.Topology{Class: clusterClass.Name},
+		ClusterClass:                  clusterClass,
+		InfrastructureClusterTemplate: getTemplate(infrastructureClusterTemplate),
+		ControlPlane: &scope.ControlPlaneBlueprint{
+			Template: getTemplate(controlPlaneTemplate),
+		},
+		MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{},
+	}
+
+	t.Run("A nil cache is a no-op", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var c *blueprintCache
+		c.Set(blueprint)
+		_, ok := c.Get(ctx, fakeClient, clusterClass)
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("Returns a copy of the cached blueprint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newBlueprintCache()
+		_, ok := c.Get(ctx, fakeClient, clusterClass)
+		g.Expect(ok).To(BeFalse())
+
+		c.Set(blueprint)
+		got, ok := c.Get(ctx, fakeClient, clusterClass)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(got.Topology).To(BeNil())
+		g.Expect(got.ClusterClass).To(Equal(blueprint.ClusterClass))
+		g.Expect(got.InfrastructureClusterTemplate).To(Equal(blueprint.InfrastructureClusterTemplate))
+		g.Expect(got.ControlPlane.Template).To(Equal(blueprint.ControlPlane.Template))
+
+		// Changes to the returned blueprint do not affect the cache.
+		got.InfrastructureClusterTemplate.SetLabels(map[string]string{"foo": "bar"})
+		got, ok = c.Get(ctx, fakeClient, clusterClass)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(got.InfrastructureClusterTemplate.GetLabels()).To(BeEmpty())
+	})
+
+	t.Run("Invalidates the cached blueprint when the ClusterClass changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newBlueprintCache()
+		c.Set(blueprint)
+
+		changedClusterClass := clusterClass.DeepCopy()
+		changedClusterClass.Generation = 2
+		_, ok := c.Get(ctx, fakeClient, changedClusterClass)
+		g.Expect(ok).To(BeFalse())
+
+		// The entry has been removed.
+		_, ok = c.Get(ctx, fakeClient, clusterClass)
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("Invalidates the cached blueprint when the ClusterClass is re-created", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newBlueprintCache()
+		c.Set(blueprint)
+
+		recreatedClusterClass := clusterClass.DeepCopy()
+		recreatedClusterClass.UID = "uid2"
+		_, ok := c.Get(ctx, fakeClient, recreatedClusterClass)
+		g.Expect(ok).To(BeFalse())
+	})
+
+	t.Run("Invalidates the cached blueprint when a template changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newBlueprintCache()
+		c.Set(blueprint)
+
+		changedTemplate := getTemplate(controlPlaneTemplate)
+		changedTemplate.SetLabels(map[string]string{"foo": "bar"})
+		g.Expect(fakeClient.Update(ctx, changedTemplate)).To(Succeed())
+
+		_, ok := c.Get(ctx, fakeClient, clusterClass)
+		g.Expect(ok).To(BeFalse())
+	})
+}
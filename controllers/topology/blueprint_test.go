@@ -20,14 +20,14 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/internal/fakeclient"
 	"sigs.k8s.io/cluster-api/internal/testtypes"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestGetBlueprint(t *testing.T) {
@@ -39,10 +39,6 @@ func TestGetBlueprint(t *testing.T) {
 		testtypes.GenericBootstrapConfigTemplateCRD,
 	}
 
-	// ignoreResourceVersion is an option to pass to cmpopts to ignore this field which is set by the fakeClient
-	// TODO: Make composable version of these options in the builder package to reuse these filters across tests.
-	ignoreResourceVersion := cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion")
-
 	// Create objects used across test cases.
 	infraClusterTemplate := testtypes.NewInfrastructureClusterTemplateBuilder(metav1.NamespaceDefault, "infraclustertemplate1").
 		Build()
@@ -68,6 +64,28 @@ func TestGetBlueprint(t *testing.T) {
 		Build()
 	mds := []clusterv1.MachineDeploymentClass{*machineDeployment}
 
+	clusterClassWithMachinePools := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(infraClusterTemplate).
+		WithControlPlaneTemplate(controlPlaneTemplate).
+		Build()
+	clusterClassWithMachinePools.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{
+		{
+			Class: "poolclass1",
+			Template: clusterv1.MachinePoolClassTemplate{
+				Metadata: clusterv1.ObjectMeta{
+					Labels:      map[string]string{"foo": "bar"},
+					Annotations: map[string]string{"a": "b"},
+				},
+				Bootstrap: clusterv1.LocalObjectTemplate{
+					Ref: contract.ObjToRef(workerBootstrapTemplate),
+				},
+				Infrastructure: clusterv1.LocalObjectTemplate{
+					Ref: contract.ObjToRef(workerInfrastructureMachineTemplate),
+				},
+			},
+		},
+	}
+
 	// Define test cases.
 	tests := []struct {
 		name         string
@@ -140,6 +158,7 @@ func TestGetBlueprint(t *testing.T) {
 					Template: controlPlaneTemplate,
 				},
 				MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{},
+				MachinePools:       map[string]*scope.MachinePoolBlueprint{},
 			},
 		},
 		{
@@ -166,6 +185,7 @@ func TestGetBlueprint(t *testing.T) {
 					InfrastructureMachineTemplate: controlPlaneInfrastructureMachineTemplate,
 				},
 				MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{},
+				MachinePools:       map[string]*scope.MachinePoolBlueprint{},
 			},
 		},
 		{
@@ -215,8 +235,48 @@ func TestGetBlueprint(t *testing.T) {
 						BootstrapTemplate:             workerBootstrapTemplate,
 					},
 				},
+				MachinePools: map[string]*scope.MachinePoolBlueprint{},
 			},
 		},
+		{
+			name:         "Should read a ClusterClass with a MachinePoolClass",
+			clusterClass: clusterClassWithMachinePools,
+			objects: []client.Object{
+				infraClusterTemplate,
+				controlPlaneTemplate,
+				workerInfrastructureMachineTemplate,
+				workerBootstrapTemplate,
+			},
+			want: &scope.ClusterBlueprint{
+				ClusterClass:                  clusterClassWithMachinePools,
+				InfrastructureClusterTemplate: infraClusterTemplate,
+				ControlPlane: &scope.ControlPlaneBlueprint{
+					Template: controlPlaneTemplate,
+				},
+				MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{},
+				MachinePools: map[string]*scope.MachinePoolBlueprint{
+					"poolclass1": {
+						Metadata: clusterv1.ObjectMeta{
+							Labels:      map[string]string{"foo": "bar"},
+							Annotations: map[string]string{"a": "b"},
+						},
+						InfrastructureMachinePoolTemplate: workerInfrastructureMachineTemplate,
+						BootstrapTemplate:                 workerBootstrapTemplate,
+					},
+				},
+			},
+		},
+		{
+			name:         "Fails if ClusterClass has a MachinePoolClass referencing a BootstrapTemplate that does not exist",
+			clusterClass: clusterClassWithMachinePools,
+			objects: []client.Object{
+				infraClusterTemplate,
+				controlPlaneTemplate,
+				workerInfrastructureMachineTemplate,
+				// workerBootstrapTemplate is missing!
+			},
+			wantErr: true,
+		},
 		{
 			name: "Fails if ClusterClass has a MachineDeploymentClass referencing a BootstrapTemplate that does not exist",
 			clusterClass: testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").
@@ -271,9 +331,11 @@ func TestGetBlueprint(t *testing.T) {
 			if tt.clusterClass != nil {
 				objs = append(objs, tt.clusterClass)
 			}
-			fakeClient := fake.NewClientBuilder().
+			// NOTE: the resourceVersion set by the fake client is dropped, so returned objects can be compared with the expected ones.
+			fakeClient := fakeclient.NewClientBuilder().
 				WithScheme(fakeScheme).
 				WithObjects(objs...).
+				WithoutResourceVersion().
 				Build()
 
 			// Calls getBlueprint.
@@ -296,17 +358,80 @@ func TestGetBlueprint(t *testing.T) {
 			}
 
 			// Checks the blueprint content.
+			g.Expect(cmp.Diff(tt.want.ClusterClass, got.ClusterClass)).To(Equal(""), cmp.Diff(tt.want.ClusterClass, got.ClusterClass))
+			g.Expect(cmp.Diff(tt.want.InfrastructureClusterTemplate, got.InfrastructureClusterTemplate)).To(Equal(""), cmp.Diff(tt.want.InfrastructureClusterTemplate, got.InfrastructureClusterTemplate))
+			g.Expect(cmp.Diff(tt.want.ControlPlane, got.ControlPlane)).To(Equal(""), cmp.Diff(tt.want.ControlPlane, got.ControlPlane))
+			g.Expect(cmp.Diff(tt.want.MachineDeployments, got.MachineDeployments)).To(Equal(""), cmp.Diff(tt.want.MachineDeployments, got.MachineDeployments))
+			g.Expect(cmp.Diff(tt.want.MachinePools, got.MachinePools)).To(Equal(""), cmp.Diff(tt.want.MachinePools, got.MachinePools))
+		})
+	}
+}
+
+func TestGetBlueprintFromOtherNamespace(t *testing.T) {
+	crds := []client.Object{
+		testtypes.GenericInfrastructureClusterTemplateCRD,
+		testtypes.GenericControlPlaneTemplateCRD,
+	}
+
+	// The ClusterClass and its templates are defined in a catalog namespace, while the Cluster is in the default namespace.
+	infraClusterTemplate := testtypes.NewInfrastructureClusterTemplateBuilder("catalog", "infraclustertemplate1").
+		Build()
+	controlPlaneTemplate := testtypes.NewControlPlaneTemplateBuilder("catalog", "controlplanetemplate1").
+		Build()
+	clusterClass := testtypes.NewClusterClassBuilder("catalog", "class1").
+		WithInfrastructureClusterTemplate(infraClusterTemplate).
+		WithControlPlaneTemplate(controlPlaneTemplate).
+		Build()
+
+	tests := []struct {
+		name                   string
+		clusterClassNamespaces []string
+		wantErr                bool
+	}{
+		{
+			name:                   "Fails if the namespace of the ClusterClass is not allowed to provide ClusterClasses to other namespaces",
+			clusterClassNamespaces: nil,
+			wantErr:                true,
+		},
+		{
+			name:                   "Should read a ClusterClass from a namespace allowed to provide ClusterClasses to other namespaces",
+			clusterClassNamespaces: []string{"catalog"},
+			wantErr:                false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterv1.SetClusterClassNamespaces(tt.clusterClassNamespaces...)
+			defer clusterv1.SetClusterClassNamespaces()
+
+			cluster := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster1").Build()
+			cluster.Spec.Topology = &clusterv1.Topology{
+				Class:          clusterClass.Name,
+				ClassNamespace: clusterClass.Namespace,
+			}
+
+			objs := []client.Object{}
+			objs = append(objs, crds...)
+			objs = append(objs, infraClusterTemplate, controlPlaneTemplate, clusterClass)
+			fakeClient := fakeclient.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(objs...).
+				Build()
 
-			// Expect the Diff resulting from each object comparison to be empty when ignoring ObjectMeta.ResourceVersion
-			// This is necessary as the FakeClient adds its own ResourceVersion on object creation.
-			g.Expect(cmp.Diff(tt.want.ClusterClass, got.ClusterClass, ignoreResourceVersion)).To(Equal(""),
-				cmp.Diff(tt.want.ClusterClass, got.ClusterClass, ignoreResourceVersion))
-			g.Expect(cmp.Diff(tt.want.InfrastructureClusterTemplate, got.InfrastructureClusterTemplate, ignoreResourceVersion)).To(Equal(""),
-				cmp.Diff(tt.want.InfrastructureClusterTemplate, got.InfrastructureClusterTemplate, ignoreResourceVersion))
-			g.Expect(cmp.Diff(tt.want.ControlPlane, got.ControlPlane, ignoreResourceVersion)).To(Equal(""),
-				cmp.Diff(tt.want.ControlPlane, got.ControlPlane, ignoreResourceVersion))
-			g.Expect(cmp.Diff(tt.want.MachineDeployments, got.MachineDeployments, ignoreResourceVersion)).To(Equal(""),
-				cmp.Diff(tt.want.MachineDeployments, got.MachineDeployments, ignoreResourceVersion))
+			r := &ClusterReconciler{
+				Client:                    fakeClient,
+				UnstructuredCachingClient: fakeClient,
+			}
+			got, err := r.getBlueprint(ctx, cluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(client.ObjectKeyFromObject(got.ClusterClass)).To(Equal(client.ObjectKeyFromObject(clusterClass)))
+			g.Expect(got.InfrastructureClusterTemplate.GetNamespace()).To(Equal("catalog"))
 		})
 	}
 }
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/variables"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Result is the result of validating a ClusterClass together with the Clusters using it.
+type Result struct {
+	// ClusterClass is the ClusterClass which has been validated.
+	ClusterClass client.ObjectKey
+
+	// ClusterClassErrors are the errors of the ClusterClass.
+	ClusterClassErrors field.ErrorList
+
+	// ClusterErrors are the errors of the Clusters using the ClusterClass; only Clusters with errors are included.
+	ClusterErrors map[client.ObjectKey]field.ErrorList
+}
+
+// Err returns an error aggregating all the errors of the Result, or nil if there are none.
+func (r *Result) Err() error {
+	var errs []error
+	if len(r.ClusterClassErrors) > 0 {
+		errs = append(errs, errors.Wrapf(r.ClusterClassErrors.ToAggregate(), "ClusterClass %s is not valid", r.ClusterClass))
+	}
+
+	keys := make([]client.ObjectKey, 0, len(r.ClusterErrors))
+	for key := range r.ClusterErrors {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	for _, key := range keys {
+		errs = append(errs, errors.Wrapf(r.ClusterErrors[key].ToAggregate(), "Cluster %s is not valid", key))
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+// ClusterClass validates a ClusterClass; if old is not nil, it also validates that the changes from old are
+// compatible, as it is required when updating a ClusterClass.
+// NOTE: The ClusterClasses are defaulted before validation, as it happens when they are applied to a management cluster.
+func ClusterClass(clusterClass, old *clusterv1.ClusterClass) field.ErrorList {
+	clusterClass = clusterClass.DeepCopy()
+	clusterClass.Default()
+	if old != nil {
+		old = old.DeepCopy()
+		old.Default()
+	}
+	return clusterClass.ValidateSpec(old)
+}
+
+// Cluster validates the managed topology of a Cluster against the ClusterClass it uses; this includes
+// checking that the classes used in the topology are defined in the ClusterClass and that the values of
+// the variables comply with the schemas defined in the ClusterClass.
+// A Cluster without resourceVersion is validated as a new Cluster, otherwise as an existing Cluster.
+// NOTE: The Cluster is defaulted before validation, as it happens when it is applied to a management cluster.
+func Cluster(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	if cluster.Spec.Topology == nil {
+		return field.ErrorList{field.Required(field.NewPath("spec", "topology"), "must be set for Clusters using a ClusterClass")}
+	}
+
+	cluster = cluster.DeepCopy()
+	cluster.Default()
+
+	var allErrs field.ErrorList
+	if cluster.Spec.Topology.Class != clusterClass.Name ||
+		(cluster.Namespace != "" && clusterClass.Namespace != "" && !cluster.UsesClusterClass(clusterClass)) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "topology", "class"), cluster.Spec.Topology.Class, "must refer to ClusterClass "+client.ObjectKeyFromObject(clusterClass).String()))
+	}
+
+	var old *clusterv1.Cluster
+	if cluster.ResourceVersion != "" {
+		old = cluster
+	}
+	allErrs = append(allErrs, cluster.ValidateTopologySpec(old)...)
+	allErrs = append(allErrs, cluster.ValidateTopologyClasses(clusterClass)...)
+	allErrs = append(allErrs, variables.ValidateClusterVariables(cluster.Spec.Topology.Variables, clusterClass.Spec.Variables, field.NewPath("spec", "topology", "variables"))...)
+
+	return allErrs
+}
+
+// Rebase validates that a Cluster can be moved from the ClusterClass it uses to another ClusterClass.
+func Rebase(cluster *clusterv1.Cluster, from, to *clusterv1.ClusterClass) field.ErrorList {
+	if cluster.Spec.Topology == nil {
+		return field.ErrorList{field.Required(field.NewPath("spec", "topology"), "must be set for Clusters using a ClusterClass")}
+	}
+
+	var allErrs field.ErrorList
+	if errs := to.ValidateRebaseFrom(from); len(errs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "topology", "class"), "class cannot be changed from "+from.Name+" to "+to.Name+", ClusterClasses are not compatible: "+errs.ToAggregate().Error()))
+	}
+
+	rebased := cluster.DeepCopy()
+	rebased.Spec.Topology.Class = to.Name
+	rebased.Spec.Topology.ClassNamespace = ""
+	if to.Namespace != "" && to.Namespace != cluster.Namespace {
+		rebased.Spec.Topology.ClassNamespace = to.Namespace
+	}
+	allErrs = append(allErrs, Cluster(rebased, to)...)
+
+	return allErrs
+}
+
+// ClusterClassWithClusters validates a ClusterClass and all the Clusters using it; if old is not nil, it also
+// validates that the changes from old are compatible. This can be used to check that a change to a ClusterClass
+// does not break any of the Clusters using it.
+// Clusters not using the ClusterClass are ignored.
+func ClusterClassWithClusters(clusterClass, old *clusterv1.ClusterClass, clusters []*clusterv1.Cluster) *Result {
+	result := &Result{
+		ClusterClass:       client.ObjectKeyFromObject(clusterClass),
+		ClusterClassErrors: ClusterClass(clusterClass, old),
+		ClusterErrors:      map[client.ObjectKey]field.ErrorList{},
+	}
+
+	for _, cluster := range clusters {
+		if !cluster.UsesClusterClass(clusterClass) {
+			continue
+		}
+		if errs := Cluster(cluster, clusterClass); len(errs) > 0 {
+			result.ClusterErrors[client.ObjectKeyFromObject(cluster)] = errs
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/testtypes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestClusterClassWithClusters(t *testing.T) {
+	newClusterClass := func(name string, mdClasses ...string) *clusterv1.ClusterClass {
+		var mds []clusterv1.MachineDeploymentClass
+		for _, mdClass := range mdClasses {
+			// NOTE: ClusterClass validation requires template kinds to be of the form <name>Template.
+			infrastructureTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infra-"+mdClass).Build()
+			infrastructureTemplate.SetKind(testtypes.GenericInfrastructureMachineTemplateKind)
+			mds = append(mds, *testtypes.NewMachineDeploymentClassBuilder(metav1.NamespaceDefault, "md-"+mdClass).
+				WithClass(mdClass).
+				WithInfrastructureTemplate(infrastructureTemplate).
+				WithBootstrapTemplate(testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-"+mdClass).Build()).
+				Build())
+		}
+		return testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, name).
+			WithInfrastructureClusterTemplate(testtypes.NewInfrastructureClusterTemplateBuilder(metav1.NamespaceDefault, "infra").Build()).
+			WithControlPlaneTemplate(testtypes.NewControlPlaneTemplateBuilder(metav1.NamespaceDefault, "cp").Build()).
+			WithWorkerMachineDeploymentClasses(mds).
+			WithVariables(clusterv1.ClusterClassVariable{
+				Name:     "cpu",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "integer"},
+				},
+			}).
+			Build()
+	}
+
+	newCluster := func(name, class, cpu string, mdClasses ...string) *clusterv1.Cluster {
+		cluster := testtypes.NewClusterBuilder(metav1.NamespaceDefault, name).
+			WithClusterClass(clusterv1.ClusterClass{}).
+			Build()
+		cluster.Spec.Topology.Class = class
+		cluster.Spec.Topology.Version = "v1.22.2"
+		cluster.Spec.Topology.Variables = []clusterv1.ClusterVariable{
+			{Name: "cpu", Value: apiextensionsv1.JSON{Raw: []byte(cpu)}},
+		}
+		if len(mdClasses) > 0 {
+			cluster.Spec.Topology.Workers = &clusterv1.WorkersTopology{}
+			for _, mdClass := range mdClasses {
+				cluster.Spec.Topology.Workers.MachineDeployments = append(cluster.Spec.Topology.Workers.MachineDeployments,
+					clusterv1.MachineDeploymentTopology{Class: mdClass, Name: mdClass})
+			}
+		}
+		return cluster
+	}
+
+	tests := []struct {
+		name                string
+		clusterClass        *clusterv1.ClusterClass
+		old                 *clusterv1.ClusterClass
+		clusters            []*clusterv1.Cluster
+		wantClusterClassErr bool
+		wantClusterErrs     []string
+	}{
+		{
+			name:         "pass for a valid ClusterClass and valid Clusters",
+			clusterClass: newClusterClass("class1", "linux"),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", "class1", "2", "linux"),
+				newCluster("cluster2", "class1", "4"),
+			},
+		},
+		{
+			name:         "ignore Clusters not using the ClusterClass",
+			clusterClass: newClusterClass("class1", "linux"),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", "class2", "\"invalid\"", "windows"),
+			},
+		},
+		{
+			name:         "fail for a Cluster with an invalid variable value",
+			clusterClass: newClusterClass("class1", "linux"),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", "class1", "2", "linux"),
+				newCluster("cluster2", "class1", "\"invalid\"", "linux"),
+			},
+			wantClusterErrs: []string{"cluster2"},
+		},
+		{
+			name:         "fail for a Cluster using an undefined MachineDeployment class",
+			clusterClass: newClusterClass("class1", "linux"),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", "class1", "2", "windows"),
+			},
+			wantClusterErrs: []string{"cluster1"},
+		},
+		{
+			name:         "fail for an incompatible change removing a MachineDeployment class used by a Cluster",
+			clusterClass: newClusterClass("class1", "linux"),
+			old:          newClusterClass("class1", "linux", "windows"),
+			clusters: []*clusterv1.Cluster{
+				newCluster("cluster1", "class1", "2", "linux"),
+				newCluster("cluster2", "class1", "2", "windows"),
+			},
+			wantClusterClassErr: true,
+			wantClusterErrs:     []string{"cluster2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result := ClusterClassWithClusters(tt.clusterClass, tt.old, tt.clusters)
+
+			g.Expect(result.ClusterClass).To(Equal(client.ObjectKeyFromObject(tt.clusterClass)))
+			if tt.wantClusterClassErr {
+				g.Expect(result.ClusterClassErrors).ToNot(BeEmpty())
+			} else {
+				g.Expect(result.ClusterClassErrors).To(BeEmpty())
+			}
+
+			g.Expect(result.ClusterErrors).To(HaveLen(len(tt.wantClusterErrs)))
+			for _, name := range tt.wantClusterErrs {
+				g.Expect(result.ClusterErrors).To(HaveKey(client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: name}))
+			}
+
+			if tt.wantClusterClassErr || len(tt.wantClusterErrs) > 0 {
+				g.Expect(result.Err()).To(HaveOccurred())
+			} else {
+				g.Expect(result.Err()).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
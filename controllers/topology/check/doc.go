@@ -0,0 +1,24 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package check implements the validation of ClusterClasses and managed topologies performed by the
+// Cluster API webhooks and by the topology controller, so it can be used by tooling, e.g. CI pipelines or
+// GitOps pre-merge checks, to validate ClusterClasses and Clusters offline, without a management cluster.
+//
+// NOTE: Feature gates and the namespaces allowed to provide ClusterClasses to other namespaces are not checked;
+// also checks requiring a management cluster, e.g. checking that the kinds referenced by a ClusterClass are
+// installed, or checks requiring the referenced templates, e.g. applying patches, are not performed.
+package check
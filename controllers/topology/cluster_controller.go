@@ -23,12 +23,18 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/patches"
+	externalpatches "sigs.k8s.io/cluster-api/controllers/topology/internal/patches/external"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +47,8 @@ import (
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusterclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinehealthchecks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // ClusterReconciler reconciles a managed topology for a Cluster object.
@@ -52,7 +60,25 @@ type ClusterReconciler struct {
 	// thus allowing to optimize reads for templates or provider specific objects in a managed topology.
 	UnstructuredCachingClient client.Client
 
-	externalTracker external.ObjectTracker
+	// ExternalTracker watches external objects referenced by managed topologies; if not set, an ObjectTracker
+	// for the controller is created when setting up with the manager.
+	ExternalTracker *external.ObjectTracker
+
+	// DryRun, if set, makes the controller only log the changes required to align Clusters to their managed topology;
+	// changes are sent to the API server as dry-run requests, so they are validated without being persisted.
+	DryRun bool
+
+	// MachineDeploymentConcurrency is the maximum number of MachineDeployments of a Cluster which are created, updated
+	// or deleted concurrently; if not set, MachineDeployments are reconciled one at a time.
+	MachineDeploymentConcurrency int
+
+	// blueprintCache caches the blueprints computed for ClusterClasses, so they can be shared across Clusters.
+	blueprintCache *blueprintCache
+
+	// externalPatchGenerator calls the external patch extensions defined in ClusterClasses.
+	externalPatchGenerator patches.ExternalPatchGenerator
+
+	controller controller.Controller
 }
 
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -67,6 +93,10 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&source.Kind{Type: &clusterv1.MachineDeployment{}},
 			handler.EnqueueRequestsFromMapFunc(r.machineDeploymentToCluster),
 		).
+		Watches(
+			&source.Kind{Type: &clusterv1.MachineHealthCheck{}},
+			handler.EnqueueRequestsFromMapFunc(r.machineHealthCheckToCluster),
+		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Build(r)
@@ -75,15 +105,31 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
-	r.externalTracker = external.ObjectTracker{
-		Controller: c,
+	// NOTE: MachinePools are watched only if the MachinePool feature flag is enabled, given that
+	// the MachinePool CRD is not installed otherwise.
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := c.Watch(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(r.machinePoolToCluster),
+			predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
+		); err != nil {
+			return errors.Wrap(err, "failed adding Watch for MachinePools to controller manager")
+		}
+	}
+
+	r.controller = c
+	r.blueprintCache = newBlueprintCache()
+	r.externalPatchGenerator = externalpatches.NewClient()
+	if r.ExternalTracker == nil {
+		r.ExternalTracker, err = external.NewObjectTracker(mgr, external.ObjectTrackerOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create external object tracker")
+		}
 	}
 	return nil
 }
 
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	// Fetch the Cluster instance.
 	cluster := &clusterv1.Cluster{}
 	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
@@ -94,6 +140,8 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		return ctrl.Result{}, err
 	}
 
+	ctx, log := logutil.Into(ctx, logutil.ClusterKey, logutil.KObj(cluster))
+
 	// Return early, if the Cluster does not use a managed topology.
 	// NOTE: This should be removed as soon as we start to support Clusters moving from managed <-> unmanaged.
 	if cluster.Spec.Topology == nil {
@@ -114,6 +162,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	if !cluster.ObjectMeta.DeletionTimestamp.IsZero() {
 		// TODO: When external patching is supported, we should handle the deletion
 		// of those external CRDs we created.
+		r.ExternalTracker.Release(log, r.controller, cluster)
 		return ctrl.Result{}, nil
 	}
 
@@ -121,14 +170,30 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	// additional information will be added about the Cluster blueprint, current state and desired state.
 	scope := scope.New(cluster)
 
+	// Handle dry-run reconciliation loop.
+	if r.DryRun {
+		return r.reconcileDryRun(ctx, scope)
+	}
+
 	// Handle normal reconciliation loop.
 	return r.reconcile(ctx, scope)
 }
 
 // reconcile handles cluster reconciliation.
-func (r *ClusterReconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result, error) {
+// NOTE: The managed topology is never requeued at fixed intervals, given that changes to the objects it depends on
+// are watched; errors are returned to the controller work queue, whose rate limiter already backs off exponentially
+// per Cluster, so requeue.Backoff is not used here to avoid stacking two backoffs for the same failure.
+func (r *ClusterReconciler) reconcile(ctx context.Context, s *scope.Scope) (_ ctrl.Result, reterr error) {
 	var err error
 
+	// Reports the state of the managed topology into conditions, including the errors which occurred
+	// during the reconcile, if any.
+	defer func() {
+		if err := r.reconcileConditions(ctx, s, reterr); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, errors.Wrap(err, "error reconciling the Cluster topology conditions")})
+		}
+	}()
+
 	// Gets the blueprint with the ClusterClass and the referenced templates
 	// and store it in the request scope.
 	s.Blueprint, err = r.getBlueprint(ctx, s.Current.Cluster)
@@ -137,20 +202,23 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl
 	}
 
 	// Gets the current state of the Cluster and store it in the request scope.
-	s.Current, err = r.getCurrentState(ctx, s)
+	// NOTE: The scope is updated only if the current state is successfully read, so the Cluster is
+	// still available in the scope when reporting errors into conditions.
+	currentState, err := r.getCurrentState(ctx, s)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "error reading current state of the Cluster topology")
 	}
+	s.Current = currentState
 
 	// Watch Infrastructure and ControlPlane CRs when they exist.
 	if s.Current.InfrastructureCluster != nil {
-		if err := r.externalTracker.Watch(ctrl.LoggerFrom(ctx), s.Current.InfrastructureCluster,
+		if err := r.ExternalTracker.Watch(ctrl.LoggerFrom(ctx), r.controller, s.Current.Cluster, s.Current.InfrastructureCluster,
 			&handler.EnqueueRequestForOwner{OwnerType: &clusterv1.Cluster{}}); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "error watching Infrastructure CR")
 		}
 	}
 	if s.Current.ControlPlane.Object != nil {
-		if err := r.externalTracker.Watch(ctrl.LoggerFrom(ctx), s.Current.ControlPlane.Object,
+		if err := r.ExternalTracker.Watch(ctrl.LoggerFrom(ctx), r.controller, s.Current.Cluster, s.Current.ControlPlane.Object,
 			&handler.EnqueueRequestForOwner{OwnerType: &clusterv1.Cluster{}}); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "error watching ControlPlane CR")
 		}
@@ -187,10 +255,13 @@ func (r *ClusterReconciler) clusterClassToCluster(o client.Object) []ctrl.Reques
 		return nil
 	}
 
-	// There can be more than one cluster using the same cluster class.
+	// There can be more than one cluster using the same cluster class, also from other namespaces.
 	// create a request for each of the clusters.
 	requests := []ctrl.Request{}
 	for i := range clusterList.Items {
+		if !clusterList.Items[i].UsesClusterClass(clusterClass) {
+			continue
+		}
 		requests = append(requests, ctrl.Request{NamespacedName: util.ObjectKey(&clusterList.Items[i])})
 	}
 	return requests
@@ -214,3 +285,41 @@ func (r *ClusterReconciler) machineDeploymentToCluster(o client.Object) []ctrl.R
 		},
 	}}
 }
+
+// machinePoolToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update when one of its own MachinePools gets updated.
+func (r *ClusterReconciler) machinePoolToCluster(o client.Object) []ctrl.Request {
+	mp, ok := o.(*expv1.MachinePool)
+	if !ok {
+		panic(fmt.Sprintf("Expected a MachinePool but got a %T", o))
+	}
+	if mp.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: mp.Namespace,
+			Name:      mp.Spec.ClusterName,
+		},
+	}}
+}
+
+// machineHealthCheckToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update when one of its own MachineHealthChecks gets updated.
+func (r *ClusterReconciler) machineHealthCheckToCluster(o client.Object) []ctrl.Request {
+	mhc, ok := o.(*clusterv1.MachineHealthCheck)
+	if !ok {
+		panic(fmt.Sprintf("Expected a MachineHealthCheck but got a %T", o))
+	}
+	if _, ok := mhc.Labels[clusterv1.ClusterTopologyOwnedLabel]; !ok || mhc.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: mhc.Namespace,
+			Name:      mhc.Spec.ClusterName,
+		},
+	}}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusterclasses;clusterclasses/status;clusterclasses/finalizers,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+
+// ClusterClassReconciler tracks the Clusters using a ClusterClass and reports their number in the ClusterClass status.
+// It also prevents ClusterClasses from being deleted while they are still in use, by holding a finalizer on them
+// until no Cluster in the same namespace references the ClusterClass anymore.
+type ClusterClassReconciler struct {
+	Client           client.Client
+	WatchFilterValue string
+}
+
+func (r *ClusterClassReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&clusterv1.ClusterClass{}).
+		Named("topology/clusterclass").
+		Watches(
+			&source.Kind{Type: &clusterv1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterToClusterClass),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
+
+func (r *ClusterClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	// Fetch the ClusterClass instance.
+	clusterClass := &clusterv1.ClusterClass{}
+	if err := r.Client.Get(ctx, req.NamespacedName, clusterClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+
+	// Return early if the ClusterClass is paused.
+	if annotations.HasPausedAnnotation(clusterClass) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(clusterClass, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: clusterClass})
+	}
+
+	// Always attempt to patch the object and status after each reconciliation.
+	defer func() {
+		if err := patchHelper.Patch(ctx, clusterClass, patch.WithStatusObservedGeneration{}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: clusterClass})})
+		}
+	}()
+
+	clusters, err := r.getClustersUsingClusterClass(ctx, clusterClass)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	clusterClass.Status.Clusters = int32(len(clusters))
+
+	// Handle deletion reconciliation loop.
+	if !clusterClass.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, clusterClass, clusters)
+	}
+
+	// Add the finalizer so the ClusterClass can't be deleted while it is in use.
+	controllerutil.AddFinalizer(clusterClass, clusterv1.ClusterClassFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// reconcileDelete removes the finalizer from a ClusterClass as soon as it is not used by any Cluster anymore.
+// NOTE: There is no need to requeue while the ClusterClass is still in use, given that any change to the Clusters
+// using it triggers a new reconciliation.
+func (r *ClusterClassReconciler) reconcileDelete(ctx context.Context, clusterClass *clusterv1.ClusterClass, clusters []clusterv1.Cluster) (ctrl.Result, error) {
+	if len(clusters) > 0 {
+		ctrl.LoggerFrom(ctx).Info("Waiting for Clusters using the ClusterClass to be deleted or moved to another ClusterClass", "clusters", len(clusters))
+		return ctrl.Result{}, nil
+	}
+
+	controllerutil.RemoveFinalizer(clusterClass, clusterv1.ClusterClassFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// getClustersUsingClusterClass returns the Clusters which are using the ClusterClass; this includes Clusters in
+// other namespaces, if the namespace of the ClusterClass is allowed to provide ClusterClasses to other namespaces.
+func (r *ClusterClassReconciler) getClustersUsingClusterClass(ctx context.Context, clusterClass *clusterv1.ClusterClass) ([]clusterv1.Cluster, error) {
+	clusterList := &clusterv1.ClusterList{}
+	if err := r.Client.List(ctx, clusterList); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Clusters using %s", tlog.KObj{Obj: clusterClass})
+	}
+
+	clusters := []clusterv1.Cluster{}
+	for _, cluster := range clusterList.Items {
+		if cluster.UsesClusterClass(clusterClass) {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters, nil
+}
+
+// clusterToClusterClass is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for a ClusterClass to update when a Cluster using it gets created, updated or deleted.
+func (r *ClusterClassReconciler) clusterToClusterClass(o client.Object) []ctrl.Request {
+	cluster, ok := o.(*clusterv1.Cluster)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Cluster but got a %T", o))
+	}
+	if cluster.Spec.Topology == nil || cluster.Spec.Topology.Class == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: cluster.GetClusterClassKey(),
+	}}
+}
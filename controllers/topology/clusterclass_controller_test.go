@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/testtypes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestClusterClassReconciler_Reconcile(t *testing.T) {
+	deletionTimeStamp := metav1.Now()
+
+	clusterClass := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").Build()
+	otherClusterClass := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class2").Build()
+	cluster1 := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster1").WithClusterClass(*clusterClass).Build()
+	cluster2 := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster2").WithClusterClass(*clusterClass).Build()
+	cluster3 := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster3").WithClusterClass(*otherClusterClass).Build()
+	clusterInOtherNamespace := testtypes.NewClusterBuilder("other", "cluster1").WithClusterClass(*clusterClass).Build()
+	clusterInOtherNamespaceWithClassNamespace := testtypes.NewClusterBuilder("other", "cluster2").WithClusterClass(*clusterClass).Build()
+	clusterInOtherNamespaceWithClassNamespace.Spec.Topology.ClassNamespace = metav1.NamespaceDefault
+
+	deletedClusterClass := func() *clusterv1.ClusterClass {
+		cc := clusterClass.DeepCopy()
+		cc.SetDeletionTimestamp(&deletionTimeStamp)
+		cc.SetFinalizers([]string{clusterv1.ClusterClassFinalizer})
+		return cc
+	}
+
+	tests := []struct {
+		name              string
+		clusterClass      *clusterv1.ClusterClass
+		objs              []client.Object
+		expectedClusters  int32
+		expectedFinalizer bool
+	}{
+		{
+			name:              "Should add the finalizer and count the Clusters using the ClusterClass",
+			clusterClass:      clusterClass.DeepCopy(),
+			objs:              []client.Object{cluster1, cluster2, cluster3, clusterInOtherNamespace},
+			expectedClusters:  2,
+			expectedFinalizer: true,
+		},
+		{
+			name:              "Should count the Clusters using the ClusterClass from other namespaces",
+			clusterClass:      clusterClass.DeepCopy(),
+			objs:              []client.Object{cluster1, clusterInOtherNamespace, clusterInOtherNamespaceWithClassNamespace},
+			expectedClusters:  2,
+			expectedFinalizer: true,
+		},
+		{
+			name:              "Should add the finalizer if the ClusterClass is not in use",
+			clusterClass:      clusterClass.DeepCopy(),
+			objs:              []client.Object{cluster3},
+			expectedClusters:  0,
+			expectedFinalizer: true,
+		},
+		{
+			name:              "Should keep the finalizer on a deleted ClusterClass which is still in use",
+			clusterClass:      deletedClusterClass(),
+			objs:              []client.Object{cluster1},
+			expectedClusters:  1,
+			expectedFinalizer: true,
+		},
+		{
+			name:              "Should remove the finalizer from a deleted ClusterClass which is not in use",
+			clusterClass:      deletedClusterClass(),
+			objs:              []client.Object{cluster3, clusterInOtherNamespace},
+			expectedClusters:  0,
+			expectedFinalizer: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(append(tt.objs, tt.clusterClass)...).
+				Build()
+
+			r := &ClusterClassReconciler{
+				Client: fakeClient,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tt.clusterClass)})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			afterClusterClass := &clusterv1.ClusterClass{}
+			err = fakeClient.Get(ctx, client.ObjectKeyFromObject(tt.clusterClass), afterClusterClass)
+			// NOTE: the fake client deletes objects being deleted as soon as the last finalizer is removed.
+			if !tt.clusterClass.DeletionTimestamp.IsZero() && !tt.expectedFinalizer {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(afterClusterClass.Status.Clusters).To(Equal(tt.expectedClusters))
+			g.Expect(controllerutil.ContainsFinalizer(afterClusterClass, clusterv1.ClusterClassFinalizer)).To(Equal(tt.expectedFinalizer))
+		})
+	}
+}
+
+func TestClusterClassReconciler_clusterToClusterClass(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterClass := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").Build()
+	cluster := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster1").WithClusterClass(*clusterClass).Build()
+	clusterInOtherNamespace := testtypes.NewClusterBuilder("other", "cluster1").WithClusterClass(*clusterClass).Build()
+	clusterInOtherNamespace.Spec.Topology.ClassNamespace = metav1.NamespaceDefault
+	clusterWithoutTopology := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster2"}}
+
+	r := &ClusterClassReconciler{}
+	g.Expect(r.clusterToClusterClass(cluster)).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(clusterClass)}))
+	g.Expect(r.clusterToClusterClass(clusterInOtherNamespace)).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(clusterClass)}))
+	g.Expect(r.clusterToClusterClass(clusterWithoutTopology)).To(BeEmpty())
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/contract"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// reconcileConditions sets the conditions reporting the state of the managed topology on the Cluster.
+// NOTE: this is called at the end of the reconcile, no matter if it failed or not; reconcileErr is the error
+// returned by the reconcile, if any, while the outcome of reconciling each component of the managed topology
+// is tracked in the ReconcileTracker.
+func (r *ClusterReconciler) reconcileConditions(ctx context.Context, s *scope.Scope, reconcileErr error) error {
+	cluster := s.Current.Cluster
+
+	// Initialize the patch helper after all the other changes to the Cluster are applied, so only the conditions
+	// are patched.
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: cluster})
+	}
+
+	ownedConditions := []clusterv1.ConditionType{
+		clusterv1.TopologyReconciledCondition,
+		clusterv1.TopologyControlPlaneTemplatesReconciledCondition,
+	}
+	reconcileTopologyReconciledCondition(cluster, reconcileErr)
+	reconcileControlPlaneTemplatesReconciledCondition(cluster, s.ReconcileTracker)
+	ownedConditions = append(ownedConditions, reconcileMachineDeploymentsReconciledConditions(cluster, s.ReconcileTracker)...)
+
+	// The rollout of the control plane machines can be checked only if the control plane has been successfully reconciled.
+	if s.ReconcileTracker.ControlPlane.Reconciled && s.ReconcileTracker.ControlPlane.Err == nil {
+		ownedConditions = append(ownedConditions, clusterv1.TopologyControlPlaneMachinesRolledOutCondition)
+		if err := reconcileControlPlaneMachinesRolledOutCondition(s); err != nil {
+			return err
+		}
+	}
+
+	return patchHelper.Patch(ctx, cluster, patch.WithOwnedConditions{Conditions: ownedConditions})
+}
+
+// reconcileTopologyReconciledCondition sets the TopologyReconciledCondition on the Cluster, summarizing
+// the outcome of the reconcile of the managed topology.
+func reconcileTopologyReconciledCondition(cluster *clusterv1.Cluster, reconcileErr error) {
+	if reconcileErr != nil {
+		conditions.MarkFalse(cluster, clusterv1.TopologyReconciledCondition, clusterv1.TopologyReconcileFailedReason, clusterv1.ConditionSeverityError,
+			"%s", reconcileErr.Error())
+		return
+	}
+	conditions.MarkTrue(cluster, clusterv1.TopologyReconciledCondition)
+}
+
+// reconcileControlPlaneTemplatesReconciledCondition sets the TopologyControlPlaneTemplatesReconciledCondition on the Cluster.
+func reconcileControlPlaneTemplatesReconciledCondition(cluster *clusterv1.Cluster, tracker *scope.ReconcileTracker) {
+	setReconciledCondition(cluster, clusterv1.TopologyControlPlaneTemplatesReconciledCondition, tracker.ControlPlane)
+}
+
+// reconcileMachineDeploymentsReconciledConditions sets a TopologyMachineDeploymentsReconciledCondition for each
+// MachineDeployment class in use in the Cluster topology, and removes the conditions for classes not in use anymore.
+// The list of the condition types being set or removed is returned.
+func reconcileMachineDeploymentsReconciledConditions(cluster *clusterv1.Cluster, tracker *scope.ReconcileTracker) []clusterv1.ConditionType {
+	// Group the outcome of reconciling the MachineDeployments by MachineDeployment class; the outcome for a class
+	// is the first error among its MachineDeployments, or reconciled if all of them have been reconciled.
+	results := map[string]scope.ReconcileResult{}
+	classes := []string{}
+	if cluster.Spec.Topology != nil && cluster.Spec.Topology.Workers != nil {
+		for _, md := range cluster.Spec.Topology.Workers.MachineDeployments {
+			mdResult := tracker.MachineDeployments[md.Name]
+			result, ok := results[md.Class]
+			if !ok {
+				classes = append(classes, md.Class)
+				results[md.Class] = mdResult
+				continue
+			}
+			if result.Err != nil {
+				continue
+			}
+			if mdResult.Err != nil || !mdResult.Reconciled {
+				results[md.Class] = mdResult
+			}
+		}
+	}
+
+	conditionTypes := []clusterv1.ConditionType{}
+	inUse := map[clusterv1.ConditionType]bool{}
+	for _, class := range classes {
+		conditionType := machineDeploymentsReconciledConditionType(class)
+		setReconciledCondition(cluster, conditionType, results[class])
+		conditionTypes = append(conditionTypes, conditionType)
+		inUse[conditionType] = true
+	}
+
+	// Remove the conditions of the MachineDeployment classes which are not in use anymore.
+	prefix := string(clusterv1.TopologyMachineDeploymentsReconciledCondition) + "/"
+	for _, condition := range cluster.GetConditions() {
+		if strings.HasPrefix(string(condition.Type), prefix) && !inUse[condition.Type] {
+			conditions.Delete(cluster, condition.Type)
+			conditionTypes = append(conditionTypes, condition.Type)
+		}
+	}
+	return conditionTypes
+}
+
+// machineDeploymentsReconciledConditionType returns the type of the condition reporting if the MachineDeployments
+// of the given MachineDeployment class have been reconciled, e.g. MachineDeploymentsReconciled/default-worker.
+func machineDeploymentsReconciledConditionType(class string) clusterv1.ConditionType {
+	return clusterv1.ConditionType(fmt.Sprintf("%s/%s", clusterv1.TopologyMachineDeploymentsReconciledCondition, class))
+}
+
+// setReconciledCondition sets a condition reporting the outcome of reconciling a component of the managed topology;
+// if the component has not been reconciled because of a previous error, the condition is set to unknown.
+func setReconciledCondition(cluster *clusterv1.Cluster, conditionType clusterv1.ConditionType, result scope.ReconcileResult) {
+	switch {
+	case result.Err != nil:
+		conditions.MarkFalse(cluster, conditionType, clusterv1.TopologyReconcileFailedReason, clusterv1.ConditionSeverityError,
+			"%s", result.Err.Error())
+	case result.Reconciled:
+		conditions.MarkTrue(cluster, conditionType)
+	default:
+		conditions.MarkUnknown(cluster, conditionType, clusterv1.TopologyReconcileSkippedReason,
+			"Reconcile has been skipped because of a previous error")
+	}
+}
+
+// reconcileControlPlaneMachinesRolledOutCondition sets the TopologyControlPlaneMachinesRolledOutCondition on the Cluster;
+// the condition is set to false when the InfrastructureMachineTemplate of the control plane is rotated, and it stays
+// false until the control plane completes the rollout of its machines.
+func reconcileControlPlaneMachinesRolledOutCondition(s *scope.Scope) error {
+	cluster := s.Current.Cluster
+
+	// If the ClusterClass does not define the control plane machine infrastructure, the topology controller
+	// is not rotating any template for the control plane machines.
+	if !s.Blueprint.HasControlPlaneInfrastructureMachine() {
+		conditions.Delete(cluster, clusterv1.TopologyControlPlaneMachinesRolledOutCondition)
+		return nil
+	}
+
+	if s.UpgradeTracker.ControlPlane.MachineInfrastructureRotated {
+		conditions.MarkFalse(cluster, clusterv1.TopologyControlPlaneMachinesRolledOutCondition, clusterv1.TopologyControlPlaneMachinesRollingOutReason, clusterv1.ConditionSeverityInfo,
+			"%s is rolling out machines using the new InfrastructureMachineTemplate", tlog.KObj{Obj: s.Current.ControlPlane.Object})
+		return nil
+	}
+
+	// If a rollout has been triggered in a previous reconcile, check if the control plane is still rolling out.
+	if conditions.IsFalse(cluster, clusterv1.TopologyControlPlaneMachinesRolledOutCondition) && s.Current.ControlPlane.Object != nil {
+		rollingOut, err := contract.ControlPlane().IsRollingOut(s.Current.ControlPlane.Object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check if %s is rolling out", tlog.KObj{Obj: s.Current.ControlPlane.Object})
+		}
+		if rollingOut {
+			return nil
+		}
+	}
+
+	conditions.MarkTrue(cluster, clusterv1.TopologyControlPlaneMachinesRolledOutCondition)
+	return nil
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/internal/testtypes"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileControlPlaneMachinesRolledOutCondition(t *testing.T) {
+	infrastructureMachineTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "cp-infra").Build()
+	classWithMachineInfrastructure := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").
+		WithControlPlaneInfrastructureMachineTemplate(infrastructureMachineTemplate).
+		Build()
+	classWithoutMachineInfrastructure := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class2").
+		Build()
+
+	stableControlPlane := testtypes.NewControlPlaneBuilder(metav1.NamespaceDefault, "cp1").
+		WithInfrastructureMachineTemplate(infrastructureMachineTemplate).
+		WithSpecFields(map[string]interface{}{"spec.replicas": int64(3)}).
+		WithStatusFields(map[string]interface{}{
+			"status.replicas":        int64(3),
+			"status.updatedReplicas": int64(3),
+			"status.readyReplicas":   int64(3),
+		}).
+		Build()
+	rollingOutControlPlane := testtypes.NewControlPlaneBuilder(metav1.NamespaceDefault, "cp1").
+		WithInfrastructureMachineTemplate(infrastructureMachineTemplate).
+		WithSpecFields(map[string]interface{}{"spec.replicas": int64(3)}).
+		WithStatusFields(map[string]interface{}{
+			"status.replicas":        int64(4),
+			"status.updatedReplicas": int64(1),
+			"status.readyReplicas":   int64(4),
+		}).
+		Build()
+
+	tests := []struct {
+		name             string
+		class            *clusterv1.ClusterClass
+		controlPlane     *unstructured.Unstructured
+		currentCondition *clusterv1.Condition
+		rotated          bool
+		wantCondition    bool
+		wantStatus       corev1.ConditionStatus
+	}{
+		{
+			name:          "Does not set the condition if the ClusterClass does not define the control plane machine infrastructure",
+			class:         classWithoutMachineInfrastructure,
+			controlPlane:  stableControlPlane,
+			wantCondition: false,
+		},
+		{
+			name:          "Sets the condition to true if templates have not been rotated",
+			class:         classWithMachineInfrastructure,
+			controlPlane:  stableControlPlane,
+			wantCondition: true,
+			wantStatus:    corev1.ConditionTrue,
+		},
+		{
+			name:          "Sets the condition to false if templates have been rotated",
+			class:         classWithMachineInfrastructure,
+			controlPlane:  stableControlPlane,
+			rotated:       true,
+			wantCondition: true,
+			wantStatus:    corev1.ConditionFalse,
+		},
+		{
+			name:             "Keeps the condition false while the control plane is rolling out",
+			class:            classWithMachineInfrastructure,
+			controlPlane:     rollingOutControlPlane,
+			currentCondition: conditions.FalseCondition(clusterv1.TopologyControlPlaneMachinesRolledOutCondition, clusterv1.TopologyControlPlaneMachinesRollingOutReason, clusterv1.ConditionSeverityInfo, ""),
+			wantCondition:    true,
+			wantStatus:       corev1.ConditionFalse,
+		},
+		{
+			name:             "Sets the condition to true when the control plane completes the rollout",
+			class:            classWithMachineInfrastructure,
+			controlPlane:     stableControlPlane,
+			currentCondition: conditions.FalseCondition(clusterv1.TopologyControlPlaneMachinesRolledOutCondition, clusterv1.TopologyControlPlaneMachinesRollingOutReason, clusterv1.ConditionSeverityInfo, ""),
+			wantCondition:    true,
+			wantStatus:       corev1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster1",
+					Namespace: metav1.NamespaceDefault,
+				},
+			}
+			if tt.currentCondition != nil {
+				conditions.Set(cluster, tt.currentCondition)
+			}
+
+			s := scope.New(cluster)
+			s.Blueprint = &scope.ClusterBlueprint{ClusterClass: tt.class}
+			s.Current.ControlPlane = &scope.ControlPlaneState{Object: tt.controlPlane}
+			s.UpgradeTracker.ControlPlane.MachineInfrastructureRotated = tt.rotated
+
+			g.Expect(reconcileControlPlaneMachinesRolledOutCondition(s)).To(Succeed())
+
+			if !tt.wantCondition {
+				g.Expect(conditions.Has(cluster, clusterv1.TopologyControlPlaneMachinesRolledOutCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(conditions.Get(cluster, clusterv1.TopologyControlPlaneMachinesRolledOutCondition).Status).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+func TestReconcileTopologyReconciledConditions(t *testing.T) {
+	mdTopology := func(name, class string) clusterv1.MachineDeploymentTopology {
+		return clusterv1.MachineDeploymentTopology{Name: name, Class: class}
+	}
+
+	tests := []struct {
+		name                 string
+		machineDeployments   []clusterv1.MachineDeploymentTopology
+		currentConditions    []*clusterv1.Condition
+		reconcileErr         error
+		controlPlane         scope.ReconcileResult
+		mdResults            map[string]scope.ReconcileResult
+		wantStatus           map[clusterv1.ConditionType]corev1.ConditionStatus
+		wantDeletedCondition []clusterv1.ConditionType
+	}{
+		{
+			name: "Sets all the conditions to true if the topology has been reconciled",
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				mdTopology("md1", "linux-worker"),
+				mdTopology("md2", "windows-worker"),
+			},
+			controlPlane: scope.ReconcileResult{Reconciled: true},
+			mdResults: map[string]scope.ReconcileResult{
+				"md1": {Reconciled: true},
+				"md2": {Reconciled: true},
+			},
+			wantStatus: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				clusterv1.TopologyReconciledCondition:                      corev1.ConditionTrue,
+				clusterv1.TopologyControlPlaneTemplatesReconciledCondition: corev1.ConditionTrue,
+				"MachineDeploymentsReconciled/linux-worker":                corev1.ConditionTrue,
+				"MachineDeploymentsReconciled/windows-worker":              corev1.ConditionTrue,
+			},
+		},
+		{
+			name:         "Sets the control plane condition to false and the other conditions to unknown if the control plane failed",
+			reconcileErr: errors.New("failed to reconcile"),
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				mdTopology("md1", "linux-worker"),
+			},
+			controlPlane: scope.ReconcileResult{Reconciled: true, Err: errors.New("failed to reconcile")},
+			wantStatus: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				clusterv1.TopologyReconciledCondition:                      corev1.ConditionFalse,
+				clusterv1.TopologyControlPlaneTemplatesReconciledCondition: corev1.ConditionFalse,
+				"MachineDeploymentsReconciled/linux-worker":                corev1.ConditionUnknown,
+			},
+		},
+		{
+			name:         "Sets the condition of a MachineDeployment class to false if any of its MachineDeployments failed",
+			reconcileErr: errors.New("failed to reconcile"),
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				mdTopology("md1", "linux-worker"),
+				mdTopology("md2", "linux-worker"),
+				mdTopology("md3", "windows-worker"),
+			},
+			controlPlane: scope.ReconcileResult{Reconciled: true},
+			mdResults: map[string]scope.ReconcileResult{
+				"md1": {Reconciled: true},
+				"md2": {Reconciled: true, Err: errors.New("failed to reconcile")},
+				"md3": {Reconciled: true},
+			},
+			wantStatus: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				clusterv1.TopologyReconciledCondition:                      corev1.ConditionFalse,
+				clusterv1.TopologyControlPlaneTemplatesReconciledCondition: corev1.ConditionTrue,
+				"MachineDeploymentsReconciled/linux-worker":                corev1.ConditionFalse,
+				"MachineDeploymentsReconciled/windows-worker":              corev1.ConditionTrue,
+			},
+		},
+		{
+			name:         "Sets the condition of a MachineDeployment class to unknown if some of its MachineDeployments have not been reconciled",
+			reconcileErr: errors.New("failed to reconcile"),
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				mdTopology("md1", "linux-worker"),
+				mdTopology("md2", "linux-worker"),
+			},
+			controlPlane: scope.ReconcileResult{Reconciled: true},
+			mdResults: map[string]scope.ReconcileResult{
+				"md1": {Reconciled: true},
+			},
+			wantStatus: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				clusterv1.TopologyReconciledCondition:                      corev1.ConditionFalse,
+				clusterv1.TopologyControlPlaneTemplatesReconciledCondition: corev1.ConditionTrue,
+				"MachineDeploymentsReconciled/linux-worker":                corev1.ConditionUnknown,
+			},
+		},
+		{
+			name: "Removes the conditions of MachineDeployment classes not in use anymore",
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				mdTopology("md1", "linux-worker"),
+			},
+			currentConditions: []*clusterv1.Condition{
+				conditions.TrueCondition("MachineDeploymentsReconciled/windows-worker"),
+			},
+			controlPlane: scope.ReconcileResult{Reconciled: true},
+			mdResults: map[string]scope.ReconcileResult{
+				"md1": {Reconciled: true},
+			},
+			wantStatus: map[clusterv1.ConditionType]corev1.ConditionStatus{
+				clusterv1.TopologyReconciledCondition:                      corev1.ConditionTrue,
+				clusterv1.TopologyControlPlaneTemplatesReconciledCondition: corev1.ConditionTrue,
+				"MachineDeploymentsReconciled/linux-worker":                corev1.ConditionTrue,
+			},
+			wantDeletedCondition: []clusterv1.ConditionType{"MachineDeploymentsReconciled/windows-worker"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster1",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.ClusterSpec{
+					Topology: &clusterv1.Topology{
+						Workers: &clusterv1.WorkersTopology{
+							MachineDeployments: tt.machineDeployments,
+						},
+					},
+				},
+			}
+			for _, condition := range tt.currentConditions {
+				conditions.Set(cluster, condition)
+			}
+
+			tracker := scope.NewReconcileTracker()
+			tracker.ControlPlane = tt.controlPlane
+			for name, result := range tt.mdResults {
+				tracker.MachineDeployments[name] = result
+			}
+
+			reconcileTopologyReconciledCondition(cluster, tt.reconcileErr)
+			reconcileControlPlaneTemplatesReconciledCondition(cluster, tracker)
+			reconcileMachineDeploymentsReconciledConditions(cluster, tracker)
+
+			for conditionType, status := range tt.wantStatus {
+				g.Expect(conditions.Has(cluster, conditionType)).To(BeTrue(), "missing condition %s", conditionType)
+				g.Expect(conditions.Get(cluster, conditionType).Status).To(Equal(status), "unexpected status for condition %s", conditionType)
+			}
+			for _, conditionType := range tt.wantDeletedCondition {
+				g.Expect(conditions.Has(cluster, conditionType)).To(BeFalse(), "unexpected condition %s", conditionType)
+			}
+		})
+	}
+}
@@ -21,11 +21,14 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
 	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -64,6 +67,18 @@ func (r *ClusterReconciler) getCurrentState(ctx context.Context, s *scope.Scope)
 	}
 	currentState.MachineDeployments = m
 
+	// A Cluster may have zero or more MachinePools and a Cluster is expected to have zero MachinePools on
+	// first reconcile.
+	// NOTE: MachinePools are read only if the MachinePool feature flag is enabled, given that
+	// the MachinePool CRD is not installed otherwise.
+	if feature.Gates.Enabled(feature.MachinePool) {
+		mp, err := r.getCurrentMachinePoolState(ctx, currentState.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		currentState.MachinePools = mp
+	}
+
 	return currentState, nil
 }
 
@@ -90,6 +105,14 @@ func (r *ClusterReconciler) getCurrentControlPlaneState(ctx context.Context, clu
 		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: cluster.Spec.ControlPlaneRef})
 	}
 
+	// Get the MachineHealthCheck for the control plane machines, if any.
+	// NOTE: This is read even if the clusterClass does not define a MachineHealthCheck for the control plane,
+	// so a MachineHealthCheck which is not required anymore can be deleted.
+	res.MachineHealthCheck, err = r.getCurrentMachineHealthCheck(ctx, res.Object)
+	if err != nil {
+		return nil, err
+	}
+
 	// If the clusterClass does not mandate the controlPlane has infrastructureMachines, return.
 	if !blueprint.HasControlPlaneInfrastructureMachine() {
 		return res, nil
@@ -164,11 +187,102 @@ func (r *ClusterReconciler) getCurrentMachineDeploymentState(ctx context.Context
 			return nil, errors.Wrap(err, fmt.Sprintf("%s Infrastructure reference could not be retrieved", tlog.KObj{Obj: m}))
 		}
 
+		// Gets the MachineHealthCheck, if any.
+		mhc, err := r.getCurrentMachineHealthCheck(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+
 		state[mdTopologyName] = &scope.MachineDeploymentState{
 			Object:                        m,
 			BootstrapTemplate:             b,
 			InfrastructureMachineTemplate: i,
+			MachineHealthCheck:            mhc,
 		}
 	}
 	return state, nil
 }
+
+// getCurrentMachinePoolState queries for all MachinePools and filters them for their linked Cluster and
+// whether they are managed by a ClusterClass using labels. A Cluster may have zero or more MachinePools. Zero is
+// expected on first reconcile. If MachinePools are found for the Cluster their Infrastructure and Bootstrap references
+// are inspected. Where these are not found the function will throw an error.
+func (r *ClusterReconciler) getCurrentMachinePoolState(ctx context.Context, cluster *clusterv1.Cluster) (map[string]*scope.MachinePoolState, error) {
+	state := make(scope.MachinePoolsStateMap)
+
+	// List all the machine pools in the current cluster and in a managed topology.
+	mp := &expv1.MachinePoolList{}
+	err := r.Client.List(ctx, mp, client.MatchingLabels{
+		clusterv1.ClusterLabelName:          cluster.Name,
+		clusterv1.ClusterTopologyOwnedLabel: "",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read MachinePools for managed topology")
+	}
+
+	// Loop over each machine pool and create the current
+	// state by retrieving all required references.
+	for i := range mp.Items {
+		m := &mp.Items[i]
+
+		// Retrieve the name which is assigned in Cluster's topology
+		// from a well-defined label.
+		mpTopologyName, ok := m.ObjectMeta.Labels[clusterv1.ClusterTopologyMachinePoolLabelName]
+		if !ok || len(mpTopologyName) == 0 {
+			return nil, fmt.Errorf("failed to find label %s in %s", clusterv1.ClusterTopologyMachinePoolLabelName, tlog.KObj{Obj: m})
+		}
+
+		// Make sure that the name of the MachinePool stays unique.
+		// If we've already have seen a MachinePool with the same name
+		// this is an error, probably caused from manual modifications or a race condition.
+		if _, ok := state[mpTopologyName]; ok {
+			return nil, fmt.Errorf("duplicate %s found for label %s: %s", tlog.KObj{Obj: m}, clusterv1.ClusterTopologyMachinePoolLabelName, mpTopologyName)
+		}
+
+		// Gets the bootstrap config.
+		bootstrapRef := m.Spec.Template.Spec.Bootstrap.ConfigRef
+		if bootstrapRef == nil {
+			return nil, fmt.Errorf("%s does not have a reference to a Bootstrap Config", tlog.KObj{Obj: m})
+		}
+		b, err := r.getReference(ctx, bootstrapRef)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("%s Bootstrap reference could not be retrieved", tlog.KObj{Obj: m}))
+		}
+
+		// Gets the InfrastructureMachinePool.
+		infraRef := m.Spec.Template.Spec.InfrastructureRef
+		if infraRef.Name == "" {
+			return nil, fmt.Errorf("%s does not have a reference to a InfrastructureMachinePool", tlog.KObj{Obj: m})
+		}
+		i, err := r.getReference(ctx, &infraRef)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("%s Infrastructure reference could not be retrieved", tlog.KObj{Obj: m}))
+		}
+
+		state[mpTopologyName] = &scope.MachinePoolState{
+			Object:                          m,
+			BootstrapObject:                 b,
+			InfrastructureMachinePoolObject: i,
+		}
+	}
+	return state, nil
+}
+
+// getCurrentMachineHealthCheck gets the MachineHealthCheck generated by the topology controller for the machines
+// of the given object; the MachineHealthCheck has the same name as the object. If there is no such MachineHealthCheck,
+// nil is returned.
+func (r *ClusterReconciler) getCurrentMachineHealthCheck(ctx context.Context, obj client.Object) (*clusterv1.MachineHealthCheck, error) {
+	mhc := &clusterv1.MachineHealthCheck{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), mhc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read MachineHealthCheck for %s", tlog.KObj{Obj: obj})
+	}
+
+	// Ignore MachineHealthChecks which are not managed by the topology controller.
+	if _, ok := mhc.Labels[clusterv1.ClusterTopologyOwnedLabel]; !ok {
+		return nil, nil
+	}
+	return mhc, nil
+}
@@ -26,8 +26,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -467,3 +470,144 @@ func TestGetCurrentState(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCurrentMachinePoolState(t *testing.T) {
+	crds := []client.Object{
+		testtypes.GenericBootstrapConfigCRD,
+		testtypes.GenericInfrastructureMachineCRD,
+	}
+
+	ignoreFields := cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion")
+
+	// MachinePool and related objects.
+	machinePoolBootstrap := &unstructured.Unstructured{}
+	machinePoolBootstrap.SetAPIVersion(testtypes.BootstrapGroupVersion.String())
+	machinePoolBootstrap.SetKind(testtypes.GenericBootstrapConfigKind)
+	machinePoolBootstrap.SetNamespace(metav1.NamespaceDefault)
+	machinePoolBootstrap.SetName("bootstrap1")
+
+	machinePoolInfrastructure := &unstructured.Unstructured{}
+	machinePoolInfrastructure.SetAPIVersion(testtypes.InfrastructureGroupVersion.String())
+	machinePoolInfrastructure.SetKind(testtypes.GenericInfrastructureMachineKind)
+	machinePoolInfrastructure.SetNamespace(metav1.NamespaceDefault)
+	machinePoolInfrastructure.SetName("infra1")
+
+	machinePool := func(name, topologyName string) *expv1.MachinePool {
+		mp := &expv1.MachinePool{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: expv1.GroupVersion.String(),
+				Kind:       "MachinePool",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:          "cluster1",
+					clusterv1.ClusterTopologyOwnedLabel: "",
+				},
+			},
+			Spec: expv1.MachinePoolSpec{
+				ClusterName: "cluster1",
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						ClusterName:       "cluster1",
+						Bootstrap:         clusterv1.Bootstrap{ConfigRef: contract.ObjToRef(machinePoolBootstrap)},
+						InfrastructureRef: *contract.ObjToRef(machinePoolInfrastructure),
+					},
+				},
+			},
+		}
+		if topologyName != "" {
+			mp.Labels[clusterv1.ClusterTopologyMachinePoolLabelName] = topologyName
+		}
+		return mp
+	}
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		want    scope.MachinePoolsStateMap
+		wantErr bool
+	}{
+		{
+			name:    "Should read a Cluster without MachinePools",
+			objects: []client.Object{},
+			want:    scope.MachinePoolsStateMap{},
+		},
+		{
+			name: "Should read MachinePools with their bootstrap and infrastructure objects",
+			objects: []client.Object{
+				machinePool("mp1", "pool1"),
+				machinePoolBootstrap,
+				machinePoolInfrastructure,
+			},
+			want: scope.MachinePoolsStateMap{
+				"pool1": {
+					Object:                          machinePool("mp1", "pool1"),
+					BootstrapObject:                 machinePoolBootstrap,
+					InfrastructureMachinePoolObject: machinePoolInfrastructure,
+				},
+			},
+		},
+		{
+			name: "Fails if there are MachinePools without the topology.cluster.x-k8s.io/pool-name",
+			objects: []client.Object{
+				machinePool("mp1", ""),
+				machinePoolBootstrap,
+				machinePoolInfrastructure,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fails if there are MachinePools with the same topology.cluster.x-k8s.io/pool-name",
+			objects: []client.Object{
+				machinePool("mp1", "pool1"),
+				machinePool("mp2", "pool1"),
+				machinePoolBootstrap,
+				machinePoolInfrastructure,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fails if a MachinePool references an InfrastructureMachinePool that does not exist",
+			objects: []client.Object{
+				machinePool("mp1", "pool1"),
+				machinePoolBootstrap,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster1").Build()
+
+			// Sets up the fakeClient for the test case.
+			objs := []client.Object{}
+			objs = append(objs, crds...)
+			objs = append(objs, tt.objects...)
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(objs...).
+				Build()
+
+			// Calls getCurrentMachinePoolState.
+			r := &ClusterReconciler{
+				Client:                    fakeClient,
+				UnstructuredCachingClient: fakeClient,
+			}
+			got, err := r.getCurrentMachinePoolState(ctx, cluster)
+
+			// Checks the return error.
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			g.Expect(cmp.Diff(tt.want, scope.MachinePoolsStateMap(got), ignoreFields)).To(Equal(""),
+				cmp.Diff(tt.want, scope.MachinePoolsStateMap(got), ignoreFields))
+		})
+	}
+}
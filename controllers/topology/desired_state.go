@@ -19,18 +19,26 @@ package topology
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
 	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/patches"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/variables"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/contract"
+	"sigs.k8s.io/cluster-api/util/nodemetadata"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -44,6 +52,21 @@ func (r *ClusterReconciler) computeDesiredState(ctx context.Context, s *scope.Sc
 		ControlPlane: &scope.ControlPlaneState{},
 	}
 
+	// Compute the values of the variables defined in the Cluster topology.
+	values, err := computeVariables(s.Blueprint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the patches defined in the ClusterClass and inject the values of the variables into the templates
+	// of the blueprint, so they are used when computing the desired state of the objects below.
+	if err := patches.Apply(ctx, s.Blueprint, values, r.externalPatchGenerator); err != nil {
+		return nil, errors.Wrapf(err, "failed to apply patches from %s", tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+	if err := injectVariables(s.Blueprint, values); err != nil {
+		return nil, err
+	}
+
 	// Compute the desired state of the InfrastructureCluster object.
 	if desiredState.InfrastructureCluster, err = computeInfrastructureCluster(ctx, s); err != nil {
 		return nil, err
@@ -62,25 +85,80 @@ func (r *ClusterReconciler) computeDesiredState(ctx context.Context, s *scope.Sc
 		return nil, err
 	}
 
+	// If the ClusterClass defines a MachineHealthCheck for the control plane, compute the desired state of the
+	// MachineHealthCheck for the control plane machines.
+	if s.Blueprint.HasControlPlaneMachineHealthCheck() {
+		desiredState.ControlPlane.MachineHealthCheck = computeMachineHealthCheck(
+			desiredState.ControlPlane.Object,
+			selectorForControlPlaneMHC(s.Current.Cluster.Name),
+			s.Current.Cluster.Name,
+			s.Blueprint.ControlPlane.MachineHealthCheck)
+	}
+
 	// Compute the desired state for the Cluster object adding a reference to the
 	// InfrastructureCluster and the ControlPlane objects generated by the previous step.
 	desiredState.Cluster = computeCluster(ctx, s, desiredState.InfrastructureCluster, desiredState.ControlPlane.Object)
 
 	// If required by the blueprint, compute the desired state of the MachineDeployment objects for the worker nodes, if any.
-	if !s.Blueprint.HasMachineDeployments() {
-		return desiredState, nil
+	if s.Blueprint.HasMachineDeployments() {
+		// Compute the desired state of the MachineDeployments from the list of MachineDeploymentTopologies
+		// defined in the cluster.
+		desiredState.MachineDeployments, err = computeMachineDeployments(ctx, s, desiredState.ControlPlane)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Compute the desired state of the MachineDeployments from the list of MachineDeploymentTopologies
-	// defined in the cluster.
-	desiredState.MachineDeployments, err = computeMachineDeployments(ctx, s, desiredState.ControlPlane)
-	if err != nil {
-		return nil, err
+	// If required by the blueprint, compute the desired state of the MachinePool objects for the worker nodes, if any.
+	// NOTE: MachinePools are computed only if the MachinePool feature flag is enabled; the webhooks
+	// prevent the usage of MachinePools in the topology otherwise.
+	if s.Blueprint.HasMachinePools() && feature.Gates.Enabled(feature.MachinePool) {
+		// Compute the desired state of the MachinePools from the list of MachinePoolTopologies
+		// defined in the cluster.
+		desiredState.MachinePools, err = computeMachinePools(ctx, s, desiredState.ControlPlane)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return desiredState, nil
 }
 
+// computeVariables validates the variables defined in the Cluster topology against the variables defined in the ClusterClass
+// and returns their values, defaulted using the ClusterClass.
+// NOTE: The variables defined in the ClusterClass are validated by the ClusterClass webhook.
+func computeVariables(blueprint *scope.ClusterBlueprint) (map[string]interface{}, error) {
+	if errs := variables.ValidateClusterVariables(blueprint.Topology.Variables, blueprint.ClusterClass.Spec.Variables, field.NewPath("spec", "topology", "variables")); len(errs) > 0 {
+		return nil, errors.Wrap(errs.ToAggregate(), "invalid variables in Cluster topology")
+	}
+
+	return variables.Values(blueprint.Topology.Variables, blueprint.ClusterClass.Spec.Variables)
+}
+
+// injectVariables injects the values of the variables into the templates of the blueprint.
+func injectVariables(blueprint *scope.ClusterBlueprint, values map[string]interface{}) error {
+	templates := []*unstructured.Unstructured{
+		blueprint.InfrastructureClusterTemplate,
+		blueprint.ControlPlane.Template,
+		blueprint.ControlPlane.InfrastructureMachineTemplate,
+	}
+	for _, md := range blueprint.MachineDeployments {
+		templates = append(templates, md.BootstrapTemplate, md.InfrastructureMachineTemplate)
+	}
+	for _, mp := range blueprint.MachinePools {
+		templates = append(templates, mp.BootstrapTemplate, mp.InfrastructureMachinePoolTemplate)
+	}
+	for _, template := range templates {
+		if template == nil {
+			continue
+		}
+		if err := variables.Inject(template, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // computeInfrastructureCluster computes the desired state for the InfrastructureCluster object starting from the
 // corresponding template defined in the blueprint.
 func computeInfrastructureCluster(_ context.Context, s *scope.Scope) (*unstructured.Unstructured, error) {
@@ -409,12 +487,144 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 	desiredMachineDeploymentObj.Spec.Template.Labels[clusterv1.ClusterTopologyMachineDeploymentLabelName] = machineDeploymentTopology.Name
 
 	// Set the desired replicas.
-	desiredMachineDeploymentObj.Spec.Replicas = machineDeploymentTopology.Replicas
+	// NOTE: If the MachineDeployment is autoscaled, replicas are managed by the cluster-autoscaler and they are set only
+	// when creating the MachineDeployment; the cluster-autoscaler min and max size annotations are set instead.
+	if autoscaling := machineDeploymentTopology.Autoscaling; autoscaling != nil {
+		if currentMachineDeployment == nil || currentMachineDeployment.Object == nil {
+			replicas := autoscaling.MinSize
+			if machineDeploymentTopology.Replicas != nil {
+				replicas = *machineDeploymentTopology.Replicas
+			}
+			desiredMachineDeploymentObj.Spec.Replicas = &replicas
+		}
+
+		annotations := desiredMachineDeploymentObj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[clusterv1.AutoscalerMinSizeAnnotation] = strconv.Itoa(int(autoscaling.MinSize))
+		annotations[clusterv1.AutoscalerMaxSizeAnnotation] = strconv.Itoa(int(autoscaling.MaxSize))
+		desiredMachineDeploymentObj.SetAnnotations(annotations)
+	} else {
+		desiredMachineDeploymentObj.Spec.Replicas = machineDeploymentTopology.Replicas
+	}
+
+	// Set the desired rollout strategy; the value from the Cluster topology, if defined, takes precedence
+	// over the default defined in the ClusterClass.
+	if machineDeploymentTopology.RolloutStrategy != nil {
+		desiredMachineDeploymentObj.Spec.Strategy = machineDeploymentTopology.RolloutStrategy.DeepCopy()
+	} else if machineDeploymentBlueprint.RolloutStrategy != nil {
+		desiredMachineDeploymentObj.Spec.Strategy = machineDeploymentBlueprint.RolloutStrategy.DeepCopy()
+	}
+
+	// Set the desired node labels and taints; the values from the Cluster topology take precedence
+	// over the defaults defined in the ClusterClass.
+	// NOTE: Node labels and taints are set as annotations on the MachineDeployment object, and not in .spec.template, so
+	// changing them does not trigger a rollout; the annotations are then propagated to MachineSets and Machines, and
+	// from Machines to the corresponding Nodes.
+	// NOTE: If the MachineDeployment already has the annotations, they are set to an empty value instead of being dropped,
+	// because dropping them from the desired state would not remove them from the current object.
+	computeMachineDeploymentNodeMetadata(desiredMachineDeploymentObj, currentMachineDeployment,
+		mergeMap(machineDeploymentTopology.NodeLabels, machineDeploymentBlueprint.NodeLabels),
+		nodemetadata.MergeTaints(machineDeploymentTopology.NodeTaints, machineDeploymentBlueprint.NodeTaints))
 
 	desiredMachineDeployment.Object = desiredMachineDeploymentObj
+
+	// If the ClusterClass defines a MachineHealthCheck for the MachineDeployment class, compute the desired state of the
+	// MachineHealthCheck for the MachineDeployment machines.
+	if machineDeploymentBlueprint.MachineHealthCheck != nil {
+		desiredMachineDeployment.MachineHealthCheck = computeMachineHealthCheck(
+			desiredMachineDeploymentObj,
+			selectorForMachineDeploymentMHC(s.Current.Cluster.Name, machineDeploymentTopology.Name),
+			s.Current.Cluster.Name,
+			machineDeploymentBlueprint.MachineHealthCheck)
+	}
+
 	return desiredMachineDeployment, nil
 }
 
+// computeMachineDeploymentNodeMetadata sets the annotations defining the labels and taints to be applied to the Nodes
+// of a MachineDeployment.
+func computeMachineDeploymentNodeMetadata(desiredMachineDeployment *clusterv1.MachineDeployment, currentMachineDeployment *scope.MachineDeploymentState, labels map[string]string, taints []corev1.Taint) {
+	var currentAnnotations map[string]string
+	if currentMachineDeployment != nil && currentMachineDeployment.Object != nil {
+		currentAnnotations = currentMachineDeployment.Object.GetAnnotations()
+	}
+
+	annotations := desiredMachineDeployment.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for key, value := range map[string]string{
+		clusterv1.NodeLabelsAnnotation: nodemetadata.FormatLabels(labels),
+		clusterv1.NodeTaintsAnnotation: nodemetadata.FormatTaints(taints),
+	} {
+		if _, ok := currentAnnotations[key]; value == "" && !ok {
+			continue
+		}
+		annotations[key] = value
+	}
+	if len(annotations) > 0 {
+		desiredMachineDeployment.SetAnnotations(annotations)
+	}
+}
+
+// computeMachineHealthCheck computes the desired state of a MachineHealthCheck for the machines of the given object,
+// using the MachineHealthCheckClass from the ClusterClass.
+// NOTE: The MachineHealthCheck has the same name as the object, so it can be found in next reconcile loops.
+func computeMachineHealthCheck(healthCheckTarget client.Object, selector *metav1.LabelSelector, clusterName string, check *clusterv1.MachineHealthCheckClass) *clusterv1.MachineHealthCheck {
+	check = check.DeepCopy()
+
+	gv := clusterv1.GroupVersion
+	return &clusterv1.MachineHealthCheck{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gv.WithKind("MachineHealthCheck").Kind,
+			APIVersion: gv.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      healthCheckTarget.GetName(),
+			Namespace: healthCheckTarget.GetNamespace(),
+			// NOTE: The cluster label is added at creation time so this object could be read by the ClusterTopology
+			// controller immediately after creation.
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:          clusterName,
+				clusterv1.ClusterTopologyOwnedLabel: "",
+			},
+		},
+		Spec: clusterv1.MachineHealthCheckSpec{
+			ClusterName:         clusterName,
+			Selector:            *selector,
+			UnhealthyConditions: check.UnhealthyConditions,
+			MaxUnhealthy:        check.MaxUnhealthy,
+			UnhealthyRange:      check.UnhealthyRange,
+			NodeStartupTimeout:  check.NodeStartupTimeout,
+			RemediationTemplate: check.RemediationTemplate,
+		},
+	}
+}
+
+// selectorForControlPlaneMHC returns the selector for the MachineHealthCheck of the control plane machines.
+func selectorForControlPlaneMHC(clusterName string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			clusterv1.ClusterLabelName:             clusterName,
+			clusterv1.ClusterTopologyOwnedLabel:    "",
+			clusterv1.MachineControlPlaneLabelName: "",
+		},
+	}
+}
+
+// selectorForMachineDeploymentMHC returns the selector for the MachineHealthCheck of the machines of a MachineDeployment.
+func selectorForMachineDeploymentMHC(clusterName, mdTopologyName string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			clusterv1.ClusterLabelName:                          clusterName,
+			clusterv1.ClusterTopologyOwnedLabel:                 "",
+			clusterv1.ClusterTopologyMachineDeploymentLabelName: mdTopologyName,
+		},
+	}
+}
+
 // computeMachineDeploymentVersion calculates the version of the desired machine deployment.
 // The version is calculated using the state of the current machine deployments,
 // the current control plane and the version defined in the topology.
@@ -433,6 +643,11 @@ func computeMachineDeploymentVersion(s *scope.Scope, desiredControlPlaneState *s
 	// Get the current version of the machine deployment.
 	currentVersion := *currentMDState.Object.Spec.Template.Spec.Version
 
+	// Return early if the rollout of the machine deployment is paused.
+	if isMachineDeploymentRolloutPaused(currentMDState.Object) {
+		return currentVersion, nil
+	}
+
 	// Return early if we are not allowed to upgrade the machine deployment.
 	if !s.UpgradeTracker.MachineDeployments.AllowUpgrade() {
 		return currentVersion, nil
@@ -508,6 +723,209 @@ func computeMachineDeploymentVersion(s *scope.Scope, desiredControlPlaneState *s
 	return desiredVersion, nil
 }
 
+// computeMachinePools computes the desired state of the list of MachinePools.
+func computeMachinePools(ctx context.Context, s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState) (scope.MachinePoolsStateMap, error) {
+	machinePoolsStateMap := make(scope.MachinePoolsStateMap)
+	for _, mpTopology := range s.Blueprint.Topology.Workers.MachinePools {
+		desiredMachinePool, err := computeMachinePool(ctx, s, desiredControlPlaneState, mpTopology)
+		if err != nil {
+			return nil, err
+		}
+		machinePoolsStateMap[mpTopology.Name] = desiredMachinePool
+	}
+	return machinePoolsStateMap, nil
+}
+
+// computeMachinePool computes the desired state for a MachinePoolTopology.
+// The generated machinePool object is calculated using the values from the machinePoolTopology and
+// the machinePool class.
+// NOTE: Differently from MachineDeployments, the bootstrap config and the InfrastructureMachinePool referenced by a
+// MachinePool are objects and not templates; they are generated from the templates defined in the ClusterClass.
+func computeMachinePool(_ context.Context, s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState, machinePoolTopology clusterv1.MachinePoolTopology) (*scope.MachinePoolState, error) {
+	desiredMachinePool := &scope.MachinePoolState{}
+
+	// Gets the blueprint for the MachinePool class.
+	className := machinePoolTopology.Class
+	machinePoolBlueprint, ok := s.Blueprint.MachinePools[className]
+	if !ok {
+		return nil, errors.Errorf("MachinePool class %s not found in %s", className, tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+
+	// Compute the bootstrap config.
+	currentMachinePool := s.Current.MachinePools[machinePoolTopology.Name]
+	var currentBootstrapConfigRef *corev1.ObjectReference
+	if currentMachinePool != nil && currentMachinePool.BootstrapObject != nil {
+		currentBootstrapConfigRef = currentMachinePool.Object.Spec.Template.Spec.Bootstrap.ConfigRef
+	}
+	var err error
+	desiredMachinePool.BootstrapObject, err = templateToObject(templateToInput{
+		template:              machinePoolBlueprint.BootstrapTemplate,
+		templateClonedFromRef: contract.ObjToRef(machinePoolBlueprint.BootstrapTemplate),
+		cluster:               s.Current.Cluster,
+		namePrefix:            bootstrapTemplateNamePrefix(s.Current.Cluster.Name, machinePoolTopology.Name),
+		currentObjectRef:      currentBootstrapConfigRef,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute bootstrap object for topology %q", machinePoolTopology.Name)
+	}
+
+	bootstrapObjectLabels := desiredMachinePool.BootstrapObject.GetLabels()
+	if bootstrapObjectLabels == nil {
+		bootstrapObjectLabels = map[string]string{}
+	}
+	// Add ClusterTopologyMachinePoolLabel to the generated bootstrap config
+	bootstrapObjectLabels[clusterv1.ClusterTopologyMachinePoolLabelName] = machinePoolTopology.Name
+	desiredMachinePool.BootstrapObject.SetLabels(bootstrapObjectLabels)
+
+	// Compute the InfrastructureMachinePool.
+	var currentInfraMachinePoolRef *corev1.ObjectReference
+	if currentMachinePool != nil && currentMachinePool.InfrastructureMachinePoolObject != nil {
+		currentInfraMachinePoolRef = &currentMachinePool.Object.Spec.Template.Spec.InfrastructureRef
+	}
+	desiredMachinePool.InfrastructureMachinePoolObject, err = templateToObject(templateToInput{
+		template:              machinePoolBlueprint.InfrastructureMachinePoolTemplate,
+		templateClonedFromRef: contract.ObjToRef(machinePoolBlueprint.InfrastructureMachinePoolTemplate),
+		cluster:               s.Current.Cluster,
+		namePrefix:            infrastructureMachineTemplateNamePrefix(s.Current.Cluster.Name, machinePoolTopology.Name),
+		currentObjectRef:      currentInfraMachinePoolRef,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute infrastructure object for topology %q", machinePoolTopology.Name)
+	}
+
+	infraMachinePoolObjectLabels := desiredMachinePool.InfrastructureMachinePoolObject.GetLabels()
+	if infraMachinePoolObjectLabels == nil {
+		infraMachinePoolObjectLabels = map[string]string{}
+	}
+	// Add ClusterTopologyMachinePoolLabel to the generated InfrastructureMachinePool
+	infraMachinePoolObjectLabels[clusterv1.ClusterTopologyMachinePoolLabelName] = machinePoolTopology.Name
+	desiredMachinePool.InfrastructureMachinePoolObject.SetLabels(infraMachinePoolObjectLabels)
+
+	version, err := computeMachinePoolVersion(s, desiredControlPlaneState, currentMachinePool)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute version for %s", machinePoolTopology.Name)
+	}
+
+	// Compute the MachinePool object.
+	gv := expv1.GroupVersion
+	desiredMachinePoolObj := &expv1.MachinePool{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gv.WithKind("MachinePool").Kind,
+			APIVersion: gv.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.SimpleNameGenerator.GenerateName(fmt.Sprintf("%s-%s-", s.Current.Cluster.Name, machinePoolTopology.Name)),
+			Namespace: s.Current.Cluster.Namespace,
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName:    s.Current.Cluster.Name,
+			Replicas:       machinePoolTopology.Replicas,
+			FailureDomains: machinePoolTopology.FailureDomains,
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels:      mergeMap(machinePoolTopology.Metadata.Labels, machinePoolBlueprint.Metadata.Labels),
+					Annotations: mergeMap(machinePoolTopology.Metadata.Annotations, machinePoolBlueprint.Metadata.Annotations),
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:       s.Current.Cluster.Name,
+					Version:           pointer.String(version),
+					Bootstrap:         clusterv1.Bootstrap{ConfigRef: contract.ObjToRef(desiredMachinePool.BootstrapObject)},
+					InfrastructureRef: *contract.ObjToRef(desiredMachinePool.InfrastructureMachinePoolObject),
+				},
+			},
+		},
+	}
+
+	// If an existing MachinePool is present, override the MachinePool generate name
+	// re-using the existing name (this will help in reconcile).
+	if currentMachinePool != nil && currentMachinePool.Object != nil {
+		desiredMachinePoolObj.SetName(currentMachinePool.Object.Name)
+	}
+
+	// Apply Labels
+	// NOTE: On top of all the labels applied to managed objects we are applying the ClusterTopologyMachinePoolLabel
+	// keeping track of the MachinePool name from the Topology; this will be used to identify the object in next reconcile loops.
+	labels := map[string]string{}
+	labels[clusterv1.ClusterLabelName] = s.Current.Cluster.Name
+	labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+	labels[clusterv1.ClusterTopologyMachinePoolLabelName] = machinePoolTopology.Name
+	desiredMachinePoolObj.SetLabels(labels)
+
+	// Also set the labels in .spec.template.labels so that they are propagated to the Machines of the MachinePool.
+	desiredMachinePoolObj.Spec.Template.Labels[clusterv1.ClusterLabelName] = s.Current.Cluster.Name
+	desiredMachinePoolObj.Spec.Template.Labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+	desiredMachinePoolObj.Spec.Template.Labels[clusterv1.ClusterTopologyMachinePoolLabelName] = machinePoolTopology.Name
+
+	desiredMachinePool.Object = desiredMachinePoolObj
+
+	return desiredMachinePool, nil
+}
+
+// computeMachinePoolVersion calculates the version of the desired machine pool.
+// The version is calculated using the state of the current machine pool,
+// the current control plane and the version defined in the topology.
+func computeMachinePoolVersion(s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState, currentMPState *scope.MachinePoolState) (string, error) {
+	desiredVersion := s.Blueprint.Topology.Version
+	// If creating a new machine pool, we can pick up the desired version
+	// Note: We are not blocking the creation of new machine pools when
+	// the control plane is upgrading/scaling.
+	if currentMPState == nil || currentMPState.Object == nil {
+		return desiredVersion, nil
+	}
+
+	// Get the current version of the machine pool.
+	currentVersion := *currentMPState.Object.Spec.Template.Spec.Version
+
+	// Return early if the currentVersion is already equal to the desiredVersion
+	// no further checks required.
+	if currentVersion == desiredVersion {
+		return currentVersion, nil
+	}
+
+	// If the control plane is being created (current control plane is nil), do not perform
+	// any machine pool upgrade in this case.
+	if s.Current.ControlPlane == nil || s.Current.ControlPlane.Object == nil {
+		return currentVersion, nil
+	}
+
+	// If the current control plane is upgrading, then do not pick up the desiredVersion yet.
+	cpUpgrading, err := contract.ControlPlane().IsUpgrading(s.Current.ControlPlane.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to check if control plane is upgrading")
+	}
+	if cpUpgrading {
+		return currentVersion, nil
+	}
+
+	// If control plane supports replicas, check if the control plane is in the middle of a scale operation.
+	// If the current control plane is scaling, then do not pick up the desiredVersion yet.
+	if s.Blueprint.Topology.ControlPlane.Replicas != nil {
+		cpScaling, err := contract.ControlPlane().IsScaling(s.Current.ControlPlane.Object)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to check if the control plane is scaling")
+		}
+		if cpScaling {
+			return currentVersion, nil
+		}
+	}
+
+	// Check if we are about to upgrade the control plane. In that case, do not upgrade the machine pool yet.
+	currentCPVersion, err := contract.ControlPlane().Version().Get(s.Current.ControlPlane.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get version of current control plane")
+	}
+	desiredCPVersion, err := contract.ControlPlane().Version().Get(desiredControlPlaneState.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get version of desired control plane")
+	}
+	if *currentCPVersion != *desiredCPVersion {
+		return currentVersion, nil
+	}
+
+	// Control plane is stable, ready to pick up the topology version.
+	return desiredVersion, nil
+}
+
 type templateToInput struct {
 	template              *unstructured.Unstructured
 	templateClonedFromRef *corev1.ObjectReference
@@ -567,6 +985,10 @@ func templateToTemplate(in templateToInput) *unstructured.Unstructured {
 	template.SetUID("")
 	template.SetSelfLink("")
 
+	// Ensure the template is cloned into the namespace of the Cluster, given that the ClusterClass and its
+	// templates can be defined in another namespace.
+	template.SetNamespace(in.cluster.Namespace)
+
 	// Enforce the topology labels into the provided label set.
 	// NOTE: The cluster label is added at creation time so this object could be read by the ClusterTopology
 	// controller immediately after creation, even before other controllers are going to add the label (if missing).
@@ -19,18 +19,22 @@ package topology
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"sigs.k8s.io/cluster-api/internal/testtypes"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 var (
@@ -801,6 +805,159 @@ func TestComputeMachineDeployment(t *testing.T) {
 		g.Expect(actualMd.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal("linux-worker-bootstraptemplate"))
 	})
 
+	t.Run("Sets the rollout strategy from the ClusterClass, unless overridden in the Cluster topology", func(t *testing.T) {
+		g := NewWithT(t)
+
+		classMaxSurge := intstr.FromInt(1)
+		classStrategy := &clusterv1.MachineDeploymentStrategy{
+			Type: clusterv1.RollingUpdateMachineDeploymentStrategyType,
+			RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+				MaxSurge:     &classMaxSurge,
+				DeletePolicy: pointer.String(string(clusterv1.OldestMachineSetDeletePolicy)),
+			},
+		}
+		topologyMaxUnavailable := intstr.FromString("50%")
+		topologyStrategy := &clusterv1.MachineDeploymentStrategy{
+			Type: clusterv1.RollingUpdateMachineDeploymentStrategyType,
+			RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+				MaxUnavailable: &topologyMaxUnavailable,
+			},
+		}
+
+		mdBlueprint := *blueprint.MachineDeployments["linux-worker"]
+		mdBlueprint.RolloutStrategy = classStrategy
+		s := scope.New(cluster)
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology:     blueprint.Topology,
+			ClusterClass: blueprint.ClusterClass,
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+				"linux-worker": &mdBlueprint,
+			},
+		}
+
+		actual, err := computeMachineDeployment(ctx, s, nil, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(actual.Object.Spec.Strategy).To(Equal(classStrategy))
+
+		mdTopologyWithStrategy := *mdTopology.DeepCopy()
+		mdTopologyWithStrategy.RolloutStrategy = topologyStrategy
+		actual, err = computeMachineDeployment(ctx, s, nil, mdTopologyWithStrategy)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(actual.Object.Spec.Strategy).To(Equal(topologyStrategy))
+	})
+
+	t.Run("Sets the node labels and taints from the ClusterClass, merged with the ones in the Cluster topology", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mdBlueprint := *blueprint.MachineDeployments["linux-worker"]
+		mdBlueprint.NodeLabels = map[string]string{"role": "worker", "zone": "a"}
+		mdBlueprint.NodeTaints = []corev1.Taint{
+			{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+		}
+		s := scope.New(cluster)
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology:     blueprint.Topology,
+			ClusterClass: blueprint.ClusterClass,
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+				"linux-worker": &mdBlueprint,
+			},
+		}
+
+		mdTopologyWithNodeMetadata := *mdTopology.DeepCopy()
+		mdTopologyWithNodeMetadata.NodeLabels = map[string]string{"zone": "b"}
+		mdTopologyWithNodeMetadata.NodeTaints = []corev1.Taint{
+			{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		}
+
+		actual, err := computeMachineDeployment(ctx, s, nil, mdTopologyWithNodeMetadata)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(actual.Object.Annotations).To(HaveKeyWithValue(clusterv1.NodeLabelsAnnotation, "role=worker,zone=b"))
+		g.Expect(actual.Object.Annotations).To(HaveKeyWithValue(clusterv1.NodeTaintsAnnotation, "dedicated=gpu:NoSchedule,spot:PreferNoSchedule"))
+		// Node labels and taints must not be set in the template, so changing them does not trigger a rollout.
+		g.Expect(actual.Object.Spec.Template.Annotations).ToNot(HaveKey(clusterv1.NodeLabelsAnnotation))
+		g.Expect(actual.Object.Spec.Template.Annotations).ToNot(HaveKey(clusterv1.NodeTaintsAnnotation))
+	})
+
+	t.Run("Clears the node labels and taints annotations if they are not desired anymore", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+		s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{
+			"big-pool-of-machines": {
+				Object: &clusterv1.MachineDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "existing-deployment-1",
+						Annotations: map[string]string{
+							clusterv1.NodeLabelsAnnotation: "zone=a",
+						},
+					},
+					Spec: clusterv1.MachineDeploymentSpec{
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								Version: pointer.String(version),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		actual, err := computeMachineDeployment(ctx, s, nil, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(actual.Object.Annotations).To(HaveKeyWithValue(clusterv1.NodeLabelsAnnotation, ""))
+		g.Expect(actual.Object.Annotations).ToNot(HaveKey(clusterv1.NodeTaintsAnnotation))
+	})
+
+	t.Run("Sets the cluster-autoscaler annotations and the initial replicas for new autoscaled machine deployments", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+
+		mdTopologyWithAutoscaling := *mdTopology.DeepCopy()
+		mdTopologyWithAutoscaling.Replicas = nil
+		mdTopologyWithAutoscaling.Autoscaling = &clusterv1.MachineDeploymentAutoscaling{MinSize: 2, MaxSize: 10}
+
+		actual, err := computeMachineDeployment(ctx, s, nil, mdTopologyWithAutoscaling)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(*actual.Object.Spec.Replicas).To(Equal(int32(2)))
+		g.Expect(actual.Object.Annotations).To(HaveKeyWithValue(clusterv1.AutoscalerMinSizeAnnotation, "2"))
+		g.Expect(actual.Object.Annotations).To(HaveKeyWithValue(clusterv1.AutoscalerMaxSizeAnnotation, "10"))
+	})
+
+	t.Run("Does not set replicas for existing autoscaled machine deployments", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+		s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{
+			"big-pool-of-machines": {
+				Object: &clusterv1.MachineDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "existing-deployment-1",
+					},
+					Spec: clusterv1.MachineDeploymentSpec{
+						Replicas: pointer.Int32(7),
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								Version: pointer.String(version),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		mdTopologyWithAutoscaling := *mdTopology.DeepCopy()
+		mdTopologyWithAutoscaling.Autoscaling = &clusterv1.MachineDeploymentAutoscaling{MinSize: 1, MaxSize: 10}
+
+		actual, err := computeMachineDeployment(ctx, s, nil, mdTopologyWithAutoscaling)
+		g.Expect(err).ToNot(HaveOccurred())
+		// Replicas are managed by the cluster-autoscaler, so they must not be part of the desired state.
+		g.Expect(actual.Object.Spec.Replicas).To(BeNil())
+		g.Expect(actual.Object.Annotations).To(HaveKeyWithValue(clusterv1.AutoscalerMinSizeAnnotation, "1"))
+		g.Expect(actual.Object.Annotations).To(HaveKeyWithValue(clusterv1.AutoscalerMaxSizeAnnotation, "10"))
+	})
+
 	t.Run("If a machine deployment references a topology class that does not exist, machine deployment generation fails", func(t *testing.T) {
 		g := NewWithT(t)
 		scope := scope.New(cluster)
@@ -936,6 +1093,192 @@ func TestComputeMachineDeployment(t *testing.T) {
 	})
 }
 
+func TestComputeMachinePool(t *testing.T) {
+	workerInfrastructureMachinePoolTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "linux-worker-inframachinepooltemplate").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.fakeSetting": true}).
+		Build()
+	workerBootstrapTemplate := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "linux-worker-bootstraptemplate").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.fakeSetting": true}).
+		Build()
+	labels := map[string]string{"fizz": "buzz", "foo": "bar"}
+	annotations := map[string]string{"annotation-1": "annotation-1-val"}
+
+	fakeClass := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").
+		Build()
+
+	version := "v1.21.2"
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{
+				Version: version,
+			},
+		},
+	}
+
+	blueprint := &scope.ClusterBlueprint{
+		Topology:     cluster.Spec.Topology,
+		ClusterClass: fakeClass,
+		MachinePools: map[string]*scope.MachinePoolBlueprint{
+			"linux-worker": {
+				Metadata: clusterv1.ObjectMeta{
+					Labels:      labels,
+					Annotations: annotations,
+				},
+				BootstrapTemplate:                 workerBootstrapTemplate,
+				InfrastructureMachinePoolTemplate: workerInfrastructureMachinePoolTemplate,
+			},
+		},
+	}
+
+	replicas := int32(5)
+	mpTopology := clusterv1.MachinePoolTopology{
+		Metadata: clusterv1.ObjectMeta{
+			Labels: map[string]string{"foo": "baz"},
+		},
+		Class:          "linux-worker",
+		Name:           "big-pool-of-machines",
+		Replicas:       &replicas,
+		FailureDomains: []string{"fd1", "fd2"},
+	}
+
+	t.Run("Generates the machine pool and the referenced objects", func(t *testing.T) {
+		g := NewWithT(t)
+		scope := scope.New(cluster)
+		scope.Blueprint = blueprint
+
+		actual, err := computeMachinePool(ctx, scope, nil, mpTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(actual.BootstrapObject.GetKind()).To(Equal(testtypes.GenericBootstrapConfigKind))
+		g.Expect(actual.BootstrapObject.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachinePoolLabelName, "big-pool-of-machines"))
+		g.Expect(actual.InfrastructureMachinePoolObject.GetKind()).To(Equal(testtypes.GenericInfrastructureMachineKind))
+		g.Expect(actual.InfrastructureMachinePoolObject.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachinePoolLabelName, "big-pool-of-machines"))
+
+		actualMp := actual.Object
+		g.Expect(*actualMp.Spec.Replicas).To(Equal(replicas))
+		g.Expect(actualMp.Spec.FailureDomains).To(Equal([]string{"fd1", "fd2"}))
+		g.Expect(actualMp.Spec.ClusterName).To(Equal("cluster1"))
+		g.Expect(actualMp.Name).To(ContainSubstring("cluster1"))
+		g.Expect(actualMp.Name).To(ContainSubstring("big-pool-of-machines"))
+
+		g.Expect(actualMp.Labels).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachinePoolLabelName, "big-pool-of-machines"))
+		g.Expect(actualMp.Labels).To(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+
+		g.Expect(actualMp.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("foo", "baz"))
+		g.Expect(actualMp.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("fizz", "buzz"))
+		g.Expect(actualMp.Spec.Template.ObjectMeta.Labels).To(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+		g.Expect(*actualMp.Spec.Template.Spec.Version).To(Equal(version))
+		g.Expect(actualMp.Spec.Template.Spec.InfrastructureRef.Name).To(Equal(actual.InfrastructureMachinePoolObject.GetName()))
+		g.Expect(actualMp.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal(actual.BootstrapObject.GetName()))
+	})
+
+	t.Run("If there is already a machine pool, it preserves the object name and the reference names", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+
+		currentBootstrapConfig := workerBootstrapTemplate.DeepCopy()
+		currentBootstrapConfig.SetName("existing-bootstrap-config")
+		currentInfrastructureMachinePool := workerInfrastructureMachinePoolTemplate.DeepCopy()
+		currentInfrastructureMachinePool.SetName("existing-infrastructure-machine-pool")
+
+		currentMp := &expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "existing-pool-1",
+			},
+			Spec: expv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						Version: pointer.String(version),
+						Bootstrap: clusterv1.Bootstrap{
+							ConfigRef: contract.ObjToRef(currentBootstrapConfig),
+						},
+						InfrastructureRef: *contract.ObjToRef(currentInfrastructureMachinePool),
+					},
+				},
+			},
+		}
+		s.Current.MachinePools = map[string]*scope.MachinePoolState{
+			"big-pool-of-machines": {
+				Object:                          currentMp,
+				BootstrapObject:                 currentBootstrapConfig,
+				InfrastructureMachinePoolObject: currentInfrastructureMachinePool,
+			},
+		}
+
+		actual, err := computeMachinePool(ctx, s, nil, mpTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		actualMp := actual.Object
+		g.Expect(actualMp.Name).To(Equal("existing-pool-1"))
+		g.Expect(actualMp.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal("existing-bootstrap-config"))
+		g.Expect(actualMp.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("existing-infrastructure-machine-pool"))
+	})
+
+	t.Run("If there is no MachinePool class, it returns an error", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+
+		mpTopology := clusterv1.MachinePoolTopology{
+			Class: "windows-worker",
+			Name:  "big-pool-of-machines",
+		}
+
+		_, err := computeMachinePool(ctx, s, nil, mpTopology)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestComputeMachineHealthCheck(t *testing.T) {
+	maxUnhealthy := intstr.FromString("50%")
+	check := &clusterv1.MachineHealthCheckClass{
+		UnhealthyConditions: []clusterv1.UnhealthyCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: metav1.Duration{Duration: 5 * time.Minute}},
+			{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Timeout: metav1.Duration{Duration: 5 * time.Minute}},
+		},
+		MaxUnhealthy:       &maxUnhealthy,
+		NodeStartupTimeout: &metav1.Duration{Duration: 15 * time.Minute},
+	}
+	md := testtypes.NewMachineDeploymentBuilder(metav1.NamespaceDefault, "cluster1-md1").Build()
+
+	t.Run("Computes the MachineHealthCheck for a MachineDeployment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		selector := selectorForMachineDeploymentMHC("cluster1", "md1")
+		got := computeMachineHealthCheck(md, selector, "cluster1", check)
+
+		g.Expect(got.Name).To(Equal(md.Name))
+		g.Expect(got.Namespace).To(Equal(md.Namespace))
+		g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "cluster1"))
+		g.Expect(got.Labels).To(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+		g.Expect(got.Spec.ClusterName).To(Equal("cluster1"))
+		g.Expect(got.Spec.Selector.MatchLabels).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachineDeploymentLabelName, "md1"))
+		g.Expect(got.Spec.UnhealthyConditions).To(Equal(check.UnhealthyConditions))
+		g.Expect(got.Spec.MaxUnhealthy).To(Equal(check.MaxUnhealthy))
+		g.Expect(got.Spec.NodeStartupTimeout).To(Equal(check.NodeStartupTimeout))
+
+		// The MachineHealthCheckClass must not be shared with the generated MachineHealthCheck.
+		got.Spec.UnhealthyConditions[0].Status = corev1.ConditionTrue
+		g.Expect(check.UnhealthyConditions[0].Status).To(Equal(corev1.ConditionUnknown))
+	})
+
+	t.Run("Computes the MachineHealthCheck for the control plane machines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		controlPlane := testtypes.NewControlPlaneBuilder(metav1.NamespaceDefault, "cluster1-cp").Build()
+		got := computeMachineHealthCheck(controlPlane, selectorForControlPlaneMHC("cluster1"), "cluster1", check)
+
+		g.Expect(got.Name).To(Equal(controlPlane.GetName()))
+		g.Expect(got.Spec.Selector.MatchLabels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "cluster1"))
+		g.Expect(got.Spec.Selector.MatchLabels).To(HaveKey(clusterv1.MachineControlPlaneLabelName))
+	})
+}
+
 func TestComputeMachineDeploymentVersion(t *testing.T) {
 	controlPlaneStable122 := testtypes.NewControlPlaneBuilder("test1", "cp1").
 		WithSpecFields(map[string]interface{}{
@@ -1080,6 +1423,18 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 			topologyVersion:               "v1.2.3",
 			expectedVersion:               "v1.2.2",
 		},
+		{
+			name: "should return machine deployment's spec.template.spec.version if the machine deployment rollout is paused",
+			currentMachineDeploymentState: &scope.MachineDeploymentState{Object: testtypes.NewMachineDeploymentBuilder("test1", "md-current").
+				WithVersion("v1.2.2").
+				WithAnnotations(map[string]string{clusterv1.ClusterTopologyMachineDeploymentRolloutPausedAnnotation: ""}).
+				Build()},
+			machineDeploymentsStateMap: machineDeploymentsStateStable,
+			currentControlPlane:        controlPlaneStable123,
+			desiredControlPlane:        controlPlaneDesired,
+			topologyVersion:            "v1.2.3",
+			expectedVersion:            "v1.2.2",
+		},
 		{
 			name:                          "should return cluster.spec.topology.version if the control plane is not upgrading, not scaling, not ready to upgrade and none of the machine deployments are rolling out",
 			currentMachineDeploymentState: &scope.MachineDeploymentState{Object: testtypes.NewMachineDeploymentBuilder("test1", "md-current").WithVersion("v1.2.2").Build()},
@@ -1116,6 +1471,87 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 	}
 }
 
+func TestComputeAndInjectVariables(t *testing.T) {
+	infrastructureClusterTemplate := testtypes.NewInfrastructureClusterTemplateBuilder(metav1.NamespaceDefault, "template1").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.region": "$(variables.region)"}).
+		Build()
+	controlPlaneTemplate := testtypes.NewControlPlaneTemplateBuilder(metav1.NamespaceDefault, "template1").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.fakeSetting": "$(variables.region)-cp"}).
+		Build()
+	workerInfrastructureMachineTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "linux-worker-inframachinetemplate").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.instanceType": "$(variables.instanceType)"}).
+		Build()
+	clusterClass := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(infrastructureClusterTemplate).
+		WithControlPlaneTemplate(controlPlaneTemplate).
+		WithVariables(
+			clusterv1.ClusterClassVariable{
+				Name:     "region",
+				Required: true,
+				Schema:   clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"}},
+			},
+			clusterv1.ClusterClassVariable{
+				Name: "instanceType",
+				Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+					Type:    "string",
+					Default: &apiextensionsv1.JSON{Raw: []byte(`"m5.large"`)},
+				}},
+			},
+		).
+		Build()
+
+	newBlueprint := func(variables ...clusterv1.ClusterVariable) *scope.ClusterBlueprint {
+		return &scope.ClusterBlueprint{
+			Topology:                      &clusterv1.Topology{Variables: variables},
+			ClusterClass:                  clusterClass,
+			InfrastructureClusterTemplate: infrastructureClusterTemplate.DeepCopy(),
+			ControlPlane: &scope.ControlPlaneBlueprint{
+				Template: controlPlaneTemplate.DeepCopy(),
+			},
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+				"linux-worker": {
+					InfrastructureMachineTemplate: workerInfrastructureMachineTemplate.DeepCopy(),
+				},
+			},
+		}
+	}
+
+	t.Run("Injects values and defaults into the templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(clusterv1.ClusterVariable{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}})
+		values, err := computeVariables(blueprint)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(injectVariables(blueprint, values)).To(Succeed())
+
+		region, _, err := unstructured.NestedString(blueprint.InfrastructureClusterTemplate.Object, "spec", "template", "spec", "region")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(region).To(Equal("us-east-1"))
+
+		fakeSetting, _, err := unstructured.NestedString(blueprint.ControlPlane.Template.Object, "spec", "template", "spec", "fakeSetting")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(fakeSetting).To(Equal("us-east-1-cp"))
+
+		instanceType, _, err := unstructured.NestedString(blueprint.MachineDeployments["linux-worker"].InfrastructureMachineTemplate.Object, "spec", "template", "spec", "instanceType")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(instanceType).To(Equal("m5.large"))
+	})
+	t.Run("Fails if a required variable is not set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint()
+		_, err := computeVariables(blueprint)
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("Fails if a variable does not comply to the schema", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(clusterv1.ClusterVariable{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}})
+		_, err := computeVariables(blueprint)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
 func TestTemplateToObject(t *testing.T) {
 	template := testtypes.NewInfrastructureClusterTemplateBuilder(metav1.NamespaceDefault, "infrastructureClusterTemplate").
 		WithSpecFields(map[string]interface{}{"spec.template.spec.fakeSetting": true}).
@@ -1217,6 +1653,20 @@ func TestTemplateToTemplate(t *testing.T) {
 			obj:         obj,
 		})
 	})
+	t.Run("Clones a template from another namespace into the namespace of the Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+		templateInOtherNamespace := testtypes.NewInfrastructureClusterTemplateBuilder("catalog", "infrastructureClusterTemplate").
+			Build()
+		obj := templateToTemplate(templateToInput{
+			template:              templateInOtherNamespace,
+			templateClonedFromRef: fakeRef1,
+			cluster:               cluster,
+			namePrefix:            cluster.Name,
+			currentObjectRef:      nil,
+		})
+		g.Expect(obj).ToNot(BeNil())
+		g.Expect(obj.GetNamespace()).To(Equal(cluster.Namespace))
+	})
 }
 
 type assertTemplateInput struct {
@@ -23,8 +23,9 @@ import (
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
+	"sigs.k8s.io/cluster-api/util/contract"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -65,9 +66,15 @@ func NewHelper(original, modified client.Object, c client.Client, opts ...Helper
 		return nil, errors.Wrap(err, "failed to marshal modified object to json")
 	}
 
+	// Remove from the original object the values in the authoritative paths which are not defined in the modified object.
+	originalWithoutAuthoritativeJSON, err := removeAuthoritativePaths(originalJSON, modifiedJSON, helperOptions.authoritativePaths)
+	if err != nil {
+		return nil, err
+	}
+
 	// Apply the modified object to the original one, merging the values of both;
 	// in case of conflicts, values from the modified object are preserved.
-	originalWithModifiedJSON, err := jsonpatch.MergePatch(originalJSON, modifiedJSON)
+	originalWithModifiedJSON, err := jsonpatch.MergePatch(originalWithoutAuthoritativeJSON, modifiedJSON)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to apply modified json to original json")
 	}
@@ -93,6 +100,36 @@ func NewHelper(original, modified client.Object, c client.Client, opts ...Helper
 	}, nil
 }
 
+// removeAuthoritativePaths removes from the original object the values in the authoritative paths
+// which are not defined in the modified object.
+func removeAuthoritativePaths(originalJSON, modifiedJSON []byte, authoritativePaths []contract.Path) ([]byte, error) {
+	if len(authoritativePaths) == 0 {
+		return originalJSON, nil
+	}
+
+	originalMap := make(map[string]interface{})
+	if err := json.Unmarshal(originalJSON, &originalMap); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal original object")
+	}
+	modifiedMap := make(map[string]interface{})
+	if err := json.Unmarshal(modifiedJSON, &modifiedMap); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal modified object")
+	}
+
+	for _, path := range authoritativePaths {
+		if _, found, _ := unstructured.NestedFieldNoCopy(modifiedMap, path...); found {
+			continue
+		}
+		unstructured.RemoveNestedField(originalMap, path...)
+	}
+
+	originalJSON, err := json.Marshal(originalMap)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal original object")
+	}
+	return originalJSON, nil
+}
+
 // filterPatch removes from the patch diffs not in the allowed paths.
 func filterPatch(patch []byte, allowedPaths, ignorePaths []contract.Path) ([]byte, error) {
 	// converts the patch into a Map
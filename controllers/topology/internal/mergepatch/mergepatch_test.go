@@ -21,7 +21,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
+	"sigs.k8s.io/cluster-api/util/contract"
 )
 
 func TestNewHelper(t *testing.T) {
@@ -323,6 +323,58 @@ func TestNewHelper(t *testing.T) {
 			wantPatch:      []byte("{}"),
 		},
 
+		// Authoritative fields
+
+		{
+			name: "Authoritative fields only in original are removed",
+			original: &unstructured.Unstructured{ // current
+				Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]interface{}{
+							"foo": "bar",
+							"baz": "qux",
+						},
+					},
+				},
+			},
+			modified: &unstructured.Unstructured{ // desired
+				Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]interface{}{
+							"baz": "qux",
+						},
+					},
+				},
+			},
+			options:        []HelperOption{AuthoritativePaths{contract.Path{"metadata", "annotations", "foo"}}},
+			wantHasChanges: true,
+			wantPatch:      []byte("{\"metadata\":{\"annotations\":{\"foo\":null}}}"),
+		},
+		{
+			name: "Authoritative fields both in original and in modified, align to modified when different",
+			original: &unstructured.Unstructured{ // current
+				Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]interface{}{
+							"foo": "bar-changed",
+						},
+					},
+				},
+			},
+			modified: &unstructured.Unstructured{ // desired
+				Object: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"annotations": map[string]interface{}{
+							"foo": "bar",
+						},
+					},
+				},
+			},
+			options:        []HelperOption{AuthoritativePaths{contract.Path{"metadata", "annotations", "foo"}}},
+			wantHasChanges: true,
+			wantPatch:      []byte("{\"metadata\":{\"annotations\":{\"foo\":\"bar\"}}}"),
+		},
+
 		// More tests
 		{
 			name: "No changes",
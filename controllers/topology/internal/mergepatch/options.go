@@ -16,7 +16,7 @@ limitations under the License.
 
 package mergepatch
 
-import "sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
+import "sigs.k8s.io/cluster-api/util/contract"
 
 // HelperOption is some configuration that modifies options for Helper.
 type HelperOption interface {
@@ -26,7 +26,8 @@ type HelperOption interface {
 
 // HelperOptions contains options for Helper.
 type HelperOptions struct {
-	ignorePaths []contract.Path
+	ignorePaths        []contract.Path
+	authoritativePaths []contract.Path
 }
 
 // ApplyOptions applies the given patch options on these options,
@@ -45,3 +46,13 @@ type IgnorePaths []contract.Path
 func (i IgnorePaths) ApplyToHelper(opts *HelperOptions) {
 	opts.ignorePaths = i
 }
+
+// AuthoritativePaths instruct the Helper to consider the modified object authoritative for the given paths;
+// if a value exists in the original object for one of those paths but not in the modified object, the
+// value is removed, while by default values not defined in the modified object are preserved.
+type AuthoritativePaths []contract.Path
+
+// ApplyToHelper applies this configuration to the given helper options.
+func (a AuthoritativePaths) ApplyToHelper(opts *HelperOptions) {
+	opts.authoritativePaths = a
+}
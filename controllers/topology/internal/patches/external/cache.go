@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"sync"
+	"time"
+)
+
+// responseCache caches the responses of external patch extensions for a limited amount of time, so the
+// extensions are not called on every reconcile when the templates and the variables did not change.
+// NOTE: Cached responses are shared, so they must not be modified by the callers.
+type responseCache struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]*responseCacheEntry
+}
+
+// responseCacheEntry is a cached response together with its expiration time.
+type responseCacheEntry struct {
+	response  *GeneratePatchesResponse
+	expiresAt time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]*responseCacheEntry{},
+	}
+}
+
+// Get returns the response cached for the given key, if not expired.
+func (c *responseCache) Get(key string) (*GeneratePatchesResponse, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// Set caches the response for the given key; expired entries are pruned, so the cache
+// does not grow indefinitely with responses for templates or variables which are not used anymore.
+func (c *responseCache) Set(key string, response *GeneratePatchesResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = &responseCacheEntry{
+		response:  response,
+		expiresAt: now.Add(c.ttl),
+	}
+}
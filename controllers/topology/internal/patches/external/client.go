@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// defaultTimeout is the timeout for calls to extensions not defining timeoutSeconds.
+	defaultTimeout = 10 * time.Second
+
+	// defaultCacheTTL is the time discovery results and responses of extensions are cached.
+	defaultCacheTTL = 10 * time.Minute
+
+	// maxResponseBytes is the maximum size of the responses read from extensions.
+	maxResponseBytes = 3 * 1024 * 1024
+)
+
+// Client calls external patch extensions.
+// Discovery results and responses are cached, so extensions are called again only if the request changes
+// or the cached entries expire.
+type Client struct {
+	lock       sync.Mutex
+	cacheTTL   time.Duration
+	transports map[string]*http.Transport
+	discovered map[string]time.Time
+	responses  *responseCache
+}
+
+// NewClient returns a Client for external patch extensions.
+func NewClient() *Client {
+	return newClient(defaultCacheTTL)
+}
+
+func newClient(cacheTTL time.Duration) *Client {
+	return &Client{
+		cacheTTL:   cacheTTL,
+		transports: map[string]*http.Transport{},
+		discovered: map[string]time.Time{},
+		responses:  newResponseCache(cacheTTL),
+	}
+}
+
+// GeneratePatches calls the extension defined in the given ExternalPatchDefinition to generate patches
+// for the templates in the request. Before the first call, and every time the discovery result expires,
+// the extension is checked to implement the API version and the handler used by the Client.
+// NOTE: The returned response could be shared with other callers, so it must not be modified.
+func (c *Client) GeneratePatches(ctx context.Context, definition *clusterv1.ExternalPatchDefinition, request *GeneratePatchesRequest) (*GeneratePatchesResponse, error) {
+	baseURL, err := extensionURL(definition.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := c.httpClient(definition)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create client for extension %s", baseURL)
+	}
+
+	request.APIVersion = APIVersion
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal request")
+	}
+
+	key := cacheKey(baseURL, body)
+	if response, ok := c.responses.Get(key); ok {
+		return response, nil
+	}
+
+	if err := c.discover(ctx, httpClient, baseURL); err != nil {
+		return nil, err
+	}
+
+	response := &GeneratePatchesResponse{}
+	if err := call(ctx, httpClient, http.MethodPost, baseURL+generatePatchesPath, body, response); err != nil {
+		return nil, errors.Wrapf(err, "failed to call extension %s", baseURL)
+	}
+	if response.Status != ResponseStatusSuccess {
+		return nil, errors.Errorf("extension %s failed to generate patches: %s", baseURL, response.Message)
+	}
+
+	c.responses.Set(key, response)
+	return response, nil
+}
+
+// discover checks that the extension implements the API version and the handler used by the Client.
+func (c *Client) discover(ctx context.Context, httpClient *http.Client, baseURL string) error {
+	c.lock.Lock()
+	expiresAt, ok := c.discovered[baseURL]
+	c.lock.Unlock()
+	if ok && time.Now().Before(expiresAt) {
+		return nil
+	}
+
+	response := &DiscoveryResponse{}
+	if err := call(ctx, httpClient, http.MethodGet, baseURL+discoveryPath, nil, response); err != nil {
+		return errors.Wrapf(err, "failed to discover extension %s", baseURL)
+	}
+	if response.APIVersion != APIVersion {
+		return errors.Errorf("extension %s implements API version %q, but %q is required", baseURL, response.APIVersion, APIVersion)
+	}
+	implemented := false
+	for _, handler := range response.Handlers {
+		if handler == GeneratePatchesHandler {
+			implemented = true
+			break
+		}
+	}
+	if !implemented {
+		return errors.Errorf("extension %s does not implement the %s handler", baseURL, GeneratePatchesHandler)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.discovered[baseURL] = time.Now().Add(c.cacheTTL)
+	return nil
+}
+
+// httpClient returns an http.Client for the extension, using the timeout and the CA bundle of the definition.
+// NOTE: Transports are shared across calls using the same CA bundle, so connections can be reused.
+func (c *Client) httpClient(definition *clusterv1.ExternalPatchDefinition) (*http.Client, error) {
+	timeout := defaultTimeout
+	if definition.TimeoutSeconds != nil {
+		timeout = time.Duration(*definition.TimeoutSeconds) * time.Second
+	}
+
+	caBundle := definition.ClientConfig.CABundle
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	transport, ok := c.transports[string(caBundle)]
+	if !ok {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(caBundle) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(caBundle) {
+				return nil, errors.New("failed to parse caBundle")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		c.transports[string(caBundle)] = transport
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// extensionURL returns the URL of the extension, without trailing slash.
+func extensionURL(config clusterv1.ExternalPatchClientConfig) (string, error) {
+	if config.URL != nil {
+		return strings.TrimSuffix(*config.URL, "/"), nil
+	}
+
+	if service := config.Service; service != nil {
+		port := int32(443)
+		if service.Port != nil {
+			port = *service.Port
+		}
+		u := url.URL{
+			Scheme: "https",
+			Host:   net.JoinHostPort(fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace), strconv.Itoa(int(port))),
+		}
+		if service.Path != nil {
+			u.Path = *service.Path
+		}
+		return strings.TrimSuffix(u.String(), "/"), nil
+	}
+
+	return "", errors.New("one of url or service must be set in clientConfig")
+}
+
+// cacheKey returns the key used to cache the response of an extension for a request.
+func cacheKey(baseURL string, body []byte) string {
+	hash := sha256.New()
+	_, _ = hash.Write([]byte(baseURL))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// call sends a request to an extension and unmarshals the response into the given object.
+func call(ctx context.Context, httpClient *http.Client, method, endpoint string, body []byte, into interface{}) error {
+	var bodyReader io.Reader = http.NoBody
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bodyReader)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// fakeExtension is an external patch extension recording the calls to its handlers.
+type fakeExtension struct {
+	handlers        []string
+	status          ResponseStatus
+	delay           time.Duration
+	discoveryCalls  int32
+	generateCalls   int32
+	lock            sync.Mutex
+	receivedRequest *GeneratePatchesRequest
+}
+
+func (e *fakeExtension) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1" + discoveryPath:
+		atomic.AddInt32(&e.discoveryCalls, 1)
+		_ = json.NewEncoder(w).Encode(&DiscoveryResponse{APIVersion: APIVersion, Handlers: e.handlers})
+	case "/v1" + generatePatchesPath:
+		atomic.AddInt32(&e.generateCalls, 1)
+		time.Sleep(e.delay)
+		request := &GeneratePatchesRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		e.lock.Lock()
+		e.receivedRequest = request
+		e.lock.Unlock()
+		_ = json.NewEncoder(w).Encode(&GeneratePatchesResponse{
+			APIVersion: APIVersion,
+			Status:     e.status,
+			Message:    "message",
+			Items: []GeneratePatchesResponseItem{{
+				UID:       "infrastructureCluster",
+				PatchType: JSONMergePatchType,
+				Patch:     []byte(`{"spec":{"template":{"spec":{"region":"eu-west-1"}}}}`),
+			}},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestClientGeneratePatches(t *testing.T) {
+	newServer := func(extension *fakeExtension) (*httptest.Server, *clusterv1.ExternalPatchDefinition) {
+		server := httptest.NewTLSServer(extension)
+		caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		return server, &clusterv1.ExternalPatchDefinition{
+			ClientConfig: clusterv1.ExternalPatchClientConfig{
+				URL:      pointer.String(server.URL + "/v1/"),
+				CABundle: caBundle,
+			},
+			TimeoutSeconds: pointer.Int32(1),
+		}
+	}
+	newRequest := func(settings map[string]string) *GeneratePatchesRequest {
+		return &GeneratePatchesRequest{
+			Settings:  settings,
+			Variables: map[string]interface{}{"region": "eu-west-1"},
+			Items:     []GeneratePatchesRequestItem{{UID: "infrastructureCluster", HolderReference: HolderReference{Kind: InfrastructureClusterHolder}}},
+		}
+	}
+
+	t.Run("Discovers the extension, generates patches and caches the response", func(t *testing.T) {
+		g := NewWithT(t)
+
+		extension := &fakeExtension{handlers: []string{GeneratePatchesHandler}, status: ResponseStatusSuccess}
+		server, definition := newServer(extension)
+		defer server.Close()
+		c := NewClient()
+
+		response, err := c.GeneratePatches(context.Background(), definition, newRequest(map[string]string{"profile": "default"}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(response.Items).To(HaveLen(1))
+		g.Expect(response.Items[0].UID).To(Equal("infrastructureCluster"))
+		extension.lock.Lock()
+		g.Expect(extension.receivedRequest.APIVersion).To(Equal(APIVersion))
+		g.Expect(extension.receivedRequest.Settings).To(Equal(map[string]string{"profile": "default"}))
+		extension.lock.Unlock()
+		g.Expect(atomic.LoadInt32(&extension.discoveryCalls)).To(Equal(int32(1)))
+		g.Expect(atomic.LoadInt32(&extension.generateCalls)).To(Equal(int32(1)))
+
+		// The same request is served from the cache.
+		_, err = c.GeneratePatches(context.Background(), definition, newRequest(map[string]string{"profile": "default"}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(atomic.LoadInt32(&extension.generateCalls)).To(Equal(int32(1)))
+
+		// A different request calls the extension again, without repeating discovery.
+		_, err = c.GeneratePatches(context.Background(), definition, newRequest(map[string]string{"profile": "large"}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(atomic.LoadInt32(&extension.discoveryCalls)).To(Equal(int32(1)))
+		g.Expect(atomic.LoadInt32(&extension.generateCalls)).To(Equal(int32(2)))
+	})
+
+	t.Run("Fails if the extension does not implement the GeneratePatches handler", func(t *testing.T) {
+		g := NewWithT(t)
+
+		extension := &fakeExtension{status: ResponseStatusSuccess}
+		server, definition := newServer(extension)
+		defer server.Close()
+
+		_, err := NewClient().GeneratePatches(context.Background(), definition, newRequest(nil))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(atomic.LoadInt32(&extension.generateCalls)).To(Equal(int32(0)))
+	})
+
+	t.Run("Fails and does not cache the response if the extension returns a failure", func(t *testing.T) {
+		g := NewWithT(t)
+
+		extension := &fakeExtension{handlers: []string{GeneratePatchesHandler}, status: ResponseStatusFailure}
+		server, definition := newServer(extension)
+		defer server.Close()
+		c := NewClient()
+
+		_, err := c.GeneratePatches(context.Background(), definition, newRequest(nil))
+		g.Expect(err).To(HaveOccurred())
+		_, err = c.GeneratePatches(context.Background(), definition, newRequest(nil))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(atomic.LoadInt32(&extension.generateCalls)).To(Equal(int32(2)))
+	})
+
+	t.Run("Fails if the extension does not respond within the timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		extension := &fakeExtension{handlers: []string{GeneratePatchesHandler}, status: ResponseStatusSuccess, delay: 2 * time.Second}
+		server, definition := newServer(extension)
+		defer server.Close()
+
+		_, err := NewClient().GeneratePatches(context.Background(), definition, newRequest(nil))
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("Fails if the serving certificate of the extension is not trusted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		extension := &fakeExtension{handlers: []string{GeneratePatchesHandler}, status: ResponseStatusSuccess}
+		server, definition := newServer(extension)
+		defer server.Close()
+		definition.ClientConfig.CABundle = nil
+
+		_, err := NewClient().GeneratePatches(context.Background(), definition, newRequest(nil))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(atomic.LoadInt32(&extension.discoveryCalls)).To(Equal(int32(0)))
+	})
+}
+
+func TestExtensionURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  clusterv1.ExternalPatchClientConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "URL without trailing slash",
+			config: clusterv1.ExternalPatchClientConfig{URL: pointer.String("https://patches.example.com/v1/")},
+			want:   "https://patches.example.com/v1",
+		},
+		{
+			name:   "Service with default port",
+			config: clusterv1.ExternalPatchClientConfig{Service: &clusterv1.ExternalPatchServiceReference{Namespace: "extensions", Name: "patches"}},
+			want:   "https://patches.extensions.svc:443",
+		},
+		{
+			name: "Service with port and path",
+			config: clusterv1.ExternalPatchClientConfig{Service: &clusterv1.ExternalPatchServiceReference{
+				Namespace: "extensions",
+				Name:      "patches",
+				Port:      pointer.Int32(8443),
+				Path:      pointer.String("/v1"),
+			}},
+			want: "https://patches.extensions.svc:8443/v1",
+		},
+		{
+			name:    "Neither URL nor Service",
+			config:  clusterv1.ExternalPatchClientConfig{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := extensionURL(tt.config)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	c := newResponseCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	response := &GeneratePatchesResponse{Status: ResponseStatusSuccess}
+	c.Set("key", response)
+
+	got, ok := c.Get("key")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(got).To(BeIdenticalTo(response))
+
+	_, ok = c.Get("other")
+	g.Expect(ok).To(BeFalse())
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("key")
+	g.Expect(ok).To(BeFalse())
+	g.Expect(c.entries).To(BeEmpty())
+}
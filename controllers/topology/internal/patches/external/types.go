@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// APIVersion is the version of the API implemented by external patch extensions.
+	APIVersion = "patches.topology.cluster.x-k8s.io/v1alpha1"
+
+	// GeneratePatchesHandler is the name of the handler generating patches; external patch extensions
+	// must list it in the discovery response.
+	GeneratePatchesHandler = "GeneratePatches"
+
+	// discoveryPath is the path of the discovery handler, relative to the URL of the extension.
+	discoveryPath = "/discovery"
+
+	// generatePatchesPath is the path of the handler generating patches, relative to the URL of the extension.
+	generatePatchesPath = "/generatepatches"
+)
+
+// DiscoveryResponse is the response of the discovery handler of an external patch extension.
+type DiscoveryResponse struct {
+	// APIVersion is the version of the API implemented by the extension.
+	APIVersion string `json:"apiVersion"`
+
+	// Handlers is the list of the handlers implemented by the extension.
+	Handlers []string `json:"handlers"`
+}
+
+// GeneratePatchesRequest is the request sent to an external patch extension to generate patches.
+type GeneratePatchesRequest struct {
+	// APIVersion is the version of the API used for the request.
+	APIVersion string `json:"apiVersion"`
+
+	// Settings are the settings defined for the patch in the ClusterClass.
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// Variables are the values of the variables defined in the Cluster topology, including builtin variables.
+	Variables map[string]interface{} `json:"variables,omitempty"`
+
+	// Items are the templates which can be patched.
+	Items []GeneratePatchesRequestItem `json:"items"`
+}
+
+// GeneratePatchesRequestItem is a template which can be patched by an external patch extension.
+type GeneratePatchesRequestItem struct {
+	// UID identifies the template in the request; patches in the response refer to the template by UID.
+	UID string `json:"uid"`
+
+	// HolderReference describes where the template is referenced in the ClusterClass.
+	HolderReference HolderReference `json:"holderReference"`
+
+	// Object is the template.
+	Object runtime.RawExtension `json:"object"`
+}
+
+// HolderReference describes where a template is referenced in a ClusterClass.
+type HolderReference struct {
+	// Kind is the kind of the reference, e.g. InfrastructureCluster or MachineDeploymentBootstrap.
+	Kind HolderKind `json:"kind"`
+
+	// MachineDeploymentClass is the name of the MachineDeployment class referencing the template, if any.
+	MachineDeploymentClass string `json:"machineDeploymentClass,omitempty"`
+}
+
+// HolderKind is the kind of a reference to a template in a ClusterClass.
+type HolderKind string
+
+const (
+	// InfrastructureClusterHolder is the template referenced in spec.infrastructure.
+	InfrastructureClusterHolder HolderKind = "InfrastructureCluster"
+
+	// ControlPlaneHolder is the template referenced in spec.controlPlane.
+	ControlPlaneHolder HolderKind = "ControlPlane"
+
+	// ControlPlaneInfrastructureMachineHolder is the template referenced in spec.controlPlane.machineInfrastructure.
+	ControlPlaneInfrastructureMachineHolder HolderKind = "ControlPlaneInfrastructureMachine"
+
+	// MachineDeploymentBootstrapHolder is the bootstrap template of a MachineDeployment class.
+	MachineDeploymentBootstrapHolder HolderKind = "MachineDeploymentBootstrap"
+
+	// MachineDeploymentInfrastructureMachineHolder is the infrastructure machine template of a MachineDeployment class.
+	MachineDeploymentInfrastructureMachineHolder HolderKind = "MachineDeploymentInfrastructureMachine"
+)
+
+// GeneratePatchesResponse is the response of an external patch extension to a GeneratePatchesRequest.
+type GeneratePatchesResponse struct {
+	// APIVersion is the version of the API used for the response.
+	APIVersion string `json:"apiVersion"`
+
+	// Status of the call; Failure is returned if the extension failed to generate the patches.
+	Status ResponseStatus `json:"status"`
+
+	// Message is a human readable description of the status of the call.
+	Message string `json:"message,omitempty"`
+
+	// Items are the patches generated for the templates of the request.
+	Items []GeneratePatchesResponseItem `json:"items,omitempty"`
+}
+
+// ResponseStatus is the status of a call to an external patch extension.
+type ResponseStatus string
+
+const (
+	// ResponseStatusSuccess is returned if the call to the extension succeeded.
+	ResponseStatusSuccess ResponseStatus = "Success"
+
+	// ResponseStatusFailure is returned if the call to the extension failed.
+	ResponseStatusFailure ResponseStatus = "Failure"
+)
+
+// GeneratePatchesResponseItem is a patch generated by an external patch extension for a template.
+type GeneratePatchesResponseItem struct {
+	// UID identifies the template the patch should be applied to.
+	UID string `json:"uid"`
+
+	// PatchType is the type of the patch.
+	PatchType PatchType `json:"patchType"`
+
+	// Patch is the patch, encoded according to the patch type.
+	Patch []byte `json:"patch"`
+}
+
+// PatchType is the type of a patch generated by an external patch extension.
+type PatchType string
+
+const (
+	// JSONPatchType is a JSON patch, as defined in RFC 6902.
+	JSONPatchType PatchType = "JSONPatch"
+
+	// JSONMergePatchType is a JSON merge patch, as defined in RFC 7386.
+	JSONMergePatchType PatchType = "JSONMergePatch"
+)
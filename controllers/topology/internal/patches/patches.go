@@ -0,0 +1,323 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patches
+
+import (
+	"context"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/patches/external"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/variables"
+)
+
+// ExternalPatchGenerator generates patches by calling external patch extensions.
+type ExternalPatchGenerator interface {
+	GeneratePatches(ctx context.Context, definition *clusterv1.ExternalPatchDefinition, request *external.GeneratePatchesRequest) (*external.GeneratePatchesResponse, error)
+}
+
+// Apply applies the patches defined in the ClusterClass to the templates of the blueprint.
+// Patches are applied in the order they are defined in the ClusterClass; values for the
+// patches can be read from the given variables. External patches are generated using the given
+// ExternalPatchGenerator.
+// NOTE: Only changes to the spec of the templates are preserved.
+func Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, values map[string]interface{}, generator ExternalPatchGenerator) error {
+	for _, patch := range blueprint.ClusterClass.Spec.Patches {
+		if patch.External != nil {
+			if err := applyExternal(ctx, blueprint, patch, values, generator); err != nil {
+				return errors.Wrapf(err, "failed to apply patch %q", patch.Name)
+			}
+			continue
+		}
+		for _, definition := range patch.Definitions {
+			for _, template := range matchingTemplates(blueprint, definition.Selector) {
+				if err := applyDefinition(template, definition, values); err != nil {
+					return errors.Wrapf(err, "failed to apply patch %q to %s %s", patch.Name, template.GetKind(), template.GetName())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchingTemplates returns the templates of the blueprint matching the selector.
+func matchingTemplates(blueprint *scope.ClusterBlueprint, selector clusterv1.PatchSelector) []*unstructured.Unstructured {
+	var templates []*unstructured.Unstructured
+
+	add := func(template *unstructured.Unstructured) {
+		if template == nil || template.GetAPIVersion() != selector.APIVersion || template.GetKind() != selector.Kind {
+			return
+		}
+		for _, t := range templates {
+			if t == template {
+				return
+			}
+		}
+		templates = append(templates, template)
+	}
+
+	if selector.MatchResources.InfrastructureCluster {
+		add(blueprint.InfrastructureClusterTemplate)
+	}
+
+	if selector.MatchResources.ControlPlane && blueprint.ControlPlane != nil {
+		add(blueprint.ControlPlane.Template)
+		add(blueprint.ControlPlane.InfrastructureMachineTemplate)
+	}
+
+	if selector.MatchResources.MachineDeploymentClass != nil {
+		for _, name := range selector.MatchResources.MachineDeploymentClass.Names {
+			if md, ok := blueprint.MachineDeployments[name]; ok {
+				add(md.BootstrapTemplate)
+				add(md.InfrastructureMachineTemplate)
+			}
+		}
+	}
+
+	return templates
+}
+
+// applyExternal applies the patches generated by an external patch extension to the templates of the blueprint.
+// The patches of an extension are applied all together; if the extension cannot be called or any of the
+// returned patches cannot be applied, no template is patched and, if the failure policy is Ignore, the patch is skipped.
+func applyExternal(ctx context.Context, blueprint *scope.ClusterBlueprint, patch clusterv1.ClusterClassPatch, values map[string]interface{}, generator ExternalPatchGenerator) error {
+	err := generateAndApplyExternal(ctx, blueprint, patch.External, values, generator)
+	if err == nil {
+		return nil
+	}
+	if patch.External.FailurePolicy != nil && *patch.External.FailurePolicy == clusterv1.ExternalPatchFailurePolicyIgnore {
+		tlog.LoggerFrom(ctx).Infof("Ignoring failure of external patch %q: %v", patch.Name, err)
+		return nil
+	}
+	return err
+}
+
+func generateAndApplyExternal(ctx context.Context, blueprint *scope.ClusterBlueprint, definition *clusterv1.ExternalPatchDefinition, values map[string]interface{}, generator ExternalPatchGenerator) error {
+	if generator == nil {
+		return errors.New("external patches are not supported")
+	}
+
+	templates := externalPatchTemplates(blueprint)
+	request := &external.GeneratePatchesRequest{
+		Settings:  definition.Settings,
+		Variables: values,
+	}
+	for _, t := range templates {
+		raw, err := json.Marshal(t.template.Object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %s %s", t.template.GetKind(), t.template.GetName())
+		}
+		request.Items = append(request.Items, external.GeneratePatchesRequestItem{
+			UID:             t.uid,
+			HolderReference: t.holder,
+			Object:          runtime.RawExtension{Raw: raw},
+		})
+	}
+
+	response, err := generator.GeneratePatches(ctx, definition, request)
+	if err != nil {
+		return err
+	}
+
+	// Apply the patches to copies of the templates, so the templates are changed only if all the patches can be applied.
+	patched := map[string]*unstructured.Unstructured{}
+	for _, item := range response.Items {
+		template, ok := patched[item.UID]
+		if !ok {
+			for _, t := range templates {
+				if t.uid == item.UID {
+					template = t.template.DeepCopy()
+					break
+				}
+			}
+			if template == nil {
+				return errors.Errorf("extension returned a patch for unknown template %q", item.UID)
+			}
+			patched[item.UID] = template
+		}
+		if err := applyGeneratedPatch(template, item); err != nil {
+			return errors.Wrapf(err, "failed to apply generated patch to %s %s", template.GetKind(), template.GetName())
+		}
+	}
+
+	for _, t := range templates {
+		template, ok := patched[t.uid]
+		if !ok {
+			continue
+		}
+		if spec, ok := template.Object["spec"]; ok {
+			t.template.Object["spec"] = spec
+		} else {
+			delete(t.template.Object, "spec")
+		}
+	}
+	return nil
+}
+
+// externalPatchTemplate is a template of the blueprint which can be patched by an external patch extension.
+type externalPatchTemplate struct {
+	uid      string
+	holder   external.HolderReference
+	template *unstructured.Unstructured
+}
+
+// externalPatchTemplates returns the templates of the blueprint which can be patched by external patch extensions,
+// sorted in a deterministic order, so requests for the same blueprint are always the same.
+func externalPatchTemplates(blueprint *scope.ClusterBlueprint) []externalPatchTemplate {
+	var templates []externalPatchTemplate
+	add := func(uid string, holder external.HolderReference, template *unstructured.Unstructured) {
+		if template != nil {
+			templates = append(templates, externalPatchTemplate{uid: uid, holder: holder, template: template})
+		}
+	}
+
+	add("infrastructureCluster", external.HolderReference{Kind: external.InfrastructureClusterHolder}, blueprint.InfrastructureClusterTemplate)
+	if blueprint.ControlPlane != nil {
+		add("controlPlane", external.HolderReference{Kind: external.ControlPlaneHolder}, blueprint.ControlPlane.Template)
+		add("controlPlaneInfrastructureMachine", external.HolderReference{Kind: external.ControlPlaneInfrastructureMachineHolder}, blueprint.ControlPlane.InfrastructureMachineTemplate)
+	}
+
+	classes := make([]string, 0, len(blueprint.MachineDeployments))
+	for class := range blueprint.MachineDeployments {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		md := blueprint.MachineDeployments[class]
+		add("machineDeployment/"+class+"/bootstrap", external.HolderReference{Kind: external.MachineDeploymentBootstrapHolder, MachineDeploymentClass: class}, md.BootstrapTemplate)
+		add("machineDeployment/"+class+"/infrastructureMachine", external.HolderReference{Kind: external.MachineDeploymentInfrastructureMachineHolder, MachineDeploymentClass: class}, md.InfrastructureMachineTemplate)
+	}
+
+	return templates
+}
+
+// applyGeneratedPatch applies a patch generated by an external patch extension to a template.
+func applyGeneratedPatch(template *unstructured.Unstructured, item external.GeneratePatchesResponseItem) error {
+	data, err := json.Marshal(template.Object)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal template")
+	}
+
+	switch item.PatchType {
+	case external.JSONPatchType:
+		patch, err := jsonpatch.DecodePatch(item.Patch)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode JSON patch")
+		}
+		if data, err = patch.Apply(data); err != nil {
+			return errors.Wrap(err, "failed to apply JSON patch")
+		}
+	case external.JSONMergePatchType:
+		if data, err = jsonpatch.MergePatch(data, item.Patch); err != nil {
+			return errors.Wrap(err, "failed to apply merge patch")
+		}
+	default:
+		return errors.Errorf("unsupported patch type %q", item.PatchType)
+	}
+
+	return setPatchedSpec(template, data)
+}
+
+// applyDefinition applies the JSON patches and the merge patch of a patch definition to a template.
+func applyDefinition(template *unstructured.Unstructured, definition clusterv1.PatchDefinition, values map[string]interface{}) error {
+	data, err := json.Marshal(template.Object)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal template")
+	}
+
+	if len(definition.JSONPatches) > 0 {
+		operations := make([]map[string]interface{}, 0, len(definition.JSONPatches))
+		for _, p := range definition.JSONPatches {
+			operation := map[string]interface{}{
+				"op":   p.Op,
+				"path": p.Path,
+			}
+			if p.Op != "remove" {
+				value, err := patchValue(p, values)
+				if err != nil {
+					return err
+				}
+				operation["value"] = value
+			}
+			operations = append(operations, operation)
+		}
+
+		patchData, err := json.Marshal(operations)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal JSON patches")
+		}
+		patch, err := jsonpatch.DecodePatch(patchData)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode JSON patches")
+		}
+		if data, err = patch.Apply(data); err != nil {
+			return errors.Wrap(err, "failed to apply JSON patches")
+		}
+	}
+
+	if definition.MergePatch != nil {
+		if data, err = jsonpatch.MergePatch(data, definition.MergePatch.Raw); err != nil {
+			return errors.Wrap(err, "failed to apply merge patch")
+		}
+	}
+
+	return setPatchedSpec(template, data)
+}
+
+// setPatchedSpec sets the spec of the template from the patched template data.
+func setPatchedSpec(template *unstructured.Unstructured, data []byte) error {
+	patched := map[string]interface{}{}
+	if err := json.Unmarshal(data, &patched); err != nil {
+		return errors.Wrap(err, "failed to unmarshal patched template")
+	}
+
+	// Only preserve changes to the spec; everything else, e.g. the metadata, is owned by the template.
+	spec, ok := patched["spec"]
+	if !ok {
+		delete(template.Object, "spec")
+		return nil
+	}
+	template.Object["spec"] = spec
+	return nil
+}
+
+// patchValue returns the value of a JSON patch, either inline or from a variable.
+func patchValue(patch clusterv1.JSONPatch, values map[string]interface{}) (interface{}, error) {
+	if patch.ValueFrom != nil {
+		value, err := variables.Lookup(patch.ValueFrom.Variable, values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get value for patch %s %s", patch.Op, patch.Path)
+		}
+		return value, nil
+	}
+
+	if patch.Value == nil {
+		return nil, errors.Errorf("value must be set for patch %s %s", patch.Op, patch.Path)
+	}
+	var value interface{}
+	if err := json.Unmarshal(patch.Value.Raw, &value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal value for patch %s %s", patch.Op, patch.Path)
+	}
+	return value, nil
+}
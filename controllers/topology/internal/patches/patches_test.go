@@ -0,0 +1,359 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patches
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/patches/external"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/internal/testtypes"
+)
+
+func TestApply(t *testing.T) {
+	newBlueprint := func(patches ...clusterv1.ClusterClassPatch) *scope.ClusterBlueprint {
+		infrastructureClusterTemplate := testtypes.NewInfrastructureClusterTemplateBuilder(metav1.NamespaceDefault, "infraClusterTemplate1").
+			WithSpecFields(map[string]interface{}{"spec.template.spec.region": "us-east-1"}).
+			Build()
+		controlPlaneInfrastructureMachineTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "cpInfraMachineTemplate1").
+			WithSpecFields(map[string]interface{}{"spec.template.spec.instanceType": "small"}).
+			Build()
+		controlPlaneTemplate := testtypes.NewControlPlaneTemplateBuilder(metav1.NamespaceDefault, "controlPlaneTemplate1").
+			Build()
+		workerInfrastructureMachineTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "workerInfraMachineTemplate1").
+			WithSpecFields(map[string]interface{}{"spec.template.spec.instanceType": "small"}).
+			Build()
+		workerBootstrapTemplate := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "workerBootstrapTemplate1").
+			Build()
+
+		return &scope.ClusterBlueprint{
+			Topology: &clusterv1.Topology{},
+			ClusterClass: &clusterv1.ClusterClass{
+				Spec: clusterv1.ClusterClassSpec{Patches: patches},
+			},
+			InfrastructureClusterTemplate: infrastructureClusterTemplate,
+			ControlPlane: &scope.ControlPlaneBlueprint{
+				Template:                      controlPlaneTemplate,
+				InfrastructureMachineTemplate: controlPlaneInfrastructureMachineTemplate,
+			},
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+				"linux-worker": {
+					InfrastructureMachineTemplate: workerInfrastructureMachineTemplate,
+					BootstrapTemplate:             workerBootstrapTemplate,
+				},
+			},
+		}
+	}
+	infrastructureMachineTemplateSelector := func(match clusterv1.PatchSelectorMatch) clusterv1.PatchSelector {
+		return clusterv1.PatchSelector{
+			APIVersion:     testtypes.InfrastructureGroupVersion.String(),
+			Kind:           testtypes.GenericInfrastructureMachineKind,
+			MatchResources: match,
+		}
+	}
+	values := map[string]interface{}{
+		"machine": map[string]interface{}{"instanceType": "large"},
+	}
+	ctx := context.Background()
+
+	t.Run("Applies JSON patches to the templates matching the selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(clusterv1.ClusterClassPatch{
+			Name: "instanceType",
+			Definitions: []clusterv1.PatchDefinition{
+				{
+					Selector: infrastructureMachineTemplateSelector(clusterv1.PatchSelectorMatch{
+						MachineDeploymentClass: &clusterv1.PatchSelectorMatchMachineDeploymentClass{Names: []string{"linux-worker"}},
+					}),
+					JSONPatches: []clusterv1.JSONPatch{
+						{Op: "replace", Path: "/spec/template/spec/instanceType", ValueFrom: &clusterv1.JSONPatchValue{Variable: "machine.instanceType"}},
+						{Op: "add", Path: "/spec/template/spec/rootVolumeSize", Value: &apiextensionsv1.JSON{Raw: []byte(`100`)}},
+					},
+				},
+			},
+		})
+		g.Expect(Apply(ctx, blueprint, values, nil)).To(Succeed())
+
+		worker := blueprint.MachineDeployments["linux-worker"].InfrastructureMachineTemplate
+		g.Expect(nestedField(worker.Object, "spec", "template", "spec", "instanceType")).To(Equal("large"))
+		g.Expect(nestedField(worker.Object, "spec", "template", "spec", "rootVolumeSize")).To(Equal(int64(100)))
+
+		// The control plane InfrastructureMachineTemplate is not selected, and must not be patched.
+		controlPlane := blueprint.ControlPlane.InfrastructureMachineTemplate
+		g.Expect(nestedField(controlPlane.Object, "spec", "template", "spec", "instanceType")).To(Equal("small"))
+	})
+
+	t.Run("Applies merge patches to the templates matching the selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(clusterv1.ClusterClassPatch{
+			Name: "controlPlane",
+			Definitions: []clusterv1.PatchDefinition{
+				{
+					Selector: infrastructureMachineTemplateSelector(clusterv1.PatchSelectorMatch{ControlPlane: true}),
+					MergePatch: &apiextensionsv1.JSON{Raw: []byte(
+						`{"metadata":{"name":"changed"},"spec":{"template":{"spec":{"instanceType":"medium","tags":["control-plane"]}}}}`,
+					)},
+				},
+			},
+		})
+		g.Expect(Apply(ctx, blueprint, values, nil)).To(Succeed())
+
+		controlPlane := blueprint.ControlPlane.InfrastructureMachineTemplate
+		g.Expect(nestedField(controlPlane.Object, "spec", "template", "spec", "instanceType")).To(Equal("medium"))
+		g.Expect(nestedField(controlPlane.Object, "spec", "template", "spec", "tags")).To(Equal([]interface{}{"control-plane"}))
+		// Only changes to the spec are preserved.
+		g.Expect(controlPlane.GetName()).To(Equal("cpInfraMachineTemplate1"))
+
+		worker := blueprint.MachineDeployments["linux-worker"].InfrastructureMachineTemplate
+		g.Expect(nestedField(worker.Object, "spec", "template", "spec", "instanceType")).To(Equal("small"))
+	})
+
+	t.Run("Applies patches in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		match := clusterv1.PatchSelectorMatch{ControlPlane: true}
+		blueprint := newBlueprint(
+			clusterv1.ClusterClassPatch{
+				Name: "first",
+				Definitions: []clusterv1.PatchDefinition{{
+					Selector:    infrastructureMachineTemplateSelector(match),
+					JSONPatches: []clusterv1.JSONPatch{{Op: "replace", Path: "/spec/template/spec/instanceType", Value: &apiextensionsv1.JSON{Raw: []byte(`"medium"`)}}},
+				}},
+			},
+			clusterv1.ClusterClassPatch{
+				Name: "second",
+				Definitions: []clusterv1.PatchDefinition{{
+					Selector:    infrastructureMachineTemplateSelector(match),
+					JSONPatches: []clusterv1.JSONPatch{{Op: "replace", Path: "/spec/template/spec/instanceType", Value: &apiextensionsv1.JSON{Raw: []byte(`"xlarge"`)}}},
+				}},
+			},
+		)
+		g.Expect(Apply(ctx, blueprint, values, nil)).To(Succeed())
+
+		controlPlane := blueprint.ControlPlane.InfrastructureMachineTemplate
+		g.Expect(nestedField(controlPlane.Object, "spec", "template", "spec", "instanceType")).To(Equal("xlarge"))
+	})
+
+	t.Run("Ignores templates with a different kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(clusterv1.ClusterClassPatch{
+			Name: "region",
+			Definitions: []clusterv1.PatchDefinition{{
+				Selector: clusterv1.PatchSelector{
+					APIVersion:     testtypes.InfrastructureGroupVersion.String(),
+					Kind:           "AnotherInfrastructureClusterTemplate",
+					MatchResources: clusterv1.PatchSelectorMatch{InfrastructureCluster: true},
+				},
+				JSONPatches: []clusterv1.JSONPatch{{Op: "replace", Path: "/spec/template/spec/region", Value: &apiextensionsv1.JSON{Raw: []byte(`"eu-west-1"`)}}},
+			}},
+		})
+		g.Expect(Apply(ctx, blueprint, values, nil)).To(Succeed())
+
+		g.Expect(nestedField(blueprint.InfrastructureClusterTemplate.Object, "spec", "template", "spec", "region")).To(Equal("us-east-1"))
+	})
+
+	t.Run("Fails if a patch cannot be applied", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(clusterv1.ClusterClassPatch{
+			Name: "missing",
+			Definitions: []clusterv1.PatchDefinition{{
+				Selector:    infrastructureMachineTemplateSelector(clusterv1.PatchSelectorMatch{ControlPlane: true}),
+				JSONPatches: []clusterv1.JSONPatch{{Op: "replace", Path: "/spec/template/spec/doesNotExist/field", Value: &apiextensionsv1.JSON{Raw: []byte(`"a"`)}}},
+			}},
+		})
+		g.Expect(Apply(ctx, blueprint, values, nil)).ToNot(Succeed())
+	})
+
+	t.Run("Fails if a variable is not set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(clusterv1.ClusterClassPatch{
+			Name: "region",
+			Definitions: []clusterv1.PatchDefinition{{
+				Selector:    infrastructureMachineTemplateSelector(clusterv1.PatchSelectorMatch{ControlPlane: true}),
+				JSONPatches: []clusterv1.JSONPatch{{Op: "replace", Path: "/spec/template/spec/instanceType", ValueFrom: &clusterv1.JSONPatchValue{Variable: "region"}}},
+			}},
+		})
+		g.Expect(Apply(ctx, blueprint, values, nil)).ToNot(Succeed())
+	})
+}
+
+func TestApplyExternal(t *testing.T) {
+	newBlueprint := func(patches ...clusterv1.ClusterClassPatch) *scope.ClusterBlueprint {
+		return &scope.ClusterBlueprint{
+			Topology: &clusterv1.Topology{},
+			ClusterClass: &clusterv1.ClusterClass{
+				Spec: clusterv1.ClusterClassSpec{Patches: patches},
+			},
+			InfrastructureClusterTemplate: testtypes.NewInfrastructureClusterTemplateBuilder(metav1.NamespaceDefault, "infraClusterTemplate1").
+				WithSpecFields(map[string]interface{}{"spec.template.spec.region": "us-east-1"}).
+				Build(),
+			ControlPlane: &scope.ControlPlaneBlueprint{
+				Template: testtypes.NewControlPlaneTemplateBuilder(metav1.NamespaceDefault, "controlPlaneTemplate1").
+					Build(),
+			},
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+				"linux-worker": {
+					InfrastructureMachineTemplate: testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "workerInfraMachineTemplate1").
+						WithSpecFields(map[string]interface{}{"spec.template.spec.instanceType": "small"}).
+						Build(),
+					BootstrapTemplate: testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "workerBootstrapTemplate1").
+						Build(),
+				},
+			},
+		}
+	}
+	externalPatch := func(failurePolicy clusterv1.ExternalPatchFailurePolicy) clusterv1.ClusterClassPatch {
+		return clusterv1.ClusterClassPatch{
+			Name: "external",
+			External: &clusterv1.ExternalPatchDefinition{
+				ClientConfig:  clusterv1.ExternalPatchClientConfig{URL: pointer.String("https://patches.example.com")},
+				FailurePolicy: &failurePolicy,
+				Settings:      map[string]string{"profile": "default"},
+			},
+		}
+	}
+	values := map[string]interface{}{
+		"machine": map[string]interface{}{"instanceType": "large"},
+	}
+	ctx := context.Background()
+
+	t.Run("Applies patches generated by the extension", func(t *testing.T) {
+		g := NewWithT(t)
+
+		generator := &fakeExternalPatchGenerator{
+			response: &external.GeneratePatchesResponse{
+				Status: external.ResponseStatusSuccess,
+				Items: []external.GeneratePatchesResponseItem{
+					{
+						UID:       "machineDeployment/linux-worker/infrastructureMachine",
+						PatchType: external.JSONPatchType,
+						Patch:     []byte(`[{"op":"replace","path":"/spec/template/spec/instanceType","value":"large"}]`),
+					},
+					{
+						UID:       "infrastructureCluster",
+						PatchType: external.JSONMergePatchType,
+						Patch:     []byte(`{"spec":{"template":{"spec":{"region":"eu-west-1"}}},"metadata":{"name":"ignored"}}`),
+					},
+				},
+			},
+		}
+		blueprint := newBlueprint(externalPatch(clusterv1.ExternalPatchFailurePolicyFail))
+		g.Expect(Apply(ctx, blueprint, values, generator)).To(Succeed())
+
+		g.Expect(generator.request.Settings).To(Equal(map[string]string{"profile": "default"}))
+		g.Expect(generator.request.Variables).To(Equal(values))
+		uids := []string{}
+		for _, item := range generator.request.Items {
+			uids = append(uids, item.UID)
+		}
+		g.Expect(uids).To(Equal([]string{
+			"infrastructureCluster",
+			"controlPlane",
+			"machineDeployment/linux-worker/bootstrap",
+			"machineDeployment/linux-worker/infrastructureMachine",
+		}))
+
+		g.Expect(nestedField(blueprint.MachineDeployments["linux-worker"].InfrastructureMachineTemplate.Object, "spec", "template", "spec", "instanceType")).To(Equal("large"))
+		g.Expect(nestedField(blueprint.InfrastructureClusterTemplate.Object, "spec", "template", "spec", "region")).To(Equal("eu-west-1"))
+		// Only changes to the spec are preserved.
+		g.Expect(blueprint.InfrastructureClusterTemplate.GetName()).To(Equal("infraClusterTemplate1"))
+	})
+
+	t.Run("Does not patch any template if one of the generated patches cannot be applied", func(t *testing.T) {
+		g := NewWithT(t)
+
+		generator := &fakeExternalPatchGenerator{
+			response: &external.GeneratePatchesResponse{
+				Status: external.ResponseStatusSuccess,
+				Items: []external.GeneratePatchesResponseItem{
+					{
+						UID:       "infrastructureCluster",
+						PatchType: external.JSONMergePatchType,
+						Patch:     []byte(`{"spec":{"template":{"spec":{"region":"eu-west-1"}}}}`),
+					},
+					{
+						UID:       "doesNotExist",
+						PatchType: external.JSONMergePatchType,
+						Patch:     []byte(`{}`),
+					},
+				},
+			},
+		}
+		blueprint := newBlueprint(externalPatch(clusterv1.ExternalPatchFailurePolicyFail))
+		g.Expect(Apply(ctx, blueprint, values, generator)).ToNot(Succeed())
+
+		g.Expect(nestedField(blueprint.InfrastructureClusterTemplate.Object, "spec", "template", "spec", "region")).To(Equal("us-east-1"))
+	})
+
+	t.Run("Fails if the extension fails and the failure policy is Fail", func(t *testing.T) {
+		g := NewWithT(t)
+
+		generator := &fakeExternalPatchGenerator{err: errors.New("connection refused")}
+		blueprint := newBlueprint(externalPatch(clusterv1.ExternalPatchFailurePolicyFail))
+		g.Expect(Apply(ctx, blueprint, values, generator)).ToNot(Succeed())
+	})
+
+	t.Run("Skips the patch if the extension fails and the failure policy is Ignore", func(t *testing.T) {
+		g := NewWithT(t)
+
+		generator := &fakeExternalPatchGenerator{err: errors.New("connection refused")}
+		blueprint := newBlueprint(externalPatch(clusterv1.ExternalPatchFailurePolicyIgnore))
+		g.Expect(Apply(ctx, blueprint, values, generator)).To(Succeed())
+
+		g.Expect(nestedField(blueprint.InfrastructureClusterTemplate.Object, "spec", "template", "spec", "region")).To(Equal("us-east-1"))
+	})
+
+	t.Run("Fails if external patches are not supported", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blueprint := newBlueprint(externalPatch(clusterv1.ExternalPatchFailurePolicyFail))
+		g.Expect(Apply(ctx, blueprint, values, nil)).ToNot(Succeed())
+	})
+}
+
+// fakeExternalPatchGenerator records the request and returns the given response or error.
+type fakeExternalPatchGenerator struct {
+	request  *external.GeneratePatchesRequest
+	response *external.GeneratePatchesResponse
+	err      error
+}
+
+func (f *fakeExternalPatchGenerator) GeneratePatches(_ context.Context, _ *clusterv1.ExternalPatchDefinition, request *external.GeneratePatchesRequest) (*external.GeneratePatchesResponse, error) {
+	f.request = request
+	return f.response, f.err
+}
+
+func nestedField(obj map[string]interface{}, fields ...string) interface{} {
+	value, _, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package scope
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -38,6 +39,9 @@ type ClusterBlueprint struct {
 
 	// MachineDeployments holds the MachineDeploymentBlueprints derived from ClusterClass.
 	MachineDeployments map[string]*MachineDeploymentBlueprint
+
+	// MachinePools holds the MachinePoolBlueprints derived from ClusterClass.
+	MachinePools map[string]*MachinePoolBlueprint
 }
 
 // ControlPlaneBlueprint holds the templates required for computing the desired state of a managed control plane.
@@ -47,6 +51,9 @@ type ControlPlaneBlueprint struct {
 
 	// InfrastructureMachineTemplate holds the infrastructure machine template for the control plane, if defined in the ClusterClass.
 	InfrastructureMachineTemplate *unstructured.Unstructured
+
+	// MachineHealthCheck holds the MachineHealthCheckClass for the control plane, if defined in the ClusterClass.
+	MachineHealthCheck *clusterv1.MachineHealthCheckClass
 }
 
 // MachineDeploymentBlueprint holds the templates required for computing the desired state of a managed MachineDeployment;
@@ -62,6 +69,33 @@ type MachineDeploymentBlueprint struct {
 
 	// InfrastructureMachineTemplate holds the infrastructure machine template for a MachineDeployment referenced from ClusterClass.
 	InfrastructureMachineTemplate *unstructured.Unstructured
+
+	// MachineHealthCheck holds the MachineHealthCheckClass for a MachineDeployment, if defined in the ClusterClass.
+	MachineHealthCheck *clusterv1.MachineHealthCheckClass
+
+	// RolloutStrategy holds the default rollout strategy for a MachineDeployment, if defined in the ClusterClass.
+	RolloutStrategy *clusterv1.MachineDeploymentStrategy
+
+	// NodeLabels holds the default labels to be applied to the Nodes of a MachineDeployment, if defined in the ClusterClass.
+	NodeLabels map[string]string
+
+	// NodeTaints holds the default taints to be applied to the Nodes of a MachineDeployment, if defined in the ClusterClass.
+	NodeTaints []corev1.Taint
+}
+
+// MachinePoolBlueprint holds the templates required for computing the desired state of a managed MachinePool;
+// it also holds a copy of the MachinePool metadata from Cluster.Topology, thus providing all the required info
+// in a single place.
+type MachinePoolBlueprint struct {
+	// Metadata holds the metadata for a MachinePool.
+	// NOTE: This is a convenience copy of the metadata field from Cluster.Spec.Topology.Workers.MachinePools[x].
+	Metadata clusterv1.ObjectMeta
+
+	// BootstrapTemplate holds the bootstrap template for a MachinePool referenced from ClusterClass.
+	BootstrapTemplate *unstructured.Unstructured
+
+	// InfrastructureMachinePoolTemplate holds the infrastructure machine pool template for a MachinePool referenced from ClusterClass.
+	InfrastructureMachinePoolTemplate *unstructured.Unstructured
 }
 
 // HasControlPlaneInfrastructureMachine checks whether the clusterClass mandates the controlPlane has infrastructureMachines.
@@ -69,7 +103,82 @@ func (b *ClusterBlueprint) HasControlPlaneInfrastructureMachine() bool {
 	return b.ClusterClass.Spec.ControlPlane.MachineInfrastructure != nil && b.ClusterClass.Spec.ControlPlane.MachineInfrastructure.Ref != nil
 }
 
+// HasControlPlaneMachineHealthCheck checks whether the clusterClass defines a MachineHealthCheck for the controlPlane.
+func (b *ClusterBlueprint) HasControlPlaneMachineHealthCheck() bool {
+	return b.HasControlPlaneInfrastructureMachine() && b.ControlPlane != nil && b.ControlPlane.MachineHealthCheck != nil
+}
+
 // HasMachineDeployments checks whether the topology has MachineDeployments.
 func (b *ClusterBlueprint) HasMachineDeployments() bool {
 	return b.Topology.Workers != nil && len(b.Topology.Workers.MachineDeployments) > 0
 }
+
+// HasMachinePools checks whether the topology has MachinePools.
+func (b *ClusterBlueprint) HasMachinePools() bool {
+	return b.Topology.Workers != nil && len(b.Topology.Workers.MachinePools) > 0
+}
+
+// DeepCopy returns a deep copy of the ClusterBlueprint.
+func (b *ClusterBlueprint) DeepCopy() *ClusterBlueprint {
+	if b == nil {
+		return nil
+	}
+	out := &ClusterBlueprint{
+		Topology:                      b.Topology.DeepCopy(),
+		ClusterClass:                  b.ClusterClass.DeepCopy(),
+		InfrastructureClusterTemplate: b.InfrastructureClusterTemplate.DeepCopy(),
+	}
+	if b.ControlPlane != nil {
+		out.ControlPlane = &ControlPlaneBlueprint{
+			Template:                      b.ControlPlane.Template.DeepCopy(),
+			InfrastructureMachineTemplate: b.ControlPlane.InfrastructureMachineTemplate.DeepCopy(),
+			MachineHealthCheck:            b.ControlPlane.MachineHealthCheck.DeepCopy(),
+		}
+	}
+	if b.MachineDeployments != nil {
+		out.MachineDeployments = make(map[string]*MachineDeploymentBlueprint, len(b.MachineDeployments))
+		for class, md := range b.MachineDeployments {
+			if md == nil {
+				out.MachineDeployments[class] = nil
+				continue
+			}
+			mdCopy := &MachineDeploymentBlueprint{
+				BootstrapTemplate:             md.BootstrapTemplate.DeepCopy(),
+				InfrastructureMachineTemplate: md.InfrastructureMachineTemplate.DeepCopy(),
+				MachineHealthCheck:            md.MachineHealthCheck.DeepCopy(),
+				RolloutStrategy:               md.RolloutStrategy.DeepCopy(),
+			}
+			md.Metadata.DeepCopyInto(&mdCopy.Metadata)
+			if md.NodeLabels != nil {
+				mdCopy.NodeLabels = make(map[string]string, len(md.NodeLabels))
+				for k, v := range md.NodeLabels {
+					mdCopy.NodeLabels[k] = v
+				}
+			}
+			if md.NodeTaints != nil {
+				mdCopy.NodeTaints = make([]corev1.Taint, len(md.NodeTaints))
+				for i := range md.NodeTaints {
+					md.NodeTaints[i].DeepCopyInto(&mdCopy.NodeTaints[i])
+				}
+			}
+			out.MachineDeployments[class] = mdCopy
+		}
+	}
+
+	if b.MachinePools != nil {
+		out.MachinePools = make(map[string]*MachinePoolBlueprint, len(b.MachinePools))
+		for class, mp := range b.MachinePools {
+			if mp == nil {
+				out.MachinePools[class] = nil
+				continue
+			}
+			mpCopy := &MachinePoolBlueprint{
+				BootstrapTemplate:                 mp.BootstrapTemplate.DeepCopy(),
+				InfrastructureMachinePoolTemplate: mp.InfrastructureMachinePoolTemplate.DeepCopy(),
+			}
+			mp.Metadata.DeepCopyInto(&mpCopy.Metadata)
+			out.MachinePools[class] = mpCopy
+		}
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import "sync"
+
+// ReconcileTracker is a helper to capture the outcome of reconciling the components of a managed topology,
+// so it can be reported in the conditions of the Cluster.
+// NOTE: Mark methods are safe to be called concurrently, e.g. when reconciling MachineDeployments concurrently.
+type ReconcileTracker struct {
+	lock sync.Mutex
+
+	// ControlPlane holds the outcome of reconciling the control plane and its templates.
+	ControlPlane ReconcileResult
+
+	// MachineDeployments holds the outcome of reconciling the MachineDeployments, by MachineDeployment topology name.
+	MachineDeployments map[string]ReconcileResult
+}
+
+// ReconcileResult holds the outcome of reconciling a component of a managed topology.
+type ReconcileResult struct {
+	// Reconciled is true if reconciling the component has been attempted in the current reconcile.
+	Reconciled bool
+
+	// Err holds the error that occurred while reconciling the component, if any.
+	Err error
+}
+
+// NewReconcileTracker returns a reconcile tracker with empty tracking information.
+func NewReconcileTracker() *ReconcileTracker {
+	return &ReconcileTracker{
+		MachineDeployments: map[string]ReconcileResult{},
+	}
+}
+
+// MarkControlPlane records the outcome of reconciling the control plane.
+func (t *ReconcileTracker) MarkControlPlane(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.ControlPlane = ReconcileResult{Reconciled: true, Err: err}
+}
+
+// MarkMachineDeployment records the outcome of reconciling the MachineDeployment with the given topology name.
+func (t *ReconcileTracker) MarkMachineDeployment(mdTopologyName string, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.MachineDeployments[mdTopologyName] = ReconcileResult{Reconciled: true, Err: err}
+}
@@ -33,6 +33,9 @@ type Scope struct {
 
 	// UpgradeTracker holds information about ongoing upgrades in the managed topology.
 	UpgradeTracker *UpgradeTracker
+
+	// ReconcileTracker holds information about the outcome of reconciling the components of the managed topology.
+	ReconcileTracker *ReconcileTracker
 }
 
 // New returns a new Scope with only the cluster; while processing a request in the topology/ClusterReconciler controller
@@ -43,6 +46,7 @@ func New(cluster *clusterv1.Cluster) *Scope {
 		Current: &ClusterState{
 			Cluster: cluster,
 		},
-		UpgradeTracker: NewUpgradeTracker(),
+		UpgradeTracker:   NewUpgradeTracker(),
+		ReconcileTracker: NewReconcileTracker(),
 	}
 }
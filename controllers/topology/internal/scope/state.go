@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 )
 
 // ClusterState holds all the objects representing the state of a managed Cluster topology.
@@ -37,6 +38,9 @@ type ClusterState struct {
 
 	// MachineDeployments holds the machine deployments in the Cluster.
 	MachineDeployments MachineDeploymentsStateMap
+
+	// MachinePools holds the machine pools in the Cluster.
+	MachinePools MachinePoolsStateMap
 }
 
 // ControlPlaneState holds all the objects representing the state of a managed control plane.
@@ -46,6 +50,9 @@ type ControlPlaneState struct {
 
 	// InfrastructureMachineTemplate holds the infrastructure template referenced by the ControlPlane object.
 	InfrastructureMachineTemplate *unstructured.Unstructured
+
+	// MachineHealthCheck holds the MachineHealthCheck for the ControlPlane machines, if any.
+	MachineHealthCheck *clusterv1.MachineHealthCheck
 }
 
 // MachineDeploymentsStateMap holds a collection of MachineDeployment states.
@@ -72,6 +79,9 @@ type MachineDeploymentState struct {
 
 	// InfrastructureMachineTemplate holds the infrastructure machine template referenced by the MachineDeployment object.
 	InfrastructureMachineTemplate *unstructured.Unstructured
+
+	// MachineHealthCheck holds the MachineHealthCheck for the MachineDeployment machines, if any.
+	MachineHealthCheck *clusterv1.MachineHealthCheck
 }
 
 // IsRollingOut determines if the machine deployment is upgrading.
@@ -80,3 +90,18 @@ type MachineDeploymentState struct {
 func (md *MachineDeploymentState) IsRollingOut() bool {
 	return !mdutil.DeploymentComplete(md.Object, &md.Object.Status) || *md.Object.Spec.Replicas != md.Object.Status.ReadyReplicas
 }
+
+// MachinePoolsStateMap holds a collection of MachinePool states.
+type MachinePoolsStateMap map[string]*MachinePoolState
+
+// MachinePoolState holds all the objects representing the state of a managed pool.
+type MachinePoolState struct {
+	// Object holds the MachinePool object.
+	Object *expv1.MachinePool
+
+	// BootstrapObject holds the bootstrap object referenced by the MachinePool object.
+	BootstrapObject *unstructured.Unstructured
+
+	// InfrastructureMachinePoolObject holds the infrastructure machine pool object referenced by the MachinePool object.
+	InfrastructureMachinePoolObject *unstructured.Unstructured
+}
@@ -22,9 +22,17 @@ const maxMachineDeploymentUpgradeConcurrency = 1
 
 // UpgradeTracker is a helper to capture the upgrade status and make upgrade decisions.
 type UpgradeTracker struct {
+	ControlPlane       ControlPlaneUpgradeTracker
 	MachineDeployments MachineDeploymentUpgradeTracker
 }
 
+// ControlPlaneUpgradeTracker holds the current upgrade status of the ControlPlane.
+type ControlPlaneUpgradeTracker struct {
+	// MachineInfrastructureRotated is true if the InfrastructureMachineTemplate of the ControlPlane has been rotated
+	// in the current reconcile, thus triggering a rollout of the control plane machines.
+	MachineInfrastructureRotated bool
+}
+
 // MachineDeploymentUpgradeTracker holds the current upgrade status and makes upgrade
 // decisions for MachineDeployments.
 type MachineDeploymentUpgradeTracker struct {
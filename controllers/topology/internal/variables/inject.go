@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package variables
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// referenceRegex matches references to variables in the form $(variables.<name>[.<field>...]);
+// references can be escaped by using a double dollar sign, e.g. $$(variables.<name>).
+var referenceRegex = regexp.MustCompile(`\$?\$\(variables\.([^)]+)\)`)
+
+// Values returns the values of the variables of a Cluster topology, keyed by variable name.
+// Variables not set in the Cluster topology are defaulted to the default value from the ClusterClass, if any.
+// NOTE: This func assumes the variables have been validated using ValidateClusterVariables.
+func Values(clusterVariables []clusterv1.ClusterVariable, clusterClassVariables []clusterv1.ClusterClassVariable) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+
+	for _, variable := range clusterVariables {
+		var value interface{}
+		if err := json.Unmarshal(variable.Value.Raw, &value); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse value of variable %q", variable.Name)
+		}
+		values[variable.Name] = value
+	}
+
+	for _, definition := range clusterClassVariables {
+		if _, ok := values[definition.Name]; ok || definition.Schema.OpenAPIV3Schema.Default == nil {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(definition.Schema.OpenAPIV3Schema.Default.Raw, &value); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse default value of variable %q", definition.Name)
+		}
+		values[definition.Name] = value
+	}
+
+	return values, nil
+}
+
+// Inject replaces the references to variables in the spec of the given object with the corresponding values.
+// A string consisting of a single reference is replaced by the value of the variable, preserving its type,
+// while references embedded in a longer string are replaced by the value of the variable formatted as a string.
+func Inject(obj *unstructured.Unstructured, values map[string]interface{}) error {
+	spec, ok := obj.Object["spec"]
+	if !ok {
+		return nil
+	}
+
+	spec, err := inject(spec, values)
+	if err != nil {
+		return errors.Wrapf(err, "failed to inject variables into %s %s", obj.GetKind(), obj.GetName())
+	}
+	obj.Object["spec"] = spec
+	return nil
+}
+
+func inject(in interface{}, values map[string]interface{}) (interface{}, error) {
+	switch v := in.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			injected, err := inject(value, values)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = injected
+		}
+		return v, nil
+	case []interface{}:
+		for i, value := range v {
+			injected, err := inject(value, values)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = injected
+		}
+		return v, nil
+	case string:
+		return injectString(v, values)
+	default:
+		return in, nil
+	}
+}
+
+func injectString(in string, values map[string]interface{}) (interface{}, error) {
+	matches := referenceRegex.FindAllStringSubmatchIndex(in, -1)
+	if len(matches) == 0 {
+		return in, nil
+	}
+
+	// If the string consists of a single reference, replace it with the value preserving its type.
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(in) && !strings.HasPrefix(in, "$$") {
+		value, err := Lookup(in[matches[0][2]:matches[0][3]], values)
+		if err != nil {
+			return nil, err
+		}
+		return runtime.DeepCopyJSONValue(value), nil
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, match := range matches {
+		out.WriteString(in[last:match[0]])
+		last = match[1]
+
+		reference := in[match[0]:match[1]]
+		if strings.HasPrefix(reference, "$$") {
+			// Escaped reference, drop the leading dollar sign.
+			out.WriteString(reference[1:])
+			continue
+		}
+
+		value, err := Lookup(in[match[2]:match[3]], values)
+		if err != nil {
+			return nil, err
+		}
+		formatted, err := format(value)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(formatted)
+	}
+	out.WriteString(in[last:])
+	return out.String(), nil
+}
+
+// Lookup returns the value for a variable path in the form <name>[.<field>...].
+func Lookup(path string, values map[string]interface{}) (interface{}, error) {
+	fields := strings.Split(path, ".")
+
+	value, ok := values[fields[0]]
+	if !ok {
+		return nil, errors.Errorf("variable %q is not set", fields[0])
+	}
+	for i, f := range fields[1:] {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("variable %q: %q is not an object", path, strings.Join(fields[:i+1], "."))
+		}
+		if value, ok = object[f]; !ok {
+			return nil, errors.Errorf("variable %q: field %q is not set", path, f)
+		}
+	}
+	return value, nil
+}
+
+func format(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal variable value")
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package variables
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValues(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterClassVariables := []clusterv1.ClusterClassVariable{
+		{
+			Name:   "region",
+			Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"}},
+		},
+		{
+			Name: "instanceType",
+			Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+				Type:    "string",
+				Default: &apiextensionsv1.JSON{Raw: []byte(`"m5.large"`)},
+			}},
+		},
+		{
+			Name: "replicas",
+			Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+				Type:    "integer",
+				Default: &apiextensionsv1.JSON{Raw: []byte(`1`)},
+			}},
+		},
+		{
+			Name:   "zone",
+			Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"}},
+		},
+	}
+	clusterVariables := []clusterv1.ClusterVariable{
+		{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+		{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`3`)}},
+	}
+
+	values, err := Values(clusterVariables, clusterClassVariables)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(values).To(Equal(map[string]interface{}{
+		"region":       "us-east-1",
+		"instanceType": "m5.large",
+		"replicas":     int64(3),
+	}))
+}
+
+func TestInject(t *testing.T) {
+	values := map[string]interface{}{
+		"region":   "us-east-1",
+		"replicas": int64(3),
+		"machine": map[string]interface{}{
+			"type":  "m5.large",
+			"zones": []interface{}{"a", "b"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "Replace a reference preserving the type of the value",
+			spec: map[string]interface{}{
+				"replicas": "$(variables.replicas)",
+				"zones":    "$(variables.machine.zones)",
+			},
+			want: map[string]interface{}{
+				"replicas": int64(3),
+				"zones":    []interface{}{"a", "b"},
+			},
+		},
+		{
+			name: "Replace references embedded in strings",
+			spec: map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"instance": "$(variables.region)/$(variables.machine.type) x$(variables.replicas)",
+						"commands": []interface{}{"echo $(variables.region)", "echo $(hostname)"},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"instance": "us-east-1/m5.large x3",
+						"commands": []interface{}{"echo us-east-1", "echo $(hostname)"},
+					},
+				},
+			},
+		},
+		{
+			name: "Escaped references are not replaced",
+			spec: map[string]interface{}{
+				"value":   "$$(variables.region)",
+				"message": "region is $$(variables.region), not $(variables.region)",
+			},
+			want: map[string]interface{}{
+				"value":   "$(variables.region)",
+				"message": "region is $(variables.region), not us-east-1",
+			},
+		},
+		{
+			name: "Fails for variables not set",
+			spec: map[string]interface{}{
+				"zone": "$(variables.zone)",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fails for fields not set",
+			spec: map[string]interface{}{
+				"size": "$(variables.machine.size)",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fails for fields of values which are not objects",
+			spec: map[string]interface{}{
+				"size": "$(variables.region.size)",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"kind": "GenericInfrastructureMachineTemplate",
+				"spec": tt.spec,
+			}}
+
+			err := Inject(obj, values)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(obj.Object["spec"]).To(Equal(tt.want))
+		})
+	}
+}
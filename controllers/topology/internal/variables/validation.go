@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package variables
+
+import (
+	"fmt"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsvalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ValidateClusterVariables validates the variables of a Cluster topology against the variables defined in the ClusterClass.
+func ValidateClusterVariables(clusterVariables []clusterv1.ClusterVariable, clusterClassVariables []clusterv1.ClusterClassVariable, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	definitions := map[string]*clusterv1.ClusterClassVariable{}
+	for i := range clusterClassVariables {
+		definitions[clusterClassVariables[i].Name] = &clusterClassVariables[i]
+	}
+
+	// Ensure all the variables are defined in the ClusterClass and their values comply with the schema.
+	names := sets.String{}
+	for i, variable := range clusterVariables {
+		variablePath := fldPath.Index(i)
+		names.Insert(variable.Name)
+
+		definition, ok := definitions[variable.Name]
+		if !ok {
+			allErrs = append(allErrs, field.Invalid(variablePath.Child("name"), variable.Name, "variable is not defined in the ClusterClass"))
+			continue
+		}
+
+		allErrs = append(allErrs, validateClusterVariable(variable, definition, variablePath.Child("value"))...)
+	}
+
+	// Ensure all the required variables are set, unless the schema provides a default value.
+	for _, definition := range clusterClassVariables {
+		if definition.Required && !names.Has(definition.Name) && definition.Schema.OpenAPIV3Schema.Default == nil {
+			allErrs = append(allErrs, field.Required(fldPath, fmt.Sprintf("required variable %q must be set", definition.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateClusterVariable(variable clusterv1.ClusterVariable, definition *clusterv1.ClusterClassVariable, fldPath *field.Path) field.ErrorList {
+	var value interface{}
+	if err := json.Unmarshal(variable.Value.Raw, &value); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(variable.Value.Raw), fmt.Sprintf("variable %q could not be parsed: %v", variable.Name, err))}
+	}
+
+	schema, err := clusterv1.ConvertToAPIExtensionsJSONSchemaProps(&definition.Schema.OpenAPIV3Schema)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(variable.Value.Raw), fmt.Sprintf("invalid schema for variable %q: %v", variable.Name, err))}
+	}
+
+	validator, _, err := apiextensionsvalidation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: schema})
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(variable.Value.Raw), fmt.Sprintf("invalid schema for variable %q: %v", variable.Name, err))}
+	}
+
+	return apiextensionsvalidation.ValidateCustomResource(fldPath, value, validator)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package variables
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValidateClusterVariables(t *testing.T) {
+	clusterClassVariables := []clusterv1.ClusterClassVariable{
+		{
+			Name:     "region",
+			Required: true,
+			Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+				Type:      "string",
+				MinLength: pointer.Int64Ptr(1),
+			}},
+		},
+		{
+			Name:     "instanceType",
+			Required: true,
+			Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+				Type:    "string",
+				Default: &apiextensionsv1.JSON{Raw: []byte(`"m5.large"`)},
+			}},
+		},
+		{
+			Name: "machine",
+			Schema: clusterv1.VariableSchema{OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]clusterv1.JSONSchemaProps{
+					"replicas": {Type: "integer", Minimum: pointer.Int64Ptr(0)},
+					"zones":    {Type: "array", Items: &clusterv1.JSONSchemaProps{Type: "string"}},
+				},
+				Required: []string{"replicas"},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		clusterVariables []clusterv1.ClusterVariable
+		wantErr          bool
+	}{
+		{
+			name: "Valid variables",
+			clusterVariables: []clusterv1.ClusterVariable{
+				{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+				{Name: "machine", Value: apiextensionsv1.JSON{Raw: []byte(`{"replicas":3,"zones":["a","b"]}`)}},
+			},
+			wantErr: false,
+		},
+		{
+			name:             "Missing required variable",
+			clusterVariables: []clusterv1.ClusterVariable{},
+			wantErr:          true,
+		},
+		{
+			name: "Variable not defined in the ClusterClass",
+			clusterVariables: []clusterv1.ClusterVariable{
+				{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+				{Name: "zone", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1a"`)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Variable with wrong type",
+			clusterVariables: []clusterv1.ClusterVariable{
+				{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Variable violating the schema",
+			clusterVariables: []clusterv1.ClusterVariable{
+				{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`""`)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Object variable missing a required field",
+			clusterVariables: []clusterv1.ClusterVariable{
+				{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+				{Name: "machine", Value: apiextensionsv1.JSON{Raw: []byte(`{"zones":["a"]}`)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Object variable with nested field violating the schema",
+			clusterVariables: []clusterv1.ClusterVariable{
+				{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+				{Name: "machine", Value: apiextensionsv1.JSON{Raw: []byte(`{"replicas":-1}`)}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := ValidateClusterVariables(tt.clusterVariables, clusterClassVariables, field.NewPath("spec", "topology", "variables"))
+			if tt.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"sort"
+	"strconv"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+)
+
+// machineDeploymentRolloutTracker decides which MachineDeployments are allowed to pick up changes to their templates,
+// and thus to roll out new Machines, according to their rollout order and rollout paused annotations.
+type machineDeploymentRolloutTracker struct {
+	current scope.MachineDeploymentsStateMap
+
+	// rollingOut tracks the MachineDeployments which started a rollout during the current reconcile.
+	rollingOut map[string]bool
+}
+
+func newMachineDeploymentRolloutTracker(current scope.MachineDeploymentsStateMap) *machineDeploymentRolloutTracker {
+	return &machineDeploymentRolloutTracker{
+		current:    current,
+		rollingOut: map[string]bool{},
+	}
+}
+
+// Sort sorts the given MachineDeployment topology names by rollout order, and then by name.
+func (t *machineDeploymentRolloutTracker) Sort(mdTopologyNames []string) []string {
+	sorted := append([]string{}, mdTopologyNames...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		oi, oj := t.order(sorted[i]), t.order(sorted[j])
+		if oi != oj {
+			return oi < oj
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// Group sorts the given MachineDeployment topology names like Sort and groups them by rollout order.
+func (t *machineDeploymentRolloutTracker) Group(mdTopologyNames []string) [][]string {
+	var groups [][]string
+	for i, mdTopologyName := range t.Sort(mdTopologyNames) {
+		if i == 0 || t.order(mdTopologyName) != t.order(groups[len(groups)-1][0]) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], mdTopologyName)
+	}
+	return groups
+}
+
+// AllowRollout returns true if the MachineDeployment is allowed to roll out, i.e. its rollout is not paused and
+// none of the MachineDeployments with a lower rollout order is rolling out.
+func (t *machineDeploymentRolloutTracker) AllowRollout(mdTopologyName string) bool {
+	md, ok := t.current[mdTopologyName]
+	if !ok || md.Object == nil {
+		return true
+	}
+	if isMachineDeploymentRolloutPaused(md.Object) {
+		return false
+	}
+
+	order := t.order(mdTopologyName)
+	for name, other := range t.current {
+		if name == mdTopologyName || other.Object == nil || t.order(name) >= order {
+			continue
+		}
+		if t.rollingOut[name] || other.IsRollingOut() {
+			return false
+		}
+	}
+	return true
+}
+
+// MarkRollingOut records that the MachineDeployment started a rollout during the current reconcile.
+func (t *machineDeploymentRolloutTracker) MarkRollingOut(mdTopologyName string) {
+	t.rollingOut[mdTopologyName] = true
+}
+
+func (t *machineDeploymentRolloutTracker) order(mdTopologyName string) int {
+	md, ok := t.current[mdTopologyName]
+	if !ok || md.Object == nil {
+		return 0
+	}
+	return machineDeploymentRolloutOrder(md.Object)
+}
+
+// machineDeploymentRolloutOrder returns the rollout order of a MachineDeployment, as defined by the
+// ClusterTopologyMachineDeploymentRolloutOrderAnnotation; if the annotation is not set or invalid, 0 is returned.
+func machineDeploymentRolloutOrder(md *clusterv1.MachineDeployment) int {
+	value, ok := md.GetAnnotations()[clusterv1.ClusterTopologyMachineDeploymentRolloutOrderAnnotation]
+	if !ok {
+		return 0
+	}
+	order, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return order
+}
+
+// isMachineDeploymentRolloutPaused returns true if the MachineDeployment has the ClusterTopologyMachineDeploymentRolloutPausedAnnotation.
+func isMachineDeploymentRolloutPaused(md *clusterv1.MachineDeployment) bool {
+	_, ok := md.GetAnnotations()[clusterv1.ClusterTopologyMachineDeploymentRolloutPausedAnnotation]
+	return ok
+}
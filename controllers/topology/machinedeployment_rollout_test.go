@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/internal/testtypes"
+)
+
+func TestMachineDeploymentRolloutTracker(t *testing.T) {
+	stableStatus := clusterv1.MachineDeploymentStatus{
+		Replicas:          1,
+		UpdatedReplicas:   1,
+		AvailableReplicas: 1,
+		ReadyReplicas:     1,
+	}
+	newMachineDeploymentState := func(name string, annotations map[string]string, status clusterv1.MachineDeploymentStatus) *scope.MachineDeploymentState {
+		return &scope.MachineDeploymentState{
+			Object: testtypes.NewMachineDeploymentBuilder(metav1.NamespaceDefault, name).
+				WithReplicas(1).
+				WithAnnotations(annotations).
+				WithStatus(status).
+				Build(),
+		}
+	}
+	order := func(value string) map[string]string {
+		return map[string]string{clusterv1.ClusterTopologyMachineDeploymentRolloutOrderAnnotation: value}
+	}
+
+	t.Run("Sort by rollout order and then by name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tracker := newMachineDeploymentRolloutTracker(scope.MachineDeploymentsStateMap{
+			"md-a": newMachineDeploymentState("md-a", order("2"), stableStatus),
+			"md-b": newMachineDeploymentState("md-b", nil, stableStatus),
+			"md-c": newMachineDeploymentState("md-c", order("1"), stableStatus),
+			"md-d": newMachineDeploymentState("md-d", order("invalid"), stableStatus),
+		})
+		g.Expect(tracker.Sort([]string{"md-a", "md-b", "md-c", "md-d"})).To(Equal([]string{"md-b", "md-d", "md-c", "md-a"}))
+	})
+
+	t.Run("Group by rollout order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tracker := newMachineDeploymentRolloutTracker(scope.MachineDeploymentsStateMap{
+			"md-a": newMachineDeploymentState("md-a", order("2"), stableStatus),
+			"md-b": newMachineDeploymentState("md-b", nil, stableStatus),
+			"md-c": newMachineDeploymentState("md-c", order("1"), stableStatus),
+			"md-d": newMachineDeploymentState("md-d", order("invalid"), stableStatus),
+			"md-e": newMachineDeploymentState("md-e", order("1"), stableStatus),
+		})
+		g.Expect(tracker.Group([]string{"md-a", "md-b", "md-c", "md-d", "md-e"})).To(Equal([][]string{{"md-b", "md-d"}, {"md-c", "md-e"}, {"md-a"}}))
+		g.Expect(tracker.Group(nil)).To(BeEmpty())
+	})
+
+	t.Run("Allow rollout if no MachineDeployment with a lower rollout order is rolling out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tracker := newMachineDeploymentRolloutTracker(scope.MachineDeploymentsStateMap{
+			"md-a": newMachineDeploymentState("md-a", nil, stableStatus),
+			"md-b": newMachineDeploymentState("md-b", order("1"), clusterv1.MachineDeploymentStatus{}),
+			"md-c": newMachineDeploymentState("md-c", order("1"), stableStatus),
+		})
+		g.Expect(tracker.AllowRollout("md-a")).To(BeTrue())
+		g.Expect(tracker.AllowRollout("md-b")).To(BeTrue())
+		g.Expect(tracker.AllowRollout("md-c")).To(BeTrue())
+	})
+
+	t.Run("Defer rollout if a MachineDeployment with a lower rollout order is rolling out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tracker := newMachineDeploymentRolloutTracker(scope.MachineDeploymentsStateMap{
+			"md-a": newMachineDeploymentState("md-a", nil, clusterv1.MachineDeploymentStatus{}),
+			"md-b": newMachineDeploymentState("md-b", order("1"), stableStatus),
+		})
+		g.Expect(tracker.AllowRollout("md-a")).To(BeTrue())
+		g.Expect(tracker.AllowRollout("md-b")).To(BeFalse())
+	})
+
+	t.Run("Defer rollout if a MachineDeployment with a lower rollout order started rolling out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tracker := newMachineDeploymentRolloutTracker(scope.MachineDeploymentsStateMap{
+			"md-a": newMachineDeploymentState("md-a", nil, stableStatus),
+			"md-b": newMachineDeploymentState("md-b", order("1"), stableStatus),
+		})
+		g.Expect(tracker.AllowRollout("md-a")).To(BeTrue())
+		tracker.MarkRollingOut("md-a")
+		g.Expect(tracker.AllowRollout("md-b")).To(BeFalse())
+	})
+
+	t.Run("Defer rollout if the MachineDeployment rollout is paused", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tracker := newMachineDeploymentRolloutTracker(scope.MachineDeploymentsStateMap{
+			"md-a": newMachineDeploymentState("md-a", map[string]string{clusterv1.ClusterTopologyMachineDeploymentRolloutPausedAnnotation: ""}, stableStatus),
+		})
+		g.Expect(tracker.AllowRollout("md-a")).To(BeFalse())
+	})
+}
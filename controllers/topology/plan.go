@@ -0,0 +1,237 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
+	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// PlanAction defines the action the topology controller is going to perform on an object.
+type PlanAction string
+
+const (
+	// PlanActionCreate is used for objects that are going to be created.
+	PlanActionCreate = PlanAction("Create")
+
+	// PlanActionUpdate is used for objects that are going to be patched.
+	PlanActionUpdate = PlanAction("Update")
+
+	// PlanActionDelete is used for objects that are going to be deleted.
+	PlanActionDelete = PlanAction("Delete")
+)
+
+// PlannedChange is a change the topology controller is going to apply to an object.
+type PlannedChange struct {
+	// Action is the action that is going to be performed on the object.
+	Action PlanAction
+
+	// Object is the object to be created, or the current object to be patched or deleted.
+	// NOTE: The GroupVersionKind of the object is always set.
+	Object client.Object
+
+	// Patch is the merge patch that is going to be applied to the object; it is set only for PlanActionUpdate.
+	Patch []byte
+}
+
+// Plan is the list of changes required to align a Cluster to its managed topology, in the same order
+// the topology controller is going to apply them.
+type Plan struct {
+	Changes []PlannedChange
+}
+
+// HasChanges returns true if the plan contains at least one change.
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// PlanOptions defines options for computing a Plan.
+type PlanOptions struct {
+	// ServerSideDryRun, if set, sends all the changes to the API server as dry-run requests, so they are
+	// defaulted and validated by admission webhooks without being persisted.
+	ServerSideDryRun bool
+}
+
+// ComputePlan computes the changes required to align a Cluster to its managed topology without applying them;
+// this allows to preview the impact of a change to a ClusterClass or to a Cluster topology before it is rolled out.
+// NOTE: The client must be able to read all the objects in the topology, including templates and provider objects.
+func ComputePlan(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, options PlanOptions) (*Plan, error) {
+	if cluster.Spec.Topology == nil {
+		return nil, errors.Errorf("failed to compute plan for %s: the Cluster does not use a managed topology", tlog.KObj{Obj: cluster})
+	}
+
+	recorder := newPlanRecorder(c, options.ServerSideDryRun)
+	r := &ClusterReconciler{
+		Client:                    recorder,
+		UnstructuredCachingClient: recorder,
+	}
+
+	var err error
+	s := scope.New(cluster)
+	s.Blueprint, err = r.getBlueprint(ctx, s.Current.Cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading the ClusterClass")
+	}
+
+	s.Current, err = r.getCurrentState(ctx, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading current state of the Cluster topology")
+	}
+
+	s.Desired, err = r.computeDesiredState(ctx, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "error computing the desired state of the Cluster topology")
+	}
+
+	if err := r.reconcileState(ctx, s); err != nil {
+		return nil, errors.Wrap(err, "error computing the changes for the Cluster topology")
+	}
+
+	return recorder.plan, nil
+}
+
+// reconcileDryRun runs the reconcile loop recording all the changes instead of applying them,
+// and logs the resulting plan. Changes are sent to the API server as dry-run requests.
+func (r *ClusterReconciler) reconcileDryRun(ctx context.Context, s *scope.Scope) (ctrl.Result, error) {
+	log := tlog.LoggerFrom(ctx)
+
+	recorder := newPlanRecorder(r.Client, true)
+	dryRunReconciler := *r
+	dryRunReconciler.Client = recorder
+
+	result, err := dryRunReconciler.reconcile(ctx, s)
+	if err != nil {
+		return result, err
+	}
+
+	if !recorder.plan.HasChanges() {
+		log.Infof("No changes planned for the Cluster topology")
+		return result, nil
+	}
+	for _, change := range recorder.plan.Changes {
+		log.Infof("Planned change: %s %s", change.Action, tlog.KObj{Obj: change.Object})
+	}
+	return result, nil
+}
+
+// planRecorder is a client.Client that records write operations into a Plan instead of applying them;
+// read operations are delegated to the underlying client.
+// NOTE: Writes to the status subresource are ignored, given that they are not part of the topology.
+type planRecorder struct {
+	client.Client
+
+	serverSideDryRun bool
+	plan             *Plan
+}
+
+func newPlanRecorder(c client.Client, serverSideDryRun bool) *planRecorder {
+	return &planRecorder{
+		Client:           c,
+		serverSideDryRun: serverSideDryRun,
+		plan:             &Plan{},
+	}
+}
+
+// Create records the creation of an object.
+func (c *planRecorder) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if c.serverSideDryRun {
+		if err := c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+	return c.record(PlanActionCreate, obj, nil)
+}
+
+// Update records the update of an object.
+func (c *planRecorder) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	current := obj.DeepCopyObject().(client.Object)
+	if c.serverSideDryRun {
+		if err := c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+	return c.record(PlanActionUpdate, current, nil)
+}
+
+// Patch records a patch to an object.
+func (c *planRecorder) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to compute patch for %s", tlog.KObj{Obj: obj})
+	}
+	current := obj.DeepCopyObject().(client.Object)
+	if c.serverSideDryRun {
+		if err := c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+	return c.record(PlanActionUpdate, current, data)
+}
+
+// Delete records the deletion of an object.
+func (c *planRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.serverSideDryRun {
+		if err := c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+			return err
+		}
+	}
+	return c.record(PlanActionDelete, obj, nil)
+}
+
+// DeleteAllOf is not supported, given that it is not possible to know which objects are going to be deleted.
+func (c *planRecorder) DeleteAllOf(_ context.Context, obj client.Object, _ ...client.DeleteAllOfOption) error {
+	return errors.Errorf("DeleteAllOf %T is not supported when computing a plan", obj)
+}
+
+// Status returns a client.StatusWriter ignoring all the writes.
+func (c *planRecorder) Status() client.StatusWriter {
+	return noopStatusWriter{}
+}
+
+func (c *planRecorder) record(action PlanAction, obj client.Object, patch []byte) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get GroupVersionKind for %T", obj)
+	}
+	recorded := obj.DeepCopyObject().(client.Object)
+	recorded.GetObjectKind().SetGroupVersionKind(gvk)
+
+	c.plan.Changes = append(c.plan.Changes, PlannedChange{
+		Action: action,
+		Object: recorded,
+		Patch:  patch,
+	})
+	return nil
+}
+
+// noopStatusWriter is a client.StatusWriter ignoring all the writes.
+type noopStatusWriter struct{}
+
+func (noopStatusWriter) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	return nil
+}
+
+func (noopStatusWriter) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	return nil
+}
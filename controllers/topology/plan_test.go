@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/testtypes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestComputePlan(t *testing.T) {
+	t.Run("fails for a Cluster without a managed topology", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster1").Build()
+		fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(cluster).Build()
+
+		_, err := ComputePlan(ctx, fakeClient, cluster, PlanOptions{})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestPlanRecorder(t *testing.T) {
+	g := NewWithT(t)
+
+	existingMD := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "existing-md"},
+		Spec:       clusterv1.MachineDeploymentSpec{ClusterName: "cluster1"},
+	}
+	deletedMD := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "deleted-md"},
+		Spec:       clusterv1.MachineDeploymentSpec{ClusterName: "cluster1"},
+	}
+	newInfrastructureCluster := testtypes.NewInfrastructureClusterBuilder(metav1.NamespaceDefault, "infrastructure-cluster1").Build()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(existingMD, deletedMD).Build()
+	recorder := newPlanRecorder(fakeClient, false)
+
+	// Reads are delegated to the underlying client.
+	current := &clusterv1.MachineDeployment{}
+	g.Expect(recorder.Get(ctx, client.ObjectKeyFromObject(existingMD), current)).To(Succeed())
+
+	// Writes are recorded.
+	g.Expect(recorder.Create(ctx, newInfrastructureCluster.DeepCopy())).To(Succeed())
+	modified := current.DeepCopy()
+	modified.Spec.Paused = true
+	g.Expect(recorder.Patch(ctx, modified, client.MergeFrom(current))).To(Succeed())
+	g.Expect(recorder.Delete(ctx, deletedMD.DeepCopy())).To(Succeed())
+	g.Expect(recorder.Status().Update(ctx, modified)).To(Succeed())
+
+	g.Expect(recorder.plan.Changes).To(HaveLen(3))
+
+	g.Expect(recorder.plan.Changes[0].Action).To(Equal(PlanActionCreate))
+	g.Expect(recorder.plan.Changes[0].Object.GetName()).To(Equal(newInfrastructureCluster.GetName()))
+	g.Expect(recorder.plan.Changes[0].Patch).To(BeNil())
+
+	g.Expect(recorder.plan.Changes[1].Action).To(Equal(PlanActionUpdate))
+	g.Expect(recorder.plan.Changes[1].Object.GetObjectKind().GroupVersionKind().Kind).To(Equal("MachineDeployment"))
+	g.Expect(recorder.plan.Changes[1].Object.GetName()).To(Equal(existingMD.Name))
+	g.Expect(string(recorder.plan.Changes[1].Patch)).To(Equal(`{"spec":{"paused":true}}`))
+
+	g.Expect(recorder.plan.Changes[2].Action).To(Equal(PlanActionDelete))
+	g.Expect(recorder.plan.Changes[2].Object.GetName()).To(Equal(deletedMD.Name))
+
+	// Nothing is applied to the underlying client.
+	g.Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(newInfrastructureCluster), newInfrastructureCluster.DeepCopy()))).To(BeTrue())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(existingMD), current)).To(Succeed())
+	g.Expect(current.Spec.Paused).To(BeFalse())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deletedMD), &clusterv1.MachineDeployment{})).To(Succeed())
+
+	// DeleteAllOf is not supported.
+	g.Expect(recorder.DeleteAllOf(ctx, &clusterv1.MachineDeployment{})).ToNot(Succeed())
+}
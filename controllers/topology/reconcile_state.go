@@ -19,6 +19,7 @@ package topology
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -26,12 +27,16 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apiserver/pkg/storage/names"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/check"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
 	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/mergepatch"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/contract"
+	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // reconcileState reconciles the current and desired state of the managed Cluster topology.
@@ -42,13 +47,22 @@ func (r *ClusterReconciler) reconcileState(ctx context.Context, s *scope.Scope)
 	log := tlog.LoggerFrom(ctx)
 	log.Infof("Reconciling state for topology owned objects")
 
+	// Ensure the desired state is compatible with the current state before applying any change, so an incompatible
+	// ClusterClass, e.g. after changing spec.topology.class, does not lead to a partially reconciled topology.
+	if err := checkDesiredStateIsCompatible(s); err != nil {
+		return err
+	}
+
 	// Reconcile desired state of the InfrastructureCluster object.
 	if err := r.reconcileInfrastructureCluster(ctx, s); err != nil {
 		return err
 	}
 
 	// Reconcile desired state of the ControlPlane object.
-	if err := r.reconcileControlPlane(ctx, s); err != nil {
+	// NOTE: The outcome is tracked, so it can be reported in the Cluster conditions.
+	err := r.reconcileControlPlane(ctx, s)
+	s.ReconcileTracker.MarkControlPlane(err)
+	if err != nil {
 		return err
 	}
 
@@ -58,7 +72,76 @@ func (r *ClusterReconciler) reconcileState(ctx context.Context, s *scope.Scope)
 	}
 
 	// Reconcile desired state of the MachineDeployment objects.
-	return r.reconcileMachineDeployments(ctx, s)
+	if err := r.reconcileMachineDeployments(ctx, s); err != nil {
+		return err
+	}
+
+	// Reconcile desired state of the MachinePool objects.
+	// NOTE: MachinePools are reconciled only if the MachinePool feature flag is enabled, given that
+	// the current state of MachinePools is not read otherwise.
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return nil
+	}
+	return r.reconcileMachinePools(ctx, s)
+}
+
+// checkDesiredStateIsCompatible checks that the desired state can be applied to the current state of the Cluster topology,
+// i.e. that existing objects are not going to change their GroupKind, and that the control plane is not going to
+// add or remove the reference to an InfrastructureMachineTemplate.
+func checkDesiredStateIsCompatible(s *scope.Scope) error {
+	if s.Current.InfrastructureCluster != nil {
+		if err := check.ReferencedObjectsAreStrictlyCompatible(s.Current.InfrastructureCluster, s.Desired.InfrastructureCluster); err != nil {
+			return err
+		}
+	}
+
+	if s.Current.ControlPlane != nil && s.Current.ControlPlane.Object != nil {
+		if err := check.ReferencedObjectsAreStrictlyCompatible(s.Current.ControlPlane.Object, s.Desired.ControlPlane.Object); err != nil {
+			return err
+		}
+
+		if s.Current.ControlPlane.InfrastructureMachineTemplate != nil && s.Desired.ControlPlane.InfrastructureMachineTemplate != nil {
+			if err := check.ReferencedObjectsAreCompatible(s.Current.ControlPlane.InfrastructureMachineTemplate, s.Desired.ControlPlane.InfrastructureMachineTemplate); err != nil {
+				return err
+			}
+		}
+
+		// NOTE: If the ClusterClass defines a machineInfrastructure for the control plane while the current control plane
+		// has none, reading the current state already fails.
+		if !s.Blueprint.HasControlPlaneInfrastructureMachine() {
+			if _, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(s.Current.ControlPlane.Object); err == nil {
+				return errors.Errorf("invalid operation: it is not possible to remove the InfrastructureMachineTemplate from %s", tlog.KObj{Obj: s.Current.ControlPlane.Object})
+			}
+		}
+	}
+
+	for mdTopologyName, currentMD := range s.Current.MachineDeployments {
+		desiredMD, ok := s.Desired.MachineDeployments[mdTopologyName]
+		if !ok {
+			continue
+		}
+		if err := check.ReferencedObjectsAreCompatible(currentMD.InfrastructureMachineTemplate, desiredMD.InfrastructureMachineTemplate); err != nil {
+			return err
+		}
+		if err := check.ObjectsAreInTheSameNamespace(currentMD.BootstrapTemplate, desiredMD.BootstrapTemplate); err != nil {
+			return err
+		}
+	}
+
+	for mpTopologyName, currentMP := range s.Current.MachinePools {
+		desiredMP, ok := s.Desired.MachinePools[mpTopologyName]
+		if !ok {
+			continue
+		}
+		if err := check.ReferencedObjectsAreStrictlyCompatible(currentMP.InfrastructureMachinePoolObject, desiredMP.InfrastructureMachinePoolObject); err != nil {
+			return err
+		}
+		if err := check.ReferencedObjectsAreStrictlyCompatible(currentMP.BootstrapObject, desiredMP.BootstrapObject); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // reconcileInfrastructureCluster reconciles the desired state of the InfrastructureCluster object.
@@ -82,6 +165,15 @@ func (r *ClusterReconciler) reconcileControlPlane(ctx context.Context, s *scope.
 			return errors.Wrapf(err, "failed to update %s", tlog.KObj{Obj: s.Desired.ControlPlane.InfrastructureMachineTemplate})
 		}
 
+		// Keep track of the current MachineInfrastructureTemplate, so it is possible to detect template rotation.
+		var currentCPInfraRef *corev1.ObjectReference
+		if s.Current.ControlPlane.Object != nil {
+			currentCPInfraRef, err = contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(s.Current.ControlPlane.Object)
+			if err != nil && !contract.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to read the infrastructureRef of %s", tlog.KObj{Obj: s.Current.ControlPlane.Object})
+			}
+		}
+
 		// Create or update the MachineInfrastructureTemplate of the control plane.
 		cleanup, err = r.reconcileReferencedTemplate(ctx, reconcileReferencedTemplateInput{
 			ref:                  cpInfraRef,
@@ -103,6 +195,12 @@ func (r *ClusterReconciler) reconcileControlPlane(ctx context.Context, s *scope.
 				cleanup(),
 			})
 		}
+
+		// If the MachineInfrastructureTemplate of an existing control plane has been rotated, the control plane
+		// is going to roll out its machines.
+		if currentCPInfraRef != nil && currentCPInfraRef.Name != cpInfraRef.Name {
+			s.UpgradeTracker.ControlPlane.MachineInfrastructureRotated = true
+		}
 	}
 
 	// Create or update the ControlPlaneObject for the ControlPlaneState.
@@ -114,6 +212,14 @@ func (r *ClusterReconciler) reconcileControlPlane(ctx context.Context, s *scope.
 		})
 	}
 
+	// Create, update or delete the MachineHealthCheck for the control plane machines.
+	if err := r.reconcileMachineHealthCheck(ctx, s.Current.ControlPlane.MachineHealthCheck, s.Desired.ControlPlane.MachineHealthCheck); err != nil {
+		return kerrors.NewAggregate([]error{
+			errors.Wrapf(err, "failed to update MachineHealthCheck for %s", tlog.KObj{Obj: s.Desired.ControlPlane.Object}),
+			cleanup(),
+		})
+	}
+
 	// At this point we've updated the ControlPlane object and, where required, the ControlPlane InfrastructureMachineTemplate
 	// without error. Run the cleanup in order to delete the old InfrastructureMachineTemplate if template rotation was done during update.
 	return cleanup()
@@ -148,31 +254,82 @@ func (r *ClusterReconciler) reconcileMachineDeployments(ctx context.Context, s *
 	diff := calculateMachineDeploymentDiff(s.Current.MachineDeployments, s.Desired.MachineDeployments)
 
 	// Create MachineDeployments.
-	for _, mdTopologyName := range diff.toCreate {
+	if err := r.forEachMachineDeployment(diff.toCreate, func(mdTopologyName string) error {
 		md := s.Desired.MachineDeployments[mdTopologyName]
-		if err := r.createMachineDeployment(ctx, md); err != nil {
-			return err
-		}
+		err := r.createMachineDeployment(ctx, md)
+		s.ReconcileTracker.MarkMachineDeployment(mdTopologyName, err)
+		return err
+	}); err != nil {
+		return err
 	}
 
-	// Update MachineDeployments.
-	for _, mdTopologyName := range diff.toUpdate {
-		currentMD := s.Current.MachineDeployments[mdTopologyName]
-		desiredMD := s.Desired.MachineDeployments[mdTopologyName]
-		if err := r.updateMachineDeployment(ctx, s.Current.Cluster.Name, mdTopologyName, currentMD, desiredMD); err != nil {
+	// Update MachineDeployments, following their rollout order.
+	// NOTE: MachineDeployments which are not allowed to roll out, because their rollout is paused or because
+	// MachineDeployments with a lower rollout order are still rolling out, keep using their current templates.
+	// MachineDeployments with the same rollout order do not depend on each other, so they are updated concurrently;
+	// MachineDeployments with a higher rollout order are updated only after all the lower ones have been updated.
+	rollout := newMachineDeploymentRolloutTracker(s.Current.MachineDeployments)
+	for _, group := range rollout.Group(diff.toUpdate) {
+		deferRotation := map[string]bool{}
+		for _, mdTopologyName := range group {
+			deferRotation[mdTopologyName] = !rollout.AllowRollout(mdTopologyName)
+		}
+
+		if err := r.forEachMachineDeployment(group, func(mdTopologyName string) error {
+			currentMD := s.Current.MachineDeployments[mdTopologyName]
+			desiredMD := s.Desired.MachineDeployments[mdTopologyName]
+			err := r.updateMachineDeployment(ctx, s.Current.Cluster.Name, mdTopologyName, currentMD, desiredMD, deferRotation[mdTopologyName])
+			s.ReconcileTracker.MarkMachineDeployment(mdTopologyName, err)
+			return err
+		}); err != nil {
 			return err
 		}
+
+		for _, mdTopologyName := range group {
+			if templatesRotated(s.Current.MachineDeployments[mdTopologyName], s.Desired.MachineDeployments[mdTopologyName]) {
+				rollout.MarkRollingOut(mdTopologyName)
+			}
+		}
 	}
 
-	// Delete MachineDeployments.
-	for _, mdTopologyName := range diff.toDelete {
+	// Delete or orphan MachineDeployments, according to the deletion policy defined in the topology.
+	deletionPolicy := machineDeploymentDeletionPolicy(s.Current.Cluster)
+	return r.forEachMachineDeployment(diff.toDelete, func(mdTopologyName string) error {
 		md := s.Current.MachineDeployments[mdTopologyName]
-		if err := r.deleteMachineDeployment(ctx, md); err != nil {
-			return err
+		if deletionPolicy == clusterv1.MachineDeploymentTopologyDeletionPolicyOrphan {
+			return r.orphanMachineDeployment(ctx, md)
 		}
-	}
+		return r.deleteMachineDeployment(ctx, md)
+	})
+}
 
-	return nil
+// forEachMachineDeployment calls fn for each of the given MachineDeployment topology names, running at most
+// MachineDeploymentConcurrency calls concurrently; it waits for all the calls to complete and returns an
+// aggregate of the errors.
+// NOTE: fn must be safe to be called concurrently.
+func (r *ClusterReconciler) forEachMachineDeployment(mdTopologyNames []string, fn func(mdTopologyName string) error) error {
+	concurrency := r.MachineDeploymentConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs := make([]error, len(mdTopologyNames))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, mdTopologyName := range mdTopologyNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, mdTopologyName string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(mdTopologyName)
+		}(i, mdTopologyName)
+	}
+	wg.Wait()
+
+	return kerrors.NewAggregate(errs)
 }
 
 // createMachineDeployment creates a MachineDeployment and the corresponding Templates.
@@ -198,11 +355,17 @@ func (r *ClusterReconciler) createMachineDeployment(ctx context.Context, md *sco
 	if err := r.Client.Create(ctx, md.Object.DeepCopy()); err != nil {
 		return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: md.Object})
 	}
+
+	// Create the MachineHealthCheck for the MachineDeployment machines, if defined.
+	if err := r.reconcileMachineHealthCheck(ctx, nil, md.MachineHealthCheck); err != nil {
+		return errors.Wrapf(err, "failed to create MachineHealthCheck for %s", tlog.KObj{Obj: md.Object})
+	}
 	return nil
 }
 
-// updateMachineDeployment updates a MachineDeployment. Also rotates the corresponding Templates if necessary.
-func (r *ClusterReconciler) updateMachineDeployment(ctx context.Context, clusterName string, mdTopologyName string, currentMD, desiredMD *scope.MachineDeploymentState) error {
+// updateMachineDeployment updates a MachineDeployment. Also rotates the corresponding Templates if necessary,
+// unless deferRotation is set; in this case the MachineDeployment keeps using the current Templates.
+func (r *ClusterReconciler) updateMachineDeployment(ctx context.Context, clusterName string, mdTopologyName string, currentMD, desiredMD *scope.MachineDeploymentState, deferRotation bool) error {
 	log := tlog.LoggerFrom(ctx).WithMachineDeployment(desiredMD.Object)
 
 	ctx, _ = log.WithObject(desiredMD.InfrastructureMachineTemplate).Into(ctx)
@@ -212,6 +375,7 @@ func (r *ClusterReconciler) updateMachineDeployment(ctx context.Context, cluster
 		desired:              desiredMD.InfrastructureMachineTemplate,
 		templateNamePrefix:   infrastructureMachineTemplateNamePrefix(clusterName, mdTopologyName),
 		compatibilityChecker: check.ReferencedObjectsAreCompatible,
+		deferRotation:        deferRotation,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to update %s", tlog.KObj{Obj: currentMD.Object})
 	}
@@ -223,13 +387,29 @@ func (r *ClusterReconciler) updateMachineDeployment(ctx context.Context, cluster
 		desired:              desiredMD.BootstrapTemplate,
 		templateNamePrefix:   bootstrapTemplateNamePrefix(clusterName, mdTopologyName),
 		compatibilityChecker: check.ObjectsAreInTheSameNamespace,
+		deferRotation:        deferRotation,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to update %s", tlog.KObj{Obj: currentMD.Object})
 	}
 
+	// Create, update or delete the MachineHealthCheck for the MachineDeployment machines.
+	if err := r.reconcileMachineHealthCheck(ctx, currentMD.MachineHealthCheck, desiredMD.MachineHealthCheck); err != nil {
+		return errors.Wrapf(err, "failed to update MachineHealthCheck for %s", tlog.KObj{Obj: currentMD.Object})
+	}
+
+	// If the replicas of the MachineDeployment are managed by the topology, remove the cluster-autoscaler annotations, if any;
+	// this is required to stop the cluster-autoscaler from scaling a MachineDeployment which is not autoscaled anymore.
+	var opts []mergepatch.HelperOption
+	if desiredMD.Object.Spec.Replicas != nil {
+		opts = append(opts, mergepatch.AuthoritativePaths{
+			contract.Path{"metadata", "annotations", clusterv1.AutoscalerMinSizeAnnotation},
+			contract.Path{"metadata", "annotations", clusterv1.AutoscalerMaxSizeAnnotation},
+		})
+	}
+
 	// Check differences between current and desired MachineDeployment, and eventually patch the current object.
 	log = log.WithObject(desiredMD.Object)
-	patchHelper, err := mergepatch.NewHelper(currentMD.Object, desiredMD.Object, r.Client)
+	patchHelper, err := mergepatch.NewHelper(currentMD.Object, desiredMD.Object, r.Client, opts...)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: currentMD.Object})
 	}
@@ -247,14 +427,238 @@ func (r *ClusterReconciler) updateMachineDeployment(ctx context.Context, cluster
 	return nil
 }
 
+// templatesRotated returns true if the desired MachineDeployment references templates other than the current ones.
+func templatesRotated(currentMD, desiredMD *scope.MachineDeploymentState) bool {
+	if currentMD.Object == nil || desiredMD.Object == nil {
+		return false
+	}
+	currentSpec, desiredSpec := currentMD.Object.Spec.Template.Spec, desiredMD.Object.Spec.Template.Spec
+	if currentSpec.InfrastructureRef.Name != desiredSpec.InfrastructureRef.Name {
+		return true
+	}
+	if currentSpec.Bootstrap.ConfigRef != nil && desiredSpec.Bootstrap.ConfigRef != nil &&
+		currentSpec.Bootstrap.ConfigRef.Name != desiredSpec.Bootstrap.ConfigRef.Name {
+		return true
+	}
+	return false
+}
+
+// machineDeploymentDeletionPolicy returns the policy to be applied to MachineDeployments whose
+// MachineDeploymentTopology has been removed from the Cluster topology; if not set, it defaults to Delete.
+func machineDeploymentDeletionPolicy(cluster *clusterv1.Cluster) clusterv1.MachineDeploymentTopologyDeletionPolicy {
+	if cluster.Spec.Topology == nil || cluster.Spec.Topology.Workers == nil || cluster.Spec.Topology.Workers.MachineDeploymentDeletionPolicy == "" {
+		return clusterv1.MachineDeploymentTopologyDeletionPolicyDelete
+	}
+	return cluster.Spec.Topology.Workers.MachineDeploymentDeletionPolicy
+}
+
 // deleteMachineDeployment deletes a MachineDeployment.
+// NOTE: Machines are drained and deleted by the MachineSet and Machine controllers, while the templates cloned
+// for the MachineDeployment are deleted by the topology MachineDeployment controller.
 func (r *ClusterReconciler) deleteMachineDeployment(ctx context.Context, md *scope.MachineDeploymentState) error {
 	log := tlog.LoggerFrom(ctx).WithMachineDeployment(md.Object).WithObject(md.Object)
 
+	// Delete the MachineHealthCheck first, so no remediation is triggered while the machines are deleted.
+	if err := r.reconcileMachineHealthCheck(ctx, md.MachineHealthCheck, nil); err != nil {
+		return errors.Wrapf(err, "failed to delete MachineHealthCheck for %s", tlog.KObj{Obj: md.Object})
+	}
+
 	log.Infof("Deleting %s", tlog.KObj{Obj: md.Object})
 	if err := r.Client.Delete(ctx, md.Object); err != nil && !apierrors.IsNotFound(err) {
 		return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: md.Object})
 	}
+
+	// If the topology finalizer is missing, e.g. because it has been removed by a user, the templates
+	// won't be deleted by the topology MachineDeployment controller, so delete them here to not leak them.
+	if controllerutil.ContainsFinalizer(md.Object, clusterv1.MachineDeploymentTopologyFinalizer) {
+		return nil
+	}
+	for _, template := range []*unstructured.Unstructured{md.BootstrapTemplate, md.InfrastructureMachineTemplate} {
+		if template == nil {
+			continue
+		}
+		log.Infof("Deleting %s", tlog.KObj{Obj: template})
+		if err := r.Client.Delete(ctx, template); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: template})
+		}
+	}
+	return nil
+}
+
+// orphanMachineDeployment releases a MachineDeployment and its MachineHealthCheck from the Cluster topology,
+// so they are not managed by the topology controller anymore. The Machines and the templates are left untouched.
+func (r *ClusterReconciler) orphanMachineDeployment(ctx context.Context, md *scope.MachineDeploymentState) error {
+	log := tlog.LoggerFrom(ctx).WithMachineDeployment(md.Object).WithObject(md.Object)
+
+	if md.MachineHealthCheck != nil {
+		patchHelper, err := patch.NewHelper(md.MachineHealthCheck, r.Client)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: md.MachineHealthCheck})
+		}
+		delete(md.MachineHealthCheck.Labels, clusterv1.ClusterTopologyOwnedLabel)
+		log.Infof("Orphaning %s", tlog.KObj{Obj: md.MachineHealthCheck})
+		if err := patchHelper.Patch(ctx, md.MachineHealthCheck); err != nil {
+			return errors.Wrapf(err, "failed to orphan %s", tlog.KObj{Obj: md.MachineHealthCheck})
+		}
+	}
+
+	// NOTE: Labels in the Machine template are preserved, so the MachineDeployment does not roll out
+	// and the selector of the MachineHealthCheck keeps matching the existing Machines.
+	patchHelper, err := patch.NewHelper(md.Object, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: md.Object})
+	}
+	delete(md.Object.Labels, clusterv1.ClusterTopologyOwnedLabel)
+	delete(md.Object.Labels, clusterv1.ClusterTopologyMachineDeploymentLabelName)
+	controllerutil.RemoveFinalizer(md.Object, clusterv1.MachineDeploymentTopologyFinalizer)
+	log.Infof("Orphaning %s", tlog.KObj{Obj: md.Object})
+	if err := patchHelper.Patch(ctx, md.Object); err != nil {
+		return errors.Wrapf(err, "failed to orphan %s", tlog.KObj{Obj: md.Object})
+	}
+	return nil
+}
+
+// reconcileMachinePools reconciles the desired state of the MachinePool objects.
+func (r *ClusterReconciler) reconcileMachinePools(ctx context.Context, s *scope.Scope) error {
+	diff := calculateMachinePoolDiff(s.Current.MachinePools, s.Desired.MachinePools)
+
+	// Create MachinePools.
+	for _, mpTopologyName := range diff.toCreate {
+		mp := s.Desired.MachinePools[mpTopologyName]
+		if err := r.createMachinePool(ctx, mp); err != nil {
+			return err
+		}
+	}
+
+	// Update MachinePools.
+	for _, mpTopologyName := range diff.toUpdate {
+		currentMP := s.Current.MachinePools[mpTopologyName]
+		desiredMP := s.Desired.MachinePools[mpTopologyName]
+		if err := r.updateMachinePool(ctx, currentMP, desiredMP); err != nil {
+			return err
+		}
+	}
+
+	// Delete MachinePools.
+	for _, mpTopologyName := range diff.toDelete {
+		mp := s.Current.MachinePools[mpTopologyName]
+		if err := r.deleteMachinePool(ctx, mp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createMachinePool creates a MachinePool and the corresponding bootstrap and infrastructure objects.
+func (r *ClusterReconciler) createMachinePool(ctx context.Context, mp *scope.MachinePoolState) error {
+	log := tlog.LoggerFrom(ctx).WithMachinePool(mp.Object)
+
+	ctx, _ = log.WithObject(mp.InfrastructureMachinePoolObject).Into(ctx)
+	if err := r.reconcileReferencedObject(ctx, nil, mp.InfrastructureMachinePoolObject); err != nil {
+		return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: mp.Object})
+	}
+
+	ctx, _ = log.WithObject(mp.BootstrapObject).Into(ctx)
+	if err := r.reconcileReferencedObject(ctx, nil, mp.BootstrapObject); err != nil {
+		return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: mp.Object})
+	}
+
+	log = log.WithObject(mp.Object)
+	log.Infof(fmt.Sprintf("Creating %s", tlog.KObj{Obj: mp.Object}))
+	if err := r.Client.Create(ctx, mp.Object.DeepCopy()); err != nil {
+		return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: mp.Object})
+	}
+	return nil
+}
+
+// updateMachinePool updates a MachinePool and the corresponding bootstrap and infrastructure objects.
+// NOTE: Differently from MachineDeployments, the objects referenced by a MachinePool are patched in place;
+// it is up to the MachinePool and the infrastructure providers to roll out the changes to the machines in the pool.
+func (r *ClusterReconciler) updateMachinePool(ctx context.Context, currentMP, desiredMP *scope.MachinePoolState) error {
+	log := tlog.LoggerFrom(ctx).WithMachinePool(desiredMP.Object)
+
+	ctx, _ = log.WithObject(desiredMP.InfrastructureMachinePoolObject).Into(ctx)
+	if err := r.reconcileReferencedObject(ctx, currentMP.InfrastructureMachinePoolObject, desiredMP.InfrastructureMachinePoolObject); err != nil {
+		return errors.Wrapf(err, "failed to update %s", tlog.KObj{Obj: currentMP.Object})
+	}
+
+	ctx, _ = log.WithObject(desiredMP.BootstrapObject).Into(ctx)
+	if err := r.reconcileReferencedObject(ctx, currentMP.BootstrapObject, desiredMP.BootstrapObject); err != nil {
+		return errors.Wrapf(err, "failed to update %s", tlog.KObj{Obj: currentMP.Object})
+	}
+
+	// Check differences between current and desired MachinePool, and eventually patch the current object.
+	log = log.WithObject(desiredMP.Object)
+	patchHelper, err := mergepatch.NewHelper(currentMP.Object, desiredMP.Object, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: currentMP.Object})
+	}
+	if !patchHelper.HasChanges() {
+		log.V(3).Infof("No changes for %s", tlog.KObj{Obj: currentMP.Object})
+		return nil
+	}
+
+	log.Infof("Patching %s", tlog.KObj{Obj: currentMP.Object})
+	if err := patchHelper.Patch(ctx); err != nil {
+		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: currentMP.Object})
+	}
+	return nil
+}
+
+// deleteMachinePool deletes a MachinePool.
+// NOTE: The bootstrap and infrastructure objects referenced by the MachinePool are deleted by the MachinePool controller.
+func (r *ClusterReconciler) deleteMachinePool(ctx context.Context, mp *scope.MachinePoolState) error {
+	log := tlog.LoggerFrom(ctx).WithMachinePool(mp.Object).WithObject(mp.Object)
+
+	log.Infof("Deleting %s", tlog.KObj{Obj: mp.Object})
+	if err := r.Client.Delete(ctx, mp.Object); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: mp.Object})
+	}
+	return nil
+}
+
+// reconcileMachineHealthCheck creates, patches or deletes a MachineHealthCheck, depending on the current and the desired state.
+func (r *ClusterReconciler) reconcileMachineHealthCheck(ctx context.Context, current, desired *clusterv1.MachineHealthCheck) error {
+	log := tlog.LoggerFrom(ctx)
+
+	// If there is neither a current nor a desired MachineHealthCheck, there is nothing to do.
+	if current == nil && desired == nil {
+		return nil
+	}
+
+	// If there is no current MachineHealthCheck, create it.
+	if current == nil {
+		log.Infof("Creating %s", tlog.KObj{Obj: desired})
+		if err := r.Client.Create(ctx, desired.DeepCopy()); err != nil {
+			return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: desired})
+		}
+		return nil
+	}
+
+	// If the MachineHealthCheck is not required anymore, delete it.
+	if desired == nil {
+		log.Infof("Deleting %s", tlog.KObj{Obj: current})
+		if err := r.Client.Delete(ctx, current); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: current})
+		}
+		return nil
+	}
+
+	// Check differences between current and desired MachineHealthCheck, and eventually patch the current object.
+	patchHelper, err := mergepatch.NewHelper(current, desired, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: current})
+	}
+	if !patchHelper.HasChanges() {
+		log.V(3).Infof("No changes for %s", tlog.KObj{Obj: current})
+		return nil
+	}
+
+	log.Infof("Patching %s", tlog.KObj{Obj: current})
+	if err := patchHelper.Patch(ctx); err != nil {
+		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: current})
+	}
 	return nil
 }
 
@@ -284,6 +688,32 @@ func calculateMachineDeploymentDiff(current, desired map[string]*scope.MachineDe
 	return diff
 }
 
+type machinePoolDiff struct {
+	toCreate, toUpdate, toDelete []string
+}
+
+// calculateMachinePoolDiff compares two maps of MachinePoolState and calculates which
+// MachinePools should be created, updated or deleted.
+func calculateMachinePoolDiff(current, desired map[string]*scope.MachinePoolState) machinePoolDiff {
+	var diff machinePoolDiff
+
+	for mp := range desired {
+		if _, ok := current[mp]; ok {
+			diff.toUpdate = append(diff.toUpdate, mp)
+		} else {
+			diff.toCreate = append(diff.toCreate, mp)
+		}
+	}
+
+	for mp := range current {
+		if _, ok := desired[mp]; !ok {
+			diff.toDelete = append(diff.toDelete, mp)
+		}
+	}
+
+	return diff
+}
+
 // reconcileReferencedObject reconciles the desired state of the referenced object.
 // NOTE: After a referenced object is created it is assumed that the reference should
 // never change (only the content of the object can eventually change). Thus, we are checking for strict compatibility.
@@ -327,6 +757,8 @@ type reconcileReferencedTemplateInput struct {
 	desired              *unstructured.Unstructured
 	templateNamePrefix   string
 	compatibilityChecker func(current, desired client.Object) error
+	// deferRotation, if set, prevents the template rotation; the reference keeps pointing to the current template.
+	deferRotation bool
 }
 
 // reconcileReferencedTemplate reconciles the desired state of a referenced Template.
@@ -336,6 +768,8 @@ type reconcileReferencedTemplateInput struct {
 // 3. delete the old Template
 // This function specifically takes care of the first step and updates the reference locally. So the remaining steps
 // can be executed afterwards.
+// NOTE: The new Template is named after a hash of its content, so a rotation interrupted before updating the reference
+// can be safely retried, re-using the Template created in the first attempt.
 // NOTE: This func has a side effect in case of template rotation, changing both the desired object and the object reference.
 func (r *ClusterReconciler) reconcileReferencedTemplate(ctx context.Context, in reconcileReferencedTemplateInput) (func() error, error) {
 	log := tlog.LoggerFrom(ctx)
@@ -372,17 +806,37 @@ func (r *ClusterReconciler) reconcileReferencedTemplate(ctx context.Context, in
 		return cleanupFunc, nil
 	}
 
+	// If the rotation is deferred, keep the reference pointing to the current template;
+	// the rotation will happen in a following reconcile.
+	if in.deferRotation {
+		log.Infof("Deferring rotation of %s", tlog.KObj{Obj: in.current})
+		*in.ref = *contract.ObjToRef(in.current)
+		return cleanupFunc, nil
+	}
+
 	// Create the new template.
 
 	// NOTE: it is required to assign a new name, because during compute the desired object name is enforced to be equal to the current one.
 	// TODO: find a way to make side effect more explicit
-	newName := names.SimpleNameGenerator.GenerateName(in.templateNamePrefix)
+	newName, err := templateVersionName(in.templateNamePrefix, in.desired)
+	if err != nil {
+		return nil, err
+	}
+	// NOTE: If the current template has been created from the same content, e.g. because it has been changed afterwards,
+	// fall back to a random name, given that the current template is going to be deleted.
+	if newName == in.current.GetName() {
+		newName = names.SimpleNameGenerator.GenerateName(in.templateNamePrefix)
+	}
 	in.desired.SetName(newName)
 
 	log.Infof("Rotating %s, new name %s", tlog.KObj{Obj: in.current}, newName)
 	log.Infof("Creating %s", tlog.KObj{Obj: in.desired})
 	if err := r.Client.Create(ctx, in.desired.DeepCopy()); err != nil {
-		return nil, errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: in.desired})
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: in.desired})
+		}
+		// The template has been already created by a previous rotation attempt.
+		log.Infof("%s already exists, re-using it", tlog.KObj{Obj: in.desired})
 	}
 
 	// Update the reference with the new name.
@@ -19,17 +19,26 @@ package topology
 import (
 	"fmt"
 	"regexp"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"sigs.k8s.io/cluster-api/internal/testtypes"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
 	"sigs.k8s.io/cluster-api/controllers/topology/internal/scope"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -436,11 +445,12 @@ func TestReconcileControlPlaneInfrastructureMachineTemplate(t *testing.T) {
 		Build()
 
 	tests := []struct {
-		name    string
-		current *scope.ControlPlaneState
-		desired *scope.ControlPlaneState
-		want    *scope.ControlPlaneState
-		wantErr bool
+		name        string
+		current     *scope.ControlPlaneState
+		desired     *scope.ControlPlaneState
+		want        *scope.ControlPlaneState
+		wantRotated bool
+		wantErr     bool
 	}{
 		{
 			name:    "Create desired InfrastructureMachineTemplate where it doesn't exist",
@@ -450,11 +460,12 @@ func TestReconcileControlPlaneInfrastructureMachineTemplate(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Update desired InfrastructureMachineTemplate connected to controlPlane",
-			current: &scope.ControlPlaneState{Object: controlPlane1, InfrastructureMachineTemplate: infrastructureMachineTemplate},
-			desired: &scope.ControlPlaneState{Object: controlPlane3, InfrastructureMachineTemplate: updatedInfrastructureMachineTemplate},
-			want:    &scope.ControlPlaneState{Object: controlPlane3, InfrastructureMachineTemplate: updatedInfrastructureMachineTemplate},
-			wantErr: false,
+			name:        "Update desired InfrastructureMachineTemplate connected to controlPlane",
+			current:     &scope.ControlPlaneState{Object: controlPlane1, InfrastructureMachineTemplate: infrastructureMachineTemplate},
+			desired:     &scope.ControlPlaneState{Object: controlPlane3, InfrastructureMachineTemplate: updatedInfrastructureMachineTemplate},
+			want:        &scope.ControlPlaneState{Object: controlPlane3, InfrastructureMachineTemplate: updatedInfrastructureMachineTemplate},
+			wantRotated: true,
+			wantErr:     false,
 		},
 		{
 			name:    "Fail on updating infrastructure with incompatible changes",
@@ -501,6 +512,7 @@ func TestReconcileControlPlaneInfrastructureMachineTemplate(t *testing.T) {
 				return
 			}
 			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(s.UpgradeTracker.ControlPlane.MachineInfrastructureRotated).To(Equal(tt.wantRotated))
 
 			// Create ControlPlane object for fetching data into
 			gotControlPlaneObject := testtypes.NewControlPlaneBuilder("", "").Build()
@@ -611,6 +623,17 @@ func TestReconcileMachineDeployments(t *testing.T) {
 	bootstrapTemplate8UpdateWithChanges.SetLabels(map[string]string{"foo": "bar"})
 	md8UpdateWithRotatedTemplates := newFakeMachineDeploymentTopologyState("md-8-update", infrastructureMachineTemplate8UpdateWithChanges, bootstrapTemplate8UpdateWithChanges)
 
+	infrastructureMachineTemplate9 := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infrastructure-machine-9").Build()
+	bootstrapTemplate9 := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-config-9").Build()
+	md9RolloutPaused := newFakeMachineDeploymentTopologyState("md-9", infrastructureMachineTemplate9, bootstrapTemplate9)
+	md9RolloutPaused.Object.SetAnnotations(map[string]string{clusterv1.ClusterTopologyMachineDeploymentRolloutPausedAnnotation: ""})
+	infrastructureMachineTemplate9WithChanges := infrastructureMachineTemplate9.DeepCopy()
+	infrastructureMachineTemplate9WithChanges.SetLabels(map[string]string{"foo": "bar"})
+	bootstrapTemplate9WithChanges := bootstrapTemplate9.DeepCopy()
+	bootstrapTemplate9WithChanges.SetLabels(map[string]string{"foo": "bar"})
+	md9WithRotatedTemplates := newFakeMachineDeploymentTopologyState("md-9", infrastructureMachineTemplate9WithChanges, bootstrapTemplate9WithChanges)
+	md9WithoutRotatedTemplates := newFakeMachineDeploymentTopologyState("md-9", infrastructureMachineTemplate9.DeepCopy(), bootstrapTemplate9.DeepCopy())
+
 	tests := []struct {
 		name                                      string
 		current                                   []*scope.MachineDeploymentState
@@ -695,6 +718,13 @@ func TestReconcileMachineDeployments(t *testing.T) {
 			wantBootstrapTemplateRotation:             map[string]bool{"md-8-update": true},
 			wantErr:                                   false,
 		},
+		{
+			name:    "Should not rotate templates of a MachineDeployment with rollout paused",
+			current: []*scope.MachineDeploymentState{md9RolloutPaused},
+			desired: []*scope.MachineDeploymentState{md9WithRotatedTemplates},
+			want:    []*scope.MachineDeploymentState{md9WithoutRotatedTemplates},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -807,6 +837,417 @@ func TestReconcileMachineDeployments(t *testing.T) {
 	}
 }
 
+func TestReconcileMachineDeploymentsDeletionPolicy(t *testing.T) {
+	newMachineDeploymentState := func(withFinalizer bool) *scope.MachineDeploymentState {
+		infrastructureMachineTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infrastructure-machine-1").Build()
+		bootstrapTemplate := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-config-1").Build()
+		md := newFakeMachineDeploymentTopologyState("md-1", infrastructureMachineTemplate, bootstrapTemplate)
+		md.Object.Labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+		if withFinalizer {
+			md.Object.Finalizers = []string{clusterv1.MachineDeploymentTopologyFinalizer}
+		}
+		md.MachineHealthCheck = &clusterv1.MachineHealthCheck{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "MachineHealthCheck",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "md-1",
+				Namespace: metav1.NamespaceDefault,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:          "cluster-1",
+					clusterv1.ClusterTopologyOwnedLabel: "",
+				},
+			},
+			Spec: clusterv1.MachineHealthCheckSpec{
+				ClusterName: "cluster-1",
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{
+					clusterv1.ClusterTopologyMachineDeploymentLabelName: "md-1-topology",
+				}},
+			},
+		}
+		return md
+	}
+
+	tests := []struct {
+		name                  string
+		deletionPolicy        clusterv1.MachineDeploymentTopologyDeletionPolicy
+		withFinalizer         bool
+		wantMachineDeployment bool
+		wantTemplates         bool
+	}{
+		{
+			name:                  "Should delete MachineDeployment and templates by default",
+			deletionPolicy:        "",
+			wantMachineDeployment: false,
+			wantTemplates:         false,
+		},
+		{
+			name:                  "Should delete MachineDeployment and templates with Delete policy",
+			deletionPolicy:        clusterv1.MachineDeploymentTopologyDeletionPolicyDelete,
+			wantMachineDeployment: false,
+			wantTemplates:         false,
+		},
+		{
+			name:                  "Should orphan MachineDeployment, MachineHealthCheck and templates with Orphan policy",
+			deletionPolicy:        clusterv1.MachineDeploymentTopologyDeletionPolicyOrphan,
+			withFinalizer:         true,
+			wantMachineDeployment: true,
+			wantTemplates:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := newMachineDeploymentState(tt.withFinalizer)
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(md.Object, md.InfrastructureMachineTemplate, md.BootstrapTemplate, md.MachineHealthCheck).
+				Build()
+
+			cluster := testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster-1").Build()
+			cluster.Spec.Topology = &clusterv1.Topology{
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeploymentDeletionPolicy: tt.deletionPolicy,
+				},
+			}
+			s := scope.New(cluster)
+			s.Current.MachineDeployments = toMachineDeploymentTopologyStateMap([]*scope.MachineDeploymentState{md})
+			s.Desired = &scope.ClusterState{MachineDeployments: map[string]*scope.MachineDeploymentState{}}
+
+			r := ClusterReconciler{
+				Client: fakeClient,
+			}
+			g.Expect(r.reconcileMachineDeployments(ctx, s)).To(Succeed())
+
+			gotMachineDeployment := &clusterv1.MachineDeployment{}
+			err := fakeClient.Get(ctx, client.ObjectKeyFromObject(md.Object), gotMachineDeployment)
+			gotMachineHealthCheck := &clusterv1.MachineHealthCheck{}
+			mhcErr := fakeClient.Get(ctx, client.ObjectKeyFromObject(md.MachineHealthCheck), gotMachineHealthCheck)
+			if !tt.wantMachineDeployment {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				g.Expect(apierrors.IsNotFound(mhcErr)).To(BeTrue())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(gotMachineDeployment.Labels).ToNot(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+				g.Expect(gotMachineDeployment.Labels).ToNot(HaveKey(clusterv1.ClusterTopologyMachineDeploymentLabelName))
+				g.Expect(gotMachineDeployment.Finalizers).ToNot(ContainElement(clusterv1.MachineDeploymentTopologyFinalizer))
+				// Labels in the Machine template must be preserved to not trigger a rollout.
+				g.Expect(gotMachineDeployment.Spec).To(Equal(md.Object.Spec))
+
+				g.Expect(mhcErr).ToNot(HaveOccurred())
+				g.Expect(gotMachineHealthCheck.Labels).ToNot(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+			}
+
+			for _, template := range []*unstructured.Unstructured{md.InfrastructureMachineTemplate, md.BootstrapTemplate} {
+				gotTemplate := &unstructured.Unstructured{}
+				gotTemplate.SetGroupVersionKind(template.GroupVersionKind())
+				err := fakeClient.Get(ctx, client.ObjectKeyFromObject(template), gotTemplate)
+				if tt.wantTemplates {
+					g.Expect(err).ToNot(HaveOccurred())
+				} else {
+					g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				}
+			}
+		})
+	}
+}
+
+func TestForEachMachineDeployment(t *testing.T) {
+	mdTopologyNames := []string{"md-1", "md-2", "md-3", "md-4", "md-5", "md-6"}
+
+	tests := []struct {
+		name            string
+		concurrency     int
+		wantConcurrency int32
+	}{
+		{
+			name:            "Should reconcile MachineDeployments one at a time if concurrency is not set",
+			concurrency:     0,
+			wantConcurrency: 1,
+		},
+		{
+			name:            "Should reconcile at most concurrency MachineDeployments at the same time",
+			concurrency:     3,
+			wantConcurrency: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := ClusterReconciler{
+				MachineDeploymentConcurrency: tt.concurrency,
+			}
+
+			var lock sync.Mutex
+			var running, maxRunning int32
+			called := sets.NewString()
+			err := r.forEachMachineDeployment(mdTopologyNames, func(mdTopologyName string) error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+
+				lock.Lock()
+				called.Insert(mdTopologyName)
+				if n > maxRunning {
+					maxRunning = n
+				}
+				lock.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+				if mdTopologyName == "md-2" || mdTopologyName == "md-5" {
+					return errors.Errorf("failed to reconcile %s", mdTopologyName)
+				}
+				return nil
+			})
+
+			// All the MachineDeployments must be reconciled, even if some of them fail, and errors aggregated.
+			g.Expect(called.List()).To(Equal(mdTopologyNames))
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring("failed to reconcile md-2"))
+			g.Expect(err.Error()).To(ContainSubstring("failed to reconcile md-5"))
+			g.Expect(maxRunning).To(BeNumerically("<=", tt.wantConcurrency))
+		})
+	}
+}
+
+func TestReconcileMachinePools(t *testing.T) {
+	infrastructureMachinePool1 := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infrastructure-machinepool-1").Build()
+	bootstrapConfig1 := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-config-1").Build()
+	mp1 := newFakeMachinePoolTopologyState("mp-1", infrastructureMachinePool1, bootstrapConfig1, 1)
+
+	infrastructureMachinePool2 := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infrastructure-machinepool-2").Build()
+	bootstrapConfig2 := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-config-2").Build()
+	mp2 := newFakeMachinePoolTopologyState("mp-2", infrastructureMachinePool2, bootstrapConfig2, 1)
+	infrastructureMachinePool2WithChanges := infrastructureMachinePool2.DeepCopy()
+	infrastructureMachinePool2WithChanges.SetLabels(map[string]string{"foo": "bar"})
+	bootstrapConfig2WithChanges := bootstrapConfig2.DeepCopy()
+	bootstrapConfig2WithChanges.SetLabels(map[string]string{"foo": "bar"})
+	mp2WithChanges := newFakeMachinePoolTopologyState("mp-2", infrastructureMachinePool2WithChanges, bootstrapConfig2WithChanges, 3)
+
+	infrastructureMachinePool3 := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infrastructure-machinepool-3").Build()
+	bootstrapConfig3 := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-config-3").Build()
+	mp3 := newFakeMachinePoolTopologyState("mp-3", infrastructureMachinePool3, bootstrapConfig3, 1)
+	infrastructureMachinePool3WithChangedKind := infrastructureMachinePool3.DeepCopy()
+	infrastructureMachinePool3WithChangedKind.SetKind("ChangedKind")
+	mp3WithChangedInfrastructureMachinePoolKind := newFakeMachinePoolTopologyState("mp-3", infrastructureMachinePool3WithChangedKind, bootstrapConfig3, 1)
+
+	infrastructureMachinePool4 := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infrastructure-machinepool-4").Build()
+	bootstrapConfig4 := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-config-4").Build()
+	mp4 := newFakeMachinePoolTopologyState("mp-4", infrastructureMachinePool4, bootstrapConfig4, 1)
+
+	tests := []struct {
+		name    string
+		current []*scope.MachinePoolState
+		desired []*scope.MachinePoolState
+		want    []*scope.MachinePoolState
+		wantErr bool
+	}{
+		{
+			name:    "Should create desired MachinePool and the referenced objects if the current does not exist yet",
+			current: nil,
+			desired: []*scope.MachinePoolState{mp1},
+			want:    []*scope.MachinePoolState{mp1},
+			wantErr: false,
+		},
+		{
+			name:    "No-op if current MachinePool is equal to desired",
+			current: []*scope.MachinePoolState{mp1},
+			desired: []*scope.MachinePoolState{mp1},
+			want:    []*scope.MachinePoolState{mp1},
+			wantErr: false,
+		},
+		{
+			name:    "Should update MachinePool and patch the referenced objects in place",
+			current: []*scope.MachinePoolState{mp2},
+			desired: []*scope.MachinePoolState{mp2WithChanges},
+			want:    []*scope.MachinePoolState{mp2WithChanges},
+			wantErr: false,
+		},
+		{
+			name:    "Should fail update MachinePool because of changed InfrastructureMachinePool kind",
+			current: []*scope.MachinePoolState{mp3},
+			desired: []*scope.MachinePoolState{mp3WithChangedInfrastructureMachinePoolKind},
+			wantErr: true,
+		},
+		{
+			name:    "Should delete MachinePool",
+			current: []*scope.MachinePoolState{mp4},
+			desired: []*scope.MachinePoolState{},
+			want:    []*scope.MachinePoolState{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeObjs := make([]client.Object, 0)
+			for _, mpts := range tt.current {
+				fakeObjs = append(fakeObjs, mpts.Object)
+				fakeObjs = append(fakeObjs, mpts.InfrastructureMachinePoolObject)
+				fakeObjs = append(fakeObjs, mpts.BootstrapObject)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(fakeObjs...).
+				Build()
+
+			s := scope.New(testtypes.NewClusterBuilder(metav1.NamespaceDefault, "cluster-1").Build())
+			s.Current.MachinePools = toMachinePoolTopologyStateMap(tt.current)
+
+			// TODO: stop setting ResourceVersion when building objects
+			for _, mp := range tt.desired {
+				mp.Object.SetResourceVersion("")
+				mp.BootstrapObject.SetResourceVersion("")
+				mp.InfrastructureMachinePoolObject.SetResourceVersion("")
+			}
+			s.Desired = &scope.ClusterState{MachinePools: toMachinePoolTopologyStateMap(tt.desired)}
+
+			r := ClusterReconciler{
+				Client: fakeClient,
+			}
+			err := r.reconcileMachinePools(ctx, s)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var gotMachinePoolList expv1.MachinePoolList
+			g.Expect(fakeClient.List(ctx, &gotMachinePoolList)).To(Succeed())
+			g.Expect(gotMachinePoolList.Items).To(HaveLen(len(tt.want)))
+
+			for _, wantMachinePoolState := range tt.want {
+				for _, gotMachinePool := range gotMachinePoolList.Items {
+					if wantMachinePoolState.Object.Name != gotMachinePool.Name {
+						continue
+					}
+
+					// Compare MachinePool.
+					// Note: We're intentionally only comparing Spec as otherwise we would have to account for
+					// empty vs. filled out TypeMeta.
+					g.Expect(gotMachinePool.Spec).To(Equal(wantMachinePoolState.Object.Spec))
+
+					// Compare the bootstrap object.
+					gotBootstrapObject := unstructured.Unstructured{}
+					gotBootstrapObject.SetKind(wantMachinePoolState.BootstrapObject.GetKind())
+					gotBootstrapObject.SetAPIVersion(wantMachinePoolState.BootstrapObject.GetAPIVersion())
+					g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(wantMachinePoolState.BootstrapObject), &gotBootstrapObject)).To(Succeed())
+					g.Expect(gotBootstrapObject.GetLabels()).To(Equal(wantMachinePoolState.BootstrapObject.GetLabels()))
+
+					// Compare the InfrastructureMachinePool object.
+					gotInfrastructureMachinePoolObject := unstructured.Unstructured{}
+					gotInfrastructureMachinePoolObject.SetKind(wantMachinePoolState.InfrastructureMachinePoolObject.GetKind())
+					gotInfrastructureMachinePoolObject.SetAPIVersion(wantMachinePoolState.InfrastructureMachinePoolObject.GetAPIVersion())
+					g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(wantMachinePoolState.InfrastructureMachinePoolObject), &gotInfrastructureMachinePoolObject)).To(Succeed())
+					g.Expect(gotInfrastructureMachinePoolObject.GetLabels()).To(Equal(wantMachinePoolState.InfrastructureMachinePoolObject.GetLabels()))
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileMachineHealthCheck(t *testing.T) {
+	maxUnhealthy := intstr.FromString("45%")
+	mhcBuilder := func(name string) *clusterv1.MachineHealthCheck {
+		return &clusterv1.MachineHealthCheck{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "MachineHealthCheck",
+				APIVersion: clusterv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:          "cluster1",
+					clusterv1.ClusterTopologyOwnedLabel: "",
+				},
+			},
+			Spec: clusterv1.MachineHealthCheckSpec{
+				ClusterName: "cluster1",
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{
+					clusterv1.ClusterTopologyMachineDeploymentLabelName: "md1",
+				}},
+				UnhealthyConditions: []clusterv1.UnhealthyCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: metav1.Duration{Duration: 5 * time.Minute}},
+				},
+			},
+		}
+	}
+	mhc := mhcBuilder("md1")
+	mhcWithMaxUnhealthy := mhcBuilder("md1")
+	mhcWithMaxUnhealthy.Spec.MaxUnhealthy = &maxUnhealthy
+
+	tests := []struct {
+		name    string
+		current *clusterv1.MachineHealthCheck
+		desired *clusterv1.MachineHealthCheck
+		want    *clusterv1.MachineHealthCheck
+	}{
+		{
+			name:    "Create a MachineHealthCheck",
+			current: nil,
+			desired: mhc,
+			want:    mhc,
+		},
+		{
+			name:    "Update a MachineHealthCheck",
+			current: mhc,
+			desired: mhcWithMaxUnhealthy,
+			want:    mhcWithMaxUnhealthy,
+		},
+		{
+			name:    "Delete a MachineHealthCheck",
+			current: mhc,
+			desired: nil,
+			want:    nil,
+		},
+		{
+			name:    "No op without current and desired MachineHealthCheck",
+			current: nil,
+			desired: nil,
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeObjs := make([]client.Object, 0)
+			if tt.current != nil {
+				fakeObjs = append(fakeObjs, tt.current.DeepCopy())
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(fakeObjs...).
+				Build()
+
+			r := ClusterReconciler{
+				Client: fakeClient,
+			}
+
+			var current *clusterv1.MachineHealthCheck
+			if tt.current != nil {
+				current = &clusterv1.MachineHealthCheck{}
+				g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(tt.current), current)).To(Succeed())
+			}
+			g.Expect(r.reconcileMachineHealthCheck(ctx, current, tt.desired)).To(Succeed())
+
+			got := &clusterv1.MachineHealthCheck{}
+			err := fakeClient.Get(ctx, client.ObjectKeyFromObject(mhc), got)
+			if tt.want == nil {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Spec).To(Equal(tt.want.Spec), cmp.Diff(got.Spec, tt.want.Spec))
+			g.Expect(got.Labels).To(Equal(tt.want.Labels))
+		})
+	}
+}
+
 func newFakeMachineDeploymentTopologyState(name string, infrastructureMachineTemplate, bootstrapTemplate *unstructured.Unstructured) *scope.MachineDeploymentState {
 	return &scope.MachineDeploymentState{
 		Object: testtypes.NewMachineDeploymentBuilder(metav1.NamespaceDefault, name).
@@ -819,6 +1260,41 @@ func newFakeMachineDeploymentTopologyState(name string, infrastructureMachineTem
 	}
 }
 
+func newFakeMachinePoolTopologyState(name string, infrastructureMachinePool, bootstrapConfig *unstructured.Unstructured, replicas int32) *scope.MachinePoolState {
+	return &scope.MachinePoolState{
+		Object: &expv1.MachinePool{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: expv1.GroupVersion.String(),
+				Kind:       "MachinePool",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{clusterv1.ClusterTopologyMachinePoolLabelName: name + "-topology"},
+			},
+			Spec: expv1.MachinePoolSpec{
+				Replicas: &replicas,
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						Bootstrap:         clusterv1.Bootstrap{ConfigRef: contract.ObjToRef(bootstrapConfig)},
+						InfrastructureRef: *contract.ObjToRef(infrastructureMachinePool),
+					},
+				},
+			},
+		},
+		InfrastructureMachinePoolObject: infrastructureMachinePool,
+		BootstrapObject:                 bootstrapConfig,
+	}
+}
+
+func toMachinePoolTopologyStateMap(states []*scope.MachinePoolState) map[string]*scope.MachinePoolState {
+	ret := map[string]*scope.MachinePoolState{}
+	for _, state := range states {
+		ret[state.Object.Labels[clusterv1.ClusterTopologyMachinePoolLabelName]] = state
+	}
+	return ret
+}
+
 func toMachineDeploymentTopologyStateMap(states []*scope.MachineDeploymentState) map[string]*scope.MachineDeploymentState {
 	ret := map[string]*scope.MachineDeploymentState{}
 	for _, state := range states {
@@ -826,3 +1302,120 @@ func toMachineDeploymentTopologyStateMap(states []*scope.MachineDeploymentState)
 	}
 	return ret
 }
+
+func TestCheckDesiredStateIsCompatible(t *testing.T) {
+	infrastructureCluster := testtypes.NewInfrastructureClusterBuilder(metav1.NamespaceDefault, "infrastructure-cluster1").Build()
+	infrastructureClusterWithAnotherKind := infrastructureCluster.DeepCopy()
+	infrastructureClusterWithAnotherKind.SetKind("AnotherInfrastructureCluster")
+
+	infrastructureMachineTemplate := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "infrastructure-machine-template1").Build()
+	infrastructureMachineTemplateWithAnotherKind := infrastructureMachineTemplate.DeepCopy()
+	infrastructureMachineTemplateWithAnotherKind.SetKind("AnotherInfrastructureMachineTemplate")
+	bootstrapTemplate := testtypes.NewBootstrapTemplateBuilder(metav1.NamespaceDefault, "bootstrap-template1").Build()
+
+	controlPlane := testtypes.NewControlPlaneBuilder(metav1.NamespaceDefault, "control-plane1").Build()
+	controlPlaneWithInfrastructureMachineTemplate := testtypes.NewControlPlaneBuilder(metav1.NamespaceDefault, "control-plane1").
+		WithInfrastructureMachineTemplate(infrastructureMachineTemplate).
+		Build()
+
+	clusterClass := testtypes.NewClusterClassBuilder(metav1.NamespaceDefault, "class1").Build()
+
+	machineDeploymentState := func(infrastructureMachineTemplate *unstructured.Unstructured) map[string]*scope.MachineDeploymentState {
+		return map[string]*scope.MachineDeploymentState{
+			"md1": {
+				Object:                        testtypes.NewMachineDeploymentBuilder(metav1.NamespaceDefault, "md1").Build(),
+				InfrastructureMachineTemplate: infrastructureMachineTemplate,
+				BootstrapTemplate:             bootstrapTemplate,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		current *scope.ClusterState
+		desired *scope.ClusterState
+		wantErr bool
+	}{
+		{
+			name: "Pass for a new Cluster",
+			current: &scope.ClusterState{
+				ControlPlane: &scope.ControlPlaneState{},
+			},
+			desired: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlane},
+				MachineDeployments:    machineDeploymentState(infrastructureMachineTemplate),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Pass for compatible current and desired state",
+			current: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlane},
+				MachineDeployments:    machineDeploymentState(infrastructureMachineTemplate),
+			},
+			desired: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlane},
+				MachineDeployments:    machineDeploymentState(infrastructureMachineTemplate),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Fail if the InfrastructureCluster changes kind",
+			current: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlane},
+			},
+			desired: &scope.ClusterState{
+				InfrastructureCluster: infrastructureClusterWithAnotherKind,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlane},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fail if the ClusterClass removes the control plane InfrastructureMachineTemplate",
+			current: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlaneWithInfrastructureMachineTemplate},
+			},
+			desired: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlaneWithInfrastructureMachineTemplate},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Fail if a MachineDeployment InfrastructureMachineTemplate changes kind",
+			current: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlane},
+				MachineDeployments:    machineDeploymentState(infrastructureMachineTemplate),
+			},
+			desired: &scope.ClusterState{
+				InfrastructureCluster: infrastructureCluster,
+				ControlPlane:          &scope.ControlPlaneState{Object: controlPlane},
+				MachineDeployments:    machineDeploymentState(infrastructureMachineTemplateWithAnotherKind),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s := scope.New(nil)
+			s.Blueprint = &scope.ClusterBlueprint{ClusterClass: clusterClass}
+			s.Current = tt.current
+			s.Desired = tt.desired
+
+			err := checkDesiredStateIsCompatible(s)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -19,6 +19,7 @@ package topology
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -26,8 +27,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	apirand "k8s.io/apimachinery/pkg/util/rand"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
 	tlog "sigs.k8s.io/cluster-api/controllers/topology/internal/log"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,6 +51,20 @@ func controlPlaneInfrastructureMachineTemplateNamePrefix(clusterName string) str
 	return fmt.Sprintf("%s-control-plane-", clusterName)
 }
 
+// templateVersionName calculates the name for a new version of a template, appending to the name prefix
+// a hash of the template content.
+func templateVersionName(namePrefix string, template *unstructured.Unstructured) (string, error) {
+	obj := template.DeepCopy()
+	obj.SetName("")
+	obj.SetResourceVersion("")
+
+	hasher := fnv.New32a()
+	if err := mdutil.SpewHashObject(hasher, obj.Object); err != nil {
+		return "", errors.Wrapf(err, "failed to compute the hash of %s", tlog.KObj{Obj: template})
+	}
+	return namePrefix + apirand.SafeEncodeString(fmt.Sprintf("%d", hasher.Sum32())), nil
+}
+
 // getReference gets the object referenced in ref.
 // If necessary, it updates the ref to the latest apiVersion of the current contract.
 func (r *ClusterReconciler) getReference(ctx context.Context, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
@@ -28,7 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"sigs.k8s.io/cluster-api/controllers/topology/internal/contract"
+	"sigs.k8s.io/cluster-api/util/contract"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -210,3 +210,28 @@ func mustTemplateRefID(ref *corev1.ObjectReference) string {
 	}
 	return refID
 }
+
+func TestTemplateVersionName(t *testing.T) {
+	g := NewWithT(t)
+
+	template := testtypes.NewInfrastructureMachineTemplateBuilder(metav1.NamespaceDefault, "template1").Build()
+
+	name, err := templateVersionName("cluster1-md1-infra-", template)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(name).To(HavePrefix("cluster1-md1-infra-"))
+
+	// The name should not depend on the current name or resource version of the template.
+	renamedTemplate := template.DeepCopy()
+	renamedTemplate.SetName("template2")
+	renamedTemplate.SetResourceVersion("42")
+	renamedTemplateName, err := templateVersionName("cluster1-md1-infra-", renamedTemplate)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(renamedTemplateName).To(Equal(name))
+
+	// The name should change when the content of the template changes.
+	changedTemplate := template.DeepCopy()
+	changedTemplate.SetLabels(map[string]string{"foo": "bar"})
+	changedTemplateName, err := templateVersionName("cluster1-md1-infra-", changedTemplate)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changedTemplateName).ToNot(Equal(name))
+}
@@ -18,6 +18,7 @@ package v1alpha3
 
 import (
 	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	cabpkv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha3"
 	kubeadmbootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
@@ -38,6 +39,11 @@ func (src *KubeadmControlPlane) ConvertTo(destRaw conversion.Hub) error {
 	}
 
 	dest.Spec.MachineTemplate.ObjectMeta = restored.Spec.MachineTemplate.ObjectMeta
+	dest.Spec.CertificateRotation = restored.Spec.CertificateRotation
+	dest.Spec.EtcdMemberRemediation = restored.Spec.EtcdMemberRemediation
+	dest.Spec.ExternalEtcdClientCertificateRotation = restored.Spec.ExternalEtcdClientCertificateRotation
+	dest.Spec.InitialMachineRemediation = restored.Spec.InitialMachineRemediation
+	dest.Spec.KubeadmConfigOverrides = restored.Spec.KubeadmConfigOverrides
 	dest.Status.Version = restored.Status.Version
 
 	if restored.Spec.KubeadmConfigSpec.JoinConfiguration != nil && restored.Spec.KubeadmConfigSpec.JoinConfiguration.NodeRegistration.IgnorePreflightErrors != nil {
@@ -54,6 +60,10 @@ func (src *KubeadmControlPlane) ConvertTo(destRaw conversion.Hub) error {
 		dest.Spec.KubeadmConfigSpec.InitConfiguration.NodeRegistration.IgnorePreflightErrors = restored.Spec.KubeadmConfigSpec.InitConfiguration.NodeRegistration.IgnorePreflightErrors
 	}
 
+	cabpkv1.RestoreFiles(restored.Spec.KubeadmConfigSpec.Files, dest.Spec.KubeadmConfigSpec.Files)
+	dest.Spec.KubeadmConfigSpec.DiskSetup = cabpkv1.RestoreDiskSetup(restored.Spec.KubeadmConfigSpec.DiskSetup, dest.Spec.KubeadmConfigSpec.DiskSetup)
+	dest.Spec.KubeadmConfigSpec.ContainerImages = restored.Spec.KubeadmConfigSpec.ContainerImages
+
 	return nil
 }
 
@@ -17,9 +17,11 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"encoding/json"
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
 
 	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
@@ -52,6 +54,7 @@ func fuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 		kubeadmBootstrapTokenStringFuzzer,
 		cabpkBootstrapTokenStringFuzzer,
 		dnsFuzzer,
+		JSONFuzzer,
 		kubeadmClusterConfigurationFuzzer,
 	}
 }
@@ -65,6 +68,12 @@ func cabpkBootstrapTokenStringFuzzer(in *cabpkv1.BootstrapTokenString, c fuzz.Co
 	in.Secret = "abcdef0123456789"
 }
 
+func JSONFuzzer(in *apiextensionsv1.JSON, c fuzz.Continue) {
+	// apiextensionsv1.JSON must contain a valid JSON value, otherwise marshalling the Hub
+	// into the conversion annotation fails.
+	in.Raw, _ = json.Marshal(c.RandString())
+}
+
 func dnsFuzzer(obj *upstreamv1beta1.DNS, c fuzz.Continue) {
 	c.FuzzNoCustom(obj)
 
@@ -199,6 +199,11 @@ func autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha3_KubeadmControlPlane
 	}
 	// WARNING: in.RolloutAfter requires manual conversion: does not exist in peer-type
 	out.RolloutStrategy = (*RolloutStrategy)(unsafe.Pointer(in.RolloutStrategy))
+	// WARNING: in.CertificateRotation requires manual conversion: does not exist in peer-type
+	// WARNING: in.EtcdMemberRemediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExternalEtcdClientCertificateRotation requires manual conversion: does not exist in peer-type
+	// WARNING: in.InitialMachineRemediation requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeadmConfigOverrides requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -17,32 +17,83 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	cabpkv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
 func (src *KubeadmControlPlane) ConvertTo(destRaw conversion.Hub) error {
 	dest := destRaw.(*v1beta1.KubeadmControlPlane)
 
-	return Convert_v1alpha4_KubeadmControlPlane_To_v1beta1_KubeadmControlPlane(src, dest, nil)
+	if err := Convert_v1alpha4_KubeadmControlPlane_To_v1beta1_KubeadmControlPlane(src, dest, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.KubeadmControlPlane{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dest.Spec.CertificateRotation = restored.Spec.CertificateRotation
+	dest.Spec.EtcdMemberRemediation = restored.Spec.EtcdMemberRemediation
+	dest.Spec.ExternalEtcdClientCertificateRotation = restored.Spec.ExternalEtcdClientCertificateRotation
+	dest.Spec.InitialMachineRemediation = restored.Spec.InitialMachineRemediation
+	dest.Spec.KubeadmConfigOverrides = restored.Spec.KubeadmConfigOverrides
+	cabpkv1.RestoreFiles(restored.Spec.KubeadmConfigSpec.Files, dest.Spec.KubeadmConfigSpec.Files)
+	dest.Spec.KubeadmConfigSpec.DiskSetup = cabpkv1.RestoreDiskSetup(restored.Spec.KubeadmConfigSpec.DiskSetup, dest.Spec.KubeadmConfigSpec.DiskSetup)
+	dest.Spec.KubeadmConfigSpec.ContainerImages = restored.Spec.KubeadmConfigSpec.ContainerImages
+
+	return nil
 }
 
 func (dest *KubeadmControlPlane) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.KubeadmControlPlane)
 
-	return Convert_v1beta1_KubeadmControlPlane_To_v1alpha4_KubeadmControlPlane(src, dest, nil)
+	if err := Convert_v1beta1_KubeadmControlPlane_To_v1alpha4_KubeadmControlPlane(src, dest, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dest)
 }
 
 func (src *KubeadmControlPlaneTemplate) ConvertTo(destRaw conversion.Hub) error {
 	dest := destRaw.(*v1beta1.KubeadmControlPlaneTemplate)
 
-	return Convert_v1alpha4_KubeadmControlPlaneTemplate_To_v1beta1_KubeadmControlPlaneTemplate(src, dest, nil)
+	if err := Convert_v1alpha4_KubeadmControlPlaneTemplate_To_v1beta1_KubeadmControlPlaneTemplate(src, dest, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.KubeadmControlPlaneTemplate{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dest.Spec.Template.Spec.CertificateRotation = restored.Spec.Template.Spec.CertificateRotation
+	dest.Spec.Template.Spec.EtcdMemberRemediation = restored.Spec.Template.Spec.EtcdMemberRemediation
+	dest.Spec.Template.Spec.ExternalEtcdClientCertificateRotation = restored.Spec.Template.Spec.ExternalEtcdClientCertificateRotation
+	dest.Spec.Template.Spec.InitialMachineRemediation = restored.Spec.Template.Spec.InitialMachineRemediation
+	dest.Spec.Template.Spec.KubeadmConfigOverrides = restored.Spec.Template.Spec.KubeadmConfigOverrides
+	cabpkv1.RestoreFiles(restored.Spec.Template.Spec.KubeadmConfigSpec.Files, dest.Spec.Template.Spec.KubeadmConfigSpec.Files)
+	dest.Spec.Template.Spec.KubeadmConfigSpec.DiskSetup = cabpkv1.RestoreDiskSetup(restored.Spec.Template.Spec.KubeadmConfigSpec.DiskSetup, dest.Spec.Template.Spec.KubeadmConfigSpec.DiskSetup)
+	dest.Spec.Template.Spec.KubeadmConfigSpec.ContainerImages = restored.Spec.Template.Spec.KubeadmConfigSpec.ContainerImages
+
+	return nil
 }
 
 func (dest *KubeadmControlPlaneTemplate) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.KubeadmControlPlaneTemplate)
 
-	return Convert_v1beta1_KubeadmControlPlaneTemplate_To_v1alpha4_KubeadmControlPlaneTemplate(src, dest, nil)
+	if err := Convert_v1beta1_KubeadmControlPlaneTemplate_To_v1alpha4_KubeadmControlPlaneTemplate(src, dest, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dest)
 }
 
 func (src *KubeadmControlPlaneList) ConvertTo(destRaw conversion.Hub) error {
@@ -56,3 +107,10 @@ func (dest *KubeadmControlPlaneList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_KubeadmControlPlaneList_To_v1alpha4_KubeadmControlPlaneList(src, dest, nil)
 }
+
+func Convert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in *v1beta1.KubeadmControlPlaneSpec, out *KubeadmControlPlaneSpec, s apiconversion.Scope) error {
+	// NOTE: custom conversion func is required because spec.CertificateRotation, spec.EtcdMemberRemediation,
+	// spec.ExternalEtcdClientCertificateRotation, spec.InitialMachineRemediation and spec.KubeadmConfigOverrides
+	// do not exist in v1alpha4.
+	return autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in, out, s)
+}
@@ -17,12 +17,15 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"encoding/json"
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
 
 	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	cabpkv1alpha4 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	cabpkv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/upstreamv1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
@@ -57,7 +60,9 @@ func fuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
 	return []interface{}{
 		kubeadmBootstrapTokenStringFuzzer,
 		cabpkBootstrapTokenStringFuzzer,
+		cabpkV1Alpha4BootstrapTokenStringFuzzer,
 		dnsFuzzer,
+		JSONFuzzer,
 	}
 }
 
@@ -69,6 +74,16 @@ func cabpkBootstrapTokenStringFuzzer(in *cabpkv1.BootstrapTokenString, c fuzz.Co
 	in.ID = "abcdef"
 	in.Secret = "abcdef0123456789"
 }
+func cabpkV1Alpha4BootstrapTokenStringFuzzer(in *cabpkv1alpha4.BootstrapTokenString, c fuzz.Continue) {
+	in.ID = "abcdef"
+	in.Secret = "abcdef0123456789"
+}
+
+func JSONFuzzer(in *apiextensionsv1.JSON, c fuzz.Continue) {
+	// apiextensionsv1.JSON must contain a valid JSON value, otherwise marshalling the Hub
+	// into the conversion annotation fails.
+	in.Raw, _ = json.Marshal(c.RandString())
+}
 
 func dnsFuzzer(obj *upstreamv1beta1.DNS, c fuzz.Continue) {
 	c.FuzzNoCustom(obj)
@@ -36,6 +36,24 @@ const (
 	CertificatesGenerationFailedReason = "CertificatesGenerationFailed"
 )
 
+const (
+	// CertificatesValidCondition reports the time until the certificates managed by the KubeadmControlPlane expire,
+	// i.e. the client certificate of the admin kubeconfig and, if rotated by KCP, the apiserver-etcd-client certificate.
+	CertificatesValidCondition clusterv1.ConditionType = "CertificatesValid"
+
+	// CertificatesExpiringReason (Severity=Warning) documents a certificate managed by the KubeadmControlPlane
+	// expiring within the renewal threshold without being renewed.
+	CertificatesExpiringReason = "CertificatesExpiring"
+
+	// CertificatesExpiredReason (Severity=Error) documents a certificate managed by the KubeadmControlPlane
+	// being expired.
+	CertificatesExpiredReason = "CertificatesExpired"
+
+	// CertificatesExpiryCheckFailedReason (Severity=Warning) documents a KubeadmControlPlane controller failing
+	// to read the expiry of the certificates it manages.
+	CertificatesExpiryCheckFailedReason = "CertificatesExpiryCheckFailed"
+)
+
 const (
 	// AvailableCondition documents that the first control plane instance has completed the kubeadm init operation
 	// and so the control plane is available and an API server instance is ready for processing requests.
@@ -122,6 +140,13 @@ const (
 	// EtcdClusterUnhealthyReason (Severity=Error) is set when the etcd cluster is unhealthy.
 	EtcdClusterUnhealthyReason = "EtcdClusterUnhealthy"
 
+	// EtcdClusterNoAlarmsCondition documents that no alarms, e.g. NOSPACE or CORRUPT, are raised on the etcd cluster.
+	// NOTE: This conditions exists only if a stacked etcd cluster is used.
+	EtcdClusterNoAlarmsCondition clusterv1.ConditionType = "EtcdClusterNoAlarms"
+
+	// EtcdClusterAlarmsRaisedReason (Severity=Error) documents alarms raised on the etcd cluster.
+	EtcdClusterAlarmsRaisedReason = "EtcdClusterAlarmsRaised"
+
 	// MachineEtcdMemberHealthyCondition report the machine's etcd member's health status.
 	// NOTE: This conditions exists only if a stacked etcd cluster is used.
 	MachineEtcdMemberHealthyCondition clusterv1.ConditionType = "EtcdMemberHealthy"
@@ -132,6 +157,10 @@ const (
 	// EtcdMemberUnhealthyReason (Severity=Error) documents a Machine's etcd member is unhealthy.
 	EtcdMemberUnhealthyReason = "EtcdMemberUnhealthy"
 
+	// EtcdMemberLearnerReason (Severity=Warning) documents a Machine's etcd member is a learner not yet promoted
+	// to voting member.
+	EtcdMemberLearnerReason = "EtcdMemberLearner"
+
 	// MachinesCreatedCondition documents that the machines controlled by the KubeadmControlPlane are created.
 	// When this condition is false, it indicates that there was an error when cloning the infrastructure/bootstrap template or
 	// when generating the machine object.
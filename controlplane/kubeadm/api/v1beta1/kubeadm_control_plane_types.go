@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -48,6 +49,14 @@ const (
 	// KubeadmClusterConfigurationAnnotation is a machine annotation that stores the json-marshalled string of KCP ClusterConfiguration.
 	// This annotation is used to detect any changes in ClusterConfiguration and trigger machine rollout in KCP.
 	KubeadmClusterConfigurationAnnotation = "controlplane.cluster.x-k8s.io/kubeadm-cluster-configuration"
+
+	// EtcdClientCertificateRenewedAtAnnotation is a secret annotation that stores the time (RFC3339) the
+	// apiserver-etcd-client certificate was renewed by KCP; machines created before this time are rolled out.
+	EtcdClientCertificateRenewedAtAnnotation = "controlplane.cluster.x-k8s.io/etcd-client-certificate-renewed-at"
+
+	// InitialMachineRemediationAnnotation is a KubeadmControlPlane annotation that stores the json-marshalled state of the
+	// remediation of the first control plane machine, i.e. the number of machines remediated and the time of the last remediation.
+	InitialMachineRemediationAnnotation = "controlplane.cluster.x-k8s.io/initial-machine-remediation"
 )
 
 // KubeadmControlPlaneSpec defines the desired state of KubeadmControlPlane.
@@ -81,6 +90,38 @@ type KubeadmControlPlaneSpec struct {
 	// +optional
 	// +kubebuilder:default={type: "RollingUpdate", rollingUpdate: {maxSurge: 1}}
 	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// CertificateRotation configures the rotation of the certificates signed by the cluster CA and managed
+	// by KCP, i.e. the client certificate of the admin kubeconfig.
+	// If not set, the certificates are renewed when they expire within 6 months.
+	// +optional
+	CertificateRotation *CertificateRotation `json:"certificateRotation,omitempty"`
+
+	// EtcdMemberRemediation configures the remediation of machines hosting an unhealthy etcd member,
+	// e.g. a member reporting alarms or stuck as a learner, even if the machines are otherwise healthy.
+	// If not set, KCP does not remediate machines because of their etcd member.
+	// +optional
+	EtcdMemberRemediation *EtcdMemberRemediation `json:"etcdMemberRemediation,omitempty"`
+
+	// ExternalEtcdClientCertificateRotation configures the rotation of the apiserver-etcd-client certificate,
+	// used by the API servers to connect to an external etcd cluster. Rotation requires the key of the external
+	// etcd CA to be stored in the <cluster-name>-etcd secret.
+	// If not set, the certificate is not rotated.
+	// +optional
+	ExternalEtcdClientCertificateRotation *ExternalEtcdClientCertificateRotation `json:"externalEtcdClientCertificateRotation,omitempty"`
+
+	// InitialMachineRemediation configures the remediation of the first control plane machine when it fails
+	// before the control plane is initialized, e.g. because its node never comes up; the machine is deleted and
+	// recreated a bounded number of times, waiting longer after each retry.
+	// If not set, the first machine is remediated up to 3 times, waiting 5 minutes before the first retry.
+	// +optional
+	InitialMachineRemediation *InitialMachineRemediation `json:"initialMachineRemediation,omitempty"`
+
+	// KubeadmConfigOverrides is a list of patches applied in order to the KubeadmConfigSpec when generating
+	// the KubeadmConfig of each machine, e.g. to set different node labels for the machines in a failure domain.
+	// Changes to the overrides are rolled out like changes to the KubeadmConfigSpec.
+	// +optional
+	KubeadmConfigOverrides []KubeadmConfigOverride `json:"kubeadmConfigOverrides,omitempty"`
 }
 
 // KubeadmControlPlaneMachineTemplate defines the template for Machines
@@ -129,6 +170,86 @@ type RollingUpdate struct {
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
+// CertificateRotation describes how the certificates managed by KCP are rotated.
+type CertificateRotation struct {
+	// RenewBefore is how long before expiring the certificates are renewed.
+	// Defaults to half of the validity of the certificates, i.e. 6 months.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// EtcdMemberRemediation describes how machines hosting an unhealthy etcd member are remediated.
+type EtcdMemberRemediation struct {
+	// UnhealthyTimeout is how long an etcd member must be unhealthy before the machine
+	// hosting it is remediated.
+	// Defaults to 5 minutes.
+	// +optional
+	UnhealthyTimeout *metav1.Duration `json:"unhealthyTimeout,omitempty"`
+
+	// MaxUnhealthy is the maximum number of unhealthy etcd members for which remediation is allowed;
+	// when more etcd members are unhealthy, remediation is skipped, given that the problem most likely
+	// does not depend on a single machine.
+	// Value can be an absolute number (ex: 1) or a percentage of the control plane machines (ex: 40%).
+	// Defaults to 1.
+	// +optional
+	MaxUnhealthy *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
+}
+
+// ExternalEtcdClientCertificateRotation describes how the apiserver-etcd-client certificate is rotated.
+type ExternalEtcdClientCertificateRotation struct {
+	// RenewBefore is how long before expiring the certificate is renewed; after the certificate is renewed,
+	// control plane machines are rolled out so the API servers use the new certificate.
+	// Defaults to 30 days.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// InitialMachineRemediation describes how the first control plane machine is remediated when it fails
+// before the control plane is initialized.
+type InitialMachineRemediation struct {
+	// MaxRetry is the maximum number of times the first control plane machine is deleted and recreated;
+	// set it to 0 to disable the remediation of the first control plane machine.
+	// Defaults to 3.
+	// +optional
+	MaxRetry *int32 `json:"maxRetry,omitempty"`
+
+	// RetryPeriod is how long KCP waits after remediating the first control plane machine before remediating
+	// its replacement; the period doubles at every following retry.
+	// Defaults to 5 minutes.
+	// +optional
+	RetryPeriod *metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// KubeadmConfigOverride defines a patch applied to the KubeadmConfigSpec of a subset of the control plane machines.
+type KubeadmConfigOverride struct {
+	// FailureDomains is the list of failure domains of the machines the override is applied to.
+	// If empty, the override is applied to all the machines.
+	// +optional
+	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// MergePatch is a JSON merge patch, as defined in RFC 7386, applied to the KubeadmConfigSpec.
+	// Note: clusterConfiguration can't be patched, because it must be the same for all the machines.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	MergePatch apiextensionsv1.JSON `json:"mergePatch"`
+}
+
+// Matches returns true if the override is applied to machines in the given failure domain.
+func (o KubeadmConfigOverride) Matches(failureDomain *string) bool {
+	if len(o.FailureDomains) == 0 {
+		return true
+	}
+	if failureDomain == nil {
+		return false
+	}
+	for _, fd := range o.FailureDomains {
+		if fd == *failureDomain {
+			return true
+		}
+	}
+	return false
+}
+
 // KubeadmControlPlaneStatus defines the observed state of KubeadmControlPlane.
 type KubeadmControlPlaneStatus struct {
 	// Selector is the label selector in string format to avoid introspection
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -27,15 +29,25 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	cabpkv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/container"
 	"sigs.k8s.io/cluster-api/util/version"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// machineReader is used to read the Machines of the Cluster when validating a change to spec.version;
+// it is set when setting up the webhook with the manager.
+var machineReader client.Reader
+
 func (in *KubeadmControlPlane) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	machineReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(in).
 		Complete()
@@ -137,6 +149,7 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, kubeadmConfigSpec, "verbosity"},
 		{spec, kubeadmConfigSpec, users},
 		{spec, kubeadmConfigSpec, ntp, "*"},
+		{spec, kubeadmConfigSpec, "containerImages", "*"},
 		{spec, "machineTemplate", "metadata", "*"},
 		{spec, "machineTemplate", "infrastructureRef", "apiVersion"},
 		{spec, "machineTemplate", "infrastructureRef", "name"},
@@ -145,6 +158,11 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, "rolloutAfter"},
 		{spec, "nodeDrainTimeout"},
 		{spec, "rolloutStrategy", "*"},
+		{spec, "certificateRotation", "*"},
+		{spec, "etcdMemberRemediation", "*"},
+		{spec, "externalEtcdClientCertificateRotation", "*"},
+		{spec, "initialMachineRemediation", "*"},
+		{spec, "kubeadmConfigOverrides"},
 	}
 
 	allErrs := validateKubeadmControlPlaneSpec(in.Spec, in.Namespace, field.NewPath("spec"))
@@ -322,6 +340,98 @@ func validateKubeadmControlPlaneSpec(s KubeadmControlPlaneSpec, namespace string
 		}
 	}
 
+	if s.EtcdMemberRemediation != nil {
+		if s.EtcdMemberRemediation.UnhealthyTimeout != nil && s.EtcdMemberRemediation.UnhealthyTimeout.Duration < 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("etcdMemberRemediation", "unhealthyTimeout"),
+					s.EtcdMemberRemediation.UnhealthyTimeout.Duration.String(),
+					"must be greater than or equal to 0",
+				),
+			)
+		}
+
+		if s.EtcdMemberRemediation.MaxUnhealthy != nil {
+			if maxUnhealthy, err := intstr.GetScaledValueFromIntOrPercent(s.EtcdMemberRemediation.MaxUnhealthy, 100, false); err != nil || maxUnhealthy < 0 {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						pathPrefix.Child("etcdMemberRemediation", "maxUnhealthy"),
+						s.EtcdMemberRemediation.MaxUnhealthy.String(),
+						"must be a positive integer or a positive percentage",
+					),
+				)
+			}
+		}
+	}
+
+	// NOTE: renewed certificates are valid for certs.DefaultCertDuration, so a longer renewal window would
+	// renew the certificates at every reconcile.
+	if s.CertificateRotation != nil && s.CertificateRotation.RenewBefore != nil {
+		if renewBefore := s.CertificateRotation.RenewBefore.Duration; renewBefore <= 0 || renewBefore >= certs.DefaultCertDuration {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("certificateRotation", "renewBefore"),
+					renewBefore.String(),
+					fmt.Sprintf("must be greater than 0 and less than %s", certs.DefaultCertDuration),
+				),
+			)
+		}
+	}
+
+	if s.ExternalEtcdClientCertificateRotation != nil {
+		if s.KubeadmConfigSpec.ClusterConfiguration == nil || s.KubeadmConfigSpec.ClusterConfiguration.Etcd.External == nil {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					pathPrefix.Child("externalEtcdClientCertificateRotation"),
+					"can only be set when using an external etcd cluster",
+				),
+			)
+		}
+
+		// NOTE: renewed certificates are valid for certs.DefaultCertDuration, so a longer renewal window would
+		// renew the certificate at every reconcile.
+		if renewBefore := s.ExternalEtcdClientCertificateRotation.RenewBefore; renewBefore != nil && (renewBefore.Duration <= 0 || renewBefore.Duration >= certs.DefaultCertDuration) {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("externalEtcdClientCertificateRotation", "renewBefore"),
+					renewBefore.Duration.String(),
+					fmt.Sprintf("must be greater than 0 and less than %s", certs.DefaultCertDuration),
+				),
+			)
+		}
+	}
+
+	if s.InitialMachineRemediation != nil {
+		if s.InitialMachineRemediation.MaxRetry != nil && *s.InitialMachineRemediation.MaxRetry < 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("initialMachineRemediation", "maxRetry"),
+					*s.InitialMachineRemediation.MaxRetry,
+					"must be greater than or equal to 0",
+				),
+			)
+		}
+
+		if s.InitialMachineRemediation.RetryPeriod != nil && s.InitialMachineRemediation.RetryPeriod.Duration < 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					pathPrefix.Child("initialMachineRemediation", "retryPeriod"),
+					s.InitialMachineRemediation.RetryPeriod.Duration.String(),
+					"must be greater than or equal to 0",
+				),
+			)
+		}
+	}
+
+	allErrs = append(allErrs, validateKubeadmConfigOverrides(s, pathPrefix.Child("kubeadmConfigOverrides"))...)
+
 	if s.KubeadmConfigSpec.ClusterConfiguration == nil {
 		return allErrs
 	}
@@ -339,6 +449,45 @@ func validateKubeadmControlPlaneSpec(s KubeadmControlPlaneSpec, namespace string
 	return allErrs
 }
 
+// validateKubeadmConfigOverrides validates that every override is a JSON merge patch that can be applied to the KubeadmConfigSpec.
+func validateKubeadmConfigOverrides(s KubeadmControlPlaneSpec, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(s.KubeadmConfigOverrides) == 0 {
+		return allErrs
+	}
+
+	specJSON, err := json.Marshal(s.KubeadmConfigSpec)
+	if err != nil {
+		return append(allErrs, field.InternalError(pathPrefix, err))
+	}
+	for i, override := range s.KubeadmConfigOverrides {
+		patchPath := pathPrefix.Index(i).Child("mergePatch")
+
+		patch := map[string]interface{}{}
+		if err := json.Unmarshal(override.MergePatch.Raw, &patch); err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, string(override.MergePatch.Raw), "must be a JSON object"))
+			continue
+		}
+		if _, ok := patch[clusterConfiguration]; ok {
+			allErrs = append(allErrs, field.Forbidden(patchPath.Child(clusterConfiguration), "cannot be overridden, because it must be the same for all the machines"))
+			continue
+		}
+
+		patchedJSON, err := jsonpatch.MergePatch(specJSON, override.MergePatch.Raw)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, string(override.MergePatch.Raw), fmt.Sprintf("failed to apply patch: %v", err)))
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(patchedJSON))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cabpkv1.KubeadmConfigSpec{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, string(override.MergePatch.Raw), fmt.Sprintf("patched KubeadmConfigSpec is not valid: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
 func validateEtcd(s, prev *KubeadmControlPlaneSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -521,24 +670,96 @@ func (in *KubeadmControlPlane) validateVersion(previousVersion string) (allErrs
 		return allErrs
 	}
 
-	// Since upgrades to the next minor version are allowed, irrespective of the patch version.
-	ceilVersion := semver.Version{
-		Major: fromVersion.Major,
-		Minor: fromVersion.Minor + 2,
-		Patch: 0,
+	if version.Compare(toVersion, fromVersion, version.WithBuildTags()) < 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "version"),
+				fmt.Sprintf("cannot update Kubernetes version from %s to %s: downgrades are not supported", previousVersion, in.Spec.Version),
+			),
+		)
+		return allErrs
 	}
-	if toVersion.GTE(ceilVersion) {
+
+	// Since upgrades to the next minor version are allowed, irrespective of the patch version.
+	if err := version.ValidateUpgrade(fromVersion, toVersion); err != nil {
 		allErrs = append(allErrs,
 			field.Forbidden(
 				field.NewPath("spec", "version"),
-				fmt.Sprintf("cannot update Kubernetes version from %s to %s", previousVersion, in.Spec.Version),
+				fmt.Sprintf("cannot update Kubernetes version from %s to %s: %v", previousVersion, in.Spec.Version, err),
 			),
 		)
+		return allErrs
+	}
+
+	if fromVersion.NE(toVersion) {
+		allErrs = append(allErrs, in.validateKubeletVersionSkew(toVersion)...)
 	}
 
 	return allErrs
 }
 
+// validateKubeletVersionSkew checks that the kubelets of all the Machines in the Cluster are compatible with
+// the given control plane version, according to the Kubernetes version skew policy.
+// NOTE: The Machines are read using the machineReader; if it is not set, the check is skipped.
+func (in *KubeadmControlPlane) validateKubeletVersionSkew(controlPlaneVersion semver.Version) (allErrs field.ErrorList) {
+	clusterName := in.clusterName()
+	if machineReader == nil || clusterName == "" {
+		return allErrs
+	}
+
+	// NOTE: webhook.Validator does not provide a context.
+	ctx := context.Background()
+
+	machines := &clusterv1.MachineList{}
+	if err := machineReader.List(ctx, machines, client.InNamespace(in.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: clusterName}); err != nil {
+		allErrs = append(allErrs,
+			field.InternalError(
+				field.NewPath("spec", "version"),
+				errors.Wrapf(err, "failed to list Machines for Cluster %s/%s", in.Namespace, clusterName),
+			),
+		)
+		return allErrs
+	}
+
+	for _, machine := range machines.Items {
+		if machine.Status.NodeInfo == nil || machine.Status.NodeInfo.KubeletVersion == "" {
+			continue
+		}
+		kubeletVersion, err := version.ParseMajorMinorPatchTolerant(machine.Status.NodeInfo.KubeletVersion)
+		if err != nil {
+			continue
+		}
+		if err := version.ValidateKubeletSkew(controlPlaneVersion, kubeletVersion); err != nil {
+			allErrs = append(allErrs,
+				field.Forbidden(
+					field.NewPath("spec", "version"),
+					fmt.Sprintf("cannot update Kubernetes version to %s: Machine %s runs an incompatible kubelet: %v", in.Spec.Version, machine.Name, err),
+				),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+// clusterName returns the name of the Cluster the KubeadmControlPlane belongs to, read from the cluster name label
+// or from the Cluster owner reference; an empty string is returned if the Cluster is not known yet.
+func (in *KubeadmControlPlane) clusterName() string {
+	if name, ok := in.Labels[clusterv1.ClusterLabelName]; ok {
+		return name
+	}
+	for _, ref := range in.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if ref.Kind == "Cluster" && gv.Group == clusterv1.GroupVersion.Group {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (in *KubeadmControlPlane) ValidateDelete() error {
 	return nil
@@ -22,11 +22,16 @@ import (
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestKubeadmControlPlaneDefault(t *testing.T) {
@@ -126,6 +131,82 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	invalidVersion2 := valid.DeepCopy()
 	invalidVersion2.Spec.Version = "1.16.6"
 
+	validEtcdMemberRemediation := valid.DeepCopy()
+	validEtcdMemberRemediation.Spec.EtcdMemberRemediation = &EtcdMemberRemediation{
+		UnhealthyTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+		MaxUnhealthy:     &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+	}
+
+	invalidEtcdMemberRemediationTimeout := valid.DeepCopy()
+	invalidEtcdMemberRemediationTimeout.Spec.EtcdMemberRemediation = &EtcdMemberRemediation{
+		UnhealthyTimeout: &metav1.Duration{Duration: -1 * time.Minute},
+	}
+
+	invalidEtcdMemberRemediationMaxUnhealthy := valid.DeepCopy()
+	invalidEtcdMemberRemediationMaxUnhealthy.Spec.EtcdMemberRemediation = &EtcdMemberRemediation{
+		MaxUnhealthy: &intstr.IntOrString{Type: intstr.String, StrVal: "one"},
+	}
+
+	validCertificateRotation := valid.DeepCopy()
+	validCertificateRotation.Spec.CertificateRotation = &CertificateRotation{
+		RenewBefore: &metav1.Duration{Duration: 90 * 24 * time.Hour},
+	}
+
+	invalidCertificateRotationRenewBefore := valid.DeepCopy()
+	invalidCertificateRotationRenewBefore.Spec.CertificateRotation = &CertificateRotation{
+		RenewBefore: &metav1.Duration{Duration: 2 * 365 * 24 * time.Hour},
+	}
+
+	validExternalEtcdClientCertificateRotation := evenReplicasExternalEtcd.DeepCopy()
+	validExternalEtcdClientCertificateRotation.Spec.ExternalEtcdClientCertificateRotation = &ExternalEtcdClientCertificateRotation{
+		RenewBefore: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+	}
+
+	invalidExternalEtcdClientCertificateRotationRenewBefore := validExternalEtcdClientCertificateRotation.DeepCopy()
+	invalidExternalEtcdClientCertificateRotationRenewBefore.Spec.ExternalEtcdClientCertificateRotation.RenewBefore.Duration = 0
+
+	invalidExternalEtcdClientCertificateRotationLocalEtcd := valid.DeepCopy()
+	invalidExternalEtcdClientCertificateRotationLocalEtcd.Spec.ExternalEtcdClientCertificateRotation = &ExternalEtcdClientCertificateRotation{}
+
+	validInitialMachineRemediation := valid.DeepCopy()
+	validInitialMachineRemediation.Spec.InitialMachineRemediation = &InitialMachineRemediation{
+		MaxRetry:    pointer.Int32Ptr(5),
+		RetryPeriod: &metav1.Duration{Duration: 10 * time.Minute},
+	}
+
+	invalidInitialMachineRemediationMaxRetry := valid.DeepCopy()
+	invalidInitialMachineRemediationMaxRetry.Spec.InitialMachineRemediation = &InitialMachineRemediation{
+		MaxRetry: pointer.Int32Ptr(-1),
+	}
+
+	invalidInitialMachineRemediationRetryPeriod := valid.DeepCopy()
+	invalidInitialMachineRemediationRetryPeriod.Spec.InitialMachineRemediation = &InitialMachineRemediation{
+		RetryPeriod: &metav1.Duration{Duration: -1 * time.Minute},
+	}
+
+	validKubeadmConfigOverrides := valid.DeepCopy()
+	validKubeadmConfigOverrides.Spec.KubeadmConfigOverrides = []KubeadmConfigOverride{
+		{
+			FailureDomains: []string{"fd1"},
+			MergePatch:     apiextensionsv1.JSON{Raw: []byte(`{"joinConfiguration":{"nodeRegistration":{"kubeletExtraArgs":{"node-labels":"zone=fd1"}}}}`)},
+		},
+	}
+
+	invalidKubeadmConfigOverridesNotAnObject := valid.DeepCopy()
+	invalidKubeadmConfigOverridesNotAnObject.Spec.KubeadmConfigOverrides = []KubeadmConfigOverride{
+		{MergePatch: apiextensionsv1.JSON{Raw: []byte(`["files"]`)}},
+	}
+
+	invalidKubeadmConfigOverridesClusterConfiguration := valid.DeepCopy()
+	invalidKubeadmConfigOverridesClusterConfiguration.Spec.KubeadmConfigOverrides = []KubeadmConfigOverride{
+		{MergePatch: apiextensionsv1.JSON{Raw: []byte(`{"clusterConfiguration":{"clusterName":"other"}}`)}},
+	}
+
+	invalidKubeadmConfigOverridesUnknownField := valid.DeepCopy()
+	invalidKubeadmConfigOverridesUnknownField.Spec.KubeadmConfigOverrides = []KubeadmConfigOverride{
+		{MergePatch: apiextensionsv1.JSON{Raw: []byte(`{"joinConfiguration":{"nodeRegistrationn":{}}}`)}},
+	}
+
 	tests := []struct {
 		name      string
 		expectErr bool
@@ -181,6 +262,81 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidMaxSurge,
 		},
+		{
+			name:      "should succeed when given a valid etcd member remediation",
+			expectErr: false,
+			kcp:       validEtcdMemberRemediation,
+		},
+		{
+			name:      "should return error when etcd member remediation unhealthyTimeout is negative",
+			expectErr: true,
+			kcp:       invalidEtcdMemberRemediationTimeout,
+		},
+		{
+			name:      "should return error when etcd member remediation maxUnhealthy is not a number or a percentage",
+			expectErr: true,
+			kcp:       invalidEtcdMemberRemediationMaxUnhealthy,
+		},
+		{
+			name:      "should succeed when given a valid certificate rotation",
+			expectErr: false,
+			kcp:       validCertificateRotation,
+		},
+		{
+			name:      "should return error when certificate rotation renewBefore is longer than the certificate validity",
+			expectErr: true,
+			kcp:       invalidCertificateRotationRenewBefore,
+		},
+		{
+			name:      "should succeed when given a valid external etcd client certificate rotation",
+			expectErr: false,
+			kcp:       validExternalEtcdClientCertificateRotation,
+		},
+		{
+			name:      "should return error when external etcd client certificate rotation renewBefore is not positive",
+			expectErr: true,
+			kcp:       invalidExternalEtcdClientCertificateRotationRenewBefore,
+		},
+		{
+			name:      "should return error when external etcd client certificate rotation is set without external etcd",
+			expectErr: true,
+			kcp:       invalidExternalEtcdClientCertificateRotationLocalEtcd,
+		},
+		{
+			name:      "should succeed when given a valid initial machine remediation",
+			expectErr: false,
+			kcp:       validInitialMachineRemediation,
+		},
+		{
+			name:      "should return error when initial machine remediation maxRetry is negative",
+			expectErr: true,
+			kcp:       invalidInitialMachineRemediationMaxRetry,
+		},
+		{
+			name:      "should return error when initial machine remediation retryPeriod is negative",
+			expectErr: true,
+			kcp:       invalidInitialMachineRemediationRetryPeriod,
+		},
+		{
+			name:      "should succeed when given valid kubeadm config overrides",
+			expectErr: false,
+			kcp:       validKubeadmConfigOverrides,
+		},
+		{
+			name:      "should return error when a kubeadm config override is not a JSON object",
+			expectErr: true,
+			kcp:       invalidKubeadmConfigOverridesNotAnObject,
+		},
+		{
+			name:      "should return error when a kubeadm config override patches the cluster configuration",
+			expectErr: true,
+			kcp:       invalidKubeadmConfigOverridesClusterConfiguration,
+		},
+		{
+			name:      "should return error when a kubeadm config override sets an unknown field",
+			expectErr: true,
+			kcp:       invalidKubeadmConfigOverridesUnknownField,
+		},
 	}
 
 	for _, tt := range tests {
@@ -332,6 +488,12 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	validUpdate.Spec.Replicas = pointer.Int32Ptr(5)
 	now := metav1.NewTime(time.Now())
 	validUpdate.Spec.RolloutAfter = &now
+	validUpdate.Spec.KubeadmConfigOverrides = []KubeadmConfigOverride{
+		{
+			FailureDomains: []string{"fd1"},
+			MergePatch:     apiextensionsv1.JSON{Raw: []byte(`{"joinConfiguration":{"nodeRegistration":{"name":"fd1"}}}`)},
+		},
+	}
 
 	scaleToZero := before.DeepCopy()
 	scaleToZero.Spec.Replicas = pointer.Int32Ptr(0)
@@ -381,6 +543,8 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 		return prev
 	}
 	skipMinorControlPlaneVersion := prevKCPWithVersion("v1.18.1")
+	downgradePatchControlPlaneVersion := prevKCPWithVersion("v1.16.5")
+	downgradeMinorControlPlaneVersion := prevKCPWithVersion("v1.15.12")
 	emptyControlPlaneVersion := prevKCPWithVersion("")
 
 	controlPlaneEndpoint := before.DeepCopy()
@@ -538,6 +702,12 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	disableNTPServers := before.DeepCopy()
 	disableNTPServers.Spec.KubeadmConfigSpec.NTP.Enabled = pointer.BoolPtr(false)
 
+	updateContainerImages := before.DeepCopy()
+	updateContainerImages.Spec.KubeadmConfigSpec.ContainerImages = &bootstrapv1.ContainerImages{
+		ImageRepositories: &bootstrapv1.ComponentImageRepositories{CoreDNS: "registry.example.com/coredns"},
+		PrePull:           []string{"registry.example.com/kube-vip:v0.4.0"},
+	}
+
 	tests := []struct {
 		name      string
 		expectErr bool
@@ -796,6 +966,18 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       before.DeepCopy(),
 		},
+		{
+			name:      "should fail when downgrading the control plane patch version",
+			expectErr: true,
+			before:    before,
+			kcp:       downgradePatchControlPlaneVersion,
+		},
+		{
+			name:      "should fail when downgrading the control plane minor version",
+			expectErr: true,
+			before:    before,
+			kcp:       downgradeMinorControlPlaneVersion,
+		},
 		{
 			name:      "should return error when trying to upgrade to v1.19.0",
 			expectErr: true,
@@ -826,6 +1008,12 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       disableNTPServers,
 		},
+		{
+			name:      "should pass if container images are updated",
+			expectErr: false,
+			before:    before,
+			kcp:       updateContainerImages,
+		},
 	}
 
 	for _, tt := range tests {
@@ -842,6 +1030,105 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestKubeadmControlPlaneValidateKubeletVersionSkew(t *testing.T) {
+	before := &KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "foo",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+		},
+		Spec: KubeadmControlPlaneSpec{
+			Version: "v1.16.6",
+			MachineTemplate: KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: "test/v1alpha1",
+					Kind:       "UnknownInfraMachine",
+					Namespace:  "foo",
+					Name:       "infraTemplate",
+				},
+			},
+			Replicas: pointer.Int32Ptr(1),
+		},
+	}
+
+	machineWithKubelet := func(name, clusterName, kubeletVersion string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "foo",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: clusterName},
+			},
+			Status: clusterv1.MachineStatus{
+				NodeInfo: &corev1.NodeSystemInfo{KubeletVersion: kubeletVersion},
+			},
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	tests := []struct {
+		name      string
+		expectErr bool
+		version   string
+		machines  []client.Object
+	}{
+		{
+			name:      "should succeed when all the kubelets are within the skew policy",
+			expectErr: false,
+			version:   "v1.17.0",
+			machines: []client.Object{
+				machineWithKubelet("control-plane", "test-cluster", "v1.16.6"),
+				machineWithKubelet("worker", "test-cluster", "v1.15.3"),
+			},
+		},
+		{
+			name:      "should fail when a kubelet would be too old for the new control plane version",
+			expectErr: true,
+			version:   "v1.17.0",
+			machines: []client.Object{
+				machineWithKubelet("control-plane", "test-cluster", "v1.16.6"),
+				machineWithKubelet("worker", "test-cluster", "v1.14.10"),
+			},
+		},
+		{
+			name:      "should succeed when the too old kubelet belongs to another cluster",
+			expectErr: false,
+			version:   "v1.17.0",
+			machines: []client.Object{
+				machineWithKubelet("worker", "another-cluster", "v1.14.10"),
+			},
+		},
+		{
+			name:      "should succeed when the version does not change",
+			expectErr: false,
+			version:   "v1.16.6",
+			machines: []client.Object{
+				machineWithKubelet("worker", "test-cluster", "v1.13.1"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			defer func(r client.Reader) { machineReader = r }(machineReader)
+			machineReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.machines...).Build()
+
+			kcp := before.DeepCopy()
+			kcp.Spec.Version = tt.version
+			err := kcp.ValidateUpdate(before.DeepCopy())
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("Machine worker runs an incompatible kubelet"))
+			} else {
+				g.Expect(err).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestKubeadmControlPlaneValidateUpdateAfterDefaulting(t *testing.T) {
 	before := &KubeadmControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
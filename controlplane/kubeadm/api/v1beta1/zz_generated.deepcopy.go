@@ -27,6 +27,117 @@ import (
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRotation) DeepCopyInto(out *CertificateRotation) {
+	*out = *in
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotation.
+func (in *CertificateRotation) DeepCopy() *CertificateRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdMemberRemediation) DeepCopyInto(out *EtcdMemberRemediation) {
+	*out = *in
+	if in.UnhealthyTimeout != nil {
+		in, out := &in.UnhealthyTimeout, &out.UnhealthyTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxUnhealthy != nil {
+		in, out := &in.MaxUnhealthy, &out.MaxUnhealthy
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdMemberRemediation.
+func (in *EtcdMemberRemediation) DeepCopy() *EtcdMemberRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdMemberRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalEtcdClientCertificateRotation) DeepCopyInto(out *ExternalEtcdClientCertificateRotation) {
+	*out = *in
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalEtcdClientCertificateRotation.
+func (in *ExternalEtcdClientCertificateRotation) DeepCopy() *ExternalEtcdClientCertificateRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalEtcdClientCertificateRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitialMachineRemediation) DeepCopyInto(out *InitialMachineRemediation) {
+	*out = *in
+	if in.MaxRetry != nil {
+		in, out := &in.MaxRetry, &out.MaxRetry
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetryPeriod != nil {
+		in, out := &in.RetryPeriod, &out.RetryPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitialMachineRemediation.
+func (in *InitialMachineRemediation) DeepCopy() *InitialMachineRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(InitialMachineRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeadmConfigOverride) DeepCopyInto(out *KubeadmConfigOverride) {
+	*out = *in
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.MergePatch.DeepCopyInto(&out.MergePatch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmConfigOverride.
+func (in *KubeadmConfigOverride) DeepCopy() *KubeadmConfigOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeadmConfigOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeadmControlPlane) DeepCopyInto(out *KubeadmControlPlane) {
 	*out = *in
@@ -127,6 +238,33 @@ func (in *KubeadmControlPlaneSpec) DeepCopyInto(out *KubeadmControlPlaneSpec) {
 		*out = new(RolloutStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EtcdMemberRemediation != nil {
+		in, out := &in.EtcdMemberRemediation, &out.EtcdMemberRemediation
+		*out = new(EtcdMemberRemediation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalEtcdClientCertificateRotation != nil {
+		in, out := &in.ExternalEtcdClientCertificateRotation, &out.ExternalEtcdClientCertificateRotation
+		*out = new(ExternalEtcdClientCertificateRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitialMachineRemediation != nil {
+		in, out := &in.InitialMachineRemediation, &out.InitialMachineRemediation
+		*out = new(InitialMachineRemediation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeadmConfigOverrides != nil {
+		in, out := &in.KubeadmConfigOverrides, &out.KubeadmConfigOverrides
+		*out = make([]KubeadmConfigOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneSpec.
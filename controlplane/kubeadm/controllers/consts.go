@@ -27,7 +27,27 @@ const (
 	// up/down if some preflight check for those operation has failed.
 	preflightFailedRequeueAfter = 15 * time.Second
 
+	// maxPreflightFailedRequeueAfter is the maximum interval between attempts to scale
+	// up/down if preflight checks are persistently failing.
+	maxPreflightFailedRequeueAfter = 2 * time.Minute
+
 	// dependentCertRequeueAfter is how long to wait before checking again to see if
 	// dependent certificates have been created.
 	dependentCertRequeueAfter = 30 * time.Second
+
+	// defaultEtcdMemberUnhealthyTimeout is how long an etcd member must be unhealthy before the machine hosting it
+	// is remediated, if not specified in the KubeadmControlPlane.
+	defaultEtcdMemberUnhealthyTimeout = 5 * time.Minute
+
+	// defaultEtcdClientCertificateRenewBefore is how long before expiring the apiserver-etcd-client certificate
+	// is renewed, if not specified in the KubeadmControlPlane.
+	defaultEtcdClientCertificateRenewBefore = 30 * 24 * time.Hour
+
+	// defaultInitialMachineRemediationMaxRetry is the maximum number of times the first control plane machine is
+	// remediated before the control plane is initialized, if not specified in the KubeadmControlPlane.
+	defaultInitialMachineRemediationMaxRetry = 3
+
+	// defaultInitialMachineRemediationRetryPeriod is how long to wait after remediating the first control plane machine
+	// before remediating its replacement, if not specified in the KubeadmControlPlane.
+	defaultInitialMachineRemediationRetryPeriod = 5 * time.Minute
 )
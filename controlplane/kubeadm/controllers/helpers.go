@@ -18,8 +18,11 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -35,12 +38,14 @@ import (
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/secret"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *KubeadmControlPlaneReconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane) (ctrl.Result, error) {
@@ -85,7 +90,7 @@ func (r *KubeadmControlPlaneReconciler) reconcileKubeconfig(ctx context.Context,
 		return ctrl.Result{}, nil
 	}
 
-	needsRotation, err := kubeconfig.NeedsClientCertRotation(configSecret, certs.ClientCertificateRenewalDuration)
+	needsRotation, err := kubeconfig.NeedsClientCertRotation(configSecret, certificatesRenewBefore(kcp))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -121,6 +126,260 @@ func (r *KubeadmControlPlaneReconciler) adoptKubeconfigSecret(ctx context.Contex
 	return nil
 }
 
+// reconcileExternalEtcdClientCertificate renews the apiserver-etcd-client certificate used by the API servers to connect
+// to an external etcd cluster when it is about to expire, if rotation is enabled. The time of the renewal is stored
+// in an annotation on the secret, so control plane machines created before this time are rolled out.
+func (r *KubeadmControlPlaneReconciler) reconcileExternalEtcdClientCertificate(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	rotation := kcp.Spec.ExternalEtcdClientCertificateRotation
+	if rotation == nil || kcp.Spec.KubeadmConfigSpec.ClusterConfiguration == nil || kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.External == nil {
+		return nil
+	}
+
+	clusterName := util.ObjectKey(cluster)
+	clientSecret, err := secret.GetFromNamespacedName(ctx, r.Client, clusterName, secret.APIServerEtcdClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the apiserver-etcd-client Secret")
+	}
+	clientCert, err := certs.DecodeCertPEM(clientSecret.Data[secret.TLSCrtDataName])
+	if err != nil {
+		return errors.Wrap(err, "failed to decode the apiserver-etcd-client certificate")
+	}
+
+	if time.Until(clientCert.NotAfter) > externalEtcdClientCertificateRenewBefore(kcp) {
+		return nil
+	}
+
+	caSecret, err := secret.GetFromNamespacedName(ctx, r.Client, clusterName, secret.EtcdCA)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the etcd CA Secret")
+	}
+	if len(caSecret.Data[secret.TLSKeyDataName]) == 0 {
+		log.Info("The apiserver-etcd-client certificate is about to expire, but it can't be renewed because the etcd CA Secret does not contain the CA key", "expiry", clientCert.NotAfter)
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "EtcdClientCertificateRenewalFailed",
+			"The apiserver-etcd-client certificate expires at %s, but it can't be renewed because Secret %s does not contain the etcd CA key", clientCert.NotAfter, caSecret.Name)
+		return nil
+	}
+	caCert, err := certs.DecodeCertPEM(caSecret.Data[secret.TLSCrtDataName])
+	if err != nil {
+		return errors.Wrap(err, "failed to decode the etcd CA certificate")
+	}
+	caKey, err := certs.DecodePrivateKeyPEM(caSecret.Data[secret.TLSKeyDataName])
+	if err != nil {
+		return errors.Wrap(err, "failed to decode the etcd CA key")
+	}
+
+	key, err := certs.NewPrivateKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the apiserver-etcd-client key")
+	}
+	cfg := &certs.Config{
+		CommonName:   clientCert.Subject.CommonName,
+		Organization: clientCert.Subject.Organization,
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	newCert, err := cfg.NewSignedCert(key, caCert, caKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign the apiserver-etcd-client certificate")
+	}
+
+	log.Info("Renewing the apiserver-etcd-client certificate", "expiry", clientCert.NotAfter)
+	patchHelper, err := patch.NewHelper(clientSecret, r.Client)
+	if err != nil {
+		return err
+	}
+	clientSecret.Data[secret.TLSCrtDataName] = certs.EncodeCertPEM(newCert)
+	clientSecret.Data[secret.TLSKeyDataName] = certs.EncodePrivateKeyPEM(key)
+	annotations := clientSecret.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[controlplanev1.EtcdClientCertificateRenewedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	clientSecret.SetAnnotations(annotations)
+	if err := patchHelper.Patch(ctx, clientSecret); err != nil {
+		return errors.Wrap(err, "failed to update the apiserver-etcd-client Secret")
+	}
+
+	r.recorder.Eventf(kcp, corev1.EventTypeNormal, "EtcdClientCertificateRenewed",
+		"Renewed the apiserver-etcd-client certificate expiring at %s; control plane machines will be rolled out", clientCert.NotAfter)
+	return nil
+}
+
+// certificatesRenewBefore returns how long before expiring the certificates signed by the cluster CA and managed by KCP are renewed.
+func certificatesRenewBefore(kcp *controlplanev1.KubeadmControlPlane) time.Duration {
+	if kcp.Spec.CertificateRotation != nil && kcp.Spec.CertificateRotation.RenewBefore != nil {
+		return kcp.Spec.CertificateRotation.RenewBefore.Duration
+	}
+	return certs.ClientCertificateRenewalDuration
+}
+
+// externalEtcdClientCertificateRenewBefore returns how long before expiring the apiserver-etcd-client certificate is renewed.
+func externalEtcdClientCertificateRenewBefore(kcp *controlplanev1.KubeadmControlPlane) time.Duration {
+	if kcp.Spec.ExternalEtcdClientCertificateRotation != nil && kcp.Spec.ExternalEtcdClientCertificateRotation.RenewBefore != nil {
+		return kcp.Spec.ExternalEtcdClientCertificateRotation.RenewBefore.Duration
+	}
+	return defaultEtcdClientCertificateRenewBefore
+}
+
+// reconcileCertificatesExpiry sets the CertificatesValid condition, reporting how long until the certificates
+// managed by KCP expire; certificates which are not generated yet are ignored.
+func (r *KubeadmControlPlaneReconciler) reconcileCertificatesExpiry(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane) {
+	log := ctrl.LoggerFrom(ctx)
+
+	type managedCertificate struct {
+		name        string
+		purpose     secret.Purpose
+		renewBefore time.Duration
+	}
+	managedCertificates := []managedCertificate{
+		{name: "kubeconfig client certificate", purpose: secret.Kubeconfig, renewBefore: certificatesRenewBefore(kcp)},
+	}
+	if kcp.Spec.ExternalEtcdClientCertificateRotation != nil {
+		managedCertificates = append(managedCertificates,
+			managedCertificate{name: "apiserver-etcd-client certificate", purpose: secret.APIServerEtcdClient, renewBefore: externalEtcdClientCertificateRenewBefore(kcp)},
+		)
+	}
+
+	var messages []string
+	expiring, expired := false, false
+	for _, c := range managedCertificates {
+		expiry, err := r.certificateExpiry(ctx, cluster, c.purpose)
+		if err != nil {
+			log.Error(err, "failed to read the expiry of the certificate", "certificate", c.name)
+			conditions.MarkUnknown(kcp, controlplanev1.CertificatesValidCondition, controlplanev1.CertificatesExpiryCheckFailedReason, "Failed to read the expiry of the %s: %v", c.name, err)
+			return
+		}
+		if expiry.IsZero() {
+			continue
+		}
+
+		untilExpiry := time.Until(expiry)
+		switch {
+		case untilExpiry <= 0:
+			expired = true
+			messages = append(messages, fmt.Sprintf("%s expired on %s", c.name, expiry.UTC().Format(time.RFC3339)))
+			continue
+		case untilExpiry < c.renewBefore:
+			expiring = true
+		}
+		messages = append(messages, fmt.Sprintf("%s expires in %d days", c.name, int(untilExpiry.Hours()/24)))
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+	message := strings.Join(messages, ", ")
+	switch {
+	case expired:
+		conditions.MarkFalse(kcp, controlplanev1.CertificatesValidCondition, controlplanev1.CertificatesExpiredReason, clusterv1.ConditionSeverityError, message)
+	case expiring:
+		conditions.MarkFalse(kcp, controlplanev1.CertificatesValidCondition, controlplanev1.CertificatesExpiringReason, clusterv1.ConditionSeverityWarning, message)
+	default:
+		conditions.Set(kcp, &clusterv1.Condition{
+			Type:    controlplanev1.CertificatesValidCondition,
+			Status:  corev1.ConditionTrue,
+			Message: message,
+		})
+	}
+}
+
+// certificateExpiry returns the time the certificate with the given purpose expires, or a zero time if the
+// Secret storing the certificate does not exist.
+func (r *KubeadmControlPlaneReconciler) certificateExpiry(ctx context.Context, cluster *clusterv1.Cluster, purpose secret.Purpose) (time.Time, error) {
+	certSecret, err := secret.GetFromNamespacedName(ctx, r.Client, util.ObjectKey(cluster), purpose)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	if purpose == secret.Kubeconfig {
+		return kubeconfig.ClientCertExpiry(certSecret)
+	}
+	cert, err := certs.DecodeCertPEM(certSecret.Data[secret.TLSCrtDataName])
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to decode certificate")
+	}
+	return cert.NotAfter, nil
+}
+
+// syncMachinesMetadata propagates the labels and annotations defined in KCP.spec.machineTemplate.metadata to the
+// existing control plane Machines, their InfrastructureMachines and KubeadmConfigs, without triggering a rollout.
+// Labels are then propagated to the Nodes by the Machine controller via the Machine's node labels annotation, if set.
+// NOTE: Labels and annotations removed from KCP.spec.machineTemplate.metadata are not removed from the existing objects,
+// because it is not possible to tell them apart from the ones added by users or other controllers.
+func (r *KubeadmControlPlaneReconciler) syncMachinesMetadata(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	labels := controlPlane.KCP.Spec.MachineTemplate.ObjectMeta.Labels
+	annotations := controlPlane.KCP.Spec.MachineTemplate.ObjectMeta.Annotations
+	if len(labels) == 0 && len(annotations) == 0 {
+		return nil
+	}
+
+	errList := []error{}
+	for _, machine := range controlPlane.Machines.Filter(collections.Not(collections.HasDeletionTimestamp)) {
+		if err := r.syncObjectMetadata(ctx, machine, labels, annotations); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to sync metadata of Machine %s", machine.Name))
+		}
+		if infraMachine, ok := controlPlane.InfraMachine(machine.Name); ok {
+			if err := r.syncObjectMetadata(ctx, infraMachine, labels, annotations); err != nil {
+				errList = append(errList, errors.Wrapf(err, "failed to sync metadata of %s %s", infraMachine.GetKind(), infraMachine.GetName()))
+			}
+		}
+		if kubeadmConfig, ok := controlPlane.KubeadmConfig(machine.Name); ok {
+			if err := r.syncObjectMetadata(ctx, kubeadmConfig, labels, annotations); err != nil {
+				errList = append(errList, errors.Wrapf(err, "failed to sync metadata of KubeadmConfig %s", kubeadmConfig.Name))
+			}
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// syncObjectMetadata patches an object so it includes the given labels and annotations, if required.
+func (r *KubeadmControlPlaneReconciler) syncObjectMetadata(ctx context.Context, obj client.Object, labels, annotations map[string]string) error {
+	if isSubsetMapOf(labels, obj.GetLabels()) && isSubsetMapOf(annotations, obj.GetAnnotations()) {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if len(labels) > 0 {
+		objLabels := obj.GetLabels()
+		if objLabels == nil {
+			objLabels = map[string]string{}
+		}
+		for k, v := range labels {
+			objLabels[k] = v
+		}
+		obj.SetLabels(objLabels)
+	}
+	if len(annotations) > 0 {
+		objAnnotations := obj.GetAnnotations()
+		if objAnnotations == nil {
+			objAnnotations = map[string]string{}
+		}
+		for k, v := range annotations {
+			objAnnotations[k] = v
+		}
+		obj.SetAnnotations(objAnnotations)
+	}
+
+	if err := r.Client.Patch(ctx, obj, patchBase); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isSubsetMapOf returns true if all the key value pairs in base are included in existing.
+func isSubsetMapOf(base map[string]string, existing map[string]string) bool {
+	for key, value := range base {
+		if existingValue, ok := existing[key]; !ok || existingValue != value {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *KubeadmControlPlaneReconciler) reconcileExternalReference(ctx context.Context, cluster *clusterv1.Cluster, ref *corev1.ObjectReference) error {
 	if !strings.HasSuffix(ref.Kind, clusterv1.TemplateSuffix) {
 		return nil
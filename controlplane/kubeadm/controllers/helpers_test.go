@@ -17,7 +17,9 @@ limitations under the License.
 package controllers
 
 import (
+	"crypto/x509"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +32,9 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/secret"
@@ -322,6 +327,310 @@ func TestKubeadmControlPlaneReconciler_reconcileKubeconfig(t *testing.T) {
 	g.Expect(kubeconfigSecret.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, cluster.Name))
 }
 
+func TestKubeadmControlPlaneReconciler_reconcileExternalEtcdClientCertificate(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.16.6",
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+					Etcd: bootstrapv1.Etcd{
+						External: &bootstrapv1.ExternalEtcd{},
+					},
+				},
+			},
+			ExternalEtcdClientCertificateRotation: &controlplanev1.ExternalEtcdClientCertificateRotation{
+				RenewBefore: &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		},
+	}
+
+	// newSecrets returns the etcd CA Secret and an apiserver-etcd-client Secret with a certificate expiring after the given duration.
+	newSecrets := func(g *WithT, clientCertDuration time.Duration) (*corev1.Secret, *corev1.Secret) {
+		clusterCerts := secret.NewCertificatesForInitialControlPlane(&bootstrapv1.ClusterConfiguration{})
+		g.Expect(clusterCerts.Generate()).To(Succeed())
+		etcdCA := clusterCerts.GetByPurpose(secret.EtcdCA)
+
+		caCert, err := certs.DecodeCertPEM(etcdCA.KeyPair.Cert)
+		g.Expect(err).ToNot(HaveOccurred())
+		caKey, err := certs.DecodePrivateKeyPEM(etcdCA.KeyPair.Key)
+		g.Expect(err).ToNot(HaveOccurred())
+		clientKey, err := certs.NewPrivateKey()
+		g.Expect(err).ToNot(HaveOccurred())
+		cfg := &certs.Config{
+			CommonName:   "kube-apiserver-etcd-client",
+			Organization: []string{"system:masters"},
+			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			Duration:     clientCertDuration,
+		}
+		clientCert, err := cfg.NewSignedCert(clientKey, caCert, caKey)
+		g.Expect(err).ToNot(HaveOccurred())
+		etcdClient := &secret.Certificate{
+			Purpose: secret.APIServerEtcdClient,
+			KeyPair: &certs.KeyPair{
+				Cert: certs.EncodeCertPEM(clientCert),
+				Key:  certs.EncodePrivateKeyPEM(clientKey),
+			},
+		}
+
+		return etcdCA.AsSecret(util.ObjectKey(cluster), metav1.OwnerReference{}), etcdClient.AsSecret(util.ObjectKey(cluster), metav1.OwnerReference{})
+	}
+
+	getClientSecret := func(g *WithT, c client.Client) *corev1.Secret {
+		clientSecret := &corev1.Secret{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: secret.Name(cluster.Name, secret.APIServerEtcdClient)}, clientSecret)).To(Succeed())
+		return clientSecret
+	}
+
+	t.Run("does not renew the certificate if it does not expire within the renewal window", func(t *testing.T) {
+		g := NewWithT(t)
+
+		caSecret, clientSecret := newSecrets(g, 48*time.Hour)
+		r := &KubeadmControlPlaneReconciler{
+			Client:   newFakeClient(kcp.DeepCopy(), caSecret, clientSecret.DeepCopy()),
+			recorder: record.NewFakeRecorder(32),
+		}
+		g.Expect(r.reconcileExternalEtcdClientCertificate(ctx, cluster, kcp)).To(Succeed())
+
+		updatedClientSecret := getClientSecret(g, r.Client)
+		g.Expect(updatedClientSecret.Data).To(Equal(clientSecret.Data))
+		g.Expect(updatedClientSecret.Annotations).ToNot(HaveKey(controlplanev1.EtcdClientCertificateRenewedAtAnnotation))
+	})
+
+	t.Run("renews the certificate if it expires within the renewal window", func(t *testing.T) {
+		g := NewWithT(t)
+
+		caSecret, clientSecret := newSecrets(g, 12*time.Hour)
+		r := &KubeadmControlPlaneReconciler{
+			Client:   newFakeClient(kcp.DeepCopy(), caSecret, clientSecret.DeepCopy()),
+			recorder: record.NewFakeRecorder(32),
+		}
+		g.Expect(r.reconcileExternalEtcdClientCertificate(ctx, cluster, kcp)).To(Succeed())
+
+		updatedClientSecret := getClientSecret(g, r.Client)
+		g.Expect(updatedClientSecret.Data).ToNot(Equal(clientSecret.Data))
+		g.Expect(updatedClientSecret.Annotations).To(HaveKey(controlplanev1.EtcdClientCertificateRenewedAtAnnotation))
+
+		renewedCert, err := certs.DecodeCertPEM(updatedClientSecret.Data[secret.TLSCrtDataName])
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renewedCert.Subject.CommonName).To(Equal("kube-apiserver-etcd-client"))
+		g.Expect(renewedCert.NotAfter).To(BeTemporally(">", time.Now().Add(24*time.Hour)))
+	})
+
+	t.Run("does not renew the certificate if the etcd CA key is not available", func(t *testing.T) {
+		g := NewWithT(t)
+
+		caSecret, clientSecret := newSecrets(g, 12*time.Hour)
+		delete(caSecret.Data, secret.TLSKeyDataName)
+		r := &KubeadmControlPlaneReconciler{
+			Client:   newFakeClient(kcp.DeepCopy(), caSecret, clientSecret.DeepCopy()),
+			recorder: record.NewFakeRecorder(32),
+		}
+		g.Expect(r.reconcileExternalEtcdClientCertificate(ctx, cluster, kcp)).To(Succeed())
+
+		updatedClientSecret := getClientSecret(g, r.Client)
+		g.Expect(updatedClientSecret.Data).To(Equal(clientSecret.Data))
+	})
+}
+
+func TestKubeadmControlPlaneReconciler_reconcileCertificatesExpiry(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "test.local", Port: 8443},
+		},
+	}
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.16.6",
+		},
+	}
+
+	// newReconciler returns a reconciler with a fake client storing the cluster CA and the kubeconfig generated by KCP.
+	newReconciler := func(g *WithT) *KubeadmControlPlaneReconciler {
+		clusterCerts := secret.NewCertificatesForInitialControlPlane(&bootstrapv1.ClusterConfiguration{})
+		g.Expect(clusterCerts.Generate()).To(Succeed())
+		caCertSecret := clusterCerts.GetByPurpose(secret.ClusterCA).AsSecret(
+			util.ObjectKey(cluster),
+			*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane")),
+		)
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:   newFakeClient(kcp.DeepCopy(), caCertSecret),
+			recorder: record.NewFakeRecorder(32),
+		}
+		_, err := r.reconcileKubeconfig(ctx, cluster, kcp)
+		g.Expect(err).ToNot(HaveOccurred())
+		return r
+	}
+
+	t.Run("reports the days until the certificates expire", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := newReconciler(g)
+		kcp := kcp.DeepCopy()
+		r.reconcileCertificatesExpiry(ctx, cluster, kcp)
+
+		g.Expect(conditions.IsTrue(kcp, controlplanev1.CertificatesValidCondition)).To(BeTrue())
+		g.Expect(conditions.GetMessage(kcp, controlplanev1.CertificatesValidCondition)).To(MatchRegexp(`kubeconfig client certificate expires in 36[45] days`))
+	})
+
+	t.Run("reports the certificates expiring within the renewal threshold", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := newReconciler(g)
+		kcp := kcp.DeepCopy()
+		kcp.Spec.CertificateRotation = &controlplanev1.CertificateRotation{
+			RenewBefore: &metav1.Duration{Duration: 400 * 24 * time.Hour},
+		}
+		r.reconcileCertificatesExpiry(ctx, cluster, kcp)
+
+		g.Expect(conditions.IsFalse(kcp, controlplanev1.CertificatesValidCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(kcp, controlplanev1.CertificatesValidCondition)).To(Equal(controlplanev1.CertificatesExpiringReason))
+		g.Expect(*conditions.GetSeverity(kcp, controlplanev1.CertificatesValidCondition)).To(Equal(clusterv1.ConditionSeverityWarning))
+	})
+
+	t.Run("does not report certificates not generated yet", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:   newFakeClient(kcp.DeepCopy()),
+			recorder: record.NewFakeRecorder(32),
+		}
+		kcp := kcp.DeepCopy()
+		r.reconcileCertificatesExpiry(ctx, cluster, kcp)
+
+		g.Expect(conditions.Has(kcp, controlplanev1.CertificatesValidCondition)).To(BeFalse())
+	})
+}
+
+func TestKubeadmControlPlaneReconciler_syncMachinesMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kcp-foo",
+			Namespace: cluster.Namespace,
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.16.6",
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels:      map[string]string{"label": "new"},
+					Annotations: map[string]string{"annotation": "new"},
+				},
+			},
+		},
+	}
+
+	infraMachine := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "GenericMachine",
+			"apiVersion": "generic.io/v1",
+			"metadata": map[string]interface{}{
+				"name":      "infra-foo",
+				"namespace": cluster.Namespace,
+				"labels": map[string]interface{}{
+					"label": "old",
+				},
+			},
+		},
+	}
+
+	kubeadmConfig := &bootstrapv1.KubeadmConfig{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "KubeadmConfig",
+			APIVersion: bootstrapv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config-foo",
+			Namespace: cluster.Namespace,
+		},
+	}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-foo",
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				"other": "value",
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+			Version:     utilpointer.StringPtr("v1.16.6"),
+			InfrastructureRef: corev1.ObjectReference{
+				Kind:       infraMachine.GetKind(),
+				APIVersion: infraMachine.GetAPIVersion(),
+				Name:       infraMachine.GetName(),
+				Namespace:  infraMachine.GetNamespace(),
+			},
+			Bootstrap: clusterv1.Bootstrap{
+				ConfigRef: &corev1.ObjectReference{
+					Kind:       kubeadmConfig.Kind,
+					APIVersion: kubeadmConfig.APIVersion,
+					Name:       kubeadmConfig.Name,
+					Namespace:  kubeadmConfig.Namespace,
+				},
+			},
+		},
+	}
+
+	fakeClient := newFakeClient(kcp.DeepCopy(), machine.DeepCopy(), infraMachine.DeepCopy(), kubeadmConfig.DeepCopy())
+	r := &KubeadmControlPlaneReconciler{
+		Client:   fakeClient,
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	controlPlane, err := internal.NewControlPlane(ctx, fakeClient, cluster, kcp, collections.FromMachines(machine))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(r.syncMachinesMetadata(ctx, controlPlane)).To(Succeed())
+
+	gotMachine := &clusterv1.Machine{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machine), gotMachine)).To(Succeed())
+	g.Expect(gotMachine.Labels).To(HaveKeyWithValue("label", "new"))
+	g.Expect(gotMachine.Labels).To(HaveKeyWithValue("other", "value"))
+	g.Expect(gotMachine.Annotations).To(HaveKeyWithValue("annotation", "new"))
+
+	gotInfraMachine, err := external.Get(ctx, fakeClient, &machine.Spec.InfrastructureRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(gotInfraMachine.GetLabels()).To(HaveKeyWithValue("label", "new"))
+	g.Expect(gotInfraMachine.GetAnnotations()).To(HaveKeyWithValue("annotation", "new"))
+
+	gotKubeadmConfig := &bootstrapv1.KubeadmConfig{}
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(kubeadmConfig), gotKubeadmConfig)).To(Succeed())
+	g.Expect(gotKubeadmConfig.Labels).To(HaveKeyWithValue("label", "new"))
+	g.Expect(gotKubeadmConfig.Annotations).To(HaveKeyWithValue("annotation", "new"))
+
+	// The machine is not considered for rollout, because the metadata is propagated in place.
+	g.Expect(controlPlane.MachinesNeedingRollout()).To(BeEmpty())
+}
+
 func TestCloneConfigsAndGenerateMachine(t *testing.T) {
 	g := NewWithT(t)
 
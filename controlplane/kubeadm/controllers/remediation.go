@@ -18,15 +18,22 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +44,11 @@ import (
 func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.Context, controlPlane *internal.ControlPlane) (ret ctrl.Result, retErr error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	// The state of the remediation of the first control plane machine is not required once the control plane is initialized.
+	if controlPlane.KCP.Status.Initialized {
+		delete(controlPlane.KCP.Annotations, controlplanev1.InitialMachineRemediationAnnotation)
+	}
+
 	// Gets all machines that have `MachineHealthCheckSucceeded=False` (indicating a problem was detected on the machine)
 	// and `MachineOwnerRemediated` present, indicating that this controller is responsible for performing remediation.
 	unhealthyMachines := controlPlane.UnhealthyMachines()
@@ -68,13 +80,19 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 		if err := patchHelper.Patch(ctx, machineToBeRemediated, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.MachineOwnerRemediatedCondition,
 		}}); err != nil {
-			log.Error(err, "Failed to patch control plane Machine", "machine", machineToBeRemediated.Name)
+			log.Error(err, "Failed to patch control plane Machine", logutil.MachineKey, logutil.KObj(machineToBeRemediated))
 			if retErr == nil {
 				retErr = errors.Wrapf(err, "failed to patch control plane Machine %s", machineToBeRemediated.Name)
 			}
 		}
 	}()
 
+	// The first control plane machine failing before the control plane is initialized is remediated with a dedicated
+	// process, given that there is no etcd quorum to preserve and no other machine to take over.
+	if !controlPlane.KCP.Status.Initialized {
+		return r.remediateInitialMachine(ctx, controlPlane, machineToBeRemediated)
+	}
+
 	// Before starting remediation, run preflight checks in order to verify it is safe to remediate.
 	// If any of the following checks fails, we'll surface the reason in the MachineOwnerRemediated condition.
 
@@ -117,43 +135,237 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 		}
 	}
 
+	if err := r.removeMachineFromWorkloadCluster(ctx, controlPlane, machineToBeRemediated, controlPlane.HealthyMachines().Newest()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Client.Delete(ctx, machineToBeRemediated); err != nil {
+		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete unhealthy machine %s", machineToBeRemediated.Name)
+	}
+
+	log.Info("Remediating unhealthy machine", "UnhealthyMachine", machineToBeRemediated.Name)
+	conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationInProgressReason, clusterv1.ConditionSeverityWarning, "")
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// initialMachineRemediation is the state of the remediation of the first control plane machine, stored in the
+// InitialMachineRemediationAnnotation of the KubeadmControlPlane.
+type initialMachineRemediation struct {
+	// Machine is the name of the last machine remediated.
+	Machine string `json:"machine"`
+
+	// RetryCount is the number of machines remediated.
+	RetryCount int32 `json:"retryCount"`
+
+	// LastRemediation is the time of the last remediation.
+	LastRemediation metav1.Time `json:"lastRemediation"`
+}
+
+// remediateInitialMachine remediates the first control plane machine, failing before the control plane is initialized,
+// by deleting it so KCP creates a replacement. Remediation is retried up to InitialMachineRemediation.MaxRetry times,
+// waiting InitialMachineRemediation.RetryPeriod after the first remediation and doubling the wait at every retry.
+func (r *KubeadmControlPlaneReconciler) remediateInitialMachine(ctx context.Context, controlPlane *internal.ControlPlane, machineToBeRemediated *clusterv1.Machine) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// The cluster MUST have no machines with a deletion timestamp. This rule prevents KCP taking actions while the cluster is in a transitional state.
+	if controlPlane.HasDeletingMachine() {
+		log.Info("The first control plane machine needs remediation, but there are other control-plane machines being deleted. Skipping remediation", "UnhealthyMachine", machineToBeRemediated.Name)
+		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP waiting for control plane machine deletion to complete before triggering remediation")
+		return ctrl.Result{}, nil
+	}
+
+	maxRetry := int32(defaultInitialMachineRemediationMaxRetry)
+	retryPeriod := defaultInitialMachineRemediationRetryPeriod
+	if settings := controlPlane.KCP.Spec.InitialMachineRemediation; settings != nil {
+		if settings.MaxRetry != nil {
+			maxRetry = *settings.MaxRetry
+		}
+		if settings.RetryPeriod != nil {
+			retryPeriod = settings.RetryPeriod.Duration
+		}
+	}
+
+	remediation := &initialMachineRemediation{}
+	if value, ok := controlPlane.KCP.Annotations[controlplanev1.InitialMachineRemediationAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), remediation); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to unmarshal the %s annotation", controlplanev1.InitialMachineRemediationAnnotation)
+		}
+	}
+
+	if remediation.RetryCount >= maxRetry {
+		log.Info("The first control plane machine needs remediation, but the maximum number of retries is reached. Skipping remediation", "UnhealthyMachine", machineToBeRemediated.Name, "MaxRetry", maxRetry)
+		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because the maximum number of retries (%d) is reached", maxRetry)
+		return ctrl.Result{}, nil
+	}
+
+	// Wait before remediating the replacement of a machine already remediated, doubling the wait at every retry,
+	// so the failure has a chance to be resolved (e.g. the infrastructure being temporarily unavailable).
+	if remediation.RetryCount > 0 {
+		wait := retryPeriod
+		for i := int32(1); i < remediation.RetryCount; i++ {
+			wait *= 2
+		}
+		if next := remediation.LastRemediation.Add(wait); time.Now().Before(next) {
+			log.Info("The first control plane machine needs remediation, but the retry period is not expired yet. Waiting", "UnhealthyMachine", machineToBeRemediated.Name, "RetryCount", remediation.RetryCount, "RetryPeriod", wait.String())
+			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP waiting until %s before triggering remediation", next.UTC().Format(time.RFC3339))
+			return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+		}
+	}
+
+	if err := r.Client.Delete(ctx, machineToBeRemediated); err != nil && !apierrors.IsNotFound(err) {
+		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete unhealthy machine %s", machineToBeRemediated.Name)
+	}
+
+	remediation.Machine = machineToBeRemediated.Name
+	remediation.RetryCount++
+	remediation.LastRemediation = metav1.Now()
+	value, err := json.Marshal(remediation)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to marshal the %s annotation", controlplanev1.InitialMachineRemediationAnnotation)
+	}
+	if controlPlane.KCP.Annotations == nil {
+		controlPlane.KCP.Annotations = map[string]string{}
+	}
+	controlPlane.KCP.Annotations[controlplanev1.InitialMachineRemediationAnnotation] = string(value)
+
+	log.Info("Remediating the first control plane machine", "UnhealthyMachine", machineToBeRemediated.Name, "RetryCount", remediation.RetryCount, "MaxRetry", maxRetry)
+	r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeNormal, "InitialMachineRemediation",
+		"Deleted the first control plane Machine %s, failed before the control plane was initialized (retry %d of %d)", machineToBeRemediated.Name, remediation.RetryCount, maxRetry)
+	conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationInProgressReason, clusterv1.ConditionSeverityWarning, "")
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// reconcileUnhealthyEtcdMembers tries to remediate KubeadmControlPlane machines hosting an etcd member which is unhealthy
+// for longer than the configured timeout, e.g. a member reporting alarms or stuck as a learner, even if the machines are
+// otherwise healthy and thus not remediated by MachineHealthCheck.
+// NOTE: remediation happens at the first reconcile after the timeout is expired.
+func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyEtcdMembers(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	remediation := controlPlane.KCP.Spec.EtcdMemberRemediation
+	if remediation == nil || !controlPlane.IsEtcdManaged() {
+		return ctrl.Result{}, nil
+	}
+
+	// Remediation of machines marked as unhealthy by MachineHealthCheck takes precedence.
+	if controlPlane.HasUnhealthyMachine() {
+		return ctrl.Result{}, nil
+	}
+
+	unhealthyMachines := controlPlane.Machines.Filter(hasUnhealthyEtcdMember)
+	if len(unhealthyMachines) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	// If there are more unhealthy etcd members than allowed, the problem most likely does not depend on a single
+	// machine, so remediation is skipped.
+	maxUnhealthy := intstr.FromInt(1)
+	if remediation.MaxUnhealthy != nil {
+		maxUnhealthy = *remediation.MaxUnhealthy
+	}
+	maxUnhealthyMembers, err := intstr.GetScaledValueFromIntOrPercent(&maxUnhealthy, controlPlane.Machines.Len(), false)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to compute the maximum number of unhealthy etcd members")
+	}
+	if len(unhealthyMachines) > maxUnhealthyMembers {
+		log.Info("Etcd members are unhealthy, but their number exceeds the maximum allowed for remediation. Skipping remediation", "UnhealthyMachines", unhealthyMachines.Names(), "MaxUnhealthy", maxUnhealthyMembers)
+		return ctrl.Result{}, nil
+	}
+
+	// Select the machine to be remediated, which is the oldest machine hosting an etcd member unhealthy for longer than the timeout.
+	unhealthyTimeout := defaultEtcdMemberUnhealthyTimeout
+	if remediation.UnhealthyTimeout != nil {
+		unhealthyTimeout = remediation.UnhealthyTimeout.Duration
+	}
+	machineToBeRemediated := unhealthyMachines.Filter(func(machine *clusterv1.Machine) bool {
+		return time.Since(conditions.GetLastTransitionTime(machine, controlplanev1.MachineEtcdMemberHealthyCondition).Time) > unhealthyTimeout
+	}).Oldest()
+	if machineToBeRemediated == nil {
+		return ctrl.Result{}, nil
+	}
+
+	// Apply the same rules used for remediating machines marked as unhealthy by MachineHealthCheck, so we will not remove
+	// a member when the cluster is in a transitional state or if it would result in etcd losing quorum.
+	desiredReplicas := int(*controlPlane.KCP.Spec.Replicas)
+	if controlPlane.Machines.Len() <= 1 || controlPlane.Machines.Len() < desiredReplicas || controlPlane.HasDeletingMachine() {
+		log.Info("A control plane machine hosts an unhealthy etcd member, but the control plane is not in a state that allows remediation. Skipping remediation", "UnhealthyMachine", machineToBeRemediated.Name, "Replicas", desiredReplicas, "CurrentReplicas", controlPlane.Machines.Len())
+		return ctrl.Result{}, nil
+	}
+	canSafelyRemediate, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, machineToBeRemediated)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !canSafelyRemediate {
+		log.Info("A control plane machine hosts an unhealthy etcd member, but removing this machine could result in etcd quorum loss. Skipping remediation", "UnhealthyMachine", machineToBeRemediated.Name)
+		return ctrl.Result{}, nil
+	}
+
+	etcdLeaderCandidate := controlPlane.Machines.Filter(func(machine *clusterv1.Machine) bool {
+		return conditions.IsTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+	}).Newest()
+	if err := r.removeMachineFromWorkloadCluster(ctx, controlPlane, machineToBeRemediated, etcdLeaderCandidate); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Client.Delete(ctx, machineToBeRemediated); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to delete machine %s hosting an unhealthy etcd member", machineToBeRemediated.Name)
+	}
+
+	log.Info("Remediating machine hosting an unhealthy etcd member", "UnhealthyMachine", machineToBeRemediated.Name)
+	r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeNormal, "EtcdMemberRemediation",
+		"Deleted control plane Machine %s hosting an unhealthy etcd member: %s", machineToBeRemediated.Name, conditions.GetMessage(machineToBeRemediated, controlplanev1.MachineEtcdMemberHealthyCondition))
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// hasUnhealthyEtcdMember returns true if the etcd member hosted on the machine is reported as unhealthy
+// or not yet promoted from learner to voting member.
+func hasUnhealthyEtcdMember(machine *clusterv1.Machine) bool {
+	if machine == nil || machine.Status.NodeRef == nil || !machine.DeletionTimestamp.IsZero() {
+		return false
+	}
+	if !conditions.IsFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition) {
+		return false
+	}
+	reason := conditions.GetReason(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+	return reason == controlplanev1.EtcdMemberUnhealthyReason || reason == controlplanev1.EtcdMemberLearnerReason
+}
+
+// removeMachineFromWorkloadCluster removes the etcd member hosted on a machine, if KCP manages etcd, and the machine
+// from the kubeadm ConfigMap before the machine gets deleted; if the machine hosts the etcd leader, the leadership
+// is moved to the given candidate.
+func (r *KubeadmControlPlaneReconciler) removeMachineFromWorkloadCluster(ctx context.Context, controlPlane *internal.ControlPlane, machine, etcdLeaderCandidate *clusterv1.Machine) error {
+	log := ctrl.LoggerFrom(ctx)
+
 	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
 	if err != nil {
 		log.Error(err, "Failed to create client to workload cluster")
-		return ctrl.Result{}, errors.Wrapf(err, "failed to create client to workload cluster")
+		return errors.Wrapf(err, "failed to create client to workload cluster")
 	}
 
 	// If the machine that is about to be deleted is the etcd leader, move it to the newest member available.
 	if controlPlane.IsEtcdManaged() {
-		etcdLeaderCandidate := controlPlane.HealthyMachines().Newest()
-		if err := workloadCluster.ForwardEtcdLeadership(ctx, machineToBeRemediated, etcdLeaderCandidate); err != nil {
+		if err := workloadCluster.ForwardEtcdLeadership(ctx, machine, etcdLeaderCandidate); err != nil {
 			log.Error(err, "Failed to move leadership to candidate machine", "candidate", etcdLeaderCandidate.Name)
-			return ctrl.Result{}, err
+			return err
 		}
-		if err := workloadCluster.RemoveEtcdMemberForMachine(ctx, machineToBeRemediated); err != nil {
+		if err := workloadCluster.RemoveEtcdMemberForMachine(ctx, machine); err != nil {
 			log.Error(err, "Failed to remove etcd member for machine")
-			return ctrl.Result{}, err
+			return err
 		}
 	}
 
 	parsedVersion, err := semver.ParseTolerant(controlPlane.KCP.Spec.Version)
 	if err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
+		return errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
 	}
 
-	if err := workloadCluster.RemoveMachineFromKubeadmConfigMap(ctx, machineToBeRemediated, parsedVersion); err != nil {
+	if err := workloadCluster.RemoveMachineFromKubeadmConfigMap(ctx, machine, parsedVersion); err != nil {
 		log.Error(err, "Failed to remove machine from kubeadm ConfigMap")
-		return ctrl.Result{}, err
-	}
-
-	if err := r.Client.Delete(ctx, machineToBeRemediated); err != nil {
-		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
-		return ctrl.Result{}, errors.Wrapf(err, "failed to delete unhealthy machine %s", machineToBeRemediated.Name)
+		return err
 	}
-
-	log.Info("Remediating unhealthy machine", "UnhealthyMachine", machineToBeRemediated.Name)
-	conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationInProgressReason, clusterv1.ConditionSeverityWarning, "")
-	return ctrl.Result{Requeue: true}, nil
+	return nil
 }
 
 // canSafelyRemoveEtcdMember assess if it is possible to remove the member hosted on the machine to be remediated
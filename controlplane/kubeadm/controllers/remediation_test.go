@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
@@ -95,7 +97,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 						},
 					},
 				},
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m),
 		}
@@ -116,7 +118,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
 				Replicas:        utilpointer.Int32Ptr(3),
 				RolloutStrategy: &controlplanev1.RolloutStrategy{},
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m1, m2),
 		}
@@ -137,7 +139,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 		controlPlane := &internal.ControlPlane{
 			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
 				Replicas: utilpointer.Int32Ptr(3),
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m1, m2, m3),
 		}
@@ -159,7 +161,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 		controlPlane := &internal.ControlPlane{
 			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
 				Replicas: utilpointer.Int32Ptr(3),
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m1, m2, m3),
 		}
@@ -194,7 +196,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 		controlPlane := &internal.ControlPlane{
 			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
 				Replicas: utilpointer.Int32Ptr(5),
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m1, m2, m3, m4, m5),
 		}
@@ -232,7 +234,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
 				Replicas: utilpointer.Int32Ptr(2),
 				Version:  "v1.19.1",
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m1, m2),
 		}
@@ -281,7 +283,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
 				Replicas: utilpointer.Int32Ptr(3),
 				Version:  "v1.19.1",
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m1, m2, m3),
 		}
@@ -331,7 +333,7 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
 				Replicas: utilpointer.Int32Ptr(4),
 				Version:  "v1.19.1",
-			}},
+			}, Status: controlplanev1.KubeadmControlPlaneStatus{Initialized: true}},
 			Cluster:  &clusterv1.Cluster{},
 			Machines: collections.FromMachines(m1, m2, m3, m4),
 		}
@@ -364,6 +366,88 @@ func TestReconcileUnhealthyMachines(t *testing.T) {
 
 		g.Expect(env.Cleanup(ctx, m1, m2, m3, m4)).To(Succeed())
 	})
+	t.Run("Remediation deletes the first control plane machine if the control plane is not initialized", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withMachineHealthCheckFailed())
+		controlPlane := &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas: utilpointer.Int32Ptr(3),
+			}},
+			Cluster:  &clusterv1.Cluster{},
+			Machines: collections.FromMachines(m),
+		}
+		ret, err := r.reconcileUnhealthyMachines(context.TODO(), controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeFalse()) // Remediation completed, requeue
+		g.Expect(err).ToNot(HaveOccurred())
+
+		assertMachineDeleted(ctx, g, m)
+
+		remediation := &initialMachineRemediation{}
+		g.Expect(json.Unmarshal([]byte(controlPlane.KCP.Annotations[controlplanev1.InitialMachineRemediationAnnotation]), remediation)).To(Succeed())
+		g.Expect(remediation.Machine).To(Equal(m.Name))
+		g.Expect(remediation.RetryCount).To(Equal(int32(1)))
+	})
+	t.Run("Remediation of the first control plane machine waits for the retry period to expire", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withMachineHealthCheckFailed())
+		controlPlane := &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						controlplanev1.InitialMachineRemediationAnnotation: initialMachineRemediationAnnotationValue(g, 2, time.Now().Add(-15*time.Minute)),
+					},
+				},
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Replicas: utilpointer.Int32Ptr(3),
+					InitialMachineRemediation: &controlplanev1.InitialMachineRemediation{
+						RetryPeriod: &metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			},
+			Cluster:  &clusterv1.Cluster{},
+			Machines: collections.FromMachines(m),
+		}
+		ret, err := r.reconcileUnhealthyMachines(context.TODO(), controlPlane)
+
+		// The second retry waits twice the retry period.
+		g.Expect(ret.RequeueAfter).To(BeNumerically(">", 0))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.GetReason(m, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(clusterv1.WaitingForRemediationReason))
+
+		g.Expect(env.Cleanup(ctx, m)).To(Succeed())
+	})
+	t.Run("Remediation of the first control plane machine does not happen if the maximum number of retries is reached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withMachineHealthCheckFailed())
+		controlPlane := &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						controlplanev1.InitialMachineRemediationAnnotation: initialMachineRemediationAnnotationValue(g, 1, time.Now().Add(-1*time.Hour)),
+					},
+				},
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Replicas: utilpointer.Int32Ptr(3),
+					InitialMachineRemediation: &controlplanev1.InitialMachineRemediation{
+						MaxRetry: utilpointer.Int32Ptr(1),
+					},
+				},
+			},
+			Cluster:  &clusterv1.Cluster{},
+			Machines: collections.FromMachines(m),
+		}
+		ret, err := r.reconcileUnhealthyMachines(context.TODO(), controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeTrue()) // Remediation skipped
+		g.Expect(err).ToNot(HaveOccurred())
+		assertMachineCondition(ctx, g, m, clusterv1.MachineOwnerRemediatedCondition, corev1.ConditionFalse, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate this machine because the maximum number of retries (1) is reached")
+
+		g.Expect(env.Cleanup(ctx, m)).To(Succeed())
+	})
 }
 
 func TestCanSafelyRemoveEtcdMember(t *testing.T) {
@@ -740,6 +824,133 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 	})
 }
 
+func TestReconcileUnhealthyEtcdMembers(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+	ns, err := env.CreateNamespace(ctx, "ns1")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(env.Cleanup(ctx, ns)).To(Succeed())
+	}()
+
+	newControlPlane := func(remediation *controlplanev1.EtcdMemberRemediation, machines ...*clusterv1.Machine) *internal.ControlPlane {
+		return &internal.ControlPlane{
+			KCP: &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas:              utilpointer.Int32Ptr(int32(len(machines))),
+				Version:               "v1.19.1",
+				EtcdMemberRemediation: remediation,
+			}},
+			Cluster:  &clusterv1.Cluster{},
+			Machines: collections.FromMachines(machines...),
+		}
+	}
+	newReconciler := func(controlPlane *internal.ControlPlane) *KubeadmControlPlaneReconciler {
+		return &KubeadmControlPlaneReconciler{
+			Client:   env.GetClient(),
+			recorder: record.NewFakeRecorder(32),
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					EtcdMembersResult: nodes(controlPlane.Machines),
+				},
+			},
+		}
+	}
+
+	t.Run("Remediation does not happen if etcd member remediation is not enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withUnhealthyEtcdMember(), withEtcdMemberUnhealthySince(time.Now().Add(-time.Hour)))
+		m2 := createMachine(ctx, g, ns.Name, "m2-healthy-", withHealthyEtcdMember())
+		m3 := createMachine(ctx, g, ns.Name, "m3-healthy-", withHealthyEtcdMember())
+
+		controlPlane := newControlPlane(nil, m1, m2, m3)
+		ret, err := newReconciler(controlPlane).reconcileUnhealthyEtcdMembers(ctx, controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeTrue()) // Remediation skipped
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(env.Get(ctx, client.ObjectKey{Namespace: m1.Namespace, Name: m1.Name}, m1)).To(Succeed())
+		g.Expect(m1.ObjectMeta.DeletionTimestamp.IsZero()).To(BeTrue())
+
+		g.Expect(env.Cleanup(ctx, m1, m2, m3)).To(Succeed())
+	})
+	t.Run("Remediation does not happen if the etcd member is unhealthy for less than the timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withUnhealthyEtcdMember(), withEtcdMemberUnhealthySince(time.Now().Add(-time.Minute)))
+		m2 := createMachine(ctx, g, ns.Name, "m2-healthy-", withHealthyEtcdMember())
+		m3 := createMachine(ctx, g, ns.Name, "m3-healthy-", withHealthyEtcdMember())
+
+		controlPlane := newControlPlane(&controlplanev1.EtcdMemberRemediation{}, m1, m2, m3)
+		ret, err := newReconciler(controlPlane).reconcileUnhealthyEtcdMembers(ctx, controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeTrue()) // Remediation skipped
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(env.Get(ctx, client.ObjectKey{Namespace: m1.Namespace, Name: m1.Name}, m1)).To(Succeed())
+		g.Expect(m1.ObjectMeta.DeletionTimestamp.IsZero()).To(BeTrue())
+
+		g.Expect(env.Cleanup(ctx, m1, m2, m3)).To(Succeed())
+	})
+	t.Run("Remediation does not happen if there are more unhealthy etcd members than allowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withUnhealthyEtcdMember(), withEtcdMemberUnhealthySince(time.Now().Add(-time.Hour)))
+		m2 := createMachine(ctx, g, ns.Name, "m2-unhealthy-", withUnhealthyEtcdMember(), withEtcdMemberUnhealthySince(time.Now().Add(-time.Hour)))
+		m3 := createMachine(ctx, g, ns.Name, "m3-healthy-", withHealthyEtcdMember())
+		m4 := createMachine(ctx, g, ns.Name, "m4-healthy-", withHealthyEtcdMember())
+		m5 := createMachine(ctx, g, ns.Name, "m5-healthy-", withHealthyEtcdMember())
+
+		controlPlane := newControlPlane(&controlplanev1.EtcdMemberRemediation{}, m1, m2, m3, m4, m5)
+		ret, err := newReconciler(controlPlane).reconcileUnhealthyEtcdMembers(ctx, controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeTrue()) // Remediation skipped
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(env.Get(ctx, client.ObjectKey{Namespace: m1.Namespace, Name: m1.Name}, m1)).To(Succeed())
+		g.Expect(m1.ObjectMeta.DeletionTimestamp.IsZero()).To(BeTrue())
+
+		g.Expect(env.Cleanup(ctx, m1, m2, m3, m4, m5)).To(Succeed())
+	})
+	t.Run("Remediation deletes the machine hosting an unhealthy etcd member - 5 CP, 2 unhealthy members allowed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := createMachine(ctx, g, ns.Name, "m1-unhealthy-", withUnhealthyEtcdMember(), withEtcdMemberUnhealthySince(time.Now().Add(-time.Hour)))
+		patchHelper, err := patch.NewHelper(m1, env.GetClient())
+		g.Expect(err).ToNot(HaveOccurred())
+		m1.ObjectMeta.Finalizers = []string{"wait-before-delete"}
+		g.Expect(patchHelper.Patch(ctx, m1))
+
+		m2 := createMachine(ctx, g, ns.Name, "m2-unhealthy-", withUnhealthyEtcdMember(), withEtcdMemberUnhealthySince(time.Now().Add(-time.Minute)))
+		m3 := createMachine(ctx, g, ns.Name, "m3-healthy-", withHealthyEtcdMember())
+		m4 := createMachine(ctx, g, ns.Name, "m4-healthy-", withHealthyEtcdMember())
+		m5 := createMachine(ctx, g, ns.Name, "m5-healthy-", withHealthyEtcdMember())
+
+		maxUnhealthy := intstr.FromString("40%")
+		controlPlane := newControlPlane(&controlplanev1.EtcdMemberRemediation{
+			UnhealthyTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			MaxUnhealthy:     &maxUnhealthy,
+		}, m1, m2, m3, m4, m5)
+		ret, err := newReconciler(controlPlane).reconcileUnhealthyEtcdMembers(ctx, controlPlane)
+
+		g.Expect(ret.IsZero()).To(BeFalse()) // Remediation completed, requeue
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(env.Get(ctx, client.ObjectKey{Namespace: m1.Namespace, Name: m1.Name}, m1)).To(Succeed())
+		g.Expect(m1.ObjectMeta.DeletionTimestamp.IsZero()).To(BeFalse())
+
+		g.Expect(env.Get(ctx, client.ObjectKey{Namespace: m2.Namespace, Name: m2.Name}, m2)).To(Succeed())
+		g.Expect(m2.ObjectMeta.DeletionTimestamp.IsZero()).To(BeTrue())
+
+		patchHelper, err = patch.NewHelper(m1, env.GetClient())
+		g.Expect(err).ToNot(HaveOccurred())
+		m1.ObjectMeta.Finalizers = nil
+		g.Expect(patchHelper.Patch(ctx, m1))
+
+		g.Expect(env.Cleanup(ctx, m1, m2, m3, m4, m5)).To(Succeed())
+	})
+}
+
 func nodes(machines collections.Machines) []string {
 	nodes := make([]string, 0, machines.Len())
 	for _, m := range machines {
@@ -771,6 +982,16 @@ func withUnhealthyEtcdMember() machineOption {
 	}
 }
 
+func withEtcdMemberUnhealthySince(t time.Time) machineOption {
+	return func(machine *clusterv1.Machine) {
+		for i := range machine.Status.Conditions {
+			if machine.Status.Conditions[i].Type == controlplanev1.MachineEtcdMemberHealthyCondition {
+				machine.Status.Conditions[i].LastTransitionTime = metav1.NewTime(t)
+			}
+		}
+	}
+}
+
 func withNodeRef(ref string) machineOption {
 	return func(machine *clusterv1.Machine) {
 		machine.Status.NodeRef = &corev1.ObjectReference{
@@ -828,6 +1049,25 @@ func getDeletingMachine(namespace, name string, options ...machineOption) *clust
 	return m
 }
 
+func assertMachineDeleted(ctx context.Context, g *WithT, m *clusterv1.Machine) {
+	err := env.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: m.Name}, m)
+	if apierrors.IsNotFound(err) {
+		return
+	}
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m.ObjectMeta.DeletionTimestamp.IsZero()).To(BeFalse())
+}
+
+func initialMachineRemediationAnnotationValue(g *WithT, retryCount int32, lastRemediation time.Time) string {
+	value, err := json.Marshal(initialMachineRemediation{
+		Machine:         "previous-machine",
+		RetryCount:      retryCount,
+		LastRemediation: metav1.NewTime(lastRemediation),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	return string(value)
+}
+
 func assertMachineCondition(ctx context.Context, g *WithT, m *clusterv1.Machine, t clusterv1.ConditionType, status corev1.ConditionStatus, reason string, severity clusterv1.ConditionSeverity, message string) {
 	g.Eventually(func() error {
 		if err := env.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: m.Name}, m); err != nil {
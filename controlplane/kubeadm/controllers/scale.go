@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -52,8 +53,11 @@ func (r *KubeadmControlPlaneReconciler) initializeControlPlane(ctx context.Conte
 		)
 	}
 
-	bootstrapSpec := controlPlane.InitialControlPlaneConfig()
 	fd := controlPlane.NextFailureDomainForScaleUp()
+	bootstrapSpec, err := controlPlane.InitialControlPlaneConfig(fd)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to generate the bootstrap configuration for the initial control plane Machine")
+	}
 	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, fd); err != nil {
 		logger.Error(err, "Failed to create initial control plane Machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedInitialization", "Failed to create initial control plane Machine for cluster %s/%s control plane: %v", cluster.Namespace, cluster.Name, err)
@@ -73,8 +77,11 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 	}
 
 	// Create the bootstrap configuration
-	bootstrapSpec := controlPlane.JoinControlPlaneConfig()
 	fd := controlPlane.NextFailureDomainForScaleUp()
+	bootstrapSpec, err := controlPlane.JoinControlPlaneConfig(fd)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to generate the bootstrap configuration for an additional control plane Machine")
+	}
 	if err := r.cloneConfigsAndGenerateMachine(ctx, cluster, kcp, bootstrapSpec, fd); err != nil {
 		logger.Error(err, "Failed to create additional control plane Machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedScaleUp", "Failed to create additional control plane Machine for cluster %s/%s control plane: %v", cluster.Namespace, cluster.Name, err)
@@ -119,6 +126,23 @@ func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 
 	// If KCP should manage etcd, If etcd leadership is on machine that is about to be deleted, move it to the newest member available.
 	if controlPlane.IsEtcdManaged() {
+		// Never remove an etcd member if this could lead to etcd losing quorum, e.g. when other members are unhealthy;
+		// this matters in particular when scaling in before scaling out during rollouts (MaxSurge=0).
+		// NOTE: Machines not yet hosting an etcd member can always be deleted, because this does not affect quorum.
+		if machineToDelete.Status.NodeRef != nil {
+			canSafelyRemove, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, machineToDelete)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !canSafelyRemove {
+				logger.Info("Waiting for etcd to become healthy before removing the etcd member of the control plane machine; removing it now could lead to etcd losing quorum",
+					logutil.MachineKey, logutil.KObj(machineToDelete))
+				r.recorder.Eventf(kcp, corev1.EventTypeWarning, "ScaleDownBlocked",
+					"Waiting for etcd to become healthy before deleting control plane Machine %s for cluster %s/%s control plane", machineToDelete.Name, cluster.Namespace, cluster.Name)
+				return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+			}
+		}
+
 		etcdLeaderCandidate := controlPlane.Machines.Newest()
 		if err := workloadCluster.ForwardEtcdLeadership(ctx, machineToDelete, etcdLeaderCandidate); err != nil {
 			logger.Error(err, "Failed to move leadership to candidate machine", "candidate", etcdLeaderCandidate.Name)
@@ -140,7 +164,7 @@ func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 		return ctrl.Result{}, err
 	}
 
-	logger = logger.WithValues("machine", machineToDelete)
+	logger = logger.WithValues(logutil.MachineKey, logutil.KObj(machineToDelete))
 	if err := r.Client.Delete(ctx, machineToDelete); err != nil && !apierrors.IsNotFound(err) {
 		logger.Error(err, "Failed to delete control plane machine")
 		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedScaleDown",
@@ -211,9 +235,10 @@ loopmachines:
 			"Waiting for control plane to pass preflight checks to continue reconciliation: %v", aggregatedError)
 		logger.Info("Waiting for control plane to pass preflight checks", "failures", aggregatedError.Error())
 
-		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+		return ctrl.Result{RequeueAfter: r.preflightBackoff.After(controlPlane.KCP, preflightFailedRequeueAfter, maxPreflightFailedRequeueAfter)}, nil
 	}
 
+	r.preflightBackoff.Forget(controlPlane.KCP)
 	return ctrl.Result{}, nil
 }
 
@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api/util/conditions"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -287,6 +288,52 @@ func TestKubeadmControlPlaneReconciler_scaleDownControlPlane_NoError(t *testing.
 		g.Expect(fakeClient.List(context.Background(), &controlPlaneMachines)).To(Succeed())
 		g.Expect(controlPlaneMachines.Items).To(HaveLen(3))
 	})
+
+	t.Run("does not scale down if removing the etcd member of the machine being deleted could lead to etcd losing quorum", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machines := map[string]*clusterv1.Machine{
+			"one": machine("one", withTimestamp(time.Now().Add(-1*time.Minute))),
+			"two": machine("two", withTimestamp(time.Now())),
+		}
+		for name, m := range machines {
+			m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: name}
+			setMachineHealthy(m)
+		}
+		fakeClient := newFakeClient(machines["one"], machines["two"])
+
+		r := &KubeadmControlPlaneReconciler{
+			recorder: record.NewFakeRecorder(32),
+			Client:   fakeClient,
+			managementCluster: &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					// The etcd member "three" does not have a corresponding machine, so it is considered unhealthy.
+					EtcdMembersResult: []string{"one", "two", "three"},
+				},
+			},
+		}
+
+		cluster := &clusterv1.Cluster{}
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Version: "v1.19.1",
+			},
+		}
+		setKCPHealthy(kcp)
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  cluster,
+			Machines: machines,
+		}
+
+		result, err := r.scaleDownControlPlane(context.Background(), cluster, kcp, controlPlane, controlPlane.Machines)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}))
+
+		controlPlaneMachines := clusterv1.MachineList{}
+		g.Expect(fakeClient.List(context.Background(), &controlPlaneMachines)).To(Succeed())
+		g.Expect(controlPlaneMachines.Items).To(HaveLen(2))
+	})
 }
 
 func TestSelectMachineForScaleDown(t *testing.T) {
@@ -491,6 +538,51 @@ func TestPreflightChecks(t *testing.T) {
 	}
 }
 
+func TestPreflightChecksBackoff(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &KubeadmControlPlaneReconciler{
+		recorder: record.NewFakeRecorder(32),
+	}
+	unhealthyMachine := &clusterv1.Machine{
+		Status: clusterv1.MachineStatus{
+			Conditions: clusterv1.Conditions{
+				*conditions.FalseCondition(controlplanev1.MachineAPIServerPodHealthyCondition, "fooReason", clusterv1.ConditionSeverityError, ""),
+			},
+		},
+	}
+	controlPlane := &internal.ControlPlane{
+		Cluster:  &clusterv1.Cluster{},
+		KCP:      &controlplanev1.KubeadmControlPlane{},
+		Machines: collections.FromMachines(unhealthyMachine),
+	}
+
+	// The first failure requeues after the default interval, consecutive failures back off.
+	result, err := r.preflightChecks(context.TODO(), controlPlane)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}))
+
+	result, err = r.preflightChecks(context.TODO(), controlPlane)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeNumerically(">", preflightFailedRequeueAfter))
+	g.Expect(result.RequeueAfter).To(BeNumerically("<=", maxPreflightFailedRequeueAfter))
+
+	// Passing preflight checks resets the backoff.
+	unhealthyMachine.Status.Conditions = clusterv1.Conditions{
+		*conditions.TrueCondition(controlplanev1.MachineAPIServerPodHealthyCondition),
+		*conditions.TrueCondition(controlplanev1.MachineControllerManagerPodHealthyCondition),
+		*conditions.TrueCondition(controlplanev1.MachineSchedulerPodHealthyCondition),
+		*conditions.TrueCondition(controlplanev1.MachineEtcdPodHealthyCondition),
+		*conditions.TrueCondition(controlplanev1.MachineEtcdMemberHealthyCondition),
+	}
+	conditions.MarkTrue(controlPlane.KCP, controlplanev1.ControlPlaneComponentsHealthyCondition)
+	conditions.MarkTrue(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition)
+	result, err = r.preflightChecks(context.TODO(), controlPlane)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(ctrl.Result{}))
+	g.Expect(r.preflightBackoff.Requeues(controlPlane.KCP)).To(Equal(0))
+}
+
 func TestPreflightCheckCondition(t *testing.T) {
 	condition := clusterv1.ConditionType("fooCondition")
 	testCases := []struct {
@@ -18,7 +18,10 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -34,13 +37,18 @@ import (
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/failuredomains"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Log is the global logger for the internal package.
 var Log = klogr.New()
 
+// KubeadmControlPlaneKey is the key used for logging a KubeadmControlPlane.
+const KubeadmControlPlaneKey = "KubeadmControlPlane"
+
 // ControlPlane holds business logic around control planes.
 // It should never need to connect to a service, that responsibility lies outside of this struct.
 // Going forward we should be trying to add more logic to here and reduce the amount of logic in the reconciler.
@@ -53,6 +61,9 @@ type ControlPlane struct {
 	// reconciliationTime is the time of the current reconciliation, and should be used for all "now" calculations
 	reconciliationTime metav1.Time
 
+	// etcdClientCertificateRenewedAt is the time the apiserver-etcd-client certificate was renewed by KCP, if any.
+	etcdClientCertificateRenewedAt *metav1.Time
+
 	// TODO: we should see if we can combine these with the Machine objects so we don't have all these separate lookups
 	// See discussion on https://github.com/kubernetes-sigs/cluster-api/pull/3405
 	kubeadmConfigs map[string]*bootstrapv1.KubeadmConfig
@@ -69,6 +80,10 @@ func NewControlPlane(ctx context.Context, client client.Client, cluster *cluster
 	if err != nil {
 		return nil, err
 	}
+	etcdClientCertificateRenewedAt, err := getEtcdClientCertificateRenewedAt(ctx, client, cluster, kcp)
+	if err != nil {
+		return nil, err
+	}
 	patchHelpers := map[string]*patch.Helper{}
 	for _, machine := range ownedMachines {
 		patchHelper, err := patch.NewHelper(machine, client)
@@ -86,12 +101,14 @@ func NewControlPlane(ctx context.Context, client client.Client, cluster *cluster
 		kubeadmConfigs:       kubeadmConfigs,
 		infraResources:       infraObjects,
 		reconciliationTime:   metav1.Now(),
+
+		etcdClientCertificateRenewedAt: etcdClientCertificateRenewedAt,
 	}, nil
 }
 
 // Logger returns a logger with useful context.
 func (c *ControlPlane) Logger() logr.Logger {
-	return Log.WithValues("namespace", c.KCP.Namespace, "name", c.KCP.Name, "cluster-name", c.Cluster.Name)
+	return Log.WithValues(KubeadmControlPlaneKey, logutil.KObj(c.KCP), logutil.ClusterKey, logutil.KObj(c.Cluster))
 }
 
 // FailureDomains returns a slice of failure domain objects synced from the infrastructure provider into Cluster.Status.
@@ -174,21 +191,63 @@ func (c *ControlPlane) NextFailureDomainForScaleUp() *string {
 	return failuredomains.PickFewest(c.FailureDomains().FilterControlPlane(), c.UpToDateMachines())
 }
 
-// InitialControlPlaneConfig returns a new KubeadmConfigSpec that is to be used for an initializing control plane.
-func (c *ControlPlane) InitialControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
-	bootstrapSpec := c.KCP.Spec.KubeadmConfigSpec.DeepCopy()
+// InitialControlPlaneConfig returns a new KubeadmConfigSpec that is to be used for an initializing control plane
+// in the given failure domain.
+func (c *ControlPlane) InitialControlPlaneConfig(failureDomain *string) (*bootstrapv1.KubeadmConfigSpec, error) {
+	bootstrapSpec, err := KubeadmConfigSpecForFailureDomain(c.KCP, failureDomain)
+	if err != nil {
+		return nil, err
+	}
 	bootstrapSpec.JoinConfiguration = nil
-	return bootstrapSpec
+	return bootstrapSpec, nil
 }
 
-// JoinControlPlaneConfig returns a new KubeadmConfigSpec that is to be used for joining control planes.
-func (c *ControlPlane) JoinControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
-	bootstrapSpec := c.KCP.Spec.KubeadmConfigSpec.DeepCopy()
+// JoinControlPlaneConfig returns a new KubeadmConfigSpec that is to be used for joining control planes
+// in the given failure domain.
+func (c *ControlPlane) JoinControlPlaneConfig(failureDomain *string) (*bootstrapv1.KubeadmConfigSpec, error) {
+	bootstrapSpec, err := KubeadmConfigSpecForFailureDomain(c.KCP, failureDomain)
+	if err != nil {
+		return nil, err
+	}
 	bootstrapSpec.InitConfiguration = nil
 	// NOTE: For the joining we are preserving the ClusterConfiguration in order to determine if the
 	// cluster is using an external etcd in the kubeadm bootstrap provider (even if this is not required by kubeadm Join).
 	// TODO: Determine if this copy of cluster configuration can be used for rollouts (thus allowing to remove the annotation at machine level)
-	return bootstrapSpec
+	return bootstrapSpec, nil
+}
+
+// KubeadmConfigSpecForFailureDomain returns a copy of the KCP KubeadmConfigSpec, with the KubeadmConfigOverrides
+// matching the given failure domain applied in order.
+func KubeadmConfigSpecForFailureDomain(kcp *controlplanev1.KubeadmControlPlane, failureDomain *string) (*bootstrapv1.KubeadmConfigSpec, error) {
+	bootstrapSpec := kcp.Spec.KubeadmConfigSpec.DeepCopy()
+	if len(kcp.Spec.KubeadmConfigOverrides) == 0 {
+		return bootstrapSpec, nil
+	}
+
+	specJSON, err := json.Marshal(bootstrapSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal KubeadmConfigSpec")
+	}
+	patched := false
+	for i, override := range kcp.Spec.KubeadmConfigOverrides {
+		if !override.Matches(failureDomain) {
+			continue
+		}
+		specJSON, err = jsonpatch.MergePatch(specJSON, override.MergePatch.Raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to apply KubeadmConfigOverride %d", i)
+		}
+		patched = true
+	}
+	if !patched {
+		return bootstrapSpec, nil
+	}
+
+	patchedSpec := &bootstrapv1.KubeadmConfigSpec{}
+	if err := json.Unmarshal(specJSON, patchedSpec); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal patched KubeadmConfigSpec")
+	}
+	return patchedSpec, nil
 }
 
 // GenerateKubeadmConfig generates a new kubeadm config for creating new control plane nodes.
@@ -262,6 +321,8 @@ func (c *ControlPlane) MachinesNeedingRollout() collections.Machines {
 	return machines.AnyFilter(
 		// Machines that are scheduled for rollout (KCP.Spec.RolloutAfter set, the RolloutAfter deadline is expired, and the machine was created before the deadline).
 		collections.ShouldRolloutAfter(&c.reconciliationTime, c.KCP.Spec.RolloutAfter),
+		// Machines that were created before the apiserver-etcd-client certificate was renewed, and thus use the old certificate.
+		collections.ShouldRolloutAfter(&c.reconciliationTime, c.etcdClientCertificateRenewedAt),
 		// Machines that do not match with KCP config.
 		collections.Not(MatchesMachineSpec(c.infraResources, c.kubeadmConfigs, c.KCP)),
 	)
@@ -273,6 +334,18 @@ func (c *ControlPlane) UpToDateMachines() collections.Machines {
 	return c.Machines.Difference(c.MachinesNeedingRollout())
 }
 
+// InfraMachine returns the infrastructure machine for the given machine, if it was found.
+func (c *ControlPlane) InfraMachine(machineName string) (*unstructured.Unstructured, bool) {
+	infraMachine, ok := c.infraResources[machineName]
+	return infraMachine, ok
+}
+
+// KubeadmConfig returns the KubeadmConfig for the given machine, if it was found.
+func (c *ControlPlane) KubeadmConfig(machineName string) (*bootstrapv1.KubeadmConfig, bool) {
+	kubeadmConfig, ok := c.kubeadmConfigs[machineName]
+	return kubeadmConfig, ok
+}
+
 // getInfraResources fetches the external infrastructure resource for each machine in the collection and returns a map of machine.Name -> infraResource.
 func getInfraResources(ctx context.Context, cl client.Client, machines collections.Machines) (map[string]*unstructured.Unstructured, error) {
 	result := map[string]*unstructured.Unstructured{}
@@ -289,6 +362,32 @@ func getInfraResources(ctx context.Context, cl client.Client, machines collectio
 	return result, nil
 }
 
+// getEtcdClientCertificateRenewedAt returns the time the apiserver-etcd-client certificate was renewed by KCP,
+// if rotation of the certificate is enabled.
+func getEtcdClientCertificateRenewedAt(ctx context.Context, cl client.Client, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane) (*metav1.Time, error) {
+	if kcp.Spec.ExternalEtcdClientCertificateRotation == nil {
+		return nil, nil
+	}
+
+	clientSecret, err := secret.GetFromNamespacedName(ctx, cl, client.ObjectKeyFromObject(cluster), secret.APIServerEtcdClient)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to retrieve the apiserver-etcd-client Secret")
+	}
+
+	value, ok := clientSecret.GetAnnotations()[controlplanev1.EtcdClientCertificateRenewedAtAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	renewedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the %s annotation", controlplanev1.EtcdClientCertificateRenewedAtAnnotation)
+	}
+	return &metav1.Time{Time: renewedAt}, nil
+}
+
 // getKubeadmConfigs fetches the kubeadm config for each machine in the collection and returns a map of machine.Name -> KubeadmConfig.
 func getKubeadmConfigs(ctx context.Context, cl client.Client, machines collections.Machines) (map[string]*bootstrapv1.KubeadmConfig, error) {
 	result := map[string]*bootstrapv1.KubeadmConfig{}
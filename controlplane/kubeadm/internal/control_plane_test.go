@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
@@ -122,6 +123,60 @@ func TestHasUnhealthyMachine(t *testing.T) {
 	g.Expect(c.HasUnhealthyMachine()).To(BeTrue())
 }
 
+func TestKubeadmConfigSpecForFailureDomain(t *testing.T) {
+	kcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				JoinConfiguration: &bootstrapv1.JoinConfiguration{
+					NodeRegistration: bootstrapv1.NodeRegistrationOptions{
+						KubeletExtraArgs: map[string]string{"node-labels": "role=control-plane"},
+					},
+				},
+				PreKubeadmCommands: []string{"echo all"},
+			},
+			KubeadmConfigOverrides: []controlplanev1.KubeadmConfigOverride{
+				{
+					MergePatch: apiextensionsv1.JSON{Raw: []byte(`{"preKubeadmCommands":["echo overridden"]}`)},
+				},
+				{
+					FailureDomains: []string{"fd1", "fd2"},
+					MergePatch:     apiextensionsv1.JSON{Raw: []byte(`{"joinConfiguration":{"nodeRegistration":{"kubeletExtraArgs":{"node-labels":"zone=fd1"}}}}`)},
+				},
+			},
+		},
+	}
+
+	t.Run("applies the overrides matching the failure domain in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		spec, err := KubeadmConfigSpecForFailureDomain(kcp, pointer.StringPtr("fd1"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(spec.PreKubeadmCommands).To(Equal([]string{"echo overridden"}))
+		g.Expect(spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{"node-labels": "zone=fd1"}))
+	})
+
+	t.Run("applies only the overrides for all the machines if the failure domain does not match", func(t *testing.T) {
+		g := NewWithT(t)
+
+		spec, err := KubeadmConfigSpecForFailureDomain(kcp, pointer.StringPtr("fd3"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(spec.PreKubeadmCommands).To(Equal([]string{"echo overridden"}))
+		g.Expect(spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{"node-labels": "role=control-plane"}))
+
+		spec, err = KubeadmConfigSpecForFailureDomain(kcp, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).To(Equal(map[string]string{"node-labels": "role=control-plane"}))
+	})
+
+	t.Run("does not modify the KCP", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := KubeadmConfigSpecForFailureDomain(kcp, pointer.StringPtr("fd1"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(kcp.Spec.KubeadmConfigSpec.PreKubeadmCommands).To(Equal([]string{"echo all"}))
+	})
+}
+
 type machineOpt func(*clusterv1.Machine)
 
 func failureDomain(controlPlane bool) clusterv1.FailureDomainSpec {
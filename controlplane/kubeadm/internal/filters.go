@@ -25,16 +25,13 @@ import (
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // MatchesMachineSpec returns a filter to find all machines that matches with KCP config and do not require any rollout.
 // Kubernetes version, infrastructure template, and KubeadmConfig field need to be equivalent.
+// NOTE: The machine template metadata is not compared, because it is propagated to existing machines without a rollout.
 func MatchesMachineSpec(infraConfigs map[string]*unstructured.Unstructured, machineConfigs map[string]*bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane) func(machine *clusterv1.Machine) bool {
 	return collections.And(
-		func(machine *clusterv1.Machine) bool {
-			return matchMachineTemplateMetadata(kcp, machine)
-		},
 		collections.MatchesKubernetesVersion(kcp.Spec.Version),
 		MatchesKubeadmBootstrapConfig(machineConfigs, kcp),
 		MatchesTemplateClonedFrom(infraConfigs, kcp),
@@ -67,11 +64,6 @@ func MatchesTemplateClonedFrom(infraConfigs map[string]*unstructured.Unstructure
 			clonedFromGroupKind != kcp.Spec.MachineTemplate.InfrastructureRef.GroupVersionKind().GroupKind().String() {
 			return false
 		}
-
-		// Check if the machine template metadata matches with the infrastructure object.
-		if !matchMachineTemplateMetadata(kcp, infraObj) {
-			return false
-		}
 		return true
 	}
 }
@@ -102,15 +94,24 @@ func MatchesKubeadmBootstrapConfig(machineConfigs map[string]*bootstrapv1.Kubead
 			return true
 		}
 
-		// Check if the machine template metadata matches with the infrastructure object.
-		if !matchMachineTemplateMetadata(kcp, machineConfig) {
-			return false
+		// Apply the KubeadmConfigOverrides for the failure domain of the machine, so the KubeadmConfigSpec
+		// is compared with the one used when creating the machine.
+		machineKCP := kcp
+		if len(kcp.Spec.KubeadmConfigOverrides) > 0 {
+			kubeadmConfigSpec, err := KubeadmConfigSpecForFailureDomain(kcp, machine.Spec.FailureDomain)
+			if err != nil {
+				// Return true here because failing to apply the overrides should not be considered as unmatching.
+				// This is a safety precaution to avoid rolling out machines if the overrides are not valid.
+				return true
+			}
+			machineKCP = kcp.DeepCopy()
+			machineKCP.Spec.KubeadmConfigSpec = *kubeadmConfigSpec
 		}
 
 		// Check if KCP and machine InitConfiguration or JoinConfiguration matches
 		// NOTE: only one between init configuration and join configuration is set on a machine, depending
 		// on the fact that the machine was the initial control plane node or a joining control plane node.
-		return matchInitOrJoinConfiguration(machineConfig, kcp)
+		return matchInitOrJoinConfiguration(machineConfig, machineKCP)
 	}
 }
 
@@ -234,30 +235,3 @@ func cleanupConfigFields(kcpConfig *bootstrapv1.KubeadmConfigSpec, machineConfig
 		machineConfig.Spec.JoinConfiguration.TypeMeta = kcpConfig.JoinConfiguration.TypeMeta
 	}
 }
-
-// matchMachineTemplateMetadata matches the machine template object meta information,
-// specifically annotations and labels, against an object.
-func matchMachineTemplateMetadata(kcp *controlplanev1.KubeadmControlPlane, obj client.Object) bool {
-	// Check if annotations and labels match.
-	if !isSubsetMapOf(kcp.Spec.MachineTemplate.ObjectMeta.Annotations, obj.GetAnnotations()) {
-		return false
-	}
-	if !isSubsetMapOf(kcp.Spec.MachineTemplate.ObjectMeta.Labels, obj.GetLabels()) {
-		return false
-	}
-	return true
-}
-
-func isSubsetMapOf(base map[string]string, existing map[string]string) bool {
-loopBase:
-	for key, value := range base {
-		for existingKey, existingValue := range existing {
-			if existingKey == key && existingValue == value {
-				continue loopBase
-			}
-		}
-		// Return false right away if a key value pair wasn't found.
-		return false
-	}
-	return true
-}
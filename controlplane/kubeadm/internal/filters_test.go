@@ -21,6 +21,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -428,6 +429,61 @@ func TestMatchInitOrJoinConfiguration(t *testing.T) {
 		}
 		g.Expect(matchInitOrJoinConfiguration(machineConfigs[m.Name], kcp)).To(BeTrue())
 	})
+	t.Run("returns true if JoinConfiguration is equal after applying the overrides for the machine failure domain", func(t *testing.T) {
+		g := NewWithT(t)
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					ClusterConfiguration: &bootstrapv1.ClusterConfiguration{},
+					InitConfiguration:    &bootstrapv1.InitConfiguration{},
+					JoinConfiguration:    &bootstrapv1.JoinConfiguration{},
+				},
+				KubeadmConfigOverrides: []controlplanev1.KubeadmConfigOverride{
+					{
+						FailureDomains: []string{"fd1"},
+						MergePatch:     apiextensionsv1.JSON{Raw: []byte(`{"joinConfiguration":{"nodeRegistration":{"kubeletExtraArgs":{"node-labels":"zone=fd1"}}}}`)},
+					},
+				},
+			},
+		}
+		newMachine := func(failureDomain string) *clusterv1.Machine {
+			return &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test",
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						ConfigRef: &corev1.ObjectReference{
+							Kind:       "KubeadmConfig",
+							Namespace:  "default",
+							Name:       "test",
+							APIVersion: bootstrapv1.GroupVersion.String(),
+						},
+					},
+					FailureDomain: &failureDomain,
+				},
+			}
+		}
+		machineConfigs := map[string]*bootstrapv1.KubeadmConfig{
+			"test": {
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test",
+				},
+				Spec: bootstrapv1.KubeadmConfigSpec{
+					JoinConfiguration: &bootstrapv1.JoinConfiguration{
+						NodeRegistration: bootstrapv1.NodeRegistrationOptions{
+							KubeletExtraArgs: map[string]string{"node-labels": "zone=fd1"},
+						},
+					},
+				},
+			},
+		}
+		f := MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
+		g.Expect(f(newMachine("fd1"))).To(BeTrue())
+		g.Expect(f(newMachine("fd2"))).To(BeFalse())
+	})
 	t.Run("returns false if JoinConfiguration is NOT equal", func(t *testing.T) {
 		g := NewWithT(t)
 		kcp := &controlplanev1.KubeadmControlPlane{
@@ -834,7 +890,7 @@ func TestMatchesKubeadmBootstrapConfig(t *testing.T) {
 		f := MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
 		g.Expect(f(m)).To(BeFalse())
 	})
-	t.Run("should match on labels and annotations", func(t *testing.T) {
+	t.Run("should ignore labels and annotations, which are propagated without a rollout", func(t *testing.T) {
 		kcp := &controlplanev1.KubeadmControlPlane{
 			Spec: controlplanev1.KubeadmControlPlaneSpec{
 				MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
@@ -890,28 +946,28 @@ func TestMatchesKubeadmBootstrapConfig(t *testing.T) {
 			},
 		}
 
-		t.Run("by returning false if neither labels or annotations match", func(t *testing.T) {
+		t.Run("by returning true if neither labels or annotations match", func(t *testing.T) {
 			g := NewWithT(t)
 			machineConfigs[m.Name].Annotations = nil
 			machineConfigs[m.Name].Labels = nil
 			f := MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
-			g.Expect(f(m)).To(BeFalse())
+			g.Expect(f(m)).To(BeTrue())
 		})
 
-		t.Run("by returning false if only labels don't match", func(t *testing.T) {
+		t.Run("by returning true if only labels don't match", func(t *testing.T) {
 			g := NewWithT(t)
 			machineConfigs[m.Name].Annotations = kcp.Spec.MachineTemplate.ObjectMeta.Annotations
 			machineConfigs[m.Name].Labels = nil
 			f := MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
-			g.Expect(f(m)).To(BeFalse())
+			g.Expect(f(m)).To(BeTrue())
 		})
 
-		t.Run("by returning false if only annotations don't match", func(t *testing.T) {
+		t.Run("by returning true if only annotations don't match", func(t *testing.T) {
 			g := NewWithT(t)
 			machineConfigs[m.Name].Annotations = nil
 			machineConfigs[m.Name].Labels = kcp.Spec.MachineTemplate.ObjectMeta.Labels
 			f := MatchesKubeadmBootstrapConfig(machineConfigs, kcp)
-			g.Expect(f(m)).To(BeFalse())
+			g.Expect(f(m)).To(BeTrue())
 		})
 
 		t.Run("by returning true if both labels and annotations match", func(t *testing.T) {
@@ -950,7 +1006,7 @@ func TestMatchesTemplateClonedFrom(t *testing.T) {
 		).To(BeTrue())
 	})
 
-	t.Run("matches labels or annotations", func(t *testing.T) {
+	t.Run("ignores labels and annotations, which are propagated without a rollout", func(t *testing.T) {
 		kcp := &controlplanev1.KubeadmControlPlane{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "default",
@@ -998,7 +1054,7 @@ func TestMatchesTemplateClonedFrom(t *testing.T) {
 			},
 		}
 
-		t.Run("by returning false if neither labels or annotations match", func(t *testing.T) {
+		t.Run("by returning true if neither labels or annotations match", func(t *testing.T) {
 			g := NewWithT(t)
 			infraConfigs[m.Name].SetAnnotations(map[string]string{
 				clusterv1.TemplateClonedFromNameAnnotation:      "infra-foo",
@@ -1006,10 +1062,10 @@ func TestMatchesTemplateClonedFrom(t *testing.T) {
 			})
 			infraConfigs[m.Name].SetLabels(nil)
 			f := MatchesTemplateClonedFrom(infraConfigs, kcp)
-			g.Expect(f(m)).To(BeFalse())
+			g.Expect(f(m)).To(BeTrue())
 		})
 
-		t.Run("by returning false if only labels don't match", func(t *testing.T) {
+		t.Run("by returning true if only labels don't match", func(t *testing.T) {
 			g := NewWithT(t)
 			infraConfigs[m.Name].SetAnnotations(map[string]string{
 				clusterv1.TemplateClonedFromNameAnnotation:      "infra-foo",
@@ -1018,10 +1074,10 @@ func TestMatchesTemplateClonedFrom(t *testing.T) {
 			})
 			infraConfigs[m.Name].SetLabels(nil)
 			f := MatchesTemplateClonedFrom(infraConfigs, kcp)
-			g.Expect(f(m)).To(BeFalse())
+			g.Expect(f(m)).To(BeTrue())
 		})
 
-		t.Run("by returning false if only annotations don't match", func(t *testing.T) {
+		t.Run("by returning true if only annotations don't match", func(t *testing.T) {
 			g := NewWithT(t)
 			infraConfigs[m.Name].SetAnnotations(map[string]string{
 				clusterv1.TemplateClonedFromNameAnnotation:      "infra-foo",
@@ -1029,7 +1085,7 @@ func TestMatchesTemplateClonedFrom(t *testing.T) {
 			})
 			infraConfigs[m.Name].SetLabels(kcp.Spec.MachineTemplate.ObjectMeta.Labels)
 			f := MatchesTemplateClonedFrom(infraConfigs, kcp)
-			g.Expect(f(m)).To(BeFalse())
+			g.Expect(f(m)).To(BeTrue())
 		})
 
 		t.Run("by returning true if both labels and annotations match", func(t *testing.T) {
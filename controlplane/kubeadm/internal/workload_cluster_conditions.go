@@ -60,6 +60,7 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 	controlPlaneNodes, err := w.getControlPlaneNodes(ctx)
 	if err != nil {
 		conditions.MarkUnknown(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterInspectionFailedReason, "Failed to list nodes which are hosting the etcd members")
+		conditions.MarkUnknown(controlPlane.KCP, controlplanev1.EtcdClusterNoAlarmsCondition, controlplanev1.EtcdClusterInspectionFailedReason, "Failed to list nodes which are hosting the etcd members")
 		for _, m := range controlPlane.Machines {
 			conditions.MarkUnknown(m, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberInspectionFailedReason, "Failed to get the node which is hosting the etcd member")
 		}
@@ -163,12 +164,22 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 			continue
 		}
 
+		// Check if the member is still a learner; this is expected for a short time after the member joins, but a member
+		// stuck as a learner does not contribute to quorum.
+		if member.IsLearner {
+			conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberLearnerReason, clusterv1.ConditionSeverityWarning, "Etcd member is a learner, not yet promoted to voting member")
+			continue
+		}
+
 		conditions.MarkTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
 	}
 
 	// Make sure that the list of etcd members and machines is consistent.
 	kcpErrors = compareMachinesAndMembers(controlPlane, members, kcpErrors)
 
+	// Forward alarms raised on the etcd cluster to KCP, including alarms on members without a corresponding machine.
+	updateEtcdAlarmsCondition(controlPlane, members)
+
 	// Aggregate components error from machines at KCP level
 	aggregateFromMachinesToKCP(aggregateFromMachinesToKCPInput{
 		controlPlane:      controlPlane,
@@ -181,6 +192,35 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 	})
 }
 
+// updateEtcdAlarmsCondition reports at KCP level the alarms raised on the etcd cluster.
+func updateEtcdAlarmsCondition(controlPlane *ControlPlane, members []*etcd.Member) {
+	// NOTE: If the list of members is not known, it is not possible to tell if there are alarms.
+	if members == nil {
+		conditions.MarkUnknown(controlPlane.KCP, controlplanev1.EtcdClusterNoAlarmsCondition, controlplanev1.EtcdClusterInspectionFailedReason, "Failed to get the list of etcd members")
+		return
+	}
+
+	alarmList := []string{}
+	for _, member := range members {
+		name := member.Name
+		if name == "" {
+			name = fmt.Sprintf("%d (Name not yet assigned)", member.ID)
+		}
+		for _, alarm := range member.Alarms {
+			if alarm == etcd.AlarmOK {
+				continue
+			}
+			alarmList = append(alarmList, fmt.Sprintf("%s on member %s", etcd.AlarmTypeName[alarm], name))
+		}
+	}
+
+	if len(alarmList) > 0 {
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdClusterNoAlarmsCondition, controlplanev1.EtcdClusterAlarmsRaisedReason, clusterv1.ConditionSeverityError, "Etcd cluster reports alarms: %s", strings.Join(alarmList, ", "))
+		return
+	}
+	conditions.MarkTrue(controlPlane.KCP, controlplanev1.EtcdClusterNoAlarmsCondition)
+}
+
 func compareMachinesAndMembers(controlPlane *ControlPlane, members []*etcd.Member, kcpErrors []string) []string {
 	// NOTE: We run this check only if we actually know the list of members, otherwise the first for loop
 	// could generate a false negative when reporting missing etcd members.
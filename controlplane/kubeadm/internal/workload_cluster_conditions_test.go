@@ -40,13 +40,14 @@ import (
 
 func TestUpdateEtcdConditions(t *testing.T) {
 	tests := []struct {
-		name                      string
-		kcp                       *controlplanev1.KubeadmControlPlane
-		machines                  []*clusterv1.Machine
-		injectClient              client.Client // This test is injecting a fake client because it is required to create nodes with a controlled Status or to fail with a specific error.
-		injectEtcdClientGenerator etcdClientFor // This test is injecting a fake etcdClientGenerator because it is required to nodes with a controlled Status or to fail with a specific error.
-		expectedKCPCondition      *clusterv1.Condition
-		expectedMachineConditions map[string]clusterv1.Conditions
+		name                       string
+		kcp                        *controlplanev1.KubeadmControlPlane
+		machines                   []*clusterv1.Machine
+		injectClient               client.Client // This test is injecting a fake client because it is required to create nodes with a controlled Status or to fail with a specific error.
+		injectEtcdClientGenerator  etcdClientFor // This test is injecting a fake etcdClientGenerator because it is required to nodes with a controlled Status or to fail with a specific error.
+		expectedKCPCondition       *clusterv1.Condition
+		expectedKCPAlarmsCondition *clusterv1.Condition
+		expectedMachineConditions  map[string]clusterv1.Conditions
 	}{
 		{
 			name: "if list nodes return an error should report all the conditions Unknown",
@@ -185,13 +186,47 @@ func TestUpdateEtcdConditions(t *testing.T) {
 					},
 				},
 			},
-			expectedKCPCondition: conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityError, "Following machines are reporting etcd member errors: %s", "m1"),
+			expectedKCPCondition:       conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityError, "Following machines are reporting etcd member errors: %s", "m1"),
+			expectedKCPAlarmsCondition: conditions.FalseCondition(controlplanev1.EtcdClusterNoAlarmsCondition, controlplanev1.EtcdClusterAlarmsRaisedReason, clusterv1.ConditionSeverityError, "Etcd cluster reports alarms: %s", "NOSPACE on member n1"),
 			expectedMachineConditions: map[string]clusterv1.Conditions{
 				"m1": {
 					*conditions.FalseCondition(controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Etcd member reports alarms: %s", "NOSPACE"),
 				},
 			},
 		},
+		{
+			name: "an etcd member which is a learner should report false condition",
+			machines: []*clusterv1.Machine{
+				fakeMachine("m1", withNodeRef("n1")),
+			},
+			injectClient: &fakeClient{
+				list: &corev1.NodeList{
+					Items: []corev1.Node{*fakeNode("n1")},
+				},
+			},
+			injectEtcdClientGenerator: &fakeEtcdClientGenerator{
+				forNodesClient: &etcd.Client{
+					EtcdClient: &fake2.FakeEtcdClient{
+						EtcdEndpoints: []string{},
+						MemberListResponse: &clientv3.MemberListResponse{
+							Members: []*pb.Member{
+								{Name: "n1", ID: uint64(1), IsLearner: true},
+							},
+						},
+						AlarmResponse: &clientv3.AlarmResponse{
+							Alarms: []*pb.AlarmMember{},
+						},
+					},
+				},
+			},
+			expectedKCPCondition:       conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityWarning, "Following machines are reporting etcd member warnings: %s", "m1"),
+			expectedKCPAlarmsCondition: conditions.TrueCondition(controlplanev1.EtcdClusterNoAlarmsCondition),
+			expectedMachineConditions: map[string]clusterv1.Conditions{
+				"m1": {
+					*conditions.FalseCondition(controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberLearnerReason, clusterv1.ConditionSeverityWarning, "Etcd member is a learner, not yet promoted to voting member"),
+				},
+			},
+		},
 		{
 			name: "etcd members with different Cluster ID should report false condition",
 			machines: []*clusterv1.Machine{
@@ -482,6 +517,9 @@ func TestUpdateEtcdConditions(t *testing.T) {
 			if tt.expectedKCPCondition != nil {
 				g.Expect(*conditions.Get(tt.kcp, controlplanev1.EtcdClusterHealthyCondition)).To(conditions.MatchCondition(*tt.expectedKCPCondition))
 			}
+			if tt.expectedKCPAlarmsCondition != nil {
+				g.Expect(*conditions.Get(tt.kcp, controlplanev1.EtcdClusterNoAlarmsCondition)).To(conditions.MatchCondition(*tt.expectedKCPAlarmsCondition))
+			}
 			for _, m := range tt.machines {
 				g.Expect(tt.expectedMachineConditions).To(HaveKey(m.Name))
 				g.Expect(m.GetConditions()).To(conditions.MatchConditions(tt.expectedMachineConditions[m.Name]), "unexpected conditions for machine %s", m.Name)
@@ -43,6 +43,7 @@ import (
 	kcpv1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	kubeadmcontrolplanecontrollers "sigs.k8s.io/cluster-api/controlplane/kubeadm/controllers"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/diagnostics"
 	"sigs.k8s.io/cluster-api/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -77,6 +78,7 @@ var (
 	watchFilterValue               string
 	watchNamespace                 string
 	profilerAddress                string
+	diagnosticsOptions             diagnostics.Options
 	kubeadmControlPlaneConcurrency int
 	syncPeriod                     time.Duration
 	webhookPort                    int
@@ -106,6 +108,9 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&profilerAddress, "profiler-address", "",
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)")
+	_ = fs.MarkDeprecated("profiler-address", "use --diagnostics-address together with --diagnostics-enable-profiling instead")
+
+	diagnostics.AddFlags(fs, &diagnosticsOptions)
 
 	fs.IntVar(&kubeadmControlPlaneConcurrency, "kubeadmcontrolplane-concurrency", 10,
 		"Number of kubeadm control planes to process simultaneously")
@@ -173,7 +178,8 @@ func main() {
 	ctx := ctrl.SetupSignalHandler()
 
 	setupChecks(mgr)
-	setupReconcilers(ctx, mgr)
+	tracker := setupReconcilers(ctx, mgr)
+	setupDiagnostics(mgr, tracker)
 	setupWebhooks(mgr)
 
 	// +kubebuilder:scaffold:builder
@@ -196,7 +202,23 @@ func setupChecks(mgr ctrl.Manager) {
 	}
 }
 
-func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
+func setupDiagnostics(mgr ctrl.Manager, tracker diagnostics.ClusterTracker) {
+	if diagnosticsOptions.BindAddress == "" {
+		return
+	}
+
+	server, err := diagnostics.NewServer(mgr, diagnosticsOptions, tracker)
+	if err != nil {
+		setupLog.Error(err, "unable to create diagnostics server")
+		os.Exit(1)
+	}
+	if err := mgr.Add(server); err != nil {
+		setupLog.Error(err, "unable to add diagnostics server")
+		os.Exit(1)
+	}
+}
+
+func setupReconcilers(ctx context.Context, mgr ctrl.Manager) *remote.ClusterCacheTracker {
 	// Set up a ClusterCacheTracker to provide to controllers
 	// requiring a connection to a remote cluster
 	tracker, err := remote.NewClusterCacheTracker(mgr, remote.ClusterCacheTrackerOptions{
@@ -231,6 +253,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmControlPlane")
 		os.Exit(1)
 	}
+
+	return tracker
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -43,10 +43,25 @@ type ClusterResourceSetSpec struct {
 	// Resources is a list of Secrets/ConfigMaps where each contains 1 or more resources to be applied to remote clusters.
 	Resources []ResourceRef `json:"resources,omitempty"`
 
+	// HelmCharts is a list of Helm charts to be rendered and applied to remote clusters, after Resources.
+	// +optional
+	HelmCharts []HelmChart `json:"helmCharts,omitempty"`
+
 	// Strategy is the strategy to be used during applying resources. Defaults to ApplyOnce. This field is immutable.
-	// +kubebuilder:validation:Enum=ApplyOnce
+	// +kubebuilder:validation:Enum=ApplyOnce;ApplyAlways
 	// +optional
 	Strategy string `json:"strategy,omitempty"`
+
+	// ReclaimPolicy defines what happens to the objects applied to a Cluster when the Cluster no longer matches
+	// the selector or the ClusterResourceSet is deleted. Defaults to Retain.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+
+	// DependsOn is a list of names of ClusterResourceSets in the same namespace that must be applied to a Cluster
+	// before the resources of this ClusterResourceSet are applied to it.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // ANCHOR_END: ClusterResourceSetSpec
@@ -58,6 +73,7 @@ type ClusterResourceSetResourceKind string
 const (
 	SecretClusterResourceSetResourceKind    ClusterResourceSetResourceKind = "Secret"
 	ConfigMapClusterResourceSetResourceKind ClusterResourceSetResourceKind = "ConfigMap"
+	HelmChartClusterResourceSetResourceKind ClusterResourceSetResourceKind = "HelmChart"
 )
 
 // ResourceRef specifies a resource.
@@ -67,10 +83,58 @@ type ResourceRef struct {
 	Name string `json:"name"`
 
 	// Kind of the resource. Supported kinds are: Secrets and ConfigMaps.
-	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// HelmChart is used by ClusterResourceSetBindings to track the charts in ClusterResourceSet.spec.helmCharts.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap;HelmChart
 	Kind string `json:"kind"`
 }
 
+// HelmChart specifies a Helm chart, and the values used to render it.
+type HelmChart struct {
+	// Name of the chart in the ClusterResourceSet, used as release name when rendering the chart.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Chart is either an OCI reference to the chart, e.g. oci://registry.example.com/charts/my-chart,
+	// or the name of the chart in the Helm repository defined by RepositoryURL.
+	// +kubebuilder:validation:MinLength=1
+	Chart string `json:"chart"`
+
+	// RepositoryURL is the URL of the Helm repository the chart is downloaded from. It must be empty for OCI charts.
+	// +optional
+	RepositoryURL string `json:"repositoryURL,omitempty"`
+
+	// Version of the chart.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// Namespace the chart is rendered for; namespaced objects that do not define a namespace are applied to it.
+	// Defaults to default.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Values is a YAML document with the values used to render the chart, merged with the default values of the chart.
+	// +optional
+	Values string `json:"values,omitempty"`
+}
+
+// ResourceRef returns the reference used to track the chart in ClusterResourceSetBindings.
+func (c *HelmChart) ResourceRef() ResourceRef {
+	return ResourceRef{
+		Name: c.Name,
+		Kind: string(HelmChartClusterResourceSetResourceKind),
+	}
+}
+
+// GetHelmChart returns the HelmChart with the given name if it exists, nil otherwise.
+func (c *ClusterResourceSetSpec) GetHelmChart(name string) *HelmChart {
+	for i := range c.HelmCharts {
+		if c.HelmCharts[i].Name == name {
+			return &c.HelmCharts[i]
+		}
+	}
+	return nil
+}
+
 // ClusterResourceSetStrategy is a string representation of a ClusterResourceSet Strategy.
 type ClusterResourceSetStrategy string
 
@@ -78,6 +142,10 @@ const (
 	// ClusterResourceSetStrategyApplyOnce is the default strategy a ClusterResourceSet strategy is assigned by
 	// ClusterResourceSet controller after being created if not specified by user.
 	ClusterResourceSetStrategyApplyOnce ClusterResourceSetStrategy = "ApplyOnce"
+
+	// ClusterResourceSetStrategyApplyAlways is the strategy that re-applies the resources to the matching clusters
+	// when the resources change, and periodically to revert any drift of the applied objects in the clusters.
+	ClusterResourceSetStrategyApplyAlways ClusterResourceSetStrategy = "ApplyAlways"
 )
 
 // SetTypedStrategy sets the Strategy field to the string representation of ClusterResourceSetStrategy.
@@ -85,6 +153,24 @@ func (c *ClusterResourceSetSpec) SetTypedStrategy(p ClusterResourceSetStrategy)
 	c.Strategy = string(p)
 }
 
+// ClusterResourceSetReclaimPolicy is a string representation of a ClusterResourceSet ReclaimPolicy.
+type ClusterResourceSetReclaimPolicy string
+
+const (
+	// ClusterResourceSetReclaimPolicyRetain is the default reclaim policy; the objects applied to a Cluster are
+	// left in the Cluster when it is unbound from the ClusterResourceSet.
+	ClusterResourceSetReclaimPolicyRetain ClusterResourceSetReclaimPolicy = "Retain"
+
+	// ClusterResourceSetReclaimPolicyDelete deletes the objects applied to a Cluster when the Cluster no longer
+	// matches the ClusterResourceSet selector or the ClusterResourceSet is deleted.
+	ClusterResourceSetReclaimPolicyDelete ClusterResourceSetReclaimPolicy = "Delete"
+)
+
+// SetTypedReclaimPolicy sets the ReclaimPolicy field to the string representation of ClusterResourceSetReclaimPolicy.
+func (c *ClusterResourceSetSpec) SetTypedReclaimPolicy(p ClusterResourceSetReclaimPolicy) {
+	c.ReclaimPolicy = string(p)
+}
+
 // ANCHOR: ClusterResourceSetStatus
 
 // ClusterResourceSetStatus defines the observed state of ClusterResourceSet.
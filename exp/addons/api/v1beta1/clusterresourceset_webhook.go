@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,6 +47,11 @@ func (m *ClusterResourceSet) Default() {
 	if m.Spec.Strategy == "" {
 		m.Spec.Strategy = string(ClusterResourceSetStrategyApplyOnce)
 	}
+
+	// ClusterResourceSet ReclaimPolicy defaults to Retain.
+	if m.Spec.ReclaimPolicy == "" {
+		m.Spec.ReclaimPolicy = string(ClusterResourceSetReclaimPolicyRetain)
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -87,6 +93,44 @@ func (m *ClusterResourceSet) validate(old *ClusterResourceSet) error {
 		)
 	}
 
+	for i, resource := range m.Spec.Resources {
+		if resource.Kind == string(HelmChartClusterResourceSetResourceKind) {
+			allErrs = append(
+				allErrs,
+				field.NotSupported(field.NewPath("spec", "resources").Index(i).Child("kind"), resource.Kind,
+					[]string{string(SecretClusterResourceSetResourceKind), string(ConfigMapClusterResourceSetResourceKind)}),
+			)
+		}
+	}
+
+	helmChartNames := map[string]bool{}
+	for i, helmChart := range m.Spec.HelmCharts {
+		if helmChartNames[helmChart.Name] {
+			allErrs = append(
+				allErrs,
+				field.Duplicate(field.NewPath("spec", "helmCharts").Index(i).Child("name"), helmChart.Name),
+			)
+		}
+		helmChartNames[helmChart.Name] = true
+
+		// OCI charts are referenced directly, so a repository can't be used with them.
+		if strings.HasPrefix(helmChart.Chart, "oci://") && helmChart.RepositoryURL != "" {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("spec", "helmCharts").Index(i).Child("repositoryURL"), helmChart.RepositoryURL, "must be empty for OCI charts"),
+			)
+		}
+	}
+
+	for i, dependency := range m.Spec.DependsOn {
+		if dependency == m.Name {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("spec", "dependsOn").Index(i), dependency, "a ClusterResourceSet cannot depend on itself"),
+			)
+		}
+	}
+
 	if old != nil && old.Spec.Strategy != "" && old.Spec.Strategy != m.Spec.Strategy {
 		allErrs = append(
 			allErrs,
@@ -36,6 +36,7 @@ func TestClusterResourcesetDefault(t *testing.T) {
 	clusterResourceSet.Default()
 
 	g.Expect(clusterResourceSet.Spec.Strategy).To(Equal(string(ClusterResourceSetStrategyApplyOnce)))
+	g.Expect(clusterResourceSet.Spec.ReclaimPolicy).To(Equal(string(ClusterResourceSetReclaimPolicyRetain)))
 }
 
 func TestClusterResourceSetLabelSelectorAsSelectorValidation(t *testing.T) {
@@ -190,3 +191,110 @@ func TestClusterResourceSetSelectorNotEmptyValidation(t *testing.T) {
 	g.Expect(err).ToNot(BeNil())
 	g.Expect(err.Error()).To(ContainSubstring("selector must not be empty"))
 }
+
+func TestClusterResourceSetDependsOnValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		dependsOn []string
+		expectErr bool
+	}{
+		{
+			name:      "should succeed when depending on other ClusterResourceSets",
+			dependsOn: []string{"cni"},
+			expectErr: false,
+		},
+		{
+			name:      "should return error when depending on itself",
+			dependsOn: []string{"cni", "addons"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &ClusterResourceSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "addons",
+				},
+				Spec: ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					DependsOn: tt.dependsOn,
+				},
+			}
+			if tt.expectErr {
+				g.Expect(clusterResourceSet.ValidateCreate()).NotTo(Succeed())
+				g.Expect(clusterResourceSet.ValidateUpdate(clusterResourceSet)).NotTo(Succeed())
+			} else {
+				g.Expect(clusterResourceSet.ValidateCreate()).To(Succeed())
+				g.Expect(clusterResourceSet.ValidateUpdate(clusterResourceSet)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestClusterResourceSetHelmChartsValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		resources  []ResourceRef
+		helmCharts []HelmChart
+		expectErr  bool
+	}{
+		{
+			name: "should succeed with charts from OCI registries and Helm repositories",
+			helmCharts: []HelmChart{
+				{Name: "cni", Chart: "oci://registry.example.com/charts/cni", Version: "1.0.0"},
+				{Name: "csi", Chart: "csi", RepositoryURL: "https://charts.example.com", Version: "1.0.0"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when chart names are duplicated",
+			helmCharts: []HelmChart{
+				{Name: "cni", Chart: "oci://registry.example.com/charts/cni", Version: "1.0.0"},
+				{Name: "cni", Chart: "cni", RepositoryURL: "https://charts.example.com", Version: "1.0.0"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when an OCI chart has a repository",
+			helmCharts: []HelmChart{
+				{Name: "cni", Chart: "oci://registry.example.com/charts/cni", RepositoryURL: "https://charts.example.com", Version: "1.0.0"},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "should return error when a HelmChart is listed in resources",
+			resources: []ResourceRef{{Name: "cni", Kind: string(HelmChartClusterResourceSetResourceKind)}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &ClusterResourceSet{
+				Spec: ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					Resources:  tt.resources,
+					HelmCharts: tt.helmCharts,
+				},
+			}
+			if tt.expectErr {
+				g.Expect(clusterResourceSet.ValidateCreate()).NotTo(Succeed())
+				g.Expect(clusterResourceSet.ValidateUpdate(clusterResourceSet)).NotTo(Succeed())
+			} else {
+				g.Expect(clusterResourceSet.ValidateCreate()).To(Succeed())
+				g.Expect(clusterResourceSet.ValidateUpdate(clusterResourceSet)).To(Succeed())
+			}
+		})
+	}
+}
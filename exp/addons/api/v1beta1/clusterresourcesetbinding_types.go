@@ -30,7 +30,8 @@ type ResourceBinding struct {
 	ResourceRef `json:",inline"`
 
 	// Hash is the hash of a resource's data. This can be used to decide if a resource is changed.
-	// For "ApplyOnce" ClusterResourceSet.spec.strategy, this is no-op as that strategy does not act on change.
+	// For "ApplyOnce" ClusterResourceSet.spec.strategy, this is no-op as that strategy does not act on change;
+	// for "ApplyAlways", the resource is applied again when its hash changes.
 	Hash string `json:"hash,omitempty"`
 
 	// LastAppliedTime identifies when this resource was last applied to the cluster.
@@ -50,6 +51,11 @@ type ResourceSetBinding struct {
 
 	// Resources is a list of resources that the ClusterResourceSet has.
 	Resources []ResourceBinding `json:"resources,omitempty"`
+
+	// BlockedBy is the list of ClusterResourceSets the ClusterResourceSet depends on that are not applied to the
+	// cluster yet; the resources of the ClusterResourceSet are not applied until this list is empty.
+	// +optional
+	BlockedBy []string `json:"blockedBy,omitempty"`
 }
 
 // IsApplied returns true if the resource is applied to the cluster by checking the cluster's binding.
@@ -64,6 +70,16 @@ func (r *ResourceSetBinding) IsApplied(resourceRef ResourceRef) bool {
 	return false
 }
 
+// GetResource returns the ResourceBinding for a resource if it exists, nil otherwise.
+func (r *ResourceSetBinding) GetResource(resourceRef ResourceRef) *ResourceBinding {
+	for i := range r.Resources {
+		if reflect.DeepEqual(r.Resources[i].ResourceRef, resourceRef) {
+			return &r.Resources[i]
+		}
+	}
+	return nil
+}
+
 // SetBinding sets resourceBinding for a resource in resourceSetbinding either by updating the existing one or
 // creating a new one.
 func (r *ResourceSetBinding) SetBinding(resourceBinding ResourceBinding) {
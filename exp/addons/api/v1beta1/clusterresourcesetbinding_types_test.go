@@ -91,6 +91,35 @@ func TestIsResourceApplied(t *testing.T) {
 	}
 }
 
+func TestGetResourceBinding(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceRef := ResourceRef{
+		Name: "applied",
+		Kind: "Secret",
+	}
+	resourceRefNotExist := ResourceRef{
+		Name: "notExist",
+		Kind: "Secret",
+	}
+	CRSBinding := &ResourceSetBinding{
+		ClusterResourceSetName: "test-clusterResourceSet",
+		Resources: []ResourceBinding{
+			{
+				ResourceRef:     resourceRef,
+				Applied:         true,
+				Hash:            "xyz",
+				LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+			},
+		},
+	}
+
+	resourceBinding := CRSBinding.GetResource(resourceRef)
+	g.Expect(resourceBinding).NotTo(BeNil())
+	g.Expect(resourceBinding.Hash).To(Equal("xyz"))
+	g.Expect(CRSBinding.GetResource(resourceRefNotExist)).To(BeNil())
+}
+
 func TestSetResourceBinding(t *testing.T) {
 	resourceRefApplyFailed := ResourceRef{
 		Name: "applyFailed",
@@ -39,4 +39,16 @@ const (
 
 	// WrongSecretTypeReason (Severity=Warning) documents at least one of the Secret's type in the resource list is not supported.
 	WrongSecretTypeReason = "WrongSecretType"
+
+	// RenderingHelmChartFailedReason (Severity=Warning) documents at least one of the Helm charts is not successfully
+	// downloaded or rendered.
+	RenderingHelmChartFailedReason = "RenderingHelmChartFailed"
+
+	// DeleteFailedReason (Severity=Warning) documents deleting the objects applied to a cluster that is unbound from the
+	// ClusterResourceSet is failed.
+	DeleteFailedReason = "DeleteFailed"
+
+	// WaitingForDependenciesReason (Severity=Info) documents the resources are not applied to one of the matching
+	// clusters because at least one of the ClusterResourceSets listed in dependsOn is not applied to it yet.
+	WaitingForDependenciesReason = "WaitingForDependencies"
 )
@@ -18,9 +18,8 @@ package controllers
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -37,6 +36,7 @@ import (
 	resourcepredicates "sigs.k8s.io/cluster-api/exp/addons/controllers/predicates"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -54,6 +54,12 @@ var (
 	ErrSecretTypeNotSupported = errors.New("unsupported secret type")
 )
 
+const (
+	// applyAlwaysResyncPeriod is the interval at which ClusterResourceSets with the ApplyAlways strategy are
+	// reconciled, so drift of the applied objects in the workload clusters is reverted.
+	applyAlwaysResyncPeriod = 5 * time.Minute
+)
+
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
@@ -78,7 +84,7 @@ func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr
 			handler.EnqueueRequestsFromMapFunc(r.resourceToClusterResourceSet),
 			builder.OnlyMetadata,
 			builder.WithPredicates(
-				resourcepredicates.ResourceCreate(ctrl.LoggerFrom(ctx)),
+				resourcepredicates.ResourceCreateOrUpdate(ctrl.LoggerFrom(ctx)),
 			),
 		).
 		Watches(
@@ -86,9 +92,13 @@ func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr
 			handler.EnqueueRequestsFromMapFunc(r.resourceToClusterResourceSet),
 			builder.OnlyMetadata,
 			builder.WithPredicates(
-				resourcepredicates.ResourceCreate(ctrl.LoggerFrom(ctx)),
+				resourcepredicates.ResourceCreateOrUpdate(ctrl.LoggerFrom(ctx)),
 			),
 		).
+		Watches(
+			&source.Kind{Type: &addonsv1.ClusterResourceSetBinding{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterResourceSetBindingToBlockedClusterResourceSet),
+		).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Complete(r)
@@ -101,8 +111,6 @@ func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr
 }
 
 func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	// Fetch the ClusterResourceSet instance.
 	clusterResourceSet := &addonsv1.ClusterResourceSet{}
 	if err := r.Client.Get(ctx, req.NamespacedName, clusterResourceSet); err != nil {
@@ -115,6 +123,8 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	ctx, log := logutil.Into(ctx, logutil.ClusterResourceSetKey, logutil.KObj(clusterResourceSet))
+
 	// Initialize the patch helper.
 	patchHelper, err := patch.NewHelper(clusterResourceSet, r.Client)
 	if err != nil {
@@ -130,7 +140,7 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	clusters, err := r.getClustersByClusterResourceSetSelector(ctx, clusterResourceSet)
 	if err != nil {
-		log.Error(err, "Failed fetching clusters that matches ClusterResourceSet labels", "ClusterResourceSet", clusterResourceSet.Name)
+		log.Error(err, "Failed fetching clusters that matches ClusterResourceSet labels")
 		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ClusterMatchFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		return ctrl.Result{}, err
 	}
@@ -146,19 +156,28 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return r.reconcileDelete(ctx, clusters, clusterResourceSet)
 	}
 
+	// Delete the objects applied to the Clusters that no longer match the selector, if required by the reclaim policy.
+	if clusterResourceSet.Spec.ReclaimPolicy == string(addonsv1.ClusterResourceSetReclaimPolicyDelete) {
+		if err := r.reconcileUnmatchedClusters(ctx, clusters, clusterResourceSet); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	for _, cluster := range clusters {
 		if err := r.ApplyClusterResourceSet(ctx, cluster, clusterResourceSet); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	if clusterResourceSet.Spec.Strategy == string(addonsv1.ClusterResourceSetStrategyApplyAlways) && len(clusters) > 0 {
+		return ctrl.Result{RequeueAfter: applyAlwaysResyncPeriod}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
 // reconcileDelete removes the deleted ClusterResourceSet from all the ClusterResourceSetBindings it is added to.
 func (r *ClusterResourceSetReconciler) reconcileDelete(ctx context.Context, clusters []*clusterv1.Cluster, crs *addonsv1.ClusterResourceSet) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx)
-
 	for _, cluster := range clusters {
 		clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
 		clusterResourceSetBindingKey := client.ObjectKey{
@@ -169,32 +188,150 @@ func (r *ClusterResourceSetReconciler) reconcileDelete(ctx context.Context, clus
 			if !apierrors.IsNotFound(err) {
 				return ctrl.Result{}, errors.Wrapf(err, "failed to get ClusterResourceSetBinding during ClusterResourceSet deletion")
 			}
-			controllerutil.RemoveFinalizer(crs, addonsv1.ClusterResourceSetFinalizer)
-			return ctrl.Result{}, nil
+			continue
 		}
 
-		// Initialize the patch helper.
-		patchHelper, err := patch.NewHelper(clusterResourceSetBinding, r.Client)
-		if err != nil {
+		if err := r.unbindCluster(ctx, cluster, clusterResourceSetBinding, crs); err != nil {
 			return ctrl.Result{}, err
 		}
+	}
 
-		clusterResourceSetBinding.DeleteBinding(crs)
+	controllerutil.RemoveFinalizer(crs, addonsv1.ClusterResourceSetFinalizer)
+	return ctrl.Result{}, nil
+}
 
-		// If CRS list is empty in the binding, delete the binding else
-		// attempt to Patch the ClusterResourceSetBinding object after delete reconciliation if there is at least 1 binding left.
-		if len(clusterResourceSetBinding.Spec.Bindings) == 0 {
-			if r.Client.Delete(ctx, clusterResourceSetBinding) != nil {
-				log.Error(err, "failed to delete empty ClusterResourceSetBinding")
+// reconcileUnmatchedClusters unbinds the ClusterResourceSet from the Clusters that no longer match its selector.
+func (r *ClusterResourceSetReconciler) reconcileUnmatchedClusters(ctx context.Context, clusters []*clusterv1.Cluster, crs *addonsv1.ClusterResourceSet) error {
+	matchingClusters := map[string]bool{}
+	for _, cluster := range clusters {
+		matchingClusters[cluster.Name] = true
+	}
+
+	clusterResourceSetBindings := &addonsv1.ClusterResourceSetBindingList{}
+	if err := r.Client.List(ctx, clusterResourceSetBindings, client.InNamespace(crs.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list ClusterResourceSetBindings")
+	}
+
+	for i := range clusterResourceSetBindings.Items {
+		clusterResourceSetBinding := &clusterResourceSetBindings.Items[i]
+		if matchingClusters[clusterResourceSetBinding.Name] || !isBound(clusterResourceSetBinding, crs) {
+			continue
+		}
+
+		// The ClusterResourceSetBinding has the same name of its Cluster; Clusters being deleted are skipped, given
+		// that the ClusterResourceSetBinding is going to be deleted with them.
+		cluster := &clusterv1.Cluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: crs.Namespace, Name: clusterResourceSetBinding.Name}, cluster); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
 			}
-		} else if err := patchHelper.Patch(ctx, clusterResourceSetBinding); err != nil {
-			log.Error(err, "failed to patch ClusterResourceSetBinding")
-			return ctrl.Result{}, err
+			return errors.Wrapf(err, "failed to get Cluster %s", clusterResourceSetBinding.Name)
+		}
+		if !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if err := r.unbindCluster(ctx, cluster, clusterResourceSetBinding, crs); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	controllerutil.RemoveFinalizer(crs, addonsv1.ClusterResourceSetFinalizer)
-	return ctrl.Result{}, nil
+// unbindCluster removes the ClusterResourceSet from the ClusterResourceSetBinding of a Cluster; if the reclaim policy
+// is Delete, the objects applied to the Cluster are deleted first.
+func (r *ClusterResourceSetReconciler) unbindCluster(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, crs *addonsv1.ClusterResourceSet) error {
+	log := ctrl.LoggerFrom(ctx, logutil.ClusterKey, logutil.KObj(cluster))
+
+	if crs.Spec.ReclaimPolicy == string(addonsv1.ClusterResourceSetReclaimPolicyDelete) && isBound(clusterResourceSetBinding, crs) {
+		if err := r.deleteAppliedObjects(ctx, cluster, crs, clusterResourceSetBinding.GetOrCreateBinding(crs)); err != nil {
+			conditions.MarkFalse(crs, addonsv1.ResourcesAppliedCondition, addonsv1.DeleteFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(clusterResourceSetBinding, r.Client)
+	if err != nil {
+		return err
+	}
+
+	clusterResourceSetBinding.DeleteBinding(crs)
+
+	// If CRS list is empty in the binding, delete the binding else
+	// attempt to Patch the ClusterResourceSetBinding object after delete reconciliation if there is at least 1 binding left.
+	if len(clusterResourceSetBinding.Spec.Bindings) == 0 {
+		if err := r.Client.Delete(ctx, clusterResourceSetBinding); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to delete empty ClusterResourceSetBinding")
+		}
+	} else if err := patchHelper.Patch(ctx, clusterResourceSetBinding); err != nil {
+		log.Error(err, "failed to patch ClusterResourceSetBinding")
+		return err
+	}
+	return nil
+}
+
+// deleteAppliedObjects deletes from the Cluster the objects defined in the resources applied by the ClusterResourceSet.
+// Resources that no longer exist are skipped; Helm charts are rendered again, and skipped if no longer listed in the
+// ClusterResourceSet.
+func (r *ClusterResourceSetReconciler) deleteAppliedObjects(ctx context.Context, cluster *clusterv1.Cluster, crs *addonsv1.ClusterResourceSet, resourceSetBinding *addonsv1.ResourceSetBinding) error {
+	log := ctrl.LoggerFrom(ctx, logutil.ClusterKey, logutil.KObj(cluster))
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return err
+	}
+
+	errList := []error{}
+	for _, resource := range resourceSetBinding.Resources {
+		if !resource.Applied {
+			continue
+		}
+
+		if resource.Kind == string(addonsv1.HelmChartClusterResourceSetResourceKind) {
+			helmChart := crs.Spec.GetHelmChart(resource.Name)
+			if helmChart == nil {
+				log.Info("Skipping deletion of the objects of a ClusterResourceSet Helm chart that is no longer defined", "Chart name", resource.Name)
+				continue
+			}
+
+			dataList, err := renderHelmChart(remoteClient, helmChart)
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+
+			for i := range dataList {
+				if err := deleteObjects(ctx, remoteClient, dataList[i]); err != nil {
+					errList = append(errList, err)
+				}
+			}
+			continue
+		}
+
+		unstructuredObj, err := r.getResource(ctx, resource.ResourceRef, cluster.GetNamespace())
+		if err != nil {
+			if apierrors.IsNotFound(err) || err == ErrSecretTypeNotSupported {
+				log.Info("Skipping deletion of the objects of a ClusterResourceSet resource that can't be retrieved", "Resource kind", resource.Kind, "Resource name", resource.Name)
+				continue
+			}
+			errList = append(errList, err)
+			continue
+		}
+
+		dataList, err := normalizeData(unstructuredObj)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+
+		for i := range dataList {
+			if err := deleteObjects(ctx, remoteClient, dataList[i]); err != nil {
+				errList = append(errList, err)
+			}
+		}
+	}
+	return kerrors.NewAggregate(errList)
 }
 
 // getClustersByClusterResourceSetSelector fetches Clusters matched by the ClusterResourceSet's label selector that are in the same namespace as the ClusterResourceSet object.
@@ -230,10 +367,15 @@ func (r *ClusterResourceSetReconciler) getClustersByClusterResourceSetSelector(c
 // ApplyClusterResourceSet applies resources in a ClusterResourceSet to a Cluster. Once applied, a record will be added to the
 // cluster's ClusterResourceSetBinding.
 // In ApplyOnce strategy, resources are applied only once to a particular cluster. ClusterResourceSetBinding is used to check if a resource is applied before.
+// In ApplyAlways strategy, resources are applied at every reconcile, creating the missing objects and patching the existing ones,
+// so changes to the resources as well as drift in the cluster are reconciled; ClusterResourceSetBinding tracks the hash of the applied resources.
+// Resources are not applied until all the ClusterResourceSets listed in dependsOn are applied to the cluster.
+// Helm charts are rendered and applied after the resources, and tracked in ClusterResourceSetBinding as resources of kind HelmChart.
 // It applies resources best effort and continue on scenarios like: unsupported resource types, failure during creation, missing resources.
 // TODO: If a resource already exists in the cluster but not applied by ClusterResourceSet, the resource will be updated ?
 func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) error {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx, logutil.ClusterKey, logutil.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
 
 	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
 	if err != nil {
@@ -263,10 +405,30 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 	errList := []error{}
 	resourceSetBinding := clusterResourceSetBinding.GetOrCreateBinding(clusterResourceSet)
 
+	// Wait for the ClusterResourceSets this one depends on to be applied to the cluster; the ClusterResourceSetBinding
+	// records them, so this ClusterResourceSet is reconciled again once they are applied.
+	blockedBy, err := r.getBlockingDependencies(ctx, clusterResourceSetBinding, clusterResourceSet)
+	if err != nil {
+		return err
+	}
+	resourceSetBinding.BlockedBy = blockedBy
+	if len(blockedBy) > 0 {
+		log.Info("Waiting for dependencies to be applied", "ClusterResourceSets", blockedBy)
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WaitingForDependenciesReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for ClusterResourceSets %s to be applied to cluster %s", strings.Join(blockedBy, ", "), cluster.Name)
+		return nil
+	}
+
+	applyAlways := clusterResourceSet.Spec.Strategy == string(addonsv1.ClusterResourceSetStrategyApplyAlways)
+	applyFn := createUnstructured
+	if applyAlways {
+		applyFn = createOrPatchUnstructured
+	}
+
 	// Iterate all resources and apply them to the cluster and update the resource status in the ClusterResourceSetBinding object.
 	for _, resource := range clusterResourceSet.Spec.Resources {
 		// If resource is already applied successfully and clusterResourceSet mode is "ApplyOnce", continue. (No need to check hash changes here)
-		if resourceSetBinding.IsApplied(resource) {
+		if !applyAlways && resourceSetBinding.IsApplied(resource) {
 			continue
 		}
 
@@ -287,13 +449,16 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 		}
 
 		// Set status in ClusterResourceSetBinding in case of early continue due to a failure.
-		// Set only when resource is retrieved successfully.
-		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
-			ResourceRef:     resource,
-			Hash:            "",
-			Applied:         false,
-			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
-		})
+		// Set only when resource is retrieved successfully, and keep the status of resources applied before
+		// with the "ApplyAlways" strategy.
+		if !resourceSetBinding.IsApplied(resource) {
+			resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
+				ResourceRef:     resource,
+				Hash:            "",
+				Applied:         false,
+				LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+			})
+		}
 
 		if err := r.patchOwnerRefToResource(ctx, clusterResourceSet, unstructuredObj); err != nil {
 			log.Error(err, "Failed to patch ClusterResourceSet as resource owner reference",
@@ -301,57 +466,38 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			errList = append(errList, err)
 		}
 
-		// Since maps are not ordered, we need to order them to get the same hash at each reconcile.
-		keys := make([]string, 0)
-		data, ok := unstructuredObj.UnstructuredContent()["data"]
-		if !ok {
-			errList = append(errList, errors.New("failed to get data field from the resource"))
+		dataList, err := normalizeData(unstructuredObj)
+		if err != nil {
+			errList = append(errList, err)
 			continue
 		}
 
-		unstructuredData := data.(map[string]interface{})
-		for key := range unstructuredData {
-			keys = append(keys, key)
+		// Apply all values in the key-value pair of the resource to the cluster.
+		// As there can be multiple key-value pairs in a resource, each value may have multiple objects in it.
+		if err := applyResourceData(ctx, remoteClient, clusterResourceSet, resourceSetBinding, resource, dataList, applyFn); err != nil {
+			errList = append(errList, err)
 		}
-		sort.Strings(keys)
-
-		dataList := make([][]byte, 0)
-		for _, key := range keys {
-			val, ok, err := unstructured.NestedString(unstructuredData, key)
-			if !ok || err != nil {
-				errList = append(errList, errors.New("failed to get value field from the resource"))
-				continue
-			}
-
-			byteArr := []byte(val)
-			// If the resource is a Secret, data needs to be decoded.
-			if unstructuredObj.GetKind() == string(addonsv1.SecretClusterResourceSetResourceKind) {
-				byteArr, _ = base64.StdEncoding.DecodeString(val)
-			}
+	}
 
-			dataList = append(dataList, byteArr)
+	// Iterate all Helm charts, render and apply them to the cluster and update their status in the ClusterResourceSetBinding object.
+	for i := range clusterResourceSet.Spec.HelmCharts {
+		helmChart := &clusterResourceSet.Spec.HelmCharts[i]
+		resource := helmChart.ResourceRef()
+		if !applyAlways && resourceSetBinding.IsApplied(resource) {
+			continue
 		}
 
-		// Apply all values in the key-value pair of the resource to the cluster.
-		// As there can be multiple key-value pairs in a resource, each value may have multiple objects in it.
-		isSuccessful := true
-		for i := range dataList {
-			data := dataList[i]
-
-			if err := apply(ctx, remoteClient, data); err != nil {
-				isSuccessful = false
-				log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
-				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-				errList = append(errList, err)
-			}
+		dataList, err := renderHelmChart(remoteClient, helmChart)
+		if err != nil {
+			log.Error(err, "failed to render ClusterResourceSet Helm chart", "Chart", helmChart.Chart, "Chart name", helmChart.Name)
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.RenderingHelmChartFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			errList = append(errList, err)
+			continue
 		}
 
-		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
-			ResourceRef:     resource,
-			Hash:            computeHash(dataList),
-			Applied:         isSuccessful,
-			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
-		})
+		if err := applyResourceData(ctx, remoteClient, clusterResourceSet, resourceSetBinding, resource, dataList, applyFn); err != nil {
+			errList = append(errList, err)
+		}
 	}
 	if len(errList) > 0 {
 		return kerrors.NewAggregate(errList)
@@ -362,6 +508,68 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 	return nil
 }
 
+// applyResourceData applies the objects in the data of a resource to the cluster using applyFn, and records the outcome
+// with the hash of the data in the ResourceSetBinding.
+func applyResourceData(ctx context.Context, remoteClient client.Client, clusterResourceSet *addonsv1.ClusterResourceSet, resourceSetBinding *addonsv1.ResourceSetBinding, resource addonsv1.ResourceRef, dataList [][]byte, applyFn func(context.Context, client.Client, *unstructured.Unstructured) error) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	previousHash := ""
+	wasApplied := resourceSetBinding.IsApplied(resource)
+	if wasApplied {
+		previousHash = resourceSetBinding.GetResource(resource).Hash
+	}
+
+	errList := []error{}
+	for i := range dataList {
+		data := dataList[i]
+
+		if err := apply(ctx, remoteClient, data, applyFn); err != nil {
+			log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			errList = append(errList, err)
+		}
+	}
+	isSuccessful := len(errList) == 0
+
+	hash := computeHash(dataList)
+
+	// When a resource applied before with the "ApplyAlways" strategy has not changed, only drift in the cluster
+	// has been reconciled, so the binding is left untouched.
+	if isSuccessful && wasApplied && previousHash == hash {
+		return nil
+	}
+
+	resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
+		ResourceRef:     resource,
+		Hash:            hash,
+		Applied:         isSuccessful,
+		LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+	})
+	return kerrors.NewAggregate(errList)
+}
+
+// getBlockingDependencies returns the names of the ClusterResourceSets listed in dependsOn that are not applied to the
+// cluster yet; a dependency is applied when it is bound to the cluster, it is not blocked in turn, and all of its
+// resources are applied.
+func (r *ClusterResourceSetReconciler) getBlockingDependencies(ctx context.Context, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, clusterResourceSet *addonsv1.ClusterResourceSet) ([]string, error) {
+	blockedBy := []string{}
+	for _, dependency := range clusterResourceSet.Spec.DependsOn {
+		dependencyClusterResourceSet := &addonsv1.ClusterResourceSet{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: clusterResourceSet.Namespace, Name: dependency}, dependencyClusterResourceSet); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "failed to get ClusterResourceSet %s", dependency)
+			}
+			blockedBy = append(blockedBy, dependency)
+			continue
+		}
+
+		if !isApplied(clusterResourceSetBinding, dependencyClusterResourceSet) {
+			blockedBy = append(blockedBy, dependency)
+		}
+	}
+	return blockedBy, nil
+}
+
 // getResource retrieves the requested resource and convert it to unstructured type.
 // Unsupported resource kinds are not denied by validation webhook, hence no need to check here.
 // Only supports Secrets/Configmaps as resource types and allow using resources in the same namespace with the cluster.
@@ -431,6 +639,15 @@ func (r *ClusterResourceSetReconciler) clusterToClusterResourceSet(o client.Obje
 		return nil
 	}
 
+	// Add the ClusterResourceSets bound to the cluster, so they are reconciled when the cluster no longer matches their selector.
+	clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
+	if err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(cluster), clusterResourceSetBinding); err == nil {
+		for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+			name := client.ObjectKey{Namespace: cluster.Namespace, Name: binding.ClusterResourceSetName}
+			result = append(result, ctrl.Request{NamespacedName: name})
+		}
+	}
+
 	labels := labels.Set(cluster.GetLabels())
 	for i := range resourceList.Items {
 		rs := &resourceList.Items[i]
@@ -445,7 +662,7 @@ func (r *ClusterResourceSetReconciler) clusterToClusterResourceSet(o client.Obje
 			return nil
 		}
 
-		if !selector.Matches(labels) {
+		if !selector.Matches(labels) || isBound(clusterResourceSetBinding, rs) {
 			continue
 		}
 
@@ -455,6 +672,26 @@ func (r *ClusterResourceSetReconciler) clusterToClusterResourceSet(o client.Obje
 	return result
 }
 
+// clusterResourceSetBindingToBlockedClusterResourceSet is mapper function that maps ClusterResourceSetBindings to the
+// ClusterResourceSets waiting for their dependencies to be applied to the cluster.
+func (r *ClusterResourceSetReconciler) clusterResourceSetBindingToBlockedClusterResourceSet(o client.Object) []ctrl.Request {
+	result := []ctrl.Request{}
+
+	clusterResourceSetBinding, ok := o.(*addonsv1.ClusterResourceSetBinding)
+	if !ok {
+		panic(fmt.Sprintf("Expected a ClusterResourceSetBinding but got a %T", o))
+	}
+
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		if len(binding.BlockedBy) == 0 {
+			continue
+		}
+		name := client.ObjectKey{Namespace: clusterResourceSetBinding.Namespace, Name: binding.ClusterResourceSetName}
+		result = append(result, ctrl.Request{NamespacedName: name})
+	}
+	return result
+}
+
 // resourceToClusterResourceSet is mapper function that maps resources to ClusterResourceSet.
 func (r *ClusterResourceSetReconciler) resourceToClusterResourceSet(o client.Object) []ctrl.Request {
 	result := []ctrl.Request{}
@@ -466,6 +466,270 @@ metadata:
 		}, timeout).Should(BeTrue())
 	})
 
+	t.Run("Should re-apply the resources of an ApplyAlways ClusterResourceSet when they change", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		newCMName := fmt.Sprintf("test-configmap-%s", util.RandomString(6))
+		remoteCMKey := client.ObjectKey{
+			Namespace: metav1.NamespaceDefault,
+			Name:      fmt.Sprintf("resource-configmap-%s", util.RandomString(6)),
+		}
+		remoteConfigMap := func(value string) string {
+			return fmt.Sprintf(`kind: ConfigMap
+apiVersion: v1
+metadata:
+ name: %s
+ namespace: %s
+data:
+ key: %s`, remoteCMKey.Name, remoteCMKey.Namespace, value)
+		}
+
+		newConfigmap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      newCMName,
+				Namespace: ns.Name,
+			},
+			Data: map[string]string{
+				"cm": remoteConfigMap("value1"),
+			},
+		}
+		g.Expect(env.Create(ctx, newConfigmap)).To(Succeed())
+		defer func() {
+			g.Expect(env.Delete(ctx, newConfigmap)).To(Succeed())
+			g.Expect(env.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:      remoteCMKey.Name,
+				Namespace: remoteCMKey.Namespace,
+			}})).To(Succeed())
+		}()
+
+		t.Log("Updating the cluster with labels")
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Creating a ClusterResourceSet instance with the ApplyAlways strategy")
+		crsInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources: []addonsv1.ResourceRef{{Name: newCMName, Kind: "ConfigMap"}},
+				Strategy:  string(addonsv1.ClusterResourceSetStrategyApplyAlways),
+			},
+		}
+		g.Expect(env.Create(ctx, crsInstance)).To(Succeed())
+
+		clusterResourceSetBindingKey := client.ObjectKey{
+			Namespace: testCluster.Namespace,
+			Name:      testCluster.Name,
+		}
+		getAppliedHash := func() (string, error) {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			if err := env.Get(ctx, clusterResourceSetBindingKey, binding); err != nil {
+				return "", err
+			}
+			if len(binding.Spec.Bindings) != 1 || len(binding.Spec.Bindings[0].Resources) != 1 || !binding.Spec.Bindings[0].Resources[0].Applied {
+				return "", errors.Errorf("ClusterResourceSet binding does not have the resource %q applied: %v", newCMName, binding.Spec.Bindings)
+			}
+			return binding.Spec.Bindings[0].Resources[0].Hash, nil
+		}
+		getRemoteValue := func() (string, error) {
+			cm := &corev1.ConfigMap{}
+			if err := env.Get(ctx, remoteCMKey, cm); err != nil {
+				return "", err
+			}
+			return cm.Data["key"], nil
+		}
+
+		t.Log("Verifying the resource is applied to the cluster")
+		g.Eventually(getRemoteValue, timeout).Should(Equal("value1"))
+		var appliedHash string
+		g.Eventually(func() error {
+			var err error
+			appliedHash, err = getAppliedHash()
+			return err
+		}, timeout).Should(Succeed())
+
+		t.Log("Updating the ConfigMap resource")
+		newConfigmap.Data["cm"] = remoteConfigMap("value2")
+		g.Expect(env.Update(ctx, newConfigmap)).To(Succeed())
+
+		t.Log("Verifying the updated resource is applied to the cluster")
+		g.Eventually(getRemoteValue, timeout).Should(Equal("value2"))
+		g.Eventually(getAppliedHash, timeout).ShouldNot(Equal(appliedHash))
+
+		t.Log("Deleting the Cluster")
+		g.Expect(env.Delete(ctx, testCluster)).To(Succeed())
+	})
+
+	t.Run("Should delete the applied objects when a cluster no longer matches a ClusterResourceSet with the Delete reclaim policy", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		newCMName := fmt.Sprintf("test-configmap-%s", util.RandomString(6))
+		remoteCMKey := client.ObjectKey{
+			Namespace: metav1.NamespaceDefault,
+			Name:      fmt.Sprintf("resource-configmap-%s", util.RandomString(6)),
+		}
+
+		newConfigmap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      newCMName,
+				Namespace: ns.Name,
+			},
+			Data: map[string]string{
+				"cm": fmt.Sprintf(`kind: ConfigMap
+apiVersion: v1
+metadata:
+ name: %s
+ namespace: %s`, remoteCMKey.Name, remoteCMKey.Namespace),
+			},
+		}
+		g.Expect(env.Create(ctx, newConfigmap)).To(Succeed())
+		defer func() {
+			g.Expect(env.Delete(ctx, newConfigmap)).To(Succeed())
+		}()
+
+		t.Log("Updating the cluster with labels")
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Creating a ClusterResourceSet instance with the Delete reclaim policy")
+		crsInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources:     []addonsv1.ResourceRef{{Name: newCMName, Kind: "ConfigMap"}},
+				ReclaimPolicy: string(addonsv1.ClusterResourceSetReclaimPolicyDelete),
+			},
+		}
+		g.Expect(env.Create(ctx, crsInstance)).To(Succeed())
+
+		t.Log("Verifying the resource is applied to the cluster")
+		g.Eventually(func() error {
+			return env.Get(ctx, remoteCMKey, &corev1.ConfigMap{})
+		}, timeout).Should(Succeed())
+
+		clusterResourceSetBindingKey := client.ObjectKey{
+			Namespace: testCluster.Namespace,
+			Name:      testCluster.Name,
+		}
+		g.Eventually(func() bool {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			if err := env.Get(ctx, clusterResourceSetBindingKey, binding); err != nil {
+				return false
+			}
+			return len(binding.Spec.Bindings) == 1 && binding.Spec.Bindings[0].IsApplied(crsInstance.Spec.Resources[0])
+		}, timeout).Should(BeTrue())
+
+		t.Log("Removing the labels from the cluster")
+		g.Expect(env.Get(ctx, client.ObjectKeyFromObject(testCluster), testCluster)).To(Succeed())
+		testCluster.SetLabels(nil)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Verifying the applied object is deleted from the cluster and the ClusterResourceSetBinding is deleted")
+		g.Eventually(func() bool {
+			return apierrors.IsNotFound(env.Get(ctx, remoteCMKey, &corev1.ConfigMap{}))
+		}, timeout).Should(BeTrue())
+		g.Eventually(func() bool {
+			return apierrors.IsNotFound(env.Get(ctx, clusterResourceSetBindingKey, &addonsv1.ClusterResourceSetBinding{}))
+		}, timeout).Should(BeTrue())
+
+		t.Log("Deleting the Cluster")
+		g.Expect(env.Delete(ctx, testCluster)).To(Succeed())
+	})
+
+	t.Run("Should apply the resources of a ClusterResourceSet only after its dependencies are applied", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		t.Log("Updating the cluster with labels")
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		t.Log("Creating a ClusterResourceSet instance that depends on a ClusterResourceSet that does not exist yet")
+		dependentCRSInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("dependent-clusterresourceset-%s", util.RandomString(6)),
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources: []addonsv1.ResourceRef{{Name: secretName, Kind: "Secret"}},
+				DependsOn: []string{clusterResourceSetName},
+			},
+		}
+		g.Expect(env.Create(ctx, dependentCRSInstance)).To(Succeed())
+		defer func() {
+			g.Expect(env.Delete(ctx, dependentCRSInstance)).To(Succeed())
+		}()
+
+		clusterResourceSetBindingKey := client.ObjectKey{
+			Namespace: testCluster.Namespace,
+			Name:      testCluster.Name,
+		}
+
+		t.Log("Verifying the ClusterResourceSet is blocked by its dependency")
+		g.Eventually(func() bool {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			if err := env.Get(ctx, clusterResourceSetBindingKey, binding); err != nil {
+				return false
+			}
+			return len(binding.Spec.Bindings) == 1 &&
+				len(binding.Spec.Bindings[0].BlockedBy) == 1 && binding.Spec.Bindings[0].BlockedBy[0] == clusterResourceSetName &&
+				!binding.Spec.Bindings[0].IsApplied(dependentCRSInstance.Spec.Resources[0])
+		}, timeout).Should(BeTrue())
+
+		t.Log("Creating the ClusterResourceSet instance of the dependency")
+		crsInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources: []addonsv1.ResourceRef{{Name: configmapName, Kind: "ConfigMap"}},
+			},
+		}
+		g.Expect(env.Create(ctx, crsInstance)).To(Succeed())
+
+		t.Log("Verifying the resources of both ClusterResourceSets are applied")
+		g.Eventually(func() bool {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			if err := env.Get(ctx, clusterResourceSetBindingKey, binding); err != nil {
+				return false
+			}
+			if len(binding.Spec.Bindings) != 2 {
+				return false
+			}
+			for _, resourceSetBinding := range binding.Spec.Bindings {
+				if len(resourceSetBinding.BlockedBy) != 0 || len(resourceSetBinding.Resources) != 1 || !resourceSetBinding.Resources[0].Applied {
+					return false
+				}
+			}
+			return true
+		}, timeout).Should(BeTrue())
+
+		t.Log("Deleting the Cluster")
+		g.Expect(env.Delete(ctx, testCluster)).To(Succeed())
+	})
+
 	t.Run("Should delete ClusterResourceSet from the bindings list when ClusterResourceSet is deleted", func(t *testing.T) {
 		g := NewWithT(t)
 		ns := setup(t, g)
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// helmNotesFileSuffix is the suffix of the template of the notes of a chart, which is not a Kubernetes object.
+const helmNotesFileSuffix = "NOTES.txt"
+
+// renderHelmChart downloads a Helm chart and renders it with its values the same way `helm template` does; the CRDs
+// of the chart come first, followed by the objects of the templates sorted by template name.
+// Namespaced objects that do not define a namespace are set to the namespace of the chart, using the RESTMapper of
+// the cluster the chart is going to be applied to.
+// Each element of the returned list is the YAML document of an object.
+func renderHelmChart(c client.Client, helmChart *addonsv1.HelmChart) ([][]byte, error) {
+	// Charts are downloaded to a temporary cache, so nothing is left behind in the filesystem of the controller.
+	cacheDir, err := os.MkdirTemp("", "clusterresourceset-helm-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the Helm cache directory")
+	}
+	defer os.RemoveAll(cacheDir)
+
+	settings := cli.New()
+	settings.RepositoryCache = cacheDir
+	settings.RepositoryConfig = filepath.Join(cacheDir, "repositories.yaml")
+
+	chartPathOptions := action.ChartPathOptions{
+		RepoURL: helmChart.RepositoryURL,
+		Version: helmChart.Version,
+	}
+	chartPath, err := chartPathOptions.LocateChart(helmChart.Chart, settings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download Helm chart %s", helmChart.Chart)
+	}
+
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load Helm chart %s", helmChart.Chart)
+	}
+
+	values, err := chartutil.ReadValues([]byte(helmChart.Values))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the values of Helm chart %s", helmChart.Name)
+	}
+
+	if err := chartutil.ProcessDependencies(chart, values); err != nil {
+		return nil, errors.Wrapf(err, "failed to process the dependencies of Helm chart %s", helmChart.Chart)
+	}
+
+	namespace := helmChart.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	releaseOptions := chartutil.ReleaseOptions{
+		Name:      helmChart.Name,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(chart, values, releaseOptions, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute the values of Helm chart %s", helmChart.Name)
+	}
+
+	templates, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render Helm chart %s", helmChart.Name)
+	}
+
+	manifests := [][]byte{}
+	for _, crd := range chart.CRDObjects() {
+		manifests = append(manifests, crd.File.Data)
+	}
+
+	// Templates are sorted by name, so the hash of the rendered chart does not change between renderings.
+	templateNames := make([]string, 0, len(templates))
+	for name, content := range templates {
+		if strings.HasSuffix(name, helmNotesFileSuffix) || strings.TrimSpace(content) == "" {
+			continue
+		}
+		templateNames = append(templateNames, name)
+	}
+	sort.Strings(templateNames)
+	for _, name := range templateNames {
+		manifests = append(manifests, []byte(templates[name]))
+	}
+
+	dataList := [][]byte{}
+	for _, manifest := range manifests {
+		objs, err := toUnstructured(manifest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the objects of Helm chart %s", helmChart.Name)
+		}
+
+		for i := range objs {
+			obj := &objs[i]
+			if obj.GetNamespace() == "" {
+				gvk := obj.GroupVersionKind()
+				mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+				if err != nil && !meta.IsNoMatchError(err) {
+					return nil, errors.Wrapf(err, "failed to get the REST mapping of %s", gvk)
+				}
+				// Objects of kinds not known by the cluster yet, e.g. defined by the CRDs of the chart, are left as is.
+				if err == nil && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+					obj.SetNamespace(namespace)
+				}
+			}
+
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to marshal the objects of Helm chart %s", helmChart.Name)
+			}
+			dataList = append(dataList, data)
+		}
+	}
+	return dataList, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// restMapperClient is a client.Client returning a custom RESTMapper, given that the fake client does not allow to set one.
+type restMapperClient struct {
+	client.Client
+	restMapper meta.RESTMapper
+}
+
+func (c restMapperClient) RESTMapper() meta.RESTMapper {
+	return c.restMapper
+}
+
+func TestRenderHelmChart(t *testing.T) {
+	g := NewWithT(t)
+
+	chartDir := t.TempDir()
+	chartFiles := map[string]string{
+		"Chart.yaml": `apiVersion: v2
+name: test-chart
+version: 0.1.0`,
+		"values.yaml":            `replicas: 1`,
+		"templates/NOTES.txt":    `Thank you for installing {{ .Chart.Name }}.`,
+		"templates/_helpers.tpl": `{{- define "test-chart.name" -}}{{ .Release.Name }}-config{{- end -}}`,
+		"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "test-chart.name" . }}
+data:
+  replicas: "{{ .Values.replicas }}"`,
+		"templates/namespace.yaml": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: {{ .Release.Namespace }}`,
+	}
+	for name, content := range chartFiles {
+		path := filepath.Join(chartDir, name)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	c := restMapperClient{Client: fake.NewClientBuilder().Build(), restMapper: restMapper}
+
+	dataList, err := renderHelmChart(c, &addonsv1.HelmChart{
+		Name:      "test",
+		Chart:     chartDir,
+		Version:   "0.1.0",
+		Namespace: "test-namespace",
+		Values:    `replicas: 3`,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dataList).To(HaveLen(2))
+
+	configMaps, err := toUnstructured(dataList[0])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(configMaps).To(HaveLen(1))
+	g.Expect(configMaps[0].GetName()).To(Equal("test-config"))
+	g.Expect(configMaps[0].GetNamespace()).To(Equal("test-namespace"))
+	g.Expect(configMaps[0].Object["data"]).To(Equal(map[string]interface{}{"replicas": "3"}))
+
+	namespaces, err := toUnstructured(dataList[1])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(namespaces).To(HaveLen(1))
+	g.Expect(namespaces[0].GetName()).To(Equal("test-namespace"))
+	g.Expect(namespaces[0].GetNamespace()).To(BeEmpty())
+}
@@ -21,8 +21,10 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"unicode"
 
 	"github.com/pkg/errors"
@@ -54,10 +56,11 @@ func isJSONList(data []byte) (bool, error) {
 	return bytes.HasPrefix(trim, jsonListPrefix), nil
 }
 
-func apply(ctx context.Context, c client.Client, data []byte) error {
+// toUnstructured converts the data, either a JSON list or JSON/YAML documents, into unstructured objects.
+func toUnstructured(data []byte) ([]unstructured.Unstructured, error) {
 	isJSONList, err := isJSONList(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	objs := []unstructured.Unstructured{}
 	// If it is a json list, convert each list element to an unstructured object.
@@ -75,21 +78,55 @@ func apply(ctx context.Context, c client.Client, data []byte) error {
 		// If it is not a json list, data is either json or yaml format.
 		objs, err = utilyaml.ToUnstructured(data)
 		if err != nil {
-			return errors.Wrapf(err, "failed converting data to unstructured objects")
+			return nil, errors.Wrapf(err, "failed converting data to unstructured objects")
 		}
 	}
+	return objs, nil
+}
+
+// apply converts the data into objects and applies each of them to the cluster using applyFn.
+func apply(ctx context.Context, c client.Client, data []byte, applyFn func(context.Context, client.Client, *unstructured.Unstructured) error) error {
+	objs, err := toUnstructured(data)
+	if err != nil {
+		return err
+	}
 
 	errList := []error{}
 	sortedObjs := utilresource.SortForCreate(objs)
 	for i := range sortedObjs {
-		if err := applyUnstructured(ctx, c, &objs[i]); err != nil {
+		if err := applyFn(ctx, c, &objs[i]); err != nil {
 			errList = append(errList, err)
 		}
 	}
 	return kerrors.NewAggregate(errList)
 }
 
-func applyUnstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+// deleteObjects converts the data into objects and deletes each of them from the cluster, in the reverse order
+// they are created; objects that do not exist are ignored.
+func deleteObjects(ctx context.Context, c client.Client, data []byte) error {
+	objs, err := toUnstructured(data)
+	if err != nil {
+		return err
+	}
+
+	errList := []error{}
+	sortedObjs := utilresource.SortForCreate(objs)
+	for i := len(sortedObjs) - 1; i >= 0; i-- {
+		obj := &sortedObjs[i]
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errList = append(errList, errors.Wrapf(
+				err,
+				"failed to delete object %s %s/%s",
+				obj.GroupVersionKind(),
+				obj.GetNamespace(),
+				obj.GetName()))
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// createUnstructured creates the object in the cluster; objects that already exist are left untouched.
+func createUnstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
 	// Create the object on the API server.
 	// TODO: Errors are only logged. If needed, exponential backoff or requeuing could be used here for remedying connection glitches etc.
 	if err := c.Create(ctx, obj); err != nil {
@@ -107,6 +144,35 @@ func applyUnstructured(ctx context.Context, c client.Client, obj *unstructured.U
 	return nil
 }
 
+// createOrPatchUnstructured creates the object in the cluster, or patches the existing object so the fields
+// defined in obj override the ones in the cluster.
+func createOrPatchUnstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return createUnstructured(ctx, c, obj)
+		}
+		return errors.Wrapf(
+			err,
+			"failed to get object %s %s/%s",
+			obj.GroupVersionKind(),
+			obj.GetNamespace(),
+			obj.GetName())
+	}
+
+	// A merge patch with the desired object is a no-op if the existing object did not drift.
+	if err := c.Patch(ctx, obj, client.Merge); err != nil {
+		return errors.Wrapf(
+			err,
+			"failed to patch object %s %s/%s",
+			obj.GroupVersionKind(),
+			obj.GetNamespace(),
+			obj.GetName())
+	}
+	return nil
+}
+
 // getOrCreateClusterResourceSetBinding retrieves ClusterResourceSetBinding resource owned by the cluster or create a new one if not found.
 func (r *ClusterResourceSetReconciler) getOrCreateClusterResourceSetBinding(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) (*addonsv1.ClusterResourceSetBinding, error) {
 	clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
@@ -145,6 +211,74 @@ func (r *ClusterResourceSetReconciler) getOrCreateClusterResourceSetBinding(ctx
 	return clusterResourceSetBinding, nil
 }
 
+// normalizeData returns the values in the data field of a resource, ordered by key; values of Secrets are decoded.
+func normalizeData(resource *unstructured.Unstructured) ([][]byte, error) {
+	// Since maps are not ordered, we need to order them to get the same hash at each reconcile.
+	keys := make([]string, 0)
+	data, ok := resource.UnstructuredContent()["data"]
+	if !ok {
+		return nil, errors.New("failed to get data field from the resource")
+	}
+
+	unstructuredData := data.(map[string]interface{})
+	for key := range unstructuredData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dataList := make([][]byte, 0)
+	for _, key := range keys {
+		val, ok, err := unstructured.NestedString(unstructuredData, key)
+		if !ok || err != nil {
+			return nil, errors.New("failed to get value field from the resource")
+		}
+
+		byteArr := []byte(val)
+		// If the resource is a Secret, data needs to be decoded.
+		if resource.GetKind() == string(addonsv1.SecretClusterResourceSetResourceKind) {
+			byteArr, _ = base64.StdEncoding.DecodeString(val)
+		}
+
+		dataList = append(dataList, byteArr)
+	}
+	return dataList, nil
+}
+
+// isBound returns true if the ClusterResourceSet is in the bindings of the ClusterResourceSetBinding.
+func isBound(clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, clusterResourceSet *addonsv1.ClusterResourceSet) bool {
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		if binding.ClusterResourceSetName == clusterResourceSet.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// isApplied returns true if the ClusterResourceSet is bound to the cluster, it is not waiting for its dependencies and
+// all of its resources and Helm charts are applied.
+func isApplied(clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, clusterResourceSet *addonsv1.ClusterResourceSet) bool {
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		if binding.ClusterResourceSetName != clusterResourceSet.Name {
+			continue
+		}
+		if len(binding.BlockedBy) > 0 {
+			return false
+		}
+		for _, resource := range clusterResourceSet.Spec.Resources {
+			if !binding.IsApplied(resource) {
+				return false
+			}
+		}
+		for i := range clusterResourceSet.Spec.HelmCharts {
+			if !binding.IsApplied(clusterResourceSet.Spec.HelmCharts[i].ResourceRef()) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 // getConfigMap retrieves any ConfigMap from the given name and namespace.
 func getConfigMap(ctx context.Context, c client.Client, configmapName types.NamespacedName) (*corev1.ConfigMap, error) {
 	configMap := &corev1.ConfigMap{}
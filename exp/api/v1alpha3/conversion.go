@@ -18,13 +18,54 @@ package v1alpha3
 
 import (
 	"k8s.io/apimachinery/pkg/conversion"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
 	v1beta1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // Convert_v1alpha3_MachinePoolSpec_To_v1beta1_MachinePoolSpec is an autogenerated conversion function.
 func Convert_v1alpha3_MachinePoolSpec_To_v1beta1_MachinePoolSpec(in *MachinePoolSpec, out *v1beta1.MachinePoolSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha3_MachinePoolSpec_To_v1beta1_MachinePoolSpec(in, out, s)
+	if err := autoConvert_v1alpha3_MachinePoolSpec_To_v1beta1_MachinePoolSpec(in, out, s); err != nil {
+		return err
+	}
+
+	// spec.strategy has been re-introduced with v1beta1 using a MachinePool specific type; only the fields
+	// supported by MachinePools are preserved.
+	if in.Strategy != nil {
+		out.Strategy = &v1beta1.MachinePoolStrategy{
+			Type: v1beta1.MachinePoolStrategyType(in.Strategy.Type),
+		}
+		if in.Strategy.RollingUpdate != nil {
+			out.Strategy.RollingUpdate = &v1beta1.MachinePoolRollingUpdate{
+				MaxUnavailable: in.Strategy.RollingUpdate.MaxUnavailable,
+			}
+		}
+	}
+	return nil
+}
+
+func Convert_v1beta1_MachinePoolSpec_To_v1alpha3_MachinePoolSpec(in *v1beta1.MachinePoolSpec, out *MachinePoolSpec, s conversion.Scope) error {
+	if err := autoConvert_v1beta1_MachinePoolSpec_To_v1alpha3_MachinePoolSpec(in, out, s); err != nil {
+		return err
+	}
+
+	// The Recreate strategy does not exist in v1alpha3.
+	if in.Strategy != nil && in.Strategy.Type != v1beta1.RecreateMachinePoolStrategyType {
+		out.Strategy = &clusterv1.MachineDeploymentStrategy{
+			Type: clusterv1.MachineDeploymentStrategyType(in.Strategy.Type),
+		}
+		if in.Strategy.RollingUpdate != nil {
+			out.Strategy.RollingUpdate = &clusterv1.MachineRollingUpdateDeployment{
+				MaxUnavailable: in.Strategy.RollingUpdate.MaxUnavailable,
+			}
+		}
+	}
+	return nil
+}
+
+func Convert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in *v1beta1.MachinePoolStatus, out *MachinePoolStatus, s conversion.Scope) error {
+	// status.updatedReplicas has been added with v1beta1.
+	return autoConvert_v1beta1_MachinePoolStatus_To_v1alpha3_MachinePoolStatus(in, out, s)
 }
 
 func Convert_v1alpha3_MachinePool_To_v1beta1_MachinePool(in *MachinePool, out *v1beta1.MachinePool, s conversion.Scope) error {
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ClusterSetNameLabel is the label set on the Clusters created by a ClusterSet, with the name of the ClusterSet.
+	ClusterSetNameLabel = "cluster.x-k8s.io/cluster-set-name"
+
+	// ClusterSetIndexLabel is the label set on the Clusters created by a ClusterSet, with the index of the Cluster
+	// in the ClusterSet.
+	ClusterSetIndexLabel = "cluster.x-k8s.io/cluster-set-index"
+
+	// DefaultClusterSetNamePattern is the default name pattern of the Clusters created by a ClusterSet.
+	DefaultClusterSetNamePattern = "{{ .clusterSet.name }}-{{ .index }}"
+)
+
+// ANCHOR: ClusterSetSpec
+
+// ClusterSetSpec defines the desired state of ClusterSet.
+type ClusterSetSpec struct {
+	// Number of desired Clusters. Defaults to 1.
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// NamePattern is the Go template used to generate the names of the Clusters; .clusterSet.name is the name of the
+	// ClusterSet and .index is the index of the Cluster in the ClusterSet, starting from 0.
+	// Defaults to "{{ .clusterSet.name }}-{{ .index }}". This field is immutable.
+	// +optional
+	NamePattern string `json:"namePattern,omitempty"`
+
+	// Template describes the Clusters that will be created; the Clusters must use a managed topology.
+	Template ClusterSetTemplate `json:"template"`
+
+	// Overrides is a list of variables overriding the ones of the template for specific Clusters.
+	// +optional
+	Overrides []ClusterSetOverride `json:"overrides,omitempty"`
+}
+
+// ANCHOR_END: ClusterSetSpec
+
+// ClusterSetTemplate describes the Clusters created by a ClusterSet.
+type ClusterSetTemplate struct {
+	// Standard object's metadata; labels and annotations are added to the Clusters.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	ObjectMeta clusterv1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the Clusters.
+	Spec clusterv1.ClusterSpec `json:"spec"`
+}
+
+// ClusterSetOverride defines the variables overriding the ones of the template for a Cluster of the ClusterSet.
+type ClusterSetOverride struct {
+	// Index of the Cluster in the ClusterSet.
+	// +kubebuilder:validation:Minimum=0
+	Index int32 `json:"index"`
+
+	// Variables replace the variables of the template with the same name, and are added to them otherwise.
+	Variables []clusterv1.ClusterVariable `json:"variables"`
+}
+
+// ANCHOR: ClusterSetStatus
+
+// ClusterSetStatus defines the observed state of ClusterSet.
+type ClusterSetStatus struct {
+	// Replicas is the most recently observed number of Clusters.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// The number of Clusters of the ClusterSet with the Ready condition set to true.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current service state of the ClusterSet.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ANCHOR_END: ClusterSetStatus
+
+// ClusterName returns the name of the Cluster with the given index, generated from the name pattern.
+func (s *ClusterSet) ClusterName(index int32) (string, error) {
+	namePattern := s.Spec.NamePattern
+	if namePattern == "" {
+		namePattern = DefaultClusterSetNamePattern
+	}
+
+	tpl, err := template.New("name").Option("missingkey=error").Parse(namePattern)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the name pattern")
+	}
+
+	var buf bytes.Buffer
+	data := map[string]interface{}{
+		"clusterSet": map[string]interface{}{
+			"name": s.Name,
+		},
+		"index": index,
+	}
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", errors.Wrap(err, "failed to render the name pattern")
+	}
+	return buf.String(), nil
+}
+
+// GetOverride returns the override for the Cluster with the given index if it exists, nil otherwise.
+func (s *ClusterSet) GetOverride(index int32) *ClusterSetOverride {
+	for i := range s.Spec.Overrides {
+		if s.Spec.Overrides[i].Index == index {
+			return &s.Spec.Overrides[i]
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clustersets,shortName=cs,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ClusterSet"
+// +kubebuilder:printcolumn:name="Replicas",type="string",JSONPath=".status.replicas",description="ClusterSet replicas count"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyReplicas",description="ClusterSet ready replicas count"
+// +kubebuilder:printcolumn:name="Class",type="string",JSONPath=".spec.template.spec.topology.class",description="ClusterClass of the Clusters of the ClusterSet"
+// +k8s:conversion-gen=false
+
+// ClusterSet is the Schema for the clustersets API.
+type ClusterSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSetSpec   `json:"spec,omitempty"`
+	Status ClusterSetStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (s *ClusterSet) GetConditions() clusterv1.Conditions {
+	return s.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (s *ClusterSet) SetConditions(conditions clusterv1.Conditions) {
+	s.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSetList contains a list of ClusterSet.
+type ClusterSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSet{}, &ClusterSetList{})
+}
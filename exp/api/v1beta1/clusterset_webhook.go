@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/cluster-api/feature"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (s *ClusterSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(s).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1beta1-clusterset,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clustersets,versions=v1beta1,name=validation.clusterset.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-cluster-x-k8s-io-v1beta1-clusterset,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clustersets,versions=v1beta1,name=default.clusterset.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.Defaulter = &ClusterSet{}
+var _ webhook.Validator = &ClusterSet{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (s *ClusterSet) Default() {
+	if s.Spec.Replicas == nil {
+		s.Spec.Replicas = pointer.Int32Ptr(1)
+	}
+
+	if s.Spec.NamePattern == "" {
+		s.Spec.NamePattern = DefaultClusterSetNamePattern
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (s *ClusterSet) ValidateCreate() error {
+	return s.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (s *ClusterSet) ValidateUpdate(old runtime.Object) error {
+	oldCS, ok := old.(*ClusterSet)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterSet but got a %T", old))
+	}
+	return s.validate(oldCS)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (s *ClusterSet) ValidateDelete() error {
+	return nil
+}
+
+func (s *ClusterSet) validate(old *ClusterSet) error {
+	// NOTE: ClusterSets create Clusters with managed topologies, which are behind the ClusterTopology feature gate
+	// flag; the web hook must prevent the creation of ClusterSets in case the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.ClusterTopology) {
+		return apierrors.NewInvalid(GroupVersion.WithKind("ClusterSet").GroupKind(), s.Name, field.ErrorList{
+			field.Forbidden(
+				field.NewPath("spec"),
+				"can be set only if the ClusterTopology feature flag is enabled",
+			),
+		})
+	}
+
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if s.Spec.Replicas != nil && *s.Spec.Replicas < 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(specPath.Child("replicas"), *s.Spec.Replicas, "must be greater than or equal to 0"),
+		)
+	}
+
+	// The name pattern must generate valid names, which are different for each Cluster.
+	firstName, err := s.ClusterName(0)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("namePattern"), s.Spec.NamePattern, err.Error()))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(firstName) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("namePattern"), s.Spec.NamePattern, msg))
+		}
+		if secondName, err := s.ClusterName(1); err == nil && secondName == firstName {
+			allErrs = append(
+				allErrs,
+				field.Invalid(specPath.Child("namePattern"), s.Spec.NamePattern, "must generate a different name for each Cluster using .index"),
+			)
+		}
+	}
+
+	if old != nil && old.Spec.NamePattern != s.Spec.NamePattern {
+		allErrs = append(
+			allErrs,
+			field.Invalid(specPath.Child("namePattern"), s.Spec.NamePattern, "field is immutable"),
+		)
+	}
+
+	templateSpecPath := specPath.Child("template", "spec")
+	if s.Spec.Template.Spec.Topology == nil {
+		allErrs = append(allErrs, field.Required(templateSpecPath.Child("topology"), "Clusters of a ClusterSet must use a managed topology"))
+	}
+	if s.Spec.Template.Spec.ControlPlaneRef != nil {
+		allErrs = append(allErrs, field.Forbidden(templateSpecPath.Child("controlPlaneRef"), "is set by the topology controller"))
+	}
+	if s.Spec.Template.Spec.InfrastructureRef != nil {
+		allErrs = append(allErrs, field.Forbidden(templateSpecPath.Child("infrastructureRef"), "is set by the topology controller"))
+	}
+
+	indexes := map[int32]bool{}
+	for i, override := range s.Spec.Overrides {
+		if indexes[override.Index] {
+			allErrs = append(allErrs, field.Duplicate(specPath.Child("overrides").Index(i).Child("index"), override.Index))
+		}
+		indexes[override.Index] = true
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ClusterSet").GroupKind(), s.Name, allErrs)
+}
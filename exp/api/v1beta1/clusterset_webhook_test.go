@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func TestClusterSetDefault(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &ClusterSet{}
+	s.Default()
+
+	g.Expect(s.Spec.Replicas).To(Equal(pointer.Int32Ptr(1)))
+	g.Expect(s.Spec.NamePattern).To(Equal(DefaultClusterSetNamePattern))
+}
+
+func TestClusterSetClusterName(t *testing.T) {
+	tests := []struct {
+		name        string
+		namePattern string
+		index       int32
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:  "should use the default name pattern if not set",
+			index: 3,
+			want:  "fleet-3",
+		},
+		{
+			name:        "should use the name pattern",
+			namePattern: "edge-{{ .index }}-{{ .clusterSet.name }}",
+			index:       3,
+			want:        "edge-3-fleet",
+		},
+		{
+			name:        "should return error if the name pattern references unknown variables",
+			namePattern: "{{ .foo }}-{{ .index }}",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s := &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec:       ClusterSetSpec{NamePattern: tt.namePattern},
+			}
+			got, err := s.ClusterName(tt.index)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestClusterSetValidation(t *testing.T) {
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to create ClusterSets.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	validTemplate := ClusterSetTemplate{
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{
+				Class:   "foo",
+				Version: "v1.22.2",
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		in        *ClusterSet
+		old       *ClusterSet
+		expectErr bool
+	}{
+		{
+			name: "should succeed with a valid ClusterSet",
+			in: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					Replicas:  pointer.Int32Ptr(3),
+					Template:  validTemplate,
+					Overrides: []ClusterSetOverride{{Index: 0}, {Index: 2}},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error for negative replicas",
+			in: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					Replicas: pointer.Int32Ptr(-1),
+					Template: validTemplate,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if the name pattern does not use the index",
+			in: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					NamePattern: "{{ .clusterSet.name }}",
+					Template:    validTemplate,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if the name pattern generates invalid names",
+			in: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					NamePattern: "{{ .clusterSet.name }}_{{ .index }}",
+					Template:    validTemplate,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if the name pattern changes",
+			in: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					NamePattern: "edge-{{ .index }}",
+					Template:    validTemplate,
+				},
+			},
+			old: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					NamePattern: DefaultClusterSetNamePattern,
+					Template:    validTemplate,
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if the template does not have a topology",
+			in: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					Template: ClusterSetTemplate{
+						Spec: clusterv1.ClusterSpec{
+							InfrastructureRef: &corev1.ObjectReference{Name: "foo"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for duplicated overrides",
+			in: &ClusterSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+				Spec: ClusterSetSpec{
+					Template:  validTemplate,
+					Overrides: []ClusterSetOverride{{Index: 1}, {Index: 1}},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var err error
+			if tt.old == nil {
+				err = tt.in.ValidateCreate()
+			} else {
+				err = tt.in.ValidateUpdate(tt.old)
+			}
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClusterSetValidationFeatureGateDisabled(t *testing.T) {
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to create ClusterSets.
+	g := NewWithT(t)
+
+	s := &ClusterSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+		Spec: ClusterSetSpec{
+			Template: ClusterSetTemplate{
+				Spec: clusterv1.ClusterSpec{
+					Topology: &clusterv1.Topology{Class: "foo", Version: "v1.22.2"},
+				},
+			},
+		},
+	}
+	g.Expect(s.ValidateCreate()).NotTo(Succeed())
+}
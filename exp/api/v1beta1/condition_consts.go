@@ -28,3 +28,37 @@ const (
 	// to be ready.
 	WaitingForReplicasReadyReason = "WaitingForReplicasReady"
 )
+
+// Conditions and condition Reasons for the ClusterSet object
+
+const (
+	// ClustersReconciledCondition documents that the Clusters of a ClusterSet have been created, updated or deleted
+	// according to the ClusterSet spec.
+	ClustersReconciledCondition clusterv1.ConditionType = "ClustersReconciled"
+
+	// ReconcileClustersFailedReason (Severity=Warning) documents a ClusterSet failing to create, update or delete
+	// at least one of its Clusters.
+	ReconcileClustersFailedReason = "ReconcileClustersFailed"
+)
+
+// Conditions and condition Reasons for the HostClaim object
+
+const (
+	// HostBoundCondition documents that a host has been bound to the HostClaim.
+	HostBoundCondition clusterv1.ConditionType = "HostBound"
+
+	// WaitingForHostReason (Severity=Info) documents a HostClaim waiting for a host matching its selector
+	// to be available.
+	WaitingForHostReason = "WaitingForHost"
+
+	// HostNotFoundReason (Severity=Error) documents a HostClaim whose bound host has been deleted.
+	HostNotFoundReason = "HostNotFound"
+
+	// HostClaimConflictReason (Severity=Error) documents a HostClaim whose bound host has been claimed
+	// by another HostClaim.
+	HostClaimConflictReason = "HostClaimConflict"
+
+	// HostReleasedReason (Severity=Info) documents a HostClaim whose host has been released because
+	// the Machine it was claimed for has been deleted.
+	HostReleasedReason = "HostReleased"
+)
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// HostClaimFinalizer is used to release the host bound to a HostClaim before removing it from the API server.
+	HostClaimFinalizer = "hostclaim.cluster.x-k8s.io"
+
+	// HostClaimAnnotation is the annotation set on the hosts bound to a HostClaim, with the namespace/name of the
+	// HostClaim; a host with this annotation is not available to other HostClaims.
+	HostClaimAnnotation = "cluster.x-k8s.io/host-claim"
+)
+
+// ANCHOR: HostClaimSpec
+
+// HostClaimSpec defines the desired state of HostClaim.
+type HostClaimSpec struct {
+	// ClusterName is the name of the Cluster this object belongs to.
+	// +kubebuilder:validation:MinLength=1
+	ClusterName string `json:"clusterName"`
+
+	// MachineName is the name of the Machine the host is claimed for; the host is released when the Machine
+	// is deleted.
+	// +kubebuilder:validation:MinLength=1
+	MachineName string `json:"machineName"`
+
+	// HostAPIVersion is the API version of the pre-registered host objects that can be bound to the HostClaim.
+	// +kubebuilder:validation:MinLength=1
+	HostAPIVersion string `json:"hostAPIVersion"`
+
+	// HostKind is the kind of the pre-registered host objects that can be bound to the HostClaim.
+	// +kubebuilder:validation:MinLength=1
+	HostKind string `json:"hostKind"`
+
+	// Selector is a label query over the hosts, in the namespace of the HostClaim, that can be bound to it.
+	// An empty selector matches all the hosts.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ANCHOR_END: HostClaimSpec
+
+// ANCHOR: HostClaimStatus
+
+// HostClaimStatus defines the observed state of HostClaim.
+type HostClaimStatus struct {
+	// HostRef is a reference to the host bound to the HostClaim.
+	// +optional
+	HostRef *corev1.ObjectReference `json:"hostRef,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current service state of the HostClaim.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ANCHOR_END: HostClaimStatus
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=hostclaims,shortName=hc,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster"
+// +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".spec.machineName",description="Machine the host is claimed for"
+// +kubebuilder:printcolumn:name="Host",type="string",JSONPath=".status.hostRef.name",description="Host bound to the HostClaim"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of HostClaim"
+// +k8s:conversion-gen=false
+
+// HostClaim is the Schema for the hostclaims API.
+type HostClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HostClaimSpec   `json:"spec,omitempty"`
+	Status HostClaimStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (c *HostClaim) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (c *HostClaim) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
+// ClaimKey returns the value of the HostClaimAnnotation set on the hosts bound to the HostClaim.
+func (c *HostClaim) ClaimKey() string {
+	return c.Namespace + "/" + c.Name
+}
+
+// +kubebuilder:object:root=true
+
+// HostClaimList contains a list of HostClaim.
+type HostClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HostClaim{}, &HostClaimList{})
+}
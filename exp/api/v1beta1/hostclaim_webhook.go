@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (c *HostClaim) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(c).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1beta1-hostclaim,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=hostclaims,versions=v1beta1,name=validation.hostclaim.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-cluster-x-k8s-io-v1beta1-hostclaim,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=hostclaims,versions=v1beta1,name=default.hostclaim.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.Defaulter = &HostClaim{}
+var _ webhook.Validator = &HostClaim{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (c *HostClaim) Default() {
+	if c.Labels == nil {
+		c.Labels = make(map[string]string)
+	}
+	c.Labels[clusterv1.ClusterLabelName] = c.Spec.ClusterName
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (c *HostClaim) ValidateCreate() error {
+	return c.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (c *HostClaim) ValidateUpdate(old runtime.Object) error {
+	oldHC, ok := old.(*HostClaim)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a HostClaim but got a %T", old))
+	}
+	return c.validate(oldHC)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (c *HostClaim) ValidateDelete() error {
+	return nil
+}
+
+func (c *HostClaim) validate(old *HostClaim) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if _, err := schema.ParseGroupVersion(c.Spec.HostAPIVersion); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("hostAPIVersion"), c.Spec.HostAPIVersion, err.Error()))
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(&c.Spec.Selector); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("selector"), c.Spec.Selector, err.Error()))
+	}
+
+	// The spec of a HostClaim is immutable, given that the bound host depends on it.
+	if old != nil {
+		if old.Spec.ClusterName != c.Spec.ClusterName {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("clusterName"), "field is immutable"))
+		}
+		if old.Spec.MachineName != c.Spec.MachineName {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("machineName"), "field is immutable"))
+		}
+		if old.Spec.HostAPIVersion != c.Spec.HostAPIVersion {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("hostAPIVersion"), "field is immutable"))
+		}
+		if old.Spec.HostKind != c.Spec.HostKind {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("hostKind"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.Selector, c.Spec.Selector) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("selector"), "field is immutable"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("HostClaim").GroupKind(), c.Name, allErrs)
+}
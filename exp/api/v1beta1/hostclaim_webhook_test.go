@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestHostClaimDefault(t *testing.T) {
+	g := NewWithT(t)
+
+	c := &HostClaim{
+		Spec: HostClaimSpec{
+			ClusterName: "test-cluster",
+		},
+	}
+	c.Default()
+
+	g.Expect(c.Labels[clusterv1.ClusterLabelName]).To(Equal(c.Spec.ClusterName))
+}
+
+func TestHostClaimValidation(t *testing.T) {
+	validSpec := func() HostClaimSpec {
+		return HostClaimSpec{
+			ClusterName:    "test-cluster",
+			MachineName:    "test-machine",
+			HostAPIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+			HostKind:       "Host",
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"rack": "a"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		old       *HostClaimSpec
+		spec      func(s *HostClaimSpec)
+		expectErr bool
+	}{
+		{
+			name:      "should accept a valid HostClaim",
+			spec:      func(s *HostClaimSpec) {},
+			expectErr: false,
+		},
+		{
+			name: "should reject an invalid host API version",
+			spec: func(s *HostClaimSpec) {
+				s.HostAPIVersion = "infrastructure.cluster.x-k8s.io/v1beta1/foo"
+			},
+			expectErr: true,
+		},
+		{
+			name: "should reject an invalid selector",
+			spec: func(s *HostClaimSpec) {
+				s.Selector = metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "rack", Operator: "Foo"},
+					},
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name:      "should accept an update not changing the spec",
+			old:       func() *HostClaimSpec { s := validSpec(); return &s }(),
+			spec:      func(s *HostClaimSpec) {},
+			expectErr: false,
+		},
+		{
+			name: "should reject changing the machine name",
+			old:  func() *HostClaimSpec { s := validSpec(); return &s }(),
+			spec: func(s *HostClaimSpec) {
+				s.MachineName = "another-machine"
+			},
+			expectErr: true,
+		},
+		{
+			name: "should reject changing the host kind",
+			old:  func() *HostClaimSpec { s := validSpec(); return &s }(),
+			spec: func(s *HostClaimSpec) {
+				s.HostKind = "AnotherHost"
+			},
+			expectErr: true,
+		},
+		{
+			name: "should reject changing the selector",
+			old:  func() *HostClaimSpec { s := validSpec(); return &s }(),
+			spec: func(s *HostClaimSpec) {
+				s.Selector.MatchLabels = map[string]string{"rack": "b"}
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &HostClaim{Spec: validSpec()}
+			tt.spec(&c.Spec)
+
+			var err error
+			if tt.old == nil {
+				err = c.ValidateCreate()
+			} else {
+				err = c.ValidateUpdate(&HostClaim{Spec: *tt.old})
+			}
+
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 )
@@ -43,6 +44,12 @@ type MachinePoolSpec struct {
 	// Template describes the machines that will be created.
 	Template clusterv1.MachineTemplateSpec `json:"template"`
 
+	// Strategy defines how the infrastructure provider replaces the existing machine instances with new ones
+	// when the Kubernetes version or the infrastructure template changes, if supported by the provider.
+	// If not set, the infrastructure provider replaces the machine instances according to its default behavior.
+	// +optional
+	Strategy *MachinePoolStrategy `json:"strategy,omitempty"`
+
 	// Minimum number of seconds for which a newly created machine instances should
 	// be ready.
 	// Defaults to 0 (machine instance will be considered available as soon as it
@@ -61,6 +68,48 @@ type MachinePoolSpec struct {
 
 // ANCHOR_END: MachinePoolSpec
 
+// MachinePoolStrategyType defines the type of MachinePool rollout strategies.
+type MachinePoolStrategyType string
+
+const (
+	// RollingUpdateMachinePoolStrategyType replaces the outdated machine instances gradually, keeping the number
+	// of unavailable machine instances within the limits defined by the rolling update config params.
+	RollingUpdateMachinePoolStrategyType MachinePoolStrategyType = "RollingUpdate"
+
+	// RecreateMachinePoolStrategyType replaces all the outdated machine instances at once.
+	RecreateMachinePoolStrategyType MachinePoolStrategyType = "Recreate"
+)
+
+// ANCHOR: MachinePoolStrategy
+
+// MachinePoolStrategy describes how to replace existing machine instances with new ones.
+type MachinePoolStrategy struct {
+	// Type of rollout.
+	// Default is RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate;Recreate
+	// +optional
+	Type MachinePoolStrategyType `json:"type,omitempty"`
+
+	// Rolling update config params. Present only if
+	// MachinePoolStrategyType = RollingUpdate.
+	// +optional
+	RollingUpdate *MachinePoolRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// ANCHOR_END: MachinePoolStrategy
+
+// MachinePoolRollingUpdate is used to control the desired behavior of rolling update.
+type MachinePoolRollingUpdate struct {
+	// The maximum number of machine instances that can be unavailable during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of desired
+	// machine instances (ex: 10%).
+	// Absolute number is calculated from percentage by rounding down, but at least one
+	// machine instance is replaced at a time.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
 // ANCHOR: MachinePoolStatus
 
 // MachinePoolStatus defines the observed state of MachinePool.
@@ -89,6 +138,12 @@ type MachinePoolStatus struct {
 	// +optional
 	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty"`
 
+	// The number of machine instances running the desired Kubernetes version and infrastructure template,
+	// as reported by the infrastructure provider; the remaining replicas are outdated and are going to be
+	// replaced according to the rollout strategy.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
 	// FailureReason indicates that there is a problem reconciling the state, and
 	// will be set to a token value suitable for programmatic interpretation.
 	// +optional
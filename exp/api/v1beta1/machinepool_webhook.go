@@ -23,6 +23,7 @@ import (
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -63,6 +64,23 @@ func (m *MachinePool) Default() {
 	if len(m.Spec.Template.Spec.InfrastructureRef.Namespace) == 0 {
 		m.Spec.Template.Spec.InfrastructureRef.Namespace = m.Namespace
 	}
+
+	if m.Spec.Strategy != nil {
+		if m.Spec.Strategy.Type == "" {
+			m.Spec.Strategy.Type = RollingUpdateMachinePoolStrategyType
+		}
+
+		// Default RollingUpdate strategy only if strategy type is RollingUpdate.
+		if m.Spec.Strategy.Type == RollingUpdateMachinePoolStrategyType {
+			if m.Spec.Strategy.RollingUpdate == nil {
+				m.Spec.Strategy.RollingUpdate = &MachinePoolRollingUpdate{}
+			}
+			if m.Spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+				ios1 := intstr.FromInt(1)
+				m.Spec.Strategy.RollingUpdate.MaxUnavailable = &ios1
+			}
+		}
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -118,6 +136,14 @@ func (m *MachinePool) validate(old *MachinePool) error {
 		)
 	}
 
+	if m.Spec.Strategy != nil {
+		total := 1
+		if m.Spec.Replicas != nil {
+			total = int(*m.Spec.Replicas)
+		}
+		allErrs = append(allErrs, m.Spec.Strategy.validate(total, field.NewPath("spec", "strategy"))...)
+	}
+
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
@@ -130,3 +156,39 @@ func (m *MachinePool) validate(old *MachinePool) error {
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("MachinePool").GroupKind(), m.Name, allErrs)
 }
+
+// validate validates a MachinePoolStrategy; total is the number of replicas used to scale
+// maxUnavailable if it is defined as a percentage.
+func (s *MachinePoolStrategy) validate(total int, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if s.RollingUpdate == nil {
+		return allErrs
+	}
+
+	if s.Type == RecreateMachinePoolStrategyType {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(fldPath.Child("rollingUpdate"), "cannot be set when strategy type is Recreate"),
+		)
+	}
+
+	if s.RollingUpdate.MaxUnavailable != nil {
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(s.RollingUpdate.MaxUnavailable, total, false)
+		if err != nil {
+			allErrs = append(
+				allErrs,
+				field.Invalid(fldPath.Child("rollingUpdate", "maxUnavailable"),
+					s.RollingUpdate.MaxUnavailable, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())),
+			)
+		} else if maxUnavailable < 0 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(fldPath.Child("rollingUpdate", "maxUnavailable"),
+					s.RollingUpdate.MaxUnavailable, "must be greater than or equal to 0"),
+			)
+		}
+	}
+
+	return allErrs
+}
@@ -23,6 +23,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
@@ -51,6 +52,31 @@ func TestMachinePoolDefault(t *testing.T) {
 	g.Expect(m.Spec.MinReadySeconds).To(Equal(pointer.Int32Ptr(0)))
 	g.Expect(m.Spec.Template.Spec.Bootstrap.ConfigRef.Namespace).To(Equal(m.Namespace))
 	g.Expect(m.Spec.Template.Spec.InfrastructureRef.Namespace).To(Equal(m.Namespace))
+	g.Expect(m.Spec.Strategy).To(BeNil())
+}
+
+func TestMachinePoolDefaultStrategy(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foobar",
+		},
+		Spec: MachinePoolSpec{
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{ConfigRef: &corev1.ObjectReference{}},
+				},
+			},
+			Strategy: &MachinePoolStrategy{},
+		},
+	}
+	t.Run("for MachinePool", utildefaulting.DefaultValidateTest(m))
+	m.Default()
+
+	g.Expect(m.Spec.Strategy.Type).To(Equal(RollingUpdateMachinePoolStrategyType))
+	g.Expect(m.Spec.Strategy.RollingUpdate).NotTo(BeNil())
+	g.Expect(m.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue()).To(Equal(1))
 }
 
 func TestMachinePoolBootstrapValidation(t *testing.T) {
@@ -219,3 +245,86 @@ func TestMachinePoolClusterNameImmutable(t *testing.T) {
 		})
 	}
 }
+
+func TestMachinePoolStrategyValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  *MachinePoolStrategy
+		expectErr bool
+	}{
+		{
+			name:      "should succeed if strategy is not set",
+			strategy:  nil,
+			expectErr: false,
+		},
+		{
+			name: "should succeed if maxUnavailable is an int",
+			strategy: &MachinePoolStrategy{
+				Type:          RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{MaxUnavailable: intOrStrPtr(intstr.FromInt(2))},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should succeed if maxUnavailable is a percentage",
+			strategy: &MachinePoolStrategy{
+				Type:          RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{MaxUnavailable: intOrStrPtr(intstr.FromString("25%"))},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error if maxUnavailable is not a valid percentage",
+			strategy: &MachinePoolStrategy{
+				Type:          RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{MaxUnavailable: intOrStrPtr(intstr.FromString("foo"))},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if maxUnavailable is negative",
+			strategy: &MachinePoolStrategy{
+				Type:          RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{MaxUnavailable: intOrStrPtr(intstr.FromInt(-1))},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error if rollingUpdate is set with the Recreate strategy type",
+			strategy: &MachinePoolStrategy{
+				Type:          RecreateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{MaxUnavailable: intOrStrPtr(intstr.FromInt(1))},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachinePool{
+				Spec: MachinePoolSpec{
+					Replicas: pointer.Int32Ptr(4),
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Bootstrap: clusterv1.Bootstrap{ConfigRef: &corev1.ObjectReference{}},
+						},
+					},
+					Strategy: tt.strategy,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
+
+func intOrStrPtr(i intstr.IntOrString) *intstr.IntOrString {
+	return &i
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clustersets;clustersets/status;clustersets/finalizers,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
+
+// ClusterSetReconciler reconciles a ClusterSet object.
+type ClusterSetReconciler struct {
+	Client           client.Client
+	WatchFilterValue string
+}
+
+func (r *ClusterSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&expv1.ClusterSet{}).
+		Owns(&clusterv1.Cluster{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *ClusterSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	clusterSet := &expv1.ClusterSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, clusterSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return. Created objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	ctx, log := logutil.Into(ctx, logutil.ClusterSetKey, logutil.KObj(clusterSet))
+
+	// Return early if the object is paused.
+	if annotations.HasPausedAnnotation(clusterSet) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// The Clusters of a ClusterSet being deleted are garbage collected, given that the ClusterSet owns them.
+	if !clusterSet.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(clusterSet, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		// Always attempt to Patch the ClusterSet object and status after each reconciliation.
+		if err := patchHelper.Patch(ctx, clusterSet, patch.WithStatusObservedGeneration{}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if err := r.reconcile(ctx, clusterSet); err != nil {
+		conditions.MarkFalse(clusterSet, expv1.ClustersReconciledCondition, expv1.ReconcileClustersFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	conditions.MarkTrue(clusterSet, expv1.ClustersReconciledCondition)
+	return ctrl.Result{}, nil
+}
+
+// reconcile creates the missing Clusters of the ClusterSet, updates the existing ones and deletes the ones exceeding
+// the desired number of replicas, then updates the status of the ClusterSet.
+func (r *ClusterSetReconciler) reconcile(ctx context.Context, clusterSet *expv1.ClusterSet) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	currentClusters, err := r.getClusters(ctx, clusterSet)
+	if err != nil {
+		return err
+	}
+
+	replicas := int32(1)
+	if clusterSet.Spec.Replicas != nil {
+		replicas = *clusterSet.Spec.Replicas
+	}
+
+	errList := []error{}
+	clusters := []*clusterv1.Cluster{}
+	desiredNames := map[string]bool{}
+	for index := int32(0); index < replicas; index++ {
+		desiredCluster, err := computeDesiredCluster(clusterSet, index)
+		if err != nil {
+			return err
+		}
+		desiredNames[desiredCluster.Name] = true
+
+		currentCluster, ok := currentClusters[desiredCluster.Name]
+		if !ok {
+			log.Info("Creating Cluster", logutil.ClusterKey, logutil.KObj(desiredCluster))
+			if err := r.Client.Create(ctx, desiredCluster); err != nil {
+				errList = append(errList, errors.Wrapf(err, "failed to create Cluster %s", desiredCluster.Name))
+				continue
+			}
+			clusters = append(clusters, desiredCluster)
+			continue
+		}
+
+		if err := r.updateCluster(ctx, currentCluster, desiredCluster); err != nil {
+			errList = append(errList, err)
+		}
+		clusters = append(clusters, currentCluster)
+	}
+
+	for name, cluster := range currentClusters {
+		if desiredNames[name] || !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		log.Info("Deleting Cluster", logutil.ClusterKey, logutil.KObj(cluster))
+		if err := r.Client.Delete(ctx, cluster); err != nil && !apierrors.IsNotFound(err) {
+			errList = append(errList, errors.Wrapf(err, "failed to delete Cluster %s", cluster.Name))
+		}
+	}
+
+	clusterSet.Status.Replicas = int32(len(clusters))
+	clusterSet.Status.ReadyReplicas = 0
+	for _, cluster := range clusters {
+		if conditions.IsTrue(cluster, clusterv1.ReadyCondition) {
+			clusterSet.Status.ReadyReplicas++
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// getClusters returns the Clusters of the ClusterSet, indexed by name.
+func (r *ClusterSetReconciler) getClusters(ctx context.Context, clusterSet *expv1.ClusterSet) (map[string]*clusterv1.Cluster, error) {
+	clusterList := &clusterv1.ClusterList{}
+	if err := r.Client.List(ctx, clusterList,
+		client.InNamespace(clusterSet.Namespace),
+		client.MatchingLabels{expv1.ClusterSetNameLabel: clusterSet.Name},
+	); err != nil {
+		return nil, errors.Wrap(err, "failed to list Clusters")
+	}
+
+	clusters := map[string]*clusterv1.Cluster{}
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if !metav1.IsControlledBy(cluster, clusterSet) {
+			continue
+		}
+		clusters[cluster.Name] = cluster
+	}
+	return clusters, nil
+}
+
+// updateCluster updates the labels, annotations and topology of a Cluster of the ClusterSet with the desired ones.
+// Other fields of the Cluster are not updated, given that they are either immutable or set by other controllers.
+func (r *ClusterSetReconciler) updateCluster(ctx context.Context, currentCluster, desiredCluster *clusterv1.Cluster) error {
+	patchHelper, err := patch.NewHelper(currentCluster, r.Client)
+	if err != nil {
+		return err
+	}
+
+	labels := currentCluster.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range desiredCluster.GetLabels() {
+		labels[k] = v
+	}
+	currentCluster.SetLabels(labels)
+
+	clusterAnnotations := currentCluster.GetAnnotations()
+	if clusterAnnotations == nil {
+		clusterAnnotations = map[string]string{}
+	}
+	for k, v := range desiredCluster.GetAnnotations() {
+		clusterAnnotations[k] = v
+	}
+	currentCluster.SetAnnotations(clusterAnnotations)
+
+	currentCluster.Spec.Topology = desiredCluster.Spec.Topology
+
+	if err := patchHelper.Patch(ctx, currentCluster); err != nil {
+		return errors.Wrapf(err, "failed to patch Cluster %s", currentCluster.Name)
+	}
+	return nil
+}
+
+// computeDesiredCluster computes the Cluster of the ClusterSet with the given index from the template, adding the
+// labels identifying the ClusterSet and the index, and applying the variable overrides for the index.
+func computeDesiredCluster(clusterSet *expv1.ClusterSet, index int32) (*clusterv1.Cluster, error) {
+	name, err := clusterSet.ClusterName(index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute the name of the Cluster with index %d", index)
+	}
+
+	labels := map[string]string{}
+	for k, v := range clusterSet.Spec.Template.ObjectMeta.Labels {
+		labels[k] = v
+	}
+	labels[expv1.ClusterSetNameLabel] = clusterSet.Name
+	labels[expv1.ClusterSetIndexLabel] = strconv.Itoa(int(index))
+
+	clusterAnnotations := map[string]string{}
+	for k, v := range clusterSet.Spec.Template.ObjectMeta.Annotations {
+		clusterAnnotations[k] = v
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       clusterSet.Namespace,
+			Labels:          labels,
+			Annotations:     clusterAnnotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(clusterSet, expv1.GroupVersion.WithKind("ClusterSet"))},
+		},
+		Spec: *clusterSet.Spec.Template.Spec.DeepCopy(),
+	}
+
+	if override := clusterSet.GetOverride(index); override != nil {
+		if cluster.Spec.Topology == nil {
+			return nil, errors.Errorf("failed to override the variables of the Cluster with index %d: the template does not have a topology", index)
+		}
+		cluster.Spec.Topology.Variables = mergeVariables(cluster.Spec.Topology.Variables, override.Variables)
+	}
+	return cluster, nil
+}
+
+// mergeVariables returns the variables with the overrides applied; overrides replace the variables with the same
+// name, and are appended otherwise.
+func mergeVariables(variables, overrides []clusterv1.ClusterVariable) []clusterv1.ClusterVariable {
+	overridesByName := map[string]clusterv1.ClusterVariable{}
+	for _, override := range overrides {
+		overridesByName[override.Name] = override
+	}
+
+	merged := make([]clusterv1.ClusterVariable, 0, len(variables)+len(overrides))
+	for _, variable := range variables {
+		if override, ok := overridesByName[variable.Name]; ok {
+			variable = override
+			delete(overridesByName, variable.Name)
+		}
+		merged = append(merged, variable)
+	}
+	for _, override := range overrides {
+		if _, ok := overridesByName[override.Name]; ok {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterSetReconcile(t *testing.T) {
+	newClusterSet := func(replicas int32) *expv1.ClusterSet {
+		return &expv1.ClusterSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fleet",
+				Namespace: metav1.NamespaceDefault,
+				UID:       "clusterset-uid",
+			},
+			Spec: expv1.ClusterSetSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+				Template: expv1.ClusterSetTemplate{
+					ObjectMeta: clusterv1.ObjectMeta{
+						Labels: map[string]string{"cni": "calico"},
+					},
+					Spec: clusterv1.ClusterSpec{
+						Topology: &clusterv1.Topology{
+							Class:   "class",
+							Version: "v1.22.0",
+							Variables: []clusterv1.ClusterVariable{
+								{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"eu-west-1"`)}},
+							},
+						},
+					},
+				},
+				Overrides: []expv1.ClusterSetOverride{
+					{
+						Index: 1,
+						Variables: []clusterv1.ClusterVariable{
+							{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	getClusters := func(g *WithT, c client.Client) map[string]*clusterv1.Cluster {
+		clusterList := &clusterv1.ClusterList{}
+		g.Expect(c.List(ctx, clusterList, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+		clusters := map[string]*clusterv1.Cluster{}
+		for i := range clusterList.Items {
+			clusters[clusterList.Items[i].Name] = &clusterList.Items[i]
+		}
+		return clusters
+	}
+
+	t.Run("Should create the Clusters from the template and apply the overrides", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterSet := newClusterSet(2)
+		c := fake.NewClientBuilder().WithObjects(clusterSet).Build()
+		r := &ClusterSetReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, clusterSet)).To(Succeed())
+		g.Expect(clusterSet.Status.Replicas).To(Equal(int32(2)))
+		g.Expect(clusterSet.Status.ReadyReplicas).To(Equal(int32(0)))
+
+		clusters := getClusters(g, c)
+		g.Expect(clusters).To(HaveLen(2))
+		g.Expect(clusters).To(HaveKey("fleet-0"))
+		g.Expect(clusters).To(HaveKey("fleet-1"))
+
+		g.Expect(clusters["fleet-0"].Labels).To(HaveKeyWithValue("cni", "calico"))
+		g.Expect(clusters["fleet-0"].Labels).To(HaveKeyWithValue(expv1.ClusterSetNameLabel, "fleet"))
+		g.Expect(clusters["fleet-0"].Labels).To(HaveKeyWithValue(expv1.ClusterSetIndexLabel, "0"))
+		g.Expect(metav1.IsControlledBy(clusters["fleet-0"], clusterSet)).To(BeTrue())
+		g.Expect(clusters["fleet-0"].Spec.Topology.Variables).To(ConsistOf(
+			clusterv1.ClusterVariable{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"eu-west-1"`)}},
+		))
+		g.Expect(clusters["fleet-1"].Spec.Topology.Variables).To(ConsistOf(
+			clusterv1.ClusterVariable{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+		))
+	})
+
+	t.Run("Should update the topology of the existing Clusters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterSet := newClusterSet(1)
+		c := fake.NewClientBuilder().WithObjects(clusterSet).Build()
+		r := &ClusterSetReconciler{Client: c}
+		g.Expect(r.reconcile(ctx, clusterSet)).To(Succeed())
+
+		clusterSet.Spec.Template.Spec.Topology.Version = "v1.23.0"
+		g.Expect(r.reconcile(ctx, clusterSet)).To(Succeed())
+
+		clusters := getClusters(g, c)
+		g.Expect(clusters).To(HaveLen(1))
+		g.Expect(clusters["fleet-0"].Spec.Topology.Version).To(Equal("v1.23.0"))
+	})
+
+	t.Run("Should delete the Clusters exceeding the desired replicas", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterSet := newClusterSet(3)
+		c := fake.NewClientBuilder().WithObjects(clusterSet).Build()
+		r := &ClusterSetReconciler{Client: c}
+		g.Expect(r.reconcile(ctx, clusterSet)).To(Succeed())
+		g.Expect(getClusters(g, c)).To(HaveLen(3))
+
+		clusterSet.Spec.Replicas = pointer.Int32Ptr(1)
+		g.Expect(r.reconcile(ctx, clusterSet)).To(Succeed())
+		g.Expect(clusterSet.Status.Replicas).To(Equal(int32(1)))
+
+		clusters := getClusters(g, c)
+		g.Expect(clusters).To(HaveLen(1))
+		g.Expect(clusters).To(HaveKey("fleet-0"))
+	})
+
+	t.Run("Should not adopt Clusters not controlled by the ClusterSet", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterSet := newClusterSet(0)
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "fleet-5",
+				Namespace: metav1.NamespaceDefault,
+				Labels:    map[string]string{expv1.ClusterSetNameLabel: "fleet"},
+			},
+		}
+		c := fake.NewClientBuilder().WithObjects(clusterSet, cluster).Build()
+		r := &ClusterSetReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, clusterSet)).To(Succeed())
+		g.Expect(getClusters(g, c)).To(HaveKey("fleet-5"))
+	})
+}
+
+func TestMergeVariables(t *testing.T) {
+	value := func(v string) apiextensionsv1.JSON {
+		return apiextensionsv1.JSON{Raw: []byte(v)}
+	}
+
+	tests := []struct {
+		name      string
+		variables []clusterv1.ClusterVariable
+		overrides []clusterv1.ClusterVariable
+		want      []clusterv1.ClusterVariable
+	}{
+		{
+			name:      "No overrides",
+			variables: []clusterv1.ClusterVariable{{Name: "a", Value: value("1")}},
+			want:      []clusterv1.ClusterVariable{{Name: "a", Value: value("1")}},
+		},
+		{
+			name:      "Overrides replace the variables with the same name",
+			variables: []clusterv1.ClusterVariable{{Name: "a", Value: value("1")}, {Name: "b", Value: value("2")}},
+			overrides: []clusterv1.ClusterVariable{{Name: "a", Value: value("3")}},
+			want:      []clusterv1.ClusterVariable{{Name: "a", Value: value("3")}, {Name: "b", Value: value("2")}},
+		},
+		{
+			name:      "Overrides are appended to the variables otherwise",
+			variables: []clusterv1.ClusterVariable{{Name: "a", Value: value("1")}},
+			overrides: []clusterv1.ClusterVariable{{Name: "b", Value: value("2")}},
+			want:      []clusterv1.ClusterVariable{{Name: "a", Value: value("1")}, {Name: "b", Value: value("2")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(mergeVariables(tt.variables, tt.overrides)).To(Equal(tt.want))
+		})
+	}
+}
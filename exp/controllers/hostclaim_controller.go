@@ -0,0 +1,417 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/cache"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=hostclaims;hostclaims/status;hostclaims/finalizers,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch
+
+// HostClaimReconciler reconciles a HostClaim object, binding it to one of the pre-registered hosts matching
+// its selector.
+// NOTE: hosts are provider specific objects; providers must grant the manager the permissions to get, list, watch
+// and patch them, e.g. with a ClusterRole labeled with cluster.x-k8s.io/aggregate-to-manager.
+type HostClaimReconciler struct {
+	Client           client.Client
+	WatchFilterValue string
+
+	// ExternalTracker watches the hosts of the kinds referenced by HostClaims; if not set, an ObjectTracker
+	// for the controller is created when setting up with the manager.
+	ExternalTracker *external.ObjectTracker
+
+	controller controller.Controller
+}
+
+func (r *HostClaimReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&expv1.HostClaim{}).
+		Watches(
+			&source.Kind{Type: &clusterv1.Machine{}},
+			handler.EnqueueRequestsFromMapFunc(r.machineToHostClaims),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	r.controller = c
+	if r.ExternalTracker == nil {
+		r.ExternalTracker, err = external.NewObjectTracker(mgr, external.ObjectTrackerOptions{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create external object tracker")
+		}
+	}
+	return nil
+}
+
+func (r *HostClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	hostClaim := &expv1.HostClaim{}
+	if err := r.Client.Get(ctx, req.NamespacedName, hostClaim); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return. Created objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	ctx, log := logutil.IntoWithCluster(ctx, hostClaim.Namespace, hostClaim.Spec.ClusterName, logutil.HostClaimKey, logutil.KObj(hostClaim))
+
+	// Return early if the object is paused.
+	if annotations.HasPausedAnnotation(hostClaim) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(hostClaim, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		// Always attempt to Patch the HostClaim object and status after each reconciliation.
+		if err := patchHelper.Patch(ctx, hostClaim, patch.WithStatusObservedGeneration{}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// Handle deletion reconciliation loop.
+	if !hostClaim.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, hostClaim)
+	}
+
+	// Add finalizer first if not exist to avoid the race condition between init and delete
+	if !controllerutil.ContainsFinalizer(hostClaim, expv1.HostClaimFinalizer) {
+		controllerutil.AddFinalizer(hostClaim, expv1.HostClaimFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	// Handle normal reconciliation loop.
+	return ctrl.Result{}, r.reconcile(ctx, hostClaim)
+}
+
+// reconcile binds a host to the HostClaim if it is not bound yet, and releases the host once the Machine
+// the host is claimed for has been deleted.
+func (r *HostClaimReconciler) reconcile(ctx context.Context, hostClaim *expv1.HostClaim) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	machine := &clusterv1.Machine{}
+	machineKey := client.ObjectKey{Namespace: hostClaim.Namespace, Name: hostClaim.Spec.MachineName}
+	if err := r.Client.Get(ctx, machineKey, machine); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get Machine %s", hostClaim.Spec.MachineName)
+		}
+
+		// The Machine has been deleted, so the host is not required anymore.
+		if hostClaim.Status.HostRef != nil {
+			log.Info("Releasing host, the Machine has been deleted", logutil.HostKey, hostClaim.Status.HostRef.Name)
+			if err := r.releaseHost(ctx, hostClaim); err != nil {
+				return err
+			}
+			conditions.MarkFalse(hostClaim, expv1.HostBoundCondition, expv1.HostReleasedReason, clusterv1.ConditionSeverityInfo, "")
+		}
+		return nil
+	}
+
+	// Ensure the HostClaim is owned by the Machine, so it is garbage collected together with the Machine.
+	hostClaim.OwnerReferences = util.EnsureOwnerRef(hostClaim.OwnerReferences, metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Machine",
+		Name:       machine.Name,
+		UID:        machine.UID,
+	})
+
+	// A HostClaim stays bound to its host, given that the Machine has been provisioned on it.
+	if hostClaim.Status.HostRef != nil {
+		return r.reconcileBoundHost(ctx, hostClaim)
+	}
+
+	host, err := r.claimHost(ctx, hostClaim)
+	if err != nil {
+		return err
+	}
+
+	// Watch the hosts of the kind referenced by the HostClaim, so it is reconciled when a host is registered,
+	// released or deleted.
+	watchObj := &unstructured.Unstructured{}
+	watchObj.SetAPIVersion(hostClaim.Spec.HostAPIVersion)
+	watchObj.SetKind(hostClaim.Spec.HostKind)
+	if err := r.ExternalTracker.Watch(log, r.controller, hostClaim, watchObj, handler.EnqueueRequestsFromMapFunc(r.hostToHostClaims)); err != nil {
+		return err
+	}
+
+	if host == nil {
+		log.Info("Waiting for a host matching the selector to be available")
+		conditions.MarkFalse(hostClaim, expv1.HostBoundCondition, expv1.WaitingForHostReason, clusterv1.ConditionSeverityInfo, "")
+		return nil
+	}
+
+	log.Info("Bound host", logutil.HostKey, host.GetName())
+	hostClaim.Status.HostRef = external.GetObjectReference(host)
+	conditions.MarkTrue(hostClaim, expv1.HostBoundCondition)
+	return nil
+}
+
+// reconcileBoundHost checks the host bound to the HostClaim still exists and is still claimed by it.
+func (r *HostClaimReconciler) reconcileBoundHost(ctx context.Context, hostClaim *expv1.HostClaim) error {
+	host, err := external.Get(ctx, r.Client, hostClaim.Status.HostRef, hostClaim.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			conditions.MarkFalse(hostClaim, expv1.HostBoundCondition, expv1.HostNotFoundReason, clusterv1.ConditionSeverityError,
+				"Host %s has been deleted", hostClaim.Status.HostRef.Name)
+			return nil
+		}
+		return err
+	}
+
+	switch host.GetAnnotations()[expv1.HostClaimAnnotation] {
+	case hostClaim.ClaimKey():
+	case "":
+		// The claim annotation has been removed from the host, e.g. by a backup restore; claim the host again.
+		if err := r.setClaim(ctx, host, hostClaim.ClaimKey()); err != nil {
+			return err
+		}
+	default:
+		conditions.MarkFalse(hostClaim, expv1.HostBoundCondition, expv1.HostClaimConflictReason, clusterv1.ConditionSeverityError,
+			"Host %s has been claimed by HostClaim %s", host.GetName(), host.GetAnnotations()[expv1.HostClaimAnnotation])
+		return nil
+	}
+
+	if err := r.ExternalTracker.Watch(ctrl.LoggerFrom(ctx), r.controller, hostClaim, host, handler.EnqueueRequestsFromMapFunc(r.hostToHostClaims)); err != nil {
+		return err
+	}
+
+	conditions.MarkTrue(hostClaim, expv1.HostBoundCondition)
+	return nil
+}
+
+// claimHost claims one of the available hosts matching the selector of the HostClaim, and returns it; it returns
+// nil if there are no available hosts.
+// Hosts are claimed by setting the HostClaimAnnotation with an optimistic lock, so when multiple HostClaims are
+// trying to claim the same host only the first one succeeds, and the others try with the next available hosts.
+func (r *HostClaimReconciler) claimHost(ctx context.Context, hostClaim *expv1.HostClaim) (*unstructured.Unstructured, error) {
+	hosts, err := r.getHosts(ctx, hostClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer a host already claimed by this HostClaim, e.g. if the status could not be patched after claiming it.
+	for i := range hosts {
+		if hosts[i].GetAnnotations()[expv1.HostClaimAnnotation] == hostClaim.ClaimKey() {
+			return &hosts[i], nil
+		}
+	}
+
+	for i := range hosts {
+		host := &hosts[i]
+		if !host.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+
+		available, err := r.isAvailable(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if !available {
+			continue
+		}
+
+		if err := r.setClaim(ctx, host, hostClaim.ClaimKey()); err != nil {
+			if apierrors.IsConflict(errors.Cause(err)) {
+				// The host has been changed since it was read, e.g. because it has been claimed by another
+				// HostClaim; try with the next one.
+				continue
+			}
+			return nil, err
+		}
+		return host, nil
+	}
+	return nil, nil
+}
+
+// getHosts returns the hosts matching the selector of the HostClaim, sorted by name so the HostClaims waiting
+// for a host try to claim them in the same order.
+func (r *HostClaimReconciler) getHosts(ctx context.Context, hostClaim *expv1.HostClaim) ([]unstructured.Unstructured, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&hostClaim.Spec.Selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build selector")
+	}
+
+	hostList := &unstructured.UnstructuredList{}
+	hostList.SetAPIVersion(hostClaim.Spec.HostAPIVersion)
+	hostList.SetKind(hostClaim.Spec.HostKind + "List")
+	if err := r.Client.List(ctx, hostList,
+		client.InNamespace(hostClaim.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s hosts", hostClaim.Spec.HostKind)
+	}
+
+	hosts := hostList.Items
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].GetName() < hosts[j].GetName()
+	})
+	return hosts, nil
+}
+
+// isAvailable returns true if the host is not claimed, or if it is claimed by a HostClaim that does not exist
+// anymore, e.g. because it has been deleted while its finalizer was being removed.
+func (r *HostClaimReconciler) isAvailable(ctx context.Context, host *unstructured.Unstructured) (bool, error) {
+	claimKey, ok := host.GetAnnotations()[expv1.HostClaimAnnotation]
+	if !ok {
+		return true, nil
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(claimKey)
+	if err != nil {
+		return true, nil // nolint:nilerr // The annotation has not been set by a HostClaim, so it can be overridden.
+	}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &expv1.HostClaim{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to get HostClaim %s", claimKey)
+	}
+	return false, nil
+}
+
+// setClaim sets the HostClaimAnnotation on the host with an optimistic lock, so the patch fails with a conflict
+// if the host has been changed since it was read.
+func (r *HostClaimReconciler) setClaim(ctx context.Context, host *unstructured.Unstructured, claimKey string) error {
+	before := host.DeepCopy()
+	annotations.AddAnnotations(host, map[string]string{expv1.HostClaimAnnotation: claimKey})
+	if err := r.Client.Patch(ctx, host, client.MergeFromWithOptions(before, client.MergeFromWithOptimisticLock{})); err != nil {
+		return errors.Wrapf(err, "failed to claim host %s", host.GetName())
+	}
+	return nil
+}
+
+// releaseHost removes the HostClaimAnnotation from the host bound to the HostClaim, so the host is available
+// to other HostClaims.
+func (r *HostClaimReconciler) releaseHost(ctx context.Context, hostClaim *expv1.HostClaim) error {
+	if hostClaim.Status.HostRef == nil {
+		return nil
+	}
+
+	host, err := external.Get(ctx, r.Client, hostClaim.Status.HostRef, hostClaim.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			hostClaim.Status.HostRef = nil
+			return nil
+		}
+		return err
+	}
+
+	// Do not release hosts claimed by other HostClaims.
+	if host.GetAnnotations()[expv1.HostClaimAnnotation] == hostClaim.ClaimKey() {
+		before := host.DeepCopy()
+		hostAnnotations := host.GetAnnotations()
+		delete(hostAnnotations, expv1.HostClaimAnnotation)
+		host.SetAnnotations(hostAnnotations)
+		if err := r.Client.Patch(ctx, host, client.MergeFrom(before)); err != nil {
+			return errors.Wrapf(err, "failed to release host %s", host.GetName())
+		}
+	}
+
+	hostClaim.Status.HostRef = nil
+	return nil
+}
+
+func (r *HostClaimReconciler) reconcileDelete(ctx context.Context, hostClaim *expv1.HostClaim) error {
+	if err := r.releaseHost(ctx, hostClaim); err != nil {
+		return err
+	}
+
+	r.ExternalTracker.Release(ctrl.LoggerFrom(ctx), r.controller, hostClaim)
+	controllerutil.RemoveFinalizer(hostClaim, expv1.HostClaimFinalizer)
+	return nil
+}
+
+// machineToHostClaims maps a Machine to the HostClaims claiming a host for it.
+func (r *HostClaimReconciler) machineToHostClaims(o client.Object) []ctrl.Request {
+	m, ok := o.(*clusterv1.Machine)
+	if !ok {
+		panic(fmt.Sprintf("Expected a Machine but got a %T", o))
+	}
+
+	hostClaimList := &expv1.HostClaimList{}
+	if err := r.Client.List(context.Background(), hostClaimList, client.InNamespace(m.Namespace)); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for _, hostClaim := range hostClaimList.Items {
+		if hostClaim.Spec.MachineName == m.Name {
+			result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: hostClaim.Namespace, Name: hostClaim.Name}})
+		}
+	}
+	return result
+}
+
+// hostToHostClaims maps a host to the HostClaim it is bound to; hosts that are not claimed are mapped to the
+// HostClaims in the same namespace waiting for a host.
+func (r *HostClaimReconciler) hostToHostClaims(o client.Object) []ctrl.Request {
+	if claimKey, ok := o.GetAnnotations()[expv1.HostClaimAnnotation]; ok {
+		if namespace, name, err := cache.SplitMetaNamespaceKey(claimKey); err == nil {
+			return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: namespace, Name: name}}}
+		}
+		return nil
+	}
+
+	hostClaimList := &expv1.HostClaimList{}
+	if err := r.Client.List(context.Background(), hostClaimList, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for _, hostClaim := range hostClaimList.Items {
+		if hostClaim.Status.HostRef == nil {
+			result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: hostClaim.Namespace, Name: hostClaim.Name}})
+		}
+	}
+	return result
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHostClaimReconcile(t *testing.T) {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "machine-uid",
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: clusterName,
+		},
+	}
+
+	newHostClaim := func(name string) *expv1.HostClaim {
+		return &expv1.HostClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  metav1.NamespaceDefault,
+				Finalizers: []string{expv1.HostClaimFinalizer},
+			},
+			Spec: expv1.HostClaimSpec{
+				ClusterName:    clusterName,
+				MachineName:    machine.Name,
+				HostAPIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				HostKind:       "Host",
+				Selector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"rack": "a"},
+				},
+			},
+		}
+	}
+
+	host := func(name, rack, claimKey string) *unstructured.Unstructured {
+		h := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "Host",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": metav1.NamespaceDefault,
+					"labels": map[string]interface{}{
+						"rack": rack,
+					},
+				},
+			},
+		}
+		if claimKey != "" {
+			h.SetAnnotations(map[string]string{expv1.HostClaimAnnotation: claimKey})
+		}
+		return h
+	}
+
+	getHost := func(g *WithT, c client.Client, name string) *unstructured.Unstructured {
+		h := &unstructured.Unstructured{}
+		h.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+		h.SetKind("Host")
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: name}, h)).To(Succeed())
+		return h
+	}
+
+	t.Run("Should bind the first available host matching the selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hostClaim := newHostClaim("claim-1")
+		c := fake.NewClientBuilder().WithObjects(
+			machine,
+			hostClaim,
+			host("host-1", "b", ""),
+			host("host-2", "a", ""),
+			host("host-3", "a", ""),
+		).Build()
+		r := &HostClaimReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, hostClaim)).To(Succeed())
+		g.Expect(hostClaim.Status.HostRef).NotTo(BeNil())
+		g.Expect(hostClaim.Status.HostRef.Name).To(Equal("host-2"))
+		g.Expect(conditions.IsTrue(hostClaim, expv1.HostBoundCondition)).To(BeTrue())
+		g.Expect(hostClaim.OwnerReferences).To(HaveLen(1))
+		g.Expect(hostClaim.OwnerReferences[0].Name).To(Equal(machine.Name))
+
+		g.Expect(getHost(g, c, "host-2").GetAnnotations()).To(HaveKeyWithValue(expv1.HostClaimAnnotation, hostClaim.ClaimKey()))
+		g.Expect(getHost(g, c, "host-3").GetAnnotations()).NotTo(HaveKey(expv1.HostClaimAnnotation))
+	})
+
+	t.Run("Should skip the hosts claimed by other HostClaims", func(t *testing.T) {
+		g := NewWithT(t)
+
+		otherHostClaim := newHostClaim("claim-2")
+		hostClaim := newHostClaim("claim-1")
+		c := fake.NewClientBuilder().WithObjects(
+			machine,
+			otherHostClaim,
+			hostClaim,
+			host("host-1", "a", otherHostClaim.ClaimKey()),
+			host("host-2", "a", ""),
+		).Build()
+		r := &HostClaimReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, hostClaim)).To(Succeed())
+		g.Expect(hostClaim.Status.HostRef).NotTo(BeNil())
+		g.Expect(hostClaim.Status.HostRef.Name).To(Equal("host-2"))
+		g.Expect(getHost(g, c, "host-1").GetAnnotations()).To(HaveKeyWithValue(expv1.HostClaimAnnotation, otherHostClaim.ClaimKey()))
+	})
+
+	t.Run("Should claim the hosts claimed by HostClaims that do not exist anymore", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hostClaim := newHostClaim("claim-1")
+		c := fake.NewClientBuilder().WithObjects(
+			machine,
+			hostClaim,
+			host("host-1", "a", metav1.NamespaceDefault+"/deleted-claim"),
+		).Build()
+		r := &HostClaimReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, hostClaim)).To(Succeed())
+		g.Expect(hostClaim.Status.HostRef).NotTo(BeNil())
+		g.Expect(hostClaim.Status.HostRef.Name).To(Equal("host-1"))
+		g.Expect(getHost(g, c, "host-1").GetAnnotations()).To(HaveKeyWithValue(expv1.HostClaimAnnotation, hostClaim.ClaimKey()))
+	})
+
+	t.Run("Should wait for a host if there are no available hosts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		otherHostClaim := newHostClaim("claim-2")
+		hostClaim := newHostClaim("claim-1")
+		c := fake.NewClientBuilder().WithObjects(
+			machine,
+			otherHostClaim,
+			hostClaim,
+			host("host-1", "a", otherHostClaim.ClaimKey()),
+		).Build()
+		r := &HostClaimReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, hostClaim)).To(Succeed())
+		g.Expect(hostClaim.Status.HostRef).To(BeNil())
+		g.Expect(conditions.IsFalse(hostClaim, expv1.HostBoundCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(hostClaim, expv1.HostBoundCondition)).To(Equal(expv1.WaitingForHostReason))
+	})
+
+	t.Run("Should report a conflict if the bound host has been claimed by another HostClaim", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hostClaim := newHostClaim("claim-1")
+		hostClaim.Status.HostRef = &corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+			Kind:       "Host",
+			Name:       "host-1",
+			Namespace:  metav1.NamespaceDefault,
+		}
+		c := fake.NewClientBuilder().WithObjects(
+			machine,
+			hostClaim,
+			host("host-1", "a", metav1.NamespaceDefault+"/claim-2"),
+		).Build()
+		r := &HostClaimReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, hostClaim)).To(Succeed())
+		g.Expect(conditions.IsFalse(hostClaim, expv1.HostBoundCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(hostClaim, expv1.HostBoundCondition)).To(Equal(expv1.HostClaimConflictReason))
+	})
+
+	t.Run("Should release the host when the Machine has been deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hostClaim := newHostClaim("claim-1")
+		hostClaim.Status.HostRef = &corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+			Kind:       "Host",
+			Name:       "host-1",
+			Namespace:  metav1.NamespaceDefault,
+		}
+		c := fake.NewClientBuilder().WithObjects(
+			hostClaim,
+			host("host-1", "a", hostClaim.ClaimKey()),
+		).Build()
+		r := &HostClaimReconciler{Client: c}
+
+		g.Expect(r.reconcile(ctx, hostClaim)).To(Succeed())
+		g.Expect(hostClaim.Status.HostRef).To(BeNil())
+		g.Expect(conditions.GetReason(hostClaim, expv1.HostBoundCondition)).To(Equal(expv1.HostReleasedReason))
+		g.Expect(getHost(g, c, "host-1").GetAnnotations()).NotTo(HaveKey(expv1.HostClaimAnnotation))
+	})
+
+	t.Run("Should release the host when the HostClaim is deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		hostClaim := newHostClaim("claim-1")
+		hostClaim.Status.HostRef = &corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+			Kind:       "Host",
+			Name:       "host-1",
+			Namespace:  metav1.NamespaceDefault,
+		}
+		c := fake.NewClientBuilder().WithObjects(
+			machine,
+			hostClaim,
+			host("host-1", "a", hostClaim.ClaimKey()),
+		).Build()
+		r := &HostClaimReconciler{Client: c}
+
+		g.Expect(r.reconcileDelete(ctx, hostClaim)).To(Succeed())
+		g.Expect(hostClaim.Status.HostRef).To(BeNil())
+		g.Expect(hostClaim.Finalizers).NotTo(ContainElement(expv1.HostClaimFinalizer))
+		g.Expect(getHost(g, c, "host-1").GetAnnotations()).NotTo(HaveKey(expv1.HostClaimAnnotation))
+	})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileMachines creates a Machine for each infrastructure machine belonging to the MachinePool, so the
+// instances of the pool can be handled like any other Machine, e.g. by MachineHealthChecks.
+// Infrastructure providers opt in by reporting the kind of their infrastructure machines in the
+// status.infrastructureMachineKind field of the InfraMachinePool, and by labeling the infrastructure machines
+// with the cluster and the MachinePool name.
+func (r *MachinePoolReconciler) reconcileMachines(ctx context.Context, mp *expv1.MachinePool, infraMachinePool *unstructured.Unstructured) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	infraMachineKind, _, err := unstructured.NestedString(infraMachinePool.Object, "status", "infrastructureMachineKind")
+	if err != nil {
+		return errors.Wrapf(err, "failed to retrieve infrastructureMachineKind from infrastructure provider for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+	if infraMachineKind == "" {
+		// The infrastructure provider does not support MachinePool Machines.
+		return nil
+	}
+
+	machinePoolLabels := map[string]string{
+		clusterv1.ClusterLabelName:     mp.Spec.ClusterName,
+		clusterv1.MachinePoolLabelName: mp.Name,
+	}
+
+	infraMachines := &unstructured.UnstructuredList{}
+	infraMachines.SetGroupVersionKind(infraMachinePool.GroupVersionKind().GroupVersion().WithKind(infraMachineKind + "List"))
+	if err := r.Client.List(ctx, infraMachines, client.InNamespace(mp.Namespace), client.MatchingLabels(machinePoolLabels)); err != nil {
+		return errors.Wrapf(err, "failed to list %s objects for MachinePool %q in namespace %q", infraMachineKind, mp.Name, mp.Namespace)
+	}
+
+	machineList := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machineList, client.InNamespace(mp.Namespace), client.MatchingLabels(machinePoolLabels)); err != nil {
+		return errors.Wrapf(err, "failed to list Machines for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+	machines := map[string]*clusterv1.Machine{}
+	for i := range machineList.Items {
+		m := &machineList.Items[i]
+		if metav1.IsControlledBy(m, mp) {
+			machines[m.Spec.InfrastructureRef.Name] = m
+		}
+	}
+
+	errs := []error{}
+	infraMachineNames := map[string]bool{}
+	for i := range infraMachines.Items {
+		infraMachine := &infraMachines.Items[i]
+		infraMachineNames[infraMachine.GetName()] = true
+
+		if _, ok := machines[infraMachine.GetName()]; ok || !infraMachine.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+
+		// Machines can be created only after the bootstrap data secret is available.
+		if mp.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
+			log.V(4).Info("Waiting for the bootstrap data secret to create the MachinePool Machines")
+			break
+		}
+
+		machine := newMachinePoolMachine(mp, infraMachine)
+		if err := r.Client.Create(ctx, machine); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			errs = append(errs, errors.Wrapf(err, "failed to create Machine for %s %q in namespace %q", infraMachineKind, infraMachine.GetName(), mp.Namespace))
+			continue
+		}
+		log.Info("Created Machine for MachinePool instance", "machine", machine.Name)
+	}
+
+	for _, m := range machines {
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		switch {
+		case !infraMachineNames[m.Spec.InfrastructureRef.Name]:
+			// The instance has been removed from the pool, e.g. because of a scale down.
+			log.Info("Deleting Machine of a MachinePool instance that does not exist anymore", "machine", m.Name)
+		case conditions.IsFalse(m, clusterv1.MachineOwnerRemediatedCondition):
+			// The Machine has been marked for remediation by a MachineHealthCheck; deleting the Machine deletes
+			// the infrastructure machine too, and the infrastructure provider is expected to replace the instance.
+			log.Info("Deleting unhealthy Machine of a MachinePool instance", "machine", m.Name)
+		default:
+			continue
+		}
+
+		if err := r.Client.Delete(ctx, m); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete Machine %q in namespace %q", m.Name, m.Namespace))
+		}
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+// newMachinePoolMachine returns the Machine representing an instance of the MachinePool.
+func newMachinePoolMachine(mp *expv1.MachinePool, infraMachine *unstructured.Unstructured) *clusterv1.Machine {
+	labels := map[string]string{}
+	for k, v := range mp.Spec.Template.Labels {
+		labels[k] = v
+	}
+	labels[clusterv1.ClusterLabelName] = mp.Spec.ClusterName
+	labels[clusterv1.MachinePoolLabelName] = mp.Name
+
+	annotations := map[string]string{}
+	for k, v := range mp.Spec.Template.Annotations {
+		annotations[k] = v
+	}
+
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            infraMachine.GetName(),
+			Namespace:       mp.Namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(mp, expv1.GroupVersion.WithKind("MachinePool"))},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: mp.Spec.ClusterName,
+			Version:     mp.Spec.Template.Spec.Version,
+			Bootstrap: clusterv1.Bootstrap{
+				DataSecretName: mp.Spec.Template.Spec.Bootstrap.DataSecretName,
+			},
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: infraMachine.GetAPIVersion(),
+				Kind:       infraMachine.GetKind(),
+				Name:       infraMachine.GetName(),
+				Namespace:  infraMachine.GetNamespace(),
+			},
+		},
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileMachinePoolMachines(t *testing.T) {
+	machinePool := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machinepool-test",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "machinepool-uid",
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: clusterName,
+			Replicas:    pointer.Int32Ptr(2),
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels: map[string]string{
+						"label-1": "true",
+					},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: clusterName,
+					Version:     pointer.StringPtr("v1.22.0"),
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("data"),
+					},
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "InfrastructureConfig",
+						Name:       "infra-config1",
+					},
+				},
+			},
+		},
+	}
+
+	infraMachinePool := func(infraMachineKind string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "InfrastructureConfig",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"status": map[string]interface{}{
+					"infrastructureMachineKind": infraMachineKind,
+				},
+			},
+		}
+	}
+
+	infraMachine := func(name, poolName string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "InfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": metav1.NamespaceDefault,
+					"labels": map[string]interface{}{
+						clusterv1.ClusterLabelName:     clusterName,
+						clusterv1.MachinePoolLabelName: poolName,
+					},
+				},
+			},
+		}
+	}
+
+	machinePoolMachines := func(g *WithT, c client.Client) []clusterv1.Machine {
+		machines := &clusterv1.MachineList{}
+		g.Expect(c.List(ctx, machines, client.InNamespace(metav1.NamespaceDefault), client.MatchingLabels{
+			clusterv1.MachinePoolLabelName: machinePool.Name,
+		})).To(Succeed())
+		return machines.Items
+	}
+
+	t.Run("Should not create Machines if the infrastructure provider does not report the infrastructure machine kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &MachinePoolReconciler{
+			Client: fake.NewClientBuilder().WithObjects(infraMachine("instance-1", machinePool.Name)).Build(),
+		}
+
+		g.Expect(r.reconcileMachines(ctx, machinePool.DeepCopy(), infraMachinePool(""))).To(Succeed())
+		g.Expect(machinePoolMachines(g, r.Client)).To(BeEmpty())
+	})
+
+	t.Run("Should create a Machine for each infrastructure machine of the MachinePool", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := &MachinePoolReconciler{
+			Client: fake.NewClientBuilder().WithObjects(
+				infraMachine("instance-1", machinePool.Name),
+				infraMachine("instance-2", machinePool.Name),
+				infraMachine("other-instance", "other-machinepool"),
+			).Build(),
+		}
+
+		g.Expect(r.reconcileMachines(ctx, machinePool.DeepCopy(), infraMachinePool("InfrastructureMachine"))).To(Succeed())
+
+		machines := machinePoolMachines(g, r.Client)
+		g.Expect(machines).To(HaveLen(2))
+		for i := range machines {
+			m := &machines[i]
+			g.Expect(m.Name).To(Or(Equal("instance-1"), Equal("instance-2")))
+			g.Expect(metav1.IsControlledBy(m, machinePool)).To(BeTrue())
+			g.Expect(m.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, clusterName))
+			g.Expect(m.Labels).To(HaveKeyWithValue("label-1", "true"))
+			g.Expect(m.Spec.ClusterName).To(Equal(clusterName))
+			g.Expect(m.Spec.Version).To(Equal(machinePool.Spec.Template.Spec.Version))
+			g.Expect(m.Spec.Bootstrap.DataSecretName).To(Equal(pointer.StringPtr("data")))
+			g.Expect(m.Spec.InfrastructureRef.Kind).To(Equal("InfrastructureMachine"))
+			g.Expect(m.Spec.InfrastructureRef.Name).To(Equal(m.Name))
+		}
+
+		// Reconciling again must not create more Machines.
+		g.Expect(r.reconcileMachines(ctx, machinePool.DeepCopy(), infraMachinePool("InfrastructureMachine"))).To(Succeed())
+		g.Expect(machinePoolMachines(g, r.Client)).To(HaveLen(2))
+	})
+
+	t.Run("Should not create Machines until the bootstrap data secret is available", func(t *testing.T) {
+		g := NewWithT(t)
+
+		mp := machinePool.DeepCopy()
+		mp.Spec.Template.Spec.Bootstrap.DataSecretName = nil
+
+		r := &MachinePoolReconciler{
+			Client: fake.NewClientBuilder().WithObjects(infraMachine("instance-1", machinePool.Name)).Build(),
+		}
+
+		g.Expect(r.reconcileMachines(ctx, mp, infraMachinePool("InfrastructureMachine"))).To(Succeed())
+		g.Expect(machinePoolMachines(g, r.Client)).To(BeEmpty())
+	})
+
+	t.Run("Should delete the Machines of removed or unhealthy instances", func(t *testing.T) {
+		g := NewWithT(t)
+
+		healthy := newMachinePoolMachine(machinePool, infraMachine("instance-1", machinePool.Name))
+		unhealthy := newMachinePoolMachine(machinePool, infraMachine("instance-2", machinePool.Name))
+		conditions.MarkFalse(unhealthy, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+		removed := newMachinePoolMachine(machinePool, infraMachine("instance-3", machinePool.Name))
+
+		r := &MachinePoolReconciler{
+			Client: fake.NewClientBuilder().WithObjects(
+				infraMachine("instance-1", machinePool.Name),
+				infraMachine("instance-2", machinePool.Name),
+				healthy,
+				unhealthy,
+				removed,
+			).Build(),
+		}
+
+		g.Expect(r.reconcileMachines(ctx, machinePool.DeepCopy(), infraMachinePool("InfrastructureMachine"))).To(Succeed())
+
+		machines := machinePoolMachines(g, r.Client)
+		g.Expect(machines).To(HaveLen(1))
+		g.Expect(machines[0].Name).To(Equal(healthy.Name))
+	})
+}
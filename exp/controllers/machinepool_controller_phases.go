@@ -39,7 +39,6 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var (
@@ -131,18 +130,9 @@ func (r *MachinePoolReconciler) reconcileExternal(ctx context.Context, cluster *
 		return external.ReconcileOutput{}, err
 	}
 
-	// Add watcher for external object, if there isn't one already.
-	_, loaded := r.externalWatchers.LoadOrStore(obj.GroupVersionKind().String(), struct{}{})
-	if !loaded && r.controller != nil {
-		log.Info("Adding watcher on external object", "gvk", obj.GroupVersionKind())
-		err := r.controller.Watch(
-			&source.Kind{Type: obj},
-			&handler.EnqueueRequestForOwner{OwnerType: &expv1.MachinePool{}},
-		)
-		if err != nil {
-			r.externalWatchers.Delete(obj.GroupVersionKind().String())
-			return external.ReconcileOutput{}, errors.Wrapf(err, "failed to add watcher on external object %q", obj.GroupVersionKind())
-		}
+	// Ensure we add a watcher to the external object.
+	if err := r.ExternalTracker.Watch(log, r.controller, m, obj, &handler.EnqueueRequestForOwner{OwnerType: &expv1.MachinePool{}}); err != nil {
+		return external.ReconcileOutput{}, err
 	}
 
 	// Set failure reason and message, if any.
@@ -166,7 +156,7 @@ func (r *MachinePoolReconciler) reconcileExternal(ctx context.Context, cluster *
 
 // reconcileBootstrap reconciles the Spec.Bootstrap.ConfigRef object on a MachinePool.
 func (r *MachinePoolReconciler) reconcileBootstrap(ctx context.Context, cluster *clusterv1.Cluster, m *expv1.MachinePool) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx)
 
 	// Call generic external reconciler if we have an external reference.
 	var bootstrapConfig *unstructured.Unstructured
@@ -226,7 +216,7 @@ func (r *MachinePoolReconciler) reconcileBootstrap(ctx context.Context, cluster
 
 // reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a MachinePool.
 func (r *MachinePoolReconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool) (ctrl.Result, error) {
-	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
+	log := ctrl.LoggerFrom(ctx)
 
 	// Call generic external reconciler.
 	infraReconcileResult, err := r.reconcileExternal(ctx, cluster, mp, &mp.Spec.Template.Spec.InfrastructureRef)
@@ -254,6 +244,11 @@ func (r *MachinePoolReconciler) reconcileInfrastructure(ctx context.Context, clu
 		return ctrl.Result{}, nil
 	}
 
+	// Create or delete the Machines representing the instances of the pool, if supported by the infrastructure provider.
+	if err := r.reconcileMachines(ctx, mp, infraConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	ready, err := external.IsReady(infraConfig)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -292,6 +287,13 @@ func (r *MachinePoolReconciler) reconcileInfrastructure(ctx context.Context, clu
 		return ctrl.Result{RequeueAfter: externalReadyWait}, nil
 	}
 
+	// Get and set Status.UpdatedReplicas from the infrastructure provider, if reported.
+	if err := util.UnstructuredUnmarshalField(infraConfig, &mp.Status.UpdatedReplicas, "status", "updatedReplicas"); err != nil {
+		if err != util.ErrUnstructuredFieldNotFound {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve updatedReplicas from infrastructure provider for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+		}
+	}
+
 	if !reflect.DeepEqual(mp.Spec.ProviderIDList, providerIDList) {
 		mp.Spec.ProviderIDList = providerIDList
 		mp.Status.ReadyReplicas = 0
@@ -856,6 +856,35 @@ func TestReconcileMachinePoolInfrastructure(t *testing.T) {
 				g.Expect(m.Status.GetTypedPhase()).To(Equal(expv1.MachinePoolPhaseFailed))
 			},
 		},
+		{
+			name: "infrastructure config ready, updated replicas are reported by the infrastructure provider",
+			infraConfig: map[string]interface{}{
+				"kind":       "InfrastructureConfig",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerIDList": []interface{}{
+						"test://id-1",
+						"test://id-2",
+					},
+				},
+				"status": map[string]interface{}{
+					"ready":           true,
+					"replicas":        int64(2),
+					"updatedReplicas": int64(1),
+				},
+			},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *expv1.MachinePool) {
+				g.Expect(m.Status.InfrastructureReady).To(BeTrue())
+				g.Expect(m.Status.Replicas).To(Equal(int32(2)))
+				g.Expect(m.Status.UpdatedReplicas).To(Equal(int32(1)))
+			},
+		},
 		{
 			name: "infrastructure ref is paused",
 			infraConfig: map[string]interface{}{
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/component-base/featuregate"
+	"sigs.k8s.io/yaml"
+)
+
+// featureGatesFlagName is the name of the flag added by featuregate.MutableFeatureGate.AddFlag.
+const featureGatesFlagName = "feature-gates"
+
+// Configuration is a ComponentConfig-style configuration for a controller manager, usually stored
+// in a ConfigMap mounted as a file into the manager Pod.
+//
+// Values set via command line flags take precedence over the values defined in the Configuration.
+type Configuration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// FeatureGates is a map of feature names to bools that enable or disable features.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// ControllerSettings is a map of flag names to values, e.g. "cluster-concurrency": "10"
+	// or "sync-period": "10m", that is used to configure controller settings without changing
+	// the manager's command line.
+	// +optional
+	ControllerSettings map[string]string `json:"controllerSettings,omitempty"`
+}
+
+// LoadConfiguration reads a Configuration from the given file.
+func LoadConfiguration(path string) (*Configuration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read configuration file %q", path)
+	}
+
+	config := &Configuration{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse configuration file %q", path)
+	}
+	return config, nil
+}
+
+// Apply applies the Configuration to the given feature gates and to the flags in the flag set.
+// Flags explicitly set on the command line are left untouched; feature gates explicitly set
+// via the feature-gates flag take precedence over the ones in the Configuration.
+// Apply must be called after the flag set has been parsed.
+func (c *Configuration) Apply(gates featuregate.MutableFeatureGate, fs *pflag.FlagSet) error {
+	if len(c.FeatureGates) > 0 {
+		// Preserve the feature gates set via flag, which are re-applied on top of the Configuration.
+		var fromFlag string
+		if f := fs.Lookup(featureGatesFlagName); f != nil && f.Changed {
+			fromFlag = f.Value.String()
+		}
+		if err := gates.SetFromMap(c.FeatureGates); err != nil {
+			return errors.Wrap(err, "failed to set feature gates from configuration")
+		}
+		if fromFlag != "" {
+			if err := gates.Set(fromFlag); err != nil {
+				return errors.Wrap(err, "failed to set feature gates from flag")
+			}
+		}
+	}
+
+	// Sort flag names to get a predictable order when reporting errors.
+	names := make([]string, 0, len(c.ControllerSettings))
+	for name := range c.ControllerSettings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == featureGatesFlagName {
+			return errors.Errorf("feature gates must be set using featureGates instead of controllerSettings")
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			return errors.Errorf("unknown controller setting %q", name)
+		}
+		if f.Changed {
+			continue
+		}
+		if err := fs.Set(name, c.ControllerSettings[name]); err != nil {
+			return errors.Wrapf(err, "invalid value for controller setting %q", name)
+		}
+	}
+	return nil
+}
+
+// WatchConfiguration polls the given configuration file at the given interval and calls onChange
+// when its content changes. Given that changing feature gates or controller settings requires
+// setting up controllers again, onChange is usually expected to stop the manager so the Pod is restarted.
+// WatchConfiguration blocks until the context is cancelled.
+func WatchConfiguration(ctx context.Context, path string, interval time.Duration, onChange func()) error {
+	initial, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read configuration file %q", path)
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		current, err := os.ReadFile(path)
+		if err != nil {
+			// The file could be temporarily missing while the ConfigMap volume is being updated.
+			return
+		}
+		if !bytes.Equal(initial, current) {
+			initial = current
+			onChange()
+		}
+	}, interval)
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+	"k8s.io/component-base/featuregate"
+)
+
+func TestLoadConfiguration(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	g.Expect(os.WriteFile(path, []byte(`
+featureGates:
+  ClusterTopology: true
+controllerSettings:
+  cluster-concurrency: "20"
+`), 0600)).To(Succeed())
+
+	config, err := LoadConfiguration(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(config.FeatureGates).To(HaveKeyWithValue("ClusterTopology", true))
+	g.Expect(config.ControllerSettings).To(HaveKeyWithValue("cluster-concurrency", "20"))
+
+	g.Expect(os.WriteFile(path, []byte("unknownField: true"), 0600)).To(Succeed())
+	_, err = LoadConfiguration(path)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestConfigurationApply(t *testing.T) {
+	newFlagSet := func() (featuregate.MutableFeatureGate, *pflag.FlagSet, *int, *time.Duration) {
+		gates := featuregate.NewFeatureGate()
+		_ = gates.Add(defaultClusterAPIFeatureGates)
+
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		concurrency := fs.Int("cluster-concurrency", 10, "")
+		syncPeriod := fs.Duration("sync-period", 10*time.Minute, "")
+		gates.AddFlag(fs)
+		return gates, fs, concurrency, syncPeriod
+	}
+
+	t.Run("applies feature gates and controller settings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		gates, fs, concurrency, syncPeriod := newFlagSet()
+		g.Expect(fs.Parse(nil)).To(Succeed())
+
+		config := &Configuration{
+			FeatureGates:       map[string]bool{string(ClusterTopology): true},
+			ControllerSettings: map[string]string{"cluster-concurrency": "20", "sync-period": "5m"},
+		}
+		g.Expect(config.Apply(gates, fs)).To(Succeed())
+		g.Expect(gates.Enabled(ClusterTopology)).To(BeTrue())
+		g.Expect(*concurrency).To(Equal(20))
+		g.Expect(*syncPeriod).To(Equal(5 * time.Minute))
+	})
+
+	t.Run("flags take precedence over the configuration", func(t *testing.T) {
+		g := NewWithT(t)
+
+		gates, fs, concurrency, _ := newFlagSet()
+		g.Expect(fs.Parse([]string{"--cluster-concurrency=5", "--feature-gates=ClusterTopology=false"})).To(Succeed())
+
+		config := &Configuration{
+			FeatureGates:       map[string]bool{string(ClusterTopology): true, string(MachinePool): true},
+			ControllerSettings: map[string]string{"cluster-concurrency": "20"},
+		}
+		g.Expect(config.Apply(gates, fs)).To(Succeed())
+		g.Expect(gates.Enabled(ClusterTopology)).To(BeFalse())
+		g.Expect(gates.Enabled(MachinePool)).To(BeTrue())
+		g.Expect(*concurrency).To(Equal(5))
+	})
+
+	t.Run("fails for unknown settings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		gates, fs, _, _ := newFlagSet()
+		g.Expect(fs.Parse(nil)).To(Succeed())
+
+		g.Expect((&Configuration{ControllerSettings: map[string]string{"unknown": "1"}}).Apply(gates, fs)).ToNot(Succeed())
+		g.Expect((&Configuration{ControllerSettings: map[string]string{"feature-gates": "MachinePool=true"}}).Apply(gates, fs)).ToNot(Succeed())
+		g.Expect((&Configuration{FeatureGates: map[string]bool{"Unknown": true}}).Apply(gates, fs)).ToNot(Succeed())
+	})
+}
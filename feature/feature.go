@@ -44,6 +44,16 @@ const (
 	//
 	// alpha: v0.4
 	ClusterTopology featuregate.Feature = "ClusterTopology"
+
+	// ClusterSet is a feature gate for the ClusterSet functionality; it requires ClusterTopology to be enabled.
+	//
+	// alpha: v1.0
+	ClusterSet featuregate.Feature = "ClusterSet"
+
+	// HostClaim is a feature gate for the HostClaim functionality.
+	//
+	// alpha: v1.0
+	HostClaim featuregate.Feature = "HostClaim"
 )
 
 func init() {
@@ -57,4 +67,6 @@ var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	MachinePool:        {Default: false, PreRelease: featuregate.Alpha},
 	ClusterResourceSet: {Default: true, PreRelease: featuregate.Beta},
 	ClusterTopology:    {Default: false, PreRelease: featuregate.Alpha},
+	ClusterSet:         {Default: false, PreRelease: featuregate.Alpha},
+	HostClaim:          {Default: false, PreRelease: featuregate.Alpha},
 }
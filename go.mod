@@ -21,6 +21,7 @@ require (
 	github.com/onsi/ginkgo v1.16.4
 	github.com/onsi/gomega v1.15.0
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.11.0
 	github.com/spf13/cobra v1.2.1
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.8.1
@@ -28,15 +29,16 @@ require (
 	go.etcd.io/etcd/client/v3 v3.5.0
 	golang.org/x/oauth2 v0.0.0-20210628180205-a41e5a781914
 	google.golang.org/grpc v1.39.0
+	helm.sh/helm/v3 v3.7.1
 	k8s.io/api v0.22.1
-	k8s.io/apiextensions-apiserver v0.21.4
+	k8s.io/apiextensions-apiserver v0.22.1
 	k8s.io/apimachinery v0.22.1
-	k8s.io/apiserver v0.21.4
+	k8s.io/apiserver v0.22.1
 	k8s.io/client-go v0.22.1
 	k8s.io/cluster-bootstrap v0.21.4
 	k8s.io/component-base v0.22.1
 	k8s.io/klog/v2 v2.9.0
-	k8s.io/kubectl v0.21.4
+	k8s.io/kubectl v0.22.1
 	k8s.io/utils v0.0.0-20210802155522-efc7438f0176
 	sigs.k8s.io/controller-runtime v0.9.7
 	sigs.k8s.io/yaml v1.2.0
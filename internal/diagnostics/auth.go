@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// authorizer authenticates requests with a bearer token using TokenReviews, and authorizes them
+// using SubjectAccessReviews for the non-resource URL being requested, e.g. a ServiceAccount
+// requires a ClusterRole allowing the get verb on the /diagnostics non-resource URL.
+type authorizer struct {
+	tokenReviews         authenticationv1client.TokenReviewInterface
+	subjectAccessReviews authorizationv1client.SubjectAccessReviewInterface
+}
+
+func newAuthorizer(clientSet kubernetes.Interface) *authorizer {
+	return &authorizer{
+		tokenReviews:         clientSet.AuthenticationV1().TokenReviews(),
+		subjectAccessReviews: clientSet.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+// filter wraps the given handler, serving requests only if authenticated and authorized.
+func (a *authorizer) filter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		log := ctrl.Log.WithName("diagnostics")
+
+		token := bearerToken(req)
+		if token == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		tokenReview, err := a.tokenReviews.Create(req.Context(), &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: token},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			log.Error(err, "Failed to authenticate diagnostics request")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if !tokenReview.Status.Authenticated {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user := tokenReview.Status.User
+		extra := map[string]authorizationv1.ExtraValue{}
+		for k, v := range user.Extra {
+			extra[k] = authorizationv1.ExtraValue(v)
+		}
+		subjectAccessReview, err := a.subjectAccessReviews.Create(req.Context(), &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   user.Username,
+				UID:    user.UID,
+				Groups: user.Groups,
+				Extra:  extra,
+				NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+					Path: req.URL.Path,
+					Verb: verb(req),
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			log.Error(err, "Failed to authorize diagnostics request", "user", user.Username)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if !subjectAccessReview.Status.Allowed {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// bearerToken returns the bearer token from the Authorization header of the request, if any.
+func bearerToken(req *http.Request) string {
+	auth := strings.TrimSpace(req.Header.Get("Authorization"))
+	parts := strings.SplitN(auth, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
+
+// verb returns the authorization verb for the request, following the same mapping used by the API server
+// for non-resource URLs.
+func verb(req *http.Request) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return "get"
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return strings.ToLower(req.Method)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestAuthorizerFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		authenticated bool
+		reviewErr     error
+		allowed       bool
+		wantStatus    int
+	}{
+		{
+			name:       "request without token is unauthorized",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "request with a non bearer token is unauthorized",
+			header:     "Basic dXNlcjpwYXNzd29yZA==",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "request with an invalid token is unauthorized",
+			header:        "Bearer token",
+			authenticated: false,
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:       "request failing the token review returns an error",
+			header:     "Bearer token",
+			reviewErr:  errors.New("failed"),
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:          "authenticated request not authorized is forbidden",
+			header:        "Bearer token",
+			authenticated: true,
+			allowed:       false,
+			wantStatus:    http.StatusForbidden,
+		},
+		{
+			name:          "authenticated and authorized request is served",
+			header:        "Bearer token",
+			authenticated: true,
+			allowed:       true,
+			wantStatus:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var sar *authorizationv1.SubjectAccessReview
+			clientSet := fake.NewSimpleClientset()
+			clientSet.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				g.Expect(review.Spec.Token).To(Equal("token"))
+				review.Status.Authenticated = tt.authenticated
+				review.Status.User = authenticationv1.UserInfo{
+					Username: "system:serviceaccount:default:test",
+					Groups:   []string{"system:serviceaccounts"},
+				}
+				return true, review, tt.reviewErr
+			})
+			clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				sar = action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				sar.Status.Allowed = tt.allowed
+				return true, sar, nil
+			})
+
+			handler := newAuthorizer(clientSet).filter(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, DiagnosticsPath, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			g.Expect(rec.Code).To(Equal(tt.wantStatus))
+			if tt.authenticated {
+				g.Expect(sar).ToNot(BeNil())
+				g.Expect(sar.Spec.User).To(Equal("system:serviceaccount:default:test"))
+				g.Expect(sar.Spec.Groups).To(ConsistOf("system:serviceaccounts"))
+				g.Expect(sar.Spec.NonResourceAttributes).To(Equal(&authorizationv1.NonResourceAttributes{
+					Path: DiagnosticsPath,
+					Verb: "get",
+				}))
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// Options are the options for the diagnostics server.
+type Options struct {
+	// BindAddress is the address the diagnostics server binds to; the server is disabled if empty.
+	BindAddress string
+
+	// EnableProfiling enables serving pprof profiles under /debug/pprof/.
+	EnableProfiling bool
+
+	// CertDir is the directory containing the tls.crt and tls.key files used for serving over TLS.
+	// If empty, the server serves over TLS with a self-signed certificate.
+	CertDir string
+
+	// Insecure makes the server serve plain HTTP, and disables authentication and authorization of requests.
+	Insecure bool
+}
+
+// AddFlags adds the flags for the diagnostics server to the given flag set.
+func AddFlags(fs *pflag.FlagSet, o *Options) {
+	fs.StringVar(&o.BindAddress, "diagnostics-address", "",
+		"Bind address to expose the diagnostics endpoint (e.g. :8443). If unspecified, the diagnostics endpoint is disabled.")
+
+	fs.BoolVar(&o.EnableProfiling, "diagnostics-enable-profiling", false,
+		"Enable serving pprof profiles under /debug/pprof/ on the diagnostics endpoint.")
+
+	fs.StringVar(&o.CertDir, "diagnostics-cert-dir", "",
+		"Directory containing the tls.crt and tls.key files used for serving the diagnostics endpoint over TLS. If unspecified, a self-signed certificate is used.")
+
+	fs.BoolVar(&o.Insecure, "diagnostics-insecure", false,
+		"Serve the diagnostics endpoint over plain HTTP, without authentication and authorization. Should only be used for development.")
+}
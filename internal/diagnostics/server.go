@@ -0,0 +1,270 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	certutil "k8s.io/client-go/util/cert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DiagnosticsPath is the path where the diagnostics report is served.
+	DiagnosticsPath = "/diagnostics"
+
+	// ProfilingPath is the path where pprof profiles are served, if enabled.
+	ProfilingPath = "/debug/pprof/"
+
+	// workqueueDepthMetric is the name of the metric reporting the length of the
+	// controllers work queues, as registered by controller-runtime.
+	workqueueDepthMetric = "workqueue_depth"
+
+	cacheSyncTimeout = 100 * time.Millisecond
+	shutdownTimeout  = 5 * time.Second
+)
+
+// ClusterTracker provides the list of workload clusters tracked by a manager,
+// e.g. a remote.ClusterCacheTracker.
+type ClusterTracker interface {
+	TrackedClusters() []client.ObjectKey
+}
+
+// Report is the diagnostics report served by the diagnostics server.
+type Report struct {
+	// CacheSynced is true if all the informers in the manager's cache are synced.
+	CacheSynced bool `json:"cacheSynced"`
+
+	// Controllers reports the length of the work queue of each controller.
+	Controllers []ControllerReport `json:"controllers"`
+
+	// TrackedClusters is the list of workload clusters for which an accessor is being tracked.
+	TrackedClusters []string `json:"trackedClusters,omitempty"`
+}
+
+// ControllerReport reports the length of the work queue of a controller.
+type ControllerReport struct {
+	Name        string `json:"name"`
+	QueueLength int    `json:"queueLength"`
+}
+
+// Server serves diagnostics about a manager.
+type Server struct {
+	options    Options
+	cache      cache.Cache
+	tracker    ClusterTracker
+	authorizer *authorizer
+}
+
+var _ manager.Runnable = &Server{}
+var _ manager.LeaderElectionRunnable = &Server{}
+
+// NewServer returns a diagnostics server for the given manager, to be added to the manager using mgr.Add.
+// The tracker is optional; if set, the workload clusters it tracks are included in the diagnostics report.
+func NewServer(mgr ctrl.Manager, options Options, tracker ClusterTracker) (*Server, error) {
+	if options.BindAddress == "" {
+		return nil, errors.New("diagnostics server bind address must be set")
+	}
+
+	s := &Server{
+		options: options,
+		cache:   mgr.GetCache(),
+		tracker: tracker,
+	}
+	if !options.Insecure {
+		clientSet, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create client for authenticating diagnostics requests")
+		}
+		s.authorizer = newAuthorizer(clientSet)
+	}
+	return s, nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; diagnostics are served by all the replicas.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start starts the diagnostics server and blocks until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	log := ctrl.LoggerFrom(ctx).WithName("diagnostics")
+
+	srv := &http.Server{
+		Addr:    s.options.BindAddress,
+		Handler: s.handler(),
+	}
+	if !s.options.Insecure {
+		tlsConfig, err := s.tlsConfig()
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig = tlsConfig
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("Serving diagnostics", "address", s.options.BindAddress, "profiling", s.options.EnableProfiling, "insecure", s.options.Insecure)
+		if s.options.Insecure {
+			errCh <- srv.ListenAndServe()
+			return
+		}
+		// The certificate is already set in the TLS config.
+		errCh <- srv.ListenAndServeTLS("", "")
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return errors.Wrap(err, "failed to serve diagnostics")
+	}
+}
+
+// tlsConfig returns the TLS config for serving diagnostics.
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	certificate, err := s.certificate()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// certificate returns the certificate in the cert directory if set, or a self-signed certificate otherwise.
+func (s *Server) certificate() (tls.Certificate, error) {
+	if s.options.CertDir != "" {
+		certificate, err := tls.LoadX509KeyPair(filepath.Join(s.options.CertDir, "tls.crt"), filepath.Join(s.options.CertDir, "tls.key"))
+		if err != nil {
+			return tls.Certificate{}, errors.Wrapf(err, "failed to load diagnostics certificate from %s", s.options.CertDir)
+		}
+		return certificate, nil
+	}
+
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "failed to generate self-signed diagnostics certificate")
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "failed to parse self-signed diagnostics certificate")
+	}
+	return certificate, nil
+}
+
+// handler returns the handler serving the diagnostics endpoints, wrapped by
+// authentication and authorization unless running in insecure mode.
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(DiagnosticsPath, s.serveReport)
+	if s.options.EnableProfiling {
+		mux.HandleFunc(ProfilingPath, pprof.Index)
+		mux.HandleFunc(ProfilingPath+"cmdline", pprof.Cmdline)
+		mux.HandleFunc(ProfilingPath+"profile", pprof.Profile)
+		mux.HandleFunc(ProfilingPath+"symbol", pprof.Symbol)
+		mux.HandleFunc(ProfilingPath+"trace", pprof.Trace)
+	}
+
+	if s.authorizer == nil {
+		return mux
+	}
+	return s.authorizer.filter(mux)
+}
+
+func (s *Server) serveReport(w http.ResponseWriter, req *http.Request) {
+	report, err := s.report(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+}
+
+// report collects the diagnostics report.
+func (s *Server) report(ctx context.Context) (*Report, error) {
+	controllers, err := controllerQueueLengths()
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not wait for the cache to sync, only check if it is already synced.
+	syncCtx, cancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer cancel()
+
+	report := &Report{
+		CacheSynced: s.cache.WaitForCacheSync(syncCtx),
+		Controllers: controllers,
+	}
+	if s.tracker != nil {
+		for _, cluster := range s.tracker.TrackedClusters() {
+			report.TrackedClusters = append(report.TrackedClusters, cluster.String())
+		}
+	}
+	return report, nil
+}
+
+// controllerQueueLengths returns the length of the controllers work queues, as reported by the
+// metrics registered by controller-runtime, sorted by controller name.
+func controllerQueueLengths() ([]ControllerReport, error) {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to gather metrics")
+	}
+
+	controllers := []ControllerReport{}
+	for _, family := range families {
+		if family.GetName() != workqueueDepthMetric {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() != "name" {
+					continue
+				}
+				controllers = append(controllers, ControllerReport{
+					Name:        label.GetValue(),
+					QueueLength: int(metric.GetGauge().GetValue()),
+				})
+			}
+		}
+	}
+	sort.Slice(controllers, func(i, j int) bool {
+		return controllers[i].Name < controllers[j].Name
+	})
+	return controllers, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fakeCache struct {
+	cache.Cache
+	synced bool
+}
+
+func (c *fakeCache) WaitForCacheSync(_ context.Context) bool {
+	return c.synced
+}
+
+type fakeTracker []client.ObjectKey
+
+func (t fakeTracker) TrackedClusters() []client.ObjectKey {
+	return t
+}
+
+func TestServerReport(t *testing.T) {
+	g := NewWithT(t)
+
+	// Work queues created by controller-runtime report their length through the workqueue_depth metric.
+	queue := workqueue.NewNamed("diagnostics-test")
+	defer queue.ShutDown()
+	queue.Add("a")
+	queue.Add("b")
+
+	s := &Server{
+		options: Options{BindAddress: ":0", Insecure: true},
+		cache:   &fakeCache{synced: true},
+		tracker: fakeTracker{
+			{Namespace: "ns1", Name: "cluster1"},
+			{Namespace: "ns2", Name: "cluster2"},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DiagnosticsPath, nil))
+	g.Expect(rec.Code).To(Equal(http.StatusOK))
+
+	report := &Report{}
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), report)).To(Succeed())
+	g.Expect(report.CacheSynced).To(BeTrue())
+	g.Expect(report.Controllers).To(ContainElement(ControllerReport{Name: "diagnostics-test", QueueLength: 2}))
+	g.Expect(report.TrackedClusters).To(Equal([]string{"ns1/cluster1", "ns2/cluster2"}))
+}
+
+func TestServerProfiling(t *testing.T) {
+	tests := []struct {
+		name            string
+		enableProfiling bool
+		wantStatus      int
+	}{
+		{
+			name:            "profiling endpoints are served if enabled",
+			enableProfiling: true,
+			wantStatus:      http.StatusOK,
+		},
+		{
+			name:            "profiling endpoints are not served if disabled",
+			enableProfiling: false,
+			wantStatus:      http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s := &Server{
+				options: Options{BindAddress: ":0", Insecure: true, EnableProfiling: tt.enableProfiling},
+				cache:   &fakeCache{},
+			}
+
+			rec := httptest.NewRecorder()
+			s.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ProfilingPath+"cmdline", nil))
+			g.Expect(rec.Code).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+func TestServerCertificate(t *testing.T) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("diagnostics.example.com", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(certDir, "tls.crt"), certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(certDir, "tls.key"), keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		certDir     string
+		wantDNSName string
+		wantErr     bool
+	}{
+		{
+			name:        "uses a self-signed certificate if the cert dir is not set",
+			wantDNSName: "localhost",
+		},
+		{
+			name:        "uses the certificate in the cert dir if set",
+			certDir:     certDir,
+			wantDNSName: "diagnostics.example.com",
+		},
+		{
+			name:    "fails if the cert dir does not contain a certificate",
+			certDir: t.TempDir(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s := &Server{
+				options: Options{BindAddress: ":0", CertDir: tt.certDir},
+			}
+
+			tlsConfig, err := s.tlsConfig()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tlsConfig.Certificates).To(HaveLen(1))
+
+			leaf, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(leaf.DNSNames).To(ContainElement(tt.wantDNSName))
+		})
+	}
+}
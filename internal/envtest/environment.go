@@ -253,6 +253,12 @@ func new(uncachedObjs ...client.Object) *Environment {
 	if err := (&expv1.MachinePool{}).SetupWebhookWithManager(mgr); err != nil {
 		klog.Fatalf("unable to create webhook for machinepool: %+v", err)
 	}
+	if err := (&expv1.ClusterSet{}).SetupWebhookWithManager(mgr); err != nil {
+		klog.Fatalf("unable to create webhook for clusterset: %+v", err)
+	}
+	if err := (&expv1.HostClaim{}).SetupWebhookWithManager(mgr); err != nil {
+		klog.Fatalf("unable to create webhook for hostclaim: %+v", err)
+	}
 
 	return &Environment{
 		Manager: mgr,
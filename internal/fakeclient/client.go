@@ -0,0 +1,308 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeclient
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Funcs contains functions intercepting calls to the fake client.
+// If a function returns an error the error is returned to the caller without
+// calling the underlying fake client, otherwise the call goes through.
+type Funcs struct {
+	Get    func(ctx context.Context, key client.ObjectKey, obj client.Object) error
+	List   func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error
+	Create func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error
+	Update func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error
+	Patch  func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error
+	Delete func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error
+}
+
+// ConflictOnUpdate returns an intercepting function returning a Conflict error for the first n Update calls.
+func ConflictOnUpdate(n int) func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	count := 0
+	return func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+		if count >= n {
+			return nil
+		}
+		count++
+		return apierrors.NewConflict(schema.GroupResource{}, obj.GetName(), errors.New("simulated conflict"))
+	}
+}
+
+// ConflictOnPatch returns an intercepting function returning a Conflict error for the first n Patch calls.
+func ConflictOnPatch(n int) func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	count := 0
+	return func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+		if count >= n {
+			return nil
+		}
+		count++
+		return apierrors.NewConflict(schema.GroupResource{}, obj.GetName(), errors.New("simulated conflict"))
+	}
+}
+
+// ClientBuilder builds a fake client.
+type ClientBuilder struct {
+	builder               *fake.ClientBuilder
+	funcs                 Funcs
+	ignoreResourceVersion bool
+}
+
+// NewClientBuilder returns a new builder to create a fake client.
+func NewClientBuilder() *ClientBuilder {
+	return &ClientBuilder{
+		builder: fake.NewClientBuilder(),
+	}
+}
+
+// WithScheme sets the scheme used by the client.
+func (b *ClientBuilder) WithScheme(scheme *runtime.Scheme) *ClientBuilder {
+	b.builder.WithScheme(scheme)
+	return b
+}
+
+// WithObjects adds the initial objects to the client.
+// NOTE: the objects are copied, because the fake client sets the resourceVersion of the initial objects; this keeps
+// them usable as expected objects in tests.
+func (b *ClientBuilder) WithObjects(objs ...client.Object) *ClientBuilder {
+	for _, obj := range objs {
+		b.builder.WithObjects(obj.DeepCopyObject().(client.Object))
+	}
+	return b
+}
+
+// WithInterceptors sets the functions intercepting calls to the client.
+func (b *ClientBuilder) WithInterceptors(funcs Funcs) *ClientBuilder {
+	b.funcs = funcs
+	return b
+}
+
+// WithoutResourceVersion makes the client drop the resourceVersion from objects being returned to the caller,
+// so they can be compared to the objects used for creating the client without the need of ignoring
+// the resourceVersion set by the fake client; the resourceVersion is transparently restored on updates.
+func (b *ClientBuilder) WithoutResourceVersion() *ClientBuilder {
+	b.ignoreResourceVersion = true
+	return b
+}
+
+// Build builds the fake client.
+func (b *ClientBuilder) Build() client.Client {
+	return &Client{
+		Client:                b.builder.Build(),
+		funcs:                 b.funcs,
+		ignoreResourceVersion: b.ignoreResourceVersion,
+	}
+}
+
+// Client wraps a controller-runtime fake client.
+type Client struct {
+	client.Client
+	funcs                 Funcs
+	ignoreResourceVersion bool
+}
+
+var _ client.Client = &Client{}
+
+// Get retrieves an obj for the given object key.
+func (c *Client) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if c.funcs.Get != nil {
+		if err := c.funcs.Get(ctx, key, obj); err != nil {
+			return err
+		}
+	}
+	if err := c.Client.Get(ctx, key, obj); err != nil {
+		return err
+	}
+	c.dropResourceVersion(obj)
+	return nil
+}
+
+// List retrieves list of objects for a given namespace and list options.
+func (c *Client) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if c.funcs.List != nil {
+		if err := c.funcs.List(ctx, list, opts...); err != nil {
+			return err
+		}
+	}
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	if !c.ignoreResourceVersion {
+		return nil
+	}
+	return meta.EachListItem(list, func(o runtime.Object) error {
+		if obj, ok := o.(metav1.Object); ok {
+			obj.SetResourceVersion("")
+		}
+		return nil
+	})
+}
+
+// Create saves the object obj.
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if c.funcs.Create != nil {
+		if err := c.funcs.Create(ctx, obj, opts...); err != nil {
+			return err
+		}
+	}
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.dropResourceVersion(obj)
+	return nil
+}
+
+// Update updates the given obj.
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.funcs.Update != nil {
+		if err := c.funcs.Update(ctx, obj, opts...); err != nil {
+			return err
+		}
+	}
+	if err := c.restoreResourceVersion(ctx, obj); err != nil {
+		return err
+	}
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.dropResourceVersion(obj)
+	return nil
+}
+
+// Patch patches the given obj; in case of server side apply, the object is created if it does not exist,
+// otherwise the applied configuration is merged into the existing object.
+// NOTE: field ownership is not tracked, so applying an object never removes fields from the existing object.
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.funcs.Patch != nil {
+		if err := c.funcs.Patch(ctx, obj, patch, opts...); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if patch.Type() == types.ApplyPatchType {
+		err = c.apply(ctx, obj, opts...)
+	} else {
+		err = c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	if err != nil {
+		return err
+	}
+	c.dropResourceVersion(obj)
+	return nil
+}
+
+// Delete deletes the given obj.
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.funcs.Delete != nil {
+		if err := c.funcs.Delete(ctx, obj, opts...); err != nil {
+			return err
+		}
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+// apply implements server side apply on top of the fake client, which does not support the apply patch type.
+func (c *Client) apply(ctx context.Context, obj client.Object, opts ...client.PatchOption) error {
+	patchOptions := &client.PatchOptions{}
+	patchOptions.ApplyOptions(opts)
+	dryRun := len(patchOptions.DryRun) > 0 && patchOptions.DryRun[0] == metav1.DryRunAll
+
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(gvk)
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		// The object does not exist yet, create it.
+		obj.SetResourceVersion("")
+		createOpts := []client.CreateOption{}
+		if dryRun {
+			createOpts = append(createOpts, client.DryRunAll)
+		}
+		return c.Client.Create(ctx, obj, createOpts...)
+	}
+
+	// The object already exists, merge the applied configuration into it.
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", gvk.Kind)
+	}
+	applied := map[string]interface{}{}
+	if err := json.Unmarshal(data, &applied); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s", gvk.Kind)
+	}
+	unstructured.RemoveNestedField(applied, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(applied, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(applied, "metadata", "managedFields")
+	if data, err = json.Marshal(applied); err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", gvk.Kind)
+	}
+
+	patchOpts := []client.PatchOption{}
+	if dryRun {
+		patchOpts = append(patchOpts, client.DryRunAll)
+	}
+	return c.Client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data), patchOpts...)
+}
+
+// dropResourceVersion drops the resourceVersion from an object being returned to the caller, if required.
+func (c *Client) dropResourceVersion(obj client.Object) {
+	if c.ignoreResourceVersion {
+		obj.SetResourceVersion("")
+	}
+}
+
+// restoreResourceVersion sets the current resourceVersion on an object without one, if resourceVersion
+// is not being returned to the caller; this prevents the fake client from failing updates.
+func (c *Client) restoreResourceVersion(ctx context.Context, obj client.Object) error {
+	if !c.ignoreResourceVersion || obj.GetResourceVersion() != "" {
+		return nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(gvk)
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return err
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return nil
+}
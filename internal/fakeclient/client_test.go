@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeclient
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ctx = context.Background()
+
+func newScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func newCluster() *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "cluster1",
+		},
+	}
+}
+
+func TestClientApply(t *testing.T) {
+	t.Run("creates the object if it does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := NewClientBuilder().WithScheme(newScheme(g)).Build()
+
+		cluster := newCluster()
+		cluster.Spec.Paused = true
+		g.Expect(c.Patch(ctx, cluster, client.Apply, client.FieldOwner("test"))).To(Succeed())
+
+		got := &clusterv1.Cluster{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), got)).To(Succeed())
+		g.Expect(got.Spec.Paused).To(BeTrue())
+	})
+
+	t.Run("merges the applied configuration into the existing object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing := newCluster()
+		existing.Labels = map[string]string{"foo": "bar"}
+		c := NewClientBuilder().WithScheme(newScheme(g)).WithObjects(existing).Build()
+
+		cluster := newCluster()
+		cluster.Spec.Paused = true
+		g.Expect(c.Patch(ctx, cluster, client.Apply, client.FieldOwner("test"))).To(Succeed())
+
+		got := &clusterv1.Cluster{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), got)).To(Succeed())
+		g.Expect(got.Spec.Paused).To(BeTrue())
+		g.Expect(got.Labels).To(HaveKeyWithValue("foo", "bar"))
+	})
+
+	t.Run("does not change the object in dry run", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := NewClientBuilder().WithScheme(newScheme(g)).Build()
+
+		cluster := newCluster()
+		g.Expect(c.Patch(ctx, cluster, client.Apply, client.FieldOwner("test"), client.DryRunAll)).To(Succeed())
+
+		err := c.Get(ctx, client.ObjectKeyFromObject(cluster), &clusterv1.Cluster{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+}
+
+func TestClientWithoutResourceVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	c := NewClientBuilder().WithScheme(newScheme(g)).WithObjects(newCluster()).WithoutResourceVersion().Build()
+
+	got := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(newCluster()), got)).To(Succeed())
+	g.Expect(got.ResourceVersion).To(BeEmpty())
+
+	list := &clusterv1.ClusterList{}
+	g.Expect(c.List(ctx, list)).To(Succeed())
+	g.Expect(list.Items).To(HaveLen(1))
+	g.Expect(list.Items[0].ResourceVersion).To(BeEmpty())
+
+	// Updates are expected to succeed even if the object has no resourceVersion.
+	got.Spec.Paused = true
+	g.Expect(c.Update(ctx, got)).To(Succeed())
+	g.Expect(got.ResourceVersion).To(BeEmpty())
+
+	updated := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(got), updated)).To(Succeed())
+	g.Expect(updated.Spec.Paused).To(BeTrue())
+}
+
+func TestClientInterceptors(t *testing.T) {
+	g := NewWithT(t)
+
+	c := NewClientBuilder().
+		WithScheme(newScheme(g)).
+		WithObjects(newCluster()).
+		WithInterceptors(Funcs{
+			Update: ConflictOnUpdate(1),
+			Patch:  ConflictOnPatch(2),
+		}).
+		Build()
+
+	cluster := &clusterv1.Cluster{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(newCluster()), cluster)).To(Succeed())
+
+	// The first Update is expected to fail with a conflict, the following ones to succeed.
+	err := c.Update(ctx, cluster)
+	g.Expect(apierrors.IsConflict(err)).To(BeTrue())
+	g.Expect(c.Update(ctx, cluster)).To(Succeed())
+
+	// The first two Patch calls are expected to fail with a conflict, the following ones to succeed.
+	patch := client.MergeFrom(cluster.DeepCopy())
+	cluster.Spec.Paused = true
+	for i := 0; i < 2; i++ {
+		err = c.Patch(ctx, cluster, patch)
+		g.Expect(apierrors.IsConflict(err)).To(BeTrue())
+	}
+	g.Expect(c.Patch(ctx, cluster, patch)).To(Succeed())
+}
@@ -95,6 +95,7 @@ type ClusterClassBuilder struct {
 	controlPlaneTemplate                      *unstructured.Unstructured
 	controlPlaneInfrastructureMachineTemplate *unstructured.Unstructured
 	machineDeploymentClasses                  []clusterv1.MachineDeploymentClass
+	variables                                 []clusterv1.ClusterClassVariable
 }
 
 // NewClusterClassBuilder returns a ClusterClassBuilder with the given name and namespace.
@@ -141,6 +142,12 @@ func (c *ClusterClassBuilder) WithWorkerMachineDeploymentClasses(mdcs []clusterv
 	return c
 }
 
+// WithVariables adds the Variables to the ClusterClassBuilder.
+func (c *ClusterClassBuilder) WithVariables(vars ...clusterv1.ClusterClassVariable) *ClusterClassBuilder {
+	c.variables = vars
+	return c
+}
+
 // Build takes the objects and variables in the ClusterClass builder and uses them to create a ClusterClass object.
 func (c *ClusterClassBuilder) Build() *clusterv1.ClusterClass {
 	obj := &clusterv1.ClusterClass{
@@ -173,6 +180,7 @@ func (c *ClusterClassBuilder) Build() *clusterv1.ClusterClass {
 		}
 	}
 	obj.Spec.Workers.MachineDeployments = c.machineDeploymentClasses
+	obj.Spec.Variables = c.variables
 	return obj
 }
 
@@ -279,8 +287,9 @@ func (i *InfrastructureMachineTemplateBuilder) Build() *unstructured.Unstructure
 
 // BootstrapTemplateBuilder holds the variables needed to build a generic BootstrapTemplate.
 type BootstrapTemplateBuilder struct {
-	namespace string
-	name      string
+	namespace  string
+	name       string
+	specFields map[string]interface{}
 }
 
 // NewBootstrapTemplateBuilder creates a BootstrapTemplateBuilder with the given name and namespace.
@@ -291,6 +300,12 @@ func NewBootstrapTemplateBuilder(namespace, name string) *BootstrapTemplateBuild
 	}
 }
 
+// WithSpecFields will add fields of any type to the object spec. It takes an argument, fields, which is of the form path: object.
+func (b *BootstrapTemplateBuilder) WithSpecFields(fields map[string]interface{}) *BootstrapTemplateBuilder {
+	b.specFields = fields
+	return b
+}
+
 // Build creates a new Unstructured object with the information passed to the BootstrapTemplateBuilder.
 func (b *BootstrapTemplateBuilder) Build() *unstructured.Unstructured {
 	obj := &unstructured.Unstructured{}
@@ -299,6 +314,7 @@ func (b *BootstrapTemplateBuilder) Build() *unstructured.Unstructured {
 	obj.SetNamespace(b.namespace)
 	obj.SetName(b.name)
 
+	setSpecFields(obj, b.specFields)
 	return obj
 }
 
@@ -495,6 +511,7 @@ type MachineDeploymentBuilder struct {
 	replicas               *int32
 	generation             *int64
 	labels                 map[string]string
+	annotations            map[string]string
 	status                 *clusterv1.MachineDeploymentStatus
 }
 
@@ -524,6 +541,12 @@ func (m *MachineDeploymentBuilder) WithLabels(labels map[string]string) *Machine
 	return m
 }
 
+// WithAnnotations adds the given annotations to the MachineDeploymentBuilder.
+func (m *MachineDeploymentBuilder) WithAnnotations(annotations map[string]string) *MachineDeploymentBuilder {
+	m.annotations = annotations
+	return m
+}
+
 // WithVersion sets the passed version on the machine deployment spec.
 func (m *MachineDeploymentBuilder) WithVersion(version string) *MachineDeploymentBuilder {
 	m.version = &version
@@ -557,9 +580,10 @@ func (m *MachineDeploymentBuilder) Build() *clusterv1.MachineDeployment {
 			APIVersion: clusterv1.GroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      m.name,
-			Namespace: m.namespace,
-			Labels:    m.labels,
+			Name:        m.name,
+			Namespace:   m.namespace,
+			Labels:      m.labels,
+			Annotations: m.annotations,
 		},
 	}
 	if m.generation != nil {
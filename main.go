@@ -38,6 +38,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers"
+	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/controllers/topology"
 	addonsv1alpha3 "sigs.k8s.io/cluster-api/exp/addons/api/v1alpha3"
@@ -49,6 +50,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	expcontrollers "sigs.k8s.io/cluster-api/exp/controllers"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/diagnostics"
 	"sigs.k8s.io/cluster-api/version"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -69,18 +71,27 @@ var (
 	watchNamespace                string
 	watchFilterValue              string
 	profilerAddress               string
+	diagnosticsOptions            diagnostics.Options
 	clusterTopologyConcurrency    int
+	clusterTopologyDryRun         bool
+	clusterTopologyMDConcurrency  int
+	clusterClassNamespaces        []string
 	clusterConcurrency            int
 	machineConcurrency            int
+	machineLifecycleHookTimeout   time.Duration
 	machineSetConcurrency         int
 	machineDeploymentConcurrency  int
 	machinePoolConcurrency        int
+	clusterSetConcurrency         int
+	hostClaimConcurrency          int
 	clusterResourceSetConcurrency int
 	machineHealthCheckConcurrency int
 	syncPeriod                    time.Duration
 	webhookPort                   int
 	webhookCertDir                string
 	healthAddr                    string
+	configFile                    string
+	configWatchInterval           time.Duration
 )
 
 func init() {
@@ -129,16 +140,31 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&profilerAddress, "profiler-address", "",
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)")
+	_ = fs.MarkDeprecated("profiler-address", "use --diagnostics-address together with --diagnostics-enable-profiling instead")
+
+	diagnostics.AddFlags(fs, &diagnosticsOptions)
 
 	fs.IntVar(&clusterTopologyConcurrency, "clustertopology-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.BoolVar(&clusterTopologyDryRun, "clustertopology-dry-run", false,
+		"If true, the managed topology controller only logs the changes required to align Clusters to their topology, without applying them")
+
+	fs.IntVar(&clusterTopologyMDConcurrency, "clustertopology-machinedeployment-concurrency", 5,
+		"Number of MachineDeployments of a cluster to reconcile simultaneously in the managed topology controller")
+
+	fs.StringSliceVar(&clusterClassNamespaces, "clusterclass-namespaces", nil,
+		"Comma-separated list of namespaces whose ClusterClasses can be used by Clusters in other namespaces, e.g. to publish a central catalog of ClusterClasses")
+
 	fs.IntVar(&clusterConcurrency, "cluster-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
 	fs.IntVar(&machineConcurrency, "machine-concurrency", 10,
 		"Number of machines to process simultaneously")
 
+	fs.DurationVar(&machineLifecycleHookTimeout, "machine-lifecycle-hook-timeout", 0,
+		"The maximum amount of time a Machine deletion waits for its pre-drain.delete and pre-terminate.delete lifecycle hooks to be removed. Defaults to 0, meaning no timeout.")
+
 	fs.IntVar(&machineSetConcurrency, "machineset-concurrency", 10,
 		"Number of machine sets to process simultaneously")
 
@@ -148,6 +174,12 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&machinePoolConcurrency, "machinepool-concurrency", 10,
 		"Number of machine pools to process simultaneously")
 
+	fs.IntVar(&clusterSetConcurrency, "clusterset-concurrency", 10,
+		"Number of cluster sets to process simultaneously")
+
+	fs.IntVar(&hostClaimConcurrency, "hostclaim-concurrency", 10,
+		"Number of host claims to process simultaneously")
+
 	fs.IntVar(&clusterResourceSetConcurrency, "clusterresourceset-concurrency", 10,
 		"Number of cluster resource sets to process simultaneously")
 
@@ -166,6 +198,12 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.StringVar(&configFile, "config", "",
+		"Path to a configuration file defining feature gates and controller settings, e.g. mounted from a ConfigMap. Flags explicitly set on the command line take precedence over the configuration file.")
+
+	fs.DurationVar(&configWatchInterval, "config-watch-interval", 30*time.Second,
+		"Interval at which the configuration file is checked for changes; when a change is detected the manager is stopped so the new configuration is applied on restart. Set to 0 to disable.")
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -179,6 +217,21 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	// NOTE: This is used both by the Cluster webhook and by the topology controller.
+	clusterv1.SetClusterClassNamespaces(clusterClassNamespaces...)
+
+	if configFile != "" {
+		config, err := feature.LoadConfiguration(configFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load configuration file")
+			os.Exit(1)
+		}
+		if err := config.Apply(feature.MutableGates, pflag.CommandLine); err != nil {
+			setupLog.Error(err, "unable to apply configuration file")
+			os.Exit(1)
+		}
+	}
+
 	if profilerAddress != "" {
 		klog.Infof("Profiler listening for requests at %s", profilerAddress)
 		go func() {
@@ -213,10 +266,12 @@ func main() {
 
 	// Setup the context that's going to be used in controllers and for the manager.
 	ctx := ctrl.SetupSignalHandler()
+	ctx = setupConfigWatch(ctx)
 
 	setupChecks(mgr)
 	setupIndexes(ctx, mgr)
-	setupReconcilers(ctx, mgr)
+	tracker := setupReconcilers(ctx, mgr)
+	setupDiagnostics(mgr, tracker)
 	setupWebhooks(mgr)
 
 	// +kubebuilder:scaffold:builder
@@ -227,6 +282,25 @@ func main() {
 	}
 }
 
+// setupConfigWatch returns a context that is cancelled, thus stopping the manager, when the configuration file changes.
+func setupConfigWatch(ctx context.Context) context.Context {
+	if configFile == "" || configWatchInterval <= 0 {
+		return ctx
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		err := feature.WatchConfiguration(ctx, configFile, configWatchInterval, func() {
+			setupLog.Info("Configuration file changed, stopping the manager to apply the new configuration", "path", configFile)
+			cancel()
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to watch configuration file")
+		}
+	}()
+	return ctx
+}
+
 func setupChecks(mgr ctrl.Manager) {
 	if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
 		setupLog.Error(err, "unable to create ready check")
@@ -239,6 +313,22 @@ func setupChecks(mgr ctrl.Manager) {
 	}
 }
 
+func setupDiagnostics(mgr ctrl.Manager, tracker diagnostics.ClusterTracker) {
+	if diagnosticsOptions.BindAddress == "" {
+		return
+	}
+
+	server, err := diagnostics.NewServer(mgr, diagnosticsOptions, tracker)
+	if err != nil {
+		setupLog.Error(err, "unable to create diagnostics server")
+		os.Exit(1)
+	}
+	if err := mgr.Add(server); err != nil {
+		setupLog.Error(err, "unable to add diagnostics server")
+		os.Exit(1)
+	}
+}
+
 func setupIndexes(ctx context.Context, mgr ctrl.Manager) {
 	if err := index.AddDefaultIndexes(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to setup indexes")
@@ -246,7 +336,7 @@ func setupIndexes(ctx context.Context, mgr ctrl.Manager) {
 	}
 }
 
-func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
+func setupReconcilers(ctx context.Context, mgr ctrl.Manager) *remote.ClusterCacheTracker {
 	// Set up a ClusterCacheTracker and ClusterCacheReconciler to provide to controllers
 	// requiring a connection to a remote cluster
 	tracker, err := remote.NewClusterCacheTracker(
@@ -270,6 +360,18 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// Set up an ObjectTracker shared by all the controllers watching external objects, like
+	// infrastructure or bootstrap objects; only metadata is cached, given that those controllers
+	// read external objects with a live client.
+	externalTracker, err := external.NewObjectTracker(mgr, external.ObjectTrackerOptions{
+		Namespace:       watchNamespace,
+		PartialMetadata: true,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to create external object tracker")
+		os.Exit(1)
+	}
+
 	if feature.Gates.Enabled(feature.ClusterTopology) {
 		unstructuredCachingClient, err := client.NewDelegatingClient(
 			client.NewDelegatingClientInput{
@@ -287,14 +389,25 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 
 		if err := (&topology.ClusterReconciler{
-			Client:                    mgr.GetClient(),
-			UnstructuredCachingClient: unstructuredCachingClient,
-			WatchFilterValue:          watchFilterValue,
+			Client:                       mgr.GetClient(),
+			UnstructuredCachingClient:    unstructuredCachingClient,
+			ExternalTracker:              externalTracker,
+			WatchFilterValue:             watchFilterValue,
+			DryRun:                       clusterTopologyDryRun,
+			MachineDeploymentConcurrency: clusterTopologyMDConcurrency,
 		}).SetupWithManager(ctx, mgr, concurrency(clusterTopologyConcurrency)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ClusterTopology")
 			os.Exit(1)
 		}
 
+		if err := (&topology.ClusterClassReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterClass")
+			os.Exit(1)
+		}
+
 		if err := (&topology.MachineDeploymentReconciler{
 			Client:           mgr.GetClient(),
 			APIReader:        mgr.GetAPIReader(),
@@ -315,15 +428,18 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 	if err := (&controllers.ClusterReconciler{
 		Client:           mgr.GetClient(),
+		ExternalTracker:  externalTracker,
 		WatchFilterValue: watchFilterValue,
 	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
 	if err := (&controllers.MachineReconciler{
-		Client:           mgr.GetClient(),
-		Tracker:          tracker,
-		WatchFilterValue: watchFilterValue,
+		Client:               mgr.GetClient(),
+		Tracker:              tracker,
+		ExternalTracker:      externalTracker,
+		WatchFilterValue:     watchFilterValue,
+		LifecycleHookTimeout: machineLifecycleHookTimeout,
 	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
@@ -347,6 +463,7 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if feature.Gates.Enabled(feature.MachinePool) {
 		if err := (&expcontrollers.MachinePoolReconciler{
 			Client:           mgr.GetClient(),
+			ExternalTracker:  externalTracker,
 			WatchFilterValue: watchFilterValue,
 		}).SetupWithManager(ctx, mgr, concurrency(machinePoolConcurrency)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "MachinePool")
@@ -354,6 +471,27 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 	}
 
+	if feature.Gates.Enabled(feature.ClusterSet) {
+		if err := (&expcontrollers.ClusterSetReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(clusterSetConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterSet")
+			os.Exit(1)
+		}
+	}
+
+	if feature.Gates.Enabled(feature.HostClaim) {
+		if err := (&expcontrollers.HostClaimReconciler{
+			Client:           mgr.GetClient(),
+			ExternalTracker:  externalTracker,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(hostClaimConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "HostClaim")
+			os.Exit(1)
+		}
+	}
+
 	if feature.Gates.Enabled(feature.ClusterResourceSet) {
 		if err := (&addonscontrollers.ClusterResourceSetReconciler{
 			Client:           mgr.GetClient(),
@@ -380,6 +518,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineHealthCheck")
 		os.Exit(1)
 	}
+
+	return tracker
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -419,6 +559,22 @@ func setupWebhooks(mgr ctrl.Manager) {
 		}
 	}
 
+	// NOTE: ClusterSets require managed topologies, which are behind ClusterTopology feature gate flag; the webhook
+	// is going to prevent creating or updating ClusterSets in case the feature flag is disabled.
+	if feature.Gates.Enabled(feature.ClusterSet) {
+		if err := (&expv1.ClusterSet{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterSet")
+			os.Exit(1)
+		}
+	}
+
+	if feature.Gates.Enabled(feature.HostClaim) {
+		if err := (&expv1.HostClaim{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HostClaim")
+			os.Exit(1)
+		}
+	}
+
 	if feature.Gates.Enabled(feature.ClusterResourceSet) {
 		if err := (&addonsv1.ClusterResourceSet{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterResourceSet")
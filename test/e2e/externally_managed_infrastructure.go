@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/framework"
+	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExternallyManagedInfrastructureSpecInput is the input for ExternallyManagedInfrastructureSpec.
+type ExternallyManagedInfrastructureSpecInput struct {
+	E2EConfig             *clusterctl.E2EConfig
+	ClusterctlConfigPath  string
+	BootstrapClusterProxy framework.ClusterProxy
+	ArtifactFolder        string
+	SkipCleanup           bool
+
+	// Flavor, if specified, must refer to a template that contains a Cluster without a control plane
+	// and a DockerCluster with the cluster.x-k8s.io/managed-by annotation and the load balancer disabled.
+	// If not specified, "externally-managed" is used.
+	Flavor *string
+}
+
+// ExternallyManagedInfrastructureSpec implements a test that verifies that an infrastructure cluster
+// with the managed-by annotation is ignored by its provider, and that the Cluster becomes ready as soon
+// as the external management system reports the infrastructure as ready.
+func ExternallyManagedInfrastructureSpec(ctx context.Context, inputGetter func() ExternallyManagedInfrastructureSpecInput) {
+	var (
+		specName      = "externally-managed"
+		input         ExternallyManagedInfrastructureSpecInput
+		namespace     *corev1.Namespace
+		cancelWatches context.CancelFunc
+		cluster       *clusterv1.Cluster
+	)
+
+	SetDefaultEventuallyTimeout(5 * time.Minute)
+	SetDefaultEventuallyPollingInterval(10 * time.Second)
+
+	BeforeEach(func() {
+		Expect(ctx).NotTo(BeNil(), "ctx is required for %s spec", specName)
+		input = inputGetter()
+		Expect(input.E2EConfig).ToNot(BeNil(), "Invalid argument. input.E2EConfig can't be nil when calling %s spec", specName)
+		Expect(input.ClusterctlConfigPath).To(BeAnExistingFile(), "Invalid argument. input.ClusterctlConfigPath must be an existing file when calling %s spec", specName)
+		Expect(input.BootstrapClusterProxy).ToNot(BeNil(), "Invalid argument. input.BootstrapClusterProxy can't be nil when calling %s spec", specName)
+		Expect(os.MkdirAll(input.ArtifactFolder, 0750)).To(Succeed(), "Invalid argument. input.ArtifactFolder can't be created for %s spec", specName)
+		Expect(input.E2EConfig.Variables).To(HaveKey(KubernetesVersion))
+
+		// Setup a Namespace where to host objects for this spec and create a watcher for the namespace events.
+		namespace, cancelWatches = setupSpecNamespace(ctx, specName, input.BootstrapClusterProxy, input.ArtifactFolder)
+	})
+
+	It("Should wait for the external management system to mark the infrastructure as ready", func() {
+		By("Creating a workload cluster with an externally managed DockerCluster")
+
+		clusterName := fmt.Sprintf("%s-%s", specName, util.RandomString(6))
+		client := input.BootstrapClusterProxy.GetClient()
+		WaitForClusterIntervals := input.E2EConfig.GetIntervals(specName, "wait-cluster")
+
+		workloadClusterTemplate := clusterctl.ConfigCluster(ctx, clusterctl.ConfigClusterInput{
+			// pass reference to the management cluster hosting this test
+			KubeconfigPath: input.BootstrapClusterProxy.GetKubeconfigPath(),
+			// pass the clusterctl config file that points to the local provider repository created for this test,
+			ClusterctlConfigPath: input.ClusterctlConfigPath,
+			// select template
+			Flavor: pointer.StringDeref(input.Flavor, "externally-managed"),
+			// define template variables
+			Namespace:                namespace.Name,
+			ClusterName:              clusterName,
+			KubernetesVersion:        input.E2EConfig.GetVariable(KubernetesVersion),
+			InfrastructureProvider:   clusterctl.DefaultInfrastructureProvider,
+			ControlPlaneMachineCount: pointer.Int64Ptr(0),
+			WorkerMachineCount:       pointer.Int64Ptr(0),
+			// setup clusterctl logs folder
+			LogFolder: filepath.Join(input.ArtifactFolder, "clusters", input.BootstrapClusterProxy.GetName()),
+		})
+		Expect(workloadClusterTemplate).ToNot(BeNil(), "Failed to get the cluster template")
+		Expect(input.BootstrapClusterProxy.Apply(ctx, workloadClusterTemplate)).To(Succeed())
+
+		cluster = framework.GetClusterByName(ctx, framework.GetClusterByNameInput{
+			Getter:    client,
+			Name:      clusterName,
+			Namespace: namespace.Name,
+		})
+
+		By("Waiting for the Cluster to report it is waiting for the externally managed infrastructure")
+		Eventually(func() (string, error) {
+			c := &clusterv1.Cluster{}
+			if err := client.Get(ctx, ctrlclient.ObjectKeyFromObject(cluster), c); err != nil {
+				return "", err
+			}
+			if !conditions.IsFalse(c, clusterv1.InfrastructureReadyCondition) {
+				return "", nil
+			}
+			return conditions.GetReason(c, clusterv1.InfrastructureReadyCondition), nil
+		}, WaitForClusterIntervals...).Should(Equal(clusterv1.WaitingForExternallyManagedInfrastructureReason))
+
+		By("Checking the DockerCluster is not reconciled by CAPD")
+		dockerCluster := &unstructured.Unstructured{}
+		dockerCluster.SetGroupVersionKind(cluster.Spec.InfrastructureRef.GroupVersionKind())
+		dockerClusterKey := ctrlclient.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+		Consistently(func() ([]string, error) {
+			if err := client.Get(ctx, dockerClusterKey, dockerCluster); err != nil {
+				return nil, err
+			}
+			return dockerCluster.GetFinalizers(), nil
+		}, "30s", "5s").Should(BeEmpty())
+		ready, _, err := unstructured.NestedBool(dockerCluster.Object, "status", "ready")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ready).To(BeFalse())
+
+		By("Marking the DockerCluster as ready, acting as the external management system")
+		patchHelper, err := patch.NewHelper(dockerCluster, client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unstructured.SetNestedMap(dockerCluster.Object, map[string]interface{}{
+			"host": "127.0.0.1",
+			"port": int64(6443),
+		}, "spec", "controlPlaneEndpoint")).To(Succeed())
+		Expect(unstructured.SetNestedField(dockerCluster.Object, true, "status", "ready")).To(Succeed())
+		Expect(patchHelper.Patch(ctx, dockerCluster)).To(Succeed())
+
+		By("Waiting for the Cluster to pick up the externally reported infrastructure status")
+		Eventually(func() (bool, error) {
+			c := &clusterv1.Cluster{}
+			if err := client.Get(ctx, ctrlclient.ObjectKeyFromObject(cluster), c); err != nil {
+				return false, err
+			}
+			return c.Status.InfrastructureReady &&
+				conditions.IsTrue(c, clusterv1.InfrastructureReadyCondition) &&
+				c.Spec.ControlPlaneEndpoint.Host == "127.0.0.1" &&
+				c.Spec.ControlPlaneEndpoint.Port == 6443, nil
+		}, WaitForClusterIntervals...).Should(BeTrue())
+
+		By("PASSED!")
+	})
+
+	AfterEach(func() {
+		// Dumps all the resources in the spec namespace, then cleanups the cluster object and the spec namespace itself.
+		dumpSpecResourcesAndCleanup(ctx, specName, input.BootstrapClusterProxy, input.ArtifactFolder, namespace, cancelWatches, cluster, input.E2EConfig.GetIntervals, input.SkipCleanup)
+	})
+}
@@ -43,7 +43,7 @@ type QuickStartSpecInput struct {
 
 	// Flavor, if specified is the template flavor used to create the cluster for testing.
 	// If not specified, and the e2econfig variable IPFamily is IPV6, then "ipv6" is used,
-	// otherwise the default flavor is used.
+	// if it is dual, then "dualstack" is used, otherwise the default flavor is used.
 	Flavor *string
 }
 
@@ -78,8 +78,11 @@ func QuickStartSpec(ctx context.Context, inputGetter func() QuickStartSpecInput)
 		By("Creating a workload cluster")
 
 		defaultFlavor := clusterctl.DefaultFlavor
-		if input.E2EConfig.GetVariable(IPFamily) == "IPv6" {
+		switch input.E2EConfig.GetVariable(IPFamily) {
+		case "IPv6":
 			defaultFlavor = "ipv6"
+		case "dual":
+			defaultFlavor = "dualstack"
 		}
 
 		clusterctl.ApplyClusterTemplateAndWait(ctx, clusterctl.ApplyClusterTemplateAndWaitInput{
@@ -47,7 +47,7 @@ type CreateKindBootstrapClusterAndLoadImagesInput struct {
 	// Images to be loaded in the cluster.
 	Images []clusterctl.ContainerImage
 
-	// IPFamily is either ipv4 or ipv6. Default is ipv4.
+	// IPFamily is either ipv4, ipv6 or dual. Default is ipv4.
 	IPFamily string
 }
 
@@ -65,8 +65,11 @@ func CreateKindBootstrapClusterAndLoadImages(ctx context.Context, input CreateKi
 	if input.RequiresDockerSock {
 		options = append(options, WithDockerSockMount())
 	}
-	if input.IPFamily == "IPv6" {
+	switch input.IPFamily {
+	case "IPv6":
 		options = append(options, WithIPv6Family())
+	case "dual":
+		options = append(options, WithDualStackFamily())
 	}
 
 	clusterProvider := NewKindClusterProvider(input.Name, options...)
@@ -156,7 +159,7 @@ func loadImage(ctx context.Context, cluster, image string) error {
 // copied from kind https://github.com/kubernetes-sigs/kind/blob/v0.7.0/pkg/cmd/kind/load/docker-image/docker-image.go#L168
 // save saves image to dest, as in `docker save`.
 func save(ctx context.Context, image, dest string) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return errors.Wrap(err, "failed to get Docker runtime client")
 	}
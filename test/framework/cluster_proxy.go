@@ -314,7 +314,7 @@ func (p *clusterProxy) isDockerCluster(ctx context.Context, namespace string, na
 }
 
 func (p *clusterProxy) fixConfig(ctx context.Context, name string, config *api.Config) {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	Expect(err).ToNot(HaveOccurred(), "Failed to get Docker runtime client")
 
 	lbContainerName := name + "-lb"
@@ -63,7 +63,7 @@ func (k DockerLogCollector) CollectMachinePoolLog(ctx context.Context, managemen
 }
 
 func (k DockerLogCollector) collectLogsFromNode(ctx context.Context, outputPath string, containerName string) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return errors.Wrap(err, "Failed to collect logs from node")
 	}
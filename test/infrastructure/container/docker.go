@@ -21,8 +21,11 @@ package container
 import (
 	"bytes"
 	"context"
+	"crypto/sha1" //nolint:gosec // used only for generating a stable subnet from the network name
+	"encoding/binary"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -48,6 +51,8 @@ type docker struct {
 	dockerClient *client.Client
 }
 
+var _ Committer = &docker{}
+
 // NewDockerClient gets a client for interacting with a Docker container runtime.
 func NewDockerClient() (Runtime, error) {
 	dockerClient, err := getDockerClient()
@@ -281,11 +286,36 @@ func (d *docker) DeleteContainer(ctx context.Context, containerName string) erro
 	})
 }
 
+// DeleteVolume will remove a named volume; it will not error if the volume does not exist.
+func (d *docker) DeleteVolume(ctx context.Context, volumeName string) error {
+	if err := d.dockerClient.VolumeRemove(ctx, volumeName, true); err != nil && !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "failed to delete volume %q", volumeName)
+	}
+	return nil
+}
+
 // KillContainer will kill a running container with the specified signal.
 func (d *docker) KillContainer(ctx context.Context, containerName, signal string) error {
 	return d.dockerClient.ContainerKill(ctx, containerName, signal)
 }
 
+// StopContainer will stop a running container, killing it if it does not stop within the timeout.
+func (d *docker) StopContainer(ctx context.Context, containerName string, timeout time.Duration) error {
+	return d.dockerClient.ContainerStop(ctx, containerName, &timeout)
+}
+
+// CommitContainer will create an image from the filesystem of a container; the container is paused while committing.
+func (d *docker) CommitContainer(ctx context.Context, containerName, image string) error {
+	_, err := d.dockerClient.ContainerCommit(ctx, containerName, types.ContainerCommitOptions{
+		Reference: image,
+		Pause:     true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit container %q to image %q", containerName, image)
+	}
+	return nil
+}
+
 // GetContainerIPs inspects a container to get its IPv4 and IPv6 IP addresses.
 // Will not error if there is no IP address assigned. Calling code will need to
 // determine whether that is an issue or not.
@@ -295,6 +325,12 @@ func (d *docker) GetContainerIPs(ctx context.Context, containerName string) (str
 		return "", "", errors.Wrap(err, "failed to get container details")
 	}
 
+	// Prefer the network the container was created with, given that the container could be attached to additional networks.
+	if containerInfo.HostConfig != nil {
+		if net, ok := containerInfo.NetworkSettings.Networks[string(containerInfo.HostConfig.NetworkMode)]; ok {
+			return net.IPAddress, net.GlobalIPv6Address, nil
+		}
+	}
 	for _, net := range containerInfo.NetworkSettings.Networks {
 		return net.IPAddress, net.GlobalIPv6Address, nil
 	}
@@ -390,9 +426,27 @@ func (d *docker) RunContainer(ctx context.Context, runConfig *RunContainerInput,
 		PortBindings:  nat.PortMap{},
 		RestartPolicy: dockercontainer.RestartPolicy{Name: "unless-stopped"},
 	}
+	if runConfig.MilliCPUs > 0 {
+		hostConfig.NanoCPUs = runConfig.MilliCPUs * 1e6
+	}
+	if runConfig.Memory > 0 {
+		// Setting the swap limit equal to the memory limit prevents the container from using swap.
+		hostConfig.Memory = runConfig.Memory
+		hostConfig.MemorySwap = runConfig.Memory
+	}
 	networkConfig := network.NetworkingConfig{}
+	if runConfig.IPv4Address != "" || runConfig.IPv6Address != "" {
+		networkConfig.EndpointsConfig = map[string]*network.EndpointSettings{
+			runConfig.Network: {
+				IPAMConfig: &network.EndpointIPAMConfig{
+					IPv4Address: runConfig.IPv4Address,
+					IPv6Address: runConfig.IPv6Address,
+				},
+			},
+		}
+	}
 
-	if runConfig.IPFamily == clusterv1.IPv6IPFamily {
+	if runConfig.IPFamily == clusterv1.IPv6IPFamily || runConfig.IPFamily == clusterv1.DualStackIPFamily {
 		hostConfig.Sysctls = map[string]string{
 			"net.ipv6.conf.all.disable_ipv6": "0",
 			"net.ipv6.conf.all.forwarding":   "1",
@@ -413,7 +467,7 @@ func (d *docker) RunContainer(ctx context.Context, runConfig *RunContainerInput,
 	envVars := environmentVariables(runConfig)
 
 	// pass proxy environment variables to be used by node's docker daemon
-	proxyDetails, err := d.getProxyDetails(ctx, runConfig.Network)
+	proxyDetails, err := getProxyDetails(ctx, runConfig.Network, d.getSubnets)
 	if err != nil {
 		return errors.Wrapf(err, "error getting subnets for %q", runConfig.Network)
 	}
@@ -449,6 +503,14 @@ func (d *docker) RunContainer(ctx context.Context, runConfig *RunContainerInput,
 		return errors.Wrapf(err, "error creating container %q", runConfig.Name)
 	}
 
+	// Connect the container to the additional networks before starting it, so all the
+	// interfaces are already in place when the entrypoint runs.
+	for _, additionalNetwork := range runConfig.AdditionalNetworks {
+		if err := d.dockerClient.NetworkConnect(ctx, additionalNetwork, resp.ID, nil); err != nil {
+			return errors.Wrapf(err, "failed to connect container %q to network %q", runConfig.Name, additionalNetwork)
+		}
+	}
+
 	var containerOutput types.HijackedResponse
 	if output != nil {
 		// Read out any output from the container
@@ -580,6 +642,63 @@ func configureVolumes(crc *RunContainerInput, config *dockercontainer.Config, ho
 	}
 }
 
+// EnsureNetwork creates a bridge network with the given name if it does not exist.
+// If ipv6 is true the network is created with IPv6 enabled, and an error is returned
+// if the network already exists without IPv6 support.
+func (d *docker) EnsureNetwork(ctx context.Context, networkName string, ipv6 bool) error {
+	networkInfo, err := d.dockerClient.NetworkInspect(ctx, networkName, types.NetworkInspectOptions{})
+	if err == nil {
+		if ipv6 && !networkInfo.EnableIPv6 {
+			return errors.Errorf("network %q exists but it does not have IPv6 enabled, which is required for IPv6 and dual-stack clusters", networkName)
+		}
+		return nil
+	}
+	if !client.IsErrNotFound(err) {
+		return errors.Wrapf(err, "failed to inspect network %q", networkName)
+	}
+
+	createOptions := types.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		EnableIPv6:     ipv6,
+		Options: map[string]string{
+			"com.docker.network.bridge.enable_ip_masquerade": "true",
+		},
+	}
+	if ipv6 {
+		// Docker requires an explicit subnet for enabling IPv6 on a network; the IPv4 subnet is allocated by Docker.
+		createOptions.IPAM = &network.IPAM{
+			Driver: "default",
+			Config: []network.IPAMConfig{{Subnet: ulaSubnetFromName(networkName)}},
+		}
+	}
+	if _, err := d.dockerClient.NetworkCreate(ctx, networkName, createOptions); err != nil {
+		return errors.Wrapf(err, "failed to create network %q", networkName)
+	}
+	return nil
+}
+
+// ulaSubnetFromName generates an IPv6 unique local address /64 subnet from the network name,
+// so the same subnet is used every time a network with the given name is created; this is
+// the same approach used by kind.
+func ulaSubnetFromName(name string) string {
+	ip := make([]byte, net.IPv6len)
+	ip[0] = 0xfc
+	ip[1] = 0x00
+	h := sha1.New() //nolint:gosec
+	_, _ = h.Write([]byte(name))
+	_ = binary.Write(h, binary.LittleEndian, int32(0))
+	bs := h.Sum(nil)
+	for i := 2; i < 8; i++ {
+		ip[i] = bs[i]
+	}
+	subnet := &net.IPNet{
+		IP:   net.IP(ip),
+		Mask: net.CIDRMask(64, 128),
+	}
+	return subnet.String()
+}
+
 // getSubnets returns a slice of subnets for a specified network.
 func (d *docker) getSubnets(ctx context.Context, networkName string) ([]string, error) {
 	subnets := []string{}
@@ -601,8 +720,9 @@ type proxyDetails struct {
 }
 
 // getProxyDetails returns a struct with the host environment proxy settings
-// that should be passed to the nodes.
-func (d *docker) getProxyDetails(ctx context.Context, network string) (*proxyDetails, error) {
+// that should be passed to the nodes; getSubnets returns the subnets of the network,
+// that are added to NO_PROXY.
+func getProxyDetails(ctx context.Context, network string, getSubnets func(context.Context, string) ([]string, error)) (*proxyDetails, error) {
 	var val string
 	details := proxyDetails{Envs: make(map[string]string)}
 	proxyEnvs := []string{httpProxy, httpsProxy, noProxy}
@@ -623,7 +743,7 @@ func (d *docker) getProxyDetails(ctx context.Context, network string) (*proxyDet
 
 	// Specifically add the docker network subnets to NO_PROXY if we are using proxies
 	if proxySupport {
-		subnets, err := d.getSubnets(ctx, network)
+		subnets, err := getSubnets(ctx, network)
 		if err != nil {
 			return &details, err
 		}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestULASubnetFromName(t *testing.T) {
+	g := NewWithT(t)
+
+	subnet := ulaSubnetFromName("kind")
+	ip, ipNet, err := net.ParseCIDR(subnet)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The subnet is a /64 in the fc00::/7 unique local address range.
+	g.Expect(ip.To4()).To(BeNil())
+	ones, bits := ipNet.Mask.Size()
+	g.Expect(ones).To(Equal(64))
+	g.Expect(bits).To(Equal(128))
+	_, ula, _ := net.ParseCIDR("fc00::/7")
+	g.Expect(ula.Contains(ip)).To(BeTrue())
+
+	// The same subnet is generated for the same network name, and different subnets for different names.
+	g.Expect(ulaSubnetFromName("kind")).To(Equal(subnet))
+	g.Expect(ulaSubnetFromName("another-network")).ToNot(Equal(subnet))
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeRuntime is a Runtime for testing, which records the operations performed instead of
+// interacting with a container runtime; use SetFakeRuntime to make NewRuntime return it.
+type FakeRuntime struct {
+	// Containers are the containers returned by ListContainers.
+	Containers []Container
+
+	// Errors are the errors returned by the operations of the runtime, by operation name, e.g.
+	// "PullContainerImageIfNotExists"; operations without an error succeed.
+	Errors map[string]error
+
+	lock  sync.Mutex
+	calls []string
+}
+
+var (
+	_ Runtime   = &FakeRuntime{}
+	_ Committer = &FakeRuntime{}
+)
+
+// Calls returns the operations performed on the runtime, in the "<operation> <args>" format, e.g.
+// "PullContainerImageIfNotExists kindest/node:v1.22.0".
+func (f *FakeRuntime) Calls() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return append([]string{}, f.calls...)
+}
+
+// record records an operation and returns the error configured for it, if any.
+func (f *FakeRuntime) record(operation string, args ...string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.calls = append(f.calls, strings.TrimSpace(fmt.Sprintf("%s %s", operation, strings.Join(args, " "))))
+	return f.Errors[operation]
+}
+
+// SaveContainerImage records saving an image.
+func (f *FakeRuntime) SaveContainerImage(_ context.Context, image, dest string) error {
+	return f.record("SaveContainerImage", image, dest)
+}
+
+// PullContainerImageIfNotExists records pulling an image.
+func (f *FakeRuntime) PullContainerImageIfNotExists(_ context.Context, image string) error {
+	return f.record("PullContainerImageIfNotExists", image)
+}
+
+// GetHostPort records looking up a host port; the host port returned is the container port.
+func (f *FakeRuntime) GetHostPort(_ context.Context, containerName, portAndProtocol string) (string, error) {
+	if err := f.record("GetHostPort", containerName, portAndProtocol); err != nil {
+		return "", err
+	}
+	return strings.Split(portAndProtocol, "/")[0], nil
+}
+
+// GetContainerIPs records looking up the IPs of a container; no IPs are returned.
+func (f *FakeRuntime) GetContainerIPs(_ context.Context, containerName string) (string, string, error) {
+	return "", "", f.record("GetContainerIPs", containerName)
+}
+
+// ExecContainer records executing a command in a container.
+func (f *FakeRuntime) ExecContainer(_ context.Context, containerName string, _ *ExecContainerInput, command string, args ...string) error {
+	return f.record("ExecContainer", append([]string{containerName, command}, args...)...)
+}
+
+// RunContainer records running a container.
+func (f *FakeRuntime) RunContainer(_ context.Context, runConfig *RunContainerInput, _ io.Writer) error {
+	return f.record("RunContainer", runConfig.Name, runConfig.Image)
+}
+
+// ListContainers records listing containers, and returns the containers of the runtime.
+func (f *FakeRuntime) ListContainers(_ context.Context, _ FilterBuilder) ([]Container, error) {
+	if err := f.record("ListContainers"); err != nil {
+		return nil, err
+	}
+	return append([]Container{}, f.Containers...), nil
+}
+
+// ContainerDebugInfo records getting the debug info of a container.
+func (f *FakeRuntime) ContainerDebugInfo(_ context.Context, containerName string, _ io.Writer) error {
+	return f.record("ContainerDebugInfo", containerName)
+}
+
+// DeleteContainer records deleting a container.
+func (f *FakeRuntime) DeleteContainer(_ context.Context, containerName string) error {
+	return f.record("DeleteContainer", containerName)
+}
+
+// KillContainer records killing a container.
+func (f *FakeRuntime) KillContainer(_ context.Context, containerName, signal string) error {
+	return f.record("KillContainer", containerName, signal)
+}
+
+// StopContainer records stopping a container.
+func (f *FakeRuntime) StopContainer(_ context.Context, containerName string, _ time.Duration) error {
+	return f.record("StopContainer", containerName)
+}
+
+// CommitContainer records committing a container to an image.
+func (f *FakeRuntime) CommitContainer(_ context.Context, containerName, image string) error {
+	return f.record("CommitContainer", containerName, image)
+}
+
+// EnsureNetwork records ensuring a network exists.
+func (f *FakeRuntime) EnsureNetwork(_ context.Context, networkName string, _ bool) error {
+	return f.record("EnsureNetwork", networkName)
+}
+
+// DeleteVolume records deleting a volume.
+func (f *FakeRuntime) DeleteVolume(_ context.Context, volumeName string) error {
+	return f.record("DeleteVolume", volumeName)
+}
@@ -19,6 +19,7 @@ package container
 import (
 	"context"
 	"io"
+	"time"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -35,6 +36,15 @@ type Runtime interface {
 	ContainerDebugInfo(ctx context.Context, containerName string, w io.Writer) error
 	DeleteContainer(ctx context.Context, containerName string) error
 	KillContainer(ctx context.Context, containerName, signal string) error
+	StopContainer(ctx context.Context, containerName string, timeout time.Duration) error
+	EnsureNetwork(ctx context.Context, networkName string, ipv6 bool) error
+	DeleteVolume(ctx context.Context, volumeName string) error
+}
+
+// Committer is implemented by the container runtimes supporting to create an image from a container;
+// it is not part of Runtime, so runtimes which do not support it can still be used.
+type Committer interface {
+	CommitContainer(ctx context.Context, containerName, image string) error
 }
 
 // Mount contains mount details.
@@ -67,6 +77,16 @@ type RunContainerInput struct {
 	Name string
 	// Network is the name of the network to connect to.
 	Network string
+	// IPv4Address is the static IPv4 address to assign to the container on Network; if empty, it is assigned by docker.
+	IPv4Address string
+	// IPv6Address is the static IPv6 address to assign to the container on Network; if empty, it is assigned by docker.
+	IPv6Address string
+	// AdditionalNetworks are the names of other existing networks to connect the container to.
+	AdditionalNetworks []string
+	// MilliCPUs is the maximum amount of CPU the container can use, in thousandths of a CPU; 0 means no limit.
+	MilliCPUs int64
+	// Memory is the maximum amount of memory the container can use, in bytes; 0 means no limit.
+	Memory int64
 	// User is the user name to run as.
 	User string
 	// Group is the user group to run as.
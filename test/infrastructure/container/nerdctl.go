@@ -0,0 +1,538 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// nerdctl implements Runtime for containerd using the nerdctl CLI, which is
+// compatible with the docker CLI; the containerd namespace and address can be
+// configured using the CONTAINERD_NAMESPACE and CONTAINERD_ADDRESS environment variables.
+type nerdctl struct {
+	// binary is the path of the nerdctl executable.
+	binary string
+}
+
+var _ Committer = &nerdctl{}
+
+// nerdctlContainerInfo is the subset of the docker compatible output of nerdctl container inspect used by CAPD.
+type nerdctlContainerInfo struct {
+	Name  string
+	State struct {
+		Status   string
+		ExitCode int
+	}
+	NetworkSettings struct {
+		IPAddress         string
+		GlobalIPv6Address string
+		Ports             map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string
+		}
+	}
+}
+
+// nerdctlNetworkInfo is the subset of the docker compatible output of nerdctl network inspect used by CAPD.
+type nerdctlNetworkInfo struct {
+	Name string
+	IPAM struct {
+		Config []struct {
+			Subnet string
+		}
+	}
+}
+
+// nerdctlContainer is the output of nerdctl ps for a container.
+type nerdctlContainer struct {
+	Names  string
+	Image  string
+	Status string
+}
+
+// NewNerdctlClient gets a client for interacting with a containerd container runtime using nerdctl.
+func NewNerdctlClient() (Runtime, error) {
+	binary, err := exec.LookPath("nerdctl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nerdctl runtime client: %v", err)
+	}
+	return &nerdctl{
+		binary: binary,
+	}, nil
+}
+
+// SaveContainerImage saves an image to the file specified by dest.
+func (n *nerdctl) SaveContainerImage(ctx context.Context, image, dest string) error {
+	if _, err := n.run(ctx, "save", "--output", dest, image); err != nil {
+		return fmt.Errorf("failed to save image %q: %v", image, err)
+	}
+	return nil
+}
+
+// PullContainerImageIfNotExists pulls an image, but only if it doesn't already exist.
+func (n *nerdctl) PullContainerImageIfNotExists(ctx context.Context, image string) error {
+	if _, err := n.run(ctx, "image", "inspect", image); err == nil {
+		// Nothing to do as the image already exists locally.
+		return nil
+	}
+
+	if _, err := n.run(ctx, "pull", image); err != nil {
+		return fmt.Errorf("failure pulling container image: %v", err)
+	}
+	return nil
+}
+
+// GetHostPort looks up the host port bound for the port and protocol (e.g. "6443/tcp").
+func (n *nerdctl) GetHostPort(ctx context.Context, containerName, portAndProtocol string) (string, error) {
+	containerInfo, err := n.inspectContainer(ctx, containerName)
+	if err != nil {
+		return "", fmt.Errorf("error getting container information for %q: %v", containerName, err)
+	}
+
+	if hostPort, ok := containerInfo.hostPort(portAndProtocol); ok {
+		return hostPort, nil
+	}
+	return "", fmt.Errorf("no host port found for load balancer %q", containerName)
+}
+
+// hostPort returns the host port bound for the port and protocol (e.g. "6443/tcp"), if any.
+func (i *nerdctlContainerInfo) hostPort(portAndProtocol string) (string, bool) {
+	bindings := i.NetworkSettings.Ports[portAndProtocol]
+	if len(bindings) == 0 {
+		return "", false
+	}
+	return bindings[0].HostPort, true
+}
+
+// GetContainerIPs inspects a container to get its IPv4 and IPv6 IP addresses; the addresses of the
+// first interface are returned, which is the one connected to the network the container was created with.
+func (n *nerdctl) GetContainerIPs(ctx context.Context, containerName string) (string, string, error) {
+	containerInfo, err := n.inspectContainer(ctx, containerName)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get container details")
+	}
+	return containerInfo.NetworkSettings.IPAddress, containerInfo.NetworkSettings.GlobalIPv6Address, nil
+}
+
+// ExecContainer executes a command in a running container and writes any output to the provided writer.
+func (n *nerdctl) ExecContainer(_ context.Context, containerName string, config *ExecContainerInput, command string, args ...string) error {
+	ctx := context.Background() // Let the command finish, even if it takes longer than the default timeout
+
+	execArgs := []string{"exec", "--privileged"}
+	if config.InputBuffer != nil {
+		execArgs = append(execArgs, "--interactive")
+	}
+	for _, env := range config.EnvironmentVars {
+		execArgs = append(execArgs, "--env", env)
+	}
+	execArgs = append(execArgs, containerName, command)
+	execArgs = append(execArgs, args...)
+
+	if config.OutputBuffer == nil {
+		// We always want to read whatever output the command sends
+		config.OutputBuffer = &bytes.Buffer{}
+	}
+
+	cmd := exec.CommandContext(ctx, n.binary, execArgs...) //nolint:gosec // the command runs nerdctl with CAPD generated arguments
+	cmd.Stdin = config.InputBuffer
+	cmd.Stdout = config.OutputBuffer
+	cmd.Stderr = config.OutputBuffer
+	if config.ErrorBuffer != nil {
+		cmd.Stderr = config.ErrorBuffer
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return errors.Errorf("exited with status: %d, %s", exitErr.ExitCode(), config.OutputBuffer)
+		}
+		return errors.Wrap(err, "error running container exec")
+	}
+	return nil
+}
+
+// RunContainer creates and starts a container; if output is not nil, RunContainer waits for
+// the container to complete, and the output of the container is written to it.
+func (n *nerdctl) RunContainer(ctx context.Context, runConfig *RunContainerInput, output io.Writer) error {
+	runArgs := []string{
+		"run",
+		"--hostname", runConfig.Name, // make hostname match container name
+		"--name", runConfig.Name,
+		"--network", runConfig.Network,
+		// Running containers in a container requires privileges.
+		"--privileged",
+		"--security-opt", "seccomp=unconfined", // ignore seccomp
+		"--tty", // allocate a tty for entrypoint logs
+	}
+	if output == nil {
+		runArgs = append(runArgs, "--detach", "--restart", "unless-stopped")
+	}
+	for _, additionalNetwork := range runConfig.AdditionalNetworks {
+		runArgs = append(runArgs, "--network", additionalNetwork)
+	}
+	if runConfig.IPv4Address != "" {
+		runArgs = append(runArgs, "--ip", runConfig.IPv4Address)
+	}
+	if runConfig.IPv6Address != "" {
+		runArgs = append(runArgs, "--ip6", runConfig.IPv6Address)
+	}
+	if runConfig.MilliCPUs > 0 {
+		runArgs = append(runArgs, "--cpus", strconv.FormatFloat(float64(runConfig.MilliCPUs)/1000, 'f', -1, 64))
+	}
+	if runConfig.Memory > 0 {
+		// Setting the swap limit equal to the memory limit prevents the container from using swap.
+		memory := strconv.FormatInt(runConfig.Memory, 10)
+		runArgs = append(runArgs, "--memory", memory, "--memory-swap", memory)
+	}
+	if user := ownerAndGroup(runConfig); user != "" {
+		runArgs = append(runArgs, "--user", user)
+	}
+	if runConfig.IPFamily == clusterv1.IPv6IPFamily || runConfig.IPFamily == clusterv1.DualStackIPFamily {
+		runArgs = append(runArgs,
+			"--sysctl", "net.ipv6.conf.all.disable_ipv6=0",
+			"--sysctl", "net.ipv6.conf.all.forwarding=1",
+		)
+	}
+	for _, key := range sortedKeys(runConfig.Labels) {
+		runArgs = append(runArgs, "--label", fmt.Sprintf("%s=%s", key, runConfig.Labels[key]))
+	}
+	for _, target := range sortedKeys(runConfig.Tmpfs) {
+		tmpfs := target
+		if opts := runConfig.Tmpfs[target]; opts != "" {
+			tmpfs = fmt.Sprintf("%s:%s", target, opts)
+		}
+		runArgs = append(runArgs, "--tmpfs", tmpfs)
+	}
+	for _, source := range sortedKeys(runConfig.Volumes) {
+		volume := source
+		if dest := runConfig.Volumes[source]; dest != "" {
+			volume = fmt.Sprintf("%s:%s", source, dest)
+		}
+		runArgs = append(runArgs, "--volume", volume)
+	}
+	for _, mount := range runConfig.Mounts {
+		volume := fmt.Sprintf("%s:%s", mount.Source, mount.Target)
+		if mount.ReadOnly {
+			volume += ":ro"
+		}
+		runArgs = append(runArgs, "--volume", volume)
+	}
+	for _, pm := range runConfig.PortMappings {
+		runArgs = append(runArgs, "--publish", nerdctlPublishArg(pm))
+	}
+
+	envVars := environmentVariables(runConfig)
+
+	// pass proxy environment variables to be used by node's container runtime
+	proxyDetails, err := getProxyDetails(ctx, runConfig.Network, n.getSubnets)
+	if err != nil {
+		return errors.Wrapf(err, "error getting subnets for %q", runConfig.Network)
+	}
+	for key, val := range proxyDetails.Envs {
+		envVars = append(envVars, fmt.Sprintf("%s=%s", key, val))
+	}
+	sort.Strings(envVars)
+	for _, env := range envVars {
+		runArgs = append(runArgs, "--env", env)
+	}
+
+	// nerdctl accepts only the executable as entrypoint, so its arguments are prepended to the command.
+	commandArgs := runConfig.CommandArgs
+	if len(runConfig.Entrypoint) > 0 {
+		runArgs = append(runArgs, "--entrypoint", runConfig.Entrypoint[0])
+		commandArgs = append(append([]string{}, runConfig.Entrypoint[1:]...), commandArgs...)
+	}
+	runArgs = append(runArgs, runConfig.Image)
+	runArgs = append(runArgs, commandArgs...)
+
+	// Make sure we have the image
+	if err := n.PullContainerImageIfNotExists(ctx, runConfig.Image); err != nil {
+		return err
+	}
+
+	if output == nil {
+		if _, err := n.run(ctx, runArgs...); err != nil {
+			return errors.Wrapf(err, "error creating container %q", runConfig.Name)
+		}
+		return nil
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, n.binary, runArgs...) //nolint:gosec // the command runs nerdctl with CAPD generated arguments
+	cmd.Stdout = output
+	cmd.Stderr = io.MultiWriter(output, stderr)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("error container run failed with exit code %d", exitErr.ExitCode())
+		}
+		return errors.Wrapf(err, "error running container %q: %s", runConfig.Name, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// ListContainers returns a list of all containers.
+func (n *nerdctl) ListContainers(ctx context.Context, filters FilterBuilder) ([]Container, error) {
+	listArgs := []string{"ps", "--all", "--format", "{{json .}}"}
+
+	// Construct our filtering options
+	for key, values := range filters {
+		for subkey, subvalues := range values {
+			for _, v := range subvalues {
+				if v == "" {
+					listArgs = append(listArgs, "--filter", fmt.Sprintf("%s=%s", key, subkey))
+				} else {
+					listArgs = append(listArgs, "--filter", fmt.Sprintf("%s=%s=%s", key, subkey, v))
+				}
+			}
+		}
+	}
+
+	out, err := n.run(ctx, listArgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list containers")
+	}
+	return parseNerdctlContainers(out)
+}
+
+// parseNerdctlContainers parses the output of nerdctl ps, with one container in JSON format per line.
+func parseNerdctlContainers(out []byte) ([]Container, error) {
+	containers := []Container{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		c := nerdctlContainer{}
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the list of containers")
+		}
+		containers = append(containers, Container{
+			Name:   c.Names,
+			Image:  c.Image,
+			Status: c.Status,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the list of containers")
+	}
+
+	return containers, nil
+}
+
+// ContainerDebugInfo gets the container metadata and logs from the runtime (nerdctl inspect, nerdctl logs).
+func (n *nerdctl) ContainerDebugInfo(ctx context.Context, containerName string, w io.Writer) error {
+	containerInfo, err := n.run(ctx, "container", "inspect", containerName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to inspect container %q", containerName)
+	}
+
+	fmt.Fprintln(w, "Inspected the container:")
+	fmt.Fprintf(w, "%s\n", containerInfo)
+
+	logs, err := n.run(ctx, "logs", containerName)
+	if err != nil {
+		return errors.Wrapf(err, "error getting container logs for %q", containerName)
+	}
+
+	fmt.Fprintln(w, "Got logs from the container:")
+	if _, err := w.Write(logs); err != nil {
+		return errors.Wrapf(err, "error reading logs from container %q", containerName)
+	}
+	return nil
+}
+
+// DeleteContainer will remove a container, forcing removal if still running.
+func (n *nerdctl) DeleteContainer(ctx context.Context, containerName string) error {
+	_, err := n.run(ctx, "rm", "--force", "--volumes", containerName)
+	return err
+}
+
+// KillContainer will kill a running container with the specified signal.
+func (n *nerdctl) KillContainer(ctx context.Context, containerName, signal string) error {
+	_, err := n.run(ctx, "kill", "--signal", signal, containerName)
+	return err
+}
+
+// StopContainer will stop a running container, killing it if it does not stop within the timeout.
+func (n *nerdctl) StopContainer(ctx context.Context, containerName string, timeout time.Duration) error {
+	_, err := n.run(ctx, "stop", "--time", strconv.Itoa(int(timeout.Seconds())), containerName)
+	return err
+}
+
+// CommitContainer will create an image from the filesystem of a container; the container is paused while committing.
+func (n *nerdctl) CommitContainer(ctx context.Context, containerName, image string) error {
+	_, err := n.run(ctx, "commit", "--pause", containerName, image)
+	return err
+}
+
+// EnsureNetwork creates a bridge network with the given name if it does not exist.
+// If ipv6 is true the network is created with IPv6 enabled, and an error is returned
+// if the network already exists without an IPv6 subnet.
+func (n *nerdctl) EnsureNetwork(ctx context.Context, networkName string, ipv6 bool) error {
+	if subnets, err := n.getSubnets(ctx, networkName); err == nil {
+		if ipv6 && !hasIPv6Subnet(subnets) {
+			return errors.Errorf("network %q exists but it does not have IPv6 enabled, which is required for IPv6 and dual-stack clusters", networkName)
+		}
+		return nil
+	}
+
+	createArgs := []string{"network", "create", "--driver", "bridge"}
+	if ipv6 {
+		// Use the same subnet used for Docker, so the subnet is stable for a given network name.
+		createArgs = append(createArgs, "--ipv6", "--subnet", ulaSubnetFromName(networkName))
+	}
+	createArgs = append(createArgs, networkName)
+	if _, err := n.run(ctx, createArgs...); err != nil {
+		return errors.Wrapf(err, "failed to create network %q", networkName)
+	}
+	return nil
+}
+
+// DeleteVolume will remove a named volume; it will not error if the volume does not exist.
+func (n *nerdctl) DeleteVolume(ctx context.Context, volumeName string) error {
+	if _, err := n.run(ctx, "volume", "rm", volumeName); err != nil && !isNerdctlNotFound(err) {
+		return errors.Wrapf(err, "failed to delete volume %q", volumeName)
+	}
+	return nil
+}
+
+// inspectContainer returns the details of a container.
+func (n *nerdctl) inspectContainer(ctx context.Context, containerName string) (*nerdctlContainerInfo, error) {
+	out, err := n.run(ctx, "container", "inspect", containerName)
+	if err != nil {
+		return nil, err
+	}
+	return parseNerdctlContainerInfo(out, containerName)
+}
+
+// parseNerdctlContainerInfo parses the output of nerdctl container inspect for a container.
+func parseNerdctlContainerInfo(out []byte, containerName string) (*nerdctlContainerInfo, error) {
+	containers := []nerdctlContainerInfo{}
+	if err := json.Unmarshal(out, &containers); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the details of container %q", containerName)
+	}
+	if len(containers) == 0 {
+		return nil, errors.Errorf("container %q not found", containerName)
+	}
+	return &containers[0], nil
+}
+
+// getSubnets returns a slice of subnets for a specified network.
+func (n *nerdctl) getSubnets(ctx context.Context, networkName string) ([]string, error) {
+	out, err := n.run(ctx, "network", "inspect", networkName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to inspect network %q", networkName)
+	}
+	return parseNerdctlSubnets(out, networkName)
+}
+
+// parseNerdctlSubnets parses the output of nerdctl network inspect for a network, and returns its subnets.
+func parseNerdctlSubnets(out []byte, networkName string) ([]string, error) {
+	networks := []nerdctlNetworkInfo{}
+	if err := json.Unmarshal(out, &networks); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the details of network %q", networkName)
+	}
+
+	subnets := []string{}
+	for _, network := range networks {
+		for _, config := range network.IPAM.Config {
+			subnets = append(subnets, config.Subnet)
+		}
+	}
+	return subnets, nil
+}
+
+// run executes nerdctl with the given arguments and returns its stdout.
+func (n *nerdctl) run(ctx context.Context, args ...string) ([]byte, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.CommandContext(ctx, n.binary, args...) //nolint:gosec // the command runs nerdctl with CAPD generated arguments
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to run nerdctl %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// isNerdctlNotFound returns true if the error returned by nerdctl is for an object that does not exist.
+func isNerdctlNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not found") || strings.Contains(msg, "no such")
+}
+
+// nerdctlPublishArg returns the value of the --publish flag for a port mapping; if the host port
+// is not set, a random port is used.
+func nerdctlPublishArg(pm PortMapping) string {
+	protocol := pm.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	hostPort := ""
+	if pm.HostPort != 0 {
+		hostPort = fmt.Sprintf("%d", pm.HostPort)
+	}
+	if pm.ListenAddress == "" {
+		if hostPort == "" {
+			return fmt.Sprintf("%d/%s", pm.ContainerPort, protocol)
+		}
+		return fmt.Sprintf("%s:%d/%s", hostPort, pm.ContainerPort, protocol)
+	}
+	listenAddress := pm.ListenAddress
+	if ip := net.ParseIP(listenAddress); ip != nil && ip.To4() == nil {
+		listenAddress = fmt.Sprintf("[%s]", listenAddress)
+	}
+	return fmt.Sprintf("%s:%s:%d/%s", listenAddress, hostPort, pm.ContainerPort, protocol)
+}
+
+// hasIPv6Subnet returns true if at least one of the subnets is an IPv6 subnet.
+func hasIPv6Subnet(subnets []string) bool {
+	for _, subnet := range subnets {
+		if ip, _, err := net.ParseCIDR(subnet); err == nil && ip.To4() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in a stable order, so the generated command is deterministic.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
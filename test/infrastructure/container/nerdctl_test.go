@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseNerdctlContainerInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		out          string
+		wantIPv4     string
+		wantIPv6     string
+		wantHostPort string
+		wantErr      bool
+	}{
+		{
+			name: "parses the addresses and the port bindings of the container",
+			out: `[{
+	"Name": "my-cluster-lb",
+	"State": {"Status": "running", "ExitCode": 0},
+	"NetworkSettings": {
+		"IPAddress": "172.18.0.2",
+		"GlobalIPv6Address": "fc00:f853:ccd:e793::2",
+		"Ports": {"6443/tcp": [{"HostIp": "0.0.0.0", "HostPort": "55001"}]}
+	}
+}]`,
+			wantIPv4:     "172.18.0.2",
+			wantIPv6:     "fc00:f853:ccd:e793::2",
+			wantHostPort: "55001",
+		},
+		{
+			name: "parses a container without port bindings",
+			out: `[{
+	"Name": "my-cluster-worker",
+	"NetworkSettings": {"IPAddress": "172.18.0.3"}
+}]`,
+			wantIPv4: "172.18.0.3",
+		},
+		{
+			name:    "fails if the container does not exist",
+			out:     `[]`,
+			wantErr: true,
+		},
+		{
+			name:    "fails on invalid output",
+			out:     `invalid`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			info, err := parseNerdctlContainerInfo([]byte(tt.out), "my-container")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(info.NetworkSettings.IPAddress).To(Equal(tt.wantIPv4))
+			g.Expect(info.NetworkSettings.GlobalIPv6Address).To(Equal(tt.wantIPv6))
+
+			hostPort, ok := info.hostPort("6443/tcp")
+			g.Expect(ok).To(Equal(tt.wantHostPort != ""))
+			g.Expect(hostPort).To(Equal(tt.wantHostPort))
+		})
+	}
+}
+
+func TestParseNerdctlContainers(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    []Container
+		wantErr bool
+	}{
+		{
+			name: "parses one container per line, skipping empty lines",
+			out: `{"Names":"my-cluster-lb","Image":"kindest/haproxy:v20210715-a6da3463","Status":"Up"}
+
+{"Names":"my-cluster-control-plane","Image":"kindest/node:v1.22.0","Status":"Created"}
+`,
+			want: []Container{
+				{Name: "my-cluster-lb", Image: "kindest/haproxy:v20210715-a6da3463", Status: "Up"},
+				{Name: "my-cluster-control-plane", Image: "kindest/node:v1.22.0", Status: "Created"},
+			},
+		},
+		{
+			name: "parses an empty list",
+			out:  "",
+			want: []Container{},
+		},
+		{
+			name:    "fails on invalid output",
+			out:     "CONTAINER ID    IMAGE    COMMAND",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseNerdctlContainers([]byte(tt.out))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestParseNerdctlSubnets(t *testing.T) {
+	tests := []struct {
+		name     string
+		out      string
+		want     []string
+		wantIPv6 bool
+		wantErr  bool
+	}{
+		{
+			name:     "parses the subnets of a dual-stack network",
+			out:      `[{"Name":"kind","IPAM":{"Config":[{"Subnet":"172.18.0.0/16"},{"Subnet":"fc00:f853:ccd:e793::/64"}]}}]`,
+			want:     []string{"172.18.0.0/16", "fc00:f853:ccd:e793::/64"},
+			wantIPv6: true,
+		},
+		{
+			name: "parses the subnets of an IPv4 network",
+			out:  `[{"Name":"kind","IPAM":{"Config":[{"Subnet":"172.18.0.0/16"}]}}]`,
+			want: []string{"172.18.0.0/16"},
+		},
+		{
+			name:    "fails on invalid output",
+			out:     `invalid`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseNerdctlSubnets([]byte(tt.out), "kind")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(hasIPv6Subnet(got)).To(Equal(tt.wantIPv6))
+		})
+	}
+}
+
+func TestNerdctlPublishArg(t *testing.T) {
+	tests := []struct {
+		name        string
+		portMapping PortMapping
+		want        string
+	}{
+		{
+			name:        "random host port",
+			portMapping: PortMapping{ContainerPort: 6443},
+			want:        "6443/tcp",
+		},
+		{
+			name:        "host port and protocol",
+			portMapping: PortMapping{ContainerPort: 53, HostPort: 5353, Protocol: "udp"},
+			want:        "5353:53/udp",
+		},
+		{
+			name:        "IPv4 listen address and random host port",
+			portMapping: PortMapping{ContainerPort: 6443, ListenAddress: "127.0.0.1"},
+			want:        "127.0.0.1::6443/tcp",
+		},
+		{
+			name:        "IPv6 listen address and host port",
+			portMapping: PortMapping{ContainerPort: 6443, HostPort: 8443, ListenAddress: "::1"},
+			want:        "[::1]:8443:6443/tcp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(nerdctlPublishArg(tt.portMapping)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNerdctlRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		script       string
+		wantOut      string
+		wantErr      string
+		wantNotFound bool
+	}{
+		{
+			name:    "returns the standard output",
+			script:  "echo \"$@\"",
+			wantOut: "volume rm my-volume\n",
+		},
+		{
+			name:         "returns the standard error in the error",
+			script:       "echo 'FATA[0000] 1 errors: no such volume: my-volume' >&2; exit 1",
+			wantErr:      "failed to run nerdctl volume: FATA[0000] 1 errors: no such volume: my-volume",
+			wantNotFound: true,
+		},
+		{
+			name:    "does not report other errors as not found",
+			script:  "echo 'FATA[0000] volume my-volume is in use' >&2; exit 1",
+			wantErr: "failed to run nerdctl volume: FATA[0000] volume my-volume is in use",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Use a script in place of the nerdctl binary.
+			binary := filepath.Join(t.TempDir(), "nerdctl")
+			g.Expect(os.WriteFile(binary, []byte("#!/bin/sh\n"+tt.script+"\n"), 0700)).To(Succeed()) //nolint:gosec // the script must be executable
+			n := &nerdctl{binary: binary}
+
+			out, err := n.run(context.Background(), "volume", "rm", "my-volume")
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				g.Expect(isNerdctlNotFound(err)).To(Equal(tt.wantNotFound))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(out)).To(Equal(tt.wantOut))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/client"
+)
+
+const (
+	// podmanHostEnv is the environment variable used by Podman for the address of the API socket.
+	podmanHostEnv = "CONTAINER_HOST"
+
+	// dockerHostEnv is the environment variable used by Docker clients for the address of the API socket.
+	dockerHostEnv = "DOCKER_HOST"
+)
+
+// NewPodmanClient gets a client for interacting with a Podman container runtime.
+// Podman serves a Docker compatible API, so the same implementation used for Docker
+// is used with a client connected to the Podman API socket.
+func NewPodmanClient() (Runtime, error) {
+	dockerClient, err := client.NewClientWithOpts(client.WithHost(podmanHost()), client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create podman runtime client: %v", err)
+	}
+	return &docker{
+		dockerClient: dockerClient,
+	}, nil
+}
+
+// podmanHost returns the address of the Podman API socket; the CONTAINER_HOST and DOCKER_HOST environment
+// variables are honored, then the default locations of the rootless and rootful sockets are checked.
+func podmanHost() string {
+	if host := os.Getenv(podmanHostEnv); host != "" {
+		return host
+	}
+	if host := os.Getenv(dockerHostEnv); host != "" {
+		return host
+	}
+
+	sockets := []string{}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		sockets = append(sockets, filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	sockets = append(sockets, "/run/podman/podman.sock")
+	for _, socket := range sockets {
+		if _, err := os.Stat(socket); err == nil {
+			return "unix://" + socket
+		}
+	}
+
+	// Fall back to the Docker socket, which is where the Podman socket is usually mounted
+	// when the controllers are running in a container, or when podman-docker is installed.
+	return client.DefaultDockerHost
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPodmanHost(t *testing.T) {
+	runtimeDir := t.TempDir()
+	g := NewWithT(t)
+	g.Expect(os.MkdirAll(filepath.Join(runtimeDir, "podman"), 0750)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(runtimeDir, "podman", "podman.sock"), nil, 0600)).To(Succeed())
+
+	tests := []struct {
+		name          string
+		containerHost string
+		dockerHost    string
+		runtimeDir    string
+		want          string
+	}{
+		{
+			name:          "uses CONTAINER_HOST if set",
+			containerHost: "tcp://podman:8080",
+			dockerHost:    "tcp://docker:2375",
+			runtimeDir:    runtimeDir,
+			want:          "tcp://podman:8080",
+		},
+		{
+			name:       "uses DOCKER_HOST if set",
+			dockerHost: "tcp://docker:2375",
+			runtimeDir: runtimeDir,
+			want:       "tcp://docker:2375",
+		},
+		{
+			name:       "uses the rootless socket if it exists",
+			runtimeDir: runtimeDir,
+			want:       "unix://" + filepath.Join(runtimeDir, "podman", "podman.sock"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			setEnv(t, podmanHostEnv, tt.containerHost)
+			setEnv(t, dockerHostEnv, tt.dockerHost)
+			setEnv(t, "XDG_RUNTIME_DIR", tt.runtimeDir)
+
+			g.Expect(podmanHost()).To(Equal(tt.want))
+		})
+	}
+}
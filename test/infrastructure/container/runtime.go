@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// DockerRuntime identifies the Docker container runtime.
+	DockerRuntime = "docker"
+	// PodmanRuntime identifies the Podman container runtime, accessed using its Docker compatible API.
+	PodmanRuntime = "podman"
+	// NerdctlRuntime identifies the containerd container runtime, accessed using the nerdctl CLI.
+	NerdctlRuntime = "nerdctl"
+
+	// RuntimeEnvVar is the environment variable used to select the container runtime.
+	RuntimeEnvVar = "CAPD_CONTAINER_RUNTIME"
+)
+
+// Runtimes is the list of supported container runtimes.
+var Runtimes = []string{DockerRuntime, PodmanRuntime, NerdctlRuntime}
+
+var (
+	runtimeLock sync.RWMutex
+	runtimeName string
+	fakeRuntime Runtime
+)
+
+// SetRuntime sets the container runtime used by NewRuntime, overriding the value of
+// the CAPD_CONTAINER_RUNTIME environment variable.
+func SetRuntime(name string) {
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	runtimeName = name
+}
+
+// SetFakeRuntime makes NewRuntime return the given runtime, e.g. a FakeRuntime, instead of a client for the
+// selected container runtime; it is intended for testing, and setting it to nil restores the default behavior.
+func SetFakeRuntime(runtime Runtime) {
+	runtimeLock.Lock()
+	defer runtimeLock.Unlock()
+	fakeRuntime = runtime
+}
+
+// RuntimeName returns the name of the container runtime used by NewRuntime, that is the value
+// set with SetRuntime, or the value of the CAPD_CONTAINER_RUNTIME environment variable, or docker
+// if none of them is set.
+func RuntimeName() string {
+	runtimeLock.RLock()
+	defer runtimeLock.RUnlock()
+	if runtimeName != "" {
+		return runtimeName
+	}
+	if name := os.Getenv(RuntimeEnvVar); name != "" {
+		return name
+	}
+	return DockerRuntime
+}
+
+// NewRuntime gets a client for interacting with the selected container runtime.
+func NewRuntime() (Runtime, error) {
+	runtimeLock.RLock()
+	runtime := fakeRuntime
+	runtimeLock.RUnlock()
+	if runtime != nil {
+		return runtime, nil
+	}
+
+	switch name := RuntimeName(); name {
+	case DockerRuntime:
+		return NewDockerClient()
+	case PodmanRuntime:
+		return NewPodmanClient()
+	case NerdctlRuntime:
+		return NewNerdctlClient()
+	default:
+		return nil, fmt.Errorf("invalid container runtime %q, valid values are %v", name, Runtimes)
+	}
+}
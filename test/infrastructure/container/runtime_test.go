@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRuntimeName(t *testing.T) {
+	tests := []struct {
+		name       string
+		setRuntime string
+		envRuntime string
+		want       string
+	}{
+		{
+			name: "defaults to docker",
+			want: DockerRuntime,
+		},
+		{
+			name:       "uses the runtime from the environment variable",
+			envRuntime: NerdctlRuntime,
+			want:       NerdctlRuntime,
+		},
+		{
+			name:       "uses the runtime set with SetRuntime",
+			setRuntime: PodmanRuntime,
+			want:       PodmanRuntime,
+		},
+		{
+			name:       "the runtime set with SetRuntime takes precedence over the environment variable",
+			setRuntime: PodmanRuntime,
+			envRuntime: NerdctlRuntime,
+			want:       PodmanRuntime,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			setEnv(t, RuntimeEnvVar, tt.envRuntime)
+			SetRuntime(tt.setRuntime)
+			defer SetRuntime("")
+
+			g.Expect(RuntimeName()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestNewRuntime(t *testing.T) {
+	tests := []struct {
+		name       string
+		envRuntime string
+		wantErr    bool
+	}{
+		{
+			name: "creates a docker runtime by default",
+		},
+		{
+			name:       "creates a podman runtime",
+			envRuntime: PodmanRuntime,
+		},
+		{
+			name:       "fails for an invalid runtime",
+			envRuntime: "invalid",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			setEnv(t, RuntimeEnvVar, tt.envRuntime)
+
+			// NOTE: Creating the docker and podman runtimes does not require a running daemon.
+			runtime, err := NewRuntime()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(runtime).ToNot(BeNil())
+		})
+	}
+}
+
+func TestNewRuntimeReturnsTheFakeRuntime(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeRuntime := &FakeRuntime{}
+	SetFakeRuntime(fakeRuntime)
+	defer SetFakeRuntime(nil)
+
+	runtime, err := NewRuntime()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(runtime).To(BeIdenticalTo(fakeRuntime))
+}
+
+// setEnv sets an environment variable for the duration of a test, or unsets it if value is empty.
+func setEnv(t *testing.T, key, value string) {
+	t.Helper()
+
+	oldValue, found := os.LookupEnv(key)
+	t.Cleanup(func() {
+		if found {
+			_ = os.Setenv(key, oldValue)
+			return
+		}
+		_ = os.Unsetenv(key)
+	})
+
+	if value == "" {
+		_ = os.Unsetenv(key)
+		return
+	}
+	_ = os.Setenv(key, value)
+}
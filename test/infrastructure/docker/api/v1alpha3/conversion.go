@@ -18,7 +18,6 @@ package v1alpha3
 
 import (
 	apiconversion "k8s.io/apimachinery/pkg/conversion"
-	"sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
@@ -32,18 +31,12 @@ func (src *DockerCluster) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	// Manually restore data.
-	restored := &v1alpha4.DockerCluster{}
+	restored := &v1beta1.DockerCluster{}
 	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
 		return err
 	}
 
-	if restored.Spec.LoadBalancer.ImageRepository != "" {
-		dst.Spec.LoadBalancer.ImageRepository = restored.Spec.LoadBalancer.ImageRepository
-	}
-
-	if restored.Spec.LoadBalancer.ImageTag != "" {
-		dst.Spec.LoadBalancer.ImageTag = restored.Spec.LoadBalancer.ImageTag
-	}
+	dst.Spec.LoadBalancer = restored.Spec.LoadBalancer
 
 	return nil
 }
@@ -66,25 +59,68 @@ func (dst *DockerCluster) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *DockerMachine) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.DockerMachine)
 
-	return Convert_v1alpha3_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil)
+	if err := Convert_v1alpha3_DockerMachine_To_v1beta1_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.DockerMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Volumes = restored.Spec.Volumes
+	dst.Spec.Network = restored.Spec.Network
+	dst.Spec.PreDeleteHook = restored.Spec.PreDeleteHook
+	dst.Spec.Resources = restored.Spec.Resources
+	dst.Status.FailureDomain = restored.Status.FailureDomain
+	dst.Status.RestoredImage = restored.Status.RestoredImage
+	dst.Status.Restoring = restored.Status.Restoring
+
+	return nil
 }
 
 func (dst *DockerMachine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.DockerMachine)
 
-	return Convert_v1beta1_DockerMachine_To_v1alpha3_DockerMachine(src, dst, nil)
+	if err := Convert_v1beta1_DockerMachine_To_v1alpha3_DockerMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *DockerMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1beta1.DockerMachineTemplate)
 
-	return Convert_v1alpha3_DockerMachineTemplate_To_v1beta1_DockerMachineTemplate(src, dst, nil)
+	if err := Convert_v1alpha3_DockerMachineTemplate_To_v1beta1_DockerMachineTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.DockerMachineTemplate{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Template.Spec.Volumes = restored.Spec.Template.Spec.Volumes
+	dst.Spec.Template.Spec.Network = restored.Spec.Template.Spec.Network
+	dst.Spec.Template.Spec.PreDeleteHook = restored.Spec.Template.Spec.PreDeleteHook
+	dst.Spec.Template.Spec.Resources = restored.Spec.Template.Spec.Resources
+
+	return nil
 }
 
 func (dst *DockerMachineTemplate) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1beta1.DockerMachineTemplate)
 
-	return Convert_v1beta1_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate(src, dst, nil)
+	if err := Convert_v1beta1_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 // Convert_v1beta1_DockerClusterSpec_To_v1alpha3_DockerClusterSpec is an autogenerated conversion function.
@@ -92,3 +128,15 @@ func Convert_v1beta1_DockerClusterSpec_To_v1alpha3_DockerClusterSpec(in *v1beta1
 	// DockerClusterSpec.LoadBalancer was added in v1alpha4, so automatic conversion is not possible
 	return autoConvert_v1beta1_DockerClusterSpec_To_v1alpha3_DockerClusterSpec(in, out, s)
 }
+
+// Convert_v1beta1_DockerMachineSpec_To_v1alpha3_DockerMachineSpec is an autogenerated conversion function.
+func Convert_v1beta1_DockerMachineSpec_To_v1alpha3_DockerMachineSpec(in *v1beta1.DockerMachineSpec, out *DockerMachineSpec, s apiconversion.Scope) error {
+	// DockerMachineSpec.Volumes, DockerMachineSpec.Network, DockerMachineSpec.PreDeleteHook and DockerMachineSpec.Resources were added in v1beta1, so automatic conversion is not possible
+	return autoConvert_v1beta1_DockerMachineSpec_To_v1alpha3_DockerMachineSpec(in, out, s)
+}
+
+// Convert_v1beta1_DockerMachineStatus_To_v1alpha3_DockerMachineStatus is an autogenerated conversion function.
+func Convert_v1beta1_DockerMachineStatus_To_v1alpha3_DockerMachineStatus(in *v1beta1.DockerMachineStatus, out *DockerMachineStatus, s apiconversion.Scope) error {
+	// DockerMachineStatus.FailureDomain, DockerMachineStatus.RestoredImage and DockerMachineStatus.Restoring were added in v1beta1, so automatic conversion is not possible
+	return autoConvert_v1beta1_DockerMachineStatus_To_v1alpha3_DockerMachineStatus(in, out, s)
+}
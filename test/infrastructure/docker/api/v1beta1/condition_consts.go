@@ -42,6 +42,20 @@ const (
 	// an error while provisioning the container that provides the DockerMachine infrastructure; those kind of
 	// errors are usually transient and failed provisioning are automatically re-tried by the controller.
 	ContainerProvisioningFailedReason = "ContainerProvisioningFailed"
+
+	// ImagePullFailedReason (Severity=Warning) documents a DockerMachine controller failing to pull the image
+	// for the container that provides the DockerMachine infrastructure, e.g. because the image for the requested
+	// Kubernetes version does not exist; pulling is automatically re-tried by the controller.
+	ImagePullFailedReason = "ImagePullFailed"
+
+	// PreDeleteHookFailedReason (Severity=Warning) documents a DockerMachine controller failing to run the pre-delete
+	// hook before deleting the container that provides the DockerMachine infrastructure; the hook is automatically
+	// re-tried by the controller, unless the hook is configured to ignore failures.
+	PreDeleteHookFailedReason = "PreDeleteHookFailed"
+
+	// RestoringReason (Severity=Info) documents a DockerMachine whose container has been deleted in order to
+	// re-create it from the image requested with the restore annotation.
+	RestoringReason = "Restoring"
 )
 
 const (
@@ -78,4 +92,8 @@ const (
 	// an error while provisioning the container that provides the cluster load balancer.; those kind of
 	// errors are usually transient and failed provisioning are automatically re-tried by the controller.
 	LoadBalancerProvisioningFailedReason = "LoadBalancerProvisioningFailed"
+
+	// WaitingForControlPlaneEndpointReason (Severity=Info) documents a DockerCluster with the load balancer disabled
+	// waiting for the externally managed control plane endpoint to be set.
+	WaitingForControlPlaneEndpointReason = "WaitingForControlPlaneEndpoint"
 )
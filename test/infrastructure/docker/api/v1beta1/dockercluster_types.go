@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -52,6 +53,29 @@ type DockerClusterSpec struct {
 type DockerLoadBalancer struct {
 	// ImageMeta allows customizing the image used for the cluster load balancer.
 	ImageMeta `json:",inline"`
+
+	// Disabled disables the load balancer managed by CAPD, so the control plane endpoint is externally managed
+	// and it must be set in spec.controlPlaneEndpoint, either by the user or by an external controller; the
+	// DockerCluster is not ready until the control plane endpoint is set. This field is immutable.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Port is the port the load balancer listens on for the Kubernetes API server, and thus the port
+	// of the cluster control plane endpoint. This field is immutable.
+	// If not set, 6443 will be used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// CustomHAProxyConfigTemplateRef allows replacing the default HAProxy configuration, e.g. for using a different
+	// balancing algorithm. It references a ConfigMap in the same namespace of the DockerCluster, where the key "value"
+	// contains a Go template for the HAProxy configuration; the template is rendered with the following data:
+	// .ControlPlanePort (the port to listen on), .BackendServers (a map of control plane node names to their address)
+	// and .IPv6 (true for IPv6 clusters).
+	// The load balancer configuration is regenerated and reloaded whenever the ConfigMap changes.
+	// +optional
+	CustomHAProxyConfigTemplateRef *corev1.LocalObjectReference `json:"customHAProxyConfigTemplateRef,omitempty"`
 }
 
 // ImageMeta allows customizing the image used for components that are not
@@ -91,6 +115,11 @@ type APIEndpoint struct {
 	Port int `json:"port"`
 }
 
+// IsValid returns true if both host and port are non-zero values.
+func (v APIEndpoint) IsValid() bool {
+	return v.Host != "" && v.Port != 0
+}
+
 // +kubebuilder:resource:path=dockerclusters,scope=Namespaced,categories=cluster-api
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
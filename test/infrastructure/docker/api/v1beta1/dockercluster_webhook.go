@@ -17,9 +17,13 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -46,6 +50,7 @@ var _ webhook.Validator = &DockerCluster{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (c *DockerCluster) ValidateCreate() error {
 	allErrs := validateDockerClusterSpec(c.Spec)
+	allErrs = append(allErrs, validateExternallyManagedDockerCluster(c)...)
 	if len(allErrs) > 0 {
 		return apierrors.NewInvalid(GroupVersion.WithKind("DockerCluster").GroupKind(), c.Name, allErrs)
 	}
@@ -53,7 +58,39 @@ func (c *DockerCluster) ValidateCreate() error {
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (c *DockerCluster) ValidateUpdate(old runtime.Object) error {
+func (c *DockerCluster) ValidateUpdate(oldRaw runtime.Object) error {
+	old, ok := oldRaw.(*DockerCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a DockerCluster but got a %T", oldRaw))
+	}
+
+	allErrs := validateDockerClusterSpec(c.Spec)
+	allErrs = append(allErrs, validateExternallyManagedDockerCluster(c)...)
+
+	// Switching between a DockerCluster managed by CAPD and an externally managed one would leave either CAPD or
+	// the external management system dealing with resources it did not create.
+	if annotations.IsExternallyManaged(c) != annotations.IsExternallyManaged(old) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("metadata", "annotations").Key(clusterv1.ManagedByAnnotation), "annotation can't be added or removed after creation"))
+	}
+
+	// The load balancer port is used as the port of the control plane endpoint, which can't be changed once the cluster is created.
+	if c.Spec.LoadBalancer.Port != old.Spec.LoadBalancer.Port {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "loadBalancer", "port"), "field is immutable"))
+	}
+
+	// Switching between a load balancer managed by CAPD and an externally managed control plane endpoint would change the endpoint.
+	if c.Spec.LoadBalancer.Disabled != old.Spec.LoadBalancer.Disabled {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "loadBalancer", "disabled"), "field is immutable"))
+	}
+
+	// An externally managed control plane endpoint can be set once, but it can't be changed afterwards.
+	if c.Spec.LoadBalancer.Disabled && old.Spec.ControlPlaneEndpoint.IsValid() && c.Spec.ControlPlaneEndpoint != old.Spec.ControlPlaneEndpoint {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "controlPlaneEndpoint"), "field is immutable once set"))
+	}
+
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("DockerCluster").GroupKind(), c.Name, allErrs)
+	}
 	return nil
 }
 
@@ -67,3 +104,14 @@ func defaultDockerClusterSpec(s *DockerClusterSpec) {}
 func validateDockerClusterSpec(s DockerClusterSpec) field.ErrorList {
 	return nil
 }
+
+// validateExternallyManagedDockerCluster validates a DockerCluster with the managed-by annotation; CAPD doesn't create
+// a load balancer for it, so the control plane endpoint must be provided by the external management system.
+func validateExternallyManagedDockerCluster(c *DockerCluster) field.ErrorList {
+	if !annotations.IsExternallyManaged(c) || c.Spec.LoadBalancer.Disabled {
+		return nil
+	}
+	return field.ErrorList{
+		field.Invalid(field.NewPath("spec", "loadBalancer", "disabled"), c.Spec.LoadBalancer.Disabled, "must be true for an externally managed DockerCluster"),
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestDockerClusterValidateUpdate(t *testing.T) {
+	oldCluster := &DockerCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "dockercluster-test", Namespace: "test-namespace"},
+		Spec: DockerClusterSpec{
+			LoadBalancer: DockerLoadBalancer{Port: 7443},
+		},
+	}
+
+	t.Run("update dockercluster should pass if the load balancer port is not changed", func(t *testing.T) {
+		g := NewWithT(t)
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.LoadBalancer.ImageTag = "v1.0.0"
+		g.Expect(newCluster.ValidateUpdate(oldCluster)).To(Succeed())
+	})
+
+	t.Run("update dockercluster should not pass if the load balancer port is changed", func(t *testing.T) {
+		g := NewWithT(t)
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.LoadBalancer.Port = 8443
+		g.Expect(newCluster.ValidateUpdate(oldCluster)).NotTo(Succeed())
+	})
+
+	t.Run("update dockercluster should not pass if the load balancer is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.LoadBalancer.Disabled = true
+		g.Expect(newCluster.ValidateUpdate(oldCluster)).NotTo(Succeed())
+	})
+
+	t.Run("update dockercluster should pass if the external control plane endpoint is set", func(t *testing.T) {
+		g := NewWithT(t)
+		oldExternalCluster := oldCluster.DeepCopy()
+		oldExternalCluster.Spec.LoadBalancer.Disabled = true
+		newCluster := oldExternalCluster.DeepCopy()
+		newCluster.Spec.ControlPlaneEndpoint = APIEndpoint{Host: "10.0.0.1", Port: 6443}
+		g.Expect(newCluster.ValidateUpdate(oldExternalCluster)).To(Succeed())
+	})
+
+	t.Run("update dockercluster should not pass if the external control plane endpoint is changed", func(t *testing.T) {
+		g := NewWithT(t)
+		oldExternalCluster := oldCluster.DeepCopy()
+		oldExternalCluster.Spec.LoadBalancer.Disabled = true
+		oldExternalCluster.Spec.ControlPlaneEndpoint = APIEndpoint{Host: "10.0.0.1", Port: 6443}
+		newCluster := oldExternalCluster.DeepCopy()
+		newCluster.Spec.ControlPlaneEndpoint.Host = "10.0.0.2"
+		g.Expect(newCluster.ValidateUpdate(oldExternalCluster)).NotTo(Succeed())
+	})
+
+	t.Run("update dockercluster should not pass if the managed-by annotation is added", func(t *testing.T) {
+		g := NewWithT(t)
+		oldExternalCluster := oldCluster.DeepCopy()
+		oldExternalCluster.Spec.LoadBalancer.Disabled = true
+		newCluster := oldExternalCluster.DeepCopy()
+		newCluster.Annotations = map[string]string{clusterv1.ManagedByAnnotation: "external-system"}
+		g.Expect(newCluster.ValidateUpdate(oldExternalCluster)).NotTo(Succeed())
+	})
+
+	t.Run("update dockercluster should not pass if the managed-by annotation is removed", func(t *testing.T) {
+		g := NewWithT(t)
+		oldExternalCluster := oldCluster.DeepCopy()
+		oldExternalCluster.Annotations = map[string]string{clusterv1.ManagedByAnnotation: "external-system"}
+		oldExternalCluster.Spec.LoadBalancer.Disabled = true
+		newCluster := oldExternalCluster.DeepCopy()
+		newCluster.Annotations = nil
+		g.Expect(newCluster.ValidateUpdate(oldExternalCluster)).NotTo(Succeed())
+	})
+}
+
+func TestDockerClusterValidateCreate(t *testing.T) {
+	t.Run("create externally managed dockercluster should pass if the load balancer is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		c := &DockerCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "dockercluster-test",
+				Namespace:   "test-namespace",
+				Annotations: map[string]string{clusterv1.ManagedByAnnotation: "external-system"},
+			},
+			Spec: DockerClusterSpec{
+				LoadBalancer: DockerLoadBalancer{Disabled: true},
+			},
+		}
+		g.Expect(c.ValidateCreate()).To(Succeed())
+	})
+
+	t.Run("create externally managed dockercluster should not pass if the load balancer is enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		c := &DockerCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "dockercluster-test",
+				Namespace:   "test-namespace",
+				Annotations: map[string]string{clusterv1.ManagedByAnnotation: "external-system"},
+			},
+		}
+		g.Expect(c.ValidateCreate()).NotTo(Succeed())
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -25,6 +26,17 @@ const (
 	// MachineFinalizer allows ReconcileDockerMachine to clean up resources associated with AWSMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "dockermachine.infrastructure.cluster.x-k8s.io"
+
+	// SnapshotAnnotation requests to commit the container hosting a DockerMachine to an image; the value of the
+	// annotation is the name of the image. The annotation is removed once the snapshot is completed.
+	// NOTE: volumes, including /var, are not part of the snapshot.
+	SnapshotAnnotation = "dockermachine.infrastructure.cluster.x-k8s.io/snapshot"
+
+	// RestoreAnnotation requests to re-create the container hosting a DockerMachine from an image, e.g. one taken
+	// with SnapshotAnnotation; the value of the annotation is the name of the image. Named volumes are preserved,
+	// and the machine is bootstrapped again; the image is reported in status.restoredImage, and it is used instead of
+	// spec.customImage from now on. The annotation is removed once the container has been deleted.
+	RestoreAnnotation = "dockermachine.infrastructure.cluster.x-k8s.io/restore"
 )
 
 // DockerMachineSpec defines the desired state of DockerMachine.
@@ -48,6 +60,27 @@ type DockerMachineSpec struct {
 	// +optional
 	ExtraMounts []Mount `json:"extraMounts,omitempty"`
 
+	// Volumes describes named docker volumes to mount into the node container.
+	// Differently from ExtraMounts, volumes are managed by docker and their data persists
+	// across container restarts until the DockerMachine is deleted, e.g. for persisting etcd data.
+	// +optional
+	Volumes []Volume `json:"volumes,omitempty"`
+
+	// Network allows to customize the networking of the node container, e.g. to attach it to
+	// additional docker networks, to assign static IPs or to expose ports on the host.
+	// +optional
+	Network DockerMachineNetwork `json:"network,omitempty"`
+
+	// PreDeleteHook allows to run a command inside the node container before deleting it, e.g. `kubeadm reset`,
+	// and to stop the node container gracefully before removing it.
+	// +optional
+	PreDeleteHook *DockerMachinePreDeleteHook `json:"preDeleteHook,omitempty"`
+
+	// Resources allows to limit the CPU and memory the node container can use, e.g. to pack
+	// more machines on a host or to simulate resource pressure on the node.
+	// +optional
+	Resources DockerMachineResources `json:"resources,omitempty"`
+
 	// Bootstrapped is true when the kubeadm bootstrapping has been run
 	// against this machine
 	// +optional
@@ -70,6 +103,99 @@ type Mount struct {
 	Readonly bool `json:"readOnly,omitempty"`
 }
 
+// Volume specifies a named docker volume to mount into a container.
+type Volume struct {
+	// Name of the volume. The docker volume is named after the machine container and this name,
+	// so each machine gets its own volume.
+	Name string `json:"name"`
+
+	// Path of the mount within the container.
+	ContainerPath string `json:"containerPath"`
+
+	// If set, the mount is read-only.
+	// +optional
+	Readonly bool `json:"readOnly,omitempty"`
+}
+
+// DockerMachineResources defines the resource constraints of a node container.
+type DockerMachineResources struct {
+	// CPU is the maximum amount of CPU the node container can use, e.g. "1.5" or "500m".
+	// If not set, the CPU usage is not limited.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the maximum amount of memory the node container can use, e.g. "2Gi".
+	// If not set, the memory usage is not limited.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+// DockerMachinePreDeleteHook defines the operations to run on the node container before deleting it.
+type DockerMachinePreDeleteHook struct {
+	// Command is the command to run inside the node container before deleting it, e.g. ["kubeadm", "reset", "--force"].
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Timeout is the maximum duration of the command; defaults to 1 minute.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// IgnoreFailure allows to delete the node container even if the command fails;
+	// if false, the command is retried until it succeeds.
+	// +optional
+	IgnoreFailure bool `json:"ignoreFailure,omitempty"`
+
+	// StopTimeout, if set, makes the node container stop gracefully before being removed; the container
+	// is sent a SIGTERM and it is killed if it does not stop within StopTimeout.
+	// +optional
+	StopTimeout *metav1.Duration `json:"stopTimeout,omitempty"`
+}
+
+// DockerMachineNetwork defines the networking of a node container.
+type DockerMachineNetwork struct {
+	// IPv4Address is the static IPv4 address of the node container on the cluster network.
+	// If not set, the address is assigned by docker.
+	// NOTE: the address must belong to the subnet of the cluster network.
+	// +optional
+	IPv4Address string `json:"ipv4Address,omitempty"`
+
+	// IPv6Address is the static IPv6 address of the node container on the cluster network.
+	// If not set, the address is assigned by docker.
+	// NOTE: the address must belong to the subnet of the cluster network.
+	// +optional
+	IPv6Address string `json:"ipv6Address,omitempty"`
+
+	// AdditionalNetworks are the names of existing docker networks to attach the node container to,
+	// in addition to the cluster network.
+	// +optional
+	AdditionalNetworks []string `json:"additionalNetworks,omitempty"`
+
+	// PortMappings are the ports of the node container to expose on the host.
+	// +optional
+	PortMappings []PortMapping `json:"portMappings,omitempty"`
+}
+
+// PortMapping specifies a port of a container to expose on the host.
+// This is a simplified version of kind v1alpha4.PortMapping types.
+type PortMapping struct {
+	// ContainerPort is the port in the container to expose.
+	ContainerPort int32 `json:"containerPort"`
+
+	// HostPort is the port on the host. If not set, a random port is used.
+	// +optional
+	HostPort int32 `json:"hostPort,omitempty"`
+
+	// ListenAddress is the address on the host to bind the port to. If not set, the port
+	// is bound to all the addresses.
+	// +optional
+	ListenAddress string `json:"listenAddress,omitempty"`
+
+	// Protocol of the port. Defaults to TCP.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+}
+
 // DockerMachineStatus defines the observed state of DockerMachine.
 type DockerMachineStatus struct {
 	// Ready denotes that the machine (docker container) is ready
@@ -85,6 +211,22 @@ type DockerMachineStatus struct {
 	// +optional
 	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
 
+	// FailureDomain is the failure domain the machine has been assigned to by Cluster API; it is also
+	// recorded as a label on the node container. Failure domains don't mean much in CAPD since it's
+	// all local, but this allows to test how the rest of Cluster API spreads machines across them.
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
+	// RestoredImage is the image the machine has been restored from, as requested with the restore annotation;
+	// once set, the container hosting the machine is created from this image instead of spec.customImage.
+	// +optional
+	RestoredImage string `json:"restoredImage,omitempty"`
+
+	// Restoring denotes that the container hosting the machine is being re-created from RestoredImage,
+	// and that the machine has to be bootstrapped again.
+	// +optional
+	Restoring bool `json:"restoring,omitempty"`
+
 	// Conditions defines current service state of the DockerMachine.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
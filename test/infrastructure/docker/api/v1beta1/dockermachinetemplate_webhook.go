@@ -18,9 +18,13 @@ package v1beta1
 
 import (
 	"fmt"
+	"net"
+	"path"
 	"reflect"
+	"regexp"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -29,6 +33,12 @@ import (
 
 const dockerMachineTemplateImmutableMsg = "DockerMachineTemplate spec.template.spec field is immutable. Please create a new resource instead."
 
+// volumeNameRegexp matches the names allowed by docker for volumes.
+var volumeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// minMachineMemory is the minimum memory limit allowed by docker for a container.
+var minMachineMemory = resource.MustParse("6Mi")
+
 func (m *DockerMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(m).
@@ -41,7 +51,11 @@ var _ webhook.Validator = &DockerMachineTemplate{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (m *DockerMachineTemplate) ValidateCreate() error {
-	return nil
+	allErrs := validateDockerMachineSpec(m.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("DockerMachineTemplate").GroupKind(), m.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -64,3 +78,90 @@ func (m *DockerMachineTemplate) ValidateUpdate(oldRaw runtime.Object) error {
 func (m *DockerMachineTemplate) ValidateDelete() error {
 	return nil
 }
+
+func validateDockerMachineSpec(spec DockerMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	for i, v := range spec.Volumes {
+		if !volumeNameRegexp.MatchString(v.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("volumes").Index(i).Child("name"), v.Name, fmt.Sprintf("must match the regex %s", volumeNameRegexp.String())))
+		}
+		if names[v.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("volumes").Index(i).Child("name"), v.Name))
+		}
+		names[v.Name] = true
+		if !path.IsAbs(v.ContainerPath) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("volumes").Index(i).Child("containerPath"), v.ContainerPath, "must be an absolute path"))
+		}
+	}
+	allErrs = append(allErrs, validateDockerMachineNetwork(spec.Network, fldPath.Child("network"))...)
+	if spec.PreDeleteHook != nil {
+		allErrs = append(allErrs, validateDockerMachinePreDeleteHook(*spec.PreDeleteHook, fldPath.Child("preDeleteHook"))...)
+	}
+	allErrs = append(allErrs, validateDockerMachineResources(spec.Resources, fldPath.Child("resources"))...)
+	return allErrs
+}
+
+func validateDockerMachineResources(resources DockerMachineResources, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if resources.CPU != nil && resources.CPU.MilliValue() <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cpu"), resources.CPU.String(), "must be greater than 0"))
+	}
+	if resources.Memory != nil && resources.Memory.Value() < minMachineMemory.Value() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("memory"), resources.Memory.String(), fmt.Sprintf("must be at least %s", minMachineMemory.String())))
+	}
+	return allErrs
+}
+
+func validateDockerMachinePreDeleteHook(hook DockerMachinePreDeleteHook, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(hook.Command) > 0 && hook.Command[0] == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("command"), hook.Command, "the command name must not be empty"))
+	}
+	if hook.Timeout != nil && hook.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), hook.Timeout.Duration.String(), "must be greater than 0"))
+	}
+	if hook.StopTimeout != nil && hook.StopTimeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("stopTimeout"), hook.StopTimeout.Duration.String(), "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+func validateDockerMachineNetwork(network DockerMachineNetwork, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if network.IPv4Address != "" {
+		if ip := net.ParseIP(network.IPv4Address); ip == nil || ip.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ipv4Address"), network.IPv4Address, "must be a valid IPv4 address"))
+		}
+	}
+	if network.IPv6Address != "" {
+		if ip := net.ParseIP(network.IPv6Address); ip == nil || ip.To4() != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ipv6Address"), network.IPv6Address, "must be a valid IPv6 address"))
+		}
+	}
+
+	networks := map[string]bool{}
+	for i, n := range network.AdditionalNetworks {
+		if n == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("additionalNetworks").Index(i), "network name must not be empty"))
+			continue
+		}
+		if networks[n] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("additionalNetworks").Index(i), n))
+		}
+		networks[n] = true
+	}
+
+	for i, pm := range network.PortMappings {
+		if pm.ContainerPort < 1 || pm.ContainerPort > 65535 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("portMappings").Index(i).Child("containerPort"), pm.ContainerPort, "must be between 1 and 65535"))
+		}
+		if pm.HostPort < 0 || pm.HostPort > 65535 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("portMappings").Index(i).Child("hostPort"), pm.HostPort, "must be between 0 and 65535"))
+		}
+		if pm.ListenAddress != "" && net.ParseIP(pm.ListenAddress) == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("portMappings").Index(i).Child("listenAddress"), pm.ListenAddress, "must be a valid IP address"))
+		}
+	}
+	return allErrs
+}
@@ -18,7 +18,9 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -61,3 +63,124 @@ func TestDockerMachineTemplateInvalid(t *testing.T) {
 		})
 	}
 }
+
+func TestDockerMachineTemplateValidateCreate(t *testing.T) {
+	tests := []struct {
+		name          string
+		volumes       []Volume
+		network       DockerMachineNetwork
+		preDeleteHook *DockerMachinePreDeleteHook
+		resources     DockerMachineResources
+		wantError     bool
+	}{
+		{
+			name:      "allow volumes",
+			volumes:   []Volume{{Name: "etcd", ContainerPath: "/var/lib/etcd"}, {Name: "data", ContainerPath: "/data", Readonly: true}},
+			wantError: false,
+		},
+		{
+			name:      "don't allow invalid volume names",
+			volumes:   []Volume{{Name: "/etcd", ContainerPath: "/var/lib/etcd"}},
+			wantError: true,
+		},
+		{
+			name:      "don't allow duplicated volume names",
+			volumes:   []Volume{{Name: "etcd", ContainerPath: "/var/lib/etcd"}, {Name: "etcd", ContainerPath: "/data"}},
+			wantError: true,
+		},
+		{
+			name:      "don't allow relative container paths",
+			volumes:   []Volume{{Name: "etcd", ContainerPath: "var/lib/etcd"}},
+			wantError: true,
+		},
+		{
+			name: "allow network customization",
+			network: DockerMachineNetwork{
+				IPv4Address:        "172.18.0.10",
+				IPv6Address:        "fc00:f853:ccd:e793::10",
+				AdditionalNetworks: []string{"storage"},
+				PortMappings:       []PortMapping{{ContainerPort: 80}, {ContainerPort: 443, HostPort: 8443, ListenAddress: "127.0.0.1", Protocol: "TCP"}},
+			},
+			wantError: false,
+		},
+		{
+			name:      "don't allow invalid IPv4 addresses",
+			network:   DockerMachineNetwork{IPv4Address: "fc00:f853:ccd:e793::10"},
+			wantError: true,
+		},
+		{
+			name:      "don't allow invalid IPv6 addresses",
+			network:   DockerMachineNetwork{IPv6Address: "172.18.0.10"},
+			wantError: true,
+		},
+		{
+			name:      "don't allow duplicated additional networks",
+			network:   DockerMachineNetwork{AdditionalNetworks: []string{"storage", "storage"}},
+			wantError: true,
+		},
+		{
+			name:      "don't allow invalid container ports",
+			network:   DockerMachineNetwork{PortMappings: []PortMapping{{ContainerPort: 0}}},
+			wantError: true,
+		},
+		{
+			name:      "don't allow invalid host ports",
+			network:   DockerMachineNetwork{PortMappings: []PortMapping{{ContainerPort: 80, HostPort: 70000}}},
+			wantError: true,
+		},
+		{
+			name: "allow pre-delete hooks",
+			preDeleteHook: &DockerMachinePreDeleteHook{
+				Command:     []string{"kubeadm", "reset", "--force"},
+				Timeout:     &metav1.Duration{Duration: 2 * time.Minute},
+				StopTimeout: &metav1.Duration{Duration: 30 * time.Second},
+			},
+			wantError: false,
+		},
+		{
+			name:          "don't allow empty pre-delete hook commands",
+			preDeleteHook: &DockerMachinePreDeleteHook{Command: []string{"", "reset"}},
+			wantError:     true,
+		},
+		{
+			name:          "don't allow invalid pre-delete hook timeouts",
+			preDeleteHook: &DockerMachinePreDeleteHook{Command: []string{"kubeadm", "reset", "--force"}, Timeout: &metav1.Duration{}},
+			wantError:     true,
+		},
+		{
+			name:      "allow resource limits",
+			resources: DockerMachineResources{CPU: resourceQuantity("500m"), Memory: resourceQuantity("2Gi")},
+			wantError: false,
+		},
+		{
+			name:      "don't allow zero CPU limits",
+			resources: DockerMachineResources{CPU: resourceQuantity("0")},
+			wantError: true,
+		},
+		{
+			name:      "don't allow memory limits lower than the docker minimum",
+			resources: DockerMachineResources{Memory: resourceQuantity("1Mi")},
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &DockerMachineTemplate{
+				Spec: DockerMachineTemplateSpec{
+					Template: DockerMachineTemplateResource{
+						Spec: DockerMachineSpec{Volumes: tt.volumes, Network: tt.network, PreDeleteHook: tt.preDeleteHook, Resources: tt.resources},
+					},
+				},
+			}
+			err := template.ValidateCreate()
+			if (err != nil) != tt.wantError {
+				t.Errorf("unexpected result - wanted %+v, got %+v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+func resourceQuantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
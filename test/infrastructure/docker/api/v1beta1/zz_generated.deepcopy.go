@@ -21,6 +21,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -110,7 +112,7 @@ func (in *DockerClusterSpec) DeepCopyInto(out *DockerClusterSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	out.LoadBalancer = in.LoadBalancer
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerClusterSpec.
@@ -246,6 +248,11 @@ func (in *DockerClusterTemplateSpec) DeepCopy() *DockerClusterTemplateSpec {
 func (in *DockerLoadBalancer) DeepCopyInto(out *DockerLoadBalancer) {
 	*out = *in
 	out.ImageMeta = in.ImageMeta
+	if in.CustomHAProxyConfigTemplateRef != nil {
+		in, out := &in.CustomHAProxyConfigTemplateRef, &out.CustomHAProxyConfigTemplateRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerLoadBalancer.
@@ -317,6 +324,86 @@ func (in *DockerMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachineNetwork) DeepCopyInto(out *DockerMachineNetwork) {
+	*out = *in
+	if in.AdditionalNetworks != nil {
+		in, out := &in.AdditionalNetworks, &out.AdditionalNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PortMappings != nil {
+		in, out := &in.PortMappings, &out.PortMappings
+		*out = make([]PortMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineNetwork.
+func (in *DockerMachineNetwork) DeepCopy() *DockerMachineNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachineNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachinePreDeleteHook) DeepCopyInto(out *DockerMachinePreDeleteHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StopTimeout != nil {
+		in, out := &in.StopTimeout, &out.StopTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachinePreDeleteHook.
+func (in *DockerMachinePreDeleteHook) DeepCopy() *DockerMachinePreDeleteHook {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachinePreDeleteHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerMachineResources) DeepCopyInto(out *DockerMachineResources) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineResources.
+func (in *DockerMachineResources) DeepCopy() *DockerMachineResources {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerMachineResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DockerMachineSpec) DeepCopyInto(out *DockerMachineSpec) {
 	*out = *in
@@ -335,6 +422,18 @@ func (in *DockerMachineSpec) DeepCopyInto(out *DockerMachineSpec) {
 		*out = make([]Mount, len(*in))
 		copy(*out, *in)
 	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]Volume, len(*in))
+		copy(*out, *in)
+	}
+	in.Network.DeepCopyInto(&out.Network)
+	if in.PreDeleteHook != nil {
+		in, out := &in.PreDeleteHook, &out.PreDeleteHook
+		*out = new(DockerMachinePreDeleteHook)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerMachineSpec.
@@ -355,6 +454,11 @@ func (in *DockerMachineStatus) DeepCopyInto(out *DockerMachineStatus) {
 		*out = make([]apiv1beta1.MachineAddress, len(*in))
 		copy(*out, *in)
 	}
+	if in.FailureDomain != nil {
+		in, out := &in.FailureDomain, &out.FailureDomain
+		*out = new(string)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -493,3 +597,33 @@ func (in *Mount) DeepCopy() *Mount {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortMapping) DeepCopyInto(out *PortMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortMapping.
+func (in *PortMapping) DeepCopy() *PortMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(PortMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Volume) DeepCopyInto(out *Volume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Volume.
+func (in *Volume) DeepCopy() *Volume {
+	if in == nil {
+		return nil
+	}
+	out := new(Volume)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -24,18 +24,22 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
@@ -47,6 +51,7 @@ type DockerClusterReconciler struct {
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockerclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockerclusters/status;dockerclusters/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a DockerCluster object and makes changes based on the state read
 // and what is in the DockerCluster.Spec.
@@ -62,6 +67,12 @@ func (r *DockerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// The DockerCluster is externally managed, so its lifecycle and status are owned by the external management system.
+	if annotations.IsExternallyManaged(dockerCluster) {
+		log.V(4).Info("DockerCluster is externally managed, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
 	// Fetch the Cluster.
 	cluster, err := util.GetOwnerCluster(ctx, r.Client, dockerCluster.ObjectMeta)
 	if err != nil {
@@ -72,10 +83,11 @@ func (r *DockerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(logutil.ClusterKey, logutil.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
 
 	// Create a helper for managing a docker container hosting the loadbalancer.
-	externalLoadBalancer, err := docker.NewLoadBalancer(cluster, dockerCluster)
+	externalLoadBalancer, err := docker.NewLoadBalancer(r.Client, cluster, dockerCluster)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalLoadBalancer")
 	}
@@ -137,6 +149,18 @@ func patchDockerCluster(ctx context.Context, patchHelper *patch.Helper, dockerCl
 }
 
 func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerCluster *infrav1.DockerCluster, externalLoadBalancer *docker.LoadBalancer) (ctrl.Result, error) {
+	// If the load balancer is disabled, the control plane endpoint is externally managed, so the DockerCluster
+	// is ready as soon as the control plane endpoint is set.
+	if dockerCluster.Spec.LoadBalancer.Disabled {
+		if !dockerCluster.Spec.ControlPlaneEndpoint.IsValid() {
+			conditions.MarkFalse(dockerCluster, infrav1.LoadBalancerAvailableCondition, infrav1.WaitingForControlPlaneEndpointReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{}, nil
+		}
+		dockerCluster.Status.Ready = true
+		conditions.MarkTrue(dockerCluster, infrav1.LoadBalancerAvailableCondition)
+		return ctrl.Result{}, nil
+	}
+
 	// Create the docker container hosting the load balancer.
 	if err := externalLoadBalancer.Create(ctx); err != nil {
 		conditions.MarkFalse(dockerCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
@@ -150,9 +174,16 @@ func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerClu
 		return ctrl.Result{}, errors.Wrap(err, "failed to get ip for the load balancer")
 	}
 
+	// Regenerate the load balancer configuration, so changes to the configuration template are picked up;
+	// changes to the control plane nodes are handled by the DockerMachine controller.
+	if err := externalLoadBalancer.UpdateConfiguration(ctx); err != nil {
+		conditions.MarkFalse(dockerCluster, infrav1.LoadBalancerAvailableCondition, infrav1.LoadBalancerProvisioningFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, errors.Wrap(err, "failed to update load balancer configuration")
+	}
+
 	dockerCluster.Spec.ControlPlaneEndpoint = infrav1.APIEndpoint{
 		Host: lbIP,
-		Port: 6443,
+		Port: int(externalLoadBalancer.Port()),
 	}
 
 	// Mark the dockerCluster ready
@@ -176,9 +207,11 @@ func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, dockerClu
 		return ctrl.Result{}, errors.Wrap(err, "failed to patch DockerCluster")
 	}
 
-	// Delete the docker container hosting the load balancer
-	if err := externalLoadBalancer.Delete(ctx); err != nil {
-		return ctrl.Result{}, errors.Wrap(err, "failed to delete load balancer")
+	// Delete the docker container hosting the load balancer, if any
+	if !dockerCluster.Spec.LoadBalancer.Disabled {
+		if err := externalLoadBalancer.Delete(ctx); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete load balancer")
+		}
 	}
 
 	// Cluster is deleted so remove the finalizer.
@@ -193,13 +226,39 @@ func (r *DockerClusterReconciler) SetupWithManager(mgr ctrl.Manager, options con
 		For(&infrav1.DockerCluster{}).
 		WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPaused(r.Log)).
+		WithEventFilter(predicates.ResourceIsNotExternallyManaged(r.Log)).
 		Build(r)
 	if err != nil {
 		return err
 	}
-	return c.Watch(
+	if err := c.Watch(
 		&source.Kind{Type: &clusterv1.Cluster{}},
 		handler.EnqueueRequestsFromMapFunc(util.ClusterToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("DockerCluster"))),
 		predicates.ClusterUnpaused(r.Log),
+	); err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(r.configMapToDockerClusters),
 	)
 }
+
+// configMapToDockerClusters is a handler.MapFunc to be used to enqueue requests for reconciliation
+// for the DockerClusters using a ConfigMap as load balancer configuration template.
+func (r *DockerClusterReconciler) configMapToDockerClusters(o client.Object) []reconcile.Request {
+	dockerClusters := &infrav1.DockerClusterList{}
+	if err := r.Client.List(context.TODO(), dockerClusters, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, dockerCluster := range dockerClusters.Items {
+		ref := dockerCluster.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef
+		if ref == nil || ref.Name != o.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Namespace: dockerCluster.Namespace, Name: dockerCluster.Name}})
+	}
+	return requests
+}
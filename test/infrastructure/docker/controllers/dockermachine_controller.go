@@ -31,8 +31,10 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/requeue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -42,15 +44,33 @@ import (
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 )
 
+const (
+	// imagePullRetryInterval is how long to wait before trying to pull the image for a machine again after a failure.
+	imagePullRetryInterval = 10 * time.Second
+
+	// maxImagePullRetryInterval is the maximum interval between attempts to pull an image that is persistently failing.
+	maxImagePullRetryInterval = 5 * time.Minute
+)
+
 // DockerMachineReconciler reconciles a DockerMachine object.
 type DockerMachineReconciler struct {
 	client.Client
+
+	// PrePullImages enables pulling the image for the container hosting the machine as soon as the machine
+	// is reconciled, without waiting for the bootstrap data to be available.
+	PrePullImages bool
+
+	// imagePullBackoff tracks consecutive image pull failures, so machines with an image that cannot be pulled
+	// are retried with an increasing interval.
+	imagePullBackoff requeue.Backoff
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachines/status;dockermachines/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machines,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // Reconcile handles DockerMachine events.
 func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
@@ -75,7 +95,7 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
-	log = log.WithValues("machine", machine.Name)
+	log = log.WithValues(logutil.MachineKey, logutil.KObj(machine))
 
 	// Fetch the Cluster.
 	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
@@ -88,7 +108,7 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(logutil.ClusterKey, logutil.KObj(cluster))
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, dockerMachine) {
@@ -107,7 +127,8 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
-	log = log.WithValues("docker-cluster", dockerCluster.Name)
+	log = log.WithValues("DockerCluster", logutil.KObj(dockerCluster))
+	ctx = ctrl.LoggerInto(ctx, log)
 
 	// Initialize the patch helper
 	patchHelper, err := patch.NewHelper(dockerMachine, r)
@@ -138,7 +159,12 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// Create a helper for managing the docker container hosting the machine.
-	externalMachine, err := docker.NewMachine(cluster, machine.Name, dockerMachine.Spec.CustomImage, nil)
+	// If the machine has been restored, the container is created from the restored image.
+	customImage := dockerMachine.Spec.CustomImage
+	if dockerMachine.Status.RestoredImage != "" {
+		customImage = dockerMachine.Status.RestoredImage
+	}
+	externalMachine, err := docker.NewMachine(cluster, machine.Name, customImage, nil)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine")
 	}
@@ -146,10 +172,14 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	// Create a helper for managing a docker container hosting the loadbalancer.
 	// NB. the machine controller has to manage the cluster load balancer because the current implementation of the
 	// docker load balancer does not support auto-discovery of control plane nodes, so CAPD should take care of
-	// updating the cluster load balancer configuration when control plane machines are added/removed
-	externalLoadBalancer, err := docker.NewLoadBalancer(cluster, dockerCluster)
-	if err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalLoadBalancer")
+	// updating the cluster load balancer configuration when control plane machines are added/removed.
+	// If the load balancer is disabled, the control plane endpoint is externally managed and there is no load balancer to update.
+	var externalLoadBalancer *docker.LoadBalancer
+	if !dockerCluster.Spec.LoadBalancer.Disabled {
+		externalLoadBalancer, err = docker.NewLoadBalancer(r.Client, cluster, dockerCluster)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalLoadBalancer")
+		}
 	}
 
 	// Handle deleted machines
@@ -187,8 +217,36 @@ func patchDockerMachine(ctx context.Context, patchHelper *patch.Helper, dockerMa
 func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) (res ctrl.Result, retErr error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	// Record the failure domain the machine has been assigned to.
+	// This is done before checking if the machine is already provisioned, because status is not moved to the target cluster.
+	dockerMachine.Status.FailureDomain = machine.Spec.FailureDomain
+
+	// Snapshot the container hosting the machine, if requested.
+	if image, ok := dockerMachine.Annotations[infrav1.SnapshotAnnotation]; ok && externalMachine.Exists() {
+		if err := externalMachine.Snapshot(ctx, image); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to snapshot the DockerMachine")
+		}
+		delete(dockerMachine.Annotations, infrav1.SnapshotAnnotation)
+	}
+
+	// Restore the machine from an image, if requested; the container is deleted and the machine goes through
+	// provisioning and bootstrap again, using the image from now on.
+	// NOTE: the restore is tracked in status, so the spec of the DockerMachine is not changed.
+	if image, ok := dockerMachine.Annotations[infrav1.RestoreAnnotation]; ok {
+		log.Info("Restoring the DockerMachine", "image", image)
+		if err := externalMachine.Restore(ctx, image); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to restore the DockerMachine")
+		}
+		dockerMachine.Status.RestoredImage = image
+		dockerMachine.Status.Restoring = true
+		dockerMachine.Status.Ready = false
+		dockerMachine.Status.LoadBalancerConfigured = false
+		delete(dockerMachine.Annotations, infrav1.RestoreAnnotation)
+		conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.RestoringReason, clusterv1.ConditionSeverityInfo, "")
+	}
+
 	// if the machine is already provisioned, return
-	if dockerMachine.Spec.ProviderID != nil {
+	if dockerMachine.Spec.ProviderID != nil && !dockerMachine.Status.Restoring {
 		// ensure ready state is set.
 		// This is required after move, because status is not moved to the target cluster.
 		dockerMachine.Status.Ready = true
@@ -196,6 +254,19 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 		return ctrl.Result{}, nil
 	}
 
+	// Pull the image for the container hosting the machine, so failures are surfaced before trying to create the container;
+	// if pre-pull is enabled, this happens while waiting for the bootstrap data to be available.
+	if !externalMachine.Exists() && (r.PrePullImages || machine.Spec.Bootstrap.DataSecretName != nil) {
+		if err := externalMachine.PullImage(ctx, machine.Spec.Version); err != nil {
+			// The machine will be re-reconciled after a pull failure, backing off in case of persistent failures.
+			requeueAfter := r.imagePullBackoff.After(dockerMachine, imagePullRetryInterval, maxImagePullRetryInterval)
+			log.Error(err, "Failed to pull the image for the DockerMachine, retrying", "after", requeueAfter)
+			conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.ImagePullFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+		r.imagePullBackoff.Forget(dockerMachine)
+	}
+
 	// Make sure bootstrap data is available and populated.
 	if machine.Spec.Bootstrap.DataSecretName == nil {
 		if !util.IsControlPlaneMachine(machine) && !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
@@ -217,7 +288,7 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 
 	// Create the machine if not existing yet
 	if !externalMachine.Exists() {
-		if err := externalMachine.Create(ctx, role, machine.Spec.Version, dockerMachine.Spec.ExtraMounts); err != nil {
+		if err := externalMachine.Create(ctx, role, machine.Spec.Version, machine.Spec.FailureDomain, dockerMachine.Spec.ExtraMounts, dockerMachine.Spec.Volumes, dockerMachine.Spec.Network, dockerMachine.Spec.Resources); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to create worker DockerMachine")
 		}
 	}
@@ -232,7 +303,7 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 	// if the machine is a control plane update the load balancer configuration
 	// we should only do this once, as reconfiguration more or less ensures
 	// node ref setting fails
-	if util.IsControlPlaneMachine(machine) && !dockerMachine.Status.LoadBalancerConfigured {
+	if util.IsControlPlaneMachine(machine) && externalLoadBalancer != nil && !dockerMachine.Status.LoadBalancerConfigured {
 		if err := externalLoadBalancer.UpdateConfiguration(ctx); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to update DockerCluster.loadbalancer configuration")
 		}
@@ -259,9 +330,9 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 		}
 	}
 
-	// if the machine isn't bootstrapped, only then run bootstrap scripts
-	if !dockerMachine.Spec.Bootstrapped {
-		bootstrapData, err := r.getBootstrapData(ctx, machine)
+	// if the machine isn't bootstrapped, or it is being restored, only then run bootstrap scripts
+	if !dockerMachine.Spec.Bootstrapped || dockerMachine.Status.Restoring {
+		bootstrapData, format, err := r.getBootstrapData(ctx, machine)
 		if err != nil {
 			log.Error(err, "failed to get bootstrap data")
 			return ctrl.Result{}, err
@@ -270,7 +341,7 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 		timeoutctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
 		defer cancel()
 		// Run the bootstrap script. Simulates cloud-init.
-		if err := externalMachine.ExecBootstrap(timeoutctx, bootstrapData); err != nil {
+		if err := externalMachine.ExecBootstrap(timeoutctx, bootstrapData, format); err != nil {
 			conditions.MarkFalse(dockerMachine, infrav1.BootstrapExecSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning, "Repeating bootstrap")
 			return ctrl.Result{}, errors.Wrap(err, "failed to exec DockerMachine bootstrap")
 		}
@@ -287,7 +358,7 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 	conditions.MarkTrue(dockerMachine, infrav1.BootstrapExecSucceededCondition)
 
 	// set address in machine status
-	machineAddress, err := externalMachine.Address(ctx)
+	machineAddresses, err := externalMachine.Addresses(ctx)
 	if err != nil {
 		log.Error(err, "failed to get the machine address")
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
@@ -298,14 +369,18 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 			Type:    clusterv1.MachineHostName,
 			Address: externalMachine.ContainerName(),
 		},
-		{
-			Type:    clusterv1.MachineInternalIP,
-			Address: machineAddress,
-		},
-		{
-			Type:    clusterv1.MachineExternalIP,
-			Address: machineAddress,
-		},
+	}
+	for _, machineAddress := range machineAddresses {
+		dockerMachine.Status.Addresses = append(dockerMachine.Status.Addresses,
+			clusterv1.MachineAddress{
+				Type:    clusterv1.MachineInternalIP,
+				Address: machineAddress,
+			},
+			clusterv1.MachineAddress{
+				Type:    clusterv1.MachineExternalIP,
+				Address: machineAddress,
+			},
+		)
 	}
 
 	// Usually a cloud provider will do this, but there is no docker-cloud provider.
@@ -321,6 +396,7 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 	// Set ProviderID so the Cluster API Machine Controller can pull it
 	providerID := externalMachine.ProviderID()
 	dockerMachine.Spec.ProviderID = &providerID
+	dockerMachine.Status.Restoring = false
 	dockerMachine.Status.Ready = true
 	conditions.MarkTrue(dockerMachine, infrav1.ContainerProvisionedCondition)
 
@@ -328,6 +404,17 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 }
 
 func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Wait for the pre-terminate hooks on the Machine to be removed before deleting the container; this is already
+	// enforced by the Machine controller before deleting the DockerMachine, but the hooks are honored also when the
+	// DockerMachine is deleted directly, so the hook semantic can be validated against a real provider.
+	if annotations.HasWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, machine.Annotations) {
+		log.Info("Waiting for pre-terminate hooks to be removed from the Machine before deleting the DockerMachine")
+		conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{}, nil
+	}
+
 	// Set the ContainerProvisionedCondition reporting delete is started, and issue a patch in order to make
 	// this visible to the users.
 	// NB. The operation in docker is fast, so there is the chance the user will not notice the status change;
@@ -341,18 +428,31 @@ func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, machine *
 		return ctrl.Result{}, errors.Wrap(err, "failed to patch DockerMachine")
 	}
 
+	// run the pre-delete hook, if any, before deleting the machine
+	if err := externalMachine.ExecPreDeleteHook(ctx, dockerMachine.Spec.PreDeleteHook); err != nil {
+		conditions.MarkFalse(dockerMachine, infrav1.ContainerProvisionedCondition, infrav1.PreDeleteHookFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, errors.Wrap(err, "failed to run the DockerMachine pre-delete hook")
+	}
+
 	// delete the machine
 	if err := externalMachine.Delete(ctx); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to delete DockerMachine")
 	}
 
+	// delete the volumes, now that they are not used by the machine container anymore
+	if err := externalMachine.DeleteVolumes(ctx, dockerMachine.Spec.Volumes); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to delete DockerMachine volumes")
+	}
+
 	// if the deleted machine is a control-plane node, remove it from the load balancer configuration;
-	if util.IsControlPlaneMachine(machine) {
+	if util.IsControlPlaneMachine(machine) && externalLoadBalancer != nil {
 		if err := externalLoadBalancer.UpdateConfiguration(ctx); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to update DockerCluster.loadbalancer configuration")
 		}
 	}
 
+	r.imagePullBackoff.Forget(dockerMachine)
+
 	// Machine is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(dockerMachine, infrav1.MachineFinalizer)
 	return ctrl.Result{}, nil
@@ -421,21 +521,27 @@ func (r *DockerMachineReconciler) DockerClusterToDockerMachines(o client.Object)
 	return result
 }
 
-func (r *DockerMachineReconciler) getBootstrapData(ctx context.Context, machine *clusterv1.Machine) (string, error) {
+func (r *DockerMachineReconciler) getBootstrapData(ctx context.Context, machine *clusterv1.Machine) (string, docker.BootstrapDataFormat, error) {
 	if machine.Spec.Bootstrap.DataSecretName == nil {
-		return "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
+		return "", "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
 	}
 
 	s := &corev1.Secret{}
 	key := client.ObjectKey{Namespace: machine.GetNamespace(), Name: *machine.Spec.Bootstrap.DataSecretName}
 	if err := r.Client.Get(ctx, key, s); err != nil {
-		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for DockerMachine %s/%s", machine.GetNamespace(), machine.GetName())
+		return "", "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for DockerMachine %s/%s", machine.GetNamespace(), machine.GetName())
 	}
 
 	value, ok := s.Data["value"]
 	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+		return "", "", errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+
+	// If the bootstrap data secret does not define the format, the bootstrap data is assumed to be in cloud-config format.
+	format := docker.CloudConfig
+	if f, ok := s.Data["format"]; ok && len(f) > 0 {
+		format = docker.BootstrapDataFormat(f)
 	}
 
-	return base64.StdEncoding.EncodeToString(value), nil
+	return base64.StdEncoding.EncodeToString(value), format, nil
 }
@@ -17,14 +17,20 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -65,6 +71,189 @@ func TestDockerMachineReconciler_DockerClusterToDockerMachines(t *testing.T) {
 	g.Expect(machineNames).To(ConsistOf("my-machine-0", "my-machine-1"))
 }
 
+func TestDockerMachineReconciler_ReconcileDeleteWaitsForPreTerminateHooks(t *testing.T) {
+	g := NewWithT(t)
+
+	hookedDockerMachine := newDockerMachine("my-docker-machine-3", "my-machine-3")
+	hookedMachine := newMachine(clusterName, "my-machine-3", hookedDockerMachine)
+	hookedMachine.Annotations = map[string]string{
+		clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/test": "",
+	}
+
+	c := fake.NewClientBuilder().WithObjects(hookedMachine, hookedDockerMachine).Build()
+	r := DockerMachineReconciler{
+		Client: c,
+	}
+
+	// The container and the load balancer are not touched while waiting for the hooks, so they can be nil.
+	_, err := r.reconcileDelete(context.Background(), hookedMachine, hookedDockerMachine, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hookedDockerMachine.Finalizers).To(ContainElement(infrav1.MachineFinalizer))
+	g.Expect(conditions.GetReason(hookedDockerMachine, infrav1.ContainerProvisionedCondition)).To(Equal(clusterv1.WaitingExternalHookReason))
+}
+
+func TestDockerMachineReconciler_ReconcileNormalRecordsFailureDomain(t *testing.T) {
+	g := NewWithT(t)
+
+	fdDockerMachine := newDockerMachine("my-docker-machine-4", "my-machine-4")
+	fdDockerMachine.Spec.ProviderID = pointer.String("docker:////my-cluster-my-machine-4")
+	fdMachine := newMachine(clusterName, "my-machine-4", fdDockerMachine)
+	fdMachine.Spec.FailureDomain = pointer.String("fd1")
+
+	r := DockerMachineReconciler{
+		Client: fake.NewClientBuilder().WithObjects(fdMachine, fdDockerMachine).Build(),
+	}
+
+	// The machine is already provisioned, so the container is not touched.
+	_, err := r.reconcileNormal(context.Background(), cluster, fdMachine, fdDockerMachine, &docker.Machine{}, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fdDockerMachine.Status.FailureDomain).To(Equal(pointer.String("fd1")))
+	g.Expect(fdDockerMachine.Status.Ready).To(BeTrue())
+}
+
+func TestDockerMachineReconciler_ReconcileNormalRetriesImagePull(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeRuntime := &container.FakeRuntime{
+		Errors: map[string]error{"PullContainerImageIfNotExists": errors.New("registry unavailable")},
+	}
+	container.SetFakeRuntime(fakeRuntime)
+	defer container.SetFakeRuntime(nil)
+
+	pullDockerMachine := newDockerMachine("my-docker-machine-5", "my-machine-5")
+	pullMachine := newMachine(clusterName, "my-machine-5", pullDockerMachine)
+	pullMachine.Spec.Version = pointer.String("v1.22.0")
+
+	externalMachine, err := docker.NewMachine(cluster, pullMachine.Name, "", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(externalMachine.Exists()).To(BeFalse())
+
+	r := DockerMachineReconciler{
+		Client:        fake.NewClientBuilder().WithObjects(pullMachine, pullDockerMachine).Build(),
+		PrePullImages: true,
+	}
+
+	// Image pull failures are reported in conditions, and the machine is requeued with an increasing interval.
+	res, err := r.reconcileNormal(context.Background(), cluster, pullMachine, pullDockerMachine, externalMachine, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(imagePullRetryInterval))
+	g.Expect(conditions.GetReason(pullDockerMachine, infrav1.ContainerProvisionedCondition)).To(Equal(infrav1.ImagePullFailedReason))
+	severity := clusterv1.ConditionSeverityWarning
+	g.Expect(conditions.GetSeverity(pullDockerMachine, infrav1.ContainerProvisionedCondition)).To(Equal(&severity))
+
+	res, err = r.reconcileNormal(context.Background(), cluster, pullMachine, pullDockerMachine, externalMachine, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(BeNumerically(">", imagePullRetryInterval))
+	g.Expect(res.RequeueAfter).To(BeNumerically("<=", maxImagePullRetryInterval))
+
+	// Once the image is pulled, the backoff is reset and the machine waits for the bootstrap data.
+	fakeRuntime.Errors = nil
+	res, err = r.reconcileNormal(context.Background(), cluster, pullMachine, pullDockerMachine, externalMachine, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.IsZero()).To(BeTrue())
+	g.Expect(r.imagePullBackoff.Requeues(pullDockerMachine)).To(Equal(0))
+	g.Expect(conditions.GetReason(pullDockerMachine, infrav1.ContainerProvisionedCondition)).To(Equal(clusterv1.WaitingForControlPlaneAvailableReason))
+	g.Expect(fakeRuntime.Calls()).To(ContainElement("PullContainerImageIfNotExists " + docker.NodeImage(pullMachine.Spec.Version)))
+}
+
+func TestDockerMachineReconciler_ReconcileNormalSnapshot(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeRuntime := &container.FakeRuntime{
+		Containers: []container.Container{{Name: "my-cluster-my-machine-6", Image: "kindest/node:v1.22.0", Status: "running"}},
+		Errors:     map[string]error{"CommitContainer": errors.New("disk full")},
+	}
+	container.SetFakeRuntime(fakeRuntime)
+	defer container.SetFakeRuntime(nil)
+
+	snapshotDockerMachine := newDockerMachine("my-docker-machine-6", "my-machine-6")
+	snapshotDockerMachine.Annotations = map[string]string{infrav1.SnapshotAnnotation: "my-snapshot:v1"}
+	snapshotDockerMachine.Spec.ProviderID = pointer.String("docker:////my-cluster-my-machine-6")
+	snapshotMachine := newMachine(clusterName, "my-machine-6", snapshotDockerMachine)
+
+	externalMachine, err := docker.NewMachine(cluster, snapshotMachine.Name, "", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(externalMachine.Exists()).To(BeTrue())
+
+	r := DockerMachineReconciler{
+		Client: fake.NewClientBuilder().WithObjects(snapshotMachine, snapshotDockerMachine).Build(),
+	}
+
+	// If committing the container fails, the annotation is preserved so the snapshot is retried.
+	_, err = r.reconcileNormal(context.Background(), cluster, snapshotMachine, snapshotDockerMachine, externalMachine, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(snapshotDockerMachine.Annotations).To(HaveKey(infrav1.SnapshotAnnotation))
+
+	// If the container runtime does not support committing containers, the snapshot fails.
+	container.SetFakeRuntime(struct{ container.Runtime }{fakeRuntime})
+	_, err = r.reconcileNormal(context.Background(), cluster, snapshotMachine, snapshotDockerMachine, externalMachine, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(snapshotDockerMachine.Annotations).To(HaveKey(infrav1.SnapshotAnnotation))
+
+	// Once the container is committed, the annotation is removed.
+	container.SetFakeRuntime(fakeRuntime)
+	fakeRuntime.Errors = nil
+	_, err = r.reconcileNormal(context.Background(), cluster, snapshotMachine, snapshotDockerMachine, externalMachine, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fakeRuntime.Calls()).To(ContainElement("CommitContainer my-cluster-my-machine-6 my-snapshot:v1"))
+	g.Expect(snapshotDockerMachine.Annotations).ToNot(HaveKey(infrav1.SnapshotAnnotation))
+	g.Expect(snapshotDockerMachine.Status.Ready).To(BeTrue())
+}
+
+func TestDockerMachineReconciler_ReconcileNormalRestore(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeRuntime := &container.FakeRuntime{
+		Containers: []container.Container{{Name: "my-cluster-my-machine-7", Image: "kindest/node:v1.22.0", Status: "running"}},
+		Errors:     map[string]error{"DeleteContainer": errors.New("container is locked")},
+	}
+	container.SetFakeRuntime(fakeRuntime)
+	defer container.SetFakeRuntime(nil)
+
+	restoreDockerMachine := newDockerMachine("my-docker-machine-7", "my-machine-7")
+	restoreDockerMachine.Annotations = map[string]string{infrav1.RestoreAnnotation: "my-snapshot:v1"}
+	restoreDockerMachine.Spec.ProviderID = pointer.String("docker:////my-cluster-my-machine-7")
+	restoreDockerMachine.Spec.CustomImage = "my-image:v1"
+	restoreDockerMachine.Spec.Bootstrapped = true
+	restoreDockerMachine.Status.Ready = true
+	restoreMachine := newMachine(clusterName, "my-machine-7", restoreDockerMachine)
+	spec := restoreDockerMachine.Spec.DeepCopy()
+
+	externalMachine, err := docker.NewMachine(cluster, restoreMachine.Name, restoreDockerMachine.Spec.CustomImage, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(externalMachine.Exists()).To(BeTrue())
+
+	r := DockerMachineReconciler{
+		Client: fake.NewClientBuilder().WithObjects(restoreMachine, restoreDockerMachine).Build(),
+	}
+
+	// If deleting the container fails, the annotation is preserved so the restore is retried.
+	_, err = r.reconcileNormal(context.Background(), cluster, restoreMachine, restoreDockerMachine, externalMachine, nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(restoreDockerMachine.Annotations).To(HaveKey(infrav1.RestoreAnnotation))
+	g.Expect(restoreDockerMachine.Status.Restoring).To(BeFalse())
+	g.Expect(restoreDockerMachine.Status.Ready).To(BeTrue())
+
+	// Once the container is deleted, the restore is tracked in status without changing the spec, and the
+	// machine goes through provisioning again, waiting for the bootstrap data.
+	fakeRuntime.Errors = nil
+	_, err = r.reconcileNormal(context.Background(), cluster, restoreMachine, restoreDockerMachine, externalMachine, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(fakeRuntime.Calls()).To(ContainElement("DeleteContainer my-cluster-my-machine-7"))
+	g.Expect(restoreDockerMachine.Annotations).ToNot(HaveKey(infrav1.RestoreAnnotation))
+	g.Expect(restoreDockerMachine.Spec).To(Equal(*spec))
+	g.Expect(restoreDockerMachine.Status.RestoredImage).To(Equal("my-snapshot:v1"))
+	g.Expect(restoreDockerMachine.Status.Restoring).To(BeTrue())
+	g.Expect(restoreDockerMachine.Status.Ready).To(BeFalse())
+	g.Expect(conditions.IsFalse(restoreDockerMachine, infrav1.ContainerProvisionedCondition)).To(BeTrue())
+
+	// While restoring, the machine is not considered provisioned even if the providerID is set.
+	_, err = r.reconcileNormal(context.Background(), cluster, restoreMachine, restoreDockerMachine, externalMachine, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(restoreDockerMachine.Status.Restoring).To(BeTrue())
+	g.Expect(restoreDockerMachine.Status.Ready).To(BeFalse())
+}
+
 func newCluster(clusterName string, dockerCluster *infrav1.DockerCluster) *clusterv1.Cluster {
 	cluster := &clusterv1.Cluster{
 		TypeMeta: metav1.TypeMeta{},
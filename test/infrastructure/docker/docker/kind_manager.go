@@ -37,6 +37,24 @@ const DefaultNetwork = "kind"
 
 type Manager struct{}
 
+// NodeNetwork defines the networking of a node container, except port mappings.
+type NodeNetwork struct {
+	// IPv4Address and IPv6Address are the static addresses of the node on the default network; if empty,
+	// the addresses are assigned by docker.
+	IPv4Address string
+	IPv6Address string
+	// AdditionalNetworks are the names of other existing networks the node is attached to.
+	AdditionalNetworks []string
+}
+
+// NodeResources defines the resource constraints of a node container.
+type NodeResources struct {
+	// MilliCPUs is the maximum amount of CPU the node can use, in thousandths of a CPU; 0 means no limit.
+	MilliCPUs int64
+	// Memory is the maximum amount of memory the node can use, in bytes; 0 means no limit.
+	Memory int64
+}
+
 type nodeCreateOpts struct {
 	Name         string
 	Image        string
@@ -44,11 +62,13 @@ type nodeCreateOpts struct {
 	Role         string
 	Mounts       []v1alpha4.Mount
 	PortMappings []v1alpha4.PortMapping
+	Network      NodeNetwork
+	Resources    NodeResources
 	Labels       map[string]string
 	IPFamily     clusterv1.ClusterIPFamily
 }
 
-func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error) {
+func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, network NodeNetwork, resources NodeResources, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error) {
 	// gets a random host port for the API server
 	if port == 0 {
 		p, err := getPort()
@@ -71,7 +91,10 @@ func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, image, clust
 		ClusterName:  clusterName,
 		Role:         constants.ControlPlaneNodeRoleValue,
 		PortMappings: portMappingsWithAPIServer,
+		Network:      network,
+		Resources:    resources,
 		Mounts:       mounts,
+		Labels:       labels,
 		IPFamily:     ipFamily,
 	}
 	node, err := createNode(ctx, createOpts)
@@ -82,13 +105,15 @@ func (m *Manager) CreateControlPlaneNode(ctx context.Context, name, image, clust
 	return node, nil
 }
 
-func (m *Manager) CreateWorkerNode(ctx context.Context, name, image, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error) {
+func (m *Manager) CreateWorkerNode(ctx context.Context, name, image, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, network NodeNetwork, resources NodeResources, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error) {
 	createOpts := &nodeCreateOpts{
 		Name:         name,
 		Image:        image,
 		ClusterName:  clusterName,
 		Role:         constants.WorkerNodeRoleValue,
 		PortMappings: portMappings,
+		Network:      network,
+		Resources:    resources,
 		Mounts:       mounts,
 		Labels:       labels,
 		IPFamily:     ipFamily,
@@ -96,7 +121,7 @@ func (m *Manager) CreateWorkerNode(ctx context.Context, name, image, clusterName
 	return createNode(ctx, createOpts)
 }
 
-func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error) {
+func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port, containerPort int32, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error) {
 	// gets a random host port for control-plane load balancer
 	// gets a random host port for the API server
 	if port == 0 {
@@ -106,12 +131,15 @@ func (m *Manager) CreateExternalLoadBalancerNode(ctx context.Context, name, imag
 		}
 		port = p
 	}
+	if containerPort == 0 {
+		containerPort = ControlPlanePort
+	}
 
 	// load balancer port mapping
 	portMappings := []v1alpha4.PortMapping{{
 		ListenAddress: listenAddress,
 		HostPort:      port,
-		ContainerPort: ControlPlanePort,
+		ContainerPort: containerPort,
 		Protocol:      v1alpha4.PortMappingProtocolTCP,
 	}}
 	createOpts := &nodeCreateOpts{
@@ -150,10 +178,15 @@ func createNode(ctx context.Context, opts *nodeCreateOpts) (*types.Node, error)
 		// filesystem, which is not only better for performance, but allows
 		// running kind in kind for "party tricks"
 		// (please don't depend on doing this though!)
-		Volumes:      map[string]string{"/var": ""},
-		Mounts:       generateMountInfo(opts.Mounts),
-		PortMappings: generatePortMappings(opts.PortMappings),
-		Network:      DefaultNetwork,
+		Volumes:            map[string]string{"/var": ""},
+		Mounts:             generateMountInfo(opts.Mounts),
+		PortMappings:       generatePortMappings(opts.PortMappings),
+		Network:            DefaultNetwork,
+		IPv4Address:        opts.Network.IPv4Address,
+		IPv6Address:        opts.Network.IPv6Address,
+		AdditionalNetworks: opts.Network.AdditionalNetworks,
+		MilliCPUs:          opts.Resources.MilliCPUs,
+		Memory:             opts.Resources.Memory,
 		Tmpfs: map[string]string{
 			"/tmp": "", // various things depend on working /tmp
 			"/run": "", // systemd wants a writable /run
@@ -162,11 +195,17 @@ func createNode(ctx context.Context, opts *nodeCreateOpts) (*types.Node, error)
 	}
 	log.V(6).Info("Container run options: %+v", runOptions)
 
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to container runtime: %v", err)
 	}
 
+	// Make sure the network exists, with IPv6 enabled if required by the cluster.
+	ipv6 := opts.IPFamily == clusterv1.IPv6IPFamily || opts.IPFamily == clusterv1.DualStackIPFamily
+	if err := containerRuntime.EnsureNetwork(ctx, DefaultNetwork, ipv6); err != nil {
+		return nil, err
+	}
+
 	err = containerRuntime.RunContainer(ctx, runOptions, nil)
 	if err != nil {
 		return nil, err
@@ -22,30 +22,40 @@ import (
 	"net"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker/types"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/third_party/forked/loadbalancer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/kind/pkg/cluster/constants"
 )
 
 type lbCreator interface {
-	CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error)
+	CreateExternalLoadBalancerNode(ctx context.Context, name, image, clusterName, listenAddress string, port, containerPort int32, ipFamily clusterv1.ClusterIPFamily) (*types.Node, error)
 }
 
+// customHAProxyConfigTemplateKey is the key of the ConfigMap referenced by
+// DockerLoadBalancer.CustomHAProxyConfigTemplateRef holding the HAProxy configuration template.
+const customHAProxyConfigTemplateKey = "value"
+
 // LoadBalancer manages the load balancer for a specific docker cluster.
 type LoadBalancer struct {
-	name      string
-	image     string
-	container *types.Node
-	ipFamily  clusterv1.ClusterIPFamily
-	lbCreator lbCreator
+	name              string
+	namespace         string
+	image             string
+	port              int32
+	configTemplateRef *corev1.LocalObjectReference
+	container         *types.Node
+	ipFamily          clusterv1.ClusterIPFamily
+	lbCreator         lbCreator
+	client            client.Client
 }
 
 // NewLoadBalancer returns a new helper for managing a docker loadbalancer with a given name.
-func NewLoadBalancer(cluster *clusterv1.Cluster, dockerCluster *infrav1.DockerCluster) (*LoadBalancer, error) {
+func NewLoadBalancer(c client.Client, cluster *clusterv1.Cluster, dockerCluster *infrav1.DockerCluster) (*LoadBalancer, error) {
 	if cluster.Name == "" {
 		return nil, errors.New("create load balancer: cluster name is empty")
 	}
@@ -69,13 +79,23 @@ func NewLoadBalancer(cluster *clusterv1.Cluster, dockerCluster *infrav1.DockerCl
 
 	image := getLoadBalancerImage(dockerCluster)
 
-	return &LoadBalancer{
+	lb := &LoadBalancer{
 		name:      cluster.Name,
+		namespace: cluster.Namespace,
 		image:     image,
+		port:      ControlPlanePort,
 		container: container,
 		ipFamily:  ipFamily,
 		lbCreator: &Manager{},
-	}, nil
+		client:    c,
+	}
+	if dockerCluster != nil {
+		if dockerCluster.Spec.LoadBalancer.Port != 0 {
+			lb.port = dockerCluster.Spec.LoadBalancer.Port
+		}
+		lb.configTemplateRef = dockerCluster.Spec.LoadBalancer.CustomHAProxyConfigTemplateRef
+	}
+	return lb, nil
 }
 
 // getLoadBalancerImage will return the image (e.g. "kindest/haproxy:2.1.1-alpine") to use for
@@ -98,6 +118,11 @@ func getLoadBalancerImage(dockerCluster *infrav1.DockerCluster) string {
 	return fmt.Sprintf("%s/%s:%s", imageRepo, image, imageTag)
 }
 
+// Port returns the port the load balancer listens on for the Kubernetes API server.
+func (s *LoadBalancer) Port() int32 {
+	return s.port
+}
+
 // ContainerName is the name of the docker container with the load balancer.
 func (s *LoadBalancer) containerName() string {
 	return fmt.Sprintf("%s-lb", s.name)
@@ -123,6 +148,7 @@ func (s *LoadBalancer) Create(ctx context.Context) error {
 			s.name,
 			listenAddr,
 			0,
+			s.port,
 			s.ipFamily,
 		)
 		if err != nil {
@@ -133,7 +159,8 @@ func (s *LoadBalancer) Create(ctx context.Context) error {
 	return nil
 }
 
-// UpdateConfiguration updates the external load balancer configuration with new control plane nodes.
+// UpdateConfiguration updates the external load balancer configuration with the current control plane nodes
+// and configuration template; the load balancer is reloaded only if the configuration changed.
 func (s *LoadBalancer) UpdateConfiguration(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -158,21 +185,32 @@ func (s *LoadBalancer) UpdateConfiguration(ctx context.Context) error {
 			return errors.Wrapf(err, "failed to get IP for container %s", n.String())
 		}
 		if s.ipFamily == clusterv1.IPv6IPFamily {
-			backendServers[n.String()] = net.JoinHostPort(controlPlaneIPv6, "6443")
+			backendServers[n.String()] = net.JoinHostPort(controlPlaneIPv6, fmt.Sprintf("%d", KubeadmContainerPort))
 		} else {
-			backendServers[n.String()] = net.JoinHostPort(controlPlaneIPv4, "6443")
+			backendServers[n.String()] = net.JoinHostPort(controlPlaneIPv4, fmt.Sprintf("%d", KubeadmContainerPort))
 		}
 	}
 
+	configTemplate, err := s.configTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
 	loadBalancerConfig, err := loadbalancer.Config(&loadbalancer.ConfigData{
-		ControlPlanePort: 6443,
+		ControlPlanePort: int(s.port),
 		BackendServers:   backendServers,
-		IPv6:             s.ipFamily == clusterv1.IPv6IPFamily,
-	})
+		IPv6:             s.ipFamily == clusterv1.IPv6IPFamily || s.ipFamily == clusterv1.DualStackIPFamily,
+	}, configTemplate)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	// Skip reloading the load balancer if the configuration did not change; errors reading the current
+	// configuration are ignored, given that the configuration is going to be overwritten.
+	if currentConfig, err := s.container.ReadFile(ctx, loadbalancer.ConfigPath); err == nil && currentConfig == loadBalancerConfig {
+		return nil
+	}
+
 	log.Info("Updating load balancer configuration")
 	if err := s.container.WriteFile(ctx, loadbalancer.ConfigPath, loadBalancerConfig); err != nil {
 		return errors.WithStack(err)
@@ -181,6 +219,28 @@ func (s *LoadBalancer) UpdateConfiguration(ctx context.Context) error {
 	return errors.WithStack(s.container.Kill(ctx, "SIGHUP"))
 }
 
+// configTemplate returns the HAProxy configuration template from the ConfigMap referenced by the
+// DockerCluster, if any, or an empty string for using the default template.
+func (s *LoadBalancer) configTemplate(ctx context.Context) (string, error) {
+	if s.configTemplateRef == nil {
+		return "", nil
+	}
+	if s.client == nil {
+		return "", errors.New("unable to read the load balancer configuration template: client is not set")
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: s.namespace, Name: s.configTemplateRef.Name}
+	if err := s.client.Get(ctx, key, configMap); err != nil {
+		return "", errors.Wrapf(err, "failed to get the load balancer configuration template from ConfigMap %s", key)
+	}
+	configTemplate, ok := configMap.Data[customHAProxyConfigTemplateKey]
+	if !ok {
+		return "", errors.Errorf("ConfigMap %s does not contain the %q key with the load balancer configuration template", key, customHAProxyConfigTemplateKey)
+	}
+	return configTemplate, nil
+}
+
 // IP returns the load balancer IP address.
 func (s *LoadBalancer) IP(ctx context.Context) (string, error) {
 	lbIPv4, lbIPv6, err := s.container.IP(ctx)
@@ -38,17 +38,36 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/cloudinit"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker/types"
-	clusterapicontainer "sigs.k8s.io/cluster-api/util/container"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/ignition"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/script"
 )
 
 const (
 	defaultImageName = "kindest/node"
 	defaultImageTag  = "v1.22.0"
+
+	// defaultPreDeleteHookTimeout is the timeout of the pre-delete hook command, if not set in the DockerMachine.
+	defaultPreDeleteHookTimeout = 1 * time.Minute
+)
+
+// BootstrapDataFormat defines the format of the bootstrap data, as defined by the format key of the bootstrap data secret.
+type BootstrapDataFormat string
+
+const (
+	// CloudConfig identifies bootstrap data in cloud-config format; this is the default
+	// when the bootstrap data secret does not define the format.
+	CloudConfig BootstrapDataFormat = "cloud-config"
+
+	// Ignition identifies bootstrap data in Ignition format.
+	Ignition BootstrapDataFormat = "ignition"
+
+	// Script identifies bootstrap data defined as a plain shell script.
+	Script BootstrapDataFormat = "script"
 )
 
 type nodeCreator interface {
-	CreateControlPlaneNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (node *types.Node, err error)
-	CreateWorkerNode(ctx context.Context, name, image, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (node *types.Node, err error)
+	CreateControlPlaneNode(ctx context.Context, name, image, clusterName, listenAddress string, port int32, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, network NodeNetwork, resources NodeResources, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (node *types.Node, err error)
+	CreateWorkerNode(ctx context.Context, name, image, clusterName string, mounts []v1alpha4.Mount, portMappings []v1alpha4.PortMapping, network NodeNetwork, resources NodeResources, labels map[string]string, ipFamily clusterv1.ClusterIPFamily) (node *types.Node, err error)
 }
 
 // Machine implement a service for managing the docker containers hosting a kubernetes nodes.
@@ -182,20 +201,30 @@ func (m *Machine) ProviderID() string {
 	return fmt.Sprintf("docker:////%s", m.ContainerName())
 }
 
-func (m *Machine) Address(ctx context.Context) (string, error) {
+// Addresses returns the IP addresses of the machine; dual-stack machines have both an IPv4 and an IPv6 address.
+func (m *Machine) Addresses(ctx context.Context) ([]string, error) {
 	ipv4, ipv6, err := m.container.IP(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return addressesForIPFamily(m.ipFamily, ipv4, ipv6), nil
+}
 
-	if m.ipFamily == clusterv1.IPv6IPFamily {
-		return ipv6, nil
+// addressesForIPFamily returns the addresses of a machine in a cluster with the given IP family.
+func addressesForIPFamily(ipFamily clusterv1.ClusterIPFamily, ipv4, ipv6 string) []string {
+	switch ipFamily {
+	case clusterv1.IPv6IPFamily:
+		return []string{ipv6}
+	case clusterv1.DualStackIPFamily:
+		return []string{ipv4, ipv6}
+	default:
+		return []string{ipv4}
 	}
-	return ipv4, nil
 }
 
 // Create creates a docker container hosting a Kubernetes node.
-func (m *Machine) Create(ctx context.Context, role string, version *string, mounts []infrav1.Mount) error {
+// If failureDomain is set, it is recorded as a label on the container.
+func (m *Machine) Create(ctx context.Context, role string, version *string, failureDomain *string, mounts []infrav1.Mount, volumes []infrav1.Volume, network infrav1.DockerMachineNetwork, resources infrav1.DockerMachineResources) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Create if not exists.
@@ -217,9 +246,11 @@ func (m *Machine) Create(ctx context.Context, role string, version *string, moun
 				m.cluster,
 				"127.0.0.1",
 				0,
-				kindMounts(mounts),
-				nil,
-				m.labels,
+				m.kindMounts(mounts, volumes),
+				kindPortMappings(network.PortMappings),
+				nodeNetwork(network),
+				nodeResources(resources),
+				m.containerLabels(failureDomain),
 				m.ipFamily,
 			)
 			if err != nil {
@@ -232,9 +263,11 @@ func (m *Machine) Create(ctx context.Context, role string, version *string, moun
 				m.ContainerName(),
 				machineImage,
 				m.cluster,
-				kindMounts(mounts),
-				nil,
-				m.labels,
+				m.kindMounts(mounts, volumes),
+				kindPortMappings(network.PortMappings),
+				nodeNetwork(network),
+				nodeResources(resources),
+				m.containerLabels(failureDomain),
 				m.ipFamily,
 			)
 			if err != nil {
@@ -259,23 +292,94 @@ func (m *Machine) Create(ctx context.Context, role string, version *string, moun
 	return nil
 }
 
-func kindMounts(mounts []infrav1.Mount) []v1alpha4.Mount {
-	if len(mounts) == 0 {
+// containerLabels returns the labels for the machine container.
+func (m *Machine) containerLabels(failureDomain *string) map[string]string {
+	if failureDomain == nil || *failureDomain == "" {
+		return m.labels
+	}
+
+	labels := map[string]string{failureDomainLabelKey: *failureDomain}
+	for key, val := range m.labels {
+		labels[key] = val
+	}
+	return labels
+}
+
+// kindPortMappings returns the port mappings for the machine container.
+func kindPortMappings(portMappings []infrav1.PortMapping) []v1alpha4.PortMapping {
+	if len(portMappings) == 0 {
+		return nil
+	}
+
+	ret := make([]v1alpha4.PortMapping, 0, len(portMappings))
+	for _, pm := range portMappings {
+		protocol := v1alpha4.PortMappingProtocolTCP
+		if pm.Protocol != "" {
+			protocol = v1alpha4.PortMappingProtocol(pm.Protocol)
+		}
+		ret = append(ret, v1alpha4.PortMapping{
+			ContainerPort: pm.ContainerPort,
+			HostPort:      pm.HostPort,
+			ListenAddress: pm.ListenAddress,
+			Protocol:      protocol,
+		})
+	}
+	return ret
+}
+
+// nodeNetwork returns the network settings for the machine container.
+func nodeNetwork(network infrav1.DockerMachineNetwork) NodeNetwork {
+	return NodeNetwork{
+		IPv4Address:        network.IPv4Address,
+		IPv6Address:        network.IPv6Address,
+		AdditionalNetworks: network.AdditionalNetworks,
+	}
+}
+
+// nodeResources returns the resource constraints of the machine container.
+func nodeResources(resources infrav1.DockerMachineResources) NodeResources {
+	ret := NodeResources{}
+	if resources.CPU != nil {
+		ret.MilliCPUs = resources.CPU.MilliValue()
+	}
+	if resources.Memory != nil {
+		ret.Memory = resources.Memory.Value()
+	}
+	return ret
+}
+
+// kindMounts returns the mounts for the machine container; named volumes are mounted using the
+// volume name as a source, and docker creates them if they do not exist.
+func (m *Machine) kindMounts(mounts []infrav1.Mount, volumes []infrav1.Volume) []v1alpha4.Mount {
+	if len(mounts) == 0 && len(volumes) == 0 {
 		return nil
 	}
 
-	ret := make([]v1alpha4.Mount, 0, len(mounts))
-	for _, m := range mounts {
+	ret := make([]v1alpha4.Mount, 0, len(mounts)+len(volumes))
+	for _, mount := range mounts {
+		ret = append(ret, v1alpha4.Mount{
+			ContainerPath: mount.ContainerPath,
+			HostPath:      mount.HostPath,
+			Readonly:      mount.Readonly,
+			Propagation:   v1alpha4.MountPropagationNone,
+		})
+	}
+	for _, volume := range volumes {
 		ret = append(ret, v1alpha4.Mount{
-			ContainerPath: m.ContainerPath,
-			HostPath:      m.HostPath,
-			Readonly:      m.Readonly,
+			ContainerPath: volume.ContainerPath,
+			HostPath:      m.volumeName(volume),
+			Readonly:      volume.Readonly,
 			Propagation:   v1alpha4.MountPropagationNone,
 		})
 	}
 	return ret
 }
 
+// volumeName returns the name of the docker volume for a machine volume.
+func (m *Machine) volumeName(volume infrav1.Volume) string {
+	return fmt.Sprintf("%s-%s", m.ContainerName(), volume.Name)
+}
+
 func (m *Machine) PreloadLoadImages(ctx context.Context, images []string) error {
 	// Save the image into a tar
 	dir, err := os.MkdirTemp("", "image-tar")
@@ -284,7 +388,7 @@ func (m *Machine) PreloadLoadImages(ctx context.Context, images []string) error
 	}
 	defer os.RemoveAll(dir)
 
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to container runtime")
 	}
@@ -313,22 +417,47 @@ func (m *Machine) PreloadLoadImages(ctx context.Context, images []string) error
 }
 
 // ExecBootstrap runs bootstrap on a node, this is generally `kubeadm <init|join>`.
-func (m *Machine) ExecBootstrap(ctx context.Context, data string) error {
+func (m *Machine) ExecBootstrap(ctx context.Context, data string, format BootstrapDataFormat) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	if m.container == nil {
 		return errors.New("unable to set ExecBootstrap. the container hosting this machine does not exists")
 	}
 
-	cloudConfig, err := base64.StdEncoding.DecodeString(data)
+	bootstrapData, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
 		return errors.Wrap(err, "failed to decode machine's bootstrap data")
 	}
 
-	commands, err := cloudinit.Commands(cloudConfig)
-	if err != nil {
-		log.Info("cloud config failed to parse", "bootstrap data", data)
-		return errors.Wrap(err, "failed to join a control plane node with kubeadm")
+	var commands []cloudinit.Cmd
+	switch format {
+	case CloudConfig, "":
+		commands, err = cloudinit.Commands(bootstrapData)
+		if err != nil {
+			log.Info("cloud config failed to parse", "bootstrap data", data)
+			return errors.Wrap(err, "failed to join a control plane node with kubeadm")
+		}
+	case Ignition:
+		commands, err = ignition.Commands(bootstrapData)
+		if err != nil {
+			log.Info("ignition config failed to parse", "bootstrap data", data)
+			return errors.Wrap(err, "failed to join a control plane node with kubeadm")
+		}
+	case Script:
+		commands, err = script.Commands(bootstrapData)
+		if err != nil {
+			log.Info("bootstrap script is not valid", "bootstrap data", data)
+			return errors.Wrap(err, "failed to join a control plane node with kubeadm")
+		}
+	default:
+		return errors.Errorf("unsupported bootstrap data format %q", format)
+	}
+
+	// kubelet can't detect the node IPs of dual-stack nodes, so they are injected before running kubeadm.
+	if m.ipFamily == clusterv1.DualStackIPFamily {
+		if err := m.setDualStackNodeIP(ctx); err != nil {
+			return err
+		}
 	}
 
 	var outErr bytes.Buffer
@@ -351,6 +480,31 @@ func (m *Machine) ExecBootstrap(ctx context.Context, data string) error {
 	return nil
 }
 
+// setDualStackNodeIP configures kubelet to use both the IPv4 and the IPv6 address of the machine as node IPs.
+// NOTE: KUBELET_EXTRA_ARGS from /etc/default/kubelet take precedence over the flags set by kubeadm.
+func (m *Machine) setDualStackNodeIP(ctx context.Context) error {
+	ipv4, ipv6, err := m.container.IP(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the IPs of the machine")
+	}
+	kubeletArgs, err := dualStackKubeletArgs(ipv4, ipv6)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure the node IPs of machine %s", m.ContainerName())
+	}
+	if err := m.container.WriteFile(ctx, "/etc/default/kubelet", kubeletArgs); err != nil {
+		return errors.Wrap(err, "failed to configure the node IPs for kubelet")
+	}
+	return nil
+}
+
+// dualStackKubeletArgs returns the content of /etc/default/kubelet setting both the IPv4 and the IPv6 address as node IPs.
+func dualStackKubeletArgs(ipv4, ipv6 string) (string, error) {
+	if ipv4 == "" || ipv6 == "" {
+		return "", errors.Errorf("both an IPv4 and an IPv6 address are required for a dual-stack cluster, got %q and %q", ipv4, ipv6)
+	}
+	return fmt.Sprintf("KUBELET_EXTRA_ARGS=--node-ip=%s,%s\n", ipv4, ipv6), nil
+}
+
 // CheckForBootstrapSuccess checks if bootstrap was successful by checking for existence of the sentinel file.
 func (m *Machine) CheckForBootstrapSuccess(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx)
@@ -431,6 +585,48 @@ func (m *Machine) getKubectlNode() (*types.Node, error) {
 	return kubectlNodes[0], nil
 }
 
+// ExecPreDeleteHook runs the pre-delete hook on the container hosting the machine, if it exists; first the hook command
+// is run inside the container, e.g. `kubeadm reset`, then the container is stopped gracefully, if required.
+func (m *Machine) ExecPreDeleteHook(ctx context.Context, hook *infrav1.DockerMachinePreDeleteHook) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if hook == nil || m.container == nil {
+		return nil
+	}
+
+	// The command can only be run while the container is running, e.g. it is skipped if the container
+	// has already been stopped by a previous attempt to delete the machine.
+	if len(hook.Command) > 0 && m.container.IsRunning() {
+		timeout := defaultPreDeleteHookTimeout
+		if hook.Timeout != nil {
+			timeout = hook.Timeout.Duration
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		log.Info("Running pre-delete hook", "command", hook.Command)
+		var outErr bytes.Buffer
+		var outStd bytes.Buffer
+		cmd := m.container.Commander.Command(hook.Command[0], hook.Command[1:]...)
+		cmd.SetStderr(&outErr)
+		cmd.SetStdout(&outStd)
+		if err := cmd.Run(timeoutCtx); err != nil {
+			log.Info("Failed running pre-delete hook", "command", hook.Command, "stdout", outStd.String(), "stderr", outErr.String(), "ignoreFailure", hook.IgnoreFailure)
+			if !hook.IgnoreFailure {
+				return errors.Wrap(errors.WithStack(err), "failed to run pre-delete hook")
+			}
+		}
+	}
+
+	if hook.StopTimeout != nil {
+		log.Info("Stopping machine container", "timeout", hook.StopTimeout.Duration.String())
+		if err := m.container.Stop(ctx, hook.StopTimeout.Duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Delete deletes a docker container hosting a Kubernetes node.
 func (m *Machine) Delete(ctx context.Context) error {
 	log := ctrl.LoggerFrom(ctx)
@@ -445,24 +641,62 @@ func (m *Machine) Delete(ctx context.Context) error {
 	return nil
 }
 
-// machineImage is the image of the container node with the machine.
-func (m *Machine) machineImage(version *string) string {
-	if version == nil {
-		defaultImage := fmt.Sprintf("%s:%s", defaultImageName, defaultImageTag)
-		return defaultImage
+// Snapshot commits the container hosting the machine to an image.
+func (m *Machine) Snapshot(ctx context.Context, image string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if m.container == nil {
+		return errors.New("unable to snapshot the machine. the container hosting this machine does not exists")
+	}
+
+	containerRuntime, err := container.NewRuntime()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
 	}
 
-	// TODO(fp) make this smarter
-	// - allows usage of custom docker repository & image names
-	// - add v only for semantic versions
-	versionString := *version
-	if !strings.HasPrefix(versionString, "v") {
-		versionString = fmt.Sprintf("v%s", versionString)
+	committer, ok := containerRuntime.(container.Committer)
+	if !ok {
+		return errors.Errorf("unable to snapshot the machine. the %s container runtime does not support committing containers to images", container.RuntimeName())
 	}
 
-	versionString = clusterapicontainer.SemverToOCIImageTag(versionString)
+	log.Info("Committing machine container to image", "image", image)
+	return committer.CommitContainer(ctx, m.ContainerName(), image)
+}
 
-	return fmt.Sprintf("%s:%s", defaultImageName, versionString)
+// Restore deletes the container hosting the machine, if it exists, so it is created again from the given image;
+// the named volumes of the machine are preserved.
+func (m *Machine) Restore(ctx context.Context, image string) error {
+	if err := m.Delete(ctx); err != nil {
+		return err
+	}
+	m.container = nil
+	m.image = image
+	return nil
+}
+
+// DeleteVolumes deletes the docker volumes of the machine.
+func (m *Machine) DeleteVolumes(ctx context.Context, volumes []infrav1.Volume) error {
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	containerRuntime, err := container.NewRuntime()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+	for _, volume := range volumes {
+		log.Info("Deleting machine volume", "volume", m.volumeName(volume))
+		if err := containerRuntime.DeleteVolume(ctx, m.volumeName(volume)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// machineImage is the image of the container node with the machine.
+func (m *Machine) machineImage(version *string) string {
+	return NodeImage(version)
 }
 
 func logContainerDebugInfo(log logr.Logger, name string) {
@@ -471,7 +705,7 @@ func logContainerDebugInfo(log logr.Logger, name string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		log.Error(err, "failed to connect to container runtime")
 		return
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestAddressesForIPFamily(t *testing.T) {
+	tests := []struct {
+		name     string
+		ipFamily clusterv1.ClusterIPFamily
+		want     []string
+	}{
+		{
+			name:     "IPv4 machines have the IPv4 address",
+			ipFamily: clusterv1.IPv4IPFamily,
+			want:     []string{"172.18.0.2"},
+		},
+		{
+			name:     "IPv6 machines have the IPv6 address",
+			ipFamily: clusterv1.IPv6IPFamily,
+			want:     []string{"fc00:f853:ccd:e793::2"},
+		},
+		{
+			name:     "dual-stack machines have both the IPv4 and the IPv6 address",
+			ipFamily: clusterv1.DualStackIPFamily,
+			want:     []string{"172.18.0.2", "fc00:f853:ccd:e793::2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(addressesForIPFamily(tt.ipFamily, "172.18.0.2", "fc00:f853:ccd:e793::2")).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDualStackKubeletArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ipv4    string
+		ipv6    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "sets both addresses as node IPs",
+			ipv4: "172.18.0.2",
+			ipv6: "fc00:f853:ccd:e793::2",
+			want: "KUBELET_EXTRA_ARGS=--node-ip=172.18.0.2,fc00:f853:ccd:e793::2\n",
+		},
+		{
+			name:    "fails without an IPv4 address",
+			ipv6:    "fc00:f853:ccd:e793::2",
+			wantErr: true,
+		},
+		{
+			name:    "fails without an IPv6 address",
+			ipv4:    "172.18.0.2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := dualStackKubeletArgs(tt.ipv4, tt.ipv6)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestContainerLabels(t *testing.T) {
+	tests := []struct {
+		name          string
+		labels        map[string]string
+		failureDomain *string
+		want          map[string]string
+	}{
+		{
+			name:   "no failure domain label without a failure domain",
+			labels: map[string]string{"foo": "bar"},
+			want:   map[string]string{"foo": "bar"},
+		},
+		{
+			name:          "no failure domain label with an empty failure domain",
+			labels:        map[string]string{"foo": "bar"},
+			failureDomain: pointer.String(""),
+			want:          map[string]string{"foo": "bar"},
+		},
+		{
+			name:          "failure domain label with a failure domain",
+			labels:        map[string]string{"foo": "bar"},
+			failureDomain: pointer.String("fd1"),
+			want:          map[string]string{"foo": "bar", failureDomainLabelKey: "fd1"},
+		},
+		{
+			name:          "failure domain label without other labels",
+			failureDomain: pointer.String("fd1"),
+			want:          map[string]string{failureDomainLabelKey: "fd1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{labels: tt.labels}
+			g.Expect(m.containerLabels(tt.failureDomain)).To(Equal(tt.want))
+			// The labels of the machine must not be changed.
+			g.Expect(m.labels).To(Equal(tt.labels))
+		})
+	}
+}
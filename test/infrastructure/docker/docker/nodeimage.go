@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+	clusterapicontainer "sigs.k8s.io/cluster-api/util/container"
+)
+
+// kindNodeImageDigests are the digests of the kindest/node images published for kind v0.11.1; using digests
+// guarantees the images built for the kind version CAPD is compatible with are used, even if the tags are re-pushed.
+var kindNodeImageDigests = map[string]string{
+	"v1.22.0":  "sha256:b8bda84bb3a190e6e028b1760d277454a72267a5454b57db34437c34a588d047",
+	"v1.21.1":  "sha256:69860bda5563ac81e3c0057d654b5253219618a22ec3a346306239bba8cfa1a6",
+	"v1.20.7":  "sha256:cbeaf907fc78ac97ce7b625e4bf0de16e3ea725daf6b04f930bd14c67c671ff9",
+	"v1.19.11": "sha256:07db187ae84b4b7de440a73886f008cf903fcf5764ba8106a9fd5243d6f32729",
+	"v1.18.19": "sha256:7af1492e19b3192a79f606e43c35fb741e520d195f96399284515f077b3b622c",
+	"v1.17.17": "sha256:66f1d0d91a88b8a001811e2f1054af60eef3b669a9a74f9b6db871f2f1eeed00",
+	"v1.16.15": "sha256:83067ed51bf2a3395b24687094e283a7c7c865ccc12a8b1d7aa673ba0c5e8861",
+	"v1.15.12": "sha256:b920920e1eda689d9936dfcf7332701e80be12566999152626b2c9d730397a95",
+	"v1.14.10": "sha256:f8a66ef82822ab4f7569e91a5bccaf27bceee135c1457c512e54de8c6f7219f8",
+}
+
+var (
+	nodeImageDigestsLock      sync.RWMutex
+	nodeImageDigestsOverrides map[string]string
+)
+
+// SetNodeImageDigests overrides the digests of the kindest/node images by Kubernetes version (e.g. "v1.22.0"),
+// e.g. for using the images published by a different kind version; an empty digest makes the image for the
+// version to be referenced by tag only. Passing nil restores the digests of the images published for kind v0.11.1.
+func SetNodeImageDigests(digests map[string]string) {
+	nodeImageDigestsLock.Lock()
+	defer nodeImageDigestsLock.Unlock()
+	nodeImageDigestsOverrides = digests
+}
+
+// nodeImageDigest returns the digest of the kindest/node image for the given version, if any.
+func nodeImageDigest(version string) (string, bool) {
+	nodeImageDigestsLock.RLock()
+	defer nodeImageDigestsLock.RUnlock()
+
+	digest, ok := nodeImageDigestsOverrides[version]
+	if !ok {
+		digest, ok = kindNodeImageDigests[version]
+	}
+	return digest, ok && digest != ""
+}
+
+// NodeImage returns the kind node image for the given Kubernetes version; if the digest of the image for the version
+// is known, the image is referenced by digest.
+func NodeImage(version *string) string {
+	versionString := defaultImageTag
+	if version != nil {
+		// TODO(fp) make this smarter
+		// - allows usage of custom docker repository & image names
+		// - add v only for semantic versions
+		versionString = *version
+		if !strings.HasPrefix(versionString, "v") {
+			versionString = fmt.Sprintf("v%s", versionString)
+		}
+
+		versionString = clusterapicontainer.SemverToOCIImageTag(versionString)
+	}
+
+	if digest, ok := nodeImageDigest(versionString); ok {
+		return fmt.Sprintf("%s:%s@%s", defaultImageName, versionString, digest)
+	}
+	return fmt.Sprintf("%s:%s", defaultImageName, versionString)
+}
+
+// PullImage pulls the image of the container hosting the machine, if it does not exist yet.
+// NOTE: Pulling is not retried, so the caller can retry without blocking, e.g. by requeueing.
+func (m *Machine) PullImage(ctx context.Context, version *string) error {
+	image := m.machineImage(version)
+	if m.image != "" {
+		image = m.image
+	}
+
+	containerRuntime, err := container.NewRuntime()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+
+	if err := containerRuntime.PullContainerImageIfNotExists(ctx, image); err != nil {
+		return errors.Wrapf(err, "failed to pull image %q", image)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
+)
+
+func TestNodeImage(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   *string
+		overrides map[string]string
+		want      string
+	}{
+		{
+			name: "default image by digest if the version is not set",
+			want: "kindest/node:v1.22.0@sha256:b8bda84bb3a190e6e028b1760d277454a72267a5454b57db34437c34a588d047",
+		},
+		{
+			name:    "image by digest for a version published by kind",
+			version: pointer.String("v1.21.1"),
+			want:    "kindest/node:v1.21.1@sha256:69860bda5563ac81e3c0057d654b5253219618a22ec3a346306239bba8cfa1a6",
+		},
+		{
+			name:    "image by digest for a version without the v prefix",
+			version: pointer.String("1.21.1"),
+			want:    "kindest/node:v1.21.1@sha256:69860bda5563ac81e3c0057d654b5253219618a22ec3a346306239bba8cfa1a6",
+		},
+		{
+			name:    "image by tag for a version not published by kind",
+			version: pointer.String("v1.23.0"),
+			want:    "kindest/node:v1.23.0",
+		},
+		{
+			name:    "image by tag for a version with build metadata",
+			version: pointer.String("v1.22.0+build.1"),
+			want:    "kindest/node:v1.22.0_build.1",
+		},
+		{
+			name:      "image by overridden digest",
+			version:   pointer.String("v1.23.0"),
+			overrides: map[string]string{"v1.23.0": "sha256:0123456789"},
+			want:      "kindest/node:v1.23.0@sha256:0123456789",
+		},
+		{
+			name:      "image by tag if the digest is overridden with an empty digest",
+			version:   pointer.String("v1.22.0"),
+			overrides: map[string]string{"v1.22.0": ""},
+			want:      "kindest/node:v1.22.0",
+		},
+		{
+			name:      "image by digest published by kind for a version which is not overridden",
+			version:   pointer.String("v1.21.1"),
+			overrides: map[string]string{"v1.23.0": "sha256:0123456789"},
+			want:      "kindest/node:v1.21.1@sha256:69860bda5563ac81e3c0057d654b5253219618a22ec3a346306239bba8cfa1a6",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			SetNodeImageDigests(tt.overrides)
+			defer SetNodeImageDigests(nil)
+
+			g.Expect(NodeImage(tt.version)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPullImage(t *testing.T) {
+	tests := []struct {
+		name      string
+		image     string
+		version   *string
+		pullErr   error
+		wantImage string
+		wantErr   bool
+	}{
+		{
+			name:      "pulls the node image for the version",
+			version:   pointer.String("v1.23.0"),
+			wantImage: "kindest/node:v1.23.0",
+		},
+		{
+			name:      "pulls the custom image",
+			image:     "my-registry/node:latest",
+			version:   pointer.String("v1.23.0"),
+			wantImage: "my-registry/node:latest",
+		},
+		{
+			name:      "fails if the image cannot be pulled",
+			version:   pointer.String("v1.23.0"),
+			pullErr:   errors.New("registry unavailable"),
+			wantImage: "kindest/node:v1.23.0",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeRuntime := &container.FakeRuntime{
+				Errors: map[string]error{"PullContainerImageIfNotExists": tt.pullErr},
+			}
+			container.SetFakeRuntime(fakeRuntime)
+			defer container.SetFakeRuntime(nil)
+
+			m := &Machine{image: tt.image}
+			err := m.PullImage(context.Background(), tt.version)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			// The image is pulled only once, retries are left to the caller.
+			g.Expect(fakeRuntime.Calls()).To(Equal([]string{"PullContainerImageIfNotExists " + tt.wantImage}))
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api/test/infrastructure/container"
@@ -69,7 +70,7 @@ func (n *Node) Role() (string, error) {
 // IP gets the docker ipv4 and ipv6 of the node.
 func (n *Node) IP(ctx context.Context) (ipv4 string, ipv6 string, err error) {
 	// retrieve the IP address of the node using docker inspect
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return "", "", errors.Wrap(err, "failed to connect to container runtime")
 	}
@@ -90,7 +91,7 @@ func (n *Node) IsRunning() bool {
 
 // Delete removes the container.
 func (n *Node) Delete(ctx context.Context) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to container runtime")
 	}
@@ -116,9 +117,35 @@ func (n *Node) WriteFile(ctx context.Context, dest, content string) error {
 	return command.Run(ctx)
 }
 
+// ReadFile returns the content of a file inside a running container.
+func (n *Node) ReadFile(ctx context.Context, src string) (string, error) {
+	var stdout bytes.Buffer
+	command := n.Commander.Command("cat", src)
+	command.SetStdout(&stdout)
+	if err := command.Run(ctx); err != nil {
+		return "", errors.Wrapf(err, "failed to read file %s", src)
+	}
+	return stdout.String(), nil
+}
+
+// Stop stops the container, killing it if it does not stop within the timeout.
+func (n *Node) Stop(ctx context.Context, timeout time.Duration) error {
+	containerRuntime, err := container.NewRuntime()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to container runtime")
+	}
+
+	err = containerRuntime.StopContainer(ctx, n.Name, timeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to stop container %q", n.Name)
+	}
+
+	return nil
+}
+
 // Kill sends the named signal to the container.
 func (n *Node) Kill(ctx context.Context, signal string) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to container runtime")
 	}
@@ -177,7 +204,7 @@ func (c *ContainerCmd) RunLoggingOutputOnFail(ctx context.Context) ([]string, er
 }
 
 func (c *ContainerCmd) Run(ctx context.Context) error {
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to container runtime")
 	}
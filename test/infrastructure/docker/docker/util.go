@@ -28,6 +28,7 @@ import (
 
 const clusterLabelKey = "io.x-k8s.kind.cluster"
 const nodeRoleLabelKey = "io.x-k8s.kind.role"
+const failureDomainLabelKey = "io.x-k8s.capd.failure-domain"
 const filterLabel = "label"
 const filterName = "name"
 
@@ -82,7 +83,7 @@ func List(filters container.FilterBuilder) ([]*types.Node, error) {
 
 func list(visit func(string, *types.Node), filters container.FilterBuilder) error {
 	ctx := context.TODO()
-	containerRuntime, err := container.NewDockerClient()
+	containerRuntime, err := container.NewRuntime()
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to container runtime")
 	}
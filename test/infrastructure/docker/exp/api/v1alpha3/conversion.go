@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+func (src *DockerMachinePool) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DockerMachinePool)
+
+	if err := Convert_v1alpha3_DockerMachinePool_To_v1beta1_DockerMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1beta1.DockerMachinePool{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Status.UpdatedReplicas = restored.Status.UpdatedReplicas
+
+	return nil
+}
+
+func (dst *DockerMachinePool) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DockerMachinePool)
+
+	if err := Convert_v1beta1_DockerMachinePool_To_v1alpha3_DockerMachinePool(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
+}
+
+func (src *DockerMachinePoolList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DockerMachinePoolList)
+
+	return Convert_v1alpha3_DockerMachinePoolList_To_v1beta1_DockerMachinePoolList(src, dst, nil)
+}
+
+func (dst *DockerMachinePoolList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DockerMachinePoolList)
+
+	return Convert_v1beta1_DockerMachinePoolList_To_v1alpha3_DockerMachinePoolList(src, dst, nil)
+}
+
+// Convert_v1beta1_DockerMachinePoolStatus_To_v1alpha3_DockerMachinePoolStatus is an autogenerated conversion function.
+func Convert_v1beta1_DockerMachinePoolStatus_To_v1alpha3_DockerMachinePoolStatus(in *v1beta1.DockerMachinePoolStatus, out *DockerMachinePoolStatus, s apiconversion.Scope) error {
+	// DockerMachinePoolStatus.UpdatedReplicas was added in v1beta1, so automatic conversion is not possible
+	return autoConvert_v1beta1_DockerMachinePoolStatus_To_v1alpha3_DockerMachinePoolStatus(in, out, s)
+}
@@ -71,6 +71,10 @@ type DockerMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// UpdatedReplicas is the number of instances matching the current Kubernetes version of the machine pool.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
 	// The generation observed by the deployment controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
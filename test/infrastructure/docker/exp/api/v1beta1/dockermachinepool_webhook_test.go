@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
+)
+
+func TestDockerMachinePoolValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		extraMounts []infrav1.Mount
+		wantError   bool
+	}{
+		{
+			name:      "return no error if there are no extra mounts",
+			wantError: false,
+		},
+		{
+			name:        "return no error if extra mounts are valid",
+			extraMounts: []infrav1.Mount{{ContainerPath: "/var/run/docker.sock", HostPath: "/var/run/docker.sock"}},
+			wantError:   false,
+		},
+		{
+			name:        "return error if the container path is missing",
+			extraMounts: []infrav1.Mount{{HostPath: "/var/run/docker.sock"}},
+			wantError:   true,
+		},
+		{
+			name:        "return error if the host path is missing",
+			extraMounts: []infrav1.Mount{{ContainerPath: "/var/run/docker.sock"}},
+			wantError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &DockerMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+				Spec: DockerMachinePoolSpec{
+					Template: DockerMachinePoolMachineTemplate{
+						ExtraMounts: tt.extraMounts,
+					},
+				},
+			}
+
+			err := pool.ValidateCreate()
+			if (err != nil) != tt.wantError {
+				t.Errorf("unexpected result on create - wanted %+v, got %+v", tt.wantError, err)
+			}
+			err = pool.ValidateUpdate(pool.DeepCopy())
+			if (err != nil) != tt.wantError {
+				t.Errorf("unexpected result on update - wanted %+v, got %+v", tt.wantError, err)
+			}
+		})
+	}
+}
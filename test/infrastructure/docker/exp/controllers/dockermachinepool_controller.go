@@ -32,6 +32,7 @@ import (
 	infrav1exp "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/docker"
 	"sigs.k8s.io/cluster-api/util"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -76,7 +77,7 @@ func (r *DockerMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
-	log = log.WithValues("machine-pool", machinePool.Name)
+	log = log.WithValues(logutil.MachinePoolKey, logutil.KObj(machinePool))
 
 	// Fetch the Cluster.
 	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
@@ -90,7 +91,8 @@ func (r *DockerMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(logutil.ClusterKey, logutil.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
 
 	// Initialize the patch helper
 	patchHelper, err := patch.NewHelper(dockerMachinePool, r.Client)
@@ -196,6 +198,12 @@ func (r *DockerMachinePoolReconciler) reconcileNormal(ctx context.Context, clust
 	}
 
 	dockerMachinePool.Status.Replicas = int32(len(dockerMachinePool.Status.Instances))
+	dockerMachinePool.Status.UpdatedReplicas = 0
+	for _, instance := range dockerMachinePool.Status.Instances {
+		if instance.Version != nil && machinePool.Spec.Template.Spec.Version != nil && *instance.Version == *machinePool.Spec.Template.Spec.Version {
+			dockerMachinePool.Status.UpdatedReplicas++
+		}
+	}
 
 	if dockerMachinePool.Spec.ProviderID == "" {
 		// This is a fake provider ID which does not tie back to any docker infrastructure. In cloud providers,
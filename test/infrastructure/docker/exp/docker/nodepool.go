@@ -25,8 +25,10 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker"
 	infrav1exp "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
@@ -71,19 +73,33 @@ func NewNodePool(c client.Client, cluster *clusterv1.Cluster, mp *clusterv1exp.M
 // ReconcileMachines will build enough machines to satisfy the machine pool / docker machine pool spec
 // eventually delete all the machine in excess, and update the status for all the machines.
 //
-// NOTE: The goal for the current implementation is to verify MachinePool construct; accordingly,
-// currently the nodepool supports only a recreate strategy for replacing old nodes with new ones
-// (all existing machines are killed before new ones are created).
-// TODO: consider if to support a Rollout strategy (a more progressive node replacement).
+// NOTE: When the machine pool defines a RollingUpdate strategy, outdated machines are replaced progressively,
+// keeping the number of unavailable machines within maxUnavailable; otherwise the nodepool uses a recreate strategy
+// for replacing old nodes with new ones (all existing machines are killed before new ones are created).
 func (np *NodePool) ReconcileMachines(ctx context.Context) (ctrl.Result, error) {
 	desiredReplicas := int(*np.machinePool.Spec.Replicas)
 
+	// Compute how many outdated machines can be deleted without exceeding maxUnavailable; machines which are
+	// not ready yet, or missing, are already unavailable.
+	unavailableBudget := 0
+	if np.isRollingUpdate() {
+		unavailableBudget = np.maxUnavailable(desiredReplicas)
+		if missing := desiredReplicas - len(np.machines); missing > 0 {
+			unavailableBudget -= missing
+		}
+		for _, machine := range np.machines {
+			if !np.isMachineReady(machine) {
+				unavailableBudget--
+			}
+		}
+	}
+
 	// Delete all the machines in excess (outdated machines or machines exceeding desired replica count).
 	machineDeleted := false
 	totalNumberOfMachines := 0
 	for _, machine := range np.machines {
 		totalNumberOfMachines++
-		if totalNumberOfMachines > desiredReplicas || !np.isMachineMatchingInfrastructureSpec(machine) {
+		if totalNumberOfMachines > desiredReplicas || np.canDeleteOutdatedMachine(machine, &unavailableBudget) {
 			externalMachine, err := docker.NewMachine(np.cluster, machine.Name(), np.dockerMachinePool.Spec.Template.CustomImage, np.labelFilters)
 			if err != nil {
 				return ctrl.Result{}, errors.Wrapf(err, "failed to create helper for managing the externalMachine named %s", machine.Name())
@@ -103,9 +119,8 @@ func (np *NodePool) ReconcileMachines(ctx context.Context) (ctrl.Result, error)
 
 	// Add new machines if missing.
 	machineAdded := false
-	matchingMachineCount := len(np.machinesMatchingInfrastructureSpec())
-	if matchingMachineCount < desiredReplicas {
-		for i := 0; i < desiredReplicas-matchingMachineCount; i++ {
+	if machineCount := len(np.machines); machineCount < desiredReplicas {
+		for i := 0; i < desiredReplicas-machineCount; i++ {
 			if err := np.addMachine(ctx); err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to create a new docker machine")
 			}
@@ -166,16 +181,52 @@ func (np *NodePool) isMachineMatchingInfrastructureSpec(machine *docker.Machine)
 	return machine.ImageVersion() == container.SemverToOCIImageTag(*np.machinePool.Spec.Template.Spec.Version)
 }
 
-// machinesMatchingInfrastructureSpec returns all of the docker.Machines which match the machine pool / docker machine pool spec.
-func (np *NodePool) machinesMatchingInfrastructureSpec() []*docker.Machine {
-	var matchingMachines []*docker.Machine
-	for _, machine := range np.machines {
-		if np.isMachineMatchingInfrastructureSpec(machine) {
-			matchingMachines = append(matchingMachines, machine)
+// canDeleteOutdatedMachine returns true if the machine does not match the machine pool / docker machine pool spec
+// and it can be deleted without exceeding the unavailable budget of a rolling update; the budget is decreased
+// every time an available machine is selected for deletion.
+func (np *NodePool) canDeleteOutdatedMachine(machine *docker.Machine, unavailableBudget *int) bool {
+	if np.isMachineMatchingInfrastructureSpec(machine) {
+		return false
+	}
+	if !np.isRollingUpdate() || !np.isMachineReady(machine) {
+		return true
+	}
+	if *unavailableBudget > 0 {
+		*unavailableBudget--
+		return true
+	}
+	return false
+}
+
+// isRollingUpdate returns true if the machine pool requires outdated machines to be replaced progressively.
+func (np *NodePool) isRollingUpdate() bool {
+	strategy := np.machinePool.Spec.Strategy
+	return strategy != nil && strategy.Type != clusterv1exp.RecreateMachinePoolStrategyType
+}
+
+// maxUnavailable returns the number of machines which can be unavailable during a rolling update; percentages
+// are rounded down, but at least one machine is always replaced at a time.
+func (np *NodePool) maxUnavailable(desiredReplicas int) int {
+	maxUnavailable := 1
+	if rollingUpdate := np.machinePool.Spec.Strategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.MaxUnavailable != nil {
+		if v, err := intstr.GetScaledValueFromIntOrPercent(rollingUpdate.MaxUnavailable, desiredReplicas, false); err == nil {
+			maxUnavailable = v
 		}
 	}
+	if maxUnavailable < 1 {
+		maxUnavailable = 1
+	}
+	return maxUnavailable
+}
 
-	return matchingMachines
+// isMachineReady returns true if the instance status for the machine reports it as ready.
+func (np *NodePool) isMachineReady(machine *docker.Machine) bool {
+	for _, instance := range np.dockerMachinePool.Status.Instances {
+		if instance.InstanceName == machine.Name() {
+			return instance.Ready
+		}
+	}
+	return false
 }
 
 // addMachine will add a new machine to the node pool and update the docker machine pool status.
@@ -186,7 +237,7 @@ func (np *NodePool) addMachine(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to create helper for managing the externalMachine named %s", instanceName)
 	}
 
-	if err := externalMachine.Create(ctx, constants.WorkerNodeRoleValue, np.machinePool.Spec.Template.Spec.Version, np.dockerMachinePool.Spec.Template.ExtraMounts); err != nil {
+	if err := externalMachine.Create(ctx, constants.WorkerNodeRoleValue, np.machinePool.Spec.Template.Spec.Version, nil, np.dockerMachinePool.Spec.Template.ExtraMounts, nil, infrav1.DockerMachineNetwork{}, infrav1.DockerMachineResources{}); err != nil {
 		return errors.Wrapf(err, "failed to create docker machine with instance name %s", instanceName)
 	}
 	return nil
@@ -254,7 +305,7 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 			return ctrl.Result{}, errors.Wrapf(err, "failed to pre-load images into the docker machine with instance name %s", machine.Name())
 		}
 
-		bootstrapData, err := getBootstrapData(ctx, np.client, np.machinePool)
+		bootstrapData, format, err := getBootstrapData(ctx, np.client, np.machinePool)
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to get bootstrap data for instance named %s", machine.Name())
 		}
@@ -262,7 +313,7 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 		timeoutctx, cancel := context.WithTimeout(ctx, 3*time.Minute)
 		defer cancel()
 		// Run the bootstrap script. Simulates cloud-init.
-		if err := externalMachine.ExecBootstrap(timeoutctx, bootstrapData); err != nil {
+		if err := externalMachine.ExecBootstrap(timeoutctx, bootstrapData, format); err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to exec DockerMachinePool instance bootstrap for instance named %s", machine.Name())
 		}
 		// Check for bootstrap success
@@ -278,7 +329,7 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 	if machineStatus.Addresses == nil {
 		log.Info("Fetching instance addresses", "instance", machine.Name())
 		// set address in machine status
-		machineAddress, err := externalMachine.Address(ctx)
+		machineAddresses, err := externalMachine.Addresses(ctx)
 		if err != nil {
 			// Requeue if there is an error, as this is likely momentary load balancer
 			// state changes during control plane provisioning.
@@ -290,14 +341,18 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 				Type:    clusterv1.MachineHostName,
 				Address: externalMachine.ContainerName(),
 			},
-			{
-				Type:    clusterv1.MachineInternalIP,
-				Address: machineAddress,
-			},
-			{
-				Type:    clusterv1.MachineExternalIP,
-				Address: machineAddress,
-			},
+		}
+		for _, machineAddress := range machineAddresses {
+			machineStatus.Addresses = append(machineStatus.Addresses,
+				clusterv1.MachineAddress{
+					Type:    clusterv1.MachineInternalIP,
+					Address: machineAddress,
+				},
+				clusterv1.MachineAddress{
+					Type:    clusterv1.MachineExternalIP,
+					Address: machineAddress,
+				},
+			)
 		}
 	}
 
@@ -320,21 +375,27 @@ func (np *NodePool) reconcileMachine(ctx context.Context, machine *docker.Machin
 }
 
 // getBootstrapData fetches the bootstrap data for the machine pool.
-func getBootstrapData(ctx context.Context, c client.Client, machinePool *clusterv1exp.MachinePool) (string, error) {
+func getBootstrapData(ctx context.Context, c client.Client, machinePool *clusterv1exp.MachinePool) (string, docker.BootstrapDataFormat, error) {
 	if machinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
-		return "", errors.New("error retrieving bootstrap data: linked MachinePool's bootstrap.dataSecretName is nil")
+		return "", "", errors.New("error retrieving bootstrap data: linked MachinePool's bootstrap.dataSecretName is nil")
 	}
 
 	s := &corev1.Secret{}
 	key := client.ObjectKey{Namespace: machinePool.GetNamespace(), Name: *machinePool.Spec.Template.Spec.Bootstrap.DataSecretName}
 	if err := c.Get(ctx, key, s); err != nil {
-		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for DockerMachinePool instance %s/%s", machinePool.GetNamespace(), machinePool.GetName())
+		return "", "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for DockerMachinePool instance %s/%s", machinePool.GetNamespace(), machinePool.GetName())
 	}
 
 	value, ok := s.Data["value"]
 	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
+		return "", "", errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+
+	// If the bootstrap data secret does not define the format, the bootstrap data is assumed to be in cloud-config format.
+	format := docker.CloudConfig
+	if f, ok := s.Data["format"]; ok && len(f) > 0 {
+		format = docker.BootstrapDataFormat(f)
 	}
 
-	return base64.StdEncoding.EncodeToString(value), nil
+	return base64.StdEncoding.EncodeToString(value), format, nil
 }
@@ -0,0 +1,24 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package ignition defines an Ignition adapter for kind nodes.
+
+The adapter supports a limited set of Ignition features, i.e. files, directories and systemd units,
+just what is necessary to test bootstrap providers generating Ignition configs; like the cloud init
+adapter, it is designed to work on existing kind node images.
+*/
+package ignition
@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/cloudinit"
+)
+
+const systemdUnitsPath = "/etc/systemd/system"
+
+// config defines the subset of an Ignition v3 config supported by the adapter.
+type config struct {
+	Ignition struct {
+		Version string `json:"version"`
+	} `json:"ignition"`
+	Storage struct {
+		Directories []directory `json:"directories,omitempty"`
+		Files       []file      `json:"files,omitempty"`
+	} `json:"storage,omitempty"`
+	Systemd struct {
+		Units []unit `json:"units,omitempty"`
+	} `json:"systemd,omitempty"`
+}
+
+type directory struct {
+	Path string `json:"path"`
+	Mode *int   `json:"mode,omitempty"`
+}
+
+type file struct {
+	Path      string     `json:"path"`
+	Mode      *int       `json:"mode,omitempty"`
+	Contents  *resource  `json:"contents,omitempty"`
+	Append    []resource `json:"append,omitempty"`
+	Overwrite *bool      `json:"overwrite,omitempty"`
+}
+
+type resource struct {
+	Source      *string `json:"source,omitempty"`
+	Compression *string `json:"compression,omitempty"`
+}
+
+type unit struct {
+	Name     string   `json:"name"`
+	Enabled  *bool    `json:"enabled,omitempty"`
+	Mask     *bool    `json:"mask,omitempty"`
+	Contents *string  `json:"contents,omitempty"`
+	Dropins  []dropin `json:"dropins,omitempty"`
+}
+
+type dropin struct {
+	Name     string  `json:"name"`
+	Contents *string `json:"contents,omitempty"`
+}
+
+// Commands converts an Ignition config to a list of commands to run in sequence on the node.
+// Directories and files are created first, then systemd units are written and enabled units are started,
+// following the same order used by Ignition when provisioning a machine.
+func Commands(ignitionData []byte) ([]cloudinit.Cmd, error) {
+	c := &config{}
+	if err := json.Unmarshal(ignitionData, c); err != nil {
+		return nil, errors.Wrap(err, "ignition config is not valid json")
+	}
+	if !strings.HasPrefix(c.Ignition.Version, "3.") {
+		return nil, errors.Errorf("unsupported ignition config version %q, only 3.x versions are supported", c.Ignition.Version)
+	}
+
+	commands := []cloudinit.Cmd{}
+	for _, d := range c.Storage.Directories {
+		commands = append(commands, cloudinit.Cmd{Cmd: "mkdir", Args: []string{"-p", d.Path}})
+		if d.Mode != nil {
+			commands = append(commands, chmod(*d.Mode, d.Path))
+		}
+	}
+
+	for _, f := range c.Storage.Files {
+		cmds, err := fileCommands(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert file %s", f.Path)
+		}
+		commands = append(commands, cmds...)
+	}
+
+	if len(c.Systemd.Units) == 0 {
+		return commands, nil
+	}
+
+	toStart := []string{}
+	for _, u := range c.Systemd.Units {
+		if u.Contents != nil {
+			commands = append(commands, writeFile(path.Join(systemdUnitsPath, u.Name), hackKubeadmIgnoreErrors(*u.Contents), false)...)
+		}
+		for _, d := range u.Dropins {
+			if d.Contents != nil {
+				commands = append(commands, writeFile(path.Join(systemdUnitsPath, u.Name+".d", d.Name), *d.Contents, false)...)
+			}
+		}
+		if u.Mask != nil && *u.Mask {
+			commands = append(commands, cloudinit.Cmd{Cmd: "systemctl", Args: []string{"mask", u.Name}})
+			continue
+		}
+		if u.Enabled != nil && *u.Enabled {
+			toStart = append(toStart, u.Name)
+		}
+	}
+
+	commands = append(commands, cloudinit.Cmd{Cmd: "systemctl", Args: []string{"daemon-reload"}})
+	for _, name := range toStart {
+		// NOTE: Ignition only enables units, which are then started at boot; given that the node is already running,
+		// units are started immediately. Starting oneshot units, e.g. the one running kubeadm, blocks until they complete.
+		commands = append(commands, cloudinit.Cmd{Cmd: "systemctl", Args: []string{"enable", "--now", name}})
+	}
+	return commands, nil
+}
+
+// fileCommands returns the commands for creating a file replicating the Ignition files section.
+func fileCommands(f file) ([]cloudinit.Cmd, error) {
+	commands := []cloudinit.Cmd{}
+
+	if f.Contents != nil {
+		content, err := decodeResource(*f.Contents)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, writeFile(f.Path, hackKubeadmIgnoreErrors(content), false)...)
+	}
+	for _, r := range f.Append {
+		content, err := decodeResource(r)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, writeFile(f.Path, hackKubeadmIgnoreErrors(content), true)...)
+	}
+	if f.Mode != nil {
+		commands = append(commands, chmod(*f.Mode, f.Path))
+	}
+	return commands, nil
+}
+
+// decodeResource returns the content of a resource; only inline data URLs are supported as a source.
+func decodeResource(r resource) (string, error) {
+	if r.Source == nil {
+		return "", nil
+	}
+
+	source := *r.Source
+	if !strings.HasPrefix(source, "data:") {
+		return "", errors.Errorf("unsupported source %q, only data URLs are supported", source)
+	}
+	source = strings.TrimPrefix(source, "data:")
+
+	i := strings.Index(source, ",")
+	if i < 0 {
+		return "", errors.New("invalid data URL: missing ','")
+	}
+	mediaType, data := source[:i], source[i+1:]
+
+	var content []byte
+	if strings.HasSuffix(mediaType, ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to decode base64 data URL")
+		}
+		content = decoded
+	} else {
+		unescaped, err := url.PathUnescape(data)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to decode data URL")
+		}
+		content = []byte(unescaped)
+	}
+
+	if r.Compression != nil && *r.Compression != "" {
+		if *r.Compression != "gzip" {
+			return "", errors.Errorf("unsupported compression %q", *r.Compression)
+		}
+		reader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(reader); err != nil {
+			return "", errors.WithStack(err)
+		}
+		content = buf.Bytes()
+	}
+	return string(content), nil
+}
+
+// hackKubeadmIgnoreErrors makes kubeadm ignore preflight errors, which is required when running in docker;
+// with Ignition kubeadm is usually invoked by a systemd unit or by a script, so the hack applies to file contents.
+func hackKubeadmIgnoreErrors(content string) string {
+	content = strings.Replace(content, "kubeadm init", "kubeadm init --ignore-preflight-errors=all", 1)
+	content = strings.Replace(content, "kubeadm join", "kubeadm join --ignore-preflight-errors=all", 1)
+	return content
+}
+
+// writeFile returns the commands for writing content to a file, creating the parent directory if necessary.
+func writeFile(filePath, content string, appendContent bool) []cloudinit.Cmd {
+	redirects := ">"
+	if appendContent {
+		redirects = ">>"
+	}
+	return []cloudinit.Cmd{
+		{Cmd: "mkdir", Args: []string{"-p", filepath.Dir(filePath)}},
+		{Cmd: "/bin/sh", Args: []string{"-c", fmt.Sprintf("cat %s %s /dev/stdin", redirects, filePath)}, Stdin: content},
+	}
+}
+
+// chmod returns the command for setting the permissions of a file; Ignition defines mode as a decimal integer.
+func chmod(mode int, filePath string) cloudinit.Cmd {
+	return cloudinit.Cmd{Cmd: "chmod", Args: []string{fmt.Sprintf("%04o", mode), filePath}}
+}
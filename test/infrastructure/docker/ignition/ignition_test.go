@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/cloudinit"
+)
+
+func TestCommands(t *testing.T) {
+	var gzipped bytes.Buffer
+	w := gzip.NewWriter(&gzipped)
+	_, _ = w.Write([]byte("gzipped content"))
+	_ = w.Close()
+
+	ignitionData := `{
+  "ignition": {"version": "3.1.0"},
+  "storage": {
+    "directories": [{"path": "/etc/kubernetes/manifests", "mode": 448}],
+    "files": [
+      {"path": "/etc/kubeadm.sh", "mode": 493, "contents": {"source": "data:,kubeadm%20init%20--config%20%2Fetc%2Fkubeadm.yml"}},
+      {"path": "/etc/kubeadm.yml", "contents": {"source": "data:;base64,` + base64.StdEncoding.EncodeToString([]byte("kind: InitConfiguration")) + `"}},
+      {"path": "/etc/gzipped", "contents": {"source": "data:;base64,` + base64.StdEncoding.EncodeToString(gzipped.Bytes()) + `", "compression": "gzip"}}
+    ]
+  },
+  "systemd": {
+    "units": [
+      {"name": "kubeadm.service", "enabled": true, "contents": "[Service]\nExecStart=/etc/kubeadm.sh\n"},
+      {"name": "containerd.service", "dropins": [{"name": "10-proxy.conf", "contents": "[Service]\n"}]}
+    ]
+  }
+}`
+
+	g := NewWithT(t)
+
+	commands, err := Commands([]byte(ignitionData))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(commands).To(Equal([]cloudinit.Cmd{
+		{Cmd: "mkdir", Args: []string{"-p", "/etc/kubernetes/manifests"}},
+		{Cmd: "chmod", Args: []string{"0700", "/etc/kubernetes/manifests"}},
+		{Cmd: "mkdir", Args: []string{"-p", "/etc"}},
+		{Cmd: "/bin/sh", Args: []string{"-c", "cat > /etc/kubeadm.sh /dev/stdin"}, Stdin: "kubeadm init --ignore-preflight-errors=all --config /etc/kubeadm.yml"},
+		{Cmd: "chmod", Args: []string{"0755", "/etc/kubeadm.sh"}},
+		{Cmd: "mkdir", Args: []string{"-p", "/etc"}},
+		{Cmd: "/bin/sh", Args: []string{"-c", "cat > /etc/kubeadm.yml /dev/stdin"}, Stdin: "kind: InitConfiguration"},
+		{Cmd: "mkdir", Args: []string{"-p", "/etc"}},
+		{Cmd: "/bin/sh", Args: []string{"-c", "cat > /etc/gzipped /dev/stdin"}, Stdin: "gzipped content"},
+		{Cmd: "mkdir", Args: []string{"-p", "/etc/systemd/system"}},
+		{Cmd: "/bin/sh", Args: []string{"-c", "cat > /etc/systemd/system/kubeadm.service /dev/stdin"}, Stdin: "[Service]\nExecStart=/etc/kubeadm.sh\n"},
+		{Cmd: "mkdir", Args: []string{"-p", "/etc/systemd/system/containerd.service.d"}},
+		{Cmd: "/bin/sh", Args: []string{"-c", "cat > /etc/systemd/system/containerd.service.d/10-proxy.conf /dev/stdin"}, Stdin: "[Service]\n"},
+		{Cmd: "systemctl", Args: []string{"daemon-reload"}},
+		{Cmd: "systemctl", Args: []string{"enable", "--now", "kubeadm.service"}},
+	}))
+}
+
+func TestCommandsErrors(t *testing.T) {
+	var useCases = []struct {
+		name         string
+		ignitionData string
+	}{
+		{
+			name:         "invalid json",
+			ignitionData: "runcmd: []",
+		},
+		{
+			name:         "unsupported version",
+			ignitionData: `{"ignition": {"version": "2.3.0"}}`,
+		},
+		{
+			name:         "unsupported source",
+			ignitionData: `{"ignition": {"version": "3.1.0"}, "storage": {"files": [{"path": "/foo", "contents": {"source": "https://example.com/foo"}}]}}`,
+		},
+	}
+
+	for _, rt := range useCases {
+		t.Run(rt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := Commands([]byte(rt.ignitionData))
+			g.Expect(err).To(HaveOccurred())
+		})
+	}
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"math/rand"
 	"os"
 	"time"
@@ -35,13 +36,16 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/diagnostics"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
+	"sigs.k8s.io/cluster-api/test/infrastructure/container"
 	infrav1alpha3 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1alpha4"
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1beta1"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/controllers"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker"
 	infraexpv1alpha3 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1alpha3"
 	infraexpv1alpha4 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1alpha4"
 	infraexpv1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1beta1"
@@ -61,6 +65,10 @@ var (
 	healthAddr           string
 	webhookPort          int
 	webhookCertDir       string
+	containerRuntime     string
+	prePullImages        bool
+	nodeImageDigests     map[string]string
+	diagnosticsOptions   diagnostics.Options
 )
 
 func init() {
@@ -93,6 +101,13 @@ func initFlags(fs *pflag.FlagSet) {
 		"Webhook Server port")
 	fs.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs/",
 		"Webhook cert dir, only used when webhook-port is specified.")
+	fs.StringVar(&containerRuntime, "container-runtime", "",
+		fmt.Sprintf("The container runtime hosting the machines, one of %v. If empty, the value of the %s environment variable is used, or docker if not set.", container.Runtimes, container.RuntimeEnvVar))
+	fs.BoolVar(&prePullImages, "pre-pull", false,
+		"Pull the node images for docker machines as soon as the machines are created, without waiting for the bootstrap data.")
+	fs.StringToStringVar(&nodeImageDigests, "node-image-digests", nil,
+		"Digests of the kindest/node images by Kubernetes version (e.g. v1.22.0=sha256:...), overriding the digests of the images published by kind v0.11.1. An empty digest makes the image for the version to be referenced by tag only.")
+	diagnostics.AddFlags(fs, &diagnosticsOptions)
 
 	feature.MutableGates.AddFlag(fs)
 }
@@ -107,6 +122,17 @@ func main() {
 
 	ctrl.SetLogger(klogr.New())
 
+	if containerRuntime != "" {
+		container.SetRuntime(containerRuntime)
+	}
+	if nodeImageDigests != nil {
+		docker.SetNodeImageDigests(nodeImageDigests)
+	}
+	if _, err := container.NewRuntime(); err != nil {
+		setupLog.Error(err, "unable to create container runtime client", "runtime", container.RuntimeName())
+		os.Exit(1)
+	}
+
 	restConfig := ctrl.GetConfigOrDie()
 	restConfig.UserAgent = remote.DefaultClusterAPIUserAgent("cluster-api-docker-controller-manager")
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
@@ -129,6 +155,7 @@ func main() {
 
 	setupChecks(mgr)
 	setupReconcilers(ctx, mgr)
+	setupDiagnostics(mgr, nil)
 	setupWebhooks(mgr)
 
 	// +kubebuilder:scaffold:builder
@@ -151,9 +178,26 @@ func setupChecks(mgr ctrl.Manager) {
 	}
 }
 
+func setupDiagnostics(mgr ctrl.Manager, tracker diagnostics.ClusterTracker) {
+	if diagnosticsOptions.BindAddress == "" {
+		return
+	}
+
+	server, err := diagnostics.NewServer(mgr, diagnosticsOptions, tracker)
+	if err != nil {
+		setupLog.Error(err, "unable to create diagnostics server")
+		os.Exit(1)
+	}
+	if err := mgr.Add(server); err != nil {
+		setupLog.Error(err, "unable to add diagnostics server")
+		os.Exit(1)
+	}
+}
+
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if err := (&controllers.DockerMachineReconciler{
-		Client: mgr.GetClient(),
+		Client:        mgr.GetClient(),
+		PrePullImages: prePullImages,
 	}).SetupWithManager(ctx, mgr, controller.Options{
 		MaxConcurrentReconciles: concurrency,
 	}); err != nil {
@@ -197,4 +241,11 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "DockerClusterTemplate")
 		os.Exit(1)
 	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := (&infraexpv1.DockerMachinePool{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DockerMachinePool")
+			os.Exit(1)
+		}
+	}
 }
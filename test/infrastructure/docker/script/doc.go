@@ -0,0 +1,24 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package script defines a shell script adapter for kind nodes.
+
+The adapter runs bootstrap data defined as a plain shell script, e.g. rendered by the kubeadm bootstrap provider
+when using the script format; like the cloud init and the Ignition adapters, it is designed to work on existing
+kind node images.
+*/
+package script
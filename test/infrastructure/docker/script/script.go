@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package script
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/cloudinit"
+)
+
+// scriptPath is the path the bootstrap script is written to before running it.
+const scriptPath = "/run/cluster-api/bootstrap.sh"
+
+// Commands converts a bootstrap script to a list of commands to run in sequence on the node;
+// the script is written to a file, so it can be inspected when debugging, and then it is run with bash.
+func Commands(scriptData []byte) ([]cloudinit.Cmd, error) {
+	script := string(scriptData)
+	if strings.TrimSpace(script) == "" {
+		return nil, errors.New("bootstrap script is empty")
+	}
+
+	return []cloudinit.Cmd{
+		{Cmd: "mkdir", Args: []string{"-p", "/run/cluster-api"}},
+		{Cmd: "/bin/sh", Args: []string{"-c", "cat > " + scriptPath + " /dev/stdin"}, Stdin: hackKubeadmIgnoreErrors(script)},
+		{Cmd: "chmod", Args: []string{"0700", scriptPath}},
+		{Cmd: "/bin/bash", Args: []string{scriptPath}},
+	}, nil
+}
+
+// hackKubeadmIgnoreErrors makes kubeadm ignore preflight errors, which is required when running in docker.
+func hackKubeadmIgnoreErrors(script string) string {
+	script = strings.Replace(script, "kubeadm init", "kubeadm init --ignore-preflight-errors=all", 1)
+	script = strings.Replace(script, "kubeadm join", "kubeadm join --ignore-preflight-errors=all", 1)
+	return script
+}
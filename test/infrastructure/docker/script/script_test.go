@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package script
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/cloudinit"
+)
+
+func TestCommands(t *testing.T) {
+	g := NewWithT(t)
+
+	script := "#!/bin/bash\nset -e\necho pre\nkubeadm join --config /run/kubeadm/kubeadm-join-config.yaml \necho post\n"
+
+	commands, err := Commands([]byte(script))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(commands).To(Equal([]cloudinit.Cmd{
+		{Cmd: "mkdir", Args: []string{"-p", "/run/cluster-api"}},
+		{Cmd: "/bin/sh", Args: []string{"-c", "cat > /run/cluster-api/bootstrap.sh /dev/stdin"}, Stdin: "#!/bin/bash\nset -e\necho pre\nkubeadm join --ignore-preflight-errors=all --config /run/kubeadm/kubeadm-join-config.yaml \necho post\n"},
+		{Cmd: "chmod", Args: []string{"0700", "/run/cluster-api/bootstrap.sh"}},
+		{Cmd: "/bin/bash", Args: []string{"/run/cluster-api/bootstrap.sh"}},
+	}))
+}
+
+func TestCommandsEmptyScript(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := Commands([]byte("\n"))
+	g.Expect(err).To(HaveOccurred())
+}
@@ -59,9 +59,12 @@ backend kube-apiservers
 `
 
 // Config returns a kubeadm config generated from config data, in particular
-// the kubernetes version
-func Config(data *ConfigData) (config string, err error) {
-	t, err := template.New("loadbalancer-config").Parse(DefaultConfigTemplate)
+// the kubernetes version; if configTemplate is empty, DefaultConfigTemplate is used.
+func Config(data *ConfigData, configTemplate string) (config string, err error) {
+	if configTemplate == "" {
+		configTemplate = DefaultConfigTemplate
+	}
+	t, err := template.New("loadbalancer-config").Parse(configTemplate)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to parse config template")
 	}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package agent implements a simulator of the agents running on the hosts of the in-memory host pool.
+package agent
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// HostAgentReconciler simulates the agents running on the InMemoryHosts: hosts are attested as soon as they are
+// registered, so they can be claimed by InMemoryMachines, and the bootstrap data of the machine which claimed a host
+// is applied by checking it is available; hosts are reset when they are released.
+type HostAgentReconciler struct {
+	client.Client
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemoryhosts,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemoryhosts/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile handles InMemoryHost events.
+func (r *HostAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the InMemoryHost instance.
+	host := &infrav1.InMemoryHost{}
+	if err := r.Client.Get(ctx, req.NamespacedName, host); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !host.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(host, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Always attempt to Patch the InMemoryHost status after each reconciliation.
+	defer func() {
+		if err := patchHelper.Patch(ctx, host, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			infrav1.HostAttestedCondition,
+			infrav1.HostBootstrappedCondition,
+		}}); err != nil {
+			log.Error(err, "failed to patch InMemoryHost")
+			if rerr == nil {
+				rerr = err
+			}
+		}
+	}()
+
+	if !r.attest(ctx, host) {
+		return ctrl.Result{}, nil
+	}
+
+	// Reset the host once it has been released.
+	if host.Spec.MachineRef == nil {
+		if host.Status.Bootstrapped {
+			log.Info("Resetting released InMemoryHost")
+		}
+		host.Status.Bootstrapped = false
+		conditions.Delete(host, infrav1.HostBootstrappedCondition)
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, r.bootstrap(ctx, host)
+}
+
+// attest attests the host, returning true if the attestation succeeded.
+func (r *HostAgentReconciler) attest(ctx context.Context, host *infrav1.InMemoryHost) bool {
+	if _, ok := host.Annotations[infrav1.AttestationFailureAnnotation]; ok {
+		host.Status.Attested = false
+		conditions.MarkFalse(host, infrav1.HostAttestedCondition, infrav1.AttestationFailedReason, clusterv1.ConditionSeverityWarning,
+			"Attestation failed as requested by the %s annotation", infrav1.AttestationFailureAnnotation)
+		return false
+	}
+
+	if !host.Status.Attested {
+		ctrl.LoggerFrom(ctx).Info("Attesting InMemoryHost")
+		host.Status.Attested = true
+		host.Status.Addresses = []clusterv1.MachineAddress{
+			{
+				Type:    clusterv1.MachineHostName,
+				Address: host.Name,
+			},
+		}
+	}
+	conditions.MarkTrue(host, infrav1.HostAttestedCondition)
+	return true
+}
+
+// bootstrap applies the bootstrap data handed over by the machine which claimed the host.
+func (r *HostAgentReconciler) bootstrap(ctx context.Context, host *infrav1.InMemoryHost) error {
+	if host.Spec.BootstrapSecret == nil || host.Status.Bootstrapped {
+		return nil
+	}
+
+	if _, ok := host.Annotations[infrav1.BootstrapFailureAnnotation]; ok {
+		conditions.MarkFalse(host, infrav1.HostBootstrappedCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning,
+			"Bootstrap failed as requested by the %s annotation", infrav1.BootstrapFailureAnnotation)
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: host.Spec.BootstrapSecret.Namespace, Name: host.Spec.BootstrapSecret.Name}
+	if key.Namespace == "" {
+		key.Namespace = host.Namespace
+	}
+	if err := r.Client.Get(ctx, key, secret); err != nil {
+		conditions.MarkFalse(host, infrav1.HostBootstrappedCondition, infrav1.BootstrappingReason, clusterv1.ConditionSeverityInfo, "")
+		return errors.Wrapf(err, "failed to get the bootstrap data secret %s", key.Name)
+	}
+	if len(secret.Data["value"]) == 0 {
+		conditions.MarkFalse(host, infrav1.HostBootstrappedCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning,
+			"The bootstrap data secret %s does not contain the value key", key.Name)
+		return nil
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Bootstrapped InMemoryHost", "secret", key.Name)
+	host.Status.Bootstrapped = true
+	conditions.MarkTrue(host, infrav1.HostBootstrappedCondition)
+	return nil
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *HostAgentReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.InMemoryHost{}).
+		WithOptions(options).
+		Complete(r)
+}
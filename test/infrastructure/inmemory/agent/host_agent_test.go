@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	utilruntime.Must(infrav1.AddToScheme(scheme.Scheme))
+}
+
+func TestHostAgentReconciler(t *testing.T) {
+	bootstrapSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-machine-bootstrap", Namespace: metav1.NamespaceDefault},
+		Data:       map[string][]byte{"value": []byte("#cloud-config")},
+	}
+	emptySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty-bootstrap", Namespace: metav1.NamespaceDefault},
+	}
+	machineRef := &corev1.ObjectReference{Namespace: metav1.NamespaceDefault, Name: "my-machine"}
+
+	tests := []struct {
+		name                      string
+		host                      *infrav1.InMemoryHost
+		wantErr                   bool
+		wantAttested              bool
+		wantBootstrapped          bool
+		wantAttestedReason        string
+		wantBootstrappedCondition *bool
+		wantBootstrapReason       string
+	}{
+		{
+			name:         "attests new hosts",
+			host:         newHost(nil, nil),
+			wantAttested: true,
+		},
+		{
+			name: "fails the attestation if requested",
+			host: func() *infrav1.InMemoryHost {
+				h := newHost(nil, nil)
+				h.Annotations = map[string]string{infrav1.AttestationFailureAnnotation: ""}
+				return h
+			}(),
+			wantAttestedReason: infrav1.AttestationFailedReason,
+		},
+		{
+			name:                      "bootstraps claimed hosts",
+			host:                      newHost(machineRef, bootstrapSecret),
+			wantAttested:              true,
+			wantBootstrapped:          true,
+			wantBootstrappedCondition: pointer.Bool(true),
+		},
+		{
+			name: "fails the bootstrap if requested",
+			host: func() *infrav1.InMemoryHost {
+				h := newHost(machineRef, bootstrapSecret)
+				h.Annotations = map[string]string{infrav1.BootstrapFailureAnnotation: ""}
+				return h
+			}(),
+			wantAttested:              true,
+			wantBootstrappedCondition: pointer.Bool(false),
+			wantBootstrapReason:       infrav1.BootstrapFailedReason,
+		},
+		{
+			name:                      "fails the bootstrap if the bootstrap data is empty",
+			host:                      newHost(machineRef, emptySecret),
+			wantAttested:              true,
+			wantBootstrappedCondition: pointer.Bool(false),
+			wantBootstrapReason:       infrav1.BootstrapFailedReason,
+		},
+		{
+			name: "waits for the bootstrap data secret",
+			host: newHost(machineRef, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "does-not-exist", Namespace: metav1.NamespaceDefault},
+			}),
+			wantErr:                   true,
+			wantAttested:              true,
+			wantBootstrappedCondition: pointer.Bool(false),
+			wantBootstrapReason:       infrav1.BootstrappingReason,
+		},
+		{
+			name: "resets released hosts",
+			host: func() *infrav1.InMemoryHost {
+				h := newHost(nil, nil)
+				h.Status.Attested = true
+				h.Status.Addresses = []clusterv1.MachineAddress{{Type: clusterv1.MachineHostName, Address: h.Name}}
+				h.Status.Bootstrapped = true
+				conditions.MarkTrue(h, infrav1.HostBootstrappedCondition)
+				return h
+			}(),
+			wantAttested: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &HostAgentReconciler{
+				Client: fake.NewClientBuilder().WithObjects(tt.host, bootstrapSecret, emptySecret).Build(),
+			}
+
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tt.host)})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			host := &infrav1.InMemoryHost{}
+			g.Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(tt.host), host)).To(Succeed())
+			g.Expect(host.Status.Attested).To(Equal(tt.wantAttested))
+			g.Expect(host.Status.Bootstrapped).To(Equal(tt.wantBootstrapped))
+			if tt.wantAttested {
+				g.Expect(conditions.IsTrue(host, infrav1.HostAttestedCondition)).To(BeTrue())
+				g.Expect(host.Status.Addresses).To(ConsistOf(clusterv1.MachineAddress{Type: clusterv1.MachineHostName, Address: host.Name}))
+			} else {
+				g.Expect(conditions.GetReason(host, infrav1.HostAttestedCondition)).To(Equal(tt.wantAttestedReason))
+			}
+			if tt.wantBootstrappedCondition == nil {
+				g.Expect(conditions.Has(host, infrav1.HostBootstrappedCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(conditions.IsTrue(host, infrav1.HostBootstrappedCondition)).To(Equal(*tt.wantBootstrappedCondition))
+			g.Expect(conditions.GetReason(host, infrav1.HostBootstrappedCondition)).To(Equal(tt.wantBootstrapReason))
+		})
+	}
+}
+
+func newHost(machineRef *corev1.ObjectReference, bootstrapSecret *corev1.Secret) *infrav1.InMemoryHost {
+	host := &infrav1.InMemoryHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "host-0",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: infrav1.InMemoryHostSpec{
+			MachineRef: machineRef,
+		},
+	}
+	if bootstrapSecret != nil {
+		host.Spec.BootstrapSecret = &corev1.ObjectReference{Namespace: bootstrapSecret.Namespace, Name: bootstrapSecret.Name}
+	}
+	return host
+}
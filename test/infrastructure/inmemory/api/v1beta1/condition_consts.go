@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+// Conditions and condition Reasons for the InMemoryMachine object
+
+const (
+	// HostClaimedCondition documents the status of the claim of an InMemoryHost by an InMemoryMachine.
+	HostClaimedCondition clusterv1.ConditionType = "HostClaimed"
+
+	// WaitingForBootstrapDataReason (Severity=Info) documents an InMemoryMachine waiting for the bootstrap
+	// data to be ready before claiming a host.
+	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+
+	// WaitingForHostReason (Severity=Info) documents an InMemoryMachine waiting for an attested InMemoryHost
+	// matching its selector to be available.
+	WaitingForHostReason = "WaitingForHost"
+
+	// HostClaimConflictReason (Severity=Warning) documents an InMemoryMachine whose host has been claimed by
+	// another machine; this requires manual intervention.
+	HostClaimConflictReason = "HostClaimConflict"
+)
+
+const (
+	// HostBootstrappedCondition documents the status of the bootstrap of an InMemoryHost by the host agent;
+	// the condition is reported both on the InMemoryHost and on the InMemoryMachine which claimed it.
+	HostBootstrappedCondition clusterv1.ConditionType = "HostBootstrapped"
+
+	// BootstrappingReason (Severity=Info) documents an InMemoryMachine waiting for the host agent to apply
+	// the bootstrap data to the host.
+	BootstrappingReason = "Bootstrapping"
+
+	// BootstrapFailedReason (Severity=Warning) documents the host agent failing to apply the bootstrap data
+	// to the host.
+	BootstrapFailedReason = "BootstrapFailed"
+)
+
+// Conditions and condition Reasons for the InMemoryHost object
+
+const (
+	// HostAttestedCondition documents the attestation of an InMemoryHost by the host agent; only attested hosts
+	// can be claimed by InMemoryMachines.
+	HostAttestedCondition clusterv1.ConditionType = "HostAttested"
+
+	// AttestationFailedReason (Severity=Warning) documents the host agent failing to attest the host.
+	AttestationFailedReason = "AttestationFailed"
+)
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// InMemoryClusterSpec defines the desired state of InMemoryCluster.
+type InMemoryClusterSpec struct {
+	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
+	// There is no load balancer for in-memory clusters, so the endpoint must be provided by the user,
+	// e.g. the address of the first control plane host.
+	// +optional
+	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+}
+
+// InMemoryClusterStatus defines the observed state of InMemoryCluster.
+type InMemoryClusterStatus struct {
+	// Ready denotes that the in-memory cluster (infrastructure) is ready.
+	// +optional
+	Ready bool `json:"ready"`
+}
+
+// +kubebuilder:resource:path=inmemoryclusters,scope=Namespaced,categories=cluster-api
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of InMemoryCluster"
+
+// InMemoryCluster is the Schema for the inmemoryclusters API.
+type InMemoryCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InMemoryClusterSpec   `json:"spec,omitempty"`
+	Status InMemoryClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// InMemoryClusterList contains a list of InMemoryCluster.
+type InMemoryClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InMemoryCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InMemoryCluster{}, &InMemoryClusterList{})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// AttestationFailureAnnotation makes the host agent fail the attestation of an InMemoryHost, so failure
+	// flows can be tested.
+	AttestationFailureAnnotation = "inmemoryhost.infrastructure.cluster.x-k8s.io/fail-attestation"
+
+	// BootstrapFailureAnnotation makes the host agent fail the bootstrap of an InMemoryHost, so failure
+	// flows can be tested.
+	BootstrapFailureAnnotation = "inmemoryhost.infrastructure.cluster.x-k8s.io/fail-bootstrap"
+)
+
+// InMemoryHostSpec defines the desired state of InMemoryHost.
+type InMemoryHostSpec struct {
+	// MachineRef references the InMemoryMachine which claimed the host. It is set by the InMemoryMachine
+	// controller with an optimistic lock, so a host can be claimed only by one machine, and it is removed
+	// when the machine is deleted, releasing the host.
+	// +optional
+	MachineRef *corev1.ObjectReference `json:"machineRef,omitempty"`
+
+	// BootstrapSecret references the secret with the bootstrap data of the machine which claimed the host;
+	// the host agent applies the bootstrap data to the host.
+	// +optional
+	BootstrapSecret *corev1.ObjectReference `json:"bootstrapSecret,omitempty"`
+}
+
+// InMemoryHostStatus defines the observed state of InMemoryHost.
+type InMemoryHostStatus struct {
+	// Attested denotes that the host has been attested by the host agent, so it can be claimed by machines.
+	// +optional
+	Attested bool `json:"attested,omitempty"`
+
+	// Bootstrapped denotes that the host agent applied the bootstrap data to the host.
+	// +optional
+	Bootstrapped bool `json:"bootstrapped,omitempty"`
+
+	// Addresses contains the addresses of the host, as reported by the host agent.
+	// +optional
+	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
+
+	// Conditions defines current service state of the InMemoryHost.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:resource:path=inmemoryhosts,scope=Namespaced,categories=cluster-api
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".spec.machineRef.name",description="InMemoryMachine which claimed the host"
+// +kubebuilder:printcolumn:name="Attested",type="boolean",JSONPath=".status.attested",description="Host attested by the host agent"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of InMemoryHost"
+
+// InMemoryHost is the Schema for the inmemoryhosts API.
+type InMemoryHost struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InMemoryHostSpec   `json:"spec,omitempty"`
+	Status InMemoryHostStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (c *InMemoryHost) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (c *InMemoryHost) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// InMemoryHostList contains a list of InMemoryHost.
+type InMemoryHostList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InMemoryHost `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InMemoryHost{}, &InMemoryHostList{})
+}
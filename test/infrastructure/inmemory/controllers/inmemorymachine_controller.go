@@ -0,0 +1,411 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllers implements the controllers of the in-memory host pool infrastructure provider.
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	logutil "sigs.k8s.io/cluster-api/util/log"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// InMemoryMachineReconciler reconciles an InMemoryMachine object, claiming an InMemoryHost for it.
+type InMemoryMachineReconciler struct {
+	client.Client
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemorymachines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemorymachines/status;inmemorymachines/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=inmemoryhosts,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machines,verbs=get;list;watch
+
+// Reconcile handles InMemoryMachine events.
+func (r *InMemoryMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the InMemoryMachine instance.
+	inMemoryMachine := &infrav1.InMemoryMachine{}
+	if err := r.Client.Get(ctx, req.NamespacedName, inMemoryMachine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Fetch the Machine.
+	machine, err := util.GetOwnerMachine(ctx, r.Client, inMemoryMachine.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machine == nil {
+		log.Info("Waiting for Machine Controller to set OwnerRef on InMemoryMachine")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues(logutil.MachineKey, logutil.KObj(machine))
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
+	if err != nil {
+		log.Info("InMemoryMachine owner Machine is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, err
+	}
+	if cluster == nil {
+		log.Info(fmt.Sprintf("Please associate this machine with a cluster using the label %s: <name of cluster>", clusterv1.ClusterLabelName))
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues(logutil.ClusterKey, logutil.KObj(cluster))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, inMemoryMachine) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(inMemoryMachine, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Always attempt to Patch the InMemoryMachine object and status after each reconciliation.
+	defer func() {
+		if err := patchInMemoryMachine(ctx, patchHelper, inMemoryMachine); err != nil {
+			log.Error(err, "failed to patch InMemoryMachine")
+			if rerr == nil {
+				rerr = err
+			}
+		}
+	}()
+
+	// Handle deleted machines
+	if !inMemoryMachine.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, inMemoryMachine)
+	}
+
+	// Add finalizer first if not exist to avoid the race condition between init and delete
+	if !controllerutil.ContainsFinalizer(inMemoryMachine, infrav1.MachineFinalizer) {
+		controllerutil.AddFinalizer(inMemoryMachine, infrav1.MachineFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	// Handle non-deleted machines
+	return r.reconcileNormal(ctx, cluster, machine, inMemoryMachine)
+}
+
+func patchInMemoryMachine(ctx context.Context, patchHelper *patch.Helper, inMemoryMachine *infrav1.InMemoryMachine) error {
+	// Always update the readyCondition by summarizing the state of other conditions.
+	conditions.SetSummary(inMemoryMachine,
+		conditions.WithConditions(
+			infrav1.HostClaimedCondition,
+			infrav1.HostBootstrappedCondition,
+		),
+		conditions.WithStepCounterIf(inMemoryMachine.ObjectMeta.DeletionTimestamp.IsZero()),
+	)
+
+	// Patch the object, ignoring conflicts on the conditions owned by this controller.
+	return patchHelper.Patch(
+		ctx,
+		inMemoryMachine,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.HostClaimedCondition,
+			infrav1.HostBootstrappedCondition,
+		}},
+	)
+}
+
+func (r *InMemoryMachineReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, inMemoryMachine *infrav1.InMemoryMachine) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// if the machine is already provisioned, return
+	if inMemoryMachine.Spec.ProviderID != nil {
+		// ensure ready state is set.
+		// This is required after move, because status is not moved to the target cluster.
+		inMemoryMachine.Status.Ready = true
+		return ctrl.Result{}, nil
+	}
+
+	// Check if the infrastructure is ready, otherwise return and wait for the cluster object to be updated
+	if !cluster.Status.InfrastructureReady {
+		log.Info("Waiting for InMemoryCluster Controller to create cluster infrastructure")
+		return ctrl.Result{}, nil
+	}
+
+	// Make sure bootstrap data is available and populated before claiming a host.
+	if machine.Spec.Bootstrap.DataSecretName == nil {
+		log.Info("Waiting for the Bootstrap provider controller to set bootstrap data")
+		conditions.MarkFalse(inMemoryMachine, infrav1.HostClaimedCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{}, nil
+	}
+
+	host, err := r.claimHost(ctx, inMemoryMachine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if host == nil {
+		log.Info("Waiting for an InMemoryHost to be available")
+		conditions.MarkFalse(inMemoryMachine, infrav1.HostClaimedCondition, infrav1.WaitingForHostReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{}, nil
+	}
+	if !isClaimedBy(host, inMemoryMachine) {
+		conditions.MarkFalse(inMemoryMachine, infrav1.HostClaimedCondition, infrav1.HostClaimConflictReason, clusterv1.ConditionSeverityWarning,
+			"InMemoryHost %s has been claimed by another machine", host.Name)
+		return ctrl.Result{}, nil
+	}
+	conditions.MarkTrue(inMemoryMachine, infrav1.HostClaimedCondition)
+
+	// Hand over the bootstrap data to the host agent.
+	if host.Spec.BootstrapSecret == nil {
+		hostPatch := client.MergeFrom(host.DeepCopy())
+		host.Spec.BootstrapSecret = &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Namespace:  machine.Namespace,
+			Name:       *machine.Spec.Bootstrap.DataSecretName,
+		}
+		if err := r.Client.Patch(ctx, host, hostPatch); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to set the bootstrap secret on InMemoryHost %s", host.Name)
+		}
+	}
+
+	// Wait for the host agent to bootstrap the host.
+	if !host.Status.Bootstrapped {
+		if conditions.GetReason(host, infrav1.HostBootstrappedCondition) == infrav1.BootstrapFailedReason {
+			conditions.MarkFalse(inMemoryMachine, infrav1.HostBootstrappedCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning,
+				"%s", conditions.GetMessage(host, infrav1.HostBootstrappedCondition))
+			return ctrl.Result{}, nil
+		}
+		log.Info("Waiting for the host agent to bootstrap the InMemoryHost", "host", host.Name)
+		conditions.MarkFalse(inMemoryMachine, infrav1.HostBootstrappedCondition, infrav1.BootstrappingReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{}, nil
+	}
+	conditions.MarkTrue(inMemoryMachine, infrav1.HostBootstrappedCondition)
+
+	// Set ProviderID so the Cluster API Machine Controller can pull it
+	providerID := fmt.Sprintf("inmemory:///%s/%s", host.Namespace, host.Name)
+	inMemoryMachine.Spec.ProviderID = &providerID
+	inMemoryMachine.Status.Addresses = host.Status.Addresses
+	inMemoryMachine.Status.Ready = true
+
+	return ctrl.Result{}, nil
+}
+
+// claimHost returns the InMemoryHost claimed by the machine, claiming an attested host matching the selector of the
+// machine if required; nil is returned if there are no hosts available.
+func (r *InMemoryMachineReconciler) claimHost(ctx context.Context, inMemoryMachine *infrav1.InMemoryMachine) (*infrav1.InMemoryHost, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	host, err := r.getHost(ctx, inMemoryMachine)
+	if err != nil {
+		return nil, err
+	}
+	if host != nil {
+		inMemoryMachine.Status.HostRef = hostRef(host)
+		return host, nil
+	}
+
+	selector := labels.Everything()
+	if inMemoryMachine.Spec.Selector != nil {
+		selector, err = metav1.LabelSelectorAsSelector(inMemoryMachine.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the InMemoryMachine selector")
+		}
+	}
+
+	hostList := &infrav1.InMemoryHostList{}
+	if err := r.Client.List(ctx, hostList, client.InNamespace(inMemoryMachine.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, errors.Wrap(err, "failed to list InMemoryHosts")
+	}
+	hosts := hostList.Items
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Name < hosts[j].Name })
+
+	for i := range hosts {
+		host := &hosts[i]
+		if !host.Status.Attested || host.Spec.MachineRef != nil || !host.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		// Claim the host with an optimistic lock, so if another machine claimed the host in the meantime
+		// the patch fails and the next host is tried.
+		hostPatch := client.MergeFromWithOptions(host.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		host.Spec.MachineRef = &corev1.ObjectReference{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "InMemoryMachine",
+			Namespace:  inMemoryMachine.Namespace,
+			Name:       inMemoryMachine.Name,
+			UID:        inMemoryMachine.UID,
+		}
+		if err := r.Client.Patch(ctx, host, hostPatch); err != nil {
+			if apierrors.IsConflict(err) {
+				log.V(4).Info("InMemoryHost has been claimed by another machine, trying the next one", "host", host.Name)
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to claim InMemoryHost %s", host.Name)
+		}
+
+		log.Info("Claimed InMemoryHost", "host", host.Name)
+		inMemoryMachine.Status.HostRef = hostRef(host)
+		return host, nil
+	}
+	return nil, nil
+}
+
+func (r *InMemoryMachineReconciler) reconcileDelete(ctx context.Context, inMemoryMachine *infrav1.InMemoryMachine) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Release the host, so it is available to other machines; the host agent takes care of resetting it.
+	host, err := r.getHost(ctx, inMemoryMachine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if host != nil && isClaimedBy(host, inMemoryMachine) {
+		hostPatch := client.MergeFrom(host.DeepCopy())
+		host.Spec.MachineRef = nil
+		host.Spec.BootstrapSecret = nil
+		if err := r.Client.Patch(ctx, host, hostPatch); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to release InMemoryHost %s", host.Name)
+		}
+		log.Info("Released InMemoryHost", "host", host.Name)
+	}
+
+	// Machine is deleted so remove the finalizer.
+	controllerutil.RemoveFinalizer(inMemoryMachine, infrav1.MachineFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// getHost returns the InMemoryHost of the machine, if any. If the host is not recorded in status, e.g. because the claim
+// succeeded but the status was not patched, or after move, the host claimed by the machine is looked up.
+func (r *InMemoryMachineReconciler) getHost(ctx context.Context, inMemoryMachine *infrav1.InMemoryMachine) (*infrav1.InMemoryHost, error) {
+	if inMemoryMachine.Status.HostRef != nil {
+		host := &infrav1.InMemoryHost{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: inMemoryMachine.Namespace, Name: inMemoryMachine.Status.HostRef.Name}, host); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, "failed to get InMemoryHost %s", inMemoryMachine.Status.HostRef.Name)
+		}
+		return host, nil
+	}
+
+	hostList := &infrav1.InMemoryHostList{}
+	if err := r.Client.List(ctx, hostList, client.InNamespace(inMemoryMachine.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list InMemoryHosts")
+	}
+	for i := range hostList.Items {
+		if isClaimedBy(&hostList.Items[i], inMemoryMachine) {
+			return &hostList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// isClaimedBy returns true if the host has been claimed by the given machine.
+// NOTE: the UID of the machine is not compared, because it changes when the machine is moved to another
+// management cluster.
+func isClaimedBy(host *infrav1.InMemoryHost, inMemoryMachine *infrav1.InMemoryMachine) bool {
+	return host.Spec.MachineRef != nil &&
+		host.Spec.MachineRef.Namespace == inMemoryMachine.Namespace &&
+		host.Spec.MachineRef.Name == inMemoryMachine.Name
+}
+
+func hostRef(host *infrav1.InMemoryHost) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: infrav1.GroupVersion.String(),
+		Kind:       "InMemoryHost",
+		Namespace:  host.Namespace,
+		Name:       host.Name,
+	}
+}
+
+// SetupWithManager will add watches for this controller.
+func (r *InMemoryMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	clusterToInMemoryMachines, err := util.ClusterToObjectsMapper(mgr.GetClient(), &infrav1.InMemoryMachineList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.InMemoryMachine{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(ctx))).
+		Watches(
+			&source.Kind{Type: &clusterv1.Machine{}},
+			handler.EnqueueRequestsFromMapFunc(util.MachineToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("InMemoryMachine"))),
+		).
+		Watches(
+			&source.Kind{Type: &infrav1.InMemoryHost{}},
+			handler.EnqueueRequestsFromMapFunc(r.InMemoryHostToInMemoryMachines),
+		).
+		Build(r)
+	if err != nil {
+		return err
+	}
+	return c.Watch(
+		&source.Kind{Type: &clusterv1.Cluster{}},
+		handler.EnqueueRequestsFromMapFunc(clusterToInMemoryMachines),
+		predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+	)
+}
+
+// InMemoryHostToInMemoryMachines is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// of InMemoryMachines; the machine which claimed the host is enqueued, or the machines still waiting for a host
+// if the host is available.
+func (r *InMemoryMachineReconciler) InMemoryHostToInMemoryMachines(o client.Object) []ctrl.Request {
+	h, ok := o.(*infrav1.InMemoryHost)
+	if !ok {
+		panic(fmt.Sprintf("Expected an InMemoryHost but got a %T", o))
+	}
+
+	if h.Spec.MachineRef != nil {
+		return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: h.Namespace, Name: h.Spec.MachineRef.Name}}}
+	}
+
+	machineList := &infrav1.InMemoryMachineList{}
+	if err := r.Client.List(context.TODO(), machineList, client.InNamespace(h.Namespace)); err != nil {
+		return nil
+	}
+	result := []ctrl.Request{}
+	for _, m := range machineList.Items {
+		if m.Status.HostRef != nil {
+			continue
+		}
+		result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: m.Namespace, Name: m.Name}})
+	}
+	return result
+}
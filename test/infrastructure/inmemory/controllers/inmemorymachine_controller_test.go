@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var cluster = &clusterv1.Cluster{
+	ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: metav1.NamespaceDefault},
+	Status:     clusterv1.ClusterStatus{InfrastructureReady: true},
+}
+
+func TestInMemoryMachineReconciler_ReconcileNormalClaimsHost(t *testing.T) {
+	g := NewWithT(t)
+
+	inMemoryMachine := newInMemoryMachine("my-machine")
+	inMemoryMachine.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "a"}}
+	machine := newMachine("my-machine", pointer.String("my-machine-bootstrap"))
+
+	notAttested := newHost("host-0", "a", false)
+	claimed := newHost("host-1", "a", true)
+	claimed.Spec.MachineRef = &corev1.ObjectReference{Namespace: metav1.NamespaceDefault, Name: "another-machine"}
+	otherRack := newHost("host-2", "b", true)
+	available := newHost("host-3", "a", true)
+	available.Status.Addresses = []clusterv1.MachineAddress{{Type: clusterv1.MachineHostName, Address: "host-3"}}
+
+	r := InMemoryMachineReconciler{
+		Client: fake.NewClientBuilder().WithObjects(inMemoryMachine, machine, notAttested, claimed, otherRack, available).Build(),
+	}
+
+	// The first attested host matching the selector which is not claimed by other machines is claimed, and the
+	// bootstrap data is handed over to the host agent.
+	_, err := r.reconcileNormal(context.Background(), cluster, machine, inMemoryMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inMemoryMachine.Status.HostRef).ToNot(BeNil())
+	g.Expect(inMemoryMachine.Status.HostRef.Name).To(Equal("host-3"))
+	g.Expect(conditions.IsTrue(inMemoryMachine, infrav1.HostClaimedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(inMemoryMachine, infrav1.HostBootstrappedCondition)).To(Equal(infrav1.BootstrappingReason))
+	g.Expect(inMemoryMachine.Status.Ready).To(BeFalse())
+
+	host := &infrav1.InMemoryHost{}
+	g.Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(available), host)).To(Succeed())
+	g.Expect(host.Spec.MachineRef).ToNot(BeNil())
+	g.Expect(host.Spec.MachineRef.Name).To(Equal("my-machine"))
+	g.Expect(host.Spec.BootstrapSecret).ToNot(BeNil())
+	g.Expect(host.Spec.BootstrapSecret.Name).To(Equal("my-machine-bootstrap"))
+
+	// The machine is ready once the host agent bootstrapped the host.
+	host.Status.Bootstrapped = true
+	g.Expect(r.Client.Update(context.Background(), host)).To(Succeed())
+
+	_, err = r.reconcileNormal(context.Background(), cluster, machine, inMemoryMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsTrue(inMemoryMachine, infrav1.HostBootstrappedCondition)).To(BeTrue())
+	g.Expect(inMemoryMachine.Spec.ProviderID).To(Equal(pointer.String("inmemory:///default/host-3")))
+	g.Expect(inMemoryMachine.Status.Addresses).To(Equal(available.Status.Addresses))
+	g.Expect(inMemoryMachine.Status.Ready).To(BeTrue())
+}
+
+func TestInMemoryMachineReconciler_ReconcileNormalWaits(t *testing.T) {
+	g := NewWithT(t)
+
+	inMemoryMachine := newInMemoryMachine("my-machine")
+	machine := newMachine("my-machine", nil)
+
+	r := InMemoryMachineReconciler{
+		Client: fake.NewClientBuilder().WithObjects(inMemoryMachine, machine, newHost("host-0", "a", false)).Build(),
+	}
+
+	// Hosts are claimed only once the bootstrap data is available.
+	_, err := r.reconcileNormal(context.Background(), cluster, machine, inMemoryMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.GetReason(inMemoryMachine, infrav1.HostClaimedCondition)).To(Equal(infrav1.WaitingForBootstrapDataReason))
+
+	// Hosts which are not attested cannot be claimed.
+	machine.Spec.Bootstrap.DataSecretName = pointer.String("my-machine-bootstrap")
+	_, err = r.reconcileNormal(context.Background(), cluster, machine, inMemoryMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inMemoryMachine.Status.HostRef).To(BeNil())
+	g.Expect(conditions.GetReason(inMemoryMachine, infrav1.HostClaimedCondition)).To(Equal(infrav1.WaitingForHostReason))
+}
+
+func TestInMemoryMachineReconciler_ReconcileNormalReportsBootstrapFailures(t *testing.T) {
+	g := NewWithT(t)
+
+	inMemoryMachine := newInMemoryMachine("my-machine")
+	machine := newMachine("my-machine", pointer.String("my-machine-bootstrap"))
+
+	host := newHost("host-0", "a", true)
+	host.Spec.MachineRef = &corev1.ObjectReference{Namespace: metav1.NamespaceDefault, Name: "my-machine"}
+	conditions.MarkFalse(host, infrav1.HostBootstrappedCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityWarning, "bootstrap failed")
+
+	r := InMemoryMachineReconciler{
+		Client: fake.NewClientBuilder().WithObjects(inMemoryMachine, machine, host).Build(),
+	}
+
+	// The host already claimed by the machine is adopted, and the bootstrap failure reported by the host agent is surfaced.
+	_, err := r.reconcileNormal(context.Background(), cluster, machine, inMemoryMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inMemoryMachine.Status.HostRef).ToNot(BeNil())
+	g.Expect(inMemoryMachine.Status.HostRef.Name).To(Equal("host-0"))
+	g.Expect(conditions.GetReason(inMemoryMachine, infrav1.HostBootstrappedCondition)).To(Equal(infrav1.BootstrapFailedReason))
+	g.Expect(conditions.GetMessage(inMemoryMachine, infrav1.HostBootstrappedCondition)).To(Equal("bootstrap failed"))
+	g.Expect(inMemoryMachine.Status.Ready).To(BeFalse())
+}
+
+func TestInMemoryMachineReconciler_ReconcileDeleteReleasesHost(t *testing.T) {
+	g := NewWithT(t)
+
+	inMemoryMachine := newInMemoryMachine("my-machine")
+	inMemoryMachine.Finalizers = []string{infrav1.MachineFinalizer}
+
+	host := newHost("host-0", "a", true)
+	host.Spec.MachineRef = &corev1.ObjectReference{Namespace: metav1.NamespaceDefault, Name: "my-machine"}
+	host.Spec.BootstrapSecret = &corev1.ObjectReference{Namespace: metav1.NamespaceDefault, Name: "my-machine-bootstrap"}
+	otherHost := newHost("host-1", "a", true)
+	otherHost.Spec.MachineRef = &corev1.ObjectReference{Namespace: metav1.NamespaceDefault, Name: "another-machine"}
+
+	r := InMemoryMachineReconciler{
+		Client: fake.NewClientBuilder().WithObjects(inMemoryMachine, host, otherHost).Build(),
+	}
+
+	// The host claimed by the machine is released, even if it is not recorded in status.
+	_, err := r.reconcileDelete(context.Background(), inMemoryMachine)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(inMemoryMachine.Finalizers).To(BeEmpty())
+
+	releasedHost := &infrav1.InMemoryHost{}
+	g.Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(host), releasedHost)).To(Succeed())
+	g.Expect(releasedHost.Spec.MachineRef).To(BeNil())
+	g.Expect(releasedHost.Spec.BootstrapSecret).To(BeNil())
+
+	claimedHost := &infrav1.InMemoryHost{}
+	g.Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(otherHost), claimedHost)).To(Succeed())
+	g.Expect(claimedHost.Spec.MachineRef).ToNot(BeNil())
+}
+
+func newInMemoryMachine(name string) *infrav1.InMemoryMachine {
+	return &infrav1.InMemoryMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+}
+
+func newMachine(name string, dataSecretName *string) *clusterv1.Machine {
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+			Bootstrap: clusterv1.Bootstrap{
+				DataSecretName: dataSecretName,
+			},
+		},
+	}
+}
+
+func newHost(name, rack string, attested bool) *infrav1.InMemoryHost {
+	return &infrav1.InMemoryHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{"rack": rack},
+		},
+		Status: infrav1.InMemoryHostStatus{
+			Attested: attested,
+		},
+	}
+}
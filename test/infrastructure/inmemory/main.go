@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/klogr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/agent"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/inmemory/api/v1beta1"
+	"sigs.k8s.io/cluster-api/test/infrastructure/inmemory/controllers"
+)
+
+var (
+	myscheme = runtime.NewScheme()
+	setupLog = ctrl.Log.WithName("setup")
+
+	// flags.
+	metricsBindAddr      string
+	enableLeaderElection bool
+	syncPeriod           time.Duration
+	concurrency          int
+	healthAddr           string
+	enableHostAgent      bool
+)
+
+func init() {
+	klog.InitFlags(nil)
+
+	_ = scheme.AddToScheme(myscheme)
+	_ = infrav1.AddToScheme(myscheme)
+	_ = clusterv1.AddToScheme(myscheme)
+}
+
+func initFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&metricsBindAddr, "metrics-bind-addr", "localhost:8080",
+		"The address the metric endpoint binds to.")
+	fs.IntVar(&concurrency, "concurrency", 10,
+		"The number of in-memory machines to process simultaneously")
+	fs.BoolVar(&enableLeaderElection, "leader-elect", false,
+		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
+		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
+	fs.StringVar(&healthAddr, "health-addr", ":9440",
+		"The address the health endpoint binds to.")
+	fs.BoolVar(&enableHostAgent, "enable-host-agent", true,
+		"Run the host agent simulator, attesting and bootstrapping the in-memory hosts. Disable it to attest and bootstrap the hosts by other means, e.g. from tests.")
+}
+
+func main() {
+	initFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.CommandLine.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	pflag.Parse()
+
+	ctrl.SetLogger(klogr.New())
+
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.UserAgent = remote.DefaultClusterAPIUserAgent("cluster-api-inmemory-controller-manager")
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme:                 myscheme,
+		MetricsBindAddress:     metricsBindAddr,
+		LeaderElection:         enableLeaderElection,
+		LeaderElectionID:       "controller-leader-election-capim",
+		SyncPeriod:             &syncPeriod,
+		HealthProbeBindAddress: healthAddr,
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	// Setup the context that's going to be used in controllers and for the manager.
+	ctx := ctrl.SetupSignalHandler()
+
+	setupChecks(mgr)
+	setupReconcilers(ctx, mgr)
+
+	setupLog.Info("starting manager")
+	if err := mgr.Start(ctx); err != nil {
+		setupLog.Error(err, "problem running manager")
+		os.Exit(1)
+	}
+}
+
+func setupChecks(mgr ctrl.Manager) {
+	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to create ready check")
+		os.Exit(1)
+	}
+
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to create health check")
+		os.Exit(1)
+	}
+}
+
+func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
+	if err := (&controllers.InMemoryMachineReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(ctx, mgr, controller.Options{
+		MaxConcurrentReconciles: concurrency,
+	}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "InMemoryMachine")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.InMemoryClusterReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "InMemoryCluster")
+		os.Exit(1)
+	}
+
+	if enableHostAgent {
+		if err := (&agent.HostAgentReconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr, controller.Options{
+			MaxConcurrentReconciles: concurrency,
+		}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "HostAgent")
+			os.Exit(1)
+		}
+	}
+}
@@ -54,6 +54,10 @@ type Helper struct {
 	Selector            string
 	PodSelector         string
 
+	// ExcludedPodSelector, if set, skips the pods matching the selector, so they are not
+	// evicted/deleted when draining the node
+	ExcludedPodSelector labels.Selector
+
 	// DisableEviction forces drain to use delete rather than evict
 	DisableEviction bool
 
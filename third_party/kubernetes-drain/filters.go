@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -139,6 +140,7 @@ func makePodDeleteStatusWithError(message string) podDeleteStatus {
 // message will be retained if there are any warnings.
 func (d *Helper) makeFilters() []podFilter {
 	return []podFilter{
+		d.excludedPodFilter,
 		d.skipDeletedFilter,
 		d.daemonSetFilter,
 		d.mirrorPodFilter,
@@ -189,6 +191,13 @@ func (d *Helper) daemonSetFilter(pod corev1.Pod) podDeleteStatus {
 	return makePodDeleteStatusWithWarning(false, daemonSetWarning)
 }
 
+func (d *Helper) excludedPodFilter(pod corev1.Pod) podDeleteStatus {
+	if d.ExcludedPodSelector != nil && d.ExcludedPodSelector.Matches(labels.Set(pod.Labels)) {
+		return makePodDeleteStatusSkip()
+	}
+	return makePodDeleteStatusOkay()
+}
+
 func (d *Helper) mirrorPodFilter(pod corev1.Pod) podDeleteStatus {
 	if _, found := pod.ObjectMeta.Annotations[corev1.MirrorPodAnnotationKey]; found {
 		return makePodDeleteStatusSkip()
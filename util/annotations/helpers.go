@@ -42,6 +42,41 @@ func HasPausedAnnotation(o metav1.Object) bool {
 	return hasAnnotation(o, clusterv1.PausedAnnotation)
 }
 
+// AddPausedFromCluster sets the `paused` annotation on the object, marking it as propagated from the Cluster.
+// If the object already has a `paused` annotation set by users it is left untouched.
+// Returns true if the annotations have changed.
+func AddPausedFromCluster(o metav1.Object) bool {
+	if HasPausedAnnotation(o) {
+		return false
+	}
+	return AddAnnotations(o, map[string]string{
+		clusterv1.PausedAnnotation:            "",
+		clusterv1.PausedFromClusterAnnotation: "",
+	})
+}
+
+// RemovePausedFromCluster removes the `paused` annotation from the object, but only if it has been propagated
+// from the Cluster. Returns true if the annotations have changed.
+func RemovePausedFromCluster(o metav1.Object) bool {
+	if !hasAnnotation(o, clusterv1.PausedFromClusterAnnotation) {
+		return false
+	}
+	annotations := o.GetAnnotations()
+	delete(annotations, clusterv1.PausedAnnotation)
+	delete(annotations, clusterv1.PausedFromClusterAnnotation)
+	o.SetAnnotations(annotations)
+	return true
+}
+
+// ReconcilePausedFromCluster propagates the paused state of the Cluster to the object, by adding or removing
+// the `paused` annotation propagated from the Cluster. Returns true if the annotations have changed.
+func ReconcilePausedFromCluster(cluster *clusterv1.Cluster, o metav1.Object) bool {
+	if cluster.Spec.Paused {
+		return AddPausedFromCluster(o)
+	}
+	return RemovePausedFromCluster(o)
+}
+
 // HasSkipRemediationAnnotation returns true if the object has the `skip-remediation` annotation.
 func HasSkipRemediationAnnotation(o metav1.Object) bool {
 	return hasAnnotation(o, clusterv1.MachineSkipRemediationAnnotation)
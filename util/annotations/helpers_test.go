@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 func TestAddAnnotations(t *testing.T) {
@@ -151,3 +152,81 @@ func TestAddAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilePausedFromCluster(t *testing.T) {
+	var testcases = []struct {
+		name     string
+		paused   bool
+		obj      metav1.Object
+		expected map[string]string
+		changed  bool
+	}{
+		{
+			name:   "should add the paused annotation when the Cluster is paused",
+			paused: true,
+			obj:    &corev1.Node{},
+			expected: map[string]string{
+				clusterv1.PausedAnnotation:            "",
+				clusterv1.PausedFromClusterAnnotation: "",
+			},
+			changed: true,
+		},
+		{
+			name:   "should not change a paused annotation set by users when the Cluster is paused",
+			paused: true,
+			obj: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						clusterv1.PausedAnnotation: "true",
+					},
+				},
+			},
+			expected: map[string]string{
+				clusterv1.PausedAnnotation: "true",
+			},
+			changed: false,
+		},
+		{
+			name:   "should remove the paused annotation propagated from the Cluster when the Cluster is not paused",
+			paused: false,
+			obj: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"foo":                                 "bar",
+						clusterv1.PausedAnnotation:            "",
+						clusterv1.PausedFromClusterAnnotation: "",
+					},
+				},
+			},
+			expected: map[string]string{
+				"foo": "bar",
+			},
+			changed: true,
+		},
+		{
+			name:   "should not remove a paused annotation set by users when the Cluster is not paused",
+			paused: false,
+			obj: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						clusterv1.PausedAnnotation: "",
+					},
+				},
+			},
+			expected: map[string]string{
+				clusterv1.PausedAnnotation: "",
+			},
+			changed: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Paused: tc.paused}}
+			g.Expect(ReconcilePausedFromCluster(cluster, tc.obj)).To(Equal(tc.changed))
+			g.Expect(tc.obj.GetAnnotations()).To(Equal(tc.expected))
+		})
+	}
+}
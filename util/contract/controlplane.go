@@ -96,6 +96,35 @@ func (c *ControlPlaneContract) ReadyReplicas() *Int64 {
 	}
 }
 
+// ObservedGeneration provide access to status.observedGeneration field  in a ControlPlane object, if any.
+func (c *ControlPlaneContract) ObservedGeneration() *Int64 {
+	return &Int64{
+		path: []string{"status", "observedGeneration"},
+	}
+}
+
+// Ready provides access to the status.ready field in a ControlPlane object.
+func (c *ControlPlaneContract) Ready() *Bool {
+	return &Bool{
+		path: []string{"status", "ready"},
+	}
+}
+
+// Initialized provides access to the status.initialized field in a ControlPlane object.
+func (c *ControlPlaneContract) Initialized() *Bool {
+	return &Bool{
+		path: []string{"status", "initialized"},
+	}
+}
+
+// ExternalManagedControlPlane provides access to the status.externalManagedControlPlane field in a ControlPlane object.
+// Note that this field is optional.
+func (c *ControlPlaneContract) ExternalManagedControlPlane() *Bool {
+	return &Bool{
+		path: []string{"status", "externalManagedControlPlane"},
+	}
+}
+
 // IsUpgrading returns true if the control plane is in the middle of an upgrade, false otherwise.
 // A control plane is considered upgrading if:
 // - if spec.version is greater than status.verison.
@@ -180,6 +209,23 @@ func (c *ControlPlaneContract) IsScaling(obj *unstructured.Unstructured) (bool,
 	return false, nil
 }
 
+// IsRollingOut returns true if the control plane is in the middle of a rollout of its machines, false otherwise.
+// A control plane is considered rolling out if:
+// - status.observedGeneration is lower than metadata.generation, i.e. the latest changes are not yet observed.
+// - the control plane is scaling, e.g. spec.replicas != status.updatedReplicas; see IsScaling for details.
+// Note: If status.observedGeneration is not set, only the second check is performed.
+func (c *ControlPlaneContract) IsRollingOut(obj *unstructured.Unstructured) (bool, error) {
+	observedGeneration, err := c.ObservedGeneration().Get(obj)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return false, errors.Wrap(err, "failed to get control plane status observedGeneration")
+	}
+	if observedGeneration != nil && *observedGeneration < obj.GetGeneration() {
+		return true, nil
+	}
+
+	return c.IsScaling(obj)
+}
+
 // ControlPlaneMachineTemplate provides a helper struct for working with MachineTemplate in ClusterClass.
 type ControlPlaneMachineTemplate struct{}
 
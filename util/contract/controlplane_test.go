@@ -105,6 +105,45 @@ func TestControlPlane(t *testing.T) {
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(*got).To(Equal(int64(3)))
 	})
+	t.Run("Manages status.observedGeneration", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ControlPlane().ObservedGeneration().Path()).To(Equal(Path{"status", "observedGeneration"}))
+
+		err := ControlPlane().ObservedGeneration().Set(obj, int64(3))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got, err := ControlPlane().ObservedGeneration().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(*got).To(Equal(int64(3)))
+	})
+	t.Run("Manages status.ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ControlPlane().Ready().Path()).To(Equal(Path{"status", "ready"}))
+
+		err := ControlPlane().Ready().Set(obj, true)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got, err := ControlPlane().Ready().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(*got).To(BeTrue())
+	})
+	t.Run("Manages status.initialized", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ControlPlane().Initialized().Path()).To(Equal(Path{"status", "initialized"}))
+
+		err := ControlPlane().Initialized().Set(obj, true)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got, err := ControlPlane().Initialized().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(*got).To(BeTrue())
+	})
 	t.Run("Manages spec.machineTemplate.infrastructureRef", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -311,3 +350,92 @@ func TestControlPlaneIsScaling(t *testing.T) {
 		})
 	}
 }
+
+func TestControlPlaneIsRollingOut(t *testing.T) {
+	tests := []struct {
+		name           string
+		obj            *unstructured.Unstructured
+		wantRollingOut bool
+	}{
+		{
+			name: "should return false for stable control plane",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"generation": int64(2),
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+				},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"replicas":           int64(3),
+					"updatedReplicas":    int64(3),
+					"readyReplicas":      int64(3),
+				},
+			}},
+			wantRollingOut: false,
+		},
+		{
+			name: "should return false for stable control plane without status.observedGeneration",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"generation": int64(2),
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+				},
+				"status": map[string]interface{}{
+					"replicas":        int64(3),
+					"updatedReplicas": int64(3),
+					"readyReplicas":   int64(3),
+				},
+			}},
+			wantRollingOut: false,
+		},
+		{
+			name: "should return true if the latest changes are not yet observed",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"generation": int64(2),
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+				},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"replicas":           int64(3),
+					"updatedReplicas":    int64(3),
+					"readyReplicas":      int64(3),
+				},
+			}},
+			wantRollingOut: true,
+		},
+		{
+			name: "should return true if not all the replicas are updated",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"generation": int64(2),
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+				},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"replicas":           int64(4),
+					"updatedReplicas":    int64(1),
+					"readyReplicas":      int64(4),
+				},
+			}},
+			wantRollingOut: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := ControlPlane().IsRollingOut(tt.obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(actual).To(Equal(tt.wantRollingOut))
+		})
+	}
+}
@@ -14,19 +14,6 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package contract provides support for controllers to handle with providers objects
+// according to the Cluster API contract, without hard-coding paths to unstructured fields.
 package contract
-
-import (
-	"testing"
-
-	. "github.com/onsi/gomega"
-)
-
-func TestInfrastructureCluster(t *testing.T) {
-	t.Run("Has ignore paths", func(t *testing.T) {
-		g := NewWithT(t)
-		g.Expect(InfrastructureCluster().IgnorePaths()).To(Equal([]Path{
-			{"spec", "controlPlaneEndpoint"},
-		}))
-	})
-}
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// InfrastructureClusterContract encodes information about the Cluster API contract for InfrastructureCluster objects
+// like e.g the DockerCluster, AWS Cluster etc.
+type InfrastructureClusterContract struct{}
+
+var infrastructureCluster *InfrastructureClusterContract
+var onceInfrastructureCluster sync.Once
+
+// InfrastructureCluster provide access to the information about the Cluster API contract for InfrastructureCluster objects.
+func InfrastructureCluster() *InfrastructureClusterContract {
+	onceInfrastructureCluster.Do(func() {
+		infrastructureCluster = &InfrastructureClusterContract{}
+	})
+	return infrastructureCluster
+}
+
+// IgnorePaths returns a list of paths to be ignored when reconciling a topology.
+func (c *InfrastructureClusterContract) IgnorePaths() []Path {
+	return []Path{
+		// NOTE: the controlPlaneEndpoint struct currently contains two mandatory fields (host and port); without this
+		// ignore path they are going to be always reconciled to the default value or to the value set into the template.
+		{"spec", "controlPlaneEndpoint"},
+	}
+}
+
+// ControlPlaneEndpoint provides access to the spec.controlPlaneEndpoint field in an InfrastructureCluster object.
+func (c *InfrastructureClusterContract) ControlPlaneEndpoint() *APIEndpoint {
+	return &APIEndpoint{
+		path: []string{"spec", "controlPlaneEndpoint"},
+	}
+}
+
+// Ready provides access to the status.ready field in an InfrastructureCluster object.
+func (c *InfrastructureClusterContract) Ready() *Bool {
+	return &Bool{
+		path: []string{"status", "ready"},
+	}
+}
+
+// FailureDomains provides access to the status.failureDomains field in an InfrastructureCluster object. Note that this field is optional.
+func (c *InfrastructureClusterContract) FailureDomains() *FailureDomains {
+	return &FailureDomains{
+		path: []string{"status", "failureDomains"},
+	}
+}
+
+// FailureReason provides access to the status.failureReason field in an InfrastructureCluster object. Note that this field is optional.
+func (c *InfrastructureClusterContract) FailureReason() *String {
+	return &String{
+		path: []string{"status", "failureReason"},
+	}
+}
+
+// FailureMessage provides access to the status.failureMessage field in an InfrastructureCluster object. Note that this field is optional.
+func (c *InfrastructureClusterContract) FailureMessage() *String {
+	return &String{
+		path: []string{"status", "failureMessage"},
+	}
+}
+
+// APIEndpoint provides a helper struct for working with APIEndpoint in an InfrastructureCluster object.
+type APIEndpoint struct {
+	path Path
+}
+
+// Path returns the path to the APIEndpoint value.
+func (a *APIEndpoint) Path() Path {
+	return a.path
+}
+
+// Get gets the APIEndpoint value.
+func (a *APIEndpoint) Get(obj *unstructured.Unstructured) (*clusterv1.APIEndpoint, error) {
+	endpoint := &clusterv1.APIEndpoint{}
+	if err := getNestedValue(obj, a.path, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// Set sets the APIEndpoint value in the path.
+func (a *APIEndpoint) Set(obj *unstructured.Unstructured, value clusterv1.APIEndpoint) error {
+	return setNestedValue(obj, a.path, value)
+}
+
+// FailureDomains provides a helper struct for working with FailureDomains in an InfrastructureCluster object.
+type FailureDomains struct {
+	path Path
+}
+
+// Path returns the path to the FailureDomains value.
+func (f *FailureDomains) Path() Path {
+	return f.path
+}
+
+// Get gets the FailureDomains value.
+func (f *FailureDomains) Get(obj *unstructured.Unstructured) (clusterv1.FailureDomains, error) {
+	domains := clusterv1.FailureDomains{}
+	if err := getNestedValue(obj, f.path, &domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// Set sets the FailureDomains value in the path.
+func (f *FailureDomains) Set(obj *unstructured.Unstructured, value clusterv1.FailureDomains) error {
+	return setNestedValue(obj, f.path, value)
+}
+
+// getNestedValue reads the value at path into the given typed object by round-tripping through JSON.
+func getNestedValue(obj *unstructured.Unstructured, path Path, into interface{}) error {
+	value, ok, err := unstructured.NestedFieldNoCopy(obj.UnstructuredContent(), path...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s from object", "."+strings.Join(path, "."))
+	}
+	if !ok {
+		return errors.Wrapf(errNotFound, "path %s", "."+strings.Join(path, "."))
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", "."+strings.Join(path, "."))
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s", "."+strings.Join(path, "."))
+	}
+	return nil
+}
+
+// setNestedValue sets the given typed value at path by round-tripping through JSON.
+// NOTE: json.Unmarshal from apimachinery is used so numbers are converted to int64 as expected in unstructured content.
+func setNestedValue(obj *unstructured.Unstructured, path Path, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal value for path %s", "."+strings.Join(path, "."))
+	}
+	var u interface{}
+	if err := json.Unmarshal(data, &u); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal value for path %s", "."+strings.Join(path, "."))
+	}
+	if err := unstructured.SetNestedField(obj.UnstructuredContent(), u, path...); err != nil {
+		return errors.Wrapf(err, "failed to set path %s of object %v", "."+strings.Join(path, "."), obj.GroupVersionKind())
+	}
+	return nil
+}
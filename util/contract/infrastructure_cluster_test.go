@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestInfrastructureCluster(t *testing.T) {
+	t.Run("Has ignore paths", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(InfrastructureCluster().IgnorePaths()).To(Equal([]Path{
+			{"spec", "controlPlaneEndpoint"},
+		}))
+	})
+	t.Run("Manages spec.controlPlaneEndpoint", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+		g.Expect(InfrastructureCluster().ControlPlaneEndpoint().Path()).To(Equal(Path{"spec", "controlPlaneEndpoint"}))
+
+		_, err := InfrastructureCluster().ControlPlaneEndpoint().Get(obj)
+		g.Expect(IsNotFound(err)).To(BeTrue())
+
+		endpoint := clusterv1.APIEndpoint{Host: "example.com", Port: 6443}
+		g.Expect(InfrastructureCluster().ControlPlaneEndpoint().Set(obj, endpoint)).To(Succeed())
+		port, ok, err := unstructured.NestedInt64(obj.Object, "spec", "controlPlaneEndpoint", "port")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(ok).To(BeTrue())
+		g.Expect(port).To(Equal(int64(6443)))
+
+		got, err := InfrastructureCluster().ControlPlaneEndpoint().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(*got).To(Equal(endpoint))
+	})
+	t.Run("Manages status.ready", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+		g.Expect(InfrastructureCluster().Ready().Path()).To(Equal(Path{"status", "ready"}))
+
+		_, err := InfrastructureCluster().Ready().Get(obj)
+		g.Expect(IsNotFound(err)).To(BeTrue())
+
+		g.Expect(InfrastructureCluster().Ready().Set(obj, true)).To(Succeed())
+
+		got, err := InfrastructureCluster().Ready().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(*got).To(BeTrue())
+	})
+	t.Run("Manages status.failureDomains", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+		g.Expect(InfrastructureCluster().FailureDomains().Path()).To(Equal(Path{"status", "failureDomains"}))
+
+		_, err := InfrastructureCluster().FailureDomains().Get(obj)
+		g.Expect(IsNotFound(err)).To(BeTrue())
+
+		failureDomains := clusterv1.FailureDomains{
+			"domain1": clusterv1.FailureDomainSpec{ControlPlane: true},
+			"domain2": clusterv1.FailureDomainSpec{Attributes: map[string]string{"foo": "bar"}},
+		}
+		g.Expect(InfrastructureCluster().FailureDomains().Set(obj, failureDomains)).To(Succeed())
+
+		got, err := InfrastructureCluster().FailureDomains().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal(failureDomains))
+	})
+}
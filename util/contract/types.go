@@ -25,6 +25,11 @@ import (
 
 var errNotFound = errors.New("not found")
 
+// IsNotFound returns true if the error reports that a path does not exist in an object.
+func IsNotFound(err error) bool {
+	return errors.Is(err, errNotFound)
+}
+
 // Path defines a how to access a field in an Unstructured object.
 type Path []string
 
@@ -87,3 +92,33 @@ func (s *String) Set(obj *unstructured.Unstructured, value string) error {
 	}
 	return nil
 }
+
+// Bool represents an accessor to a bool path value.
+type Bool struct {
+	path Path
+}
+
+// Path returns the path to the bool value.
+func (b *Bool) Path() Path {
+	return b.path
+}
+
+// Get gets the bool value.
+func (b *Bool) Get(obj *unstructured.Unstructured) (*bool, error) {
+	value, ok, err := unstructured.NestedBool(obj.UnstructuredContent(), b.path...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s from object", "."+strings.Join(b.path, "."))
+	}
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "path %s", "."+strings.Join(b.path, "."))
+	}
+	return &value, nil
+}
+
+// Set sets the bool value in the path.
+func (b *Bool) Set(obj *unstructured.Unstructured, value bool) error {
+	if err := unstructured.SetNestedField(obj.UnstructuredContent(), value, b.path...); err != nil {
+		return errors.Wrapf(err, "failed to set path %s of object %v", "."+strings.Join(b.path, "."), obj.GroupVersionKind())
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+// Version is the Cluster API contract implemented by the core types, e.g. "cluster.x-k8s.io/v1beta1".
+// Provider CRDs declare the API versions implementing this contract using a label with the contract as a key.
+var Version = clusterv1.GroupVersion.String()
+
+// GetAPIVersionsForContract returns the API versions of a provider CRD implementing the given contract,
+// as declared by the contract label on the CRD metadata.
+func GetAPIVersionsForContract(crdMetadata metav1.Object, contract string) []string {
+	supportedVersions, ok := crdMetadata.GetLabels()[contract]
+	if !ok || supportedVersions == "" {
+		return nil
+	}
+	return strings.Split(supportedVersions, "_")
+}
+
+// GetLatestAPIVersionForContract returns the latest API version of a provider CRD implementing the given contract.
+func GetLatestAPIVersionForContract(crdMetadata metav1.Object, contract string) (string, error) {
+	supportedVersions := GetAPIVersionsForContract(crdMetadata, contract)
+	if len(supportedVersions) == 0 {
+		return "", errors.Errorf("cannot find any versions matching contract %q for GVK %v as contract version label(s) are either missing or empty", contract, crdMetadata.GetName())
+	}
+
+	// Pick the latest version in the slice.
+	kubeVersions := util.KubeAwareAPIVersions(supportedVersions)
+	sort.Sort(kubeVersions)
+	return kubeVersions[len(kubeVersions)-1], nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contract
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetLatestAPIVersionForContract(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		want      string
+		wantError bool
+	}{
+		{
+			name:      "fails without contract label",
+			labels:    map[string]string{},
+			wantError: true,
+		},
+		{
+			name:      "fails with an empty contract label",
+			labels:    map[string]string{Version: ""},
+			wantError: true,
+		},
+		{
+			name:   "picks the only version",
+			labels: map[string]string{Version: "v1alpha1"},
+			want:   "v1alpha1",
+		},
+		{
+			name:   "picks the latest version",
+			labels: map[string]string{Version: "v1alpha3_v1beta1_v1alpha4"},
+			want:   "v1beta1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			crdMetadata := &metav1.PartialObjectMetadata{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "foos.infrastructure.cluster.x-k8s.io",
+					Labels: tt.labels,
+				},
+			}
+
+			got, err := GetLatestAPIVersionForContract(crdMetadata, Version)
+			if tt.wantError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
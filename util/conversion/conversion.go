@@ -20,14 +20,11 @@ package conversion
 import (
 	"context"
 	"math/rand"
-	"sort"
-	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	fuzz "github.com/google/gofuzz"
 	"github.com/onsi/gomega"
-	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -39,8 +36,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/contract"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
@@ -50,10 +47,6 @@ const (
 	DataAnnotation = "cluster.x-k8s.io/conversion-data"
 )
 
-var (
-	contract = clusterv1.GroupVersion.String()
-)
-
 // UpdateReferenceAPIContract takes a client and object reference, queries the API Server for
 // the Custom Resource Definition and looks which one is the stored version available.
 //
@@ -66,7 +59,7 @@ func UpdateReferenceAPIContract(ctx context.Context, c client.Client, ref *corev
 	if err != nil {
 		log.Info("Cannot retrieve CRD with metadata only client, falling back to slower listing", "err", err.Error())
 		// Fallback to slower and more memory intensive method to get the full CRD.
-		crd, err := util.GetCRDWithContract(ctx, c, gvk, contract)
+		crd, err := util.GetCRDWithContract(ctx, c, gvk, contract.Version)
 		if err != nil {
 			return err
 		}
@@ -104,7 +97,7 @@ func ConvertReferenceAPIContract(ctx context.Context, c client.Client, restConfi
 	if err != nil {
 		log.Info("Cannot retrieve CRD with metadata only client, falling back to slower listing", "err", err.Error())
 		// Fallback to slower and more memory intensive method to get the full CRD.
-		crd, err := util.GetCRDWithContract(ctx, c, gvk, contract)
+		crd, err := util.GetCRDWithContract(ctx, c, gvk, contract.Version)
 		if err != nil {
 			return err
 		}
@@ -129,18 +122,7 @@ func ConvertReferenceAPIContract(ctx context.Context, c client.Client, restConfi
 }
 
 func getLatestAPIVersionFromContract(metadata metav1.Object) (string, error) {
-	labels := metadata.GetLabels()
-
-	// If there is no label, return early without changing the reference.
-	supportedVersions, ok := labels[contract]
-	if !ok || supportedVersions == "" {
-		return "", errors.Errorf("cannot find any versions matching contract %q for GVK %v as contract version label(s) are either missing or empty", contract, metadata.GetName())
-	}
-
-	// Pick the latest version in the slice and validate it.
-	kubeVersions := util.KubeAwareAPIVersions(strings.Split(supportedVersions, "_"))
-	sort.Sort(kubeVersions)
-	return kubeVersions[len(kubeVersions)-1], nil
+	return contract.GetLatestAPIVersionForContract(metadata, contract.Version)
 }
 
 // MarshalData stores the source object as json data in the destination object annotations map.
@@ -40,8 +40,13 @@ func (f failureDomainAggregations) Len() int {
 
 // Less reports whether the element with
 // index i should sort before the element with index j.
+// Failure domains with the same number of machines are sorted by id, so the failure domain picked among
+// failure domains with the same number of machines is always the same.
 func (f failureDomainAggregations) Less(i, j int) bool {
-	return f[i].count < f[j].count
+	if f[i].count != f[j].count {
+		return f[i].count < f[j].count
+	}
+	return f[i].id < f[j].id
 }
 
 // Swap swaps the elements with indexes i and j.
@@ -86,6 +91,19 @@ func PickFewest(failureDomains clusterv1.FailureDomains, machines collections.Ma
 	return pointer.StringPtr(aggregations[0].id)
 }
 
+// CountMachines returns the number of machines in each failure domain, keyed by failure domain id;
+// machines without a failure domain are not counted.
+func CountMachines(machines []*clusterv1.Machine) map[string]int {
+	counters := map[string]int{}
+	for _, m := range machines {
+		if m.Spec.FailureDomain == nil {
+			continue
+		}
+		counters[*m.Spec.FailureDomain]++
+	}
+	return counters
+}
+
 func pick(failureDomains clusterv1.FailureDomains, machines collections.Machines) failureDomainAggregations {
 	if len(failureDomains) == 0 {
 		return failureDomainAggregations{}
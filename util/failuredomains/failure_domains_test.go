@@ -178,3 +178,42 @@ func TestNewFailureDomainPickMost(t *testing.T) {
 		})
 	}
 }
+
+func TestPickFewestIsDeterministic(t *testing.T) {
+	g := NewWithT(t)
+
+	a := pointer.StringPtr("us-west-1a")
+	b := pointer.StringPtr("us-west-1b")
+	c := pointer.StringPtr("us-west-1c")
+
+	fds := clusterv1.FailureDomains{
+		*a: clusterv1.FailureDomainSpec{},
+		*b: clusterv1.FailureDomainSpec{},
+		*c: clusterv1.FailureDomainSpec{},
+	}
+	machines := collections.FromMachines(
+		&clusterv1.Machine{Spec: clusterv1.MachineSpec{FailureDomain: a}},
+	)
+
+	// us-west-1b and us-west-1c have the same number of machines, the first by id is picked.
+	for i := 0; i < 10; i++ {
+		g.Expect(PickFewest(fds, machines)).To(Equal(b))
+	}
+}
+
+func TestCountMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	a := pointer.StringPtr("us-west-1a")
+	b := pointer.StringPtr("us-west-1b")
+
+	machines := []*clusterv1.Machine{
+		{Spec: clusterv1.MachineSpec{FailureDomain: a}},
+		{Spec: clusterv1.MachineSpec{FailureDomain: a}},
+		{Spec: clusterv1.MachineSpec{FailureDomain: b}},
+		{},
+	}
+
+	g.Expect(CountMachines(machines)).To(Equal(map[string]int{*a: 2, *b: 1}))
+	g.Expect(CountMachines(nil)).To(BeEmpty())
+}
@@ -53,10 +53,17 @@ func FromSecret(ctx context.Context, c client.Reader, cluster client.ObjectKey)
 
 // New creates a new Kubeconfig using the cluster name and specified endpoint.
 func New(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Signer) (*api.Config, error) {
+	return NewWithCertificateDuration(clusterName, endpoint, caCert, caKey, certs.DefaultCertDuration)
+}
+
+// NewWithCertificateDuration creates a new Kubeconfig using the cluster name and specified endpoint,
+// with a client certificate valid for the given duration.
+func NewWithCertificateDuration(clusterName, endpoint string, caCert *x509.Certificate, caKey crypto.Signer, duration time.Duration) (*api.Config, error) {
 	cfg := &certs.Config{
 		CommonName:   "kubernetes-admin",
 		Organization: []string{"system:masters"},
 		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		Duration:     duration,
 	}
 
 	clientKey, err := certs.NewPrivateKey()
@@ -150,29 +157,38 @@ func GenerateSecretWithOwner(clusterName client.ObjectKey, data []byte, owner me
 
 // NeedsClientCertRotation returns whether any of the Kubeconfig secret's client certificates will expire before the given threshold.
 func NeedsClientCertRotation(configSecret *corev1.Secret, threshold time.Duration) (bool, error) {
-	now := time.Now()
+	expiry, err := ClientCertExpiry(configSecret)
+	if err != nil {
+		return false, err
+	}
+	return !expiry.IsZero() && time.Until(expiry) < threshold, nil
+}
 
+// ClientCertExpiry returns the time the first of the Kubeconfig secret's client certificates expires;
+// a zero time is returned if the Kubeconfig does not contain client certificates.
+func ClientCertExpiry(configSecret *corev1.Secret) (time.Time, error) {
 	data, err := toKubeconfigBytes(configSecret)
 	if err != nil {
-		return false, err
+		return time.Time{}, err
 	}
 
 	config, err := clientcmd.Load(data)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+		return time.Time{}, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
 	}
 
+	var expiry time.Time
 	for _, authInfo := range config.AuthInfos {
 		cert, err := certs.DecodeCertPEM(authInfo.ClientCertificateData)
 		if err != nil {
-			return false, errors.Wrap(err, "failed to decode kubeconfig client certificate")
+			return time.Time{}, errors.Wrap(err, "failed to decode kubeconfig client certificate")
 		}
-		if cert.NotAfter.Sub(now) < threshold {
-			return true, nil
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
 		}
 	}
 
-	return false, nil
+	return expiry, nil
 }
 
 // RegenerateSecret creates and stores a new Kubeconfig in the given secret.
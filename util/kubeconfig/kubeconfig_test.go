@@ -177,6 +177,23 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewWithCertificateDuration(t *testing.T) {
+	g := NewWithT(t)
+
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config, err := NewWithCertificateDuration("foo", "https://127.0.0.1:4003", caCert, caKey, time.Hour)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cert, err := certs.DecodeCertPEM(config.AuthInfos["foo-admin"].ClientCertificateData)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+}
+
 func TestGenerateSecretWithOwner(t *testing.T) {
 	g := NewWithT(t)
 
@@ -378,6 +395,10 @@ func TestNeedsClientCertRotation(t *testing.T) {
 
 	g.Expect(NeedsClientCertRotation(kubeconfigSecret, certs.DefaultCertDuration)).To(BeTrue())
 	g.Expect(NeedsClientCertRotation(kubeconfigSecret, certs.DefaultCertDuration-time.Hour)).To(BeFalse())
+
+	expiry, err := ClientCertExpiry(kubeconfigSecret)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(expiry).To(BeTemporally("~", time.Now().Add(certs.DefaultCertDuration), time.Minute))
 }
 
 func TestRegenerateClientCerts(t *testing.T) {
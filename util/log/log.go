@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package log implements the logging conventions used by Cluster API controllers.
+//
+// Objects are logged using their Kind as a key and a reference in the namespace/name format as a value,
+// e.g. "Cluster"="default/my-cluster", consistently with the Kubernetes structured logging guidelines;
+// this allows to reliably query and correlate logs from different controllers in aggregated logging systems.
+package log
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Standard keys used for logging Cluster API objects and related objects.
+const (
+	// ClusterKey is the key used for logging a Cluster.
+	ClusterKey = "Cluster"
+
+	// MachineKey is the key used for logging a Machine.
+	MachineKey = "Machine"
+
+	// MachineSetKey is the key used for logging a MachineSet.
+	MachineSetKey = "MachineSet"
+
+	// MachineDeploymentKey is the key used for logging a MachineDeployment.
+	MachineDeploymentKey = "MachineDeployment"
+
+	// MachinePoolKey is the key used for logging a MachinePool.
+	MachinePoolKey = "MachinePool"
+
+	// MachineHealthCheckKey is the key used for logging a MachineHealthCheck.
+	MachineHealthCheckKey = "MachineHealthCheck"
+
+	// ClusterResourceSetKey is the key used for logging a ClusterResourceSet.
+	ClusterResourceSetKey = "ClusterResourceSet"
+
+	// ClusterSetKey is the key used for logging a ClusterSet.
+	ClusterSetKey = "ClusterSet"
+
+	// HostClaimKey is the key used for logging a HostClaim.
+	HostClaimKey = "HostClaim"
+
+	// HostKey is the key used for logging a host bound to a HostClaim.
+	HostKey = "Host"
+
+	// NodeKey is the key used for logging a Node.
+	NodeKey = "Node"
+)
+
+// KObj returns a reference to a Kubernetes object in the namespace/name format.
+func KObj(obj metav1.Object) klog.ObjectRef {
+	return klog.KObj(obj)
+}
+
+// KRef returns a reference to a Kubernetes object with the given namespace and name in the namespace/name format.
+// NOTE: namespace must be empty for cluster-scoped objects, e.g. Nodes.
+func KRef(namespace, name string) klog.ObjectRef {
+	return klog.KRef(namespace, name)
+}
+
+// Into returns the logger from the context enriched with the given key/value pairs, and a copy of the
+// context with the enriched logger injected, so the key/value pairs are included in all the log lines
+// emitted down the call chain.
+func Into(ctx context.Context, keysAndValues ...interface{}) (context.Context, logr.Logger) {
+	log := ctrl.LoggerFrom(ctx, keysAndValues...)
+	return ctrl.LoggerInto(ctx, log), log
+}
+
+// IntoWithCluster is like Into, but it also adds the Cluster with the given namespace and name to the logger.
+// The Cluster is not added if clusterName is empty, e.g. for objects not yet associated to a Cluster.
+func IntoWithCluster(ctx context.Context, namespace, clusterName string, keysAndValues ...interface{}) (context.Context, logr.Logger) {
+	if clusterName != "" {
+		keysAndValues = append(keysAndValues, ClusterKey, KRef(namespace, clusterName))
+	}
+	return Into(ctx, keysAndValues...)
+}
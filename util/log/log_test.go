@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// recordingLogger is a logr.Logger recording the key/value pairs added to the logger.
+type recordingLogger struct {
+	values []interface{}
+}
+
+func (l *recordingLogger) Enabled() bool                       { return true }
+func (l *recordingLogger) Info(string, ...interface{})         {}
+func (l *recordingLogger) Error(error, string, ...interface{}) {}
+func (l *recordingLogger) V(int) logr.Logger                   { return l }
+func (l *recordingLogger) WithName(string) logr.Logger         { return l }
+func (l *recordingLogger) WithValues(kv ...interface{}) logr.Logger {
+	return &recordingLogger{values: append(append([]interface{}{}, l.values...), kv...)}
+}
+
+func TestKObj(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "machine1"}}
+	g.Expect(KObj(m).String()).To(Equal("ns1/machine1"))
+	g.Expect(KRef("ns1", "cluster1").String()).To(Equal("ns1/cluster1"))
+	g.Expect(KRef("", "node1").String()).To(Equal("node1"))
+}
+
+func TestIntoWithCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "machine1"}}
+	ctx := ctrl.LoggerInto(context.Background(), &recordingLogger{})
+
+	ctx, log := IntoWithCluster(ctx, m.Namespace, "cluster1", MachineKey, KObj(m))
+	g.Expect(log.(*recordingLogger).values).To(Equal([]interface{}{MachineKey, KObj(m), ClusterKey, KRef("ns1", "cluster1")}))
+
+	// The logger in the returned context is expected to carry the same values.
+	g.Expect(ctrl.LoggerFrom(ctx).(*recordingLogger).values).To(Equal(log.(*recordingLogger).values))
+
+	// The Cluster is not added when the cluster name is empty.
+	_, log = IntoWithCluster(ctx, m.Namespace, "", MachineSetKey, KRef(m.Namespace, "machineset1"))
+	g.Expect(log.(*recordingLogger).values).To(Equal([]interface{}{MachineKey, KObj(m), ClusterKey, KRef("ns1", "cluster1"), MachineSetKey, KRef("ns1", "machineset1")}))
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodemetadata implements utility functions for the labels and taints Cluster API applies to Nodes.
+package nodemetadata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// NodeRoleLabelDomain is the domain of the labels defining the roles of a Node, e.g. node-role.kubernetes.io/worker.
+	NodeRoleLabelDomain = "node-role.kubernetes.io"
+
+	// NodeRestrictionLabelDomain is the domain of the labels reserved for Node isolation, which the
+	// NodeRestriction admission plugin prevents kubelets from setting on their own Node.
+	NodeRestrictionLabelDomain = "node-restriction.kubernetes.io"
+)
+
+// propagatedLabelDomains are the domains of the Machine labels propagated to the corresponding Node.
+var propagatedLabelDomains = []string{NodeRoleLabelDomain, NodeRestrictionLabelDomain}
+
+// MachineNodeLabels returns the Machine labels to be propagated to the corresponding Node, i.e. the labels
+// in the NodeRoleLabelDomain and NodeRestrictionLabelDomain domains or any of their subdomains.
+func MachineNodeLabels(machineLabels map[string]string) map[string]string {
+	labels := map[string]string{}
+	for k, v := range machineLabels {
+		i := strings.Index(k, "/")
+		if i < 0 {
+			continue
+		}
+		domain := k[:i]
+		for _, d := range propagatedLabelDomains {
+			if domain == d || strings.HasSuffix(domain, "."+d) {
+				labels[k] = v
+				break
+			}
+		}
+	}
+	return labels
+}
+
+// FormatLabels formats labels using the format of the kubelet --node-labels flag, e.g. "key1=value1,key2=value2".
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, labels[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseLabels parses labels in the format of the kubelet --node-labels flag, e.g. "key1=value1,key2=value2".
+func ParseLabels(value string) (map[string]string, error) {
+	labels := map[string]string{}
+	if value == "" {
+		return labels, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid label %q, expected key=value", pair)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return labels, nil
+}
+
+// FormatTaints formats taints using the format of the kubelet --register-with-taints flag,
+// e.g. "key1=value1:NoSchedule,key2:NoExecute".
+func FormatTaints(taints []corev1.Taint) string {
+	items := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		items = append(items, taint.ToString())
+	}
+	return strings.Join(items, ",")
+}
+
+// ParseTaints parses taints in the format of the kubelet --register-with-taints flag,
+// e.g. "key1=value1:NoSchedule,key2:NoExecute".
+func ParseTaints(value string) ([]corev1.Taint, error) {
+	taints := []corev1.Taint{}
+	if value == "" {
+		return taints, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid taint %q, expected key[=value]:effect", item)
+		}
+		taint := corev1.Taint{Effect: corev1.TaintEffect(parts[1])}
+		kv := strings.SplitN(parts[0], "=", 2)
+		taint.Key = kv[0]
+		if len(kv) == 2 {
+			taint.Value = kv[1]
+		}
+		if err := ValidateTaintEffect(taint.Effect); err != nil {
+			return nil, errors.Wrapf(err, "invalid taint %q", item)
+		}
+		taints = append(taints, taint)
+	}
+	return taints, nil
+}
+
+// ValidateTaintEffect returns an error if the effect is not a valid taint effect.
+func ValidateTaintEffect(effect corev1.TaintEffect) error {
+	switch effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		return nil
+	default:
+		return errors.Errorf("invalid effect %q, must be one of %s, %s or %s", effect,
+			corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+	}
+}
+
+// SyncNode applies labels and taints to a Node, removing the labels and taints previously applied by this func
+// which are not desired anymore; labels and taints applied by this func are tracked using the NodeLabelsAnnotation
+// and the NodeTaintsAnnotation on the Node. Labels and taints not applied by this func are preserved.
+// Returns true if the Node has been changed.
+func SyncNode(node *corev1.Node, labels map[string]string, taints []corev1.Taint) (bool, error) {
+	changed := false
+
+	appliedLabels, err := ParseLabels(node.GetAnnotations()[clusterv1.NodeLabelsAnnotation])
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s annotation on Node %s", clusterv1.NodeLabelsAnnotation, node.Name)
+	}
+	appliedTaints, err := ParseTaints(node.GetAnnotations()[clusterv1.NodeTaintsAnnotation])
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s annotation on Node %s", clusterv1.NodeTaintsAnnotation, node.Name)
+	}
+
+	// Sync labels.
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	for k := range appliedLabels {
+		if _, ok := labels[k]; ok {
+			continue
+		}
+		if _, ok := node.Labels[k]; ok {
+			delete(node.Labels, k)
+			changed = true
+		}
+	}
+	for k, v := range labels {
+		if current, ok := node.Labels[k]; !ok || current != v {
+			node.Labels[k] = v
+			changed = true
+		}
+	}
+
+	// Sync taints.
+	newTaints := make([]corev1.Taint, 0, len(node.Spec.Taints)+len(taints))
+	for _, taint := range node.Spec.Taints {
+		taint := taint
+		if containsTaint(appliedTaints, &taint) && !containsTaint(taints, &taint) {
+			changed = true
+			continue
+		}
+		newTaints = append(newTaints, taint)
+	}
+	for _, taint := range taints {
+		taint := taint
+		if containsTaint(newTaints, &taint) {
+			continue
+		}
+		changed = true
+		// A Node can't have two taints with the same key and effect, so the desired taint replaces the existing one.
+		replaced := false
+		for i := range newTaints {
+			if newTaints[i].MatchTaint(&taint) {
+				newTaints[i] = taint
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			newTaints = append(newTaints, taint)
+		}
+	}
+	node.Spec.Taints = newTaints
+
+	// Keep track of the labels and taints applied to the Node.
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	for key, value := range map[string]string{
+		clusterv1.NodeLabelsAnnotation: FormatLabels(labels),
+		clusterv1.NodeTaintsAnnotation: FormatTaints(taints),
+	} {
+		current, ok := node.Annotations[key]
+		switch {
+		case value == "" && ok:
+			delete(node.Annotations, key)
+			changed = true
+		case value != "" && (!ok || current != value):
+			node.Annotations[key] = value
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+// MergeTaints merges two lists of taints; in case both the lists contain a taint with the same key and effect,
+// the taint from the first list takes precedence.
+func MergeTaints(a, b []corev1.Taint) []corev1.Taint {
+	taints := make([]corev1.Taint, 0, len(a)+len(b))
+	taints = append(taints, a...)
+	for i := range b {
+		found := false
+		for j := range a {
+			if a[j].MatchTaint(&b[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			taints = append(taints, b[i])
+		}
+	}
+	return taints
+}
+
+func containsTaint(taints []corev1.Taint, taint *corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(taint) && taints[i].Value == taint.Value {
+			return true
+		}
+	}
+	return false
+}
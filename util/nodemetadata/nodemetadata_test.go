@@ -0,0 +1,232 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetadata
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	labels := map[string]string{"zone": "a", "node-role.kubernetes.io/worker": ""}
+	value := FormatLabels(labels)
+	g.Expect(value).To(Equal("node-role.kubernetes.io/worker=,zone=a"))
+
+	got, err := ParseLabels(value)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(labels))
+
+	got, err = ParseLabels("")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(BeEmpty())
+
+	_, err = ParseLabels("zone")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestMachineNodeLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(MachineNodeLabels(map[string]string{
+		clusterv1.ClusterLabelName:                      "cluster",
+		"node-role.kubernetes.io/worker":                "",
+		"node-restriction.kubernetes.io/dedicated":      "gpu",
+		"team.node-restriction.kubernetes.io/owner":     "infra",
+		"example.com/node-restriction.kubernetes.io":    "foo",
+		"fake-node-restriction.kubernetes.io/dedicated": "gpu",
+		"zone": "a",
+	})).To(Equal(map[string]string{
+		"node-role.kubernetes.io/worker":            "",
+		"node-restriction.kubernetes.io/dedicated":  "gpu",
+		"team.node-restriction.kubernetes.io/owner": "infra",
+	}))
+	g.Expect(MachineNodeLabels(nil)).To(BeEmpty())
+}
+
+func TestTaints(t *testing.T) {
+	g := NewWithT(t)
+
+	taints := []corev1.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	value := FormatTaints(taints)
+	g.Expect(value).To(Equal("dedicated=gpu:NoSchedule,spot:PreferNoSchedule"))
+
+	got, err := ParseTaints(value)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(taints))
+
+	got, err = ParseTaints("")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(BeEmpty())
+
+	_, err = ParseTaints("dedicated=gpu")
+	g.Expect(err).To(HaveOccurred())
+	_, err = ParseTaints("dedicated=gpu:Invalid")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestMergeTaints(t *testing.T) {
+	g := NewWithT(t)
+
+	a := []corev1.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+	}
+	b := []corev1.Taint{
+		{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoExecute},
+	}
+	g.Expect(MergeTaints(a, b)).To(Equal([]corev1.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoExecute},
+	}))
+}
+
+func TestSyncNode(t *testing.T) {
+	tests := []struct {
+		name        string
+		node        *corev1.Node
+		labels      map[string]string
+		taints      []corev1.Taint
+		wantNode    *corev1.Node
+		wantChanged bool
+	}{
+		{
+			name: "applies labels and taints, preserving the existing ones",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kubernetes.io/os": "linux"},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			labels: map[string]string{"zone": "a"},
+			taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+			wantNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kubernetes.io/os": "linux", "zone": "a"},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "zone=a",
+						clusterv1.NodeTaintsAnnotation: "dedicated=gpu:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoSchedule},
+						{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "removes labels and taints not desired anymore",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kubernetes.io/os": "linux", "zone": "a", "role": "worker"},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "role=worker,zone=a",
+						clusterv1.NodeTaintsAnnotation: "dedicated=gpu:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			labels: map[string]string{"zone": "b"},
+			wantNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"kubernetes.io/os": "linux", "zone": "b"},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "zone=b",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{},
+				},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "replaces a taint with the same key and effect",
+			node: &corev1.Node{
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+			wantNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{},
+					Annotations: map[string]string{
+						clusterv1.NodeTaintsAnnotation: "dedicated=gpu:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "does not change a Node already in sync",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"zone": "a"},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "zone=a",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{},
+				},
+			},
+			labels: map[string]string{"zone": "a"},
+			wantNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"zone": "a"},
+					Annotations: map[string]string{
+						clusterv1.NodeLabelsAnnotation: "zone=a",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{},
+				},
+			},
+			wantChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			changed, err := SyncNode(tt.node, tt.labels, tt.taints)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changed).To(Equal(tt.wantChanged))
+			g.Expect(tt.node).To(Equal(tt.wantNode))
+		})
+	}
+}
@@ -20,6 +20,7 @@ package predicates
 import (
 	"github.com/go-logr/logr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logutil "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -36,7 +37,7 @@ func ClusterCreateInfraReady(logger logr.Logger) predicate.Funcs {
 				log.V(4).Info("Expected Cluster", "type", e.Object.GetObjectKind().GroupVersionKind().String())
 				return false
 			}
-			log = log.WithValues("namespace", c.Namespace, "cluster", c.Name)
+			log = log.WithValues(logutil.ClusterKey, logutil.KObj(c))
 
 			// Only need to trigger a reconcile if the Cluster.Status.InfrastructureReady is true
 			if c.Status.InfrastructureReady {
@@ -65,7 +66,7 @@ func ClusterCreateNotPaused(logger logr.Logger) predicate.Funcs {
 				log.V(4).Info("Expected Cluster", "type", e.Object.GetObjectKind().GroupVersionKind().String())
 				return false
 			}
-			log = log.WithValues("namespace", c.Namespace, "cluster", c.Name)
+			log = log.WithValues(logutil.ClusterKey, logutil.KObj(c))
 
 			// Only need to trigger a reconcile if the Cluster.Spec.Paused is false
 			if !c.Spec.Paused {
@@ -94,7 +95,7 @@ func ClusterUpdateInfraReady(logger logr.Logger) predicate.Funcs {
 				log.V(4).Info("Expected Cluster", "type", e.ObjectOld.GetObjectKind().GroupVersionKind().String())
 				return false
 			}
-			log = log.WithValues("namespace", oldCluster.Namespace, "cluster", oldCluster.Name)
+			log = log.WithValues(logutil.ClusterKey, logutil.KObj(oldCluster))
 
 			newCluster := e.ObjectNew.(*clusterv1.Cluster)
 
@@ -124,7 +125,7 @@ func ClusterUpdateUnpaused(logger logr.Logger) predicate.Funcs {
 				log.V(4).Info("Expected Cluster", "type", e.ObjectOld.GetObjectKind().GroupVersionKind().String())
 				return false
 			}
-			log = log.WithValues("namespace", oldCluster.Namespace, "cluster", oldCluster.Name)
+			log = log.WithValues(logutil.ClusterKey, logutil.KObj(oldCluster))
 
 			newCluster := e.ObjectNew.(*clusterv1.Cluster)
 
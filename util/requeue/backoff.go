@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requeue implements helpers for computing requeue intervals in reconcilers.
+package requeue
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// backoffFactor is the factor the requeue interval is multiplied by for each consecutive requeue of an object.
+	backoffFactor = 2.0
+
+	// backoffJitter is the maximum fraction of the requeue interval added as a random jitter,
+	// so requeues for objects failing at the same time are spread over time.
+	backoffJitter = 0.2
+)
+
+// Backoff tracks consecutive requeues per object and computes exponentially increasing requeue intervals
+// with jitter, thus preventing reconcilers from hot-looping on objects that are persistently failing.
+// The zero value is ready to use.
+//
+// NOTE: Forget must be called when the condition causing the requeue is resolved, or when the object is deleted.
+type Backoff struct {
+	lock     sync.Mutex
+	requeues map[client.ObjectKey]int
+}
+
+// After records a requeue for the given object and returns the interval after which the object
+// should be requeued. The first requeue of an object returns base; consecutive requeues return
+// an exponentially increasing interval with jitter, capped to maxInterval.
+func (b *Backoff) After(obj client.Object, base, maxInterval time.Duration) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.requeues == nil {
+		b.requeues = map[client.ObjectKey]int{}
+	}
+
+	key := client.ObjectKeyFromObject(obj)
+	n := b.requeues[key]
+	b.requeues[key] = n + 1
+
+	if n == 0 {
+		return base
+	}
+
+	interval := time.Duration(float64(base) * math.Pow(backoffFactor, float64(n)))
+	// NOTE: the interval could overflow for an high number of requeues.
+	if interval <= 0 || interval > maxInterval {
+		interval = maxInterval
+	}
+	interval = wait.Jitter(interval, backoffJitter)
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+	return interval
+}
+
+// Requeues returns the number of consecutive requeues recorded for the given object.
+func (b *Backoff) Requeues(obj client.Object) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.requeues[client.ObjectKeyFromObject(obj)]
+}
+
+// Forget resets the consecutive requeues recorded for the given object.
+func (b *Backoff) Forget(obj client.Object) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	delete(b.requeues, client.ObjectKeyFromObject(obj))
+}
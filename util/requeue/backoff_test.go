@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requeue
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestBackoff(t *testing.T) {
+	g := NewWithT(t)
+
+	base := 10 * time.Second
+	max := time.Minute
+
+	m1 := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "m1"}}
+	m2 := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "m2"}}
+
+	b := &Backoff{}
+
+	// The first requeue returns the base interval.
+	g.Expect(b.After(m1, base, max)).To(Equal(base))
+
+	// Consecutive requeues return an increasing interval with jitter.
+	second := b.After(m1, base, max)
+	g.Expect(second).To(BeNumerically(">=", 2*base))
+	g.Expect(second).To(BeNumerically("<=", time.Duration(float64(2*base)*(1+backoffJitter))))
+
+	third := b.After(m1, base, max)
+	g.Expect(third).To(BeNumerically(">=", 4*base))
+	g.Expect(third).To(BeNumerically("<=", max))
+
+	// The interval is capped to max.
+	for i := 0; i < 100; i++ {
+		g.Expect(b.After(m1, base, max)).To(BeNumerically("<=", max))
+	}
+	g.Expect(b.After(m1, base, max)).To(Equal(max))
+	g.Expect(b.Requeues(m1)).To(Equal(104))
+
+	// Requeues are tracked per object.
+	g.Expect(b.Requeues(m2)).To(Equal(0))
+	g.Expect(b.After(m2, base, max)).To(Equal(base))
+
+	// Forget resets the requeues for an object.
+	b.Forget(m1)
+	g.Expect(b.Requeues(m1)).To(Equal(0))
+	g.Expect(b.After(m1, base, max)).To(Equal(base))
+	g.Expect(b.Requeues(m2)).To(Equal(1))
+}
@@ -180,10 +180,84 @@ func (v buildIdentifier) compare(o buildIdentifier) int {
 //   0 == a is equal to b.
 //   1 == a is greater than b.
 func CompareWithBuildIdentifiers(a semver.Version, b semver.Version) int {
-	if comp := a.Compare(b); comp != 0 {
+	return Compare(a, b, WithBuildTags())
+}
+
+type comparer struct {
+	buildTags      bool
+	skipPreRelease bool
+}
+
+// CompareOption is an option for Compare.
+type CompareOption func(*comparer)
+
+// WithBuildTags modifies the version comparison to also consider build tags
+// when comparing versions, e.g. v1.22.3+vmware.1 is lower than v1.22.3+vmware.2.
+func WithBuildTags() CompareOption {
+	return func(c *comparer) {
+		c.buildTags = true
+	}
+}
+
+// WithoutPreReleases modifies the version comparison to not consider pre-releases
+// when comparing versions, e.g. v1.22.0-rc.1 is equal to v1.22.0.
+func WithoutPreReleases() CompareOption {
+	return func(c *comparer) {
+		c.skipPreRelease = true
+	}
+}
+
+// Compare compares 2 versions a and b; by default pre-releases are considered
+// and build metadata are ignored, as defined by the semver specification.
+//   -1 == a is less than b.
+//   0 == a is equal to b.
+//   1 == a is greater than b.
+func Compare(a, b semver.Version, options ...CompareOption) int {
+	c := &comparer{}
+	for _, o := range options {
+		o(c)
+	}
+
+	if c.skipPreRelease {
+		a.Pre = nil
+		b.Pre = nil
+	}
+	if comp := a.Compare(b); comp != 0 || !c.buildTags {
 		return comp
 	}
-	biA := newBuildIdentifiers(a.Build)
-	biB := newBuildIdentifiers(b.Build)
-	return biA.compare(biB)
+	return newBuildIdentifiers(a.Build).compare(newBuildIdentifiers(b.Build))
+}
+
+// MaxKubeletSkew is the maximum number of minor versions a kubelet could be older than
+// the control plane, as defined by the Kubernetes version skew policy.
+const MaxKubeletSkew = 2
+
+// ValidateUpgrade checks if upgrading from a version to another complies with the Kubernetes version skew policy,
+// which requires control planes to be upgraded one minor version at a time; pre-releases and build metadata,
+// e.g. v1.22.3+vmware.1, are not relevant for the skew policy and thus ignored. Downgrades are not checked.
+func ValidateUpgrade(from, to semver.Version) error {
+	ceil := semver.Version{
+		Major: from.Major,
+		Minor: from.Minor + 2,
+	}
+	if Compare(to, ceil, WithoutPreReleases()) >= 0 {
+		return errors.Errorf("upgrading from v%d.%d to v%d.%d skips at least one minor version", from.Major, from.Minor, to.Major, to.Minor)
+	}
+	return nil
+}
+
+// ValidateKubeletSkew checks if a kubelet version is supported with a control plane version according to the
+// Kubernetes version skew policy: a kubelet must not be newer than the control plane, and it could be up to
+// MaxKubeletSkew minor versions older; pre-releases and build metadata are ignored.
+func ValidateKubeletSkew(controlPlane, kubelet semver.Version) error {
+	if kubelet.Major != controlPlane.Major {
+		return errors.Errorf("kubelet version v%d.%d must have the same major version of the control plane version v%d.%d", kubelet.Major, kubelet.Minor, controlPlane.Major, controlPlane.Minor)
+	}
+	if kubelet.Minor > controlPlane.Minor {
+		return errors.Errorf("kubelet version v%d.%d must not be newer than the control plane version v%d.%d", kubelet.Major, kubelet.Minor, controlPlane.Major, controlPlane.Minor)
+	}
+	if controlPlane.Minor-kubelet.Minor > MaxKubeletSkew {
+		return errors.Errorf("kubelet version v%d.%d must not be more than %d minor versions older than the control plane version v%d.%d", kubelet.Major, kubelet.Minor, MaxKubeletSkew, controlPlane.Major, controlPlane.Minor)
+	}
+	return nil
 }
@@ -272,3 +272,172 @@ func TestCompareWithBuildIdentifiers(t *testing.T) {
 		})
 	}
 }
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        semver.Version
+		b        semver.Version
+		options  []CompareOption
+		expected int
+	}{
+		{
+			name:     "compare versions",
+			a:        semver.MustParse("1.22.3"),
+			b:        semver.MustParse("1.22.4"),
+			expected: -1,
+		},
+		{
+			name:     "compare versions ignoring build tags",
+			a:        semver.MustParse("1.22.3+vmware.2"),
+			b:        semver.MustParse("1.22.3+vmware.1"),
+			expected: 0,
+		},
+		{
+			name:     "compare versions with build tags",
+			a:        semver.MustParse("1.22.3+vmware.2"),
+			b:        semver.MustParse("1.22.3+vmware.1"),
+			options:  []CompareOption{WithBuildTags()},
+			expected: 1,
+		},
+		{
+			name:     "compare versions with pre-releases",
+			a:        semver.MustParse("1.22.0-rc.1"),
+			b:        semver.MustParse("1.22.0"),
+			expected: -1,
+		},
+		{
+			name:     "compare versions without pre-releases",
+			a:        semver.MustParse("1.22.0-rc.1"),
+			b:        semver.MustParse("1.22.0"),
+			options:  []CompareOption{WithoutPreReleases()},
+			expected: 0,
+		},
+		{
+			name:     "compare versions without pre-releases and with build tags",
+			a:        semver.MustParse("1.22.0-rc.1+vmware.1"),
+			b:        semver.MustParse("1.22.0+vmware.2"),
+			options:  []CompareOption{WithoutPreReleases(), WithBuildTags()},
+			expected: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Compare(tt.a, tt.b, tt.options...)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestValidateUpgrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		expectErr bool
+	}{
+		{
+			name: "upgrade to the next patch version",
+			from: "1.21.1",
+			to:   "1.21.2",
+		},
+		{
+			name: "upgrade to the next minor version",
+			from: "1.21.1",
+			to:   "1.22.5",
+		},
+		{
+			name: "upgrade to the next minor version with build metadata",
+			from: "1.21.1+vmware.1",
+			to:   "1.22.3+vmware.1",
+		},
+		{
+			name: "downgrade",
+			from: "1.22.1",
+			to:   "1.21.1",
+		},
+		{
+			name:      "upgrade skipping a minor version",
+			from:      "1.21.1",
+			to:        "1.23.0",
+			expectErr: true,
+		},
+		{
+			name:      "upgrade skipping a minor version to a pre-release",
+			from:      "1.21.1",
+			to:        "1.23.0-rc.1",
+			expectErr: true,
+		},
+		{
+			name:      "upgrade to the next major version",
+			from:      "1.22.1",
+			to:        "2.0.0",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := ValidateUpgrade(semver.MustParse(tt.from), semver.MustParse(tt.to))
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestValidateKubeletSkew(t *testing.T) {
+	tests := []struct {
+		name         string
+		controlPlane string
+		kubelet      string
+		expectErr    bool
+	}{
+		{
+			name:         "same version",
+			controlPlane: "1.22.3+vmware.1",
+			kubelet:      "1.22.3",
+		},
+		{
+			name:         "kubelet older within the skew",
+			controlPlane: "1.22.3",
+			kubelet:      "1.20.9+vmware.2",
+		},
+		{
+			name:         "kubelet older than the skew",
+			controlPlane: "1.22.3",
+			kubelet:      "1.19.9",
+			expectErr:    true,
+		},
+		{
+			name:         "kubelet newer than the control plane",
+			controlPlane: "1.21.3",
+			kubelet:      "1.22.0-rc.1",
+			expectErr:    true,
+		},
+		{
+			name:         "kubelet with a different major version",
+			controlPlane: "2.0.0",
+			kubelet:      "1.22.0",
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := ValidateKubeletSkew(semver.MustParse(tt.controlPlane), semver.MustParse(tt.kubelet))
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}